@@ -1112,14 +1112,18 @@ func (x *HardwareProfile) GetSerialNumber() string {
 }
 
 type DiskInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	SizeBytes     int64                  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
-	DiskType      string                 `protobuf:"bytes,3,opt,name=disk_type,json=diskType,proto3" json:"disk_type,omitempty"` // SSD, HDD, NVMe
-	Model         string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
-	Serial        string                 `protobuf:"bytes,5,opt,name=serial,proto3" json:"serial,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Name               string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	SizeBytes          int64                  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	DiskType           string                 `protobuf:"bytes,3,opt,name=disk_type,json=diskType,proto3" json:"disk_type,omitempty"` // SSD, HDD, NVMe
+	Model              string                 `protobuf:"bytes,4,opt,name=model,proto3" json:"model,omitempty"`
+	Serial             string                 `protobuf:"bytes,5,opt,name=serial,proto3" json:"serial,omitempty"`
+	SmartHealth        string                 `protobuf:"bytes,6,opt,name=smart_health,json=smartHealth,proto3" json:"smart_health,omitempty"` // healthy, warning, failing, unknown
+	TemperatureCelsius int32                  `protobuf:"varint,7,opt,name=temperature_celsius,json=temperatureCelsius,proto3" json:"temperature_celsius,omitempty"`
+	PowerOnHours       int64                  `protobuf:"varint,8,opt,name=power_on_hours,json=powerOnHours,proto3" json:"power_on_hours,omitempty"`
+	ReallocatedSectors int64                  `protobuf:"varint,9,opt,name=reallocated_sectors,json=reallocatedSectors,proto3" json:"reallocated_sectors,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *DiskInfo) Reset() {
@@ -1187,6 +1191,34 @@ func (x *DiskInfo) GetSerial() string {
 	return ""
 }
 
+func (x *DiskInfo) GetSmartHealth() string {
+	if x != nil {
+		return x.SmartHealth
+	}
+	return ""
+}
+
+func (x *DiskInfo) GetTemperatureCelsius() int32 {
+	if x != nil {
+		return x.TemperatureCelsius
+	}
+	return 0
+}
+
+func (x *DiskInfo) GetPowerOnHours() int64 {
+	if x != nil {
+		return x.PowerOnHours
+	}
+	return 0
+}
+
+func (x *DiskInfo) GetReallocatedSectors() int64 {
+	if x != nil {
+		return x.ReallocatedSectors
+	}
+	return 0
+}
+
 type GPUInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
@@ -1632,7 +1664,7 @@ const file_api_proto_v1_scout_proto_rawDesc = "" +
 	"\ametrics\x18\x05 \x01(\v2\x1b.subnetree.v1.SystemMetricsR\ametrics\x12#\n" +
 	"\rproto_version\x18\x06 \x01(\rR\fprotoVersion\x12!\n" +
 	"\fenroll_token\x18\a \x01(\tR\venrollToken\x12/\n" +
-	"\x13certificate_request\x18\b \x01(\fR\x12certificateRequest\"\xac\x03\n" +
+	"\x13certificate_request\x18\b \x01(\fR\x12certificateRequest\"\xcb\x03\n" +
 	"\x0fCheckInResponse\x12\"\n" +
 	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x124\n" +
 	"\x16check_interval_seconds\x18\x02 \x01(\x05R\x14checkIntervalSeconds\x12)\n" +
@@ -1642,7 +1674,10 @@ const file_api_proto_v1_scout_proto_rawDesc = "" +
 	"\x0fupgrade_message\x18\x06 \x01(\tR\x0eupgradeMessage\x12*\n" +
 	"\x11assigned_agent_id\x18\a \x01(\tR\x0fassignedAgentId\x12-\n" +
 	"\x12signed_certificate\x18\b \x01(\fR\x11signedCertificate\x12%\n" +
-	"\x0eca_certificate\x18\t \x01(\fR\rcaCertificate\"\xe7\x02\n" +
+	"\x0eca_certificate\x18\t \x01(\fR\rcaCertificate\x12\x1d\n" +
+	"\n" +
+	"update_url\x18\n" +
+	" \x01(\tR\tupdateUrl\"\xe7\x02\n" +
 	"\rSystemMetrics\x12\x1f\n" +
 	"\vcpu_percent\x18\x01 \x01(\x01R\n" +
 	"cpuPercent\x12%\n" +
@@ -1719,14 +1754,18 @@ const file_api_proto_v1_scout_proto_rawDesc = "" +
 	"\x13system_manufacturer\x18\t \x01(\tR\x12systemManufacturer\x12!\n" +
 	"\fsystem_model\x18\n" +
 	" \x01(\tR\vsystemModel\x12#\n" +
-	"\rserial_number\x18\v \x01(\tR\fserialNumber\"\x88\x01\n" +
+	"\rserial_number\x18\v \x01(\tR\fserialNumber\"\xb3\x02\n" +
 	"\bDiskInfo\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1d\n" +
 	"\n" +
 	"size_bytes\x18\x02 \x01(\x03R\tsizeBytes\x12\x1b\n" +
 	"\tdisk_type\x18\x03 \x01(\tR\bdiskType\x12\x14\n" +
 	"\x05model\x18\x04 \x01(\tR\x05model\x12\x16\n" +
-	"\x06serial\x18\x05 \x01(\tR\x06serial\"e\n" +
+	"\x06serial\x18\x05 \x01(\tR\x06serial\x12!\n" +
+	"\fsmart_health\x18\x06 \x01(\tR\vsmartHealth\x12/\n" +
+	"\x13temperature_celsius\x18\a \x01(\x05R\x12temperatureCelsius\x12$\n" +
+	"\x0epower_on_hours\x18\b \x01(\x03R\fpowerOnHours\x12/\n" +
+	"\x13reallocated_sectors\x18\t \x01(\x03R\x12reallocatedSectors\"e\n" +
 	"\aGPUInfo\x12\x14\n" +
 	"\x05model\x18\x01 \x01(\tR\x05model\x12\x1d\n" +
 	"\n" +
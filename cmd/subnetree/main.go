@@ -18,12 +18,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	_ "github.com/HerbHall/subnetree/api/swagger"
 	"github.com/HerbHall/subnetree/internal/auth"
 	"github.com/HerbHall/subnetree/internal/autodoc"
+	"github.com/HerbHall/subnetree/internal/backup"
 	mcpmod "github.com/HerbHall/subnetree/internal/mcp"
 	nbmod "github.com/HerbHall/subnetree/internal/netbox"
 	"github.com/HerbHall/subnetree/internal/catalog"
@@ -47,6 +49,7 @@ import (
 	"github.com/HerbHall/subnetree/internal/svcmap"
 	tsmod "github.com/HerbHall/subnetree/internal/tailscale"
 	"github.com/HerbHall/subnetree/internal/tier"
+	"github.com/HerbHall/subnetree/internal/tracing"
 	"github.com/HerbHall/subnetree/internal/vault"
 	"github.com/HerbHall/subnetree/internal/version"
 	"github.com/HerbHall/subnetree/internal/webhook"
@@ -106,7 +109,7 @@ func main() {
 	tier.ApplyDefaults(viperCfg, detectedTier)
 
 	// Initialize logger from configuration.
-	logger, err := config.NewLogger(viperCfg)
+	logger, logLevel, err := config.NewLeveledLogger(viperCfg)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to initialize logger: %v\n", err)
 		os.Exit(1)
@@ -131,21 +134,58 @@ func main() {
 		)
 	}
 
-	// Open database
+	// Tracing stays a no-op unless an exporter is configured.
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    viperCfg.GetString("tracing.exporter"),
+		Endpoint:    viperCfg.GetString("tracing.endpoint"),
+		Insecure:    viperCfg.GetBool("tracing.insecure"),
+		SampleRatio: viperCfg.GetFloat64("tracing.sample_ratio"),
+	}, version.Short())
+	if err != nil {
+		logger.Fatal("failed to initialize tracing", zap.Error(err))
+	}
+	if exporter := viperCfg.GetString("tracing.exporter"); exporter != tracing.ExporterNone {
+		logger.Info("tracing enabled",
+			zap.String("component", "tracing"),
+			zap.String("exporter", exporter),
+			zap.Float64("sample_ratio", viperCfg.GetFloat64("tracing.sample_ratio")),
+		)
+	}
+
+	// Open database. SQLite takes a file path; PostgreSQL takes database.dsn.
+	dbDriver := viperCfg.GetString("database.driver")
 	dbPath := viperCfg.GetString("database.path")
 	if dbPath == "" {
 		dbPath = "subnetree.db"
 	}
-	db, err := store.New(dbPath)
+	dsn := dbPath
+	if store.Dialect(dbDriver) == store.DialectPostgres {
+		// Plugin migrations are still written for SQLite, so a PostgreSQL
+		// database cannot run the full server yet.
+		if !viperCfg.GetBool("database.experimental_postgres") {
+			logger.Fatal("database driver \"postgres\" is experimental: plugin migrations do not support it yet; " +
+				"use \"sqlite\", or set database.experimental_postgres to try it anyway")
+		}
+		dsn = viperCfg.GetString("database.dsn")
+	}
+	db, err := store.Open(dbDriver, dsn)
 	if err != nil {
-		logger.Fatal("failed to open database", zap.Error(err))
+		logger.Fatal("failed to open database", zap.Error(err), zap.String("driver", dbDriver))
 	}
 	defer db.Close()
 
-	logger.Info("database initialized",
-		zap.String("component", "database"),
-		zap.String("path", dbPath),
-	)
+	if db.Dialect() == store.DialectPostgres {
+		logger.Info("database initialized",
+			zap.String("component", "database"),
+			zap.String("driver", dbDriver),
+		)
+	} else {
+		logger.Info("database initialized",
+			zap.String("component", "database"),
+			zap.String("driver", string(store.DialectSQLite)),
+			zap.String("path", dbPath),
+		)
+	}
 
 	// Check database schema version compatibility.
 	if err := db.CheckVersion(context.Background(), version.Short()); err != nil {
@@ -159,6 +199,13 @@ func main() {
 	bus := event.NewBus(logger.Named("event"))
 	logger.Info("event bus created", zap.String("component", "event"))
 
+	// Keep recent bus events for GET /api/v1/admin/events/recent.
+	var eventRecorder *event.Recorder
+	if size := viperCfg.GetInt("server.event_recorder.size"); size > 0 {
+		eventRecorder = event.NewRecorder(size)
+		eventRecorder.Attach(bus)
+	}
+
 	// Create plugin registry
 	reg := registry.New(logger.Named("registry"))
 	logger.Info("plugin registry created", zap.String("component", "registry"))
@@ -198,11 +245,12 @@ func main() {
 	if err := reg.InitAll(ctx, func(name string) plugin.Dependencies {
 		pluginCfg := cfg.Sub("plugins." + name)
 		return plugin.Dependencies{
-			Config:  pluginCfg,
-			Logger:  logger.Named(name),
-			Store:   db,
-			Bus:     bus,
-			Plugins: reg,
+			Config:   pluginCfg,
+			Logger:   logger.Named(name),
+			Store:    db,
+			Bus:      bus,
+			Plugins:  reg,
+			LogLevel: logLevel,
 		}
 	}); err != nil {
 		logger.Fatal("failed to initialize plugins", zap.Error(err))
@@ -270,6 +318,15 @@ func main() {
 	tokens := auth.NewTokenService([]byte(jwtSecret), accessTTL, refreshTTL)
 	totpSvc := auth.NewTOTPService([]byte(jwtSecret))
 	authService := auth.NewService(authStore, tokens, totpSvc, logger.Named("auth"))
+	authService.SetLockoutPolicy(
+		viperCfg.GetInt("auth.max_failed_attempts"),
+		viperCfg.GetDuration("auth.lockout_duration"),
+	)
+	passwordPolicy := services.DefaultPasswordPolicy()
+	if err := viperCfg.UnmarshalKey("auth.password_policy", &passwordPolicy); err != nil {
+		logger.Fatal("invalid auth.password_policy configuration", zap.Error(err))
+	}
+	authService.SetPasswordPolicy(passwordPolicy)
 	authHandler := auth.NewHandler(authService, logger.Named("auth"))
 	logger.Info("auth service initialized",
 		zap.String("component", "auth"),
@@ -278,7 +335,7 @@ func main() {
 	)
 
 	// Create settings service
-	settingsRepo, err := services.NewSQLiteSettingsRepository(ctx, db)
+	settingsRepo, err := services.NewSettingsRepository(ctx, db)
 	if err != nil {
 		logger.Fatal("failed to initialize settings repository", zap.Error(err))
 	}
@@ -304,9 +361,16 @@ func main() {
 		}
 	}
 	if reconMod != nil && vaultMod != nil {
-		reconMod.SetCredentialAccessor(recon.NewVaultCredentialAdapter(&vaultDecryptAdapter{vault: vaultMod}))
+		vaultAdapter := recon.NewVaultCredentialAdapter(&vaultDecryptAdapter{vault: vaultMod})
+		reconMod.SetCredentialAccessor(vaultAdapter)
+		reconMod.SetCollectorCredentialResolver(vaultAdapter)
 		reconMod.SetCredentialProvider(vaultMod)
-		logger.Info("SNMP credential adapter wired", zap.String("component", "recon"))
+		logger.Info("SNMP and collector credential adapters wired", zap.String("component", "recon"))
+	}
+
+	// Wire scan interface setting: recon -> settings.
+	if reconMod != nil {
+		reconMod.SetScanInterfaceSource(&scanInterfaceAdapter{settings: settingsRepo})
 	}
 
 	// Wire hardware profile bridge: dispatch -> recon.
@@ -421,6 +485,41 @@ func main() {
 		zap.String("addr", addr),
 	)
 	devMode := viperCfg.GetBool("server.dev_mode")
+	corsCfg := server.CORSConfig{
+		AllowedOrigins:   viperCfg.GetStringSlice("server.cors.allowed_origins"),
+		AllowedMethods:   viperCfg.GetStringSlice("server.cors.allowed_methods"),
+		AllowedHeaders:   viperCfg.GetStringSlice("server.cors.allowed_headers"),
+		AllowCredentials: viperCfg.GetBool("server.cors.allow_credentials"),
+		MaxAge:           viperCfg.GetDuration("server.cors.max_age"),
+	}
+	rateLimitCfg := server.RateLimitConfig{
+		Anonymous: server.RateLimit{
+			RPS:   viperCfg.GetFloat64("server.rate_limit.anonymous.rps"),
+			Burst: viperCfg.GetInt("server.rate_limit.anonymous.burst"),
+		},
+		Authenticated: server.RateLimit{
+			RPS:   viperCfg.GetFloat64("server.rate_limit.authenticated.rps"),
+			Burst: viperCfg.GetInt("server.rate_limit.authenticated.burst"),
+		},
+	}
+	if err := viperCfg.UnmarshalKey("server.rate_limit.overrides", &rateLimitCfg.Overrides); err != nil {
+		logger.Fatal("invalid server.rate_limit.overrides", zap.Error(err))
+	}
+	// Demo visitors all share one synthetic user, so keep them per-IP.
+	if !isDemoMode {
+		rateLimitCfg.Identity = auth.RateLimitIdentity
+	}
+	limits := server.RequestLimits{
+		MaxBodyBytes:   viperCfg.GetInt64("server.limits.max_body_bytes"),
+		MaxUploadBytes: viperCfg.GetInt64("server.limits.max_upload_bytes"),
+		HandlerTimeout: viperCfg.GetDuration("server.limits.handler_timeout"),
+	}
+	if len(corsCfg.AllowedOrigins) > 0 {
+		logger.Info("CORS enabled",
+			zap.String("component", "server"),
+			zap.Strings("allowed_origins", corsCfg.AllowedOrigins),
+		)
+	}
 	readyCheck := server.ReadinessChecker(func(ctx context.Context) error {
 		return db.DB().PingContext(ctx)
 	})
@@ -435,6 +534,19 @@ func main() {
 	if sshHandler != nil {
 		extraRoutes = append(extraRoutes, sshHandler)
 	}
+	// Online database snapshots (admin only; not offered in demo mode).
+	// PostgreSQL deployments back up with the database's own tooling.
+	if snapshotter, ok := db.(backup.Snapshotter); ok && !isDemoMode {
+		backupDir := viperCfg.GetString("database.backup_dir")
+		if backupDir == "" {
+			backupDir = filepath.Join(filepath.Dir(dbPath), "backups")
+		}
+		extraRoutes = append(extraRoutes, backup.NewHandler(snapshotter, backupDir, logger.Named("backup"), authHandler.RequireAdmin))
+	}
+	if eventRecorder != nil {
+		extraRoutes = append(extraRoutes, event.NewHandler(eventRecorder, authHandler.RequireAdmin))
+	}
+	extraRoutes = append(extraRoutes, config.NewLogLevelHandler(logLevel, logger, authHandler.RequireAdmin))
 	// In demo mode, use DemoAuthMiddleware instead of JWT validation.
 	var authRegistrar server.RouteRegistrar
 	if isDemoMode {
@@ -444,7 +556,7 @@ func main() {
 		authRegistrar = authHandler
 	}
 
-	srv := server.New(addr, reg, logger, readyCheck, authRegistrar, dashboardHandler, devMode, isDemoMode, extraRoutes...)
+	srv := server.New(addr, reg, logger, readyCheck, authRegistrar, dashboardHandler, devMode, isDemoMode, corsCfg, rateLimitCfg, limits, extraRoutes...)
 
 	// Start server in background
 	go func() {
@@ -479,11 +591,15 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("server shutdown error", zap.Error(err))
 	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("tracing shutdown error", zap.Error(err))
+	}
 
 	logger.Info("SubNetree server stopped")
 }
 
-// vaultDecryptAdapter adapts vault.Module to the recon.CredentialDecrypter interface.
+// vaultDecryptAdapter adapts vault.Module to the recon and tailscale
+// CredentialDecrypter interfaces.
 // Lives in the composition root to avoid coupling recon -> vault.
 type vaultDecryptAdapter struct {
 	vault *vault.Module
@@ -493,6 +609,27 @@ func (a *vaultDecryptAdapter) DecryptCredential(ctx context.Context, id string)
 	return a.vault.DecryptCredentialData(ctx, id)
 }
 
+func (a *vaultDecryptAdapter) DecryptTypedCredential(ctx context.Context, id string) (string, map[string]any, error) {
+	return a.vault.DecryptCredentialWithType(ctx, id)
+}
+
+// scanInterfaceAdapter adapts services.SettingsRepository to the
+// recon.ScanInterfaceSource interface.
+type scanInterfaceAdapter struct {
+	settings services.SettingsRepository
+}
+
+func (a *scanInterfaceAdapter) ScanInterface(ctx context.Context) (string, error) {
+	setting, err := a.settings.Get(ctx, "scan_interface")
+	if err == services.ErrNotFound {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return setting.Value, nil
+}
+
 // tokenAdapter adapts auth.TokenService to the gateway.TokenValidator interface.
 // Lives in the composition root to avoid coupling gateway -> auth.
 type tokenAdapter struct {
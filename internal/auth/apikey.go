@@ -0,0 +1,182 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// apiKeyPrefix marks SubNetree API keys so they are recognizable in
+// config files and secret scanners.
+const apiKeyPrefix = "snt_"
+
+// API key errors.
+var (
+	ErrAPIKeyNotFound = errors.New("api key not found")
+	ErrInvalidAPIKey  = errors.New("invalid, revoked, or expired api key")
+	ErrRoleNotAllowed = errors.New("api key role cannot exceed the owner's role")
+)
+
+// roleRank orders roles by privilege for comparing a key's role against
+// its owner's.
+var roleRank = map[Role]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// APIKey is a long-lived credential for programmatic access. Only the
+// SHA-256 hash of the key is stored; the plaintext is returned once at
+// creation.
+type APIKey struct {
+	ID         string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID     string     `json:"user_id"`
+	Name       string     `json:"name" example:"backup-script"`
+	Prefix     string     `json:"prefix" example:"snt_1a2b3c4d"` // First characters of the key, for identification
+	KeyHash    string     `json:"-"`                             // Never serialized
+	Role       Role       `json:"role" example:"viewer"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Revoked    bool       `json:"revoked"`
+}
+
+// Expired reports whether the key has an expiry that has passed.
+func (k *APIKey) Expired(now time.Time) bool {
+	return k.ExpiresAt != nil && !now.Before(*k.ExpiresAt)
+}
+
+// CreateAPIKey mints an API key for a user. An empty role defaults to the
+// user's own role; a role above the user's is rejected. Returns the
+// plaintext key, which is not stored and cannot be retrieved again.
+func (s *Service) CreateAPIKey(ctx context.Context, userID, name string, role Role, expiresAt *time.Time) (string, *APIKey, error) {
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, ErrUserNotFound
+		}
+		return "", nil, fmt.Errorf("lookup user: %w", err)
+	}
+	if role == "" {
+		role = user.Role
+	}
+	if !ValidRoles[role] {
+		return "", nil, fmt.Errorf("invalid role: %s", role)
+	}
+	if roleRank[role] > roleRank[user.Role] {
+		return "", nil, ErrRoleNotAllowed
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, fmt.Errorf("generate API key: %w", err)
+	}
+	raw := apiKeyPrefix + hex.EncodeToString(b)
+
+	key := &APIKey{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    raw[:len(apiKeyPrefix)+8],
+		KeyHash:   HashToken(raw),
+		Role:      role,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.store.CreateAPIKey(ctx, key); err != nil {
+		return "", nil, err
+	}
+
+	s.logger.Info("API key created",
+		zap.String("user_id", user.ID),
+		zap.String("key_id", key.ID),
+		zap.String("role", string(role)),
+	)
+	return raw, key, nil
+}
+
+// ListAPIKeys returns the API keys owned by a user.
+func (s *Service) ListAPIKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	keys, err := s.store.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if keys == nil {
+		keys = []APIKey{}
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes an API key. Non-admin callers may only revoke their
+// own keys; other users' keys are reported as not found.
+func (s *Service) RevokeAPIKey(ctx context.Context, caller *Claims, id string) error {
+	key, err := s.store.GetAPIKey(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAPIKeyNotFound
+		}
+		return fmt.Errorf("lookup API key: %w", err)
+	}
+	if key.UserID != caller.UserID && Role(caller.Role) != RoleAdmin {
+		return ErrAPIKeyNotFound
+	}
+	if err := s.store.RevokeAPIKey(ctx, id); err != nil {
+		return err
+	}
+	s.logger.Info("API key revoked", zap.String("key_id", id), zap.String("by", caller.UserID))
+	return nil
+}
+
+// ValidateAPIKey resolves a plaintext API key to claims for its owner and
+// the key's role. Revoked and expired keys, and keys of disabled users,
+// are rejected with ErrInvalidAPIKey.
+func (s *Service) ValidateAPIKey(ctx context.Context, raw string) (*Claims, error) {
+	if !strings.HasPrefix(raw, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	key, err := s.store.GetAPIKeyByHash(ctx, HashToken(raw))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("lookup API key: %w", err)
+	}
+	if key.Revoked || key.Expired(time.Now()) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	user, err := s.store.GetUserByID(ctx, key.UserID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, fmt.Errorf("lookup user: %w", err)
+	}
+	if user.Disabled {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if err := s.store.TouchAPIKey(ctx, key.ID); err != nil {
+		s.logger.Warn("failed to record API key use", zap.String("key_id", key.ID), zap.Error(err))
+	}
+
+	// A key never grants more than its owner currently has.
+	role := key.Role
+	if roleRank[user.Role] < roleRank[role] {
+		role = user.Role
+	}
+	return &Claims{
+		UserID:   user.ID,
+		Username: user.Username,
+		Role:     string(role),
+		APIKeyID: key.ID,
+	}, nil
+}
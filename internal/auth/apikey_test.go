@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateAPIKey_AndValidate(t *testing.T) {
+	_, _, svc := testEnv(t)
+	ctx := context.Background()
+
+	admin, err := svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+
+	raw, key, err := svc.CreateAPIKey(ctx, admin.ID, "ci", "", nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey: %v", err)
+	}
+	if !strings.HasPrefix(raw, apiKeyPrefix) || !strings.HasPrefix(raw, key.Prefix) {
+		t.Errorf("raw key %q does not start with %q and prefix %q", raw, apiKeyPrefix, key.Prefix)
+	}
+	if key.Role != RoleAdmin {
+		t.Errorf("Role = %q, want owner's role %q", key.Role, RoleAdmin)
+	}
+	if key.KeyHash == raw {
+		t.Error("plaintext key stored as hash")
+	}
+
+	claims, err := svc.ValidateAPIKey(ctx, raw)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+	if claims.UserID != admin.ID || claims.Username != "admin" || claims.Role != string(RoleAdmin) || claims.APIKeyID != key.ID {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	keys, err := svc.ListAPIKeys(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("ListAPIKeys: %v", err)
+	}
+	if len(keys) != 1 || keys[0].LastUsedAt == nil {
+		t.Errorf("keys = %+v, want one key with last_used_at set", keys)
+	}
+
+	if _, err := svc.ValidateAPIKey(ctx, raw+"0"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("ValidateAPIKey(wrong key) = %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestCreateAPIKey_RoleCappedByOwner(t *testing.T) {
+	store, _, svc := testEnv(t)
+	ctx := context.Background()
+
+	admin, err := svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	viewer := &User{
+		ID: "viewer-1", Username: "viewer", Email: "viewer@example.com",
+		Role: RoleViewer, AuthProvider: "local", CreatedAt: time.Now().UTC(),
+	}
+	if err := store.CreateUser(ctx, viewer); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+
+	if _, _, err := svc.CreateAPIKey(ctx, viewer.ID, "escalate", RoleAdmin, nil); !errors.Is(err, ErrRoleNotAllowed) {
+		t.Errorf("CreateAPIKey(admin role) = %v, want ErrRoleNotAllowed", err)
+	}
+
+	raw, _, err := svc.CreateAPIKey(ctx, admin.ID, "read-only", RoleViewer, nil)
+	if err != nil {
+		t.Fatalf("CreateAPIKey(viewer role): %v", err)
+	}
+	claims, err := svc.ValidateAPIKey(ctx, raw)
+	if err != nil {
+		t.Fatalf("ValidateAPIKey: %v", err)
+	}
+	if claims.Role != string(RoleViewer) {
+		t.Errorf("Role = %q, want %q", claims.Role, RoleViewer)
+	}
+}
+
+func TestValidateAPIKey_RevokedAndExpired(t *testing.T) {
+	_, _, svc := testEnv(t)
+	ctx := context.Background()
+
+	admin, _ := svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	caller := &Claims{UserID: admin.ID, Role: string(RoleAdmin)}
+
+	revoked, key, _ := svc.CreateAPIKey(ctx, admin.ID, "revoked", "", nil)
+	if err := svc.RevokeAPIKey(ctx, caller, key.ID); err != nil {
+		t.Fatalf("RevokeAPIKey: %v", err)
+	}
+	if _, err := svc.ValidateAPIKey(ctx, revoked); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("ValidateAPIKey(revoked) = %v, want ErrInvalidAPIKey", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	expired, _, _ := svc.CreateAPIKey(ctx, admin.ID, "expired", "", &past)
+	if _, err := svc.ValidateAPIKey(ctx, expired); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("ValidateAPIKey(expired) = %v, want ErrInvalidAPIKey", err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	valid, _, _ := svc.CreateAPIKey(ctx, admin.ID, "not yet expired", "", &future)
+	if _, err := svc.ValidateAPIKey(ctx, valid); err != nil {
+		t.Errorf("ValidateAPIKey(unexpired) = %v", err)
+	}
+
+	if err := svc.RevokeAPIKey(ctx, caller, "missing"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey(missing) = %v, want ErrAPIKeyNotFound", err)
+	}
+	other := &Claims{UserID: "someone-else", Role: string(RoleOperator)}
+	_, key, _ = svc.CreateAPIKey(ctx, admin.ID, "not theirs", "", nil)
+	if err := svc.RevokeAPIKey(ctx, other, key.ID); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("RevokeAPIKey(other user's key) = %v, want ErrAPIKeyNotFound", err)
+	}
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	_, tokens, svc := testEnv(t)
+	ctx := context.Background()
+
+	admin, _ := svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	raw, key, _ := svc.CreateAPIKey(ctx, admin.ID, "script", RoleOperator, nil)
+
+	mw := AuthMiddleware(tokens, svc)
+	var gotClaims *Claims
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotClaims = UserFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+	send := func(apiKey string) int {
+		gotClaims = nil
+		req := httptest.NewRequest("GET", "/api/v1/plugins", http.NoBody)
+		req.Header.Set(APIKeyHeader, apiKey)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(raw); code != http.StatusOK {
+		t.Fatalf("valid key status = %d, want 200", code)
+	}
+	if gotClaims == nil || gotClaims.UserID != admin.ID || gotClaims.Role != string(RoleOperator) {
+		t.Errorf("claims = %+v, want user %s with operator role", gotClaims, admin.ID)
+	}
+
+	if code := send("snt_bogus"); code != http.StatusUnauthorized {
+		t.Errorf("unknown key status = %d, want 401", code)
+	}
+
+	_ = svc.RevokeAPIKey(ctx, &Claims{UserID: admin.ID, Role: string(RoleAdmin)}, key.ID)
+	if code := send(raw); code != http.StatusUnauthorized {
+		t.Errorf("revoked key status = %d, want 401", code)
+	}
+	if gotClaims != nil {
+		t.Error("handler reached with revoked key")
+	}
+}
+
+func TestHandleAPIKeys(t *testing.T) {
+	h, mux := setupHandlerEnv(t)
+	ctx := context.Background()
+
+	admin, err := h.service.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	claims := &Claims{UserID: admin.ID, Username: "admin", Role: string(RoleAdmin)}
+	send := func(c *Claims, method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), authUserKey{}, c))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(claims, "POST", "/api/v1/settings/api-keys", `{"name":"ci","expires_at":"2099-01-01T00:00:00Z"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var created CreateAPIKeyResponse
+	_ = json.NewDecoder(w.Body).Decode(&created)
+	if !strings.HasPrefix(created.Key, apiKeyPrefix) || created.ExpiresAt == nil {
+		t.Errorf("created = %+v", created)
+	}
+
+	// The plaintext is not returned again.
+	w = send(claims, "GET", "/api/v1/settings/api-keys", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), created.Key) || strings.Contains(w.Body.String(), "key_hash") {
+		t.Errorf("list leaks key material: %s", w.Body.String())
+	}
+
+	for _, tt := range []struct {
+		name   string
+		claims *Claims
+		body   string
+		want   int
+	}{
+		{"missing name", claims, `{}`, http.StatusBadRequest},
+		{"bad role", claims, `{"name":"x","role":"root"}`, http.StatusBadRequest},
+		{"past expiry", claims, `{"name":"x","expires_at":"2000-01-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"key minting key", &Claims{UserID: admin.ID, Role: string(RoleAdmin), APIKeyID: created.ID}, `{"name":"x"}`, http.StatusForbidden},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := send(tt.claims, "POST", "/api/v1/settings/api-keys", tt.body); w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	if w := send(claims, "DELETE", "/api/v1/settings/api-keys/"+created.ID, ""); w.Code != http.StatusNoContent {
+		t.Errorf("revoke status = %d, want 204", w.Code)
+	}
+	if _, err := h.service.ValidateAPIKey(ctx, created.Key); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("ValidateAPIKey after revoke = %v, want ErrInvalidAPIKey", err)
+	}
+	if w := send(claims, "DELETE", "/api/v1/settings/api-keys/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("revoke missing status = %d, want 404", w.Code)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	_ "github.com/HerbHall/subnetree/pkg/models" // swagger type reference
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/internal/version"
 	"go.uber.org/zap"
 )
@@ -29,6 +32,7 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("POST /api/v1/auth/logout", h.handleLogout)
 	mux.HandleFunc("POST /api/v1/auth/setup", h.handleSetup)
 	mux.HandleFunc("GET /api/v1/auth/setup/status", h.handleSetupStatus)
+	mux.HandleFunc("POST /api/v1/auth/password", h.handleChangePassword)
 
 	// MFA endpoints (verify/recovery are public since the user only has an MFA token).
 	mux.HandleFunc("POST /api/v1/auth/mfa/verify", h.handleMFAVerify)
@@ -44,11 +48,17 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/v1/users/{id}", h.handleGetUser)
 	mux.HandleFunc("PUT /api/v1/users/{id}", h.handleUpdateUser)
 	mux.HandleFunc("DELETE /api/v1/users/{id}", h.handleDeleteUser)
+	mux.HandleFunc("POST /api/v1/users/{id}/unlock", h.handleUnlockUser)
+
+	// API key management for the authenticated user.
+	mux.HandleFunc("POST /api/v1/settings/api-keys", h.handleCreateAPIKey)
+	mux.HandleFunc("GET /api/v1/settings/api-keys", h.handleListAPIKeys)
+	mux.HandleFunc("DELETE /api/v1/settings/api-keys/{id}", h.handleRevokeAPIKey)
 }
 
 // Middleware returns the JWT authentication middleware.
 func (h *Handler) Middleware() func(http.Handler) http.Handler {
-	return AuthMiddleware(h.service.Tokens())
+	return AuthMiddleware(h.service.Tokens(), h.service)
 }
 
 // handleLogin authenticates a user and returns a token pair.
@@ -210,6 +220,11 @@ func (h *Handler) handleSetup(w http.ResponseWriter, r *http.Request) {
 			writeAuthError(w, http.StatusConflict, "setup already completed")
 			return
 		}
+		var policyErr *services.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			writePasswordPolicyError(w, policyErr)
+			return
+		}
 		writeAuthError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -217,6 +232,56 @@ func (h *Handler) handleSetup(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, user)
 }
 
+// handleChangePassword changes the authenticated user's password.
+//
+//	@Summary		Change password
+//	@Description	Change the authenticated user's password. The new password must satisfy the server's password policy; a 400 response lists every rule it failed.
+//	@Tags			auth
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Param			request	body	ChangePasswordRequest	true	"Current and new password"
+//	@Success		204		"No Content"
+//	@Failure		400		{object}	PasswordPolicyProblem
+//	@Failure		401		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/auth/password [post]
+func (h *Handler) handleChangePassword(w http.ResponseWriter, r *http.Request) {
+	claims := UserFromContext(r.Context())
+	if claims == nil {
+		writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAuthError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		writeAuthError(w, http.StatusBadRequest, "current_password and new_password are required")
+		return
+	}
+
+	err := h.service.ChangePassword(r.Context(), claims.UserID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		var policyErr *services.PasswordPolicyError
+		switch {
+		case errors.As(err, &policyErr):
+			writePasswordPolicyError(w, policyErr)
+		case errors.Is(err, ErrInvalidCredentials):
+			writeAuthError(w, http.StatusUnauthorized, "current password is incorrect")
+		case errors.Is(err, ErrUserNotFound):
+			writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		default:
+			h.logger.Error("change password error", zap.Error(err))
+			writeAuthError(w, http.StatusInternalServerError, "failed to change password")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // handleSetupStatus reports whether initial setup is required.
 //
 //	@Summary		Check setup status
@@ -381,6 +446,39 @@ func (h *Handler) handleDeleteUser(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleUnlockUser clears a user's account lockout.
+//
+//	@Summary		Unlock user
+//	@Description	Clear a user's lockout and failed login counter so they can log in again. Requires admin role.
+//	@Tags			users
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	User
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		403	{object}	models.APIProblem
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/users/{id}/unlock [post]
+func (h *Handler) handleUnlockUser(w http.ResponseWriter, r *http.Request) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	user, err := h.service.UnlockUser(r.Context(), r.PathValue("id"))
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			writeAuthError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("unlock user error", zap.Error(err))
+		writeAuthError(w, http.StatusInternalServerError, "failed to unlock user")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
 // handleMFAVerify completes an MFA login with a TOTP code.
 //
 //	@Summary		Verify MFA code
@@ -594,6 +692,125 @@ func (h *Handler) handleMFADisable(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleCreateAPIKey mints an API key for the authenticated user.
+//
+//	@Summary		Create API key
+//	@Description	Mint an API key for programmatic access, sent in the X-API-Key header. The key acts as its owner with the given role, which defaults to and cannot exceed the owner's role. The plaintext key is returned only in this response. API keys cannot be used to create further keys.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		CreateAPIKeyRequest	true	"Key name, role, and optional expiry"
+//	@Success		201		{object}	CreateAPIKeyResponse
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		401		{object}	models.APIProblem
+//	@Failure		403		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/settings/api-keys [post]
+func (h *Handler) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	if user == nil {
+		writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+	if user.APIKeyID != "" {
+		writeAuthError(w, http.StatusForbidden, "API keys cannot be used to create API keys")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeAuthError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeAuthError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if req.Role != "" && !ValidRoles[Role(req.Role)] {
+		writeAuthError(w, http.StatusBadRequest, "invalid role")
+		return
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		writeAuthError(w, http.StatusBadRequest, "expires_at must be in the future")
+		return
+	}
+
+	raw, key, err := h.service.CreateAPIKey(r.Context(), user.UserID, strings.TrimSpace(req.Name), Role(req.Role), req.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrRoleNotAllowed):
+			writeAuthError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, ErrUserNotFound):
+			writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		default:
+			h.logger.Error("create API key error", zap.Error(err))
+			writeAuthError(w, http.StatusInternalServerError, "failed to create API key")
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, CreateAPIKeyResponse{APIKey: *key, Key: raw})
+}
+
+// handleListAPIKeys returns the authenticated user's API keys.
+//
+//	@Summary		List API keys
+//	@Description	Returns the API keys owned by the authenticated user, including revoked and expired keys. Plaintext keys are never returned.
+//	@Tags			auth
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		APIKey
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/settings/api-keys [get]
+func (h *Handler) handleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	if user == nil {
+		writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	keys, err := h.service.ListAPIKeys(r.Context(), user.UserID)
+	if err != nil {
+		h.logger.Error("list API keys error", zap.Error(err))
+		writeAuthError(w, http.StatusInternalServerError, "failed to list API keys")
+		return
+	}
+	writeJSON(w, http.StatusOK, keys)
+}
+
+// handleRevokeAPIKey revokes an API key.
+//
+//	@Summary		Revoke API key
+//	@Description	Revokes an API key so it can no longer authenticate. Users may revoke their own keys; admins may revoke any key.
+//	@Tags			auth
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"API key ID"
+//	@Success		204	"No content"
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/settings/api-keys/{id} [delete]
+func (h *Handler) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	user := UserFromContext(r.Context())
+	if user == nil {
+		writeAuthError(w, http.StatusUnauthorized, "authentication required")
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(r.Context(), user, r.PathValue("id")); err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			writeAuthError(w, http.StatusNotFound, "API key not found")
+			return
+		}
+		h.logger.Error("revoke API key error", zap.Error(err))
+		writeAuthError(w, http.StatusInternalServerError, "failed to revoke API key")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // requireAdmin checks that the authenticated user has admin role.
 // Returns false (and writes an error response) if not authorized.
 func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
@@ -609,6 +826,17 @@ func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
 	return true
 }
 
+// RequireAdmin restricts next to authenticated users with the admin role.
+// It lets routes registered outside this package reuse the admin check.
+func (h *Handler) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.requireAdmin(w, r) {
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // writeJSON writes a JSON response with the given status code.
 func writeJSON(w http.ResponseWriter, status int, v any) {
 	w.Header().Set("Content-Type", "application/json")
@@ -616,6 +844,20 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// writePasswordPolicyError writes a 400 problem response listing the
+// password policy rules that failed.
+func writePasswordPolicyError(w http.ResponseWriter, err *services.PasswordPolicyError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusBadRequest)
+	_ = json.NewEncoder(w).Encode(PasswordPolicyProblem{
+		Type:       "https://subnetree.com/problems/password-policy",
+		Title:      http.StatusText(http.StatusBadRequest),
+		Status:     http.StatusBadRequest,
+		Detail:     "password does not meet the password policy",
+		Violations: err.Violations,
+	})
+}
+
 // writeAuthError writes an RFC 7807 problem response.
 func writeAuthError(w http.ResponseWriter, status int, detail string) {
 	w.Header().Set("Content-Type", "application/problem+json")
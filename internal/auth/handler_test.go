@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/internal/store"
 	"go.uber.org/zap"
 )
@@ -295,6 +296,111 @@ func TestHandleListUsers_NonAdminForbidden(t *testing.T) {
 	}
 }
 
+func TestHandleUnlockUser(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/auth/setup", map[string]string{
+		"username": "admin",
+		"email":    "admin@example.com",
+		"password": "securepassword",
+	})
+	var admin User
+	_ = json.NewDecoder(w.Body).Decode(&admin)
+
+	login := map[string]string{"username": "admin", "password": "wrongpassword"}
+	for i := 0; i < DefaultMaxFailedAttempts; i++ {
+		doRequest(mux, "POST", "/api/v1/auth/login", login)
+	}
+	login["password"] = "securepassword"
+	if w = doRequest(mux, "POST", "/api/v1/auth/login", login); w.Code == http.StatusOK {
+		t.Fatal("login succeeded on a locked account")
+	}
+
+	if w = doRequest(mux, "POST", "/api/v1/users/"+admin.ID+"/unlock", nil); w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous unlock status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w = doAuthRequest(mux, "POST", "/api/v1/users/"+admin.ID+"/unlock", "admin", nil); w.Code != http.StatusOK {
+		t.Fatalf("unlock status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if w = doRequest(mux, "POST", "/api/v1/auth/login", login); w.Code != http.StatusOK {
+		t.Errorf("login after unlock status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	if w = doAuthRequest(mux, "POST", "/api/v1/users/no-such-user/unlock", "admin", nil); w.Code != http.StatusNotFound {
+		t.Errorf("unknown user unlock status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleSetup_PasswordPolicy(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/auth/setup", map[string]string{
+		"username": "admin",
+		"email":    "admin@example.com",
+		"password": "password",
+	})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var problem PasswordPolicyProblem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if len(problem.Violations) != 1 || problem.Violations[0].Rule != "common" {
+		t.Errorf("violations = %+v, want the common rule", problem.Violations)
+	}
+}
+
+func TestHandleChangePassword(t *testing.T) {
+	h, mux := setupHandlerEnv(t)
+	h.service.SetPasswordPolicy(services.PasswordPolicy{MinLength: 12, RequireDigit: true})
+
+	// Setup is held to the configured policy too.
+	w := doRequest(mux, "POST", "/api/v1/auth/setup", map[string]string{
+		"username": "admin",
+		"email":    "admin@example.com",
+		"password": "securepassword1",
+	})
+	var admin User
+	_ = json.NewDecoder(w.Body).Decode(&admin)
+
+	change := func(current, next string) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		_ = json.NewEncoder(&buf).Encode(ChangePasswordRequest{CurrentPassword: current, NewPassword: next})
+		req := httptest.NewRequest("POST", "/api/v1/auth/password", &buf)
+		req = req.WithContext(context.WithValue(req.Context(), authUserKey{}, &Claims{UserID: admin.ID, Username: "admin", Role: "admin"}))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w = change("securepassword1", "short")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("weak password status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	var problem PasswordPolicyProblem
+	_ = json.NewDecoder(w.Body).Decode(&problem)
+	var rules []string
+	for _, v := range problem.Violations {
+		rules = append(rules, v.Rule)
+	}
+	if len(rules) != 2 || rules[0] != "min_length" || rules[1] != "digit" {
+		t.Errorf("violations = %v, want [min_length digit]", rules)
+	}
+
+	if w = change("wrongpassword", "a-much-longer-passphrase-9"); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong current password status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+	if w = change("securepassword1", "a-much-longer-passphrase-9"); w.Code != http.StatusNoContent {
+		t.Fatalf("change status = %d, want %d; body: %s", w.Code, http.StatusNoContent, w.Body.String())
+	}
+
+	w = doRequest(mux, "POST", "/api/v1/auth/login", map[string]string{"username": "admin", "password": "a-much-longer-passphrase-9"})
+	if w.Code != http.StatusOK {
+		t.Errorf("login with new password status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
 func TestWriteAuthError_Format(t *testing.T) {
 	w := httptest.NewRecorder()
 	writeAuthError(w, http.StatusBadRequest, "something went wrong")
@@ -362,3 +468,33 @@ func TestHandleSetupStatus_AfterSetup(t *testing.T) {
 		t.Error("expected setup_required=false after creating admin")
 	}
 }
+
+func TestRequireAdmin(t *testing.T) {
+	h := &Handler{}
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   int
+	}{
+		{name: "unauthenticated", want: http.StatusUnauthorized},
+		{name: "viewer", claims: &Claims{UserID: "u1", Role: string(RoleViewer)}, want: http.StatusForbidden},
+		{name: "admin", claims: &Claims{UserID: "u1", Role: string(RoleAdmin)}, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/plugins/recon/restart", http.NoBody)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), authUserKey{}, tt.claims))
+			}
+			w := httptest.NewRecorder()
+			h.RequireAdmin(next).ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
@@ -18,6 +18,26 @@ func UserFromContext(ctx context.Context) *Claims {
 	return nil
 }
 
+// ContextWithUser returns a copy of ctx carrying the given authenticated user.
+func ContextWithUser(ctx context.Context, c *Claims) context.Context {
+	return context.WithValue(ctx, authUserKey{}, c)
+}
+
+// RateLimitIdentity names the caller of an authenticated request for
+// per-identity rate limiting: "key:<id>" for API keys, "user:<id>" for
+// access tokens, or "" when the request is unauthenticated.
+func RateLimitIdentity(r *http.Request) string {
+	c := UserFromContext(r.Context())
+	switch {
+	case c == nil:
+		return ""
+	case c.APIKeyID != "":
+		return "key:" + c.APIKeyID
+	default:
+		return "user:" + c.UserID
+	}
+}
+
 // Public paths that don't require authentication.
 var publicPaths = map[string]bool{
 	"/api/v1/auth/login":              true,
@@ -29,9 +49,19 @@ var publicPaths = map[string]bool{
 	"/api/v1/auth/mfa/verify-recovery": true,
 }
 
-// AuthMiddleware validates JWT access tokens on API routes.
+// APIKeyHeader is the request header carrying an API key.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyValidator resolves an API key to the claims of the user and role
+// it was issued for.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*Claims, error)
+}
+
+// AuthMiddleware validates JWT access tokens on API routes. If keys is
+// non-nil, an X-API-Key header is accepted in place of a Bearer token.
 // Public paths and non-API paths (healthz, readyz, metrics) are skipped.
-func AuthMiddleware(tokens *TokenService) func(http.Handler) http.Handler {
+func AuthMiddleware(tokens *TokenService, keys APIKeyValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			// Skip non-API paths (healthz, readyz, metrics, etc.).
@@ -52,6 +82,18 @@ func AuthMiddleware(tokens *TokenService) func(http.Handler) http.Handler {
 				return
 			}
 
+			// An API key, when present, takes the place of a Bearer token.
+			if apiKey := r.Header.Get(APIKeyHeader); apiKey != "" && keys != nil {
+				claims, err := keys.ValidateAPIKey(r.Context(), apiKey)
+				if err != nil {
+					writeAuthError(w, http.StatusUnauthorized, "invalid, revoked, or expired API key")
+					return
+				}
+				ctx := context.WithValue(r.Context(), authUserKey{}, claims)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			// Extract Bearer token from Authorization header.
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
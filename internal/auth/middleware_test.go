@@ -1,6 +1,7 @@
 package auth
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,7 +10,7 @@ import (
 
 func TestAuthMiddleware_SkipsNonAPIPath(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	called := false
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +32,7 @@ func TestAuthMiddleware_SkipsNonAPIPath(t *testing.T) {
 
 func TestAuthMiddleware_SkipsPublicPaths(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	for _, path := range []string{
 		"/api/v1/auth/login",
@@ -58,7 +59,7 @@ func TestAuthMiddleware_SkipsPublicPaths(t *testing.T) {
 
 func TestAuthMiddleware_RejectsNoHeader(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	called := false
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -79,7 +80,7 @@ func TestAuthMiddleware_RejectsNoHeader(t *testing.T) {
 
 func TestAuthMiddleware_RejectsBadToken(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("handler should not be called")
@@ -97,7 +98,7 @@ func TestAuthMiddleware_RejectsBadToken(t *testing.T) {
 
 func TestAuthMiddleware_AcceptsValidToken(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	user := &User{ID: "user-1", Username: "alice", Role: RoleAdmin}
 	token, err := ts.IssueAccessToken(user)
@@ -132,7 +133,7 @@ func TestAuthMiddleware_AcceptsValidToken(t *testing.T) {
 
 func TestAuthMiddleware_RejectsNonBearerScheme(t *testing.T) {
 	ts := NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
-	mw := AuthMiddleware(ts)
+	mw := AuthMiddleware(ts, nil)
 
 	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		t.Error("handler should not be called")
@@ -155,3 +156,26 @@ func TestUserFromContext_Nil(t *testing.T) {
 		t.Error("expected nil claims for empty context")
 	}
 }
+
+func TestRateLimitIdentity(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims *Claims
+		want   string
+	}{
+		{name: "unauthenticated", want: ""},
+		{name: "access token", claims: &Claims{UserID: "u1"}, want: "user:u1"},
+		{name: "api key", claims: &Claims{UserID: "u1", APIKeyID: "k1"}, want: "key:k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/recon/devices", http.NoBody)
+			if tt.claims != nil {
+				req = req.WithContext(context.WithValue(req.Context(), authUserKey{}, tt.claims))
+			}
+			if got := RateLimitIdentity(req); got != tt.want {
+				t.Errorf("RateLimitIdentity = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
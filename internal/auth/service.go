@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -53,15 +54,39 @@ type Service struct {
 	tokens *TokenService
 	totp   *TOTPService
 	logger *zap.Logger
+
+	maxFailedAttempts int
+	lockoutDuration   time.Duration
+	passwordPolicy    services.PasswordPolicy
 }
 
-// NewService creates an auth Service.
+// NewService creates an auth Service with the default lockout and password
+// policies.
 func NewService(store *UserStore, tokens *TokenService, totp *TOTPService, logger *zap.Logger) *Service {
 	return &Service{
-		store:  store,
-		tokens: tokens,
-		totp:   totp,
-		logger: logger,
+		store:             store,
+		tokens:            tokens,
+		totp:              totp,
+		logger:            logger,
+		maxFailedAttempts: DefaultMaxFailedAttempts,
+		lockoutDuration:   DefaultLockoutDuration,
+		passwordPolicy:    services.DefaultPasswordPolicy(),
+	}
+}
+
+// SetPasswordPolicy sets the rules new passwords must satisfy.
+func (s *Service) SetPasswordPolicy(policy services.PasswordPolicy) {
+	s.passwordPolicy = policy
+}
+
+// SetLockoutPolicy sets how many consecutive failed logins lock an account
+// and for how long. Non-positive values keep the defaults.
+func (s *Service) SetLockoutPolicy(maxFailedAttempts int, lockoutDuration time.Duration) {
+	if maxFailedAttempts > 0 {
+		s.maxFailedAttempts = maxFailedAttempts
+	}
+	if lockoutDuration > 0 {
+		s.lockoutDuration = lockoutDuration
 	}
 }
 
@@ -85,13 +110,21 @@ func (s *Service) Login(ctx context.Context, username, password string) (*LoginR
 		return nil, ErrUserDisabled
 	}
 
-	// Check if account is locked.
-	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
-		s.logger.Warn("login attempt on locked account",
-			zap.String("username", username),
-			zap.Time("locked_until", *user.LockedUntil),
-		)
-		return nil, ErrAccountLocked
+	// Check if account is locked. Once the lockout window has passed the
+	// counter starts over, so one more failure does not re-lock it.
+	if user.LockedUntil != nil {
+		if user.LockedUntil.After(time.Now()) {
+			s.logger.Warn("login attempt on locked account",
+				zap.String("username", username),
+				zap.Time("locked_until", *user.LockedUntil),
+			)
+			return nil, ErrAccountLocked
+		}
+		if err := s.store.ClearFailedLogins(ctx, user.ID); err != nil {
+			return nil, fmt.Errorf("clear expired lockout: %w", err)
+		}
+		user.FailedLoginAttempts = 0
+		user.LockedUntil = nil
 	}
 
 	if !CheckPassword(user.PasswordHash, password) {
@@ -135,8 +168,8 @@ func (s *Service) handleFailedLogin(ctx context.Context, user *User) {
 		return
 	}
 
-	if attempts >= DefaultMaxFailedAttempts {
-		lockedUntil := time.Now().Add(DefaultLockoutDuration)
+	if attempts >= s.maxFailedAttempts {
+		lockedUntil := time.Now().Add(s.lockoutDuration)
 		if err := s.store.LockAccount(ctx, user.ID, lockedUntil); err != nil {
 			s.logger.Error("failed to lock account", zap.Error(err))
 			return
@@ -160,7 +193,7 @@ func (s *Service) Setup(ctx context.Context, username, email, password string) (
 		return nil, ErrSetupComplete
 	}
 
-	if err := ValidatePassword(password); err != nil {
+	if err := s.passwordPolicy.Validate(password); err != nil {
 		return nil, err
 	}
 
@@ -292,6 +325,53 @@ func (s *Service) DeleteUser(ctx context.Context, id string) error {
 	return nil
 }
 
+// ChangePassword replaces a user's password after checking the current one.
+// The new password must satisfy the password policy; a failure is returned
+// as a *services.PasswordPolicyError.
+func (s *Service) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	user, err := s.store.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if !CheckPassword(user.PasswordHash, currentPassword) {
+		return ErrInvalidCredentials
+	}
+	if err := s.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := HashPassword(newPassword, 0)
+	if err != nil {
+		return err
+	}
+	if err := s.store.UpdatePassword(ctx, userID, hash); err != nil {
+		return err
+	}
+	s.logger.Info("password changed", zap.String("username", user.Username), zap.String("user_id", user.ID))
+	return nil
+}
+
+// UnlockUser clears a user's lockout and failed login counter.
+func (s *Service) UnlockUser(ctx context.Context, id string) (*User, error) {
+	user, err := s.store.GetUserByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if err := s.store.ClearFailedLogins(ctx, id); err != nil {
+		return nil, fmt.Errorf("unlock user: %w", err)
+	}
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	s.logger.Info("account unlocked", zap.String("username", user.Username), zap.String("user_id", user.ID))
+	return user, nil
+}
+
 func (s *Service) issueTokenPair(ctx context.Context, user *User) (*TokenPair, error) {
 	accessToken, err := s.tokens.IssueAccessToken(user)
 	if err != nil {
@@ -394,3 +394,85 @@ func TestLogin_LockoutPreventsCorrectPassword(t *testing.T) {
 		}
 	}
 }
+
+func TestLogin_ConfiguredLockoutPolicy(t *testing.T) {
+	us, _, svc := testEnv(t)
+	ctx := context.Background()
+	svc.SetLockoutPolicy(3, time.Hour)
+
+	_, _ = svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+
+	for i := 0; i < 2; i++ {
+		_, _ = svc.Login(ctx, "admin", "wrongpassword")
+	}
+	if _, err := svc.Login(ctx, "admin", "securepassword"); err != nil {
+		t.Fatalf("Login below threshold: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, _ = svc.Login(ctx, "admin", "wrongpassword")
+	}
+	if _, err := svc.Login(ctx, "admin", "securepassword"); err != ErrAccountLocked {
+		t.Fatalf("Login after 3 failures: err = %v, want ErrAccountLocked", err)
+	}
+
+	user, _ := us.GetUserByUsername(ctx, "admin")
+	if user.LockedUntil == nil || user.LockedUntil.Before(time.Now().Add(59*time.Minute)) {
+		t.Errorf("LockedUntil = %v, want about an hour from now", user.LockedUntil)
+	}
+}
+
+func TestLogin_ExpiredLockoutResetsCounter(t *testing.T) {
+	us, _, svc := testEnv(t)
+	ctx := context.Background()
+
+	_, _ = svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	for i := 0; i < DefaultMaxFailedAttempts; i++ {
+		_, _ = svc.Login(ctx, "admin", "wrongpassword")
+	}
+
+	// Let the window pass without clearing the counter.
+	user, _ := us.GetUserByUsername(ctx, "admin")
+	_ = us.LockAccount(ctx, user.ID, time.Now().Add(-time.Minute))
+
+	// One more failure must not re-lock the account.
+	if _, err := svc.Login(ctx, "admin", "wrongpassword"); err != ErrInvalidCredentials {
+		t.Fatalf("Login after expiry: err = %v, want ErrInvalidCredentials", err)
+	}
+	user, _ = us.GetUserByUsername(ctx, "admin")
+	if user.FailedLoginAttempts != 1 || user.LockedUntil != nil {
+		t.Errorf("after expiry: attempts = %d, locked_until = %v; want 1, nil", user.FailedLoginAttempts, user.LockedUntil)
+	}
+	if _, err := svc.Login(ctx, "admin", "securepassword"); err != nil {
+		t.Errorf("Login after expiry: %v", err)
+	}
+}
+
+func TestUnlockUser(t *testing.T) {
+	us, _, svc := testEnv(t)
+	ctx := context.Background()
+
+	admin, _ := svc.Setup(ctx, "admin", "admin@example.com", "securepassword")
+	for i := 0; i < DefaultMaxFailedAttempts; i++ {
+		_, _ = svc.Login(ctx, "admin", "wrongpassword")
+	}
+
+	user, err := svc.UnlockUser(ctx, admin.ID)
+	if err != nil {
+		t.Fatalf("UnlockUser: %v", err)
+	}
+	if user.LockedUntil != nil {
+		t.Errorf("returned LockedUntil = %v, want nil", user.LockedUntil)
+	}
+	stored, _ := us.GetUserByID(ctx, admin.ID)
+	if stored.FailedLoginAttempts != 0 || stored.LockedUntil != nil {
+		t.Errorf("stored attempts = %d, locked_until = %v; want 0, nil", stored.FailedLoginAttempts, stored.LockedUntil)
+	}
+	if _, err := svc.Login(ctx, "admin", "securepassword"); err != nil {
+		t.Errorf("Login after unlock: %v", err)
+	}
+
+	if _, err := svc.UnlockUser(ctx, "no-such-user"); err != ErrUserNotFound {
+		t.Errorf("UnlockUser(unknown) err = %v, want ErrUserNotFound", err)
+	}
+}
@@ -82,6 +82,18 @@ func (s *UserStore) UpdateUser(ctx context.Context, u *User) error {
 	return nil
 }
 
+// UpdatePassword replaces a user's password hash.
+func (s *UserStore) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE auth_users SET password_hash = ? WHERE id = ?`,
+		passwordHash, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	return nil
+}
+
 // UpdateLastLogin sets the last_login timestamp.
 func (s *UserStore) UpdateLastLogin(ctx context.Context, userID string) error {
 	_, err := s.db.ExecContext(ctx,
@@ -353,6 +365,30 @@ var migrations = []plugin.Migration{
 			return err
 		},
 	},
+	{
+		Version:     5,
+		Description: "create auth_api_keys table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`
+				CREATE TABLE auth_api_keys (
+					id           TEXT PRIMARY KEY,
+					user_id      TEXT NOT NULL REFERENCES auth_users(id) ON DELETE CASCADE,
+					name         TEXT NOT NULL,
+					prefix       TEXT NOT NULL,
+					key_hash     TEXT NOT NULL UNIQUE,
+					role         TEXT NOT NULL,
+					created_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					expires_at   DATETIME,
+					last_used_at DATETIME,
+					revoked      INTEGER NOT NULL DEFAULT 0
+				)`)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec(`CREATE INDEX idx_api_keys_user ON auth_api_keys(user_id)`)
+			return err
+		},
+	},
 }
 
 // GetTOTPSecret returns the encrypted TOTP secret for a user.
@@ -482,3 +518,92 @@ func (s *UserStore) RevokeMFAToken(ctx context.Context, tokenHash string) error
 		`DELETE FROM auth_mfa_tokens WHERE token_hash = ?`, tokenHash)
 	return err
 }
+
+// apiKeyColumns is the shared SELECT column list for API key queries.
+const apiKeyColumns = `id, user_id, name, prefix, key_hash, role, created_at, expires_at, last_used_at, revoked`
+
+// CreateAPIKey inserts a new API key.
+func (s *UserStore) CreateAPIKey(ctx context.Context, k *APIKey) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO auth_api_keys (id, user_id, name, prefix, key_hash, role, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		k.ID, k.UserID, k.Name, k.Prefix, k.KeyHash, string(k.Role), k.CreatedAt, k.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create API key: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKey returns an API key by ID.
+func (s *UserStore) GetAPIKey(ctx context.Context, id string) (*APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM auth_api_keys WHERE id = ?`, id))
+}
+
+// GetAPIKeyByHash looks up an API key by the hash of its plaintext.
+func (s *UserStore) GetAPIKeyByHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	return scanAPIKey(s.db.QueryRowContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM auth_api_keys WHERE key_hash = ?`, keyHash))
+}
+
+// ListAPIKeys returns all API keys owned by a user, newest first.
+func (s *UserStore) ListAPIKeys(ctx context.Context, userID string) ([]APIKey, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT `+apiKeyColumns+` FROM auth_api_keys WHERE user_id = ? ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []APIKey
+	for rows.Next() {
+		k, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, *k)
+	}
+	return keys, rows.Err()
+}
+
+// RevokeAPIKey marks an API key as revoked.
+func (s *UserStore) RevokeAPIKey(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE auth_api_keys SET revoked = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("revoke API key: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// TouchAPIKey sets the last_used_at timestamp of an API key.
+func (s *UserStore) TouchAPIKey(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx,
+		`UPDATE auth_api_keys SET last_used_at = ? WHERE id = ?`,
+		time.Now().UTC(), id)
+	return err
+}
+
+func scanAPIKey(row interface{ Scan(...any) error }) (*APIKey, error) {
+	var k APIKey
+	var role string
+	var expiresAt, lastUsedAt sql.NullTime
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.Prefix, &k.KeyHash, &role,
+		&k.CreatedAt, &expiresAt, &lastUsedAt, &k.Revoked)
+	if err != nil {
+		return nil, err
+	}
+	k.Role = Role(role)
+	if expiresAt.Valid {
+		k.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		k.LastUsedAt = &lastUsedAt.Time
+	}
+	return &k, nil
+}
@@ -1,5 +1,11 @@
 package auth
 
+import (
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/services"
+)
+
 // LoginRequest is the request body for POST /auth/login.
 type LoginRequest struct {
 	Username string `json:"username" example:"admin"`
@@ -23,6 +29,22 @@ type SetupRequest struct {
 	Password string `json:"password" example:"securepassword123"`
 }
 
+// ChangePasswordRequest is the request body for POST /auth/password.
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"securepassword123"`
+	NewPassword     string `json:"new_password" example:"a-longer-passphrase"`
+}
+
+// PasswordPolicyProblem is the RFC 7807 response for a password that fails
+// the password policy. Violations lists every failed rule.
+type PasswordPolicyProblem struct {
+	Type       string                       `json:"type" example:"https://subnetree.com/problems/password-policy"`
+	Title      string                       `json:"title" example:"Bad Request"`
+	Status     int                          `json:"status" example:"400"`
+	Detail     string                       `json:"detail" example:"password does not meet the password policy"`
+	Violations []services.PasswordViolation `json:"violations"`
+}
+
 // UpdateUserRequest is the request body for PUT /users/{id}.
 type UpdateUserRequest struct {
 	Email    string `json:"email" example:"user@example.com"`
@@ -69,3 +91,17 @@ type MFAVerifySetupRequest struct {
 type MFADisableRequest struct {
 	TOTPCode string `json:"totp_code" example:"123456"`
 }
+
+// CreateAPIKeyRequest is the request body for POST /settings/api-keys.
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" example:"backup-script"`
+	Role      string     `json:"role,omitempty" example:"viewer"` // Defaults to the caller's role
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`
+}
+
+// CreateAPIKeyResponse is the response from POST /settings/api-keys. Key
+// holds the plaintext API key and is only ever returned here.
+type CreateAPIKeyResponse struct {
+	APIKey
+	Key string `json:"key" example:"snt_1a2b3c4d..."`
+}
@@ -16,6 +16,10 @@ type Claims struct {
 	UserID   string `json:"uid"`
 	Username string `json:"usr"`
 	Role     string `json:"role"`
+
+	// APIKeyID is set when the request was authenticated with an API key
+	// rather than a JWT. It is never part of a signed token.
+	APIKeyID string `json:"-"`
 }
 
 // TokenService handles JWT access tokens and refresh token generation.
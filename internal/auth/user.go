@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -58,10 +59,8 @@ func CheckPassword(hash, password string) bool {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
 
-// ValidatePassword checks that a password meets minimum requirements.
+// ValidatePassword checks a password against the default password policy.
+// Service validates against its configured policy instead.
 func ValidatePassword(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters")
-	}
-	return nil
+	return services.DefaultPasswordPolicy().Validate(password)
 }
@@ -36,10 +36,11 @@ func TestValidatePassword(t *testing.T) {
 		password string
 		wantErr  bool
 	}{
-		{"valid 8 chars", "12345678", false},
+		{"valid 8 chars", "24681357", false},
 		{"valid long", "a-very-secure-password", false},
 		{"too short", "1234567", true},
 		{"empty", "", true},
+		{"common", "Password123", true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
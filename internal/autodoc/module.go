@@ -110,10 +110,10 @@ func (m *Module) Stop(_ context.Context) error {
 func (m *Module) Routes() []plugin.Route {
 	return []plugin.Route{
 		{Method: "GET", Path: "/changes", Handler: m.handleListChanges},
-		{Method: "GET", Path: "/export", Handler: m.handleExport},
+		{Method: "GET", Path: "/export", Handler: m.handleExport, Class: plugin.RouteStream},
 		{Method: "GET", Path: "/stats", Handler: m.handleStats},
 		{Method: "GET", Path: "/devices/{id}", Handler: m.handleDeviceDoc},
-		{Method: "GET", Path: "/devices", Handler: m.handleBulkExport},
+		{Method: "GET", Path: "/devices", Handler: m.handleBulkExport, Class: plugin.RouteStream},
 	}
 }
 
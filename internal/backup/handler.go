@@ -0,0 +1,235 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Snapshotter writes a consistent copy of a live database to a new file.
+// Implemented by *store.SQLiteStore.
+type Snapshotter interface {
+	Backup(ctx context.Context, destPath string) error
+}
+
+// snapshotPrefix and snapshotExt bracket the UTC timestamp in snapshot file
+// names, so the newest snapshot sorts last.
+const (
+	snapshotPrefix     = "subnetree-snapshot-"
+	snapshotExt        = ".db"
+	snapshotTimeLayout = "20060102T150405Z"
+)
+
+// SnapshotResponse describes a database snapshot.
+// @Description A point-in-time snapshot of the SubNetree database.
+type SnapshotResponse struct {
+	Filename  string    `json:"filename" example:"subnetree-snapshot-20260101T120000Z.db"`
+	SizeBytes int64     `json:"size_bytes" example:"1048576"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Handler serves the admin backup endpoints, which snapshot the live
+// database into dir and download the latest snapshot.
+type Handler struct {
+	db         Snapshotter
+	dir        string
+	logger     *zap.Logger
+	adminGuard func(http.Handler) http.Handler
+
+	mu  sync.Mutex // serializes snapshots
+	now func() time.Time
+}
+
+// NewHandler creates a backup Handler that stores snapshots in dir.
+// adminGuard restricts the routes to administrators.
+func NewHandler(db Snapshotter, dir string, logger *zap.Logger, adminGuard func(http.Handler) http.Handler) *Handler {
+	return &Handler{
+		db:         db,
+		dir:        dir,
+		logger:     logger,
+		adminGuard: adminGuard,
+		now:        time.Now,
+	}
+}
+
+// RegisterRoutes registers the backup routes on the mux.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("POST /api/v1/admin/backup", h.adminGuard(http.HandlerFunc(h.handleCreate)))
+	mux.Handle("GET /api/v1/admin/backup/download", h.adminGuard(http.HandlerFunc(h.handleDownload)))
+}
+
+// handleCreate snapshots the database while the server keeps running.
+//
+//	@Summary		Create database snapshot
+//	@Description	Writes a consistent point-in-time snapshot of the database,
+//	@Description	replacing the previous snapshot. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		201	{object}	SnapshotResponse
+//	@Failure		403	{object}	map[string]any
+//	@Failure		500	{object}	map[string]any
+//	@Router			/admin/backup [post]
+func (h *Handler) handleCreate(w http.ResponseWriter, r *http.Request) {
+	// Large databases can take longer than the server's write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	snap, err := h.snapshot(r.Context())
+	if err != nil {
+		h.logger.Error("database snapshot failed", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to create snapshot")
+		return
+	}
+	h.logger.Info("database snapshot created",
+		zap.String("file", snap.Filename),
+		zap.Int64("size_bytes", snap.SizeBytes),
+	)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(snap)
+}
+
+// handleDownload streams the latest snapshot.
+//
+//	@Summary		Download database snapshot
+//	@Description	Streams the most recent snapshot created by POST /admin/backup.
+//	@Description	Requires the admin role.
+//	@Tags			admin
+//	@Produce		application/octet-stream
+//	@Security		BearerAuth
+//	@Success		200	{file}		binary
+//	@Failure		403	{object}	map[string]any
+//	@Failure		404	{object}	map[string]any
+//	@Router			/admin/backup/download [get]
+func (h *Handler) handleDownload(w http.ResponseWriter, r *http.Request) {
+	name, err := h.latest()
+	if err != nil {
+		h.logger.Error("find latest snapshot", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to read snapshots")
+		return
+	}
+	if name == "" {
+		writeError(w, http.StatusNotFound, "no snapshot has been created")
+		return
+	}
+
+	f, err := os.Open(filepath.Join(h.dir, name))
+	if err != nil {
+		h.logger.Error("open snapshot", zap.String("file", name), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to open snapshot")
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to open snapshot")
+		return
+	}
+
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeContent(w, r, name, info.ModTime(), f)
+}
+
+// snapshot writes a new snapshot to a temporary file, moves it into place,
+// and removes older snapshots.
+func (h *Handler) snapshot(ctx context.Context) (*SnapshotResponse, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := os.MkdirAll(h.dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create snapshot dir: %w", err)
+	}
+
+	created := h.now().UTC()
+	name := snapshotPrefix + created.Format(snapshotTimeLayout) + snapshotExt
+	final := filepath.Join(h.dir, name)
+	tmp := final + ".tmp"
+	_ = os.Remove(tmp)
+
+	if err := h.db.Backup(ctx, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return nil, err
+	}
+	if err := os.Rename(tmp, final); err != nil {
+		_ = os.Remove(tmp)
+		return nil, fmt.Errorf("move snapshot into place: %w", err)
+	}
+	info, err := os.Stat(final)
+	if err != nil {
+		return nil, fmt.Errorf("stat snapshot: %w", err)
+	}
+
+	h.prune(name)
+	return &SnapshotResponse{Filename: name, SizeBytes: info.Size(), CreatedAt: created}, nil
+}
+
+// prune removes snapshots other than keep. Failures are logged; a snapshot
+// still being downloaded may not be removable on every platform.
+func (h *Handler) prune(keep string) {
+	names, err := h.snapshots()
+	if err != nil {
+		h.logger.Warn("list snapshots for pruning", zap.Error(err))
+		return
+	}
+	for _, name := range names {
+		if name == keep {
+			continue
+		}
+		if err := os.Remove(filepath.Join(h.dir, name)); err != nil {
+			h.logger.Warn("remove old snapshot", zap.String("file", name), zap.Error(err))
+		}
+	}
+}
+
+// latest returns the newest snapshot's file name, or "" if there is none.
+func (h *Handler) latest() (string, error) {
+	names, err := h.snapshots()
+	if err != nil || len(names) == 0 {
+		return "", err
+	}
+	return names[len(names)-1], nil
+}
+
+// snapshots lists snapshot file names in dir, oldest first.
+func (h *Handler) snapshots() ([]string, error) {
+	entries, err := os.ReadDir(h.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.Type().IsRegular() && strings.HasPrefix(name, snapshotPrefix) && strings.HasSuffix(name, snapshotExt) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// writeError writes a problem+json error response.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":   "https://subnetree.com/problems/backup-error",
+		"title":  http.StatusText(status),
+		"status": status,
+		"detail": detail,
+	})
+}
@@ -0,0 +1,123 @@
+package backup_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/backup"
+	"github.com/HerbHall/subnetree/internal/store"
+	"go.uber.org/zap"
+)
+
+// allowAll is an admin guard that admits every request.
+func allowAll(next http.Handler) http.Handler { return next }
+
+func newBackupMux(t *testing.T, guard func(http.Handler) http.Handler) (*http.ServeMux, *store.SQLiteStore, string) {
+	t.Helper()
+	dir := t.TempDir()
+	db, err := store.New(filepath.Join(dir, "subnetree.db"))
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	snapDir := filepath.Join(dir, "backups")
+	mux := http.NewServeMux()
+	backup.NewHandler(db, snapDir, zap.NewNop(), guard).RegisterRoutes(mux)
+	return mux, db, snapDir
+}
+
+func TestHandler_CreateAndDownload(t *testing.T) {
+	mux, db, snapDir := newBackupMux(t, allowAll)
+	ctx := context.Background()
+
+	if _, err := db.DB().ExecContext(ctx, `
+		CREATE TABLE test_data (id INTEGER PRIMARY KEY, name TEXT);
+		INSERT INTO test_data (name) VALUES ('alice'), ('bob');
+	`); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nothing to download before the first snapshot.
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/backup/download", http.NoBody))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("download before snapshot status = %d, want 404", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/admin/backup", http.NoBody))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want 201: %s", w.Code, w.Body.String())
+	}
+	var snap backup.SnapshotResponse
+	if err := json.NewDecoder(w.Body).Decode(&snap); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if snap.Filename == "" || snap.SizeBytes == 0 {
+		t.Errorf("snapshot = %+v, want a named, non-empty file", snap)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/backup/download", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Fatalf("download status = %d, want 200", w.Code)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="`+snap.Filename+`"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	// The downloaded bytes are a database holding the rows.
+	downloaded := filepath.Join(t.TempDir(), "downloaded.db")
+	if err := os.WriteFile(downloaded, w.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := store.New(downloaded)
+	if err != nil {
+		t.Fatalf("open downloaded snapshot: %v", err)
+	}
+	defer restored.Close()
+	var count int
+	if err := restored.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM test_data").Scan(&count); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("snapshot has %d rows, want 2", count)
+	}
+
+	entries, err := os.ReadDir(snapDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("snapshot dir has %d entries, want 1", len(entries))
+	}
+}
+
+func TestHandler_AdminGuard(t *testing.T) {
+	deny := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	mux, _, snapDir := newBackupMux(t, deny)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("POST", "/api/v1/admin/backup", http.NoBody),
+		httptest.NewRequest("GET", "/api/v1/admin/backup/download", http.NoBody),
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s %s status = %d, want 403", req.Method, req.URL.Path, w.Code)
+		}
+	}
+	if _, err := os.Stat(snapDir); !os.IsNotExist(err) {
+		t.Error("snapshot dir created despite the guard rejecting the request")
+	}
+}
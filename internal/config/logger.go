@@ -12,12 +12,20 @@ import (
 // Reads "logging.level" (debug, info, warn, error; default "info")
 // and "logging.format" (json, console; default "json").
 func NewLogger(v *viper.Viper) (*zap.Logger, error) {
+	logger, _, err := NewLeveledLogger(v)
+	return logger, err
+}
+
+// NewLeveledLogger is NewLogger but also returns the logger's level, which
+// can be changed at runtime and applies to the logger and everything
+// derived from it.
+func NewLeveledLogger(v *viper.Viper) (*zap.Logger, zap.AtomicLevel, error) {
 	level := v.GetString("logging.level")
 	format := v.GetString("logging.format")
 
 	var zapLevel zapcore.Level
 	if err := zapLevel.UnmarshalText([]byte(level)); err != nil {
-		return nil, fmt.Errorf("invalid log level %q: %w", level, err)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log level %q: %w", level, err)
 	}
 
 	var cfg zap.Config
@@ -27,10 +35,14 @@ func NewLogger(v *viper.Viper) (*zap.Logger, error) {
 	case "json", "":
 		cfg = zap.NewProductionConfig()
 	default:
-		return nil, fmt.Errorf("invalid log format %q: must be \"json\" or \"console\"", format)
+		return nil, zap.AtomicLevel{}, fmt.Errorf("invalid log format %q: must be \"json\" or \"console\"", format)
 	}
 
 	cfg.Level = zap.NewAtomicLevelAt(zapLevel)
 
-	return cfg.Build()
+	logger, err := cfg.Build()
+	if err != nil {
+		return nil, zap.AtomicLevel{}, err
+	}
+	return logger, cfg.Level, nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/spf13/viper"
+	"go.uber.org/zap"
 )
 
 func TestNewLogger_Defaults(t *testing.T) {
@@ -69,3 +70,21 @@ func TestNewLogger_InvalidFormat(t *testing.T) {
 		t.Fatal("expected error for invalid format")
 	}
 }
+
+func TestNewLeveledLogger_LevelIsAdjustable(t *testing.T) {
+	v := viper.New()
+	v.Set("logging.level", "warn")
+	v.Set("logging.format", "json")
+
+	logger, level, err := NewLeveledLogger(v)
+	if err != nil {
+		t.Fatalf("NewLeveledLogger: %v", err)
+	}
+	if logger.Core().Enabled(zap.InfoLevel) {
+		t.Error("info enabled at configured level warn")
+	}
+	level.SetLevel(zap.DebugLevel)
+	if !logger.Named("plugin").Core().Enabled(zap.DebugLevel) {
+		t.Error("debug not enabled after lowering the level")
+	}
+}
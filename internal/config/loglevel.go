@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogLevelRequest is the request body for PUT /admin/loglevel.
+type LogLevelRequest struct {
+	Level string `json:"level" example:"debug"`
+}
+
+// LogLevelResponse reports the current log level.
+type LogLevelResponse struct {
+	Level string `json:"level" example:"info"`
+}
+
+// LogLevelHandler serves the admin endpoints that read and change the
+// server's log level at runtime.
+type LogLevelHandler struct {
+	level      zap.AtomicLevel
+	logger     *zap.Logger
+	adminGuard func(http.Handler) http.Handler
+}
+
+// NewLogLevelHandler creates a LogLevelHandler that changes level.
+// adminGuard restricts the routes to administrators.
+func NewLogLevelHandler(level zap.AtomicLevel, logger *zap.Logger, adminGuard func(http.Handler) http.Handler) *LogLevelHandler {
+	return &LogLevelHandler{level: level, logger: logger, adminGuard: adminGuard}
+}
+
+// RegisterRoutes registers the log level routes on the mux.
+func (h *LogLevelHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /api/v1/admin/loglevel", h.adminGuard(http.HandlerFunc(h.handleGet)))
+	mux.Handle("PUT /api/v1/admin/loglevel", h.adminGuard(http.HandlerFunc(h.handlePut)))
+}
+
+// handleGet returns the current log level.
+//
+//	@Summary		Get log level
+//	@Description	Returns the server's current log level. Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	LogLevelResponse
+//	@Failure		403	{object}	map[string]any
+//	@Router			/admin/loglevel [get]
+func (h *LogLevelHandler) handleGet(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, LogLevelResponse{Level: h.level.Level().String()})
+}
+
+// handlePut changes the log level without a restart. The change applies
+// to the server and all plugin loggers and lasts until the next restart,
+// which goes back to logging.level.
+//
+//	@Summary		Set log level
+//	@Description	Changes the server's log level (debug, info, warn, error) without a restart.
+//	@Description	The change is not persisted. Requires the admin role.
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		LogLevelRequest	true	"New log level"
+//	@Success		200		{object}	LogLevelResponse
+//	@Failure		400		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/loglevel [put]
+func (h *LogLevelHandler) handlePut(w http.ResponseWriter, r *http.Request) {
+	var req LogLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil || level < zapcore.DebugLevel || level > zapcore.ErrorLevel {
+		writeError(w, http.StatusBadRequest, "level must be debug, info, warn, or error")
+		return
+	}
+
+	old := h.level.Level()
+	h.level.SetLevel(level)
+	// Logged at warn so the change is recorded at any level.
+	h.logger.Warn("log level changed",
+		zap.String("component", "config"),
+		zap.String("from", old.String()),
+		zap.String("to", level.String()),
+	)
+	writeJSON(w, http.StatusOK, LogLevelResponse{Level: level.String()})
+}
+
+// writeJSON writes a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(data)
+}
+
+// writeError writes an RFC 7807 problem detail response.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":   "https://subnetree.com/problems/config-error",
+		"title":  http.StatusText(status),
+		"status": status,
+		"detail": detail,
+	})
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func allowAll(next http.Handler) http.Handler { return next }
+
+func TestLogLevelHandler_GatesDebugOutput(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	core, logs := observer.New(level)
+	logger := zap.New(core)
+	pluginLogger := logger.Named("recon")
+
+	mux := http.NewServeMux()
+	NewLogLevelHandler(level, logger, allowAll).RegisterRoutes(mux)
+
+	setLevel := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/admin/loglevel", strings.NewReader(body)))
+		return w
+	}
+
+	pluginLogger.Debug("hidden at info")
+	if n := logs.FilterMessage("hidden at info").Len(); n != 0 {
+		t.Fatalf("debug entry logged at info level")
+	}
+
+	w := setLevel(`{"level":"debug"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200; body: %s", w.Code, w.Body.String())
+	}
+	var resp LogLevelResponse
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Level != "debug" {
+		t.Errorf("response level = %q, want debug", resp.Level)
+	}
+	pluginLogger.Debug("shown at debug")
+	if n := logs.FilterMessage("shown at debug").Len(); n != 1 {
+		t.Errorf("debug entries after raising verbosity = %d, want 1", n)
+	}
+
+	if w := setLevel(`{"level":"warn"}`); w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, want 200", w.Code)
+	}
+	pluginLogger.Info("hidden at warn")
+	if n := logs.FilterMessage("hidden at warn").Len(); n != 0 {
+		t.Errorf("info entry logged at warn level")
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/loglevel", http.NoBody))
+	_ = json.NewDecoder(w.Body).Decode(&resp)
+	if resp.Level != "warn" {
+		t.Errorf("GET level = %q, want warn", resp.Level)
+	}
+}
+
+func TestLogLevelHandler_InvalidLevel(t *testing.T) {
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	mux := http.NewServeMux()
+	NewLogLevelHandler(level, zap.NewNop(), allowAll).RegisterRoutes(mux)
+
+	for _, body := range []string{`{"level":"banana"}`, `{"level":"fatal"}`, `not json`} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/admin/loglevel", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("body %s: status = %d, want 400", body, w.Code)
+		}
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("level = %s after rejected requests, want info", level.Level())
+	}
+}
+
+func TestLogLevelHandler_AdminGuard(t *testing.T) {
+	deny := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	mux := http.NewServeMux()
+	NewLogLevelHandler(level, zap.NewNop(), deny).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("PUT", "/api/v1/admin/loglevel", strings.NewReader(`{"level":"debug"}`)))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+	if level.Level() != zapcore.InfoLevel {
+		t.Errorf("level changed despite the guard rejecting the request")
+	}
+}
@@ -15,6 +15,32 @@ type DispatchConfig struct {
 	TLSEnabled            bool          `mapstructure:"tls_enabled"`
 	ServerCertPath        string        `mapstructure:"server_cert_path"` //nolint:gosec // G101: file path, not a credential
 	ServerKeyPath         string        `mapstructure:"server_key_path"`
+	Webhooks              WebhookConfig `mapstructure:"webhooks"`
+	Email                 EmailConfig   `mapstructure:"email"`
+}
+
+// WebhookConfig controls delivery of pulse alerts to outbound webhook targets.
+type WebhookConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Timeout        time.Duration `mapstructure:"timeout"`
+	QueueSize      int           `mapstructure:"queue_size"` // Also the per-target queue size
+
+	// DeliveryRetention is how long delivery records, which suppress
+	// duplicate sends, are kept. Zero keeps them forever.
+	DeliveryRetention time.Duration `mapstructure:"delivery_retention"`
+}
+
+// EmailConfig controls delivery of pulse alerts to SMTP email targets.
+// Per-target settings (server, recipients, aggregation window) live on
+// the targets themselves.
+type EmailConfig struct {
+	MaxAttempts    int           `mapstructure:"max_attempts"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+	Timeout        time.Duration `mapstructure:"timeout"` // Per-connection SMTP timeout
+	QueueSize      int           `mapstructure:"queue_size"`
 }
 
 // DefaultConfig returns the default Dispatch configuration.
@@ -29,5 +55,20 @@ func DefaultConfig() DispatchConfig {
 			Validity:     ca.DefaultValidity,
 			Organization: ca.DefaultOrganization,
 		},
+		Webhooks: WebhookConfig{
+			MaxAttempts:       5,
+			InitialBackoff:    2 * time.Second,
+			MaxBackoff:        time.Minute,
+			Timeout:           10 * time.Second,
+			QueueSize:         256,
+			DeliveryRetention: 7 * 24 * time.Hour,
+		},
+		Email: EmailConfig{
+			MaxAttempts:    3,
+			InitialBackoff: 5 * time.Second,
+			MaxBackoff:     time.Minute,
+			Timeout:        30 * time.Second,
+			QueueSize:      256,
+		},
 	}
 }
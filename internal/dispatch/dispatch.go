@@ -21,8 +21,9 @@ import (
 
 // Compile-time interface guards.
 var (
-	_ plugin.Plugin       = (*Module)(nil)
-	_ plugin.HTTPProvider = (*Module)(nil)
+	_ plugin.Plugin          = (*Module)(nil)
+	_ plugin.HTTPProvider    = (*Module)(nil)
+	_ plugin.EventSubscriber = (*Module)(nil)
 )
 
 // Module implements the Dispatch agent management plugin.
@@ -35,6 +36,8 @@ type Module struct {
 	authority  *ca.Authority
 	grpcServer *grpc.Server
 	grpcLis    net.Listener
+	webhooks   *AlertWebhookWorker
+	email      *AlertEmailWorker
 }
 
 // New creates a new Dispatch plugin instance.
@@ -116,6 +119,11 @@ func (m *Module) Start(_ context.Context) error {
 		return nil
 	}
 
+	m.webhooks = NewAlertWebhookWorker(m.store, m.cfg.Webhooks, m.logger.Named("webhooks"))
+	m.webhooks.Start(context.Background())
+	m.email = NewAlertEmailWorker(m.store, nil, m.cfg.Email, m.logger.Named("email"))
+	m.email.Start(context.Background())
+
 	lis, err := net.Listen("tcp", m.cfg.GRPCAddr)
 	if err != nil {
 		return fmt.Errorf("listen gRPC %s: %w", m.cfg.GRPCAddr, err)
@@ -150,6 +158,12 @@ func (m *Module) Stop(_ context.Context) error {
 		m.grpcServer.GracefulStop()
 		m.logger.Info("gRPC server stopped")
 	}
+	if m.webhooks != nil {
+		m.webhooks.Stop()
+	}
+	if m.email != nil {
+		m.email.Stop()
+	}
 	m.logger.Info("dispatch module stopped")
 	return nil
 }
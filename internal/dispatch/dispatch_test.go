@@ -90,16 +90,34 @@ func TestRoutes(t *testing.T) {
 
 	// Verify expected routes exist.
 	want := map[string]string{
-		"GET /agents":                  "",
-		"GET /agents/{id}":             "",
-		"POST /enroll":                 "",
-		"DELETE /agents/{id}":          "",
-		"GET /agents/{id}/hardware":    "",
-		"GET /agents/{id}/software":    "",
-		"GET /agents/{id}/services":    "",
-		"GET /install/{platform}/{arch}":  "",
-		"GET /download/{platform}/{arch}": "",
-		"GET /updates/latest":             "",
+		"GET /agents":                      "",
+		"GET /agents/{id}":                 "",
+		"POST /enroll":                     "",
+		"DELETE /agents/{id}":              "",
+		"GET /agents/{id}/hardware":        "",
+		"GET /agents/{id}/software":        "",
+		"GET /agents/{id}/services":        "",
+		"GET /install/{platform}/{arch}":   "",
+		"GET /download/{platform}/{arch}":  "",
+		"GET /updates/latest":              "",
+		"GET /webhooks":                    "",
+		"POST /webhooks":                   "",
+		"GET /webhooks/dead-letters":       "",
+		"GET /webhooks/{id}":               "",
+		"PUT /webhooks/{id}":               "",
+		"DELETE /webhooks/{id}":            "",
+		"POST /targets/{id}/test":          "",
+		"GET /email-targets":               "",
+		"POST /email-targets":              "",
+		"GET /email-targets/{id}":          "",
+		"PUT /email-targets/{id}":          "",
+		"DELETE /email-targets/{id}":       "",
+		"POST /email-targets/{id}/test":    "",
+		"GET /notification-routes":         "",
+		"POST /notification-routes":        "",
+		"GET /notification-routes/{id}":    "",
+		"PUT /notification-routes/{id}":    "",
+		"DELETE /notification-routes/{id}": "",
 	}
 	for _, r := range routes {
 		key := r.Method + " " + r.Path
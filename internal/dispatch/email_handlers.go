@@ -0,0 +1,379 @@
+package dispatch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Email target defaults applied on create.
+const (
+	defaultEmailPort              = 587
+	defaultEmailAggregationWindow = 60 // seconds
+	maxEmailAggregationWindow     = 24 * 60 * 60
+)
+
+// emailTargetRequest is the JSON body for creating or updating an email target.
+// On update, empty fields and nil pointers leave the stored value unchanged.
+type emailTargetRequest struct {
+	Name                     string   `json:"name"`
+	Host                     string   `json:"host"`
+	Port                     int      `json:"port,omitempty"`
+	Username                 string   `json:"username,omitempty"`
+	Password                 string   `json:"password,omitempty"`
+	TLSMode                  string   `json:"tls_mode,omitempty"`
+	From                     string   `json:"from"`
+	To                       []string `json:"to"`
+	SubjectTemplate          *string  `json:"subject_template,omitempty"`
+	BodyTemplate             *string  `json:"body_template,omitempty"`
+	AggregationWindowSeconds *int     `json:"aggregation_window_seconds,omitempty"`
+	Enabled                  *bool    `json:"enabled,omitempty"`
+}
+
+// handleListEmailTargets returns all alert email targets.
+//
+//	@Summary		List email targets
+//	@Description	Returns all SMTP alert email targets. Passwords are masked.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		EmailTarget
+//	@Router			/dispatch/email-targets [get]
+func (m *Module) handleListEmailTargets(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	targets, err := m.store.ListEmailTargets(r.Context())
+	if err != nil {
+		m.logger.Warn("failed to list email targets", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to list email targets")
+		return
+	}
+	if targets == nil {
+		targets = []EmailTarget{}
+	}
+	for i := range targets {
+		maskEmailTarget(&targets[i])
+	}
+	dispatchWriteJSON(w, http.StatusOK, targets)
+}
+
+// handleGetEmailTarget returns a single alert email target.
+//
+//	@Summary		Get email target
+//	@Description	Returns an SMTP alert email target by ID. The password is masked.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Email target ID"
+//	@Success		200	{object}	EmailTarget
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/email-targets/{id} [get]
+func (m *Module) handleGetEmailTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	target, err := m.store.GetEmailTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get email target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get email target")
+		return
+	}
+	if target == nil {
+		dispatchWriteError(w, http.StatusNotFound, "email target not found")
+		return
+	}
+	maskEmailTarget(target)
+	dispatchWriteJSON(w, http.StatusOK, target)
+}
+
+// handleCreateEmailTarget creates an alert email target.
+//
+//	@Summary		Create email target
+//	@Description	Registers an SMTP server and recipients that receive pulse alert events by email.
+//	@Description	Events arriving within aggregation_window_seconds (default 60) are sent as one message.
+//	@Description	Subject and body are Go text/template strings; empty templates use the defaults.
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body	body		emailTargetRequest	true	"Email target"
+//	@Success		201		{object}	EmailTarget
+//	@Failure		400		{object}	models.APIProblem
+//	@Router			/dispatch/email-targets [post]
+func (m *Module) handleCreateEmailTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	var req emailTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		dispatchWriteError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	now := time.Now().UTC()
+	target := &EmailTarget{
+		ID:                       uuid.New().String(),
+		Name:                     req.Name,
+		Host:                     req.Host,
+		Port:                     req.Port,
+		Username:                 req.Username,
+		Password:                 req.Password,
+		TLSMode:                  req.TLSMode,
+		From:                     req.From,
+		To:                       req.To,
+		AggregationWindowSeconds: defaultEmailAggregationWindow,
+		Enabled:                  req.Enabled == nil || *req.Enabled,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+	}
+	if target.Port == 0 {
+		target.Port = defaultEmailPort
+	}
+	if target.TLSMode == "" {
+		target.TLSMode = EmailTLSStartTLS
+	}
+	if req.SubjectTemplate != nil {
+		target.SubjectTemplate = *req.SubjectTemplate
+	}
+	if req.BodyTemplate != nil {
+		target.BodyTemplate = *req.BodyTemplate
+	}
+	if req.AggregationWindowSeconds != nil {
+		target.AggregationWindowSeconds = *req.AggregationWindowSeconds
+	}
+	if msg := validateEmailTarget(target); msg != "" {
+		dispatchWriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	if err := m.store.CreateEmailTarget(r.Context(), target); err != nil {
+		m.logger.Warn("failed to create email target", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to create email target")
+		return
+	}
+
+	maskEmailTarget(target)
+	dispatchWriteJSON(w, http.StatusCreated, target)
+}
+
+// handleUpdateEmailTarget updates an alert email target.
+//
+//	@Summary		Update email target
+//	@Description	Updates fields on an SMTP alert email target. Omitted fields are unchanged.
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string				true	"Email target ID"
+//	@Param			body	body		emailTargetRequest	true	"Fields to update"
+//	@Success		200		{object}	EmailTarget
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Router			/dispatch/email-targets/{id} [put]
+func (m *Module) handleUpdateEmailTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	existing, err := m.store.GetEmailTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get email target for update", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get email target")
+		return
+	}
+	if existing == nil {
+		dispatchWriteError(w, http.StatusNotFound, "email target not found")
+		return
+	}
+
+	var req emailTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name != "" {
+		existing.Name = req.Name
+	}
+	if req.Host != "" {
+		existing.Host = req.Host
+	}
+	if req.Port != 0 {
+		existing.Port = req.Port
+	}
+	if req.Username != "" {
+		existing.Username = req.Username
+	}
+	// A masked password echoed back from a GET keeps the stored value.
+	if req.Password != "" && req.Password != maskedValue {
+		existing.Password = req.Password
+	}
+	if req.TLSMode != "" {
+		existing.TLSMode = req.TLSMode
+	}
+	if req.From != "" {
+		existing.From = req.From
+	}
+	if req.To != nil {
+		existing.To = req.To
+	}
+	if req.SubjectTemplate != nil {
+		existing.SubjectTemplate = *req.SubjectTemplate
+	}
+	if req.BodyTemplate != nil {
+		existing.BodyTemplate = *req.BodyTemplate
+	}
+	if req.AggregationWindowSeconds != nil {
+		existing.AggregationWindowSeconds = *req.AggregationWindowSeconds
+	}
+	if req.Enabled != nil {
+		existing.Enabled = *req.Enabled
+	}
+	if msg := validateEmailTarget(existing); msg != "" {
+		dispatchWriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.UpdateEmailTarget(r.Context(), existing); err != nil {
+		m.logger.Warn("failed to update email target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to update email target")
+		return
+	}
+
+	maskEmailTarget(existing)
+	dispatchWriteJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteEmailTarget removes an alert email target.
+//
+//	@Summary		Delete email target
+//	@Description	Removes an SMTP alert email target by ID.
+//	@Tags			dispatch
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Email target ID"
+//	@Success		204
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/email-targets/{id} [delete]
+func (m *Module) handleDeleteEmailTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := m.store.DeleteEmailTarget(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			dispatchWriteError(w, http.StatusNotFound, "email target not found")
+			return
+		}
+		m.logger.Warn("failed to delete email target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to delete email target")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestEmailTarget sends a test email to an email target.
+//
+//	@Summary		Test email target
+//	@Description	Sends a synthetic alert to the target's recipients immediately, through the same composition
+//	@Description	and sender as real alerts, and reports the result of a single attempt bounded to 5 seconds.
+//	@Description	A failed delivery is reported in the response body rather than as an error status.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Email target ID"
+//	@Success		200	{object}	TestDelivery
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/email-targets/{id}/test [post]
+func (m *Module) handleTestEmailTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil || m.email == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	target, err := m.store.GetEmailTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get email target for test", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get email target")
+		return
+	}
+	if target == nil {
+		dispatchWriteError(w, http.StatusNotFound, "email target not found")
+		return
+	}
+
+	result := m.email.SendTest(r.Context(), target)
+	m.logger.Info("sent test email",
+		zap.String("target_id", id),
+		zap.Bool("delivered", result.Delivered),
+		zap.Int("attempts", result.Attempts),
+	)
+	dispatchWriteJSON(w, http.StatusOK, result)
+}
+
+// maskEmailTarget hides the SMTP password before a target is returned to
+// API clients.
+func maskEmailTarget(t *EmailTarget) {
+	if t.Password != "" {
+		t.Password = maskedValue
+	}
+}
+
+// validateEmailTarget returns a problem detail for an invalid target, or "".
+func validateEmailTarget(t *EmailTarget) string {
+	if t.Host == "" {
+		return "host is required"
+	}
+	if t.Port < 1 || t.Port > 65535 {
+		return "port must be between 1 and 65535"
+	}
+	switch t.TLSMode {
+	case EmailTLSStartTLS, EmailTLSImplicit:
+	case EmailTLSNone:
+		if t.Username != "" {
+			return "tls_mode none cannot be used with a username; credentials are never sent unencrypted"
+		}
+	default:
+		return "tls_mode must be starttls, implicit, or none"
+	}
+	if _, err := mail.ParseAddress(t.From); err != nil {
+		return "from must be a valid email address"
+	}
+	if len(t.To) == 0 {
+		return "to must list at least one recipient"
+	}
+	for _, rcpt := range t.To {
+		if _, err := mail.ParseAddress(rcpt); err != nil {
+			return "to contains an invalid email address: " + rcpt
+		}
+	}
+	if t.AggregationWindowSeconds < 0 || t.AggregationWindowSeconds > maxEmailAggregationWindow {
+		return "aggregation_window_seconds must be between 0 and 86400"
+	}
+	if _, _, err := parseEmailTemplates(t); err != nil {
+		return err.Error()
+	}
+	return ""
+}
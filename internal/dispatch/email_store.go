@@ -0,0 +1,168 @@
+package dispatch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// SMTP connection security modes for email targets.
+const (
+	EmailTLSStartTLS = "starttls" // Plain connection upgraded with STARTTLS (port 587)
+	EmailTLSImplicit = "implicit" // TLS from the first byte (port 465)
+	EmailTLSNone     = "none"     // No encryption; only for local relays
+)
+
+// EmailTarget is a user-configured SMTP server and recipient list that
+// receives pulse alert events by email.
+type EmailTarget struct {
+	ID                       string    `json:"id"`
+	Name                     string    `json:"name"`
+	Host                     string    `json:"host"`
+	Port                     int       `json:"port"`
+	Username                 string    `json:"username,omitempty"`
+	Password                 string    `json:"password,omitempty"`
+	TLSMode                  string    `json:"tls_mode"` // starttls, implicit, none
+	From                     string    `json:"from"`
+	To                       []string  `json:"to"`
+	SubjectTemplate          string    `json:"subject_template,omitempty"` // Go text/template; empty uses the default
+	BodyTemplate             string    `json:"body_template,omitempty"`    // Go text/template; empty uses the default
+	AggregationWindowSeconds int       `json:"aggregation_window_seconds"` // 0 sends one email per alert event
+	Enabled                  bool      `json:"enabled"`
+	CreatedAt                time.Time `json:"created_at"`
+	UpdatedAt                time.Time `json:"updated_at"`
+}
+
+// AggregationWindow returns how long alert events are collected before
+// they are sent together.
+func (t *EmailTarget) AggregationWindow() time.Duration {
+	return time.Duration(t.AggregationWindowSeconds) * time.Second
+}
+
+const emailTargetColumns = `id, name, host, port, username, password, tls_mode, from_address,
+	recipients_json, subject_template, body_template, aggregation_window_seconds,
+	enabled, created_at, updated_at`
+
+// CreateEmailTarget inserts a new email target.
+func (s *DispatchStore) CreateEmailTarget(ctx context.Context, t *EmailTarget) error {
+	recipients, err := json.Marshal(t.To)
+	if err != nil {
+		return fmt.Errorf("marshal email recipients: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO dispatch_email_targets (`+emailTargetColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, t.Name, t.Host, t.Port, t.Username, t.Password, t.TLSMode, t.From,
+		string(recipients), t.SubjectTemplate, t.BodyTemplate, t.AggregationWindowSeconds,
+		boolToInt(t.Enabled), t.CreatedAt, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert email target: %w", err)
+	}
+	return nil
+}
+
+// GetEmailTarget returns an email target by ID. Returns nil, nil if not found.
+func (s *DispatchStore) GetEmailTarget(ctx context.Context, id string) (*EmailTarget, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+emailTargetColumns+` FROM dispatch_email_targets WHERE id = ?`, id)
+	t, err := scanEmailTarget(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get email target: %w", err)
+	}
+	return t, nil
+}
+
+// ListEmailTargets returns all email targets ordered by creation time.
+func (s *DispatchStore) ListEmailTargets(ctx context.Context) ([]EmailTarget, error) {
+	return s.queryEmailTargets(ctx, `
+		SELECT `+emailTargetColumns+` FROM dispatch_email_targets ORDER BY created_at`)
+}
+
+// ListEnabledEmailTargets returns the email targets that should receive alerts.
+func (s *DispatchStore) ListEnabledEmailTargets(ctx context.Context) ([]EmailTarget, error) {
+	return s.queryEmailTargets(ctx, `
+		SELECT `+emailTargetColumns+` FROM dispatch_email_targets WHERE enabled = 1 ORDER BY created_at`)
+}
+
+// UpdateEmailTarget replaces the mutable fields of an email target.
+// Returns sql.ErrNoRows if the target does not exist.
+func (s *DispatchStore) UpdateEmailTarget(ctx context.Context, t *EmailTarget) error {
+	recipients, err := json.Marshal(t.To)
+	if err != nil {
+		return fmt.Errorf("marshal email recipients: %w", err)
+	}
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE dispatch_email_targets
+		SET name = ?, host = ?, port = ?, username = ?, password = ?, tls_mode = ?, from_address = ?,
+			recipients_json = ?, subject_template = ?, body_template = ?, aggregation_window_seconds = ?,
+			enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		t.Name, t.Host, t.Port, t.Username, t.Password, t.TLSMode, t.From,
+		string(recipients), t.SubjectTemplate, t.BodyTemplate, t.AggregationWindowSeconds,
+		boolToInt(t.Enabled), t.UpdatedAt, t.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update email target: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteEmailTarget removes an email target.
+// Returns sql.ErrNoRows if the target does not exist.
+func (s *DispatchStore) DeleteEmailTarget(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM dispatch_email_targets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete email target: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *DispatchStore) queryEmailTargets(ctx context.Context, query string) ([]EmailTarget, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list email targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []EmailTarget
+	for rows.Next() {
+		t, err := scanEmailTarget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan email target: %w", err)
+		}
+		targets = append(targets, *t)
+	}
+	return targets, rows.Err()
+}
+
+func scanEmailTarget(row webhookScanner) (*EmailTarget, error) {
+	var t EmailTarget
+	var recipients string
+	var enabled int
+	if err := row.Scan(
+		&t.ID, &t.Name, &t.Host, &t.Port, &t.Username, &t.Password, &t.TLSMode, &t.From,
+		&recipients, &t.SubjectTemplate, &t.BodyTemplate, &t.AggregationWindowSeconds,
+		&enabled, &t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	t.Enabled = enabled != 0
+	if err := json.Unmarshal([]byte(recipients), &t.To); err != nil {
+		return nil, fmt.Errorf("unmarshal email recipients: %w", err)
+	}
+	return &t, nil
+}
@@ -0,0 +1,447 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// emailFlushInterval is how often the worker sends batches whose
+// aggregation window has closed.
+const emailFlushInterval = time.Second
+
+// Default templates used when a target leaves its own empty.
+const (
+	defaultEmailSubject = `[SubNetree] {{if eq .Count 1}}{{with index .Alerts 0}}{{.Event | title}}: {{.Severity | upper}} {{.Device}}{{end}}{{else}}{{.Count}} alert events{{end}}`
+	defaultEmailBody    = `{{range .Alerts}}[{{.Event | title}}] {{.Severity | upper}} {{.Device}}
+  {{.Message}}
+  Triggered: {{.TriggeredAt.Format "2006-01-02 15:04:05 MST"}}{{with .ResolvedAt}}
+  Resolved:  {{.Format "2006-01-02 15:04:05 MST"}}{{end}}
+
+{{end}}--
+Sent by SubNetree.
+`
+)
+
+// emailTemplateFuncs are available to subject and body templates.
+var emailTemplateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"title": func(s string) string {
+		if s == "" {
+			return s
+		}
+		return strings.ToUpper(s[:1]) + s[1:]
+	},
+}
+
+// EmailMessage is a composed plain-text email.
+type EmailMessage struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Bytes renders the message in RFC 5322 wire format.
+func (m *EmailMessage) Bytes() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// MailSender delivers a composed message through a target's SMTP server.
+type MailSender interface {
+	Send(ctx context.Context, target *EmailTarget, msg *EmailMessage) error
+}
+
+// emailAlert is the alert representation available to email templates.
+// Like webhookAlert it is built field by field with secrets redacted.
+type emailAlert struct {
+	Event       string // triggered, resolved, escalated
+	ID          string
+	Severity    string
+	DeviceID    string
+	Device      string // Device name, or ID when unnamed
+	Message     string
+	TriggeredAt time.Time
+	ResolvedAt  *time.Time
+}
+
+// emailTemplateData is the data passed to email templates.
+type emailTemplateData struct {
+	Count  int
+	Alerts []emailAlert
+}
+
+// composeEmail renders the target's templates for a batch of alert events.
+func composeEmail(target *EmailTarget, events []alertEvent) (*EmailMessage, error) {
+	subjectTmpl, bodyTmpl, err := parseEmailTemplates(target)
+	if err != nil {
+		return nil, err
+	}
+
+	data := emailTemplateData{Count: len(events)}
+	for _, ev := range events {
+		device := redactSecrets(ev.alert.DeviceName)
+		if device == "" {
+			device = ev.alert.DeviceID
+		}
+		data.Alerts = append(data.Alerts, emailAlert{
+			Event:       strings.TrimPrefix(ev.eventType, "pulse.alert."),
+			ID:          ev.alert.ID,
+			Severity:    ev.alert.Severity,
+			DeviceID:    ev.alert.DeviceID,
+			Device:      device,
+			Message:     redactSecrets(ev.alert.Message),
+			TriggeredAt: ev.alert.TriggeredAt,
+			ResolvedAt:  ev.alert.ResolvedAt,
+		})
+	}
+
+	var subject, body bytes.Buffer
+	if err := subjectTmpl.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("render email subject: %w", err)
+	}
+	if err := bodyTmpl.Execute(&body, data); err != nil {
+		return nil, fmt.Errorf("render email body: %w", err)
+	}
+	// Header values must stay on one line.
+	oneLine := strings.Join(strings.Fields(subject.String()), " ")
+	return &EmailMessage{From: target.From, To: target.To, Subject: oneLine, Body: body.String()}, nil
+}
+
+// parseEmailTemplates parses the target's templates, falling back to the
+// defaults for empty ones.
+func parseEmailTemplates(target *EmailTarget) (subject, body *template.Template, err error) {
+	subjectSrc, bodySrc := target.SubjectTemplate, target.BodyTemplate
+	if subjectSrc == "" {
+		subjectSrc = defaultEmailSubject
+	}
+	if bodySrc == "" {
+		bodySrc = defaultEmailBody
+	}
+	subject, err = template.New("subject").Funcs(emailTemplateFuncs).Parse(subjectSrc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse subject template: %w", err)
+	}
+	body, err = template.New("body").Funcs(emailTemplateFuncs).Parse(bodySrc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse body template: %w", err)
+	}
+	return subject, body, nil
+}
+
+// emailBatch collects alert events for one target until its window closes.
+type emailBatch struct {
+	target EmailTarget
+	events []alertEvent
+	due    time.Time
+}
+
+// AlertEmailWorker sends pulse alert events to the enabled email targets.
+// Events for a target are collected for the target's aggregation window
+// and sent as one message, so a flapping device produces one email per
+// window rather than one per transition.
+type AlertEmailWorker struct {
+	store  *DispatchStore
+	sender MailSender
+	cfg    EmailConfig
+	logger *zap.Logger
+	queue  chan alertEvent
+
+	// batches is owned by the delivery loop.
+	batches map[string]*emailBatch
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertEmailWorker creates a worker that sends alerts with sender. A nil
+// sender uses SMTP.
+func NewAlertEmailWorker(store *DispatchStore, sender MailSender, cfg EmailConfig, logger *zap.Logger) *AlertEmailWorker {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	if sender == nil {
+		sender = &smtpSender{timeout: cfg.Timeout}
+	}
+	return &AlertEmailWorker{
+		store:   store,
+		sender:  sender,
+		cfg:     cfg,
+		logger:  logger,
+		queue:   make(chan alertEvent, cfg.QueueSize),
+		batches: make(map[string]*emailBatch),
+	}
+}
+
+// Start launches the delivery loop. It returns immediately.
+func (w *AlertEmailWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(emailFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-w.queue:
+				w.collect(ctx, ev, time.Now())
+			case now := <-ticker.C:
+				w.flushDue(ctx, now)
+			}
+		}
+	}()
+}
+
+// Stop cancels in-flight deliveries and waits for the delivery loop to
+// exit. Batches still inside their window are discarded.
+func (w *AlertEmailWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// Enqueue queues an alert event for delivery. It never blocks; events are
+// dropped with a warning when the queue is full.
+func (w *AlertEmailWorker) Enqueue(eventType string, alert *pulse.Alert) bool {
+	select {
+	case w.queue <- alertEvent{eventType: eventType, alert: alert, at: time.Now().UTC()}:
+		return true
+	default:
+		w.logger.Warn("email queue full, dropping alert event",
+			zap.String("event_type", eventType),
+			zap.String("alert_id", alert.ID),
+		)
+		return false
+	}
+}
+
+// collect adds an alert event to the batch of every enabled target,
+// opening a batch that closes after the target's window when none is open.
+func (w *AlertEmailWorker) collect(ctx context.Context, ev alertEvent, now time.Time) {
+	targets, err := w.store.ListEnabledEmailTargets(ctx)
+	if err != nil {
+		w.logger.Warn("failed to list email targets", zap.Error(err))
+		return
+	}
+	for i := range targets {
+		t := &targets[i]
+		b, open := w.batches[t.ID]
+		if !open {
+			b = &emailBatch{target: *t, due: now.Add(t.AggregationWindow())}
+			w.batches[t.ID] = b
+		}
+		b.events = append(b.events, ev)
+	}
+}
+
+// flushDue sends every batch whose window has closed by now.
+func (w *AlertEmailWorker) flushDue(ctx context.Context, now time.Time) {
+	for id, b := range w.batches {
+		if now.Before(b.due) {
+			continue
+		}
+		delete(w.batches, id)
+		w.sendBatch(ctx, b)
+	}
+}
+
+// sendBatch composes and sends one batch, logging the outcome.
+func (w *AlertEmailWorker) sendBatch(ctx context.Context, b *emailBatch) {
+	msg, err := composeEmail(&b.target, b.events)
+	if err != nil {
+		w.logger.Warn("failed to compose alert email", zap.String("target_id", b.target.ID), zap.Error(err))
+		return
+	}
+	attempts, err := w.sendWithRetry(ctx, &b.target, msg)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Warn("alert email delivery failed",
+				zap.String("target_id", b.target.ID),
+				zap.Int("events", len(b.events)),
+				zap.Int("attempts", attempts),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+	w.logger.Debug("sent alert email",
+		zap.String("target_id", b.target.ID),
+		zap.Int("events", len(b.events)),
+	)
+}
+
+// sendWithRetry sends msg until it succeeds or MaxAttempts is reached,
+// backing off like webhook deliveries. It returns the number of attempts made.
+func (w *AlertEmailWorker) sendWithRetry(ctx context.Context, target *EmailTarget, msg *EmailMessage) (int, error) {
+	var lastErr error
+	for attempt := 1; attempt <= w.cfg.MaxAttempts; attempt++ {
+		lastErr = w.sender.Send(ctx, target, msg)
+		if lastErr == nil || attempt == w.cfg.MaxAttempts {
+			return attempt, lastErr
+		}
+
+		timer := time.NewTimer(webhookBackoff(w.cfg.InitialBackoff, w.cfg.MaxBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return w.cfg.MaxAttempts, lastErr
+}
+
+// SendTest sends a synthetic alert to target immediately, bypassing the
+// aggregation window but using the same composition and sender as real
+// alerts. Like a webhook test it makes a single attempt bounded by
+// testDeliveryTimeout, so an unreachable server or rejected credentials
+// are reported rather than retried.
+func (w *AlertEmailWorker) SendTest(ctx context.Context, target *EmailTarget) TestDelivery {
+	now := time.Now().UTC()
+	ev := alertEvent{
+		eventType: pulse.TopicAlertTriggered,
+		alert: &pulse.Alert{
+			ID:          "test-" + uuid.NewString(),
+			CheckID:     "test",
+			DeviceID:    "test",
+			DeviceName:  "SubNetree test",
+			Severity:    "info",
+			Message:     "Test notification from SubNetree. No action is needed.",
+			TriggeredAt: now,
+		},
+		at: now,
+	}
+	msg, err := composeEmail(target, []alertEvent{ev})
+	if err != nil {
+		return TestDelivery{Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testDeliveryTimeout)
+	defer cancel()
+	start := time.Now()
+	err = w.sender.Send(ctx, target, msg)
+	result := TestDelivery{
+		Delivered: err == nil,
+		Attempts:  1,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Error = redactSecrets(err.Error())
+	}
+	return result
+}
+
+// smtpSender delivers messages with net/smtp.
+type smtpSender struct {
+	timeout time.Duration
+}
+
+// Send connects to the target's server, secures the connection as the
+// target's TLS mode requires, authenticates when a username is set, and
+// submits the message to every recipient.
+func (s *smtpSender) Send(ctx context.Context, target *EmailTarget, msg *EmailMessage) error {
+	addr := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+	tlsConfig := &tls.Config{ServerName: target.Host, MinVersion: tls.VersionTLS12}
+
+	dialer := &net.Dialer{Timeout: s.timeout}
+	var conn net.Conn
+	var err error
+	if target.TLSMode == EmailTLSImplicit {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to SMTP server: %w", err)
+	}
+	// The session must finish within both the configured timeout and the
+	// caller's deadline.
+	var deadline time.Time
+	if s.timeout > 0 {
+		deadline = time.Now().Add(s.timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	if !deadline.IsZero() {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, target.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("start SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if target.TLSMode == EmailTLSStartTLS {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("SMTP STARTTLS: %w", err)
+		}
+	}
+	if target.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", target.Username, target.Password, target.Host)); err != nil {
+			return fmt.Errorf("SMTP auth: %w", err)
+		}
+	}
+	if err := c.Mail(envelopeAddress(msg.From)); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM: %w", err)
+	}
+	for _, rcpt := range msg.To {
+		if err := c.Rcpt(envelopeAddress(rcpt)); err != nil {
+			return fmt.Errorf("SMTP RCPT TO %s: %w", rcpt, err)
+		}
+	}
+	wc, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if _, err := wc.Write(msg.Bytes()); err != nil {
+		wc.Close()
+		return fmt.Errorf("write SMTP message: %w", err)
+	}
+	if err := wc.Close(); err != nil {
+		return fmt.Errorf("finish SMTP message: %w", err)
+	}
+	if err := c.Quit(); err != nil && !errors.Is(err, net.ErrClosed) {
+		return fmt.Errorf("SMTP QUIT: %w", err)
+	}
+	return nil
+}
+
+// envelopeAddress strips any display name from addr ("Ops <ops@example.com>"
+// becomes "ops@example.com") for use in SMTP commands.
+func envelopeAddress(addr string) string {
+	if a, err := mail.ParseAddress(addr); err == nil {
+		return a.Address
+	}
+	return addr
+}
@@ -0,0 +1,404 @@
+package dispatch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"go.uber.org/zap"
+)
+
+// recordingSender records sent messages and fails the first failures sends.
+type recordingSender struct {
+	mu       sync.Mutex
+	messages []*EmailMessage
+	failures int
+}
+
+func (s *recordingSender) Send(_ context.Context, _ *EmailTarget, msg *EmailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("535 authentication failed")
+	}
+	s.messages = append(s.messages, msg)
+	return nil
+}
+
+func makeEmailTarget(t *testing.T, s *DispatchStore, id string, windowSeconds int) *EmailTarget {
+	t.Helper()
+	now := time.Now().UTC()
+	target := &EmailTarget{
+		ID:                       id,
+		Name:                     "email " + id,
+		Host:                     "smtp.example.com",
+		Port:                     587,
+		TLSMode:                  EmailTLSStartTLS,
+		From:                     "SubNetree <alerts@example.com>",
+		To:                       []string{"ops@example.com", "oncall@example.com"},
+		AggregationWindowSeconds: windowSeconds,
+		Enabled:                  true,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+	}
+	if err := s.CreateEmailTarget(context.Background(), target); err != nil {
+		t.Fatalf("CreateEmailTarget: %v", err)
+	}
+	return target
+}
+
+func testEmailWorker(s *DispatchStore, sender MailSender) *AlertEmailWorker {
+	return NewAlertEmailWorker(s, sender, EmailConfig{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Timeout:        time.Second,
+		QueueSize:      8,
+	}, zap.NewNop())
+}
+
+func TestDispatchStore_EmailTargetCRUD(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	target := makeEmailTarget(t, s, "em-1", 60)
+
+	got, err := s.GetEmailTarget(ctx, "em-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetEmailTarget = %v, %v", got, err)
+	}
+	if len(got.To) != 2 || got.To[1] != "oncall@example.com" || got.AggregationWindow() != time.Minute {
+		t.Errorf("got %+v", got)
+	}
+
+	target.Enabled = false
+	target.To = []string{"ops@example.com"}
+	if err := s.UpdateEmailTarget(ctx, target); err != nil {
+		t.Fatalf("UpdateEmailTarget: %v", err)
+	}
+	enabled, err := s.ListEnabledEmailTargets(ctx)
+	if err != nil {
+		t.Fatalf("ListEnabledEmailTargets: %v", err)
+	}
+	if len(enabled) != 0 {
+		t.Errorf("enabled targets = %d, want 0", len(enabled))
+	}
+
+	if err := s.DeleteEmailTarget(ctx, "em-1"); err != nil {
+		t.Fatalf("DeleteEmailTarget: %v", err)
+	}
+	if got, _ := s.GetEmailTarget(ctx, "em-1"); got != nil {
+		t.Error("target still present after delete")
+	}
+}
+
+func TestComposeEmail_AlertFields(t *testing.T) {
+	target := &EmailTarget{From: "alerts@example.com", To: []string{"ops@example.com"}}
+	ev := testAlertEvent(pulse.TopicAlertTriggered, "ping failed: token=abc123")
+
+	msg, err := composeEmail(target, []alertEvent{ev})
+	if err != nil {
+		t.Fatalf("composeEmail: %v", err)
+	}
+	if msg.Subject != "[SubNetree] Triggered: CRITICAL router" {
+		t.Errorf("Subject = %q", msg.Subject)
+	}
+	for _, want := range []string{"[Triggered] CRITICAL router", "ping failed: token=****", "Triggered: "} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, msg.Body)
+		}
+	}
+	if strings.Contains(msg.Body, "abc123") {
+		t.Error("body leaks the redacted secret")
+	}
+
+	wire := string(msg.Bytes())
+	for _, want := range []string{"From: alerts@example.com\r\n", "To: ops@example.com\r\n", "Subject: [SubNetree] Triggered: CRITICAL router\r\n"} {
+		if !strings.Contains(wire, want) {
+			t.Errorf("message missing header %q", want)
+		}
+	}
+
+	target.SubjectTemplate = "{{range .Alerts}}{{.DeviceID}}/{{.Severity}}\n{{end}}"
+	target.BodyTemplate = "{{(index .Alerts 0).ID}}"
+	msg, err = composeEmail(target, []alertEvent{ev})
+	if err != nil {
+		t.Fatalf("composeEmail (custom): %v", err)
+	}
+	if msg.Subject != "dev-1/critical" || msg.Body != "alert-1" {
+		t.Errorf("custom templates rendered subject %q, body %q", msg.Subject, msg.Body)
+	}
+}
+
+func TestAlertEmailWorker_AggregatesWithinWindow(t *testing.T) {
+	s := testStore(t)
+	makeEmailTarget(t, s, "em-1", 60)
+	sender := &recordingSender{}
+	w := testEmailWorker(s, sender)
+	ctx := context.Background()
+	start := time.Now()
+
+	// A flapping device: trigger, resolve, trigger within one window.
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertTriggered, "down"), start)
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertResolved, "down"), start.Add(10*time.Second))
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertTriggered, "down again"), start.Add(20*time.Second))
+
+	w.flushDue(ctx, start.Add(30*time.Second))
+	if len(sender.messages) != 0 {
+		t.Fatalf("sent %d emails before the window closed, want 0", len(sender.messages))
+	}
+
+	w.flushDue(ctx, start.Add(61*time.Second))
+	if len(sender.messages) != 1 {
+		t.Fatalf("sent %d emails, want 1 batched email", len(sender.messages))
+	}
+	msg := sender.messages[0]
+	if msg.Subject != "[SubNetree] 3 alert events" {
+		t.Errorf("Subject = %q, want the batch count", msg.Subject)
+	}
+	for _, want := range []string{"[Triggered]", "[Resolved]", "down again"} {
+		if !strings.Contains(msg.Body, want) {
+			t.Errorf("body missing %q:\n%s", want, msg.Body)
+		}
+	}
+	if len(msg.To) != 2 {
+		t.Errorf("recipients = %v, want both", msg.To)
+	}
+
+	// The next event opens a new window.
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertResolved, "up"), start.Add(90*time.Second))
+	w.flushDue(ctx, start.Add(151*time.Second))
+	if len(sender.messages) != 2 {
+		t.Errorf("sent %d emails, want a second email for the new window", len(sender.messages))
+	}
+}
+
+func TestAlertEmailWorker_NoWindowSendsEachEvent(t *testing.T) {
+	s := testStore(t)
+	makeEmailTarget(t, s, "em-1", 0)
+	sender := &recordingSender{failures: 1}
+	w := testEmailWorker(s, sender)
+	ctx := context.Background()
+	now := time.Now()
+
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertTriggered, "down"), now)
+	w.flushDue(ctx, now)
+	w.collect(ctx, testAlertEvent(pulse.TopicAlertResolved, "down"), now)
+	w.flushDue(ctx, now)
+
+	// The first send fails once and is retried.
+	if len(sender.messages) != 2 {
+		t.Errorf("sent %d emails, want 2", len(sender.messages))
+	}
+}
+
+func TestHandleTestEmailTarget(t *testing.T) {
+	s := testStore(t)
+	makeEmailTarget(t, s, "em-1", 60)
+	sender := &recordingSender{}
+	m := &Module{logger: zap.NewNop(), store: s, email: testEmailWorker(s, sender)}
+
+	sendTest := func() TestDelivery {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodPost, "/email-targets/em-1/test", http.NoBody)
+		req.SetPathValue("id", "em-1")
+		rec := httptest.NewRecorder()
+		m.handleTestEmailTarget(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+		}
+		var result TestDelivery
+		if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return result
+	}
+
+	// The test bypasses the aggregation window.
+	if result := sendTest(); !result.Delivered || result.Attempts != 1 {
+		t.Errorf("result = %+v, want delivered on the first attempt", result)
+	}
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0].Body, "Test notification") {
+		t.Fatalf("messages = %+v, want one test email", sender.messages)
+	}
+
+	sender.failures = 3
+	result := sendTest()
+	if result.Delivered || result.Attempts != 1 || !strings.Contains(result.Error, "535") {
+		t.Errorf("result = %+v, want the SMTP error from a single attempt", result)
+	}
+	if sender.failures != 2 {
+		t.Errorf("send attempts = %d, want 1", 3-sender.failures)
+	}
+}
+
+func TestHandleCreateEmailTarget_Validation(t *testing.T) {
+	s := testStore(t)
+	m := &Module{logger: zap.NewNop(), store: s}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"valid", `{"name":"ops","host":"smtp.example.com","from":"alerts@example.com","to":["ops@example.com"]}`, http.StatusCreated},
+		{"missing host", `{"name":"ops","from":"alerts@example.com","to":["ops@example.com"]}`, http.StatusBadRequest},
+		{"no recipients", `{"name":"ops","host":"smtp.example.com","from":"alerts@example.com"}`, http.StatusBadRequest},
+		{"bad recipient", `{"name":"ops","host":"smtp.example.com","from":"alerts@example.com","to":["nope"]}`, http.StatusBadRequest},
+		{"bad tls mode", `{"name":"ops","host":"smtp.example.com","tls_mode":"ssl","from":"alerts@example.com","to":["ops@example.com"]}`, http.StatusBadRequest},
+		{"auth without tls", `{"name":"ops","host":"smtp.example.com","tls_mode":"none","username":"u","from":"alerts@example.com","to":["ops@example.com"]}`, http.StatusBadRequest},
+		{"bad template", `{"name":"ops","host":"smtp.example.com","from":"alerts@example.com","to":["ops@example.com"],"subject_template":"{{.Nope"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/email-targets", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			m.handleCreateEmailTarget(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+// fakeSMTPServer accepts one SMTP session and records the envelope and data.
+type fakeSMTPServer struct {
+	ln   net.Listener
+	done chan struct{}
+	from string
+	rcpt []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	srv := &fakeSMTPServer{ln: ln, done: make(chan struct{})}
+	t.Cleanup(func() { ln.Close() })
+	go srv.serve()
+	return srv
+}
+
+func (s *fakeSMTPServer) serve() {
+	defer close(s.done)
+	conn, err := s.ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		switch upper := strings.ToUpper(cmd); {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.rcpt = append(s.rcpt, strings.Trim(cmd[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 go ahead")
+			var b strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				b.WriteString(l)
+			}
+			s.data = b.String()
+			reply("250 queued")
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPSender_HonoursContextDeadline(t *testing.T) {
+	// A server that accepts the connection but never greets.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	host, portStr, _ := net.SplitHostPort(ln.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	target := &EmailTarget{Host: host, Port: port, TLSMode: EmailTLSNone, From: "alerts@example.com", To: []string{"ops@example.com"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = (&smtpSender{timeout: 30 * time.Second}).Send(ctx, target, &EmailMessage{From: target.From, To: target.To})
+	if err == nil {
+		t.Fatal("Send to a silent server: expected error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send took %v, want it bounded by the context deadline", elapsed)
+	}
+}
+
+func TestSMTPSender_DeliversToAllRecipients(t *testing.T) {
+	srv := newFakeSMTPServer(t)
+	host, portStr, _ := net.SplitHostPort(srv.ln.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+	target := &EmailTarget{
+		Host:    host,
+		Port:    port,
+		TLSMode: EmailTLSNone,
+		From:    "SubNetree <alerts@example.com>",
+		To:      []string{"ops@example.com", "Oncall <oncall@example.com>"},
+	}
+	msg, err := composeEmail(target, []alertEvent{testAlertEvent(pulse.TopicAlertTriggered, "ping failed")})
+	if err != nil {
+		t.Fatalf("composeEmail: %v", err)
+	}
+
+	if err := (&smtpSender{timeout: 5 * time.Second}).Send(context.Background(), target, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	<-srv.done
+
+	if srv.from != "alerts@example.com" {
+		t.Errorf("MAIL FROM = %q, want the bare address", srv.from)
+	}
+	if len(srv.rcpt) != 2 || srv.rcpt[1] != "oncall@example.com" {
+		t.Errorf("RCPT TO = %v, want both bare addresses", srv.rcpt)
+	}
+	if !strings.Contains(srv.data, "Subject: [SubNetree] Triggered: CRITICAL router\r\n") ||
+		!strings.Contains(srv.data, "ping failed") {
+		t.Errorf("message data missing alert fields:\n%s", srv.data)
+	}
+}
@@ -26,6 +26,24 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "GET", Path: "/install/{platform}/{arch}", Handler: m.handleInstallScript},
 		{Method: "GET", Path: "/download/{platform}/{arch}", Handler: m.handleDownloadRedirect},
 		{Method: "GET", Path: "/updates/latest", Handler: m.handleGetUpdateManifest},
+		{Method: "GET", Path: "/webhooks", Handler: m.handleListWebhookTargets},
+		{Method: "POST", Path: "/webhooks", Handler: m.handleCreateWebhookTarget},
+		{Method: "GET", Path: "/webhooks/dead-letters", Handler: m.handleListWebhookDeadLetters},
+		{Method: "GET", Path: "/webhooks/{id}", Handler: m.handleGetWebhookTarget},
+		{Method: "PUT", Path: "/webhooks/{id}", Handler: m.handleUpdateWebhookTarget},
+		{Method: "DELETE", Path: "/webhooks/{id}", Handler: m.handleDeleteWebhookTarget},
+		{Method: "POST", Path: "/targets/{id}/test", Handler: m.handleTestWebhookTarget},
+		{Method: "GET", Path: "/email-targets", Handler: m.handleListEmailTargets},
+		{Method: "POST", Path: "/email-targets", Handler: m.handleCreateEmailTarget},
+		{Method: "GET", Path: "/email-targets/{id}", Handler: m.handleGetEmailTarget},
+		{Method: "PUT", Path: "/email-targets/{id}", Handler: m.handleUpdateEmailTarget},
+		{Method: "DELETE", Path: "/email-targets/{id}", Handler: m.handleDeleteEmailTarget},
+		{Method: "POST", Path: "/email-targets/{id}/test", Handler: m.handleTestEmailTarget},
+		{Method: "GET", Path: "/notification-routes", Handler: m.handleListNotificationRoutes},
+		{Method: "POST", Path: "/notification-routes", Handler: m.handleCreateNotificationRoute},
+		{Method: "GET", Path: "/notification-routes/{id}", Handler: m.handleGetNotificationRoute},
+		{Method: "PUT", Path: "/notification-routes/{id}", Handler: m.handleUpdateNotificationRoute},
+		{Method: "DELETE", Path: "/notification-routes/{id}", Handler: m.handleDeleteNotificationRoute},
 	}
 }
 
@@ -67,5 +67,104 @@ func migrations() []plugin.Migration {
 				return err
 			},
 		},
+		{
+			Version:     3,
+			Description: "create alert webhook targets, delivery log, and dead-letter tables",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`CREATE TABLE IF NOT EXISTS dispatch_webhook_targets (
+						id TEXT PRIMARY KEY,
+						name TEXT NOT NULL,
+						url TEXT NOT NULL,
+						format TEXT NOT NULL DEFAULT 'generic',
+						secret TEXT NOT NULL DEFAULT '',
+						headers_json TEXT NOT NULL DEFAULT '{}',
+						enabled INTEGER NOT NULL DEFAULT 1,
+						created_at DATETIME NOT NULL,
+						updated_at DATETIME NOT NULL
+					)`,
+					`CREATE TABLE IF NOT EXISTS dispatch_webhook_deliveries (
+						target_id TEXT NOT NULL REFERENCES dispatch_webhook_targets(id) ON DELETE CASCADE,
+						delivery_key TEXT NOT NULL,
+						status TEXT NOT NULL DEFAULT 'pending',
+						attempts INTEGER NOT NULL DEFAULT 0,
+						created_at DATETIME NOT NULL,
+						updated_at DATETIME NOT NULL,
+						PRIMARY KEY (target_id, delivery_key)
+					)`,
+					`CREATE TABLE IF NOT EXISTS dispatch_webhook_dead_letters (
+						id INTEGER PRIMARY KEY AUTOINCREMENT,
+						target_id TEXT NOT NULL,
+						delivery_key TEXT NOT NULL,
+						event_type TEXT NOT NULL,
+						alert_id TEXT NOT NULL,
+						payload TEXT NOT NULL,
+						attempts INTEGER NOT NULL,
+						last_error TEXT NOT NULL DEFAULT '',
+						created_at DATETIME NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_dispatch_dead_letters_created ON dispatch_webhook_dead_letters(created_at)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     4,
+			Description: "create notification routes table",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`CREATE TABLE IF NOT EXISTS dispatch_notification_routes (
+						id TEXT PRIMARY KEY,
+						name TEXT NOT NULL,
+						priority INTEGER NOT NULL DEFAULT 100,
+						severity TEXT NOT NULL DEFAULT '',
+						device_id TEXT NOT NULL DEFAULT '',
+						device_tag TEXT NOT NULL DEFAULT '',
+						target_id TEXT NOT NULL REFERENCES dispatch_webhook_targets(id) ON DELETE CASCADE,
+						stop INTEGER NOT NULL DEFAULT 0,
+						enabled INTEGER NOT NULL DEFAULT 1,
+						created_at DATETIME NOT NULL,
+						updated_at DATETIME NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_dispatch_routes_priority ON dispatch_notification_routes(priority, created_at)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.ExecContext(context.Background(), stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     5,
+			Description: "create email notification targets table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(context.Background(), `
+					CREATE TABLE IF NOT EXISTS dispatch_email_targets (
+						id TEXT PRIMARY KEY,
+						name TEXT NOT NULL,
+						host TEXT NOT NULL,
+						port INTEGER NOT NULL,
+						username TEXT NOT NULL DEFAULT '',
+						password TEXT NOT NULL DEFAULT '',
+						tls_mode TEXT NOT NULL DEFAULT 'starttls',
+						from_address TEXT NOT NULL,
+						recipients_json TEXT NOT NULL DEFAULT '[]',
+						subject_template TEXT NOT NULL DEFAULT '',
+						body_template TEXT NOT NULL DEFAULT '',
+						aggregation_window_seconds INTEGER NOT NULL DEFAULT 0,
+						enabled INTEGER NOT NULL DEFAULT 1,
+						created_at DATETIME NOT NULL,
+						updated_at DATETIME NOT NULL
+					)`)
+				return err
+			},
+		},
 	}
 }
@@ -0,0 +1,269 @@
+package dispatch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultRoutePriority is the priority given to routes created without one.
+const defaultRoutePriority = 100
+
+// notificationRouteRequest is the JSON body for creating or updating a
+// notification route. On update, nil fields leave the stored value
+// unchanged; an empty string clears a match criterion.
+type notificationRouteRequest struct {
+	Name      string  `json:"name"`
+	Priority  *int    `json:"priority,omitempty"`
+	Severity  *string `json:"severity,omitempty"`
+	DeviceID  *string `json:"device_id,omitempty"`
+	DeviceTag *string `json:"device_tag,omitempty"`
+	TargetID  string  `json:"target_id"`
+	Stop      *bool   `json:"stop,omitempty"`
+	Enabled   *bool   `json:"enabled,omitempty"`
+}
+
+// apply copies the fields set in the request onto a route.
+func (req *notificationRouteRequest) apply(route *NotificationRoute) {
+	if req.Name != "" {
+		route.Name = req.Name
+	}
+	if req.Priority != nil {
+		route.Priority = *req.Priority
+	}
+	if req.Severity != nil {
+		route.Severity = *req.Severity
+	}
+	if req.DeviceID != nil {
+		route.DeviceID = *req.DeviceID
+	}
+	if req.DeviceTag != nil {
+		route.DeviceTag = *req.DeviceTag
+	}
+	if req.TargetID != "" {
+		route.TargetID = req.TargetID
+	}
+	if req.Stop != nil {
+		route.Stop = *req.Stop
+	}
+	if req.Enabled != nil {
+		route.Enabled = *req.Enabled
+	}
+}
+
+// handleListNotificationRoutes returns all notification routes.
+//
+//	@Summary		List notification routes
+//	@Description	Returns all alert notification routes in the order they are evaluated.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}	NotificationRoute
+//	@Router			/dispatch/notification-routes [get]
+func (m *Module) handleListNotificationRoutes(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	routes, err := m.store.ListNotificationRoutes(r.Context())
+	if err != nil {
+		m.logger.Warn("failed to list notification routes", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to list notification routes")
+		return
+	}
+	if routes == nil {
+		routes = []NotificationRoute{}
+	}
+	dispatchWriteJSON(w, http.StatusOK, routes)
+}
+
+// handleGetNotificationRoute returns a single notification route.
+//
+//	@Summary		Get notification route
+//	@Description	Returns an alert notification route by ID.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Notification route ID"
+//	@Success		200	{object}	NotificationRoute
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/notification-routes/{id} [get]
+func (m *Module) handleGetNotificationRoute(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	route, err := m.store.GetNotificationRoute(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get notification route", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get notification route")
+		return
+	}
+	if route == nil {
+		dispatchWriteError(w, http.StatusNotFound, "notification route not found")
+		return
+	}
+	dispatchWriteJSON(w, http.StatusOK, route)
+}
+
+// handleCreateNotificationRoute creates a notification route.
+//
+//	@Summary		Create notification route
+//	@Description	Sends alerts matching a severity, device ID, and/or device tag to a webhook target. A route with no criteria is a default route, used when no other route matches.
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body	body		notificationRouteRequest	true	"Notification route"
+//	@Success		201		{object}	NotificationRoute
+//	@Failure		400		{object}	models.APIProblem
+//	@Router			/dispatch/notification-routes [post]
+func (m *Module) handleCreateNotificationRoute(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	var req notificationRouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		dispatchWriteError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+
+	now := time.Now().UTC()
+	route := &NotificationRoute{
+		ID:        uuid.New().String(),
+		Priority:  defaultRoutePriority,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	req.apply(route)
+	if !m.validateNotificationRoute(w, r, route) {
+		return
+	}
+	if err := m.store.CreateNotificationRoute(r.Context(), route); err != nil {
+		m.logger.Warn("failed to create notification route", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to create notification route")
+		return
+	}
+	dispatchWriteJSON(w, http.StatusCreated, route)
+}
+
+// handleUpdateNotificationRoute updates a notification route.
+//
+//	@Summary		Update notification route
+//	@Description	Updates fields on an alert notification route. Omitted fields are unchanged.
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string						true	"Notification route ID"
+//	@Param			body	body		notificationRouteRequest	true	"Fields to update"
+//	@Success		200		{object}	NotificationRoute
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Router			/dispatch/notification-routes/{id} [put]
+func (m *Module) handleUpdateNotificationRoute(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	existing, err := m.store.GetNotificationRoute(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get notification route for update", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get notification route")
+		return
+	}
+	if existing == nil {
+		dispatchWriteError(w, http.StatusNotFound, "notification route not found")
+		return
+	}
+
+	var req notificationRouteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.apply(existing)
+	if !m.validateNotificationRoute(w, r, existing) {
+		return
+	}
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.UpdateNotificationRoute(r.Context(), existing); err != nil {
+		m.logger.Warn("failed to update notification route", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to update notification route")
+		return
+	}
+	dispatchWriteJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteNotificationRoute removes a notification route.
+//
+//	@Summary		Delete notification route
+//	@Description	Removes an alert notification route by ID.
+//	@Tags			dispatch
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Notification route ID"
+//	@Success		204
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/notification-routes/{id} [delete]
+func (m *Module) handleDeleteNotificationRoute(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := m.store.DeleteNotificationRoute(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			dispatchWriteError(w, http.StatusNotFound, "notification route not found")
+			return
+		}
+		m.logger.Warn("failed to delete notification route", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to delete notification route")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateNotificationRoute checks a route before it is stored, writing a
+// problem response and returning false if it is invalid.
+func (m *Module) validateNotificationRoute(w http.ResponseWriter, r *http.Request, route *NotificationRoute) bool {
+	switch route.Severity {
+	case "", "warning", "critical":
+	default:
+		dispatchWriteError(w, http.StatusBadRequest, "severity must be warning or critical")
+		return false
+	}
+	if route.TargetID == "" {
+		dispatchWriteError(w, http.StatusBadRequest, "target_id is required")
+		return false
+	}
+	target, err := m.store.GetWebhookTarget(r.Context(), route.TargetID)
+	if err != nil {
+		m.logger.Warn("failed to get webhook target for route", zap.String("target_id", route.TargetID), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get webhook target")
+		return false
+	}
+	if target == nil {
+		dispatchWriteError(w, http.StatusBadRequest, "target_id does not match a webhook target")
+		return false
+	}
+	return true
+}
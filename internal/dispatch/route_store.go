@@ -0,0 +1,226 @@
+package dispatch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+)
+
+// NotificationRoute sends alerts matching its criteria to one webhook target.
+// Empty criteria match anything; a route with no criteria at all is a
+// default (catch-all) route, used only when no other route matches.
+type NotificationRoute struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Priority  int       `json:"priority"`             // lower runs first
+	Severity  string    `json:"severity,omitempty"`   // warning, critical; empty matches any
+	DeviceID  string    `json:"device_id,omitempty"`  // empty matches any device
+	DeviceTag string    `json:"device_tag,omitempty"` // empty matches any device
+	TargetID  string    `json:"target_id"`
+	Stop      bool      `json:"stop"` // skip lower-priority routes once this one matches
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IsDefault reports whether the route is a catch-all route.
+func (r *NotificationRoute) IsDefault() bool {
+	return r.Severity == "" && r.DeviceID == "" && r.DeviceTag == ""
+}
+
+// Matches reports whether an alert on a device carrying tags satisfies every
+// criterion the route sets.
+func (r *NotificationRoute) Matches(alert *pulse.Alert, tags []string) bool {
+	if r.Severity != "" && !strings.EqualFold(r.Severity, alert.Severity) {
+		return false
+	}
+	if r.DeviceID != "" && r.DeviceID != alert.DeviceID {
+		return false
+	}
+	if r.DeviceTag != "" && !slices.Contains(tags, r.DeviceTag) {
+		return false
+	}
+	return true
+}
+
+// usesDeviceTags reports whether any route matches on device tags, so
+// callers only look tags up when they are needed.
+func usesDeviceTags(routes []NotificationRoute) bool {
+	return slices.ContainsFunc(routes, func(r NotificationRoute) bool { return r.DeviceTag != "" })
+}
+
+// selectRouteTargets returns the IDs of the targets an alert should be sent
+// to, in route priority order and without duplicates. Routes must already be
+// sorted by priority. Every matching route contributes its target until one
+// with Stop set matches. Default routes apply only when no other route did.
+func selectRouteTargets(routes []NotificationRoute, alert *pulse.Alert, tags []string) []string {
+	var targets []string
+	add := func(id string) {
+		if !slices.Contains(targets, id) {
+			targets = append(targets, id)
+		}
+	}
+
+	matched := false
+	for i := range routes {
+		r := &routes[i]
+		if r.IsDefault() || !r.Matches(alert, tags) {
+			continue
+		}
+		matched = true
+		add(r.TargetID)
+		if r.Stop {
+			break
+		}
+	}
+	if matched {
+		return targets
+	}
+
+	for i := range routes {
+		if routes[i].IsDefault() {
+			add(routes[i].TargetID)
+			if routes[i].Stop {
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// -- Notification route methods --
+
+// CreateNotificationRoute inserts a new notification route.
+func (s *DispatchStore) CreateNotificationRoute(ctx context.Context, r *NotificationRoute) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO dispatch_notification_routes (
+			id, name, priority, severity, device_id, device_tag, target_id, stop, enabled, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		r.ID, r.Name, r.Priority, r.Severity, r.DeviceID, r.DeviceTag, r.TargetID,
+		boolToInt(r.Stop), boolToInt(r.Enabled), r.CreatedAt, r.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert notification route: %w", err)
+	}
+	return nil
+}
+
+// GetNotificationRoute returns a notification route by ID. Returns nil, nil if not found.
+func (s *DispatchStore) GetNotificationRoute(ctx context.Context, id string) (*NotificationRoute, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, name, priority, severity, device_id, device_tag, target_id, stop, enabled, created_at, updated_at
+		FROM dispatch_notification_routes WHERE id = ?`, id)
+	r, err := scanNotificationRoute(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get notification route: %w", err)
+	}
+	return r, nil
+}
+
+// ListNotificationRoutes returns all notification routes in priority order.
+func (s *DispatchStore) ListNotificationRoutes(ctx context.Context) ([]NotificationRoute, error) {
+	return s.queryNotificationRoutes(ctx, `
+		SELECT id, name, priority, severity, device_id, device_tag, target_id, stop, enabled, created_at, updated_at
+		FROM dispatch_notification_routes ORDER BY priority, created_at`)
+}
+
+// ListEnabledNotificationRoutes returns the routes alerts are matched against,
+// in priority order.
+func (s *DispatchStore) ListEnabledNotificationRoutes(ctx context.Context) ([]NotificationRoute, error) {
+	return s.queryNotificationRoutes(ctx, `
+		SELECT id, name, priority, severity, device_id, device_tag, target_id, stop, enabled, created_at, updated_at
+		FROM dispatch_notification_routes WHERE enabled = 1 ORDER BY priority, created_at`)
+}
+
+// UpdateNotificationRoute replaces the mutable fields of a notification route.
+// Returns sql.ErrNoRows if the route does not exist.
+func (s *DispatchStore) UpdateNotificationRoute(ctx context.Context, r *NotificationRoute) error {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE dispatch_notification_routes
+		SET name = ?, priority = ?, severity = ?, device_id = ?, device_tag = ?, target_id = ?,
+			stop = ?, enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		r.Name, r.Priority, r.Severity, r.DeviceID, r.DeviceTag, r.TargetID,
+		boolToInt(r.Stop), boolToInt(r.Enabled), r.UpdatedAt, r.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update notification route: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteNotificationRoute removes a notification route.
+// Returns sql.ErrNoRows if the route does not exist.
+func (s *DispatchStore) DeleteNotificationRoute(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM dispatch_notification_routes WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete notification route: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *DispatchStore) queryNotificationRoutes(ctx context.Context, query string) ([]NotificationRoute, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list notification routes: %w", err)
+	}
+	defer rows.Close()
+
+	var routes []NotificationRoute
+	for rows.Next() {
+		r, err := scanNotificationRoute(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan notification route: %w", err)
+		}
+		routes = append(routes, *r)
+	}
+	return routes, rows.Err()
+}
+
+func scanNotificationRoute(row webhookScanner) (*NotificationRoute, error) {
+	var r NotificationRoute
+	var stop, enabled int
+	if err := row.Scan(
+		&r.ID, &r.Name, &r.Priority, &r.Severity, &r.DeviceID, &r.DeviceTag, &r.TargetID,
+		&stop, &enabled, &r.CreatedAt, &r.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	r.Stop = stop != 0
+	r.Enabled = enabled != 0
+	return &r, nil
+}
+
+// DeviceTags returns the tags of a recon device, or nil if it is unknown.
+func (s *DispatchStore) DeviceTags(ctx context.Context, deviceID string) ([]string, error) {
+	var tagsJSON string
+	err := s.db.QueryRowContext(ctx, `SELECT tags FROM recon_devices WHERE id = ?`, deviceID).Scan(&tagsJSON)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get device tags: %w", err)
+	}
+	var tags []string
+	if err := json.Unmarshal([]byte(tagsJSON), &tags); err != nil {
+		return nil, fmt.Errorf("unmarshal device tags: %w", err)
+	}
+	return tags, nil
+}
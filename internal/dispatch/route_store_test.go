@@ -0,0 +1,188 @@
+package dispatch
+
+import (
+	"context"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+)
+
+func makeNotificationRoute(t *testing.T, s *DispatchStore, r NotificationRoute) *NotificationRoute {
+	t.Helper()
+	now := time.Now().UTC()
+	r.Name = "route " + r.ID
+	r.Enabled = true
+	r.CreatedAt = now
+	r.UpdatedAt = now
+	if err := s.CreateNotificationRoute(context.Background(), &r); err != nil {
+		t.Fatalf("CreateNotificationRoute: %v", err)
+	}
+	return &r
+}
+
+func TestDispatchStore_NotificationRouteCRUD(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	makeWebhookTarget(t, s, "wh-1", "https://hooks.example.com/a")
+
+	makeNotificationRoute(t, s, NotificationRoute{ID: "r-low", Priority: 50, TargetID: "wh-1"})
+	route := makeNotificationRoute(t, s, NotificationRoute{ID: "r-1", Priority: 10, Severity: "critical", TargetID: "wh-1"})
+
+	got, err := s.GetNotificationRoute(ctx, "r-1")
+	if err != nil || got == nil {
+		t.Fatalf("GetNotificationRoute = %v, %v", got, err)
+	}
+	if got.Severity != "critical" || !got.Enabled {
+		t.Errorf("got %+v", got)
+	}
+
+	route.Enabled = false
+	route.Stop = true
+	if err := s.UpdateNotificationRoute(ctx, route); err != nil {
+		t.Fatalf("UpdateNotificationRoute: %v", err)
+	}
+	all, err := s.ListNotificationRoutes(ctx)
+	if err != nil {
+		t.Fatalf("ListNotificationRoutes: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "r-1" || !all[0].Stop {
+		t.Errorf("ListNotificationRoutes = %+v, want r-1 first with stop set", all)
+	}
+	enabled, err := s.ListEnabledNotificationRoutes(ctx)
+	if err != nil {
+		t.Fatalf("ListEnabledNotificationRoutes: %v", err)
+	}
+	if len(enabled) != 1 || enabled[0].ID != "r-low" {
+		t.Errorf("ListEnabledNotificationRoutes = %+v, want only r-low", enabled)
+	}
+
+	if err := s.DeleteWebhookTarget(ctx, "wh-1"); err != nil {
+		t.Fatalf("DeleteWebhookTarget: %v", err)
+	}
+	if got, _ := s.GetNotificationRoute(ctx, "r-1"); got != nil {
+		t.Error("route should be removed with its target")
+	}
+}
+
+func TestSelectRouteTargets_Precedence(t *testing.T) {
+	alert := &pulse.Alert{ID: "a-1", DeviceID: "dev-1", Severity: "critical"}
+	tags := []string{"core", "rack-2"}
+
+	tests := []struct {
+		name   string
+		routes []NotificationRoute
+		want   []string
+	}{
+		{
+			name: "matching routes in priority order",
+			routes: []NotificationRoute{
+				{Priority: 1, DeviceID: "dev-1", TargetID: "by-device"},
+				{Priority: 2, Severity: "critical", TargetID: "by-severity"},
+				{Priority: 3, DeviceTag: "core", TargetID: "by-tag"},
+			},
+			want: []string{"by-device", "by-severity", "by-tag"},
+		},
+		{
+			name: "stop ends evaluation",
+			routes: []NotificationRoute{
+				{Priority: 1, Severity: "critical", TargetID: "pager", Stop: true},
+				{Priority: 2, DeviceTag: "core", TargetID: "chat"},
+			},
+			want: []string{"pager"},
+		},
+		{
+			name: "non-matching stop route is skipped",
+			routes: []NotificationRoute{
+				{Priority: 1, Severity: "warning", TargetID: "pager", Stop: true},
+				{Priority: 2, DeviceTag: "core", TargetID: "chat"},
+			},
+			want: []string{"chat"},
+		},
+		{
+			name: "all criteria must match",
+			routes: []NotificationRoute{
+				{Priority: 1, Severity: "critical", DeviceTag: "edge", TargetID: "edge-critical"},
+				{Priority: 2, Severity: "critical", DeviceTag: "core", TargetID: "core-critical"},
+			},
+			want: []string{"core-critical"},
+		},
+		{
+			name: "default used only when nothing else matches",
+			routes: []NotificationRoute{
+				{Priority: 1, TargetID: "default"},
+				{Priority: 2, DeviceID: "dev-9", TargetID: "other-device"},
+			},
+			want: []string{"default"},
+		},
+		{
+			name: "default skipped when a specific route matches",
+			routes: []NotificationRoute{
+				{Priority: 1, TargetID: "default"},
+				{Priority: 2, Severity: "critical", TargetID: "pager"},
+			},
+			want: []string{"pager"},
+		},
+		{
+			name: "duplicate targets sent once",
+			routes: []NotificationRoute{
+				{Priority: 1, Severity: "critical", TargetID: "pager"},
+				{Priority: 2, DeviceID: "dev-1", TargetID: "pager"},
+			},
+			want: []string{"pager"},
+		},
+		{
+			name: "no routes match and no default",
+			routes: []NotificationRoute{
+				{Priority: 1, Severity: "warning", TargetID: "pager"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectRouteTargets(tt.routes, alert, tags)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("selectRouteTargets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertWebhookWorker_RoutesToMultipleTargets(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	if _, err := s.db.ExecContext(ctx, `CREATE TABLE recon_devices (id TEXT PRIMARY KEY, tags TEXT NOT NULL DEFAULT '[]')`); err != nil {
+		t.Fatalf("create recon_devices: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `INSERT INTO recon_devices (id, tags) VALUES ('dev-1', '["core"]')`); err != nil {
+		t.Fatalf("insert device: %v", err)
+	}
+
+	pager, chat, fallback, other := &webhookRecorder{}, &webhookRecorder{}, &webhookRecorder{}, &webhookRecorder{}
+	for id, rec := range map[string]*webhookRecorder{"pager": pager, "chat": chat, "fallback": fallback, "other": other} {
+		srv := httptest.NewServer(rec)
+		t.Cleanup(srv.Close)
+		makeWebhookTarget(t, s, id, srv.URL)
+	}
+	makeNotificationRoute(t, s, NotificationRoute{ID: "r-sev", Priority: 1, Severity: "critical", TargetID: "pager"})
+	makeNotificationRoute(t, s, NotificationRoute{ID: "r-tag", Priority: 2, DeviceTag: "core", TargetID: "chat"})
+	makeNotificationRoute(t, s, NotificationRoute{ID: "r-edge", Priority: 3, DeviceTag: "edge", TargetID: "other"})
+	makeNotificationRoute(t, s, NotificationRoute{ID: "r-default", Priority: 100, TargetID: "fallback"})
+
+	w := testWebhookWorker(s, 1)
+	w.deliver(ctx, testAlertEvent(pulse.TopicAlertTriggered, "host down"))
+
+	if pager.calls.Load() != 1 || chat.calls.Load() != 1 {
+		t.Errorf("pager calls = %d, chat calls = %d, want 1 each", pager.calls.Load(), chat.calls.Load())
+	}
+	if other.calls.Load() != 0 {
+		t.Errorf("non-matching target received %d calls", other.calls.Load())
+	}
+	if fallback.calls.Load() != 0 {
+		t.Errorf("default target received %d calls, want 0 when another route matched", fallback.calls.Load())
+	}
+}
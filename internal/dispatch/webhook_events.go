@@ -0,0 +1,37 @@
+package dispatch
+
+import (
+	"context"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+// Subscriptions implements plugin.EventSubscriber.
+func (m *Module) Subscriptions() []plugin.Subscription {
+	return []plugin.Subscription{
+		{Topic: pulse.TopicAlertTriggered, Handler: m.handleAlertEvent},
+		{Topic: pulse.TopicAlertResolved, Handler: m.handleAlertEvent},
+		{Topic: pulse.TopicAlertEscalated, Handler: m.handleAlertEvent},
+	}
+}
+
+// handleAlertEvent queues a pulse alert for delivery to webhook and email targets.
+func (m *Module) handleAlertEvent(_ context.Context, event plugin.Event) {
+	if m.webhooks == nil && m.email == nil {
+		return
+	}
+	alert, ok := event.Payload.(*pulse.Alert)
+	if !ok || alert == nil {
+		m.logger.Debug("ignoring alert event with unexpected payload",
+			zap.String("topic", event.Topic))
+		return
+	}
+	if m.webhooks != nil {
+		m.webhooks.Enqueue(event.Topic, alert)
+	}
+	if m.email != nil {
+		m.email.Enqueue(event.Topic, alert)
+	}
+}
@@ -0,0 +1,364 @@
+package dispatch
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultDeadLetterLimit is the number of dead letters returned when no limit is given.
+const defaultDeadLetterLimit = 100
+
+// webhookTargetRequest is the JSON body for creating or updating a webhook target.
+// On update, empty fields and a nil Enabled leave the stored value unchanged.
+type webhookTargetRequest struct {
+	Name    string            `json:"name"`
+	URL     string            `json:"url"`
+	Format  string            `json:"format,omitempty"`
+	Secret  string            `json:"secret,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+}
+
+// handleListWebhookTargets returns all alert webhook targets.
+//
+//	@Summary		List webhook targets
+//	@Description	Returns all outbound alert webhook targets. Secrets and header values are masked.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		WebhookTarget
+//	@Router			/dispatch/webhooks [get]
+func (m *Module) handleListWebhookTargets(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	targets, err := m.store.ListWebhookTargets(r.Context())
+	if err != nil {
+		m.logger.Warn("failed to list webhook targets", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to list webhook targets")
+		return
+	}
+	if targets == nil {
+		targets = []WebhookTarget{}
+	}
+	for i := range targets {
+		maskWebhookTarget(&targets[i])
+	}
+	dispatchWriteJSON(w, http.StatusOK, targets)
+}
+
+// handleGetWebhookTarget returns a single alert webhook target.
+//
+//	@Summary		Get webhook target
+//	@Description	Returns an outbound alert webhook target by ID. Secrets and header values are masked.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Webhook target ID"
+//	@Success		200	{object}	WebhookTarget
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/webhooks/{id} [get]
+func (m *Module) handleGetWebhookTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	target, err := m.store.GetWebhookTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get webhook target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get webhook target")
+		return
+	}
+	if target == nil {
+		dispatchWriteError(w, http.StatusNotFound, "webhook target not found")
+		return
+	}
+	maskWebhookTarget(target)
+	dispatchWriteJSON(w, http.StatusOK, target)
+}
+
+// handleCreateWebhookTarget creates an alert webhook target.
+//
+//	@Summary		Create webhook target
+//	@Description	Registers a URL that receives pulse alert events (generic JSON or Slack-compatible).
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body	body		webhookTargetRequest	true	"Webhook target"
+//	@Success		201		{object}	WebhookTarget
+//	@Failure		400		{object}	models.APIProblem
+//	@Router			/dispatch/webhooks [post]
+func (m *Module) handleCreateWebhookTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	var req webhookTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		dispatchWriteError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if req.Format == "" {
+		req.Format = WebhookFormatGeneric
+	}
+	if msg := validateWebhookTarget(req.URL, req.Format); msg != "" {
+		dispatchWriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	now := time.Now().UTC()
+	target := &WebhookTarget{
+		ID:        uuid.New().String(),
+		Name:      req.Name,
+		URL:       req.URL,
+		Format:    req.Format,
+		Secret:    req.Secret,
+		Headers:   req.Headers,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := m.store.CreateWebhookTarget(r.Context(), target); err != nil {
+		m.logger.Warn("failed to create webhook target", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to create webhook target")
+		return
+	}
+
+	maskWebhookTarget(target)
+	dispatchWriteJSON(w, http.StatusCreated, target)
+}
+
+// handleUpdateWebhookTarget updates an alert webhook target.
+//
+//	@Summary		Update webhook target
+//	@Description	Updates fields on an outbound alert webhook target. Omitted fields are unchanged.
+//	@Tags			dispatch
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string					true	"Webhook target ID"
+//	@Param			body	body		webhookTargetRequest	true	"Fields to update"
+//	@Success		200		{object}	WebhookTarget
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Router			/dispatch/webhooks/{id} [put]
+func (m *Module) handleUpdateWebhookTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	existing, err := m.store.GetWebhookTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get webhook target for update", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get webhook target")
+		return
+	}
+	if existing == nil {
+		dispatchWriteError(w, http.StatusNotFound, "webhook target not found")
+		return
+	}
+
+	var req webhookTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		dispatchWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name != "" {
+		existing.Name = req.Name
+	}
+	if req.URL != "" {
+		existing.URL = req.URL
+	}
+	if req.Format != "" {
+		existing.Format = req.Format
+	}
+	// A masked secret echoed back from a GET keeps the stored value.
+	if req.Secret != "" && req.Secret != maskedValue {
+		existing.Secret = req.Secret
+	}
+	if req.Headers != nil {
+		for k, v := range req.Headers {
+			if v == maskedValue {
+				req.Headers[k] = existing.Headers[k]
+			}
+		}
+		existing.Headers = req.Headers
+	}
+	if req.Enabled != nil {
+		existing.Enabled = *req.Enabled
+	}
+	if msg := validateWebhookTarget(existing.URL, existing.Format); msg != "" {
+		dispatchWriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.UpdateWebhookTarget(r.Context(), existing); err != nil {
+		m.logger.Warn("failed to update webhook target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to update webhook target")
+		return
+	}
+
+	maskWebhookTarget(existing)
+	dispatchWriteJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteWebhookTarget removes an alert webhook target.
+//
+//	@Summary		Delete webhook target
+//	@Description	Removes an outbound alert webhook target by ID.
+//	@Tags			dispatch
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Webhook target ID"
+//	@Success		204
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/webhooks/{id} [delete]
+func (m *Module) handleDeleteWebhookTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	if err := m.store.DeleteWebhookTarget(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			dispatchWriteError(w, http.StatusNotFound, "webhook target not found")
+			return
+		}
+		m.logger.Warn("failed to delete webhook target", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to delete webhook target")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleTestWebhookTarget sends a test notification to a webhook target.
+//
+//	@Summary		Test webhook target
+//	@Description	Sends a synthetic alert to the target through the same send path as real alerts and reports
+//	@Description	the result of a single attempt, bounded to 5 seconds. Disabled targets can be tested too. A
+//	@Description	failed delivery is reported in the response body rather than as an error status.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Webhook target ID"
+//	@Success		200	{object}	TestDelivery
+//	@Failure		404	{object}	models.APIProblem
+//	@Router			/dispatch/targets/{id}/test [post]
+func (m *Module) handleTestWebhookTarget(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil || m.webhooks == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	target, err := m.store.GetWebhookTarget(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get webhook target for test", zap.String("id", id), zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to get webhook target")
+		return
+	}
+	if target == nil {
+		dispatchWriteError(w, http.StatusNotFound, "webhook target not found")
+		return
+	}
+
+	result := m.webhooks.SendTest(r.Context(), target)
+	m.logger.Info("sent test notification",
+		zap.String("target_id", id),
+		zap.Bool("delivered", result.Delivered),
+		zap.Int("attempts", result.Attempts),
+	)
+	dispatchWriteJSON(w, http.StatusOK, result)
+}
+
+// handleListWebhookDeadLetters returns alert deliveries that failed after all retries.
+//
+//	@Summary		List webhook dead letters
+//	@Description	Returns the most recent alert webhook deliveries that exhausted their retries.
+//	@Tags			dispatch
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query	int	false	"Maximum entries to return (default 100)"
+//	@Success		200		{array}	WebhookDeadLetter
+//	@Router			/dispatch/webhooks/dead-letters [get]
+func (m *Module) handleListWebhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		dispatchWriteError(w, http.StatusServiceUnavailable, "dispatch store not available")
+		return
+	}
+
+	limit := defaultDeadLetterLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			dispatchWriteError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+
+	letters, err := m.store.ListWebhookDeadLetters(r.Context(), limit)
+	if err != nil {
+		m.logger.Warn("failed to list webhook dead letters", zap.Error(err))
+		dispatchWriteError(w, http.StatusInternalServerError, "failed to list webhook dead letters")
+		return
+	}
+	if letters == nil {
+		letters = []WebhookDeadLetter{}
+	}
+	dispatchWriteJSON(w, http.StatusOK, letters)
+}
+
+// maskedValue replaces secrets in API responses.
+const maskedValue = "****"
+
+// maskWebhookTarget hides the signing secret and custom header values, which
+// commonly carry API tokens, before a target is returned to API clients.
+func maskWebhookTarget(t *WebhookTarget) {
+	if t.Secret != "" {
+		t.Secret = maskedValue
+	}
+	if len(t.Headers) > 0 {
+		masked := make(map[string]string, len(t.Headers))
+		for k := range t.Headers {
+			masked[k] = maskedValue
+		}
+		t.Headers = masked
+	}
+}
+
+// validateWebhookTarget returns a problem detail for an invalid target, or "".
+func validateWebhookTarget(rawURL, format string) string {
+	if rawURL == "" {
+		return "url is required"
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "url must be an absolute http or https URL"
+	}
+	if format != WebhookFormatGeneric && format != WebhookFormatSlack {
+		return "format must be generic or slack"
+	}
+	return ""
+}
@@ -0,0 +1,284 @@
+package dispatch
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Webhook target payload formats.
+const (
+	WebhookFormatGeneric = "generic"
+	WebhookFormatSlack   = "slack"
+)
+
+// Webhook delivery states recorded in dispatch_webhook_deliveries.
+const (
+	deliveryStatusPending   = "pending"
+	deliveryStatusDelivered = "delivered"
+	deliveryStatusFailed    = "failed"
+)
+
+// WebhookTarget is a user-configured URL that receives pulse alert events.
+type WebhookTarget struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	URL       string            `json:"url"`
+	Format    string            `json:"format"` // generic, slack
+	Secret    string            `json:"secret,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Enabled   bool              `json:"enabled"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// WebhookDeadLetter records an alert delivery that failed after all retries.
+type WebhookDeadLetter struct {
+	ID          int64     `json:"id"`
+	TargetID    string    `json:"target_id"`
+	DeliveryKey string    `json:"delivery_key"`
+	EventType   string    `json:"event_type"`
+	AlertID     string    `json:"alert_id"`
+	Payload     string    `json:"payload"`
+	Attempts    int       `json:"attempts"`
+	LastError   string    `json:"last_error"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// -- Webhook target methods --
+
+// CreateWebhookTarget inserts a new webhook target.
+func (s *DispatchStore) CreateWebhookTarget(ctx context.Context, t *WebhookTarget) error {
+	headersJSON, err := marshalWebhookHeaders(t.Headers)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO dispatch_webhook_targets (
+			id, name, url, format, secret, headers_json, enabled, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		t.ID, t.Name, t.URL, t.Format, t.Secret, headersJSON, boolToInt(t.Enabled),
+		t.CreatedAt, t.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert webhook target: %w", err)
+	}
+	return nil
+}
+
+// GetWebhookTarget returns a webhook target by ID. Returns nil, nil if not found.
+func (s *DispatchStore) GetWebhookTarget(ctx context.Context, id string) (*WebhookTarget, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT id, name, url, format, secret, headers_json, enabled, created_at, updated_at
+		FROM dispatch_webhook_targets WHERE id = ?`, id)
+	t, err := scanWebhookTarget(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get webhook target: %w", err)
+	}
+	return t, nil
+}
+
+// ListWebhookTargets returns all webhook targets ordered by creation time.
+func (s *DispatchStore) ListWebhookTargets(ctx context.Context) ([]WebhookTarget, error) {
+	return s.queryWebhookTargets(ctx, `
+		SELECT id, name, url, format, secret, headers_json, enabled, created_at, updated_at
+		FROM dispatch_webhook_targets ORDER BY created_at`)
+}
+
+// ListEnabledWebhookTargets returns the webhook targets that should receive alerts.
+func (s *DispatchStore) ListEnabledWebhookTargets(ctx context.Context) ([]WebhookTarget, error) {
+	return s.queryWebhookTargets(ctx, `
+		SELECT id, name, url, format, secret, headers_json, enabled, created_at, updated_at
+		FROM dispatch_webhook_targets WHERE enabled = 1 ORDER BY created_at`)
+}
+
+// UpdateWebhookTarget replaces the mutable fields of a webhook target.
+// Returns sql.ErrNoRows if the target does not exist.
+func (s *DispatchStore) UpdateWebhookTarget(ctx context.Context, t *WebhookTarget) error {
+	headersJSON, err := marshalWebhookHeaders(t.Headers)
+	if err != nil {
+		return err
+	}
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE dispatch_webhook_targets
+		SET name = ?, url = ?, format = ?, secret = ?, headers_json = ?, enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		t.Name, t.URL, t.Format, t.Secret, headersJSON, boolToInt(t.Enabled), t.UpdatedAt, t.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update webhook target: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// DeleteWebhookTarget removes a webhook target and its delivery log.
+// Returns sql.ErrNoRows if the target does not exist.
+func (s *DispatchStore) DeleteWebhookTarget(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM dispatch_webhook_targets WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete webhook target: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *DispatchStore) queryWebhookTargets(ctx context.Context, query string) ([]WebhookTarget, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook targets: %w", err)
+	}
+	defer rows.Close()
+
+	var targets []WebhookTarget
+	for rows.Next() {
+		t, err := scanWebhookTarget(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan webhook target: %w", err)
+		}
+		targets = append(targets, *t)
+	}
+	return targets, rows.Err()
+}
+
+type webhookScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWebhookTarget(row webhookScanner) (*WebhookTarget, error) {
+	var t WebhookTarget
+	var headersJSON string
+	var enabled int
+	if err := row.Scan(
+		&t.ID, &t.Name, &t.URL, &t.Format, &t.Secret, &headersJSON, &enabled,
+		&t.CreatedAt, &t.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	t.Enabled = enabled != 0
+	if headersJSON != "" {
+		if err := json.Unmarshal([]byte(headersJSON), &t.Headers); err != nil {
+			return nil, fmt.Errorf("unmarshal webhook headers: %w", err)
+		}
+	}
+	return &t, nil
+}
+
+func marshalWebhookHeaders(headers map[string]string) (string, error) {
+	if len(headers) == 0 {
+		return "{}", nil
+	}
+	data, err := json.Marshal(headers)
+	if err != nil {
+		return "", fmt.Errorf("marshal webhook headers: %w", err)
+	}
+	return string(data), nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// -- Delivery log methods --
+
+// ClaimWebhookDelivery records that an alert event is about to be delivered to
+// a target. It returns false if the same delivery key was already claimed, so
+// duplicate or racing events for one alert are sent at most once per target.
+func (s *DispatchStore) ClaimWebhookDelivery(ctx context.Context, targetID, deliveryKey string) (bool, error) {
+	now := time.Now().UTC()
+	res, err := s.db.ExecContext(ctx, `
+		INSERT OR IGNORE INTO dispatch_webhook_deliveries (
+			target_id, delivery_key, status, attempts, created_at, updated_at
+		) VALUES (?, ?, ?, 0, ?, ?)`,
+		targetID, deliveryKey, deliveryStatusPending, now, now,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim webhook delivery: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("claim webhook delivery: %w", err)
+	}
+	return n > 0, nil
+}
+
+// FinishWebhookDelivery records the outcome of a claimed delivery.
+func (s *DispatchStore) FinishWebhookDelivery(ctx context.Context, targetID, deliveryKey, status string, attempts int) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE dispatch_webhook_deliveries SET status = ?, attempts = ?, updated_at = ?
+		WHERE target_id = ? AND delivery_key = ?`,
+		status, attempts, time.Now().UTC(), targetID, deliveryKey,
+	)
+	if err != nil {
+		return fmt.Errorf("finish webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// DeleteOldWebhookDeliveries removes delivery records created before the
+// given time and returns the number deleted.
+func (s *DispatchStore) DeleteOldWebhookDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM dispatch_webhook_deliveries WHERE created_at < ?`, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete old webhook deliveries: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// -- Dead-letter methods --
+
+// InsertWebhookDeadLetter records a delivery that exhausted its retries.
+func (s *DispatchStore) InsertWebhookDeadLetter(ctx context.Context, dl *WebhookDeadLetter) error {
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO dispatch_webhook_dead_letters (
+			target_id, delivery_key, event_type, alert_id, payload, attempts, last_error, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		dl.TargetID, dl.DeliveryKey, dl.EventType, dl.AlertID, dl.Payload,
+		dl.Attempts, dl.LastError, dl.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert webhook dead letter: %w", err)
+	}
+	dl.ID, _ = res.LastInsertId()
+	return nil
+}
+
+// ListWebhookDeadLetters returns the most recent dead letters, newest first.
+func (s *DispatchStore) ListWebhookDeadLetters(ctx context.Context, limit int) ([]WebhookDeadLetter, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, target_id, delivery_key, event_type, alert_id, payload, attempts, last_error, created_at
+		FROM dispatch_webhook_dead_letters
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	var letters []WebhookDeadLetter
+	for rows.Next() {
+		var dl WebhookDeadLetter
+		if err := rows.Scan(
+			&dl.ID, &dl.TargetID, &dl.DeliveryKey, &dl.EventType, &dl.AlertID,
+			&dl.Payload, &dl.Attempts, &dl.LastError, &dl.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan webhook dead letter: %w", err)
+		}
+		letters = append(letters, dl)
+	}
+	return letters, rows.Err()
+}
@@ -0,0 +1,172 @@
+package dispatch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func makeWebhookTarget(t *testing.T, s *DispatchStore, id, url string) *WebhookTarget {
+	t.Helper()
+	now := time.Now().UTC()
+	target := &WebhookTarget{
+		ID:        id,
+		Name:      "target " + id,
+		URL:       url,
+		Format:    WebhookFormatGeneric,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.CreateWebhookTarget(context.Background(), target); err != nil {
+		t.Fatalf("CreateWebhookTarget: %v", err)
+	}
+	return target
+}
+
+func TestDispatchStore_WebhookTargetCRUD(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	target := makeWebhookTarget(t, s, "wh-1", "https://hooks.example.com/a")
+
+	got, err := s.GetWebhookTarget(ctx, "wh-1")
+	if err != nil {
+		t.Fatalf("GetWebhookTarget: %v", err)
+	}
+	if got == nil {
+		t.Fatal("GetWebhookTarget returned nil")
+	}
+	if got.URL != target.URL {
+		t.Errorf("URL = %q, want %q", got.URL, target.URL)
+	}
+
+	got.Secret = "s3cret"
+	got.Headers = map[string]string{"X-Api-Key": "abc"}
+	got.Enabled = false
+	if err := s.UpdateWebhookTarget(ctx, got); err != nil {
+		t.Fatalf("UpdateWebhookTarget: %v", err)
+	}
+	updated, err := s.GetWebhookTarget(ctx, "wh-1")
+	if err != nil {
+		t.Fatalf("GetWebhookTarget after update: %v", err)
+	}
+	if updated.Secret != "s3cret" {
+		t.Errorf("Secret = %q, want %q", updated.Secret, "s3cret")
+	}
+	if updated.Headers["X-Api-Key"] != "abc" {
+		t.Errorf("Headers[X-Api-Key] = %q, want %q", updated.Headers["X-Api-Key"], "abc")
+	}
+	if updated.Enabled {
+		t.Error("Enabled = true, want false")
+	}
+
+	enabled, err := s.ListEnabledWebhookTargets(ctx)
+	if err != nil {
+		t.Fatalf("ListEnabledWebhookTargets: %v", err)
+	}
+	if len(enabled) != 0 {
+		t.Errorf("enabled targets = %d, want 0", len(enabled))
+	}
+	all, err := s.ListWebhookTargets(ctx)
+	if err != nil {
+		t.Fatalf("ListWebhookTargets: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("targets = %d, want 1", len(all))
+	}
+
+	if err := s.DeleteWebhookTarget(ctx, "wh-1"); err != nil {
+		t.Fatalf("DeleteWebhookTarget: %v", err)
+	}
+	if err := s.DeleteWebhookTarget(ctx, "wh-1"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("second DeleteWebhookTarget = %v, want sql.ErrNoRows", err)
+	}
+	gone, err := s.GetWebhookTarget(ctx, "wh-1")
+	if err != nil {
+		t.Fatalf("GetWebhookTarget after delete: %v", err)
+	}
+	if gone != nil {
+		t.Error("GetWebhookTarget after delete returned target, want nil")
+	}
+}
+
+func TestDispatchStore_ClaimWebhookDelivery(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	makeWebhookTarget(t, s, "wh-1", "https://hooks.example.com/a")
+
+	first, err := s.ClaimWebhookDelivery(ctx, "wh-1", "alert-1:pulse.alert.resolved")
+	if err != nil {
+		t.Fatalf("ClaimWebhookDelivery: %v", err)
+	}
+	if !first {
+		t.Error("first claim = false, want true")
+	}
+	second, err := s.ClaimWebhookDelivery(ctx, "wh-1", "alert-1:pulse.alert.resolved")
+	if err != nil {
+		t.Fatalf("ClaimWebhookDelivery (duplicate): %v", err)
+	}
+	if second {
+		t.Error("duplicate claim = true, want false")
+	}
+}
+
+func TestDispatchStore_DeleteOldWebhookDeliveries(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	makeWebhookTarget(t, s, "wh-1", "https://hooks.example.com/a")
+	if _, err := s.ClaimWebhookDelivery(ctx, "wh-1", "alert-1:pulse.alert.resolved"); err != nil {
+		t.Fatalf("ClaimWebhookDelivery: %v", err)
+	}
+
+	deleted, err := s.DeleteOldWebhookDeliveries(ctx, time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("DeleteOldWebhookDeliveries: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted recent deliveries = %d, want 0", deleted)
+	}
+
+	deleted, err = s.DeleteOldWebhookDeliveries(ctx, time.Now().UTC().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("DeleteOldWebhookDeliveries: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("deleted old deliveries = %d, want 1", deleted)
+	}
+}
+
+func TestDispatchStore_WebhookDeadLetters(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	for i, alertID := range []string{"alert-1", "alert-2"} {
+		dl := &WebhookDeadLetter{
+			TargetID:    "wh-1",
+			DeliveryKey: alertID + ":pulse.alert.triggered",
+			EventType:   "pulse.alert.triggered",
+			AlertID:     alertID,
+			Payload:     "{}",
+			Attempts:    3,
+			LastError:   "webhook returned status 500",
+			CreatedAt:   time.Now().UTC().Add(time.Duration(i) * time.Second),
+		}
+		if err := s.InsertWebhookDeadLetter(ctx, dl); err != nil {
+			t.Fatalf("InsertWebhookDeadLetter: %v", err)
+		}
+	}
+
+	letters, err := s.ListWebhookDeadLetters(ctx, 1)
+	if err != nil {
+		t.Fatalf("ListWebhookDeadLetters: %v", err)
+	}
+	if len(letters) != 1 {
+		t.Fatalf("dead letters = %d, want 1", len(letters))
+	}
+	if letters[0].AlertID != "alert-2" {
+		t.Errorf("newest AlertID = %q, want %q", letters[0].AlertID, "alert-2")
+	}
+}
@@ -0,0 +1,532 @@
+package dispatch
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// alertEvent is a pulse alert queued for delivery to webhook targets.
+type alertEvent struct {
+	eventType string
+	alert     *pulse.Alert
+	at        time.Time
+}
+
+// webhookAlert is the redacted alert representation sent to webhook targets.
+// It is built field by field so new pulse.Alert fields are never forwarded
+// to third parties without review.
+type webhookAlert struct {
+	ID                  string     `json:"id"`
+	CheckID             string     `json:"check_id"`
+	DeviceID            string     `json:"device_id"`
+	DeviceName          string     `json:"device_name,omitempty"`
+	Severity            string     `json:"severity"`
+	Message             string     `json:"message"`
+	TriggeredAt         time.Time  `json:"triggered_at"`
+	ResolvedAt          *time.Time `json:"resolved_at,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+}
+
+// genericWebhookPayload is the JSON body sent to generic webhook targets.
+type genericWebhookPayload struct {
+	EventType string       `json:"event_type"`
+	Alert     webhookAlert `json:"alert"`
+	Timestamp time.Time    `json:"timestamp"`
+}
+
+// slackWebhookPayload is the JSON body sent to Slack-compatible incoming webhooks.
+type slackWebhookPayload struct {
+	Text string `json:"text"`
+}
+
+// targetDelivery is an alert event routed to one target and queued on that
+// target's delivery goroutine.
+type targetDelivery struct {
+	target *WebhookTarget
+	ev     alertEvent
+}
+
+// errPermanentDelivery marks a delivery failure that retrying cannot fix.
+var errPermanentDelivery = errors.New("permanent delivery failure")
+
+// webhookPruneInterval is how often delivery records past the retention
+// period are deleted.
+const webhookPruneInterval = time.Hour
+
+// AlertWebhookWorker fans pulse alert events out to the enabled webhook
+// targets, retrying failed deliveries with exponential backoff and recording
+// a dead letter when every attempt fails. Each target is delivered to by its
+// own goroutine, so one target's retries never delay the others.
+type AlertWebhookWorker struct {
+	store  *DispatchStore
+	client *http.Client
+	cfg    WebhookConfig
+	logger *zap.Logger
+	queue  chan alertEvent
+
+	mu      sync.Mutex
+	targets map[string]chan targetDelivery // per-target queues, keyed by target ID
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertWebhookWorker creates a worker that delivers alerts using the given config.
+func NewAlertWebhookWorker(store *DispatchStore, cfg WebhookConfig, logger *zap.Logger) *AlertWebhookWorker {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.QueueSize < 1 {
+		cfg.QueueSize = 1
+	}
+	return &AlertWebhookWorker{
+		store:   store,
+		client:  &http.Client{Timeout: cfg.Timeout},
+		cfg:     cfg,
+		logger:  logger,
+		queue:   make(chan alertEvent, cfg.QueueSize),
+		targets: make(map[string]chan targetDelivery),
+	}
+}
+
+// Start launches the routing loop, which hands each event to the delivery
+// goroutine of every target it routes to, and the delivery record pruning
+// loop. It returns immediately.
+func (w *AlertWebhookWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.wg.Add(2)
+	go func() {
+		defer w.wg.Done()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-w.queue:
+				for _, target := range w.routeTargets(ctx, ev) {
+					w.enqueueForTarget(ctx, target, ev)
+				}
+			}
+		}
+	}()
+	go func() {
+		defer w.wg.Done()
+		ticker := time.NewTicker(webhookPruneInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				w.pruneDeliveries(ctx)
+			}
+		}
+	}()
+}
+
+// Stop cancels in-flight deliveries and waits for the delivery goroutines to exit.
+func (w *AlertWebhookWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// Enqueue queues an alert event for delivery. It never blocks; events are
+// dropped with a warning when the queue is full.
+func (w *AlertWebhookWorker) Enqueue(eventType string, alert *pulse.Alert) bool {
+	select {
+	case w.queue <- alertEvent{eventType: eventType, alert: alert, at: time.Now().UTC()}:
+		return true
+	default:
+		w.logger.Warn("webhook queue full, dropping alert event",
+			zap.String("event_type", eventType),
+			zap.String("alert_id", alert.ID),
+		)
+		return false
+	}
+}
+
+// deliver sends one alert event to every target it routes to, one after
+// another. The running worker delivers through enqueueForTarget instead.
+func (w *AlertWebhookWorker) deliver(ctx context.Context, ev alertEvent) {
+	for _, target := range w.routeTargets(ctx, ev) {
+		w.deliverToTarget(ctx, target, ev)
+	}
+}
+
+// routeTargets returns the enabled targets an alert event should be sent to.
+func (w *AlertWebhookWorker) routeTargets(ctx context.Context, ev alertEvent) []*WebhookTarget {
+	routes, err := w.store.ListEnabledNotificationRoutes(ctx)
+	if err != nil {
+		w.logger.Warn("failed to list notification routes", zap.Error(err))
+		return nil
+	}
+	if len(routes) == 0 {
+		// Without routing rules every enabled target receives every alert.
+		targets, err := w.store.ListEnabledWebhookTargets(ctx)
+		if err != nil {
+			w.logger.Warn("failed to list webhook targets", zap.Error(err))
+			return nil
+		}
+		selected := make([]*WebhookTarget, 0, len(targets))
+		for i := range targets {
+			selected = append(selected, &targets[i])
+		}
+		return selected
+	}
+
+	var tags []string
+	if usesDeviceTags(routes) && ev.alert.DeviceID != "" {
+		tags, err = w.store.DeviceTags(ctx, ev.alert.DeviceID)
+		if err != nil {
+			w.logger.Warn("failed to load device tags for routing",
+				zap.String("device_id", ev.alert.DeviceID), zap.Error(err))
+		}
+	}
+
+	var selected []*WebhookTarget
+	for _, id := range selectRouteTargets(routes, ev.alert, tags) {
+		target, err := w.store.GetWebhookTarget(ctx, id)
+		if err != nil {
+			w.logger.Warn("failed to get routed webhook target", zap.String("target_id", id), zap.Error(err))
+			continue
+		}
+		if target == nil || !target.Enabled {
+			continue
+		}
+		selected = append(selected, target)
+	}
+	return selected
+}
+
+// enqueueForTarget queues an event on the target's delivery goroutine,
+// starting the goroutine on the target's first event. It never blocks; the
+// event is dropped with a warning when the target's queue is full, which
+// only happens while that target is failing and backing off.
+func (w *AlertWebhookWorker) enqueueForTarget(ctx context.Context, target *WebhookTarget, ev alertEvent) {
+	w.mu.Lock()
+	q, ok := w.targets[target.ID]
+	if !ok {
+		q = make(chan targetDelivery, w.cfg.QueueSize)
+		w.targets[target.ID] = q
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case d := <-q:
+					w.deliverToTarget(ctx, d.target, d.ev)
+				}
+			}
+		}()
+	}
+	w.mu.Unlock()
+
+	select {
+	case q <- targetDelivery{target: target, ev: ev}:
+	default:
+		w.logger.Warn("webhook target queue full, dropping alert event",
+			zap.String("target_id", target.ID),
+			zap.String("event_type", ev.eventType),
+			zap.String("alert_id", ev.alert.ID),
+		)
+	}
+}
+
+// pruneDeliveries deletes delivery records older than the retention period.
+// Records only need to outlive the window in which a duplicate event could
+// arrive, so a short retention keeps the table from growing without bound.
+func (w *AlertWebhookWorker) pruneDeliveries(ctx context.Context) {
+	if w.cfg.DeliveryRetention <= 0 {
+		return
+	}
+	deleted, err := w.store.DeleteOldWebhookDeliveries(ctx, time.Now().UTC().Add(-w.cfg.DeliveryRetention))
+	if err != nil {
+		w.logger.Warn("failed to prune webhook deliveries", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		w.logger.Info("pruned old webhook deliveries", zap.Int64("count", deleted))
+	}
+}
+
+// deliverToTarget sends one alert event to one target. The delivery key is
+// claimed first so that duplicate events (for example a resolve published
+// twice by racing checks) are sent at most once.
+func (w *AlertWebhookWorker) deliverToTarget(ctx context.Context, target *WebhookTarget, ev alertEvent) {
+	key := webhookDeliveryKey(ev)
+	claimed, err := w.store.ClaimWebhookDelivery(ctx, target.ID, key)
+	if err != nil {
+		w.logger.Warn("failed to claim webhook delivery",
+			zap.String("target_id", target.ID), zap.String("delivery_key", key), zap.Error(err))
+		return
+	}
+	if !claimed {
+		w.logger.Debug("skipping duplicate webhook delivery",
+			zap.String("target_id", target.ID), zap.String("delivery_key", key))
+		return
+	}
+
+	body, err := buildWebhookBody(target.Format, ev)
+	if err != nil {
+		w.logger.Warn("failed to build webhook payload", zap.String("target_id", target.ID), zap.Error(err))
+		return
+	}
+
+	attempts, _, lastErr := w.sendWithRetry(ctx, target, key, body)
+	if lastErr == nil {
+		if err := w.store.FinishWebhookDelivery(ctx, target.ID, key, deliveryStatusDelivered, attempts); err != nil {
+			w.logger.Warn("failed to record webhook delivery", zap.String("target_id", target.ID), zap.Error(err))
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		// Shutting down: leave the claim pending rather than dead-lettering.
+		return
+	}
+
+	w.logger.Warn("webhook delivery failed, writing dead letter",
+		zap.String("target_id", target.ID),
+		zap.String("delivery_key", key),
+		zap.Int("attempts", attempts),
+		zap.Error(lastErr),
+	)
+	if err := w.store.FinishWebhookDelivery(ctx, target.ID, key, deliveryStatusFailed, attempts); err != nil {
+		w.logger.Warn("failed to record webhook delivery", zap.String("target_id", target.ID), zap.Error(err))
+	}
+	if err := w.store.InsertWebhookDeadLetter(ctx, &WebhookDeadLetter{
+		TargetID:    target.ID,
+		DeliveryKey: key,
+		EventType:   ev.eventType,
+		AlertID:     ev.alert.ID,
+		Payload:     string(body),
+		Attempts:    attempts,
+		LastError:   redactSecrets(lastErr.Error()),
+		CreatedAt:   time.Now().UTC(),
+	}); err != nil {
+		w.logger.Error("failed to write webhook dead letter", zap.String("target_id", target.ID), zap.Error(err))
+	}
+}
+
+// webhookDeliveryKey identifies one alert event for delivery claims. Pulse
+// reopens the same alert for each trigger cycle of a flapping check, so the
+// cycle's trigger time is part of the key; without it every cycle after the
+// first would be dropped as a duplicate.
+func webhookDeliveryKey(ev alertEvent) string {
+	return fmt.Sprintf("%s:%s:%d", ev.alert.ID, ev.eventType, ev.alert.TriggeredAt.UnixMilli())
+}
+
+// sendWithRetry POSTs the body until it succeeds, a permanent failure occurs,
+// or MaxAttempts is reached. It returns the number of attempts made and the
+// HTTP status of the last response (0 when no response was received).
+func (w *AlertWebhookWorker) sendWithRetry(ctx context.Context, target *WebhookTarget, key string, body []byte) (int, int, error) {
+	var status int
+	var lastErr error
+	for attempt := 1; attempt <= w.cfg.MaxAttempts; attempt++ {
+		status, lastErr = w.send(ctx, target, key, body)
+		if lastErr == nil || errors.Is(lastErr, errPermanentDelivery) {
+			return attempt, status, lastErr
+		}
+		if attempt == w.cfg.MaxAttempts {
+			break
+		}
+
+		timer := time.NewTimer(webhookBackoff(w.cfg.InitialBackoff, w.cfg.MaxBackoff, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, status, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return w.cfg.MaxAttempts, status, lastErr
+}
+
+// send performs a single POST to the target and returns the response status.
+func (w *AlertWebhookWorker) send(ctx context.Context, target *WebhookTarget, key string, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("create webhook request: %w: %w", errPermanentDelivery, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SubNetree-Webhook/0.1")
+	req.Header.Set("X-SubNetree-Delivery", key)
+	if target.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(target.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", hex.EncodeToString(mac.Sum(nil)))
+	}
+	for k, v := range target.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook POST: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck // drain body for connection reuse
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusRequestTimeout,
+		resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode >= 500:
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	default:
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %w", resp.StatusCode, errPermanentDelivery)
+	}
+}
+
+// testDeliveryTimeout bounds a test notification so that a failing target
+// reports its own error well within the API's request timeout.
+const testDeliveryTimeout = 5 * time.Second
+
+// TestDelivery reports the outcome of a test notification.
+type TestDelivery struct {
+	Delivered  bool    `json:"delivered"`
+	StatusCode int     `json:"status_code,omitempty"` // HTTP status; omitted when no response arrived
+	Attempts   int     `json:"attempts"`
+	LatencyMs  float64 `json:"latency_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// SendTest delivers a synthetic alert to target through the same payload
+// builder and send path used for real alerts. It makes a single attempt
+// bounded by testDeliveryTimeout instead of retrying, so the caller sees
+// the target's error rather than waiting out the backoff. Unlike a real
+// delivery it claims no delivery key and writes no dead letter, so it can
+// be repeated freely and leaves no trace in the delivery history.
+func (w *AlertWebhookWorker) SendTest(ctx context.Context, target *WebhookTarget) TestDelivery {
+	now := time.Now().UTC()
+	ev := alertEvent{
+		eventType: pulse.TopicAlertTriggered,
+		alert: &pulse.Alert{
+			ID:          "test-" + uuid.NewString(),
+			CheckID:     "test",
+			DeviceID:    "test",
+			DeviceName:  "SubNetree test",
+			Severity:    "info",
+			Message:     "Test notification from SubNetree. No action is needed.",
+			TriggeredAt: now,
+		},
+		at: now,
+	}
+	body, err := buildWebhookBody(target.Format, ev)
+	if err != nil {
+		return TestDelivery{Error: err.Error()}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testDeliveryTimeout)
+	defer cancel()
+	start := time.Now()
+	status, err := w.send(ctx, target, ev.alert.ID+":test", body)
+	result := TestDelivery{
+		Delivered:  err == nil,
+		StatusCode: status,
+		Attempts:   1,
+		LatencyMs:  float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Error = redactSecrets(err.Error())
+	}
+	return result
+}
+
+// webhookBackoff returns the delay before retry number attempt (1-based):
+// initial, 2*initial, 4*initial, ... capped at maxDelay.
+func webhookBackoff(initial, maxDelay time.Duration, attempt int) time.Duration {
+	d := initial
+	for i := 1; i < attempt && (maxDelay <= 0 || d < maxDelay); i++ {
+		d *= 2
+	}
+	if maxDelay > 0 && d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+// buildWebhookBody renders the alert event in the target's format with
+// secrets redacted from all free-text fields.
+func buildWebhookBody(format string, ev alertEvent) ([]byte, error) {
+	a := webhookAlert{
+		ID:                  ev.alert.ID,
+		CheckID:             ev.alert.CheckID,
+		DeviceID:            ev.alert.DeviceID,
+		DeviceName:          redactSecrets(ev.alert.DeviceName),
+		Severity:            ev.alert.Severity,
+		Message:             redactSecrets(ev.alert.Message),
+		TriggeredAt:         ev.alert.TriggeredAt,
+		ResolvedAt:          ev.alert.ResolvedAt,
+		ConsecutiveFailures: ev.alert.ConsecutiveFailures,
+	}
+
+	var payload any
+	switch format {
+	case WebhookFormatSlack:
+		payload = slackWebhookPayload{Text: slackAlertText(ev.eventType, &a)}
+	default:
+		payload = genericWebhookPayload{EventType: ev.eventType, Alert: a, Timestamp: ev.at}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	return body, nil
+}
+
+// slackAlertText formats an alert as a single Slack message line.
+func slackAlertText(eventType string, a *webhookAlert) string {
+	device := a.DeviceName
+	if device == "" {
+		device = a.DeviceID
+	}
+	switch eventType {
+	case pulse.TopicAlertResolved:
+		return fmt.Sprintf(":white_check_mark: Resolved: %s - %s", device, a.Message)
+	case pulse.TopicAlertEscalated:
+		return fmt.Sprintf(":arrow_double_up: Escalated to %s: %s - %s", strings.ToUpper(a.Severity), device, a.Message)
+	}
+	return fmt.Sprintf(":rotating_light: [%s] %s - %s", strings.ToUpper(a.Severity), device, a.Message)
+}
+
+var (
+	// key=value or key: value pairs whose key names a credential.
+	secretPairPattern = regexp.MustCompile(`(?i)\b(password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|auth)(\s*[=:]\s*)("[^"]*"|'[^']*'|[^\s&,;]+)`)
+	// Authorization bearer tokens.
+	bearerPattern = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]+`)
+	// user:password@ credentials embedded in URLs.
+	urlUserinfoPattern = regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*://)[^/\s:@]+:[^/\s@]+@`)
+)
+
+// redactSecrets masks credential-like substrings so that alert text copied
+// from check errors or targets cannot leak secrets to third-party endpoints.
+func redactSecrets(s string) string {
+	if s == "" {
+		return s
+	}
+	s = urlUserinfoPattern.ReplaceAllString(s, "${1}****@")
+	s = bearerPattern.ReplaceAllString(s, "Bearer ****")
+	s = secretPairPattern.ReplaceAllString(s, "${1}${2}****")
+	return s
+}
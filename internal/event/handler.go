@@ -0,0 +1,96 @@
+package event
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RecentEventsResponse is the response for GET /admin/events/recent.
+type RecentEventsResponse struct {
+	// Size is how many events the recorder keeps.
+	Size int `json:"size" example:"256"`
+	// Total counts every event recorded since startup, including those
+	// that have rolled out of the buffer.
+	Total  uint64          `json:"total" example:"10452"`
+	Events []RecordedEvent `json:"events"`
+}
+
+// Handler serves the admin event inspection endpoint.
+type Handler struct {
+	recorder   *Recorder
+	adminGuard func(http.Handler) http.Handler
+}
+
+// NewHandler creates a Handler for rec. adminGuard restricts the route to
+// administrators.
+func NewHandler(rec *Recorder, adminGuard func(http.Handler) http.Handler) *Handler {
+	return &Handler{recorder: rec, adminGuard: adminGuard}
+}
+
+// RegisterRoutes registers the event inspection route on the mux.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /api/v1/admin/events/recent", h.adminGuard(http.HandlerFunc(h.handleRecent)))
+}
+
+// handleRecent returns the most recent events seen on the bus.
+//
+//	@Summary		Recent bus events
+//	@Description	Returns the metadata (topic, source, timestamp, payload type) of the
+//	@Description	most recent events published on the event bus, oldest first.
+//	@Description	Requires the admin role.
+//	@Tags			admin
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			limit	query		int		false	"Max events (default: all retained)"
+//	@Param			topic	query		string	false	"Only events whose topic starts with this prefix"
+//	@Success		200		{object}	RecentEventsResponse
+//	@Failure		400		{object}	map[string]any
+//	@Failure		403		{object}	map[string]any
+//	@Router			/admin/events/recent [get]
+func (h *Handler) handleRecent(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "limit must be a positive integer")
+			return
+		}
+		limit = n
+	}
+	topic := r.URL.Query().Get("topic")
+
+	events := h.recorder.Recent(0)
+	if topic != "" {
+		filtered := events[:0]
+		for _, e := range events {
+			if strings.HasPrefix(e.Topic, topic) {
+				filtered = append(filtered, e)
+			}
+		}
+		events = filtered
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(RecentEventsResponse{
+		Size:   h.recorder.Size(),
+		Total:  h.recorder.Total(),
+		Events: events,
+	})
+}
+
+// writeError writes an RFC 7807 problem detail response.
+func writeError(w http.ResponseWriter, status int, detail string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":   "https://subnetree.com/problems/event-error",
+		"title":  http.StatusText(status),
+		"status": status,
+		"detail": detail,
+	})
+}
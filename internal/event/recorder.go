@@ -0,0 +1,94 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+// DefaultRecorderSize is the number of events a Recorder keeps when no size
+// is configured.
+const DefaultRecorderSize = 256
+
+// RecordedEvent is the metadata of one event seen on the bus. Payloads are
+// not kept: they can be large and may carry sensitive data.
+type RecordedEvent struct {
+	Seq         uint64    `json:"seq" example:"1042"`
+	Topic       string    `json:"topic" example:"recon.device.discovered"`
+	Source      string    `json:"source" example:"recon"`
+	Timestamp   time.Time `json:"timestamp"`
+	PayloadType string    `json:"payload_type,omitempty" example:"*recon.DeviceEvent"`
+}
+
+// Recorder keeps the most recent events published on a bus in a fixed-size
+// ring. Recording is lock-free, so a Recorder never blocks publishers; a
+// reader racing a writer may skip the slot being overwritten.
+type Recorder struct {
+	slots []atomic.Pointer[RecordedEvent]
+	next  atomic.Uint64 // sequence number of the last recorded event
+}
+
+// NewRecorder creates a Recorder that keeps the last size events. A size
+// below 1 uses DefaultRecorderSize.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = DefaultRecorderSize
+	}
+	return &Recorder{slots: make([]atomic.Pointer[RecordedEvent], size)}
+}
+
+// Attach subscribes the recorder to every topic on bus. Returns an
+// unsubscribe function.
+func (r *Recorder) Attach(bus plugin.EventBus) (unsubscribe func()) {
+	return bus.SubscribeAll(r.Record)
+}
+
+// Record stores the event's metadata, overwriting the oldest entry once the
+// ring is full. It is a plugin.EventHandler.
+func (r *Recorder) Record(_ context.Context, e plugin.Event) {
+	rec := &RecordedEvent{
+		Topic:     e.Topic,
+		Source:    e.Source,
+		Timestamp: e.Timestamp,
+	}
+	if e.Payload != nil {
+		rec.PayloadType = fmt.Sprintf("%T", e.Payload)
+	}
+	rec.Seq = r.next.Add(1)
+	r.slots[r.slot(rec.Seq)].Store(rec)
+}
+
+// Recent returns up to limit of the most recent events, oldest first. A
+// limit below 1 returns everything retained.
+func (r *Recorder) Recent(limit int) []RecordedEvent {
+	last := r.next.Load()
+	n := min(last, uint64(len(r.slots)))
+	if limit > 0 && uint64(limit) < n {
+		n = uint64(limit)
+	}
+
+	out := make([]RecordedEvent, 0, n)
+	for seq := last - n + 1; seq <= last; seq++ {
+		rec := r.slots[r.slot(seq)].Load()
+		// Nil or a different sequence means the slot is mid-write.
+		if rec == nil || rec.Seq != seq {
+			continue
+		}
+		out = append(out, *rec)
+	}
+	return out
+}
+
+// Size returns the number of events the recorder keeps.
+func (r *Recorder) Size() int { return len(r.slots) }
+
+// Total returns the number of events recorded since creation, including
+// those that have been overwritten.
+func (r *Recorder) Total() uint64 { return r.next.Load() }
+
+func (r *Recorder) slot(seq uint64) uint64 {
+	return (seq - 1) % uint64(len(r.slots))
+}
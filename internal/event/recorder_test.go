@@ -0,0 +1,171 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+func publishN(t *testing.T, bus *Bus, n int) {
+	t.Helper()
+	for i := 1; i <= n; i++ {
+		err := bus.Publish(context.Background(), plugin.Event{
+			Topic:     fmt.Sprintf("test.topic.%d", i),
+			Source:    "test",
+			Timestamp: time.Now(),
+			Payload:   i,
+		})
+		if err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+}
+
+func TestRecorder_RecordsInOrder(t *testing.T) {
+	bus := NewBus(testLogger())
+	rec := NewRecorder(10)
+	rec.Attach(bus)
+
+	publishN(t, bus, 3)
+
+	got := rec.Recent(0)
+	if len(got) != 3 {
+		t.Fatalf("Recent() returned %d events, want 3", len(got))
+	}
+	for i, e := range got {
+		if want := fmt.Sprintf("test.topic.%d", i+1); e.Topic != want {
+			t.Errorf("event %d topic = %q, want %q", i, e.Topic, want)
+		}
+		if e.Source != "test" {
+			t.Errorf("event %d source = %q, want test", i, e.Source)
+		}
+		if e.PayloadType != "int" {
+			t.Errorf("event %d payload type = %q, want int", i, e.PayloadType)
+		}
+		if e.Seq != uint64(i+1) {
+			t.Errorf("event %d seq = %d, want %d", i, e.Seq, i+1)
+		}
+	}
+}
+
+func TestRecorder_CapsAtSize(t *testing.T) {
+	bus := NewBus(testLogger())
+	rec := NewRecorder(5)
+	rec.Attach(bus)
+
+	publishN(t, bus, 12)
+
+	got := rec.Recent(0)
+	if len(got) != 5 {
+		t.Fatalf("Recent() returned %d events, want 5", len(got))
+	}
+	for i, e := range got {
+		if want := fmt.Sprintf("test.topic.%d", 8+i); e.Topic != want {
+			t.Errorf("event %d topic = %q, want %q", i, e.Topic, want)
+		}
+	}
+	if rec.Total() != 12 {
+		t.Errorf("Total() = %d, want 12", rec.Total())
+	}
+
+	last := rec.Recent(2)
+	if len(last) != 2 || last[0].Topic != "test.topic.11" || last[1].Topic != "test.topic.12" {
+		t.Errorf("Recent(2) = %+v, want topics 11 and 12", last)
+	}
+}
+
+func TestRecorder_ConcurrentPublishers(t *testing.T) {
+	bus := NewBus(testLogger())
+	rec := NewRecorder(64)
+	rec.Attach(bus)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				bus.PublishAsync(context.Background(), plugin.Event{Topic: "load", Source: "test"})
+				_ = rec.Recent(0)
+			}
+		}()
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rec.Total() < 800 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if rec.Total() != 800 {
+		t.Fatalf("Total() = %d, want 800", rec.Total())
+	}
+	got := rec.Recent(0)
+	if len(got) != 64 {
+		t.Fatalf("Recent() returned %d events, want 64", len(got))
+	}
+	for i := 1; i < len(got); i++ {
+		if got[i].Seq <= got[i-1].Seq {
+			t.Fatalf("events out of order: seq %d after %d", got[i].Seq, got[i-1].Seq)
+		}
+	}
+}
+
+func TestHandler_Recent(t *testing.T) {
+	bus := NewBus(testLogger())
+	rec := NewRecorder(10)
+	rec.Attach(bus)
+	publishN(t, bus, 4)
+	_ = bus.Publish(context.Background(), plugin.Event{Topic: "other.topic", Source: "test"})
+
+	allow := func(next http.Handler) http.Handler { return next }
+	mux := http.NewServeMux()
+	NewHandler(rec, allow).RegisterRoutes(mux)
+
+	get := func(query string) (int, RecentEventsResponse) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/events/recent"+query, http.NoBody))
+		var resp RecentEventsResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	code, resp := get("")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if resp.Size != 10 || resp.Total != 5 || len(resp.Events) != 5 {
+		t.Errorf("response = size %d, total %d, %d events; want 10, 5, 5", resp.Size, resp.Total, len(resp.Events))
+	}
+
+	_, resp = get("?topic=test.&limit=2")
+	if len(resp.Events) != 2 || resp.Events[0].Topic != "test.topic.3" || resp.Events[1].Topic != "test.topic.4" {
+		t.Errorf("filtered events = %+v, want test.topic.3 and test.topic.4", resp.Events)
+	}
+
+	if code, _ := get("?limit=0"); code != http.StatusBadRequest {
+		t.Errorf("limit=0 status = %d, want 400", code)
+	}
+}
+
+func TestHandler_AdminGuard(t *testing.T) {
+	deny := func(http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	mux := http.NewServeMux()
+	NewHandler(NewRecorder(4), deny).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/admin/events/recent", http.NoBody))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", w.Code)
+	}
+}
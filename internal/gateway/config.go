@@ -4,20 +4,29 @@ import "time"
 
 // GatewayConfig holds configuration for the Gateway module.
 type GatewayConfig struct {
-	SessionTimeout      time.Duration `mapstructure:"session_timeout"`
+	SessionTimeout      time.Duration `mapstructure:"session_timeout"` // absolute lifetime
+	IdleTimeout         time.Duration `mapstructure:"idle_timeout"`    // 0 disables
 	MaxSessions         int           `mapstructure:"max_sessions"`
 	AuditRetentionDays  int           `mapstructure:"audit_retention_days"`
 	MaintenanceInterval time.Duration `mapstructure:"maintenance_interval"`
 	DefaultProxyPort    int           `mapstructure:"default_proxy_port"`
+
+	// RecordingEnabled records SSH sessions as asciinema casts in
+	// RecordingDir. Recordings are pruned with the audit log.
+	RecordingEnabled bool   `mapstructure:"recording_enabled"`
+	RecordingDir     string `mapstructure:"recording_dir"`
 }
 
 // DefaultConfig returns the default Gateway configuration.
 func DefaultConfig() GatewayConfig {
 	return GatewayConfig{
 		SessionTimeout:      30 * time.Minute,
+		IdleTimeout:         10 * time.Minute,
 		MaxSessions:         100,
 		AuditRetentionDays:  90,
 		MaintenanceInterval: 5 * time.Minute,
 		DefaultProxyPort:    80,
+		RecordingEnabled:    false,
+		RecordingDir:        "./data/recordings",
 	}
 }
@@ -96,10 +96,14 @@ func (m *Module) Start(_ context.Context) error {
 	if m.store != nil {
 		m.startMaintenance()
 	}
+	if m.cfg.IdleTimeout > 0 {
+		m.startIdleReaper()
+	}
 
 	m.logger.Info("gateway module started",
 		zap.Int("max_sessions", m.cfg.MaxSessions),
 		zap.Duration("session_timeout", m.cfg.SessionTimeout),
+		zap.Duration("idle_timeout", m.cfg.IdleTimeout),
 	)
 	return nil
 }
@@ -114,6 +118,7 @@ func (m *Module) Stop(_ context.Context) error {
 	if m.sessions != nil {
 		for _, s := range m.sessions.List() {
 			m.sessions.Delete(s.ID)
+			s.close()
 			m.logSessionClosed(s, "shutdown")
 		}
 	}
@@ -184,6 +189,51 @@ func (m *Module) startMaintenance() {
 	}()
 }
 
+// startIdleReaper closes sessions that have been idle longer than
+// IdleTimeout. It checks several times per timeout so sessions are reaped
+// close to the deadline, but never more than once a second.
+func (m *Module) startIdleReaper() {
+	interval := m.cfg.IdleTimeout / 4
+	interval = max(interval, time.Second)
+	interval = min(interval, time.Minute)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.reapIdleSessions()
+			}
+		}
+	}()
+}
+
+// reapIdleSessions closes idle sessions, tearing down their proxies and
+// live connections, and audits each as closed:idle.
+func (m *Module) reapIdleSessions() {
+	if m.sessions == nil {
+		return
+	}
+	idle := m.sessions.CloseIdle(m.cfg.IdleTimeout)
+	for _, s := range idle {
+		if m.proxies != nil {
+			m.proxies.RemoveProxy(s.ID)
+		}
+		s.close()
+		m.logSessionClosed(s, "idle")
+	}
+	if len(idle) > 0 {
+		m.logger.Info("idle sessions closed", zap.Int("count", len(idle)))
+	}
+}
+
 func (m *Module) runMaintenance() {
 	// Close expired sessions and their proxies.
 	if m.sessions != nil {
@@ -192,6 +242,7 @@ func (m *Module) runMaintenance() {
 			if m.proxies != nil {
 				m.proxies.RemoveProxy(s.ID)
 			}
+			s.close()
 			m.logSessionClosed(s, "expired")
 		}
 		if len(expired) > 0 {
@@ -214,6 +265,15 @@ func (m *Module) runMaintenance() {
 				zap.Int64("deleted", deleted),
 			)
 		}
+
+		if m.cfg.RecordingEnabled {
+			pruned, err := m.pruneRecordings(cutoff)
+			if err != nil {
+				m.logger.Warn("gateway recording maintenance failed", zap.Error(err))
+			} else if pruned > 0 {
+				m.logger.Info("gateway recordings pruned", zap.Int("deleted", pruned))
+			}
+		}
 	}
 }
 
@@ -3,6 +3,7 @@ package gateway
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/pkg/plugin"
@@ -120,8 +121,10 @@ func TestRoutes(t *testing.T) {
 		"GET /sessions":                           "",
 		"GET /sessions/{id}":                      "",
 		"DELETE /sessions/{id}":                   "",
+		"GET /sessions/{id}/recording":            "",
 		"GET /status":                             "",
 		"GET /audit":                              "",
+		"GET /audit.csv":                          "",
 		"POST /proxy/{device_id}":                 "",
 		"GET /proxy/s/{session_id}/{path...}":     "",
 		"POST /proxy/s/{session_id}/{path...}":    "",
@@ -226,3 +229,53 @@ func TestAvailable_NilSessions(t *testing.T) {
 		t.Error("Available() should return false when sessions manager is nil")
 	}
 }
+
+func TestReapIdleSessions(t *testing.T) {
+	m := newTestModule(t)
+	bus := &testEventBus{}
+	m.bus = bus
+	m.cfg.IdleTimeout = 5 * time.Minute
+
+	clock := time.Now()
+	m.sessions.now = func() time.Time { return clock }
+
+	var cancelled bool
+	idle := newTestSession("gw-idle", "dev-1", clock.Add(time.Hour))
+	idle.CreatedAt = clock
+	idle.cancel = func() { cancelled = true }
+	active := newTestSession("gw-active", "dev-2", clock.Add(time.Hour))
+	active.CreatedAt = clock
+	for _, s := range []*Session{idle, active} {
+		if err := m.sessions.Create(s); err != nil {
+			t.Fatalf("Create(%s): %v", s.ID, err)
+		}
+	}
+
+	clock = clock.Add(3 * time.Minute)
+	active.BytesIn.Add(512)
+	m.reapIdleSessions()
+
+	clock = clock.Add(3 * time.Minute)
+	m.reapIdleSessions()
+
+	if _, ok := m.sessions.Get("gw-idle"); ok {
+		t.Error("idle session was not reaped")
+	}
+	if _, ok := m.sessions.Get("gw-active"); !ok {
+		t.Error("active session was reaped")
+	}
+	if !cancelled {
+		t.Error("idle session connection was not cancelled")
+	}
+
+	entries, err := m.store.ListAuditEntries(context.Background(), "dev-1", 10)
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Action != "closed:idle" {
+		t.Errorf("audit entries = %+v, want one closed:idle", entries)
+	}
+	if e := bus.lastEvent(); e == nil || e.Topic != TopicSessionClosed {
+		t.Errorf("last event = %v, want %s", e, TopicSessionClosed)
+	}
+}
@@ -1,21 +1,25 @@
 package gateway
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/HerbHall/subnetree/pkg/pagination"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"go.uber.org/zap"
 )
 
 // createProxyRequest is the JSON body for POST /proxy/{device_id}.
 type createProxyRequest struct {
-	Port   int    `json:"port"`
-	Scheme string `json:"scheme"`
-	Target string `json:"target"` // Optional fallback IP when DeviceLookup unavailable
+	Port      int    `json:"port"`
+	Scheme    string `json:"scheme"`
+	Target    string `json:"target"`     // Optional fallback IP when DeviceLookup unavailable
+	RateLimit int64  `json:"rate_limit"` // Bytes per second; 0 means unlimited
 }
 
 // createProxyResponse is the JSON response for a newly created proxy session.
@@ -30,14 +34,16 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "GET", Path: "/sessions", Handler: m.handleListSessions},
 		{Method: "GET", Path: "/sessions/{id}", Handler: m.handleGetSession},
 		{Method: "DELETE", Path: "/sessions/{id}", Handler: m.handleDeleteSession},
+		{Method: "GET", Path: "/sessions/{id}/recording", Handler: m.handleGetRecording, Class: plugin.RouteStream},
 		{Method: "GET", Path: "/status", Handler: m.handleStatus},
 		{Method: "GET", Path: "/audit", Handler: m.handleListAudit},
+		{Method: "GET", Path: "/audit.csv", Handler: m.handleExportAuditCSV, Class: plugin.RouteStream},
 		{Method: "POST", Path: "/proxy/{device_id}", Handler: m.handleCreateProxy},
-		{Method: "GET", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic},
-		{Method: "POST", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic},
-		{Method: "PUT", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic},
-		{Method: "DELETE", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic},
-		{Method: "PATCH", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic},
+		{Method: "GET", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic, Class: plugin.RouteProxy},
+		{Method: "POST", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic, Class: plugin.RouteProxy},
+		{Method: "PUT", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic, Class: plugin.RouteProxy},
+		{Method: "DELETE", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic, Class: plugin.RouteProxy},
+		{Method: "PATCH", Path: "/proxy/s/{session_id}/{path...}", Handler: m.handleProxyTraffic, Class: plugin.RouteProxy},
 	}
 }
 
@@ -101,6 +107,7 @@ func (m *Module) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
 	if m.proxies != nil {
 		m.proxies.RemoveProxy(id)
 	}
+	session.close()
 	m.logSessionClosed(session, "manual")
 
 	w.WriteHeader(http.StatusNoContent)
@@ -125,26 +132,119 @@ func (m *Module) handleStatus(w http.ResponseWriter, _ *http.Request) {
 	})
 }
 
-// handleListAudit returns audit log entries with optional device filtering.
+// handleListAudit returns audit log entries with optional device and
+// time range filtering. from and to are RFC3339 timestamps; from is
+// inclusive and to is exclusive. X-Total-Count holds the number of
+// matching entries, and envelope=true wraps the page with its metadata.
 func (m *Module) handleListAudit(w http.ResponseWriter, r *http.Request) {
 	if m.store == nil {
 		gatewayWriteError(w, http.StatusServiceUnavailable, "gateway store not available")
 		return
 	}
 
-	deviceID := r.URL.Query().Get("device_id")
-	limit := gatewayParseLimit(r, 100)
+	q, err := parseAuditQuery(r)
+	if err != nil {
+		gatewayWriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	q.Limit = gatewayParseLimit(r, 100)
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			q.Offset = n
+		}
+	}
 
-	entries, err := m.store.ListAuditEntries(r.Context(), deviceID, limit)
+	entries, err := m.store.QueryAuditEntries(r.Context(), q)
 	if err != nil {
 		m.logger.Warn("failed to list gateway audit entries", zap.Error(err))
 		gatewayWriteError(w, http.StatusInternalServerError, "failed to list audit entries")
 		return
 	}
-	if entries == nil {
-		entries = []AuditEntry{}
+	total, err := m.store.CountAuditEntries(r.Context(), q)
+	if err != nil {
+		m.logger.Warn("failed to count gateway audit entries", zap.Error(err))
+		gatewayWriteError(w, http.StatusInternalServerError, "failed to list audit entries")
+		return
+	}
+	pagination.Write(w, r, entries, total, q.Limit, q.Offset)
+}
+
+// auditCSVHeader is the column order of the audit CSV export.
+var auditCSVHeader = []string{
+	"id", "timestamp", "session_id", "device_id", "user_id", "session_type",
+	"target", "action", "bytes_in", "bytes_out", "source_ip",
+}
+
+// handleExportAuditCSV streams audit log entries as a CSV file. It accepts
+// the same device_id, from, and to filters as handleListAudit but returns
+// every matching entry.
+func (m *Module) handleExportAuditCSV(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		gatewayWriteError(w, http.StatusServiceUnavailable, "gateway store not available")
+		return
+	}
+
+	q, err := parseAuditQuery(r)
+	if err != nil {
+		gatewayWriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", `attachment; filename="gateway-audit.csv"`)
+
+	writer := csv.NewWriter(w)
+	_ = writer.Write(auditCSVHeader)
+
+	err = m.store.EachAuditEntry(r.Context(), q, func(e *AuditEntry) error {
+		return writer.Write(auditCSVRow(e))
+	})
+	writer.Flush()
+	if err != nil {
+		// Headers are already sent; all we can do is log the truncation.
+		m.logger.Warn("failed to export gateway audit entries", zap.Error(err))
+	}
+}
+
+// auditCSVRow formats an audit entry in auditCSVHeader order.
+func auditCSVRow(e *AuditEntry) []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		e.Timestamp.UTC().Format(time.RFC3339),
+		e.SessionID,
+		e.DeviceID,
+		e.UserID,
+		e.SessionType,
+		e.Target,
+		e.Action,
+		strconv.FormatInt(e.BytesIn, 10),
+		strconv.FormatInt(e.BytesOut, 10),
+		e.SourceIP,
+	}
+}
+
+// parseAuditQuery reads the device_id, from, and to filters shared by the
+// audit endpoints.
+func parseAuditQuery(r *http.Request) (AuditQuery, error) {
+	q := AuditQuery{DeviceID: r.URL.Query().Get("device_id")}
+	if s := r.URL.Query().Get("from"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return q, errors.New("from must be an RFC3339 timestamp")
+		}
+		q.From = t
+	}
+	if s := r.URL.Query().Get("to"); s != "" {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return q, errors.New("to must be an RFC3339 timestamp")
+		}
+		q.To = t
 	}
-	gatewayWriteJSON(w, http.StatusOK, entries)
+	if !q.From.IsZero() && !q.To.IsZero() && !q.From.Before(q.To) {
+		return q, errors.New("from must be before to")
+	}
+	return q, nil
 }
 
 // --- Proxy Handlers ---
@@ -169,6 +269,10 @@ func (m *Module) handleCreateProxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if body.RateLimit < 0 {
+		gatewayWriteError(w, http.StatusBadRequest, "rate_limit must not be negative")
+		return
+	}
 	if body.Port <= 0 {
 		body.Port = m.cfg.DefaultProxyPort
 	}
@@ -200,6 +304,8 @@ func (m *Module) handleCreateProxy(w http.ResponseWriter, r *http.Request) {
 		SourceIP:    r.RemoteAddr,
 		CreatedAt:   time.Now().UTC(),
 		ExpiresAt:   time.Now().UTC().Add(m.cfg.SessionTimeout),
+		RateLimit:   body.RateLimit,
+		limiter:     newByteLimiter(body.RateLimit),
 	}
 
 	if err := m.sessions.Create(session); err != nil {
@@ -293,6 +399,13 @@ func (m *Module) handleProxyTraffic(w http.ResponseWriter, r *http.Request) {
 	// Clear the RequestURI to avoid conflicts with the modified URL.
 	r.RequestURI = ""
 
+	// Count proxied bytes so the idle reaper sees activity, and throttle
+	// them to the session's rate limit.
+	if r.Body != nil && r.Body != http.NoBody {
+		r.Body = &countingReadCloser{ReadCloser: r.Body, n: &session.BytesIn, ctx: r.Context(), limiter: session.limiter}
+	}
+	w = &countingResponseWriter{ResponseWriter: w, n: &session.BytesOut, ctx: r.Context(), limiter: session.limiter}
+
 	if err := m.proxies.ServeProxy(sessionID, w, r); err != nil {
 		gatewayWriteError(w, http.StatusNotFound, "proxy session not found")
 		return
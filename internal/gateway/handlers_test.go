@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,7 @@ import (
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/store"
+	"github.com/HerbHall/subnetree/pkg/pagination"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"go.uber.org/zap"
 )
@@ -363,6 +365,39 @@ func TestHandleListAudit_WithDeviceFilter(t *testing.T) {
 	}
 }
 
+func TestHandleListAudit_Pagination(t *testing.T) {
+	m := newTestModule(t)
+	base := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		_ = m.store.InsertAuditEntry(context.Background(), &AuditEntry{
+			SessionID: fmt.Sprintf("s%d", i), DeviceID: "dev-1", SessionType: "ssh",
+			Target: "192.168.1.2:22", Action: "created", Timestamp: base.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?limit=2&offset=2&envelope=true", http.NoBody)
+	rr := httptest.NewRecorder()
+	m.handleListAudit(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	if got := rr.Header().Get(pagination.TotalCountHeader); got != "5" {
+		t.Errorf("%s = %q, want 5", pagination.TotalCountHeader, got)
+	}
+	var page pagination.Page[AuditEntry]
+	if err := json.NewDecoder(rr.Body).Decode(&page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if page.Total != 5 || page.Limit != 2 || page.Offset != 2 {
+		t.Errorf("page = total %d, limit %d, offset %d; want 5, 2, 2", page.Total, page.Limit, page.Offset)
+	}
+	// Newest first, so offset 2 starts at s2.
+	if len(page.Items) != 2 || page.Items[0].SessionID != "s2" || page.Items[1].SessionID != "s1" {
+		t.Errorf("items = %+v, want s2 and s1", page.Items)
+	}
+}
+
 func TestHandleListAudit_NilStore(t *testing.T) {
 	m := &Module{
 		logger:   zap.NewNop(),
@@ -707,3 +742,110 @@ func TestGatewayParseLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleListAudit_TimeRange(t *testing.T) {
+	m := newTestModule(t)
+	base := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	for i, ts := range []time.Time{base.Add(-time.Hour), base, base.Add(time.Hour)} {
+		_ = m.store.InsertAuditEntry(context.Background(), &AuditEntry{
+			SessionID: fmt.Sprintf("s%d", i), DeviceID: "dev-1", SessionType: "ssh",
+			Target: "192.168.1.2:22", Action: "created", Timestamp: ts,
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?from=2026-07-01T00:00:00Z&to=2026-07-01T01:00:00Z", http.NoBody)
+	rr := httptest.NewRecorder()
+	m.handleListAudit(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+	}
+	var entries []AuditEntry
+	if err := json.NewDecoder(rr.Body).Decode(&entries); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(entries) != 1 || entries[0].SessionID != "s1" {
+		t.Errorf("entries = %+v, want only s1", entries)
+	}
+}
+
+func TestHandleListAudit_InvalidRange(t *testing.T) {
+	m := newTestModule(t)
+
+	for _, query := range []string{
+		"from=yesterday",
+		"to=2026-07-01",
+		"from=2026-07-02T00:00:00Z&to=2026-07-01T00:00:00Z",
+		"from=2026-07-01T00:00:00Z&to=2026-07-01T00:00:00Z",
+	} {
+		for name, handler := range map[string]http.HandlerFunc{
+			"json": m.handleListAudit,
+			"csv":  m.handleExportAuditCSV,
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/audit?"+query, http.NoBody)
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+			if rr.Code != http.StatusBadRequest {
+				t.Errorf("%s %q: status = %d, want %d", name, query, rr.Code, http.StatusBadRequest)
+			}
+		}
+	}
+}
+
+func TestHandleExportAuditCSV(t *testing.T) {
+	m := newTestModule(t)
+	ts := time.Date(2026, 8, 15, 9, 30, 0, 0, time.UTC)
+	_ = m.store.InsertAuditEntry(context.Background(), &AuditEntry{
+		SessionID: "s1", DeviceID: "dev-1", UserID: "alice", SessionType: "ssh",
+		Target: "192.168.1.2:22", Action: "closed:idle", BytesIn: 42, BytesOut: 1024,
+		SourceIP: "10.0.0.5:51234", Timestamp: ts,
+	})
+	_ = m.store.InsertAuditEntry(context.Background(), &AuditEntry{
+		SessionID: "s2", DeviceID: "dev-1", UserID: "bob, jr", SessionType: "http_proxy",
+		Target: "192.168.1.1:80", Action: "created", Timestamp: ts.Add(-90 * 24 * time.Hour),
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/audit.csv?device_id=dev-1&from=2026-07-01T00:00:00Z", http.NoBody)
+	rr := httptest.NewRecorder()
+	m.handleExportAuditCSV(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != `attachment; filename="gateway-audit.csv"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("parse CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("records = %d, want header + 1 row", len(records))
+	}
+	if strings.Join(records[0], ",") != strings.Join(auditCSVHeader, ",") {
+		t.Errorf("header = %v", records[0])
+	}
+	want := []string{
+		records[1][0], "2026-08-15T09:30:00Z", "s1", "dev-1", "alice", "ssh",
+		"192.168.1.2:22", "closed:idle", "42", "1024", "10.0.0.5:51234",
+	}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("row = %v, want %v", records[1], want)
+	}
+}
+
+func TestAuditCSVRow_Quoting(t *testing.T) {
+	var buf strings.Builder
+	w := csv.NewWriter(&buf)
+	_ = w.Write(auditCSVRow(&AuditEntry{ID: 7, UserID: "bob, jr", Action: `say "hi"`, Timestamp: time.Unix(0, 0)}))
+	w.Flush()
+
+	want := `7,1970-01-01T00:00:00Z,,,"bob, jr",,,"say ""hi""",0,0,` + "\n"
+	if buf.String() != want {
+		t.Errorf("row = %q, want %q", buf.String(), want)
+	}
+}
@@ -1,11 +1,14 @@
 package gateway
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
 	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 )
@@ -103,3 +106,46 @@ func (pm *ReverseProxyManager) Count() int {
 	defer pm.mu.RUnlock()
 	return len(pm.proxies)
 }
+
+// countingResponseWriter adds the bytes written to the client to n. When
+// limiter is set, each write first waits for the session's rate limit.
+type countingResponseWriter struct {
+	http.ResponseWriter
+	n       *atomic.Int64
+	ctx     context.Context
+	limiter *byteLimiter
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	if err := w.limiter.WaitN(w.ctx, len(p)); err != nil {
+		return 0, err
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.n.Add(int64(n))
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer so the
+// reverse proxy can still flush streamed responses.
+func (w *countingResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// countingReadCloser adds the bytes read from the request body to n. When
+// limiter is set, each read waits for the session's rate limit before
+// returning the data.
+type countingReadCloser struct {
+	io.ReadCloser
+	n       *atomic.Int64
+	ctx     context.Context
+	limiter *byteLimiter
+}
+
+func (r *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n.Add(int64(n))
+	if wErr := r.limiter.WaitN(r.ctx, n); wErr != nil {
+		return n, wErr
+	}
+	return n, err
+}
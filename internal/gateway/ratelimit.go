@@ -0,0 +1,75 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxLimiterBurst caps the token bucket size so a session cannot transfer
+// more than this many bytes in a single burst above its configured rate.
+const maxLimiterBurst = 32 * 1024
+
+// byteLimiter throttles a session's traffic to a fixed number of bytes per
+// second using a token bucket. A nil *byteLimiter is valid and never waits,
+// which is how an unlimited session is represented.
+type byteLimiter struct {
+	lim   *rate.Limiter
+	burst int
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// newByteLimiter returns a limiter for bytesPerSec, or nil when
+// bytesPerSec is zero or negative (unlimited).
+func newByteLimiter(bytesPerSec int64) *byteLimiter {
+	if bytesPerSec <= 0 {
+		return nil
+	}
+	burst := int(min(bytesPerSec, maxLimiterBurst))
+	return &byteLimiter{
+		lim:   rate.NewLimiter(rate.Limit(bytesPerSec), burst),
+		burst: burst,
+		now:   time.Now,
+		sleep: sleepContext,
+	}
+}
+
+// WaitN blocks until n bytes may be transferred or ctx is done. Transfers
+// larger than the bucket are split so any size can be admitted.
+func (l *byteLimiter) WaitN(ctx context.Context, n int) error {
+	if l == nil {
+		return nil
+	}
+	for n > 0 {
+		chunk := min(n, l.burst)
+		now := l.now()
+		r := l.lim.ReserveN(now, chunk)
+		if !r.OK() {
+			return fmt.Errorf("rate limiter cannot admit %d bytes", chunk)
+		}
+		if err := l.sleep(ctx, r.DelayFrom(now)); err != nil {
+			r.CancelAt(l.now())
+			return err
+		}
+		n -= chunk
+	}
+	return nil
+}
+
+// sleepContext sleeps for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,157 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClockLimiter returns a limiter whose sleeps advance a virtual clock
+// instead of blocking, and a func reporting the virtual time elapsed.
+func fakeClockLimiter(t *testing.T, bytesPerSec int64) (*byteLimiter, func() time.Duration) {
+	t.Helper()
+	l := newByteLimiter(bytesPerSec)
+	if l == nil {
+		t.Fatalf("newByteLimiter(%d) = nil", bytesPerSec)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := start
+	l.now = func() time.Time { return clock }
+	l.sleep = func(_ context.Context, d time.Duration) error {
+		clock = clock.Add(d)
+		return nil
+	}
+	return l, func() time.Duration { return clock.Sub(start) }
+}
+
+func TestByteLimiter_StaysUnderRate(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  int64
+		chunk int
+	}{
+		{"small writes", 10_000, 512},
+		{"writes larger than burst", 50_000, 100_000},
+		{"tiny rate", 100, 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, elapsed := fakeClockLimiter(t, tt.rate)
+			ctx := context.Background()
+
+			var sent int64
+			for elapsed() < 10*time.Second {
+				if err := l.WaitN(ctx, tt.chunk); err != nil {
+					t.Fatalf("WaitN: %v", err)
+				}
+				sent += int64(tt.chunk)
+			}
+
+			// The bucket starts full, so allow one burst on top of the rate,
+			// plus the chunk the final WaitN reserved before its sleep ended.
+			window := elapsed().Seconds()
+			limit := int64(math.Ceil(float64(tt.rate)*window)) + int64(l.burst) + int64(tt.chunk)
+			if sent > limit {
+				t.Errorf("sent %d bytes in %.2fs, want at most %d", sent, window, limit)
+			}
+			if achieved := float64(sent) / window; achieved < 0.9*float64(tt.rate) {
+				t.Errorf("achieved %.0f B/s, want close to %d", achieved, tt.rate)
+			}
+		})
+	}
+}
+
+func TestByteLimiter_ZeroIsUnlimited(t *testing.T) {
+	for _, limit := range []int64{0, -1} {
+		if l := newByteLimiter(limit); l != nil {
+			t.Errorf("newByteLimiter(%d) = %+v, want nil", limit, l)
+		}
+	}
+
+	var l *byteLimiter
+	if err := l.WaitN(context.Background(), 1<<30); err != nil {
+		t.Errorf("nil limiter WaitN: %v", err)
+	}
+}
+
+func TestByteLimiter_ContextCancelled(t *testing.T) {
+	l := newByteLimiter(1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// A cancelled context aborts the wait instead of admitting the bytes.
+	if err := l.WaitN(ctx, 2); err == nil {
+		t.Error("WaitN with cancelled context = nil, want error")
+	}
+}
+
+func TestCountingResponseWriter_RateLimited(t *testing.T) {
+	l, elapsed := fakeClockLimiter(t, 1000)
+	var n atomic.Int64
+	rec := httptest.NewRecorder()
+	w := &countingResponseWriter{ResponseWriter: rec, n: &n, ctx: context.Background(), limiter: l}
+
+	payload := bytes.Repeat([]byte("x"), 5000)
+	if _, err := w.Write(payload); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if n.Load() != 5000 || rec.Body.Len() != 5000 {
+		t.Errorf("counted %d, wrote %d, want 5000", n.Load(), rec.Body.Len())
+	}
+	// 1000 bytes of burst, then 4000 bytes at 1000 B/s.
+	if got := elapsed(); got < 4*time.Second {
+		t.Errorf("elapsed = %v, want at least 4s", got)
+	}
+}
+
+func TestCountingReadCloser_Unlimited(t *testing.T) {
+	var n atomic.Int64
+	r := &countingReadCloser{
+		ReadCloser: io.NopCloser(strings.NewReader("hello world")),
+		n:          &n,
+		ctx:        context.Background(),
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll: %v", err)
+	}
+	if string(data) != "hello world" || n.Load() != 11 {
+		t.Errorf("read %q, counted %d", data, n.Load())
+	}
+}
+
+func TestHandleCreateProxy_RateLimit(t *testing.T) {
+	m := newTestModule(t)
+
+	body := `{"target": "192.168.1.100", "rate_limit": 65536}`
+	req := httptest.NewRequest(http.MethodPost, "/proxy/dev-1", strings.NewReader(body))
+	req.SetPathValue("device_id", "dev-1")
+	rr := httptest.NewRecorder()
+	m.handleCreateProxy(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body = %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	if !strings.Contains(rr.Body.String(), `"rate_limit":65536`) {
+		t.Errorf("response missing rate_limit: %s", rr.Body.String())
+	}
+	sessions := m.sessions.List()
+	if len(sessions) != 1 || sessions[0].limiter == nil {
+		t.Fatal("session was created without a limiter")
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/proxy/dev-1", strings.NewReader(`{"target": "192.168.1.100", "rate_limit": -1}`))
+	req.SetPathValue("device_id", "dev-1")
+	rr = httptest.NewRecorder()
+	m.handleCreateProxy(rr, req)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("negative rate_limit status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+}
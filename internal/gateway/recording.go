@@ -0,0 +1,268 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// recordingExt is the file extension for session recordings.
+const recordingExt = ".cast"
+
+// castHeader is the first line of an asciinema v2 cast file.
+type castHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// SessionRecorder writes the I/O of a terminal session as an asciinema v2
+// cast: a JSON header line followed by one [elapsed, type, data] event per
+// line. Output is recorded as "o" events and keystrokes as "i" events.
+// It is safe for concurrent use by the stdin and stdout copy goroutines.
+type SessionRecorder struct {
+	mu    sync.Mutex
+	w     io.WriteCloser
+	start time.Time
+	now   func() time.Time
+
+	// Trailing bytes of an incomplete UTF-8 sequence, per stream, carried
+	// into the next event so multi-byte characters split across reads are
+	// not replaced with U+FFFD.
+	pending map[string][]byte
+}
+
+// NewSessionRecorder writes the cast header to w and returns a recorder
+// whose event times are relative to start.
+func NewSessionRecorder(w io.WriteCloser, width, height int, start time.Time) (*SessionRecorder, error) {
+	header, err := json.Marshal(castHeader{
+		Version:   2,
+		Width:     width,
+		Height:    height,
+		Timestamp: start.Unix(),
+		Env:       map[string]string{"TERM": "xterm"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal cast header: %w", err)
+	}
+	if _, err := w.Write(append(header, '\n')); err != nil {
+		return nil, fmt.Errorf("write cast header: %w", err)
+	}
+	return &SessionRecorder{
+		w:       w,
+		start:   start,
+		now:     time.Now,
+		pending: make(map[string][]byte),
+	}, nil
+}
+
+// RecordOutput records data sent from the remote host to the terminal.
+func (r *SessionRecorder) RecordOutput(p []byte) error {
+	return r.writeEvent("o", p)
+}
+
+// RecordInput records data typed by the user.
+func (r *SessionRecorder) RecordInput(p []byte) error {
+	return r.writeEvent("i", p)
+}
+
+func (r *SessionRecorder) writeEvent(kind string, p []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data := append(r.pending[kind], p...)
+	cut := incompleteUTF8Suffix(data)
+	r.pending[kind] = append([]byte(nil), data[len(data)-cut:]...)
+	data = data[:len(data)-cut]
+	if len(data) == 0 {
+		return nil
+	}
+
+	return r.emit(kind, data)
+}
+
+// emit writes one event line. The caller must hold r.mu.
+func (r *SessionRecorder) emit(kind string, data []byte) error {
+	elapsed := r.now().Sub(r.start).Seconds()
+	line, err := json.Marshal([]any{elapsed, kind, string(data)})
+	if err != nil {
+		return fmt.Errorf("marshal cast event: %w", err)
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write cast event: %w", err)
+	}
+	return nil
+}
+
+// Close writes out any buffered partial character and closes the
+// underlying writer.
+func (r *SessionRecorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, kind := range []string{"o", "i"} {
+		if p := r.pending[kind]; len(p) > 0 {
+			// The stream ended mid-character; keep what we have.
+			_ = r.emit(kind, p)
+		}
+	}
+	clear(r.pending)
+	return r.w.Close()
+}
+
+// incompleteUTF8Suffix returns the length of a trailing partial UTF-8
+// sequence in p, or 0 if p ends on a character boundary.
+func incompleteUTF8Suffix(p []byte) int {
+	for n := 1; n <= utf8.UTFMax-1 && n <= len(p); n++ {
+		b := p[len(p)-n]
+		if b < utf8.RuneSelf {
+			return 0 // ASCII: boundary
+		}
+		if utf8.RuneStart(b) {
+			if utf8.FullRune(p[len(p)-n:]) {
+				return 0
+			}
+			return n
+		}
+	}
+	return 0
+}
+
+// recordingPath returns the cast file path for a session.
+func (m *Module) recordingPath(sessionID string) string {
+	return filepath.Join(m.cfg.RecordingDir, sessionID+recordingExt)
+}
+
+// startRecording opens a recording for an SSH session when recording is
+// enabled. Failures are logged and return nil so the session continues
+// unrecorded.
+func (m *Module) startRecording(ctx context.Context, s *Session, width, height int) *SessionRecorder {
+	if !m.cfg.RecordingEnabled {
+		return nil
+	}
+	if err := os.MkdirAll(m.cfg.RecordingDir, 0o750); err != nil {
+		m.logger.Warn("failed to create recording directory",
+			zap.String("dir", m.cfg.RecordingDir), zap.Error(err))
+		return nil
+	}
+	f, err := os.OpenFile(m.recordingPath(s.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		m.logger.Warn("failed to create session recording",
+			zap.String("session_id", s.ID), zap.Error(err))
+		return nil
+	}
+	rec, err := NewSessionRecorder(f, width, height, s.CreatedAt)
+	if err != nil {
+		f.Close()
+		m.logger.Warn("failed to start session recording",
+			zap.String("session_id", s.ID), zap.Error(err))
+		return nil
+	}
+	m.auditRecording(ctx, s, "recording_started")
+	return rec
+}
+
+// stopRecording closes rec, if any, and audits the end of the recording.
+func (m *Module) stopRecording(ctx context.Context, s *Session, rec *SessionRecorder) {
+	if rec == nil {
+		return
+	}
+	if err := rec.Close(); err != nil {
+		m.logger.Warn("failed to close session recording",
+			zap.String("session_id", s.ID), zap.Error(err))
+	}
+	m.auditRecording(ctx, s, "recording_stopped")
+}
+
+func (m *Module) auditRecording(ctx context.Context, s *Session, action string) {
+	if m.store == nil {
+		return
+	}
+	entry := &AuditEntry{
+		SessionID:   s.ID,
+		DeviceID:    s.DeviceID,
+		UserID:      s.UserID,
+		SessionType: string(s.SessionType),
+		Target:      fmt.Sprintf("%s:%d", s.Target.Host, s.Target.Port),
+		Action:      action,
+		BytesIn:     s.BytesInCount(),
+		BytesOut:    s.BytesOutCount(),
+		SourceIP:    s.SourceIP,
+		Timestamp:   time.Now().UTC(),
+	}
+	if err := m.store.InsertAuditEntry(ctx, entry); err != nil {
+		m.logger.Warn("failed to write recording audit entry", zap.Error(err))
+	}
+}
+
+// pruneRecordings deletes recordings last written before cutoff.
+func (m *Module) pruneRecordings(cutoff time.Time) (int, error) {
+	entries, err := os.ReadDir(m.cfg.RecordingDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("read recording directory: %w", err)
+	}
+	var deleted int
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), recordingExt) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(m.cfg.RecordingDir, e.Name())); err == nil {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// handleGetRecording downloads the asciinema recording of an SSH session.
+func (m *Module) handleGetRecording(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		gatewayWriteError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+	if filepath.Base(id) != id || strings.ContainsAny(id, `/\`) || strings.HasPrefix(id, ".") {
+		gatewayWriteError(w, http.StatusBadRequest, "invalid session id")
+		return
+	}
+
+	f, err := os.Open(m.recordingPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			gatewayWriteError(w, http.StatusNotFound, "recording not found")
+			return
+		}
+		m.logger.Error("failed to open session recording", zap.String("session_id", id), zap.Error(err))
+		gatewayWriteError(w, http.StatusInternalServerError, "failed to open recording")
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		gatewayWriteError(w, http.StatusInternalServerError, "failed to read recording")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-asciicast")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", id+recordingExt))
+	http.ServeContent(w, r, id+recordingExt, info.ModTime(), f)
+}
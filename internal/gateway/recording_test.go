@@ -0,0 +1,276 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// nopWriteCloser adds a Close that records it was called.
+type nopWriteCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (w *nopWriteCloser) Close() error {
+	w.closed = true
+	return nil
+}
+
+// readCast splits a cast file into its header and event lines.
+func readCast(t *testing.T, data []byte) (castHeader, [][]any) {
+	t.Helper()
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	if !sc.Scan() {
+		t.Fatal("cast is empty")
+	}
+	var header castHeader
+	if err := json.Unmarshal(sc.Bytes(), &header); err != nil {
+		t.Fatalf("decode header: %v", err)
+	}
+	var events [][]any
+	for sc.Scan() {
+		var ev []any
+		if err := json.Unmarshal(sc.Bytes(), &ev); err != nil {
+			t.Fatalf("decode event %q: %v", sc.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return header, events
+}
+
+func TestSessionRecorder(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var out nopWriteCloser
+	rec, err := NewSessionRecorder(&out, 80, 24, start)
+	if err != nil {
+		t.Fatalf("NewSessionRecorder: %v", err)
+	}
+	now := start
+	rec.now = func() time.Time { return now }
+
+	now = start.Add(500 * time.Millisecond)
+	if err := rec.RecordOutput([]byte("$ ")); err != nil {
+		t.Fatalf("RecordOutput: %v", err)
+	}
+	now = start.Add(time.Second)
+	if err := rec.RecordInput([]byte("ls\r")); err != nil {
+		t.Fatalf("RecordInput: %v", err)
+	}
+
+	// "é" (0xC3 0xA9) split across two reads must not be mangled.
+	now = start.Add(1500 * time.Millisecond)
+	if err := rec.RecordOutput([]byte("caf\xc3")); err != nil {
+		t.Fatalf("RecordOutput: %v", err)
+	}
+	now = start.Add(2 * time.Second)
+	if err := rec.RecordOutput([]byte("\xa9\r\n")); err != nil {
+		t.Fatalf("RecordOutput: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if !out.closed {
+		t.Error("underlying writer not closed")
+	}
+
+	header, events := readCast(t, out.Bytes())
+	if header.Version != 2 || header.Width != 80 || header.Height != 24 || header.Timestamp != start.Unix() {
+		t.Errorf("header = %+v", header)
+	}
+
+	want := [][]any{
+		{0.5, "o", "$ "},
+		{1.0, "i", "ls\r"},
+		{1.5, "o", "caf"},
+		{2.0, "o", "é\r\n"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %v, want %v", events, want)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if events[i][j] != want[i][j] {
+				t.Errorf("event %d = %v, want %v", i, events[i], want[i])
+				break
+			}
+		}
+	}
+}
+
+func TestSessionRecorder_FlushesPartialOnClose(t *testing.T) {
+	var out nopWriteCloser
+	rec, err := NewSessionRecorder(&out, 80, 24, time.Now())
+	if err != nil {
+		t.Fatalf("NewSessionRecorder: %v", err)
+	}
+	if err := rec.RecordOutput([]byte("\xe2\x82")); err != nil {
+		t.Fatalf("RecordOutput: %v", err)
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	_, events := readCast(t, out.Bytes())
+	if len(events) != 1 || events[0][1] != "o" {
+		t.Errorf("events = %v, want one output event", events)
+	}
+}
+
+func TestIncompleteUTF8Suffix(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"caf\xc3\xa9", 0},
+		{"caf\xc3", 1},
+		{"\xe2\x82", 2},
+		{"\xe2\x82\xac", 0},
+		{"\xf0\x9f\x98", 3},
+	}
+	for _, tt := range tests {
+		if got := incompleteUTF8Suffix([]byte(tt.in)); got != tt.want {
+			t.Errorf("incompleteUTF8Suffix(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStartRecording_Disabled(t *testing.T) {
+	m := newTestModule(t)
+	m.cfg.RecordingDir = t.TempDir()
+
+	s := &Session{ID: "gw-1", SessionType: SessionTypeSSH, CreatedAt: time.Now()}
+	if rec := m.startRecording(context.Background(), s, 80, 24); rec != nil {
+		t.Fatal("startRecording returned a recorder with recording disabled")
+	}
+	if _, err := os.Stat(m.recordingPath(s.ID)); !os.IsNotExist(err) {
+		t.Errorf("recording file created while disabled: %v", err)
+	}
+}
+
+func TestStartRecording_AuditsStartAndStop(t *testing.T) {
+	m := newTestModule(t)
+	m.cfg.RecordingEnabled = true
+	m.cfg.RecordingDir = filepath.Join(t.TempDir(), "recordings")
+	ctx := context.Background()
+
+	s := &Session{
+		ID:          "gw-2",
+		DeviceID:    "dev-1",
+		SessionType: SessionTypeSSH,
+		Target:      ProxyTarget{Host: "10.0.0.1", Port: 22},
+		CreatedAt:   time.Now(),
+	}
+	rec := m.startRecording(ctx, s, 80, 24)
+	if rec == nil {
+		t.Fatal("startRecording returned nil")
+	}
+	if err := rec.RecordOutput([]byte("hello")); err != nil {
+		t.Fatalf("RecordOutput: %v", err)
+	}
+	m.stopRecording(ctx, s, rec)
+
+	data, err := os.ReadFile(m.recordingPath(s.ID))
+	if err != nil {
+		t.Fatalf("read recording: %v", err)
+	}
+	if _, events := readCast(t, data); len(events) != 1 {
+		t.Errorf("events = %v, want 1", events)
+	}
+
+	entries, err := m.store.ListAuditEntries(ctx, "dev-1", 10)
+	if err != nil {
+		t.Fatalf("ListAuditEntries: %v", err)
+	}
+	actions := map[string]bool{}
+	for _, e := range entries {
+		actions[e.Action] = true
+	}
+	if !actions["recording_started"] || !actions["recording_stopped"] {
+		t.Errorf("audit actions = %v, want recording_started and recording_stopped", actions)
+	}
+}
+
+func TestHandleGetRecording(t *testing.T) {
+	m := newTestModule(t)
+	m.cfg.RecordingDir = t.TempDir()
+	cast := "{\"version\":2,\"width\":80,\"height\":24,\"timestamp\":0}\n[0.1,\"o\",\"hi\"]\n"
+	if err := os.WriteFile(m.recordingPath("gw-42"), []byte(cast), 0o600); err != nil {
+		t.Fatalf("write recording: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		id   string
+		want int
+	}{
+		{"found", "gw-42", http.StatusOK},
+		{"not found", "gw-missing", http.StatusNotFound},
+		{"traversal", "..", http.StatusBadRequest},
+		{"hidden", ".gw-42", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/sessions/x/recording", http.NoBody)
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+			m.handleGetRecording(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/x-asciicast" {
+				t.Errorf("Content-Type = %q", ct)
+			}
+			if cd := w.Header().Get("Content-Disposition"); !strings.Contains(cd, "gw-42.cast") {
+				t.Errorf("Content-Disposition = %q", cd)
+			}
+			if w.Body.String() != cast {
+				t.Errorf("body = %q, want %q", w.Body.String(), cast)
+			}
+		})
+	}
+}
+
+func TestPruneRecordings(t *testing.T) {
+	m := newTestModule(t)
+	m.cfg.RecordingDir = t.TempDir()
+
+	old := m.recordingPath("gw-old")
+	recent := m.recordingPath("gw-new")
+	for _, p := range []string{old, recent} {
+		if err := os.WriteFile(p, []byte("{}\n"), 0o600); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	past := time.Now().Add(-48 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	n, err := m.pruneRecordings(time.Now().Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("pruneRecordings: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("pruned = %d, want 1", n)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("old recording still exists")
+	}
+	if _, err := os.Stat(recent); err != nil {
+		t.Errorf("recent recording removed: %v", err)
+	}
+}
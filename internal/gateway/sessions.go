@@ -11,12 +11,14 @@ import (
 type SessionManager struct {
 	sessions    sync.Map
 	maxSessions int
+	now         func() time.Time
 }
 
 // NewSessionManager creates a new SessionManager with the given maximum session limit.
 func NewSessionManager(limit int) *SessionManager {
 	return &SessionManager{
 		maxSessions: limit,
+		now:         time.Now,
 	}
 }
 
@@ -66,7 +68,7 @@ func (sm *SessionManager) Count() int {
 
 // CloseExpired removes and returns all sessions that have exceeded their expiration time.
 func (sm *SessionManager) CloseExpired() []*Session {
-	now := time.Now()
+	now := sm.now()
 	var expired []*Session
 
 	sm.sessions.Range(func(key, value any) bool {
@@ -80,3 +82,33 @@ func (sm *SessionManager) CloseExpired() []*Session {
 
 	return expired
 }
+
+// CloseIdle removes and returns all sessions with no byte activity for
+// longer than idle. Activity is detected by comparing BytesIn+BytesOut with
+// the total seen on the previous call, so a session is idle once its
+// counters have not moved for the whole timeout. A non-positive idle
+// disables the check.
+func (sm *SessionManager) CloseIdle(idle time.Duration) []*Session {
+	if idle <= 0 {
+		return nil
+	}
+	now := sm.now()
+	var closed []*Session
+
+	sm.sessions.Range(func(key, value any) bool {
+		s := value.(*Session)
+		total := s.BytesInCount() + s.BytesOutCount()
+		if total != s.seenBytes.Load() {
+			s.seenBytes.Store(total)
+			s.lastActive.Store(now.UnixNano())
+			return true
+		}
+		if now.Sub(s.LastActive()) > idle {
+			sm.sessions.Delete(key)
+			closed = append(closed, s)
+		}
+		return true
+	})
+
+	return closed
+}
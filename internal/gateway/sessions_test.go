@@ -204,3 +204,62 @@ func TestSession_ToView(t *testing.T) {
 		t.Errorf("BytesOut = %d, want 200", v.BytesOut)
 	}
 }
+
+func TestSessionManager_CloseIdle(t *testing.T) {
+	sm := NewSessionManager(10)
+	clock := time.Now()
+	sm.now = func() time.Time { return clock }
+
+	idle := newTestSession("idle", "dev-1", clock.Add(time.Hour))
+	idle.CreatedAt = clock
+	active := newTestSession("active", "dev-2", clock.Add(time.Hour))
+	active.CreatedAt = clock
+	for _, s := range []*Session{idle, active} {
+		if err := sm.Create(s); err != nil {
+			t.Fatalf("Create(%s): %v", s.ID, err)
+		}
+	}
+
+	// Traffic on the active session every few minutes keeps it alive. The
+	// idle session stays within the timeout until the last tick (9 minutes).
+	for i := 1; i <= 3; i++ {
+		clock = clock.Add(3 * time.Minute)
+		active.BytesOut.Add(100)
+		if closed := sm.CloseIdle(10 * time.Minute); len(closed) != 0 {
+			t.Fatalf("tick %d: closed %d sessions early", i, len(closed))
+		}
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	closed := sm.CloseIdle(10 * time.Minute)
+	if len(closed) != 1 || closed[0].ID != "idle" {
+		t.Fatalf("CloseIdle() = %v, want only the idle session", closed)
+	}
+	if _, ok := sm.Get("idle"); ok {
+		t.Error("idle session still in manager")
+	}
+	if _, ok := sm.Get("active"); !ok {
+		t.Error("active session was reaped")
+	}
+
+	// Once traffic stops, the active session goes idle too.
+	clock = clock.Add(11 * time.Minute)
+	if closed := sm.CloseIdle(10 * time.Minute); len(closed) != 1 || closed[0].ID != "active" {
+		t.Errorf("CloseIdle() = %v, want the formerly active session", closed)
+	}
+}
+
+func TestSessionManager_CloseIdle_Disabled(t *testing.T) {
+	sm := NewSessionManager(10)
+	s := newTestSession("s1", "dev-1", time.Now().Add(time.Hour))
+	s.CreatedAt = time.Now().Add(-24 * time.Hour)
+	if err := sm.Create(s); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if closed := sm.CloseIdle(0); closed != nil {
+		t.Errorf("CloseIdle(0) = %v, want nil", closed)
+	}
+	if sm.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", sm.Count())
+	}
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -75,6 +76,17 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Optional per-session rate limit in bytes per second (0 = unlimited).
+	var rateLimit int64
+	if v := r.URL.Query().Get("rate_limit"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			http.Error(w, "rate_limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		rateLimit = n
+	}
+
 	// 4. Resolve device IP via module's deviceLookup, or accept ?host= as fallback.
 	host := r.URL.Query().Get("host")
 	if b.module.deviceLookup != nil {
@@ -191,7 +203,11 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// 10. Create a gateway session.
+	// 10. Create a gateway session. Cancelling ctx ends the bridge, which
+	// lets the idle reaper and DELETE /sessions/{id} close it.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	gwSession := &Session{
 		ID:          generateSessionID(),
 		DeviceID:    deviceID,
@@ -201,6 +217,9 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 		SourceIP:    r.RemoteAddr,
 		CreatedAt:   time.Now().UTC(),
 		ExpiresAt:   time.Now().UTC().Add(b.module.cfg.SessionTimeout),
+		RateLimit:   rateLimit,
+		limiter:     newByteLimiter(rateLimit),
+		cancel:      cancel,
 	}
 
 	if err := b.module.sessions.Create(gwSession); err != nil {
@@ -234,7 +253,10 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 		"target":       fmt.Sprintf("%s:%d", host, port),
 	})
 
-	// 12. Bidirectional copy between WebSocket and SSH.
+	// 12. Start recording if enabled. Recording failures never block the session.
+	rec := b.module.startRecording(ctx, gwSession, 80, 24)
+
+	// 13. Bidirectional copy between WebSocket and SSH.
 	done := make(chan struct{}, 2)
 
 	// WS -> SSH stdin
@@ -246,6 +268,14 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			gwSession.BytesIn.Add(int64(len(data)))
+			if err := gwSession.limiter.WaitN(ctx, len(data)); err != nil {
+				return
+			}
+			if rec != nil {
+				if err := rec.RecordInput(data); err != nil {
+					b.logger.Debug("session recording input failed", zap.Error(err))
+				}
+			}
 			if _, err := stdin.Write(data); err != nil {
 				return
 			}
@@ -260,6 +290,14 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 			n, err := stdout.Read(buf)
 			if n > 0 {
 				gwSession.BytesOut.Add(int64(n))
+				if wErr := gwSession.limiter.WaitN(ctx, n); wErr != nil {
+					return
+				}
+				if rec != nil {
+					if rErr := rec.RecordOutput(buf[:n]); rErr != nil {
+						b.logger.Debug("session recording output failed", zap.Error(rErr))
+					}
+				}
 				if wErr := conn.Write(ctx, websocket.MessageBinary, buf[:n]); wErr != nil {
 					return
 				}
@@ -280,7 +318,10 @@ func (b *SSHBridge) HandleSSH(w http.ResponseWriter, r *http.Request) {
 	client.Close()
 	conn.Close(websocket.StatusNormalClosure, "session ended")
 
-	// Remove session and audit.
-	b.module.sessions.Delete(gwSession.ID)
-	b.module.logSessionClosed(gwSession, "disconnected")
+	// Remove session and audit. The request context may already be done.
+	// A session already removed by the reaper or an API call was audited there.
+	b.module.stopRecording(context.WithoutCancel(ctx), gwSession, rec)
+	if b.module.sessions.Delete(gwSession.ID) {
+		b.module.logSessionClosed(gwSession, "disconnected")
+	}
 }
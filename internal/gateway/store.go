@@ -32,39 +32,99 @@ func (s *GatewayStore) InsertAuditEntry(ctx context.Context, entry *AuditEntry)
 	return nil
 }
 
+// AuditQuery filters audit log queries. Zero values leave a field
+// unfiltered.
+type AuditQuery struct {
+	DeviceID string
+	From     time.Time // inclusive
+	To       time.Time // exclusive
+	Limit    int       // 0 returns all matching entries
+	Offset   int
+}
+
 // ListAuditEntries returns audit entries, optionally filtered by device ID.
 // Pass empty deviceID to list all entries.
 func (s *GatewayStore) ListAuditEntries(ctx context.Context, deviceID string, limit int) ([]AuditEntry, error) {
-	var query string
-	var args []any
+	return s.QueryAuditEntries(ctx, AuditQuery{DeviceID: deviceID, Limit: limit})
+}
+
+// QueryAuditEntries returns audit entries matching q, newest first.
+func (s *GatewayStore) QueryAuditEntries(ctx context.Context, q AuditQuery) ([]AuditEntry, error) {
+	var entries []AuditEntry
+	err := s.EachAuditEntry(ctx, q, func(e *AuditEntry) error {
+		entries = append(entries, *e)
+		return nil
+	})
+	return entries, err
+}
 
-	if deviceID != "" {
-		query = `SELECT id, session_id, device_id, user_id, session_type, target, action, bytes_in, bytes_out, source_ip, timestamp
-			FROM gateway_audit_log WHERE device_id = ? ORDER BY timestamp DESC LIMIT ?`
-		args = []any{deviceID, limit}
-	} else {
-		query = `SELECT id, session_id, device_id, user_id, session_type, target, action, bytes_in, bytes_out, source_ip, timestamp
-			FROM gateway_audit_log ORDER BY timestamp DESC LIMIT ?`
-		args = []any{limit}
+// EachAuditEntry calls fn for each audit entry matching q, newest first,
+// without loading the whole result into memory. Iteration stops at the
+// first error returned by fn.
+func (s *GatewayStore) EachAuditEntry(ctx context.Context, q AuditQuery, fn func(*AuditEntry) error) error {
+	where, args := auditFilterClause(q)
+	query := `SELECT id, session_id, device_id, user_id, session_type, target, action, bytes_in, bytes_out, source_ip, timestamp
+		FROM gateway_audit_log` + where + ` ORDER BY timestamp DESC`
+	if q.Limit > 0 || q.Offset > 0 {
+		// SQLite requires a LIMIT before OFFSET; -1 means no limit.
+		limit := q.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, q.Offset)
 	}
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("list gateway audit entries: %w", err)
+		return fmt.Errorf("list gateway audit entries: %w", err)
 	}
 	defer rows.Close()
 
-	var entries []AuditEntry
 	for rows.Next() {
 		var e AuditEntry
 		if err := rows.Scan(&e.ID, &e.SessionID, &e.DeviceID, &e.UserID,
 			&e.SessionType, &e.Target, &e.Action, &e.BytesIn, &e.BytesOut,
 			&e.SourceIP, &e.Timestamp); err != nil {
-			return nil, fmt.Errorf("scan gateway audit row: %w", err)
+			return fmt.Errorf("scan gateway audit row: %w", err)
+		}
+		if err := fn(&e); err != nil {
+			return err
 		}
-		entries = append(entries, e)
 	}
-	return entries, rows.Err()
+	return rows.Err()
+}
+
+// CountAuditEntries returns the number of audit entries matching q,
+// ignoring Limit and Offset.
+func (s *GatewayStore) CountAuditEntries(ctx context.Context, q AuditQuery) (int, error) {
+	where, args := auditFilterClause(q)
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM gateway_audit_log`+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count gateway audit entries: %w", err)
+	}
+	return n, nil
+}
+
+// auditFilterClause builds the WHERE clause, with a leading space, for
+// the filters in q.
+func auditFilterClause(q AuditQuery) (string, []any) {
+	where := ` WHERE 1=1`
+	var args []any
+
+	if q.DeviceID != "" {
+		where += ` AND device_id = ?`
+		args = append(args, q.DeviceID)
+	}
+	if !q.From.IsZero() {
+		where += ` AND timestamp >= ?`
+		args = append(args, q.From.UTC())
+	}
+	if !q.To.IsZero() {
+		where += ` AND timestamp < ?`
+		args = append(args, q.To.UTC())
+	}
+	return where, args
 }
 
 // DeleteOldAuditEntries deletes audit entries older than the given time.
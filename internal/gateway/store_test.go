@@ -2,6 +2,8 @@ package gateway
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -198,3 +200,52 @@ func TestDeleteOldAuditEntries_NoneToDelete(t *testing.T) {
 		t.Errorf("deleted = %d, want 0", deleted)
 	}
 }
+
+func TestQueryAuditEntries_TimeRange(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, ts := range []time.Time{
+		base.Add(-time.Second),                  // just before the range
+		base,                                    // first instant of the range
+		base.Add(45 * 24 * time.Hour),           // mid-range
+		base.Add(92*24*time.Hour - time.Second), // last second of the range
+		base.Add(92 * 24 * time.Hour),           // end of range (exclusive)
+	} {
+		if err := s.InsertAuditEntry(ctx, &AuditEntry{
+			SessionID: fmt.Sprintf("s%d", i), DeviceID: "dev-1", SessionType: "ssh",
+			Target: "192.168.1.2:22", Action: "created", Timestamp: ts,
+		}); err != nil {
+			t.Fatalf("InsertAuditEntry: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    AuditQuery
+		want []string
+	}{
+		{"quarter", AuditQuery{From: base, To: base.Add(92 * 24 * time.Hour)}, []string{"s3", "s2", "s1"}},
+		{"from only", AuditQuery{From: base.Add(45 * 24 * time.Hour)}, []string{"s4", "s3", "s2"}},
+		{"to only", AuditQuery{To: base}, []string{"s0"}},
+		{"non-UTC bounds", AuditQuery{From: base.In(time.FixedZone("EST", -5*3600)), To: base.Add(time.Second)}, []string{"s1"}},
+		{"limit", AuditQuery{From: base, Limit: 2}, []string{"s4", "s3"}},
+		{"other device", AuditQuery{DeviceID: "dev-2", From: base}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := s.QueryAuditEntries(ctx, tt.q)
+			if err != nil {
+				t.Fatalf("QueryAuditEntries() error = %v", err)
+			}
+			var got []string
+			for i := range entries {
+				got = append(got, entries[i].SessionID)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sessions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package gateway
 
 import (
+	"context"
 	"sync/atomic"
 	"time"
 )
@@ -25,9 +26,23 @@ type Session struct {
 	CreatedAt   time.Time   `json:"created_at"`
 	ExpiresAt   time.Time   `json:"expires_at"`
 
+	// RateLimit caps the session's combined inbound and outbound traffic in
+	// bytes per second. Zero means unlimited.
+	RateLimit int64 `json:"rate_limit"`
+	limiter   *byteLimiter
+
 	// Thread-safe byte counters updated by proxy goroutines.
 	BytesIn  atomic.Int64 `json:"-"`
 	BytesOut atomic.Int64 `json:"-"`
+
+	// Idle tracking maintained by SessionManager.CloseIdle: the byte total
+	// last observed and when it last changed (unix nanoseconds).
+	seenBytes  atomic.Int64
+	lastActive atomic.Int64
+
+	// cancel tears down the live connection of a bridged session (SSH).
+	// Nil for sessions without one, such as HTTP proxies.
+	cancel context.CancelFunc
 }
 
 // BytesInCount returns the current inbound byte count.
@@ -40,6 +55,31 @@ func (s *Session) BytesOutCount() int64 {
 	return s.BytesOut.Load()
 }
 
+// ByteRate returns the average combined transfer rate in bytes per second
+// since the session was created.
+func (s *Session) ByteRate() int64 {
+	elapsed := time.Since(s.CreatedAt).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(s.BytesInCount()+s.BytesOutCount()) / elapsed)
+}
+
+// LastActive returns when byte activity was last observed on the session.
+func (s *Session) LastActive() time.Time {
+	if n := s.lastActive.Load(); n != 0 {
+		return time.Unix(0, n)
+	}
+	return s.CreatedAt
+}
+
+// close tears down the session's live connection, if it has one.
+func (s *Session) close() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+}
+
 // ProxyTarget describes the target host and port for a session.
 type ProxyTarget struct {
 	Host string `json:"host"`
@@ -72,8 +112,11 @@ type sessionView struct {
 	SourceIP    string      `json:"source_ip"`
 	CreatedAt   time.Time   `json:"created_at"`
 	ExpiresAt   time.Time   `json:"expires_at"`
+	LastActive  time.Time   `json:"last_active"`
 	BytesIn     int64       `json:"bytes_in"`
 	BytesOut    int64       `json:"bytes_out"`
+	RateLimit   int64       `json:"rate_limit"`
+	ByteRate    int64       `json:"byte_rate"`
 }
 
 // toView converts a Session to its JSON-serializable form.
@@ -87,7 +130,10 @@ func (s *Session) toView() sessionView {
 		SourceIP:    s.SourceIP,
 		CreatedAt:   s.CreatedAt,
 		ExpiresAt:   s.ExpiresAt,
+		LastActive:  s.LastActive(),
 		BytesIn:     s.BytesInCount(),
 		BytesOut:    s.BytesOutCount(),
+		RateLimit:   s.RateLimit,
+		ByteRate:    s.ByteRate(),
 	}
 }
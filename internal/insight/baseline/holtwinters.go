@@ -84,6 +84,40 @@ func (hw *HoltWinters) initialize() {
 	}
 }
 
+// InitializeFromSeasons sets the level, trend, and seasonal components
+// from the first two seasons of values, which captures an existing trend
+// immediately rather than learning it slowly from a zero start. The two
+// seasons count as processed samples; any further values are ignored.
+// Returns false, leaving the model unchanged, if fewer than two seasons are
+// given or the model has already seen data.
+func (hw *HoltWinters) InitializeFromSeasons(values []float64) bool {
+	m := hw.SeasonLen
+	if len(values) < 2*m || hw.Samples > 0 {
+		return false
+	}
+
+	var mean1, mean2 float64
+	for i := range m {
+		mean1 += values[i]
+		mean2 += values[m+i]
+	}
+	mean1 /= float64(m)
+	mean2 /= float64(m)
+
+	// Season means sit mid-season; detrend each point around them.
+	trend := (mean2 - mean1) / float64(m)
+	mid := float64(m-1) / 2
+	for i := range m {
+		offset := (float64(i) - mid) * trend
+		hw.Seasonal[i] = ((values[i] - mean1 - offset) + (values[m+i] - mean2 - offset)) / 2
+	}
+	hw.Level = mean2 + mid*trend
+	hw.Trend = trend
+	hw.Samples = 2 * m
+	hw.initialized = true
+	return true
+}
+
 // Predict returns the forecasted value for stepsAhead into the future.
 func (hw *HoltWinters) Predict(stepsAhead int) float64 {
 	if !hw.initialized {
@@ -148,6 +182,12 @@ func (hw *HoltWinters) IsInitialized() bool {
 	return hw.initialized
 }
 
+// ConfidenceZ returns the two-tailed Gaussian z-score for a confidence
+// level (0-1), e.g. 1.96 for 0.95. Out-of-range levels default to 95%.
+func ConfidenceZ(confidence float64) float64 {
+	return confidenceToZ(confidence)
+}
+
 // confidenceToZ converts a confidence level (0-1) to the corresponding
 // z-score for a two-tailed Gaussian distribution.
 // Uses rational approximation of the inverse normal CDF (Abramowitz & Stegun 26.2.23).
@@ -435,3 +435,36 @@ func BenchmarkHoltWinters_ExpectedRange(b *testing.B) {
 		hw.ExpectedRange(0.95)
 	}
 }
+
+func TestHoltWinters_InitializeFromSeasons(t *testing.T) {
+	// Two seasons of a line with slope 1 plus a repeating +/-5 pattern.
+	pattern := []float64{5, -5, 5, -5}
+	values := make([]float64, 8)
+	for i := range values {
+		values[i] = float64(i) + pattern[i%4]
+	}
+
+	hw := NewHoltWinters(0.3, 0.1, 0.3, 4)
+	if !hw.InitializeFromSeasons(values) {
+		t.Fatal("InitializeFromSeasons returned false")
+	}
+	if math.Abs(hw.Trend-1) > epsilon {
+		t.Errorf("Trend = %v, want 1", hw.Trend)
+	}
+	for i, want := range pattern {
+		if math.Abs(hw.Seasonal[i]-want) > epsilon {
+			t.Errorf("Seasonal[%d] = %v, want %v", i, hw.Seasonal[i], want)
+		}
+	}
+	// The next point continues the line exactly.
+	if got := hw.Predict(1); math.Abs(got-(8+pattern[0])) > epsilon {
+		t.Errorf("Predict(1) = %v, want %v", got, 8+pattern[0])
+	}
+
+	if hw.InitializeFromSeasons(values) {
+		t.Error("re-initializing a model with data should fail")
+	}
+	if NewHoltWinters(0.3, 0.1, 0.3, 4).InitializeFromSeasons(values[:7]) {
+		t.Error("initializing from less than two seasons should fail")
+	}
+}
@@ -0,0 +1,106 @@
+package baseline
+
+import (
+	"math"
+	"time"
+)
+
+// Bucket counts for the seasonal model.
+const (
+	HourlyBuckets = 24     // One bucket per hour of day
+	WeeklyBuckets = 7 * 24 // One bucket per hour of week
+)
+
+// SeasonalBucket holds the mean and variance of values seen in one
+// time-of-day (or time-of-week) slot.
+type SeasonalBucket struct {
+	Mean    float64 `json:"mean"`
+	Var     float64 `json:"variance"`
+	Samples int     `json:"samples"`
+}
+
+// StdDev returns the bucket's standard deviation.
+func (b *SeasonalBucket) StdDev() float64 {
+	if b.Samples < 2 {
+		return 0
+	}
+	return math.Sqrt(b.Var)
+}
+
+// Seasonal tracks separate mean/variance statistics per hour of day, or per
+// hour of week when Weekly is set, so that recurring patterns such as a
+// nightly backup are scored against their own time slot instead of the
+// global mean.
+//
+// Each bucket is an EWMA whose smoothing factor starts at 1/n, giving the
+// exact mean and variance while a bucket is young, and settles at Alpha.
+// Buckets use the clock of the timestamps they are given.
+type Seasonal struct {
+	Alpha   float64          // Smoothing factor once a bucket has 1/Alpha samples
+	Weekly  bool             // Bucket by hour of week instead of hour of day
+	Buckets []SeasonalBucket // HourlyBuckets or WeeklyBuckets entries
+}
+
+// NewSeasonal creates a seasonal model with the given smoothing factor.
+func NewSeasonal(alpha float64, weekly bool) *Seasonal {
+	if alpha <= 0 || alpha > 1 {
+		alpha = 0.01
+	}
+	n := HourlyBuckets
+	if weekly {
+		n = WeeklyBuckets
+	}
+	return &Seasonal{
+		Alpha:   alpha,
+		Weekly:  weekly,
+		Buckets: make([]SeasonalBucket, n),
+	}
+}
+
+// BucketIndex returns the index of the bucket that t falls into.
+func (s *Seasonal) BucketIndex(t time.Time) int {
+	if s.Weekly {
+		return int(t.Weekday())*24 + t.Hour()
+	}
+	return t.Hour()
+}
+
+// Update adds a value observed at t to its bucket.
+func (s *Seasonal) Update(t time.Time, value float64) {
+	b := &s.Buckets[s.BucketIndex(t)]
+	b.Samples++
+	if b.Samples == 1 {
+		b.Mean = value
+		b.Var = 0
+		return
+	}
+	alpha := math.Max(s.Alpha, 1/float64(b.Samples))
+	diff := value - b.Mean
+	b.Mean += alpha * diff
+	b.Var = (1 - alpha) * (b.Var + alpha*diff*diff)
+}
+
+// Expected returns the statistics of the bucket t falls into.
+func (s *Seasonal) Expected(t time.Time) SeasonalBucket {
+	return s.Buckets[s.BucketIndex(t)]
+}
+
+// Summary returns the average mean and standard deviation across buckets
+// that have seen data, and the total number of samples.
+func (s *Seasonal) Summary() (mean, stdDev float64, samples int) {
+	populated := 0
+	for i := range s.Buckets {
+		b := &s.Buckets[i]
+		if b.Samples == 0 {
+			continue
+		}
+		populated++
+		mean += b.Mean
+		stdDev += b.StdDev()
+		samples += b.Samples
+	}
+	if populated == 0 {
+		return 0, 0, 0
+	}
+	return mean / float64(populated), stdDev / float64(populated), samples
+}
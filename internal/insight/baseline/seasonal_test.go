@@ -0,0 +1,97 @@
+package baseline
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// nightlySpikeSeries feeds days of 10-minute samples into update: a CPU
+// metric idling around 20% with a backup pushing it to ~90% from 02:00 to
+// 02:59 every night. Returns the time just after the last sample.
+func nightlySpikeSeries(days int, update func(t time.Time, v float64)) time.Time {
+	t := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC) // A Monday
+	for i := 0; i < days*24*6; i++ {
+		noise := float64(i%5) - 2 // Deterministic -2..2
+		v := 20 + noise
+		if t.Hour() == 2 {
+			v = 90 + noise
+		}
+		update(t, v)
+		t = t.Add(10 * time.Minute)
+	}
+	return t
+}
+
+func zScore(value float64, b SeasonalBucket) float64 {
+	if b.StdDev() == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(value-b.Mean) / b.StdDev()
+}
+
+func TestSeasonal_NightlySpike(t *testing.T) {
+	const threshold = 3.0
+	s := NewSeasonal(0.01, false)
+	global := NewEWMA(0.1)
+	end := nightlySpikeSeries(14, func(t time.Time, v float64) {
+		s.Update(t, v)
+		global.Update(v)
+	})
+
+	nextNight := end.Add(2*time.Hour + 30*time.Minute)
+	if nextNight.Hour() != 2 {
+		t.Fatalf("test setup: next night sample at %v, want 02:30", nextNight)
+	}
+	if z := zScore(91, s.Expected(nextNight)); z >= threshold {
+		t.Errorf("scheduled spike z-score = %.2f, want < %.1f (bucket %+v)", z, threshold, s.Expected(nextNight))
+	}
+
+	// The global EWMA has no notion of time of day and flags every backup.
+	if z := math.Abs(91-global.Mean) / global.StdDev(); z < threshold {
+		t.Errorf("global EWMA z-score = %.2f, expected it to flag the spike", z)
+	}
+
+	afternoon := end.Add(14*time.Hour + 30*time.Minute)
+	if z := zScore(90, s.Expected(afternoon)); z < threshold {
+		t.Errorf("off-schedule spike z-score = %.2f, want >= %.1f (bucket %+v)", z, threshold, s.Expected(afternoon))
+	}
+}
+
+func TestSeasonal_BucketStats(t *testing.T) {
+	s := NewSeasonal(0.01, false)
+	at := time.Date(2026, 3, 2, 9, 15, 0, 0, time.UTC)
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		s.Update(at, v)
+	}
+
+	// While a bucket has fewer than 1/alpha samples its stats are exact.
+	b := s.Expected(at.Add(30 * time.Minute))
+	if b.Samples != 8 || math.Abs(b.Mean-5) > epsilon || math.Abs(b.StdDev()-2) > epsilon {
+		t.Errorf("bucket = %+v (stddev %.4f), want mean 5, stddev 2 over 8 samples", b, b.StdDev())
+	}
+	if other := s.Expected(at.Add(time.Hour)); other.Samples != 0 {
+		t.Errorf("neighbouring bucket has %d samples, want 0", other.Samples)
+	}
+
+	mean, stdDev, samples := s.Summary()
+	if math.Abs(mean-5) > epsilon || math.Abs(stdDev-2) > epsilon || samples != 8 {
+		t.Errorf("Summary() = %.2f, %.2f, %d", mean, stdDev, samples)
+	}
+}
+
+func TestSeasonal_WeeklyBuckets(t *testing.T) {
+	s := NewSeasonal(0.01, true)
+	if len(s.Buckets) != WeeklyBuckets {
+		t.Fatalf("len(Buckets) = %d, want %d", len(s.Buckets), WeeklyBuckets)
+	}
+
+	monday := time.Date(2026, 3, 2, 2, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	if s.BucketIndex(monday) == s.BucketIndex(tuesday) {
+		t.Error("same hour on different weekdays share a bucket")
+	}
+	if s.BucketIndex(monday) != s.BucketIndex(monday.AddDate(0, 0, 7)) {
+		t.Error("same hour one week apart use different buckets")
+	}
+}
@@ -12,6 +12,7 @@ type baselineState struct {
 	EWMA        *baseline.EWMA
 	HoltWinters *baseline.HoltWinters
 	CUSUM       *anomaly.CUSUM
+	Seasonal    *baseline.Seasonal // nil unless the seasonal algorithm is enabled
 }
 
 // stateKey returns a map key for a device+metric pair.
@@ -32,6 +33,11 @@ type stateManager struct {
 	hwBeta     float64
 	hwGamma    float64
 	hwSeasonLen int
+
+	// Seasonal baseline parameters; seasonal is false when disabled.
+	seasonal       bool
+	seasonalAlpha  float64
+	seasonalWeekly bool
 }
 
 // newStateManager creates a new state manager with the given EWMA alpha, CUSUM parameters,
@@ -49,6 +55,16 @@ func newStateManager(alpha, cusumDrift, cusumThreshold, hwAlpha, hwBeta, hwGamma
 	}
 }
 
+// enableSeasonal makes new states track a seasonal baseline with the given
+// per-bucket smoothing factor.
+func (sm *stateManager) enableSeasonal(alpha float64, weekly bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.seasonal = true
+	sm.seasonalAlpha = alpha
+	sm.seasonalWeekly = weekly
+}
+
 // getOrCreate returns the state for a device+metric pair, creating it if needed.
 func (sm *stateManager) getOrCreate(deviceID, metric string) *baselineState {
 	key := stateKey(deviceID, metric)
@@ -70,6 +86,9 @@ func (sm *stateManager) getOrCreate(deviceID, metric string) *baselineState {
 		HoltWinters: baseline.NewHoltWinters(sm.hwAlpha, sm.hwBeta, sm.hwGamma, sm.hwSeasonLen),
 		CUSUM:       anomaly.NewCUSUM(sm.drift, sm.threshold),
 	}
+	if sm.seasonal {
+		s.Seasonal = baseline.NewSeasonal(sm.seasonalAlpha, sm.seasonalWeekly)
+	}
 	sm.states[key] = s
 	return s
 }
@@ -20,8 +20,41 @@ type InsightConfig struct {
 	HWGamma     float64 `mapstructure:"hw_gamma"`      // Seasonal smoothing (0-1)
 	HWSeasonLen int     `mapstructure:"hw_season_len"`  // Points per season (24=daily, 168=weekly)
 	HWConfidence float64 `mapstructure:"hw_confidence"` // Confidence level for expected range (0-1)
+
+	// BaselineAlgorithm selects how point anomalies are scored: "ewma" scores
+	// against a single global baseline; "seasonal" scores against per-hour
+	// buckets so recurring spikes (nightly backups) are not flagged.
+	BaselineAlgorithm  string  `mapstructure:"baseline_algorithm"`
+	SeasonalAlpha      float64 `mapstructure:"seasonal_alpha"`       // Per-bucket smoothing (0-1)
+	SeasonalWeekly     bool    `mapstructure:"seasonal_weekly"`      // Bucket by hour of week instead of hour of day
+	SeasonalMinSamples int     `mapstructure:"seasonal_min_samples"` // Samples a bucket needs before it is used
+
+	// ForecastAlgorithm selects the capacity forecaster: "linear" regression,
+	// or "holt_winters" for metrics with trend and seasonality. Holt-Winters
+	// resamples metrics to ForecastStep and falls back to linear regression
+	// until it has more than two seasons of data.
+	ForecastAlgorithm string        `mapstructure:"forecast_algorithm"`
+	ForecastSeasonLen int           `mapstructure:"forecast_season_len"` // Steps per season (24=daily, 168=weekly at 1h)
+	ForecastStep      time.Duration `mapstructure:"forecast_step"`       // Resampling interval for Holt-Winters
+
+	// Natural language query intent cache. Repeated queries reuse the parsed
+	// intent instead of another LLM call. A size of 0 disables the cache.
+	NLIntentCacheSize int           `mapstructure:"nl_intent_cache_size"`
+	NLIntentCacheTTL  time.Duration `mapstructure:"nl_intent_cache_ttl"`
 }
 
+// Baseline algorithms accepted by InsightConfig.BaselineAlgorithm.
+const (
+	AlgorithmEWMA     = "ewma"
+	AlgorithmSeasonal = "seasonal"
+)
+
+// Forecast algorithms accepted by InsightConfig.ForecastAlgorithm.
+const (
+	ForecastLinear      = "linear"
+	ForecastHoltWinters = "holt_winters"
+)
+
 // DefaultConfig returns sensible defaults for the Insight module.
 func DefaultConfig() InsightConfig {
 	return InsightConfig{
@@ -40,5 +73,16 @@ func DefaultConfig() InsightConfig {
 		HWGamma:      0.3,
 		HWSeasonLen:  24,
 		HWConfidence: 0.95,
+
+		BaselineAlgorithm:  AlgorithmEWMA,
+		SeasonalAlpha:      0.01,
+		SeasonalMinSamples: 30,
+
+		ForecastAlgorithm: ForecastLinear,
+		ForecastSeasonLen: 24,
+		ForecastStep:      time.Hour,
+
+		NLIntentCacheSize: 128,
+		NLIntentCacheTTL:  10 * time.Minute,
 	}
 }
@@ -0,0 +1,110 @@
+package insight
+
+import (
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/llm"
+)
+
+// Conversation history limits. History is prepended to every intent-parsing
+// request, so it is kept small: a few recent exchanges, capped by size.
+const (
+	conversationMaxTurns    = 6                // User/assistant exchanges kept per session
+	conversationMaxChars    = 6000             // Roughly 1.5k tokens of history
+	conversationTTL         = 30 * time.Minute // Idle sessions are dropped after this
+	conversationMaxSessions = 256              // Oldest sessions are evicted beyond this
+)
+
+// conversation is the recent message history of one NL query session.
+type conversation struct {
+	messages []llm.Message
+	updated  time.Time
+}
+
+// conversationStore keeps bounded, in-memory NL query history keyed by
+// session ID. History is lost on restart, which is acceptable for
+// follow-up questions.
+type conversationStore struct {
+	mu       sync.Mutex
+	sessions map[string]*conversation
+	now      func() time.Time
+}
+
+// newConversationStore creates an empty conversation store.
+func newConversationStore() *conversationStore {
+	return &conversationStore{
+		sessions: make(map[string]*conversation),
+		now:      time.Now,
+	}
+}
+
+// history returns a copy of the messages recorded for a session, or nil if
+// the session is unknown or has expired.
+func (cs *conversationStore) history(sessionID string) []llm.Message {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	c, ok := cs.sessions[sessionID]
+	if !ok {
+		return nil
+	}
+	if cs.now().Sub(c.updated) > conversationTTL {
+		delete(cs.sessions, sessionID)
+		return nil
+	}
+	return append([]llm.Message(nil), c.messages...)
+}
+
+// record appends a user/assistant exchange to a session and trims the
+// history to the turn and size budgets.
+func (cs *conversationStore) record(sessionID, query, reply string) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	now := cs.now()
+	c, ok := cs.sessions[sessionID]
+	if !ok {
+		cs.evictLocked(now)
+		c = &conversation{}
+		cs.sessions[sessionID] = c
+	}
+	c.updated = now
+	c.messages = append(c.messages,
+		llm.Message{Role: llm.RoleUser, Content: query},
+		llm.Message{Role: llm.RoleAssistant, Content: reply},
+	)
+
+	// Drop whole exchanges from the front until both budgets are met.
+	for len(c.messages) > 2*conversationMaxTurns || (len(c.messages) > 2 && messageChars(c.messages) > conversationMaxChars) {
+		c.messages = c.messages[2:]
+	}
+}
+
+// evictLocked removes expired sessions and, if the store is still full,
+// the least recently updated one. Callers must hold cs.mu.
+func (cs *conversationStore) evictLocked(now time.Time) {
+	var oldestID string
+	var oldest time.Time
+	for id, c := range cs.sessions {
+		if now.Sub(c.updated) > conversationTTL {
+			delete(cs.sessions, id)
+			continue
+		}
+		if oldestID == "" || c.updated.Before(oldest) {
+			oldestID, oldest = id, c.updated
+		}
+	}
+	if len(cs.sessions) >= conversationMaxSessions {
+		delete(cs.sessions, oldestID)
+	}
+}
+
+// messageChars returns the total content length of messages.
+func messageChars(messages []llm.Message) int {
+	n := 0
+	for _, m := range messages {
+		n += len(m.Content)
+	}
+	return n
+}
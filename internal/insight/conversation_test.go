@@ -0,0 +1,75 @@
+package insight
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConversationStore_TrimsTurns(t *testing.T) {
+	cs := newConversationStore()
+	for i := range conversationMaxTurns + 3 {
+		cs.record("s", fmt.Sprintf("q%d", i), fmt.Sprintf("a%d", i))
+	}
+
+	got := cs.history("s")
+	if len(got) != 2*conversationMaxTurns {
+		t.Fatalf("history length = %d, want %d", len(got), 2*conversationMaxTurns)
+	}
+	if got[0].Content != "q3" || got[len(got)-1].Content != fmt.Sprintf("a%d", conversationMaxTurns+2) {
+		t.Errorf("history spans %q..%q, want the most recent turns", got[0].Content, got[len(got)-1].Content)
+	}
+}
+
+func TestConversationStore_TrimsSize(t *testing.T) {
+	cs := newConversationStore()
+	long := strings.Repeat("x", conversationMaxChars/2)
+	cs.record("s", "first", long)
+	cs.record("s", "second", long)
+
+	got := cs.history("s")
+	if len(got) != 2 || got[0].Content != "second" {
+		t.Errorf("history = %d messages starting %q, want only the latest turn", len(got), got[0].Content)
+	}
+
+	// A single oversized turn is still kept so the follow-up has context.
+	cs.record("big", "q", strings.Repeat("x", 2*conversationMaxChars))
+	if got := cs.history("big"); len(got) != 2 {
+		t.Errorf("oversized history length = %d, want 2", len(got))
+	}
+}
+
+func TestConversationStore_Expiry(t *testing.T) {
+	now := time.Now()
+	cs := newConversationStore()
+	cs.now = func() time.Time { return now }
+
+	cs.record("s", "q", "a")
+	now = now.Add(conversationTTL + time.Second)
+	if got := cs.history("s"); got != nil {
+		t.Errorf("expired history = %+v, want nil", got)
+	}
+}
+
+func TestConversationStore_EvictsOldestSession(t *testing.T) {
+	now := time.Now()
+	cs := newConversationStore()
+	cs.now = func() time.Time { return now }
+
+	for i := range conversationMaxSessions {
+		cs.record(fmt.Sprintf("s%d", i), "q", "a")
+		now = now.Add(time.Second)
+	}
+	cs.record("new", "q", "a")
+
+	if len(cs.sessions) != conversationMaxSessions {
+		t.Errorf("sessions = %d, want %d", len(cs.sessions), conversationMaxSessions)
+	}
+	if cs.history("s0") != nil {
+		t.Error("oldest session was not evicted")
+	}
+	if cs.history("new") == nil || cs.history("s1") == nil {
+		t.Error("recent sessions should be kept")
+	}
+}
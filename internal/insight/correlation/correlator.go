@@ -20,6 +20,9 @@ type Group struct {
 	DeviceIDs []string
 	Alerts    []Alert
 	RootCause string // Device ID with earliest alert in group
+	// RootCauseHint records how RootCause was chosen by
+	// CorrelateHierarchy; empty for Correlate.
+	RootCauseHint string
 }
 
 // Correlate groups alerts that are topologically connected and occur within the given time window.
@@ -0,0 +1,158 @@
+package correlation
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Root-cause hints explain how CorrelateHierarchy picked a group's root cause.
+const (
+	// HintAlertingAncestor means the root cause is alerting itself and is an
+	// ancestor of every other alerting device in the group.
+	HintAlertingAncestor = "alerting_ancestor"
+	// HintHighestLayer means the root cause is the alerting device highest
+	// in the network layer hierarchy among siblings sharing a parent.
+	HintHighestLayer = "highest_layer"
+	// HintCommonParent means the root cause is the parent shared by the
+	// alerting devices; it has no alert of its own, so it may be unmonitored.
+	HintCommonParent = "common_parent"
+)
+
+// DeviceNode places a device in the inferred network hierarchy.
+type DeviceNode struct {
+	ID       string
+	Name     string
+	ParentID string // Upstream device; empty at the top of the tree
+	Layer    int    // models.NetworkLayer*; 0 = unknown, 1 = gateway
+}
+
+// CorrelateHierarchy groups alerts whose devices sit under the same branch
+// of the network hierarchy and names the device most likely causing them.
+// An alerting device is attributed to its highest alerting ancestor;
+// branches whose tops share a parent are merged, and the root cause is then
+// the branch top highest in the layer hierarchy or, failing a clear winner,
+// the shared parent. Devices missing from devices are treated as roots.
+// Groups covering a single device are excluded.
+func CorrelateHierarchy(alerts []Alert, devices []DeviceNode) []Group {
+	if len(alerts) < 2 {
+		return nil
+	}
+
+	nodes := make(map[string]DeviceNode, len(devices))
+	for _, d := range devices {
+		nodes[d.ID] = d
+	}
+	alerting := make(map[string]bool, len(alerts))
+	for _, a := range alerts {
+		alerting[a.DeviceID] = true
+	}
+
+	// top returns the highest alerting device on id's path to the root.
+	top := func(id string) string {
+		best := id
+		seen := map[string]bool{id: true}
+		for cur := nodes[id].ParentID; cur != "" && !seen[cur]; cur = nodes[cur].ParentID {
+			seen[cur] = true
+			if alerting[cur] {
+				best = cur
+			}
+		}
+		return best
+	}
+
+	// Group by the parent of each branch top. No ancestor of a top is
+	// alerting, so that parent is either healthy or absent.
+	type branchGroup struct {
+		parent string
+		tops   map[string]bool
+		group  Group
+	}
+	byKey := make(map[string]*branchGroup)
+	var keys []string
+	for _, a := range alerts {
+		t := top(a.DeviceID)
+		key := nodes[t].ParentID
+		if key == "" {
+			key = t
+		}
+		bg, ok := byKey[key]
+		if !ok {
+			bg = &branchGroup{parent: nodes[t].ParentID, tops: make(map[string]bool)}
+			byKey[key] = bg
+			keys = append(keys, key)
+		}
+		bg.tops[t] = true
+		bg.group.Alerts = append(bg.group.Alerts, a)
+	}
+
+	var result []Group
+	for _, key := range keys {
+		bg := byKey[key]
+		deviceSet := make(map[string]bool)
+		for _, a := range bg.group.Alerts {
+			deviceSet[a.DeviceID] = true
+		}
+		if len(deviceSet) < 2 {
+			continue
+		}
+		g := bg.group
+		for d := range deviceSet {
+			g.DeviceIDs = append(g.DeviceIDs, d)
+		}
+		sort.Strings(g.DeviceIDs)
+		g.RootCause, g.RootCauseHint = pickRootCause(bg.tops, bg.parent, nodes)
+		result = append(result, g)
+	}
+	return result
+}
+
+// pickRootCause chooses the root cause among the branch tops of a group.
+func pickRootCause(tops map[string]bool, parent string, nodes map[string]DeviceNode) (string, string) {
+	ids := make([]string, 0, len(tops))
+	for id := range tops {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) == 1 {
+		return ids[0], HintAlertingAncestor
+	}
+
+	// A single top strictly higher in the layer hierarchy than the others.
+	best, bestLayer, tie := "", 0, false
+	for _, id := range ids {
+		layer := nodes[id].Layer
+		if layer == 0 {
+			continue
+		}
+		switch {
+		case bestLayer == 0 || layer < bestLayer:
+			best, bestLayer, tie = id, layer, false
+		case layer == bestLayer:
+			tie = true
+		}
+	}
+	if best != "" && !tie {
+		return best, HintHighestLayer
+	}
+	// Tops only share a group through a common parent.
+	return parent, HintCommonParent
+}
+
+// Describe summarizes a hierarchy group for display.
+func Describe(g Group, nodes []DeviceNode) string {
+	name := g.RootCause
+	for _, n := range nodes {
+		if n.ID == g.RootCause && n.Name != "" {
+			name = n.Name
+			break
+		}
+	}
+	switch g.RootCauseHint {
+	case HintCommonParent:
+		return fmt.Sprintf("%d alerts on %d devices share upstream device %s, which is not alerting; it is the probable root cause",
+			len(g.Alerts), len(g.DeviceIDs), name)
+	default:
+		return fmt.Sprintf("%d alerts on %d devices trace back to %s, the probable root cause",
+			len(g.Alerts), len(g.DeviceIDs), name)
+	}
+}
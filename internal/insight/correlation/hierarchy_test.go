@@ -0,0 +1,106 @@
+package correlation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// gatewayTopology is a gateway with a switch and two endpoints below it.
+var gatewayTopology = []DeviceNode{
+	{ID: "gw", Name: "gateway", Layer: 1},
+	{ID: "sw", Name: "core-switch", ParentID: "gw", Layer: 2},
+	{ID: "pc1", ParentID: "sw", Layer: 4},
+	{ID: "pc2", ParentID: "sw", Layer: 4},
+	{ID: "nas", ParentID: "gw", Layer: 4},
+}
+
+func TestCorrelateHierarchy_GatewayRootCause(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	alerts := []Alert{
+		{DeviceID: "pc1", Metric: "ping", Timestamp: now},
+		{DeviceID: "nas", Metric: "ping", Timestamp: now},
+		{DeviceID: "gw", Metric: "ping", Timestamp: now},
+		{DeviceID: "pc2", Metric: "ping", Timestamp: now},
+	}
+
+	groups := CorrelateHierarchy(alerts, gatewayTopology)
+
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.RootCause != "gw" {
+		t.Errorf("RootCause = %q, want gw", g.RootCause)
+	}
+	if g.RootCauseHint != HintAlertingAncestor {
+		t.Errorf("RootCauseHint = %q, want %q", g.RootCauseHint, HintAlertingAncestor)
+	}
+	if want := []string{"gw", "nas", "pc1", "pc2"}; !reflect.DeepEqual(g.DeviceIDs, want) {
+		t.Errorf("DeviceIDs = %v, want %v", g.DeviceIDs, want)
+	}
+	if len(g.Alerts) != 4 {
+		t.Errorf("Alerts = %d, want 4", len(g.Alerts))
+	}
+}
+
+func TestCorrelateHierarchy_HighestLayer(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	alerts := []Alert{
+		{DeviceID: "pc1", Metric: "ping", Timestamp: now},
+		{DeviceID: "sw", Metric: "ping", Timestamp: now},
+		{DeviceID: "nas", Metric: "ping", Timestamp: now},
+	}
+
+	groups := CorrelateHierarchy(alerts, gatewayTopology)
+
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].RootCause != "sw" || groups[0].RootCauseHint != HintHighestLayer {
+		t.Errorf("root cause = %q (%s), want sw (%s)", groups[0].RootCause, groups[0].RootCauseHint, HintHighestLayer)
+	}
+}
+
+func TestCorrelateHierarchy_CommonParent(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	alerts := []Alert{
+		{DeviceID: "pc1", Metric: "ping", Timestamp: now},
+		{DeviceID: "pc2", Metric: "ping", Timestamp: now},
+	}
+
+	groups := CorrelateHierarchy(alerts, gatewayTopology)
+
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %d", len(groups))
+	}
+	if groups[0].RootCause != "sw" || groups[0].RootCauseHint != HintCommonParent {
+		t.Errorf("root cause = %q (%s), want sw (%s)", groups[0].RootCause, groups[0].RootCauseHint, HintCommonParent)
+	}
+	if got := Describe(groups[0], gatewayTopology); got == "" {
+		t.Error("Describe returned an empty string")
+	}
+}
+
+func TestCorrelateHierarchy_UnrelatedDevices(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	alerts := []Alert{
+		{DeviceID: "pc1", Metric: "ping", Timestamp: now},
+		{DeviceID: "nas", Metric: "ping", Timestamp: now.Add(time.Minute)},
+		{DeviceID: "unknown", Metric: "ping", Timestamp: now},
+	}
+
+	// pc1 and nas sit in different branches under a healthy gateway and
+	// unknown has no topology, so every group covers a single device.
+	if groups := CorrelateHierarchy(alerts, gatewayTopology); len(groups) != 0 {
+		t.Errorf("expected no groups, got %+v", groups)
+	}
+}
@@ -0,0 +1,127 @@
+package forecast
+
+import (
+	"math"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/insight/baseline"
+)
+
+// HoltWintersParams configures a Holt-Winters forecast.
+type HoltWintersParams struct {
+	Alpha      float64 // Level smoothing (0-1)
+	Beta       float64 // Trend smoothing (0-1)
+	Gamma      float64 // Seasonal smoothing (0-1)
+	SeasonLen  int     // Steps per season
+	Horizon    int     // Steps to forecast
+	Confidence float64 // Confidence level for the bounds (0-1)
+}
+
+// HoltWintersResult contains a Holt-Winters forecast.
+type HoltWintersResult struct {
+	Predicted []float64 // Forecast for steps 1..Horizon
+	Lower     []float64 // Lower confidence bound per step
+	Upper     []float64 // Upper confidence bound per step
+	Trend     float64   // Current trend per step
+	Fitted    float64   // Model's fitted value for the last observation
+	RSquared  float64   // Share of variance explained by one-step-ahead fits (0-1)
+}
+
+// HoltWinters fits an additive Holt-Winters model to evenly spaced values
+// and forecasts params.Horizon steps ahead. The confidence bounds use the
+// standard h-step variance for additive Holt-Winters, so they widen with
+// the horizon. The first two seasons initialize the model, so nil is
+// returned unless there is data beyond them.
+func HoltWinters(values []float64, params HoltWintersParams) *HoltWintersResult {
+	if params.SeasonLen < 2 || len(values) <= 2*params.SeasonLen || params.Horizon <= 0 {
+		return nil
+	}
+
+	hw := baseline.NewHoltWinters(params.Alpha, params.Beta, params.Gamma, params.SeasonLen)
+	warmup := 2 * params.SeasonLen
+	hw.InitializeFromSeasons(values[:warmup])
+
+	// One-step-ahead residuals over everything after the warm-up seasons.
+	var sse, mean, sst float64
+	rest := values[warmup:]
+	for _, v := range rest {
+		residual := v - hw.Predict(1)
+		sse += residual * residual
+		hw.Update(v)
+	}
+	sigma2 := sse / float64(len(rest))
+
+	for _, v := range rest {
+		mean += v
+	}
+	mean /= float64(len(rest))
+	for _, v := range rest {
+		sst += (v - mean) * (v - mean)
+	}
+	rSquared := 1.0
+	if sst > 0 {
+		rSquared = math.Max(0, 1-sse/sst)
+	}
+
+	z := baseline.ConfidenceZ(params.Confidence)
+	result := &HoltWintersResult{
+		Predicted: hw.Forecast(params.Horizon),
+		Lower:     make([]float64, params.Horizon),
+		Upper:     make([]float64, params.Horizon),
+		Trend:     hw.Trend,
+		Fitted:    hw.Fitted(),
+		RSquared:  rSquared,
+	}
+
+	// var_h = sigma^2 * (1 + sum_{j=1}^{h-1} c_j^2), where
+	// c_j = alpha*(1 + j*beta) + gamma*[j is a multiple of the season].
+	sumC2 := 0.0
+	for h := 1; h <= params.Horizon; h++ {
+		if j := h - 1; j > 0 {
+			c := hw.Alpha * (1 + float64(j)*hw.Beta)
+			if j%params.SeasonLen == 0 {
+				c += hw.Gamma
+			}
+			sumC2 += c * c
+		}
+		margin := z * math.Sqrt(sigma2*(1+sumC2))
+		result.Lower[h-1] = result.Predicted[h-1] - margin
+		result.Upper[h-1] = result.Predicted[h-1] + margin
+	}
+
+	return result
+}
+
+// Resample averages irregular samples into consecutive buckets of width
+// step, starting at the first timestamp truncated to step. Empty buckets
+// carry the previous bucket's value forward. Returns the start of the first
+// bucket and the bucket values; timestamps must be in ascending order.
+func Resample(timestamps []time.Time, values []float64, step time.Duration) (time.Time, []float64) {
+	if len(timestamps) == 0 || len(values) != len(timestamps) || step <= 0 {
+		return time.Time{}, nil
+	}
+
+	start := timestamps[0].Truncate(step)
+	n := int(timestamps[len(timestamps)-1].Sub(start)/step) + 1
+	sums := make([]float64, n)
+	counts := make([]int, n)
+	for i, t := range timestamps {
+		idx := int(t.Sub(start) / step)
+		if idx < 0 || idx >= n {
+			continue
+		}
+		sums[idx] += values[i]
+		counts[idx]++
+	}
+
+	out := make([]float64, n)
+	for i := range out {
+		switch {
+		case counts[i] > 0:
+			out[i] = sums[i] / float64(counts[i])
+		case i > 0:
+			out[i] = out[i-1]
+		}
+	}
+	return start, out
+}
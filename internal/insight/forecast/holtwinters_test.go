@@ -0,0 +1,103 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// trendedSeasonal returns n hourly points of a series growing 0.5 per step
+// with a daily cycle of amplitude 10 and small deterministic noise.
+func trendedSeasonal(n int) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		noise := float64(i%3) - 1
+		values[i] = 50 + 0.5*float64(i) + 10*math.Sin(2*math.Pi*float64(i)/24) + noise
+	}
+	return values
+}
+
+func TestHoltWinters_TrendedSeasonal(t *testing.T) {
+	const train, horizon = 24 * 10, 48
+	series := trendedSeasonal(train + horizon)
+
+	result := HoltWinters(series[:train], HoltWintersParams{
+		Alpha: 0.3, Beta: 0.1, Gamma: 0.3, SeasonLen: 24, Horizon: horizon, Confidence: 0.95,
+	})
+	if result == nil {
+		t.Fatal("HoltWinters returned nil")
+	}
+	if len(result.Predicted) != horizon || len(result.Lower) != horizon || len(result.Upper) != horizon {
+		t.Fatalf("lengths = %d/%d/%d, want %d", len(result.Predicted), len(result.Lower), len(result.Upper), horizon)
+	}
+
+	// Mean absolute percentage error against the true continuation.
+	var mape float64
+	for h, want := range series[train:] {
+		mape += math.Abs(result.Predicted[h]-want) / want
+	}
+	mape /= horizon
+	if mape > 0.05 {
+		t.Errorf("MAPE = %.3f, want <= 0.05", mape)
+	}
+
+	if math.Abs(result.Trend-0.5) > 0.1 {
+		t.Errorf("Trend = %.3f, want ~0.5", result.Trend)
+	}
+	if result.RSquared < 0.9 {
+		t.Errorf("RSquared = %.3f, want >= 0.9", result.RSquared)
+	}
+
+	for h := range horizon {
+		if result.Lower[h] > result.Predicted[h] || result.Upper[h] < result.Predicted[h] {
+			t.Fatalf("step %d: bounds [%.2f, %.2f] do not contain %.2f", h+1, result.Lower[h], result.Upper[h], result.Predicted[h])
+		}
+		if h > 0 {
+			width := result.Upper[h] - result.Lower[h]
+			prev := result.Upper[h-1] - result.Lower[h-1]
+			if width < prev {
+				t.Fatalf("step %d: bound width %.3f narrower than step %d (%.3f)", h+1, width, h, prev)
+			}
+		}
+	}
+	first := result.Upper[0] - result.Lower[0]
+	last := result.Upper[horizon-1] - result.Lower[horizon-1]
+	if last <= first {
+		t.Errorf("bound width at horizon %.3f, want wider than first step %.3f", last, first)
+	}
+}
+
+func TestHoltWinters_InsufficientData(t *testing.T) {
+	params := HoltWintersParams{Alpha: 0.3, Beta: 0.1, Gamma: 0.3, SeasonLen: 24, Horizon: 10, Confidence: 0.95}
+	if r := HoltWinters(trendedSeasonal(48), params); r != nil {
+		t.Error("expected nil with no data beyond the two warm-up seasons")
+	}
+	params.Horizon = 0
+	if r := HoltWinters(trendedSeasonal(72), params); r != nil {
+		t.Error("expected nil with zero horizon")
+	}
+}
+
+func TestResample(t *testing.T) {
+	base := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	timestamps := []time.Time{
+		base.Add(5 * time.Minute),
+		base.Add(35 * time.Minute),
+		// 11:00 bucket is empty.
+		base.Add(2*time.Hour + 10*time.Minute),
+	}
+	start, got := Resample(timestamps, []float64{10, 20, 40}, time.Hour)
+
+	if !start.Equal(base) {
+		t.Errorf("start = %v, want %v", start, base)
+	}
+	want := []float64{15, 15, 40}
+	if len(got) != len(want) {
+		t.Fatalf("Resample = %v, want %v", got, want)
+	}
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 1e-9 {
+			t.Errorf("bucket %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -2,8 +2,10 @@ package insight
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/HerbHall/subnetree/pkg/analytics"
 	"github.com/HerbHall/subnetree/pkg/plugin"
@@ -20,6 +22,7 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "GET", Path: "/correlations/{device_id}", Handler: m.handleDeviceCorrelations},
 		{Method: "GET", Path: "/baselines/{device_id}", Handler: m.handleDeviceBaselines},
 		{Method: "POST", Path: "/query", Handler: m.handleNLQuery},
+		{Method: "POST", Path: "/query/stream", Handler: m.handleNLQueryStream, Class: plugin.RouteStream},
 		{Method: "GET", Path: "/recommendations", Handler: m.handleRecommendations},
 	}
 }
@@ -109,7 +112,8 @@ func (m *Module) handleDeviceForecasts(w http.ResponseWriter, r *http.Request) {
 // handleListCorrelations returns active alert correlation groups.
 //
 //	@Summary		List correlations
-//	@Description	Returns active alert correlation groups.
+//	@Description	Returns active alert correlation groups. Groups found from the network topology
+//	@Description	carry the probable root-cause device in root_cause and how it was chosen in root_cause_hint.
 //	@Tags			insight
 //	@Produce		json
 //	@Security		BearerAuth
@@ -214,6 +218,7 @@ func (m *Module) handleNLQuery(w http.ResponseWriter, r *http.Request) {
 			"natural language queries require the LLM plugin")
 		return
 	}
+	proc.withSession(m.conversations, req.SessionID).withIntentCache(m.intents)
 
 	resp, err := proc.Process(r.Context(), req.Query)
 	if err != nil {
@@ -228,6 +233,91 @@ func (m *Module) handleNLQuery(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, resp)
 }
 
+// handleNLQueryStream processes a natural language query and streams the
+// answer as Server-Sent Events.
+//
+// The stream emits a "result" event with the structured data, "chunk"
+// events ({"text": ...}) as the answer is generated, and a final "done"
+// event with the complete response. Failures after the stream has started
+// are reported as an "error" event.
+//
+//	@Summary		Streaming natural language query
+//	@Description	Like POST /insight/query, but streams the answer as Server-Sent Events: a result event with the structured data, chunk events as the LLM generates the answer, then a done event with the full response.
+//	@Tags			insight
+//	@Accept			json
+//	@Produce		text/event-stream
+//	@Security		BearerAuth
+//	@Param			request body analytics.NLQueryRequest true "Query"
+//	@Success		200 {string} string "Event stream"
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Failure		503 {object} map[string]any
+//	@Router			/insight/query/stream [post]
+func (m *Module) handleNLQueryStream(w http.ResponseWriter, r *http.Request) {
+	var req analytics.NLQueryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Query == "" {
+		writeError(w, http.StatusBadRequest, "query is required")
+		return
+	}
+
+	proc := newNLQueryProcessor(m.plugins, m.store)
+	if proc == nil {
+		writeError(w, http.StatusServiceUnavailable,
+			"natural language queries require the LLM plugin")
+		return
+	}
+	proc.withSession(m.conversations, req.SessionID).withIntentCache(m.intents)
+
+	rc := http.NewResponseController(w)
+	// A long answer can outlast the server's write timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+	started := false
+	send := func(event string, data any) error {
+		payload, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+			return err
+		}
+		// Writers that cannot flush still receive the events, buffered.
+		_ = rc.Flush()
+		return nil
+	}
+
+	resp, err := proc.ProcessStream(r.Context(), req.Query,
+		func(result *analytics.NLQueryResponse) error {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			w.Header().Set("X-Accel-Buffering", "no")
+			w.WriteHeader(http.StatusOK)
+			started = true
+			return send("result", result)
+		},
+		func(text string) error {
+			return send("chunk", map[string]string{"text": text})
+		},
+	)
+	if err != nil {
+		m.logger.Error("nl query stream failed",
+			zap.String("query", req.Query),
+			zap.Error(err),
+		)
+		if !started {
+			writeError(w, http.StatusInternalServerError, "query processing failed")
+			return
+		}
+		_ = send("error", map[string]string{"detail": "query processing failed"})
+		return
+	}
+
+	_ = send("done", resp)
+}
+
 // handleRecommendations returns AI optimization recommendations.
 //
 //	@Summary		Get recommendations
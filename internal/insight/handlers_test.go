@@ -3,14 +3,18 @@ package insight
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/pkg/analytics"
+	"github.com/HerbHall/subnetree/pkg/llm"
 	"github.com/HerbHall/subnetree/pkg/plugin"
+	"github.com/HerbHall/subnetree/pkg/roles"
 	"go.uber.org/zap"
 )
 
@@ -208,3 +212,109 @@ func TestHandleDeviceAnomalies_MissingID(t *testing.T) {
 		t.Errorf("detail = %q, want %q", got["detail"], "device_id is required")
 	}
 }
+
+// flushRecorder snapshots the body on every Flush so tests can check that
+// events are written out incrementally.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed []string
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushed = append(f.flushed, f.Body.String())
+	f.ResponseRecorder.Flush()
+}
+
+func TestHandleNLQueryStream(t *testing.T) {
+	m := newTestModule(t)
+	m.plugins = &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleLLM: {&mockLLMPlugin{provider: streamingLLM("one ", "two ", "three")}},
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/query/stream", strings.NewReader(`{"query":"show anomalies"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	m.handleNLQueryStream(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	// result, three chunks, done -- each flushed on its own.
+	if len(w.flushed) != 5 {
+		t.Fatalf("flushes = %d, want 5; body: %s", len(w.flushed), w.Body.String())
+	}
+	if !strings.HasPrefix(w.flushed[0], "event: result\n") || strings.Contains(w.flushed[0], "chunk") {
+		t.Errorf("first flush = %q, want only the result event", w.flushed[0])
+	}
+	for i, text := range []string{"one ", "two ", "three"} {
+		want := `event: chunk` + "\n" + `data: {"text":"` + text + `"}`
+		got := strings.TrimPrefix(w.flushed[i+1], w.flushed[i])
+		if !strings.HasPrefix(got, want) {
+			t.Errorf("flush %d = %q, want %q", i+1, got, want)
+		}
+	}
+	last := strings.TrimPrefix(w.flushed[4], w.flushed[3])
+	if !strings.HasPrefix(last, "event: done\n") || !strings.Contains(last, `"answer":"one two three"`) {
+		t.Errorf("final event = %q", last)
+	}
+}
+
+func TestHandleNLQueryStream_OutlastsWriteTimeout(t *testing.T) {
+	provider := streamingLLM()
+	provider.generateFunc = func(ctx context.Context, _ string, opts ...llm.CallOption) (*llm.Response, error) {
+		cfg := llm.ApplyOptions(opts...)
+		for _, c := range []string{"slow ", "answer"} {
+			time.Sleep(150 * time.Millisecond)
+			if err := cfg.StreamFunc(ctx, []byte(c)); err != nil {
+				return nil, err
+			}
+		}
+		return &llm.Response{Content: "slow answer", Model: "test-model", Done: true}, nil
+	}
+	m := newTestModule(t)
+	m.plugins = &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{roles.RoleLLM: {&mockLLMPlugin{provider: provider}}},
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(m.handleNLQueryStream))
+	srv.Config.WriteTimeout = 100 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"query":"show anomalies"}`))
+	if err != nil {
+		t.Fatalf("POST: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read stream: %v", err)
+	}
+	if !strings.Contains(string(body), "event: done\n") {
+		t.Errorf("stream cut short by write timeout; body: %s", body)
+	}
+}
+
+func TestHandleNLQueryStream_NoLLM(t *testing.T) {
+	m := newTestModule(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/query/stream", strings.NewReader(`{"query":"hello"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	m.handleNLQueryStream(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	if ct := w.Header().Get("Content-Type"); ct == "text/event-stream" {
+		t.Error("error before streaming should not use an event stream")
+	}
+}
@@ -0,0 +1,97 @@
+package insight
+
+import (
+	"container/list"
+	"strings"
+	"sync"
+	"time"
+)
+
+// intentCache is a size-bounded LRU cache of parsed NL query intents, keyed
+// by normalized query text. Only the intent is cached -- answers depend on
+// live data and are always regenerated.
+type intentCache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	order   *list.List // Front is most recently used
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+// intentCacheEntry is the value stored in each list element.
+type intentCacheEntry struct {
+	key     string
+	intent  queryIntent
+	model   string
+	expires time.Time
+}
+
+// newIntentCache creates a cache holding up to size intents for ttl each.
+// Returns nil, which disables caching, if size or ttl is not positive.
+func newIntentCache(size int, ttl time.Duration) *intentCache {
+	if size <= 0 || ttl <= 0 {
+		return nil
+	}
+	return &intentCache{
+		size:    size,
+		ttl:     ttl,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// normalizeQuery folds case, whitespace, and trailing punctuation so that
+// trivially different phrasings of a query share a cache entry.
+func normalizeQuery(query string) string {
+	q := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	return strings.TrimRight(q, "?!. ")
+}
+
+// get returns a copy of the cached intent for a normalized query and the
+// model that parsed it.
+func (c *intentCache) get(key string) (*queryIntent, string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, "", false
+	}
+	e := el.Value.(*intentCacheEntry)
+	if !c.now().Before(e.expires) {
+		c.order.Remove(el)
+		delete(c.entries, key)
+		return nil, "", false
+	}
+	c.order.MoveToFront(el)
+	intent := e.intent
+	return &intent, e.model, true
+}
+
+// put stores an intent, evicting the least recently used entry when full.
+func (c *intentCache) put(key string, intent *queryIntent, model string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*intentCacheEntry)
+		e.intent, e.model, e.expires = *intent, model, expires
+		c.order.MoveToFront(el)
+		return
+	}
+
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*intentCacheEntry).key)
+	}
+	c.entries[key] = c.order.PushFront(&intentCacheEntry{
+		key:     key,
+		intent:  *intent,
+		model:   model,
+		expires: expires,
+	})
+}
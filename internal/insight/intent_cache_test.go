@@ -0,0 +1,70 @@
+package insight
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewIntentCache_Disabled(t *testing.T) {
+	if c := newIntentCache(0, time.Minute); c != nil {
+		t.Error("size 0 should disable the cache")
+	}
+	if c := newIntentCache(10, 0); c != nil {
+		t.Error("ttl 0 should disable the cache")
+	}
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Show me down devices", "show me down devices"},
+		{"  show   me\tdown devices?! ", "show me down devices"},
+		{"status of web-01.", "status of web-01"},
+	}
+	for _, tt := range tests {
+		if got := normalizeQuery(tt.in); got != tt.want {
+			t.Errorf("normalizeQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestIntentCache_Expiry(t *testing.T) {
+	now := time.Now()
+	c := newIntentCache(4, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.put("q", &queryIntent{Type: IntentListDevices}, "m")
+	intent, model, ok := c.get("q")
+	if !ok || intent.Type != IntentListDevices || model != "m" {
+		t.Fatalf("get = %+v, %q, %v", intent, model, ok)
+	}
+
+	// Callers get a copy they may modify.
+	intent.Type = IntentListAnomalies
+	if again, _, _ := c.get("q"); again.Type != IntentListDevices {
+		t.Error("modifying a returned intent changed the cache")
+	}
+
+	now = now.Add(time.Minute)
+	if _, _, ok := c.get("q"); ok {
+		t.Error("expired entry returned")
+	}
+}
+
+func TestIntentCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newIntentCache(2, time.Minute)
+	c.put("a", &queryIntent{Type: IntentListDevices}, "m")
+	c.put("b", &queryIntent{Type: IntentListAnomalies}, "m")
+	c.get("a") // b is now least recently used
+	c.put("c", &queryIntent{Type: IntentListCorrelations}, "m")
+
+	if _, _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, _, ok := c.get(key); !ok {
+			t.Errorf("entry %q missing", key)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/insight/baseline"
 	"github.com/HerbHall/subnetree/pkg/analytics"
 	"go.uber.org/zap"
 )
@@ -51,8 +52,8 @@ func (m *Module) runMaintenance() {
 		m.logger.Info("purged old anomalies", zap.Int64("count", deleted))
 	}
 
-	// Delete old metrics (keep 2x forecast window for regression)
-	metricCutoff := time.Now().Add(-2 * m.cfg.ForecastWindow)
+	// Delete old metrics (keep 2x the forecast history)
+	metricCutoff := time.Now().Add(-2 * m.forecastHistory())
 	deletedMetrics, err := m.store.DeleteOldMetrics(ctx, metricCutoff)
 	if err != nil {
 		m.logger.Warn("failed to delete old metrics", zap.Error(err))
@@ -121,8 +122,84 @@ func (m *Module) persistBaselines(ctx context.Context) {
 			}
 			persisted++
 		}
+
+		// Persist the seasonal baseline summary and its buckets
+		if sm := state.Seasonal; sm != nil {
+			mean, stdDev, samples := sm.Summary()
+			seasonalBaseline := &analytics.Baseline{
+				DeviceID:   deviceID,
+				MetricName: metric + ":seasonal",
+				Algorithm:  AlgorithmSeasonal,
+				Mean:       mean,
+				StdDev:     stdDev,
+				Samples:    samples,
+				Stable:     seasonalStable(sm, m.cfg.SeasonalMinSamples),
+				UpdatedAt:  now,
+			}
+			err := m.store.UpsertBaseline(ctx, seasonalBaseline)
+			if err == nil {
+				err = m.store.UpsertBaselineBuckets(ctx, deviceID, metric, sm)
+			}
+			if err != nil {
+				m.logger.Warn("failed to persist baseline",
+					zap.String("device_id", deviceID),
+					zap.String("metric", metric),
+					zap.String("algorithm", AlgorithmSeasonal),
+					zap.Error(err),
+				)
+				continue
+			}
+			persisted++
+		}
 	}
 	if persisted > 0 {
 		m.logger.Debug("persisted baselines", zap.Int("count", persisted))
 	}
 }
+
+// seasonalStable reports whether every bucket of a seasonal model has enough
+// samples to be used for anomaly detection.
+func seasonalStable(sm *baseline.Seasonal, minSamples int) bool {
+	for i := range sm.Buckets {
+		if sm.Buckets[i].Samples < minSamples {
+			return false
+		}
+	}
+	return true
+}
+
+// restoreSeasonalBaselines loads persisted seasonal buckets into memory so
+// that the weeks of history a seasonal model needs survive restarts.
+func (m *Module) restoreSeasonalBaselines() {
+	if m.store == nil || m.cfg.BaselineAlgorithm != AlgorithmSeasonal {
+		return
+	}
+	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
+	defer cancel()
+
+	period := baseline.HourlyBuckets
+	if m.cfg.SeasonalWeekly {
+		period = baseline.WeeklyBuckets
+	}
+	buckets, err := m.store.ListBaselineBuckets(ctx, period)
+	if err != nil {
+		m.logger.Warn("failed to restore seasonal baselines", zap.Error(err))
+		return
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		state := m.states.getOrCreate(b.DeviceID, b.MetricName)
+		if state.Seasonal == nil || b.Bucket < 0 || b.Bucket >= len(state.Seasonal.Buckets) {
+			continue
+		}
+		state.Seasonal.Buckets[b.Bucket] = b.SeasonalBucket
+
+		m.mu.Lock()
+		m.baselines[stateKey(b.DeviceID, b.MetricName)] = struct{}{}
+		m.mu.Unlock()
+	}
+	if len(buckets) > 0 {
+		m.logger.Info("restored seasonal baselines", zap.Int("buckets", len(buckets)))
+	}
+}
@@ -86,5 +86,46 @@ func migrations() []plugin.Migration {
 				return nil
 			},
 		},
+		{
+			Version:     2,
+			Description: "create seasonal baseline buckets table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS analytics_baseline_buckets (
+					device_id    TEXT NOT NULL,
+					metric_name  TEXT NOT NULL,
+					period       INTEGER NOT NULL,
+					bucket       INTEGER NOT NULL,
+					mean         REAL NOT NULL DEFAULT 0,
+					variance     REAL NOT NULL DEFAULT 0,
+					samples      INTEGER NOT NULL DEFAULT 0,
+					PRIMARY KEY (device_id, metric_name, period, bucket)
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     3,
+			Description: "add forecast algorithm and predicted points",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE analytics_forecasts ADD COLUMN algorithm TEXT NOT NULL DEFAULT 'linear'`,
+					`ALTER TABLE analytics_forecasts ADD COLUMN points TEXT NOT NULL DEFAULT '[]'`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     4,
+			Description: "add root cause hint to correlations",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE analytics_correlations ADD COLUMN root_cause_hint TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+		},
 	}
 }
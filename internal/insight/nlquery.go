@@ -21,9 +21,12 @@ Available intent types:
 - "list_correlations": Show active alert correlation groups. No parameters needed.
 - "list_devices": Show all discovered network devices. No parameters needed.
 - "device_status": Show comprehensive status for a specific device including anomalies, baselines, and forecasts. Requires "device_id".
+- "list_alerts": Show monitoring alerts. Optional "device_id" to filter by device, optional "active_only" (true for unresolved alerts only), optional "limit" (default 50).
+- "device_uptime": Show availability of a specific device. Requires "device_id". Optional "range": one of "1h", "6h", "24h", "7d", "30d" (default "24h").
+- "list_down_devices": Show devices that are currently down. No parameters needed.
 
 Output format — return ONLY valid JSON, no explanation:
-{"type":"<intent_type>","device_id":"<id_if_applicable>","limit":<number_if_applicable>}
+{"type":"<intent_type>","device_id":"<id_if_applicable>","limit":<number_if_applicable>,"active_only":<bool_if_applicable>,"range":"<range_if_applicable>"}
 
 Examples:
 - "show me recent anomalies" → {"type":"list_anomalies","limit":10}
@@ -32,7 +35,12 @@ Examples:
 - "status of web-server-01" → {"type":"device_status","device_id":"web-server-01"}
 - "forecasts for db-primary" → {"type":"list_forecasts","device_id":"db-primary"}
 - "are there correlated alerts?" → {"type":"list_correlations"}
-- "baselines for switch-core" → {"type":"list_baselines","device_id":"switch-core"}`
+- "baselines for switch-core" → {"type":"list_baselines","device_id":"switch-core"}
+- "any open alerts?" → {"type":"list_alerts","active_only":true}
+- "uptime of nas-01 this week" → {"type":"device_uptime","device_id":"nas-01","range":"7d"}
+- "what's down right now?" → {"type":"list_down_devices"}
+
+If earlier turns of the conversation are included, use them to resolve follow-up questions such as "and on that device?".`
 
 // responseFormatterTemplate is used for the second LLM call that converts structured data
 // into a natural language answer.
@@ -50,6 +58,13 @@ type nlQueryProcessor struct {
 	llmProvider llm.Provider
 	store       *InsightStore
 	plugins     plugin.PluginResolver
+
+	// Optional conversation context; nil history means stateless queries.
+	history   *conversationStore
+	sessionID string
+
+	// Optional cache of parsed intents; nil disables caching.
+	intents *intentCache
 }
 
 // newNLQueryProcessor creates a processor by resolving the LLM plugin.
@@ -76,6 +91,24 @@ func newNLQueryProcessor(plugins plugin.PluginResolver, store *InsightStore) *nl
 	}
 }
 
+// withSession makes queries part of a conversation: prior turns of the
+// session are sent along with each query, and each answered query is
+// recorded. An empty session ID leaves the processor stateless.
+func (p *nlQueryProcessor) withSession(history *conversationStore, sessionID string) *nlQueryProcessor {
+	if sessionID != "" {
+		p.history = history
+		p.sessionID = sessionID
+	}
+	return p
+}
+
+// withIntentCache lets the processor reuse intents parsed for earlier,
+// identical queries.
+func (p *nlQueryProcessor) withIntentCache(cache *intentCache) *nlQueryProcessor {
+	p.intents = cache
+	return p
+}
+
 // Process executes a natural language query through a two-phase LLM pipeline:
 // 1. Parse the user's question into a structured intent (low temperature).
 // 2. Execute the intent against the store/plugins.
@@ -95,6 +128,7 @@ func (p *nlQueryProcessor) Process(ctx context.Context, query string) (*analytic
 	if err != nil {
 		return nil, fmt.Errorf("format response: %w", err)
 	}
+	p.recordTurn(query, intent)
 
 	return &analytics.NLQueryResponse{
 		Query:      query,
@@ -104,14 +138,81 @@ func (p *nlQueryProcessor) Process(ctx context.Context, query string) (*analytic
 	}, nil
 }
 
+// ProcessStream runs the same pipeline as Process but delivers the answer
+// incrementally. onResult is called once with the parsed intent's
+// structured data (Answer empty) before formatting starts; onChunk is then
+// called for each piece of the answer as the LLM produces it. Providers
+// that ignore llm.WithStreamFunc yield the whole answer as a single chunk.
+// The returned response carries the complete answer.
+func (p *nlQueryProcessor) ProcessStream(ctx context.Context, query string,
+	onResult func(*analytics.NLQueryResponse) error, onChunk func(string) error,
+) (*analytics.NLQueryResponse, error) {
+	intent, model, err := p.parseIntent(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("parse intent: %w", err)
+	}
+
+	structured, err := intent.execute(ctx, p.store, p.plugins)
+	if err != nil {
+		return nil, fmt.Errorf("execute intent: %w", err)
+	}
+
+	resp := &analytics.NLQueryResponse{
+		Query:      query,
+		Structured: structured,
+		Model:      model,
+	}
+	if err := onResult(resp); err != nil {
+		return nil, err
+	}
+
+	streamed := false
+	answer, err := p.formatResponse(ctx, query, intent, structured,
+		llm.WithStreamFunc(func(_ context.Context, chunk []byte) error {
+			streamed = true
+			return onChunk(string(chunk))
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("format response: %w", err)
+	}
+	if !streamed && answer != "" {
+		if err := onChunk(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	p.recordTurn(query, intent)
+
+	resp.Answer = answer
+	return resp, nil
+}
+
 // parseIntent sends the user query to the LLM with a system prompt that instructs it
-// to return a JSON intent object.
+// to return a JSON intent object. Queries without conversation history are
+// served from the intent cache when possible.
 func (p *nlQueryProcessor) parseIntent(ctx context.Context, query string) (*queryIntent, string, error) {
-	messages := []llm.Message{
-		{Role: llm.RoleSystem, Content: intentParserSystemPrompt},
-		{Role: llm.RoleUser, Content: query},
+	var history []llm.Message
+	if p.history != nil {
+		history = p.history.history(p.sessionID)
+	}
+
+	// Follow-ups depend on earlier turns, so only standalone queries are cached.
+	cache := p.intents
+	if len(history) > 0 {
+		cache = nil
+	}
+	key := normalizeQuery(query)
+	if cache != nil {
+		if intent, model, ok := cache.get(key); ok {
+			return intent, model, nil
+		}
 	}
 
+	messages := []llm.Message{{Role: llm.RoleSystem, Content: intentParserSystemPrompt}}
+	messages = append(messages, history...)
+	messages = append(messages, llm.Message{Role: llm.RoleUser, Content: query})
+
 	resp, err := p.llmProvider.Chat(ctx, messages,
 		llm.WithTemperature(0.1),
 		llm.WithMaxTokens(256),
@@ -124,13 +225,32 @@ func (p *nlQueryProcessor) parseIntent(ctx context.Context, query string) (*quer
 	if err := json.Unmarshal([]byte(resp.Content), &intent); err != nil {
 		return nil, "", fmt.Errorf("LLM returned invalid JSON: %w", err)
 	}
+	if cache != nil {
+		cache.put(key, &intent, resp.Model)
+	}
 
 	return &intent, resp.Model, nil
 }
 
+// recordTurn adds an answered query to the session history, if any. The
+// history is replayed only to the intent parser, so the assistant turn is
+// the parsed intent as JSON rather than the prose answer; prose turns would
+// lead the parser to answer follow-ups in prose instead of JSON.
+func (p *nlQueryProcessor) recordTurn(query string, intent *queryIntent) {
+	if p.history == nil {
+		return
+	}
+	reply, err := json.Marshal(intent)
+	if err != nil {
+		return
+	}
+	p.history.record(p.sessionID, query, string(reply))
+}
+
 // formatResponse sends a second LLM call to convert structured query results
-// into a natural language answer.
-func (p *nlQueryProcessor) formatResponse(ctx context.Context, query string, intent *queryIntent, data any) (string, error) {
+// into a natural language answer. Extra options, such as a stream function,
+// are passed through to the provider.
+func (p *nlQueryProcessor) formatResponse(ctx context.Context, query string, intent *queryIntent, data any, opts ...llm.CallOption) (string, error) {
 	dataJSON, err := json.MarshalIndent(data, "", "  ")
 	if err != nil {
 		dataJSON = []byte("[]")
@@ -138,10 +258,11 @@ func (p *nlQueryProcessor) formatResponse(ctx context.Context, query string, int
 
 	prompt := fmt.Sprintf(responseFormatterTemplate, query, intent.Type, string(dataJSON))
 
-	resp, err := p.llmProvider.Generate(ctx, prompt,
+	opts = append([]llm.CallOption{
 		llm.WithTemperature(0.7),
 		llm.WithMaxTokens(1024),
-	)
+	}, opts...)
+	resp, err := p.llmProvider.Generate(ctx, prompt, opts...)
 	if err != nil {
 		return "", err
 	}
@@ -18,13 +18,23 @@ const (
 	IntentListCorrelations = "list_correlations"
 	IntentListDevices      = "list_devices"
 	IntentDeviceStatus     = "device_status"
+	IntentListAlerts       = "list_alerts"
+	IntentDeviceUptime     = "device_uptime"
+	IntentListDownDevices  = "list_down_devices"
 )
 
+// defaultUptimeRange is the uptime window used when the query names none.
+const defaultUptimeRange = "24h"
+
 // queryIntent represents the structured output from the LLM intent parser.
 type queryIntent struct {
 	Type     string `json:"type"`
 	DeviceID string `json:"device_id,omitempty"`
 	Limit    int    `json:"limit,omitempty"`
+
+	// Monitoring intents only.
+	ActiveOnly bool   `json:"active_only,omitempty"` // list_alerts: unresolved alerts only
+	Range      string `json:"range,omitempty"`       // device_uptime: "1h", "24h", "7d", ...
 }
 
 // deviceStatusResult is the composite response for a device_status intent.
@@ -50,6 +60,12 @@ func (i *queryIntent) execute(ctx context.Context, store *InsightStore, plugins
 		return i.executeListDevices(ctx, plugins)
 	case IntentDeviceStatus:
 		return i.executeDeviceStatus(ctx, store, plugins)
+	case IntentListAlerts:
+		return i.executeListAlerts(ctx, plugins)
+	case IntentDeviceUptime:
+		return i.executeDeviceUptime(ctx, plugins)
+	case IntentListDownDevices:
+		return i.executeListDownDevices(ctx, plugins)
 	default:
 		return nil, fmt.Errorf("unsupported intent type: %s", i.Type)
 	}
@@ -175,3 +191,70 @@ func (i *queryIntent) executeDeviceStatus(ctx context.Context, store *InsightSto
 
 	return result, nil
 }
+
+// monitoringHistory resolves the monitoring plugin's history interface.
+// Returns nil if no monitoring plugin is registered or it keeps no history.
+func monitoringHistory(plugins plugin.PluginResolver) roles.MonitoringHistoryProvider {
+	if plugins == nil {
+		return nil
+	}
+	monitors := plugins.ResolveByRole(roles.RoleMonitoring)
+	if len(monitors) == 0 {
+		return nil
+	}
+	mp, ok := monitors[0].(roles.MonitoringHistoryProvider)
+	if !ok {
+		return nil
+	}
+	return mp
+}
+
+func (i *queryIntent) executeListAlerts(ctx context.Context, plugins plugin.PluginResolver) ([]roles.MonitorAlert, error) {
+	mp := monitoringHistory(plugins)
+	if mp == nil {
+		return []roles.MonitorAlert{}, nil
+	}
+	limit := i.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	alerts, err := mp.Alerts(ctx, i.DeviceID, i.ActiveOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	if alerts == nil {
+		return []roles.MonitorAlert{}, nil
+	}
+	return alerts, nil
+}
+
+func (i *queryIntent) executeDeviceUptime(ctx context.Context, plugins plugin.PluginResolver) (*roles.UptimeStats, error) {
+	if i.DeviceID == "" {
+		return nil, fmt.Errorf("device_id required for device_uptime intent")
+	}
+	timeRange := i.Range
+	if timeRange == "" {
+		timeRange = defaultUptimeRange
+	}
+
+	mp := monitoringHistory(plugins)
+	if mp == nil {
+		return &roles.UptimeStats{DeviceID: i.DeviceID, Range: timeRange}, nil
+	}
+	return mp.Uptime(ctx, i.DeviceID, timeRange)
+}
+
+func (i *queryIntent) executeListDownDevices(ctx context.Context, plugins plugin.PluginResolver) ([]roles.MonitorStatus, error) {
+	mp := monitoringHistory(plugins)
+	if mp == nil {
+		return []roles.MonitorStatus{}, nil
+	}
+	down, err := mp.DownDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if down == nil {
+		return []roles.MonitorStatus{}, nil
+	}
+	return down, nil
+}
@@ -91,6 +91,49 @@ func (m *mockDiscoveryPlugin) DeviceByID(_ context.Context, id string) (*models.
 
 // -- Constructor tests --
 
+// mockMonitoringPlugin implements plugin.Plugin + roles.MonitoringHistoryProvider.
+type mockMonitoringPlugin struct {
+	alerts []roles.MonitorAlert
+	uptime map[string]*roles.UptimeStats // keyed by device_id + ":" + range
+	down   []roles.MonitorStatus
+}
+
+func (m *mockMonitoringPlugin) Info() plugin.PluginInfo {
+	return plugin.PluginInfo{Name: "mock-pulse", Roles: []string{roles.RoleMonitoring}}
+}
+
+func (m *mockMonitoringPlugin) Init(_ context.Context, _ plugin.Dependencies) error { return nil }
+func (m *mockMonitoringPlugin) Start(_ context.Context) error                       { return nil }
+func (m *mockMonitoringPlugin) Stop(_ context.Context) error                        { return nil }
+
+func (m *mockMonitoringPlugin) Alerts(_ context.Context, deviceID string, activeOnly bool, limit int) ([]roles.MonitorAlert, error) {
+	var out []roles.MonitorAlert
+	for _, a := range m.alerts {
+		if deviceID != "" && a.DeviceID != deviceID {
+			continue
+		}
+		if activeOnly && a.ResolvedAt != nil {
+			continue
+		}
+		out = append(out, a)
+	}
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func (m *mockMonitoringPlugin) Uptime(_ context.Context, deviceID, timeRange string) (*roles.UptimeStats, error) {
+	if u, ok := m.uptime[deviceID+":"+timeRange]; ok {
+		return u, nil
+	}
+	return nil, fmt.Errorf("no uptime for %s over %s", deviceID, timeRange)
+}
+
+func (m *mockMonitoringPlugin) DownDevices(_ context.Context) ([]roles.MonitorStatus, error) {
+	return m.down, nil
+}
+
 func TestNewNLQueryProcessor_NilPlugins(t *testing.T) {
 	proc := newNLQueryProcessor(nil, nil)
 	if proc != nil {
@@ -443,3 +486,343 @@ func TestExecuteListCorrelations_NilStore(t *testing.T) {
 		t.Errorf("expected empty, got %d", len(groups))
 	}
 }
+
+// streamingLLM returns a provider whose Generate streams chunks through the
+// caller's StreamFunc, as a streaming-capable backend would.
+func streamingLLM(chunks ...string) *mockLLMProvider {
+	return &mockLLMProvider{
+		chatFunc: func(_ context.Context, _ []llm.Message, _ ...llm.CallOption) (*llm.Response, error) {
+			return &llm.Response{Content: `{"type":"list_anomalies","limit":10}`, Model: "test-model", Done: true}, nil
+		},
+		generateFunc: func(ctx context.Context, _ string, opts ...llm.CallOption) (*llm.Response, error) {
+			cfg := llm.ApplyOptions(opts...)
+			if cfg.StreamFunc == nil {
+				return nil, fmt.Errorf("expected a stream function")
+			}
+			var full string
+			for _, c := range chunks {
+				if err := cfg.StreamFunc(ctx, []byte(c)); err != nil {
+					return nil, err
+				}
+				full += c
+			}
+			return &llm.Response{Content: full, Model: "test-model", Done: true}, nil
+		},
+	}
+}
+
+func TestProcessStream_Chunks(t *testing.T) {
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleLLM: {&mockLLMPlugin{provider: streamingLLM("Found ", "no ", "anomalies.")}},
+		},
+	}
+	proc := newNLQueryProcessor(resolver, testStore(t))
+
+	var events []string
+	resp, err := proc.ProcessStream(context.Background(), "show anomalies",
+		func(r *analytics.NLQueryResponse) error {
+			if r.Structured == nil || r.Answer != "" {
+				t.Errorf("result = %+v, want structured data and no answer", r)
+			}
+			events = append(events, "result")
+			return nil
+		},
+		func(text string) error {
+			events = append(events, text)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("ProcessStream() error: %v", err)
+	}
+
+	want := []string{"result", "Found ", "no ", "anomalies."}
+	if fmt.Sprint(events) != fmt.Sprint(want) {
+		t.Errorf("events = %q, want %q", events, want)
+	}
+	if resp.Answer != "Found no anomalies." || resp.Model != "test-model" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
+
+func TestProcessStream_NonStreamingProvider(t *testing.T) {
+	mockLLM := &mockLLMProvider{
+		chatFunc: func(_ context.Context, _ []llm.Message, _ ...llm.CallOption) (*llm.Response, error) {
+			return &llm.Response{Content: `{"type":"list_anomalies"}`, Model: "test-model", Done: true}, nil
+		},
+		// Ignores StreamFunc, like providers without streaming support.
+		generateFunc: func(_ context.Context, _ string, _ ...llm.CallOption) (*llm.Response, error) {
+			return &llm.Response{Content: "All quiet.", Model: "test-model", Done: true}, nil
+		},
+	}
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{roles.RoleLLM: {&mockLLMPlugin{provider: mockLLM}}},
+	}
+	proc := newNLQueryProcessor(resolver, testStore(t))
+
+	var chunks []string
+	resp, err := proc.ProcessStream(context.Background(), "anything wrong?",
+		func(*analytics.NLQueryResponse) error { return nil },
+		func(text string) error {
+			chunks = append(chunks, text)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("ProcessStream() error: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0] != "All quiet." {
+		t.Errorf("chunks = %q, want the buffered answer as one chunk", chunks)
+	}
+	if resp.Answer != "All quiet." {
+		t.Errorf("Answer = %q", resp.Answer)
+	}
+}
+
+func TestProcess_SessionHistory(t *testing.T) {
+	var calls [][]llm.Message
+	mockLLM := &mockLLMProvider{
+		chatFunc: func(_ context.Context, messages []llm.Message, _ ...llm.CallOption) (*llm.Response, error) {
+			calls = append(calls, messages)
+			return &llm.Response{Content: `{"type":"device_status","device_id":"router-01"}`, Model: "test-model", Done: true}, nil
+		},
+		generateFunc: func(_ context.Context, _ string, _ ...llm.CallOption) (*llm.Response, error) {
+			return &llm.Response{Content: "router-01 is healthy.", Model: "test-model", Done: true}, nil
+		},
+	}
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{roles.RoleLLM: {&mockLLMPlugin{provider: mockLLM}}},
+	}
+	s := testStore(t)
+	history := newConversationStore()
+	ctx := context.Background()
+
+	query := func(sessionID, q string) {
+		t.Helper()
+		proc := newNLQueryProcessor(resolver, s).withSession(history, sessionID)
+		if _, err := proc.Process(ctx, q); err != nil {
+			t.Fatalf("Process(%q) error: %v", q, err)
+		}
+	}
+
+	query("s1", "status of router-01")
+	query("s1", "and its forecasts?")
+	query("s2", "any anomalies?")
+	query("", "any anomalies?")
+
+	if len(calls[0]) != 2 {
+		t.Errorf("first call messages = %d, want system + user", len(calls[0]))
+	}
+
+	second := calls[1]
+	want := []llm.Message{
+		{Role: llm.RoleUser, Content: "status of router-01"},
+		{Role: llm.RoleAssistant, Content: `{"type":"device_status","device_id":"router-01"}`},
+		{Role: llm.RoleUser, Content: "and its forecasts?"},
+	}
+	if len(second) != 4 || second[0].Role != llm.RoleSystem {
+		t.Fatalf("second call messages = %+v, want system + prior turn + query", second)
+	}
+	for i, m := range want {
+		if second[i+1] != m {
+			t.Errorf("second call message %d = %+v, want %+v", i+1, second[i+1], m)
+		}
+	}
+
+	// Other sessions, and queries without a session, start fresh.
+	if len(calls[2]) != 2 || len(calls[3]) != 2 {
+		t.Errorf("unrelated call messages = %d, %d; want 2 each", len(calls[2]), len(calls[3]))
+	}
+}
+
+func TestExecuteListAlerts_WithMonitoring(t *testing.T) {
+	resolved := time.Now()
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleMonitoring: {&mockMonitoringPlugin{alerts: []roles.MonitorAlert{
+				{ID: "a1", DeviceID: "router-01", Severity: "critical", Message: "ping failed"},
+				{ID: "a2", DeviceID: "nas-01", Severity: "warning", Message: "high latency"},
+				{ID: "a3", DeviceID: "router-01", Severity: "warning", Message: "flapping", ResolvedAt: &resolved},
+			}}},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		intent queryIntent
+		want   []string
+	}{
+		{"all", queryIntent{Type: IntentListAlerts}, []string{"a1", "a2", "a3"}},
+		{"active only", queryIntent{Type: IntentListAlerts, ActiveOnly: true}, []string{"a1", "a2"}},
+		{"by device", queryIntent{Type: IntentListAlerts, DeviceID: "router-01"}, []string{"a1", "a3"}},
+		{"limit", queryIntent{Type: IntentListAlerts, Limit: 1}, []string{"a1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.intent.execute(context.Background(), nil, resolver)
+			if err != nil {
+				t.Fatalf("execute error: %v", err)
+			}
+			alerts, ok := result.([]roles.MonitorAlert)
+			if !ok {
+				t.Fatalf("expected []roles.MonitorAlert, got %T", result)
+			}
+			var ids []string
+			for _, a := range alerts {
+				ids = append(ids, a.ID)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.want) {
+				t.Errorf("alert IDs = %v, want %v", ids, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteDeviceUptime_WithMonitoring(t *testing.T) {
+	pct := 99.5
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleMonitoring: {&mockMonitoringPlugin{uptime: map[string]*roles.UptimeStats{
+				"nas-01:24h": {DeviceID: "nas-01", Range: "24h", UptimePercent: &pct, Outages: 1},
+				"nas-01:7d":  {DeviceID: "nas-01", Range: "7d"},
+			}}},
+		},
+	}
+
+	// No range defaults to the last 24 hours.
+	intent := &queryIntent{Type: IntentDeviceUptime, DeviceID: "nas-01"}
+	result, err := intent.execute(context.Background(), nil, resolver)
+	if err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	uptime, ok := result.(*roles.UptimeStats)
+	if !ok {
+		t.Fatalf("expected *roles.UptimeStats, got %T", result)
+	}
+	if uptime.Range != "24h" || uptime.UptimePercent == nil || *uptime.UptimePercent != pct {
+		t.Errorf("uptime = %+v", uptime)
+	}
+
+	intent.Range = "7d"
+	result, err = intent.execute(context.Background(), nil, resolver)
+	if err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	if got := result.(*roles.UptimeStats).Range; got != "7d" {
+		t.Errorf("Range = %q, want 7d", got)
+	}
+
+	intent = &queryIntent{Type: IntentDeviceUptime}
+	if _, err := intent.execute(context.Background(), nil, resolver); err == nil {
+		t.Error("expected error for missing device_id")
+	}
+}
+
+func TestExecuteListDownDevices_WithMonitoring(t *testing.T) {
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleMonitoring: {&mockMonitoringPlugin{down: []roles.MonitorStatus{
+				{DeviceID: "router-01", Message: "ping failed"},
+			}}},
+		},
+	}
+	intent := &queryIntent{Type: IntentListDownDevices}
+	result, err := intent.execute(context.Background(), nil, resolver)
+	if err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	down, ok := result.([]roles.MonitorStatus)
+	if !ok {
+		t.Fatalf("expected []roles.MonitorStatus, got %T", result)
+	}
+	if len(down) != 1 || down[0].DeviceID != "router-01" {
+		t.Errorf("down = %+v", down)
+	}
+}
+
+func TestExecuteMonitoringIntents_NoMonitoring(t *testing.T) {
+	// A monitoring-role plugin without history, and no resolver at all,
+	// both yield empty results rather than errors.
+	withoutHistory := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{
+			roles.RoleMonitoring: {&mockDiscoveryPlugin{}},
+		},
+	}
+	for _, resolver := range []plugin.PluginResolver{nil, withoutHistory} {
+		ctx := context.Background()
+
+		result, err := (&queryIntent{Type: IntentListAlerts}).execute(ctx, nil, resolver)
+		if err != nil {
+			t.Fatalf("list_alerts error: %v", err)
+		}
+		if alerts, ok := result.([]roles.MonitorAlert); !ok || len(alerts) != 0 {
+			t.Errorf("list_alerts = %#v, want empty slice", result)
+		}
+
+		result, err = (&queryIntent{Type: IntentDeviceUptime, DeviceID: "nas-01"}).execute(ctx, nil, resolver)
+		if err != nil {
+			t.Fatalf("device_uptime error: %v", err)
+		}
+		if uptime, ok := result.(*roles.UptimeStats); !ok || uptime.UptimePercent != nil || uptime.Range != defaultUptimeRange {
+			t.Errorf("device_uptime = %#v, want empty stats", result)
+		}
+
+		result, err = (&queryIntent{Type: IntentListDownDevices}).execute(ctx, nil, resolver)
+		if err != nil {
+			t.Fatalf("list_down_devices error: %v", err)
+		}
+		if down, ok := result.([]roles.MonitorStatus); !ok || len(down) != 0 {
+			t.Errorf("list_down_devices = %#v, want empty slice", result)
+		}
+	}
+}
+
+func TestProcess_IntentCache(t *testing.T) {
+	chatCalls := 0
+	mockLLM := &mockLLMProvider{
+		chatFunc: func(_ context.Context, _ []llm.Message, _ ...llm.CallOption) (*llm.Response, error) {
+			chatCalls++
+			return &llm.Response{Content: `{"type":"list_correlations"}`, Model: "test-model", Done: true}, nil
+		},
+		generateFunc: func(_ context.Context, _ string, _ ...llm.CallOption) (*llm.Response, error) {
+			return &llm.Response{Content: "No correlated alerts.", Model: "test-model", Done: true}, nil
+		},
+	}
+	resolver := &mockPluginResolver{
+		byRole: map[string][]plugin.Plugin{roles.RoleLLM: {&mockLLMPlugin{provider: mockLLM}}},
+	}
+	s := testStore(t)
+	cache := newIntentCache(8, time.Minute)
+	history := newConversationStore()
+	ctx := context.Background()
+
+	query := func(sessionID, q string) *analytics.NLQueryResponse {
+		t.Helper()
+		proc := newNLQueryProcessor(resolver, s).withSession(history, sessionID).withIntentCache(cache)
+		resp, err := proc.Process(ctx, q)
+		if err != nil {
+			t.Fatalf("Process(%q) error: %v", q, err)
+		}
+		return resp
+	}
+
+	query("", "Are there correlated alerts?")
+	resp := query("", "  are there   correlated alerts ")
+	if chatCalls != 1 {
+		t.Errorf("Chat calls = %d, want 1 (second query served from cache)", chatCalls)
+	}
+	if resp.Model != "test-model" || resp.Answer != "No correlated alerts." {
+		t.Errorf("cached response = %+v", resp)
+	}
+
+	// A session's first query may use the cache; follow-ups always parse.
+	query("s1", "are there correlated alerts?")
+	if chatCalls != 1 {
+		t.Errorf("Chat calls = %d, want 1 for a session without history", chatCalls)
+	}
+	query("s1", "are there correlated alerts?")
+	if chatCalls != 2 {
+		t.Errorf("Chat calls = %d, want 2 for a follow-up in a session", chatCalls)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/insight/anomaly"
+	"github.com/HerbHall/subnetree/internal/insight/baseline"
 	"github.com/HerbHall/subnetree/internal/insight/forecast"
 	"github.com/HerbHall/subnetree/pkg/analytics"
 	"github.com/HerbHall/subnetree/pkg/plugin"
@@ -34,9 +35,14 @@ type Module struct {
 	plugins plugin.PluginResolver
 	states  *stateManager
 
+	conversations *conversationStore // NL query session history
+	intents       *intentCache       // Parsed NL query intents; nil if disabled
+
 	mu        sync.RWMutex
 	baselines map[string]struct{} // Tracked device:metric pairs
 
+	correlateMu sync.Mutex // Serializes topology correlation passes
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
@@ -45,7 +51,8 @@ type Module struct {
 // New creates a new Insight plugin instance.
 func New() *Module {
 	return &Module{
-		baselines: make(map[string]struct{}),
+		baselines:     make(map[string]struct{}),
+		conversations: newConversationStore(),
 	}
 }
 
@@ -79,10 +86,34 @@ func (m *Module) Init(_ context.Context, deps plugin.Dependencies) error {
 
 	m.bus = deps.Bus
 	m.plugins = deps.Plugins
+	m.intents = newIntentCache(m.cfg.NLIntentCacheSize, m.cfg.NLIntentCacheTTL)
 	m.states = newStateManager(
 		m.cfg.EWMAAlpha, m.cfg.CUSUMDrift, m.cfg.CUSUMThreshold,
 		m.cfg.HWAlpha, m.cfg.HWBeta, m.cfg.HWGamma, m.cfg.HWSeasonLen,
 	)
+	switch m.cfg.BaselineAlgorithm {
+	case AlgorithmEWMA, "":
+	case AlgorithmSeasonal:
+		m.states.enableSeasonal(m.cfg.SeasonalAlpha, m.cfg.SeasonalWeekly)
+	default:
+		return fmt.Errorf("unknown baseline_algorithm %q: must be %q or %q",
+			m.cfg.BaselineAlgorithm, AlgorithmEWMA, AlgorithmSeasonal)
+	}
+	switch m.cfg.ForecastAlgorithm {
+	case ForecastLinear, "":
+	case ForecastHoltWinters:
+		if m.cfg.ForecastStep <= 0 {
+			return fmt.Errorf("forecast_step must be positive for %q forecasts, got %s",
+				ForecastHoltWinters, m.cfg.ForecastStep)
+		}
+		if m.cfg.ForecastSeasonLen < 2 {
+			return fmt.Errorf("forecast_season_len must be at least 2 for %q forecasts, got %d",
+				ForecastHoltWinters, m.cfg.ForecastSeasonLen)
+		}
+	default:
+		return fmt.Errorf("unknown forecast_algorithm %q: must be %q or %q",
+			m.cfg.ForecastAlgorithm, ForecastLinear, ForecastHoltWinters)
+	}
 
 	m.logger.Info("insight module initialized",
 		zap.Float64("ewma_alpha", m.cfg.EWMAAlpha),
@@ -92,12 +123,15 @@ func (m *Module) Init(_ context.Context, deps plugin.Dependencies) error {
 		zap.Float64("hw_beta", m.cfg.HWBeta),
 		zap.Float64("hw_gamma", m.cfg.HWGamma),
 		zap.Int("hw_season_len", m.cfg.HWSeasonLen),
+		zap.String("baseline_algorithm", m.cfg.BaselineAlgorithm),
+		zap.String("forecast_algorithm", m.cfg.ForecastAlgorithm),
 	)
 	return nil
 }
 
 func (m *Module) Start(_ context.Context) error {
 	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.restoreSeasonalBaselines()
 	m.startMaintenance()
 	m.logger.Info("insight module started")
 	return nil
@@ -169,8 +203,11 @@ func (m *Module) handleMetricsCollected(_ context.Context, event plugin.Event) {
 }
 
 // processMetric runs a single metric point through the analytics pipeline.
-// When Holt-Winters has accumulated enough seasonal data (>= 2 * season_len),
-// it is used for anomaly detection via expected range. Otherwise, EWMA + Z-score is used.
+// With the seasonal algorithm, points are scored against the mean and
+// standard deviation of their hour-of-day bucket once it has enough samples.
+// Otherwise, when Holt-Winters has accumulated enough seasonal data
+// (>= 2 * season_len), it is used for anomaly detection via expected range,
+// falling back to EWMA + Z-score.
 func (m *Module) processMetric(p *analytics.MetricPoint) {
 	// Store raw metric for regression
 	if m.store != nil {
@@ -197,14 +234,35 @@ func (m *Module) processMetric(p *analytics.MetricPoint) {
 	// Update Holt-Winters model
 	state.HoltWinters.Update(p.Value)
 
+	// Update the seasonal model, keeping the bucket's prior stats for scoring
+	var bucket baseline.SeasonalBucket
+	if state.Seasonal != nil {
+		at := p.Timestamp
+		if at.IsZero() {
+			at = time.Now()
+		}
+		bucket = state.Seasonal.Expected(at)
+		state.Seasonal.Update(at, p.Value)
+	}
+	seasonalReady := state.Seasonal != nil &&
+		bucket.Samples >= m.cfg.SeasonalMinSamples && bucket.StdDev() > 0
+
 	// Skip anomaly detection during learning period
 	if state.EWMA.Samples < m.cfg.MinSamplesStable {
 		return
 	}
 
+	// Seasonal bucket anomaly detection replaces the global checks when ready
+	if seasonalReady {
+		zResult := anomaly.ZScoreCheck(p.Value, bucket.Mean, bucket.StdDev(), m.cfg.ZScoreThreshold)
+		if zResult.IsAnomaly {
+			m.recordAnomaly(p, AlgorithmSeasonal, zResult.Severity, zResult.ZScore, bucket.Mean)
+		}
+	}
+
 	// Holt-Winters seasonal anomaly detection: use when model has seen >= 2 full seasons
 	hwUsed := false
-	if state.HoltWinters.IsInitialized() && state.HoltWinters.Samples >= 2*m.cfg.HWSeasonLen {
+	if !seasonalReady && state.HoltWinters.IsInitialized() && state.HoltWinters.Samples >= 2*m.cfg.HWSeasonLen {
 		lower, upper := state.HoltWinters.ExpectedRange(m.cfg.HWConfidence)
 		if lower != upper && (p.Value < lower || p.Value > upper) {
 			hwFitted := state.HoltWinters.Fitted()
@@ -219,14 +277,18 @@ func (m *Module) processMetric(p *analytics.MetricPoint) {
 	}
 
 	// Fall back to Z-score when Holt-Winters did not flag (or is not ready)
-	if !hwUsed {
+	if !seasonalReady && !hwUsed {
 		zResult := anomaly.ZScoreCheck(p.Value, prevMean, prevStdDev, m.cfg.ZScoreThreshold)
 		if zResult.IsAnomaly {
 			m.recordAnomaly(p, "zscore", zResult.Severity, zResult.ZScore, prevMean)
 		}
 	}
 
-	// CUSUM check (normalized input) -- always runs for change-point detection
+	// CUSUM check (normalized input) -- always runs for change-point detection,
+	// normalized against the seasonal bucket when one is in use
+	if seasonalReady {
+		prevMean, prevStdDev = bucket.Mean, bucket.StdDev()
+	}
 	if prevStdDev > 0 {
 		normalized := (p.Value - prevMean) / prevStdDev
 		cResult := state.CUSUM.Update(normalized)
@@ -288,7 +350,7 @@ func (m *Module) runForecast(deviceID, metricName string) {
 	ctx, cancel := context.WithTimeout(m.ctx, 10*time.Second)
 	defer cancel()
 
-	since := time.Now().Add(-m.cfg.ForecastWindow)
+	since := time.Now().Add(-m.forecastHistory())
 	points, err := m.store.GetMetricWindow(ctx, deviceID, metricName, since)
 	if err != nil || len(points) < 2 {
 		return
@@ -301,34 +363,40 @@ func (m *Module) runForecast(deviceID, metricName string) {
 		values[i] = p.Value
 	}
 
-	hours := forecast.TimeToHours(timestamps)
 	// Use a default threshold of 90% (configurable per-metric in the future)
-	result := forecast.LinearRegression(hours, values, 90.0)
-	if result == nil {
-		return
-	}
+	const threshold = 90.0
+
+	f := m.holtWintersForecast(timestamps, values, threshold)
+	if f == nil {
+		hours := forecast.TimeToHours(timestamps)
+		result := forecast.LinearRegression(hours, values, threshold)
+		if result == nil {
+			return
+		}
 
-	f := &analytics.Forecast{
-		DeviceID:       deviceID,
-		MetricName:     metricName,
-		CurrentValue:   values[len(values)-1],
-		PredictedValue: result.Predicted,
-		Threshold:      90.0,
-		Slope:          result.Slope,
-		Confidence:     result.RSquared,
-		GeneratedAt:    time.Now(),
-	}
-	if result.TimeToLimit != nil {
-		d := *result.TimeToLimit
-		f.TimeToThreshold = &d
+		f = &analytics.Forecast{
+			CurrentValue:   values[len(values)-1],
+			PredictedValue: result.Predicted,
+			Slope:          result.Slope,
+			Confidence:     result.RSquared,
+			Algorithm:      ForecastLinear,
+		}
+		if result.TimeToLimit != nil {
+			d := *result.TimeToLimit
+			f.TimeToThreshold = &d
+		}
 	}
+	f.DeviceID = deviceID
+	f.MetricName = metricName
+	f.Threshold = threshold
+	f.GeneratedAt = time.Now()
 
 	if err := m.store.UpsertForecast(ctx, f); err != nil {
 		m.logger.Warn("failed to store forecast", zap.Error(err))
 	}
 
 	// Publish warning if threshold will be reached within forecast window
-	if result.TimeToLimit != nil && *result.TimeToLimit < m.cfg.ForecastWindow && m.bus != nil {
+	if f.TimeToThreshold != nil && *f.TimeToThreshold < m.cfg.ForecastWindow && m.bus != nil {
 		m.bus.PublishAsync(m.ctx, plugin.Event{
 			Topic:   TopicForecastWarning,
 			Source:  "insight",
@@ -337,14 +405,74 @@ func (m *Module) runForecast(deviceID, metricName string) {
 	}
 }
 
-func (m *Module) handleAlertTriggered(_ context.Context, event plugin.Event) {
+// forecastHistory returns how much metric history forecasts use: the
+// forecast window, extended for Holt-Winters to cover the two seasons that
+// initialize the model plus one more to fit it.
+func (m *Module) forecastHistory() time.Duration {
+	history := m.cfg.ForecastWindow
+	if m.cfg.ForecastAlgorithm == ForecastHoltWinters {
+		history = max(history, 3*time.Duration(m.cfg.ForecastSeasonLen)*m.cfg.ForecastStep)
+	}
+	return history
+}
+
+// holtWintersForecast forecasts a metric with Holt-Winters when that is the
+// configured algorithm, predicting one point per ForecastStep across the
+// forecast window. Returns nil when Holt-Winters is not configured or there
+// is too little history, so the caller falls back to linear regression.
+// Device, metric, threshold, and generation time are left for the caller.
+func (m *Module) holtWintersForecast(timestamps []time.Time, values []float64, threshold float64) *analytics.Forecast {
+	if m.cfg.ForecastAlgorithm != ForecastHoltWinters {
+		return nil
+	}
+	step := m.cfg.ForecastStep
+	start, series := forecast.Resample(timestamps, values, step)
+	result := forecast.HoltWinters(series, forecast.HoltWintersParams{
+		Alpha:      m.cfg.HWAlpha,
+		Beta:       m.cfg.HWBeta,
+		Gamma:      m.cfg.HWGamma,
+		SeasonLen:  m.cfg.ForecastSeasonLen,
+		Horizon:    int(m.cfg.ForecastWindow / step),
+		Confidence: m.cfg.HWConfidence,
+	})
+	if result == nil {
+		return nil
+	}
+
+	f := &analytics.Forecast{
+		CurrentValue:   values[len(values)-1],
+		PredictedValue: result.Fitted,
+		Slope:          result.Trend / step.Hours(),
+		Confidence:     result.RSquared,
+		Algorithm:      ForecastHoltWinters,
+		Points:         make([]analytics.ForecastPoint, len(result.Predicted)),
+	}
+	last := start.Add(time.Duration(len(series)-1) * step)
+	rising := result.Fitted < threshold
+	for i, v := range result.Predicted {
+		ahead := time.Duration(i+1) * step
+		f.Points[i] = analytics.ForecastPoint{
+			Timestamp: last.Add(ahead),
+			Value:     v,
+			Lower:     result.Lower[i],
+			Upper:     result.Upper[i],
+		}
+		// Time to the first predicted crossing of the threshold
+		if f.TimeToThreshold == nil && (rising && v >= threshold || !rising && v <= threshold) {
+			f.TimeToThreshold = &ahead
+		}
+	}
+	return f
+}
+
+func (m *Module) handleAlertTriggered(ctx context.Context, event plugin.Event) {
 	m.logger.Debug("received alert triggered event", zap.String("source", event.Source))
-	// Alert correlation will use topology data from Recon when Pulse ships.
-	// For now, store the alert context for future correlation.
+	m.correlateTopology(ctx)
 }
 
-func (m *Module) handleAlertResolved(_ context.Context, event plugin.Event) {
+func (m *Module) handleAlertResolved(ctx context.Context, event plugin.Event) {
 	m.logger.Debug("received alert resolved event", zap.String("source", event.Source))
+	m.correlateTopology(ctx)
 }
 
 func (m *Module) handleDeviceDiscovered(_ context.Context, event plugin.Event) {
@@ -2,11 +2,13 @@ package insight
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/config"
 	"github.com/HerbHall/subnetree/internal/store"
+	"github.com/HerbHall/subnetree/pkg/analytics"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"github.com/HerbHall/subnetree/pkg/plugin/plugintest"
 	"github.com/HerbHall/subnetree/pkg/roles"
@@ -187,3 +189,154 @@ func TestAnalyticsProvider_EmptyResults(t *testing.T) {
 		t.Errorf("Forecasts() = %v, want nil (empty)", forecasts)
 	}
 }
+
+func TestInit_UnknownBaselineAlgorithm(t *testing.T) {
+	v := viper.New()
+	v.Set("baseline_algorithm", "magic")
+
+	m := New()
+	err := m.Init(context.Background(), plugin.Dependencies{
+		Logger: zap.NewNop(),
+		Config: config.New(v),
+	})
+	if err == nil {
+		t.Fatal("Init() with unknown baseline_algorithm: expected error")
+	}
+}
+
+func TestInit_InvalidForecastConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		want     string
+	}{
+		{"unknown algorithm", map[string]any{"forecast_algorithm": "arima"}, "forecast_algorithm"},
+		{"zero step", map[string]any{"forecast_algorithm": ForecastHoltWinters, "forecast_step": "0s"}, "forecast_step"},
+		{"negative step", map[string]any{"forecast_algorithm": ForecastHoltWinters, "forecast_step": "-1h"}, "forecast_step"},
+		{"season too short", map[string]any{"forecast_algorithm": ForecastHoltWinters, "forecast_season_len": 1}, "forecast_season_len"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := viper.New()
+			for k, val := range tt.settings {
+				v.Set(k, val)
+			}
+
+			m := New()
+			err := m.Init(context.Background(), plugin.Dependencies{
+				Logger: zap.NewNop(),
+				Config: config.New(v),
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Init() error = %v, want error mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+// seasonalModule starts a module using the seasonal baseline algorithm.
+func seasonalModule(t *testing.T, db *store.SQLiteStore) *Module {
+	t.Helper()
+	v := viper.New()
+	v.Set("baseline_algorithm", AlgorithmSeasonal)
+
+	m := New()
+	err := m.Init(context.Background(), plugin.Dependencies{
+		Logger: zap.NewNop(),
+		Config: config.New(v),
+		Store:  db,
+	})
+	if err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = m.Stop(context.Background()) })
+	return m
+}
+
+func TestProcessMetric_SeasonalBaseline(t *testing.T) {
+	db, err := store.New(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	m := seasonalModule(t, db)
+	ctx := context.Background()
+
+	// Two weeks of 10-minute CPU samples idling around 20%, with a nightly
+	// backup pushing it to ~90% from 02:00 to 02:59.
+	feed := func(at time.Time, value float64) {
+		m.processMetric(&analytics.MetricPoint{
+			DeviceID: "nas-01", MetricName: "cpu", Value: value, Timestamp: at,
+		})
+	}
+	start := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	at := start
+	for i := 0; at.Before(start.AddDate(0, 0, 14)); i++ {
+		v := 20 + float64(i%5) - 2
+		if at.Hour() == 2 {
+			v += 70
+		}
+		feed(at, v)
+		at = at.Add(10 * time.Minute)
+	}
+
+	anomalyCount := func() int {
+		t.Helper()
+		anomalies, err := m.store.ListAnomalies(ctx, "nas-01", 10000)
+		if err != nil {
+			t.Fatalf("ListAnomalies: %v", err)
+		}
+		return len(anomalies)
+	}
+
+	// The next night's backup is expected.
+	before := anomalyCount()
+	night := start.AddDate(0, 0, 14).Add(2 * time.Hour)
+	for i, v := range []float64{89, 90, 91, 92, 88, 90} {
+		feed(night.Add(time.Duration(i)*10*time.Minute), v)
+	}
+	if got := anomalyCount() - before; got != 0 {
+		t.Errorf("scheduled spike produced %d anomalies, want 0", got)
+	}
+
+	// The same load in the afternoon is not.
+	feed(night.Add(12*time.Hour), 90)
+	anomalies, _ := m.store.ListAnomalies(ctx, "nas-01", anomalyCount()-before)
+	var seasonal *analytics.Anomaly
+	for i := range anomalies {
+		if anomalies[i].Type == AlgorithmSeasonal {
+			seasonal = &anomalies[i]
+		}
+	}
+	if seasonal == nil {
+		t.Fatalf("off-schedule spike not flagged by the seasonal baseline: %+v", anomalies)
+	}
+	if seasonal.Expected > 25 {
+		t.Errorf("Expected = %.1f, want the afternoon bucket mean (~20)", seasonal.Expected)
+	}
+
+	// Buckets survive a restart.
+	m.persistBaselines(ctx)
+	baselines, err := m.store.GetBaselines(ctx, "nas-01")
+	if err != nil {
+		t.Fatalf("GetBaselines: %v", err)
+	}
+	var found bool
+	for _, b := range baselines {
+		if b.Algorithm == AlgorithmSeasonal {
+			found = b.MetricName == "cpu:seasonal" && b.Stable
+		}
+	}
+	if !found {
+		t.Errorf("seasonal baseline not persisted as stable: %+v", baselines)
+	}
+
+	restarted := seasonalModule(t, db)
+	state := restarted.states.getOrCreate("nas-01", "cpu")
+	if got, want := state.Seasonal.Expected(night), m.states.getOrCreate("nas-01", "cpu").Seasonal.Expected(night); got != want {
+		t.Errorf("restored bucket = %+v, want %+v", got, want)
+	}
+}
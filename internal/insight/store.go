@@ -5,8 +5,10 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/insight/baseline"
 	"github.com/HerbHall/subnetree/pkg/analytics"
 )
 
@@ -70,6 +72,75 @@ func (s *InsightStore) GetBaselines(ctx context.Context, deviceID string) ([]ana
 	return baselines, rows.Err()
 }
 
+// BaselineBucket is one persisted bucket of a seasonal baseline. Period is
+// the model's bucket count (24 hourly, 168 weekly), so buckets from a model
+// with a different period are not mixed up.
+type BaselineBucket struct {
+	DeviceID   string
+	MetricName string
+	Period     int
+	Bucket     int
+	baseline.SeasonalBucket
+}
+
+// UpsertBaselineBuckets stores the populated buckets of a seasonal baseline.
+func (s *InsightStore) UpsertBaselineBuckets(ctx context.Context, deviceID, metricName string, model *baseline.Seasonal) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO analytics_baseline_buckets (
+			device_id, metric_name, period, bucket, mean, variance, samples
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare baseline bucket upsert: %w", err)
+	}
+	defer stmt.Close()
+
+	period := len(model.Buckets)
+	for i := range model.Buckets {
+		b := &model.Buckets[i]
+		if b.Samples == 0 {
+			continue
+		}
+		if _, err := stmt.ExecContext(ctx, deviceID, metricName, period, i, b.Mean, b.Var, b.Samples); err != nil {
+			return fmt.Errorf("upsert baseline bucket: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListBaselineBuckets returns all persisted seasonal baseline buckets with
+// the given period.
+func (s *InsightStore) ListBaselineBuckets(ctx context.Context, period int) ([]BaselineBucket, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT device_id, metric_name, period, bucket, mean, variance, samples
+		FROM analytics_baseline_buckets WHERE period = ?
+		ORDER BY device_id, metric_name, bucket`,
+		period,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list baseline buckets: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []BaselineBucket
+	for rows.Next() {
+		var b BaselineBucket
+		if err := rows.Scan(
+			&b.DeviceID, &b.MetricName, &b.Period, &b.Bucket,
+			&b.Mean, &b.Var, &b.Samples,
+		); err != nil {
+			return nil, fmt.Errorf("scan baseline bucket row: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
 // -- Anomalies --
 
 // InsertAnomaly inserts a new anomaly record.
@@ -170,13 +241,27 @@ func (s *InsightStore) UpsertForecast(ctx context.Context, f *analytics.Forecast
 		secs := int64(f.TimeToThreshold.Seconds())
 		thresholdSecs = &secs
 	}
-	_, err := s.db.ExecContext(ctx, `
+	algorithm := f.Algorithm
+	if algorithm == "" {
+		algorithm = ForecastLinear
+	}
+	points := f.Points
+	if points == nil {
+		points = []analytics.ForecastPoint{}
+	}
+	pointsJSON, err := json.Marshal(points)
+	if err != nil {
+		return fmt.Errorf("marshal forecast points: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
 		INSERT OR REPLACE INTO analytics_forecasts (
 			device_id, metric_name, current_value, predicted_value,
-			threshold, slope, confidence, time_to_threshold_secs, generated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			threshold, slope, confidence, time_to_threshold_secs, generated_at,
+			algorithm, points
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		f.DeviceID, f.MetricName, f.CurrentValue, f.PredictedValue,
 		f.Threshold, f.Slope, f.Confidence, thresholdSecs, f.GeneratedAt,
+		algorithm, string(pointsJSON),
 	)
 	if err != nil {
 		return fmt.Errorf("upsert forecast: %w", err)
@@ -188,7 +273,8 @@ func (s *InsightStore) UpsertForecast(ctx context.Context, f *analytics.Forecast
 func (s *InsightStore) GetForecasts(ctx context.Context, deviceID string) ([]analytics.Forecast, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT device_id, metric_name, current_value, predicted_value,
-			threshold, slope, confidence, time_to_threshold_secs, generated_at
+			threshold, slope, confidence, time_to_threshold_secs, generated_at,
+			algorithm, points
 		FROM analytics_forecasts WHERE device_id = ?`,
 		deviceID,
 	)
@@ -201,12 +287,20 @@ func (s *InsightStore) GetForecasts(ctx context.Context, deviceID string) ([]ana
 	for rows.Next() {
 		var f analytics.Forecast
 		var thresholdSecs sql.NullInt64
+		var pointsJSON string
 		if err := rows.Scan(
 			&f.DeviceID, &f.MetricName, &f.CurrentValue, &f.PredictedValue,
 			&f.Threshold, &f.Slope, &f.Confidence, &thresholdSecs, &f.GeneratedAt,
+			&f.Algorithm, &pointsJSON,
 		); err != nil {
 			return nil, fmt.Errorf("scan forecast row: %w", err)
 		}
+		if err := json.Unmarshal([]byte(pointsJSON), &f.Points); err != nil {
+			return nil, fmt.Errorf("unmarshal forecast points: %w", err)
+		}
+		if len(f.Points) == 0 {
+			f.Points = nil
+		}
 		if thresholdSecs.Valid {
 			d := time.Duration(thresholdSecs.Int64) * time.Second
 			f.TimeToThreshold = &d
@@ -226,9 +320,9 @@ func (s *InsightStore) InsertCorrelation(ctx context.Context, g *analytics.Alert
 	}
 	_, err = s.db.ExecContext(ctx, `
 		INSERT INTO analytics_correlations (
-			id, root_cause, device_ids, alert_count, description, created_at
-		) VALUES (?, ?, ?, ?, ?, ?)`,
-		g.ID, g.RootCause, string(deviceIDsJSON), g.AlertCount, g.Description, g.CreatedAt,
+			id, root_cause, root_cause_hint, device_ids, alert_count, description, created_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		g.ID, g.RootCause, g.RootCauseHint, string(deviceIDsJSON), g.AlertCount, g.Description, g.CreatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert correlation: %w", err)
@@ -236,10 +330,60 @@ func (s *InsightStore) InsertCorrelation(ctx context.Context, g *analytics.Alert
 	return nil
 }
 
+// ReplaceCorrelations makes groups the active correlations whose IDs start
+// with prefix. A group whose ID is already active is updated in place and
+// keeps its created_at; active correlations with the prefix that are not
+// in groups are resolved at now.
+func (s *InsightStore) ReplaceCorrelations(ctx context.Context, prefix string, groups []analytics.AlertGroup, now time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin replace correlations: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	keep := make([]any, 0, len(groups)+2)
+	keep = append(keep, now, prefix+"%")
+	for i := range groups {
+		g := &groups[i]
+		deviceIDsJSON, err := json.Marshal(g.DeviceIDs)
+		if err != nil {
+			return fmt.Errorf("marshal device_ids: %w", err)
+		}
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO analytics_correlations (
+				id, root_cause, root_cause_hint, device_ids, alert_count, description, created_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET
+				root_cause = excluded.root_cause,
+				root_cause_hint = excluded.root_cause_hint,
+				device_ids = excluded.device_ids,
+				alert_count = excluded.alert_count,
+				description = excluded.description,
+				created_at = CASE WHEN resolved_at IS NULL THEN created_at ELSE excluded.created_at END,
+				resolved_at = NULL`,
+			g.ID, g.RootCause, g.RootCauseHint, string(deviceIDsJSON), g.AlertCount, g.Description, g.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("upsert correlation: %w", err)
+		}
+		keep = append(keep, g.ID)
+	}
+
+	query := `UPDATE analytics_correlations SET resolved_at = ?
+		WHERE id LIKE ? AND resolved_at IS NULL`
+	if len(groups) > 0 {
+		query += ` AND id NOT IN (?` + strings.Repeat(", ?", len(groups)-1) + `)`
+	}
+	if _, err := tx.ExecContext(ctx, query, keep...); err != nil {
+		return fmt.Errorf("resolve stale correlations: %w", err)
+	}
+	return tx.Commit()
+}
+
 // ListActiveCorrelations returns correlation groups that have not been resolved.
 func (s *InsightStore) ListActiveCorrelations(ctx context.Context) ([]analytics.AlertGroup, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, root_cause, device_ids, alert_count, description, created_at
+		SELECT id, root_cause, root_cause_hint, device_ids, alert_count, description, created_at
 		FROM analytics_correlations WHERE resolved_at IS NULL ORDER BY created_at DESC`,
 	)
 	if err != nil {
@@ -252,7 +396,7 @@ func (s *InsightStore) ListActiveCorrelations(ctx context.Context) ([]analytics.
 		var g analytics.AlertGroup
 		var deviceIDsJSON string
 		if err := rows.Scan(
-			&g.ID, &g.RootCause, &deviceIDsJSON, &g.AlertCount,
+			&g.ID, &g.RootCause, &g.RootCauseHint, &deviceIDsJSON, &g.AlertCount,
 			&g.Description, &g.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan correlation row: %w", err)
@@ -268,7 +412,7 @@ func (s *InsightStore) ListActiveCorrelations(ctx context.Context) ([]analytics.
 // ListDeviceCorrelations returns active correlation groups involving the given device.
 func (s *InsightStore) ListDeviceCorrelations(ctx context.Context, deviceID string) ([]analytics.AlertGroup, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, root_cause, device_ids, alert_count, description, created_at
+		SELECT id, root_cause, root_cause_hint, device_ids, alert_count, description, created_at
 		FROM analytics_correlations
 		WHERE resolved_at IS NULL
 			AND (root_cause = ? OR device_ids LIKE '%' || ? || '%')
@@ -285,7 +429,7 @@ func (s *InsightStore) ListDeviceCorrelations(ctx context.Context, deviceID stri
 		var g analytics.AlertGroup
 		var deviceIDsJSON string
 		if err := rows.Scan(
-			&g.ID, &g.RootCause, &deviceIDsJSON, &g.AlertCount,
+			&g.ID, &g.RootCause, &g.RootCauseHint, &deviceIDsJSON, &g.AlertCount,
 			&g.Description, &g.CreatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan correlation row: %w", err)
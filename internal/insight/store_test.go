@@ -467,6 +467,47 @@ func TestUpsertForecast_NilTimeToThreshold(t *testing.T) {
 	}
 }
 
+func TestUpsertForecast_HoltWintersPoints(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second).UTC()
+	f := &analytics.Forecast{
+		DeviceID:       "dev-001",
+		MetricName:     "cpu_usage",
+		CurrentValue:   40.0,
+		PredictedValue: 41.0,
+		Threshold:      90.0,
+		Algorithm:      ForecastHoltWinters,
+		Points: []analytics.ForecastPoint{
+			{Timestamp: now.Add(time.Hour), Value: 42, Lower: 38, Upper: 46},
+			{Timestamp: now.Add(2 * time.Hour), Value: 44, Lower: 38, Upper: 50},
+		},
+		GeneratedAt: now,
+	}
+	if err := s.UpsertForecast(ctx, f); err != nil {
+		t.Fatalf("UpsertForecast: %v", err)
+	}
+
+	forecasts, err := s.GetForecasts(ctx, "dev-001")
+	if err != nil {
+		t.Fatalf("GetForecasts: %v", err)
+	}
+	if len(forecasts) != 1 {
+		t.Fatalf("expected 1 forecast, got %d", len(forecasts))
+	}
+	got := forecasts[0]
+	if got.Algorithm != ForecastHoltWinters {
+		t.Errorf("Algorithm = %q, want %q", got.Algorithm, ForecastHoltWinters)
+	}
+	if len(got.Points) != 2 {
+		t.Fatalf("len(Points) = %d, want 2", len(got.Points))
+	}
+	if p := got.Points[1]; p.Value != 44 || p.Lower != 38 || p.Upper != 50 || !p.Timestamp.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("Points[1] = %+v", p)
+	}
+}
+
 // -- Correlations --
 
 func TestInsertCorrelation_AndList(t *testing.T) {
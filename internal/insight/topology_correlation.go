@@ -0,0 +1,100 @@
+package insight
+
+import (
+	"context"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/insight/correlation"
+	"github.com/HerbHall/subnetree/pkg/analytics"
+	"github.com/HerbHall/subnetree/pkg/roles"
+	"go.uber.org/zap"
+)
+
+// topologyCorrelationPrefix marks correlation groups produced by the
+// topology pass. Their IDs are derived from the root-cause device so a
+// group keeps its ID while the same device stays at fault.
+const topologyCorrelationPrefix = "topology-"
+
+// topologyAlertLimit caps how many active alerts a pass considers.
+const topologyAlertLimit = 1000
+
+// correlateTopology groups the active monitoring alerts by the network
+// hierarchy Recon inferred (parent devices and network layers) and stores
+// one correlation per group, annotated with its probable root-cause device.
+// Groups that no longer apply are resolved. It does nothing when either
+// the monitoring or the discovery plugin is unavailable.
+func (m *Module) correlateTopology(ctx context.Context) {
+	if m.store == nil || m.plugins == nil {
+		return
+	}
+	mp := monitoringHistory(m.plugins)
+	if mp == nil {
+		return
+	}
+	discovery := m.plugins.ResolveByRole(roles.RoleDiscovery)
+	if len(discovery) == 0 {
+		return
+	}
+	dp, ok := discovery[0].(roles.DiscoveryProvider)
+	if !ok {
+		return
+	}
+
+	// Serialize passes so concurrent alert events can't interleave their
+	// replace operations.
+	m.correlateMu.Lock()
+	defer m.correlateMu.Unlock()
+
+	active, err := mp.Alerts(ctx, "", true, topologyAlertLimit)
+	if err != nil {
+		m.logger.Warn("topology correlation: failed to list active alerts", zap.Error(err))
+		return
+	}
+	devices, err := dp.Devices(ctx)
+	if err != nil {
+		m.logger.Warn("topology correlation: failed to list devices", zap.Error(err))
+		return
+	}
+
+	alerts := make([]correlation.Alert, 0, len(active))
+	for i := range active {
+		alerts = append(alerts, correlation.Alert{
+			DeviceID:  active[i].DeviceID,
+			Metric:    active[i].Message,
+			Timestamp: active[i].TriggeredAt,
+		})
+	}
+	nodes := make([]correlation.DeviceNode, 0, len(devices))
+	for i := range devices {
+		d := &devices[i]
+		nodes = append(nodes, correlation.DeviceNode{
+			ID:       d.ID,
+			Name:     d.Hostname,
+			ParentID: d.ParentDeviceID,
+			Layer:    d.NetworkLayer,
+		})
+	}
+
+	now := time.Now().UTC()
+	found := correlation.CorrelateHierarchy(alerts, nodes)
+	groups := make([]analytics.AlertGroup, 0, len(found))
+	for i := range found {
+		g := &found[i]
+		groups = append(groups, analytics.AlertGroup{
+			ID:            topologyCorrelationPrefix + g.RootCause,
+			RootCause:     g.RootCause,
+			RootCauseHint: g.RootCauseHint,
+			DeviceIDs:     g.DeviceIDs,
+			AlertCount:    len(g.Alerts),
+			CreatedAt:     now,
+			Description:   correlation.Describe(*g, nodes),
+		})
+	}
+	if err := m.store.ReplaceCorrelations(ctx, topologyCorrelationPrefix, groups, now); err != nil {
+		m.logger.Warn("topology correlation: failed to store groups", zap.Error(err))
+		return
+	}
+	if len(groups) > 0 {
+		m.logger.Debug("topology correlation updated", zap.Int("groups", len(groups)))
+	}
+}
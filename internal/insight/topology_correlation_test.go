@@ -0,0 +1,83 @@
+package insight
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/insight/correlation"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"github.com/HerbHall/subnetree/pkg/roles"
+)
+
+func TestCorrelateTopology_GatewayRootCause(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC()
+	monitor := &mockMonitoringPlugin{alerts: []roles.MonitorAlert{
+		{ID: "a1", DeviceID: "gw", Message: "gateway unreachable", TriggeredAt: now},
+		{ID: "a2", DeviceID: "pc1", Message: "pc1 unreachable", TriggeredAt: now},
+		{ID: "a3", DeviceID: "pc2", Message: "pc2 unreachable", TriggeredAt: now},
+	}}
+	discovery := &mockDiscoveryPlugin{devices: []models.Device{
+		{ID: "gw", Hostname: "gateway", NetworkLayer: models.NetworkLayerGateway},
+		{ID: "pc1", Hostname: "pc1", ParentDeviceID: "gw", NetworkLayer: models.NetworkLayerEndpoint},
+		{ID: "pc2", Hostname: "pc2", ParentDeviceID: "gw", NetworkLayer: models.NetworkLayerEndpoint},
+	}}
+	m.plugins = &mockPluginResolver{byRole: map[string][]plugin.Plugin{
+		roles.RoleMonitoring: {monitor},
+		roles.RoleDiscovery:  {discovery},
+	}}
+
+	m.correlateTopology(ctx)
+
+	groups, err := m.store.ListActiveCorrelations(ctx)
+	if err != nil {
+		t.Fatalf("ListActiveCorrelations: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 correlation, got %d", len(groups))
+	}
+	g := groups[0]
+	if g.RootCause != "gw" {
+		t.Errorf("RootCause = %q, want gw", g.RootCause)
+	}
+	if g.RootCauseHint != correlation.HintAlertingAncestor {
+		t.Errorf("RootCauseHint = %q, want %q", g.RootCauseHint, correlation.HintAlertingAncestor)
+	}
+	if g.AlertCount != 3 || len(g.DeviceIDs) != 3 {
+		t.Errorf("AlertCount = %d, DeviceIDs = %v, want 3 alerts on 3 devices", g.AlertCount, g.DeviceIDs)
+	}
+
+	// Re-running with the same alerts keeps a single group.
+	m.correlateTopology(ctx)
+	if groups, _ = m.store.ListActiveCorrelations(ctx); len(groups) != 1 {
+		t.Fatalf("after second pass expected 1 correlation, got %d", len(groups))
+	}
+
+	// Once the alerts resolve the group is no longer active.
+	for i := range monitor.alerts {
+		monitor.alerts[i].ResolvedAt = &now
+	}
+	m.correlateTopology(ctx)
+	if groups, _ = m.store.ListActiveCorrelations(ctx); len(groups) != 0 {
+		t.Errorf("after resolution expected no correlations, got %d", len(groups))
+	}
+}
+
+func TestCorrelateTopology_NoPlugins(t *testing.T) {
+	m := newTestModule(t)
+
+	// Without discovery or monitoring plugins the pass is a no-op.
+	m.correlateTopology(context.Background())
+
+	groups, err := m.store.ListActiveCorrelations(context.Background())
+	if err != nil {
+		t.Fatalf("ListActiveCorrelations: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("expected no correlations, got %d", len(groups))
+	}
+}
@@ -3,6 +3,7 @@ package pulse
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -10,27 +11,86 @@ import (
 	"go.uber.org/zap"
 )
 
-// Alerter tracks consecutive check failures and manages alert lifecycle.
+// Alerter tracks consecutive check failures and successes and manages alert lifecycle.
 type Alerter struct {
-	store       *PulseStore
-	bus         plugin.EventBus
-	threshold   int
-	logger      *zap.Logger
-	correlation *CorrelationEngine
+	store            *PulseStore
+	bus              plugin.EventBus
+	threshold        int
+	resolveThreshold int
+	logger           *zap.Logger
+	correlation      *CorrelationEngine
+	lowPriorityTags  []string
+	autoAckSeverity  map[string]bool
+	escalateAfter    time.Duration
+	flapWindow       time.Duration
+	now              func() time.Time
 
 	mu       sync.Mutex
-	failures map[string]int // check_id -> consecutive failure count
+	failures map[string]*checkStreak // check_id -> consecutive failure/success counts
 }
 
-// NewAlerter creates an alerter with the given consecutive failure threshold.
-func NewAlerter(store *PulseStore, bus plugin.EventBus, threshold int, logger *zap.Logger) *Alerter {
+// checkStreak holds the consecutive result counts for one check. successes
+// only counts while an alert is active and waiting to be resolved.
+type checkStreak struct {
+	failures  int
+	successes int
+}
+
+// NewAlerter creates an alerter that triggers after threshold consecutive
+// failures, unless a check sets its own FailureThreshold, and resolves
+// after resolveThreshold consecutive successes.
+// A resolveThreshold below 1 is treated as 1 (resolve on the first success).
+func NewAlerter(store *PulseStore, bus plugin.EventBus, threshold, resolveThreshold int, logger *zap.Logger) *Alerter {
+	if resolveThreshold < 1 {
+		resolveThreshold = 1
+	}
 	return &Alerter{
-		store:     store,
-		bus:       bus,
-		threshold: threshold,
-		logger:    logger,
-		failures:  make(map[string]int),
+		store:            store,
+		bus:              bus,
+		threshold:        threshold,
+		resolveThreshold: resolveThreshold,
+		logger:           logger,
+		now:              time.Now,
+		failures:         make(map[string]*checkStreak),
+	}
+}
+
+// SetEscalation enables time-based escalation: an alert left active and
+// unacknowledged for after is raised to the next severity tier, and again
+// after each further period until it reaches the top tier. Zero disables it.
+func (a *Alerter) SetEscalation(after time.Duration) {
+	a.escalateAfter = after
+}
+
+// SetLowPriority configures auto-acknowledgement for low-priority checks.
+// Alerts of the given severities raised by a low-priority check, or by a
+// check on a device tagged with one of tags, are created already
+// acknowledged and are not published for notification.
+func (a *Alerter) SetLowPriority(tags, severities []string) {
+	a.lowPriorityTags = tags
+	a.autoAckSeverity = make(map[string]bool, len(severities))
+	for _, sev := range severities {
+		a.autoAckSeverity[sev] = true
+	}
+}
+
+// isLowPriority reports whether alerts for check should be auto-acknowledged.
+func (a *Alerter) isLowPriority(ctx context.Context, check Check) bool {
+	if check.LowPriority {
+		return true
+	}
+	if len(a.lowPriorityTags) == 0 || check.DeviceID == "" {
+		return false
+	}
+	tagged, err := a.store.DeviceHasAnyTag(ctx, check.DeviceID, a.lowPriorityTags)
+	if err != nil {
+		a.logger.Warn("low-priority tag lookup failed, treating check as normal priority",
+			zap.String("check_id", check.ID),
+			zap.Error(err),
+		)
+		return false
 	}
+	return tagged
 }
 
 // SetCorrelation enables topology-aware alert correlation on this alerter.
@@ -50,33 +110,56 @@ func (a *Alerter) ProcessResult(ctx context.Context, check Check, result *CheckR
 	}
 }
 
-// handleSuccess resets the failure counter and resolves any active alert.
+// handleSuccess resets the failure counter and resolves any active alert
+// once resolveThreshold consecutive successes have been seen.
 func (a *Alerter) handleSuccess(ctx context.Context, check Check) {
-	delete(a.failures, check.ID)
-
 	alert, err := a.store.GetActiveAlert(ctx, check.ID)
 	if err != nil {
+		delete(a.failures, check.ID)
 		a.logger.Warn("failed to get active alert", zap.String("check_id", check.ID), zap.Error(err))
 		return
 	}
 	if alert == nil {
+		delete(a.failures, check.ID)
 		return
 	}
 
-	now := time.Now().UTC()
+	streak := a.streak(check.ID)
+	streak.failures = 0
+	streak.successes++
+	if streak.successes < a.resolveThreshold {
+		a.logger.Debug("alert resolve pending",
+			zap.String("alert_id", alert.ID),
+			zap.String("check_id", check.ID),
+			zap.Int("consecutive_successes", streak.successes),
+			zap.Int("resolve_threshold", a.resolveThreshold),
+		)
+		return
+	}
+	delete(a.failures, check.ID)
+
+	now := a.now().UTC()
 	if err := a.store.ResolveAlert(ctx, alert.ID, now); err != nil {
 		a.logger.Warn("failed to resolve alert", zap.String("alert_id", alert.ID), zap.Error(err))
 		return
 	}
 
 	alert.ResolvedAt = &now
+	if !alert.LowPriority {
+		pulseAlertActive.WithLabelValues(alert.Severity).Dec()
+	}
 	a.logger.Info("alert resolved",
 		zap.String("alert_id", alert.ID),
 		zap.String("check_id", check.ID),
 		zap.String("device_id", check.DeviceID),
 	)
 
-	if a.bus != nil {
+	// Low-priority and muted alerts were never paged, so their resolution
+	// isn't either; nor is any resolution while the check is muted.
+	if (alert.Suppressed && alert.SuppressedBy == muteSuppressBy) || checkMuted(&check, now) {
+		return
+	}
+	if a.bus != nil && !alert.LowPriority {
 		a.bus.PublishAsync(ctx, plugin.Event{
 			Topic:     TopicAlertResolved,
 			Source:    "pulse",
@@ -86,12 +169,34 @@ func (a *Alerter) handleSuccess(ctx context.Context, check Check) {
 	}
 }
 
+// streak returns the counters for a check, creating them if needed.
+func (a *Alerter) streak(checkID string) *checkStreak {
+	st, ok := a.failures[checkID]
+	if !ok {
+		st = &checkStreak{}
+		a.failures[checkID] = st
+	}
+	return st
+}
+
+// failureThreshold returns the consecutive failures needed to alert on a
+// check: its own threshold when set, otherwise the alerter default.
+func (a *Alerter) failureThreshold(check *Check) int {
+	if check.FailureThreshold != nil && *check.FailureThreshold > 0 {
+		return *check.FailureThreshold
+	}
+	return a.threshold
+}
+
 // handleFailure increments the failure counter and triggers an alert if threshold reached.
 func (a *Alerter) handleFailure(ctx context.Context, check Check, result *CheckResult) {
-	a.failures[check.ID]++
-	count := a.failures[check.ID]
+	streak := a.streak(check.ID)
+	streak.successes = 0
+	streak.failures++
+	count := streak.failures
+	threshold := a.failureThreshold(&check)
 
-	if count < a.threshold {
+	if count < threshold {
 		return
 	}
 
@@ -102,23 +207,24 @@ func (a *Alerter) handleFailure(ctx context.Context, check Check, result *CheckR
 		return
 	}
 
-	now := time.Now().UTC()
+	now := a.now().UTC()
 
 	if existing != nil {
 		// Update severity if escalation threshold reached.
-		if count >= a.threshold*2 && existing.Severity != "critical" {
+		if count >= threshold*2 && existing.Severity != "critical" {
 			a.logger.Info("alert escalated to critical",
 				zap.String("alert_id", existing.ID),
 				zap.String("check_id", check.ID),
 				zap.Int("consecutive_failures", count),
 			)
 		}
+		a.releaseSuppression(ctx, check, existing, now)
 		return
 	}
 
 	// Determine severity.
 	severity := "warning"
-	if count >= a.threshold*2 {
+	if count >= threshold*2 {
 		severity = "critical"
 	}
 
@@ -137,17 +243,25 @@ func (a *Alerter) handleFailure(ctx context.Context, check Check, result *CheckR
 		ConsecutiveFailures: count,
 	}
 
-	// Check if this alert should be suppressed due to an upstream device failure.
-	suppressed, byDevice, suppErr := a.store.IsSuppressed(ctx, check.ID)
-	if suppErr != nil {
-		a.logger.Warn("suppression check failed, proceeding with alert",
-			zap.String("check_id", check.ID),
-			zap.Error(suppErr),
-		)
-	}
-	if suppressed {
+	// A dependent check stays quiet while its parent check is alerting.
+	if a.parentAlerting(ctx, check) {
 		alert.Suppressed = true
-		alert.SuppressedBy = byDevice
+		alert.SuppressedBy = parentCheckSuppressPrefix + check.ParentCheckID
+	}
+
+	// Check if this alert should be suppressed due to an upstream device failure.
+	if !alert.Suppressed {
+		suppressed, byDevice, suppErr := a.store.IsSuppressed(ctx, check.ID)
+		if suppErr != nil {
+			a.logger.Warn("suppression check failed, proceeding with alert",
+				zap.String("check_id", check.ID),
+				zap.Error(suppErr),
+			)
+		}
+		if suppressed {
+			alert.Suppressed = true
+			alert.SuppressedBy = byDevice
+		}
 	}
 
 	// Check topology-aware correlation if not already suppressed.
@@ -165,10 +279,50 @@ func (a *Alerter) handleFailure(ctx context.Context, check Check, result *CheckR
 		}
 	}
 
-	if err := a.store.InsertAlert(ctx, alert); err != nil {
+	// A muted check still records its alert, but nothing is published
+	// until the mute expires.
+	if !alert.Suppressed && checkMuted(&check, now) {
+		alert.Suppressed = true
+		alert.SuppressedBy = muteSuppressBy
+	}
+
+	// Low-priority checks still record their alerts, but pre-acknowledged
+	// so they stay out of the active view and paging.
+	if a.autoAckSeverity[severity] && a.isLowPriority(ctx, check) {
+		alert.LowPriority = true
+		alert.AcknowledgedAt = &now
+	}
+
+	if err := a.recordAlert(ctx, alert); err != nil {
 		a.logger.Warn("failed to insert alert", zap.String("check_id", check.ID), zap.Error(err))
 		return
 	}
+	if !alert.LowPriority {
+		pulseAlertActive.WithLabelValues(alert.Severity).Inc()
+	}
+	if !alert.Suppressed {
+		pulseAlertsTriggeredTotal.WithLabelValues(alert.Severity).Inc()
+	}
+
+	if alert.LowPriority {
+		a.logger.Info("low-priority alert auto-acknowledged",
+			zap.String("alert_id", alert.ID),
+			zap.String("check_id", check.ID),
+			zap.String("device_id", check.DeviceID),
+			zap.String("severity", severity),
+		)
+		return
+	}
+
+	if alert.SuppressedBy == muteSuppressBy {
+		a.logger.Info("alert muted",
+			zap.String("alert_id", alert.ID),
+			zap.String("check_id", check.ID),
+			zap.String("device_id", check.DeviceID),
+			zap.Time("muted_until", *check.MutedUntil),
+		)
+		return
+	}
 
 	if alert.Suppressed {
 		a.logger.Info("alert suppressed",
@@ -206,3 +360,162 @@ func (a *Alerter) handleFailure(ctx context.Context, check Check, result *CheckR
 		})
 	}
 }
+
+// parentCheckSuppressPrefix marks SuppressedBy values that name a parent
+// check rather than an upstream device.
+const parentCheckSuppressPrefix = "check:"
+
+// parentAlerting reports whether check depends on a parent check that
+// currently has an active alert.
+func (a *Alerter) parentAlerting(ctx context.Context, check Check) bool {
+	if check.ParentCheckID == "" {
+		return false
+	}
+	parent, err := a.store.GetActiveAlert(ctx, check.ParentCheckID)
+	if err != nil {
+		a.logger.Warn("parent alert lookup failed, proceeding with alert",
+			zap.String("check_id", check.ID),
+			zap.String("parent_check_id", check.ParentCheckID),
+			zap.Error(err),
+		)
+		return false
+	}
+	return parent != nil
+}
+
+// muteSuppressBy marks alerts raised while their check was muted.
+const muteSuppressBy = "mute"
+
+// checkMuted reports whether check's notifications are muted at now.
+func checkMuted(check *Check, now time.Time) bool {
+	return check.MutedUntil != nil && now.Before(*check.MutedUntil)
+}
+
+// releaseSuppression emits an alert that was held back by its parent check
+// or by a mute once that no longer applies and the check is still failing.
+func (a *Alerter) releaseSuppression(ctx context.Context, check Check, alert *Alert, now time.Time) {
+	if !alert.Suppressed {
+		return
+	}
+	switch {
+	case alert.SuppressedBy == muteSuppressBy:
+		if checkMuted(&check, now) {
+			return
+		}
+	case strings.HasPrefix(alert.SuppressedBy, parentCheckSuppressPrefix):
+		if a.parentAlerting(ctx, check) {
+			return
+		}
+	default:
+		return
+	}
+	if err := a.store.UnsuppressAlert(ctx, alert.ID); err != nil {
+		a.logger.Warn("failed to unsuppress alert", zap.String("alert_id", alert.ID), zap.Error(err))
+		return
+	}
+	releasedFrom := alert.SuppressedBy
+	alert.Suppressed = false
+	alert.SuppressedBy = ""
+	if alert.LowPriority {
+		return
+	}
+	pulseAlertsTriggeredTotal.WithLabelValues(alert.Severity).Inc()
+
+	a.logger.Warn("alert triggered after suppression was released",
+		zap.String("alert_id", alert.ID),
+		zap.String("check_id", check.ID),
+		zap.String("released_from", releasedFrom),
+		zap.String("severity", alert.Severity),
+	)
+
+	if a.bus != nil {
+		a.bus.PublishAsync(ctx, plugin.Event{
+			Topic:     TopicAlertTriggered,
+			Source:    "pulse",
+			Timestamp: now,
+			Payload:   alert,
+		})
+	}
+}
+
+// escalationTiers lists alert severities from lowest to highest. Escalation
+// moves an alert one step along it.
+var escalationTiers = []string{"warning", "critical"}
+
+// nextSeverity returns the tier above severity, or "" if severity is the
+// top tier or not a tier.
+func nextSeverity(severity string) string {
+	for i, tier := range escalationTiers[:len(escalationTiers)-1] {
+		if tier == severity {
+			return escalationTiers[i+1]
+		}
+	}
+	return ""
+}
+
+// Escalate raises every alert that has stayed active and unacknowledged at
+// its severity for longer than the escalation period to the next tier, and
+// publishes TopicAlertEscalated for each so it is notified again. Since the
+// period restarts at each escalation, an alert moves up at most one tier per
+// period. Suppressed and low-priority alerts, and alerts of muted checks, are
+// not escalated. Returns the number of alerts escalated.
+func (a *Alerter) Escalate(ctx context.Context) int {
+	if a.escalateAfter <= 0 {
+		return 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now().UTC()
+	cutoff := now.Add(-a.escalateAfter)
+	escalated := 0
+	for _, from := range escalationTiers {
+		to := nextSeverity(from)
+		if to == "" {
+			continue
+		}
+		candidates, err := a.store.ListEscalationCandidates(ctx, from, cutoff)
+		if err != nil {
+			a.logger.Warn("failed to list alerts for escalation", zap.String("severity", from), zap.Error(err))
+			continue
+		}
+		for i := range candidates {
+			alert := &candidates[i]
+			if check, err := a.store.GetCheck(ctx, alert.CheckID); err == nil && check != nil && checkMuted(check, now) {
+				continue
+			}
+			ok, err := a.store.EscalateAlert(ctx, alert.ID, from, to, now)
+			if err != nil {
+				a.logger.Warn("failed to escalate alert", zap.String("alert_id", alert.ID), zap.Error(err))
+				continue
+			}
+			if !ok {
+				// Acknowledged or resolved since it was listed.
+				continue
+			}
+			escalated++
+			alert.Severity = to
+			alert.EscalatedAt = &now
+			pulseAlertActive.WithLabelValues(from).Dec()
+			pulseAlertActive.WithLabelValues(to).Inc()
+
+			a.logger.Warn("alert escalated",
+				zap.String("alert_id", alert.ID),
+				zap.String("check_id", alert.CheckID),
+				zap.String("device_id", alert.DeviceID),
+				zap.String("from_severity", from),
+				zap.String("to_severity", to),
+			)
+
+			if a.bus != nil {
+				a.bus.PublishAsync(ctx, plugin.Event{
+					Topic:     TopicAlertEscalated,
+					Source:    "pulse",
+					Timestamp: now,
+					Payload:   alert,
+				})
+			}
+		}
+	}
+	return escalated
+}
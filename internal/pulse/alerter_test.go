@@ -73,7 +73,7 @@ func TestAlerter_SingleFailure_NoAlert(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -107,7 +107,7 @@ func TestAlerter_ConsecutiveFailures_TriggersAlert(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -166,7 +166,7 @@ func TestAlerter_Success_ResolvesAlert(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -227,7 +227,7 @@ func TestAlerter_Success_ResetsCounter(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -285,7 +285,7 @@ func TestAlerter_NoDoubleAlert(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -319,7 +319,7 @@ func TestAlerter_SeverityWarning(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -354,7 +354,7 @@ func TestAlerter_SeverityCritical_FirstAlertAfterManyFailures(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -362,7 +362,7 @@ func TestAlerter_SeverityCritical_FirstAlertAfterManyFailures(t *testing.T) {
 	// Manually set failure counter to simulate many failures before alert creation.
 	// This tests the code path where count >= threshold*2 when the alert is first created.
 	alerter.mu.Lock()
-	alerter.failures[check.ID] = threshold*2 - 1
+	alerter.failures[check.ID] = &checkStreak{failures: threshold*2 - 1}
 	alerter.mu.Unlock()
 
 	failureResult := &CheckResult{
@@ -396,7 +396,7 @@ func TestAlerter_SeverityCritical_FirstAlertAfterManyFailures(t *testing.T) {
 func TestAlerter_NilBus(t *testing.T) {
 	ps := alerterTestStore(t)
 	threshold := 3
-	alerter := NewAlerter(ps, nil, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, nil, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -446,7 +446,7 @@ func TestAlerter_MultipleChecks(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check1 := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	check2 := makeTestCheck(t, ps, "device2", "ping", "192.168.1.2")
@@ -500,7 +500,7 @@ func TestAlerter_ErrorMessage(t *testing.T) {
 	ps := alerterTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
 	ctx := context.Background()
@@ -532,3 +532,435 @@ func TestAlerter_ErrorMessage(t *testing.T) {
 		t.Errorf("alert.Message = %q, want %q", alert.Message, customError)
 	}
 }
+
+func TestAlerter_ResolveThreshold_RequiresConsecutiveSuccesses(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	threshold := 2
+	resolveThreshold := 3
+	alerter := NewAlerter(ps, bus, threshold, resolveThreshold, zap.NewNop())
+
+	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+	ctx := context.Background()
+
+	failureResult := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: time.Now().UTC()}
+	successResult := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: true, CheckedAt: time.Now().UTC()}
+
+	for i := 0; i < threshold; i++ {
+		alerter.ProcessResult(ctx, check, failureResult)
+	}
+	bus.events = nil
+
+	for i := 0; i < resolveThreshold-1; i++ {
+		alerter.ProcessResult(ctx, check, successResult)
+		alert, err := ps.GetActiveAlert(ctx, check.ID)
+		if err != nil {
+			t.Fatalf("GetActiveAlert: %v", err)
+		}
+		if alert == nil {
+			t.Fatalf("alert resolved after %d successes, want %d", i+1, resolveThreshold)
+		}
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("got %d events before resolve threshold, want 0", len(bus.events))
+	}
+
+	alerter.ProcessResult(ctx, check, successResult)
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert != nil {
+		t.Errorf("got alert = %v, want nil after %d successes", alert, resolveThreshold)
+	}
+	if len(bus.events) != 1 || bus.events[0].Topic != TopicAlertResolved {
+		t.Errorf("events = %v, want one %s event", bus.events, TopicAlertResolved)
+	}
+}
+
+func TestAlerter_ResolveThreshold_FailureResetsSuccessCount(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	threshold := 2
+	resolveThreshold := 2
+	alerter := NewAlerter(ps, bus, threshold, resolveThreshold, zap.NewNop())
+
+	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+	ctx := context.Background()
+
+	failureResult := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: time.Now().UTC()}
+	successResult := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: true, CheckedAt: time.Now().UTC()}
+
+	for i := 0; i < threshold; i++ {
+		alerter.ProcessResult(ctx, check, failureResult)
+	}
+
+	// Success, failure, success: the failure resets the success streak,
+	// so the alert must still be active.
+	alerter.ProcessResult(ctx, check, successResult)
+	alerter.ProcessResult(ctx, check, failureResult)
+	alerter.ProcessResult(ctx, check, successResult)
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("alert resolved after interrupted success streak, want still active")
+	}
+	if got := alerter.failures[check.ID].successes; got != 1 {
+		t.Errorf("successes = %d, want 1 after reset", got)
+	}
+
+	// A second consecutive success completes the streak.
+	alerter.ProcessResult(ctx, check, successResult)
+	alert, err = ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert != nil {
+		t.Errorf("got alert = %v, want nil after %d consecutive successes", alert, resolveThreshold)
+	}
+	if _, ok := alerter.failures[check.ID]; ok {
+		t.Error("counters still tracked after resolve, want cleared")
+	}
+}
+
+func TestAlerter_PerCheckFailureThreshold(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	ctx := context.Background()
+
+	// The router alerts early, the IoT device waits longer, and the
+	// unconfigured check uses the alerter default of 3.
+	router := makeTestCheck(t, ps, "router", "icmp", "10.0.0.1")
+	routerThreshold := 2
+	router.FailureThreshold = &routerThreshold
+	iot := makeTestCheck(t, ps, "iot", "icmp", "10.0.0.50")
+	iotThreshold := 10
+	iot.FailureThreshold = &iotThreshold
+	plain := makeTestCheck(t, ps, "plain", "icmp", "10.0.0.2")
+
+	firstAlertAt := map[string]int{}
+	for i := 1; i <= 10; i++ {
+		for _, check := range []Check{router, iot, plain} {
+			alerter.ProcessResult(ctx, check, &CheckResult{
+				CheckID:   check.ID,
+				DeviceID:  check.DeviceID,
+				Success:   false,
+				CheckedAt: time.Now().UTC(),
+			})
+			if _, seen := firstAlertAt[check.ID]; seen {
+				continue
+			}
+			alert, err := ps.GetActiveAlert(ctx, check.ID)
+			if err != nil {
+				t.Fatalf("GetActiveAlert(%s): %v", check.ID, err)
+			}
+			if alert != nil {
+				firstAlertAt[check.ID] = i
+			}
+		}
+	}
+
+	want := map[string]int{router.ID: 2, iot.ID: 10, plain.ID: 3}
+	for id, n := range want {
+		if got := firstAlertAt[id]; got != n {
+			t.Errorf("check %s alerted after %d failures, want %d", id, got, n)
+		}
+	}
+}
+
+// newEscalationAlerter returns an alerter with a 1h escalation period whose
+// clock reads *clock, and a check with a warning alert triggered at *clock.
+func newEscalationAlerter(t *testing.T, clock *time.Time) (*Alerter, *PulseStore, *mockEventBus, Check) {
+	t.Helper()
+	_, ps := newTestModule(t) // escalation lists join recon_devices for names
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	alerter.now = func() time.Time { return *clock }
+	alerter.SetEscalation(time.Hour)
+
+	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+	failure := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: *clock}
+	for i := 0; i < 3; i++ {
+		alerter.ProcessResult(context.Background(), check, failure)
+	}
+	bus.events = nil
+	return alerter, ps, bus, check
+}
+
+func TestAlerter_Escalate_AfterThreshold(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, bus, check := newEscalationAlerter(t, &clock)
+	ctx := context.Background()
+
+	clock = clock.Add(59 * time.Minute)
+	if n := alerter.Escalate(ctx); n != 0 {
+		t.Fatalf("Escalate before threshold = %d, want 0", n)
+	}
+	if len(bus.events) != 0 {
+		t.Fatalf("events before threshold = %d, want 0", len(bus.events))
+	}
+
+	clock = clock.Add(2 * time.Minute)
+	if n := alerter.Escalate(ctx); n != 1 {
+		t.Fatalf("Escalate after threshold = %d, want 1", n)
+	}
+	if len(bus.events) != 1 || bus.events[0].Topic != TopicAlertEscalated {
+		t.Fatalf("events = %v, want one %s event", bus.events, TopicAlertEscalated)
+	}
+	payload, ok := bus.events[0].Payload.(*Alert)
+	if !ok || payload.Severity != "critical" {
+		t.Errorf("event payload = %#v, want alert with severity critical", bus.events[0].Payload)
+	}
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil || alert == nil {
+		t.Fatalf("GetActiveAlert: %v, %v", alert, err)
+	}
+	if alert.Severity != "critical" {
+		t.Errorf("stored severity = %q, want critical", alert.Severity)
+	}
+	if alert.EscalatedAt == nil || !alert.EscalatedAt.Equal(clock) {
+		t.Errorf("EscalatedAt = %v, want %v", alert.EscalatedAt, clock)
+	}
+
+	// Critical is the top tier: no further escalation however long it waits.
+	clock = clock.Add(24 * time.Hour)
+	if n := alerter.Escalate(ctx); n != 0 {
+		t.Errorf("Escalate at top tier = %d, want 0", n)
+	}
+	if len(bus.events) != 1 {
+		t.Errorf("events after top tier = %d, want 1", len(bus.events))
+	}
+}
+
+func TestAlerter_Escalate_AcknowledgedStopsEscalation(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, bus, check := newEscalationAlerter(t, &clock)
+	ctx := context.Background()
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil || alert == nil {
+		t.Fatalf("GetActiveAlert: %v, %v", alert, err)
+	}
+	if err := ps.AcknowledgeAlert(ctx, alert.ID); err != nil {
+		t.Fatalf("AcknowledgeAlert: %v", err)
+	}
+
+	clock = clock.Add(2 * time.Hour)
+	if n := alerter.Escalate(ctx); n != 0 {
+		t.Errorf("Escalate acknowledged alert = %d, want 0", n)
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("events = %d, want 0", len(bus.events))
+	}
+	alert, err = ps.GetAlert(ctx, alert.ID)
+	if err != nil || alert == nil {
+		t.Fatalf("GetAlert: %v, %v", alert, err)
+	}
+	if alert.Severity != "warning" {
+		t.Errorf("severity = %q, want warning", alert.Severity)
+	}
+}
+
+func TestAlerter_Escalate_Disabled(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, _, bus, _ := newEscalationAlerter(t, &clock)
+	alerter.SetEscalation(0)
+
+	clock = clock.Add(48 * time.Hour)
+	if n := alerter.Escalate(context.Background()); n != 0 {
+		t.Errorf("Escalate with escalation disabled = %d, want 0", n)
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("events = %d, want 0", len(bus.events))
+	}
+}
+
+// makeDependentCheck inserts a check on deviceID whose parent is parentID.
+func makeDependentCheck(t *testing.T, ps *PulseStore, deviceID, parentID string) Check {
+	t.Helper()
+	check := makeTestCheck(t, ps, deviceID, "tcp", "10.0.0.2:80")
+	check.ParentCheckID = parentID
+	if err := ps.UpdateCheck(context.Background(), &check); err != nil {
+		t.Fatalf("UpdateCheck: %v", err)
+	}
+	return check
+}
+
+func TestAlerter_ParentCheckAlert_SuppressesChild(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 1, 1, zap.NewNop())
+	ctx := context.Background()
+
+	parent := makeTestCheck(t, ps, "router", "icmp", "10.0.0.1")
+	child := makeDependentCheck(t, ps, "server", parent.ID)
+
+	alerter.ProcessResult(ctx, parent, &CheckResult{CheckID: parent.ID, Success: false, CheckedAt: time.Now().UTC()})
+	alerter.ProcessResult(ctx, child, &CheckResult{CheckID: child.ID, Success: false, CheckedAt: time.Now().UTC()})
+
+	alert, err := ps.GetActiveAlert(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("child alert not recorded")
+	}
+	if !alert.Suppressed {
+		t.Error("child alert.Suppressed = false, want true")
+	}
+	if want := parentCheckSuppressPrefix + parent.ID; alert.SuppressedBy != want {
+		t.Errorf("child alert.SuppressedBy = %q, want %q", alert.SuppressedBy, want)
+	}
+
+	if len(bus.events) != 2 {
+		t.Fatalf("got %d events, want 2", len(bus.events))
+	}
+	if bus.events[0].Topic != TopicAlertTriggered {
+		t.Errorf("events[0].Topic = %q, want %q", bus.events[0].Topic, TopicAlertTriggered)
+	}
+	if bus.events[1].Topic != TopicAlertSuppressed {
+		t.Errorf("events[1].Topic = %q, want %q", bus.events[1].Topic, TopicAlertSuppressed)
+	}
+}
+
+func TestAlerter_ParentCheckRecovery_ReleasesFailingChild(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 1, 1, zap.NewNop())
+	ctx := context.Background()
+
+	parent := makeTestCheck(t, ps, "router", "icmp", "10.0.0.1")
+	child := makeDependentCheck(t, ps, "server", parent.ID)
+	fail := func(c Check) {
+		alerter.ProcessResult(ctx, c, &CheckResult{CheckID: c.ID, Success: false, CheckedAt: time.Now().UTC()})
+	}
+
+	fail(parent)
+	fail(child)
+	alerter.ProcessResult(ctx, parent, &CheckResult{CheckID: parent.ID, Success: true, CheckedAt: time.Now().UTC()})
+	bus.events = nil
+
+	// The child is still failing after the parent recovered.
+	fail(child)
+
+	alert, err := ps.GetActiveAlert(ctx, child.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("child alert missing")
+	}
+	if alert.Suppressed {
+		t.Error("child alert.Suppressed = true after parent recovered, want false")
+	}
+	if len(bus.events) != 1 {
+		t.Fatalf("got %d events, want 1", len(bus.events))
+	}
+	if bus.events[0].Topic != TopicAlertTriggered {
+		t.Errorf("event.Topic = %q, want %q", bus.events[0].Topic, TopicAlertTriggered)
+	}
+
+	// Further failures do not re-notify.
+	fail(child)
+	if len(bus.events) != 1 {
+		t.Errorf("got %d events after repeat failure, want 1", len(bus.events))
+	}
+}
+
+func TestAlerter_MutedCheck_RecordsWithoutNotifying(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 2, 1, zap.NewNop())
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+	until := clock.Add(time.Hour)
+	if err := ps.SetCheckMute(ctx, check.ID, &until); err != nil {
+		t.Fatalf("SetCheckMute: %v", err)
+	}
+	check.MutedUntil = &until
+	fail := func() {
+		result := &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: clock}
+		if err := ps.InsertResult(ctx, result); err != nil {
+			t.Fatalf("InsertResult: %v", err)
+		}
+		alerter.ProcessResult(ctx, check, result)
+	}
+
+	fail()
+	fail()
+	fail()
+
+	results, err := ps.ListResults(ctx, check.DeviceID, 10)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("recorded %d results, want 3", len(results))
+	}
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("alert not recorded while muted")
+	}
+	if !alert.Suppressed || alert.SuppressedBy != muteSuppressBy {
+		t.Errorf("alert suppressed = %v by %q, want true by %q", alert.Suppressed, alert.SuppressedBy, muteSuppressBy)
+	}
+	if len(bus.events) != 0 {
+		t.Fatalf("got %d events while muted, want 0", len(bus.events))
+	}
+
+	// Once the mute expires the still-failing check alerts on its next failure.
+	clock = until.Add(time.Minute)
+	fail()
+	if len(bus.events) != 1 {
+		t.Fatalf("got %d events after mute expired, want 1", len(bus.events))
+	}
+	if bus.events[0].Topic != TopicAlertTriggered {
+		t.Errorf("event.Topic = %q, want %q", bus.events[0].Topic, TopicAlertTriggered)
+	}
+	alert, err = ps.GetActiveAlert(ctx, check.ID)
+	if err != nil || alert == nil {
+		t.Fatalf("GetActiveAlert: %v, %v", alert, err)
+	}
+	if alert.Suppressed {
+		t.Error("alert.Suppressed = true after mute expired, want false")
+	}
+
+	alerter.ProcessResult(ctx, check, &CheckResult{CheckID: check.ID, Success: true, CheckedAt: clock})
+	if len(bus.events) != 2 || bus.events[1].Topic != TopicAlertResolved {
+		t.Errorf("events = %v, want resolution after the triggered alert", bus.events)
+	}
+}
+
+func TestAlerter_MutedCheck_NotEscalated(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, bus, check := newEscalationAlerter(t, &clock)
+	ctx := context.Background()
+
+	until := clock.Add(3 * time.Hour)
+	if err := ps.SetCheckMute(ctx, check.ID, &until); err != nil {
+		t.Fatalf("SetCheckMute: %v", err)
+	}
+
+	clock = clock.Add(2 * time.Hour)
+	if n := alerter.Escalate(ctx); n != 0 {
+		t.Errorf("Escalate while muted = %d, want 0", n)
+	}
+	clock = until.Add(time.Minute)
+	if n := alerter.Escalate(ctx); n != 1 {
+		t.Errorf("Escalate after mute expired = %d, want 1", n)
+	}
+	if len(bus.events) != 1 || bus.events[0].Topic != TopicAlertEscalated {
+		t.Errorf("events = %v, want one %s event", bus.events, TopicAlertEscalated)
+	}
+}
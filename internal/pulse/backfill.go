@@ -0,0 +1,175 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxBackfillResults caps the number of results accepted in one backfill request.
+const maxBackfillResults = 10000
+
+// backfillRequest is the JSON body for POST /results/backfill.
+type backfillRequest struct {
+	Results []CheckResult `json:"results"`
+}
+
+// backfillResponse is the JSON response for POST /results/backfill.
+type backfillResponse struct {
+	Inserted int `json:"inserted"`
+}
+
+// InsertResults inserts a batch of check results in a single transaction.
+// Either all results are stored or none are. Metric rollups are rewound to
+// the earliest result so the next rollup run picks the batch up.
+func (s *PulseStore) InsertResults(ctx context.Context, results []CheckResult) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin insert results: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO pulse_check_results (
+			check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare insert results: %w", err)
+	}
+	defer stmt.Close()
+
+	var earliest time.Time
+	for i := range results {
+		r := &results[i]
+		if earliest.IsZero() || r.CheckedAt.Before(earliest) {
+			earliest = r.CheckedAt
+		}
+		success := 0
+		if r.Success {
+			success = 1
+		}
+		if _, err := stmt.ExecContext(ctx,
+			r.CheckID, r.DeviceID, success, r.LatencyMs, r.PacketLoss,
+			r.ErrorMessage, r.CheckedAt,
+		); err != nil {
+			return fmt.Errorf("insert result %d: %w", i, err)
+		}
+	}
+	if !earliest.IsZero() {
+		if err := rewindRollups(ctx, tx, earliest); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit insert results: %w", err)
+	}
+	return nil
+}
+
+// handleBackfillResults imports historical check results.
+//
+//	@Summary		Backfill results
+//	@Description	Imports historical check results for existing checks. Results are stored as-is and do not run through alerting. Results older than the retention period are rejected.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body body backfillRequest true "Historical results"
+//	@Success		201 {object} backfillResponse
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/results/backfill [post]
+func (m *Module) handleBackfillResults(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	var req backfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if len(req.Results) == 0 {
+		pulseWriteError(w, http.StatusBadRequest, "results must not be empty")
+		return
+	}
+	if len(req.Results) > maxBackfillResults {
+		pulseWriteError(w, http.StatusBadRequest,
+			fmt.Sprintf("at most %d results may be backfilled per request", maxBackfillResults))
+		return
+	}
+
+	// Validate every result before inserting any, so a bad row never
+	// leaves a partially imported history behind. Results older than the
+	// retention period are rejected rather than stored only for the next
+	// maintenance run to delete them.
+	now := time.Now().UTC()
+	var oldest time.Time
+	if m.cfg.RetentionPeriod > 0 {
+		oldest = now.Add(-m.cfg.RetentionPeriod)
+	}
+	checks := make(map[string]*Check)
+	for i := range req.Results {
+		res := &req.Results[i]
+		if res.CheckID == "" {
+			pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("results[%d]: check_id is required", i))
+			return
+		}
+		if res.CheckedAt.IsZero() {
+			pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("results[%d]: checked_at is required", i))
+			return
+		}
+		if !res.CheckedAt.Before(now) {
+			pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("results[%d]: checked_at must be in the past", i))
+			return
+		}
+		if res.CheckedAt.Before(oldest) {
+			pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf(
+				"results[%d]: checked_at is older than the %s retention period", i, m.cfg.RetentionPeriod))
+			return
+		}
+
+		check, seen := checks[res.CheckID]
+		if !seen {
+			var err error
+			check, err = m.store.GetCheck(r.Context(), res.CheckID)
+			if err != nil {
+				m.logger.Warn("failed to get check for backfill", zap.String("check_id", res.CheckID), zap.Error(err))
+				pulseWriteError(w, http.StatusInternalServerError, "failed to get check")
+				return
+			}
+			checks[res.CheckID] = check
+		}
+		if check == nil {
+			pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("results[%d]: check %q not found", i, res.CheckID))
+			return
+		}
+
+		// The check owns the device association; ignore any client-supplied value.
+		res.DeviceID = check.DeviceID
+		res.CheckedAt = res.CheckedAt.UTC()
+	}
+
+	// Historical results are written directly and never passed to the
+	// alerter: replaying old failures must not raise alerts today. Inserting
+	// rewinds the metric rollups, so QueryMetrics reads the backfilled span
+	// from raw results until the next rollup run covers it.
+	if err := m.store.InsertResults(r.Context(), req.Results); err != nil {
+		m.logger.Warn("failed to backfill results", zap.Int("count", len(req.Results)), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to backfill results")
+		return
+	}
+
+	m.logger.Info("backfilled historical check results",
+		zap.Int("count", len(req.Results)),
+		zap.Int("checks", len(checks)),
+	)
+
+	pulseWriteJSON(w, http.StatusCreated, backfillResponse{Inserted: len(req.Results)})
+}
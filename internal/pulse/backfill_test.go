@@ -0,0 +1,161 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func backfillBody(t *testing.T, results []CheckResult) *strings.Reader {
+	t.Helper()
+	data, err := json.Marshal(backfillRequest{Results: results})
+	if err != nil {
+		t.Fatalf("marshal body: %v", err)
+	}
+	return strings.NewReader(string(data))
+}
+
+func TestHandleBackfillResults_AppearsInMetrics(t *testing.T) {
+	m, ps := newTestModule(t)
+	bus := &mockEventBus{}
+	m.bus = bus
+	m.alerter = NewAlerter(ps, bus, 1, 1, m.logger)
+	ctx := context.Background()
+
+	check := makeTestCheck(t, ps, "dev-bf", "icmp", "192.168.1.10")
+
+	start := time.Now().UTC().Add(-5 * time.Hour).Truncate(time.Minute)
+	results := make([]CheckResult, 0, 10)
+	for i := 0; i < 10; i++ {
+		results = append(results, CheckResult{
+			CheckID:      check.ID,
+			Success:      false, // historical outage
+			LatencyMs:    20,
+			ErrorMessage: "timeout",
+			CheckedAt:    start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/results/backfill", backfillBody(t, results))
+	w := httptest.NewRecorder()
+	m.handleBackfillResults(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var resp backfillResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Inserted != 10 {
+		t.Errorf("Inserted = %d, want 10", resp.Inserted)
+	}
+
+	// Backfilled results fall inside the 6h range but outside the 1h range.
+	series, err := ps.QueryMetrics(ctx, check.DeviceID, "success_rate", "6h")
+	if err != nil {
+		t.Fatalf("QueryMetrics(6h): %v", err)
+	}
+	if len(series.Points) != 10 {
+		t.Errorf("6h points = %d, want 10", len(series.Points))
+	}
+	series, err = ps.QueryMetrics(ctx, check.DeviceID, "success_rate", "1h")
+	if err != nil {
+		t.Fatalf("QueryMetrics(1h): %v", err)
+	}
+	if len(series.Points) != 0 {
+		t.Errorf("1h points = %d, want 0", len(series.Points))
+	}
+
+	// Historical failures must not raise alerts.
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert != nil {
+		t.Errorf("active alert = %+v, want nil", alert)
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("published %d events, want 0", len(bus.events))
+	}
+}
+
+func TestHandleBackfillResults_UsesCheckDeviceID(t *testing.T) {
+	m, ps := newTestModule(t)
+	check := makeTestCheck(t, ps, "dev-owner", "icmp", "192.168.1.11")
+
+	results := []CheckResult{{
+		CheckID:   check.ID,
+		DeviceID:  "someone-else",
+		Success:   true,
+		CheckedAt: time.Now().UTC().Add(-time.Hour),
+	}}
+	req := httptest.NewRequest(http.MethodPost, "/results/backfill", backfillBody(t, results))
+	w := httptest.NewRecorder()
+	m.handleBackfillResults(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	stored, err := ps.ListResults(context.Background(), "dev-owner", 10)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Errorf("results for check device = %d, want 1", len(stored))
+	}
+}
+
+func TestHandleBackfillResults_Validation(t *testing.T) {
+	m, ps := newTestModule(t)
+	check := makeTestCheck(t, ps, "dev-val", "icmp", "192.168.1.12")
+	past := time.Now().UTC().Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		results []CheckResult
+	}{
+		{"empty batch", nil},
+		{"missing check_id", []CheckResult{{CheckedAt: past}}},
+		{"missing checked_at", []CheckResult{{CheckID: check.ID}}},
+		{"future timestamp", []CheckResult{{CheckID: check.ID, CheckedAt: time.Now().UTC().Add(time.Hour)}}},
+		{"older than retention", []CheckResult{{CheckID: check.ID, CheckedAt: time.Now().UTC().Add(-m.cfg.RetentionPeriod - time.Hour)}}},
+		{"unknown check", []CheckResult{{CheckID: "nope", CheckedAt: past}}},
+		{"one bad row rejects batch", []CheckResult{
+			{CheckID: check.ID, CheckedAt: past},
+			{CheckID: "nope", CheckedAt: past},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/results/backfill", backfillBody(t, tt.results))
+			w := httptest.NewRecorder()
+			m.handleBackfillResults(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+
+	stored, err := ps.ListResults(context.Background(), "dev-val", 10)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Errorf("stored results = %d, want 0 after rejected batches", len(stored))
+	}
+}
+
+func TestHandleBackfillResults_InvalidJSON(t *testing.T) {
+	m, _ := newTestModule(t)
+	req := httptest.NewRequest(http.MethodPost, "/results/backfill", strings.NewReader("{bad"))
+	w := httptest.NewRecorder()
+	m.handleBackfillResults(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
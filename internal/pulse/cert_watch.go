@@ -0,0 +1,209 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+// TLSCertInfo describes the leaf certificate observed by a tls check.
+type TLSCertInfo struct {
+	Fingerprint string    `json:"fingerprint"` // SHA-256 of the DER-encoded certificate, hex
+	Issuer      string    `json:"issuer"`
+	Subject     string    `json:"subject"`
+	NotAfter    time.Time `json:"not_after"`
+}
+
+// CertState is the last-seen certificate for a tls check. When the endpoint
+// presents a different certificate, the previous values are kept so that
+// the change can be reviewed.
+type CertState struct {
+	CheckID             string     `json:"check_id"`
+	Fingerprint         string     `json:"fingerprint"`
+	Issuer              string     `json:"issuer"`
+	Subject             string     `json:"subject"`
+	NotAfter            time.Time  `json:"not_after"`
+	ExpectRotation      bool       `json:"expect_rotation"`
+	PreviousFingerprint string     `json:"previous_fingerprint,omitempty"`
+	PreviousIssuer      string     `json:"previous_issuer,omitempty"`
+	ChangedAt           *time.Time `json:"changed_at,omitempty"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// -- Certificate State Store --
+
+// GetCertState returns the last-seen certificate for a check. Returns nil, nil if none.
+func (s *PulseStore) GetCertState(ctx context.Context, checkID string) (*CertState, error) {
+	var cs CertState
+	var expectInt int
+	var changedAt sql.NullTime
+	err := s.db.QueryRowContext(ctx, `
+		SELECT check_id, fingerprint, issuer, subject, not_after, expect_rotation,
+			previous_fingerprint, previous_issuer, changed_at, updated_at
+		FROM pulse_tls_certs WHERE check_id = ?`,
+		checkID,
+	).Scan(
+		&cs.CheckID, &cs.Fingerprint, &cs.Issuer, &cs.Subject, &cs.NotAfter, &expectInt,
+		&cs.PreviousFingerprint, &cs.PreviousIssuer, &changedAt, &cs.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get cert state: %w", err)
+	}
+	cs.ExpectRotation = expectInt != 0
+	if changedAt.Valid {
+		cs.ChangedAt = &changedAt.Time
+	}
+	return &cs, nil
+}
+
+// UpsertCertState inserts or replaces the last-seen certificate for a check.
+func (s *PulseStore) UpsertCertState(ctx context.Context, cs *CertState) error {
+	expectInt := 0
+	if cs.ExpectRotation {
+		expectInt = 1
+	}
+	var changedAt sql.NullTime
+	if cs.ChangedAt != nil {
+		changedAt = sql.NullTime{Time: *cs.ChangedAt, Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pulse_tls_certs (
+			check_id, fingerprint, issuer, subject, not_after, expect_rotation,
+			previous_fingerprint, previous_issuer, changed_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(check_id) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			issuer = excluded.issuer,
+			subject = excluded.subject,
+			not_after = excluded.not_after,
+			expect_rotation = excluded.expect_rotation,
+			previous_fingerprint = excluded.previous_fingerprint,
+			previous_issuer = excluded.previous_issuer,
+			changed_at = excluded.changed_at,
+			updated_at = excluded.updated_at`,
+		cs.CheckID, cs.Fingerprint, cs.Issuer, cs.Subject, cs.NotAfter, expectInt,
+		cs.PreviousFingerprint, cs.PreviousIssuer, changedAt, cs.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert cert state: %w", err)
+	}
+	return nil
+}
+
+// SetCertExpectRotation marks the next certificate change for a check as expected.
+// Returns false if no certificate has been recorded for the check yet.
+func (s *PulseStore) SetCertExpectRotation(ctx context.Context, checkID string, expect bool) (bool, error) {
+	expectInt := 0
+	if expect {
+		expectInt = 1
+	}
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE pulse_tls_certs SET expect_rotation = ?, updated_at = ? WHERE check_id = ?`,
+		expectInt, time.Now().UTC(), checkID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("set cert expect rotation: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("set cert expect rotation: %w", err)
+	}
+	return n > 0, nil
+}
+
+// -- Certificate Change Detection --
+
+// ProcessCertificate compares the certificate in a tls check result against
+// the last-seen certificate for the check. An unexpected issuer or fingerprint
+// change publishes a TopicCertChanged alert; a change following an
+// expected-rotation acknowledgment is recorded silently and clears the flag.
+func (a *Alerter) ProcessCertificate(ctx context.Context, check Check, result *CheckResult) {
+	if result == nil || result.TLS == nil {
+		return
+	}
+	info := result.TLS
+	now := time.Now().UTC()
+
+	prev, err := a.store.GetCertState(ctx, check.ID)
+	if err != nil {
+		a.logger.Warn("failed to get cert state", zap.String("check_id", check.ID), zap.Error(err))
+		return
+	}
+
+	next := &CertState{
+		CheckID:     check.ID,
+		Fingerprint: info.Fingerprint,
+		Issuer:      info.Issuer,
+		Subject:     info.Subject,
+		NotAfter:    info.NotAfter,
+		UpdatedAt:   now,
+	}
+
+	changed := prev != nil && (prev.Fingerprint != info.Fingerprint || prev.Issuer != info.Issuer)
+	switch {
+	case prev == nil:
+		// First observation: record the baseline.
+	case !changed:
+		next.ExpectRotation = prev.ExpectRotation
+		next.PreviousFingerprint = prev.PreviousFingerprint
+		next.PreviousIssuer = prev.PreviousIssuer
+		next.ChangedAt = prev.ChangedAt
+	default:
+		next.PreviousFingerprint = prev.Fingerprint
+		next.PreviousIssuer = prev.Issuer
+		next.ChangedAt = &now
+	}
+
+	if err := a.store.UpsertCertState(ctx, next); err != nil {
+		a.logger.Warn("failed to store cert state", zap.String("check_id", check.ID), zap.Error(err))
+		return
+	}
+
+	if !changed {
+		return
+	}
+
+	if prev.ExpectRotation {
+		a.logger.Info("certificate rotated as expected",
+			zap.String("check_id", check.ID),
+			zap.String("old_fingerprint", prev.Fingerprint),
+			zap.String("new_fingerprint", info.Fingerprint),
+		)
+		return
+	}
+
+	alert := &Alert{
+		ID:       fmt.Sprintf("cert-%s-%d", check.ID, now.UnixMilli()),
+		CheckID:  check.ID,
+		DeviceID: check.DeviceID,
+		Severity: "warning",
+		Message: fmt.Sprintf("certificate for %s changed: issuer %q -> %q, fingerprint %s -> %s",
+			check.Target, prev.Issuer, info.Issuer, prev.Fingerprint, info.Fingerprint),
+		TriggeredAt: now,
+	}
+
+	a.logger.Warn("certificate changed unexpectedly",
+		zap.String("check_id", check.ID),
+		zap.String("device_id", check.DeviceID),
+		zap.String("old_issuer", prev.Issuer),
+		zap.String("new_issuer", info.Issuer),
+		zap.String("old_fingerprint", prev.Fingerprint),
+		zap.String("new_fingerprint", info.Fingerprint),
+	)
+
+	if a.bus != nil {
+		a.bus.PublishAsync(ctx, plugin.Event{
+			Topic:     TopicCertChanged,
+			Source:    "pulse",
+			Timestamp: now,
+			Payload:   alert,
+		})
+	}
+}
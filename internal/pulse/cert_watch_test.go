@@ -0,0 +1,192 @@
+package pulse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func tlsResult(check Check, fingerprint, issuer string) *CheckResult {
+	return &CheckResult{
+		CheckID:   check.ID,
+		DeviceID:  check.DeviceID,
+		Success:   true,
+		CheckedAt: time.Now().UTC(),
+		TLS: &TLSCertInfo{
+			Fingerprint: fingerprint,
+			Issuer:      issuer,
+			Subject:     "CN=example.lan",
+			NotAfter:    time.Now().Add(90 * 24 * time.Hour).UTC(),
+		},
+	}
+}
+
+func countTopic(bus *mockEventBus, topic string) int {
+	n := 0
+	for i := range bus.events {
+		if bus.events[i].Topic == topic {
+			n++
+		}
+	}
+	return n
+}
+
+func TestProcessCertificate_FirstObservation_NoAlert(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	check := makeTestCheck(t, ps, "device1", "tls", "example.lan:443")
+	ctx := context.Background()
+
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "aaaa", "CN=Old CA"))
+
+	if n := countTopic(bus, TopicCertChanged); n != 0 {
+		t.Errorf("cert changed events = %d, want 0", n)
+	}
+	cs, err := ps.GetCertState(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetCertState: %v", err)
+	}
+	if cs == nil {
+		t.Fatal("GetCertState returned nil, want recorded state")
+	}
+	if cs.Fingerprint != "aaaa" {
+		t.Errorf("Fingerprint = %q, want %q", cs.Fingerprint, "aaaa")
+	}
+}
+
+func TestProcessCertificate_Unchanged_NoAlert(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	check := makeTestCheck(t, ps, "device1", "tls", "example.lan:443")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		alerter.ProcessCertificate(ctx, check, tlsResult(check, "aaaa", "CN=Old CA"))
+	}
+
+	if n := countTopic(bus, TopicCertChanged); n != 0 {
+		t.Errorf("cert changed events = %d, want 0", n)
+	}
+}
+
+func TestProcessCertificate_ChangedFingerprint_TriggersAlert(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	check := makeTestCheck(t, ps, "device1", "tls", "example.lan:443")
+	ctx := context.Background()
+
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "aaaa", "CN=Old CA"))
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "bbbb", "CN=New CA"))
+
+	if n := countTopic(bus, TopicCertChanged); n != 1 {
+		t.Fatalf("cert changed events = %d, want 1", n)
+	}
+	alert, ok := bus.events[0].Payload.(*Alert)
+	if !ok {
+		t.Fatalf("payload type = %T, want *Alert", bus.events[0].Payload)
+	}
+	for _, want := range []string{"aaaa", "bbbb", "CN=Old CA", "CN=New CA"} {
+		if !strings.Contains(alert.Message, want) {
+			t.Errorf("alert.Message = %q, want it to contain %q", alert.Message, want)
+		}
+	}
+
+	cs, err := ps.GetCertState(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetCertState: %v", err)
+	}
+	if cs.PreviousFingerprint != "aaaa" {
+		t.Errorf("PreviousFingerprint = %q, want %q", cs.PreviousFingerprint, "aaaa")
+	}
+	if cs.ChangedAt == nil {
+		t.Error("ChangedAt = nil, want change timestamp")
+	}
+}
+
+func TestProcessCertificate_ExpectedRotation_SuppressesNextChange(t *testing.T) {
+	ps := alerterTestStore(t)
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 3, 1, zap.NewNop())
+	check := makeTestCheck(t, ps, "device1", "tls", "example.lan:443")
+	ctx := context.Background()
+
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "aaaa", "CN=CA"))
+	found, err := ps.SetCertExpectRotation(ctx, check.ID, true)
+	if err != nil || !found {
+		t.Fatalf("SetCertExpectRotation = %v, %v; want true, nil", found, err)
+	}
+
+	// Expected rotation: no alert, flag cleared.
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "bbbb", "CN=CA"))
+	if n := countTopic(bus, TopicCertChanged); n != 0 {
+		t.Fatalf("cert changed events after expected rotation = %d, want 0", n)
+	}
+	cs, err := ps.GetCertState(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetCertState: %v", err)
+	}
+	if cs.ExpectRotation {
+		t.Error("ExpectRotation = true after rotation, want false")
+	}
+
+	// The acknowledgment only covers one change.
+	alerter.ProcessCertificate(ctx, check, tlsResult(check, "cccc", "CN=CA"))
+	if n := countTopic(bus, TopicCertChanged); n != 1 {
+		t.Errorf("cert changed events after second change = %d, want 1", n)
+	}
+}
+
+func TestSetCertExpectRotation_NoState(t *testing.T) {
+	ps := alerterTestStore(t)
+	check := makeTestCheck(t, ps, "device1", "tls", "example.lan:443")
+
+	found, err := ps.SetCertExpectRotation(context.Background(), check.ID, true)
+	if err != nil {
+		t.Fatalf("SetCertExpectRotation: %v", err)
+	}
+	if found {
+		t.Error("found = true, want false when no certificate recorded")
+	}
+}
+
+func TestTLSChecker_Success(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	target := strings.TrimPrefix(server.URL, "https://")
+	checker := NewTLSChecker(5 * time.Second)
+	result, err := checker.Check(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Check() error = %v, want nil", err)
+	}
+	if !result.Success {
+		t.Errorf("Check() Success = false, want true")
+	}
+	if result.TLS == nil {
+		t.Fatal("Check() TLS = nil, want certificate info")
+	}
+	if len(result.TLS.Fingerprint) != 64 {
+		t.Errorf("Fingerprint length = %d, want 64 hex chars", len(result.TLS.Fingerprint))
+	}
+}
+
+func TestTLSChecker_InvalidTarget(t *testing.T) {
+	checker := NewTLSChecker(time.Second)
+	result, err := checker.Check(context.Background(), "no-port")
+	if err == nil {
+		t.Error("Check() error = nil, want error for missing port")
+	}
+	if result == nil || result.Success {
+		t.Error("Check() result should be a failure")
+	}
+}
@@ -0,0 +1,199 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxBulkChecks caps the number of checks accepted in one bulk create request.
+const maxBulkChecks = 1000
+
+// bulkCheckError describes why a single item in a bulk request failed.
+type bulkCheckError struct {
+	Message string `json:"message"`
+}
+
+// bulkCheckResult is the outcome of one item in POST /checks/bulk. Exactly
+// one of Check or Error is set.
+type bulkCheckResult struct {
+	Index int             `json:"index"`
+	Check *Check          `json:"check,omitempty"`
+	Error *bulkCheckError `json:"error,omitempty"`
+}
+
+// bulkCheckResponse is the JSON response for POST /checks/bulk.
+type bulkCheckResponse struct {
+	Created int               `json:"created"`
+	Failed  int               `json:"failed"`
+	Results []bulkCheckResult `json:"results"`
+}
+
+// InsertChecks inserts a batch of checks in a single transaction. Each row
+// runs under its own savepoint, so a row that fails is rolled back on its own
+// while the rest of the batch is still committed. The returned slice holds
+// the per-row error (nil on success) in input order; the error return is set
+// only when the transaction itself fails, in which case nothing is stored.
+func (s *PulseStore) InsertChecks(ctx context.Context, checks []*Check) ([]error, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin insert checks: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	rowErrs := make([]error, len(checks))
+	for i, c := range checks {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT bulk_check"); err != nil {
+			return nil, fmt.Errorf("savepoint check %d: %w", i, err)
+		}
+		enabled := 0
+		if c.Enabled {
+			enabled = 1
+		}
+		lowPriority := 0
+		if c.LowPriority {
+			lowPriority = 1
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO pulse_checks (
+				id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+				expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+				created_at, updated_at
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			c.ID, c.DeviceID, c.CheckType, c.Target, c.IntervalSeconds,
+			enabled, lowPriority, c.ExpectedStatus, c.ExpectedBodySubstring, nullInt(c.FailureThreshold),
+			c.TimeoutMs, c.Payload, c.ParentCheckID, c.CreatedAt, c.UpdatedAt,
+		)
+		if err != nil {
+			rowErrs[i] = fmt.Errorf("insert check: %w", err)
+			if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO bulk_check"); rbErr != nil {
+				return nil, fmt.Errorf("rollback check %d: %w", i, rbErr)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE bulk_check"); err != nil {
+			return nil, fmt.Errorf("release check %d: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit insert checks: %w", err)
+	}
+	return rowErrs, nil
+}
+
+// handleBulkCreateChecks creates many monitoring checks in one request.
+//
+//	@Summary		Bulk create checks
+//	@Description	Creates several monitoring checks in a single transaction. Each item is validated and inserted independently; the response lists the created check or an error for every item. Returns 201 when all items succeed and 207 when any fail.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			body body []createCheckRequest true "Checks to create"
+//	@Success		201 {object} bulkCheckResponse
+//	@Success		207 {object} bulkCheckResponse
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/checks/bulk [post]
+func (m *Module) handleBulkCreateChecks(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	var reqs []createCheckRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if len(reqs) == 0 {
+		pulseWriteError(w, http.StatusBadRequest, "checks must not be empty")
+		return
+	}
+	if len(reqs) > maxBulkChecks {
+		pulseWriteError(w, http.StatusBadRequest,
+			fmt.Sprintf("at most %d checks may be created per request", maxBulkChecks))
+		return
+	}
+
+	resp := bulkCheckResponse{Results: make([]bulkCheckResult, len(reqs))}
+	now := time.Now().UTC()
+	checks := make([]*Check, 0, len(reqs))
+	indexes := make([]int, 0, len(reqs))
+	for i := range reqs {
+		req := &reqs[i]
+		resp.Results[i].Index = i
+		if err := validateCreateCheck(req); err != nil {
+			resp.Results[i].Error = &bulkCheckError{Message: err.Error()}
+			continue
+		}
+		if err := m.validateParentCheck(r.Context(), "", req.ParentCheckID); err != nil {
+			if !errors.Is(err, errParentCheckNotFound) {
+				m.logger.Warn("failed to validate parent check", zap.Int("index", i), zap.Error(err))
+				pulseWriteError(w, http.StatusInternalServerError, "failed to create checks")
+				return
+			}
+			resp.Results[i].Error = &bulkCheckError{Message: err.Error()}
+			continue
+		}
+		if req.IntervalSeconds <= 0 {
+			req.IntervalSeconds = 30
+		}
+		checks = append(checks, &Check{
+			// The index keeps IDs unique when one batch holds several
+			// checks of the same type for a device.
+			ID:                    fmt.Sprintf("pulse-%s-%s-%d-%d", req.DeviceID, req.CheckType, now.UnixMilli(), i),
+			DeviceID:              req.DeviceID,
+			CheckType:             req.CheckType,
+			Target:                req.Target,
+			IntervalSeconds:       req.IntervalSeconds,
+			Enabled:               true,
+			LowPriority:           req.LowPriority,
+			ExpectedStatus:        req.ExpectedStatus,
+			ExpectedBodySubstring: req.ExpectedBodySubstring,
+			FailureThreshold:      req.FailureThreshold,
+			TimeoutMs:             req.TimeoutMs,
+			Payload:               req.Payload,
+			ParentCheckID:         req.ParentCheckID,
+			CreatedAt:             now,
+			UpdatedAt:             now,
+		})
+		indexes = append(indexes, i)
+	}
+
+	if len(checks) > 0 {
+		rowErrs, err := m.store.InsertChecks(r.Context(), checks)
+		if err != nil {
+			m.logger.Warn("failed to bulk create checks", zap.Int("count", len(checks)), zap.Error(err))
+			pulseWriteError(w, http.StatusInternalServerError, "failed to create checks")
+			return
+		}
+		for j, idx := range indexes {
+			if rowErrs[j] != nil {
+				m.logger.Warn("failed to create check in bulk request", zap.Int("index", idx), zap.Error(rowErrs[j]))
+				resp.Results[idx].Error = &bulkCheckError{Message: "failed to create check"}
+				continue
+			}
+			resp.Results[idx].Check = checks[j]
+		}
+	}
+
+	for i := range resp.Results {
+		if resp.Results[i].Error != nil {
+			resp.Failed++
+		} else {
+			resp.Created++
+		}
+	}
+
+	status := http.StatusCreated
+	if resp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+	pulseWriteJSON(w, status, resp)
+}
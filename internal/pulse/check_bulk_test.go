@@ -0,0 +1,167 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleBulkCreateChecks_MixedBatch(t *testing.T) {
+	m, ps := newTestModule(t)
+
+	body := `[
+		{"device_id":"dev-1","check_type":"icmp","target":"192.168.1.1"},
+		{"device_id":"dev-1","check_type":"tcp","target":"192.168.1.1"},
+		{"device_id":"dev-2","check_type":"http","target":"https://192.168.1.2/health","interval_seconds":60},
+		{"device_id":"","check_type":"icmp","target":"192.168.1.3"},
+		{"device_id":"dev-2","check_type":"tcp","target":"192.168.1.2:22"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/checks/bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	m.handleBulkCreateChecks(w, req)
+
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMultiStatus)
+	}
+	var resp bulkCheckResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Created != 3 || resp.Failed != 2 {
+		t.Errorf("created=%d failed=%d, want 3 and 2", resp.Created, resp.Failed)
+	}
+	if len(resp.Results) != 5 {
+		t.Fatalf("results = %d, want 5", len(resp.Results))
+	}
+	for i, wantOK := range []bool{true, false, true, false, true} {
+		res := resp.Results[i]
+		if res.Index != i {
+			t.Errorf("results[%d].Index = %d", i, res.Index)
+		}
+		if wantOK && (res.Check == nil || res.Error != nil) {
+			t.Errorf("results[%d] = %+v, want created check", i, res)
+		}
+		if !wantOK && (res.Check != nil || res.Error == nil || res.Error.Message == "") {
+			t.Errorf("results[%d] = %+v, want error", i, res)
+		}
+	}
+	if got := resp.Results[1].Error.Message; got != "tcp target must be host:port format" {
+		t.Errorf("results[1] error = %q, want validateTarget message", got)
+	}
+	if got := resp.Results[2].Check.IntervalSeconds; got != 60 {
+		t.Errorf("results[2] interval = %d, want 60", got)
+	}
+
+	checks, err := ps.ListAllChecks(context.Background())
+	if err != nil {
+		t.Fatalf("ListAllChecks: %v", err)
+	}
+	if len(checks) != 3 {
+		t.Errorf("stored checks = %d, want 3", len(checks))
+	}
+}
+
+func TestHandleBulkCreateChecks_AllValid(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	body := `[
+		{"device_id":"dev-1","check_type":"icmp","target":"192.168.1.1"},
+		{"device_id":"dev-1","check_type":"icmp","target":"192.168.1.1"}
+	]`
+	req := httptest.NewRequest(http.MethodPost, "/checks/bulk", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	m.handleBulkCreateChecks(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	var resp bulkCheckResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Created != 2 {
+		t.Fatalf("created = %d, want 2", resp.Created)
+	}
+	if resp.Results[0].Check.ID == resp.Results[1].Check.ID {
+		t.Errorf("duplicate check ID %q for same device and type", resp.Results[0].Check.ID)
+	}
+}
+
+func TestHandleBulkCreateChecks_BadRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"malformed JSON", `[{"device_id":`},
+		{"not an array", `{"device_id":"dev-1","check_type":"icmp","target":"10.0.0.1"}`},
+		{"empty", `[]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestModule(t)
+			req := httptest.NewRequest(http.MethodPost, "/checks/bulk", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			m.handleBulkCreateChecks(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPulseStore_InsertChecks_RollsBackOnlyFailedRows(t *testing.T) {
+	_, ps := newTestModule(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	existing := &Check{ID: "chk-dup", DeviceID: "dev-1", CheckType: "icmp", Target: "10.0.0.1",
+		IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now}
+	if err := ps.InsertCheck(ctx, existing); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+
+	batch := []*Check{
+		{ID: "chk-a", DeviceID: "dev-2", CheckType: "icmp", Target: "10.0.0.2",
+			IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now},
+		// Conflicts with the existing primary key.
+		{ID: "chk-dup", DeviceID: "dev-3", CheckType: "icmp", Target: "10.0.0.3",
+			IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "chk-b", DeviceID: "dev-4", CheckType: "tcp", Target: "10.0.0.4:22",
+			IntervalSeconds: 30, Enabled: false, CreatedAt: now, UpdatedAt: now},
+	}
+	rowErrs, err := ps.InsertChecks(ctx, batch)
+	if err != nil {
+		t.Fatalf("InsertChecks: %v", err)
+	}
+	if rowErrs[0] != nil || rowErrs[2] != nil {
+		t.Errorf("row errors = %v, want only row 1 to fail", rowErrs)
+	}
+	if rowErrs[1] == nil {
+		t.Error("row 1 error = nil, want primary key conflict")
+	}
+
+	for _, id := range []string{"chk-a", "chk-b"} {
+		c, err := ps.GetCheck(ctx, id)
+		if err != nil {
+			t.Fatalf("GetCheck(%s): %v", id, err)
+		}
+		if c == nil {
+			t.Errorf("check %s not committed", id)
+		}
+	}
+	dup, err := ps.GetCheck(ctx, "chk-dup")
+	if err != nil {
+		t.Fatalf("GetCheck(chk-dup): %v", err)
+	}
+	if dup == nil || dup.DeviceID != "dev-1" {
+		t.Errorf("existing check = %+v, want original row untouched", dup)
+	}
+}
@@ -14,6 +14,22 @@ type Checker interface {
 	Check(ctx context.Context, target string) (*CheckResult, error)
 }
 
+// expectationChecker is implemented by checkers that can also assert on the
+// response content of a check (expected status code and body).
+type expectationChecker interface {
+	CheckExpect(ctx context.Context, check *Check) (*CheckResult, error)
+}
+
+// deadlineTimeout returns the time left before ctx's deadline, or fallback
+// when ctx has none. Checkers use it so a per-check deadline set by the
+// caller overrides the timeout they were constructed with.
+func deadlineTimeout(ctx context.Context, fallback time.Duration) time.Duration {
+	if deadline, ok := ctx.Deadline(); ok {
+		return time.Until(deadline)
+	}
+	return fallback
+}
+
 // ICMPChecker pings targets using ICMP via pro-bing.
 type ICMPChecker struct {
 	timeout time.Duration
@@ -36,7 +52,7 @@ func (c *ICMPChecker) Check(ctx context.Context, target string) (*CheckResult, e
 	}
 
 	pinger.Count = c.count
-	pinger.Timeout = c.timeout
+	pinger.Timeout = deadlineTimeout(ctx, c.timeout)
 	pinger.SetPrivileged(runtime.GOOS == "windows")
 
 	// Run pinger in a goroutine for context cancellation.
@@ -3,15 +3,30 @@ package pulse
 import "time"
 
 type PulseConfig struct {
-	CheckInterval       time.Duration `mapstructure:"check_interval"`
-	PingTimeout         time.Duration `mapstructure:"ping_timeout"`
-	PingCount           int           `mapstructure:"ping_count"`
-	ConsecutiveFailures int           `mapstructure:"consecutive_failures"`
-	RetentionPeriod     time.Duration `mapstructure:"retention_period"`
-	MaxWorkers          int           `mapstructure:"max_workers"`
-	MaintenanceInterval time.Duration `mapstructure:"maintenance_interval"`
-	CorrelationEnabled  bool          `mapstructure:"correlation_enabled"`
-	CorrelationWindow   time.Duration `mapstructure:"correlation_window"`
+	CheckInterval       time.Duration     `mapstructure:"check_interval"`
+	PingTimeout         time.Duration     `mapstructure:"ping_timeout"`
+	PingCount           int               `mapstructure:"ping_count"`
+	ConsecutiveFailures int               `mapstructure:"consecutive_failures"`
+	ResolveThreshold    int               `mapstructure:"resolve_threshold"`
+	RetentionPeriod     time.Duration     `mapstructure:"retention_period"`
+	MaxWorkers          int               `mapstructure:"max_workers"`
+	MaintenanceInterval time.Duration     `mapstructure:"maintenance_interval"`
+	PruneBatchSize      int               `mapstructure:"prune_batch_size"` // Rows deleted per statement when pruning results
+	RollupRetention     time.Duration     `mapstructure:"rollup_retention"` // How long daily metric rollups are kept; 0 keeps them forever
+	CorrelationEnabled  bool              `mapstructure:"correlation_enabled"`
+	CorrelationWindow   time.Duration     `mapstructure:"correlation_window"`
+	CertChangeAlerts    bool              `mapstructure:"cert_change_alerts"`
+	EscalateAfter       time.Duration     `mapstructure:"escalate_after"` // 0 disables escalation
+	FlapWindow          time.Duration     `mapstructure:"flap_window"`    // 0 disables incident roll-up
+	LowPriority         LowPriorityConfig `mapstructure:"low_priority"`
+}
+
+// LowPriorityConfig controls auto-acknowledgement of alerts raised by
+// low-priority checks. A check is low priority when it is flagged itself or
+// when its device carries one of Tags.
+type LowPriorityConfig struct {
+	Tags              []string `mapstructure:"tags"`
+	AutoAckSeverities []string `mapstructure:"auto_ack_severities"`
 }
 
 func DefaultConfig() PulseConfig {
@@ -20,10 +35,19 @@ func DefaultConfig() PulseConfig {
 		PingTimeout:         5 * time.Second,
 		PingCount:           3,
 		ConsecutiveFailures: 3,
-		RetentionPeriod:     30 * 24 * time.Hour,
+		ResolveThreshold:    1,
+		RetentionPeriod:     90 * 24 * time.Hour,
 		MaxWorkers:          10,
 		MaintenanceInterval: 1 * time.Hour,
+		PruneBatchSize:      1000,
+		RollupRetention:     365 * 24 * time.Hour,
 		CorrelationEnabled:  true,
 		CorrelationWindow:   5 * time.Minute,
+		CertChangeAlerts:    true,
+		EscalateAfter:       1 * time.Hour,
+		FlapWindow:          15 * time.Minute,
+		LowPriority: LowPriorityConfig{
+			AutoAckSeverities: []string{"warning", "critical"},
+		},
 	}
 }
@@ -336,7 +336,7 @@ func TestAlerter_CorrelationIntegration(t *testing.T) {
 	ps := correlationTestStore(t)
 	bus := &mockEventBus{}
 	threshold := 3
-	alerter := NewAlerter(ps, bus, threshold, zap.NewNop())
+	alerter := NewAlerter(ps, bus, threshold, 1, zap.NewNop())
 
 	// Enable correlation.
 	corr := NewCorrelationEngine(ps, 5*time.Minute, zap.NewNop())
@@ -11,4 +11,6 @@ const (
 	TopicAlertTriggered   = "pulse.alert.triggered"
 	TopicAlertResolved    = "pulse.alert.resolved"
 	TopicAlertSuppressed  = "pulse.alert.suppressed"
+	TopicAlertEscalated   = "pulse.alert.escalated"
+	TopicCertChanged      = "pulse.cert.changed"
 )
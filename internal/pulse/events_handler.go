@@ -10,7 +10,8 @@ import (
 	"go.uber.org/zap"
 )
 
-// handleDeviceDiscovered auto-creates an ICMP check when Recon discovers a new device.
+// handleDeviceDiscovered auto-creates checks when Recon discovers a new device:
+// those defined by matching monitoring policies, or a single ICMP check.
 func (m *Module) handleDeviceDiscovered(ctx context.Context, event plugin.Event) {
 	if m.store == nil {
 		return
@@ -34,6 +35,12 @@ func (m *Module) handleDeviceDiscovered(ctx context.Context, event plugin.Event)
 		return
 	}
 
+	// Monitoring policies for the device's type take precedence over the
+	// default ICMP check.
+	if m.applyMonitorPolicies(ctx, de.Device) {
+		return
+	}
+
 	// Check if a pulse check already exists for this device.
 	existing, err := m.store.GetCheckByDeviceID(ctx, de.Device.ID)
 	if err != nil {
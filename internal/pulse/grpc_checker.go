@@ -0,0 +1,100 @@
+package pulse
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Compile-time interface guard.
+var _ Checker = (*GRPCChecker)(nil)
+
+// GRPCChecker probes targets that implement the standard gRPC health
+// checking protocol (grpc.health.v1.Health/Check).
+type GRPCChecker struct {
+	timeout     time.Duration
+	dialOptions []grpc.DialOption
+}
+
+// NewGRPCChecker creates a new gRPC health checker with the given timeout.
+// Connections are plaintext, which matches how internal services usually
+// expose their health endpoint.
+func NewGRPCChecker(timeout time.Duration) *GRPCChecker {
+	return &GRPCChecker{
+		timeout: timeout,
+		dialOptions: []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		},
+	}
+}
+
+// parseGRPCTarget splits a "host:port[/service]" target into the dial
+// address and the optional service name. An empty service checks the
+// server's overall health.
+func parseGRPCTarget(target string) (addr, service string, err error) {
+	addr, service, _ = strings.Cut(target, "/")
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return "", "", err
+	}
+	return addr, service, nil
+}
+
+// Check calls the health service on the target. SERVING is a success; any
+// other status is a failure with the status recorded in ErrorMessage.
+func (c *GRPCChecker) Check(ctx context.Context, target string) (*CheckResult, error) {
+	addr, service, err := parseGRPCTarget(target)
+	if err != nil {
+		return &CheckResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("invalid target %q: %v", target, err),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("invalid target %q: %w", target, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, deadlineTimeout(ctx, c.timeout))
+	defer cancel()
+
+	start := time.Now()
+	conn, err := grpc.NewClient("passthrough:///"+addr, c.dialOptions...)
+	if err != nil {
+		return &CheckResult{
+			Success:      false,
+			ErrorMessage: err.Error(),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("grpc client %s: %w", addr, err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return &CheckResult{
+			Success:      false,
+			LatencyMs:    float64(elapsed) / float64(time.Millisecond),
+			ErrorMessage: err.Error(),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("grpc health check %s: %w", target, err)
+	}
+
+	if status := resp.GetStatus(); status != healthpb.HealthCheckResponse_SERVING {
+		return &CheckResult{
+			Success:      false,
+			LatencyMs:    float64(elapsed) / float64(time.Millisecond),
+			ErrorMessage: fmt.Sprintf("health status %s", status),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("grpc health check %s: status %s", target, status)
+	}
+
+	return &CheckResult{
+		Success:   true,
+		LatencyMs: float64(elapsed) / float64(time.Millisecond),
+		CheckedAt: time.Now().UTC(),
+	}, nil
+}
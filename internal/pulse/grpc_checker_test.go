@@ -0,0 +1,113 @@
+package pulse
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// newBufconnGRPCChecker starts an in-memory gRPC health server and returns a
+// checker wired to dial it along with the health server for status changes.
+func newBufconnGRPCChecker(t *testing.T) (*GRPCChecker, *health.Server) {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(srv, hs)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	checker := NewGRPCChecker(2 * time.Second)
+	checker.dialOptions = []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	return checker, hs
+}
+
+func TestGRPCChecker_Serving(t *testing.T) {
+	checker, hs := newBufconnGRPCChecker(t)
+	hs.SetServingStatus("api.v1.Users", healthpb.HealthCheckResponse_SERVING)
+
+	for _, target := range []string{"bufconn:50051", "bufconn:50051/api.v1.Users"} {
+		result, err := checker.Check(context.Background(), target)
+		if err != nil {
+			t.Fatalf("Check(%s): %v", target, err)
+		}
+		if !result.Success {
+			t.Errorf("Check(%s) Success = false (%s), want true", target, result.ErrorMessage)
+		}
+	}
+}
+
+func TestGRPCChecker_NotServing(t *testing.T) {
+	checker, hs := newBufconnGRPCChecker(t)
+	hs.SetServingStatus("api.v1.Users", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	result, err := checker.Check(context.Background(), "bufconn:50051/api.v1.Users")
+	if err == nil {
+		t.Error("Check() error = nil, want error for NOT_SERVING")
+	}
+	if result == nil {
+		t.Fatal("Check() result = nil")
+	}
+	if result.Success {
+		t.Error("Success = true, want false")
+	}
+	if !strings.Contains(result.ErrorMessage, "NOT_SERVING") {
+		t.Errorf("ErrorMessage = %q, want it to contain NOT_SERVING", result.ErrorMessage)
+	}
+}
+
+func TestGRPCChecker_UnknownService(t *testing.T) {
+	checker, _ := newBufconnGRPCChecker(t)
+
+	result, err := checker.Check(context.Background(), "bufconn:50051/missing.Service")
+	if err == nil {
+		t.Error("Check() error = nil, want error for unknown service")
+	}
+	if result == nil || result.Success {
+		t.Fatalf("result = %+v, want failed result", result)
+	}
+}
+
+func TestGRPCChecker_InvalidTarget(t *testing.T) {
+	checker := NewGRPCChecker(time.Second)
+
+	result, err := checker.Check(context.Background(), "no-port/service")
+	if err == nil {
+		t.Error("Check() error = nil, want error for missing port")
+	}
+	if result == nil || result.Success {
+		t.Fatalf("result = %+v, want failed result", result)
+	}
+}
+
+func TestValidateTarget_GRPC(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"10.0.0.1:50051", false},
+		{"svc.internal:443/api.v1.Users", false},
+		{"10.0.0.1", true},
+		{"/api.v1.Users", true},
+	}
+	for _, tt := range tests {
+		err := validateTarget("grpc", tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateTarget(grpc, %q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package pulse
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -12,6 +15,9 @@ import (
 
 	"github.com/google/uuid"
 
+	"github.com/HerbHall/subnetree/internal/auth"
+	"github.com/HerbHall/subnetree/pkg/httperr"
+	"github.com/HerbHall/subnetree/pkg/pagination"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"go.uber.org/zap"
 )
@@ -22,6 +28,18 @@ type createCheckRequest struct {
 	CheckType       string `json:"check_type"`
 	Target          string `json:"target"`
 	IntervalSeconds int    `json:"interval_seconds"`
+	LowPriority     bool   `json:"low_priority"`
+	// Optional response assertions; see Check.
+	ExpectedStatus        int    `json:"expected_status,omitempty"`
+	ExpectedBodySubstring string `json:"expected_body_substring,omitempty"`
+	// Datagram sent by udp checks.
+	Payload string `json:"payload,omitempty"`
+	// Consecutive failures before alerting; omit to use the global default.
+	FailureThreshold *int `json:"failure_threshold,omitempty"`
+	// Check timeout in milliseconds; omit to use the global ping_timeout.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+	// Check whose active alert suppresses this check's alerts.
+	ParentCheckID string `json:"parent_check_id,omitempty"`
 }
 
 // updateCheckRequest is the JSON body for PUT /checks/{id}.
@@ -30,6 +48,18 @@ type updateCheckRequest struct {
 	CheckType       string `json:"check_type,omitempty"`
 	IntervalSeconds int    `json:"interval_seconds,omitempty"`
 	Enabled         *bool  `json:"enabled,omitempty"`
+	LowPriority     *bool  `json:"low_priority,omitempty"`
+	// Optional response assertions; set to 0 or "" to clear.
+	ExpectedStatus        *int    `json:"expected_status,omitempty"`
+	ExpectedBodySubstring *string `json:"expected_body_substring,omitempty"`
+	// Datagram sent by udp checks; set to "" to clear.
+	Payload *string `json:"payload,omitempty"`
+	// Consecutive failures before alerting; set to 0 to use the global default.
+	FailureThreshold *int `json:"failure_threshold,omitempty"`
+	// Check timeout in milliseconds; set to 0 to use the global ping_timeout.
+	TimeoutMs *int `json:"timeout_ms,omitempty"`
+	// Check whose active alert suppresses this check's alerts; set to "" to clear.
+	ParentCheckID *string `json:"parent_check_id,omitempty"`
 }
 
 // createNotificationRequest is the JSON body for POST /notifications.
@@ -72,20 +102,32 @@ func (m *Module) Routes() []plugin.Route {
 	return []plugin.Route{
 		{Method: "GET", Path: "/checks", Handler: m.handleListChecks},
 		{Method: "POST", Path: "/checks", Handler: m.handleCreateCheck},
+		{Method: "POST", Path: "/checks/bulk", Handler: m.handleBulkCreateChecks},
 		{Method: "GET", Path: "/checks/{device_id}", Handler: m.handleDeviceChecks},
 		{Method: "PUT", Path: "/checks/{id}", Handler: m.handleUpdateCheck},
 		{Method: "DELETE", Path: "/checks/{id}", Handler: m.handleDeleteCheck},
 		{Method: "PATCH", Path: "/checks/{id}/toggle", Handler: m.handleToggleCheck},
+		{Method: "POST", Path: "/checks/{id}/mute", Handler: m.handleMuteCheck},
+		{Method: "POST", Path: "/checks/{id}/unmute", Handler: m.handleUnmuteCheck},
 		{Method: "GET", Path: "/checks/{check_id}/dependencies", Handler: m.handleListCheckDependencies},
 		{Method: "POST", Path: "/checks/{check_id}/dependencies", Handler: m.handleAddCheckDependency},
 		{Method: "DELETE", Path: "/checks/{check_id}/dependencies/{device_id}", Handler: m.handleRemoveCheckDependency},
+		{Method: "GET", Path: "/checks/{check_id}/certificate", Handler: m.handleGetCertState},
+		{Method: "POST", Path: "/checks/{check_id}/certificate/expect-rotation", Handler: m.handleExpectCertRotation},
 		{Method: "GET", Path: "/results/{device_id}", Handler: m.handleDeviceResults},
+		{Method: "POST", Path: "/results/backfill", Handler: m.handleBackfillResults, Class: plugin.RouteUpload},
 		{Method: "GET", Path: "/metrics/{device_id}", Handler: m.handleDeviceMetrics},
+		{Method: "GET", Path: "/heatmap", Handler: m.handleHeatmap},
+		{Method: "GET", Path: "/uptime/{device_id}", Handler: m.handleDeviceUptime},
 		{Method: "GET", Path: "/alerts", Handler: m.handleListAlerts},
 		{Method: "GET", Path: "/alerts/correlated", Handler: m.handleCorrelatedAlerts},
 		{Method: "GET", Path: "/alerts/{id}", Handler: m.handleGetAlert},
 		{Method: "POST", Path: "/alerts/{id}/acknowledge", Handler: m.handleAcknowledgeAlert},
+		{Method: "POST", Path: "/alerts/{id}/annotations", Handler: m.handleAddAlertAnnotation},
 		{Method: "POST", Path: "/alerts/{id}/resolve", Handler: m.handleResolveAlert},
+		{Method: "GET", Path: "/incidents", Handler: m.handleListIncidents},
+		{Method: "GET", Path: "/incidents/{id}", Handler: m.handleGetIncident},
+		{Method: "GET", Path: "/status", Handler: m.handleStatusRollup},
 		{Method: "GET", Path: "/status/{device_id}", Handler: m.handleDeviceStatus},
 		{Method: "GET", Path: "/notifications", Handler: m.handleListNotifications},
 		{Method: "GET", Path: "/notifications/{id}", Handler: m.handleGetNotification},
@@ -98,17 +140,28 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "GET", Path: "/maintenance-windows/{id}", Handler: m.handleGetMaintWindow},
 		{Method: "PUT", Path: "/maintenance-windows/{id}", Handler: m.handleUpdateMaintWindow},
 		{Method: "DELETE", Path: "/maintenance-windows/{id}", Handler: m.handleDeleteMaintWindow},
+		{Method: "GET", Path: "/policies", Handler: m.handleListMonitorPolicies},
+		{Method: "POST", Path: "/policies", Handler: m.handleCreateMonitorPolicy},
+		{Method: "GET", Path: "/policies/{id}", Handler: m.handleGetMonitorPolicy},
+		{Method: "PUT", Path: "/policies/{id}", Handler: m.handleUpdateMonitorPolicy},
+		{Method: "DELETE", Path: "/policies/{id}", Handler: m.handleDeleteMonitorPolicy},
 	}
 }
 
-// handleListChecks returns all registered monitoring checks.
+// handleListChecks returns the registered monitoring checks. All checks are
+// returned unless limit is given; X-Total-Count holds the number of checks.
 //
 //	@Summary		List checks
-//	@Description	Returns all monitoring checks (enabled and disabled).
+//	@Description	Returns monitoring checks (enabled and disabled). X-Total-Count holds the total number of checks;
+//	@Description	pass envelope=true to get an object with items, total, limit, and offset instead of an array.
 //	@Tags			pulse
 //	@Produce		json
 //	@Security		BearerAuth
+//	@Param			limit query int false "Maximum checks (default: all)"
+//	@Param			offset query int false "Offset" default(0)
+//	@Param			envelope query bool false "Wrap the results with pagination metadata"
 //	@Success		200 {array} Check
+//	@Header			200 {integer} X-Total-Count "Total number of checks"
 //	@Failure		500 {object} map[string]any
 //	@Router			/pulse/checks [get]
 func (m *Module) handleListChecks(w http.ResponseWriter, r *http.Request) {
@@ -122,10 +175,9 @@ func (m *Module) handleListChecks(w http.ResponseWriter, r *http.Request) {
 		pulseWriteError(w, http.StatusInternalServerError, "failed to list checks")
 		return
 	}
-	if checks == nil {
-		checks = []Check{}
-	}
-	pulseWriteJSON(w, http.StatusOK, checks)
+	limit := pulseParseLimit(r, 0)
+	offset := pulseParseOffset(r)
+	pagination.Write(w, r, pagination.Slice(checks, limit, offset), len(checks), limit, offset)
 }
 
 // handleCreateCheck creates a new monitoring check.
@@ -153,27 +205,13 @@ func (m *Module) handleCreateCheck(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.DeviceID == "" {
-		pulseWriteError(w, http.StatusBadRequest, "device_id is required")
-		return
-	}
-
-	// Validate check_type.
-	switch req.CheckType {
-	case "icmp", "tcp", "http":
-		// valid
-	default:
-		pulseWriteError(w, http.StatusBadRequest, "check_type must be icmp, tcp, or http")
+	if err := validateCreateCheck(&req); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	// Validate target based on check type.
-	if req.Target == "" {
-		pulseWriteError(w, http.StatusBadRequest, "target is required")
-		return
-	}
-	if err := validateTarget(req.CheckType, req.Target); err != nil {
-		pulseWriteError(w, http.StatusBadRequest, err.Error())
+	if err := m.validateParentCheck(r.Context(), "", req.ParentCheckID); err != nil {
+		m.writeParentCheckError(w, err)
 		return
 	}
 
@@ -183,14 +221,21 @@ func (m *Module) handleCreateCheck(w http.ResponseWriter, r *http.Request) {
 
 	now := time.Now().UTC()
 	check := &Check{
-		ID:              fmt.Sprintf("pulse-%s-%s-%d", req.DeviceID, req.CheckType, now.UnixMilli()),
-		DeviceID:        req.DeviceID,
-		CheckType:       req.CheckType,
-		Target:          req.Target,
-		IntervalSeconds: req.IntervalSeconds,
-		Enabled:         true,
-		CreatedAt:       now,
-		UpdatedAt:       now,
+		ID:                    fmt.Sprintf("pulse-%s-%s-%d", req.DeviceID, req.CheckType, now.UnixMilli()),
+		DeviceID:              req.DeviceID,
+		CheckType:             req.CheckType,
+		Target:                req.Target,
+		IntervalSeconds:       req.IntervalSeconds,
+		Enabled:               true,
+		LowPriority:           req.LowPriority,
+		ExpectedStatus:        req.ExpectedStatus,
+		ExpectedBodySubstring: req.ExpectedBodySubstring,
+		FailureThreshold:      req.FailureThreshold,
+		TimeoutMs:             req.TimeoutMs,
+		Payload:               req.Payload,
+		ParentCheckID:         req.ParentCheckID,
+		CreatedAt:             now,
+		UpdatedAt:             now,
 	}
 
 	if err := m.store.InsertCheck(r.Context(), check); err != nil {
@@ -248,10 +293,10 @@ func (m *Module) handleUpdateCheck(w http.ResponseWriter, r *http.Request) {
 
 	if req.CheckType != "" {
 		switch req.CheckType {
-		case "icmp", "tcp", "http":
+		case "icmp", "tcp", "http", "tls", "grpc", "udp":
 			existing.CheckType = req.CheckType
 		default:
-			pulseWriteError(w, http.StatusBadRequest, "check_type must be icmp, tcp, or http")
+			pulseWriteError(w, http.StatusBadRequest, "check_type must be icmp, tcp, http, tls, grpc, or udp")
 			return
 		}
 	}
@@ -268,6 +313,51 @@ func (m *Module) handleUpdateCheck(w http.ResponseWriter, r *http.Request) {
 	if req.Enabled != nil {
 		existing.Enabled = *req.Enabled
 	}
+	if req.LowPriority != nil {
+		existing.LowPriority = *req.LowPriority
+	}
+	if req.ExpectedStatus != nil {
+		existing.ExpectedStatus = *req.ExpectedStatus
+	}
+	if req.ExpectedBodySubstring != nil {
+		existing.ExpectedBodySubstring = *req.ExpectedBodySubstring
+	}
+	if req.Payload != nil {
+		existing.Payload = *req.Payload
+	}
+	if err := validateExpectations(existing.CheckType, existing.ExpectedStatus, existing.ExpectedBodySubstring); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validatePayload(existing.CheckType, existing.Payload); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if req.FailureThreshold != nil {
+		switch {
+		case *req.FailureThreshold == 0:
+			existing.FailureThreshold = nil
+		case *req.FailureThreshold < 0:
+			pulseWriteError(w, http.StatusBadRequest, "failure_threshold must be at least 1")
+			return
+		default:
+			existing.FailureThreshold = req.FailureThreshold
+		}
+	}
+	if req.TimeoutMs != nil {
+		if err := validateTimeoutMs(*req.TimeoutMs); err != nil {
+			pulseWriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		existing.TimeoutMs = *req.TimeoutMs
+	}
+	if req.ParentCheckID != nil {
+		if err := m.validateParentCheck(r.Context(), existing.ID, *req.ParentCheckID); err != nil {
+			m.writeParentCheckError(w, err)
+			return
+		}
+		existing.ParentCheckID = *req.ParentCheckID
+	}
 	existing.UpdatedAt = time.Now().UTC()
 
 	if err := m.store.UpdateCheck(r.Context(), existing); err != nil {
@@ -356,6 +446,100 @@ func (m *Module) handleToggleCheck(w http.ResponseWriter, r *http.Request) {
 	pulseWriteJSON(w, http.StatusOK, existing)
 }
 
+// defaultMuteDuration is how long a check stays muted when no duration is given.
+const defaultMuteDuration = time.Hour
+
+// maxMuteDuration bounds a single mute; longer silences belong in a
+// maintenance window or disabling the check.
+const maxMuteDuration = 30 * 24 * time.Hour
+
+// MuteCheckRequest is the optional request body for POST /checks/{id}/mute.
+type MuteCheckRequest struct {
+	Duration string `json:"duration,omitempty" example:"4h"`
+}
+
+// handleMuteCheck silences notifications for a check for a while.
+//
+//	@Summary		Mute check
+//	@Description	Withholds alert notifications for a check until the mute expires. Results and alert state are still recorded; if the check is still failing when the mute expires its alert is published then. Duration defaults to 1h and may be at most 720h.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string				true	"Check ID"
+//	@Param			request	body		MuteCheckRequest	false	"Mute duration"
+//	@Success		200		{object}	Check
+//	@Failure		400		{object}	map[string]any
+//	@Failure		404		{object}	map[string]any
+//	@Failure		500		{object}	map[string]any
+//	@Router			/pulse/checks/{id}/mute [post]
+func (m *Module) handleMuteCheck(w http.ResponseWriter, r *http.Request) {
+	var req MuteCheckRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			pulseWriteError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+	duration := defaultMuteDuration
+	if req.Duration != "" {
+		d, err := time.ParseDuration(req.Duration)
+		if err != nil || d <= 0 || d > maxMuteDuration {
+			pulseWriteError(w, http.StatusBadRequest, "duration must be a positive duration of at most 720h")
+			return
+		}
+		duration = d
+	}
+	until := time.Now().UTC().Add(duration)
+	m.setCheckMute(w, r, &until)
+}
+
+// handleUnmuteCheck ends a check's mute early.
+//
+//	@Summary		Unmute check
+//	@Description	Ends a check's notification mute. An alert raised while muted is published on the check's next failure.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Check ID"
+//	@Success		200	{object}	Check
+//	@Failure		404	{object}	map[string]any
+//	@Failure		500	{object}	map[string]any
+//	@Router			/pulse/checks/{id}/unmute [post]
+func (m *Module) handleUnmuteCheck(w http.ResponseWriter, r *http.Request) {
+	m.setCheckMute(w, r, nil)
+}
+
+// setCheckMute sets or clears the mute on the check named in the path and
+// writes the updated check.
+func (m *Module) setCheckMute(w http.ResponseWriter, r *http.Request, until *time.Time) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	existing, err := m.store.GetCheck(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get check for mute", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get check")
+		return
+	}
+	if existing == nil {
+		pulseWriteError(w, http.StatusNotFound, "check not found")
+		return
+	}
+
+	if err := m.store.SetCheckMute(r.Context(), id, until); err != nil {
+		m.logger.Warn("failed to set check mute", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to update check mute")
+		return
+	}
+
+	existing.MutedUntil = until
+	pulseWriteJSON(w, http.StatusOK, existing)
+}
+
 // handleDeviceChecks returns checks for a specific device.
 //
 //	@Summary		Device checks
@@ -394,7 +578,7 @@ func (m *Module) handleDeviceChecks(w http.ResponseWriter, r *http.Request) {
 // handleDeviceResults returns recent check results for a device.
 //
 //	@Summary		Device results
-//	@Description	Returns recent check results for a specific device.
+//	@Description	Returns recent check results for a specific device. HTTP check results include a DNS, connect, TLS, and time-to-first-byte breakdown.
 //	@Tags			pulse
 //	@Produce		json
 //	@Security		BearerAuth
@@ -464,8 +648,12 @@ func (m *Module) handleCorrelatedAlerts(w http.ResponseWriter, r *http.Request)
 //	@Param			device_id query string false "Filter by device ID"
 //	@Param			severity query string false "Filter by severity (warning, critical)"
 //	@Param			active query bool false "Only active (unresolved) alerts" default(true)
+//	@Param			low_priority query string false "Low-priority alerts: true, false, or all (active view defaults to false)"
 //	@Param			limit query int false "Maximum alerts" default(50)
+//	@Param			offset query int false "Offset" default(0)
+//	@Param			envelope query bool false "Wrap the results with pagination metadata"
 //	@Success		200 {array} Alert
+//	@Header			200 {integer} X-Total-Count "Number of alerts matching the filters"
 //	@Failure		500 {object} map[string]any
 //	@Router			/pulse/alerts [get]
 func (m *Module) handleListAlerts(w http.ResponseWriter, r *http.Request) {
@@ -479,6 +667,7 @@ func (m *Module) handleListAlerts(w http.ResponseWriter, r *http.Request) {
 		Severity:   r.URL.Query().Get("severity"),
 		ActiveOnly: true,
 		Limit:      pulseParseLimit(r, 50),
+		Offset:     pulseParseOffset(r),
 	}
 
 	if activeStr := r.URL.Query().Get("active"); activeStr != "" {
@@ -488,6 +677,19 @@ func (m *Module) handleListAlerts(w http.ResponseWriter, r *http.Request) {
 		v := suppressedStr == "true"
 		filters.Suppressed = &v
 	}
+	// Low-priority alerts are auto-acknowledged noise; keep them out of the
+	// default active view unless explicitly requested.
+	switch lowStr := r.URL.Query().Get("low_priority"); lowStr {
+	case "":
+		if filters.ActiveOnly {
+			v := false
+			filters.LowPriority = &v
+		}
+	case "all":
+	default:
+		v := lowStr == "true"
+		filters.LowPriority = &v
+	}
 
 	alerts, err := m.store.ListAlerts(r.Context(), filters)
 	if err != nil {
@@ -495,16 +697,41 @@ func (m *Module) handleListAlerts(w http.ResponseWriter, r *http.Request) {
 		pulseWriteError(w, http.StatusInternalServerError, "failed to list alerts")
 		return
 	}
-	if alerts == nil {
-		alerts = []Alert{}
+	total, err := m.store.CountAlerts(r.Context(), filters)
+	if err != nil {
+		m.logger.Warn("failed to count alerts", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to list alerts")
+		return
 	}
-	pulseWriteJSON(w, http.StatusOK, alerts)
+	pagination.Write(w, r, alerts, total, filters.Limit, filters.Offset)
+}
+
+// maxAnnotationLength caps the size of an alert comment or note.
+const maxAnnotationLength = 4096
+
+// acknowledgeAlertRequest is the optional JSON body for POST /alerts/{id}/acknowledge.
+type acknowledgeAlertRequest struct {
+	Comment string `json:"comment"`
+}
+
+// alertAnnotationRequest is the JSON body for POST /alerts/{id}/annotations.
+type alertAnnotationRequest struct {
+	Body string `json:"body"`
+}
+
+// requestUsername returns the authenticated caller's username, or "" when
+// the request is unauthenticated.
+func requestUsername(r *http.Request) string {
+	if c := auth.UserFromContext(r.Context()); c != nil {
+		return c.Username
+	}
+	return ""
 }
 
 // handleGetAlert returns a single alert by ID.
 //
 //	@Summary		Get alert
-//	@Description	Returns a single monitoring alert by ID.
+//	@Description	Returns a single monitoring alert by ID, including its annotation timeline.
 //	@Tags			pulse
 //	@Produce		json
 //	@Security		BearerAuth
@@ -536,18 +763,28 @@ func (m *Module) handleGetAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	alert.Annotations, err = m.store.ListAlertAnnotations(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to list alert annotations", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get alert")
+		return
+	}
+
 	pulseWriteJSON(w, http.StatusOK, alert)
 }
 
 // handleAcknowledgeAlert acknowledges an alert.
 //
 //	@Summary		Acknowledge alert
-//	@Description	Marks an alert as acknowledged.
+//	@Description	Marks an alert as acknowledged by the caller, with an optional comment that is added to the alert's annotations.
 //	@Tags			pulse
+//	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			id path string true "Alert ID"
+//	@Param			body body acknowledgeAlertRequest false "Optional comment"
 //	@Success		200 {object} Alert
+//	@Failure		400 {object} map[string]any
 //	@Failure		404 {object} map[string]any
 //	@Failure		500 {object} map[string]any
 //	@Router			/pulse/alerts/{id}/acknowledge [post]
@@ -563,7 +800,19 @@ func (m *Module) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if err := m.store.AcknowledgeAlert(r.Context(), id); err != nil {
+	// The body is optional; an empty one acknowledges without a comment.
+	var req acknowledgeAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Comment = strings.TrimSpace(req.Comment)
+	if len(req.Comment) > maxAnnotationLength {
+		pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("comment must be at most %d bytes", maxAnnotationLength))
+		return
+	}
+
+	if err := m.store.AcknowledgeAlertBy(r.Context(), id, requestUsername(r), req.Comment); err != nil {
 		m.logger.Warn("failed to acknowledge alert", zap.String("id", id), zap.Error(err))
 		pulseWriteError(w, http.StatusInternalServerError, "failed to acknowledge alert")
 		return
@@ -580,9 +829,84 @@ func (m *Module) handleAcknowledgeAlert(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	alert.Annotations, err = m.store.ListAlertAnnotations(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to list alert annotations", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get alert")
+		return
+	}
+
 	pulseWriteJSON(w, http.StatusOK, alert)
 }
 
+// handleAddAlertAnnotation appends a note to an alert's timeline.
+//
+//	@Summary		Annotate alert
+//	@Description	Adds a note to an alert's annotation timeline, attributed to the caller.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id path string true "Alert ID"
+//	@Param			body body alertAnnotationRequest true "Note"
+//	@Success		201 {object} AlertAnnotation
+//	@Failure		400 {object} map[string]any
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/alerts/{id}/annotations [post]
+func (m *Module) handleAddAlertAnnotation(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	id := r.PathValue("id")
+	if id == "" {
+		pulseWriteError(w, http.StatusBadRequest, "id is required")
+		return
+	}
+
+	var req alertAnnotationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	req.Body = strings.TrimSpace(req.Body)
+	if req.Body == "" {
+		pulseWriteError(w, http.StatusBadRequest, "body is required")
+		return
+	}
+	if len(req.Body) > maxAnnotationLength {
+		pulseWriteError(w, http.StatusBadRequest, fmt.Sprintf("body must be at most %d bytes", maxAnnotationLength))
+		return
+	}
+
+	alert, err := m.store.GetAlert(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get alert for annotation", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get alert")
+		return
+	}
+	if alert == nil {
+		pulseWriteError(w, http.StatusNotFound, "alert not found")
+		return
+	}
+
+	annotation := &AlertAnnotation{
+		AlertID: id,
+		Kind:    AnnotationNote,
+		Author:  requestUsername(r),
+		Body:    req.Body,
+	}
+	if err := m.store.AddAlertAnnotation(r.Context(), annotation); err != nil {
+		m.logger.Warn("failed to add alert annotation", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to add alert annotation")
+		return
+	}
+
+	pulseWriteJSON(w, http.StatusCreated, annotation)
+}
+
 // handleResolveAlert resolves an alert.
 //
 //	@Summary		Resolve alert
@@ -613,6 +937,7 @@ func (m *Module) handleResolveAlert(w http.ResponseWriter, r *http.Request) {
 		pulseWriteError(w, http.StatusInternalServerError, "failed to resolve alert")
 		return
 	}
+	m.syncAlertMetrics(r.Context())
 
 	alert, err := m.store.GetAlert(r.Context(), id)
 	if err != nil {
@@ -838,6 +1163,85 @@ func (m *Module) handleRemoveCheckDependency(w http.ResponseWriter, r *http.Requ
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// -- TLS certificate handlers --
+
+// handleGetCertState returns the last-seen certificate for a tls check.
+//
+//	@Summary		Check certificate
+//	@Description	Returns the last-seen TLS certificate for a check, including the previous certificate if it changed.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			check_id path string true "Check ID"
+//	@Success		200 {object} CertState
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/checks/{check_id}/certificate [get]
+func (m *Module) handleGetCertState(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	checkID := r.PathValue("check_id")
+	if checkID == "" {
+		pulseWriteError(w, http.StatusBadRequest, "check_id is required")
+		return
+	}
+	cs, err := m.store.GetCertState(r.Context(), checkID)
+	if err != nil {
+		m.logger.Warn("failed to get cert state", zap.String("check_id", checkID), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get certificate")
+		return
+	}
+	if cs == nil {
+		pulseWriteError(w, http.StatusNotFound, "no certificate recorded for check")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, cs)
+}
+
+// handleExpectCertRotation acknowledges an upcoming certificate rotation so
+// the next change does not raise a cert-changed alert.
+//
+//	@Summary		Expect certificate rotation
+//	@Description	Suppresses the cert-changed alert for the next certificate change on a tls check.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			check_id path string true "Check ID"
+//	@Success		200 {object} CertState
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/checks/{check_id}/certificate/expect-rotation [post]
+func (m *Module) handleExpectCertRotation(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	checkID := r.PathValue("check_id")
+	if checkID == "" {
+		pulseWriteError(w, http.StatusBadRequest, "check_id is required")
+		return
+	}
+	found, err := m.store.SetCertExpectRotation(r.Context(), checkID, true)
+	if err != nil {
+		m.logger.Warn("failed to set expected rotation", zap.String("check_id", checkID), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to acknowledge rotation")
+		return
+	}
+	if !found {
+		pulseWriteError(w, http.StatusNotFound, "no certificate recorded for check")
+		return
+	}
+	cs, err := m.store.GetCertState(r.Context(), checkID)
+	if err != nil || cs == nil {
+		m.logger.Warn("failed to get cert state after acknowledge", zap.String("check_id", checkID), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get certificate")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, cs)
+}
+
 // -- helpers --
 
 func pulseWriteJSON(w http.ResponseWriter, status int, data any) {
@@ -847,14 +1251,7 @@ func pulseWriteJSON(w http.ResponseWriter, status int, data any) {
 }
 
 func pulseWriteError(w http.ResponseWriter, status int, detail string) {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"type":   "https://subnetree.com/problems/" + http.StatusText(status),
-		"title":  http.StatusText(status),
-		"status": status,
-		"detail": detail,
-	})
+	httperr.WriteProblem(w, status, detail)
 }
 
 func pulseParseLimit(r *http.Request, defaultLimit int) int {
@@ -866,6 +1263,17 @@ func pulseParseLimit(r *http.Request, defaultLimit int) int {
 	return defaultLimit
 }
 
+// pulseParseOffset returns the offset query parameter, or 0 when it is
+// missing or invalid.
+func pulseParseOffset(r *http.Request) int {
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return 0
+}
+
 // -- Notification channel handlers --
 
 // handleListNotifications returns all notification channels.
@@ -1406,6 +1814,133 @@ func (m *Module) handleDeleteMaintWindow(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// validateCreateCheck validates the fields of a create check request.
+func validateCreateCheck(req *createCheckRequest) error {
+	if req.DeviceID == "" {
+		return fmt.Errorf("device_id is required")
+	}
+
+	// Validate check_type.
+	switch req.CheckType {
+	case "icmp", "tcp", "http", "tls", "grpc", "udp":
+		// valid
+	default:
+		return fmt.Errorf("check_type must be icmp, tcp, http, tls, grpc, or udp")
+	}
+
+	// Validate target based on check type.
+	if req.Target == "" {
+		return fmt.Errorf("target is required")
+	}
+	if err := validateTarget(req.CheckType, req.Target); err != nil {
+		return err
+	}
+	if req.FailureThreshold != nil && *req.FailureThreshold < 1 {
+		return fmt.Errorf("failure_threshold must be at least 1")
+	}
+	if err := validateTimeoutMs(req.TimeoutMs); err != nil {
+		return err
+	}
+	if err := validatePayload(req.CheckType, req.Payload); err != nil {
+		return err
+	}
+	return validateExpectations(req.CheckType, req.ExpectedStatus, req.ExpectedBodySubstring)
+}
+
+var (
+	errParentCheckNotFound = errors.New("parent_check_id does not match an existing check")
+	errParentCheckCycle    = errors.New("parent_check_id would create a dependency cycle")
+)
+
+// validateParentCheck verifies that parentID names an existing check and
+// that making it the parent of checkID would not create a dependency cycle.
+// checkID is empty for checks that do not exist yet. An empty parentID is
+// always valid.
+func (m *Module) validateParentCheck(ctx context.Context, checkID, parentID string) error {
+	if parentID == "" {
+		return nil
+	}
+	visited := make(map[string]bool)
+	for cur := parentID; cur != ""; {
+		if cur == checkID || visited[cur] {
+			return errParentCheckCycle
+		}
+		visited[cur] = true
+		c, err := m.store.GetCheck(ctx, cur)
+		if err != nil {
+			return fmt.Errorf("get parent check: %w", err)
+		}
+		if c == nil {
+			if cur == parentID {
+				return errParentCheckNotFound
+			}
+			break
+		}
+		cur = c.ParentCheckID
+	}
+	return nil
+}
+
+// writeParentCheckError reports a validateParentCheck failure, mapping
+// validation errors to 400 and store errors to 500.
+func (m *Module) writeParentCheckError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errParentCheckNotFound) || errors.Is(err, errParentCheckCycle) {
+		pulseWriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	m.logger.Warn("failed to validate parent check", zap.Error(err))
+	pulseWriteError(w, http.StatusInternalServerError, "failed to validate parent check")
+}
+
+// maxUDPPayload caps the datagram a udp check sends.
+const maxUDPPayload = 1024
+
+// validatePayload validates the optional udp payload.
+func validatePayload(checkType, payload string) error {
+	if payload == "" {
+		return nil
+	}
+	if checkType != "udp" {
+		return fmt.Errorf("payload is only valid for udp checks")
+	}
+	if len(payload) > maxUDPPayload {
+		return fmt.Errorf("payload must be at most %d bytes", maxUDPPayload)
+	}
+	return nil
+}
+
+// Bounds for a per-check timeout.
+const (
+	minCheckTimeoutMs = 100
+	maxCheckTimeoutMs = 60000
+)
+
+// validateTimeoutMs validates a per-check timeout; 0 means use the default.
+func validateTimeoutMs(ms int) error {
+	if ms != 0 && (ms < minCheckTimeoutMs || ms > maxCheckTimeoutMs) {
+		return fmt.Errorf("timeout_ms must be between %d and %d", minCheckTimeoutMs, maxCheckTimeoutMs)
+	}
+	return nil
+}
+
+// validateExpectations validates the optional response assertions. Both
+// apply to http checks; udp checks accept only the substring.
+func validateExpectations(checkType string, expectedStatus int, expectedBody string) error {
+	if expectedStatus == 0 && expectedBody == "" {
+		return nil
+	}
+	if checkType == "udp" && expectedStatus == 0 {
+		return nil
+	}
+	if checkType != "http" {
+		return fmt.Errorf("expected_status and expected_body_substring are only valid for http checks")
+	}
+	if expectedStatus != 0 && (expectedStatus < 100 || expectedStatus > 599) {
+		return fmt.Errorf("expected_status must be between 100 and 599")
+	}
+	return nil
+}
+
 // validateTarget validates a check target based on the check type.
 func validateTarget(checkType, target string) error {
 	switch checkType {
@@ -1420,6 +1955,22 @@ func validateTarget(checkType, target string) error {
 		if _, _, err := net.SplitHostPort(target); err != nil {
 			return fmt.Errorf("tcp target must be host:port format")
 		}
+	case "tls":
+		if _, _, err := net.SplitHostPort(target); err != nil {
+			return fmt.Errorf("tls target must be host:port format")
+		}
+	case "udp":
+		host, port, err := net.SplitHostPort(target)
+		if err != nil || host == "" {
+			return fmt.Errorf("udp target must be host:port format")
+		}
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("udp target port must be between 1 and 65535")
+		}
+	case "grpc":
+		if _, _, err := parseGRPCTarget(target); err != nil {
+			return fmt.Errorf("grpc target must be host:port or host:port/service format")
+		}
 	case "http":
 		u, err := url.Parse(target)
 		if err != nil {
@@ -3,12 +3,15 @@ package pulse
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/auth"
+	"github.com/HerbHall/subnetree/pkg/pagination"
 	"github.com/HerbHall/subnetree/pkg/roles"
 	"go.uber.org/zap"
 )
@@ -89,6 +92,43 @@ func TestHandleListChecks_WithData(t *testing.T) {
 	}
 }
 
+func TestHandleListChecks_Envelope(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i := 0; i < 3; i++ {
+		check := &Check{
+			ID: fmt.Sprintf("check-%d", i), DeviceID: fmt.Sprintf("dev-%d", i), CheckType: "icmp",
+			Target: "192.168.1.1", IntervalSeconds: 60, Enabled: true,
+			CreatedAt: now.Add(time.Duration(i) * time.Second), UpdatedAt: now,
+		}
+		if err := m.store.InsertCheck(context.Background(), check); err != nil {
+			t.Fatalf("insert check: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/checks?limit=2&envelope=true", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleListChecks(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get(pagination.TotalCountHeader); got != "3" {
+		t.Errorf("%s = %q, want 3", pagination.TotalCountHeader, got)
+	}
+	var page pagination.Page[Check]
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(page.Items) != 2 || page.Total != 3 || page.Limit != 2 {
+		t.Errorf("page = %d items, total %d, limit %d; want 2, 3, 2", len(page.Items), page.Total, page.Limit)
+	}
+	if page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Errorf("next_offset = %v, want 2", page.NextOffset)
+	}
+}
+
 func TestHandleListChecks_NilStore(t *testing.T) {
 	m := &Module{logger: zap.NewNop()}
 	req := httptest.NewRequest(http.MethodGet, "/checks", http.NoBody)
@@ -176,6 +216,98 @@ func TestHandleCreateCheck_InvalidType(t *testing.T) {
 	}
 }
 
+func TestHandleCreateCheck_FailureThreshold(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	body := `{"device_id":"dev-1","check_type":"icmp","target":"10.0.0.1","failure_threshold":0}`
+	req := httptest.NewRequest(http.MethodPost, "/checks", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.handleCreateCheck(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("failure_threshold=0: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	body = `{"device_id":"dev-1","check_type":"icmp","target":"10.0.0.1","failure_threshold":10}`
+	req = httptest.NewRequest(http.MethodPost, "/checks", strings.NewReader(body))
+	w = httptest.NewRecorder()
+	m.handleCreateCheck(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("failure_threshold=10: status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	var check Check
+	if err := json.NewDecoder(w.Body).Decode(&check); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if check.FailureThreshold == nil || *check.FailureThreshold != 10 {
+		t.Errorf("FailureThreshold = %v, want 10", check.FailureThreshold)
+	}
+}
+
+func TestHandleCreateCheck_TimeoutMs(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	for _, ms := range []int{50, 60001} {
+		body := fmt.Sprintf(`{"device_id":"dev-1","check_type":"icmp","target":"10.0.0.1","timeout_ms":%d}`, ms)
+		req := httptest.NewRequest(http.MethodPost, "/checks", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		m.handleCreateCheck(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("timeout_ms=%d: status = %d, want %d", ms, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	body := `{"device_id":"dev-1","check_type":"icmp","target":"10.0.0.1","timeout_ms":15000}`
+	req := httptest.NewRequest(http.MethodPost, "/checks", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	m.handleCreateCheck(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("timeout_ms=15000: status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	var check Check
+	if err := json.NewDecoder(w.Body).Decode(&check); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if check.TimeoutMs != 15000 {
+		t.Errorf("TimeoutMs = %d, want 15000", check.TimeoutMs)
+	}
+}
+
+func TestHandleCreateCheck_HTTPExpectations(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"valid", `{"device_id":"dev-1","check_type":"http","target":"http://10.0.0.1/health","expected_status":200,"expected_body_substring":"ok"}`, http.StatusCreated},
+		{"status too low", `{"device_id":"dev-1","check_type":"http","target":"http://10.0.0.1/","expected_status":99}`, http.StatusBadRequest},
+		{"status too high", `{"device_id":"dev-1","check_type":"http","target":"http://10.0.0.1/","expected_status":600}`, http.StatusBadRequest},
+		{"not http", `{"device_id":"dev-1","check_type":"tcp","target":"10.0.0.1:22","expected_body_substring":"ok"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestModule(t)
+			req := httptest.NewRequest(http.MethodPost, "/checks", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+
+			m.handleCreateCheck(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+			var check Check
+			if err := json.NewDecoder(w.Body).Decode(&check); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if check.ExpectedStatus != 200 || check.ExpectedBodySubstring != "ok" {
+				t.Errorf("expectations = %d/%q, want 200/\"ok\"", check.ExpectedStatus, check.ExpectedBodySubstring)
+			}
+		})
+	}
+}
+
 func TestHandleCreateCheck_InvalidTarget_TCP(t *testing.T) {
 	m, _ := newTestModule(t)
 
@@ -247,6 +379,53 @@ func TestHandleUpdateCheck_Success(t *testing.T) {
 	}
 }
 
+func TestHandleUpdateCheck_ParentCheckCycle(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	now := time.Now().UTC()
+	for _, c := range []*Check{
+		{ID: "check-a", DeviceID: "dev-1", CheckType: "icmp", Target: "10.0.0.1", IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now},
+		{ID: "check-b", DeviceID: "dev-2", CheckType: "icmp", Target: "10.0.0.2", IntervalSeconds: 30, Enabled: true, ParentCheckID: "check-a", CreatedAt: now, UpdatedAt: now},
+	} {
+		if err := m.store.InsertCheck(context.Background(), c); err != nil {
+			t.Fatalf("insert check: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+	}{
+		{"self", "check-a", `{"parent_check_id":"check-a"}`, http.StatusBadRequest},
+		{"cycle", "check-a", `{"parent_check_id":"check-b"}`, http.StatusBadRequest},
+		{"unknown parent", "check-a", `{"parent_check_id":"missing"}`, http.StatusBadRequest},
+		{"clear", "check-b", `{"parent_check_id":""}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/checks/"+tt.id, strings.NewReader(tt.body))
+			req.SetPathValue("id", tt.id)
+			w := httptest.NewRecorder()
+
+			m.handleUpdateCheck(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+
+	got, err := m.store.GetCheck(context.Background(), "check-b")
+	if err != nil {
+		t.Fatalf("GetCheck: %v", err)
+	}
+	if got.ParentCheckID != "" {
+		t.Errorf("ParentCheckID = %q after clear, want empty", got.ParentCheckID)
+	}
+}
+
 func TestHandleUpdateCheck_NotFound(t *testing.T) {
 	m, _ := newTestModule(t)
 
@@ -355,6 +534,101 @@ func TestHandleToggleCheck_NotFound(t *testing.T) {
 	}
 }
 
+// -- handleMuteCheck / handleUnmuteCheck tests --
+
+func TestHandleMuteCheck(t *testing.T) {
+	m, _ := newTestModule(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	check := &Check{
+		ID:              "check-1",
+		DeviceID:        "dev-1",
+		CheckType:       "icmp",
+		Target:          "192.168.1.1",
+		IntervalSeconds: 30,
+		Enabled:         true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := m.store.InsertCheck(ctx, check); err != nil {
+		t.Fatalf("insert check: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		want     int
+		duration time.Duration
+	}{
+		{"default duration", "", http.StatusOK, defaultMuteDuration},
+		{"explicit duration", `{"duration":"4h"}`, http.StatusOK, 4 * time.Hour},
+		{"bad duration", `{"duration":"soon"}`, http.StatusBadRequest, 0},
+		{"negative duration", `{"duration":"-1h"}`, http.StatusBadRequest, 0},
+		{"too long", `{"duration":"721h"}`, http.StatusBadRequest, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/checks/check-1/mute", http.NoBody)
+			if tt.body != "" {
+				req = httptest.NewRequest(http.MethodPost, "/checks/check-1/mute", strings.NewReader(tt.body))
+			}
+			req.SetPathValue("id", "check-1")
+			w := httptest.NewRecorder()
+			before := time.Now().UTC()
+
+			m.handleMuteCheck(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var muted Check
+			if err := json.NewDecoder(w.Body).Decode(&muted); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if muted.MutedUntil == nil || muted.MutedUntil.Before(before.Add(tt.duration)) {
+				t.Errorf("MutedUntil = %v, want about %v", muted.MutedUntil, before.Add(tt.duration))
+			}
+			stored, err := m.store.GetCheck(ctx, "check-1")
+			if err != nil || stored == nil || stored.MutedUntil == nil {
+				t.Fatalf("stored check = %+v, %v, want muted", stored, err)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/checks/check-1/unmute", http.NoBody)
+	req.SetPathValue("id", "check-1")
+	w := httptest.NewRecorder()
+	m.handleUnmuteCheck(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unmute status = %d, want %d", w.Code, http.StatusOK)
+	}
+	stored, err := m.store.GetCheck(ctx, "check-1")
+	if err != nil || stored == nil {
+		t.Fatalf("GetCheck: %+v, %v", stored, err)
+	}
+	if stored.MutedUntil != nil {
+		t.Errorf("MutedUntil = %v after unmute, want nil", stored.MutedUntil)
+	}
+}
+
+func TestHandleMuteCheck_NotFound(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/checks/nonexistent/mute", http.NoBody)
+	req.SetPathValue("id", "nonexistent")
+	w := httptest.NewRecorder()
+
+	m.handleMuteCheck(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
 // -- handleDeviceChecks tests --
 
 func TestHandleDeviceChecks_Found(t *testing.T) {
@@ -677,6 +951,49 @@ func TestHandleListAlerts_WithData(t *testing.T) {
 	}
 }
 
+func TestHandleListAlerts_TotalCount(t *testing.T) {
+	m, _ := newTestModule(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	check := &Check{
+		ID: "check-1", DeviceID: "dev-1", CheckType: "icmp",
+		Target: "192.168.1.1", IntervalSeconds: 60, Enabled: true,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := m.store.InsertCheck(ctx, check); err != nil {
+		t.Fatalf("insert check: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		alert := &Alert{
+			ID: fmt.Sprintf("alert-%d", i), CheckID: "check-1", DeviceID: "dev-1",
+			Severity: "warning", Message: "Device unreachable",
+			TriggeredAt: now.Add(time.Duration(i) * time.Second), ConsecutiveFailures: 3,
+		}
+		if err := m.store.InsertAlert(ctx, alert); err != nil {
+			t.Fatalf("insert alert: %v", err)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/alerts?limit=3&offset=1", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleListAlerts(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get(pagination.TotalCountHeader); got != "4" {
+		t.Errorf("%s = %q, want 4", pagination.TotalCountHeader, got)
+	}
+	var alerts []Alert
+	if err := json.NewDecoder(w.Body).Decode(&alerts); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(alerts) != 3 || alerts[0].ID != "alert-2" {
+		t.Errorf("alerts = %d starting at %q, want 3 starting at alert-2", len(alerts), alerts[0].ID)
+	}
+}
+
 func TestHandleListAlerts_WithFilters(t *testing.T) {
 	m, _ := newTestModule(t)
 
@@ -867,6 +1184,129 @@ func TestHandleAcknowledgeAlert_Success(t *testing.T) {
 	}
 }
 
+func TestHandleAcknowledgeAlert_WithComment(t *testing.T) {
+	m, _ := newTestModule(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	check := &Check{
+		ID: "check-1", DeviceID: "dev-1", CheckType: "icmp",
+		Target: "192.168.1.1", IntervalSeconds: 60, Enabled: true,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := m.store.InsertCheck(ctx, check); err != nil {
+		t.Fatalf("insert check: %v", err)
+	}
+	alert := &Alert{
+		ID: "alert-1", CheckID: "check-1", DeviceID: "dev-1",
+		Severity: "critical", Message: "Host down",
+		TriggeredAt: now, ConsecutiveFailures: 3,
+	}
+	if err := m.store.InsertAlert(ctx, alert); err != nil {
+		t.Fatalf("insert alert: %v", err)
+	}
+
+	body := strings.NewReader(`{"comment":"known ISP outage, ticket #123"}`)
+	req := httptest.NewRequest(http.MethodPost, "/alerts/alert-1/acknowledge", body)
+	req.SetPathValue("id", "alert-1")
+	req = req.WithContext(auth.ContextWithUser(req.Context(), &auth.Claims{UserID: "u-1", Username: "oncall"}))
+	w := httptest.NewRecorder()
+
+	m.handleAcknowledgeAlert(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var acked Alert
+	if err := json.NewDecoder(w.Body).Decode(&acked); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if acked.AcknowledgedBy != "oncall" || acked.AcknowledgeComment != "known ISP outage, ticket #123" {
+		t.Errorf("acknowledged_by = %q, comment = %q", acked.AcknowledgedBy, acked.AcknowledgeComment)
+	}
+
+	// The comment is persisted and returned by GET /alerts/{id}.
+	req = httptest.NewRequest(http.MethodGet, "/alerts/alert-1", http.NoBody)
+	req.SetPathValue("id", "alert-1")
+	w = httptest.NewRecorder()
+	m.handleGetAlert(w, req)
+
+	var got Alert
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.AcknowledgedAt == nil || got.AcknowledgeComment != "known ISP outage, ticket #123" {
+		t.Errorf("got acknowledged_at = %v, comment = %q", got.AcknowledgedAt, got.AcknowledgeComment)
+	}
+	if len(got.Annotations) != 1 {
+		t.Fatalf("annotations = %d, want 1", len(got.Annotations))
+	}
+	if a := got.Annotations[0]; a.Kind != AnnotationAcknowledge || a.Author != "oncall" || a.Body != "known ISP outage, ticket #123" {
+		t.Errorf("annotation = %+v", a)
+	}
+}
+
+func TestHandleAddAlertAnnotation_PreservesOrder(t *testing.T) {
+	m, _ := newTestModule(t)
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	check := &Check{
+		ID: "check-1", DeviceID: "dev-1", CheckType: "icmp",
+		Target: "192.168.1.1", IntervalSeconds: 60, Enabled: true,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := m.store.InsertCheck(ctx, check); err != nil {
+		t.Fatalf("insert check: %v", err)
+	}
+	alert := &Alert{
+		ID: "alert-1", CheckID: "check-1", DeviceID: "dev-1",
+		Severity: "warning", Message: "High latency",
+		TriggeredAt: now, ConsecutiveFailures: 3,
+	}
+	if err := m.store.InsertAlert(ctx, alert); err != nil {
+		t.Fatalf("insert alert: %v", err)
+	}
+
+	notes := []string{"looking into it", "upstream switch rebooted", "latency back to normal"}
+	for _, note := range notes {
+		req := httptest.NewRequest(http.MethodPost, "/alerts/alert-1/annotations",
+			strings.NewReader(`{"body":"`+note+`"}`))
+		req.SetPathValue("id", "alert-1")
+		w := httptest.NewRecorder()
+		m.handleAddAlertAnnotation(w, req)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusCreated, w.Body.String())
+		}
+	}
+
+	got, err := m.store.ListAlertAnnotations(ctx, "alert-1")
+	if err != nil {
+		t.Fatalf("ListAlertAnnotations: %v", err)
+	}
+	if len(got) != len(notes) {
+		t.Fatalf("annotations = %d, want %d", len(got), len(notes))
+	}
+	for i, note := range notes {
+		if got[i].Body != note || got[i].Kind != AnnotationNote {
+			t.Errorf("annotation[%d] = %+v, want note %q", i, got[i], note)
+		}
+	}
+}
+
+func TestHandleAddAlertAnnotation_NotFound(t *testing.T) {
+	m, _ := newTestModule(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/alerts/missing/annotations", strings.NewReader(`{"body":"note"}`))
+	req.SetPathValue("id", "missing")
+	w := httptest.NewRecorder()
+	m.handleAddAlertAnnotation(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
 // -- handleResolveAlert tests --
 
 func TestHandleResolveAlert_Success(t *testing.T) {
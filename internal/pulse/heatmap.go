@@ -0,0 +1,280 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Heatmap bucket states.
+const (
+	HeatmapUp       = "up"
+	HeatmapDegraded = "degraded"
+	HeatmapDown     = "down"
+	HeatmapUnknown  = "unknown"
+)
+
+// Heatmap is a per-device grid of health states over a time range. Every
+// row has the same number of buckets, oldest first, so it can be rendered
+// directly as a grid.
+type Heatmap struct {
+	Range         string       `json:"range"`
+	Start         time.Time    `json:"start"`
+	BucketSeconds int64        `json:"bucket_seconds"`
+	Buckets       int          `json:"buckets"`
+	Devices       []HeatmapRow `json:"devices"`
+	Total         int          `json:"total"`
+	Limit         int          `json:"limit"`
+	Offset        int          `json:"offset"`
+}
+
+// HeatmapRow holds the bucket states for one monitored device.
+type HeatmapRow struct {
+	DeviceID   string   `json:"device_id"`
+	DeviceName string   `json:"device_name"`
+	States     []string `json:"states"`
+}
+
+// heatmapBucketSize returns the bucket width for a heatmap range. Buckets are
+// coarser than QueryMetrics so a row stays small enough to render as-is.
+func heatmapBucketSize(d time.Duration) time.Duration {
+	switch {
+	case d <= time.Hour:
+		return 5 * time.Minute
+	case d <= 6*time.Hour:
+		return 15 * time.Minute
+	case d <= 24*time.Hour:
+		return time.Hour
+	case d <= 7*24*time.Hour:
+		return 6 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// heatmapCell accumulates results and alert overlap for one bucket.
+type heatmapCell struct {
+	success, total int
+	alerted        bool
+}
+
+// state derives the discrete bucket state. Results decide the base state:
+// all successful is up, all failed is down, a mix is degraded. An alert that
+// was active during the bucket turns up into degraded and unknown into down.
+func (c heatmapCell) state() string {
+	var s string
+	switch {
+	case c.total == 0:
+		s = HeatmapUnknown
+	case c.success == c.total:
+		s = HeatmapUp
+	case c.success == 0:
+		s = HeatmapDown
+	default:
+		s = HeatmapDegraded
+	}
+	if c.alerted {
+		switch s {
+		case HeatmapUp:
+			s = HeatmapDegraded
+		case HeatmapUnknown:
+			s = HeatmapDown
+		}
+	}
+	return s
+}
+
+// QueryHeatmap builds a heatmap for monitored devices over timeRange ending
+// at end. Devices are ordered by ID and paginated with limit and offset.
+func (s *PulseStore) QueryHeatmap(ctx context.Context, timeRange string, end time.Time, limit, offset int) (*Heatmap, error) {
+	duration, ok := validRanges[timeRange]
+	if !ok {
+		return nil, fmt.Errorf("unknown range %q: must be 1h, 6h, 24h, 7d, or 30d", timeRange)
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	bucketSec := int64(heatmapBucketSize(duration) / time.Second)
+	n := int(int64(duration/time.Second) / bucketSec)
+	// Align buckets to the bucket width; the last bucket contains end.
+	start := (end.UTC().Unix()/bucketSec)*bucketSec - int64(n-1)*bucketSec
+
+	hm := &Heatmap{
+		Range:         timeRange,
+		Start:         time.Unix(start, 0).UTC(),
+		BucketSeconds: bucketSec,
+		Buckets:       n,
+		Devices:       []HeatmapRow{},
+		Limit:         limit,
+		Offset:        offset,
+	}
+
+	if err := s.db.QueryRowContext(ctx,
+		`SELECT COUNT(DISTINCT device_id) FROM pulse_checks WHERE device_id != ''`,
+	).Scan(&hm.Total); err != nil {
+		return nil, fmt.Errorf("count heatmap devices: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT c.device_id,
+			COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), c.device_id) AS device_name
+		FROM (SELECT DISTINCT device_id FROM pulse_checks WHERE device_id != '') c
+		LEFT JOIN recon_devices d ON d.id = c.device_id
+		ORDER BY c.device_id
+		LIMIT ? OFFSET ?`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list heatmap devices: %w", err)
+	}
+	index := make(map[string]int)
+	var ids []any
+	for rows.Next() {
+		var row HeatmapRow
+		if err := rows.Scan(&row.DeviceID, &row.DeviceName); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan heatmap device: %w", err)
+		}
+		index[row.DeviceID] = len(hm.Devices)
+		ids = append(ids, row.DeviceID)
+		hm.Devices = append(hm.Devices, row)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate heatmap devices: %w", err)
+	}
+	if len(ids) == 0 {
+		return hm, nil
+	}
+
+	cells := make([][]heatmapCell, len(hm.Devices))
+	for i := range cells {
+		cells[i] = make([]heatmapCell, n)
+	}
+	since := time.Unix(start, 0).UTC()
+	until := time.Unix(start+int64(n)*bucketSec, 0).UTC()
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+
+	// Results: count successes per bucket.
+	resultQuery := `SELECT device_id, success, checked_at FROM pulse_check_results
+		WHERE device_id IN (` + placeholders + `) AND checked_at >= ? AND checked_at < ?` //nolint:gosec // G202: dynamic SQL uses parameterized placeholders only
+	rows, err = s.db.QueryContext(ctx, resultQuery, append(append([]any{}, ids...), since, until)...)
+	if err != nil {
+		return nil, fmt.Errorf("query heatmap results: %w", err)
+	}
+	for rows.Next() {
+		var deviceID string
+		var successInt int
+		var checkedAt time.Time
+		if err := rows.Scan(&deviceID, &successInt, &checkedAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan heatmap result: %w", err)
+		}
+		b := (checkedAt.Unix() - start) / bucketSec
+		if b < 0 || b >= int64(n) {
+			continue
+		}
+		cell := &cells[index[deviceID]][b]
+		cell.total++
+		cell.success += successInt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate heatmap results: %w", err)
+	}
+
+	// Alerts: mark every bucket overlapped by an alert's active window.
+	// Suppressed and low-priority alerts don't describe the device's own
+	// health and are left out.
+	alertQuery := `SELECT device_id, triggered_at, resolved_at FROM pulse_alerts
+		WHERE device_id IN (` + placeholders + `) AND suppressed = 0 AND low_priority = 0
+			AND triggered_at < ? AND (resolved_at IS NULL OR resolved_at >= ?)` //nolint:gosec // G202: dynamic SQL uses parameterized placeholders only
+	rows, err = s.db.QueryContext(ctx, alertQuery, append(append([]any{}, ids...), until, since)...)
+	if err != nil {
+		return nil, fmt.Errorf("query heatmap alerts: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var deviceID string
+		var triggeredAt time.Time
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&deviceID, &triggeredAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("scan heatmap alert: %w", err)
+		}
+		from := (triggeredAt.Unix() - start) / bucketSec
+		to := int64(n - 1)
+		if resolvedAt.Valid {
+			to = (resolvedAt.Time.Unix() - start) / bucketSec
+		}
+		for b := max(from, 0); b <= min(to, int64(n-1)); b++ {
+			cells[index[deviceID]][b].alerted = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate heatmap alerts: %w", err)
+	}
+
+	for i := range hm.Devices {
+		states := make([]string, n)
+		for b := range cells[i] {
+			states[b] = cells[i][b].state()
+		}
+		hm.Devices[i].States = states
+	}
+	return hm, nil
+}
+
+// handleHeatmap returns per-device health states bucketed over a time range.
+//
+//	@Summary		Reachability heatmap
+//	@Description	Returns, for each monitored device, an array of bucket states (up, degraded, down, unknown) over the requested range, oldest first. Devices are paginated.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			range query string false "Time range: 1h, 6h, 24h, 7d, 30d" default(24h)
+//	@Param			limit query int false "Maximum devices" default(50)
+//	@Param			offset query int false "Devices to skip" default(0)
+//	@Success		200 {object} Heatmap
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/heatmap [get]
+func (m *Module) handleHeatmap(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	timeRange := r.URL.Query().Get("range")
+	if timeRange == "" {
+		timeRange = "24h"
+	}
+	if _, ok := validRanges[timeRange]; !ok {
+		pulseWriteError(w, http.StatusBadRequest, "range must be 1h, 6h, 24h, 7d, or 30d")
+		return
+	}
+
+	offset := 0
+	if s := r.URL.Query().Get("offset"); s != "" {
+		if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+			offset = n
+		}
+	}
+
+	hm, err := m.store.QueryHeatmap(r.Context(), timeRange, time.Now(), pulseParseLimit(r, 50), offset)
+	if err != nil {
+		m.logger.Warn("failed to query heatmap", zap.String("range", timeRange), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to query heatmap")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, hm)
+}
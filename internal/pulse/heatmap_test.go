@@ -0,0 +1,160 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryHeatmap_OutageWindow(t *testing.T) {
+	_, ps := newTestModule(t)
+	ctx := context.Background()
+	end := time.Now().UTC()
+
+	makeTestCheck(t, ps, "dev-a", "icmp", "10.0.0.1")
+	check := makeTestCheck(t, ps, "dev-h", "icmp", "10.0.0.2")
+
+	// 24h range uses 24 one-hour buckets aligned to the hour.
+	start := end.Truncate(time.Hour).Add(-23 * time.Hour)
+	for b := 0; b < 24; b++ {
+		if b == 15 {
+			continue // no data
+		}
+		for q := 0; q < 4; q++ {
+			success := true
+			switch {
+			case b >= 5 && b <= 7:
+				success = false // outage
+			case b == 10 && q%2 == 0:
+				success = false // flapping
+			}
+			if err := ps.InsertResult(ctx, &CheckResult{
+				CheckID:   check.ID,
+				DeviceID:  "dev-h",
+				Success:   success,
+				CheckedAt: start.Add(time.Duration(b)*time.Hour + time.Duration(q)*15*time.Minute),
+			}); err != nil {
+				t.Fatalf("InsertResult: %v", err)
+			}
+		}
+	}
+
+	// A short alert inside an otherwise healthy bucket.
+	triggered := start.Add(20*time.Hour + 10*time.Minute)
+	if err := ps.InsertAlert(ctx, &Alert{
+		ID: "alert-hm", CheckID: check.ID, DeviceID: "dev-h", Severity: "warning",
+		Message: "timeout", TriggeredAt: triggered, ConsecutiveFailures: 3,
+	}); err != nil {
+		t.Fatalf("InsertAlert: %v", err)
+	}
+	if err := ps.ResolveAlert(ctx, "alert-hm", triggered.Add(10*time.Minute)); err != nil {
+		t.Fatalf("ResolveAlert: %v", err)
+	}
+
+	hm, err := ps.QueryHeatmap(ctx, "24h", end, 50, 0)
+	if err != nil {
+		t.Fatalf("QueryHeatmap: %v", err)
+	}
+	if hm.Buckets != 24 || hm.BucketSeconds != 3600 {
+		t.Fatalf("buckets = %d x %ds, want 24 x 3600s", hm.Buckets, hm.BucketSeconds)
+	}
+	if !hm.Start.Equal(start) {
+		t.Errorf("Start = %v, want %v", hm.Start, start)
+	}
+	if hm.Total != 2 || len(hm.Devices) != 2 {
+		t.Fatalf("devices = %d (total %d), want 2", len(hm.Devices), hm.Total)
+	}
+
+	idle := hm.Devices[0]
+	if idle.DeviceID != "dev-a" {
+		t.Fatalf("Devices[0] = %q, want dev-a", idle.DeviceID)
+	}
+	for b, s := range idle.States {
+		if s != HeatmapUnknown {
+			t.Errorf("dev-a bucket %d = %q, want unknown", b, s)
+		}
+	}
+
+	row := hm.Devices[1]
+	if len(row.States) != 24 {
+		t.Fatalf("states = %d, want 24", len(row.States))
+	}
+	for b, got := range row.States {
+		want := HeatmapUp
+		switch {
+		case b >= 5 && b <= 7:
+			want = HeatmapDown
+		case b == 10, b == 20:
+			want = HeatmapDegraded
+		case b == 15:
+			want = HeatmapUnknown
+		}
+		if got != want {
+			t.Errorf("dev-h bucket %d = %q, want %q", b, got, want)
+		}
+	}
+}
+
+func TestHeatmapCellState(t *testing.T) {
+	tests := []struct {
+		name string
+		cell heatmapCell
+		want string
+	}{
+		{"no data", heatmapCell{}, HeatmapUnknown},
+		{"all up", heatmapCell{success: 4, total: 4}, HeatmapUp},
+		{"all down", heatmapCell{success: 0, total: 4}, HeatmapDown},
+		{"mixed", heatmapCell{success: 2, total: 4}, HeatmapDegraded},
+		{"up with alert", heatmapCell{success: 4, total: 4, alerted: true}, HeatmapDegraded},
+		{"no data with alert", heatmapCell{alerted: true}, HeatmapDown},
+		{"down with alert", heatmapCell{total: 2, alerted: true}, HeatmapDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cell.state(); got != tt.want {
+				t.Errorf("state() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleHeatmap_Pagination(t *testing.T) {
+	m, ps := newTestModule(t)
+	for _, id := range []string{"dev-1", "dev-2", "dev-3"} {
+		makeTestCheck(t, ps, id, "icmp", "10.0.0.1")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/heatmap?range=6h&limit=2&offset=1", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleHeatmap(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var hm Heatmap
+	if err := json.NewDecoder(w.Body).Decode(&hm); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if hm.Total != 3 || hm.Limit != 2 || hm.Offset != 1 {
+		t.Errorf("total=%d limit=%d offset=%d, want 3/2/1", hm.Total, hm.Limit, hm.Offset)
+	}
+	if len(hm.Devices) != 2 || hm.Devices[0].DeviceID != "dev-2" || hm.Devices[1].DeviceID != "dev-3" {
+		t.Errorf("devices = %+v, want dev-2 and dev-3", hm.Devices)
+	}
+	if hm.Buckets != 24 || len(hm.Devices[0].States) != 24 {
+		t.Errorf("6h buckets = %d, want 24 x 15m", hm.Buckets)
+	}
+}
+
+func TestHandleHeatmap_InvalidRange(t *testing.T) {
+	m, _ := newTestModule(t)
+	req := httptest.NewRequest(http.MethodGet, "/heatmap?range=2w", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleHeatmap(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
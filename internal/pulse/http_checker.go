@@ -4,12 +4,19 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
 	"time"
 )
 
-// Compile-time interface guard.
-var _ Checker = (*HTTPChecker)(nil)
+// Compile-time interface guards.
+var (
+	_ Checker            = (*HTTPChecker)(nil)
+	_ expectationChecker = (*HTTPChecker)(nil)
+)
 
 // HTTPChecker tests HTTP/HTTPS endpoint reachability by sending GET requests.
 type HTTPChecker struct {
@@ -30,8 +37,23 @@ func NewHTTPChecker(timeout time.Duration) *HTTPChecker {
 	}
 }
 
+// maxHTTPBodyMatch caps how much of a response body is searched for an
+// expected substring.
+const maxHTTPBodyMatch = 1 << 20
+
 // Check sends a GET request to the target URL and checks for a 2xx response.
 func (c *HTTPChecker) Check(ctx context.Context, target string) (*CheckResult, error) {
+	return c.check(ctx, target, 0, "")
+}
+
+// CheckExpect sends a GET request to the check's target and validates the
+// response against its expected status code and body substring. With no
+// expected status, any 2xx response passes.
+func (c *HTTPChecker) CheckExpect(ctx context.Context, check *Check) (*CheckResult, error) {
+	return c.check(ctx, check.Target, check.ExpectedStatus, check.ExpectedBodySubstring)
+}
+
+func (c *HTTPChecker) check(ctx context.Context, target string, expectedStatus int, expectedBody string) (*CheckResult, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, http.NoBody)
 	if err != nil {
 		return &CheckResult{
@@ -41,9 +63,16 @@ func (c *HTTPChecker) Check(ctx context.Context, target string) (*CheckResult, e
 		}, fmt.Errorf("invalid URL %q: %w", target, err)
 	}
 
-	start := time.Now()
-	resp, err := c.client.Do(req)
-	elapsed := time.Since(start)
+	tracer := &httpPhaseTracer{}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, tracer.clientTrace()))
+
+	// A per-check deadline on ctx replaces the client's default timeout.
+	client := *c.client
+	client.Timeout = deadlineTimeout(ctx, c.client.Timeout)
+
+	tracer.start = time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(tracer.start)
 
 	if err != nil {
 		return &CheckResult{
@@ -51,22 +80,108 @@ func (c *HTTPChecker) Check(ctx context.Context, target string) (*CheckResult, e
 			LatencyMs:    float64(elapsed) / float64(time.Millisecond),
 			ErrorMessage: err.Error(),
 			CheckedAt:    time.Now().UTC(),
+			Timing:       tracer.timing(),
 		}, fmt.Errorf("http get %s: %w", target, err)
 	}
-	resp.Body.Close()
+	defer resp.Body.Close()
 
 	result := &CheckResult{
 		LatencyMs: float64(elapsed) / float64(time.Millisecond),
 		CheckedAt: time.Now().UTC(),
+		Timing:    tracer.timing(),
 	}
 
-	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-		result.Success = true
-	} else {
+	statusOK := resp.StatusCode >= 200 && resp.StatusCode < 300
+	if expectedStatus != 0 {
+		statusOK = resp.StatusCode == expectedStatus
+	}
+	if !statusOK {
 		result.Success = false
 		result.ErrorMessage = fmt.Sprintf("HTTP %d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+		if expectedStatus != 0 {
+			result.ErrorMessage += fmt.Sprintf(", expected %d", expectedStatus)
+		}
 		return result, fmt.Errorf("http %s: status %d", target, resp.StatusCode)
 	}
 
+	if expectedBody != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodyMatch))
+		if err != nil {
+			result.Success = false
+			result.ErrorMessage = fmt.Sprintf("read response body: %v", err)
+			return result, fmt.Errorf("http %s: read body: %w", target, err)
+		}
+		if !strings.Contains(string(body), expectedBody) {
+			result.Success = false
+			result.ErrorMessage = fmt.Sprintf("response body does not contain %q", expectedBody)
+			return result, fmt.Errorf("http %s: body does not contain expected substring", target)
+		}
+	}
+
+	result.Success = true
 	return result, nil
 }
+
+// httpPhaseTracer records when each phase of an HTTP request starts and
+// ends. Hooks run on transport goroutines, and an abandoned dial may still
+// fire them after Do returns, so all fields are guarded by mu.
+type httpPhaseTracer struct {
+	mu                     sync.Mutex
+	start                  time.Time
+	dnsStart, dnsDone      time.Time
+	connectStart, connDone time.Time
+	tlsStart, tlsDone      time.Time
+	firstByte              time.Time
+}
+
+// mark stores the current time in *field unless it is already set.
+func (t *httpPhaseTracer) mark(field *time.Time) {
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if field.IsZero() {
+		*field = now
+	}
+}
+
+func (t *httpPhaseTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:     func(httptrace.DNSStartInfo) { t.mark(&t.dnsStart) },
+		DNSDone:      func(httptrace.DNSDoneInfo) { t.mark(&t.dnsDone) },
+		ConnectStart: func(_, _ string) { t.mark(&t.connectStart) },
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.mark(&t.connDone)
+			}
+		},
+		TLSHandshakeStart:    func() { t.mark(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.mark(&t.tlsDone) },
+		GotFirstResponseByte: func() { t.mark(&t.firstByte) },
+	}
+}
+
+// timing returns the phase durations observed so far, or nil if the request
+// never got as far as a connection. Phases that did not happen (DNS for an
+// IP literal, TLS for plain HTTP) are zero.
+func (t *httpPhaseTracer) timing() *HTTPTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.connectStart.IsZero() && t.dnsStart.IsZero() {
+		return nil
+	}
+	return &HTTPTiming{
+		DNSMs:       phaseMs(t.dnsStart, t.dnsDone),
+		ConnectMs:   phaseMs(t.connectStart, t.connDone),
+		TLSMs:       phaseMs(t.tlsStart, t.tlsDone),
+		FirstByteMs: phaseMs(t.start, t.firstByte),
+	}
+}
+
+// phaseMs returns the milliseconds between start and end, or 0 if the phase
+// did not complete.
+func phaseMs(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return float64(end.Sub(start)) / float64(time.Millisecond)
+}
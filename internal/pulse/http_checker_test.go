@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -152,6 +153,44 @@ func TestHTTPChecker_HTTPS_SelfSigned(t *testing.T) {
 	}
 }
 
+func TestHTTPChecker_TimingBreakdown(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// Use a hostname so the DNS phase runs too.
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	result, err := NewHTTPChecker(5*time.Second).Check(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	timing := result.Timing
+	if timing == nil {
+		t.Fatal("Timing = nil, want phase breakdown")
+	}
+	if timing.ConnectMs <= 0 || timing.TLSMs <= 0 || timing.FirstByteMs <= 0 {
+		t.Errorf("Timing = %+v, want connect, TLS, and first byte populated", timing)
+	}
+	if timing.DNSMs < 0 {
+		t.Errorf("DNSMs = %v, want >= 0", timing.DNSMs)
+	}
+	if timing.FirstByteMs < 20 {
+		t.Errorf("FirstByteMs = %v, want at least the 20ms server delay", timing.FirstByteMs)
+	}
+
+	// The phases happen in sequence before the first byte, which arrives
+	// before the request completes.
+	const slackMs = 1.0
+	if sum := timing.DNSMs + timing.ConnectMs + timing.TLSMs; sum > timing.FirstByteMs+slackMs {
+		t.Errorf("DNS+connect+TLS = %.2fms exceeds first byte %.2fms", sum, timing.FirstByteMs)
+	}
+	if timing.FirstByteMs > result.LatencyMs+slackMs {
+		t.Errorf("FirstByteMs = %.2f exceeds total latency %.2f", timing.FirstByteMs, result.LatencyMs)
+	}
+}
+
 func TestHTTPChecker_ContextCancelled(t *testing.T) {
 	// Server that delays response.
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -204,3 +243,158 @@ func TestHTTPChecker_2xxRange(t *testing.T) {
 		}
 	}
 }
+
+func TestHTTPChecker_CheckExpect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/degraded":
+			_, _ = w.Write([]byte(`{"status":"degraded"}`))
+		case "/auth":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		body        string
+		wantSuccess bool
+		wantMessage string
+	}{
+		{"defaults accept 2xx", "/", 0, "", true, ""},
+		{"body matches", "/", 0, `"status":"ok"`, true, ""},
+		{"body mismatch", "/degraded", 0, `"status":"ok"`, false, `response body does not contain "\"status\":\"ok\""`},
+		{"status matches", "/", 200, "", true, ""},
+		{"status mismatch", "/", 204, "", false, "HTTP 200 OK, expected 204"},
+		{"expected non-2xx", "/auth", 401, "", true, ""},
+		{"non-2xx without expectation", "/auth", 0, "", false, "HTTP 401 Unauthorized"},
+	}
+	checker := NewHTTPChecker(5 * time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := &Check{
+				CheckType:             "http",
+				Target:                server.URL + tt.path,
+				ExpectedStatus:        tt.status,
+				ExpectedBodySubstring: tt.body,
+			}
+			result, err := checker.CheckExpect(context.Background(), check)
+			if result == nil {
+				t.Fatal("CheckExpect() returned nil result")
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (message %q)", result.Success, tt.wantSuccess, result.ErrorMessage)
+			}
+			if (err == nil) != tt.wantSuccess {
+				t.Errorf("err = %v, want error only on failure", err)
+			}
+			if result.ErrorMessage != tt.wantMessage {
+				t.Errorf("ErrorMessage = %q, want %q", result.ErrorMessage, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestExecuteCheck_PerCheckTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(300 * time.Millisecond):
+		case <-r.Context().Done():
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// The module default is shorter than the slow target's response time.
+	m, ps := newTestModule(t)
+	m.cfg.PingTimeout = 150 * time.Millisecond
+	m.checkers = map[string]Checker{"http": NewHTTPChecker(m.cfg.PingTimeout)}
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name        string
+		timeoutMs   int
+		wantSuccess bool
+	}{
+		{name: "short", timeoutMs: 100, wantSuccess: false},
+		{name: "default", timeoutMs: 0, wantSuccess: false},
+		{name: "long", timeoutMs: 2000, wantSuccess: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			check := Check{
+				ID: "chk-" + tt.name, DeviceID: "dev-" + tt.name, CheckType: "http", Target: server.URL,
+				IntervalSeconds: 30, Enabled: true, TimeoutMs: tt.timeoutMs,
+				CreatedAt: now, UpdatedAt: now,
+			}
+			if err := ps.InsertCheck(ctx, &check); err != nil {
+				t.Fatalf("InsertCheck: %v", err)
+			}
+			stored, err := ps.GetCheck(ctx, check.ID)
+			if err != nil {
+				t.Fatalf("GetCheck: %v", err)
+			}
+			if stored.TimeoutMs != tt.timeoutMs {
+				t.Fatalf("stored TimeoutMs = %d, want %d", stored.TimeoutMs, tt.timeoutMs)
+			}
+
+			m.executeCheck(ctx, *stored)
+
+			results, err := ps.ListResults(ctx, check.DeviceID, 1)
+			if err != nil {
+				t.Fatalf("ListResults: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("results = %d, want 1", len(results))
+			}
+			if results[0].Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v (error %q)", results[0].Success, tt.wantSuccess, results[0].ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestExecuteCheck_UsesHTTPExpectations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"degraded"}`))
+	}))
+	defer server.Close()
+
+	m, ps := newTestModule(t)
+	m.checkers = map[string]Checker{"http": NewHTTPChecker(5 * time.Second)}
+	ctx := context.Background()
+	now := time.Now().UTC()
+	check := Check{
+		ID: "chk-http-body", DeviceID: "dev-http", CheckType: "http", Target: server.URL,
+		IntervalSeconds: 30, Enabled: true, ExpectedBodySubstring: `"status":"ok"`,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := ps.InsertCheck(ctx, &check); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+	stored, err := ps.GetCheck(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetCheck: %v", err)
+	}
+	if stored.ExpectedBodySubstring != check.ExpectedBodySubstring {
+		t.Fatalf("stored ExpectedBodySubstring = %q, want %q", stored.ExpectedBodySubstring, check.ExpectedBodySubstring)
+	}
+
+	m.executeCheck(ctx, *stored)
+
+	results, err := ps.ListResults(ctx, "dev-http", 1)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("results = %d, want 1", len(results))
+	}
+	if results[0].Success {
+		t.Error("result Success = true, want false for degraded body")
+	}
+}
@@ -0,0 +1,388 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+// Incident is one underlying problem on a check. A check that triggers
+// again within the flap window after its alert resolved reopens the same
+// alert and incident instead of raising a new alert, so a flapping device
+// produces a single incident whose FlapCount counts its trigger cycles.
+type Incident struct {
+	ID              string     `json:"id"`
+	CheckID         string     `json:"check_id"`
+	DeviceID        string     `json:"device_id"`
+	AlertID         string     `json:"alert_id"`
+	Severity        string     `json:"severity" example:"warning"`
+	Message         string     `json:"message"`
+	FlapCount       int        `json:"flap_count" example:"5"`
+	OpenedAt        time.Time  `json:"opened_at"`
+	LastTriggeredAt time.Time  `json:"last_triggered_at"`
+	ResolvedAt      *time.Time `json:"resolved_at,omitempty"`
+}
+
+// IncidentFilters filters incident queries. Zero values leave a field
+// unfiltered.
+type IncidentFilters struct {
+	DeviceID   string
+	CheckID    string
+	ActiveOnly bool
+	Limit      int
+	Offset     int
+}
+
+// -- Incident store --
+
+// InsertIncident inserts a new incident.
+func (s *PulseStore) InsertIncident(ctx context.Context, inc *Incident) error {
+	var resolvedAt sql.NullTime
+	if inc.ResolvedAt != nil {
+		resolvedAt = sql.NullTime{Time: *inc.ResolvedAt, Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO pulse_incidents (
+			id, check_id, device_id, alert_id, severity, message, flap_count,
+			opened_at, last_triggered_at, resolved_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		inc.ID, inc.CheckID, inc.DeviceID, inc.AlertID, inc.Severity, inc.Message,
+		inc.FlapCount, inc.OpenedAt, inc.LastTriggeredAt, resolvedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert incident: %w", err)
+	}
+	return nil
+}
+
+// GetReopenableIncident returns the check's most recent incident that
+// resolved at or after since and whose alert still exists. Returns nil, nil
+// if there is none.
+func (s *PulseStore) GetReopenableIncident(ctx context.Context, checkID string, since time.Time) (*Incident, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT i.id, i.check_id, i.device_id, i.alert_id, i.severity, i.message, i.flap_count,
+			i.opened_at, i.last_triggered_at, i.resolved_at
+		FROM pulse_incidents i
+		JOIN pulse_alerts a ON a.id = i.alert_id
+		WHERE i.check_id = ? AND i.resolved_at IS NOT NULL AND i.resolved_at >= ?
+		ORDER BY i.resolved_at DESC LIMIT 1`,
+		checkID, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get reopenable incident: %w", err)
+	}
+	defer rows.Close()
+
+	incidents, err := scanIncidentRows(rows)
+	if err != nil || len(incidents) == 0 {
+		return nil, err
+	}
+	return &incidents[0], nil
+}
+
+// ReopenIncident reactivates inc and its alert for another trigger cycle.
+// The alert takes the state of alert, the freshly evaluated trigger, but
+// keeps its ID; its annotations carry over. The previous cycle's
+// acknowledgement is cleared, so an acknowledgement without a comment (one
+// with a comment is already on the timeline) is first recorded as an
+// annotation.
+func (s *PulseStore) ReopenIncident(ctx context.Context, inc *Incident, alert *Alert) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin reopen incident: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	var acknowledgedAt sql.NullTime
+	if alert.AcknowledgedAt != nil {
+		acknowledgedAt = sql.NullTime{Time: *alert.AcknowledgedAt, Valid: true}
+	}
+	suppressed := 0
+	if alert.Suppressed {
+		suppressed = 1
+	}
+	lowPriority := 0
+	if alert.LowPriority {
+		lowPriority = 1
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO pulse_alert_annotations (alert_id, kind, author, body, created_at)
+		SELECT id, ?, acknowledged_by, 'Acknowledged', acknowledged_at
+		FROM pulse_alerts
+		WHERE id = ? AND acknowledged_at IS NOT NULL AND acknowledge_comment = ''`,
+		AnnotationAcknowledge, inc.AlertID,
+	); err != nil {
+		return fmt.Errorf("record previous acknowledgement: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE pulse_alerts SET severity = ?, message = ?, triggered_at = ?, resolved_at = NULL,
+			acknowledged_at = ?, acknowledged_by = '', acknowledge_comment = '',
+			consecutive_failures = ?, suppressed = ?, suppressed_by = ?, low_priority = ?, escalated_at = NULL
+		WHERE id = ?`,
+		alert.Severity, alert.Message, alert.TriggeredAt, acknowledgedAt,
+		alert.ConsecutiveFailures, suppressed, alert.SuppressedBy, lowPriority, inc.AlertID,
+	); err != nil {
+		return fmt.Errorf("reopen alert: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE pulse_incidents SET flap_count = flap_count + 1, severity = ?, message = ?,
+			last_triggered_at = ?, resolved_at = NULL
+		WHERE id = ?`,
+		alert.Severity, alert.Message, alert.TriggeredAt, inc.ID,
+	); err != nil {
+		return fmt.Errorf("reopen incident: %w", err)
+	}
+	return tx.Commit()
+}
+
+// GetIncident returns an incident by ID. Returns nil, nil if not found.
+func (s *PulseStore) GetIncident(ctx context.Context, id string) (*Incident, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, check_id, device_id, alert_id, severity, message, flap_count,
+			opened_at, last_triggered_at, resolved_at
+		FROM pulse_incidents WHERE id = ?`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get incident: %w", err)
+	}
+	defer rows.Close()
+
+	incidents, err := scanIncidentRows(rows)
+	if err != nil || len(incidents) == 0 {
+		return nil, err
+	}
+	return &incidents[0], nil
+}
+
+// ListIncidents returns incidents matching filters, most recently
+// triggered first.
+func (s *PulseStore) ListIncidents(ctx context.Context, filters IncidentFilters) ([]Incident, error) {
+	where, args := incidentFilterClause(filters)
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query := `SELECT id, check_id, device_id, alert_id, severity, message, flap_count,
+			opened_at, last_triggered_at, resolved_at
+		FROM pulse_incidents` + where + fmt.Sprintf(" ORDER BY last_triggered_at DESC LIMIT %d OFFSET %d", limit, filters.Offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list incidents: %w", err)
+	}
+	defer rows.Close()
+
+	return scanIncidentRows(rows)
+}
+
+// CountIncidents returns the number of incidents matching filters,
+// ignoring Limit and Offset.
+func (s *PulseStore) CountIncidents(ctx context.Context, filters IncidentFilters) (int, error) {
+	where, args := incidentFilterClause(filters)
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pulse_incidents"+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count incidents: %w", err)
+	}
+	return n, nil
+}
+
+// DeleteOldIncidents deletes resolved incidents older than the given time.
+// Returns the number of rows deleted.
+func (s *PulseStore) DeleteOldIncidents(ctx context.Context, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM pulse_incidents WHERE resolved_at IS NOT NULL AND resolved_at < ?`,
+		before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete old incidents: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// incidentFilterClause builds the WHERE clause, with a leading space, for
+// filters.
+func incidentFilterClause(filters IncidentFilters) (string, []any) {
+	var conditions []string
+	var args []any
+	if filters.DeviceID != "" {
+		conditions = append(conditions, "device_id = ?")
+		args = append(args, filters.DeviceID)
+	}
+	if filters.CheckID != "" {
+		conditions = append(conditions, "check_id = ?")
+		args = append(args, filters.CheckID)
+	}
+	if filters.ActiveOnly {
+		conditions = append(conditions, "resolved_at IS NULL")
+	}
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// scanIncidentRows scans incident rows into a slice.
+func scanIncidentRows(rows *sql.Rows) ([]Incident, error) {
+	var incidents []Incident
+	for rows.Next() {
+		var inc Incident
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(
+			&inc.ID, &inc.CheckID, &inc.DeviceID, &inc.AlertID, &inc.Severity, &inc.Message,
+			&inc.FlapCount, &inc.OpenedAt, &inc.LastTriggeredAt, &resolvedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan incident: %w", err)
+		}
+		if resolvedAt.Valid {
+			inc.ResolvedAt = &resolvedAt.Time
+		}
+		incidents = append(incidents, inc)
+	}
+	return incidents, rows.Err()
+}
+
+// -- Alerter integration --
+
+// SetFlapWindow sets how soon after its alert resolves a check must fail
+// again for the new trigger to reopen the same incident. Zero disables
+// the roll-up, so every trigger raises a new alert and incident.
+func (a *Alerter) SetFlapWindow(window time.Duration) {
+	a.flapWindow = window
+}
+
+// recordAlert stores a newly triggered alert. When the check's previous
+// incident resolved within the flap window, that incident and its alert
+// are reopened and alert takes over the existing alert's ID; otherwise
+// alert is inserted with a new incident. Each reopened cycle keeps its own
+// TriggeredAt, which notifiers use to tell the cycles' events apart.
+func (a *Alerter) recordAlert(ctx context.Context, alert *Alert) error {
+	if a.flapWindow > 0 {
+		inc, err := a.store.GetReopenableIncident(ctx, alert.CheckID, alert.TriggeredAt.Add(-a.flapWindow))
+		if err != nil {
+			a.logger.Warn("incident lookup failed, raising a new alert",
+				zap.String("check_id", alert.CheckID),
+				zap.Error(err),
+			)
+		} else if inc != nil {
+			if err := a.store.ReopenIncident(ctx, inc, alert); err != nil {
+				return err
+			}
+			alert.ID = inc.AlertID
+			a.logger.Info("flapping check reopened incident",
+				zap.String("incident_id", inc.ID),
+				zap.String("alert_id", inc.AlertID),
+				zap.String("check_id", alert.CheckID),
+				zap.Int("flap_count", inc.FlapCount+1),
+			)
+			return nil
+		}
+	}
+
+	if err := a.store.InsertAlert(ctx, alert); err != nil {
+		return err
+	}
+	inc := &Incident{
+		ID:              fmt.Sprintf("incident-%s-%d", alert.CheckID, alert.TriggeredAt.UnixMilli()),
+		CheckID:         alert.CheckID,
+		DeviceID:        alert.DeviceID,
+		AlertID:         alert.ID,
+		Severity:        alert.Severity,
+		Message:         alert.Message,
+		FlapCount:       1,
+		OpenedAt:        alert.TriggeredAt,
+		LastTriggeredAt: alert.TriggeredAt,
+	}
+	if err := a.store.InsertIncident(ctx, inc); err != nil {
+		// The alert itself is recorded; only the grouping is lost.
+		a.logger.Warn("failed to insert incident", zap.String("alert_id", alert.ID), zap.Error(err))
+	}
+	return nil
+}
+
+// -- Incident handlers --
+
+// handleListIncidents returns incidents with optional filtering.
+//
+//	@Summary		List incidents
+//	@Description	Returns alert incidents, most recently triggered first. An incident groups the
+//	@Description	trigger/resolve cycles of one check that recur within the flap window; flap_count
+//	@Description	counts them. X-Total-Count holds the number of matching incidents; pass
+//	@Description	envelope=true to get an object with items, total, limit, and offset.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			device_id query string false "Filter by device ID"
+//	@Param			check_id query string false "Filter by check ID"
+//	@Param			active query bool false "Only unresolved incidents" default(false)
+//	@Param			limit query int false "Maximum incidents" default(50)
+//	@Param			offset query int false "Offset" default(0)
+//	@Param			envelope query bool false "Wrap the results with pagination metadata"
+//	@Success		200 {array} Incident
+//	@Header			200 {integer} X-Total-Count "Number of incidents matching the filters"
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/incidents [get]
+func (m *Module) handleListIncidents(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	filters := IncidentFilters{
+		DeviceID:   r.URL.Query().Get("device_id"),
+		CheckID:    r.URL.Query().Get("check_id"),
+		ActiveOnly: r.URL.Query().Get("active") == "true",
+		Limit:      pulseParseLimit(r, 50),
+		Offset:     pulseParseOffset(r),
+	}
+
+	incidents, err := m.store.ListIncidents(r.Context(), filters)
+	if err != nil {
+		m.logger.Warn("failed to list incidents", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to list incidents")
+		return
+	}
+	total, err := m.store.CountIncidents(r.Context(), filters)
+	if err != nil {
+		m.logger.Warn("failed to count incidents", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to list incidents")
+		return
+	}
+	pagination.Write(w, r, incidents, total, filters.Limit, filters.Offset)
+}
+
+// handleGetIncident returns a single incident by ID.
+//
+//	@Summary		Get incident
+//	@Description	Returns a single alert incident by ID.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id path string true "Incident ID"
+//	@Success		200 {object} Incident
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/incidents/{id} [get]
+func (m *Module) handleGetIncident(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	inc, err := m.store.GetIncident(r.Context(), r.PathValue("id"))
+	if err != nil {
+		m.logger.Warn("failed to get incident", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get incident")
+		return
+	}
+	if inc == nil {
+		pulseWriteError(w, http.StatusNotFound, "incident not found")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, inc)
+}
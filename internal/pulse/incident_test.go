@@ -0,0 +1,232 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// newFlapAlerter returns an alerter that triggers on the first failure and
+// rolls up re-triggers within a 10 minute flap window.
+func newFlapAlerter(t *testing.T, clock *time.Time) (*Alerter, *PulseStore, *mockEventBus, Check) {
+	t.Helper()
+	_, ps := newTestModule(t) // alert lists join recon_devices for names
+	bus := &mockEventBus{}
+	alerter := NewAlerter(ps, bus, 1, 1, zap.NewNop())
+	alerter.now = func() time.Time { return *clock }
+	alerter.SetFlapWindow(10 * time.Minute)
+	return alerter, ps, bus, makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+}
+
+// flap runs one trigger/resolve cycle on check.
+func flap(alerter *Alerter, check Check, clock *time.Time) {
+	ctx := context.Background()
+	alerter.ProcessResult(ctx, check, &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: *clock})
+	*clock = clock.Add(time.Minute)
+	alerter.ProcessResult(ctx, check, &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: true, CheckedAt: *clock})
+	*clock = clock.Add(time.Minute)
+}
+
+func TestAlerter_FlapsWithinWindow_RollUpIntoOneIncident(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, bus, check := newFlapAlerter(t, &clock)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		flap(alerter, check, &clock)
+	}
+
+	incidents, err := ps.ListIncidents(ctx, IncidentFilters{CheckID: check.ID})
+	if err != nil {
+		t.Fatalf("ListIncidents: %v", err)
+	}
+	if len(incidents) != 1 {
+		t.Fatalf("incidents = %d, want 1", len(incidents))
+	}
+	inc := incidents[0]
+	if inc.FlapCount != 5 {
+		t.Errorf("FlapCount = %d, want 5", inc.FlapCount)
+	}
+	if inc.ResolvedAt == nil {
+		t.Error("incident unresolved after the last recovery")
+	}
+	if want := time.Date(2026, 3, 1, 12, 8, 0, 0, time.UTC); !inc.LastTriggeredAt.Equal(want) {
+		t.Errorf("LastTriggeredAt = %v, want %v", inc.LastTriggeredAt, want)
+	}
+
+	alerts, err := ps.ListAlerts(ctx, AlertFilters{DeviceID: check.DeviceID})
+	if err != nil {
+		t.Fatalf("ListAlerts: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ID != inc.AlertID {
+		t.Errorf("alerts = %+v, want only %s", alerts, inc.AlertID)
+	}
+
+	var triggered int
+	for _, e := range bus.events {
+		if e.Topic == TopicAlertTriggered {
+			triggered++
+			if a := e.Payload.(*Alert); a.ID != inc.AlertID {
+				t.Errorf("triggered event for alert %q, want %q", a.ID, inc.AlertID)
+			}
+		}
+	}
+	if triggered != 5 {
+		t.Errorf("triggered events = %d, want 5", triggered)
+	}
+}
+
+func TestAlerter_ReopenedCycles_DistinctTriggerTimes(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, _, bus, check := newFlapAlerter(t, &clock)
+
+	for i := 0; i < 3; i++ {
+		flap(alerter, check, &clock)
+	}
+
+	// Notifiers key deliveries on the alert ID plus its trigger time, so
+	// each cycle's events must carry that cycle's trigger time.
+	seen := make(map[string]bool)
+	for _, e := range bus.events {
+		a := e.Payload.(*Alert)
+		key := e.Topic + a.TriggeredAt.String()
+		if seen[key] {
+			t.Errorf("%s published twice with TriggeredAt %v", e.Topic, a.TriggeredAt)
+		}
+		seen[key] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("distinct events = %d, want 3 triggered and 3 resolved", len(seen))
+	}
+}
+
+func TestAlerter_Reopen_RecordsAcknowledgement(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, _, check := newFlapAlerter(t, &clock)
+	ctx := context.Background()
+
+	flap(alerter, check, &clock)
+	incidents, err := ps.ListIncidents(ctx, IncidentFilters{CheckID: check.ID})
+	if err != nil || len(incidents) != 1 {
+		t.Fatalf("ListIncidents = %v, %v", incidents, err)
+	}
+	alertID := incidents[0].AlertID
+	if err := ps.AcknowledgeAlertBy(ctx, alertID, "alice", ""); err != nil {
+		t.Fatalf("AcknowledgeAlertBy: %v", err)
+	}
+
+	flap(alerter, check, &clock)
+
+	alert, err := ps.GetAlert(ctx, alertID)
+	if err != nil || alert == nil {
+		t.Fatalf("GetAlert = %v, %v", alert, err)
+	}
+	if alert.AcknowledgedBy != "" {
+		t.Errorf("AcknowledgedBy = %q, want cleared for the new cycle", alert.AcknowledgedBy)
+	}
+	annotations, err := ps.ListAlertAnnotations(ctx, alertID)
+	if err != nil {
+		t.Fatalf("ListAlertAnnotations: %v", err)
+	}
+	if len(annotations) != 1 || annotations[0].Kind != AnnotationAcknowledge || annotations[0].Author != "alice" {
+		t.Errorf("annotations = %+v, want alice's acknowledgement", annotations)
+	}
+}
+
+func TestAlerter_TriggerAfterWindow_OpensNewIncident(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, _, check := newFlapAlerter(t, &clock)
+	ctx := context.Background()
+
+	flap(alerter, check, &clock)
+	clock = clock.Add(11 * time.Minute)
+	flap(alerter, check, &clock)
+
+	incidents, err := ps.ListIncidents(ctx, IncidentFilters{CheckID: check.ID})
+	if err != nil {
+		t.Fatalf("ListIncidents: %v", err)
+	}
+	if len(incidents) != 2 {
+		t.Fatalf("incidents = %d, want 2", len(incidents))
+	}
+	for _, inc := range incidents {
+		if inc.FlapCount != 1 {
+			t.Errorf("incident %s FlapCount = %d, want 1", inc.ID, inc.FlapCount)
+		}
+	}
+	if incidents[0].AlertID == incidents[1].AlertID {
+		t.Error("incidents share an alert, want one alert each")
+	}
+}
+
+func TestAlerter_FlapWindowDisabled_RaisesNewAlerts(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	alerter, ps, _, check := newFlapAlerter(t, &clock)
+	alerter.SetFlapWindow(0)
+
+	for i := 0; i < 3; i++ {
+		flap(alerter, check, &clock)
+	}
+
+	alerts, err := ps.ListAlerts(context.Background(), AlertFilters{DeviceID: check.DeviceID})
+	if err != nil {
+		t.Fatalf("ListAlerts: %v", err)
+	}
+	if len(alerts) != 3 {
+		t.Errorf("alerts = %d, want 3", len(alerts))
+	}
+}
+
+func TestHandleListIncidents(t *testing.T) {
+	clock := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	m, ps := newTestModule(t)
+	alerter := NewAlerter(ps, nil, 1, 1, zap.NewNop())
+	alerter.now = func() time.Time { return clock }
+	alerter.SetFlapWindow(10 * time.Minute)
+	check := makeTestCheck(t, ps, "device1", "ping", "192.168.1.1")
+
+	for i := 0; i < 5; i++ {
+		flap(alerter, check, &clock)
+	}
+	// Leave the check failing so the incident is active.
+	alerter.ProcessResult(context.Background(), check, &CheckResult{CheckID: check.ID, DeviceID: check.DeviceID, Success: false, CheckedAt: clock})
+
+	req := httptest.NewRequest(http.MethodGet, "/incidents?active=true", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleListIncidents(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("X-Total-Count"); got != "1" {
+		t.Errorf("X-Total-Count = %q, want 1", got)
+	}
+	var incidents []Incident
+	if err := json.NewDecoder(w.Body).Decode(&incidents); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(incidents) != 1 || incidents[0].FlapCount != 6 || incidents[0].ResolvedAt != nil {
+		t.Fatalf("incidents = %+v, want one active incident with flap_count 6", incidents)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/incidents/"+incidents[0].ID, http.NoBody)
+	req.SetPathValue("id", incidents[0].ID)
+	w = httptest.NewRecorder()
+	m.handleGetIncident(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("get status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/incidents/missing", http.NoBody)
+	req.SetPathValue("id", "missing")
+	w = httptest.NewRecorder()
+	m.handleGetIncident(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,177 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func failCheck(a *Alerter, check Check) {
+	a.ProcessResult(context.Background(), check, &CheckResult{
+		CheckID:      check.ID,
+		DeviceID:     check.DeviceID,
+		Success:      false,
+		ErrorMessage: "timeout",
+		CheckedAt:    time.Now().UTC(),
+	})
+}
+
+func listAlertsQuery(t *testing.T, m *Module, query string) []Alert {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/alerts"+query, http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleListAlerts(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("list alerts%s: status = %d", query, w.Code)
+	}
+	var alerts []Alert
+	if err := json.NewDecoder(w.Body).Decode(&alerts); err != nil {
+		t.Fatalf("decode alerts: %v", err)
+	}
+	return alerts
+}
+
+func TestAlerter_LowPriorityCheck_AlertPreAcknowledged(t *testing.T) {
+	m, ps := newTestModule(t)
+	ctx := context.Background()
+	bus := &mockEventBus{}
+	a := NewAlerter(ps, bus, 1, 1, zap.NewNop())
+	a.SetLowPriority(nil, []string{"warning", "critical"})
+
+	check := makeTestCheck(t, ps, "lab-1", "icmp", "10.0.0.50")
+	check.LowPriority = true
+	failCheck(a, check)
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("alert not recorded for low-priority check")
+	}
+	if !alert.LowPriority {
+		t.Error("alert.LowPriority = false, want true")
+	}
+	if alert.AcknowledgedAt == nil {
+		t.Error("alert.AcknowledgedAt = nil, want auto-acknowledged")
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("published %d events, want 0 for low-priority alert", len(bus.events))
+	}
+
+	// Excluded from the default active view and the device health summary.
+	if got := listAlertsQuery(t, m, ""); len(got) != 0 {
+		t.Errorf("default active alerts = %d, want 0", len(got))
+	}
+	if got := listAlertsQuery(t, m, "?low_priority=true"); len(got) != 1 {
+		t.Errorf("low_priority=true alerts = %d, want 1", len(got))
+	}
+	if got := listAlertsQuery(t, m, "?low_priority=all"); len(got) != 1 {
+		t.Errorf("low_priority=all alerts = %d, want 1", len(got))
+	}
+	status, err := m.Status(ctx, "lab-1")
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if !status.Healthy {
+		t.Errorf("Status.Healthy = false (%q), want low-priority alert ignored", status.Message)
+	}
+
+	// Still resolvable, and the resolution is not published either.
+	check.LowPriority = true
+	a.ProcessResult(ctx, check, &CheckResult{CheckID: check.ID, Success: true, CheckedAt: time.Now().UTC()})
+	resolved, err := ps.GetAlert(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetAlert: %v", err)
+	}
+	if resolved.ResolvedAt == nil {
+		t.Error("low-priority alert not resolved on recovery")
+	}
+	if len(bus.events) != 0 {
+		t.Errorf("published %d events after resolve, want 0", len(bus.events))
+	}
+}
+
+func TestAlerter_LowPriorityTag_AlertPreAcknowledged(t *testing.T) {
+	m, ps := newTestModule(t)
+	ctx := context.Background()
+	if _, err := ps.db.ExecContext(ctx,
+		`INSERT INTO recon_devices (id, hostname, tags) VALUES ('lab-2', 'lab-box', '["lab","test"]')`,
+	); err != nil {
+		t.Fatalf("insert device: %v", err)
+	}
+	a := NewAlerter(ps, &mockEventBus{}, 1, 1, zap.NewNop())
+	a.SetLowPriority([]string{"lab"}, []string{"warning"})
+
+	check := makeTestCheck(t, ps, "lab-2", "icmp", "10.0.0.51")
+	failCheck(a, check)
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil || !alert.LowPriority || alert.AcknowledgedAt == nil {
+		t.Fatalf("alert = %+v, want low-priority and acknowledged", alert)
+	}
+	if got := listAlertsQuery(t, m, "?active=true"); len(got) != 0 {
+		t.Errorf("active alerts = %d, want 0", len(got))
+	}
+}
+
+func TestAlerter_LowPriority_SeverityNotAutoAcked(t *testing.T) {
+	_, ps := newTestModule(t)
+	ctx := context.Background()
+	bus := &mockEventBus{}
+	a := NewAlerter(ps, bus, 1, 1, zap.NewNop())
+	// Only critical alerts are auto-acknowledged; the first alert is a warning.
+	a.SetLowPriority(nil, []string{"critical"})
+
+	check := makeTestCheck(t, ps, "lab-3", "icmp", "10.0.0.52")
+	check.LowPriority = true
+	failCheck(a, check)
+
+	alert, err := ps.GetActiveAlert(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetActiveAlert: %v", err)
+	}
+	if alert == nil {
+		t.Fatal("alert not created")
+	}
+	if alert.LowPriority || alert.AcknowledgedAt != nil {
+		t.Errorf("warning alert = %+v, want normal unacknowledged alert", alert)
+	}
+	if len(bus.events) != 1 {
+		t.Errorf("published %d events, want 1", len(bus.events))
+	}
+}
+
+func TestPulseStore_CheckLowPriorityRoundTrip(t *testing.T) {
+	_, ps := newTestModule(t)
+	ctx := context.Background()
+	check := makeTestCheck(t, ps, "dev-lp", "icmp", "10.0.0.60")
+
+	check.LowPriority = true
+	check.UpdatedAt = time.Now().UTC()
+	if err := ps.UpdateCheck(ctx, &check); err != nil {
+		t.Fatalf("UpdateCheck: %v", err)
+	}
+	got, err := ps.GetCheck(ctx, check.ID)
+	if err != nil {
+		t.Fatalf("GetCheck: %v", err)
+	}
+	if !got.LowPriority {
+		t.Error("GetCheck LowPriority = false, want true")
+	}
+	enabled, err := ps.ListEnabledChecks(ctx)
+	if err != nil {
+		t.Fatalf("ListEnabledChecks: %v", err)
+	}
+	if len(enabled) != 1 || !enabled[0].LowPriority {
+		t.Errorf("ListEnabledChecks = %+v, want low-priority check", enabled)
+	}
+}
@@ -8,7 +8,8 @@ import (
 )
 
 // startMaintenance launches a background goroutine that periodically
-// deletes old check results and resolved alerts past the retention window.
+// rolls up metrics and deletes old check results and resolved alerts past
+// the retention window.
 func (m *Module) startMaintenance() {
 	m.wg.Add(1)
 	go func() {
@@ -27,6 +28,26 @@ func (m *Module) startMaintenance() {
 	}()
 }
 
+// startEscalation launches a background goroutine that escalates stale
+// unacknowledged alerts every check interval.
+func (m *Module) startEscalation() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.alerter.Escalate(m.ctx)
+			}
+		}
+	}()
+}
+
 // runMaintenance executes a single maintenance cycle.
 func (m *Module) runMaintenance() {
 	if m.store == nil {
@@ -35,16 +56,36 @@ func (m *Module) runMaintenance() {
 	ctx, cancel := context.WithTimeout(m.ctx, 30*time.Second)
 	defer cancel()
 
-	cutoff := time.Now().Add(-m.cfg.RetentionPeriod)
+	now := time.Now()
+	cutoff := now.Add(-m.cfg.RetentionPeriod)
 
-	// Purge old check results.
-	deletedResults, err := m.store.DeleteOldResults(ctx, cutoff)
-	if err != nil {
+	// Roll up metrics, then purge old check results in batches. Results
+	// are kept when the rollup fails so no history is lost before it is
+	// summarized.
+	if err := m.store.RollupMetrics(ctx, now); err != nil {
+		m.logger.Warn("failed to roll up metrics, keeping old results", zap.Error(err))
+	} else if deletedResults, err := m.store.PruneResults(ctx, cutoff, m.cfg.PruneBatchSize); err != nil {
 		m.logger.Warn("failed to delete old results", zap.Error(err))
 	} else if deletedResults > 0 {
 		m.logger.Info("purged old check results", zap.Int64("count", deletedResults))
 	}
 
+	// Hourly rollups follow the raw retention period; daily rollups are
+	// kept for the longer rollup retention.
+	if deleted, err := m.store.DeleteOldRollups(ctx, RollupHourly, cutoff); err != nil {
+		m.logger.Warn("failed to delete old hourly rollups", zap.Error(err))
+	} else if deleted > 0 {
+		m.logger.Info("purged old hourly rollups", zap.Int64("count", deleted))
+	}
+	if m.cfg.RollupRetention > 0 {
+		deleted, err := m.store.DeleteOldRollups(ctx, RollupDaily, now.Add(-m.cfg.RollupRetention))
+		if err != nil {
+			m.logger.Warn("failed to delete old daily rollups", zap.Error(err))
+		} else if deleted > 0 {
+			m.logger.Info("purged old daily rollups", zap.Int64("count", deleted))
+		}
+	}
+
 	// Purge old resolved alerts.
 	deletedAlerts, err := m.store.DeleteOldAlerts(ctx, cutoff)
 	if err != nil {
@@ -52,4 +93,12 @@ func (m *Module) runMaintenance() {
 	} else if deletedAlerts > 0 {
 		m.logger.Info("purged old resolved alerts", zap.Int64("count", deletedAlerts))
 	}
+
+	// Purge old resolved incidents.
+	deletedIncidents, err := m.store.DeleteOldIncidents(ctx, cutoff)
+	if err != nil {
+		m.logger.Warn("failed to delete old incidents", zap.Error(err))
+	} else if deletedIncidents > 0 {
+		m.logger.Info("purged old resolved incidents", zap.Int64("count", deletedIncidents))
+	}
 }
@@ -432,7 +432,8 @@ func TestRunMaintenance_MixedData(t *testing.T) {
 	// Run maintenance.
 	m.runMaintenance()
 
-	// Verify results: 2 old results deleted, 2 recent remain.
+	// Verify results: the old dev-001 result is deleted. The old dev-002
+	// failure is kept because the alert it belongs to is still active.
 	dev1Results, err := s.ListResults(ctx, "dev-001", 100)
 	if err != nil {
 		t.Fatalf("ListResults dev-001: %v", err)
@@ -448,11 +449,8 @@ func TestRunMaintenance_MixedData(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ListResults dev-002: %v", err)
 	}
-	if len(dev2Results) != 1 {
-		t.Fatalf("expected 1 result for dev-002, got %d", len(dev2Results))
-	}
-	if dev2Results[0].LatencyMs != 15.0 {
-		t.Errorf("dev-002 result LatencyMs = %f, want %f", dev2Results[0].LatencyMs, 15.0)
+	if len(dev2Results) != 2 {
+		t.Fatalf("expected 2 results for dev-002, got %d", len(dev2Results))
 	}
 
 	// Verify alerts: old resolved deleted, recent resolved and active old remain.
@@ -130,5 +130,227 @@ func migrations() []plugin.Migration {
 				return err
 			},
 		},
+		{
+			Version:     6,
+			Description: "create tls certificate state table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pulse_tls_certs (
+					check_id TEXT PRIMARY KEY REFERENCES pulse_checks(id) ON DELETE CASCADE,
+					fingerprint TEXT NOT NULL,
+					issuer TEXT NOT NULL DEFAULT '',
+					subject TEXT NOT NULL DEFAULT '',
+					not_after DATETIME,
+					expect_rotation INTEGER NOT NULL DEFAULT 0,
+					previous_fingerprint TEXT NOT NULL DEFAULT '',
+					previous_issuer TEXT NOT NULL DEFAULT '',
+					changed_at DATETIME,
+					updated_at DATETIME NOT NULL
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     7,
+			Description: "create monitoring policies table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS pulse_monitor_policies (
+					id TEXT PRIMARY KEY,
+					name TEXT NOT NULL,
+					device_type TEXT NOT NULL,
+					checks TEXT NOT NULL DEFAULT '[]',
+					enabled INTEGER NOT NULL DEFAULT 1,
+					created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+				)`)
+				if err != nil {
+					return err
+				}
+				_, err = tx.Exec(`CREATE INDEX IF NOT EXISTS idx_pulse_monitor_policies_type ON pulse_monitor_policies(device_type)`)
+				return err
+			},
+		},
+		{
+			Version:     8,
+			Description: "add low_priority to checks and alerts",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE pulse_checks ADD COLUMN low_priority INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE pulse_alerts ADD COLUMN low_priority INTEGER NOT NULL DEFAULT 0`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     9,
+			Description: "add http response expectations to checks",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE pulse_checks ADD COLUMN expected_status INTEGER NOT NULL DEFAULT 0`,
+					`ALTER TABLE pulse_checks ADD COLUMN expected_body TEXT NOT NULL DEFAULT ''`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     10,
+			Description: "add per-check failure threshold",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_checks ADD COLUMN failure_threshold INTEGER`)
+				return err
+			},
+		},
+		{
+			Version:     11,
+			Description: "add escalated_at to pulse_alerts",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_alerts ADD COLUMN escalated_at DATETIME`)
+				return err
+			},
+		},
+		{
+			Version:     12,
+			Description: "add acknowledgement details and alert annotations",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE pulse_alerts ADD COLUMN acknowledged_by TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE pulse_alerts ADD COLUMN acknowledge_comment TEXT NOT NULL DEFAULT ''`,
+					`CREATE TABLE IF NOT EXISTS pulse_alert_annotations (
+						id INTEGER PRIMARY KEY AUTOINCREMENT,
+						alert_id TEXT NOT NULL REFERENCES pulse_alerts(id) ON DELETE CASCADE,
+						kind TEXT NOT NULL,
+						author TEXT NOT NULL DEFAULT '',
+						body TEXT NOT NULL,
+						created_at DATETIME NOT NULL
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_pulse_alert_annotations_alert ON pulse_alert_annotations(alert_id, id)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     13,
+			Description: "add http phase timing to check results",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_check_results ADD COLUMN timing TEXT`)
+				return err
+			},
+		},
+		{
+			Version:     14,
+			Description: "add per-check timeout",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_checks ADD COLUMN timeout_ms INTEGER NOT NULL DEFAULT 0`)
+				return err
+			},
+		},
+		{
+			Version:     15,
+			Description: "add udp check payload",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_checks ADD COLUMN payload TEXT NOT NULL DEFAULT ''`)
+				return err
+			},
+		},
+		{
+			Version:     16,
+			Description: "add parent check dependency",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE pulse_checks ADD COLUMN parent_check_id TEXT NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_pulse_checks_parent ON pulse_checks(parent_check_id)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     17,
+			Description: "add muted_until to pulse_checks for notification mutes",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`ALTER TABLE pulse_checks ADD COLUMN muted_until DATETIME`)
+				return err
+			},
+		},
+		{
+			Version:     18,
+			Description: "create pulse_incidents for flapping alert roll-up",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`CREATE TABLE IF NOT EXISTS pulse_incidents (
+						id TEXT PRIMARY KEY,
+						check_id TEXT NOT NULL,
+						device_id TEXT NOT NULL,
+						alert_id TEXT NOT NULL,
+						severity TEXT NOT NULL DEFAULT 'warning',
+						message TEXT NOT NULL DEFAULT '',
+						flap_count INTEGER NOT NULL DEFAULT 1,
+						opened_at DATETIME NOT NULL,
+						last_triggered_at DATETIME NOT NULL,
+						resolved_at DATETIME
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_pulse_incidents_check ON pulse_incidents(check_id, resolved_at)`,
+					`CREATE INDEX IF NOT EXISTS idx_pulse_incidents_alert ON pulse_incidents(alert_id)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     19,
+			Description: "create pulse_metric_rollups and pulse_rollup_state for long-range metrics",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`CREATE TABLE IF NOT EXISTS pulse_metric_rollups (
+						check_id TEXT NOT NULL,
+						device_id TEXT NOT NULL,
+						granularity TEXT NOT NULL,
+						bucket_start DATETIME NOT NULL,
+						sample_count INTEGER NOT NULL,
+						success_count INTEGER NOT NULL,
+						latency_sum REAL NOT NULL DEFAULT 0,
+						latency_min REAL NOT NULL DEFAULT 0,
+						latency_max REAL NOT NULL DEFAULT 0,
+						packet_loss_sum REAL NOT NULL DEFAULT 0,
+						packet_loss_min REAL NOT NULL DEFAULT 0,
+						packet_loss_max REAL NOT NULL DEFAULT 0,
+						PRIMARY KEY (check_id, granularity, bucket_start)
+					)`,
+					`CREATE INDEX IF NOT EXISTS idx_pulse_rollups_device ON pulse_metric_rollups(device_id, granularity, bucket_start)`,
+					`CREATE TABLE IF NOT EXISTS pulse_rollup_state (
+						granularity TEXT PRIMARY KEY,
+						rolled_through DATETIME NOT NULL
+					)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
 	}
 }
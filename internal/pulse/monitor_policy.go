@@ -0,0 +1,531 @@
+package pulse
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// MonitorPolicy maps a device type to the checks that are created automatically
+// when Recon discovers a device of that type. Devices that match no enabled
+// policy fall back to the default single ICMP check.
+type MonitorPolicy struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	DeviceType string        `json:"device_type"`
+	Checks     []PolicyCheck `json:"checks"`
+	Enabled    bool          `json:"enabled"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+}
+
+// PolicyCheck is a check template in a monitoring policy. The target is
+// derived from the discovered device's first IP address.
+type PolicyCheck struct {
+	CheckType string `json:"check_type"`     // icmp, tcp, http, tls
+	Port      int    `json:"port,omitempty"` // required for tcp; defaults to 443 for tls
+	Path      string `json:"path,omitempty"` // http only; defaults to "/"
+}
+
+// monitorPolicyRequest is the JSON body for POST and PUT /policies.
+type monitorPolicyRequest struct {
+	Name       string        `json:"name"`
+	DeviceType string        `json:"device_type"`
+	Checks     []PolicyCheck `json:"checks"`
+	Enabled    *bool         `json:"enabled,omitempty"`
+}
+
+// -- Monitoring Policy CRUD --
+
+// InsertMonitorPolicy inserts a new monitoring policy.
+func (s *PulseStore) InsertMonitorPolicy(ctx context.Context, p *MonitorPolicy) error {
+	enabled := 0
+	if p.Enabled {
+		enabled = 1
+	}
+	checksJSON, err := json.Marshal(p.Checks)
+	if err != nil {
+		return fmt.Errorf("marshal policy checks: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO pulse_monitor_policies (
+			id, name, device_type, checks, enabled, created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		p.ID, p.Name, p.DeviceType, string(checksJSON), enabled, p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert monitor policy: %w", err)
+	}
+	return nil
+}
+
+// GetMonitorPolicy returns a monitoring policy by ID. Returns nil, nil if not found.
+func (s *PulseStore) GetMonitorPolicy(ctx context.Context, id string) (*MonitorPolicy, error) {
+	var p MonitorPolicy
+	var enabledInt int
+	var checksJSON string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, name, device_type, checks, enabled, created_at, updated_at
+		FROM pulse_monitor_policies WHERE id = ?`,
+		id,
+	).Scan(&p.ID, &p.Name, &p.DeviceType, &checksJSON, &enabledInt, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get monitor policy: %w", err)
+	}
+	p.Enabled = enabledInt != 0
+	if err := json.Unmarshal([]byte(checksJSON), &p.Checks); err != nil {
+		return nil, fmt.Errorf("unmarshal policy checks: %w", err)
+	}
+	return &p, nil
+}
+
+// ListMonitorPolicies returns all monitoring policies.
+func (s *PulseStore) ListMonitorPolicies(ctx context.Context) ([]MonitorPolicy, error) {
+	return s.queryMonitorPolicies(ctx, `
+		SELECT id, name, device_type, checks, enabled, created_at, updated_at
+		FROM pulse_monitor_policies ORDER BY created_at`)
+}
+
+// ListEnabledMonitorPolicies returns the enabled policies for a device type.
+func (s *PulseStore) ListEnabledMonitorPolicies(ctx context.Context, deviceType string) ([]MonitorPolicy, error) {
+	return s.queryMonitorPolicies(ctx, `
+		SELECT id, name, device_type, checks, enabled, created_at, updated_at
+		FROM pulse_monitor_policies WHERE enabled = 1 AND device_type = ? ORDER BY created_at`,
+		deviceType)
+}
+
+func (s *PulseStore) queryMonitorPolicies(ctx context.Context, query string, args ...any) ([]MonitorPolicy, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list monitor policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []MonitorPolicy
+	for rows.Next() {
+		var p MonitorPolicy
+		var enabledInt int
+		var checksJSON string
+		if err := rows.Scan(
+			&p.ID, &p.Name, &p.DeviceType, &checksJSON, &enabledInt, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan monitor policy: %w", err)
+		}
+		p.Enabled = enabledInt != 0
+		if err := json.Unmarshal([]byte(checksJSON), &p.Checks); err != nil {
+			return nil, fmt.Errorf("unmarshal policy checks: %w", err)
+		}
+		policies = append(policies, p)
+	}
+	return policies, rows.Err()
+}
+
+// UpdateMonitorPolicy updates an existing monitoring policy.
+func (s *PulseStore) UpdateMonitorPolicy(ctx context.Context, p *MonitorPolicy) error {
+	enabled := 0
+	if p.Enabled {
+		enabled = 1
+	}
+	checksJSON, err := json.Marshal(p.Checks)
+	if err != nil {
+		return fmt.Errorf("marshal policy checks: %w", err)
+	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE pulse_monitor_policies SET
+			name = ?, device_type = ?, checks = ?, enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		p.Name, p.DeviceType, string(checksJSON), enabled, p.UpdatedAt, p.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update monitor policy: %w", err)
+	}
+	return nil
+}
+
+// DeleteMonitorPolicy removes a monitoring policy by ID. It returns
+// sql.ErrNoRows if no policy has that ID.
+func (s *PulseStore) DeleteMonitorPolicy(ctx context.Context, id string) error {
+	res, err := s.db.ExecContext(ctx, `DELETE FROM pulse_monitor_policies WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete monitor policy: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("delete monitor policy rows affected: %w", err)
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// -- Policy Evaluation --
+
+// applyMonitorPolicies creates the checks defined by every enabled policy
+// matching the device's type. Checks whose type and target already exist for
+// the device are skipped. It returns false when no policy matches, so the
+// caller can fall back to the default check.
+func (m *Module) applyMonitorPolicies(ctx context.Context, device *models.Device) bool {
+	policies, err := m.store.ListEnabledMonitorPolicies(ctx, string(device.DeviceType))
+	if err != nil {
+		m.logger.Warn("failed to list monitor policies",
+			zap.String("device_id", device.ID),
+			zap.Error(err),
+		)
+		return false
+	}
+	if len(policies) == 0 {
+		return false
+	}
+
+	existing, err := m.store.ListChecksByDevice(ctx, device.ID)
+	if err != nil {
+		m.logger.Warn("failed to list existing checks for policy",
+			zap.String("device_id", device.ID),
+			zap.Error(err),
+		)
+		return true
+	}
+	have := make(map[string]bool, len(existing))
+	for i := range existing {
+		have[existing[i].CheckType+" "+existing[i].Target] = true
+	}
+
+	ip := device.IPAddresses[0]
+	now := time.Now().UTC()
+	for i := range policies {
+		for _, pc := range policies[i].Checks {
+			target := policyCheckTarget(pc, ip)
+			key := pc.CheckType + " " + target
+			if have[key] {
+				continue
+			}
+			have[key] = true
+
+			check := &Check{
+				ID:              policyCheckID(device.ID, pc),
+				DeviceID:        device.ID,
+				CheckType:       pc.CheckType,
+				Target:          target,
+				IntervalSeconds: int(m.cfg.CheckInterval.Seconds()),
+				Enabled:         true,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			}
+			if err := m.store.InsertCheck(ctx, check); err != nil {
+				m.logger.Warn("failed to create policy check",
+					zap.String("policy_id", policies[i].ID),
+					zap.String("device_id", device.ID),
+					zap.String("target", target),
+					zap.Error(err),
+				)
+				continue
+			}
+			m.logger.Info("created pulse check from monitoring policy",
+				zap.String("policy_id", policies[i].ID),
+				zap.String("check_id", check.ID),
+				zap.String("device_id", device.ID),
+				zap.String("check_type", check.CheckType),
+				zap.String("target", target),
+			)
+		}
+	}
+	return true
+}
+
+// policyCheckTarget builds the check target for a policy check on the given IP.
+func policyCheckTarget(pc PolicyCheck, ip string) string {
+	switch pc.CheckType {
+	case "tcp":
+		return net.JoinHostPort(ip, strconv.Itoa(pc.Port))
+	case "tls":
+		port := pc.Port
+		if port == 0 {
+			port = 443
+		}
+		return net.JoinHostPort(ip, strconv.Itoa(port))
+	case "http":
+		host := ip
+		if strings.Contains(ip, ":") {
+			host = "[" + ip + "]"
+		}
+		scheme := "http"
+		if pc.Port == 443 {
+			scheme = "https"
+		} else if pc.Port != 0 && pc.Port != 80 {
+			host = net.JoinHostPort(ip, strconv.Itoa(pc.Port))
+		}
+		path := pc.Path
+		if path == "" {
+			path = "/"
+		}
+		return scheme + "://" + host + path
+	default:
+		return ip
+	}
+}
+
+// policyCheckID returns a stable check ID for a policy check on a device.
+// The ID covers the check's whole target (type, port and HTTP path), so two
+// checks of one type in a policy get distinct IDs. The path is hashed to keep
+// the ID usable as a URL path segment.
+func policyCheckID(deviceID string, pc PolicyCheck) string {
+	id := fmt.Sprintf("pulse-%s-%s", deviceID, pc.CheckType)
+	if pc.Port != 0 {
+		id += "-" + strconv.Itoa(pc.Port)
+	}
+	if pc.Path != "" && pc.Path != "/" {
+		sum := sha256.Sum256([]byte(pc.Path))
+		id += "-" + hex.EncodeToString(sum[:4])
+	}
+	return id
+}
+
+// validatePolicyChecks returns a problem detail for invalid policy checks, or "".
+func validatePolicyChecks(checks []PolicyCheck) string {
+	if len(checks) == 0 {
+		return "checks must not be empty"
+	}
+	for i, pc := range checks {
+		switch pc.CheckType {
+		case "icmp", "http", "tls":
+		case "tcp":
+			if pc.Port == 0 {
+				return fmt.Sprintf("checks[%d]: port is required for tcp", i)
+			}
+		default:
+			return fmt.Sprintf("checks[%d]: check_type must be icmp, tcp, http, or tls", i)
+		}
+		if pc.Port < 0 || pc.Port > 65535 {
+			return fmt.Sprintf("checks[%d]: port must be between 1 and 65535", i)
+		}
+		if pc.Path != "" && (pc.CheckType != "http" || !strings.HasPrefix(pc.Path, "/")) {
+			return fmt.Sprintf("checks[%d]: path is only valid for http and must start with /", i)
+		}
+	}
+	return ""
+}
+
+// -- Monitoring policy handlers --
+
+// handleListMonitorPolicies returns all monitoring policies.
+//
+//	@Summary		List monitor policies
+//	@Description	Returns all monitoring policies that map device types to auto-created checks.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200 {array} MonitorPolicy
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/policies [get]
+func (m *Module) handleListMonitorPolicies(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	policies, err := m.store.ListMonitorPolicies(r.Context())
+	if err != nil {
+		m.logger.Warn("failed to list monitor policies", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to list monitor policies")
+		return
+	}
+	if policies == nil {
+		policies = []MonitorPolicy{}
+	}
+	pulseWriteJSON(w, http.StatusOK, policies)
+}
+
+// handleCreateMonitorPolicy creates a new monitoring policy.
+//
+//	@Summary		Create monitor policy
+//	@Description	Creates a monitoring policy. Devices of the policy's type discovered afterwards get its checks.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request body monitorPolicyRequest true "Monitor policy"
+//	@Success		201 {object} MonitorPolicy
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/policies [post]
+func (m *Module) handleCreateMonitorPolicy(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	var req monitorPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name == "" {
+		pulseWriteError(w, http.StatusBadRequest, "name is required")
+		return
+	}
+	if req.DeviceType == "" {
+		pulseWriteError(w, http.StatusBadRequest, "device_type is required")
+		return
+	}
+	if msg := validatePolicyChecks(req.Checks); msg != "" {
+		pulseWriteError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	now := time.Now().UTC()
+	p := &MonitorPolicy{
+		ID:         uuid.New().String(),
+		Name:       req.Name,
+		DeviceType: req.DeviceType,
+		Checks:     req.Checks,
+		Enabled:    req.Enabled == nil || *req.Enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := m.store.InsertMonitorPolicy(r.Context(), p); err != nil {
+		m.logger.Warn("failed to create monitor policy", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to create monitor policy")
+		return
+	}
+	pulseWriteJSON(w, http.StatusCreated, p)
+}
+
+// handleGetMonitorPolicy returns a single monitoring policy by ID.
+//
+//	@Summary		Get monitor policy
+//	@Description	Returns a single monitoring policy by ID.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id path string true "Policy ID"
+//	@Success		200 {object} MonitorPolicy
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/policies/{id} [get]
+func (m *Module) handleGetMonitorPolicy(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	id := r.PathValue("id")
+	p, err := m.store.GetMonitorPolicy(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get monitor policy", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get monitor policy")
+		return
+	}
+	if p == nil {
+		pulseWriteError(w, http.StatusNotFound, "monitor policy not found")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, p)
+}
+
+// handleUpdateMonitorPolicy updates an existing monitoring policy.
+// Omitted fields are left unchanged.
+//
+//	@Summary		Update monitor policy
+//	@Description	Updates a monitoring policy. Omitted fields are left unchanged.
+//	@Tags			pulse
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id path string true "Policy ID"
+//	@Param			request body monitorPolicyRequest true "Fields to update"
+//	@Success		200 {object} MonitorPolicy
+//	@Failure		400 {object} map[string]any
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/policies/{id} [put]
+func (m *Module) handleUpdateMonitorPolicy(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	id := r.PathValue("id")
+	existing, err := m.store.GetMonitorPolicy(r.Context(), id)
+	if err != nil {
+		m.logger.Warn("failed to get monitor policy for update", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get monitor policy")
+		return
+	}
+	if existing == nil {
+		pulseWriteError(w, http.StatusNotFound, "monitor policy not found")
+		return
+	}
+
+	var req monitorPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		pulseWriteError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Name != "" {
+		existing.Name = req.Name
+	}
+	if req.DeviceType != "" {
+		existing.DeviceType = req.DeviceType
+	}
+	if req.Checks != nil {
+		if msg := validatePolicyChecks(req.Checks); msg != "" {
+			pulseWriteError(w, http.StatusBadRequest, msg)
+			return
+		}
+		existing.Checks = req.Checks
+	}
+	if req.Enabled != nil {
+		existing.Enabled = *req.Enabled
+	}
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := m.store.UpdateMonitorPolicy(r.Context(), existing); err != nil {
+		m.logger.Warn("failed to update monitor policy", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to update monitor policy")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, existing)
+}
+
+// handleDeleteMonitorPolicy deletes a monitoring policy. Checks already
+// created by the policy are kept.
+//
+//	@Summary		Delete monitor policy
+//	@Description	Deletes a monitoring policy. Checks already created by the policy are kept.
+//	@Tags			pulse
+//	@Security		BearerAuth
+//	@Param			id path string true "Policy ID"
+//	@Success		204
+//	@Failure		404 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/policies/{id} [delete]
+func (m *Module) handleDeleteMonitorPolicy(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+	id := r.PathValue("id")
+	if err := m.store.DeleteMonitorPolicy(r.Context(), id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			pulseWriteError(w, http.StatusNotFound, "monitor policy not found")
+			return
+		}
+		m.logger.Warn("failed to delete monitor policy", zap.String("id", id), zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to delete monitor policy")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,222 @@
+package pulse
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/recon"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+func insertTestPolicy(t *testing.T, ps *PulseStore, deviceType string, enabled bool, checks ...PolicyCheck) {
+	t.Helper()
+	now := time.Now().UTC()
+	p := &MonitorPolicy{
+		ID:         "policy-" + deviceType,
+		Name:       deviceType + " defaults",
+		DeviceType: deviceType,
+		Checks:     checks,
+		Enabled:    enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := ps.InsertMonitorPolicy(context.Background(), p); err != nil {
+		t.Fatalf("InsertMonitorPolicy: %v", err)
+	}
+}
+
+func discoverDevice(m *Module, id string, deviceType models.DeviceType, ip string) {
+	m.handleDeviceDiscovered(context.Background(), plugin.Event{
+		Topic: recon.TopicDeviceDiscovered,
+		Payload: &recon.DeviceEvent{
+			ScanID: "scan-1",
+			Device: &models.Device{ID: id, DeviceType: deviceType, IPAddresses: []string{ip}},
+		},
+	})
+}
+
+func deviceCheckKeys(t *testing.T, ps *PulseStore, deviceID string) []string {
+	t.Helper()
+	checks, err := ps.ListChecksByDevice(context.Background(), deviceID)
+	if err != nil {
+		t.Fatalf("ListChecksByDevice: %v", err)
+	}
+	keys := make([]string, 0, len(checks))
+	for i := range checks {
+		keys = append(keys, checks[i].CheckType+" "+checks[i].Target)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMonitorPolicy_RouterAndServerGetDifferentChecks(t *testing.T) {
+	m, ps := newTestModule(t)
+	insertTestPolicy(t, ps, "router", true, PolicyCheck{CheckType: "icmp"})
+	insertTestPolicy(t, ps, "server", true,
+		PolicyCheck{CheckType: "icmp"},
+		PolicyCheck{CheckType: "tcp", Port: 22},
+	)
+
+	discoverDevice(m, "router-1", models.DeviceTypeRouter, "192.168.1.1")
+	discoverDevice(m, "server-1", models.DeviceTypeServer, "192.168.1.10")
+
+	if got, want := deviceCheckKeys(t, ps, "router-1"), []string{"icmp 192.168.1.1"}; !equalKeys(got, want) {
+		t.Errorf("router checks = %v, want %v", got, want)
+	}
+	if got, want := deviceCheckKeys(t, ps, "server-1"), []string{"icmp 192.168.1.10", "tcp 192.168.1.10:22"}; !equalKeys(got, want) {
+		t.Errorf("server checks = %v, want %v", got, want)
+	}
+}
+
+func TestMonitorPolicy_DoesNotDuplicateExistingChecks(t *testing.T) {
+	m, ps := newTestModule(t)
+	ctx := context.Background()
+	insertTestPolicy(t, ps, "server", true,
+		PolicyCheck{CheckType: "icmp"},
+		PolicyCheck{CheckType: "tcp", Port: 22},
+	)
+
+	// A manually created ICMP check already covers part of the policy.
+	now := time.Now().UTC()
+	if err := ps.InsertCheck(ctx, &Check{
+		ID: "manual-icmp", DeviceID: "server-1", CheckType: "icmp", Target: "192.168.1.10",
+		IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now,
+	}); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+
+	discoverDevice(m, "server-1", models.DeviceTypeServer, "192.168.1.10")
+	discoverDevice(m, "server-1", models.DeviceTypeServer, "192.168.1.10")
+
+	if got, want := deviceCheckKeys(t, ps, "server-1"), []string{"icmp 192.168.1.10", "tcp 192.168.1.10:22"}; !equalKeys(got, want) {
+		t.Errorf("server checks = %v, want %v", got, want)
+	}
+}
+
+func TestMonitorPolicy_NoMatchFallsBackToDefault(t *testing.T) {
+	m, ps := newTestModule(t)
+	insertTestPolicy(t, ps, "router", true, PolicyCheck{CheckType: "tcp", Port: 443})
+	insertTestPolicy(t, ps, "server", false, PolicyCheck{CheckType: "tcp", Port: 22})
+
+	discoverDevice(m, "printer-1", models.DeviceTypePrinter, "192.168.1.20")
+	discoverDevice(m, "server-1", models.DeviceTypeServer, "192.168.1.10")
+
+	if got, want := deviceCheckKeys(t, ps, "printer-1"), []string{"icmp 192.168.1.20"}; !equalKeys(got, want) {
+		t.Errorf("printer checks = %v, want default %v", got, want)
+	}
+	if got, want := deviceCheckKeys(t, ps, "server-1"), []string{"icmp 192.168.1.10"}; !equalKeys(got, want) {
+		t.Errorf("server checks with disabled policy = %v, want default %v", got, want)
+	}
+}
+
+func TestMonitorPolicy_SameTypeChecksGetDistinctIDs(t *testing.T) {
+	m, ps := newTestModule(t)
+	insertTestPolicy(t, ps, "server", true,
+		PolicyCheck{CheckType: "http", Port: 8080, Path: "/health"},
+		PolicyCheck{CheckType: "http", Port: 8080, Path: "/metrics"},
+		PolicyCheck{CheckType: "tcp", Port: 22},
+		PolicyCheck{CheckType: "tcp", Port: 5432},
+	)
+
+	discoverDevice(m, "server-1", models.DeviceTypeServer, "192.168.1.10")
+
+	want := []string{
+		"http http://192.168.1.10:8080/health",
+		"http http://192.168.1.10:8080/metrics",
+		"tcp 192.168.1.10:22",
+		"tcp 192.168.1.10:5432",
+	}
+	if got := deviceCheckKeys(t, ps, "server-1"); !equalKeys(got, want) {
+		t.Errorf("server checks = %v, want %v", got, want)
+	}
+}
+
+func TestHandleDeleteMonitorPolicy_NotFound(t *testing.T) {
+	m, ps := newTestModule(t)
+	insertTestPolicy(t, ps, "server", true, PolicyCheck{CheckType: "icmp"})
+
+	tests := []struct {
+		id         string
+		wantStatus int
+	}{
+		{"policy-server", http.StatusNoContent},
+		{"policy-server", http.StatusNotFound},
+		{"nonexistent", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodDelete, "/policies/"+tt.id, http.NoBody)
+		req.SetPathValue("id", tt.id)
+		w := httptest.NewRecorder()
+
+		m.handleDeleteMonitorPolicy(w, req)
+
+		if w.Code != tt.wantStatus {
+			t.Errorf("DELETE %s: status = %d, want %d", tt.id, w.Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestPolicyCheckTarget(t *testing.T) {
+	tests := []struct {
+		name string
+		pc   PolicyCheck
+		ip   string
+		want string
+	}{
+		{"icmp", PolicyCheck{CheckType: "icmp"}, "10.0.0.1", "10.0.0.1"},
+		{"tcp", PolicyCheck{CheckType: "tcp", Port: 22}, "10.0.0.1", "10.0.0.1:22"},
+		{"tls default port", PolicyCheck{CheckType: "tls"}, "10.0.0.1", "10.0.0.1:443"},
+		{"http default", PolicyCheck{CheckType: "http"}, "10.0.0.1", "http://10.0.0.1/"},
+		{"http custom port and path", PolicyCheck{CheckType: "http", Port: 8080, Path: "/health"}, "10.0.0.1", "http://10.0.0.1:8080/health"},
+		{"https", PolicyCheck{CheckType: "http", Port: 443}, "10.0.0.1", "https://10.0.0.1/"},
+		{"ipv6 tcp", PolicyCheck{CheckType: "tcp", Port: 22}, "fe80::1", "[fe80::1]:22"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policyCheckTarget(tt.pc, tt.ip); got != tt.want {
+				t.Errorf("policyCheckTarget() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePolicyChecks(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []PolicyCheck
+		valid  bool
+	}{
+		{"empty", nil, false},
+		{"icmp", []PolicyCheck{{CheckType: "icmp"}}, true},
+		{"tcp without port", []PolicyCheck{{CheckType: "tcp"}}, false},
+		{"unknown type", []PolicyCheck{{CheckType: "snmp"}}, false},
+		{"port out of range", []PolicyCheck{{CheckType: "tcp", Port: 70000}}, false},
+		{"path on tcp", []PolicyCheck{{CheckType: "tcp", Port: 22, Path: "/x"}}, false},
+		{"http path", []PolicyCheck{{CheckType: "http", Path: "/health"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := validatePolicyChecks(tt.checks)
+			if (msg == "") != tt.valid {
+				t.Errorf("validatePolicyChecks() = %q, want valid=%v", msg, tt.valid)
+			}
+		})
+	}
+}
@@ -36,8 +36,11 @@ func (d *NotificationDispatcher) HandleAlertEvent(ctx context.Context, event plu
 
 	// Determine event type from topic.
 	eventType := "triggered"
-	if strings.HasSuffix(event.Topic, ".resolved") {
+	switch {
+	case strings.HasSuffix(event.Topic, ".resolved"):
 		eventType = "resolved"
+	case strings.HasSuffix(event.Topic, ".escalated"):
+		eventType = "escalated"
 	}
 
 	channels, err := d.store.ListEnabledChannels(ctx)
@@ -7,7 +7,8 @@ import (
 
 // Notifier delivers alert notifications through a specific channel type.
 type Notifier interface {
-	// Notify sends an alert notification. eventType is "triggered" or "resolved".
+	// Notify sends an alert notification. eventType is "triggered",
+	// "escalated", or "resolved".
 	Notify(ctx context.Context, alert *Alert, eventType string) error
 	// Type returns the notifier type identifier (e.g., "webhook", "alertmanager", "email").
 	Type() string
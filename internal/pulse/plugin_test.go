@@ -3,6 +3,7 @@ package pulse
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/HerbHall/subnetree/internal/config"
 	"github.com/HerbHall/subnetree/internal/store"
@@ -106,6 +107,36 @@ func TestHealth_ReportsStatus(t *testing.T) {
 	}
 }
 
+func TestReadiness(t *testing.T) {
+	db, err := store.New(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m := New()
+	if err := m.Init(context.Background(), plugin.Dependencies{Logger: zap.NewNop(), Store: db}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := m.Readiness(); err == nil {
+		t.Error("Readiness() before Start = nil, want error")
+	}
+
+	if err := m.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := m.Readiness(); err != nil {
+		t.Errorf("Readiness() after Start = %v, want nil", err)
+	}
+
+	if err := m.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := m.Readiness(); err == nil {
+		t.Error("Readiness() after Stop = nil, want error")
+	}
+}
+
 func TestHealth_WithStore(t *testing.T) {
 	db, err := store.New(":memory:")
 	if err != nil {
@@ -145,14 +176,16 @@ func TestSubscriptions_ReturnsTopics(t *testing.T) {
 	m := New()
 
 	subs := m.Subscriptions()
-	if len(subs) != 3 {
-		t.Fatalf("Subscriptions() returned %d, want 3", len(subs))
+	if len(subs) != 5 {
+		t.Fatalf("Subscriptions() returned %d, want 5", len(subs))
 	}
 
 	expectedTopics := map[string]bool{
 		TopicDeviceDiscovered: false,
 		TopicAlertTriggered:   false,
 		TopicAlertResolved:    false,
+		TopicAlertEscalated:   false,
+		TopicCertChanged:      false,
 	}
 	for i := range subs {
 		if subs[i].Handler == nil {
@@ -169,3 +202,61 @@ func TestSubscriptions_ReturnsTopics(t *testing.T) {
 		}
 	}
 }
+
+func TestMonitoringHistoryProvider(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	for _, dev := range []string{"dev-001", "dev-002", "dev-003"} {
+		insertTestCheck(t, s, &Check{
+			ID: "chk-" + dev, DeviceID: dev, CheckType: "icmp", Target: "192.168.1.1",
+			IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+	resolved := now.Add(-time.Minute)
+	for _, a := range []*Alert{
+		{ID: "a-old", CheckID: "chk-dev-001", DeviceID: "dev-001", Severity: "warning", Message: "older", TriggeredAt: now.Add(-2 * time.Hour)},
+		{ID: "a-new", CheckID: "chk-dev-001", DeviceID: "dev-001", Severity: "critical", Message: "unreachable", TriggeredAt: now.Add(-time.Hour)},
+		{ID: "a-low", CheckID: "chk-dev-002", DeviceID: "dev-002", Severity: "warning", Message: "low priority", TriggeredAt: now, LowPriority: true},
+		{ID: "a-done", CheckID: "chk-dev-003", DeviceID: "dev-003", Severity: "warning", Message: "recovered", TriggeredAt: now.Add(-3 * time.Hour), ResolvedAt: &resolved},
+	} {
+		if err := s.InsertAlert(ctx, a); err != nil {
+			t.Fatalf("InsertAlert(%s): %v", a.ID, err)
+		}
+	}
+
+	m := New()
+	m.store = s
+
+	alerts, err := m.Alerts(ctx, "", true, 10)
+	if err != nil {
+		t.Fatalf("Alerts: %v", err)
+	}
+	if len(alerts) != 3 || alerts[0].ID != "a-low" {
+		t.Errorf("active alerts = %+v, want 3 newest first", alerts)
+	}
+	alerts, _ = m.Alerts(ctx, "dev-003", false, 10)
+	if len(alerts) != 1 || alerts[0].ResolvedAt == nil {
+		t.Errorf("dev-003 alerts = %+v, want the resolved alert", alerts)
+	}
+
+	down, err := m.DownDevices(ctx)
+	if err != nil {
+		t.Fatalf("DownDevices: %v", err)
+	}
+	if len(down) != 1 || down[0].DeviceID != "dev-001" || down[0].Message != "unreachable" || down[0].Healthy {
+		t.Errorf("down = %+v, want dev-001 with its latest alert", down)
+	}
+
+	uptime, err := m.Uptime(ctx, "dev-001", "24h")
+	if err != nil {
+		t.Fatalf("Uptime: %v", err)
+	}
+	if uptime.DeviceID != "dev-001" || uptime.Range != "24h" || uptime.UptimePercent != nil {
+		t.Errorf("uptime = %+v, want empty stats for a device without results", uptime)
+	}
+	if _, err := m.Uptime(ctx, "dev-001", "2y"); err == nil {
+		t.Error("Uptime with unknown range: expected error")
+	}
+}
@@ -0,0 +1,87 @@
+package pulse
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Prometheus check and alert metrics, served by the server's /metrics
+// endpoint. Labels are limited to device_id and check_type (or severity)
+// to keep cardinality bounded; per-check IDs and targets are deliberately
+// left out.
+var (
+	pulseCheckUp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_check_up",
+			Help: "Whether the latest check result was successful (1) or failed (0).",
+		},
+		[]string{"device_id", "check_type"},
+	)
+	pulseCheckLatency = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "pulse_check_latency_ms",
+			Help:    "Check latency in milliseconds.",
+			Buckets: []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000},
+		},
+		[]string{"check_type"},
+	)
+	pulseAlertActive = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "pulse_alert_active",
+			Help: "Number of unresolved alerts.",
+		},
+		[]string{"severity"},
+	)
+	pulseAlertsTriggeredTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "pulse_alerts_triggered_total",
+			Help: "Total number of alerts triggered.",
+		},
+		[]string{"severity"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(pulseCheckUp)
+	prometheus.MustRegister(pulseCheckLatency)
+	prometheus.MustRegister(pulseAlertActive)
+	prometheus.MustRegister(pulseAlertsTriggeredTotal)
+}
+
+// observeCheckResult updates the check metrics from a stored result.
+func observeCheckResult(check *Check, result *CheckResult) {
+	checkType := check.CheckType
+	if checkType == "" {
+		checkType = "icmp"
+	}
+	up := 0.0
+	if result.Success {
+		up = 1
+		pulseCheckLatency.WithLabelValues(checkType).Observe(result.LatencyMs)
+	}
+	pulseCheckUp.WithLabelValues(check.DeviceID, checkType).Set(up)
+}
+
+// syncAlertMetrics recomputes pulse_alert_active from the store, leaving out
+// auto-acknowledged low-priority alerts. It is used
+// at startup and after manual resolution, where the alerter's incremental
+// updates don't apply.
+func (m *Module) syncAlertMetrics(ctx context.Context) {
+	if m.store == nil {
+		return
+	}
+	alerts, err := m.store.ListActiveAlerts(ctx, "")
+	if err != nil {
+		m.logger.Warn("failed to sync alert metrics", zap.Error(err))
+		return
+	}
+	pulseAlertActive.Reset()
+	for i := range alerts {
+		if alerts[i].LowPriority {
+			continue
+		}
+		pulseAlertActive.WithLabelValues(alerts[i].Severity).Inc()
+	}
+}
@@ -0,0 +1,106 @@
+package pulse
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeMetric scrapes the default registry the same way /metrics does and
+// returns the value of the series whose name and labels match exactly.
+func scrapeMetric(t *testing.T, series string) (float64, bool) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{}).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		line := sc.Text()
+		if !strings.HasPrefix(line, series+" ") {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimPrefix(line, series+" "), 64)
+		if err != nil {
+			t.Fatalf("parse %q: %v", line, err)
+		}
+		return v, true
+	}
+	return 0, false
+}
+
+func TestPrometheus_CheckUpReflectsLatestResult(t *testing.T) {
+	m, ps := newTestModule(t)
+	ctx := context.Background()
+	check := Check{
+		ID: "chk-prom-1", DeviceID: "dev-prom-1", CheckType: "tcp", Target: "10.0.0.1:22",
+		IntervalSeconds: 30, Enabled: true, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+	}
+	if err := ps.InsertCheck(ctx, &check); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+	series := `pulse_check_up{check_type="tcp",device_id="dev-prom-1"}`
+
+	m.checkers = map[string]Checker{
+		"tcp": newMockChecker(&CheckResult{Success: true, LatencyMs: 12, CheckedAt: time.Now().UTC()}, nil),
+	}
+	m.executeCheck(ctx, check)
+	if v, ok := scrapeMetric(t, series); !ok || v != 1 {
+		t.Errorf("%s = %v (found=%v), want 1 after success", series, v, ok)
+	}
+
+	m.checkers["tcp"] = newMockChecker(&CheckResult{Success: false, PacketLoss: 1, CheckedAt: time.Now().UTC()}, nil)
+	m.executeCheck(ctx, check)
+	if v, ok := scrapeMetric(t, series); !ok || v != 0 {
+		t.Errorf("%s = %v (found=%v), want 0 after failure", series, v, ok)
+	}
+
+	if v, ok := scrapeMetric(t, `pulse_check_latency_ms_count{check_type="tcp"}`); !ok || v < 1 {
+		t.Errorf("latency histogram count = %v (found=%v), want >= 1", v, ok)
+	}
+}
+
+func TestPrometheus_AlertMetrics(t *testing.T) {
+	m, ps := newTestModule(t)
+	ctx := context.Background()
+	check := Check{
+		ID: "chk-prom-2", DeviceID: "dev-prom-2", CheckType: "tcp", Target: "10.0.0.2:22",
+		IntervalSeconds: 30, Enabled: true, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC(),
+	}
+	if err := ps.InsertCheck(ctx, &check); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+	m.alerter = NewAlerter(ps, nil, 1, 1, m.logger)
+	m.syncAlertMetrics(ctx)
+
+	const triggered = `pulse_alerts_triggered_total{severity="warning"}`
+	const active = `pulse_alert_active{severity="warning"}`
+	before, _ := scrapeMetric(t, triggered)
+
+	m.checkers = map[string]Checker{
+		"tcp": newMockChecker(&CheckResult{Success: false, PacketLoss: 1, CheckedAt: time.Now().UTC()}, nil),
+	}
+	m.executeCheck(ctx, check)
+
+	if v, _ := scrapeMetric(t, triggered); v != before+1 {
+		t.Errorf("%s = %v, want %v", triggered, v, before+1)
+	}
+	if v, _ := scrapeMetric(t, active); v != 1 {
+		t.Errorf("%s = %v, want 1 while alert is active", active, v)
+	}
+
+	m.checkers["tcp"] = newMockChecker(&CheckResult{Success: true, LatencyMs: 3, CheckedAt: time.Now().UTC()}, nil)
+	m.executeCheck(ctx, check)
+
+	if v, _ := scrapeMetric(t, active); v != 0 {
+		t.Errorf("%s = %v, want 0 after resolve", active, v)
+	}
+}
@@ -2,6 +2,7 @@ package pulse
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -14,20 +15,22 @@ import (
 
 // Compile-time interface guards.
 var (
-	_ plugin.Plugin            = (*Module)(nil)
-	_ plugin.HTTPProvider      = (*Module)(nil)
-	_ plugin.HealthChecker     = (*Module)(nil)
-	_ plugin.EventSubscriber   = (*Module)(nil)
-	_ roles.MonitoringProvider = (*Module)(nil)
+	_ plugin.Plugin                   = (*Module)(nil)
+	_ plugin.HTTPProvider             = (*Module)(nil)
+	_ plugin.HealthChecker            = (*Module)(nil)
+	_ plugin.ReadinessReporter        = (*Module)(nil)
+	_ plugin.EventSubscriber          = (*Module)(nil)
+	_ roles.MonitoringProvider        = (*Module)(nil)
+	_ roles.MonitoringHistoryProvider = (*Module)(nil)
 )
 
 // Module implements the Pulse monitoring plugin.
 type Module struct {
-	logger    *zap.Logger
-	cfg       PulseConfig
-	store     *PulseStore
-	bus       plugin.EventBus
-	plugins   plugin.PluginResolver
+	logger     *zap.Logger
+	cfg        PulseConfig
+	store      *PulseStore
+	bus        plugin.EventBus
+	plugins    plugin.PluginResolver
 	scheduler  *Scheduler
 	checkers   map[string]Checker
 	alerter    *Alerter
@@ -90,10 +93,13 @@ func (m *Module) Start(_ context.Context) error {
 		"icmp": NewICMPChecker(m.cfg.PingTimeout, m.cfg.PingCount),
 		"tcp":  NewTCPChecker(m.cfg.PingTimeout),
 		"http": NewHTTPChecker(m.cfg.PingTimeout),
+		"tls":  NewTLSChecker(m.cfg.PingTimeout),
+		"grpc": NewGRPCChecker(m.cfg.PingTimeout),
+		"udp":  NewUDPChecker(m.cfg.PingTimeout),
 	}
 
 	if m.store != nil {
-		m.alerter = NewAlerter(m.store, m.bus, m.cfg.ConsecutiveFailures, m.logger)
+		m.alerter = NewAlerter(m.store, m.bus, m.cfg.ConsecutiveFailures, m.cfg.ResolveThreshold, m.logger)
 		if m.cfg.CorrelationEnabled {
 			corr := NewCorrelationEngine(m.store, m.cfg.CorrelationWindow, m.logger)
 			m.alerter.SetCorrelation(corr)
@@ -101,6 +107,9 @@ func (m *Module) Start(_ context.Context) error {
 				zap.Duration("correlation_window", m.cfg.CorrelationWindow),
 			)
 		}
+		m.alerter.SetLowPriority(m.cfg.LowPriority.Tags, m.cfg.LowPriority.AutoAckSeverities)
+		m.alerter.SetEscalation(m.cfg.EscalateAfter)
+		m.alerter.SetFlapWindow(m.cfg.FlapWindow)
 		m.dispatcher = NewNotificationDispatcher(m.store, m.logger)
 
 		m.scheduler = NewScheduler(
@@ -111,6 +120,10 @@ func (m *Module) Start(_ context.Context) error {
 			m.logger,
 		)
 		m.scheduler.Start(m.ctx)
+		m.syncAlertMetrics(m.ctx)
+		if m.cfg.EscalateAfter > 0 {
+			m.startEscalation()
+		}
 	}
 
 	m.startMaintenance()
@@ -148,7 +161,22 @@ func (m *Module) executeCheck(ctx context.Context, check Check) {
 		return
 	}
 
-	result, err := checker.Check(ctx, check.Target)
+	// Checkers honor the context deadline, so a per-check timeout can be
+	// longer or shorter than the configured default. The deadline covers
+	// only the probe, not storing its result.
+	checkCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout := m.checkTimeout(&check); timeout > 0 {
+		checkCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+
+	var result *CheckResult
+	var err error
+	if ec, ok := checker.(expectationChecker); ok && (check.ExpectedStatus != 0 || check.ExpectedBodySubstring != "" || check.Payload != "") {
+		result, err = ec.CheckExpect(checkCtx, &check)
+	} else {
+		result, err = checker.Check(checkCtx, check.Target)
+	}
 	if err != nil {
 		m.logger.Debug("check returned error",
 			zap.String("check_id", check.ID),
@@ -169,6 +197,8 @@ func (m *Module) executeCheck(ctx context.Context, check Check) {
 			zap.String("check_id", check.ID),
 			zap.Error(err),
 		)
+	} else {
+		observeCheckResult(&check, result)
 	}
 
 	// Update device last_seen on successful checks.
@@ -184,6 +214,9 @@ func (m *Module) executeCheck(ctx context.Context, check Check) {
 	// Process alert state machine.
 	if m.alerter != nil {
 		m.alerter.ProcessResult(ctx, check, result)
+		if m.cfg.CertChangeAlerts && result.Success && checkType == "tls" {
+			m.alerter.ProcessCertificate(ctx, check, result)
+		}
 	}
 
 	// Publish metrics to the event bus for Insight consumption.
@@ -202,7 +235,7 @@ func (m *Module) publishMetrics(ctx context.Context, check Check, result *CheckR
 		successVal = 1.0
 	}
 
-	// Use check type as metric prefix (icmp, tcp, http).
+	// Use check type as metric prefix (icmp, tcp, http, tls, grpc, udp).
 	prefix := check.CheckType
 	if prefix == "" {
 		prefix = "ping" // backwards-compatible for legacy ICMP checks
@@ -251,6 +284,18 @@ func (m *Module) Health(_ context.Context) plugin.HealthStatus {
 	}
 }
 
+// Readiness reports whether pulse is running checks: its store must be
+// available and its check scheduler running.
+func (m *Module) Readiness() error {
+	if m.store == nil {
+		return errors.New("store unavailable")
+	}
+	if m.scheduler == nil || !m.scheduler.Running() {
+		return errors.New("check scheduler is not running")
+	}
+	return nil
+}
+
 // -- plugin.EventSubscriber --
 
 // Subscriptions implements plugin.EventSubscriber.
@@ -259,6 +304,8 @@ func (m *Module) Subscriptions() []plugin.Subscription {
 		{Topic: TopicDeviceDiscovered, Handler: m.handleDeviceDiscovered},
 		{Topic: TopicAlertTriggered, Handler: m.handleAlertNotification},
 		{Topic: TopicAlertResolved, Handler: m.handleAlertNotification},
+		{Topic: TopicAlertEscalated, Handler: m.handleAlertNotification},
+		{Topic: TopicCertChanged, Handler: m.handleAlertNotification},
 	}
 }
 
@@ -269,6 +316,15 @@ func (m *Module) handleAlertNotification(ctx context.Context, event plugin.Event
 	}
 }
 
+// checkTimeout returns how long a check may run: its own TimeoutMs when
+// set, otherwise the configured ping timeout.
+func (m *Module) checkTimeout(check *Check) time.Duration {
+	if check.TimeoutMs > 0 {
+		return time.Duration(check.TimeoutMs) * time.Millisecond
+	}
+	return m.cfg.PingTimeout
+}
+
 // -- roles.MonitoringProvider --
 
 // Status implements roles.MonitoringProvider.
@@ -290,29 +346,126 @@ func (m *Module) Status(ctx context.Context, deviceID string) (*roles.MonitorSta
 	}
 
 	if len(results) > 0 {
-		latest := results[0]
-		status.Healthy = latest.Success
-		status.CheckedAt = latest.CheckedAt
-		if latest.Success {
-			status.Message = fmt.Sprintf("ping OK (%.1fms, %.0f%% loss)", latest.LatencyMs, latest.PacketLoss*100)
-		} else {
-			status.Message = "ping failed"
-			if latest.ErrorMessage != "" {
-				status.Message = latest.ErrorMessage
-			}
-		}
+		applyLatestResult(status, &results[0])
 	}
 
-	// Check for active alerts.
+	// Check for active alerts. Auto-acknowledged low-priority alerts don't
+	// count against the device's health summary.
 	alerts, err := m.store.ListActiveAlerts(ctx, deviceID)
-	if err == nil && len(alerts) > 0 {
-		status.Healthy = false
-		status.Message = alerts[0].Message
+	if err == nil {
+		for i := range alerts {
+			if alerts[i].LowPriority {
+				continue
+			}
+			status.Healthy = false
+			status.Message = alerts[i].Message
+			break
+		}
 	}
 
 	return status, nil
 }
 
+// applyLatestResult sets a status's health, message, and check time from
+// the device's most recent result. A nil result leaves the status as is.
+func applyLatestResult(status *roles.MonitorStatus, latest *CheckResult) {
+	if latest == nil {
+		status.Message = "no check data available"
+		return
+	}
+	status.Healthy = latest.Success
+	status.CheckedAt = latest.CheckedAt
+	if latest.Success {
+		status.Message = fmt.Sprintf("ping OK (%.1fms, %.0f%% loss)", latest.LatencyMs, latest.PacketLoss*100)
+	} else {
+		status.Message = "ping failed"
+		if latest.ErrorMessage != "" {
+			status.Message = latest.ErrorMessage
+		}
+	}
+}
+
+// -- roles.MonitoringHistoryProvider --
+
+// Alerts implements roles.MonitoringHistoryProvider.
+func (m *Module) Alerts(ctx context.Context, deviceID string, activeOnly bool, limit int) ([]roles.MonitorAlert, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("pulse store not available")
+	}
+	alerts, err := m.store.ListAlerts(ctx, AlertFilters{
+		DeviceID:   deviceID,
+		ActiveOnly: activeOnly,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]roles.MonitorAlert, 0, len(alerts))
+	for i := range alerts {
+		a := &alerts[i]
+		out = append(out, roles.MonitorAlert{
+			ID:          a.ID,
+			DeviceID:    a.DeviceID,
+			DeviceName:  a.DeviceName,
+			Severity:    a.Severity,
+			Message:     a.Message,
+			TriggeredAt: a.TriggeredAt,
+			ResolvedAt:  a.ResolvedAt,
+		})
+	}
+	return out, nil
+}
+
+// Uptime implements roles.MonitoringHistoryProvider.
+func (m *Module) Uptime(ctx context.Context, deviceID, timeRange string) (*roles.UptimeStats, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("pulse store not available")
+	}
+	sum, err := m.store.QueryUptime(ctx, deviceID, timeRange, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &roles.UptimeStats{
+		DeviceID:        sum.DeviceID,
+		Range:           sum.Range,
+		UptimePercent:   sum.UptimePercent,
+		DowntimeSeconds: sum.DowntimeSeconds,
+		Outages:         sum.Outages,
+	}, nil
+}
+
+// DownDevices implements roles.MonitoringHistoryProvider. A device is down
+// while it has an active alert; auto-acknowledged low-priority alerts are
+// ignored, as in Status.
+func (m *Module) DownDevices(ctx context.Context) ([]roles.MonitorStatus, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("pulse store not available")
+	}
+	alerts, err := m.store.ListActiveAlerts(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Alerts are newest first, so each device reports its latest alert.
+	seen := make(map[string]bool)
+	down := make([]roles.MonitorStatus, 0)
+	for i := range alerts {
+		a := &alerts[i]
+		if a.LowPriority || seen[a.DeviceID] {
+			continue
+		}
+		seen[a.DeviceID] = true
+		down = append(down, roles.MonitorStatus{
+			DeviceID:  a.DeviceID,
+			Healthy:   false,
+			Message:   a.Message,
+			CheckedAt: a.TriggeredAt,
+		})
+	}
+	return down, nil
+}
+
 // Store returns the PulseStore for external use (e.g., seeding demo data).
 func (m *Module) Store() *PulseStore {
 	return m.store
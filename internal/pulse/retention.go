@@ -0,0 +1,60 @@
+package pulse
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// defaultPruneBatchSize is the batch size used when none is configured.
+const defaultPruneBatchSize = 1000
+
+// PruneResults deletes check results older than before in batches of at
+// most batchSize rows, so no single statement holds the write lock for
+// long. Results an unresolved alert still depends on are kept: for a check
+// with an open alert, everything from the last success before the alert
+// triggered onward survives, covering the failure streak that raised it.
+// Pruning stops early when ctx is cancelled; the remaining rows are picked
+// up by the next run. Returns the number of rows deleted.
+func (s *PulseStore) PruneResults(ctx context.Context, before time.Time, batchSize int) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = defaultPruneBatchSize
+	}
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		result, err := s.db.ExecContext(ctx, `
+			DELETE FROM pulse_check_results WHERE id IN (
+				SELECT r.id FROM pulse_check_results r
+				WHERE r.checked_at < ?
+				  AND NOT EXISTS (
+					SELECT 1 FROM pulse_alerts a
+					WHERE a.check_id = r.check_id
+					  AND a.resolved_at IS NULL
+					  AND r.checked_at >= COALESCE((
+						SELECT MAX(s.checked_at) FROM pulse_check_results s
+						WHERE s.check_id = a.check_id
+						  AND s.success = 1
+						  AND s.checked_at < a.triggered_at
+					  ), r.checked_at)
+				  )
+				ORDER BY r.id
+				LIMIT ?
+			)`,
+			before, batchSize,
+		)
+		if err != nil {
+			return total, fmt.Errorf("prune results: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return total, fmt.Errorf("prune results: %w", err)
+		}
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package pulse
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPruneResults(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	day := 24 * time.Hour
+
+	for _, id := range []string{"chk-ok", "chk-down"} {
+		insertTestCheck(t, s, &Check{
+			ID: id, DeviceID: "dev-" + id, CheckType: "icmp", Target: "192.168.1.1",
+			IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+
+	insert := func(checkID string, success bool, age time.Duration) {
+		t.Helper()
+		r := &CheckResult{CheckID: checkID, DeviceID: "dev-" + checkID, Success: success, CheckedAt: now.Add(-age)}
+		if err := s.InsertResult(ctx, r); err != nil {
+			t.Fatalf("InsertResult: %v", err)
+		}
+	}
+
+	// A healthy check: five old results and one recent.
+	for i := 0; i < 5; i++ {
+		insert("chk-ok", true, 100*day+time.Duration(i)*time.Hour)
+	}
+	insert("chk-ok", true, time.Hour)
+
+	// A check that went down 100 days ago and never recovered. The success
+	// before the failure streak and the streak itself must survive.
+	insert("chk-down", true, 120*day) // prunable
+	insert("chk-down", true, 101*day) // last success before the alert
+	insert("chk-down", false, 100*day+2*time.Hour)
+	insert("chk-down", false, 100*day+time.Hour)
+	insert("chk-down", false, 100*day)
+	insert("chk-down", false, time.Hour)
+	if err := s.InsertAlert(ctx, &Alert{
+		ID: "alert-down", CheckID: "chk-down", DeviceID: "dev-chk-down", Severity: "critical",
+		Message: "down", TriggeredAt: now.Add(-100 * day), ConsecutiveFailures: 3,
+	}); err != nil {
+		t.Fatalf("InsertAlert: %v", err)
+	}
+
+	// A batch size of 2 forces several passes.
+	deleted, err := s.PruneResults(ctx, now.Add(-90*day), 2)
+	if err != nil {
+		t.Fatalf("PruneResults: %v", err)
+	}
+	if deleted != 6 {
+		t.Errorf("deleted = %d, want 6", deleted)
+	}
+
+	ok, err := s.ListResults(ctx, "dev-chk-ok", 100)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(ok) != 1 || !ok[0].CheckedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("healthy check kept %d results, want only the recent one", len(ok))
+	}
+
+	down, err := s.ListResults(ctx, "dev-chk-down", 100)
+	if err != nil {
+		t.Fatalf("ListResults: %v", err)
+	}
+	if len(down) != 5 {
+		t.Fatalf("alerting check kept %d results, want 5", len(down))
+	}
+	for _, r := range down {
+		if r.CheckedAt.Before(now.Add(-101 * day)) {
+			t.Errorf("result at %v should have been pruned", r.CheckedAt)
+		}
+	}
+
+	// Once the alert resolves its history is no longer protected.
+	if err := s.ResolveAlert(ctx, "alert-down", now); err != nil {
+		t.Fatalf("ResolveAlert: %v", err)
+	}
+	deleted, err = s.PruneResults(ctx, now.Add(-90*day), 2)
+	if err != nil {
+		t.Fatalf("PruneResults after resolve: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("deleted after resolve = %d, want 4", deleted)
+	}
+}
@@ -0,0 +1,376 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rollup granularities stored in pulse_metric_rollups.
+const (
+	RollupHourly = "hour"
+	RollupDaily  = "day"
+)
+
+// metricSourceRaw names raw results as the source of a metrics query.
+const metricSourceRaw = "raw"
+
+// rollupChunk bounds how much raw history one rollup transaction reads,
+// so catching up after a long gap neither holds the write lock nor the
+// rows in memory all at once.
+const rollupChunk = 24 * time.Hour
+
+// MetricRollup summarizes one check's results over an hour or a day.
+// Sums are kept rather than averages so rollups merge exactly: the average
+// latency of any set of rollups is the sum of LatencySum over the sum of
+// Samples. Min and max cover the same samples as the sums.
+type MetricRollup struct {
+	CheckID       string    `json:"check_id"`
+	DeviceID      string    `json:"device_id"`
+	Granularity   string    `json:"granularity"`
+	BucketStart   time.Time `json:"bucket_start"`
+	Samples       int       `json:"samples"`
+	Successes     int       `json:"successes"`
+	LatencySum    float64   `json:"latency_sum"`
+	LatencyMin    float64   `json:"latency_min"`
+	LatencyMax    float64   `json:"latency_max"`
+	PacketLossSum float64   `json:"packet_loss_sum"`
+	PacketLossMin float64   `json:"packet_loss_min"`
+	PacketLossMax float64   `json:"packet_loss_max"`
+}
+
+// add folds one sample into the rollup.
+func (r *MetricRollup) add(latency, packetLoss float64, success bool) {
+	if r.Samples == 0 {
+		r.LatencyMin, r.LatencyMax = latency, latency
+		r.PacketLossMin, r.PacketLossMax = packetLoss, packetLoss
+	}
+	r.Samples++
+	if success {
+		r.Successes++
+	}
+	r.LatencySum += latency
+	r.PacketLossSum += packetLoss
+	r.LatencyMin = min(r.LatencyMin, latency)
+	r.LatencyMax = max(r.LatencyMax, latency)
+	r.PacketLossMin = min(r.PacketLossMin, packetLoss)
+	r.PacketLossMax = max(r.PacketLossMax, packetLoss)
+}
+
+// merge folds another rollup of the same check into r.
+func (r *MetricRollup) merge(o *MetricRollup) {
+	if o.Samples == 0 {
+		return
+	}
+	if r.Samples == 0 {
+		r.LatencyMin, r.LatencyMax = o.LatencyMin, o.LatencyMax
+		r.PacketLossMin, r.PacketLossMax = o.PacketLossMin, o.PacketLossMax
+	}
+	r.Samples += o.Samples
+	r.Successes += o.Successes
+	r.LatencySum += o.LatencySum
+	r.PacketLossSum += o.PacketLossSum
+	r.LatencyMin = min(r.LatencyMin, o.LatencyMin)
+	r.LatencyMax = max(r.LatencyMax, o.LatencyMax)
+	r.PacketLossMin = min(r.PacketLossMin, o.PacketLossMin)
+	r.PacketLossMax = max(r.PacketLossMax, o.PacketLossMax)
+}
+
+// rollupKey identifies a rollup row.
+type rollupKey struct {
+	checkID string
+	start   int64
+}
+
+// metricSource picks where QueryMetrics reads a range bucketed by
+// bucketSec from: the coarsest rollup that still fits in one bucket, or
+// raw results when buckets are finer than an hour.
+func metricSource(bucketSec int64) string {
+	switch {
+	case bucketSec >= int64(24*time.Hour/time.Second):
+		return RollupDaily
+	case bucketSec >= int64(time.Hour/time.Second):
+		return RollupHourly
+	default:
+		return metricSourceRaw
+	}
+}
+
+// RollupWatermark returns the time up to which rollups of the given
+// granularity are complete. ok is false when no rollup has run yet.
+func (s *PulseStore) RollupWatermark(ctx context.Context, granularity string) (time.Time, bool, error) {
+	var through sql.NullTime
+	err := s.db.QueryRowContext(ctx,
+		`SELECT rolled_through FROM pulse_rollup_state WHERE granularity = ?`, granularity,
+	).Scan(&through)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("get rollup watermark: %w", err)
+	}
+	return through.Time.UTC(), through.Valid, nil
+}
+
+// RollupMetrics brings the hourly rollups up to the last complete hour
+// before now, then the daily rollups up to the last complete day. Hourly
+// rollups are built from raw results and daily rollups from hourly ones,
+// so daily history survives after raw results are pruned. It is safe to
+// call repeatedly; each run resumes where the previous one stopped.
+func (s *PulseStore) RollupMetrics(ctx context.Context, now time.Time) error {
+	now = now.UTC()
+	if err := s.rollupHourly(ctx, now.Truncate(time.Hour)); err != nil {
+		return err
+	}
+	through, ok, err := s.RollupWatermark(ctx, RollupHourly)
+	if err != nil || !ok {
+		return err
+	}
+	return s.rollupDaily(ctx, through.Truncate(24*time.Hour))
+}
+
+// rollupHourly aggregates raw results in [watermark, end) into hourly rollups.
+func (s *PulseStore) rollupHourly(ctx context.Context, end time.Time) error {
+	from, ok, err := s.RollupWatermark(ctx, RollupHourly)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		var first time.Time
+		err := s.db.QueryRowContext(ctx,
+			`SELECT checked_at FROM pulse_check_results ORDER BY checked_at ASC LIMIT 1`,
+		).Scan(&first)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			first = end
+		case err != nil:
+			return fmt.Errorf("find first result: %w", err)
+		}
+		from = first.UTC().Truncate(time.Hour)
+	}
+
+	for from.Before(end) || !ok {
+		to := earlier(from.Add(rollupChunk), end).Truncate(time.Hour)
+		if err := s.rollupChunk(ctx, RollupHourly, from, to, `
+			SELECT check_id, device_id, COALESCE(latency_ms, 0), COALESCE(packet_loss, 0), success, checked_at
+			FROM pulse_check_results
+			WHERE checked_at >= ? AND checked_at < ?`,
+			time.Hour, scanRawSample,
+		); err != nil {
+			return err
+		}
+		from, ok = to, true
+	}
+	return nil
+}
+
+// rollupDaily aggregates hourly rollups in [watermark, end) into daily rollups.
+func (s *PulseStore) rollupDaily(ctx context.Context, end time.Time) error {
+	from, ok, err := s.RollupWatermark(ctx, RollupDaily)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		var first time.Time
+		err := s.db.QueryRowContext(ctx,
+			`SELECT bucket_start FROM pulse_metric_rollups WHERE granularity = ? ORDER BY bucket_start ASC LIMIT 1`,
+			RollupHourly,
+		).Scan(&first)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			first = end
+		case err != nil:
+			return fmt.Errorf("find first hourly rollup: %w", err)
+		}
+		from = first.UTC().Truncate(24 * time.Hour)
+	}
+
+	for from.Before(end) || !ok {
+		to := earlier(from.Add(rollupChunk*7), end)
+		if err := s.rollupChunk(ctx, RollupDaily, from, to, `
+			SELECT check_id, device_id, bucket_start, sample_count, success_count,
+				latency_sum, latency_min, latency_max,
+				packet_loss_sum, packet_loss_min, packet_loss_max
+			FROM pulse_metric_rollups
+			WHERE granularity = 'hour' AND bucket_start >= ? AND bucket_start < ?`,
+			24*time.Hour, scanHourlyRollup,
+		); err != nil {
+			return err
+		}
+		from, ok = to, true
+	}
+	return nil
+}
+
+// earlier returns the earlier of a and b.
+func earlier(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+// scanRawSample reads a raw result row as a single-sample rollup.
+func scanRawSample(rows *sql.Rows) (MetricRollup, time.Time, error) {
+	var r MetricRollup
+	var latency, packetLoss float64
+	var success int
+	var checkedAt time.Time
+	if err := rows.Scan(&r.CheckID, &r.DeviceID, &latency, &packetLoss, &success, &checkedAt); err != nil {
+		return r, checkedAt, err
+	}
+	r.add(latency, packetLoss, success == 1)
+	return r, checkedAt, nil
+}
+
+// scanHourlyRollup reads an hourly rollup row.
+func scanHourlyRollup(rows *sql.Rows) (MetricRollup, time.Time, error) {
+	var r MetricRollup
+	err := rows.Scan(&r.CheckID, &r.DeviceID, &r.BucketStart, &r.Samples, &r.Successes,
+		&r.LatencySum, &r.LatencyMin, &r.LatencyMax,
+		&r.PacketLossSum, &r.PacketLossMin, &r.PacketLossMax)
+	return r, r.BucketStart, err
+}
+
+// rollupChunk reads the rows query returns for [from, to), aggregates them
+// into buckets of the given width, and stores the rollups together with
+// the new watermark in one transaction.
+func (s *PulseStore) rollupChunk(
+	ctx context.Context, granularity string, from, to time.Time,
+	query string, bucket time.Duration,
+	scan func(*sql.Rows) (MetricRollup, time.Time, error),
+) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin %s rollup: %w", granularity, err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	rows, err := tx.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return fmt.Errorf("read %s rollup input: %w", granularity, err)
+	}
+	rollups := make(map[rollupKey]*MetricRollup)
+	var keys []rollupKey
+	for rows.Next() {
+		sample, at, err := scan(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("scan %s rollup input: %w", granularity, err)
+		}
+		start := at.UTC().Truncate(bucket)
+		key := rollupKey{checkID: sample.CheckID, start: start.Unix()}
+		r, exists := rollups[key]
+		if !exists {
+			r = &MetricRollup{CheckID: sample.CheckID, DeviceID: sample.DeviceID, Granularity: granularity, BucketStart: start}
+			rollups[key] = r
+			keys = append(keys, key)
+		}
+		r.merge(&sample)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return fmt.Errorf("iterate %s rollup input: %w", granularity, err)
+	}
+	rows.Close()
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT OR REPLACE INTO pulse_metric_rollups (
+			check_id, device_id, granularity, bucket_start, sample_count, success_count,
+			latency_sum, latency_min, latency_max,
+			packet_loss_sum, packet_loss_min, packet_loss_max
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("prepare %s rollup insert: %w", granularity, err)
+	}
+	defer stmt.Close()
+	for _, key := range keys {
+		r := rollups[key]
+		if _, err := stmt.ExecContext(ctx,
+			r.CheckID, r.DeviceID, r.Granularity, r.BucketStart, r.Samples, r.Successes,
+			r.LatencySum, r.LatencyMin, r.LatencyMax,
+			r.PacketLossSum, r.PacketLossMin, r.PacketLossMax,
+		); err != nil {
+			return fmt.Errorf("insert %s rollup: %w", granularity, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO pulse_rollup_state (granularity, rolled_through) VALUES (?, ?)
+		ON CONFLICT(granularity) DO UPDATE SET rolled_through = excluded.rolled_through`,
+		granularity, to,
+	); err != nil {
+		return fmt.Errorf("update %s rollup watermark: %w", granularity, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit %s rollup: %w", granularity, err)
+	}
+	return nil
+}
+
+// rewindRollups moves the rollup watermarks back so results recorded at or
+// after since are rolled up again on the next run. Used when history is
+// inserted behind the watermark, as backfill does.
+func rewindRollups(ctx context.Context, tx *sql.Tx, since time.Time) error {
+	since = since.UTC()
+	for granularity, width := range map[string]time.Duration{RollupHourly: time.Hour, RollupDaily: 24 * time.Hour} {
+		start := since.Truncate(width)
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE pulse_rollup_state SET rolled_through = ?
+			WHERE granularity = ? AND rolled_through > ?`,
+			start, granularity, start,
+		); err != nil {
+			return fmt.Errorf("rewind %s rollups: %w", granularity, err)
+		}
+	}
+	return nil
+}
+
+// DeleteOldRollups deletes rollups of the given granularity that start
+// before the given time. Returns the number of rows deleted.
+func (s *PulseStore) DeleteOldRollups(ctx context.Context, granularity string, before time.Time) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM pulse_metric_rollups WHERE granularity = ? AND bucket_start < ?`,
+		granularity, before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete old rollups: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// addRollupMetrics folds a device's rollups of the given granularity that
+// start in [since, through) into buckets.
+func (s *PulseStore) addRollupMetrics(ctx context.Context, buckets *metricBuckets, deviceID, granularity string, since, through time.Time) error {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT bucket_start, sample_count, success_count, latency_sum, packet_loss_sum
+		FROM pulse_metric_rollups
+		WHERE device_id = ? AND granularity = ? AND bucket_start >= ? AND bucket_start < ?
+		ORDER BY bucket_start ASC`,
+		deviceID, granularity, since, through,
+	)
+	if err != nil {
+		return fmt.Errorf("query metric rollups: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start time.Time
+		var samples, successes int
+		var latencySum, packetLossSum float64
+		if err := rows.Scan(&start, &samples, &successes, &latencySum, &packetLossSum); err != nil {
+			return fmt.Errorf("scan metric rollup: %w", err)
+		}
+		b := buckets.get(start)
+		b.latencySum += latencySum
+		b.packetLossSum += packetLossSum
+		b.successCount += successes
+		b.total += samples
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate metric rollups: %w", err)
+	}
+	return nil
+}
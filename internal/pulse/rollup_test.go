@@ -0,0 +1,155 @@
+package pulse
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMetricSource(t *testing.T) {
+	tests := []struct {
+		timeRange string
+		want      string
+	}{
+		{"1h", metricSourceRaw},
+		{"24h", metricSourceRaw},
+		{"7d", metricSourceRaw},
+		{"30d", RollupHourly},
+	}
+	bucketFor := map[string]int64{"1h": 60, "24h": 60, "7d": 300, "30d": 3600}
+	for _, tt := range tests {
+		if got := metricSource(bucketFor[tt.timeRange]); got != tt.want {
+			t.Errorf("metricSource(%s) = %q, want %q", tt.timeRange, got, tt.want)
+		}
+	}
+	if got := metricSource(86400); got != RollupDaily {
+		t.Errorf("metricSource(1 day) = %q, want %q", got, RollupDaily)
+	}
+}
+
+func TestQueryMetrics_RollupMatchesRaw(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	seedMetricsData(t, s, "dev-roll", 432, now.Add(-3*24*time.Hour), 10*time.Minute)
+
+	metrics := []string{"latency", "packet_loss", "success_rate"}
+	raw := make(map[string]*MetricSeries)
+	for _, metric := range metrics {
+		series, err := s.QueryMetrics(ctx, "dev-roll", metric, "30d")
+		if err != nil {
+			t.Fatalf("QueryMetrics(%s) before rollup: %v", metric, err)
+		}
+		raw[metric] = series
+	}
+
+	if err := s.RollupMetrics(ctx, now); err != nil {
+		t.Fatalf("RollupMetrics: %v", err)
+	}
+	// A second run must not count any sample twice.
+	if err := s.RollupMetrics(ctx, now); err != nil {
+		t.Fatalf("RollupMetrics (again): %v", err)
+	}
+	through, ok, err := s.RollupWatermark(ctx, RollupHourly)
+	if err != nil || !ok {
+		t.Fatalf("RollupWatermark = %v, %v, %v", through, ok, err)
+	}
+	if want := now.Truncate(time.Hour); !through.Equal(want) {
+		t.Errorf("hourly watermark = %v, want %v", through, want)
+	}
+
+	// Drop the raw results the rollups cover: the 30d range must be served
+	// from rollups unchanged, while the raw-backed 24h range loses them.
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM pulse_check_results WHERE checked_at < ?`, through); err != nil {
+		t.Fatalf("delete rolled-up results: %v", err)
+	}
+
+	for _, metric := range metrics {
+		series, err := s.QueryMetrics(ctx, "dev-roll", metric, "30d")
+		if err != nil {
+			t.Fatalf("QueryMetrics(%s) after rollup: %v", metric, err)
+		}
+		want := raw[metric].Points
+		if len(series.Points) != len(want) {
+			t.Fatalf("%s: %d points from rollups, want %d", metric, len(series.Points), len(want))
+		}
+		for i, p := range series.Points {
+			if !p.Timestamp.Equal(want[i].Timestamp) {
+				t.Fatalf("%s: points[%d] at %v, want %v", metric, i, p.Timestamp, want[i].Timestamp)
+			}
+			if math.Abs(p.Value-want[i].Value) > 1e-9 {
+				t.Errorf("%s: points[%d] = %f, want %f", metric, i, p.Value, want[i].Value)
+			}
+		}
+	}
+
+	day, err := s.QueryMetrics(ctx, "dev-roll", "latency", "24h")
+	if err != nil {
+		t.Fatalf("QueryMetrics(24h): %v", err)
+	}
+	for _, p := range day.Points {
+		if p.Timestamp.Before(through) {
+			t.Fatalf("24h range returned a point at %v, before the pruned watermark %v", p.Timestamp, through)
+		}
+	}
+}
+
+func TestRollupMetrics_Daily(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(24 * time.Hour).Add(12 * time.Hour)
+	day := now.Truncate(24 * time.Hour).Add(-24 * time.Hour)
+
+	insertTestCheck(t, s, &Check{
+		ID: "chk-day", DeviceID: "dev-day", CheckType: "icmp", Target: "192.168.1.1",
+		IntervalSeconds: 30, Enabled: true, CreatedAt: day, UpdatedAt: day,
+	})
+	for i, latency := range []float64{20, 5, 40, 15} {
+		if err := s.InsertResult(ctx, &CheckResult{
+			CheckID: "chk-day", DeviceID: "dev-day", Success: i != 2, LatencyMs: latency,
+			CheckedAt: day.Add(time.Duration(i*5) * time.Hour),
+		}); err != nil {
+			t.Fatalf("InsertResult: %v", err)
+		}
+	}
+
+	if err := s.RollupMetrics(ctx, now); err != nil {
+		t.Fatalf("RollupMetrics: %v", err)
+	}
+
+	var r MetricRollup
+	err := s.db.QueryRowContext(ctx, `
+		SELECT sample_count, success_count, latency_sum, latency_min, latency_max
+		FROM pulse_metric_rollups WHERE check_id = ? AND granularity = ? AND bucket_start = ?`,
+		"chk-day", RollupDaily, day,
+	).Scan(&r.Samples, &r.Successes, &r.LatencySum, &r.LatencyMin, &r.LatencyMax)
+	if err != nil {
+		t.Fatalf("read daily rollup: %v", err)
+	}
+	if r.Samples != 4 || r.Successes != 3 || r.LatencySum != 80 || r.LatencyMin != 5 || r.LatencyMax != 40 {
+		t.Errorf("daily rollup = %+v, want 4 samples, 3 successes, sum 80, min 5, max 40", r)
+	}
+
+	// Backfilling into a rolled-up day rewinds the watermarks so the next
+	// run includes the new result.
+	if err := s.InsertResults(ctx, []CheckResult{{
+		CheckID: "chk-day", DeviceID: "dev-day", Success: true, LatencyMs: 1, CheckedAt: day.Add(time.Hour),
+	}}); err != nil {
+		t.Fatalf("InsertResults: %v", err)
+	}
+	if err := s.RollupMetrics(ctx, now); err != nil {
+		t.Fatalf("RollupMetrics after backfill: %v", err)
+	}
+	err = s.db.QueryRowContext(ctx, `
+		SELECT sample_count, latency_min FROM pulse_metric_rollups
+		WHERE check_id = ? AND granularity = ? AND bucket_start = ?`,
+		"chk-day", RollupDaily, day,
+	).Scan(&r.Samples, &r.LatencyMin)
+	if err != nil {
+		t.Fatalf("read daily rollup after backfill: %v", err)
+	}
+	if r.Samples != 5 || r.LatencyMin != 1 {
+		t.Errorf("daily rollup after backfill: samples = %d, min = %f, want 5 and 1", r.Samples, r.LatencyMin)
+	}
+}
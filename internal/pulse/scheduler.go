@@ -2,6 +2,7 @@ package pulse
 
 import (
 	"context"
+	"hash/fnv"
 	"sync"
 	"time"
 
@@ -11,7 +12,20 @@ import (
 // CheckExecutor is called by the scheduler for each enabled check.
 type CheckExecutor func(ctx context.Context, check Check)
 
-// Scheduler runs monitoring checks on a periodic interval using a worker pool.
+// maxSchedulerResolution bounds how often the scheduler wakes up to dispatch
+// due checks. Jittered first runs are only as precise as this resolution.
+const maxSchedulerResolution = time.Second
+
+// scheduledCheck tracks when a check should next run.
+type scheduledCheck struct {
+	check    Check
+	interval time.Duration
+	next     time.Time
+}
+
+// Scheduler runs monitoring checks on their configured intervals using a
+// worker pool. Each check's first run is offset by a deterministic jitter so
+// checks sharing an interval do not all fire on the same boundary.
 type Scheduler struct {
 	store    *PulseStore
 	executor CheckExecutor
@@ -19,12 +33,19 @@ type Scheduler struct {
 	workers  int
 	logger   *zap.Logger
 
+	// jitter computes the first-run offset for a check. Overridable in tests.
+	jitter   func(checkID string, interval time.Duration) time.Duration
+	schedule map[string]*scheduledCheck
+	sem      chan struct{}
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     sync.WaitGroup
 }
 
 // NewScheduler creates a scheduler that dispatches checks to the executor.
+// The interval controls how often the check list is reloaded from the store
+// and is used for checks that do not set their own interval.
 func NewScheduler(store *PulseStore, executor CheckExecutor, interval time.Duration, workers int, logger *zap.Logger) *Scheduler {
 	return &Scheduler{
 		store:    store,
@@ -32,28 +53,38 @@ func NewScheduler(store *PulseStore, executor CheckExecutor, interval time.Durat
 		interval: interval,
 		workers:  workers,
 		logger:   logger,
+		jitter:   checkJitter,
+		schedule: make(map[string]*scheduledCheck),
 	}
 }
 
 // Start begins the scheduling loop. Blocks until Stop is called.
 func (s *Scheduler) Start(ctx context.Context) {
 	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.sem = make(chan struct{}, s.workers)
 
 	s.wg.Add(1)
 	go func() {
 		defer s.wg.Done()
-		ticker := time.NewTicker(s.interval)
+		ticker := time.NewTicker(s.resolution())
 		defer ticker.Stop()
 
-		// Run immediately on start, then on each tick.
-		s.tick()
+		// Load checks immediately on start, then reload every interval and
+		// dispatch due checks on each tick.
+		lastRefresh := time.Now()
+		s.refresh(s.ctx, lastRefresh)
+		s.dispatchDue(lastRefresh)
 
 		for {
 			select {
 			case <-s.ctx.Done():
 				return
-			case <-ticker.C:
-				s.tick()
+			case now := <-ticker.C:
+				if now.Sub(lastRefresh) >= s.interval {
+					s.refresh(s.ctx, now)
+					lastRefresh = now
+				}
+				s.dispatchDue(now)
 			}
 		}
 	}()
@@ -72,13 +103,32 @@ func (s *Scheduler) Running() bool {
 	return s.ctx != nil && s.ctx.Err() == nil
 }
 
-// tick loads all enabled checks and dispatches them to the worker pool.
-func (s *Scheduler) tick() {
+// resolution returns the ticker period used to dispatch due checks.
+func (s *Scheduler) resolution() time.Duration {
+	if s.interval < maxSchedulerResolution {
+		return s.interval
+	}
+	return maxSchedulerResolution
+}
+
+// checkInterval returns the run interval for a check, falling back to the
+// scheduler interval when the check does not set one.
+func (s *Scheduler) checkInterval(c *Check) time.Duration {
+	if c.IntervalSeconds > 0 {
+		return time.Duration(c.IntervalSeconds) * time.Second
+	}
+	return s.interval
+}
+
+// refresh reloads enabled checks from the store and updates the schedule.
+// New checks, and checks whose interval changed, get a jittered first run;
+// existing checks keep their phase. Checks no longer enabled are dropped.
+func (s *Scheduler) refresh(ctx context.Context, now time.Time) {
 	if s.store == nil {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(s.ctx, s.interval)
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
 	defer cancel()
 
 	checks, err := s.store.ListEnabledChecks(ctx)
@@ -87,56 +137,85 @@ func (s *Scheduler) tick() {
 		return
 	}
 
-	if len(checks) == 0 {
-		return
+	seen := make(map[string]struct{}, len(checks))
+	for i := range checks {
+		c := checks[i]
+		seen[c.ID] = struct{}{}
+		interval := s.checkInterval(&c)
+
+		entry, ok := s.schedule[c.ID]
+		if !ok || entry.interval != interval {
+			entry = &scheduledCheck{
+				interval: interval,
+				next:     now.Add(s.jitter(c.ID, interval)),
+			}
+			s.schedule[c.ID] = entry
+		}
+		entry.check = c
 	}
 
-	// Filter out checks for devices in active maintenance windows.
-	activeChecks := make([]Check, 0, len(checks))
-	for i := range checks {
-		inMaint, err := s.store.IsDeviceInMaintenanceWindow(ctx, checks[i].DeviceID)
+	for id := range s.schedule {
+		if _, ok := seen[id]; !ok {
+			delete(s.schedule, id)
+		}
+	}
+}
+
+// dispatchDue hands every check whose next run has arrived to the worker
+// pool and advances its next run by whole intervals.
+func (s *Scheduler) dispatchDue(now time.Time) {
+	for _, entry := range s.schedule {
+		if now.Before(entry.next) {
+			continue
+		}
+		// Skip missed runs rather than bursting to catch up.
+		missed := now.Sub(entry.next) / entry.interval
+		entry.next = entry.next.Add((missed + 1) * entry.interval)
+
+		// Filter out checks for devices in active maintenance windows.
+		inMaint, err := s.store.IsDeviceInMaintenanceWindow(s.ctx, entry.check.DeviceID)
 		if err != nil {
 			s.logger.Warn("scheduler: failed to check maintenance window",
-				zap.String("device_id", checks[i].DeviceID),
+				zap.String("device_id", entry.check.DeviceID),
 				zap.Error(err),
 			)
-			activeChecks = append(activeChecks, checks[i])
-			continue
-		}
-		if inMaint {
+		} else if inMaint {
 			s.logger.Debug("scheduler: skipping check (maintenance window)",
-				zap.String("check_id", checks[i].ID),
-				zap.String("device_id", checks[i].DeviceID),
+				zap.String("check_id", entry.check.ID),
+				zap.String("device_id", entry.check.DeviceID),
 			)
 			continue
 		}
-		activeChecks = append(activeChecks, checks[i])
+
+		s.wg.Add(1)
+		go s.run(entry.check, entry.interval)
 	}
-	checks = activeChecks
+}
+
+// run executes a single check once a worker slot is free. The check is
+// bounded by its own interval so a slow target cannot overlap its next run.
+func (s *Scheduler) run(c Check, timeout time.Duration) {
+	defer s.wg.Done()
 
-	if len(checks) == 0 {
+	select {
+	case <-s.ctx.Done():
 		return
+	case s.sem <- struct{}{}:
 	}
+	defer func() { <-s.sem }()
 
-	// Semaphore-based worker pool.
-	sem := make(chan struct{}, s.workers)
-	var wg sync.WaitGroup
-
-dispatch:
-	for i := range checks {
-		select {
-		case <-s.ctx.Done():
-			break dispatch
-		case sem <- struct{}{}:
-		}
+	ctx, cancel := context.WithTimeout(s.ctx, timeout)
+	defer cancel()
+	s.executor(ctx, c)
+}
 
-		wg.Add(1)
-		go func(c Check) {
-			defer wg.Done()
-			defer func() { <-sem }()
-			s.executor(ctx, c)
-		}(checks[i])
+// checkJitter returns a deterministic offset in [0, interval) derived from
+// the check ID, so checks sharing an interval are spread across it.
+func checkJitter(checkID string, interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return 0
 	}
-
-	wg.Wait()
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(checkID))
+	return time.Duration(h.Sum64() % uint64(interval)) //nolint:gosec // G115: remainder is below interval, which fits in int64
 }
@@ -28,6 +28,9 @@ func newTestStore(t *testing.T) *PulseStore {
 	return NewPulseStore(db.DB())
 }
 
+// noJitter disables first-run jitter so checks fire on the first tick.
+func noJitter(string, time.Duration) time.Duration { return 0 }
+
 func TestScheduler_StartStop(t *testing.T) {
 	ps := newTestStore(t)
 	executor := func(_ context.Context, _ Check) {}
@@ -81,6 +84,7 @@ func TestScheduler_ExecutesChecks(t *testing.T) {
 	}
 
 	s := NewScheduler(ps, executor, 50*time.Millisecond, 4, zap.NewNop())
+	s.jitter = noJitter
 	s.Start(context.Background())
 	// Wait for the initial tick plus one interval tick to be safe.
 	time.Sleep(100 * time.Millisecond)
@@ -136,6 +140,7 @@ func TestScheduler_WorkerConcurrencyLimit(t *testing.T) {
 	// on Start, so we only need to wait long enough for the first tick.
 	maxWorkers := 2
 	s := NewScheduler(ps, executor, 5*time.Second, maxWorkers, zap.NewNop())
+	s.jitter = noJitter
 	s.Start(context.Background())
 	// 5 checks / 2 workers * 50ms sleep = ~150ms; wait 500ms for margin.
 	time.Sleep(500 * time.Millisecond)
@@ -149,3 +154,128 @@ func TestScheduler_WorkerConcurrencyLimit(t *testing.T) {
 		t.Error("peak concurrency = 0, executor was never called")
 	}
 }
+
+func TestCheckJitter(t *testing.T) {
+	interval := 30 * time.Second
+
+	if got, again := checkJitter("chk-1", interval), checkJitter("chk-1", interval); got != again {
+		t.Errorf("checkJitter not deterministic: %v != %v", got, again)
+	}
+	if got := checkJitter("chk-1", 0); got != 0 {
+		t.Errorf("checkJitter(interval=0) = %v, want 0", got)
+	}
+	for i := range 100 {
+		id := fmt.Sprintf("chk-%d", i)
+		if got := checkJitter(id, interval); got < 0 || got >= interval {
+			t.Fatalf("checkJitter(%s) = %v, want within [0, %v)", id, got, interval)
+		}
+	}
+}
+
+func TestScheduler_RefreshSpreadsFirstRuns(t *testing.T) {
+	ps := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	const numChecks = 200
+	const buckets = 10
+	interval := 60 * time.Second
+
+	for i := range numChecks {
+		c := Check{
+			ID:              fmt.Sprintf("pulse-dev-%d-icmp", i),
+			DeviceID:        fmt.Sprintf("dev-%d", i),
+			CheckType:       "icmp",
+			Target:          fmt.Sprintf("10.0.%d.%d", i/250, i%250+1),
+			IntervalSeconds: int(interval.Seconds()),
+			Enabled:         true,
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		if err := ps.InsertCheck(ctx, &c); err != nil {
+			t.Fatalf("InsertCheck(%s): %v", c.ID, err)
+		}
+	}
+
+	s := NewScheduler(ps, func(context.Context, Check) {}, 30*time.Second, 4, zap.NewNop())
+	s.refresh(ctx, now)
+
+	if len(s.schedule) != numChecks {
+		t.Fatalf("scheduled %d checks, want %d", len(s.schedule), numChecks)
+	}
+
+	var counts [buckets]int
+	firstRuns := make(map[string]time.Time, numChecks)
+	for id, entry := range s.schedule {
+		if entry.interval != interval {
+			t.Fatalf("check %s interval = %v, want %v", id, entry.interval, interval)
+		}
+		offset := entry.next.Sub(now)
+		if offset < 0 || offset >= interval {
+			t.Fatalf("check %s first run offset = %v, want within [0, %v)", id, offset, interval)
+		}
+		counts[int(offset*buckets/interval)]++
+		firstRuns[id] = entry.next
+	}
+
+	// 200 checks over 10 buckets averages 20 per bucket. A clustered
+	// schedule would leave buckets empty or pile most checks into one.
+	for i, n := range counts {
+		if n == 0 || n > numChecks/buckets*3 {
+			t.Errorf("bucket %d has %d checks, want between 1 and %d (counts %v)", i, n, numChecks/buckets*3, counts)
+		}
+	}
+
+	// A later refresh keeps the existing phase of each check.
+	s.refresh(ctx, now.Add(45*time.Second))
+	for id, entry := range s.schedule {
+		if !entry.next.Equal(firstRuns[id]) {
+			t.Errorf("check %s next run moved on refresh: %v -> %v", id, firstRuns[id], entry.next)
+		}
+	}
+}
+
+func TestScheduler_DispatchDueMaintainsInterval(t *testing.T) {
+	ps := newTestStore(t)
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	c := Check{ID: "chk-1", DeviceID: "dev-1", CheckType: "icmp", Target: "10.0.0.1", IntervalSeconds: 30, Enabled: true, CreatedAt: now, UpdatedAt: now}
+	if err := ps.InsertCheck(ctx, &c); err != nil {
+		t.Fatalf("InsertCheck: %v", err)
+	}
+
+	var counter atomic.Int64
+	s := NewScheduler(ps, func(context.Context, Check) { counter.Add(1) }, 30*time.Second, 1, zap.NewNop())
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.sem = make(chan struct{}, s.workers)
+	defer s.Stop()
+
+	s.refresh(ctx, now)
+	first := s.schedule["chk-1"].next
+
+	s.dispatchDue(first.Add(-time.Millisecond))
+	s.wg.Wait()
+	if got := counter.Load(); got != 0 {
+		t.Fatalf("executor called %d times before first run, want 0", got)
+	}
+
+	s.dispatchDue(first)
+	s.wg.Wait()
+	if got := counter.Load(); got != 1 {
+		t.Fatalf("executor called %d times at first run, want 1", got)
+	}
+	if next := s.schedule["chk-1"].next; !next.Equal(first.Add(30 * time.Second)) {
+		t.Errorf("next run = %v, want %v", next, first.Add(30*time.Second))
+	}
+
+	// A late tick skips missed runs and stays on the original phase.
+	s.dispatchDue(first.Add(95 * time.Second))
+	s.wg.Wait()
+	if got := counter.Load(); got != 2 {
+		t.Fatalf("executor called %d times after late tick, want 2", got)
+	}
+	if next := s.schedule["chk-1"].next; !next.Equal(first.Add(120 * time.Second)) {
+		t.Errorf("next run after late tick = %v, want %v", next, first.Add(120*time.Second))
+	}
+}
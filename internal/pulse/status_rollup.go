@@ -0,0 +1,215 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/HerbHall/subnetree/pkg/roles"
+	"go.uber.org/zap"
+)
+
+// Device states reported by the status rollup.
+const (
+	DeviceStateHealthy  = "healthy"
+	DeviceStateDegraded = "degraded"
+	DeviceStateDown     = "down"
+)
+
+// DeviceStatus is one device's entry in the status rollup.
+type DeviceStatus struct {
+	roles.MonitorStatus
+	State        string `json:"state"` // healthy, degraded, or down
+	ActiveAlerts int    `json:"active_alerts"`
+}
+
+// StatusSummary counts devices by state.
+type StatusSummary struct {
+	Total    int `json:"total"`
+	Healthy  int `json:"healthy"`
+	Degraded int `json:"degraded"`
+	Down     int `json:"down"`
+}
+
+// StatusRollup is the response for GET /pulse/status.
+type StatusRollup struct {
+	Summary StatusSummary  `json:"summary"`
+	Devices []DeviceStatus `json:"devices"`
+}
+
+// deviceLatestResult pairs a monitored device with its most recent check
+// result, which is nil when the device has not been checked yet.
+type deviceLatestResult struct {
+	DeviceID string
+	Latest   *CheckResult
+}
+
+// ListLatestDeviceResults returns every device that has checks, with its
+// most recent result, in one query. If deviceIDs is non-empty only those
+// devices are returned.
+func (s *PulseStore) ListLatestDeviceResults(ctx context.Context, deviceIDs []string) ([]deviceLatestResult, error) {
+	devices := `SELECT DISTINCT device_id FROM pulse_checks`
+	args := make([]any, 0, len(deviceIDs))
+	if len(deviceIDs) > 0 {
+		devices += ` WHERE device_id IN (?` + strings.Repeat(", ?", len(deviceIDs)-1) + `)`
+		for _, id := range deviceIDs {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		WITH devices AS (`+devices+`),
+		latest AS (
+			SELECT id, check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at,
+				ROW_NUMBER() OVER (PARTITION BY device_id ORDER BY checked_at DESC, id DESC) AS rn
+			FROM pulse_check_results
+			WHERE device_id IN (SELECT device_id FROM devices)
+		)
+		SELECT d.device_id, l.id, l.check_id, l.success, l.latency_ms, l.packet_loss, l.error_message, l.checked_at
+		FROM devices d
+		LEFT JOIN latest l ON l.device_id = d.device_id AND l.rn = 1
+		ORDER BY d.device_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list latest device results: %w", err)
+	}
+	defer rows.Close()
+
+	var out []deviceLatestResult
+	for rows.Next() {
+		var deviceID string
+		var id sql.NullInt64
+		var checkID, errMsg sql.NullString
+		var success sql.NullInt64
+		var latency, loss sql.NullFloat64
+		var checkedAt sql.NullTime
+		if err := rows.Scan(&deviceID, &id, &checkID, &success, &latency, &loss, &errMsg, &checkedAt); err != nil {
+			return nil, fmt.Errorf("scan latest device result: %w", err)
+		}
+		entry := deviceLatestResult{DeviceID: deviceID}
+		if id.Valid {
+			entry.Latest = &CheckResult{
+				ID:           id.Int64,
+				CheckID:      checkID.String,
+				DeviceID:     deviceID,
+				Success:      success.Int64 != 0,
+				LatencyMs:    latency.Float64,
+				PacketLoss:   loss.Float64,
+				ErrorMessage: errMsg.String,
+				CheckedAt:    checkedAt.Time,
+			}
+		}
+		out = append(out, entry)
+	}
+	return out, rows.Err()
+}
+
+// StatusRollup computes the status of every monitored device (or only those
+// in deviceIDs) from one pass over latest results and active alerts.
+func (m *Module) StatusRollup(ctx context.Context, deviceIDs []string) (*StatusRollup, error) {
+	if m.store == nil {
+		return nil, fmt.Errorf("pulse store not available")
+	}
+
+	latest, err := m.store.ListLatestDeviceResults(ctx, deviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := m.store.ListActiveAlerts(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("list active alerts: %w", err)
+	}
+
+	// Alerts are newest first, so the first one seen per device is its latest.
+	byDevice := make(map[string][]*Alert)
+	for i := range alerts {
+		if alerts[i].LowPriority {
+			continue
+		}
+		byDevice[alerts[i].DeviceID] = append(byDevice[alerts[i].DeviceID], &alerts[i])
+	}
+
+	rollup := &StatusRollup{Devices: make([]DeviceStatus, 0, len(latest))}
+	for i := range latest {
+		entry := deviceStatusFrom(latest[i].DeviceID, latest[i].Latest, byDevice[latest[i].DeviceID])
+		switch entry.State {
+		case DeviceStateHealthy:
+			rollup.Summary.Healthy++
+		case DeviceStateDegraded:
+			rollup.Summary.Degraded++
+		case DeviceStateDown:
+			rollup.Summary.Down++
+		}
+		rollup.Devices = append(rollup.Devices, entry)
+	}
+	rollup.Summary.Total = len(rollup.Devices)
+	return rollup, nil
+}
+
+// deviceStatusFrom derives a device's rollup entry. A failed latest check or
+// an active critical alert means down; any other active alert means
+// degraded. Low-priority alerts must already be excluded.
+func deviceStatusFrom(deviceID string, latest *CheckResult, alerts []*Alert) DeviceStatus {
+	entry := DeviceStatus{
+		MonitorStatus: roles.MonitorStatus{DeviceID: deviceID, Healthy: true},
+		State:         DeviceStateHealthy,
+		ActiveAlerts:  len(alerts),
+	}
+	applyLatestResult(&entry.MonitorStatus, latest)
+	if latest != nil && !latest.Success {
+		entry.State = DeviceStateDown
+	}
+
+	if len(alerts) > 0 {
+		entry.Healthy = false
+		entry.Message = alerts[0].Message
+		if entry.State == DeviceStateHealthy {
+			entry.State = DeviceStateDegraded
+		}
+		for _, a := range alerts {
+			if a.Severity == "critical" {
+				entry.State = DeviceStateDown
+				break
+			}
+		}
+	}
+	return entry
+}
+
+// handleStatusRollup returns the monitoring status of every monitored device.
+//
+//	@Summary		Fleet status rollup
+//	@Description	Returns monitoring status for every device with checks, plus counts of healthy, degraded, and down devices.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			device_ids query string false "Comma-separated device IDs to include"
+//	@Success		200 {object} StatusRollup
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/status [get]
+func (m *Module) handleStatusRollup(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	var deviceIDs []string
+	if v := r.URL.Query().Get("device_ids"); v != "" {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				deviceIDs = append(deviceIDs, id)
+			}
+		}
+	}
+
+	rollup, err := m.StatusRollup(r.Context(), deviceIDs)
+	if err != nil {
+		m.logger.Warn("failed to compute status rollup", zap.Error(err))
+		pulseWriteError(w, http.StatusInternalServerError, "failed to get status")
+		return
+	}
+	pulseWriteJSON(w, http.StatusOK, rollup)
+}
@@ -0,0 +1,139 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// seedRollupDevice creates a check for a device and records the given
+// results, oldest first.
+func seedRollupDevice(t *testing.T, ps *PulseStore, deviceID string, results ...bool) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	check := &Check{
+		ID: "check-" + deviceID, DeviceID: deviceID, CheckType: "icmp",
+		Target: "10.0.0.1", IntervalSeconds: 60, Enabled: true,
+		CreatedAt: now, UpdatedAt: now,
+	}
+	if err := ps.InsertCheck(ctx, check); err != nil {
+		t.Fatalf("insert check: %v", err)
+	}
+	for i, ok := range results {
+		r := &CheckResult{
+			CheckID: check.ID, DeviceID: deviceID, Success: ok, LatencyMs: 5,
+			CheckedAt: now.Add(time.Duration(i-len(results)) * time.Minute),
+		}
+		if !ok {
+			r.ErrorMessage = "timeout"
+		}
+		if err := ps.InsertResult(ctx, r); err != nil {
+			t.Fatalf("insert result: %v", err)
+		}
+	}
+}
+
+func seedRollupAlert(t *testing.T, ps *PulseStore, deviceID, severity string, lowPriority bool) {
+	t.Helper()
+	a := &Alert{
+		ID: fmt.Sprintf("alert-%s-%s", deviceID, severity), CheckID: "check-" + deviceID, DeviceID: deviceID,
+		Severity: severity, Message: severity + " on " + deviceID,
+		TriggeredAt: time.Now().UTC(), ConsecutiveFailures: 3, LowPriority: lowPriority,
+	}
+	if err := ps.InsertAlert(context.Background(), a); err != nil {
+		t.Fatalf("insert alert: %v", err)
+	}
+}
+
+func TestHandleStatusRollup_MixedStates(t *testing.T) {
+	m, ps := newTestModule(t)
+
+	seedRollupDevice(t, ps, "dev-ok", false, true)      // recovered
+	seedRollupDevice(t, ps, "dev-new")                  // no results yet
+	seedRollupDevice(t, ps, "dev-warn", true)           // up, warning alert
+	seedRollupDevice(t, ps, "dev-failing", true, false) // latest check failed
+	seedRollupDevice(t, ps, "dev-critical", true)       // critical alert
+	seedRollupDevice(t, ps, "dev-lowprio", true)        // low-priority alert only
+	seedRollupAlert(t, ps, "dev-warn", "warning", false)
+	seedRollupAlert(t, ps, "dev-critical", "critical", false)
+	seedRollupAlert(t, ps, "dev-lowprio", "critical", true)
+
+	req := httptest.NewRequest(http.MethodGet, "/status", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleStatusRollup(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body = %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var got StatusRollup
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	wantSummary := StatusSummary{Total: 6, Healthy: 3, Degraded: 1, Down: 2}
+	if got.Summary != wantSummary {
+		t.Errorf("summary = %+v, want %+v", got.Summary, wantSummary)
+	}
+
+	wantStates := map[string]string{
+		"dev-ok":       DeviceStateHealthy,
+		"dev-new":      DeviceStateHealthy,
+		"dev-warn":     DeviceStateDegraded,
+		"dev-failing":  DeviceStateDown,
+		"dev-critical": DeviceStateDown,
+		"dev-lowprio":  DeviceStateHealthy,
+	}
+	if len(got.Devices) != len(wantStates) {
+		t.Fatalf("devices = %d, want %d", len(got.Devices), len(wantStates))
+	}
+	for _, d := range got.Devices {
+		if d.State != wantStates[d.DeviceID] {
+			t.Errorf("%s state = %q, want %q", d.DeviceID, d.State, wantStates[d.DeviceID])
+		}
+		if d.Healthy != (d.State == DeviceStateHealthy) {
+			t.Errorf("%s healthy = %v with state %q", d.DeviceID, d.Healthy, d.State)
+		}
+		switch d.DeviceID {
+		case "dev-new":
+			if d.Message != "no check data available" || !d.CheckedAt.IsZero() {
+				t.Errorf("dev-new = %+v, want no check data", d)
+			}
+		case "dev-failing":
+			if d.Message != "timeout" {
+				t.Errorf("dev-failing message = %q, want latest result error", d.Message)
+			}
+		case "dev-critical":
+			if d.ActiveAlerts != 1 || d.Message != "critical on dev-critical" {
+				t.Errorf("dev-critical = %+v", d)
+			}
+		}
+	}
+}
+
+func TestHandleStatusRollup_DeviceFilter(t *testing.T) {
+	m, ps := newTestModule(t)
+
+	seedRollupDevice(t, ps, "dev-a", true)
+	seedRollupDevice(t, ps, "dev-b", false)
+	seedRollupDevice(t, ps, "dev-c", true)
+
+	req := httptest.NewRequest(http.MethodGet, "/status?device_ids=dev-b,dev-c,dev-unknown", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleStatusRollup(w, req)
+
+	var got StatusRollup
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if got.Summary != (StatusSummary{Total: 2, Healthy: 1, Down: 1}) {
+		t.Errorf("summary = %+v", got.Summary)
+	}
+	if len(got.Devices) != 2 || got.Devices[0].DeviceID != "dev-b" || got.Devices[1].DeviceID != "dev-c" {
+		t.Errorf("devices = %+v, want dev-b and dev-c", got.Devices)
+	}
+}
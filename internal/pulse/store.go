@@ -3,8 +3,13 @@ package pulse
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/HerbHall/subnetree/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // MetricDataPoint represents a single aggregated metric value at a point in time.
@@ -23,15 +28,23 @@ type MetricSeries struct {
 
 // Check represents a registered monitoring target.
 type Check struct {
-	ID              string    `json:"id"`
-	DeviceID        string    `json:"device_id"`
-	DeviceName      string    `json:"device_name"`
-	CheckType       string    `json:"check_type"`
-	Target          string    `json:"target"`
-	IntervalSeconds int       `json:"interval_seconds"`
-	Enabled         bool      `json:"enabled"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID                    string     `json:"id"`
+	DeviceID              string     `json:"device_id"`
+	DeviceName            string     `json:"device_name"`
+	CheckType             string     `json:"check_type"`
+	Target                string     `json:"target"`
+	IntervalSeconds       int        `json:"interval_seconds"`
+	Enabled               bool       `json:"enabled"`
+	LowPriority           bool       `json:"low_priority"`                      // alerts are auto-acknowledged and hidden from the active view
+	ExpectedStatus        int        `json:"expected_status,omitempty"`         // http only; 0 accepts any 2xx
+	ExpectedBodySubstring string     `json:"expected_body_substring,omitempty"` // http and udp; empty skips the response check
+	FailureThreshold      *int       `json:"failure_threshold,omitempty"`       // nil uses the global consecutive_failures
+	TimeoutMs             int        `json:"timeout_ms,omitempty"`              // 0 uses the global ping_timeout
+	Payload               string     `json:"payload,omitempty"`                 // udp only; datagram sent to the target
+	ParentCheckID         string     `json:"parent_check_id,omitempty"`         // failures are suppressed while this check has an active alert
+	MutedUntil            *time.Time `json:"muted_until,omitempty"`             // notifications are withheld until this time
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
 }
 
 // CheckResult represents the outcome of a single health check.
@@ -44,6 +57,24 @@ type CheckResult struct {
 	PacketLoss   float64   `json:"packet_loss"`
 	ErrorMessage string    `json:"error_message,omitempty"`
 	CheckedAt    time.Time `json:"checked_at"`
+
+	// TLS holds the certificate observed by a tls check. It is not persisted
+	// with the result; certificate state is tracked in pulse_tls_certs.
+	TLS *TLSCertInfo `json:"tls,omitempty"`
+
+	// Timing breaks down where an http check spent its time. It is nil for
+	// other check types.
+	Timing *HTTPTiming `json:"timing,omitempty"`
+}
+
+// HTTPTiming is the per-phase latency of an HTTP check, in milliseconds.
+// FirstByteMs runs from the start of the request, so it includes the
+// other phases.
+type HTTPTiming struct {
+	DNSMs       float64 `json:"dns_ms"`
+	ConnectMs   float64 `json:"connect_ms"`
+	TLSMs       float64 `json:"tls_ms"`
+	FirstByteMs float64 `json:"first_byte_ms"`
 }
 
 // Alert represents a triggered monitoring alert.
@@ -60,6 +91,31 @@ type Alert struct {
 	ConsecutiveFailures int        `json:"consecutive_failures"`
 	Suppressed          bool       `json:"suppressed"`
 	SuppressedBy        string     `json:"suppressed_by,omitempty"`
+	LowPriority         bool       `json:"low_priority"`
+	EscalatedAt         *time.Time `json:"escalated_at,omitempty"` // when Severity was last raised by escalation
+	AcknowledgedBy      string     `json:"acknowledged_by,omitempty"`
+	AcknowledgeComment  string     `json:"acknowledge_comment,omitempty"`
+
+	// Annotations is the alert's note timeline, oldest first. It is only
+	// populated when a single alert is fetched.
+	Annotations []AlertAnnotation `json:"annotations,omitempty"`
+}
+
+// Alert annotation kinds.
+const (
+	AnnotationAcknowledge = "acknowledge"
+	AnnotationNote        = "note"
+)
+
+// AlertAnnotation is a note left on an alert, for example by whoever
+// acknowledged it, so context carries over to the next shift.
+type AlertAnnotation struct {
+	ID        int64     `json:"id"`
+	AlertID   string    `json:"alert_id"`
+	Kind      string    `json:"kind"`
+	Author    string    `json:"author,omitempty"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // CheckDependency represents a dependency between a check and an upstream device.
@@ -72,11 +128,13 @@ type CheckDependency struct {
 
 // AlertFilters controls filtering for ListAlerts queries.
 type AlertFilters struct {
-	DeviceID      string
-	Severity      string
-	ActiveOnly    bool
-	Suppressed    *bool // nil = no filter, true = only suppressed, false = only non-suppressed
-	Limit         int
+	DeviceID    string
+	Severity    string
+	ActiveOnly  bool
+	Suppressed  *bool // nil = no filter, true = only suppressed, false = only non-suppressed
+	LowPriority *bool // nil = no filter, true = only low-priority, false = only normal
+	Limit       int
+	Offset      int
 }
 
 // PulseStore provides database access for the Pulse monitoring plugin.
@@ -97,12 +155,19 @@ func (s *PulseStore) InsertCheck(ctx context.Context, c *Check) error {
 	if c.Enabled {
 		enabled = 1
 	}
+	lowPriority := 0
+	if c.LowPriority {
+		lowPriority = 1
+	}
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO pulse_checks (
-			id, device_id, check_type, target, interval_seconds, enabled, created_at, updated_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+			expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+			created_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		c.ID, c.DeviceID, c.CheckType, c.Target, c.IntervalSeconds,
-		enabled, c.CreatedAt, c.UpdatedAt,
+		enabled, lowPriority, c.ExpectedStatus, c.ExpectedBodySubstring, nullInt(c.FailureThreshold),
+		c.TimeoutMs, c.Payload, c.ParentCheckID, c.CreatedAt, c.UpdatedAt,
 	)
 	if err != nil {
 		return fmt.Errorf("insert check: %w", err)
@@ -113,14 +178,19 @@ func (s *PulseStore) InsertCheck(ctx context.Context, c *Check) error {
 // GetCheck returns a check by ID. Returns nil, nil if not found.
 func (s *PulseStore) GetCheck(ctx context.Context, id string) (*Check, error) {
 	var c Check
-	var enabledInt int
+	var enabledInt, lowPriorityInt int
+	var failureThreshold sql.NullInt64
+	var mutedUntil sql.NullTime
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, device_id, check_type, target, interval_seconds, enabled, created_at, updated_at
+		SELECT id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+			expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+			muted_until, created_at, updated_at
 		FROM pulse_checks WHERE id = ?`,
 		id,
 	).Scan(
 		&c.ID, &c.DeviceID, &c.CheckType, &c.Target, &c.IntervalSeconds,
-		&enabledInt, &c.CreatedAt, &c.UpdatedAt,
+		&enabledInt, &lowPriorityInt,
+		&c.ExpectedStatus, &c.ExpectedBodySubstring, &failureThreshold, &c.TimeoutMs, &c.Payload, &c.ParentCheckID, &mutedUntil, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -129,20 +199,28 @@ func (s *PulseStore) GetCheck(ctx context.Context, id string) (*Check, error) {
 		return nil, fmt.Errorf("get check: %w", err)
 	}
 	c.Enabled = enabledInt != 0
+	c.LowPriority = lowPriorityInt != 0
+	c.FailureThreshold = intPtr(failureThreshold)
+	c.MutedUntil = timePtr(mutedUntil)
 	return &c, nil
 }
 
 // GetCheckByDeviceID returns the first check for a device. Returns nil, nil if not found.
 func (s *PulseStore) GetCheckByDeviceID(ctx context.Context, deviceID string) (*Check, error) {
 	var c Check
-	var enabledInt int
+	var enabledInt, lowPriorityInt int
+	var failureThreshold sql.NullInt64
+	var mutedUntil sql.NullTime
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, device_id, check_type, target, interval_seconds, enabled, created_at, updated_at
+		SELECT id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+			expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+			muted_until, created_at, updated_at
 		FROM pulse_checks WHERE device_id = ? LIMIT 1`,
 		deviceID,
 	).Scan(
 		&c.ID, &c.DeviceID, &c.CheckType, &c.Target, &c.IntervalSeconds,
-		&enabledInt, &c.CreatedAt, &c.UpdatedAt,
+		&enabledInt, &lowPriorityInt,
+		&c.ExpectedStatus, &c.ExpectedBodySubstring, &failureThreshold, &c.TimeoutMs, &c.Payload, &c.ParentCheckID, &mutedUntil, &c.CreatedAt, &c.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -151,13 +229,54 @@ func (s *PulseStore) GetCheckByDeviceID(ctx context.Context, deviceID string) (*
 		return nil, fmt.Errorf("get check by device_id: %w", err)
 	}
 	c.Enabled = enabledInt != 0
+	c.LowPriority = lowPriorityInt != 0
+	c.FailureThreshold = intPtr(failureThreshold)
+	c.MutedUntil = timePtr(mutedUntil)
 	return &c, nil
 }
 
+// ListChecksByDevice returns all checks for a device.
+func (s *PulseStore) ListChecksByDevice(ctx context.Context, deviceID string) ([]Check, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+			expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+			muted_until, created_at, updated_at
+		FROM pulse_checks WHERE device_id = ? ORDER BY created_at`,
+		deviceID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list checks by device: %w", err)
+	}
+	defer rows.Close()
+
+	var checks []Check
+	for rows.Next() {
+		var c Check
+		var enabledInt, lowPriorityInt int
+		var failureThreshold sql.NullInt64
+		var mutedUntil sql.NullTime
+		if err := rows.Scan(
+			&c.ID, &c.DeviceID, &c.CheckType, &c.Target, &c.IntervalSeconds,
+			&enabledInt, &lowPriorityInt,
+			&c.ExpectedStatus, &c.ExpectedBodySubstring, &failureThreshold, &c.TimeoutMs, &c.Payload, &c.ParentCheckID, &mutedUntil, &c.CreatedAt, &c.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan check row: %w", err)
+		}
+		c.Enabled = enabledInt != 0
+		c.LowPriority = lowPriorityInt != 0
+		c.FailureThreshold = intPtr(failureThreshold)
+		c.MutedUntil = timePtr(mutedUntil)
+		checks = append(checks, c)
+	}
+	return checks, rows.Err()
+}
+
 // ListEnabledChecks returns all enabled monitoring checks.
 func (s *PulseStore) ListEnabledChecks(ctx context.Context) ([]Check, error) {
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, device_id, check_type, target, interval_seconds, enabled, created_at, updated_at
+		SELECT id, device_id, check_type, target, interval_seconds, enabled, low_priority,
+			expected_status, expected_body, failure_threshold, timeout_ms, payload, parent_check_id,
+			muted_until, created_at, updated_at
 		FROM pulse_checks WHERE enabled = 1 ORDER BY created_at`,
 	)
 	if err != nil {
@@ -168,19 +287,68 @@ func (s *PulseStore) ListEnabledChecks(ctx context.Context) ([]Check, error) {
 	var checks []Check
 	for rows.Next() {
 		var c Check
-		var enabledInt int
+		var enabledInt, lowPriorityInt int
+		var failureThreshold sql.NullInt64
+		var mutedUntil sql.NullTime
 		if err := rows.Scan(
 			&c.ID, &c.DeviceID, &c.CheckType, &c.Target, &c.IntervalSeconds,
-			&enabledInt, &c.CreatedAt, &c.UpdatedAt,
+			&enabledInt, &lowPriorityInt,
+			&c.ExpectedStatus, &c.ExpectedBodySubstring, &failureThreshold, &c.TimeoutMs, &c.Payload, &c.ParentCheckID, &mutedUntil, &c.CreatedAt, &c.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan check row: %w", err)
 		}
 		c.Enabled = enabledInt != 0
+		c.LowPriority = lowPriorityInt != 0
+		c.FailureThreshold = intPtr(failureThreshold)
+		c.MutedUntil = timePtr(mutedUntil)
 		checks = append(checks, c)
 	}
 	return checks, rows.Err()
 }
 
+// nullInt converts an optional int to a nullable SQL value.
+func nullInt(v *int) sql.NullInt64 {
+	if v == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: int64(*v), Valid: true}
+}
+
+// intPtr converts a nullable SQL integer to an optional int.
+func intPtr(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	n := int(v.Int64)
+	return &n
+}
+
+// timePtr converts a nullable SQL timestamp to an optional time.
+func timePtr(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	t := v.Time
+	return &t
+}
+
+// SetCheckMute mutes a check's notifications until the given time, or
+// unmutes it when until is nil.
+func (s *PulseStore) SetCheckMute(ctx context.Context, id string, until *time.Time) error {
+	var mutedUntil sql.NullTime
+	if until != nil {
+		mutedUntil = sql.NullTime{Time: until.UTC(), Valid: true}
+	}
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE pulse_checks SET muted_until = ?, updated_at = ? WHERE id = ?`,
+		mutedUntil, time.Now().UTC(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("set check mute: %w", err)
+	}
+	return nil
+}
+
 // UpdateCheckEnabled sets the enabled state of a check.
 func (s *PulseStore) UpdateCheckEnabled(ctx context.Context, id string, enabled bool) error {
 	enabledInt := 0
@@ -203,7 +371,8 @@ func (s *PulseStore) UpdateCheckEnabled(ctx context.Context, id string, enabled
 func (s *PulseStore) ListAllChecks(ctx context.Context) ([]Check, error) {
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT c.id, c.device_id, c.check_type, c.target, c.interval_seconds,
-			c.enabled, c.created_at, c.updated_at,
+			c.enabled, c.low_priority, c.expected_status, c.expected_body, c.failure_threshold,
+			c.timeout_ms, c.payload, c.parent_check_id, c.muted_until, c.created_at, c.updated_at,
 			COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), c.device_id) AS device_name
 		FROM pulse_checks c
 		LEFT JOIN recon_devices d ON d.id = c.device_id
@@ -217,29 +386,45 @@ func (s *PulseStore) ListAllChecks(ctx context.Context) ([]Check, error) {
 	var checks []Check
 	for rows.Next() {
 		var c Check
-		var enabledInt int
+		var enabledInt, lowPriorityInt int
+		var failureThreshold sql.NullInt64
+		var mutedUntil sql.NullTime
 		if err := rows.Scan(
 			&c.ID, &c.DeviceID, &c.CheckType, &c.Target, &c.IntervalSeconds,
-			&enabledInt, &c.CreatedAt, &c.UpdatedAt, &c.DeviceName,
+			&enabledInt, &lowPriorityInt,
+			&c.ExpectedStatus, &c.ExpectedBodySubstring, &failureThreshold, &c.TimeoutMs, &c.Payload, &c.ParentCheckID, &mutedUntil, &c.CreatedAt, &c.UpdatedAt, &c.DeviceName,
 		); err != nil {
 			return nil, fmt.Errorf("scan check row: %w", err)
 		}
 		c.Enabled = enabledInt != 0
+		c.LowPriority = lowPriorityInt != 0
+		c.FailureThreshold = intPtr(failureThreshold)
+		c.MutedUntil = timePtr(mutedUntil)
 		checks = append(checks, c)
 	}
 	return checks, rows.Err()
 }
 
-// UpdateCheck updates a check's type, target, interval, and enabled state.
+// UpdateCheck updates a check's type, target, interval, enabled state,
+// priority, response expectations, failure threshold, timeout, payload, and
+// parent check.
 func (s *PulseStore) UpdateCheck(ctx context.Context, c *Check) error {
 	enabledInt := 0
 	if c.Enabled {
 		enabledInt = 1
 	}
+	lowPriorityInt := 0
+	if c.LowPriority {
+		lowPriorityInt = 1
+	}
 	_, err := s.db.ExecContext(ctx, `
-		UPDATE pulse_checks SET check_type = ?, target = ?, interval_seconds = ?, enabled = ?, updated_at = ?
+		UPDATE pulse_checks SET check_type = ?, target = ?, interval_seconds = ?, enabled = ?,
+			low_priority = ?, expected_status = ?, expected_body = ?, failure_threshold = ?,
+			timeout_ms = ?, payload = ?, parent_check_id = ?, updated_at = ?
 		WHERE id = ?`,
-		c.CheckType, c.Target, c.IntervalSeconds, enabledInt, c.UpdatedAt, c.ID,
+		c.CheckType, c.Target, c.IntervalSeconds, enabledInt, lowPriorityInt,
+		c.ExpectedStatus, c.ExpectedBodySubstring, nullInt(c.FailureThreshold), c.TimeoutMs, c.Payload, c.ParentCheckID,
+		c.UpdatedAt, c.ID,
 	)
 	if err != nil {
 		return fmt.Errorf("update check: %w", err)
@@ -257,6 +442,11 @@ func (s *PulseStore) DeleteCheck(ctx context.Context, id string) error {
 	if err != nil {
 		return fmt.Errorf("delete check: %w", err)
 	}
+	// Dependents of a deleted check no longer have a parent.
+	_, err = s.db.ExecContext(ctx, `UPDATE pulse_checks SET parent_check_id = '' WHERE parent_check_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("clear parent check: %w", err)
+	}
 	return nil
 }
 
@@ -268,12 +458,20 @@ func (s *PulseStore) InsertResult(ctx context.Context, r *CheckResult) error {
 	if r.Success {
 		success = 1
 	}
+	var timing sql.NullString
+	if r.Timing != nil {
+		b, err := json.Marshal(r.Timing)
+		if err != nil {
+			return fmt.Errorf("marshal result timing: %w", err)
+		}
+		timing = sql.NullString{String: string(b), Valid: true}
+	}
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO pulse_check_results (
-			check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at
-		) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at, timing
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 		r.CheckID, r.DeviceID, success, r.LatencyMs, r.PacketLoss,
-		r.ErrorMessage, r.CheckedAt,
+		r.ErrorMessage, r.CheckedAt, timing,
 	)
 	if err != nil {
 		return fmt.Errorf("insert result: %w", err)
@@ -293,6 +491,30 @@ func (s *PulseStore) UpdateDeviceLastSeen(ctx context.Context, deviceID string,
 	return nil
 }
 
+// DeviceHasAnyTag reports whether the device carries at least one of tags.
+func (s *PulseStore) DeviceHasAnyTag(ctx context.Context, deviceID string, tags []string) (bool, error) {
+	if len(tags) == 0 {
+		return false, nil
+	}
+	args := make([]any, 0, len(tags)+1)
+	args = append(args, deviceID)
+	for _, t := range tags {
+		args = append(args, t)
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(tags)), ",")
+	query := `SELECT 1 FROM recon_devices d, json_each(d.tags) t
+		WHERE d.id = ? AND t.value IN (` + placeholders + `) LIMIT 1` //nolint:gosec // G202: dynamic SQL uses parameterized placeholders only
+	var found int
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(&found)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, fmt.Errorf("check device tags: %w", err)
+	}
+	return true, nil
+}
+
 // ListResults returns check results for a device, ordered by checked_at descending.
 // If limit <= 0, defaults to 100.
 func (s *PulseStore) ListResults(ctx context.Context, deviceID string, limit int) ([]CheckResult, error) {
@@ -300,7 +522,7 @@ func (s *PulseStore) ListResults(ctx context.Context, deviceID string, limit int
 		limit = 100
 	}
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at
+		SELECT id, check_id, device_id, success, latency_ms, packet_loss, error_message, checked_at, timing
 		FROM pulse_check_results WHERE device_id = ? ORDER BY checked_at DESC LIMIT ?`,
 		deviceID, limit,
 	)
@@ -313,29 +535,30 @@ func (s *PulseStore) ListResults(ctx context.Context, deviceID string, limit int
 	for rows.Next() {
 		var r CheckResult
 		var successInt int
+		var timing sql.NullString
 		if err := rows.Scan(
 			&r.ID, &r.CheckID, &r.DeviceID, &successInt, &r.LatencyMs,
-			&r.PacketLoss, &r.ErrorMessage, &r.CheckedAt,
+			&r.PacketLoss, &r.ErrorMessage, &r.CheckedAt, &timing,
 		); err != nil {
 			return nil, fmt.Errorf("scan result row: %w", err)
 		}
 		r.Success = successInt != 0
+		if timing.Valid {
+			r.Timing = &HTTPTiming{}
+			if err := json.Unmarshal([]byte(timing.String), r.Timing); err != nil {
+				return nil, fmt.Errorf("unmarshal result timing: %w", err)
+			}
+		}
 		results = append(results, r)
 	}
 	return results, rows.Err()
 }
 
-// DeleteOldResults deletes check results older than the given time.
+// DeleteOldResults deletes check results older than the given time,
+// keeping those unresolved alerts depend on. See PruneResults.
 // Returns the number of rows deleted.
 func (s *PulseStore) DeleteOldResults(ctx context.Context, before time.Time) (int64, error) {
-	result, err := s.db.ExecContext(ctx, `
-		DELETE FROM pulse_check_results WHERE checked_at < ?`,
-		before,
-	)
-	if err != nil {
-		return 0, fmt.Errorf("delete old results: %w", err)
-	}
-	return result.RowsAffected()
+	return s.PruneResults(ctx, before, defaultPruneBatchSize)
 }
 
 // -- Metrics Queries --
@@ -364,10 +587,47 @@ type metricBucket struct {
 	total         int
 }
 
+// metricBuckets collects fixed-width buckets in the order they are first
+// seen; feeding it rows in time order keeps the buckets in time order.
+type metricBuckets struct {
+	width int64
+	byKey map[int64]*metricBucket
+	keys  []int64
+}
+
+func newMetricBuckets(widthSec int64) *metricBuckets {
+	return &metricBuckets{width: widthSec, byKey: make(map[int64]*metricBucket)}
+}
+
+// get returns the bucket containing t, creating it if needed.
+func (mb *metricBuckets) get(t time.Time) *metricBucket {
+	key := (t.Unix() / mb.width) * mb.width
+	b, exists := mb.byKey[key]
+	if !exists {
+		b = &metricBucket{}
+		mb.byKey[key] = b
+		mb.keys = append(mb.keys, key)
+	}
+	return b
+}
+
 // QueryMetrics returns aggregated time-series data for a device, with
 // automatic downsampling based on the requested time range.
 // Bucketing is performed in Go to avoid SQLite date-format parsing issues.
+// Ranges bucketed by the hour or coarser read whole buckets from the metric
+// rollups and only the edges from raw results; see RollupMetrics.
 func (s *PulseStore) QueryMetrics(ctx context.Context, deviceID, metric, timeRange string) (*MetricSeries, error) {
+	ctx, span := tracing.Start(ctx, "PulseStore.QueryMetrics",
+		attribute.String("device.id", deviceID),
+		attribute.String("pulse.metric", metric),
+		attribute.String("pulse.range", timeRange),
+	)
+	series, err := s.queryMetrics(ctx, deviceID, metric, timeRange)
+	tracing.End(span, err)
+	return series, err
+}
+
+func (s *PulseStore) queryMetrics(ctx context.Context, deviceID, metric, timeRange string) (*MetricSeries, error) {
 	if !validMetrics[metric] {
 		return nil, fmt.Errorf("unknown metric %q: must be latency, packet_loss, or success_rate", metric)
 	}
@@ -390,49 +650,35 @@ func (s *PulseStore) QueryMetrics(ctx context.Context, deviceID, metric, timeRan
 		bucketSec = 3600 // 1-hour buckets
 	}
 
-	// Fetch raw results within the time range.
-	rows, err := s.db.QueryContext(ctx, `
-		SELECT latency_ms, packet_loss, success, checked_at
-		FROM pulse_check_results
-		WHERE device_id = ? AND checked_at >= ?
-		ORDER BY checked_at ASC`,
-		deviceID, since,
-	)
-	if err != nil {
-		return nil, fmt.Errorf("query metrics: %w", err)
-	}
-	defer rows.Close()
-
-	// Aggregate results into time buckets in Go.
-	buckets := make(map[int64]*metricBucket)
-	var bucketKeys []int64
-	for rows.Next() {
-		var latency, packetLoss float64
-		var successInt int
-		var checkedAt time.Time
-		if err := rows.Scan(&latency, &packetLoss, &successInt, &checkedAt); err != nil {
-			return nil, fmt.Errorf("scan metric row: %w", err)
+	buckets := newMetricBuckets(bucketSec)
+	rawSince := since
+	if source := metricSource(bucketSec); source != metricSourceRaw {
+		through, rolled, err := s.RollupWatermark(ctx, source)
+		if err != nil {
+			return nil, err
 		}
-		key := (checkedAt.Unix() / bucketSec) * bucketSec
-		b, exists := buckets[key]
-		if !exists {
-			b = &metricBucket{}
-			buckets[key] = b
-			bucketKeys = append(bucketKeys, key)
+		// Rollups cover whole buckets, so the partial bucket at the start
+		// of the range and everything past the watermark come from raw
+		// results. The output matches a raw-only query exactly.
+		firstWhole := time.Unix(((since.Unix()+bucketSec-1)/bucketSec)*bucketSec, 0).UTC()
+		if rolled && through.After(firstWhole) {
+			if err := s.addRawMetrics(ctx, buckets, deviceID, since, firstWhole); err != nil {
+				return nil, err
+			}
+			if err := s.addRollupMetrics(ctx, buckets, deviceID, source, firstWhole, through); err != nil {
+				return nil, err
+			}
+			rawSince = through
 		}
-		b.latencySum += latency
-		b.packetLossSum += packetLoss
-		b.successCount += successInt
-		b.total++
 	}
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("iterate metric rows: %w", err)
+	if err := s.addRawMetrics(ctx, buckets, deviceID, rawSince, time.Time{}); err != nil {
+		return nil, err
 	}
 
-	// Convert buckets to data points (already ordered by checkedAt ASC).
-	points := make([]MetricDataPoint, 0, len(bucketKeys))
-	for _, key := range bucketKeys {
-		b := buckets[key]
+	// Convert buckets to data points (already ordered by time).
+	points := make([]MetricDataPoint, 0, len(buckets.keys))
+	for _, key := range buckets.keys {
+		b := buckets.byKey[key]
 		var value float64
 		switch metric {
 		case "latency":
@@ -456,6 +702,43 @@ func (s *PulseStore) QueryMetrics(ctx context.Context, deviceID, metric, timeRan
 	}, nil
 }
 
+// addRawMetrics folds a device's raw results checked in [since, until)
+// into buckets. A zero until leaves the range open-ended.
+func (s *PulseStore) addRawMetrics(ctx context.Context, buckets *metricBuckets, deviceID string, since, until time.Time) error {
+	query := `
+		SELECT latency_ms, packet_loss, success, checked_at
+		FROM pulse_check_results
+		WHERE device_id = ? AND checked_at >= ?`
+	args := []any{deviceID, since}
+	if !until.IsZero() {
+		query += ` AND checked_at < ?`
+		args = append(args, until)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY checked_at ASC`, args...)
+	if err != nil {
+		return fmt.Errorf("query metrics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var latency, packetLoss float64
+		var successInt int
+		var checkedAt time.Time
+		if err := rows.Scan(&latency, &packetLoss, &successInt, &checkedAt); err != nil {
+			return fmt.Errorf("scan metric row: %w", err)
+		}
+		b := buckets.get(checkedAt)
+		b.latencySum += latency
+		b.packetLossSum += packetLoss
+		b.successCount += successInt
+		b.total++
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate metric rows: %w", err)
+	}
+	return nil
+}
+
 // -- Alerts --
 
 // InsertAlert inserts a new monitoring alert.
@@ -464,18 +747,26 @@ func (s *PulseStore) InsertAlert(ctx context.Context, a *Alert) error {
 	if a.ResolvedAt != nil {
 		resolvedAt = sql.NullTime{Time: *a.ResolvedAt, Valid: true}
 	}
+	var acknowledgedAt sql.NullTime
+	if a.AcknowledgedAt != nil {
+		acknowledgedAt = sql.NullTime{Time: *a.AcknowledgedAt, Valid: true}
+	}
 	suppressed := 0
 	if a.Suppressed {
 		suppressed = 1
 	}
+	lowPriority := 0
+	if a.LowPriority {
+		lowPriority = 1
+	}
 	_, err := s.db.ExecContext(ctx, `
 		INSERT INTO pulse_alerts (
 			id, check_id, device_id, severity, message, triggered_at, resolved_at,
-			consecutive_failures, suppressed, suppressed_by
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			acknowledged_at, consecutive_failures, suppressed, suppressed_by, low_priority
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		a.ID, a.CheckID, a.DeviceID, a.Severity, a.Message,
-		a.TriggeredAt, resolvedAt, a.ConsecutiveFailures,
-		suppressed, a.SuppressedBy,
+		a.TriggeredAt, resolvedAt, acknowledgedAt, a.ConsecutiveFailures,
+		suppressed, a.SuppressedBy, lowPriority,
 	)
 	if err != nil {
 		return fmt.Errorf("insert alert: %w", err)
@@ -483,7 +774,7 @@ func (s *PulseStore) InsertAlert(ctx context.Context, a *Alert) error {
 	return nil
 }
 
-// ResolveAlert marks an alert as resolved.
+// ResolveAlert marks an alert and its open incident as resolved.
 func (s *PulseStore) ResolveAlert(ctx context.Context, id string, resolvedAt time.Time) error {
 	_, err := s.db.ExecContext(ctx, `
 		UPDATE pulse_alerts SET resolved_at = ? WHERE id = ?`,
@@ -492,23 +783,32 @@ func (s *PulseStore) ResolveAlert(ctx context.Context, id string, resolvedAt tim
 	if err != nil {
 		return fmt.Errorf("resolve alert: %w", err)
 	}
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE pulse_incidents SET resolved_at = ? WHERE alert_id = ? AND resolved_at IS NULL`,
+		resolvedAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("resolve incident: %w", err)
+	}
 	return nil
 }
 
 // GetActiveAlert returns the active (unresolved) alert for a check. Returns nil, nil if none.
 func (s *PulseStore) GetActiveAlert(ctx context.Context, checkID string) (*Alert, error) {
 	var a Alert
-	var resolvedAt, acknowledgedAt sql.NullTime
-	var suppressedInt int
+	var resolvedAt, acknowledgedAt, escalatedAt sql.NullTime
+	var suppressedInt, lowPriorityInt int
 	err := s.db.QueryRowContext(ctx, `
 		SELECT id, check_id, device_id, severity, message, triggered_at, resolved_at,
-			acknowledged_at, consecutive_failures, suppressed, suppressed_by
+			acknowledged_at, consecutive_failures, suppressed, suppressed_by, low_priority, escalated_at,
+			acknowledged_by, acknowledge_comment
 		FROM pulse_alerts WHERE check_id = ? AND resolved_at IS NULL`,
 		checkID,
 	).Scan(
 		&a.ID, &a.CheckID, &a.DeviceID, &a.Severity, &a.Message,
 		&a.TriggeredAt, &resolvedAt, &acknowledgedAt, &a.ConsecutiveFailures,
-		&suppressedInt, &a.SuppressedBy,
+		&suppressedInt, &a.SuppressedBy, &lowPriorityInt, &escalatedAt,
+		&a.AcknowledgedBy, &a.AcknowledgeComment,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -523,6 +823,10 @@ func (s *PulseStore) GetActiveAlert(ctx context.Context, checkID string) (*Alert
 		a.AcknowledgedAt = &acknowledgedAt.Time
 	}
 	a.Suppressed = suppressedInt != 0
+	a.LowPriority = lowPriorityInt != 0
+	if escalatedAt.Valid {
+		a.EscalatedAt = &escalatedAt.Time
+	}
 	return &a, nil
 }
 
@@ -535,7 +839,8 @@ func (s *PulseStore) ListActiveAlerts(ctx context.Context, deviceID string) ([]A
 	if deviceID == "" {
 		rows, err = s.db.QueryContext(ctx, `
 			SELECT a.id, a.check_id, a.device_id, a.severity, a.message, a.triggered_at, a.resolved_at,
-				a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by,
+				a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by, a.low_priority, a.escalated_at,
+				a.acknowledged_by, a.acknowledge_comment,
 				COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), a.device_id) AS device_name
 			FROM pulse_alerts a
 			LEFT JOIN recon_devices d ON d.id = a.device_id
@@ -544,7 +849,8 @@ func (s *PulseStore) ListActiveAlerts(ctx context.Context, deviceID string) ([]A
 	} else {
 		rows, err = s.db.QueryContext(ctx, `
 			SELECT a.id, a.check_id, a.device_id, a.severity, a.message, a.triggered_at, a.resolved_at,
-				a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by,
+				a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by, a.low_priority, a.escalated_at,
+				a.acknowledged_by, a.acknowledge_comment,
 				COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), a.device_id) AS device_name
 			FROM pulse_alerts a
 			LEFT JOIN recon_devices d ON d.id = a.device_id
@@ -563,17 +869,19 @@ func (s *PulseStore) ListActiveAlerts(ctx context.Context, deviceID string) ([]A
 // GetAlert returns a single alert by ID. Returns nil, nil if not found.
 func (s *PulseStore) GetAlert(ctx context.Context, id string) (*Alert, error) {
 	var a Alert
-	var resolvedAt, acknowledgedAt sql.NullTime
-	var suppressedInt int
+	var resolvedAt, acknowledgedAt, escalatedAt sql.NullTime
+	var suppressedInt, lowPriorityInt int
 	err := s.db.QueryRowContext(ctx, `
 		SELECT id, check_id, device_id, severity, message, triggered_at, resolved_at,
-			acknowledged_at, consecutive_failures, suppressed, suppressed_by
+			acknowledged_at, consecutive_failures, suppressed, suppressed_by, low_priority, escalated_at,
+			acknowledged_by, acknowledge_comment
 		FROM pulse_alerts WHERE id = ?`,
 		id,
 	).Scan(
 		&a.ID, &a.CheckID, &a.DeviceID, &a.Severity, &a.Message,
 		&a.TriggeredAt, &resolvedAt, &acknowledgedAt, &a.ConsecutiveFailures,
-		&suppressedInt, &a.SuppressedBy,
+		&suppressedInt, &a.SuppressedBy, &lowPriorityInt, &escalatedAt,
+		&a.AcknowledgedBy, &a.AcknowledgeComment,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -588,6 +896,10 @@ func (s *PulseStore) GetAlert(ctx context.Context, id string) (*Alert, error) {
 		a.AcknowledgedAt = &acknowledgedAt.Time
 	}
 	a.Suppressed = suppressedInt != 0
+	a.LowPriority = lowPriorityInt != 0
+	if escalatedAt.Valid {
+		a.EscalatedAt = &escalatedAt.Time
+	}
 	return &a, nil
 }
 
@@ -595,10 +907,46 @@ func (s *PulseStore) GetAlert(ctx context.Context, id string) (*Alert, error) {
 // Device names are resolved via LEFT JOIN with recon_devices.
 func (s *PulseStore) ListAlerts(ctx context.Context, filters AlertFilters) ([]Alert, error) {
 	query := `SELECT a.id, a.check_id, a.device_id, a.severity, a.message, a.triggered_at, a.resolved_at,
-		a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by,
+		a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by, a.low_priority, a.escalated_at,
+		a.acknowledged_by, a.acknowledge_comment,
 		COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), a.device_id) AS device_name
 		FROM pulse_alerts a
 		LEFT JOIN recon_devices d ON d.id = a.device_id`
+	where, args := alertFilterClause(filters)
+	query += where + " ORDER BY a.triggered_at DESC"
+
+	limit := filters.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	query += fmt.Sprintf(" LIMIT %d", limit)
+	if filters.Offset > 0 {
+		query += fmt.Sprintf(" OFFSET %d", filters.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	return scanAlertRows(rows)
+}
+
+// CountAlerts returns the number of alerts matching filters, ignoring
+// Limit and Offset.
+func (s *PulseStore) CountAlerts(ctx context.Context, filters AlertFilters) (int, error) {
+	where, args := alertFilterClause(filters)
+	var n int
+	if err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM pulse_alerts a"+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count alerts: %w", err)
+	}
+	return n, nil
+}
+
+// alertFilterClause builds the WHERE clause, with a leading space, for
+// filters against pulse_alerts aliased as a.
+func alertFilterClause(filters AlertFilters) (string, []any) {
 	var conditions []string
 	var args []any
 
@@ -620,41 +968,144 @@ func (s *PulseStore) ListAlerts(ctx context.Context, filters AlertFilters) ([]Al
 			conditions = append(conditions, "a.suppressed = 0")
 		}
 	}
+	if filters.LowPriority != nil {
+		if *filters.LowPriority {
+			conditions = append(conditions, "a.low_priority = 1")
+		} else {
+			conditions = append(conditions, "a.low_priority = 0")
+		}
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// AcknowledgeAlert sets the acknowledged_at timestamp on an alert.
+func (s *PulseStore) AcknowledgeAlert(ctx context.Context, id string) error {
+	return s.AcknowledgeAlertBy(ctx, id, "", "")
+}
+
+// AcknowledgeAlertBy acknowledges an alert on behalf of a user, recording
+// who acknowledged it and why. A non-empty comment is also appended to the
+// alert's annotation timeline.
+func (s *PulseStore) AcknowledgeAlertBy(ctx context.Context, id, by, comment string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin acknowledge alert: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
 
-	if len(conditions) > 0 {
-		query += " WHERE " + conditions[0]
-		for i := 1; i < len(conditions); i++ {
-			query += " AND " + conditions[i]
+	now := time.Now().UTC()
+	res, err := tx.ExecContext(ctx, `
+		UPDATE pulse_alerts SET acknowledged_at = ?, acknowledged_by = ?, acknowledge_comment = ?
+		WHERE id = ?`,
+		now, by, comment, id,
+	)
+	if err != nil {
+		return fmt.Errorf("acknowledge alert: %w", err)
+	}
+	if comment != "" {
+		if n, _ := res.RowsAffected(); n > 0 {
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO pulse_alert_annotations (alert_id, kind, author, body, created_at)
+				VALUES (?, ?, ?, ?, ?)`,
+				id, AnnotationAcknowledge, by, comment, now,
+			); err != nil {
+				return fmt.Errorf("insert acknowledge annotation: %w", err)
+			}
 		}
 	}
+	return tx.Commit()
+}
 
-	query += " ORDER BY a.triggered_at DESC"
+// AddAlertAnnotation appends a note to an alert's timeline, setting its ID
+// and, if unset, its creation time.
+func (s *PulseStore) AddAlertAnnotation(ctx context.Context, a *AlertAnnotation) error {
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = time.Now().UTC()
+	}
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO pulse_alert_annotations (alert_id, kind, author, body, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		a.AlertID, a.Kind, a.Author, a.Body, a.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert alert annotation: %w", err)
+	}
+	a.ID, err = res.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("insert alert annotation: %w", err)
+	}
+	return nil
+}
 
-	limit := filters.Limit
-	if limit <= 0 {
-		limit = 50
+// ListAlertAnnotations returns an alert's annotations in the order they were added.
+func (s *PulseStore) ListAlertAnnotations(ctx context.Context, alertID string) ([]AlertAnnotation, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, alert_id, kind, author, body, created_at
+		FROM pulse_alert_annotations WHERE alert_id = ? ORDER BY id`,
+		alertID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list alert annotations: %w", err)
 	}
-	query += fmt.Sprintf(" LIMIT %d", limit)
+	defer rows.Close()
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	var annotations []AlertAnnotation
+	for rows.Next() {
+		var a AlertAnnotation
+		if err := rows.Scan(&a.ID, &a.AlertID, &a.Kind, &a.Author, &a.Body, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan alert annotation: %w", err)
+		}
+		annotations = append(annotations, a)
+	}
+	return annotations, rows.Err()
+}
+
+// ListEscalationCandidates returns active, unacknowledged, unsuppressed
+// alerts of the given severity that have held it since before the cutoff:
+// triggered before it and, if escalated, escalated before it.
+func (s *PulseStore) ListEscalationCandidates(ctx context.Context, severity string, before time.Time) ([]Alert, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT a.id, a.check_id, a.device_id, a.severity, a.message, a.triggered_at, a.resolved_at,
+			a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by, a.low_priority, a.escalated_at,
+			a.acknowledged_by, a.acknowledge_comment,
+			COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), a.device_id) AS device_name
+		FROM pulse_alerts a
+		LEFT JOIN recon_devices d ON d.id = a.device_id
+		WHERE a.severity = ? AND a.resolved_at IS NULL AND a.acknowledged_at IS NULL
+			AND a.suppressed = 0 AND COALESCE(a.escalated_at, a.triggered_at) <= ?
+		ORDER BY a.triggered_at`,
+		severity, before,
+	)
 	if err != nil {
-		return nil, fmt.Errorf("list alerts: %w", err)
+		return nil, fmt.Errorf("list escalation candidates: %w", err)
 	}
 	defer rows.Close()
 
 	return scanAlertRows(rows)
 }
 
-// AcknowledgeAlert sets the acknowledged_at timestamp on an alert.
-func (s *PulseStore) AcknowledgeAlert(ctx context.Context, id string) error {
-	_, err := s.db.ExecContext(ctx, `
-		UPDATE pulse_alerts SET acknowledged_at = ? WHERE id = ?`,
-		time.Now().UTC(), id,
+// EscalateAlert raises an alert from one severity to the next. The update
+// only applies while the alert still has severity from and is active and
+// unacknowledged, so an alert acknowledged or escalated concurrently is left
+// alone. Reports whether the alert was escalated.
+func (s *PulseStore) EscalateAlert(ctx context.Context, id, from, to string, at time.Time) (bool, error) {
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE pulse_alerts SET severity = ?, escalated_at = ?
+		WHERE id = ? AND severity = ? AND resolved_at IS NULL AND acknowledged_at IS NULL`,
+		to, at, id, from,
 	)
 	if err != nil {
-		return fmt.Errorf("acknowledge alert: %w", err)
+		return false, fmt.Errorf("escalate alert: %w", err)
 	}
-	return nil
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("escalate alert: %w", err)
+	}
+	return n > 0, nil
 }
 
 // DeleteOldAlerts deletes resolved alerts older than the given time.
@@ -671,17 +1122,18 @@ func (s *PulseStore) DeleteOldAlerts(ctx context.Context, before time.Time) (int
 }
 
 // scanAlertRows scans alert rows into a slice, handling nullable columns.
-// Expects 12 columns: the standard 11 alert columns plus device_name.
+// Expects 16 columns: the standard 15 alert columns plus device_name.
 func scanAlertRows(rows *sql.Rows) ([]Alert, error) {
 	var alerts []Alert
 	for rows.Next() {
 		var a Alert
-		var resolvedAt, acknowledgedAt sql.NullTime
-		var suppressedInt int
+		var resolvedAt, acknowledgedAt, escalatedAt sql.NullTime
+		var suppressedInt, lowPriorityInt int
 		if err := rows.Scan(
 			&a.ID, &a.CheckID, &a.DeviceID, &a.Severity, &a.Message,
 			&a.TriggeredAt, &resolvedAt, &acknowledgedAt, &a.ConsecutiveFailures,
-			&suppressedInt, &a.SuppressedBy, &a.DeviceName,
+			&suppressedInt, &a.SuppressedBy, &lowPriorityInt, &escalatedAt,
+			&a.AcknowledgedBy, &a.AcknowledgeComment, &a.DeviceName,
 		); err != nil {
 			return nil, fmt.Errorf("scan alert row: %w", err)
 		}
@@ -692,6 +1144,10 @@ func scanAlertRows(rows *sql.Rows) ([]Alert, error) {
 			a.AcknowledgedAt = &acknowledgedAt.Time
 		}
 		a.Suppressed = suppressedInt != 0
+		a.LowPriority = lowPriorityInt != 0
+		if escalatedAt.Valid {
+			a.EscalatedAt = &escalatedAt.Time
+		}
 		alerts = append(alerts, a)
 	}
 	return alerts, rows.Err()
@@ -789,6 +1245,17 @@ func (s *PulseStore) IsSuppressed(ctx context.Context, checkID string) (suppress
 	return true, byDevice, nil
 }
 
+// UnsuppressAlert clears the suppression flag on an alert so it is treated
+// as a normal active alert.
+func (s *PulseStore) UnsuppressAlert(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE pulse_alerts SET suppressed = 0, suppressed_by = '' WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("unsuppress alert: %w", err)
+	}
+	return nil
+}
+
 // -- Correlation Queries --
 
 // GetParentActiveAlerts returns active alerts for the parent device of the given device,
@@ -814,7 +1281,8 @@ func (s *PulseStore) GetParentActiveAlerts(ctx context.Context, deviceID string,
 	since := time.Now().UTC().Add(-window)
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT a.id, a.check_id, a.device_id, a.severity, a.message, a.triggered_at, a.resolved_at,
-			a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by,
+			a.acknowledged_at, a.consecutive_failures, a.suppressed, a.suppressed_by, a.low_priority, a.escalated_at,
+			a.acknowledged_by, a.acknowledge_comment,
 			COALESCE(NULLIF(d.hostname, ''), json_extract(d.ip_addresses, '$[0]'), a.device_id) AS device_name
 		FROM pulse_alerts a
 		LEFT JOIN recon_devices d ON d.id = a.device_id
@@ -296,6 +296,7 @@ func TestInsertResult_AndListResults(t *testing.T) {
 		PacketLoss:   0.0,
 		ErrorMessage: "",
 		CheckedAt:    now.Add(-1 * time.Minute),
+		Timing:       &HTTPTiming{DNSMs: 1.5, ConnectMs: 2, TLSMs: 4.25, FirstByteMs: 14},
 	}
 	r3 := &CheckResult{
 		CheckID:      "chk-001",
@@ -343,6 +344,12 @@ func TestInsertResult_AndListResults(t *testing.T) {
 	if results[1].LatencyMs != 15.3 {
 		t.Errorf("results[1].LatencyMs = %f, want %f", results[1].LatencyMs, 15.3)
 	}
+	if results[1].Timing == nil || *results[1].Timing != *r2.Timing {
+		t.Errorf("results[1].Timing = %+v, want %+v", results[1].Timing, r2.Timing)
+	}
+	if results[0].Timing != nil {
+		t.Errorf("results[0].Timing = %+v, want nil", results[0].Timing)
+	}
 }
 
 func TestListResults_DefaultLimit(t *testing.T) {
@@ -1035,6 +1042,47 @@ func TestUpdateCheck(t *testing.T) {
 	}
 }
 
+func TestCheckFailureThreshold_RoundTrip(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Second)
+	threshold := 5
+	c := &Check{
+		ID:               "chk-001",
+		DeviceID:         "dev-001",
+		CheckType:        "icmp",
+		Target:           "192.168.1.1",
+		IntervalSeconds:  30,
+		Enabled:          true,
+		FailureThreshold: &threshold,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	insertTestCheck(t, s, c)
+
+	got, err := s.GetCheck(ctx, "chk-001")
+	if err != nil {
+		t.Fatalf("GetCheck: %v", err)
+	}
+	if got.FailureThreshold == nil || *got.FailureThreshold != 5 {
+		t.Fatalf("FailureThreshold = %v, want 5", got.FailureThreshold)
+	}
+
+	// Clearing the threshold stores NULL so the global default applies.
+	got.FailureThreshold = nil
+	if err := s.UpdateCheck(ctx, got); err != nil {
+		t.Fatalf("UpdateCheck: %v", err)
+	}
+	checks, err := s.ListEnabledChecks(ctx)
+	if err != nil {
+		t.Fatalf("ListEnabledChecks: %v", err)
+	}
+	if len(checks) != 1 || checks[0].FailureThreshold != nil {
+		t.Errorf("FailureThreshold after clear = %v, want nil", checks[0].FailureThreshold)
+	}
+}
+
 // -- DeleteCheck --
 
 func TestDeleteCheck(t *testing.T) {
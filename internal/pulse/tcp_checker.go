@@ -35,7 +35,7 @@ func (c *TCPChecker) Check(ctx context.Context, target string) (*CheckResult, er
 	start := time.Now()
 
 	// Use a dialer with context for clean cancellation.
-	dialer := net.Dialer{Timeout: c.timeout}
+	dialer := net.Dialer{Timeout: deadlineTimeout(ctx, c.timeout)}
 	conn, err := dialer.DialContext(ctx, "tcp", target)
 	elapsed := time.Since(start)
 
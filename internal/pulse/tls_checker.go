@@ -0,0 +1,91 @@
+package pulse
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+)
+
+// Compile-time interface guard.
+var _ Checker = (*TLSChecker)(nil)
+
+// TLSChecker performs a TLS handshake against host:port targets and records
+// the leaf certificate presented by the endpoint.
+type TLSChecker struct {
+	timeout time.Duration
+}
+
+// NewTLSChecker creates a new TLS checker with the given handshake timeout.
+// Certificates are not verified; the checker observes what the endpoint
+// presents so that issuer and fingerprint changes can be detected.
+func NewTLSChecker(timeout time.Duration) *TLSChecker {
+	return &TLSChecker{timeout: timeout}
+}
+
+// Check dials the target, completes a TLS handshake, and returns the leaf
+// certificate details alongside the handshake latency.
+func (c *TLSChecker) Check(ctx context.Context, target string) (*CheckResult, error) {
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return &CheckResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("invalid target %q: %v", target, err),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("invalid target %q: %w", target, err)
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: deadlineTimeout(ctx, c.timeout)},
+		Config: &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         host,
+			InsecureSkipVerify: true, //nolint:gosec // G402: the checker records certificates, it does not trust them
+		},
+	}
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", target)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		return &CheckResult{
+			Success:      false,
+			LatencyMs:    float64(elapsed) / float64(time.Millisecond),
+			ErrorMessage: err.Error(),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("tls handshake %s: %w", target, err)
+	}
+	defer conn.Close()
+
+	result := &CheckResult{
+		Success:   true,
+		LatencyMs: float64(elapsed) / float64(time.Millisecond),
+		CheckedAt: time.Now().UTC(),
+	}
+
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return result, nil
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		result.Success = false
+		result.ErrorMessage = "no certificate presented"
+		return result, fmt.Errorf("tls %s: no certificate presented", target)
+	}
+
+	leaf := certs[0]
+	sum := sha256.Sum256(leaf.Raw)
+	result.TLS = &TLSCertInfo{
+		Fingerprint: hex.EncodeToString(sum[:]),
+		Issuer:      leaf.Issuer.String(),
+		Subject:     leaf.Subject.String(),
+		NotAfter:    leaf.NotAfter.UTC(),
+	}
+
+	return result, nil
+}
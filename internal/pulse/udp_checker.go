@@ -0,0 +1,108 @@
+package pulse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// Compile-time interface guards.
+var (
+	_ Checker            = (*UDPChecker)(nil)
+	_ expectationChecker = (*UDPChecker)(nil)
+)
+
+// maxUDPResponse is the largest reply datagram read by a udp check.
+const maxUDPResponse = 64 * 1024
+
+// UDPChecker probes UDP services on host:port targets by sending a payload
+// and waiting for a reply. UDP has no connection, so a missing reply within
+// the timeout is a failure.
+type UDPChecker struct {
+	timeout time.Duration
+}
+
+// NewUDPChecker creates a new UDP checker with the given reply timeout.
+func NewUDPChecker(timeout time.Duration) *UDPChecker {
+	return &UDPChecker{timeout: timeout}
+}
+
+// Check sends an empty datagram to the target and succeeds on any reply.
+func (c *UDPChecker) Check(ctx context.Context, target string) (*CheckResult, error) {
+	return c.check(ctx, target, "", "")
+}
+
+// CheckExpect sends the check's payload to its target and succeeds when the
+// reply contains the expected substring, or on any reply if none is set.
+func (c *UDPChecker) CheckExpect(ctx context.Context, check *Check) (*CheckResult, error) {
+	return c.check(ctx, check.Target, check.Payload, check.ExpectedBodySubstring)
+}
+
+func (c *UDPChecker) check(ctx context.Context, target, payload, expected string) (*CheckResult, error) {
+	if _, _, err := net.SplitHostPort(target); err != nil {
+		return &CheckResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("invalid target %q: %v", target, err),
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("invalid target %q: %w", target, err)
+	}
+
+	timeout := deadlineTimeout(ctx, c.timeout)
+	start := time.Now()
+
+	fail := func(msg string, err error) (*CheckResult, error) {
+		return &CheckResult{
+			Success:      false,
+			LatencyMs:    float64(time.Since(start)) / float64(time.Millisecond),
+			PacketLoss:   1.0,
+			ErrorMessage: msg,
+			CheckedAt:    time.Now().UTC(),
+		}, fmt.Errorf("udp %s: %w", target, err)
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "udp", target)
+	if err != nil {
+		return fail(err.Error(), err)
+	}
+	defer conn.Close()
+
+	// Unblock the read if the context is cancelled before the deadline.
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if timeout > 0 {
+		if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+			return fail(err.Error(), err)
+		}
+	}
+	if _, err := conn.Write([]byte(payload)); err != nil {
+		return fail(err.Error(), err)
+	}
+
+	buf := make([]byte, maxUDPResponse)
+	n, err := conn.Read(buf)
+	elapsed := time.Since(start)
+	if err != nil {
+		var ne net.Error
+		if errors.As(err, &ne) && ne.Timeout() {
+			return fail("no response before timeout", err)
+		}
+		return fail(err.Error(), err)
+	}
+
+	result := &CheckResult{
+		Success:   true,
+		LatencyMs: float64(elapsed) / float64(time.Millisecond),
+		CheckedAt: time.Now().UTC(),
+	}
+	if expected != "" && !strings.Contains(string(buf[:n]), expected) {
+		result.Success = false
+		result.ErrorMessage = fmt.Sprintf("response does not contain %q", expected)
+		return result, fmt.Errorf("udp %s: response does not contain expected substring", target)
+	}
+	return result, nil
+}
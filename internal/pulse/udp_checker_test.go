@@ -0,0 +1,131 @@
+package pulse
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+// startUDPEcho starts a local UDP server that replies with "echo:" plus the
+// received payload. If silent is set it reads but never replies.
+func startUDPEcho(t *testing.T, silent bool) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	t.Cleanup(func() { pc.Close() })
+
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, addr, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if silent {
+				continue
+			}
+			_, _ = pc.WriteTo(append([]byte("echo:"), buf[:n]...), addr)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+func TestUDPChecker_EchoSuccess(t *testing.T) {
+	addr := startUDPEcho(t, false)
+	checker := NewUDPChecker(2 * time.Second)
+
+	result, err := checker.CheckExpect(context.Background(), &Check{
+		Target: addr, Payload: "ping", ExpectedBodySubstring: "echo:ping",
+	})
+	if err != nil {
+		t.Fatalf("CheckExpect() error = %v", err)
+	}
+	if !result.Success {
+		t.Errorf("Success = false, want true (error %q)", result.ErrorMessage)
+	}
+	if result.LatencyMs <= 0 {
+		t.Errorf("LatencyMs = %v, want > 0", result.LatencyMs)
+	}
+
+	// Without expectations any reply passes.
+	result, err = checker.Check(context.Background(), addr)
+	if err != nil || !result.Success {
+		t.Errorf("Check() = %+v, %v, want success", result, err)
+	}
+}
+
+func TestUDPChecker_UnexpectedResponse(t *testing.T) {
+	addr := startUDPEcho(t, false)
+	checker := NewUDPChecker(2 * time.Second)
+
+	result, err := checker.CheckExpect(context.Background(), &Check{
+		Target: addr, Payload: "ping", ExpectedBodySubstring: "pong",
+	})
+	if err == nil {
+		t.Error("CheckExpect() error = nil, want mismatch error")
+	}
+	if result.Success {
+		t.Error("Success = true, want false for mismatched reply")
+	}
+}
+
+func TestUDPChecker_NoReplyTimesOut(t *testing.T) {
+	addr := startUDPEcho(t, true)
+	checker := NewUDPChecker(200 * time.Millisecond)
+
+	start := time.Now()
+	result, err := checker.CheckExpect(context.Background(), &Check{Target: addr, Payload: "ping"})
+	if err == nil {
+		t.Error("CheckExpect() error = nil, want timeout")
+	}
+	if result.Success {
+		t.Error("Success = true, want false when nothing replies")
+	}
+	if result.ErrorMessage != "no response before timeout" {
+		t.Errorf("ErrorMessage = %q, want timeout message", result.ErrorMessage)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("check took %v, want it bounded by the timeout", elapsed)
+	}
+}
+
+func TestUDPChecker_DeadPort(t *testing.T) {
+	// Reserve a port, then close it so nothing is listening.
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen udp: %v", err)
+	}
+	addr := pc.LocalAddr().String()
+	pc.Close()
+
+	result, err := NewUDPChecker(300*time.Millisecond).CheckExpect(context.Background(), &Check{Target: addr, Payload: "ping"})
+	if err == nil {
+		t.Error("CheckExpect() error = nil, want failure for dead port")
+	}
+	if result.Success {
+		t.Error("Success = true, want false for dead port")
+	}
+}
+
+func TestValidateTarget_UDP(t *testing.T) {
+	tests := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"10.0.0.1:514", false},
+		{"dns.example.com:53", false},
+		{"10.0.0.1", true},
+		{":53", true},
+		{"10.0.0.1:0", true},
+		{"10.0.0.1:70000", true},
+	}
+	for _, tt := range tests {
+		err := validateTarget("udp", tt.target)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("validateTarget(udp, %q) error = %v, wantErr %v", tt.target, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package pulse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultResultSpan is how long a result is assumed to represent when its
+// check no longer exists or has no interval, matching the API default.
+const defaultResultSpan = 30 * time.Second
+
+// UptimeSummary reports availability for a device over a time range.
+// Uptime is time-weighted from check results: each result stands for the
+// time until the next result of the same check, capped at the check
+// interval, so periods where no checks ran are not counted as downtime.
+// Outage counts and durations come from alert triggered/resolved times.
+type UptimeSummary struct {
+	DeviceID             string    `json:"device_id"`
+	Range                string    `json:"range"`
+	Start                time.Time `json:"start"`
+	End                  time.Time `json:"end"`
+	UptimePercent        *float64  `json:"uptime_percent"` // nil when no results fall in the range
+	MonitoredSeconds     float64   `json:"monitored_seconds"`
+	DowntimeSeconds      float64   `json:"downtime_seconds"`
+	ChecksTotal          int       `json:"checks_total"`
+	ChecksFailed         int       `json:"checks_failed"`
+	Outages              int       `json:"outages"`
+	LongestOutageSeconds float64   `json:"longest_outage_seconds"`
+	MTTRSeconds          float64   `json:"mttr_seconds"` // mean over outages resolved within the range
+}
+
+// QueryUptime computes an uptime summary for a device over timeRange ending
+// at end.
+func (s *PulseStore) QueryUptime(ctx context.Context, deviceID, timeRange string, end time.Time) (*UptimeSummary, error) {
+	duration, ok := validRanges[timeRange]
+	if !ok {
+		return nil, fmt.Errorf("unknown range %q: must be 1h, 6h, 24h, 7d, or 30d", timeRange)
+	}
+	end = end.UTC()
+	start := end.Add(-duration)
+
+	sum := &UptimeSummary{
+		DeviceID: deviceID,
+		Range:    timeRange,
+		Start:    start,
+		End:      end,
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.check_id, r.success, r.checked_at, COALESCE(c.interval_seconds, 0)
+		FROM pulse_check_results r
+		LEFT JOIN pulse_checks c ON c.id = r.check_id
+		WHERE r.device_id = ? AND r.checked_at >= ? AND r.checked_at < ?
+		ORDER BY r.check_id, r.checked_at ASC`,
+		deviceID, start, end,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query uptime results: %w", err)
+	}
+
+	var (
+		prevCheck   string
+		prevAt      time.Time
+		prevSuccess bool
+		prevSpan    time.Duration
+		monitored   time.Duration
+		downtime    time.Duration
+	)
+	// settle credits the previous result with the time until next, capped
+	// at its check interval.
+	settle := func(next time.Time) {
+		if prevCheck == "" {
+			return
+		}
+		span := min(next.Sub(prevAt), prevSpan)
+		monitored += span
+		if !prevSuccess {
+			downtime += span
+		}
+	}
+	for rows.Next() {
+		var checkID string
+		var successInt, intervalSec int
+		var checkedAt time.Time
+		if err := rows.Scan(&checkID, &successInt, &checkedAt, &intervalSec); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan uptime result: %w", err)
+		}
+		if checkID == prevCheck {
+			settle(checkedAt)
+		} else {
+			settle(end)
+		}
+
+		prevCheck = checkID
+		prevAt = checkedAt
+		prevSuccess = successInt == 1
+		prevSpan = defaultResultSpan
+		if intervalSec > 0 {
+			prevSpan = time.Duration(intervalSec) * time.Second
+		}
+		sum.ChecksTotal++
+		if !prevSuccess {
+			sum.ChecksFailed++
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate uptime results: %w", err)
+	}
+	settle(end)
+
+	sum.MonitoredSeconds = monitored.Seconds()
+	sum.DowntimeSeconds = downtime.Seconds()
+	if monitored > 0 {
+		pct := float64(monitored-downtime) * 100 / float64(monitored)
+		sum.UptimePercent = &pct
+	}
+
+	// Outages: every alert active during the range, clipped to the range.
+	// MTTR uses the full triggered-to-resolved time of resolved alerts.
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT triggered_at, resolved_at FROM pulse_alerts
+		WHERE device_id = ? AND triggered_at < ? AND (resolved_at IS NULL OR resolved_at >= ?)`,
+		deviceID, end, start,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query uptime alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var longest, repairTotal time.Duration
+	var repaired int
+	for rows.Next() {
+		var triggeredAt time.Time
+		var resolvedAt sql.NullTime
+		if err := rows.Scan(&triggeredAt, &resolvedAt); err != nil {
+			return nil, fmt.Errorf("scan uptime alert: %w", err)
+		}
+		sum.Outages++
+
+		outageEnd := end
+		if resolvedAt.Valid {
+			if resolvedAt.Time.Before(end) {
+				outageEnd = resolvedAt.Time
+			}
+			repairTotal += resolvedAt.Time.Sub(triggeredAt)
+			repaired++
+		}
+		outageStart := triggeredAt
+		if outageStart.Before(start) {
+			outageStart = start
+		}
+		longest = max(longest, outageEnd.Sub(outageStart))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate uptime alerts: %w", err)
+	}
+
+	sum.LongestOutageSeconds = longest.Seconds()
+	if repaired > 0 {
+		sum.MTTRSeconds = (repairTotal / time.Duration(repaired)).Seconds()
+	}
+	return sum, nil
+}
+
+// handleDeviceUptime returns an availability summary for a device.
+//
+//	@Summary		Device uptime summary
+//	@Description	Returns uptime percentage, downtime, outage count, longest outage, and MTTR for a device over a time range. Periods with no check results are excluded rather than counted as downtime.
+//	@Tags			pulse
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			device_id path string true "Device ID"
+//	@Param			range query string false "Time range" Enums(1h, 6h, 24h, 7d, 30d) default(30d)
+//	@Success		200 {object} UptimeSummary
+//	@Failure		400 {object} map[string]any
+//	@Failure		500 {object} map[string]any
+//	@Router			/pulse/uptime/{device_id} [get]
+func (m *Module) handleDeviceUptime(w http.ResponseWriter, r *http.Request) {
+	if m.store == nil {
+		pulseWriteError(w, http.StatusServiceUnavailable, "pulse store not available")
+		return
+	}
+
+	deviceID := r.PathValue("device_id")
+	if deviceID == "" {
+		pulseWriteError(w, http.StatusBadRequest, "device_id is required")
+		return
+	}
+
+	timeRange := r.URL.Query().Get("range")
+	if timeRange == "" {
+		timeRange = "30d"
+	}
+	if _, ok := validRanges[timeRange]; !ok {
+		pulseWriteError(w, http.StatusBadRequest, "range must be 1h, 6h, 24h, 7d, or 30d")
+		return
+	}
+
+	sum, err := m.store.QueryUptime(r.Context(), deviceID, timeRange, time.Now())
+	if err != nil {
+		m.logger.Warn("failed to query uptime",
+			zap.String("device_id", deviceID),
+			zap.String("range", timeRange),
+			zap.Error(err),
+		)
+		pulseWriteError(w, http.StatusInternalServerError, "failed to query uptime")
+		return
+	}
+
+	pulseWriteJSON(w, http.StatusOK, sum)
+}
@@ -0,0 +1,120 @@
+package pulse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQueryUptime_KnownSequence(t *testing.T) {
+	_, ps := newTestModule(t)
+	ctx := context.Background()
+
+	// 60s interval check: 10 results, two failures, a one-hour gap with no
+	// data, then 10 more successes. The gap must not count as downtime.
+	check := makeTestCheck(t, ps, "dev-u", "icmp", "10.0.0.1")
+	base := time.Now().UTC().Add(-2 * time.Hour).Truncate(time.Minute)
+	insert := func(minute int, success bool) {
+		t.Helper()
+		if err := ps.InsertResult(ctx, &CheckResult{
+			CheckID:   check.ID,
+			DeviceID:  "dev-u",
+			Success:   success,
+			CheckedAt: base.Add(time.Duration(minute) * time.Minute),
+		}); err != nil {
+			t.Fatalf("InsertResult: %v", err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		insert(i, i != 4 && i != 5)
+	}
+	for i := 70; i < 80; i++ {
+		insert(i, true)
+	}
+
+	for _, a := range []struct {
+		id       string
+		from, to int
+	}{
+		{"alert-u1", 4, 6},
+		{"alert-u2", 30, 40},
+	} {
+		if err := ps.InsertAlert(ctx, &Alert{
+			ID: a.id, CheckID: check.ID, DeviceID: "dev-u", Severity: "warning",
+			Message: "down", TriggeredAt: base.Add(time.Duration(a.from) * time.Minute), ConsecutiveFailures: 2,
+		}); err != nil {
+			t.Fatalf("InsertAlert: %v", err)
+		}
+		if err := ps.ResolveAlert(ctx, a.id, base.Add(time.Duration(a.to)*time.Minute)); err != nil {
+			t.Fatalf("ResolveAlert: %v", err)
+		}
+	}
+
+	sum, err := ps.QueryUptime(ctx, "dev-u", "24h", base.Add(80*time.Minute))
+	if err != nil {
+		t.Fatalf("QueryUptime: %v", err)
+	}
+
+	if sum.UptimePercent == nil {
+		t.Fatal("UptimePercent = nil, want 90")
+	}
+	if got := *sum.UptimePercent; got < 89.999 || got > 90.001 {
+		t.Errorf("UptimePercent = %.3f, want 90", got)
+	}
+	if sum.MonitoredSeconds != 1200 || sum.DowntimeSeconds != 120 {
+		t.Errorf("monitored/downtime = %v/%v, want 1200/120", sum.MonitoredSeconds, sum.DowntimeSeconds)
+	}
+	if sum.ChecksTotal != 20 || sum.ChecksFailed != 2 {
+		t.Errorf("checks total/failed = %d/%d, want 20/2", sum.ChecksTotal, sum.ChecksFailed)
+	}
+	if sum.Outages != 2 {
+		t.Errorf("Outages = %d, want 2", sum.Outages)
+	}
+	if sum.LongestOutageSeconds != 600 {
+		t.Errorf("LongestOutageSeconds = %v, want 600", sum.LongestOutageSeconds)
+	}
+	if sum.MTTRSeconds != 360 {
+		t.Errorf("MTTRSeconds = %v, want 360", sum.MTTRSeconds)
+	}
+}
+
+func TestHandleDeviceUptime(t *testing.T) {
+	m, ps := newTestModule(t)
+	makeTestCheck(t, ps, "dev-1", "icmp", "10.0.0.1")
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{"default range", "/uptime/dev-1", http.StatusOK},
+		{"explicit range", "/uptime/dev-1?range=7d", http.StatusOK},
+		{"invalid range", "/uptime/dev-1?range=2w", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, http.NoBody)
+			req.SetPathValue("device_id", "dev-1")
+			w := httptest.NewRecorder()
+
+			m.handleDeviceUptime(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var sum UptimeSummary
+			if err := json.NewDecoder(w.Body).Decode(&sum); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if sum.DeviceID != "dev-1" || sum.UptimePercent != nil || sum.ChecksTotal != 0 {
+				t.Errorf("summary = %+v, want empty summary for dev-1", sum)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package recon
+
+import (
+	"context"
+	"errors"
+)
+
+// errNoCredentialResolver is returned when an authenticated collector runs
+// before a credential resolver has been wired (no vault module).
+var errNoCredentialResolver = errors.New("no credential resolver configured")
+
+// CollectorCredential holds the secret material an authenticated inventory
+// collector presents to its API. What the fields mean is up to the
+// collector: for Proxmox, Username is the API token ID and Secret the token
+// secret.
+type CollectorCredential struct {
+	Username string
+	Secret   string
+}
+
+// CollectorCredentialResolver looks up a collector credential by its vault
+// credential ID. Collectors call it each time they authenticate a request
+// so secrets are never kept in config or collector state.
+// Defined here (consumer-side) to avoid importing the vault package.
+type CollectorCredentialResolver interface {
+	ResolveCollectorCredential(ctx context.Context, credentialID string) (*CollectorCredential, error)
+}
+
+// staticCredential resolves every credential ID to itself. It backs
+// requests that carry an API token inline instead of a vault reference.
+type staticCredential CollectorCredential
+
+func (s staticCredential) ResolveCollectorCredential(context.Context, string) (*CollectorCredential, error) {
+	cred := CollectorCredential(s)
+	return &cred, nil
+}
+
+// ResolveCollectorCredential implements CollectorCredentialResolver by
+// delegating to the resolver set with SetCollectorCredentialResolver. The
+// indirection lets collectors built during Init pick up a resolver wired
+// after they have started polling.
+func (m *Module) ResolveCollectorCredential(ctx context.Context, credentialID string) (*CollectorCredential, error) {
+	m.collectorCredsMu.RLock()
+	r := m.collectorCreds
+	m.collectorCredsMu.RUnlock()
+	if r == nil {
+		return nil, errNoCredentialResolver
+	}
+	return r.ResolveCollectorCredential(ctx, credentialID)
+}
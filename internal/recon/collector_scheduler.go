@@ -0,0 +1,242 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+// PolledCollector is an inventory source that the CollectorScheduler polls
+// on an interval (Proxmox, VMware, Docker, ...). Collect returns the devices
+// observed in this run; collectors that maintain their own device records
+// (such as Proxmox, which links guests to a host) may return nil.
+type PolledCollector interface {
+	Name() string
+	DiscoveryMethod() models.DiscoveryMethod
+	Collect(ctx context.Context) ([]models.Device, error)
+}
+
+// CollectorRunResult summarises one poll of a collector.
+type CollectorRunResult struct {
+	Collector string `json:"collector"`
+	Devices   int    `json:"devices"`
+	Created   int    `json:"created"`
+	Updated   int    `json:"updated"`
+	Skipped   int    `json:"skipped"`
+}
+
+// defaultCollectorInterval is used when a collector is registered without an interval.
+const defaultCollectorInterval = 15 * time.Minute
+
+type scheduledCollector struct {
+	collector PolledCollector
+	interval  time.Duration
+}
+
+// CollectorScheduler runs registered collectors on their own intervals and
+// merges the results into the recon inventory. A semaphore bounds how many
+// collectors poll at once, and each collector runs independently so that one
+// failing source does not delay or stop the others.
+type CollectorScheduler struct {
+	store  *ReconStore
+	bus    plugin.EventBus
+	logger *zap.Logger
+	sem    chan struct{}
+
+	mu   sync.Mutex
+	jobs []scheduledCollector
+}
+
+// NewCollectorScheduler creates a scheduler that polls at most maxConcurrent
+// collectors at the same time. A maxConcurrent below 1 is treated as 1.
+func NewCollectorScheduler(store *ReconStore, bus plugin.EventBus, maxConcurrent int, logger *zap.Logger) *CollectorScheduler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	return &CollectorScheduler{
+		store:  store,
+		bus:    bus,
+		logger: logger,
+		sem:    make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Register adds a collector to be polled every interval. Collectors must be
+// registered before Run is called.
+func (s *CollectorScheduler) Register(c PolledCollector, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultCollectorInterval
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs = append(s.jobs, scheduledCollector{collector: c, interval: interval})
+}
+
+// Len returns the number of registered collectors.
+func (s *CollectorScheduler) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.jobs)
+}
+
+// Run polls every registered collector immediately and then on its interval.
+// It blocks until ctx is cancelled and all in-flight polls have returned.
+func (s *CollectorScheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	jobs := make([]scheduledCollector, len(s.jobs))
+	copy(jobs, s.jobs)
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i := range jobs {
+		job := jobs[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.runLoop(ctx, job)
+		}()
+	}
+	s.logger.Info("collector scheduler started",
+		zap.Int("collectors", len(jobs)),
+		zap.Int("max_concurrent", cap(s.sem)),
+	)
+	wg.Wait()
+	s.logger.Info("collector scheduler stopped")
+}
+
+// runLoop polls one collector until ctx is cancelled. A poll that overruns
+// its interval delays the next one rather than overlapping with it.
+func (s *CollectorScheduler) runLoop(ctx context.Context, job scheduledCollector) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RunOnce(ctx, job.collector); err != nil && ctx.Err() == nil {
+			s.logger.Warn("collector poll failed",
+				zap.String("collector", job.collector.Name()),
+				zap.Error(err),
+			)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce polls a collector once, waiting for a concurrency slot, and merges
+// the returned devices into the inventory.
+func (s *CollectorScheduler) RunOnce(ctx context.Context, c PolledCollector) (result *CollectorRunResult, err error) {
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-s.sem }()
+
+	// A misbehaving collector must not take the scheduler down with it.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("collector %s panicked: %v", c.Name(), r)
+		}
+	}()
+
+	start := time.Now()
+	devices, err := c.Collect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("collect %s: %w", c.Name(), err)
+	}
+
+	result = s.merge(ctx, c, devices)
+	s.logger.Info("collector poll completed",
+		zap.String("collector", result.Collector),
+		zap.Int("devices", result.Devices),
+		zap.Int("created", result.Created),
+		zap.Int("updated", result.Updated),
+		zap.Int("skipped", result.Skipped),
+		zap.Duration("duration", time.Since(start)),
+	)
+	return result, nil
+}
+
+// merge upserts collected devices, stamping them with the collector's
+// discovery method, and publishes discovered/updated events.
+func (s *CollectorScheduler) merge(ctx context.Context, c PolledCollector, devices []models.Device) *CollectorRunResult {
+	result := &CollectorRunResult{Collector: c.Name(), Devices: len(devices)}
+	method := c.DiscoveryMethod()
+
+	for i := range devices {
+		dev := &devices[i]
+		// UpsertDevice matches on MAC or IP; without either every poll
+		// would create a duplicate record.
+		if dev.MACAddress == "" && len(dev.IPAddresses) == 0 {
+			s.logger.Debug("skipping collected device without MAC or IP",
+				zap.String("collector", result.Collector),
+				zap.String("hostname", dev.Hostname),
+			)
+			result.Skipped++
+			continue
+		}
+		dev.DiscoveryMethod = method
+		if dev.DeviceType == "" {
+			dev.DeviceType = models.DeviceTypeUnknown
+		}
+
+		created, err := s.store.UpsertDevice(ctx, dev)
+		if err != nil {
+			s.logger.Warn("failed to upsert collected device",
+				zap.String("collector", result.Collector),
+				zap.String("hostname", dev.Hostname),
+				zap.Error(err),
+			)
+			result.Skipped++
+			continue
+		}
+
+		topic := TopicDeviceUpdated
+		if created {
+			result.Created++
+			topic = TopicDeviceDiscovered
+		} else {
+			result.Updated++
+		}
+		if s.bus != nil {
+			s.bus.PublishAsync(ctx, plugin.Event{
+				Topic:     topic,
+				Source:    "recon",
+				Timestamp: time.Now(),
+				Payload:   &DeviceEvent{Device: dev},
+			})
+		}
+	}
+	return result
+}
+
+// proxmoxPolledCollector adapts a Proxmox host to the collector scheduler.
+// ProxmoxSyncer writes guest devices itself (linked to the host device), so
+// Collect returns no devices for the scheduler to merge.
+type proxmoxPolledCollector struct {
+	name         string
+	collector    *ProxmoxCollector
+	syncer       *ProxmoxSyncer
+	hostDeviceID string
+}
+
+func (p *proxmoxPolledCollector) Name() string { return p.name }
+
+func (p *proxmoxPolledCollector) DiscoveryMethod() models.DiscoveryMethod {
+	return models.DiscoveryProxmox
+}
+
+func (p *proxmoxPolledCollector) Collect(ctx context.Context) ([]models.Device, error) {
+	if _, err := p.syncer.Sync(ctx, p.collector, p.hostDeviceID); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,228 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// mockPolledCollector returns a fixed device list (or error) and records
+// how often and how concurrently it was polled.
+type mockPolledCollector struct {
+	name    string
+	method  models.DiscoveryMethod
+	devices []models.Device
+	err     error
+	panics  bool
+	delay   time.Duration
+
+	calls    atomic.Int32
+	inFlight *atomic.Int32
+	peak     *atomic.Int32
+}
+
+func (c *mockPolledCollector) Name() string                            { return c.name }
+func (c *mockPolledCollector) DiscoveryMethod() models.DiscoveryMethod { return c.method }
+
+func (c *mockPolledCollector) Collect(ctx context.Context) ([]models.Device, error) {
+	c.calls.Add(1)
+	if c.inFlight != nil {
+		n := c.inFlight.Add(1)
+		defer c.inFlight.Add(-1)
+		for {
+			p := c.peak.Load()
+			if n <= p || c.peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+	}
+	if c.delay > 0 {
+		select {
+		case <-time.After(c.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if c.panics {
+		panic("collector exploded")
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	// Return copies so the scheduler's mutations don't leak between polls.
+	out := make([]models.Device, len(c.devices))
+	copy(out, c.devices)
+	return out, nil
+}
+
+func waitForCalls(t *testing.T, c *mockPolledCollector, want int32) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for c.calls.Load() < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("collector %s called %d times, want at least %d", c.name, c.calls.Load(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestCollectorScheduler_InvokesOnInterval(t *testing.T) {
+	s := testStore(t)
+	sched := NewCollectorScheduler(s, &mockEventBus{}, 2, zap.NewNop())
+	c := &mockPolledCollector{name: "mock", method: models.DiscoveryProxmox}
+	sched.Register(c, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		sched.Run(ctx)
+		close(done)
+	}()
+
+	waitForCalls(t, c, 3)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run did not return after context cancellation")
+	}
+}
+
+func TestCollectorScheduler_MergesResults(t *testing.T) {
+	s := testStore(t)
+	bus := &mockEventBus{}
+	sched := NewCollectorScheduler(s, bus, 1, zap.NewNop())
+	c := &mockPolledCollector{
+		name:   "mock",
+		method: models.DiscoveryProxmox,
+		devices: []models.Device{
+			{Hostname: "vm-1", IPAddresses: []string{"10.0.0.11"}, DeviceType: models.DeviceTypeVM},
+			{Hostname: "vm-2", MACAddress: "AA:BB:CC:00:00:02"},
+			{Hostname: "no-address"},
+		},
+	}
+	ctx := context.Background()
+
+	result, err := sched.RunOnce(ctx, c)
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if result.Created != 2 || result.Updated != 0 || result.Skipped != 1 {
+		t.Errorf("first run = %+v, want 2 created, 0 updated, 1 skipped", result)
+	}
+
+	result, err = sched.RunOnce(ctx, c)
+	if err != nil {
+		t.Fatalf("RunOnce (second): %v", err)
+	}
+	if result.Created != 0 || result.Updated != 2 {
+		t.Errorf("second run = %+v, want 0 created, 2 updated", result)
+	}
+
+	dev, err := s.GetDeviceByIP(ctx, "10.0.0.11")
+	if err != nil {
+		t.Fatalf("GetDeviceByIP: %v", err)
+	}
+	if dev == nil {
+		t.Fatal("collected device not found in inventory")
+	}
+	if dev.DiscoveryMethod != models.DiscoveryProxmox {
+		t.Errorf("DiscoveryMethod = %q, want %q", dev.DiscoveryMethod, models.DiscoveryProxmox)
+	}
+	if dev.DeviceType != models.DeviceTypeVM {
+		t.Errorf("DeviceType = %q, want %q", dev.DeviceType, models.DeviceTypeVM)
+	}
+
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	var discovered, updated int
+	for _, e := range bus.events {
+		switch e.Topic {
+		case TopicDeviceDiscovered:
+			discovered++
+		case TopicDeviceUpdated:
+			updated++
+		}
+	}
+	if discovered != 2 || updated != 2 {
+		t.Errorf("events discovered=%d updated=%d, want 2 and 2", discovered, updated)
+	}
+}
+
+func TestCollectorScheduler_FailureDoesNotStopOthers(t *testing.T) {
+	s := testStore(t)
+	sched := NewCollectorScheduler(s, nil, 2, zap.NewNop())
+	failing := &mockPolledCollector{name: "failing", method: models.DiscoveryProxmox, err: errors.New("connection refused")}
+	panicking := &mockPolledCollector{name: "panicking", method: models.DiscoveryProxmox, panics: true}
+	healthy := &mockPolledCollector{
+		name:    "healthy",
+		method:  models.DiscoveryProxmox,
+		devices: []models.Device{{Hostname: "vm-1", IPAddresses: []string{"10.0.0.21"}}},
+	}
+	sched.Register(failing, 10*time.Millisecond)
+	sched.Register(panicking, 10*time.Millisecond)
+	sched.Register(healthy, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sched.Run(ctx)
+	}()
+
+	waitForCalls(t, failing, 2)
+	waitForCalls(t, panicking, 2)
+	waitForCalls(t, healthy, 2)
+	cancel()
+	wg.Wait()
+
+	dev, err := s.GetDeviceByIP(context.Background(), "10.0.0.21")
+	if err != nil {
+		t.Fatalf("GetDeviceByIP: %v", err)
+	}
+	if dev == nil {
+		t.Error("healthy collector's device not merged")
+	}
+}
+
+func TestCollectorScheduler_BoundsConcurrency(t *testing.T) {
+	s := testStore(t)
+	sched := NewCollectorScheduler(s, nil, 2, zap.NewNop())
+	var inFlight, peak atomic.Int32
+	collectors := make([]*mockPolledCollector, 5)
+	for i := range collectors {
+		collectors[i] = &mockPolledCollector{
+			name:     "cluster",
+			method:   models.DiscoveryProxmox,
+			delay:    20 * time.Millisecond,
+			inFlight: &inFlight,
+			peak:     &peak,
+		}
+		sched.Register(collectors[i], time.Hour)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sched.Run(ctx)
+	}()
+	for _, c := range collectors {
+		waitForCalls(t, c, 1)
+	}
+	cancel()
+	wg.Wait()
+
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrent polls = %d, want <= 2", got)
+	}
+}
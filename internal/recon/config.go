@@ -4,17 +4,85 @@ import "time"
 
 // ReconConfig holds the Recon module configuration.
 type ReconConfig struct {
-	ScanTimeout     time.Duration  `mapstructure:"scan_timeout"`
-	PingTimeout     time.Duration  `mapstructure:"ping_timeout"`
-	PingCount       int            `mapstructure:"ping_count"`
-	Concurrency     int            `mapstructure:"concurrency"`
-	ARPEnabled      bool           `mapstructure:"arp_enabled"`
-	DeviceLostAfter time.Duration  `mapstructure:"device_lost_after"`
-	MDNSEnabled     bool           `mapstructure:"mdns_enabled"`
-	MDNSInterval    time.Duration  `mapstructure:"mdns_interval"`
-	UPNPEnabled     bool           `mapstructure:"upnp_enabled"`
-	UPNPInterval    time.Duration  `mapstructure:"upnp_interval"`
-	Schedule        ScheduleConfig `mapstructure:"schedule"`
+	ScanTimeout     time.Duration        `mapstructure:"scan_timeout"`
+	PingTimeout     time.Duration        `mapstructure:"ping_timeout"`
+	PingCount       int                  `mapstructure:"ping_count"`
+	Concurrency     int                  `mapstructure:"concurrency"`
+	ARPEnabled      bool                 `mapstructure:"arp_enabled"`
+	DeviceLostAfter time.Duration        `mapstructure:"device_lost_after"`
+	MDNSEnabled     bool                 `mapstructure:"mdns_enabled"`
+	MDNSInterval    time.Duration        `mapstructure:"mdns_interval"`
+	MDNSTimeout     time.Duration        `mapstructure:"mdns_timeout"`
+	UPNPEnabled     bool                 `mapstructure:"upnp_enabled"`
+	UPNPInterval    time.Duration        `mapstructure:"upnp_interval"`
+	MaxTraceroutes  int                  `mapstructure:"max_traceroutes"`
+	PortScan        PortScanConfig       `mapstructure:"port_scan"`
+	Schedule        ScheduleConfig       `mapstructure:"schedule"`
+	Collectors      CollectorsConfig     `mapstructure:"collectors"`
+	OUIUpdate       OUIUpdateConfig      `mapstructure:"oui_update"`
+	HostnameLookup  HostnameLookupConfig `mapstructure:"hostname_lookup"`
+}
+
+// HostnameLookupConfig controls how names are found for discovered hosts
+// that ICMP and ARP leave unnamed.
+type HostnameLookupConfig struct {
+	ReverseDNS bool `mapstructure:"reverse_dns"`
+	// NetBIOS sends a node status query to UDP 137 when reverse DNS finds
+	// nothing, which names most Windows and Samba hosts.
+	NetBIOS bool `mapstructure:"netbios"`
+	// Timeout bounds the whole lookup for one host.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// OUIUpdateConfig controls refreshing the vendor table from the IEEE
+// OUI registry.
+type OUIUpdateConfig struct {
+	// Enabled turns on the scheduled refresh. Manual updates through the
+	// API are always available.
+	Enabled  bool          `mapstructure:"enabled"`
+	Interval time.Duration `mapstructure:"interval"`
+	URL      string        `mapstructure:"url"`
+	// CachePath, when set, stores the last downloaded registry so it is
+	// reloaded on startup without a network fetch.
+	CachePath string `mapstructure:"cache_path"`
+}
+
+// PortScanConfig controls the optional TCP port scan of discovered devices.
+type PortScanConfig struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Ports       []int         `mapstructure:"ports"`
+	Timeout     time.Duration `mapstructure:"timeout"`
+	Concurrency int           `mapstructure:"concurrency"`
+}
+
+// CollectorsConfig holds configuration for periodic inventory collectors.
+type CollectorsConfig struct {
+	MaxConcurrent int                 `mapstructure:"max_concurrent"`
+	Proxmox       []ProxmoxPollConfig `mapstructure:"proxmox"`
+}
+
+// ProxmoxPollConfig configures periodic sync of one Proxmox VE host.
+type ProxmoxPollConfig struct {
+	Name         string        `mapstructure:"name"`
+	Enabled      bool          `mapstructure:"enabled"`
+	Interval     time.Duration `mapstructure:"interval"`
+	BaseURL      string        `mapstructure:"base_url"`
+	HostDeviceID string        `mapstructure:"host_device_id"`
+
+	// CredentialID references the vault credential holding the API token
+	// (an http_basic credential with the token ID as username and the
+	// secret as password). The secret is fetched from the vault on use.
+	CredentialID string `mapstructure:"credential_id"`
+
+	// Deprecated: TokenID and TokenSecret keep the API token in the config
+	// file. They are only used when CredentialID is empty.
+	TokenID     string `mapstructure:"token_id"`
+	TokenSecret string `mapstructure:"token_secret"` //nolint:gosec // G101: field name, not a credential
+
+	// MaxRetries and RetryBackoff control retries of failed API calls.
+	// Zero values keep the defaults (2 retries, 250ms initial backoff).
+	MaxRetries   int           `mapstructure:"max_retries"`
+	RetryBackoff time.Duration `mapstructure:"retry_backoff"`
 }
 
 // ScheduleConfig holds configuration for recurring scheduled scans.
@@ -37,11 +105,32 @@ func DefaultConfig() ReconConfig {
 		DeviceLostAfter: 24 * time.Hour,
 		MDNSEnabled:     true,
 		MDNSInterval:    60 * time.Second,
+		MDNSTimeout:     3 * time.Second,
 		UPNPEnabled:     true,
 		UPNPInterval:    5 * time.Minute,
+		MaxTraceroutes:  4,
+		PortScan: PortScanConfig{
+			Enabled:     false,
+			Ports:       DefaultDevicePorts(),
+			Timeout:     time.Second,
+			Concurrency: 32,
+		},
 		Schedule: ScheduleConfig{
 			Enabled:  false,
 			Interval: time.Hour,
 		},
+		Collectors: CollectorsConfig{
+			MaxConcurrent: 2,
+		},
+		HostnameLookup: HostnameLookupConfig{
+			ReverseDNS: true,
+			NetBIOS:    true,
+			Timeout:    500 * time.Millisecond,
+		},
+		OUIUpdate: OUIUpdateConfig{
+			Enabled:  false,
+			Interval: 7 * 24 * time.Hour,
+			URL:      DefaultOUIRegistryURL,
+		},
 	}
 }
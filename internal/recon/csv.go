@@ -2,6 +2,7 @@ package recon
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,7 +15,7 @@ func csvHeaders() []string {
 		"id", "hostname", "ip_addresses", "mac_address", "manufacturer",
 		"device_type", "os", "status", "discovery_method", "last_seen",
 		"first_seen", "notes", "tags", "location", "category",
-		"primary_role", "owner",
+		"primary_role", "owner", "rack", "rack_unit",
 	}
 }
 
@@ -38,10 +39,22 @@ func deviceToCSVRow(d models.Device) []string {
 		d.Category,
 		d.PrimaryRole,
 		d.Owner,
+		d.Rack,
+		rackUnitCSV(d.RackUnit),
 	}
 }
 
-// csvColumnCount is the number of columns in the CSV format.
+// rackUnitCSV formats a rack unit for CSV, leaving it blank when unset.
+func rackUnitCSV(u int) string {
+	if u == 0 {
+		return ""
+	}
+	return strconv.Itoa(u)
+}
+
+// csvColumnCount is the number of required columns in the CSV format. The
+// rack and rack_unit columns that follow are optional so files written
+// before they were added still parse.
 const csvColumnCount = 17
 
 // csvRowToDevice parses a CSV row into a Device. Returns error for invalid data.
@@ -94,5 +107,16 @@ func csvRowToDevice(row []string) (models.Device, error) {
 	d.PrimaryRole = r[15]
 	d.Owner = r[16]
 
+	if len(row) > csvColumnCount {
+		d.Rack = row[csvColumnCount]
+	}
+	if len(row) > csvColumnCount+1 && row[csvColumnCount+1] != "" {
+		u, err := strconv.Atoi(row[csvColumnCount+1])
+		if err != nil || u < 0 {
+			return models.Device{}, fmt.Errorf("invalid rack_unit %q", row[csvColumnCount+1])
+		}
+		d.RackUnit = u
+	}
+
 	return d, nil
 }
@@ -111,6 +111,26 @@ func TestCSVRowToDevice_SemicolonSeparatedIPsAndTags(t *testing.T) {
 	}
 }
 
+func TestCSVRowToDevice_RackColumns(t *testing.T) {
+	d := models.Device{
+		ID: "abc-123", IPAddresses: []string{"10.0.0.5"},
+		Location: "DC1", Rack: "A3", RackUnit: 12,
+	}
+	got, err := csvRowToDevice(deviceToCSVRow(d))
+	if err != nil {
+		t.Fatalf("csvRowToDevice: %v", err)
+	}
+	if got.Location != "DC1" || got.Rack != "A3" || got.RackUnit != 12 {
+		t.Errorf("location/rack/unit = %q/%q/%d, want DC1/A3/12", got.Location, got.Rack, got.RackUnit)
+	}
+
+	row := deviceToCSVRow(d)
+	row[len(row)-1] = "top"
+	if _, err := csvRowToDevice(row); err == nil {
+		t.Error("expected error for non-numeric rack_unit")
+	}
+}
+
 func TestCSVRowToDevice_TooFewColumns(t *testing.T) {
 	row := []string{"id-only", "hostname"}
 
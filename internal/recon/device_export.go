@@ -0,0 +1,188 @@
+package recon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// exportPageSize is the number of devices fetched per repository query
+// while streaming an export. It matches the repository's maximum page size;
+// tests lower it to exercise paging.
+var exportPageSize = 1000
+
+// exportFilter builds the device filter for an export from the query
+// string. Tags may be given as repeated tag parameters or comma-separated.
+func exportFilter(r *http.Request) services.DeviceFilter {
+	q := r.URL.Query()
+	filter := services.DeviceFilter{
+		Status:     q.Get("status"),
+		DeviceType: q.Get("type"),
+		Search:     q.Get("search"),
+		ScanID:     q.Get("scan_id"),
+
+		IncludeArchived: q.Get("include_archived") == "true",
+	}
+	for _, v := range q["tag"] {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	return filter
+}
+
+// deviceExportWriter writes devices in one export format. begin and end
+// bracket the output, write is called once per device, and flush pushes
+// buffered rows to the response between pages.
+type deviceExportWriter interface {
+	begin() error
+	write(d *models.Device) error
+	flush()
+	end() error
+}
+
+// csvExportWriter writes the same columns that POST /devices/import reads.
+type csvExportWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvExportWriter) begin() error { return c.w.Write(csvHeaders()) }
+
+func (c *csvExportWriter) write(d *models.Device) error { return c.w.Write(deviceToCSVRow(*d)) }
+
+func (c *csvExportWriter) flush() { c.w.Flush() }
+
+func (c *csvExportWriter) end() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonExportWriter writes a plain JSON array of devices, one element at a
+// time so the whole array is never held in memory.
+type jsonExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (j *jsonExportWriter) begin() error {
+	_, err := io.WriteString(j.w, "[")
+	return err
+}
+
+func (j *jsonExportWriter) write(d *models.Device) error {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ",\n"); err != nil {
+			return err
+		}
+	}
+	j.count++
+	_, err = j.w.Write(b)
+	return err
+}
+
+func (j *jsonExportWriter) flush() {}
+
+func (j *jsonExportWriter) end() error {
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
+
+// handleExportDevices streams the device inventory as CSV or JSON.
+//
+//	@Summary		Export devices
+//	@Description	Downloads all devices matching the filters as CSV (the format POST /recon/devices/import accepts) or as a JSON array. The export is streamed page by page, so large inventories are not buffered in memory.
+//	@Tags			recon
+//	@Produce		text/csv
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			format	query		string	false	"Export format: csv or json"	default(csv)
+//	@Param			status	query		string	false	"Filter by status"
+//	@Param			type	query		string	false	"Filter by device type"
+//	@Param			search	query		string	false	"Search hostname, IP addresses, or MAC address"
+//	@Param			scan_id	query		string	false	"Filter to devices found by a scan"
+//	@Param			tag		query		string	false	"Filter to devices with this tag (repeatable or comma-separated)"
+//	@Param			include_archived	query	bool	false	"Include archived devices"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/export [get]
+func (m *Module) handleExportDevices(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+
+	var out deviceExportWriter
+	switch format {
+	case "csv":
+		out = &csvExportWriter{w: csv.NewWriter(w)}
+	case "json":
+		out = &jsonExportWriter{w: w}
+	default:
+		writeError(w, http.StatusBadRequest, "format must be csv or json")
+		return
+	}
+
+	filter := exportFilter(r)
+	opts := services.ListOptions{Limit: exportPageSize, SortBy: "hostname", SortOrder: "asc"}
+
+	// Fetch the first page before writing anything so a query failure can
+	// still be reported with a proper status code.
+	page, err := m.devices.List(r.Context(), filter, opts)
+	if err != nil {
+		m.logger.Error("failed to list devices for export", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to export devices")
+		return
+	}
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		w.Header().Set("Content-Type", "text/csv")
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="subnetree-devices.`+format+`"`)
+	rc := http.NewResponseController(w)
+
+	if err := out.begin(); err != nil {
+		m.logger.Debug("device export aborted", zap.Error(err))
+		return
+	}
+	for {
+		for i := range page.Items {
+			if err := out.write(&page.Items[i]); err != nil {
+				m.logger.Debug("device export aborted", zap.Error(err))
+				return
+			}
+		}
+		opts.Offset += len(page.Items)
+		if len(page.Items) < opts.Limit || opts.Offset >= page.Total {
+			break
+		}
+		out.flush()
+		// Writers that cannot flush still receive the export, buffered.
+		_ = rc.Flush()
+
+		// Headers are already sent, so a failure here can only truncate
+		// the download.
+		page, err = m.devices.List(r.Context(), filter, opts)
+		if err != nil {
+			m.logger.Error("device export truncated", zap.Int("written", opts.Offset), zap.Error(err))
+			return
+		}
+	}
+	if err := out.end(); err != nil {
+		m.logger.Debug("device export aborted", zap.Error(err))
+	}
+}
@@ -0,0 +1,183 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+func getExport(t *testing.T, m *Module, query string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/devices/export?"+query, http.NoBody)
+	rr := httptest.NewRecorder()
+	m.handleExportDevices(rr, req)
+	return rr
+}
+
+// seedExportDevices creates five devices with a mix of statuses, types,
+// and tags.
+func seedExportDevices(t *testing.T, m *Module) {
+	t.Helper()
+	devices := []models.Device{
+		{Hostname: "core-switch", IPAddresses: []string{"10.0.0.1"}, DeviceType: models.DeviceTypeSwitch, Status: models.DeviceStatusOnline, Tags: []string{"rack-1", "core"}},
+		{Hostname: "nas", IPAddresses: []string{"10.0.0.20", "10.0.1.20"}, DeviceType: models.DeviceTypeNAS, Status: models.DeviceStatusOnline, Tags: []string{"rack-1"}},
+		{Hostname: "printer", IPAddresses: []string{"10.0.0.30"}, DeviceType: models.DeviceTypePrinter, Status: models.DeviceStatusOffline},
+		{Hostname: "ap-upstairs", IPAddresses: []string{"10.0.0.40"}, DeviceType: models.DeviceTypeAccessPoint, Status: models.DeviceStatusOnline, Tags: []string{"wifi"}},
+		{Hostname: "backup-server", IPAddresses: []string{"10.0.0.50"}, DeviceType: models.DeviceTypeServer, Status: models.DeviceStatusDegraded, Tags: []string{"rack-1"}, Notes: "line one, \"quoted\"\nline two"},
+	}
+	for i := range devices {
+		devices[i].DiscoveryMethod = models.DiscoveryManual
+		if err := m.devices.Create(context.Background(), &devices[i]); err != nil {
+			t.Fatalf("Create %s: %v", devices[i].Hostname, err)
+		}
+	}
+}
+
+func exportHostnames(devices []models.Device) string {
+	names := make([]string, len(devices))
+	for i := range devices {
+		names[i] = devices[i].Hostname
+	}
+	return strings.Join(names, ",")
+}
+
+func TestHandleExportDevices_CSV(t *testing.T) {
+	m := newTestModule(t)
+	seedExportDevices(t, m)
+
+	rr := getExport(t, m, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("Content-Type = %q, want text/csv", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); !strings.Contains(cd, "subnetree-devices.csv") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	header, _, _ := strings.Cut(rr.Body.String(), "\n")
+	if header != strings.Join(csvHeaders(), ",") {
+		t.Errorf("header = %q", header)
+	}
+
+	// The export must be readable by the importer.
+	rows, err := parseImportCSV(strings.NewReader(rr.Body.String()))
+	if err != nil {
+		t.Fatalf("parseImportCSV: %v", err)
+	}
+	got := make([]models.Device, 0, len(rows))
+	for _, row := range rows {
+		if row.err != nil {
+			t.Fatalf("row %d: %v", row.row, row.err)
+		}
+		got = append(got, row.device)
+	}
+	if names := exportHostnames(got); names != "ap-upstairs,backup-server,core-switch,nas,printer" {
+		t.Errorf("hostnames = %s, want all devices sorted by hostname", names)
+	}
+	nas := got[3]
+	if strings.Join(nas.IPAddresses, ";") != "10.0.0.20;10.0.1.20" {
+		t.Errorf("nas IPs = %v", nas.IPAddresses)
+	}
+	if got[1].Notes != "line one, \"quoted\"\nline two" {
+		t.Errorf("notes not escaped correctly: %q", got[1].Notes)
+	}
+	if strings.Join(got[2].Tags, ";") != "rack-1;core" {
+		t.Errorf("core-switch tags = %v", got[2].Tags)
+	}
+}
+
+func TestHandleExportDevices_JSON(t *testing.T) {
+	m := newTestModule(t)
+	seedExportDevices(t, m)
+
+	// Force several pages, including a final partial one.
+	orig := exportPageSize
+	exportPageSize = 2
+	t.Cleanup(func() { exportPageSize = orig })
+
+	rr := getExport(t, m, "format=json")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+
+	var got []models.Device
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v\n%s", err, rr.Body.String())
+	}
+	if names := exportHostnames(got); names != "ap-upstairs,backup-server,core-switch,nas,printer" {
+		t.Errorf("hostnames = %s, want every device exactly once", names)
+	}
+	if got[0].ID == "" || got[0].DiscoveryMethod != models.DiscoveryManual {
+		t.Errorf("device fields missing: %+v", got[0])
+	}
+}
+
+func TestHandleExportDevices_EmptyJSON(t *testing.T) {
+	m := newTestModule(t)
+
+	rr := getExport(t, m, "format=json")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rr.Code)
+	}
+	if body := strings.TrimSpace(rr.Body.String()); body != "[]" {
+		t.Errorf("body = %q, want []", body)
+	}
+}
+
+func TestHandleExportDevices_Filters(t *testing.T) {
+	m := newTestModule(t)
+	seedExportDevices(t, m)
+
+	orig := exportPageSize
+	exportPageSize = 1
+	t.Cleanup(func() { exportPageSize = orig })
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "status", query: "status=online", want: "ap-upstairs,core-switch,nas"},
+		{name: "type", query: "type=printer", want: "printer"},
+		{name: "search", query: "search=10.0.1.", want: "nas"},
+		{name: "tag", query: "tag=rack-1", want: "backup-server,core-switch,nas"},
+		{name: "repeated tags", query: "tag=rack-1&tag=core", want: "core-switch"},
+		{name: "comma tags", query: "tag=rack-1,core", want: "core-switch"},
+		{name: "combined", query: "status=online&tag=rack-1", want: "core-switch,nas"},
+		{name: "no match", query: "status=unknown", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := getExport(t, m, "format=json&"+tt.query)
+			if rr.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rr.Code, rr.Body.String())
+			}
+			var got []models.Device
+			if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if names := exportHostnames(got); names != tt.want {
+				t.Errorf("hostnames = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleExportDevices_InvalidFormat(t *testing.T) {
+	m := newTestModule(t)
+
+	rr := getExport(t, m, "format=xml")
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rr.Code)
+	}
+}
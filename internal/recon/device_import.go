@@ -0,0 +1,302 @@
+package recon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// maxImportBytes caps the size of a device import upload.
+const maxImportBytes = 10 << 20
+
+// ImportResult contains the results of a device import.
+type ImportResult struct {
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"`
+	Errors  []ImportRowError `json:"errors,omitempty"`
+}
+
+// ImportRowError explains why one row of an import was skipped. Row is
+// 1-indexed; for CSV the header is row 1, for JSON it is the array position.
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// importRow is one parsed device from an import file.
+type importRow struct {
+	row    int
+	device models.Device
+	err    error
+}
+
+// importFormat picks the parser for a request from its content type. For
+// multipart uploads, the file part's content type or extension decides.
+func importFormat(contentType, filename string) (string, error) {
+	if contentType == "" && filename == "" {
+		return "", errors.New("content type is required: use text/csv or application/json")
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil && filename == "" {
+		return "", fmt.Errorf("invalid content type %q", contentType)
+	}
+	switch mediaType {
+	case "text/csv", "application/csv":
+		return "csv", nil
+	case "application/json":
+		return "json", nil
+	}
+	if filename != "" {
+		if strings.EqualFold(filepath.Ext(filename), ".json") {
+			return "json", nil
+		}
+		return "csv", nil
+	}
+	return "", fmt.Errorf("unsupported content type %q: use text/csv or application/json", mediaType)
+}
+
+// parseImportCSV reads devices from CSV. Columns are matched by header name
+// (the same names GET /devices/export writes), so exports from other tools
+// only need renamed headers. Unknown columns are ignored.
+func parseImportCSV(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("failed to read CSV header")
+	}
+	byName := make(map[string]int, len(header))
+	for i, name := range header {
+		byName[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	_, hasIP := byName["ip_addresses"]
+	_, hasMAC := byName["mac_address"]
+	if !hasIP && !hasMAC {
+		return nil, errors.New("invalid CSV: header must include ip_addresses or mac_address")
+	}
+
+	// Map each export column to its position in this file.
+	cols := csvHeaders()
+	idx := make([]int, len(cols))
+	for i, name := range cols {
+		pos, ok := byName[name]
+		if !ok {
+			pos = -1
+		}
+		idx[i] = pos
+	}
+
+	var rows []importRow
+	rowNum := 1
+	for {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		rowNum++
+		if readErr != nil {
+			var parseErr *csv.ParseError
+			if errors.As(readErr, &parseErr) {
+				rows = append(rows, importRow{row: rowNum, err: parseErr.Err})
+				continue
+			}
+			return nil, fmt.Errorf("read CSV: %w", readErr)
+		}
+
+		aligned := make([]string, len(cols))
+		for i, pos := range idx {
+			if pos >= 0 && pos < len(record) {
+				aligned[i] = strings.TrimSpace(record[pos])
+			}
+		}
+		device, parseErr := csvRowToDevice(aligned)
+		rows = append(rows, importRow{row: rowNum, device: device, err: parseErr})
+	}
+	return rows, nil
+}
+
+// parseImportJSON reads devices from a JSON array of device objects in the
+// GET /devices shape. An element that does not decode is a row error.
+func parseImportJSON(r io.Reader) ([]importRow, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, errors.New("invalid JSON: expected an array of devices")
+	}
+	rows := make([]importRow, 0, len(raw))
+	for i, msg := range raw {
+		var device models.Device
+		err := json.Unmarshal(msg, &device)
+		rows = append(rows, importRow{row: i + 1, device: device, err: err})
+	}
+	return rows, nil
+}
+
+// validateImportDevice checks and normalizes the match keys of an imported
+// device. MACs are rewritten to upper-case colon form to match ARP results.
+func validateImportDevice(d *models.Device) error {
+	if d.MACAddress != "" {
+		hw, err := net.ParseMAC(d.MACAddress)
+		if err != nil {
+			return fmt.Errorf("invalid MAC address %q", d.MACAddress)
+		}
+		d.MACAddress = strings.ToUpper(hw.String())
+	}
+
+	ips := make([]string, 0, len(d.IPAddresses))
+	for _, ip := range d.IPAddresses {
+		ip = strings.TrimSpace(ip)
+		if ip == "" {
+			continue
+		}
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			return fmt.Errorf("invalid IP address %q", ip)
+		}
+		ips = append(ips, parsed.String())
+	}
+	d.IPAddresses = ips
+
+	if d.MACAddress == "" && len(d.IPAddresses) == 0 {
+		return errors.New("mac_address or ip_addresses is required")
+	}
+	return nil
+}
+
+// importDevices validates rows and upserts the valid ones. Rows repeating an
+// IP or MAC already used by an earlier row in the same file are reported
+// rather than merged, since that usually means the export is wrong.
+func (m *Module) importDevices(r *http.Request, rows []importRow) ImportResult {
+	result := ImportResult{}
+	skip := func(row int, err error) {
+		result.Skipped++
+		result.Errors = append(result.Errors, ImportRowError{Row: row, Error: err.Error()})
+	}
+
+	seenIP := make(map[string]int)
+	seenMAC := make(map[string]int)
+	for i := range rows {
+		row := &rows[i]
+		if row.err != nil {
+			skip(row.row, row.err)
+			continue
+		}
+		device := &row.device
+		if err := validateImportDevice(device); err != nil {
+			skip(row.row, err)
+			continue
+		}
+
+		if first, dup := seenMAC[device.MACAddress]; dup && device.MACAddress != "" {
+			skip(row.row, fmt.Errorf("duplicate MAC address %s (first seen in row %d)", device.MACAddress, first))
+			continue
+		}
+		var dupErr error
+		for _, ip := range device.IPAddresses {
+			if first, dup := seenIP[ip]; dup {
+				dupErr = fmt.Errorf("duplicate IP address %s (first seen in row %d)", ip, first)
+				break
+			}
+		}
+		if dupErr != nil {
+			skip(row.row, dupErr)
+			continue
+		}
+		if device.MACAddress != "" {
+			seenMAC[device.MACAddress] = row.row
+		}
+		for _, ip := range device.IPAddresses {
+			seenIP[ip] = row.row
+		}
+
+		// Imported rows carry inventory data, not discovery results.
+		device.ID = ""
+		if device.Status == "" {
+			device.Status = models.DeviceStatusUnknown
+		}
+		if device.DeviceType == "" {
+			device.DeviceType = models.DeviceTypeUnknown
+		}
+		if device.DiscoveryMethod == "" {
+			device.DiscoveryMethod = models.DiscoveryManual
+		}
+
+		created, err := m.store.UpsertDevice(r.Context(), device)
+		if err != nil {
+			m.logger.Error("failed to import device", zap.Int("row", row.row), zap.Error(err))
+			skip(row.row, errors.New("failed to save device"))
+			continue
+		}
+		if created {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+	return result
+}
+
+// handleImportDevices imports devices from a CSV or JSON file.
+//
+//	@Summary		Import devices
+//	@Description	Creates or updates devices from a CSV or JSON file. Send the file as the request body with Content-Type text/csv or application/json, or as the "file" field of a multipart form. CSV columns are matched by the header names used by GET /devices/export; JSON is an array of device objects. Existing devices are matched by MAC address, then IP address. Rows with a malformed MAC or IP, no MAC or IP, or an IP or MAC repeated from an earlier row are skipped and reported in errors.
+//	@Tags			recon
+//	@Accept			text/csv,json,multipart/form-data
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			file			formData	file	false	"CSV or JSON file (multipart uploads)"
+//	@Param			Idempotency-Key	header		string	false	"Repeats with the same key within 24h return the first import's result instead of importing again"
+//	@Success		200				{object}	ImportResult
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		415		{object}	models.APIProblem
+//	@Failure		422		{object}	models.APIProblem	"Idempotency-Key reused for a different request"
+//	@Router			/recon/devices/import [post]
+func (m *Module) handleImportDevices(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	body := io.Reader(r.Body)
+	contentType := r.Header.Get("Content-Type")
+	filename := ""
+	if strings.HasPrefix(contentType, "multipart/form-data") {
+		file, fh, err := r.FormFile("file")
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "missing or invalid file field")
+			return
+		}
+		defer file.Close()
+		body = file
+		contentType = fh.Header.Get("Content-Type")
+		filename = fh.Filename
+	}
+
+	format, err := importFormat(contentType, filename)
+	if err != nil {
+		writeError(w, http.StatusUnsupportedMediaType, err.Error())
+		return
+	}
+
+	var rows []importRow
+	if format == "json" {
+		rows, err = parseImportJSON(body)
+	} else {
+		rows, err = parseImportCSV(body)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m.importDevices(r, rows))
+}
@@ -0,0 +1,190 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+func postImport(t *testing.T, m *Module, contentType, body string) (*httptest.ResponseRecorder, ImportResult) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/devices/import", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rr := httptest.NewRecorder()
+	m.handleImportDevices(rr, req)
+
+	var result ImportResult
+	if rr.Code == http.StatusOK {
+		if err := json.NewDecoder(rr.Body).Decode(&result); err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+	}
+	return rr, result
+}
+
+// seedImportDevice creates the device the import files below should update.
+func seedImportDevice(t *testing.T, m *Module) *models.Device {
+	t.Helper()
+	dev := &models.Device{
+		Hostname:        "core-switch",
+		IPAddresses:     []string{"10.0.0.1"},
+		MACAddress:      "AA:BB:CC:00:00:01",
+		Status:          models.DeviceStatusOnline,
+		DeviceType:      models.DeviceTypeUnknown,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	if _, err := m.store.UpsertDevice(context.Background(), dev); err != nil {
+		t.Fatalf("UpsertDevice: %v", err)
+	}
+	return dev
+}
+
+func assertImportErrors(t *testing.T, got []ImportRowError, want map[int]string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("errors = %+v, want %d", got, len(want))
+	}
+	for _, e := range got {
+		substr, ok := want[e.Row]
+		if !ok {
+			t.Errorf("unexpected error for row %d: %s", e.Row, e.Error)
+			continue
+		}
+		if !strings.Contains(e.Error, substr) {
+			t.Errorf("row %d error = %q, want it to contain %q", e.Row, e.Error, substr)
+		}
+	}
+}
+
+func TestHandleImportDevices_CSV(t *testing.T) {
+	m := newTestModule(t)
+	existing := seedImportDevice(t, m)
+
+	// Columns in a different order than the export, plus one we don't know.
+	body := strings.Join([]string{
+		"hostname,mac_address,ip_addresses,rack,location",
+		"core-switch,aa-bb-cc-00-00-01,10.0.0.1;10.0.0.254,R1,closet", // matches by MAC
+		"nas,,10.0.0.20,R2,office",
+		"printer,ZZ:ZZ:ZZ:ZZ:ZZ:ZZ,10.0.0.30,,office", // bad MAC
+		"nas-dup,,10.0.0.20,,office",                  // duplicate IP
+		"nothing,,,,office",                           // no match keys
+	}, "\n")
+
+	rr, result := postImport(t, m, "text/csv", body)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Skipped != 3 {
+		t.Errorf("created/updated/skipped = %d/%d/%d, want 1/1/3", result.Created, result.Updated, result.Skipped)
+	}
+	assertImportErrors(t, result.Errors, map[int]string{
+		4: "invalid MAC address",
+		5: "duplicate IP address 10.0.0.20 (first seen in row 3)",
+		6: "mac_address or ip_addresses is required",
+	})
+
+	ctx := context.Background()
+	updated, err := m.store.GetDeviceByMAC(ctx, existing.MACAddress)
+	if err != nil || updated == nil {
+		t.Fatalf("GetDeviceByMAC: %v, %v", updated, err)
+	}
+	if updated.ID != existing.ID || updated.Location != "closet" || len(updated.IPAddresses) != 2 {
+		t.Errorf("updated device = %+v, want same ID, location closet, and two IPs", updated)
+	}
+	nas, err := m.store.GetDeviceByIP(ctx, "10.0.0.20")
+	if err != nil || nas == nil {
+		t.Fatalf("GetDeviceByIP: %v, %v", nas, err)
+	}
+	if nas.Hostname != "nas" || nas.DiscoveryMethod != models.DiscoveryManual {
+		t.Errorf("created device = %+v, want hostname nas discovered manually", nas)
+	}
+}
+
+func TestHandleImportDevices_JSON(t *testing.T) {
+	m := newTestModule(t)
+	existing := seedImportDevice(t, m)
+
+	body := `[
+		{"hostname": "core-switch-renamed", "ip_addresses": ["10.0.0.1"]},
+		{"hostname": "ap-1", "mac_address": "aa:bb:cc:00:00:02", "ip_addresses": ["10.0.0.40"]},
+		{"hostname": "ap-2", "mac_address": "not-a-mac", "ip_addresses": ["10.0.0.41"]},
+		{"hostname": "ap-3", "ip_addresses": ["10.0.0.999"]},
+		{"hostname": 42}
+	]`
+
+	rr, result := postImport(t, m, "application/json; charset=utf-8", body)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if result.Created != 1 || result.Updated != 1 || result.Skipped != 3 {
+		t.Errorf("created/updated/skipped = %d/%d/%d, want 1/1/3", result.Created, result.Updated, result.Skipped)
+	}
+	assertImportErrors(t, result.Errors, map[int]string{
+		3: "invalid MAC address",
+		4: "invalid IP address",
+		5: "cannot unmarshal",
+	})
+
+	ctx := context.Background()
+	renamed, err := m.store.GetDeviceByIP(ctx, "10.0.0.1")
+	if err != nil || renamed == nil {
+		t.Fatalf("GetDeviceByIP: %v, %v", renamed, err)
+	}
+	if renamed.ID != existing.ID || renamed.Hostname != "core-switch-renamed" {
+		t.Errorf("device matched by IP = %+v, want %s renamed", renamed, existing.ID)
+	}
+	// MACs are stored in the upper-case form the scanner uses.
+	if ap, _ := m.store.GetDeviceByMAC(ctx, "AA:BB:CC:00:00:02"); ap == nil {
+		t.Error("imported device not found by normalized MAC")
+	}
+}
+
+func TestHandleImportDevices_Multipart(t *testing.T) {
+	m := newTestModule(t)
+
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	part, err := mw.CreateFormFile("file", "devices.json")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	_, _ = part.Write([]byte(`[{"hostname": "nas", "ip_addresses": ["10.0.0.20"]}]`))
+	_ = mw.Close()
+
+	rr, result := postImport(t, m, mw.FormDataContentType(), buf.String())
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	if result.Created != 1 || len(result.Errors) != 0 {
+		t.Errorf("result = %+v, want one created device", result)
+	}
+}
+
+func TestHandleImportDevices_RejectedFiles(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{"unsupported content type", "application/xml", "<devices/>", http.StatusUnsupportedMediaType},
+		{"csv without match columns", "text/csv", "hostname,location\nnas,office", http.StatusBadRequest},
+		{"json object instead of array", "application/json", `{"hostname":"nas"}`, http.StatusBadRequest},
+		{"empty csv", "text/csv", "", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, _ := postImport(t, newTestModule(t), tt.contentType, tt.body)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+}
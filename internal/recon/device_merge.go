@@ -0,0 +1,269 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// MergeDevicesRequest is the request body for POST /devices/merge.
+type MergeDevicesRequest struct {
+	PrimaryID    string   `json:"primary_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	DuplicateIDs []string `json:"duplicate_ids"`
+}
+
+// MergeDevices folds the duplicate devices into primary in one transaction.
+// IP addresses and tags are unioned, custom fields from the duplicates fill
+// keys the primary does not have, and the primary keeps the earliest
+// first_seen and latest last_seen. Empty identity fields on the primary
+// (hostname, MAC, manufacturer, OS) are filled from the duplicates.
+//
+// Scan membership, topology links, status history, service movements, and
+// child devices are repointed to the primary before the duplicates are
+// deleted, so nothing that referenced a duplicate is cascaded away. Links
+// that would become self-loops are dropped. Per-device inventory (hardware,
+// ports, services) of the duplicates is not carried over.
+func (s *ReconStore) MergeDevices(ctx context.Context, primaryID string, duplicateIDs []string) (*models.Device, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin merge: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	primary, err := s.getDeviceTx(ctx, tx, primaryID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("device not found: %s", primaryID)
+		}
+		return nil, fmt.Errorf("get primary device: %w", err)
+	}
+
+	seen := map[string]bool{primaryID: true}
+	var dups []*models.Device
+	for _, id := range duplicateIDs {
+		if id == primaryID {
+			return nil, fmt.Errorf("cannot merge device %s into itself", id)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		dup, err := s.getDeviceTx(ctx, tx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("device not found: %s", id)
+			}
+			return nil, fmt.Errorf("get duplicate device: %w", err)
+		}
+		dups = append(dups, dup)
+	}
+	if len(dups) == 0 {
+		return nil, errors.New("no duplicate devices to merge")
+	}
+
+	for _, dup := range dups {
+		mergeDeviceFields(primary, dup)
+	}
+
+	ipsJSON, _ := json.Marshal(primary.IPAddresses)
+	tagsJSON, _ := json.Marshal(primary.Tags)
+	if primary.Tags == nil {
+		tagsJSON = []byte("[]")
+	}
+	cfJSON, _ := json.Marshal(primary.CustomFields)
+	if primary.CustomFields == nil {
+		cfJSON = []byte("{}")
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE recon_devices SET
+			ip_addresses = ?, tags = ?, custom_fields = ?,
+			first_seen = ?, last_seen = ?,
+			hostname = ?, mac_address = ?, manufacturer = ?, os = ?
+		WHERE id = ?`,
+		string(ipsJSON), string(tagsJSON), string(cfJSON),
+		primary.FirstSeen, primary.LastSeen,
+		primary.Hostname, primary.MACAddress, primary.Manufacturer, primary.OS,
+		primaryID,
+	); err != nil {
+		return nil, fmt.Errorf("update primary device: %w", err)
+	}
+
+	for _, dup := range dups {
+		if err := repointDevice(ctx, tx, dup.ID, primaryID); err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM recon_devices WHERE id = ?`, dup.ID); err != nil {
+			return nil, fmt.Errorf("delete duplicate device %s: %w", dup.ID, err)
+		}
+	}
+
+	// A duplicate may have been the primary's parent.
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE recon_devices SET parent_device_id = '' WHERE id = ? AND parent_device_id = ?`,
+		primaryID, primaryID,
+	); err != nil {
+		return nil, fmt.Errorf("clear self parent: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit merge: %w", err)
+	}
+	return s.GetDevice(ctx, primaryID)
+}
+
+// repointDevice moves every reference to device from onto device to. Rows
+// that would collide with an existing row for to are left in place and are
+// removed by the cascade when from is deleted.
+func repointDevice(ctx context.Context, tx *sql.Tx, from, to string) error {
+	stmts := []struct {
+		query string
+		args  []any
+	}{
+		{`UPDATE OR IGNORE recon_scan_devices SET device_id = ? WHERE device_id = ?`, []any{to, from}},
+		// Links between the two devices would become self-loops.
+		{`DELETE FROM recon_topology_links
+			WHERE (source_device_id = ? AND target_device_id = ?)
+			   OR (source_device_id = ? AND target_device_id = ?)`, []any{from, to, to, from}},
+		{`UPDATE OR IGNORE recon_topology_links SET source_device_id = ? WHERE source_device_id = ?`, []any{to, from}},
+		{`UPDATE OR IGNORE recon_topology_links SET target_device_id = ? WHERE target_device_id = ?`, []any{to, from}},
+		{`DELETE FROM recon_topology_links WHERE source_device_id = target_device_id`, nil},
+		{`UPDATE recon_device_history SET device_id = ? WHERE device_id = ?`, []any{to, from}},
+		{`UPDATE recon_service_movements SET from_device_id = ? WHERE from_device_id = ?`, []any{to, from}},
+		{`UPDATE recon_service_movements SET to_device_id = ? WHERE to_device_id = ?`, []any{to, from}},
+		{`UPDATE recon_devices SET parent_device_id = ? WHERE parent_device_id = ?`, []any{to, from}},
+	}
+	for _, st := range stmts {
+		if _, err := tx.ExecContext(ctx, st.query, st.args...); err != nil {
+			return fmt.Errorf("repoint device %s: %w", from, err)
+		}
+	}
+	return nil
+}
+
+// mergeDeviceFields folds dup's attributes into primary.
+func mergeDeviceFields(primary, dup *models.Device) {
+	primary.IPAddresses = appendUnique(primary.IPAddresses, dup.IPAddresses)
+	primary.Tags = appendUnique(primary.Tags, dup.Tags)
+
+	for k, v := range dup.CustomFields {
+		if _, ok := primary.CustomFields[k]; ok {
+			continue
+		}
+		if primary.CustomFields == nil {
+			primary.CustomFields = make(map[string]string)
+		}
+		primary.CustomFields[k] = v
+	}
+
+	if dup.FirstSeen.Before(primary.FirstSeen) {
+		primary.FirstSeen = dup.FirstSeen
+	}
+	if dup.LastSeen.After(primary.LastSeen) {
+		primary.LastSeen = dup.LastSeen
+	}
+
+	if primary.Hostname == "" {
+		primary.Hostname = dup.Hostname
+	}
+	if primary.MACAddress == "" {
+		primary.MACAddress = dup.MACAddress
+	}
+	if primary.Manufacturer == "" {
+		primary.Manufacturer = dup.Manufacturer
+	}
+	if primary.OS == "" {
+		primary.OS = dup.OS
+	}
+}
+
+// appendUnique appends the values of extra not already in base, keeping
+// the order of first appearance.
+func appendUnique(base, extra []string) []string {
+	seen := make(map[string]bool, len(base))
+	for _, v := range base {
+		seen[v] = true
+	}
+	for _, v := range extra {
+		if !seen[v] {
+			seen[v] = true
+			base = append(base, v)
+		}
+	}
+	return base
+}
+
+// getDeviceTx reads a device inside tx.
+func (s *ReconStore) getDeviceTx(ctx context.Context, tx *sql.Tx, id string) (*models.Device, error) {
+	return s.scanDevice(tx.QueryRowContext(ctx, `SELECT
+		id, hostname, ip_addresses, mac_address, manufacturer,
+		device_type, os, status, discovery_method, agent_id,
+		first_seen, last_seen, notes, tags, custom_fields,
+		location, rack, rack_unit, category, primary_role, owner,
+		classification_confidence, classification_source, classification_signals,
+		parent_device_id, network_layer, connection_type, archived_at
+		FROM recon_devices WHERE id = ?`, id))
+}
+
+// handleMergeDevices merges duplicate device records into a primary device.
+//
+//	@Summary		Merge devices
+//	@Description	Merges duplicate devices (for example one host seen through two NICs) into a primary device. IP addresses, tags, and custom fields are combined, first/last seen span all records, and scan history and topology links are moved to the primary. The duplicates are deleted.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		MergeDevicesRequest	true	"Primary and duplicate device IDs"
+//	@Success		200		{object}	models.Device
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/merge [post]
+func (m *Module) handleMergeDevices(w http.ResponseWriter, r *http.Request) {
+	var req MergeDevicesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.PrimaryID == "" {
+		writeError(w, http.StatusBadRequest, "primary_id is required")
+		return
+	}
+	if len(req.DuplicateIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "duplicate_ids must not be empty")
+		return
+	}
+	for _, id := range req.DuplicateIDs {
+		if id == "" {
+			writeError(w, http.StatusBadRequest, "duplicate_ids must not contain empty IDs")
+			return
+		}
+		if id == req.PrimaryID {
+			writeError(w, http.StatusBadRequest, "cannot merge a device into itself")
+			return
+		}
+	}
+
+	device, err := m.store.MergeDevices(r.Context(), req.PrimaryID, req.DuplicateIDs)
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		m.logger.Error("failed to merge devices",
+			zap.String("primary_id", req.PrimaryID),
+			zap.Strings("duplicate_ids", req.DuplicateIDs),
+			zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to merge devices")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, device)
+}
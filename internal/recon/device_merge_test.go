@@ -0,0 +1,216 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// insertMergeDevice creates a device with the given seen times.
+func insertMergeDevice(t *testing.T, s *ReconStore, d *models.Device, firstSeen, lastSeen time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	if err := s.InsertManualDevice(ctx, d); err != nil {
+		t.Fatalf("InsertManualDevice: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE recon_devices SET first_seen = ?, last_seen = ? WHERE id = ?`,
+		firstSeen, lastSeen, d.ID,
+	); err != nil {
+		t.Fatalf("set seen times: %v", err)
+	}
+}
+
+func TestMergeDevices(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	primary := &models.Device{
+		Hostname:     "nas",
+		IPAddresses:  []string{"192.168.1.10"},
+		Tags:         []string{"storage"},
+		CustomFields: map[string]string{"rack": "A1"},
+	}
+	eth1 := &models.Device{
+		MACAddress:   "AA:BB:CC:00:00:02",
+		IPAddresses:  []string{"192.168.2.10", "192.168.1.10"},
+		Tags:         []string{"storage", "10g"},
+		CustomFields: map[string]string{"rack": "B2", "asset": "1234"},
+	}
+	oldIP := &models.Device{
+		IPAddresses: []string{"192.168.1.99"},
+		Tags:        []string{"dhcp"},
+	}
+	sw := &models.Device{Hostname: "switch", IPAddresses: []string{"192.168.1.2"}}
+
+	insertMergeDevice(t, s, primary, t0.Add(48*time.Hour), t0.Add(72*time.Hour))
+	insertMergeDevice(t, s, eth1, t0.Add(24*time.Hour), t0.Add(96*time.Hour))
+	insertMergeDevice(t, s, oldIP, t0, t0.Add(time.Hour))
+	insertMergeDevice(t, s, sw, t0, t0)
+
+	// Both the primary and a duplicate were seen in scan-1; only the
+	// duplicate was seen in scan-2.
+	for _, id := range []string{"scan-1", "scan-2"} {
+		if err := s.CreateScan(ctx, &models.ScanResult{ID: id, Subnet: "192.168.1.0/24", Status: "completed"}); err != nil {
+			t.Fatalf("CreateScan: %v", err)
+		}
+	}
+	for _, link := range [][2]string{{"scan-1", primary.ID}, {"scan-1", eth1.ID}, {"scan-2", oldIP.ID}} {
+		if err := s.LinkScanDevice(ctx, link[0], link[1]); err != nil {
+			t.Fatalf("LinkScanDevice: %v", err)
+		}
+	}
+
+	links := []TopologyLink{
+		{SourceDeviceID: sw.ID, TargetDeviceID: eth1.ID, LinkType: "fdb", SourcePort: "ge-0/0/5"},
+		{SourceDeviceID: oldIP.ID, TargetDeviceID: sw.ID, LinkType: "arp"},
+		// Would become a self-loop.
+		{SourceDeviceID: primary.ID, TargetDeviceID: eth1.ID, LinkType: "arp"},
+	}
+	for i := range links {
+		if err := s.UpsertTopologyLink(ctx, &links[i]); err != nil {
+			t.Fatalf("UpsertTopologyLink: %v", err)
+		}
+	}
+
+	merged, err := s.MergeDevices(ctx, primary.ID, []string{eth1.ID, oldIP.ID})
+	if err != nil {
+		t.Fatalf("MergeDevices: %v", err)
+	}
+
+	if want := []string{"192.168.1.10", "192.168.2.10", "192.168.1.99"}; !slices.Equal(merged.IPAddresses, want) {
+		t.Errorf("IPAddresses = %v, want %v", merged.IPAddresses, want)
+	}
+	if want := []string{"storage", "10g", "dhcp"}; !slices.Equal(merged.Tags, want) {
+		t.Errorf("Tags = %v, want %v", merged.Tags, want)
+	}
+	if merged.CustomFields["rack"] != "A1" || merged.CustomFields["asset"] != "1234" {
+		t.Errorf("CustomFields = %v, want rack=A1 asset=1234", merged.CustomFields)
+	}
+	if merged.MACAddress != "AA:BB:CC:00:00:02" || merged.Hostname != "nas" {
+		t.Errorf("identity = %q/%q, want nas/AA:BB:CC:00:00:02", merged.Hostname, merged.MACAddress)
+	}
+	if !merged.FirstSeen.Equal(t0) {
+		t.Errorf("FirstSeen = %v, want %v", merged.FirstSeen, t0)
+	}
+	if !merged.LastSeen.Equal(t0.Add(96 * time.Hour)) {
+		t.Errorf("LastSeen = %v, want %v", merged.LastSeen, t0.Add(96*time.Hour))
+	}
+
+	for _, id := range []string{eth1.ID, oldIP.ID} {
+		if _, err := s.GetDevice(ctx, id); err == nil {
+			t.Errorf("duplicate %s still exists", id)
+		}
+	}
+
+	scans, total, err := s.GetDeviceScans(ctx, primary.ID, 10, 0)
+	if err != nil {
+		t.Fatalf("GetDeviceScans: %v", err)
+	}
+	if total != 2 || len(scans) != 2 {
+		t.Errorf("primary scans = %d (total %d), want 2", len(scans), total)
+	}
+
+	got, err := s.GetTopologyLinks(ctx)
+	if err != nil {
+		t.Fatalf("GetTopologyLinks: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("links = %+v, want 2", got)
+	}
+	for _, l := range got {
+		if l.SourceDeviceID == l.TargetDeviceID {
+			t.Errorf("self-loop link left behind: %+v", l)
+		}
+		switch l.LinkType {
+		case "fdb":
+			if l.SourceDeviceID != sw.ID || l.TargetDeviceID != primary.ID || l.SourcePort != "ge-0/0/5" {
+				t.Errorf("fdb link = %+v, want switch -> primary on ge-0/0/5", l)
+			}
+		case "arp":
+			if l.SourceDeviceID != primary.ID || l.TargetDeviceID != sw.ID {
+				t.Errorf("arp link = %+v, want primary -> switch", l)
+			}
+		}
+	}
+}
+
+func TestMergeDevices_Errors(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	d := &models.Device{IPAddresses: []string{"10.0.0.1"}}
+	if err := s.InsertManualDevice(ctx, d); err != nil {
+		t.Fatalf("InsertManualDevice: %v", err)
+	}
+
+	if _, err := s.MergeDevices(ctx, d.ID, []string{d.ID}); err == nil {
+		t.Error("merge into self: error = nil")
+	}
+	if _, err := s.MergeDevices(ctx, d.ID, []string{"missing"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing duplicate: error = %v, want not found", err)
+	}
+	if _, err := s.MergeDevices(ctx, "missing", []string{d.ID}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("missing primary: error = %v, want not found", err)
+	}
+
+	// A failed merge must not delete anything.
+	if _, err := s.GetDevice(ctx, d.ID); err != nil {
+		t.Errorf("device deleted after failed merge: %v", err)
+	}
+}
+
+func TestHandleMergeDevices(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	a := &models.Device{IPAddresses: []string{"10.0.0.1"}}
+	b := &models.Device{IPAddresses: []string{"10.0.0.2"}}
+	for _, d := range []*models.Device{a, b} {
+		if err := m.store.InsertManualDevice(ctx, d); err != nil {
+			t.Fatalf("InsertManualDevice: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"invalid json", `{`, http.StatusBadRequest},
+		{"missing primary", `{"duplicate_ids":["` + b.ID + `"]}`, http.StatusBadRequest},
+		{"no duplicates", `{"primary_id":"` + a.ID + `"}`, http.StatusBadRequest},
+		{"self merge", `{"primary_id":"` + a.ID + `","duplicate_ids":["` + a.ID + `"]}`, http.StatusBadRequest},
+		{"unknown duplicate", `{"primary_id":"` + a.ID + `","duplicate_ids":["nope"]}`, http.StatusNotFound},
+		{"merge", `{"primary_id":"` + a.ID + `","duplicate_ids":["` + b.ID + `"]}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/devices/merge", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			m.handleMergeDevices(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+			var dev models.Device
+			if err := json.NewDecoder(w.Body).Decode(&dev); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if dev.ID != a.ID || !slices.Equal(dev.IPAddresses, []string{"10.0.0.1", "10.0.0.2"}) {
+				t.Errorf("merged device = %+v", dev)
+			}
+		})
+	}
+}
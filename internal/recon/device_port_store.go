@@ -0,0 +1,73 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DevicePort is the last observed state of a TCP port on a device.
+type DevicePort struct {
+	DeviceID  string    `json:"device_id"`
+	Port      int       `json:"port" example:"22"`
+	Protocol  string    `json:"protocol" example:"tcp"`
+	State     PortState `json:"state" example:"open"`
+	Service   string    `json:"service,omitempty" example:"ssh"`
+	ScannedAt time.Time `json:"scanned_at"`
+}
+
+// ReplaceDevicePorts stores the results of a port scan of a device,
+// replacing the previous results for the scanned ports.
+func (s *ReconStore) ReplaceDevicePorts(ctx context.Context, deviceID string, ports []PortStatus, scannedAt time.Time) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback after commit is a no-op
+
+	for _, p := range ports {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO recon_device_ports (device_id, port, protocol, state, service, scanned_at)
+			VALUES (?, ?, 'tcp', ?, ?, ?)
+			ON CONFLICT (device_id, port, protocol) DO UPDATE SET
+				state = excluded.state,
+				service = excluded.service,
+				scanned_at = excluded.scanned_at`,
+			deviceID, p.Port, string(p.State), WellKnownPorts[p.Port], scannedAt.UTC(),
+		)
+		if err != nil {
+			return fmt.Errorf("upsert device port: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// ListDevicePorts returns the stored port states for a device. If openOnly
+// is set, closed and filtered ports are omitted.
+func (s *ReconStore) ListDevicePorts(ctx context.Context, deviceID string, openOnly bool) ([]DevicePort, error) {
+	query := `
+		SELECT device_id, port, protocol, state, service, scanned_at
+		FROM recon_device_ports WHERE device_id = ?`
+	if openOnly {
+		query += ` AND state = 'open'`
+	}
+	query += ` ORDER BY port ASC`
+
+	rows, err := s.db.QueryContext(ctx, query, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("list device ports: %w", err)
+	}
+	defer rows.Close()
+
+	var ports []DevicePort
+	for rows.Next() {
+		var p DevicePort
+		var state string
+		if err := rows.Scan(&p.DeviceID, &p.Port, &p.Protocol, &state, &p.Service, &p.ScannedAt); err != nil {
+			return nil, fmt.Errorf("scan device port row: %w", err)
+		}
+		p.State = PortState(state)
+		ports = append(ports, p)
+	}
+	return ports, rows.Err()
+}
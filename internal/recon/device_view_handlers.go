@@ -0,0 +1,187 @@
+package recon
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+	"go.uber.org/zap"
+)
+
+// CreateDeviceViewRequest is the request body for POST /devices/views.
+type CreateDeviceViewRequest struct {
+	Name    string            `json:"name" example:"Offline servers"`
+	Filter  DeviceViewFilter  `json:"filter"`
+	Options DeviceViewOptions `json:"options"`
+}
+
+// requestUserID returns the authenticated user's ID, writing a 401 and
+// returning "" if there is none.
+func requestUserID(w http.ResponseWriter, r *http.Request) string {
+	user := auth.UserFromContext(r.Context())
+	if user == nil || user.UserID == "" {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return ""
+	}
+	return user.UserID
+}
+
+// handleCreateDeviceView saves a named device search for the caller.
+//
+//	@Summary		Create device view
+//	@Description	Saves a device filter and list options as a named view owned by the authenticated user. Filter values are checked against the statuses, device types, and sort fields the device list accepts; unknown fields are rejected.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		CreateDeviceViewRequest	true	"View to save"
+//	@Success		201		{object}	DeviceView
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		401		{object}	models.APIProblem
+//	@Failure		409		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/views [post]
+func (m *Module) handleCreateDeviceView(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(w, r)
+	if userID == "" {
+		return
+	}
+
+	var req CreateDeviceViewRequest
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body: "+err.Error())
+		return
+	}
+
+	view := &DeviceView{
+		UserID:  userID,
+		Name:    req.Name,
+		Filter:  req.Filter,
+		Options: req.Options,
+	}
+	if err := validateDeviceView(view); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := m.store.CreateDeviceView(r.Context(), view); err != nil {
+		if errors.Is(err, errDeviceViewExists) {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		m.logger.Error("failed to create device view", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to create device view")
+		return
+	}
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// handleListDeviceViews returns the caller's saved device views.
+//
+//	@Summary		List device views
+//	@Description	Returns the device views saved by the authenticated user, ordered by name.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		DeviceView
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/devices/views [get]
+func (m *Module) handleListDeviceViews(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(w, r)
+	if userID == "" {
+		return
+	}
+	views, err := m.store.ListDeviceViews(r.Context(), userID)
+	if err != nil {
+		m.logger.Error("failed to list device views", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list device views")
+		return
+	}
+	if views == nil {
+		views = []DeviceView{}
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+// handleDeleteDeviceView deletes one of the caller's saved views.
+//
+//	@Summary		Delete device view
+//	@Description	Deletes a device view owned by the authenticated user.
+//	@Tags			recon
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"View ID"
+//	@Success		204
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/devices/views/{id} [delete]
+func (m *Module) handleDeleteDeviceView(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(w, r)
+	if userID == "" {
+		return
+	}
+	deleted, err := m.store.DeleteDeviceView(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		m.logger.Error("failed to delete device view", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to delete device view")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "device view not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleDeviceViewResults runs a saved view against the device inventory.
+//
+//	@Summary		Run device view
+//	@Description	Lists devices matching one of the authenticated user's saved views, using the view's saved sort and page size.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string	true	"View ID"
+//	@Param			offset	query		int		false	"Offset"	default(0)
+//	@Success		200		{object}	DeviceListResponse
+//	@Failure		401		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/views/{id}/results [get]
+func (m *Module) handleDeviceViewResults(w http.ResponseWriter, r *http.Request) {
+	userID := requestUserID(w, r)
+	if userID == "" {
+		return
+	}
+	view, err := m.store.GetDeviceView(r.Context(), userID, r.PathValue("id"))
+	if err != nil {
+		m.logger.Error("failed to get device view", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to get device view")
+		return
+	}
+	if view == nil {
+		writeError(w, http.StatusNotFound, "device view not found")
+		return
+	}
+
+	opts := view.Options.ListOptions(queryInt(r, "offset", 0))
+	page, err := m.devices.List(r.Context(), view.Filter.DeviceFilter(), opts)
+	if err != nil {
+		m.logger.Error("failed to run device view", zap.String("view_id", view.ID), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list devices")
+		return
+	}
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	writeJSON(w, http.StatusOK, DeviceListResponse{
+		Devices: page.Items,
+		Total:   page.Total,
+		Limit:   limit,
+		Offset:  opts.Offset,
+	})
+}
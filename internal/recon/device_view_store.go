@@ -0,0 +1,221 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/google/uuid"
+)
+
+// DeviceViewFilter is the saved form of services.DeviceFilter.
+type DeviceViewFilter struct {
+	Status          string   `json:"status,omitempty" example:"offline"`
+	DeviceType      string   `json:"device_type,omitempty" example:"server"`
+	Search          string   `json:"search,omitempty"`
+	ScanID          string   `json:"scan_id,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	IncludeArchived bool     `json:"include_archived,omitempty"`
+}
+
+// DeviceViewOptions is the saved form of services.ListOptions. Offset is
+// not saved; it is given when the view is run.
+type DeviceViewOptions struct {
+	Limit     int    `json:"limit,omitempty" example:"50"`
+	SortBy    string `json:"sort_by,omitempty" example:"hostname"`
+	SortOrder string `json:"sort_order,omitempty" example:"asc"`
+}
+
+// DeviceView is a named device search saved by a user.
+type DeviceView struct {
+	ID        string            `json:"id"`
+	UserID    string            `json:"user_id"`
+	Name      string            `json:"name" example:"Offline servers"`
+	Filter    DeviceViewFilter  `json:"filter"`
+	Options   DeviceViewOptions `json:"options"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// errDeviceViewExists is returned when a user already has a view with the
+// same name.
+var errDeviceViewExists = errors.New("a device view with this name already exists")
+
+// validateDeviceView checks the view's filter and options against the
+// values the device list accepts.
+func validateDeviceView(v *DeviceView) error {
+	if strings.TrimSpace(v.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(v.Name) > 100 {
+		return fmt.Errorf("name must be at most 100 characters")
+	}
+
+	switch models.DeviceStatus(v.Filter.Status) {
+	case "", models.DeviceStatusOnline, models.DeviceStatusOffline,
+		models.DeviceStatusDegraded, models.DeviceStatusUnknown:
+	default:
+		return fmt.Errorf("filter.status %q is not a valid device status", v.Filter.Status)
+	}
+	if v.Filter.DeviceType != "" && !isKnownDeviceType(models.DeviceType(v.Filter.DeviceType)) {
+		return fmt.Errorf("filter.device_type %q is not a valid device type", v.Filter.DeviceType)
+	}
+	for _, tag := range v.Filter.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return fmt.Errorf("filter.tags must not contain empty tags")
+		}
+	}
+
+	if v.Options.Limit < 0 || v.Options.Limit > 1000 {
+		return fmt.Errorf("options.limit must be between 0 and 1000")
+	}
+	if v.Options.SortBy != "" && !services.IsDeviceSortField(v.Options.SortBy) {
+		return fmt.Errorf("options.sort_by %q is not a sortable device field", v.Options.SortBy)
+	}
+	switch v.Options.SortOrder {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("options.sort_order must be asc or desc")
+	}
+	return nil
+}
+
+// isKnownDeviceType reports whether dt is one of the defined device types.
+func isKnownDeviceType(dt models.DeviceType) bool {
+	switch dt {
+	case models.DeviceTypeServer, models.DeviceTypeDesktop, models.DeviceTypeLaptop,
+		models.DeviceTypeMobile, models.DeviceTypeRouter, models.DeviceTypeSwitch,
+		models.DeviceTypePrinter, models.DeviceTypeIoT, models.DeviceTypeAccessPoint,
+		models.DeviceTypeFirewall, models.DeviceTypeNAS, models.DeviceTypePhone,
+		models.DeviceTypeTablet, models.DeviceTypeCamera, models.DeviceTypeVM,
+		models.DeviceTypeContainer, models.DeviceTypeUnknown:
+		return true
+	}
+	return false
+}
+
+// DeviceFilter converts the saved filter to a repository filter.
+func (f DeviceViewFilter) DeviceFilter() services.DeviceFilter {
+	return services.DeviceFilter{
+		Status:          f.Status,
+		DeviceType:      f.DeviceType,
+		Search:          f.Search,
+		ScanID:          f.ScanID,
+		Tags:            f.Tags,
+		IncludeArchived: f.IncludeArchived,
+	}
+}
+
+// ListOptions converts the saved options to repository list options
+// starting at offset.
+func (o DeviceViewOptions) ListOptions(offset int) services.ListOptions {
+	return services.ListOptions{
+		Limit:     o.Limit,
+		Offset:    offset,
+		SortBy:    o.SortBy,
+		SortOrder: o.SortOrder,
+	}
+}
+
+// CreateDeviceView inserts a saved view. The view must already be valid.
+func (s *ReconStore) CreateDeviceView(ctx context.Context, v *DeviceView) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	v.CreatedAt = time.Now().UTC()
+
+	filterJSON, err := json.Marshal(v.Filter)
+	if err != nil {
+		return fmt.Errorf("marshal device view filter: %w", err)
+	}
+	optionsJSON, err := json.Marshal(v.Options)
+	if err != nil {
+		return fmt.Errorf("marshal device view options: %w", err)
+	}
+
+	var exists int
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM recon_device_views WHERE user_id = ? AND name = ?`, v.UserID, v.Name,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check device view: %w", err)
+	}
+	if exists > 0 {
+		return errDeviceViewExists
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO recon_device_views (id, user_id, name, filter, options, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		v.ID, v.UserID, v.Name, string(filterJSON), string(optionsJSON), v.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create device view: %w", err)
+	}
+	return nil
+}
+
+// ListDeviceViews returns a user's saved views ordered by name.
+func (s *ReconStore) ListDeviceViews(ctx context.Context, userID string) ([]DeviceView, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, user_id, name, filter, options, created_at
+		FROM recon_device_views WHERE user_id = ? ORDER BY name ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list device views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []DeviceView
+	for rows.Next() {
+		v, err := scanDeviceView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, *v)
+	}
+	return views, rows.Err()
+}
+
+// GetDeviceView returns one of a user's saved views, or nil if the user has
+// no view with that ID.
+func (s *ReconStore) GetDeviceView(ctx context.Context, userID, id string) (*DeviceView, error) {
+	v, err := scanDeviceView(s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, name, filter, options, created_at
+		FROM recon_device_views WHERE id = ? AND user_id = ?`, id, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return v, err
+}
+
+// DeleteDeviceView removes one of a user's saved views. It reports whether
+// a view was deleted.
+func (s *ReconStore) DeleteDeviceView(ctx context.Context, userID, id string) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM recon_device_views WHERE id = ? AND user_id = ?`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete device view: %w", err)
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// scanDeviceView reads a device view from a row.
+func scanDeviceView(row interface{ Scan(...any) error }) (*DeviceView, error) {
+	var v DeviceView
+	var filterJSON, optionsJSON string
+	if err := row.Scan(&v.ID, &v.UserID, &v.Name, &filterJSON, &optionsJSON, &v.CreatedAt); err != nil {
+		return nil, fmt.Errorf("scan device view: %w", err)
+	}
+	if err := json.Unmarshal([]byte(filterJSON), &v.Filter); err != nil {
+		return nil, fmt.Errorf("unmarshal device view filter: %w", err)
+	}
+	if err := json.Unmarshal([]byte(optionsJSON), &v.Options); err != nil {
+		return nil, fmt.Errorf("unmarshal device view options: %w", err)
+	}
+	return &v, nil
+}
@@ -0,0 +1,139 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// viewMux registers all recon routes, as the server does.
+func viewMux(m *Module) *http.ServeMux {
+	mux := http.NewServeMux()
+	for _, rt := range m.Routes() {
+		mux.HandleFunc(rt.Method+" "+rt.Path, rt.Handler)
+	}
+	return mux
+}
+
+// doAsUser serves a request on mux as the given user.
+func doAsUser(mux http.Handler, userID, method, path, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req = httptest.NewRequest(method, path, http.NoBody)
+	} else {
+		req = httptest.NewRequest(method, path, strings.NewReader(body))
+	}
+	req = req.WithContext(auth.ContextWithUser(req.Context(), &auth.Claims{UserID: userID, Role: "operator"}))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	return w
+}
+
+func TestDeviceViews_SaveListRun(t *testing.T) {
+	m := newTestModule(t)
+	mux := viewMux(m)
+	ctx := context.Background()
+
+	for _, d := range []*models.Device{
+		{Hostname: "db-01", IPAddresses: []string{"10.0.0.1"}, DeviceType: models.DeviceTypeServer, Status: models.DeviceStatusOffline},
+		{Hostname: "web-01", IPAddresses: []string{"10.0.0.2"}, DeviceType: models.DeviceTypeServer, Status: models.DeviceStatusOnline},
+		{Hostname: "cam-01", IPAddresses: []string{"10.0.0.3"}, DeviceType: models.DeviceTypeCamera, Status: models.DeviceStatusOffline},
+	} {
+		if err := m.devices.Create(ctx, d); err != nil {
+			t.Fatalf("create device: %v", err)
+		}
+	}
+
+	w := doAsUser(mux, "user-1", http.MethodPost, "/devices/views",
+		`{"name":"Offline servers","filter":{"status":"offline","device_type":"server"},"options":{"sort_by":"hostname","sort_order":"asc"}}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d (body %s)", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var view DeviceView
+	if err := json.NewDecoder(w.Body).Decode(&view); err != nil {
+		t.Fatalf("decode view: %v", err)
+	}
+
+	// Listing is scoped to the owner.
+	w = doAsUser(mux, "user-1", http.MethodGet, "/devices/views", "")
+	var views []DeviceView
+	if err := json.NewDecoder(w.Body).Decode(&views); err != nil {
+		t.Fatalf("decode views: %v", err)
+	}
+	if len(views) != 1 || views[0].Name != "Offline servers" {
+		t.Errorf("user-1 views = %+v, want the saved view", views)
+	}
+	w = doAsUser(mux, "user-2", http.MethodGet, "/devices/views", "")
+	views = nil
+	if err := json.NewDecoder(w.Body).Decode(&views); err != nil {
+		t.Fatalf("decode views: %v", err)
+	}
+	if len(views) != 0 {
+		t.Errorf("user-2 views = %+v, want none", views)
+	}
+
+	w = doAsUser(mux, "user-1", http.MethodGet, "/devices/views/"+view.ID+"/results", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("results status = %d, want %d (body %s)", w.Code, http.StatusOK, w.Body.String())
+	}
+	var results DeviceListResponse
+	if err := json.NewDecoder(w.Body).Decode(&results); err != nil {
+		t.Fatalf("decode results: %v", err)
+	}
+	if results.Total != 1 || len(results.Devices) != 1 || results.Devices[0].Hostname != "db-01" {
+		t.Errorf("results = %+v, want only db-01", results)
+	}
+
+	// Another user can neither run nor delete the view.
+	if w = doAsUser(mux, "user-2", http.MethodGet, "/devices/views/"+view.ID+"/results", ""); w.Code != http.StatusNotFound {
+		t.Errorf("user-2 results status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w = doAsUser(mux, "user-2", http.MethodDelete, "/devices/views/"+view.ID, ""); w.Code != http.StatusNotFound {
+		t.Errorf("user-2 delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w = doAsUser(mux, "user-1", http.MethodDelete, "/devices/views/"+view.ID, ""); w.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestDeviceViews_Validation(t *testing.T) {
+	m := newTestModule(t)
+	mux := viewMux(m)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing name", `{"filter":{"status":"offline"}}`, http.StatusBadRequest},
+		{"bad status", `{"name":"v","filter":{"status":"asleep"}}`, http.StatusBadRequest},
+		{"bad device type", `{"name":"v","filter":{"device_type":"toaster"}}`, http.StatusBadRequest},
+		{"bad sort field", `{"name":"v","options":{"sort_by":"notes"}}`, http.StatusBadRequest},
+		{"bad sort order", `{"name":"v","options":{"sort_order":"up"}}`, http.StatusBadRequest},
+		{"unknown filter field", `{"name":"v","filter":{"owner":"me"}}`, http.StatusBadRequest},
+		{"valid", `{"name":"Untagged IoT","filter":{"device_type":"iot"}}`, http.StatusCreated},
+		{"duplicate name", `{"name":"Untagged IoT"}`, http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAsUser(mux, "user-1", http.MethodPost, "/devices/views", tt.body)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	// Requests without a user are rejected.
+	req := httptest.NewRequest(http.MethodGet, "/devices/views", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("anonymous status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,264 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// DockerCollector lists containers from a Docker Engine API endpoint. This
+// provides agentless discovery of containers on standalone Docker hosts.
+type DockerCollector struct {
+	endpoint   string
+	baseURL    string
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// DockerContainer is a running container reported by the Docker Engine API.
+type DockerContainer struct {
+	ID       string              `json:"id"`
+	Name     string              `json:"name"`
+	Image    string              `json:"image"`
+	State    string              `json:"state"`
+	Ports    []DockerPort        `json:"ports"`
+	Networks []DockerNetworkAddr `json:"networks"`
+}
+
+// DockerPort is a container port published on the host.
+type DockerPort struct {
+	HostIP        string `json:"host_ip,omitempty"`
+	HostPort      int    `json:"host_port"`
+	ContainerPort int    `json:"container_port"`
+	Protocol      string `json:"protocol"`
+}
+
+// String formats the port like `docker ps`, e.g. "0.0.0.0:8080->80/tcp".
+func (p DockerPort) String() string {
+	host := p.HostIP
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	return fmt.Sprintf("%s->%d/%s", net.JoinHostPort(host, fmt.Sprint(p.HostPort)), p.ContainerPort, p.Protocol)
+}
+
+// DockerNetworkAddr is a container's attachment to a Docker network.
+type DockerNetworkAddr struct {
+	Network    string `json:"network"`
+	IPAddress  string `json:"ip_address,omitempty"`
+	MACAddress string `json:"mac_address,omitempty"`
+}
+
+// dockerContainerSummary is one entry of the Engine API /containers/json response.
+type dockerContainerSummary struct {
+	ID    string   `json:"Id"`
+	Names []string `json:"Names"`
+	Image string   `json:"Image"`
+	State string   `json:"State"`
+	Ports []struct {
+		IP          string `json:"IP"`
+		PrivatePort int    `json:"PrivatePort"`
+		PublicPort  int    `json:"PublicPort"`
+		Type        string `json:"Type"`
+	} `json:"Ports"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress  string `json:"IPAddress"`
+			MacAddress string `json:"MacAddress"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+// NewDockerCollector creates a collector for the Docker Engine API at
+// endpoint. Accepted forms are unix:///var/run/docker.sock, tcp://host:2375,
+// and http(s)://host:port.
+func NewDockerCollector(endpoint string, logger *zap.Logger) (*DockerCollector, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse docker endpoint: %w", err)
+	}
+
+	transport := &http.Transport{}
+	var baseURL string
+	switch u.Scheme {
+	case "unix":
+		socket := u.Path
+		if socket == "" {
+			return nil, fmt.Errorf("docker endpoint %q has no socket path", endpoint)
+		}
+		transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", socket)
+		}
+		// The host is ignored when dialing the socket.
+		baseURL = "http://docker"
+	case "tcp":
+		baseURL = "http://" + u.Host
+	case "http", "https":
+		baseURL = strings.TrimRight(u.Scheme+"://"+u.Host+u.Path, "/")
+	default:
+		return nil, fmt.Errorf("unsupported docker endpoint scheme %q: use unix, tcp, http, or https", u.Scheme)
+	}
+	if u.Scheme != "unix" && u.Host == "" {
+		return nil, fmt.Errorf("docker endpoint %q has no host", endpoint)
+	}
+
+	return &DockerCollector{
+		endpoint:   endpoint,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: transport},
+		logger:     logger,
+	}, nil
+}
+
+// CollectContainers returns the running containers on the Docker host.
+func (c *DockerCollector) CollectContainers(ctx context.Context) ([]DockerContainer, error) {
+	body, err := c.apiGet(ctx, "/containers/json")
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+
+	var summaries []dockerContainerSummary
+	if err := json.Unmarshal(body, &summaries); err != nil {
+		return nil, fmt.Errorf("parse containers response: %w", err)
+	}
+
+	containers := make([]DockerContainer, 0, len(summaries))
+	for i := range summaries {
+		s := &summaries[i]
+		// /containers/json lists only running containers unless all=true,
+		// but guard against proxies that pass the flag through.
+		if s.State != "" && s.State != "running" {
+			continue
+		}
+		containers = append(containers, s.toContainer())
+	}
+	return containers, nil
+}
+
+// toContainer maps an Engine API summary, keeping only published ports.
+func (s *dockerContainerSummary) toContainer() DockerContainer {
+	ct := DockerContainer{
+		ID:    s.ID,
+		Image: s.Image,
+		State: s.State,
+	}
+	if len(s.Names) > 0 {
+		ct.Name = strings.TrimPrefix(s.Names[0], "/")
+	}
+	if ct.Name == "" && len(s.ID) >= 12 {
+		ct.Name = s.ID[:12]
+	}
+
+	for _, p := range s.Ports {
+		if p.PublicPort == 0 {
+			continue
+		}
+		ct.Ports = append(ct.Ports, DockerPort{
+			HostIP:        p.IP,
+			HostPort:      p.PublicPort,
+			ContainerPort: p.PrivatePort,
+			Protocol:      p.Type,
+		})
+	}
+
+	names := make([]string, 0, len(s.NetworkSettings.Networks))
+	for name := range s.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n := s.NetworkSettings.Networks[name]
+		ct.Networks = append(ct.Networks, DockerNetworkAddr{
+			Network:    name,
+			IPAddress:  n.IPAddress,
+			MACAddress: strings.ToUpper(n.MacAddress),
+		})
+	}
+	return ct
+}
+
+// Device maps the container to a device record under the Docker host
+// device hostDeviceID. The image is kept as a tag and published ports and
+// networks are summarised in the notes.
+func (ct *DockerContainer) Device(hostDeviceID string) *models.Device {
+	dev := &models.Device{
+		Hostname:        ct.Name,
+		DeviceType:      models.DeviceTypeContainer,
+		Status:          models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryDocker,
+		ParentDeviceID:  hostDeviceID,
+		NetworkLayer:    models.NetworkLayerEndpoint,
+		Tags:            []string{"docker"},
+	}
+	if ct.Image != "" {
+		dev.Tags = append(dev.Tags, "image:"+ct.Image)
+	}
+
+	var networks []string
+	for _, n := range ct.Networks {
+		networks = append(networks, n.Network)
+		if n.IPAddress != "" {
+			dev.IPAddresses = append(dev.IPAddresses, n.IPAddress)
+		}
+		if dev.MACAddress == "" {
+			dev.MACAddress = n.MACAddress
+		}
+	}
+
+	notes := []string{"Image: " + ct.Image}
+	if len(ct.Ports) > 0 {
+		ports := make([]string, 0, len(ct.Ports))
+		for _, p := range ct.Ports {
+			ports = append(ports, p.String())
+		}
+		notes = append(notes, "Ports: "+strings.Join(ports, ", "))
+	}
+	if len(networks) > 0 {
+		notes = append(notes, "Networks: "+strings.Join(networks, ", "))
+	}
+	dev.Notes = strings.Join(notes, "\n")
+	return dev
+}
+
+// apiGet performs a GET request against the Docker Engine API and returns
+// the response body.
+func (c *DockerCollector) apiGet(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("docker daemon unreachable at %s: %w", c.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// Engine API errors are {"message": "..."}.
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return nil, fmt.Errorf("docker API returned %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return nil, fmt.Errorf("docker API returned %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
@@ -0,0 +1,225 @@
+package recon
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+const dockerContainersJSON = `[
+	{
+		"Id": "4f2a9c1e7b3d8a6f0e5c2b1a9d8e7f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e",
+		"Names": ["/web"],
+		"Image": "nginx:1.25",
+		"State": "running",
+		"Ports": [
+			{"IP": "0.0.0.0", "PrivatePort": 80, "PublicPort": 8080, "Type": "tcp"},
+			{"PrivatePort": 443, "Type": "tcp"}
+		],
+		"NetworkSettings": {"Networks": {
+			"frontend": {"IPAddress": "172.18.0.2", "MacAddress": "02:42:ac:12:00:02"},
+			"bridge": {"IPAddress": "172.17.0.2", "MacAddress": "02:42:ac:11:00:02"}
+		}}
+	},
+	{
+		"Id": "9e8d7c6b5a4f3e2d1c0b9a8f7e6d5c4b3a2f1e0d9c8b7a6f5e4d3c2b1a0f9e8d",
+		"Names": ["/db"],
+		"Image": "postgres:16",
+		"State": "running",
+		"Ports": [{"PrivatePort": 5432, "Type": "tcp"}],
+		"NetworkSettings": {"Networks": {"backend": {"IPAddress": "172.19.0.3", "MacAddress": "02:42:ac:13:00:03"}}}
+	},
+	{
+		"Id": "0a1b2c3d4e5f",
+		"Names": ["/old"],
+		"Image": "busybox",
+		"State": "exited",
+		"NetworkSettings": {"Networks": {}}
+	}
+]`
+
+func newTestDockerServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDockerCollector_CollectContainers(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   string
+		statusCode int
+		wantNames  []string
+		wantErr    string
+	}{
+		{
+			name:       "running containers",
+			response:   dockerContainersJSON,
+			statusCode: http.StatusOK,
+			wantNames:  []string{"web", "db"},
+		},
+		{
+			name:       "no containers",
+			response:   `[]`,
+			statusCode: http.StatusOK,
+			wantNames:  []string{},
+		},
+		{
+			name:       "API error",
+			response:   `{"message": "client version 1.99 is too new"}`,
+			statusCode: http.StatusBadRequest,
+			wantErr:    "client version 1.99 is too new",
+		},
+		{
+			name:       "malformed response",
+			response:   `{"not": "a list"}`,
+			statusCode: http.StatusOK,
+			wantErr:    "parse containers response",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestDockerServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/containers/json" {
+					http.NotFound(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.statusCode)
+				_, _ = w.Write([]byte(tt.response))
+			})
+
+			collector, err := NewDockerCollector(srv.URL, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewDockerCollector: %v", err)
+			}
+			containers, err := collector.CollectContainers(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CollectContainers: %v", err)
+			}
+			names := make([]string, 0, len(containers))
+			for _, c := range containers {
+				names = append(names, c.Name)
+			}
+			if !slices.Equal(names, tt.wantNames) {
+				t.Errorf("names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+func TestDockerCollector_ContainerDetails(t *testing.T) {
+	srv := newTestDockerServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(dockerContainersJSON))
+	})
+	collector, err := NewDockerCollector(srv.URL, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewDockerCollector: %v", err)
+	}
+	containers, err := collector.CollectContainers(context.Background())
+	if err != nil {
+		t.Fatalf("CollectContainers: %v", err)
+	}
+
+	web := containers[0]
+	if web.Image != "nginx:1.25" {
+		t.Errorf("image = %q, want nginx:1.25", web.Image)
+	}
+	// Only published ports are reported.
+	if len(web.Ports) != 1 || web.Ports[0].String() != "0.0.0.0:8080->80/tcp" {
+		t.Errorf("ports = %+v, want only 0.0.0.0:8080->80/tcp", web.Ports)
+	}
+	if len(web.Networks) != 2 || web.Networks[0].Network != "bridge" || web.Networks[1].Network != "frontend" {
+		t.Errorf("networks = %+v, want bridge and frontend sorted", web.Networks)
+	}
+
+	dev := web.Device("host-1")
+	if dev.DeviceType != models.DeviceTypeContainer || dev.ParentDeviceID != "host-1" {
+		t.Errorf("device type/parent = %s/%s, want container under host-1", dev.DeviceType, dev.ParentDeviceID)
+	}
+	if dev.DiscoveryMethod != models.DiscoveryDocker || dev.Hostname != "web" {
+		t.Errorf("device = %+v, want docker-discovered web", dev)
+	}
+	if !slices.Equal(dev.IPAddresses, []string{"172.17.0.2", "172.18.0.2"}) || dev.MACAddress != "02:42:AC:11:00:02" {
+		t.Errorf("device addresses = %v %s, want both network IPs and bridge MAC", dev.IPAddresses, dev.MACAddress)
+	}
+	if !slices.Contains(dev.Tags, "docker") || !slices.Contains(dev.Tags, "image:nginx:1.25") {
+		t.Errorf("tags = %v, want docker and image tags", dev.Tags)
+	}
+	if !strings.Contains(dev.Notes, "0.0.0.0:8080->80/tcp") || !strings.Contains(dev.Notes, "Networks: bridge, frontend") {
+		t.Errorf("notes = %q, want ports and networks", dev.Notes)
+	}
+}
+
+func TestDockerCollector_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(dockerContainersJSON))
+	}))
+	srv.Listener = ln
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	collector, err := NewDockerCollector("unix://"+socket, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewDockerCollector: %v", err)
+	}
+	containers, err := collector.CollectContainers(context.Background())
+	if err != nil {
+		t.Fatalf("CollectContainers: %v", err)
+	}
+	if len(containers) != 2 {
+		t.Errorf("containers = %d, want 2", len(containers))
+	}
+}
+
+func TestDockerCollector_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	closedURL := srv.URL
+	srv.Close()
+
+	endpoints := []string{
+		strings.Replace(closedURL, "http://", "tcp://", 1),
+		"unix://" + filepath.Join(t.TempDir(), "missing.sock"),
+	}
+	for _, endpoint := range endpoints {
+		t.Run(endpoint, func(t *testing.T) {
+			collector, err := NewDockerCollector(endpoint, zap.NewNop())
+			if err != nil {
+				t.Fatalf("NewDockerCollector: %v", err)
+			}
+			_, err = collector.CollectContainers(context.Background())
+			if err == nil || !strings.Contains(err.Error(), "docker daemon unreachable at "+endpoint) {
+				t.Errorf("error = %v, want daemon unreachable", err)
+			}
+		})
+	}
+}
+
+func TestNewDockerCollector_InvalidEndpoint(t *testing.T) {
+	for _, endpoint := range []string{"ftp://host", "tcp://", "unix://", "://bad"} {
+		if _, err := NewDockerCollector(endpoint, zap.NewNop()); err == nil {
+			t.Errorf("NewDockerCollector(%q) succeeded, want error", endpoint)
+		}
+	}
+}
@@ -13,6 +13,7 @@ const (
 	TopicDeviceLost       = "recon.device.lost"
 	TopicScanStarted      = "recon.scan.started"
 	TopicScanCompleted    = "recon.scan.completed"
+	TopicScanCancelled    = "recon.scan.cancelled"
 	TopicScanProgress     = "recon.scan.progress"
 	TopicServiceMoved            = "recon.service.moved"
 	TopicDeviceHardwareUpdated   = "recon.device.hardware.updated"
@@ -31,11 +32,17 @@ type DeviceEvent struct {
 	Device *models.Device `json:"device"`
 }
 
-// ScanProgressEvent reports incremental scan progress as each host responds.
+// ScanProgressEvent reports incremental scan progress as each host responds,
+// and periodically while probing. RatePPS is a moving average of probes per
+// second and ETASeconds the estimated time left to finish probing.
 type ScanProgressEvent struct {
-	ScanID     string `json:"scan_id"`
-	HostsAlive int    `json:"hosts_alive"`
-	SubnetSize int    `json:"subnet_size"`
+	ScanID      string  `json:"scan_id"`
+	HostsAlive  int     `json:"hosts_alive"`
+	SubnetSize  int     `json:"subnet_size"`
+	ProbesDone  int     `json:"probes_done"`
+	ProbesTotal int     `json:"probes_total"`
+	RatePPS     float64 `json:"rate_pps"`
+	ETASeconds  float64 `json:"eta_seconds"`
 }
 
 // ServiceMovedEvent is the payload for TopicServiceMoved events.
@@ -3,7 +3,6 @@ package recon
 import (
 	"context"
 	"database/sql"
-	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,7 +12,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/pkg/httperr"
 	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/pagination"
 	"github.com/HerbHall/subnetree/pkg/roles"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -28,20 +30,15 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 
 // writeError writes an RFC 7807 problem detail response.
 func writeError(w http.ResponseWriter, status int, detail string) {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"type":   "https://subnetree.com/problems/" + http.StatusText(status),
-		"title":  http.StatusText(status),
-		"status": status,
-		"detail": detail,
-	})
+	httperr.WriteProblem(w, status, detail)
 }
 
-// TopologyGraph is the response for GET /topology.
+// TopologyGraph is the response for GET /topology. Groups is set only when
+// the request asks for grouping.
 type TopologyGraph struct {
-	Nodes []TopologyNode `json:"nodes"`
-	Edges []TopologyEdge `json:"edges"`
+	Nodes  []TopologyNode  `json:"nodes"`
+	Edges  []TopologyEdge  `json:"edges"`
+	Groups []TopologyGroup `json:"groups,omitempty"`
 }
 
 // TopologyNode represents a device in the topology graph.
@@ -55,6 +52,10 @@ type TopologyNode struct {
 	Manufacturer   string              `json:"manufacturer,omitempty" example:"Dell Inc."`
 	ParentDeviceID string              `json:"parent_device_id,omitempty"`
 	NetworkLayer   int                 `json:"network_layer,omitempty"`
+	Location       string              `json:"location,omitempty" example:"DC1 Room 2"`
+	Rack           string              `json:"rack,omitempty" example:"A3"`
+	RackUnit       int                 `json:"rack_unit,omitempty" example:"12"`
+	Group          string              `json:"group,omitempty" example:"location:DC1 Room 2"`
 }
 
 // TopologyEdge represents a link in the topology graph.
@@ -69,25 +70,28 @@ type TopologyEdge struct {
 // ScanRequest is the request body for POST /scan.
 type ScanRequest struct {
 	Subnet string `json:"subnet" example:"192.168.1.0/24"`
+	// Exclusions are IPs or CIDRs to skip in this scan, in addition to the
+	// stored exclusion list.
+	Exclusions []string `json:"exclusions,omitempty" example:"192.168.1.10,192.168.1.128/28"`
 }
 
 // handleScan triggers a new network scan.
 //
 //	@Summary		Start scan
-//	@Description	Trigger a new network scan on the given subnet. Returns immediately with scan ID.
+//	@Description	Trigger a new network scan on the given subnet. Returns immediately with scan ID. Hosts in the stored exclusion list or the request's exclusions are skipped without being probed.
 //	@Tags			recon
 //	@Accept			json
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			request	body		ScanRequest			true	"Subnet to scan"
-//	@Success		202		{object}	models.ScanResult	"Scan accepted"
+//	@Param			request			body		ScanRequest			true	"Subnet to scan"
+//	@Param			Idempotency-Key	header		string				false	"Repeats with the same key within 24h return the original scan instead of starting another"
+//	@Success		202				{object}	models.ScanResult	"Scan accepted"
 //	@Failure		400		{object}	models.APIProblem
+//	@Failure		422		{object}	models.APIProblem	"Idempotency-Key reused for a different request"
 //	@Failure		500		{object}	models.APIProblem
 //	@Router			/recon/scan [post]
 func (m *Module) handleScan(w http.ResponseWriter, r *http.Request) {
-	var req struct {
-		Subnet string `json:"subnet"`
-	}
+	var req ScanRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid JSON body")
 		return
@@ -97,18 +101,16 @@ func (m *Module) handleScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate CIDR.
-	_, ipNet, err := net.ParseCIDR(req.Subnet)
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "invalid CIDR: "+err.Error())
+	// Validate CIDR and reject subnets larger than /16.
+	if err := validateScanSubnet(req.Subnet); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-
-	// Reject subnets larger than /16.
-	ones, bits := ipNet.Mask.Size()
-	if bits-ones > 16 {
-		writeError(w, http.StatusBadRequest, "subnet too large: maximum /16 allowed")
-		return
+	for _, entry := range req.Exclusions {
+		if _, err := normalizeExclusion(entry); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 	}
 
 	// Create scan record.
@@ -132,26 +134,33 @@ func (m *Module) handleScan(w http.ResponseWriter, r *http.Request) {
 	go func() {
 		defer m.wg.Done()
 		defer m.activeScans.Delete(scanID)
-		m.orchestrator.RunScan(scanCtx, scanID, req.Subnet)
+		m.orchestrator.RunScan(scanCtx, scanID, req.Subnet, req.Exclusions...)
 	}()
 
 	writeJSON(w, http.StatusAccepted, scan)
 }
 
-// handleListScans returns a paginated list of scans.
+// handleListScans returns a paginated list of scans. The total number of
+// scans is reported in X-Total-Count.
 //
 //	@Summary		List scans
-//	@Description	Returns a paginated list of scan results.
+//	@Description	Returns a paginated list of scan results. X-Total-Count holds the total number of scans;
+//	@Description	pass envelope=true to get an object with items, total, limit, and offset instead of an array.
 //	@Tags			recon
 //	@Produce		json
 //	@Security		BearerAuth
-//	@Param			limit	query		int	false	"Max results"	default(50)
-//	@Param			offset	query		int	false	"Offset"		default(0)
-//	@Success		200		{array}		models.ScanResult
-//	@Failure		500		{object}	models.APIProblem
+//	@Param			limit		query		int		false	"Max results"	default(50)
+//	@Param			offset		query		int		false	"Offset"		default(0)
+//	@Param			envelope	query		bool	false	"Wrap the results with pagination metadata"
+//	@Success		200			{array}		models.ScanResult
+//	@Header			200			{integer}	X-Total-Count	"Total number of scans"
+//	@Failure		500			{object}	models.APIProblem
 //	@Router			/recon/scans [get]
 func (m *Module) handleListScans(w http.ResponseWriter, r *http.Request) {
 	limit := queryInt(r, "limit", 50)
+	if limit == 0 {
+		limit = 50
+	}
 	offset := queryInt(r, "offset", 0)
 
 	scans, err := m.store.ListScans(r.Context(), limit, offset)
@@ -160,10 +169,13 @@ func (m *Module) handleListScans(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "failed to list scans")
 		return
 	}
-	if scans == nil {
-		scans = []models.ScanResult{}
+	total, err := m.store.CountScans(r.Context())
+	if err != nil {
+		m.logger.Error("failed to count scans", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list scans")
+		return
 	}
-	writeJSON(w, http.StatusOK, scans)
+	pagination.Write(w, r, scans, total, limit, offset)
 }
 
 // handleGetScan returns a single scan with its discovered devices.
@@ -191,6 +203,14 @@ func (m *Module) handleGetScan(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "scan not found")
 		return
 	}
+	if m.orchestrator != nil {
+		if p, ok := m.orchestrator.Progress(id); ok {
+			scan.ProbesDone = p.Done
+			scan.ProbesTotal = p.Total
+			scan.RatePPS = p.Rate
+			scan.ETASeconds = p.ETA.Seconds()
+		}
+	}
 
 	// Load devices for this scan.
 	devices, _, err := m.store.ListDevices(r.Context(), ListDevicesOptions{ScanID: id})
@@ -204,6 +224,42 @@ func (m *Module) handleGetScan(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, scan)
 }
 
+// handleCancelScan stops a running scan.
+//
+//	@Summary		Cancel scan
+//	@Description	Stops probing for a running scan. The scan is marked cancelled once its goroutine exits; devices already discovered are kept.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string				true	"Scan ID"
+//	@Success		202	{object}	models.ScanResult	"Cancellation requested"
+//	@Failure		400	{object}	models.APIProblem
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		409	{object}	models.APIProblem
+//	@Router			/recon/scans/{id}/cancel [post]
+func (m *Module) handleCancelScan(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if id == "" {
+		writeError(w, http.StatusBadRequest, "scan ID is required")
+		return
+	}
+
+	scan, err := m.store.GetScan(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+	v, ok := m.activeScans.Load(id)
+	if !ok {
+		writeError(w, http.StatusConflict, "scan is not running")
+		return
+	}
+	v.(context.CancelFunc)()
+	m.logger.Info("scan cancellation requested", zap.String("scan_id", id))
+
+	writeJSON(w, http.StatusAccepted, scan)
+}
+
 // handleGetScanMetrics returns timing and count metrics for a single scan.
 func (m *Module) handleGetScanMetrics(w http.ResponseWriter, r *http.Request) {
 	id := r.PathValue("id")
@@ -229,14 +285,32 @@ func (m *Module) handleGetScanMetrics(w http.ResponseWriter, r *http.Request) {
 // handleTopology returns the network topology as a graph.
 //
 //	@Summary		Get topology
-//	@Description	Returns the network topology as a graph of nodes and edges.
+//	@Description	Returns the network topology as a graph of nodes and edges. Use format=dot for a Graphviz digraph or format=cytoscape for Cytoscape.js elements; edges are styled by link type in both. With group_by=location, nodes are clustered by device location (groups in JSON, clusters in DOT, compound nodes in Cytoscape) and all edges are kept.
 //	@Tags			recon
-//	@Produce		json
+//	@Produce		json,text/vnd.graphviz
 //	@Security		BearerAuth
-//	@Success		200	{object}	TopologyGraph
-//	@Failure		500	{object}	models.APIProblem
+//	@Param			format		query		string	false	"Output format"		Enums(json, dot, cytoscape)	default(json)
+//	@Param			group_by	query		string	false	"Cluster nodes by"	Enums(location)
+//	@Success		200			{object}	TopologyGraph
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
 //	@Router			/recon/topology [get]
 func (m *Module) handleTopology(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "":
+		format = topologyFormatJSON
+	case topologyFormatJSON, topologyFormatDOT, topologyFormatCytoscape:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid format: must be json, dot, or cytoscape")
+		return
+	}
+	groupBy := r.URL.Query().Get("group_by")
+	if groupBy != "" && groupBy != topologyGroupByLocation {
+		writeError(w, http.StatusBadRequest, "invalid group_by: must be location")
+		return
+	}
+
 	devices, _, err := m.store.ListDevices(r.Context(), ListDevicesOptions{Limit: 10000})
 	if err != nil {
 		m.logger.Error("failed to list devices for topology", zap.Error(err))
@@ -272,6 +346,9 @@ func (m *Module) handleTopology(w http.ResponseWriter, r *http.Request) {
 			Manufacturer:   d.Manufacturer,
 			ParentDeviceID: d.ParentDeviceID,
 			NetworkLayer:   d.NetworkLayer,
+			Location:       d.Location,
+			Rack:           d.Rack,
+			RackUnit:       d.RackUnit,
 		})
 	}
 
@@ -294,7 +371,22 @@ func (m *Module) handleTopology(w http.ResponseWriter, r *http.Request) {
 	inferred := inferGatewayEdges(devices, existingLinks)
 	graph.Edges = append(graph.Edges, inferred...)
 
-	writeJSON(w, http.StatusOK, graph)
+	if groupBy == topologyGroupByLocation {
+		groupTopologyByLocation(&graph)
+	}
+
+	switch format {
+	case topologyFormatDOT:
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		if err := writeTopologyDOT(w, &graph); err != nil {
+			m.logger.Debug("failed to write topology DOT", zap.Error(err))
+		}
+	case topologyFormatCytoscape:
+		writeJSON(w, http.StatusOK, topologyCytoscape(&graph))
+	default:
+		writeJSON(w, http.StatusOK, graph)
+	}
 }
 
 // inferGatewayEdges generates synthetic topology edges that model the network
@@ -522,165 +614,6 @@ func (m *Module) handleDeleteTopologyLayout(w http.ResponseWriter, r *http.Reque
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// ImportResult contains the results of a CSV import operation.
-type ImportResult struct {
-	Created int      `json:"created"`
-	Updated int      `json:"updated"`
-	Skipped int      `json:"skipped"`
-	Errors  []string `json:"errors,omitempty"`
-}
-
-// handleExportCSV exports all devices as a CSV file.
-//
-//	@Summary		Export devices as CSV
-//	@Description	Downloads all devices in CSV format for spreadsheet import or backup.
-//	@Tags			recon
-//	@Produce		text/csv
-//	@Security		BearerAuth
-//	@Success		200	{file}	file
-//	@Failure		500	{object}	models.APIProblem
-//	@Router			/recon/devices/export [get]
-func (m *Module) handleExportCSV(w http.ResponseWriter, r *http.Request) {
-	devices, _, err := m.store.ListDevices(r.Context(), ListDevicesOptions{Limit: 100000})
-	if err != nil {
-		m.logger.Error("failed to list devices for export", zap.Error(err))
-		writeError(w, http.StatusInternalServerError, "failed to export devices")
-		return
-	}
-
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", `attachment; filename="subnetree-devices.csv"`)
-
-	writer := csv.NewWriter(w)
-	defer writer.Flush()
-
-	_ = writer.Write(csvHeaders())
-
-	for i := range devices {
-		_ = writer.Write(deviceToCSVRow(devices[i]))
-	}
-}
-
-// handleImportCSV imports devices from a CSV file.
-//
-//	@Summary		Import devices from CSV
-//	@Description	Uploads a CSV file to create or update devices. Duplicates detected by MAC address or hostname.
-//	@Tags			recon
-//	@Accept			multipart/form-data
-//	@Produce		json
-//	@Security		BearerAuth
-//	@Param			file	formData	file	true	"CSV file"
-//	@Success		200		{object}	ImportResult
-//	@Failure		400		{object}	models.APIProblem
-//	@Failure		500		{object}	models.APIProblem
-//	@Router			/recon/devices/import [post]
-func (m *Module) handleImportCSV(w http.ResponseWriter, r *http.Request) {
-	// Limit upload to 10 MB.
-	r.Body = http.MaxBytesReader(w, r.Body, 10<<20)
-
-	file, _, err := r.FormFile("file")
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "missing or invalid file field")
-		return
-	}
-	defer file.Close()
-
-	reader := csv.NewReader(file)
-
-	// Read and validate header.
-	header, err := reader.Read()
-	if err != nil {
-		writeError(w, http.StatusBadRequest, "failed to read CSV header")
-		return
-	}
-	if len(header) < 2 {
-		writeError(w, http.StatusBadRequest, "invalid CSV: too few columns")
-		return
-	}
-
-	result := ImportResult{}
-	rowNum := 1 // 1-indexed, header is row 1
-
-	for {
-		row, readErr := reader.Read()
-		if readErr != nil {
-			break // EOF or error
-		}
-		rowNum++
-
-		device, parseErr := csvRowToDevice(row)
-		if parseErr != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %v", rowNum, parseErr))
-			result.Skipped++
-			continue
-		}
-
-		if device.Hostname == "" {
-			result.Errors = append(result.Errors, fmt.Sprintf("row %d: hostname is required", rowNum))
-			result.Skipped++
-			continue
-		}
-
-		// Check for existing device by MAC.
-		if device.MACAddress != "" {
-			existing, _ := m.store.GetDeviceByMAC(r.Context(), device.MACAddress)
-			if existing != nil {
-				tags := device.Tags
-				dtStr := string(device.DeviceType)
-				_ = m.store.UpdateDevice(r.Context(), existing.ID, UpdateDeviceParams{
-					Notes:       &device.Notes,
-					Tags:        &tags,
-					DeviceType:  &dtStr,
-					Location:    &device.Location,
-					Category:    &device.Category,
-					PrimaryRole: &device.PrimaryRole,
-					Owner:       &device.Owner,
-				})
-				result.Updated++
-				continue
-			}
-		}
-
-		// Check for existing device by hostname.
-		existing, _ := m.store.GetDeviceByHostname(r.Context(), device.Hostname)
-		if existing != nil {
-			tags := device.Tags
-			dtStr := string(device.DeviceType)
-			_ = m.store.UpdateDevice(r.Context(), existing.ID, UpdateDeviceParams{
-				Notes:       &device.Notes,
-				Tags:        &tags,
-				DeviceType:  &dtStr,
-				Location:    &device.Location,
-				Category:    &device.Category,
-				PrimaryRole: &device.PrimaryRole,
-				Owner:       &device.Owner,
-			})
-			result.Updated++
-			continue
-		}
-
-		// Create new device.
-		if device.ID == "" {
-			device.ID = uuid.New().String()
-		}
-		if device.Status == "" {
-			device.Status = models.DeviceStatusUnknown
-		}
-		if device.DiscoveryMethod == "" {
-			device.DiscoveryMethod = models.DiscoveryManual
-		}
-
-		if createErr := m.store.InsertManualDevice(r.Context(), &device); createErr != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("row %d: %v", rowNum, createErr))
-			result.Skipped++
-			continue
-		}
-		result.Created++
-	}
-
-	writeJSON(w, http.StatusOK, result)
-}
-
 // DeviceListResponse is the paginated response for GET /devices.
 type DeviceListResponse struct {
 	Devices []models.Device `json:"devices"`
@@ -689,11 +622,16 @@ type DeviceListResponse struct {
 	Offset  int             `json:"offset"`
 }
 
-// DeviceStatusEvent is the frontend-compatible status history entry.
+// DeviceStatusEvent is the frontend-compatible device history entry.
+// Status is set only for status changes.
 type DeviceStatusEvent struct {
 	ID        string `json:"id"`
 	DeviceID  string `json:"device_id"`
-	Status    string `json:"status"`
+	Field     string `json:"field" example:"hostname"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+	Source    string `json:"source" example:"scan"`
+	Status    string `json:"status,omitempty"`
 	Timestamp string `json:"timestamp"`
 }
 
@@ -720,7 +658,7 @@ type CreateDeviceRequest struct {
 // handleListDevices returns a paginated list of devices with optional filters.
 //
 //	@Summary		List devices
-//	@Description	Returns a paginated list of devices with optional status, type, category, and owner filters.
+//	@Description	Returns a paginated list of devices with optional status, type, category, and owner filters. Archived devices are excluded unless include_archived is true.
 //	@Tags			recon
 //	@Produce		json
 //	@Security		BearerAuth
@@ -730,6 +668,7 @@ type CreateDeviceRequest struct {
 //	@Param			type		query		string	false	"Filter by device type"
 //	@Param			category	query		string	false	"Filter by category"
 //	@Param			owner		query		string	false	"Filter by owner"
+//	@Param			include_archived	query	bool	false	"Include archived devices"
 //	@Success		200			{object}	DeviceListResponse
 //	@Failure		500			{object}	models.APIProblem
 //	@Router			/recon/devices [get]
@@ -742,12 +681,13 @@ func (m *Module) handleListDevices(w http.ResponseWriter, r *http.Request) {
 	owner := r.URL.Query().Get("owner")
 
 	devices, total, err := m.store.ListDevices(r.Context(), ListDevicesOptions{
-		Limit:      limit,
-		Offset:     offset,
-		Status:     status,
-		DeviceType: deviceType,
-		Category:   category,
-		Owner:      owner,
+		Limit:           limit,
+		Offset:          offset,
+		Status:          status,
+		DeviceType:      deviceType,
+		Category:        category,
+		Owner:           owner,
+		IncludeArchived: r.URL.Query().Get("include_archived") == "true",
 	})
 	if err != nil {
 		m.logger.Error("failed to list devices", zap.Error(err))
@@ -819,6 +759,10 @@ func (m *Module) handleUpdateDevice(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid JSON body")
 		return
 	}
+	if params.RackUnit != nil && *params.RackUnit < 0 {
+		writeError(w, http.StatusBadRequest, "rack_unit must not be negative")
+		return
+	}
 
 	if err := m.store.UpdateDevice(r.Context(), id, params); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -840,13 +784,15 @@ func (m *Module) handleUpdateDevice(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, device)
 }
 
-// handleDeleteDevice removes a device by ID.
+// handleDeleteDevice archives a device by ID, or removes it permanently
+// with ?hard=true.
 //
 //	@Summary		Delete device
-//	@Description	Permanently removes a device and its history.
+//	@Description	Archives a device: it is hidden from listings but keeps its history and can be restored. With hard=true the device and its history are removed permanently.
 //	@Tags			recon
 //	@Security		BearerAuth
-//	@Param			id	path	string	true	"Device ID"
+//	@Param			id		path	string	true	"Device ID"
+//	@Param			hard	query	bool	false	"Delete permanently instead of archiving"
 //	@Success		204	"No content"
 //	@Failure		400	{object}	models.APIProblem
 //	@Failure		404	{object}	models.APIProblem
@@ -859,8 +805,12 @@ func (m *Module) handleDeleteDevice(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := m.store.DeleteDevice(r.Context(), id); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+	del := m.devices.Delete
+	if r.URL.Query().Get("hard") == "true" {
+		del = m.devices.HardDelete
+	}
+	if err := del(r.Context(), id); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
 			writeError(w, http.StatusNotFound, "device not found")
 			return
 		}
@@ -871,6 +821,39 @@ func (m *Module) handleDeleteDevice(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// handleRestoreDevice un-archives a device.
+//
+//	@Summary		Restore device
+//	@Description	Restores an archived device so it appears in listings again.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Device ID"
+//	@Success		200	{object}	models.Device
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/devices/{id}/restore [post]
+func (m *Module) handleRestoreDevice(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if err := m.devices.Restore(r.Context(), id); err != nil {
+		if errors.Is(err, services.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "device not found")
+			return
+		}
+		m.logger.Error("failed to restore device", zap.String("id", id), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to restore device")
+		return
+	}
+
+	device, err := m.store.GetDevice(r.Context(), id)
+	if err != nil {
+		m.logger.Error("failed to get restored device", zap.String("id", id), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to restore device")
+		return
+	}
+	writeJSON(w, http.StatusOK, device)
+}
+
 // handleCreateDevice manually creates a new device.
 //
 //	@Summary		Create device
@@ -917,14 +900,15 @@ func (m *Module) handleCreateDevice(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusCreated, device)
 }
 
-// handleDeviceHistory returns status change history for a device.
+// handleDeviceHistory returns field change history for a device.
 //
-//	@Summary		Device status history
-//	@Description	Returns the status change timeline for a device.
+//	@Summary		Device change history
+//	@Description	Returns changes to a device's tracked fields (hostname, IP addresses, MAC, manufacturer, type, OS, status), newest first, with the source of each change: scan, api, or sync.
 //	@Tags			recon
 //	@Produce		json
 //	@Security		BearerAuth
 //	@Param			id		path		string	true	"Device ID"
+//	@Param			field	query		string	false	"Only changes to this field"	example(status)
 //	@Param			limit	query		int		false	"Max results"	default(50)
 //	@Success		200		{array}		DeviceStatusEvent
 //	@Failure		400		{object}	models.APIProblem
@@ -939,7 +923,7 @@ func (m *Module) handleDeviceHistory(w http.ResponseWriter, r *http.Request) {
 
 	limit := queryInt(r, "limit", 50)
 
-	changes, _, err := m.store.GetDeviceHistory(r.Context(), id, limit, 0)
+	changes, _, err := m.store.GetDeviceHistory(r.Context(), id, r.URL.Query().Get("field"), limit, 0)
 	if err != nil {
 		m.logger.Error("failed to get device history", zap.String("id", id), zap.Error(err))
 		writeError(w, http.StatusInternalServerError, "failed to get device history")
@@ -949,12 +933,19 @@ func (m *Module) handleDeviceHistory(w http.ResponseWriter, r *http.Request) {
 	// Map to frontend-compatible format.
 	events := make([]DeviceStatusEvent, 0, len(changes))
 	for i := range changes {
-		events = append(events, DeviceStatusEvent{
+		event := DeviceStatusEvent{
 			ID:        changes[i].ID,
 			DeviceID:  changes[i].DeviceID,
-			Status:    changes[i].NewStatus,
+			Field:     changes[i].Field,
+			OldValue:  changes[i].OldValue,
+			NewValue:  changes[i].NewValue,
+			Source:    changes[i].Source,
 			Timestamp: changes[i].ChangedAt.Format(time.RFC3339),
-		})
+		}
+		if event.Field == "status" {
+			event.Status = event.NewValue
+		}
+		events = append(events, event)
 	}
 	writeJSON(w, http.StatusOK, events)
 }
@@ -1002,6 +993,38 @@ func (m *Module) handleDeviceScans(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, events)
 }
 
+// handleDevicePorts returns the TCP port scan results for a device.
+//
+//	@Summary		Device ports
+//	@Description	Returns the state of each TCP port probed on the device by the post-scan port scan (recon.port_scan.enabled). Use open=true to list only open ports.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string	true	"Device ID"
+//	@Param			open	query		bool	false	"Only open ports"
+//	@Success		200		{array}		DevicePort
+//	@Failure		404		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/{id}/ports [get]
+func (m *Module) handleDevicePorts(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := m.store.GetDevice(r.Context(), id); err != nil {
+		writeError(w, http.StatusNotFound, "device not found")
+		return
+	}
+
+	ports, err := m.store.ListDevicePorts(r.Context(), id, r.URL.Query().Get("open") == "true")
+	if err != nil {
+		m.logger.Error("failed to list device ports", zap.String("id", id), zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list device ports")
+		return
+	}
+	if ports == nil {
+		ports = []DevicePort{}
+	}
+	writeJSON(w, http.StatusOK, ports)
+}
+
 // BulkUpdateRequest is the request body for PATCH /devices/bulk.
 type BulkUpdateRequest struct {
 	DeviceIDs []string           `json:"device_ids"`
@@ -1013,6 +1036,13 @@ type BulkUpdateResponse struct {
 	Updated int `json:"updated"`
 }
 
+// BulkTagsRequest is the request body for POST /devices/bulk/tags/add and
+// POST /devices/bulk/tags/remove.
+type BulkTagsRequest struct {
+	DeviceIDs []string `json:"device_ids"`
+	Tags      []string `json:"tags" example:"production,rack-2"`
+}
+
 // handleInventorySummary returns aggregate inventory statistics.
 //
 //	@Summary		Inventory summary
@@ -1059,6 +1089,10 @@ func (m *Module) handleBulkUpdateDevices(w http.ResponseWriter, r *http.Request)
 		writeError(w, http.StatusBadRequest, "device_ids is required")
 		return
 	}
+	if req.Updates.RackUnit != nil && *req.Updates.RackUnit < 0 {
+		writeError(w, http.StatusBadRequest, "rack_unit must not be negative")
+		return
+	}
 
 	updated, err := m.store.BulkUpdateDevices(r.Context(), req.DeviceIDs, req.Updates)
 	if err != nil {
@@ -1069,6 +1103,65 @@ func (m *Module) handleBulkUpdateDevices(w http.ResponseWriter, r *http.Request)
 	writeJSON(w, http.StatusOK, BulkUpdateResponse{Updated: updated})
 }
 
+// handleBulkAddTags adds tags to multiple devices.
+//
+//	@Summary		Bulk add device tags
+//	@Description	Adds tags to multiple devices by ID. Tags a device already has are not duplicated. Returns the number of devices whose tags changed.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		BulkTagsRequest	true	"Device IDs and tags to add"
+//	@Success		200		{object}	BulkUpdateResponse
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/bulk/tags/add [post]
+func (m *Module) handleBulkAddTags(w http.ResponseWriter, r *http.Request) {
+	m.handleBulkTags(w, r, "add", m.devices.AddTags)
+}
+
+// handleBulkRemoveTags removes tags from multiple devices.
+//
+//	@Summary		Bulk remove device tags
+//	@Description	Removes tags from multiple devices by ID, keeping their other tags. Returns the number of devices whose tags changed.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		BulkTagsRequest	true	"Device IDs and tags to remove"
+//	@Success		200		{object}	BulkUpdateResponse
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/devices/bulk/tags/remove [post]
+func (m *Module) handleBulkRemoveTags(w http.ResponseWriter, r *http.Request) {
+	m.handleBulkTags(w, r, "remove", m.devices.RemoveTags)
+}
+
+func (m *Module) handleBulkTags(w http.ResponseWriter, r *http.Request, op string,
+	apply func(ctx context.Context, ids, tags []string) (int, error)) {
+	var req BulkTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if len(req.DeviceIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "device_ids is required")
+		return
+	}
+	if len(req.Tags) == 0 {
+		writeError(w, http.StatusBadRequest, "tags is required")
+		return
+	}
+
+	updated, err := apply(r.Context(), req.DeviceIDs, req.Tags)
+	if err != nil {
+		m.logger.Error("failed to "+op+" device tags", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to "+op+" device tags")
+		return
+	}
+	writeJSON(w, http.StatusOK, BulkUpdateResponse{Updated: updated})
+}
+
 // queryInt extracts an integer query parameter with a default value.
 func queryInt(r *http.Request, key string, defaultVal int) int {
 	s := r.URL.Query().Get(key)
@@ -1230,7 +1323,7 @@ func (m *Module) handleListServiceMovements(w http.ResponseWriter, r *http.Reque
 // handleSNMPDiscover triggers SNMP discovery for a specific target.
 //
 //	@Summary		SNMP discover
-//	@Description	Discover a device via SNMP using the given credentials.
+//	@Description	Discover a device via SNMP using the given credentials. LLDP neighbors reported by the device are recorded as topology links.
 //	@Tags			recon
 //	@Accept			json
 //	@Produce		json
@@ -1266,7 +1359,7 @@ func (m *Module) handleSNMPDiscover(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 15*time.Second)
 	defer cancel()
 
-	discovered, err := m.snmpCollector.Discover(ctx, req.Target, m.credAccessor, req.CredentialID)
+	data, err := m.snmpCollector.Collect(ctx, req.Target, m.credAccessor, req.CredentialID)
 	if err != nil {
 		m.logger.Error("SNMP discovery failed",
 			zap.String("target", req.Target),
@@ -1276,17 +1369,33 @@ func (m *Module) handleSNMPDiscover(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Upsert each discovered device to the store.
-	devices := make([]models.Device, 0, len(discovered))
-	for i := range discovered {
-		if _, upsertErr := m.store.UpsertDevice(ctx, &discovered[i]); upsertErr != nil {
-			m.logger.Error("failed to upsert SNMP-discovered device",
-				zap.String("hostname", discovered[i].Hostname),
-				zap.Error(upsertErr),
+	// Upsert the discovered device to the store.
+	device := m.snmpCollector.DeviceFromData(req.Target, data)
+	devices := make([]models.Device, 0, 1)
+	if _, upsertErr := m.store.UpsertDevice(ctx, &device); upsertErr != nil {
+		m.logger.Error("failed to upsert SNMP-discovered device",
+			zap.String("hostname", device.Hostname),
+			zap.Error(upsertErr),
+		)
+		writeJSON(w, http.StatusOK, devices)
+		return
+	}
+	devices = append(devices, device)
+
+	// Record LLDP neighbors as authoritative topology links.
+	if len(data.Neighbors) > 0 {
+		links, lldpErr := NewLLDPCollector(m.logger).BuildTopologyFromLLDP(ctx, m.store, data.Neighbors, device.ID)
+		if lldpErr != nil {
+			m.logger.Warn("failed to record LLDP topology links",
+				zap.String("device_id", device.ID),
+				zap.Error(lldpErr),
+			)
+		} else {
+			m.logger.Debug("LLDP topology links recorded",
+				zap.String("device_id", device.ID),
+				zap.Int("links", links),
 			)
-			continue
 		}
-		devices = append(devices, discovered[i])
 	}
 
 	writeJSON(w, http.StatusOK, devices)
@@ -1459,10 +1568,19 @@ func (m *Module) SetCredentialProvider(cp roles.CredentialProvider) {
 	m.credProvider = cp
 }
 
+// tracerouteLimiter returns the module's traceroute limiter, sized from
+// config on first use.
+func (m *Module) tracerouteLimiter() *tracerouteLimiter {
+	m.tracerouteOnce.Do(func() {
+		m.traceroutes = newTracerouteLimiter(m.cfg.MaxTraceroutes)
+	})
+	return m.traceroutes
+}
+
 // handleTraceroute runs an ICMP traceroute to a target IP.
 //
 //	@Summary		Run traceroute
-//	@Description	Performs an ICMP traceroute to the specified target IP address
+//	@Description	Performs an ICMP traceroute to the specified target IP address, sending count probes (default 3, at most 10) per hop interval_ms apart and reporting min/avg/max RTT and loss for each hop. At most max_traceroutes (default 4) run at once; requests beyond that get 429.
 //	@Tags			recon
 //	@Accept			json
 //	@Produce		json
@@ -1490,12 +1608,14 @@ func (m *Module) handleTraceroute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Rate limit: only one concurrent traceroute at a time.
-	if !tracerouteRunning.CompareAndSwap(false, true) {
-		writeError(w, http.StatusTooManyRequests, "a traceroute is already in progress, please wait")
+	// Rate limit: bound concurrent traceroutes and reject the overflow.
+	limiter := m.tracerouteLimiter()
+	if !limiter.TryAcquire() {
+		writeError(w, http.StatusTooManyRequests,
+			fmt.Sprintf("too many traceroutes in progress (limit %d), please retry shortly", limiter.Limit()))
 		return
 	}
-	defer tracerouteRunning.Store(false)
+	defer limiter.Release()
 
 	// Apply defaults.
 	maxHops := req.MaxHops
@@ -1506,13 +1626,25 @@ func (m *Module) handleTraceroute(w http.ResponseWriter, r *http.Request) {
 	if timeoutMs <= 0 || timeoutMs > 10000 {
 		timeoutMs = 1000
 	}
+	count := req.Count
+	if count <= 0 || count > 10 {
+		count = 3
+	}
+	intervalMs := req.IntervalMs
+	if intervalMs < 0 || intervalMs > 1000 {
+		intervalMs = 0
+	}
 
 	// Run with a context timeout for the entire traceroute.
-	totalTimeout := time.Duration(maxHops) * time.Duration(timeoutMs) * time.Millisecond
+	totalTimeout := time.Duration(maxHops*count) * time.Duration(timeoutMs+intervalMs) * time.Millisecond
 	ctx, cancel := context.WithTimeout(r.Context(), totalTimeout+5*time.Second)
 	defer cancel()
 
-	result, err := RunTraceroute(ctx, req.Target, maxHops, timeoutMs, m.logger.Named("traceroute"))
+	run := m.traceroute
+	if run == nil {
+		run = RunTraceroute
+	}
+	result, err := run(ctx, req.Target, maxHops, timeoutMs, count, intervalMs, m.logger.Named("traceroute"))
 	if err != nil {
 		m.logger.Error("traceroute failed",
 			zap.String("target", req.Target),
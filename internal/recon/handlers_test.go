@@ -3,13 +3,20 @@ package recon
 import (
 	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/internal/store"
+	"github.com/HerbHall/subnetree/pkg/httperr"
 	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/pagination"
+	"github.com/HerbHall/subnetree/pkg/plugin"
 	"go.uber.org/zap"
 )
 
@@ -40,6 +47,7 @@ func newTestModule(t *testing.T) *Module {
 		logger:      logger,
 		cfg:         DefaultConfig(),
 		store:       reconStore,
+		devices:     services.NewDeviceRepository(db),
 		bus:         bus,
 		oui:         oui,
 		orchestrator: NewScanOrchestrator(reconStore, bus, oui, pinger, arp, logger),
@@ -94,6 +102,14 @@ func TestHandleScan_InvalidCIDR(t *testing.T) {
 	if ct != "application/problem+json" {
 		t.Errorf("Content-Type = %q, want application/problem+json", ct)
 	}
+
+	var problem httperr.Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatalf("decode problem: %v", err)
+	}
+	if problem.Type != "https://subnetree.com/problems/bad-request" || problem.Status != http.StatusBadRequest || problem.Title != "Bad Request" {
+		t.Errorf("problem = %+v, want the shared bad-request shape", problem)
+	}
 }
 
 func TestHandleScan_MissingSubnet(t *testing.T) {
@@ -177,6 +193,34 @@ func TestHandleListScans_WithData(t *testing.T) {
 	if len(scans) != 2 {
 		t.Errorf("scan count = %d, want 2 (paginated)", len(scans))
 	}
+	if got := w.Header().Get(pagination.TotalCountHeader); got != "3" {
+		t.Errorf("%s = %q, want 3", pagination.TotalCountHeader, got)
+	}
+}
+
+func TestHandleListScans_Envelope(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		_ = m.store.CreateScan(ctx, &models.ScanResult{Subnet: "10.0.0.0/24"})
+	}
+
+	req := httptest.NewRequest("GET", "/scans?limit=2&offset=2&envelope=true", http.NoBody)
+	w := httptest.NewRecorder()
+	m.handleListScans(w, req)
+
+	var page pagination.Page[models.ScanResult]
+	if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(page.Items) != 1 || page.Total != 3 || page.Limit != 2 || page.Offset != 2 {
+		t.Errorf("page = %d items, total %d, limit %d, offset %d; want 1, 3, 2, 2",
+			len(page.Items), page.Total, page.Limit, page.Offset)
+	}
+	if page.NextOffset != nil {
+		t.Errorf("next_offset = %d on the last page, want omitted", *page.NextOffset)
+	}
 }
 
 func TestHandleGetScan_Found(t *testing.T) {
@@ -220,6 +264,93 @@ func TestHandleGetScan_NotFound(t *testing.T) {
 	}
 }
 
+// stallingPinger reports one alive host, then blocks until the scan is
+// cancelled.
+type stallingPinger struct{ ip string }
+
+func (p stallingPinger) Scan(ctx context.Context, _ *net.IPNet, results chan<- HostResult) error {
+	results <- HostResult{IP: p.ip, Alive: true, RTT: time.Millisecond, Method: "icmp"}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestHandleCancelScan(t *testing.T) {
+	m := newTestModule(t)
+	m.orchestrator = NewScanOrchestrator(m.store, m.bus, m.oui,
+		stallingPinger{ip: "192.168.1.5"}, &mockARPReader{table: map[string]string{}}, m.logger)
+	discovered := make(chan struct{}, 1)
+	m.bus.Subscribe(TopicDeviceDiscovered, func(context.Context, plugin.Event) {
+		discovered <- struct{}{}
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /scan", m.handleScan)
+	mux.HandleFunc("POST /scans/{id}/cancel", m.handleCancelScan)
+
+	req := httptest.NewRequest("POST", "/scan", strings.NewReader(`{"subnet":"192.168.1.0/24"}`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("start status = %d, want %d", w.Code, http.StatusAccepted)
+	}
+	var started models.ScanResult
+	_ = json.NewDecoder(w.Body).Decode(&started)
+
+	select {
+	case <-discovered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first device")
+	}
+
+	req = httptest.NewRequest("POST", "/scans/"+started.ID+"/cancel", http.NoBody)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("cancel status = %d, want %d; body: %s", w.Code, http.StatusAccepted, w.Body.String())
+	}
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scan goroutine did not exit after cancel")
+	}
+
+	scan, err := m.store.GetScan(context.Background(), started.ID)
+	if err != nil {
+		t.Fatalf("GetScan: %v", err)
+	}
+	if scan.Status != "cancelled" {
+		t.Errorf("status = %q, want cancelled", scan.Status)
+	}
+	if scan.Online != 1 {
+		t.Errorf("online = %d, want 1", scan.Online)
+	}
+	devices, _, err := m.store.ListDevices(context.Background(), ListDevicesOptions{ScanID: started.ID})
+	if err != nil {
+		t.Fatalf("ListDevices: %v", err)
+	}
+	if len(devices) != 1 {
+		t.Errorf("scan devices = %d, want the 1 found before cancelling", len(devices))
+	}
+
+	// A finished scan can no longer be cancelled.
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/scans/"+started.ID+"/cancel", http.NoBody))
+	if w.Code != http.StatusConflict {
+		t.Errorf("second cancel status = %d, want %d", w.Code, http.StatusConflict)
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/scans/nonexistent/cancel", http.NoBody))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown scan status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
 func TestHandleTopology_Empty(t *testing.T) {
 	m := newTestModule(t)
 
@@ -409,9 +540,12 @@ func deviceMux(m *Module) *http.ServeMux {
 	mux.HandleFunc("GET /devices", m.handleListDevices)
 	mux.HandleFunc("POST /devices", m.handleCreateDevice)
 	mux.HandleFunc("PATCH /devices/bulk", m.handleBulkUpdateDevices)
+	mux.HandleFunc("POST /devices/bulk/tags/add", m.handleBulkAddTags)
+	mux.HandleFunc("POST /devices/bulk/tags/remove", m.handleBulkRemoveTags)
 	mux.HandleFunc("GET /devices/{id}", m.handleGetDevice)
 	mux.HandleFunc("PUT /devices/{id}", m.handleUpdateDevice)
 	mux.HandleFunc("DELETE /devices/{id}", m.handleDeleteDevice)
+	mux.HandleFunc("POST /devices/{id}/restore", m.handleRestoreDevice)
 	mux.HandleFunc("GET /devices/{id}/history", m.handleDeviceHistory)
 	mux.HandleFunc("GET /devices/{id}/scans", m.handleDeviceScans)
 	mux.HandleFunc("GET /inventory/summary", m.handleInventorySummary)
@@ -661,12 +795,152 @@ func TestHandleDeleteDevice_Success(t *testing.T) {
 		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
 	}
 
+	// Delete archives: the device is still readable by ID.
+	req2 := httptest.NewRequest("GET", "/devices/"+d.ID, http.NoBody)
+	w2 := httptest.NewRecorder()
+	mux.ServeHTTP(w2, req2)
+	if w2.Code != http.StatusOK {
+		t.Fatalf("after delete: status = %d, want %d", w2.Code, http.StatusOK)
+	}
+	var got models.Device
+	_ = json.NewDecoder(w2.Body).Decode(&got)
+	if got.ArchivedAt == nil {
+		t.Error("archived_at not set after delete")
+	}
+}
+
+func TestHandleDeleteDevice_Hard(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+	mux := deviceMux(m)
+
+	d := &models.Device{
+		Hostname: "delete-me", IPAddresses: []string{"10.0.0.1"},
+		MACAddress: "AA:BB:CC:DD:EE:01", Status: models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	_, _ = m.store.UpsertDevice(ctx, d)
+
+	req := httptest.NewRequest("DELETE", "/devices/"+d.ID+"?hard=true", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
 	// Verify it's gone.
 	req2 := httptest.NewRequest("GET", "/devices/"+d.ID, http.NoBody)
 	w2 := httptest.NewRecorder()
 	mux.ServeHTTP(w2, req2)
 	if w2.Code != http.StatusNotFound {
-		t.Errorf("after delete: status = %d, want %d", w2.Code, http.StatusNotFound)
+		t.Errorf("after hard delete: status = %d, want %d", w2.Code, http.StatusNotFound)
+	}
+}
+
+func TestHandleDeleteDevice_ArchiveAndRestore(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+	mux := deviceMux(m)
+
+	for i, host := range []string{"keep", "archive-me"} {
+		d := &models.Device{
+			Hostname: host, IPAddresses: []string{"10.0.0." + strconv.Itoa(i+1)},
+			Status: models.DeviceStatusOnline, DiscoveryMethod: models.DiscoveryICMP,
+		}
+		_, _ = m.store.UpsertDevice(ctx, d)
+		if host == "archive-me" {
+			req := httptest.NewRequest("DELETE", "/devices/"+d.ID, http.NoBody)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("delete status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+		}
+	}
+
+	list := func(query string) []models.Device {
+		t.Helper()
+		req := httptest.NewRequest("GET", "/devices"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		var resp DeviceListResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if resp.Total != len(resp.Devices) {
+			t.Errorf("total = %d, devices = %d", resp.Total, len(resp.Devices))
+		}
+		return resp.Devices
+	}
+
+	if got := list(""); len(got) != 1 || got[0].Hostname != "keep" {
+		t.Fatalf("default list = %+v, want only the unarchived device", got)
+	}
+	all := list("?include_archived=true")
+	if len(all) != 2 {
+		t.Fatalf("include_archived list has %d devices, want 2", len(all))
+	}
+	var archivedID string
+	for _, d := range all {
+		if d.Hostname == "archive-me" {
+			archivedID = d.ID
+			if d.ArchivedAt == nil {
+				t.Error("archived device listed without archived_at")
+			}
+		}
+	}
+
+	req := httptest.NewRequest("POST", "/devices/"+archivedID+"/restore", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("restore status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var restored models.Device
+	_ = json.NewDecoder(w.Body).Decode(&restored)
+	if restored.ID != archivedID || restored.ArchivedAt != nil {
+		t.Errorf("restored device = %+v", restored)
+	}
+	if got := list(""); len(got) != 2 {
+		t.Errorf("list after restore has %d devices, want 2", len(got))
+	}
+
+	req = httptest.NewRequest("POST", "/devices/nonexistent/restore", http.NoBody)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("restore nonexistent status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestUpsertDevice_RediscoveryRestoresArchived(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	d := &models.Device{
+		Hostname: "came-back", IPAddresses: []string{"10.0.0.9"},
+		MACAddress: "AA:BB:CC:DD:EE:09", Status: models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	_, _ = m.store.UpsertDevice(ctx, d)
+	if err := m.devices.Delete(ctx, d.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	again := &models.Device{
+		IPAddresses: []string{"10.0.0.9"}, MACAddress: "AA:BB:CC:DD:EE:09",
+		Status: models.DeviceStatusOnline, DiscoveryMethod: models.DiscoveryARP,
+	}
+	created, err := m.store.UpsertDevice(ctx, again)
+	if err != nil {
+		t.Fatalf("UpsertDevice: %v", err)
+	}
+	if created || again.ID != d.ID {
+		t.Errorf("rediscovery created a new device (created=%v, id=%s, want %s)", created, again.ID, d.ID)
+	}
+	got, _ := m.store.GetDevice(ctx, d.ID)
+	if got.ArchivedAt != nil {
+		t.Error("rediscovered device is still archived")
 	}
 }
 
@@ -803,6 +1077,53 @@ func TestHandleDeviceHistory_WithData(t *testing.T) {
 	}
 }
 
+func TestHandleDeviceHistory_FieldChanges(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+	mux := deviceMux(m)
+
+	d := &models.Device{
+		Hostname: "old-name", IPAddresses: []string{"10.0.0.1"},
+		MACAddress: "AA:BB:CC:DD:EE:01", Status: models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	_, _ = m.store.UpsertDevice(ctx, d)
+
+	// A rescan that only reports the same values records nothing.
+	same := &models.Device{IPAddresses: []string{"10.0.0.1"}, MACAddress: "AA:BB:CC:DD:EE:01"}
+	_, _ = m.store.UpsertDevice(ctx, same)
+	// A rescan that sees a new hostname records one change.
+	renamed := &models.Device{Hostname: "new-name", IPAddresses: []string{"10.0.0.1"}, MACAddress: "AA:BB:CC:DD:EE:01"}
+	_, _ = m.store.UpsertDevice(ctx, renamed)
+	_ = m.store.MarkDeviceOffline(ctx, d.ID)
+
+	req := httptest.NewRequest("GET", "/devices/"+d.ID+"/history", http.NoBody)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	var events []DeviceStatusEvent
+	_ = json.NewDecoder(w.Body).Decode(&events)
+	if len(events) != 2 {
+		t.Fatalf("events = %+v, want 2", events)
+	}
+	// Newest first.
+	if e := events[0]; e.Field != "status" || e.OldValue != "online" || e.NewValue != "offline" || e.Status != "offline" {
+		t.Errorf("events[0] = %+v, want status online -> offline", e)
+	}
+	if e := events[1]; e.Field != "hostname" || e.OldValue != "old-name" || e.NewValue != "new-name" || e.Source != "scan" || e.Status != "" {
+		t.Errorf("events[1] = %+v, want hostname old-name -> new-name from scan", e)
+	}
+
+	req = httptest.NewRequest("GET", "/devices/"+d.ID+"/history?field=hostname", http.NoBody)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	events = nil
+	_ = json.NewDecoder(w.Body).Decode(&events)
+	if len(events) != 1 || events[0].Field != "hostname" {
+		t.Errorf("field=hostname events = %+v, want the hostname change", events)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Inventory management handler tests
 // ---------------------------------------------------------------------------
@@ -938,6 +1259,78 @@ func TestHandleBulkUpdateDevices_EmptyIDs(t *testing.T) {
 	}
 }
 
+func TestHandleBulkTags(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+	mux := deviceMux(m)
+
+	d1 := &models.Device{
+		Hostname: "tags-1", IPAddresses: []string{"10.0.0.1"},
+		MACAddress: "AA:BB:CC:DD:EE:01", Status: models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP, Tags: []string{"lab"},
+	}
+	d2 := &models.Device{
+		Hostname: "tags-2", IPAddresses: []string{"10.0.0.2"},
+		MACAddress: "AA:BB:CC:DD:EE:02", Status: models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	_, _ = m.store.UpsertDevice(ctx, d1)
+	_, _ = m.store.UpsertDevice(ctx, d2)
+
+	post := func(path, body string) BulkUpdateResponse {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s status = %d, want %d; body: %s", path, w.Code, http.StatusOK, w.Body.String())
+		}
+		var resp BulkUpdateResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return resp
+	}
+
+	ids := `["` + d1.ID + `","` + d2.ID + `"]`
+	if resp := post("/devices/bulk/tags/add", `{"device_ids":`+ids+`,"tags":["lab","prod"]}`); resp.Updated != 2 {
+		t.Errorf("add updated = %d, want 2", resp.Updated)
+	}
+	got, _ := m.store.GetDevice(ctx, d1.ID)
+	if strings.Join(got.Tags, ",") != "lab,prod" {
+		t.Errorf("d1 tags = %v, want [lab prod]", got.Tags)
+	}
+
+	// Both devices carry "lab" after the add, so both change.
+	if resp := post("/devices/bulk/tags/remove", `{"device_ids":`+ids+`,"tags":["lab"]}`); resp.Updated != 2 {
+		t.Errorf("remove updated = %d, want 2", resp.Updated)
+	}
+	got, _ = m.store.GetDevice(ctx, d1.ID)
+	if strings.Join(got.Tags, ",") != "prod" {
+		t.Errorf("d1 tags = %v, want [prod]", got.Tags)
+	}
+
+	// Devices whose tags do not change are not counted.
+	if resp := post("/devices/bulk/tags/remove", `{"device_ids":`+ids+`,"tags":["lab"]}`); resp.Updated != 0 {
+		t.Errorf("second remove updated = %d, want 0", resp.Updated)
+	}
+}
+
+func TestHandleBulkTags_Validation(t *testing.T) {
+	m := newTestModule(t)
+	mux := deviceMux(m)
+
+	for _, body := range []string{`not json`, `{"device_ids":[],"tags":["a"]}`, `{"device_ids":["x"],"tags":[]}`} {
+		req := httptest.NewRequest("POST", "/devices/bulk/tags/add", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
 func TestHandleListDevices_FilterByCategory(t *testing.T) {
 	m := newTestModule(t)
 	ctx := context.Background()
@@ -967,3 +1360,56 @@ func TestHandleListDevices_FilterByCategory(t *testing.T) {
 		t.Errorf("total = %d, want 1", resp.Total)
 	}
 }
+
+func TestHandleTraceroute_ConcurrencyLimit(t *testing.T) {
+	m := newTestModule(t)
+	m.cfg.MaxTraceroutes = 2
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	m.traceroute = func(_ context.Context, target string, _, _, _, _ int, _ *zap.Logger) (*TracerouteResult, error) {
+		started <- struct{}{}
+		<-release
+		return &TracerouteResult{Target: target, Reached: true}, nil
+	}
+
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/traceroute", strings.NewReader(`{"target":"192.0.2.1"}`))
+		rr := httptest.NewRecorder()
+		m.handleTraceroute(rr, req)
+		return rr
+	}
+
+	// Fill every slot and wait until each traceroute is running.
+	results := make(chan *httptest.ResponseRecorder, m.cfg.MaxTraceroutes)
+	for range m.cfg.MaxTraceroutes {
+		go func() { results <- post() }()
+	}
+	for range m.cfg.MaxTraceroutes {
+		<-started
+	}
+
+	// Requests over the limit are rejected immediately.
+	for range 2 {
+		rr := post()
+		if rr.Code != http.StatusTooManyRequests {
+			t.Fatalf("overflow status = %d, want %d", rr.Code, http.StatusTooManyRequests)
+		}
+		if !strings.Contains(rr.Body.String(), "limit 2") {
+			t.Errorf("overflow body = %s, want limit in message", rr.Body.String())
+		}
+	}
+
+	close(release)
+	for range m.cfg.MaxTraceroutes {
+		if rr := <-results; rr.Code != http.StatusOK {
+			t.Errorf("in-limit status = %d, want %d", rr.Code, http.StatusOK)
+		}
+	}
+
+	// Slots are released once the running traceroutes finish.
+	go func() { <-started }()
+	if rr := post(); rr.Code != http.StatusOK {
+		t.Errorf("status after release = %d, want %d", rr.Code, http.StatusOK)
+	}
+}
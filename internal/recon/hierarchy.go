@@ -65,13 +65,73 @@ type HierarchyAssignment struct {
 	NetworkLayer   int
 }
 
+// linkTypePriority ranks topology link types by how directly they observe
+// physical wiring. LLDP is reported by the devices themselves; FDB and ARP
+// are inferred from forwarding and address tables.
+var linkTypePriority = map[string]int{
+	"lldp": 3,
+	"fdb":  2,
+	"arp":  1,
+}
+
+// preferredLinks reduces LLDP, FDB, and ARP links to one per device pair,
+// keeping the type with the highest priority. FDB and ARP links between two
+// devices that both take part in LLDP are dropped: if they were wired
+// together, LLDP would have reported it. Other link types are kept as-is.
+func preferredLinks(links []TopologyLink) []TopologyLink {
+	lldpSpeaker := make(map[string]bool)
+	for i := range links {
+		if links[i].LinkType == "lldp" {
+			lldpSpeaker[links[i].SourceDeviceID] = true
+			lldpSpeaker[links[i].TargetDeviceID] = true
+		}
+	}
+
+	type devicePair struct{ a, b string }
+	best := make(map[devicePair]int)
+	var order []devicePair
+	result := make([]TopologyLink, 0, len(links))
+	for i := range links {
+		linkType := links[i].LinkType
+		if _, ranked := linkTypePriority[linkType]; !ranked {
+			result = append(result, links[i])
+			continue
+		}
+		src, tgt := links[i].SourceDeviceID, links[i].TargetDeviceID
+		if linkType != "lldp" && lldpSpeaker[src] && lldpSpeaker[tgt] {
+			continue
+		}
+		key := devicePair{src, tgt}
+		if tgt < src {
+			key = devicePair{tgt, src}
+		}
+		j, seen := best[key]
+		if !seen {
+			best[key] = i
+			order = append(order, key)
+			continue
+		}
+		if linkTypePriority[linkType] > linkTypePriority[links[j].LinkType] {
+			best[key] = i
+		}
+	}
+
+	for _, key := range order {
+		result = append(result, links[best[key]])
+	}
+	return result
+}
+
 // InferHierarchyFromData is the pure-logic hierarchy inference function,
-// separated from I/O for testability.
+// separated from I/O for testability. When several links connect the same
+// pair of devices, LLDP links take precedence over FDB and ARP links.
 func InferHierarchyFromData(devices []models.Device, links []TopologyLink) []HierarchyAssignment {
 	if len(devices) == 0 {
 		return nil
 	}
 
+	links = preferredLinks(links)
+
 	// Build lookup maps.
 	deviceByID := make(map[string]*models.Device, len(devices))
 	for i := range devices {
@@ -85,6 +145,8 @@ func InferHierarchyFromData(devices []models.Device, links []TopologyLink) []Hie
 	linksToTarget := make(map[string][]string)
 	// fdbLinksFromSwitch[switchID] = list of target device IDs (FDB only)
 	fdbLinksFromSwitch := make(map[string][]string)
+	// lldpLinksFromDevice[sourceID] = list of LLDP neighbor device IDs
+	lldpLinksFromDevice := make(map[string][]string)
 
 	for i := range links {
 		src := links[i].SourceDeviceID
@@ -92,8 +154,11 @@ func InferHierarchyFromData(devices []models.Device, links []TopologyLink) []Hie
 		linksFromSource[src] = append(linksFromSource[src], tgt)
 		linksToTarget[tgt] = append(linksToTarget[tgt], src)
 
-		if links[i].LinkType == "fdb" {
+		switch links[i].LinkType {
+		case "fdb":
 			fdbLinksFromSwitch[src] = append(fdbLinksFromSwitch[src], tgt)
+		case "lldp":
+			lldpLinksFromDevice[src] = append(lldpLinksFromDevice[src], tgt)
 		}
 	}
 
@@ -225,24 +290,28 @@ func InferHierarchyFromData(devices []models.Device, links []TopologyLink) []Hie
 		}
 	}
 
-	// Step 5: Assign parents from FDB links (switch -> device).
-	for switchID, targets := range fdbLinksFromSwitch {
-		for _, tgtID := range targets {
-			a := assignments[tgtID]
-			if a == nil {
-				continue
-			}
-			// Don't override already-assigned infrastructure parents.
-			tgtDev := deviceByID[tgtID]
-			if tgtDev == nil {
-				continue
-			}
-			if tgtDev.DeviceType == models.DeviceTypeRouter ||
-				tgtDev.DeviceType == models.DeviceTypeFirewall {
-				continue
-			}
-			if a.ParentDeviceID == "" {
-				a.ParentDeviceID = switchID
+	// Step 5: Assign parents from LLDP links (device -> neighbor), then from
+	// FDB links (switch -> device). LLDP goes first so that observed wiring
+	// wins over forwarding-table inference.
+	for _, parentLinks := range []map[string][]string{lldpLinksFromDevice, fdbLinksFromSwitch} {
+		for switchID, targets := range parentLinks {
+			for _, tgtID := range targets {
+				a := assignments[tgtID]
+				if a == nil {
+					continue
+				}
+				// Don't override already-assigned infrastructure parents.
+				tgtDev := deviceByID[tgtID]
+				if tgtDev == nil {
+					continue
+				}
+				if tgtDev.DeviceType == models.DeviceTypeRouter ||
+					tgtDev.DeviceType == models.DeviceTypeFirewall {
+					continue
+				}
+				if a.ParentDeviceID == "" {
+					a.ParentDeviceID = switchID
+				}
 			}
 		}
 	}
@@ -223,6 +223,61 @@ func TestInferHierarchyFromData(t *testing.T) {
 	}
 }
 
+func TestInferHierarchyFromData_PrefersLLDP(t *testing.T) {
+	devices := []models.Device{
+		{ID: "router-1", DeviceType: models.DeviceTypeRouter},
+		{ID: "switch-core", DeviceType: models.DeviceTypeSwitch},
+		{ID: "switch-edge", DeviceType: models.DeviceTypeSwitch},
+		{ID: "pc-1", DeviceType: models.DeviceTypeDesktop},
+	}
+	links := []TopologyLink{
+		// The router's tables see switch-edge directly, which would make it
+		// a distribution switch. LLDP shows it is cabled to switch-core.
+		{SourceDeviceID: "router-1", TargetDeviceID: "switch-edge", LinkType: "fdb"},
+		{SourceDeviceID: "router-1", TargetDeviceID: "switch-edge", LinkType: "arp"},
+		{SourceDeviceID: "switch-core", TargetDeviceID: "switch-edge", LinkType: "arp"},
+		{SourceDeviceID: "switch-core", TargetDeviceID: "router-1", LinkType: "lldp"},
+		{SourceDeviceID: "switch-edge", TargetDeviceID: "switch-core", LinkType: "lldp"},
+		{SourceDeviceID: "switch-edge", TargetDeviceID: "pc-1", LinkType: "fdb"},
+	}
+
+	m := assignmentMap(InferHierarchyFromData(devices, links))
+
+	assertLayer(t, m, "switch-core", models.NetworkLayerDistribution)
+	assertParent(t, m, "switch-core", "router-1")
+	assertLayer(t, m, "switch-edge", models.NetworkLayerAccess)
+	assertParent(t, m, "switch-edge", "switch-core")
+	assertLayer(t, m, "pc-1", models.NetworkLayerEndpoint)
+	assertParent(t, m, "pc-1", "switch-edge")
+}
+
+func TestPreferredLinks(t *testing.T) {
+	links := []TopologyLink{
+		{ID: "arp", SourceDeviceID: "a", TargetDeviceID: "b", LinkType: "arp"},
+		{ID: "fdb", SourceDeviceID: "a", TargetDeviceID: "b", LinkType: "fdb"},
+		{ID: "lldp", SourceDeviceID: "b", TargetDeviceID: "a", LinkType: "lldp"},
+		{ID: "fdb-only", SourceDeviceID: "a", TargetDeviceID: "c", LinkType: "fdb"},
+		{ID: "arp-c", SourceDeviceID: "c", TargetDeviceID: "a", LinkType: "arp"},
+		{ID: "eth", SourceDeviceID: "a", TargetDeviceID: "b", LinkType: "ethernet"},
+	}
+
+	got := preferredLinks(links)
+
+	ids := make(map[string]bool, len(got))
+	for i := range got {
+		ids[got[i].ID] = true
+	}
+	want := []string{"lldp", "fdb-only", "eth"}
+	if len(got) != len(want) {
+		t.Fatalf("preferredLinks() returned %d links (%v), want %v", len(got), ids, want)
+	}
+	for _, id := range want {
+		if !ids[id] {
+			t.Errorf("preferredLinks() missing %q (got %v)", id, ids)
+		}
+	}
+}
+
 func TestInferHierarchy_Integration(t *testing.T) {
 	_, store, _ := setupTestModule(t)
 	ctx := context.Background()
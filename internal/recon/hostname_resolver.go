@@ -0,0 +1,213 @@
+package recon
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// HostnameResolver looks up a name for an IP address discovered by a scan.
+// Implementations return an empty name and an error when none is found.
+type HostnameResolver interface {
+	LookupHostname(ctx context.Context, ip string) (string, error)
+}
+
+// ReverseDNSResolver resolves hostnames from PTR records.
+type ReverseDNSResolver struct {
+	resolver *net.Resolver
+}
+
+// NewReverseDNSResolver creates a resolver using the system DNS settings.
+func NewReverseDNSResolver() *ReverseDNSResolver {
+	return &ReverseDNSResolver{resolver: net.DefaultResolver}
+}
+
+// LookupHostname returns the first PTR name for ip without the trailing dot.
+func (r *ReverseDNSResolver) LookupHostname(ctx context.Context, ip string) (string, error) {
+	names, err := r.resolver.LookupAddr(ctx, ip)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		if name = strings.TrimSuffix(name, "."); name != "" {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no PTR record for %s", ip)
+}
+
+// netbiosPort is the NetBIOS name service port.
+const netbiosPort = 137
+
+// NetBIOSResolver resolves hostnames with a NetBIOS node status query,
+// which Windows hosts and Samba servers answer even without DNS entries.
+type NetBIOSResolver struct{}
+
+// NewNetBIOSResolver creates a NetBIOS node status resolver.
+func NewNetBIOSResolver() *NetBIOSResolver {
+	return &NetBIOSResolver{}
+}
+
+// LookupHostname sends a node status request to ip and returns the
+// workstation name from the reply.
+func (r *NetBIOSResolver) LookupHostname(ctx context.Context, ip string) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", net.JoinHostPort(ip, fmt.Sprint(netbiosPort)))
+	if err != nil {
+		return "", fmt.Errorf("dial netbios: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(dnsTimeout)
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return "", fmt.Errorf("set netbios deadline: %w", err)
+	}
+
+	txID := uint16(time.Now().UnixNano())
+	if _, err := conn.Write(netbiosNodeStatusRequest(txID)); err != nil {
+		return "", fmt.Errorf("send netbios query: %w", err)
+	}
+	buf := make([]byte, 1024)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return "", fmt.Errorf("read netbios reply: %w", err)
+	}
+	if n < 2 || binary.BigEndian.Uint16(buf[:2]) != txID {
+		return "", errors.New("netbios reply does not match query")
+	}
+	return parseNetBIOSNodeStatus(buf[:n])
+}
+
+// netbiosNodeStatusRequest builds an NBSTAT query for the wildcard name "*".
+func netbiosNodeStatusRequest(txID uint16) []byte {
+	pkt := make([]byte, 0, 50)
+	pkt = binary.BigEndian.AppendUint16(pkt, txID)
+	pkt = append(pkt,
+		0x00, 0x00, // flags: query
+		0x00, 0x01, // QDCOUNT
+		0x00, 0x00, // ANCOUNT
+		0x00, 0x00, // NSCOUNT
+		0x00, 0x00, // ARCOUNT
+	)
+	// First-level encoding of "*" padded with NULs to 16 bytes: each
+	// nibble becomes 'A'+nibble.
+	name := [16]byte{'*'}
+	pkt = append(pkt, 0x20)
+	for _, c := range name {
+		pkt = append(pkt, 'A'+(c>>4), 'A'+(c&0x0f))
+	}
+	pkt = append(pkt, 0x00)
+	pkt = append(pkt,
+		0x00, 0x21, // QTYPE NBSTAT
+		0x00, 0x01, // QCLASS IN
+	)
+	return pkt
+}
+
+// parseNetBIOSNodeStatus extracts the workstation name (suffix 0x00, not a
+// group name) from a node status reply.
+func parseNetBIOSNodeStatus(b []byte) (string, error) {
+	const headerLen = 12
+	if len(b) < headerLen {
+		return "", errors.New("netbios reply too short")
+	}
+	if binary.BigEndian.Uint16(b[6:8]) == 0 {
+		return "", errors.New("netbios reply has no answers")
+	}
+
+	// Skip the answer's name.
+	off := headerLen
+	for {
+		if off >= len(b) {
+			return "", errors.New("netbios reply truncated in name")
+		}
+		l := int(b[off])
+		if l == 0 {
+			off++
+			break
+		}
+		if l&0xc0 == 0xc0 {
+			off += 2
+			break
+		}
+		off += 1 + l
+	}
+
+	// TYPE, CLASS, TTL, RDLENGTH, then NUM_NAMES.
+	off += 2 + 2 + 4 + 2
+	if off >= len(b) {
+		return "", errors.New("netbios reply truncated before names")
+	}
+	count := int(b[off])
+	off++
+
+	const entryLen = 18 // 15-byte name, 1-byte suffix, 2-byte flags
+	for i := 0; i < count; i++ {
+		if off+entryLen > len(b) {
+			break
+		}
+		entry := b[off : off+entryLen]
+		off += entryLen
+		suffix := entry[15]
+		group := binary.BigEndian.Uint16(entry[16:18])&0x8000 != 0
+		if suffix != 0x00 || group {
+			continue
+		}
+		if name := strings.TrimRight(string(entry[:15]), " \x00"); name != "" {
+			return name, nil
+		}
+	}
+	return "", errors.New("netbios reply has no workstation name")
+}
+
+// hostnameResolverChain tries each resolver in order and returns the first
+// name found.
+type hostnameResolverChain []HostnameResolver
+
+// LookupHostname implements HostnameResolver.
+func (c hostnameResolverChain) LookupHostname(ctx context.Context, ip string) (string, error) {
+	var errs []error
+	for _, r := range c {
+		if ctx.Err() != nil {
+			break
+		}
+		name, err := r.LookupHostname(ctx, ip)
+		if err == nil && name != "" {
+			return name, nil
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return "", fmt.Errorf("no hostname for %s", ip)
+	}
+	return "", errors.Join(errs...)
+}
+
+// NewHostnameResolver builds the resolver configured by cfg, or nil when
+// hostname lookup is disabled.
+func NewHostnameResolver(cfg HostnameLookupConfig) HostnameResolver {
+	var chain hostnameResolverChain
+	if cfg.ReverseDNS {
+		chain = append(chain, NewReverseDNSResolver())
+	}
+	if cfg.NetBIOS {
+		chain = append(chain, NewNetBIOSResolver())
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	default:
+		return chain
+	}
+}
@@ -0,0 +1,133 @@
+package recon
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// stubHostnameResolver returns fixed names by IP and fails for others.
+type stubHostnameResolver struct {
+	names map[string]string
+}
+
+func (s *stubHostnameResolver) LookupHostname(_ context.Context, ip string) (string, error) {
+	if name, ok := s.names[ip]; ok {
+		return name, nil
+	}
+	return "", errors.New("not found")
+}
+
+func TestScanOrchestrator_HostnameEnrichment(t *testing.T) {
+	pinger := &mockPingScanner{
+		results: []HostResult{
+			{IP: "192.168.1.10", Alive: true, Method: "icmp"},
+			{IP: "192.168.1.11", Alive: true, Method: "icmp"},
+			{IP: "192.168.1.12", Alive: true, Method: "icmp"},
+		},
+	}
+	orch, reconStore, _ := setupOrchestrator(t, pinger, &mockARPReader{}, &mockOUI{table: map[string]string{}})
+	orch.SetHostnameResolver(hostnameResolverChain{
+		&stubHostnameResolver{names: map[string]string{"192.168.1.10": "nas.lan"}},
+		&stubHostnameResolver{names: map[string]string{"192.168.1.11": "DESKTOP-42"}},
+	}, time.Second)
+
+	ctx := context.Background()
+	scan := &models.ScanResult{ID: "scan-names", Subnet: "192.168.1.0/24", Status: "running"}
+	if err := reconStore.CreateScan(ctx, scan); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+	orch.RunScan(ctx, "scan-names", "192.168.1.0/24")
+
+	want := map[string]string{
+		"192.168.1.10": "nas.lan",
+		"192.168.1.11": "DESKTOP-42",
+		"192.168.1.12": "",
+	}
+	for ip, name := range want {
+		d, err := reconStore.GetDeviceByIP(ctx, ip)
+		if err != nil {
+			t.Fatalf("GetDeviceByIP(%s): %v", ip, err)
+		}
+		if d == nil {
+			t.Fatalf("device %s not found", ip)
+		}
+		if d.Hostname != name {
+			t.Errorf("device %s hostname = %q, want %q", ip, d.Hostname, name)
+		}
+	}
+}
+
+func TestScanOrchestrator_HostnameLookupDisabled(t *testing.T) {
+	orch, _, _ := setupOrchestrator(t, &mockPingScanner{}, &mockARPReader{}, &mockOUI{table: map[string]string{}})
+	orch.SetHostnameResolver(nil, 0)
+
+	if got := orch.resolveHostname("127.0.0.1"); got != "" {
+		t.Errorf("resolveHostname() = %q with lookup disabled, want empty", got)
+	}
+}
+
+func TestNewHostnameResolver(t *testing.T) {
+	if r := NewHostnameResolver(HostnameLookupConfig{}); r != nil {
+		t.Errorf("NewHostnameResolver(disabled) = %T, want nil", r)
+	}
+	if _, ok := NewHostnameResolver(HostnameLookupConfig{ReverseDNS: true}).(*ReverseDNSResolver); !ok {
+		t.Error("NewHostnameResolver(reverse_dns) is not a *ReverseDNSResolver")
+	}
+	r := NewHostnameResolver(HostnameLookupConfig{ReverseDNS: true, NetBIOS: true})
+	if chain, ok := r.(hostnameResolverChain); !ok || len(chain) != 2 {
+		t.Errorf("NewHostnameResolver(both) = %#v, want chain of 2", r)
+	}
+}
+
+// netbiosReply builds a node status reply holding the given entries.
+func netbiosReply(entries ...[18]byte) []byte {
+	b := netbiosNodeStatusRequest(0x1234)[:12]
+	b[2], b[3] = 0x84, 0x00                              // response, authoritative
+	b[4], b[5] = 0x00, 0x00                              // QDCOUNT
+	b[6], b[7] = 0x00, 0x01                              // ANCOUNT
+	b = append(b, netbiosNodeStatusRequest(0)[12:46]...) // encoded name
+	b = append(b, 0x00, 0x21, 0x00, 0x01, 0, 0, 0, 0)
+	b = binary.BigEndian.AppendUint16(b, uint16(1+18*len(entries)))
+	b = append(b, byte(len(entries)))
+	for _, e := range entries {
+		b = append(b, e[:]...)
+	}
+	return b
+}
+
+func netbiosEntry(name string, suffix byte, group bool) [18]byte {
+	var e [18]byte
+	copy(e[:15], []byte(name + "               ")[:15])
+	e[15] = suffix
+	if group {
+		e[16] = 0x80
+	}
+	return e
+}
+
+func TestParseNetBIOSNodeStatus(t *testing.T) {
+	reply := netbiosReply(
+		netbiosEntry("WORKGROUP", 0x00, true),
+		netbiosEntry("DESKTOP-42", 0x20, false),
+		netbiosEntry("DESKTOP-42", 0x00, false),
+	)
+	got, err := parseNetBIOSNodeStatus(reply)
+	if err != nil {
+		t.Fatalf("parseNetBIOSNodeStatus: %v", err)
+	}
+	if got != "DESKTOP-42" {
+		t.Errorf("name = %q, want %q", got, "DESKTOP-42")
+	}
+
+	if _, err := parseNetBIOSNodeStatus(netbiosReply(netbiosEntry("WORKGROUP", 0x00, true))); err == nil {
+		t.Error("parseNetBIOSNodeStatus(group only) error = nil, want error")
+	}
+	if _, err := parseNetBIOSNodeStatus(reply[:20]); err == nil {
+		t.Error("parseNetBIOSNodeStatus(truncated) error = nil, want error")
+	}
+}
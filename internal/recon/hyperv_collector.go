@@ -0,0 +1,390 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ErrHyperVAuth is returned when the WinRM endpoint rejects the credentials.
+var ErrHyperVAuth = errors.New("winrm authentication failed")
+
+// HyperVCollector collects host and VM inventory from a Hyper-V host over
+// WinRM. It sends WS-Management Enumerate/Pull requests for the WMI classes
+// in root/virtualization/v2 and root/cimv2 directly over net/http (the same
+// approach as VSphereCollector), so it builds and runs on any OS.
+//
+// Authentication is HTTP Basic, which the WinRM service must allow
+// (winrm set winrm/config/service/auth @{Basic="true"}); use the HTTPS
+// listener on port 5986 so credentials are not sent in the clear.
+type HyperVCollector struct {
+	baseURL    string
+	username   string
+	password   string //nolint:gosec // G101: field name, not a credential
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// HyperVHost represents the Hyper-V host itself.
+type HyperVHost struct {
+	Name         string `json:"name"`
+	Manufacturer string `json:"manufacturer"`
+	Model        string `json:"model"`
+	CPUModel     string `json:"cpu_model"`
+	CPUCores     int    `json:"cpu_cores"`
+	CPUThreads   int    `json:"cpu_threads"`
+	MemoryBytes  int64  `json:"memory_bytes"`
+}
+
+// HyperVVM represents a virtual machine on the host.
+type HyperVVM struct {
+	ID       string `json:"id"` // VM GUID
+	Name     string `json:"name"`
+	State    string `json:"state"` // "running", "off", "saved", "paused", "starting", "stopping", "unknown"
+	NumCPU   int    `json:"num_cpu"`
+	MemoryMB int    `json:"memory_mb"` // Startup memory from the VM settings
+}
+
+// WMI resource URIs used by the collector.
+const (
+	hypervWMIBase        = "http://schemas.microsoft.com/wbem/wsman/1/wmi/"
+	hypervComputerSystem = hypervWMIBase + "root/virtualization/v2/Msvm_ComputerSystem"
+	hypervProcessorData  = hypervWMIBase + "root/virtualization/v2/Msvm_ProcessorSettingData"
+	hypervMemoryData     = hypervWMIBase + "root/virtualization/v2/Msvm_MemorySettingData"
+	hypervWin32System    = hypervWMIBase + "root/cimv2/Win32_ComputerSystem"
+	hypervWin32Processor = hypervWMIBase + "root/cimv2/Win32_Processor"
+)
+
+// WS-Management actions.
+const (
+	wsmanActionEnumerate = "http://schemas.xmlsoap.org/ws/2004/09/enumeration/Enumerate"
+	wsmanActionPull      = "http://schemas.xmlsoap.org/ws/2004/09/enumeration/Pull"
+)
+
+// wsmanMaxElements is the number of instances requested per round trip.
+const wsmanMaxElements = 100
+
+// wsmanObject is one WMI instance. Each child element is a property.
+type wsmanObject struct {
+	Props []struct {
+		XMLName xml.Name
+		Value   string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// wsmanEnumeration is the body of an EnumerateResponse or PullResponse.
+// Enumerate returns items under w:Items and Pull under n:Items; both match
+// by local name.
+type wsmanEnumeration struct {
+	Context string `xml:"EnumerationContext"`
+	Items   struct {
+		Objects []wsmanObject `xml:",any"`
+	} `xml:"Items"`
+	EndOfSequence *struct{} `xml:"EndOfSequence"`
+}
+
+// wsmanEnvelope is the SOAP 1.2 response envelope. The response element is
+// kept as raw XML and decoded by the caller.
+type wsmanEnvelope struct {
+	Body struct {
+		Fault *struct {
+			Reason string `xml:"Reason>Text"`
+		} `xml:"Fault"`
+		Inner []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// NewHyperVCollector creates a new collector for a Hyper-V host. baseURL is
+// the WinRM listener root (e.g. "https://hv01.lan:5986"); requests go to
+// baseURL + "/wsman".
+func NewHyperVCollector(baseURL, username, password string, logger *zap.Logger) *HyperVCollector {
+	return &HyperVCollector{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12,
+					//nolint:gosec // G402: WinRM HTTPS listeners commonly use self-signed certs.
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		logger: logger,
+	}
+}
+
+// CollectHost returns the host's hardware summary.
+func (c *HyperVCollector) CollectHost(ctx context.Context) (*HyperVHost, error) {
+	systems, err := c.enumerate(ctx, hypervWin32System)
+	if err != nil {
+		return nil, fmt.Errorf("get computer system: %w", err)
+	}
+	if len(systems) == 0 {
+		return nil, errors.New("get computer system: no instances returned")
+	}
+	cs := systems[0].props()
+
+	host := &HyperVHost{
+		Name:         cs["DNSHostName"],
+		Manufacturer: cs["Manufacturer"],
+		Model:        cs["Model"],
+		CPUThreads:   atoiOrZero(cs["NumberOfLogicalProcessors"]),
+		MemoryBytes:  parseInt64OrZero(cs["TotalPhysicalMemory"]),
+	}
+	if host.Name == "" {
+		host.Name = cs["Name"]
+	}
+
+	// Sum cores across sockets; all sockets share a model.
+	processors, err := c.enumerate(ctx, hypervWin32Processor)
+	if err != nil {
+		return nil, fmt.Errorf("list processors: %w", err)
+	}
+	for _, obj := range processors {
+		p := obj.props()
+		if host.CPUModel == "" {
+			host.CPUModel = strings.TrimSpace(p["Name"])
+		}
+		host.CPUCores += atoiOrZero(p["NumberOfCores"])
+	}
+	return host, nil
+}
+
+// CollectVMs returns all virtual machines on the host with their vCPU count
+// and startup memory.
+func (c *HyperVCollector) CollectVMs(ctx context.Context) ([]HyperVVM, error) {
+	systems, err := c.enumerate(ctx, hypervComputerSystem)
+	if err != nil {
+		return nil, fmt.Errorf("list vms: %w", err)
+	}
+
+	// Settings are keyed by the VM GUID embedded in their InstanceID.
+	cpus, err := c.settingsByVM(ctx, hypervProcessorData)
+	if err != nil {
+		return nil, fmt.Errorf("list processor settings: %w", err)
+	}
+	memory, err := c.settingsByVM(ctx, hypervMemoryData)
+	if err != nil {
+		return nil, fmt.Errorf("list memory settings: %w", err)
+	}
+
+	var vms []HyperVVM
+	for _, obj := range systems {
+		p := obj.props()
+		// Msvm_ComputerSystem also lists the host itself.
+		if p["Caption"] != "Virtual Machine" {
+			continue
+		}
+		id := p["Name"]
+		key := strings.ToUpper(id)
+		if _, ok := cpus[key]; !ok {
+			c.logger.Debug("hyper-v vm has no processor settings", zap.String("vm", p["ElementName"]))
+		}
+		vms = append(vms, HyperVVM{
+			ID:       id,
+			Name:     p["ElementName"],
+			State:    hypervState(p["EnabledState"]),
+			NumCPU:   atoiOrZero(cpus[key]),
+			MemoryMB: atoiOrZero(memory[key]),
+		})
+	}
+	return vms, nil
+}
+
+// settingsByVM maps VM GUIDs to the VirtualQuantity of a settings class.
+// InstanceIDs look like "Microsoft:<VM GUID>\<device GUID>\0"; snapshot
+// settings carry the snapshot's GUID and so never match a VM.
+func (c *HyperVCollector) settingsByVM(ctx context.Context, resourceURI string) (map[string]string, error) {
+	objects, err := c.enumerate(ctx, resourceURI)
+	if err != nil {
+		return nil, err
+	}
+	settings := make(map[string]string, len(objects))
+	for _, obj := range objects {
+		p := obj.props()
+		id, _, _ := strings.Cut(strings.TrimPrefix(p["InstanceID"], "Microsoft:"), `\`)
+		if id != "" {
+			settings[strings.ToUpper(id)] = p["VirtualQuantity"]
+		}
+	}
+	return settings, nil
+}
+
+// hypervState translates Msvm_ComputerSystem.EnabledState, which mixes CIM
+// and Hyper-V specific values.
+func hypervState(enabledState string) string {
+	switch enabledState {
+	case "2":
+		return "running"
+	case "3":
+		return "off"
+	case "6", "32769":
+		return "saved"
+	case "9", "32768":
+		return "paused"
+	case "10", "32770":
+		return "starting"
+	case "4", "32774":
+		return "stopping"
+	default:
+		return "unknown"
+	}
+}
+
+// Hardware maps the host onto the hardware profile used for Proxmox nodes.
+func (h *HyperVHost) Hardware() *models.DeviceHardware {
+	now := time.Now().UTC()
+	return &models.DeviceHardware{
+		Hostname:           h.Name,
+		CPUModel:           h.CPUModel,
+		CPUCores:           h.CPUCores,
+		CPUThreads:         h.CPUThreads,
+		RAMTotalMB:         int(h.MemoryBytes / (1024 * 1024)),
+		PlatformType:       "baremetal",
+		Hypervisor:         "hyperv",
+		SystemManufacturer: h.Manufacturer,
+		SystemModel:        h.Model,
+		CollectionSource:   "hyperv-winrm",
+		CollectedAt:        &now,
+	}
+}
+
+// Hardware maps the VM onto the hardware profile used for Proxmox guests.
+func (v *HyperVVM) Hardware() *models.DeviceHardware {
+	now := time.Now().UTC()
+	return &models.DeviceHardware{
+		Hostname:         v.Name,
+		CPUCores:         v.NumCPU,
+		RAMTotalMB:       v.MemoryMB,
+		PlatformType:     "vm",
+		Hypervisor:       "hyperv",
+		CollectionSource: "hyperv-winrm",
+		CollectedAt:      &now,
+	}
+}
+
+// GuestStatus translates the VM state into the "running"/"stopped"
+// vocabulary the Proxmox guest sync uses to derive device status.
+func (v *HyperVVM) GuestStatus() string {
+	if v.State == "running" {
+		return "running"
+	}
+	return "stopped"
+}
+
+// enumerate returns every instance of a WMI class, pulling until the
+// enumeration ends.
+func (c *HyperVCollector) enumerate(ctx context.Context, resourceURI string) ([]wsmanObject, error) {
+	var res wsmanEnumeration
+	body := `<n:Enumerate><w:OptimizeEnumeration/>` +
+		fmt.Sprintf(`<w:MaxElements>%d</w:MaxElements>`, wsmanMaxElements) +
+		`</n:Enumerate>`
+	if err := c.call(ctx, resourceURI, wsmanActionEnumerate, body, &res); err != nil {
+		return nil, fmt.Errorf("enumerate: %w", err)
+	}
+	objects := res.Items.Objects
+
+	for res.EndOfSequence == nil && res.Context != "" {
+		enumCtx := res.Context
+		res = wsmanEnumeration{}
+		body := `<n:Pull><n:EnumerationContext>` + xmlEscape(enumCtx) + `</n:EnumerationContext>` +
+			fmt.Sprintf(`<n:MaxElements>%d</n:MaxElements>`, wsmanMaxElements) +
+			`</n:Pull>`
+		if err := c.call(ctx, resourceURI, wsmanActionPull, body, &res); err != nil {
+			return nil, fmt.Errorf("pull: %w", err)
+		}
+		objects = append(objects, res.Items.Objects...)
+	}
+	return objects, nil
+}
+
+// call posts a WS-Management request to /wsman and decodes the response
+// element into out.
+func (c *HyperVCollector) call(ctx context.Context, resourceURI, action, body string, out any) error {
+	endpoint := c.baseURL + "/wsman"
+	envelope := `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope"` +
+		` xmlns:a="http://schemas.xmlsoap.org/ws/2004/08/addressing"` +
+		` xmlns:n="http://schemas.xmlsoap.org/ws/2004/09/enumeration"` +
+		` xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd">` +
+		`<s:Header>` +
+		`<a:To>` + xmlEscape(endpoint) + `</a:To>` +
+		`<w:ResourceURI s:mustUnderstand="true">` + resourceURI + `</w:ResourceURI>` +
+		`<a:ReplyTo><a:Address s:mustUnderstand="true">http://schemas.xmlsoap.org/ws/2004/08/addressing/role/anonymous</a:Address></a:ReplyTo>` +
+		`<a:Action s:mustUnderstand="true">` + action + `</a:Action>` +
+		`<w:MaxEnvelopeSize s:mustUnderstand="true">512000</w:MaxEnvelopeSize>` +
+		`<a:MessageID>uuid:` + uuid.NewString() + `</a:MessageID>` +
+		`<w:OperationTimeout>PT60S</w:OperationTimeout>` +
+		`</s:Header>` +
+		`<s:Body>` + body + `</s:Body></s:Envelope>`
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(envelope))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/soap+xml;charset=UTF-8")
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w for user %q: check the credentials and that the WinRM service allows Basic authentication",
+			ErrHyperVAuth, c.username)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	// Faults come back as HTTP 500 with a SOAP envelope, so try to decode
+	// before looking at the status code.
+	var env wsmanEnvelope
+	if xErr := xml.Unmarshal(respBody, &env); xErr != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("winrm returned status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("parse response envelope: %w", xErr)
+	}
+	if env.Body.Fault != nil {
+		return fmt.Errorf("winrm fault: %s", strings.TrimSpace(env.Body.Fault.Reason))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("winrm returned status %d", resp.StatusCode)
+	}
+
+	if err := xml.Unmarshal(bytes.TrimSpace(env.Body.Inner), out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// props flattens an instance's properties into a name-to-value map. For
+// array properties the first element wins.
+func (o *wsmanObject) props() map[string]string {
+	m := make(map[string]string, len(o.Props))
+	for _, p := range o.Props {
+		if _, ok := m[p.XMLName.Local]; !ok {
+			m[p.XMLName.Local] = strings.TrimSpace(p.Value)
+		}
+	}
+	return m
+}
@@ -0,0 +1,237 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// hypervTestHandler serves a minimal WS-Management endpoint. Requests are
+// dispatched on the resource URI: Enumerate responses come from enums and
+// Pull responses from pulls, both keyed by WMI class name.
+func hypervTestHandler(t *testing.T, enums, pulls map[string]string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/wsman" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != `HV01\Administrator` || pass != "s3cret&" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="WSMAN"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		body := string(b)
+
+		class := ""
+		if i := strings.Index(body, "</w:ResourceURI>"); i >= 0 {
+			class = body[strings.LastIndex(body[:i], "/")+1 : i]
+		}
+
+		var inner string
+		var found bool
+		switch {
+		case strings.Contains(body, "<n:Enumerate>"):
+			inner, found = enums[class]
+		case strings.Contains(body, "<n:Pull>"):
+			if !strings.Contains(body, "<n:EnumerationContext>uuid:ctx-"+class+"</n:EnumerationContext>") {
+				t.Errorf("Pull for %s sent without its enumeration context: %s", class, body)
+			}
+			inner, found = pulls[class]
+		}
+		if !found {
+			w.WriteHeader(http.StatusInternalServerError)
+			inner = `<s:Fault><s:Code><s:Value>s:Sender</s:Value></s:Code>` +
+				`<s:Reason><s:Text xml:lang="en-US">The WS-Management service cannot process the request. ` +
+				`The WMI service or the WMI provider returned an unknown error.</s:Text></s:Reason></s:Fault>`
+		}
+
+		w.Header().Set("Content-Type", "application/soap+xml;charset=UTF-8")
+		_, _ = io.WriteString(w, `<s:Envelope xmlns:s="http://www.w3.org/2003/05/soap-envelope" `+
+			`xmlns:n="http://schemas.xmlsoap.org/ws/2004/09/enumeration" `+
+			`xmlns:w="http://schemas.dmtf.org/wbem/wsman/1/wsman.xsd"><s:Header/><s:Body>`+
+			inner+`</s:Body></s:Envelope>`)
+	}
+}
+
+// hypervEnumerate wraps instances in an EnumerateResponse. A non-empty
+// class leaves the enumeration open for a Pull.
+func hypervEnumerate(pullClass, items string) string {
+	end := `<w:EndOfSequence/>`
+	if pullClass != "" {
+		end = ""
+	}
+	return `<n:EnumerateResponse><n:EnumerationContext>uuid:ctx-` + pullClass + `</n:EnumerationContext>` +
+		`<w:Items>` + items + `</w:Items>` + end + `</n:EnumerateResponse>`
+}
+
+const (
+	hypervVMWeb = `<p:Msvm_ComputerSystem xmlns:p="http://schemas.microsoft.com/wbem/wsman/1/wmi/root/virtualization/v2/Msvm_ComputerSystem">
+<p:Caption>Virtual Machine</p:Caption><p:ElementName>web-01</p:ElementName>
+<p:EnabledState>2</p:EnabledState><p:Name>B637F346-6A0E-4DEC-AF52-BD70CB80A21D</p:Name>
+<p:OperationalStatus>2</p:OperationalStatus><p:OperationalStatus>32768</p:OperationalStatus>
+</p:Msvm_ComputerSystem>`
+	hypervHostSystem = `<p:Msvm_ComputerSystem xmlns:p="http://schemas.microsoft.com/wbem/wsman/1/wmi/root/virtualization/v2/Msvm_ComputerSystem">
+<p:Caption>Hosting Computer System</p:Caption><p:ElementName>HV01</p:ElementName>
+<p:EnabledState>2</p:EnabledState><p:Name>HV01</p:Name>
+</p:Msvm_ComputerSystem>`
+	hypervVMBuild = `<p:Msvm_ComputerSystem xmlns:p="http://schemas.microsoft.com/wbem/wsman/1/wmi/root/virtualization/v2/Msvm_ComputerSystem">
+<p:Caption>Virtual Machine</p:Caption><p:ElementName>build-agent</p:ElementName>
+<p:EnabledState>32769</p:EnabledState><p:Name>4A1C2E9B-0D3F-4B5A-9C7E-1F2A3B4C5D6E</p:Name>
+</p:Msvm_ComputerSystem>`
+)
+
+func hypervVMResponses() (enums, pulls map[string]string) {
+	enums = map[string]string{
+		"Msvm_ComputerSystem": hypervEnumerate("Msvm_ComputerSystem", hypervVMWeb+hypervHostSystem),
+		"Msvm_ProcessorSettingData": hypervEnumerate("", `<p:Msvm_ProcessorSettingData xmlns:p="x">
+<p:InstanceID>Microsoft:B637F346-6A0E-4DEC-AF52-BD70CB80A21D\b637f346-6a0e-4dec-af52-bd70cb80a21d\0</p:InstanceID>
+<p:VirtualQuantity>4</p:VirtualQuantity></p:Msvm_ProcessorSettingData>
+<p:Msvm_ProcessorSettingData xmlns:p="x">
+<p:InstanceID>Microsoft:4A1C2E9B-0D3F-4B5A-9C7E-1F2A3B4C5D6E\4a1c2e9b-0d3f-4b5a-9c7e-1f2a3b4c5d6e\0</p:InstanceID>
+<p:VirtualQuantity>8</p:VirtualQuantity></p:Msvm_ProcessorSettingData>`),
+		"Msvm_MemorySettingData": hypervEnumerate("", `<p:Msvm_MemorySettingData xmlns:p="x">
+<p:InstanceID>Microsoft:B637F346-6A0E-4DEC-AF52-BD70CB80A21D\4764334d-e001-4176-82ee-5594ec9b530e</p:InstanceID>
+<p:VirtualQuantity>8192</p:VirtualQuantity></p:Msvm_MemorySettingData>
+<p:Msvm_MemorySettingData xmlns:p="x">
+<p:InstanceID>Microsoft:4A1C2E9B-0D3F-4B5A-9C7E-1F2A3B4C5D6E\4764334d-e001-4176-82ee-5594ec9b530e</p:InstanceID>
+<p:VirtualQuantity>16384</p:VirtualQuantity></p:Msvm_MemorySettingData>`),
+	}
+	pulls = map[string]string{
+		"Msvm_ComputerSystem": `<n:PullResponse><n:Items>` + hypervVMBuild + `</n:Items><n:EndOfSequence/></n:PullResponse>`,
+	}
+	return enums, pulls
+}
+
+func TestHyperVCollector_CollectVMs(t *testing.T) {
+	enums, pulls := hypervVMResponses()
+	srv := newTestProxmoxServer(t, hypervTestHandler(t, enums, pulls))
+
+	c := NewHyperVCollector(srv.URL, `HV01\Administrator`, "s3cret&", zap.NewNop())
+	vms, err := c.CollectVMs(context.Background())
+	if err != nil {
+		t.Fatalf("CollectVMs() error = %v", err)
+	}
+	if len(vms) != 2 {
+		t.Fatalf("CollectVMs() returned %d vms, want 2 (host entry excluded)", len(vms))
+	}
+
+	tests := []struct {
+		name       string
+		wantID     string
+		wantState  string
+		wantStatus string
+		wantCPU    int
+		wantMemMB  int
+	}{
+		{"web-01", "B637F346-6A0E-4DEC-AF52-BD70CB80A21D", "running", "running", 4, 8192},
+		{"build-agent", "4A1C2E9B-0D3F-4B5A-9C7E-1F2A3B4C5D6E", "saved", "stopped", 8, 16384},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := vms[i]
+			if vm.Name != tt.name || vm.ID != tt.wantID {
+				t.Errorf("vm = %q/%q, want %q/%q", vm.Name, vm.ID, tt.name, tt.wantID)
+			}
+			if vm.State != tt.wantState || vm.GuestStatus() != tt.wantStatus {
+				t.Errorf("state = %q (%q), want %q (%q)", vm.State, vm.GuestStatus(), tt.wantState, tt.wantStatus)
+			}
+			if vm.NumCPU != tt.wantCPU || vm.MemoryMB != tt.wantMemMB {
+				t.Errorf("resources = %d vCPU/%d MB, want %d/%d", vm.NumCPU, vm.MemoryMB, tt.wantCPU, tt.wantMemMB)
+			}
+			hw := vm.Hardware()
+			if hw.PlatformType != "vm" || hw.Hypervisor != "hyperv" || hw.RAMTotalMB != tt.wantMemMB {
+				t.Errorf("Hardware() = %+v, want vm on hyperv with %d MB", hw, tt.wantMemMB)
+			}
+		})
+	}
+}
+
+func TestHyperVCollector_CollectHost(t *testing.T) {
+	enums := map[string]string{
+		"Win32_ComputerSystem": hypervEnumerate("", `<p:Win32_ComputerSystem xmlns:p="x">
+<p:DNSHostName>hv01</p:DNSHostName><p:Manufacturer>HPE</p:Manufacturer>
+<p:Model>ProLiant DL380 Gen10</p:Model><p:Name>HV01</p:Name>
+<p:NumberOfLogicalProcessors>48</p:NumberOfLogicalProcessors>
+<p:TotalPhysicalMemory>274799050752</p:TotalPhysicalMemory></p:Win32_ComputerSystem>`),
+		"Win32_Processor": hypervEnumerate("", `<p:Win32_Processor xmlns:p="x">
+<p:Name>Intel(R) Xeon(R) Silver 4214 CPU @ 2.20GHz</p:Name><p:NumberOfCores>12</p:NumberOfCores></p:Win32_Processor>
+<p:Win32_Processor xmlns:p="x">
+<p:Name>Intel(R) Xeon(R) Silver 4214 CPU @ 2.20GHz</p:Name><p:NumberOfCores>12</p:NumberOfCores></p:Win32_Processor>`),
+	}
+	srv := newTestProxmoxServer(t, hypervTestHandler(t, enums, nil))
+
+	c := NewHyperVCollector(srv.URL, `HV01\Administrator`, "s3cret&", zap.NewNop())
+	host, err := c.CollectHost(context.Background())
+	if err != nil {
+		t.Fatalf("CollectHost() error = %v", err)
+	}
+	if host.Name != "hv01" || host.Manufacturer != "HPE" || host.Model != "ProLiant DL380 Gen10" {
+		t.Errorf("host = %+v, want hv01 HPE ProLiant DL380 Gen10", host)
+	}
+	if host.CPUCores != 24 || host.CPUThreads != 48 {
+		t.Errorf("CPU = %d cores/%d threads, want 24/48 across two sockets", host.CPUCores, host.CPUThreads)
+	}
+	if host.CPUModel != "Intel(R) Xeon(R) Silver 4214 CPU @ 2.20GHz" {
+		t.Errorf("CPUModel = %q", host.CPUModel)
+	}
+
+	hw := host.Hardware()
+	if hw.RAMTotalMB != 262068 || hw.Hypervisor != "hyperv" || hw.PlatformType != "baremetal" {
+		t.Errorf("Hardware() = %+v, want 262068 MB baremetal hyperv", hw)
+	}
+}
+
+func TestHyperVCollector_AuthFailure(t *testing.T) {
+	srv := newTestProxmoxServer(t, hypervTestHandler(t, nil, nil))
+
+	c := NewHyperVCollector(srv.URL, `HV01\Administrator`, "wrong", zap.NewNop())
+	_, err := c.CollectVMs(context.Background())
+	if err == nil {
+		t.Fatal("CollectVMs() expected error for bad credentials")
+	}
+	if !errors.Is(err, ErrHyperVAuth) {
+		t.Errorf("error = %v, want ErrHyperVAuth", err)
+	}
+	if !strings.Contains(err.Error(), "Basic authentication") {
+		t.Errorf("error = %v, want a hint about Basic authentication", err)
+	}
+}
+
+func TestHyperVCollector_Fault(t *testing.T) {
+	// Hyper-V role not installed: the virtualization namespace is missing.
+	srv := newTestProxmoxServer(t, hypervTestHandler(t, map[string]string{}, nil))
+
+	c := NewHyperVCollector(srv.URL, `HV01\Administrator`, "s3cret&", zap.NewNop())
+	_, err := c.CollectVMs(context.Background())
+	if err == nil {
+		t.Fatal("CollectVMs() expected error for SOAP fault")
+	}
+	if !strings.Contains(err.Error(), "WMI provider returned an unknown error") {
+		t.Errorf("error = %v, want SOAP fault reason", err)
+	}
+}
+
+func TestHypervState(t *testing.T) {
+	tests := map[string]string{
+		"2":     "running",
+		"3":     "off",
+		"32769": "saved",
+		"32768": "paused",
+		"32770": "starting",
+		"32774": "stopping",
+		"5":     "unknown",
+	}
+	for in, want := range tests {
+		if got := hypervState(in); got != want {
+			t.Errorf("hypervState(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
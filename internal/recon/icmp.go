@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"runtime"
+	"slices"
 	"time"
 
 	probing "github.com/prometheus-community/pro-bing"
@@ -38,13 +39,23 @@ func NewICMPScanner(cfg ReconConfig, logger *zap.Logger) *ICMPScanner {
 	}
 }
 
-// Scan pings all hosts in the given subnet and sends alive hosts to results.
-// The caller must close the results channel after Scan returns.
+// Scan pings all hosts in the given subnet and sends a result for each host
+// probed, with Alive set for those that answered. The caller must close the
+// results channel after Scan returns.
 func (s *ICMPScanner) Scan(ctx context.Context, subnet *net.IPNet, results chan<- HostResult) error {
+	return s.ScanExcluding(ctx, subnet, nil, results)
+}
+
+// ScanExcluding is like Scan but never pings hosts for which skip returns
+// true. Skipped hosts produce no result at all.
+func (s *ICMPScanner) ScanExcluding(ctx context.Context, subnet *net.IPNet, skip func(ip string) bool, results chan<- HostResult) error {
 	hosts := expandSubnet(subnet)
 	if len(hosts) == 0 {
 		return fmt.Errorf("no hosts in subnet %s", subnet)
 	}
+	if skip != nil {
+		hosts = slices.DeleteFunc(hosts, skip)
+	}
 
 	s.logger.Info("starting ICMP scan",
 		zap.String("subnet", subnet.String()),
@@ -70,11 +81,9 @@ func (s *ICMPScanner) Scan(ctx context.Context, subnet *net.IPNet, results chan<
 			defer func() { <-sem }()
 
 			alive, rtt, ttl := s.pingHost(ctx, ip, privileged)
-			if alive {
-				select {
-				case results <- HostResult{IP: ip, RTT: rtt, Alive: true, Method: "icmp", TTL: ttl}:
-				case <-ctx.Done():
-				}
+			select {
+			case results <- HostResult{IP: ip, RTT: rtt, Alive: alive, Method: "icmp", TTL: ttl}:
+			case <-ctx.Done():
 			}
 		}(ip)
 	}
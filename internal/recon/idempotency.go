@@ -0,0 +1,216 @@
+package recon
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+)
+
+const (
+	// idempotencyKeyHeader carries a client-chosen key that makes a POST
+	// safe to retry.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotencyReplayHeader is set on responses replayed from the cache.
+	idempotencyReplayHeader = "Idempotent-Replayed"
+
+	// idempotencyTTL is how long a completed request's response is kept.
+	idempotencyTTL = 24 * time.Hour
+
+	// maxIdempotencyKeyLen bounds the keys clients may send.
+	maxIdempotencyKeyLen = 255
+
+	// maxIdempotentBodyBytes bounds the request bodies read to fingerprint
+	// a keyed request. It matches the largest body an idempotent route
+	// accepts (device import).
+	maxIdempotentBodyBytes = maxImportBytes
+)
+
+// idempotentResponse is a request's recorded outcome. done is closed once
+// the response is recorded, or once the request failed and its key was
+// released. fingerprint identifies the request that claimed the key.
+type idempotentResponse struct {
+	done        chan struct{}
+	fingerprint [sha256.Size]byte
+	ok          bool
+	status      int
+	contentType string
+	body        []byte
+	expires     time.Time
+}
+
+// idempotencyCache remembers the responses of requests sent with an
+// Idempotency-Key. Keys are held in memory, so a restart forgets them.
+type idempotencyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[string]*idempotentResponse
+}
+
+func newIdempotencyCache(ttl time.Duration) *idempotencyCache {
+	return &idempotencyCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]*idempotentResponse),
+	}
+}
+
+// claim returns the entry for key. owner is true when the key was unused
+// and the caller must run the request and then call finish; the new entry
+// records fingerprint.
+func (c *idempotencyCache) claim(key string, fingerprint [sha256.Size]byte) (entry *idempotentResponse, owner bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, e := range c.entries {
+		if e.ok && now.After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if e, ok := c.entries[key]; ok {
+		return e, false
+	}
+	e := &idempotentResponse{done: make(chan struct{}), fingerprint: fingerprint}
+	c.entries[key] = e
+	return e, true
+}
+
+// finish records the response for key. Only successful responses of
+// requests that completed are kept; for anything else, including a handler
+// that panicked, the key is released so the client can retry.
+func (c *idempotencyCache) finish(key string, e *idempotentResponse, rec *idempotencyRecorder, completed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if completed && rec.status >= 200 && rec.status < 300 {
+		e.ok = true
+		e.status = rec.status
+		e.contentType = rec.Header().Get("Content-Type")
+		e.body = rec.body.Bytes()
+		e.expires = c.now().Add(c.ttl)
+	} else {
+		delete(c.entries, key)
+	}
+	close(e.done)
+}
+
+// idempotencyRecorder passes a response through while keeping a copy.
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *idempotencyRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *idempotencyRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *idempotencyRecorder) Unwrap() http.ResponseWriter { return r.ResponseWriter }
+
+// idempotencyKeys returns the module's idempotency cache, creating it on
+// first use.
+func (m *Module) idempotencyKeys() *idempotencyCache {
+	m.idempotencyOnce.Do(func() {
+		m.idempotency = newIdempotencyCache(idempotencyTTL)
+	})
+	return m.idempotency
+}
+
+// idempotent makes next safe to retry. A request carrying an
+// Idempotency-Key runs once per key, caller, and scope; repeats within the
+// TTL get the original response back with Idempotent-Replayed set. A
+// repeat that arrives while the original is still running waits for it.
+// Reusing a key for a request with a different path or body is rejected
+// with 422. Requests without the header are passed through.
+func (m *Module) idempotent(scope string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			writeError(w, http.StatusBadRequest, "Idempotency-Key must be at most 255 characters")
+			return
+		}
+		cacheKey := scope + "\x00" + key
+		if user := auth.UserFromContext(r.Context()); user != nil {
+			cacheKey = user.UserID + "\x00" + cacheKey
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxIdempotentBodyBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			writeError(w, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := sha256.Sum256(append([]byte(r.URL.RequestURI()+"\x00"), body...))
+
+		cache := m.idempotencyKeys()
+		for {
+			e, owner := cache.claim(cacheKey, fingerprint)
+			if owner {
+				cache.run(cacheKey, e, w, r, next)
+				return
+			}
+			if e.fingerprint != fingerprint {
+				writeError(w, http.StatusUnprocessableEntity, "Idempotency-Key was already used for a different request")
+				return
+			}
+
+			select {
+			case <-e.done:
+			case <-r.Context().Done():
+				writeError(w, http.StatusServiceUnavailable, "request cancelled while waiting for the original request")
+				return
+			}
+			if e.ok {
+				if e.contentType != "" {
+					w.Header().Set("Content-Type", e.contentType)
+				}
+				w.Header().Set(idempotencyReplayHeader, "true")
+				w.WriteHeader(e.status)
+				_, _ = w.Write(e.body)
+				return
+			}
+			// The original failed and released the key; run this one.
+		}
+	}
+}
+
+// run runs next for the request that claimed key and records its response.
+// finish is deferred so the key is never left claimed: if next panics, the
+// key is released and the panic continues up the stack.
+func (c *idempotencyCache) run(key string, e *idempotentResponse, w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	rec := &idempotencyRecorder{ResponseWriter: w, status: http.StatusOK}
+	completed := false
+	defer func() { c.finish(key, e, rec, completed) }()
+	next(rec, r)
+	completed = true
+}
@@ -0,0 +1,208 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingPinger counts scans and reports no hosts.
+type countingPinger struct{ scans atomic.Int32 }
+
+func (p *countingPinger) Scan(_ context.Context, _ *net.IPNet, _ chan<- HostResult) error {
+	p.scans.Add(1)
+	return nil
+}
+
+func TestIdempotentScan(t *testing.T) {
+	m := newTestModule(t)
+	pinger := &countingPinger{}
+	m.orchestrator = NewScanOrchestrator(m.store, m.bus, m.oui, pinger, &mockARPReader{table: map[string]string{}}, m.logger)
+	handler := m.idempotent("scan", m.handleScan)
+
+	startScan := func(key string) (string, *httptest.ResponseRecorder) {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/scan", strings.NewReader(`{"subnet":"192.168.1.0/30"}`))
+		if key != "" {
+			req.Header.Set(idempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusAccepted, w.Body.String())
+		}
+		var resp struct {
+			ID string `json:"id"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp.ID, w
+	}
+
+	first, _ := startScan("key-a")
+	again, w := startScan("key-a")
+	if again != first {
+		t.Errorf("repeated key returned scan %q, want %q", again, first)
+	}
+	if w.Header().Get(idempotencyReplayHeader) != "true" {
+		t.Errorf("%s header = %q, want true", idempotencyReplayHeader, w.Header().Get(idempotencyReplayHeader))
+	}
+
+	other, _ := startScan("key-b")
+	if other == first {
+		t.Error("different keys returned the same scan")
+	}
+	unkeyed, _ := startScan("")
+	if unkeyed == first || unkeyed == other {
+		t.Error("request without a key reused a scan")
+	}
+	m.wg.Wait()
+
+	if got := pinger.scans.Load(); got != 3 {
+		t.Errorf("scans run = %d, want 3", got)
+	}
+	scans, err := m.store.ListScans(context.Background(), 10, 0)
+	if err != nil {
+		t.Fatalf("ListScans: %v", err)
+	}
+	if len(scans) != 3 {
+		t.Errorf("scan records = %d, want 3", len(scans))
+	}
+}
+
+func TestIdempotent_FailedRequestReleasesKey(t *testing.T) {
+	m := newTestModule(t)
+	handler := m.idempotent("scan", m.handleScan)
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/scan", strings.NewReader(body))
+		req.Header.Set(idempotencyKeyHeader, "retry-me")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	if code := post(`{"subnet":"not-a-cidr"}`); code != http.StatusBadRequest {
+		t.Fatalf("invalid request status = %d, want %d", code, http.StatusBadRequest)
+	}
+	if code := post(`{"subnet":"192.168.1.0/30"}`); code != http.StatusAccepted {
+		t.Errorf("retry after failure status = %d, want %d", code, http.StatusAccepted)
+	}
+	m.wg.Wait()
+}
+
+func TestIdempotentImport(t *testing.T) {
+	m := newTestModule(t)
+	handler := m.idempotent("devices-import", m.handleImportDevices)
+
+	csvBody := "hostname,ip_addresses\nweb-01,192.168.1.10\n"
+	importWith := func(key string) ImportResult {
+		t.Helper()
+		req := httptest.NewRequest("POST", "/devices/import", strings.NewReader(csvBody))
+		req.Header.Set("Content-Type", "text/csv")
+		req.Header.Set(idempotencyKeyHeader, key)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body: %s", w.Code, w.Body.String())
+		}
+		var res ImportResult
+		_ = json.NewDecoder(w.Body).Decode(&res)
+		return res
+	}
+
+	if res := importWith("import-1"); res.Created != 1 {
+		t.Fatalf("first import created %d, want 1", res.Created)
+	}
+	// A replay returns the original result rather than importing again,
+	// which would report the device as updated.
+	if res := importWith("import-1"); res.Created != 1 || res.Updated != 0 {
+		t.Errorf("replayed import = %+v, want created 1", res)
+	}
+	if res := importWith("import-2"); res.Updated != 1 {
+		t.Errorf("import with a new key = %+v, want updated 1", res)
+	}
+}
+
+func TestIdempotent_KeyTooLong(t *testing.T) {
+	m := newTestModule(t)
+	handler := m.idempotent("scan", m.handleScan)
+
+	req := httptest.NewRequest("POST", "/scan", strings.NewReader(`{"subnet":"192.168.1.0/30"}`))
+	req.Header.Set(idempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLen+1))
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestIdempotent_KeyReusedWithDifferentBody(t *testing.T) {
+	m := newTestModule(t)
+	handler := m.idempotent("scan", m.handleScan)
+
+	post := func(body string) int {
+		req := httptest.NewRequest("POST", "/scan", strings.NewReader(body))
+		req.Header.Set(idempotencyKeyHeader, "reused")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	if code := post(`{"subnet":"192.168.1.0/30"}`); code != http.StatusAccepted {
+		t.Fatalf("first request status = %d, want %d", code, http.StatusAccepted)
+	}
+	if code := post(`{"subnet":"192.168.2.0/30"}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("different body status = %d, want %d", code, http.StatusUnprocessableEntity)
+	}
+	m.wg.Wait()
+}
+
+func TestIdempotent_PanicReleasesKey(t *testing.T) {
+	m := newTestModule(t)
+	var calls atomic.Int32
+	handler := m.idempotent("scan", func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) == 1 {
+			panic("boom")
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	post := func() int {
+		req := httptest.NewRequest("POST", "/scan", strings.NewReader(`{}`))
+		req.Header.Set(idempotencyKeyHeader, "panics")
+		w := httptest.NewRecorder()
+		handler(w, req)
+		return w.Code
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("handler panic was swallowed")
+			}
+		}()
+		post()
+	}()
+
+	done := make(chan int, 1)
+	go func() { done <- post() }()
+	select {
+	case code := <-done:
+		if code != http.StatusAccepted {
+			t.Errorf("retry after panic status = %d, want %d", code, http.StatusAccepted)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("retry after panic blocked on the unfinished key")
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("handler calls = %d, want 2", got)
+	}
+}
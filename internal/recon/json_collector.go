@@ -0,0 +1,208 @@
+package recon
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for jsonCollector requests.
+const (
+	jsonCollectorTimeout      = 30 * time.Second
+	jsonCollectorMaxRetries   = 2
+	jsonCollectorRetryBackoff = 250 * time.Millisecond // Doubled after each retry, with jitter
+)
+
+// jsonCollector is the HTTP plumbing shared by collectors that read a JSON
+// REST API: a base URL, an auth hook applied to every request, and a GET
+// that retries transient failures. Collectors embed one and keep only the
+// endpoint paths and the mapping from API structs to models.
+type jsonCollector struct {
+	name         string // API name used in error messages, e.g. "proxmox API"
+	baseURL      string
+	authorize    func(req *http.Request) error // Sets auth headers; nil for none
+	httpClient   *http.Client
+	maxRetries   int
+	retryBackoff time.Duration
+}
+
+// apiStatusError is returned for a non-2xx response once retries are
+// exhausted.
+type apiStatusError struct {
+	name       string
+	StatusCode int
+	Body       string
+}
+
+func (e *apiStatusError) Error() string {
+	return fmt.Sprintf("%s returned %d: %s", e.name, e.StatusCode, e.Body)
+}
+
+// newJSONCollector creates a jsonCollector for the API at baseURL. Set
+// insecureSkipVerify for appliances that ship with self-signed
+// certificates.
+func newJSONCollector(name, baseURL string, authorize func(*http.Request) error, insecureSkipVerify bool) *jsonCollector {
+	return &jsonCollector{
+		name:      name,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+		authorize: authorize,
+		httpClient: &http.Client{
+			Timeout: jsonCollectorTimeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion:         tls.VersionTLS12,
+					InsecureSkipVerify: insecureSkipVerify, //nolint:gosec // G402: opt-in per collector for self-signed homelab certs
+				},
+			},
+		},
+		maxRetries:   jsonCollectorMaxRetries,
+		retryBackoff: jsonCollectorRetryBackoff,
+	}
+}
+
+// get performs an authenticated GET of path and returns the response body.
+// Transport errors and 5xx responses are retried with exponential backoff;
+// other error statuses fail immediately with an *apiStatusError.
+func (c *jsonCollector) get(ctx context.Context, path string) ([]byte, error) {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		body, retry, err := c.doGet(ctx, path)
+		if err == nil || !retry || attempt >= c.maxRetries {
+			return body, err
+		}
+
+		// Jitter each wait to 50-150% of the nominal backoff so that
+		// collectors started together do not retry in lockstep.
+		wait := backoff/2 + rand.N(backoff+1) //nolint:gosec // G404: jitter, not security
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
+
+// getJSON performs get and decodes the response body into v.
+func (c *jsonCollector) getJSON(ctx context.Context, path string, v any) error {
+	body, err := c.get(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// doGet makes one GET attempt and reports whether a failure is worth
+// retrying.
+func (c *jsonCollector) doGet(ctx context.Context, path string) (body []byte, retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, false, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.authorize != nil {
+		if err := c.authorize(req); err != nil {
+			return nil, false, fmt.Errorf("authorize request: %w", err)
+		}
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("http request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, resp.StatusCode >= 500, &apiStatusError{name: c.name, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return body, false, nil
+}
+
+// isTransientError reports whether err from get means the API host could
+// not serve the request (a transport failure or a 5xx response), as opposed
+// to rejecting it.
+func isTransientError(err error) bool {
+	var statusErr *apiStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// Defaults for circuitBreaker.
+const (
+	breakerFailureThreshold = 3
+	breakerCooldown         = 5 * time.Minute
+)
+
+// circuitBreaker tracks consecutive failures per host. After threshold
+// failures in a row the host's circuit opens and allow rejects calls until
+// cooldown has passed; the next call is then let through as a trial, and
+// the circuit closes on its success or reopens on its failure.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*breakerState
+}
+
+type breakerState struct {
+	failures  int
+	openUntil time.Time
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+		hosts:     make(map[string]*breakerState),
+	}
+}
+
+// allow reports whether a call to host may proceed.
+func (b *circuitBreaker) allow(host string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	st, ok := b.hosts[host]
+	return !ok || !b.now().Before(st.openUntil)
+}
+
+// record updates host's state with the outcome of a call.
+func (b *circuitBreaker) record(host string, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		delete(b.hosts, host)
+		return
+	}
+	st, ok := b.hosts[host]
+	if !ok {
+		st = &breakerState{}
+		b.hosts[host] = st
+	}
+	st.failures++
+	if st.failures >= b.threshold {
+		st.openUntil = b.now().Add(b.cooldown)
+	}
+}
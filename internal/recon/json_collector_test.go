@@ -0,0 +1,132 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestJSONCollector points a jsonCollector at handler with a short
+// retry backoff.
+func newTestJSONCollector(t *testing.T, handler http.HandlerFunc, authorize func(*http.Request) error) *jsonCollector {
+	t.Helper()
+	srv := newTestProxmoxServer(t, handler)
+	c := newJSONCollector("test API", srv.URL+"/", authorize, false)
+	c.retryBackoff = time.Millisecond
+	return c
+}
+
+func TestJSONCollector_AuthHeader(t *testing.T) {
+	c := newTestJSONCollector(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer s3cret" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer s3cret")
+		}
+		if got := r.Header.Get("Accept"); got != "application/json" {
+			t.Errorf("Accept = %q, want application/json", got)
+		}
+		if r.URL.Path != "/api/items" {
+			t.Errorf("path = %q, want /api/items", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"items":[{"name":"a"},{"name":"b"}]}`))
+	}, func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer s3cret")
+		return nil
+	})
+
+	var resp struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+	}
+	if err := c.getJSON(context.Background(), "/api/items", &resp); err != nil {
+		t.Fatalf("getJSON: %v", err)
+	}
+	if len(resp.Items) != 2 || resp.Items[1].Name != "b" {
+		t.Errorf("decoded = %+v", resp)
+	}
+}
+
+func TestJSONCollector_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // Response status per attempt; the last repeats
+		wantAttempts int32
+		wantStatus   int // 0 means success
+	}{
+		{name: "recovers after 5xx", statuses: []int{503, 502, 200}, wantAttempts: 3},
+		{name: "gives up after max retries", statuses: []int{500}, wantAttempts: 3, wantStatus: 500},
+		{name: "4xx is not retried", statuses: []int{401}, wantAttempts: 1, wantStatus: 401},
+		{name: "404 is not retried", statuses: []int{500, 404}, wantAttempts: 2, wantStatus: 404},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			c := newTestJSONCollector(t, func(w http.ResponseWriter, _ *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				status := tt.statuses[min(n, len(tt.statuses)-1)]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					_, _ = w.Write([]byte(`{"ok":true}`))
+				} else {
+					_, _ = w.Write([]byte("upstream unavailable"))
+				}
+			}, nil)
+
+			var resp struct {
+				OK bool `json:"ok"`
+			}
+			err := c.getJSON(context.Background(), "/status", &resp)
+
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil || !resp.OK {
+					t.Errorf("getJSON = %v, resp = %+v; want success", err, resp)
+				}
+				return
+			}
+			var statusErr *apiStatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("error = %v, want *apiStatusError", err)
+			}
+			if statusErr.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", statusErr.StatusCode, tt.wantStatus)
+			}
+			if !strings.HasPrefix(err.Error(), "test API returned") {
+				t.Errorf("error = %q, want it to name the API", err)
+			}
+		})
+	}
+}
+
+func TestJSONCollector_RetryStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts atomic.Int32
+	c := newTestJSONCollector(t, func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, nil)
+	c.retryBackoff = time.Minute
+
+	done := make(chan error, 1)
+	go func() { _, err := c.get(ctx, "/status"); done <- err }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected error after cancellation")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("get kept waiting to retry after the context was cancelled")
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
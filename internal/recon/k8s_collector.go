@@ -0,0 +1,564 @@
+package recon
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// In-cluster service account locations, as mounted by the kubelet.
+const (
+	k8sServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	k8sListLimit         = 500 // Items per page for list calls
+)
+
+// K8sConfig describes how to reach and authenticate to a Kubernetes API
+// server. Build one with LoadKubeconfig or InClusterConfig.
+type K8sConfig struct {
+	Server             string
+	Token              string //nolint:gosec // G101: field name, not a credential
+	CAData             []byte // PEM; system roots are used when empty
+	ClientCertData     []byte // PEM client certificate for mTLS auth
+	ClientKeyData      []byte // PEM client key for mTLS auth
+	InsecureSkipVerify bool
+}
+
+// K8sCollector inventories nodes and pods from a Kubernetes API server. Like
+// the other recon collectors it calls the REST API directly over net/http
+// rather than depending on client-go; only the node and pod list endpoints
+// are used, so a read-only ClusterRole with list on nodes and pods suffices.
+type K8sCollector struct {
+	baseURL    string
+	token      string
+	namespaces []string // Pod namespaces to list; empty means all
+	httpClient *http.Client
+	logger     *zap.Logger
+}
+
+// K8sNode is a cluster node with its capacity and runtime details.
+type K8sNode struct {
+	Name             string   `json:"name"`
+	InternalIP       string   `json:"internal_ip,omitempty"`
+	Roles            []string `json:"roles,omitempty"` // From node-role.kubernetes.io/<role> labels
+	Ready            bool     `json:"ready"`
+	KernelVersion    string   `json:"kernel_version"`
+	OSImage          string   `json:"os_image"`
+	ContainerRuntime string   `json:"container_runtime"` // e.g. "containerd://1.7.13"
+	KubeletVersion   string   `json:"kubelet_version"`
+	Architecture     string   `json:"architecture"`
+	CPUCores         int      `json:"cpu_cores"`
+	MemoryBytes      int64    `json:"memory_bytes"`
+	PodCapacity      int      `json:"pod_capacity"`
+}
+
+// K8sPod is a pod scheduled in the cluster.
+type K8sPod struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace"`
+	NodeName    string   `json:"node_name,omitempty"`
+	Phase       string   `json:"phase"` // "Pending", "Running", "Succeeded", "Failed", "Unknown"
+	PodIP       string   `json:"pod_ip,omitempty"`
+	HostNetwork bool     `json:"host_network"`
+	Images      []string `json:"images"`
+}
+
+// k8sNodeList is the /api/v1/nodes response.
+type k8sNodeList struct {
+	Metadata k8sListMeta `json:"metadata"`
+	Items    []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+		Status struct {
+			Capacity  map[string]string `json:"capacity"`
+			Addresses []struct {
+				Type    string `json:"type"`
+				Address string `json:"address"`
+			} `json:"addresses"`
+			Conditions []struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+			} `json:"conditions"`
+			NodeInfo struct {
+				KernelVersion           string `json:"kernelVersion"`
+				OSImage                 string `json:"osImage"`
+				ContainerRuntimeVersion string `json:"containerRuntimeVersion"`
+				KubeletVersion          string `json:"kubeletVersion"`
+				Architecture            string `json:"architecture"`
+			} `json:"nodeInfo"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// k8sPodList is the /api/v1/pods response.
+type k8sPodList struct {
+	Metadata k8sListMeta `json:"metadata"`
+	Items    []struct {
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Spec struct {
+			NodeName    string `json:"nodeName"`
+			HostNetwork bool   `json:"hostNetwork"`
+			Containers  []struct {
+				Image string `json:"image"`
+			} `json:"containers"`
+		} `json:"spec"`
+		Status struct {
+			Phase string `json:"phase"`
+			PodIP string `json:"podIP"`
+		} `json:"status"`
+	} `json:"items"`
+}
+
+// k8sListMeta carries the pagination token of a list response.
+type k8sListMeta struct {
+	Continue string `json:"continue"`
+}
+
+// NewK8sCollector creates a collector for the API server in cfg. Pods are
+// listed only in namespaces, or in all namespaces when it is empty.
+func NewK8sCollector(cfg *K8sConfig, namespaces []string, logger *zap.Logger) (*K8sCollector, error) {
+	u, err := url.Parse(cfg.Server)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return nil, fmt.Errorf("invalid kubernetes API server %q", cfg.Server)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec // G402: opt-in via kubeconfig insecure-skip-tls-verify
+	}
+	if len(cfg.CAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.CAData) {
+			return nil, errors.New("kubernetes CA data contains no valid certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(cfg.ClientCertData) > 0 {
+		cert, err := tls.X509KeyPair(cfg.ClientCertData, cfg.ClientKeyData)
+		if err != nil {
+			return nil, fmt.Errorf("load kubernetes client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &K8sCollector{
+		baseURL:    strings.TrimRight(cfg.Server, "/"),
+		token:      cfg.Token,
+		namespaces: namespaces,
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		logger: logger,
+	}, nil
+}
+
+// InClusterConfig returns the config for the service account of the pod
+// SubNetree is running in.
+func InClusterConfig() (*K8sConfig, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, errors.New("not running in a kubernetes cluster: KUBERNETES_SERVICE_HOST is not set")
+	}
+	token, err := os.ReadFile(filepath.Join(k8sServiceAccountDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(k8sServiceAccountDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("read service account CA: %w", err)
+	}
+	return &K8sConfig{
+		Server: "https://" + net.JoinHostPort(host, port),
+		Token:  strings.TrimSpace(string(token)),
+		CAData: ca,
+	}, nil
+}
+
+// kubeconfig is the subset of the kubeconfig file format the collector
+// understands: static tokens and client certificates, inline or by path.
+// Exec and auth-provider plugins are not supported.
+type kubeconfig struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster string `yaml:"cluster"`
+			User    string `yaml:"user"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string    `yaml:"token"`
+			TokenFile             string    `yaml:"tokenFile"`
+			ClientCertificate     string    `yaml:"client-certificate"`
+			ClientCertificateData string    `yaml:"client-certificate-data"`
+			ClientKey             string    `yaml:"client-key"`
+			ClientKeyData         string    `yaml:"client-key-data"`
+			Exec                  *struct{} `yaml:"exec"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// LoadKubeconfig reads the cluster and credentials for contextName from a
+// kubeconfig file, or for its current context when contextName is empty.
+// Relative file references are resolved against the kubeconfig's directory.
+func LoadKubeconfig(path, contextName string) (*K8sConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read kubeconfig: %w", err)
+	}
+	var kc kubeconfig
+	if err := yaml.Unmarshal(data, &kc); err != nil {
+		return nil, fmt.Errorf("parse kubeconfig: %w", err)
+	}
+
+	if contextName == "" {
+		contextName = kc.CurrentContext
+	}
+	var clusterName, userName string
+	found := false
+	for _, c := range kc.Contexts {
+		if c.Name == contextName {
+			clusterName, userName, found = c.Context.Cluster, c.Context.User, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig context %q not found", contextName)
+	}
+
+	dir := filepath.Dir(path)
+	cfg := &K8sConfig{}
+	found = false
+	for _, c := range kc.Clusters {
+		if c.Name != clusterName {
+			continue
+		}
+		found = true
+		cfg.Server = c.Cluster.Server
+		cfg.InsecureSkipVerify = c.Cluster.InsecureSkipTLSVerify
+		if cfg.CAData, err = kubeconfigData(c.Cluster.CertificateAuthorityData, c.Cluster.CertificateAuthority, dir); err != nil {
+			return nil, fmt.Errorf("cluster %q certificate authority: %w", clusterName, err)
+		}
+		break
+	}
+	if !found {
+		return nil, fmt.Errorf("kubeconfig cluster %q not found", clusterName)
+	}
+
+	for _, u := range kc.Users {
+		if u.Name != userName {
+			continue
+		}
+		if u.User.Exec != nil {
+			return nil, fmt.Errorf("kubeconfig user %q uses an exec credential plugin, which is not supported; use a service account token", userName)
+		}
+		cfg.Token = u.User.Token
+		if cfg.Token == "" && u.User.TokenFile != "" {
+			token, err := os.ReadFile(resolveKubeconfigPath(u.User.TokenFile, dir))
+			if err != nil {
+				return nil, fmt.Errorf("user %q token file: %w", userName, err)
+			}
+			cfg.Token = strings.TrimSpace(string(token))
+		}
+		if cfg.ClientCertData, err = kubeconfigData(u.User.ClientCertificateData, u.User.ClientCertificate, dir); err != nil {
+			return nil, fmt.Errorf("user %q client certificate: %w", userName, err)
+		}
+		if cfg.ClientKeyData, err = kubeconfigData(u.User.ClientKeyData, u.User.ClientKey, dir); err != nil {
+			return nil, fmt.Errorf("user %q client key: %w", userName, err)
+		}
+		break
+	}
+	return cfg, nil
+}
+
+// kubeconfigData returns base64-decoded inline data, or the contents of the
+// referenced file when there is no inline data.
+func kubeconfigData(inline, path, dir string) ([]byte, error) {
+	if inline != "" {
+		return base64.StdEncoding.DecodeString(inline)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	return os.ReadFile(resolveKubeconfigPath(path, dir))
+}
+
+func resolveKubeconfigPath(path, dir string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// CollectNodes returns every node in the cluster.
+func (c *K8sCollector) CollectNodes(ctx context.Context) ([]K8sNode, error) {
+	var nodes []K8sNode
+	err := c.list(ctx, "/api/v1/nodes", func(body []byte) (string, error) {
+		var list k8sNodeList
+		if err := json.Unmarshal(body, &list); err != nil {
+			return "", fmt.Errorf("parse nodes response: %w", err)
+		}
+		for i := range list.Items {
+			item := &list.Items[i]
+			info := &item.Status.NodeInfo
+			node := K8sNode{
+				Name:             item.Metadata.Name,
+				KernelVersion:    info.KernelVersion,
+				OSImage:          info.OSImage,
+				ContainerRuntime: info.ContainerRuntimeVersion,
+				KubeletVersion:   info.KubeletVersion,
+				Architecture:     info.Architecture,
+				CPUCores:         int(parseK8sCPU(item.Status.Capacity["cpu"]) / 1000),
+				MemoryBytes:      parseK8sQuantity(item.Status.Capacity["memory"]),
+				PodCapacity:      int(parseK8sQuantity(item.Status.Capacity["pods"])),
+			}
+			for _, a := range item.Status.Addresses {
+				if a.Type == "InternalIP" && node.InternalIP == "" {
+					node.InternalIP = a.Address
+				}
+			}
+			for _, cond := range item.Status.Conditions {
+				if cond.Type == "Ready" {
+					node.Ready = cond.Status == "True"
+				}
+			}
+			for label := range item.Metadata.Labels {
+				if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+					node.Roles = append(node.Roles, role)
+				}
+			}
+			sort.Strings(node.Roles)
+			nodes = append(nodes, node)
+		}
+		return list.Metadata.Continue, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list nodes: %w", err)
+	}
+	return nodes, nil
+}
+
+// CollectPods returns the pods in the configured namespaces, or in all
+// namespaces when none are configured.
+func (c *K8sCollector) CollectPods(ctx context.Context) ([]K8sPod, error) {
+	paths := []string{"/api/v1/pods"}
+	if len(c.namespaces) > 0 {
+		paths = paths[:0]
+		for _, ns := range c.namespaces {
+			paths = append(paths, "/api/v1/namespaces/"+url.PathEscape(ns)+"/pods")
+		}
+	}
+
+	var pods []K8sPod
+	for _, path := range paths {
+		err := c.list(ctx, path, func(body []byte) (string, error) {
+			var list k8sPodList
+			if err := json.Unmarshal(body, &list); err != nil {
+				return "", fmt.Errorf("parse pods response: %w", err)
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				pod := K8sPod{
+					Name:        item.Metadata.Name,
+					Namespace:   item.Metadata.Namespace,
+					NodeName:    item.Spec.NodeName,
+					Phase:       item.Status.Phase,
+					PodIP:       item.Status.PodIP,
+					HostNetwork: item.Spec.HostNetwork,
+				}
+				for _, ct := range item.Spec.Containers {
+					pod.Images = append(pod.Images, ct.Image)
+				}
+				pods = append(pods, pod)
+			}
+			return list.Metadata.Continue, nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("list pods: %w", err)
+		}
+	}
+	return pods, nil
+}
+
+// Device maps the node to a device record tagged as a Kubernetes node.
+// Capacity and runtime details are summarised in the notes.
+func (n *K8sNode) Device() *models.Device {
+	status := models.DeviceStatusOnline
+	if !n.Ready {
+		status = models.DeviceStatusDegraded
+	}
+	dev := &models.Device{
+		Hostname:        n.Name,
+		DeviceType:      models.DeviceTypeServer,
+		OS:              n.OSImage,
+		Status:          status,
+		DiscoveryMethod: models.DiscoveryKubernetes,
+		NetworkLayer:    models.NetworkLayerEndpoint,
+		Tags:            []string{"k8s", "k8s-node"},
+	}
+	if n.InternalIP != "" {
+		dev.IPAddresses = []string{n.InternalIP}
+	}
+	for _, role := range n.Roles {
+		dev.Tags = append(dev.Tags, "k8s-role:"+role)
+	}
+
+	dev.Notes = strings.Join([]string{
+		fmt.Sprintf("Capacity: %d CPU, %d MiB memory, %d pods", n.CPUCores, n.MemoryBytes/(1024*1024), n.PodCapacity),
+		"Kernel: " + n.KernelVersion,
+		"Container runtime: " + n.ContainerRuntime,
+		"Kubelet: " + n.KubeletVersion,
+	}, "\n")
+	return dev
+}
+
+// Device maps the pod to a device record under the node device
+// nodeDeviceID. Host-network pods share the node's IP, so no address is
+// recorded for them.
+func (p *K8sPod) Device(nodeDeviceID string) *models.Device {
+	status := models.DeviceStatusOnline
+	if p.Phase != "Running" {
+		status = models.DeviceStatusOffline
+	}
+	dev := &models.Device{
+		Hostname:        p.Name,
+		DeviceType:      models.DeviceTypeContainer,
+		Status:          status,
+		DiscoveryMethod: models.DiscoveryKubernetes,
+		ParentDeviceID:  nodeDeviceID,
+		NetworkLayer:    models.NetworkLayerEndpoint,
+		Tags:            []string{"k8s", "k8s-pod", "namespace:" + p.Namespace},
+		Notes:           "Images: " + strings.Join(p.Images, ", "),
+	}
+	if p.PodIP != "" && !p.HostNetwork {
+		dev.IPAddresses = []string{p.PodIP}
+	}
+	return dev
+}
+
+// list GETs path, following continue tokens. page decodes one response and
+// returns its continue token.
+func (c *K8sCollector) list(ctx context.Context, path string, page func(body []byte) (string, error)) error {
+	token := ""
+	for {
+		q := url.Values{"limit": {strconv.Itoa(k8sListLimit)}}
+		if token != "" {
+			q.Set("continue", token)
+		}
+		body, err := c.apiGet(ctx, path+"?"+q.Encode())
+		if err != nil {
+			return err
+		}
+		if token, err = page(body); err != nil {
+			return err
+		}
+		if token == "" {
+			return nil
+		}
+	}
+}
+
+// apiGet performs a GET request against the API server and returns the
+// response body.
+func (c *K8sCollector) apiGet(ctx context.Context, path string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// API errors are Status objects: {"kind":"Status","message":"..."}.
+		var status struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &status) == nil && status.Message != "" {
+			return nil, fmt.Errorf("kubernetes API returned %d: %s", resp.StatusCode, status.Message)
+		}
+		return nil, fmt.Errorf("kubernetes API returned %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// parseK8sCPU parses a CPU quantity ("4", "3500m") into millicores.
+func parseK8sCPU(s string) int64 {
+	if m, ok := strings.CutSuffix(s, "m"); ok {
+		n, _ := strconv.ParseInt(m, 10, 64)
+		return n
+	}
+	f, _ := strconv.ParseFloat(s, 64)
+	return int64(f * 1000)
+}
+
+// k8sQuantitySuffixes are the binary and decimal quantity multipliers.
+var k8sQuantitySuffixes = []struct {
+	suffix string
+	mult   int64
+}{
+	{"Ki", 1 << 10}, {"Mi", 1 << 20}, {"Gi", 1 << 30}, {"Ti", 1 << 40},
+	{"k", 1e3}, {"M", 1e6}, {"G", 1e9}, {"T", 1e12},
+}
+
+// parseK8sQuantity parses an integer quantity such as "16318412Ki", "8Gi",
+// or "110". Returns 0 for values it does not understand.
+func parseK8sQuantity(s string) int64 {
+	for _, q := range k8sQuantitySuffixes {
+		if num, ok := strings.CutSuffix(s, q.suffix); ok {
+			n, err := strconv.ParseInt(num, 10, 64)
+			if err != nil {
+				return 0
+			}
+			return n * q.mult
+		}
+	}
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
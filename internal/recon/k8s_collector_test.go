@@ -0,0 +1,387 @@
+package recon
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+const k8sTestToken = "eyJhbGciOiJSUzI1NiJ9.test"
+
+// k8sTestHandler serves list responses keyed by "<path>?continue=<token>"
+// (token empty for the first page) and checks the bearer token.
+//
+// These tests stand in an API server rather than client-go's fake
+// clientset. The collector does not use client-go (it is not a module
+// dependency), and a fake clientset would bypass the code being tested:
+// bearer auth, continue-token paging, and decoding of Status errors.
+func k8sTestHandler(t *testing.T, pages map[string]string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer "+k8sTestToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"kind":"Status","apiVersion":"v1","status":"Failure","message":"Unauthorized","reason":"Unauthorized","code":401}`)
+			return
+		}
+		if r.URL.Query().Get("limit") == "" {
+			t.Errorf("list request %s sent without a limit", r.URL)
+		}
+		body, ok := pages[r.URL.Path+"?continue="+r.URL.Query().Get("continue")]
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = io.WriteString(w, `{"kind":"Status","apiVersion":"v1","status":"Failure",`+
+				`"message":"pods is forbidden: User \"system:serviceaccount:monitoring:subnetree\" cannot list resource \"pods\" in API group \"\" at the cluster scope",`+
+				`"reason":"Forbidden","code":403}`)
+			return
+		}
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func newTestK8sCollector(t *testing.T, pages map[string]string, namespaces []string) *K8sCollector {
+	t.Helper()
+	srv := newTestProxmoxServer(t, k8sTestHandler(t, pages))
+	c, err := NewK8sCollector(&K8sConfig{Server: srv.URL, Token: k8sTestToken}, namespaces, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewK8sCollector: %v", err)
+	}
+	return c
+}
+
+const k8sNodesPage1 = `{"kind":"NodeList","apiVersion":"v1","metadata":{"resourceVersion":"81234","continue":"eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6ODEyMzQsInN0YXJ0IjoiY3AtMVx1MDAwMCJ9"},
+"items":[{"metadata":{"name":"cp-1","labels":{"kubernetes.io/hostname":"cp-1","node-role.kubernetes.io/control-plane":"","node-role.kubernetes.io/etcd":"true"}},
+"status":{"capacity":{"cpu":"4","ephemeral-storage":"61255492Ki","memory":"8148088Ki","pods":"110"},
+"conditions":[{"type":"MemoryPressure","status":"False"},{"type":"Ready","status":"True"}],
+"addresses":[{"type":"InternalIP","address":"10.0.0.11"},{"type":"Hostname","address":"cp-1"}],
+"nodeInfo":{"kernelVersion":"6.1.0-18-amd64","osImage":"Debian GNU/Linux 12 (bookworm)","containerRuntimeVersion":"containerd://1.7.13","kubeletVersion":"v1.29.2","architecture":"amd64"}}}]}`
+
+const k8sNodesPage2 = `{"kind":"NodeList","apiVersion":"v1","metadata":{"resourceVersion":"81234"},
+"items":[{"metadata":{"name":"worker-1","labels":{"kubernetes.io/hostname":"worker-1"}},
+"status":{"capacity":{"cpu":"3500m","memory":"16Gi","pods":"250"},
+"conditions":[{"type":"Ready","status":"Unknown"}],
+"addresses":[{"type":"ExternalIP","address":"203.0.113.7"},{"type":"InternalIP","address":"10.0.0.21"}],
+"nodeInfo":{"kernelVersion":"5.15.0-100-generic","osImage":"Ubuntu 22.04.4 LTS","containerRuntimeVersion":"cri-o://1.29.1","kubeletVersion":"v1.29.2","architecture":"arm64"}}}]}`
+
+const k8sPodsDefault = `{"kind":"PodList","apiVersion":"v1","metadata":{},"items":[
+{"metadata":{"name":"web-7d4b9c8f6-x2x9z","namespace":"default"},
+"spec":{"nodeName":"worker-1","containers":[{"name":"nginx","image":"nginx:1.25"},{"name":"exporter","image":"nginx/nginx-prometheus-exporter:1.1"}]},
+"status":{"phase":"Running","podIP":"10.244.1.17"}},
+{"metadata":{"name":"migrate-28461","namespace":"default"},
+"spec":{"nodeName":"worker-1","containers":[{"name":"migrate","image":"app:2.3.0"}]},
+"status":{"phase":"Succeeded","podIP":"10.244.1.9"}}]}`
+
+const k8sPodsKubeSystem = `{"kind":"PodList","apiVersion":"v1","metadata":{},"items":[
+{"metadata":{"name":"kube-proxy-8xk2p","namespace":"kube-system"},
+"spec":{"nodeName":"cp-1","hostNetwork":true,"containers":[{"name":"kube-proxy","image":"registry.k8s.io/kube-proxy:v1.29.2"}]},
+"status":{"phase":"Running","podIP":"10.0.0.11"}}]}`
+
+func TestK8sCollector_CollectNodes(t *testing.T) {
+	c := newTestK8sCollector(t, map[string]string{
+		"/api/v1/nodes?continue=": k8sNodesPage1,
+		"/api/v1/nodes?continue=eyJ2IjoibWV0YS5rOHMuaW8vdjEiLCJydiI6ODEyMzQsInN0YXJ0IjoiY3AtMVx1MDAwMCJ9": k8sNodesPage2,
+	}, nil)
+
+	nodes, err := c.CollectNodes(context.Background())
+	if err != nil {
+		t.Fatalf("CollectNodes: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("got %d nodes, want 2 (both pages)", len(nodes))
+	}
+
+	cp := nodes[0]
+	if cp.Name != "cp-1" || cp.InternalIP != "10.0.0.11" || !cp.Ready {
+		t.Errorf("cp-1 = %+v", cp)
+	}
+	if strings.Join(cp.Roles, ",") != "control-plane,etcd" {
+		t.Errorf("cp-1 roles = %v, want [control-plane etcd]", cp.Roles)
+	}
+	if cp.CPUCores != 4 || cp.MemoryBytes != 8148088*1024 || cp.PodCapacity != 110 {
+		t.Errorf("cp-1 capacity = %d CPU, %d bytes, %d pods", cp.CPUCores, cp.MemoryBytes, cp.PodCapacity)
+	}
+	if cp.ContainerRuntime != "containerd://1.7.13" || cp.KubeletVersion != "v1.29.2" || cp.KernelVersion != "6.1.0-18-amd64" {
+		t.Errorf("cp-1 node info = %+v", cp)
+	}
+
+	w := nodes[1]
+	if w.InternalIP != "10.0.0.21" {
+		t.Errorf("worker-1 InternalIP = %q, want 10.0.0.21", w.InternalIP)
+	}
+	if w.Ready {
+		t.Error("worker-1 with Ready=Unknown should not be ready")
+	}
+	if len(w.Roles) != 0 {
+		t.Errorf("worker-1 roles = %v, want none", w.Roles)
+	}
+	if w.CPUCores != 3 || w.MemoryBytes != 16<<30 || w.Architecture != "arm64" {
+		t.Errorf("worker-1 = %+v", w)
+	}
+}
+
+func TestK8sCollector_CollectPods(t *testing.T) {
+	pages := map[string]string{
+		"/api/v1/namespaces/default/pods?continue=":     k8sPodsDefault,
+		"/api/v1/namespaces/kube-system/pods?continue=": k8sPodsKubeSystem,
+	}
+
+	t.Run("namespaced", func(t *testing.T) {
+		c := newTestK8sCollector(t, pages, []string{"default", "kube-system"})
+		pods, err := c.CollectPods(context.Background())
+		if err != nil {
+			t.Fatalf("CollectPods: %v", err)
+		}
+		if len(pods) != 3 {
+			t.Fatalf("got %d pods, want 3", len(pods))
+		}
+		web := pods[0]
+		if web.Namespace != "default" || web.NodeName != "worker-1" || web.Phase != "Running" || web.PodIP != "10.244.1.17" {
+			t.Errorf("web pod = %+v", web)
+		}
+		if strings.Join(web.Images, ",") != "nginx:1.25,nginx/nginx-prometheus-exporter:1.1" {
+			t.Errorf("web images = %v", web.Images)
+		}
+		if !pods[2].HostNetwork {
+			t.Error("kube-proxy should be host-network")
+		}
+	})
+
+	t.Run("all namespaces forbidden", func(t *testing.T) {
+		// Only the namespaced paths are served, so a cluster-wide list
+		// gets the RBAC error.
+		c := newTestK8sCollector(t, pages, nil)
+		_, err := c.CollectPods(context.Background())
+		if err == nil {
+			t.Fatal("expected error listing pods cluster-wide")
+		}
+		if !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "cannot list resource") {
+			t.Errorf("error should carry the API status message: %v", err)
+		}
+	})
+}
+
+func TestK8sCollector_Unauthorized(t *testing.T) {
+	srv := newTestProxmoxServer(t, k8sTestHandler(t, nil))
+	c, err := NewK8sCollector(&K8sConfig{Server: srv.URL, Token: "expired"}, nil, zap.NewNop())
+	if err != nil {
+		t.Fatalf("NewK8sCollector: %v", err)
+	}
+	_, err = c.CollectNodes(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "401: Unauthorized") {
+		t.Errorf("CollectNodes error = %v, want 401 Unauthorized", err)
+	}
+}
+
+func TestNewK8sCollector_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  K8sConfig
+	}{
+		{name: "empty server", cfg: K8sConfig{}},
+		{name: "no scheme", cfg: K8sConfig{Server: "10.0.0.11:6443"}},
+		{name: "bad CA", cfg: K8sConfig{Server: "https://10.0.0.11:6443", CAData: []byte("not a certificate")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewK8sCollector(&tt.cfg, nil, zap.NewNop()); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestK8sNode_Device(t *testing.T) {
+	n := K8sNode{
+		Name:             "cp-1",
+		InternalIP:       "10.0.0.11",
+		Roles:            []string{"control-plane"},
+		Ready:            true,
+		KernelVersion:    "6.1.0-18-amd64",
+		OSImage:          "Debian GNU/Linux 12 (bookworm)",
+		ContainerRuntime: "containerd://1.7.13",
+		KubeletVersion:   "v1.29.2",
+		CPUCores:         4,
+		MemoryBytes:      8 << 30,
+		PodCapacity:      110,
+	}
+	dev := n.Device()
+	if dev.Hostname != "cp-1" || dev.DeviceType != models.DeviceTypeServer || dev.Status != models.DeviceStatusOnline {
+		t.Errorf("device = %+v", dev)
+	}
+	if dev.DiscoveryMethod != models.DiscoveryKubernetes {
+		t.Errorf("DiscoveryMethod = %q, want kubernetes", dev.DiscoveryMethod)
+	}
+	if len(dev.IPAddresses) != 1 || dev.IPAddresses[0] != "10.0.0.11" {
+		t.Errorf("IPAddresses = %v", dev.IPAddresses)
+	}
+	if strings.Join(dev.Tags, ",") != "k8s,k8s-node,k8s-role:control-plane" {
+		t.Errorf("Tags = %v", dev.Tags)
+	}
+	for _, want := range []string{"4 CPU, 8192 MiB memory, 110 pods", "containerd://1.7.13", "v1.29.2"} {
+		if !strings.Contains(dev.Notes, want) {
+			t.Errorf("Notes missing %q: %s", want, dev.Notes)
+		}
+	}
+
+	n.Ready = false
+	if got := n.Device().Status; got != models.DeviceStatusDegraded {
+		t.Errorf("not-ready node status = %q, want degraded", got)
+	}
+}
+
+func TestK8sPod_Device(t *testing.T) {
+	tests := []struct {
+		name       string
+		pod        K8sPod
+		wantStatus models.DeviceStatus
+		wantIP     string
+	}{
+		{
+			name:       "running",
+			pod:        K8sPod{Name: "web-1", Namespace: "default", Phase: "Running", PodIP: "10.244.1.17", Images: []string{"nginx:1.25"}},
+			wantStatus: models.DeviceStatusOnline,
+			wantIP:     "10.244.1.17",
+		},
+		{
+			name:       "completed job",
+			pod:        K8sPod{Name: "migrate-1", Namespace: "default", Phase: "Succeeded", PodIP: "10.244.1.9"},
+			wantStatus: models.DeviceStatusOffline,
+			wantIP:     "10.244.1.9",
+		},
+		{
+			name:       "host network",
+			pod:        K8sPod{Name: "kube-proxy-8xk2p", Namespace: "kube-system", Phase: "Running", PodIP: "10.0.0.11", HostNetwork: true},
+			wantStatus: models.DeviceStatusOnline,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dev := tt.pod.Device("node-dev-1")
+			if dev.DeviceType != models.DeviceTypeContainer || dev.ParentDeviceID != "node-dev-1" {
+				t.Errorf("device = %+v", dev)
+			}
+			if dev.Status != tt.wantStatus {
+				t.Errorf("Status = %q, want %q", dev.Status, tt.wantStatus)
+			}
+			gotIP := ""
+			if len(dev.IPAddresses) > 0 {
+				gotIP = dev.IPAddresses[0]
+			}
+			if gotIP != tt.wantIP {
+				t.Errorf("IP = %q, want %q", gotIP, tt.wantIP)
+			}
+			if !strings.Contains(strings.Join(dev.Tags, ","), "namespace:"+tt.pod.Namespace) {
+				t.Errorf("Tags = %v, want namespace tag", dev.Tags)
+			}
+		})
+	}
+}
+
+func TestLoadKubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	caPEM := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+	if err := os.WriteFile(filepath.Join(dir, "ca.crt"), []byte(caPEM), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := `apiVersion: v1
+kind: Config
+current-context: homelab
+clusters:
+- name: homelab
+  cluster:
+    server: https://10.0.0.11:6443
+    certificate-authority: ca.crt
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+    insecure-skip-tls-verify: true
+contexts:
+- name: homelab
+  context:
+    cluster: homelab
+    user: subnetree
+- name: staging
+  context:
+    cluster: staging
+    user: staging-admin
+- name: eks
+  context:
+    cluster: staging
+    user: aws
+users:
+- name: subnetree
+  user:
+    token: ` + k8sTestToken + `
+- name: staging-admin
+  user:
+    client-certificate-data: ` + base64.StdEncoding.EncodeToString([]byte("CERT")) + `
+    client-key-data: ` + base64.StdEncoding.EncodeToString([]byte("KEY")) + `
+- name: aws
+  user:
+    exec:
+      apiVersion: client.authentication.k8s.io/v1beta1
+      command: aws
+`
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadKubeconfig(path, "")
+	if err != nil {
+		t.Fatalf("LoadKubeconfig current context: %v", err)
+	}
+	if cfg.Server != "https://10.0.0.11:6443" || cfg.Token != k8sTestToken || string(cfg.CAData) != caPEM {
+		t.Errorf("homelab config = %+v", cfg)
+	}
+
+	cfg, err = LoadKubeconfig(path, "staging")
+	if err != nil {
+		t.Fatalf("LoadKubeconfig staging: %v", err)
+	}
+	if !cfg.InsecureSkipVerify || string(cfg.ClientCertData) != "CERT" || string(cfg.ClientKeyData) != "KEY" {
+		t.Errorf("staging config = %+v", cfg)
+	}
+
+	if _, err := LoadKubeconfig(path, "eks"); err == nil || !strings.Contains(err.Error(), "exec") {
+		t.Errorf("exec user error = %v, want unsupported exec plugin", err)
+	}
+	if _, err := LoadKubeconfig(path, "missing"); err == nil {
+		t.Error("expected error for unknown context")
+	}
+}
+
+func TestParseK8sQuantity(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"16318412Ki", 16318412 * 1024},
+		{"8Gi", 8 << 30},
+		{"512Mi", 512 << 20},
+		{"2G", 2e9},
+		{"110", 110},
+		{"", 0},
+		{"1.5Gi", 0},
+	}
+	for _, tt := range tests {
+		if got := parseK8sQuantity(tt.input); got != tt.want {
+			t.Errorf("parseK8sQuantity(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	if got := parseK8sCPU("3500m"); got != 3500 {
+		t.Errorf("parseK8sCPU(3500m) = %d, want 3500", got)
+	}
+	if got := parseK8sCPU("4"); got != 4000 {
+		t.Errorf("parseK8sCPU(4) = %d, want 4000", got)
+	}
+}
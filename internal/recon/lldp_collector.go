@@ -83,7 +83,7 @@ func InferDeviceTypeFromLLDPCaps(caps uint16) models.DeviceType {
 // DiscoverNeighbors queries LLDP-MIB on the given SNMP session and returns
 // all discovered neighbors. Returns an empty slice (not error) if LLDP is
 // not supported or the device has no LLDP neighbors.
-func (c *LLDPCollector) DiscoverNeighbors(g *gosnmp.GoSNMP) ([]LLDPNeighbor, error) {
+func (c *LLDPCollector) DiscoverNeighbors(g SNMPSession) ([]LLDPNeighbor, error) {
 	// Walk all columns of the lldpRemTable.
 	remTableOIDs := []string{
 		OIDLLDPRemSysDesc,
@@ -15,24 +15,6 @@ import (
 	"github.com/HerbHall/subnetree/pkg/plugin"
 )
 
-// mdnsDefaultServices lists well-known mDNS service types to query.
-var mdnsDefaultServices = []string{
-	"_http._tcp",
-	"_https._tcp",
-	"_ssh._tcp",
-	"_smb._tcp",
-	"_nfs._tcp",
-	"_ipp._tcp",
-	"_printer._tcp",
-	"_airplay._tcp",
-	"_raop._tcp",
-	"_googlecast._tcp",
-	"_homekit._tcp",
-	"_hap._tcp",
-	"_mqtt._tcp",
-	"_workstation._tcp",
-}
-
 // MDNSListener passively discovers devices via mDNS/Bonjour service announcements.
 type MDNSListener struct {
 	store    *ReconStore
@@ -245,20 +227,3 @@ func (l *MDNSListener) publishEvent(ctx context.Context, topic string, payload a
 		Payload:   payload,
 	})
 }
-
-// inferDeviceTypeFromService guesses the device type from the mDNS service name.
-func inferDeviceTypeFromService(service string) models.DeviceType {
-	switch {
-	case strings.Contains(service, "printer") || strings.Contains(service, "ipp"):
-		return models.DeviceTypePrinter
-	case strings.Contains(service, "airplay") || strings.Contains(service, "raop") ||
-		strings.Contains(service, "googlecast"):
-		return models.DeviceTypeIoT
-	case strings.Contains(service, "homekit") || strings.Contains(service, "hap") ||
-		strings.Contains(service, "mqtt"):
-		return models.DeviceTypeIoT
-	default:
-		return models.DeviceTypeUnknown
-	}
-}
-
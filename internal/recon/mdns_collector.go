@@ -0,0 +1,388 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+	"golang.org/x/net/ipv4"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// mdnsGroupAddr is the IPv4 mDNS multicast group and port (RFC 6762).
+var mdnsGroupAddr = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// mdnsDefaultServices lists well-known mDNS service types to query.
+var mdnsDefaultServices = []string{
+	"_http._tcp",
+	"_https._tcp",
+	"_ssh._tcp",
+	"_smb._tcp",
+	"_nfs._tcp",
+	"_ipp._tcp",
+	"_printer._tcp",
+	"_airplay._tcp",
+	"_raop._tcp",
+	"_googlecast._tcp",
+	"_homekit._tcp",
+	"_hap._tcp",
+	"_mqtt._tcp",
+	"_workstation._tcp",
+}
+
+// MDNSCollector actively browses DNS-SD service types on the local link and
+// reports the hosts that answer. Unlike MDNSListener it runs as part of a
+// scan so results are linked to the scan and respect its subnet.
+type MDNSCollector struct {
+	timeout  time.Duration
+	services []string
+	logger   *zap.Logger
+}
+
+// MDNSHost is a host that advertised one or more DNS-SD services.
+type MDNSHost struct {
+	Hostname    string        `json:"hostname"`
+	IPAddresses []string      `json:"ip_addresses"`
+	Services    []MDNSService `json:"services"`
+}
+
+// MDNSService is a single DNS-SD service instance advertised by a host.
+type MDNSService struct {
+	Type     string `json:"type"`     // e.g. "_ipp._tcp"
+	Instance string `json:"instance"` // e.g. "Office Printer"
+	Port     int    `json:"port"`
+}
+
+// NewMDNSCollector creates a collector that browses the default service
+// types and waits up to timeout for responses.
+func NewMDNSCollector(timeout time.Duration, logger *zap.Logger) *MDNSCollector {
+	return &MDNSCollector{
+		timeout:  timeout,
+		services: mdnsDefaultServices,
+		logger:   logger,
+	}
+}
+
+// Browse sends a PTR query for each service type out of the named interface
+// (or the system default when iface is empty) and collects responses until
+// the discovery timeout or ctx expires.
+//
+// The query is sent from an ephemeral port, so responders reply with legacy
+// unicast (RFC 6762 section 6.7) and no bind to port 5353 is needed. This
+// keeps the collector working alongside avahi or mDNSResponder.
+func (c *MDNSCollector) Browse(ctx context.Context, iface string) ([]MDNSHost, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4zero})
+	if err != nil {
+		return nil, fmt.Errorf("open mDNS socket: %w", err)
+	}
+	defer conn.Close()
+
+	if iface != "" {
+		ifi, err := net.InterfaceByName(iface)
+		if err != nil {
+			return nil, fmt.Errorf("scan interface %q: %w", iface, err)
+		}
+		if err := ipv4.NewPacketConn(conn).SetMulticastInterface(ifi); err != nil {
+			return nil, fmt.Errorf("set mDNS multicast interface %q: %w", iface, err)
+		}
+	}
+
+	query, err := buildMDNSQuery(c.services)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.WriteToUDP(query, mdnsGroupAddr); err != nil {
+		return nil, fmt.Errorf("send mDNS query: %w", err)
+	}
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("set mDNS read deadline: %w", err)
+	}
+
+	// Unblock the read loop early if the scan is cancelled.
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	agg := newMDNSAggregator()
+	buf := make([]byte, 9000)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			}
+			return nil, fmt.Errorf("read mDNS response: %w", err)
+		}
+		if err := agg.AddPacket(buf[:n]); err != nil {
+			c.logger.Debug("ignoring malformed mDNS packet", zap.Error(err))
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	hosts := agg.Hosts()
+	c.logger.Debug("mDNS browse complete",
+		zap.String("interface", iface),
+		zap.Int("hosts", len(hosts)))
+	return hosts, nil
+}
+
+// buildMDNSQuery packs a single query message with one PTR question per
+// service type.
+func buildMDNSQuery(services []string) ([]byte, error) {
+	msg := new(dns.Msg)
+	msg.Id = dns.Id()
+	msg.RecursionDesired = false
+	for _, svc := range services {
+		msg.Question = append(msg.Question, dns.Question{
+			Name:   dns.Fqdn(svc + ".local"),
+			Qtype:  dns.TypePTR,
+			Qclass: dns.ClassINET,
+		})
+	}
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("pack mDNS query: %w", err)
+	}
+	return packed, nil
+}
+
+// mdnsInstance is a service instance assembled from PTR and SRV records.
+type mdnsInstance struct {
+	name        string
+	serviceType string
+	target      string
+	port        int
+}
+
+// mdnsAggregator assembles hosts from DNS-SD records that may be spread
+// across several response packets.
+type mdnsAggregator struct {
+	instances map[string]*mdnsInstance // instance FQDN -> instance
+	addrs     map[string][]string      // host FQDN -> addresses
+}
+
+func newMDNSAggregator() *mdnsAggregator {
+	return &mdnsAggregator{
+		instances: make(map[string]*mdnsInstance),
+		addrs:     make(map[string][]string),
+	}
+}
+
+// AddPacket parses one mDNS response packet and records its PTR, SRV, A,
+// and AAAA records. Queries are ignored.
+func (a *mdnsAggregator) AddPacket(pkt []byte) error {
+	var msg dns.Msg
+	if err := msg.Unpack(pkt); err != nil {
+		return fmt.Errorf("unpack mDNS packet: %w", err)
+	}
+	if !msg.Response {
+		return nil
+	}
+
+	records := make([]dns.RR, 0, len(msg.Answer)+len(msg.Ns)+len(msg.Extra))
+	records = append(records, msg.Answer...)
+	records = append(records, msg.Ns...)
+	records = append(records, msg.Extra...)
+	for _, rr := range records {
+		switch r := rr.(type) {
+		case *dns.PTR:
+			inst := a.instance(r.Ptr)
+			inst.serviceType = mdnsServiceType(r.Hdr.Name)
+		case *dns.SRV:
+			inst := a.instance(r.Hdr.Name)
+			inst.target = strings.ToLower(r.Target)
+			inst.port = int(r.Port)
+			if inst.serviceType == "" {
+				inst.serviceType = mdnsServiceType(mdnsInstanceParent(r.Hdr.Name))
+			}
+		case *dns.A:
+			a.addAddr(r.Hdr.Name, r.A.String())
+		case *dns.AAAA:
+			a.addAddr(r.Hdr.Name, r.AAAA.String())
+		}
+	}
+	return nil
+}
+
+func (a *mdnsAggregator) instance(name string) *mdnsInstance {
+	key := strings.ToLower(name)
+	inst, ok := a.instances[key]
+	if !ok {
+		inst = &mdnsInstance{name: name}
+		a.instances[key] = inst
+	}
+	return inst
+}
+
+func (a *mdnsAggregator) addAddr(host, addr string) {
+	key := strings.ToLower(host)
+	for _, existing := range a.addrs[key] {
+		if existing == addr {
+			return
+		}
+	}
+	a.addrs[key] = append(a.addrs[key], addr)
+}
+
+// Hosts returns every host with at least one resolved service and address,
+// sorted by hostname. Instances without an SRV record or whose target did
+// not resolve are dropped.
+func (a *mdnsAggregator) Hosts() []MDNSHost {
+	byTarget := make(map[string]*MDNSHost)
+	for _, inst := range a.instances {
+		if inst.target == "" || inst.serviceType == "" {
+			continue
+		}
+		addrs := a.addrs[inst.target]
+		if len(addrs) == 0 {
+			continue
+		}
+		host, ok := byTarget[inst.target]
+		if !ok {
+			host = &MDNSHost{
+				Hostname:    strings.TrimSuffix(inst.target, "."),
+				IPAddresses: sortedIPs(addrs),
+			}
+			byTarget[inst.target] = host
+		}
+		host.Services = append(host.Services, MDNSService{
+			Type:     inst.serviceType,
+			Instance: mdnsInstanceLabel(inst.name),
+			Port:     inst.port,
+		})
+	}
+
+	hosts := make([]MDNSHost, 0, len(byTarget))
+	for _, h := range byTarget {
+		sort.Slice(h.Services, func(i, j int) bool {
+			if h.Services[i].Type != h.Services[j].Type {
+				return h.Services[i].Type < h.Services[j].Type
+			}
+			return h.Services[i].Instance < h.Services[j].Instance
+		})
+		hosts = append(hosts, *h)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Hostname < hosts[j].Hostname })
+	return hosts
+}
+
+// sortedIPs orders IPv4 addresses before IPv6 so the first address is the
+// one the store matches on.
+func sortedIPs(addrs []string) []string {
+	out := append([]string(nil), addrs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		iv4 := net.ParseIP(out[i]).To4() != nil
+		jv4 := net.ParseIP(out[j]).To4() != nil
+		if iv4 != jv4 {
+			return iv4
+		}
+		return out[i] < out[j]
+	})
+	return out
+}
+
+// mdnsServiceType converts "_ipp._tcp.local." to "_ipp._tcp".
+func mdnsServiceType(name string) string {
+	return strings.TrimSuffix(strings.ToLower(dns.Fqdn(name)), ".local.")
+}
+
+// mdnsInstanceParent strips the instance label from an instance FQDN,
+// leaving the service type domain.
+func mdnsInstanceParent(name string) string {
+	labels := dns.SplitDomainName(name)
+	if len(labels) < 2 {
+		return name
+	}
+	return strings.Join(labels[1:], ".") + "."
+}
+
+// mdnsInstanceLabel returns the unescaped first label of an instance FQDN,
+// e.g. "Office Printer" for "Office\ Printer._ipp._tcp.local.".
+func mdnsInstanceLabel(name string) string {
+	labels := dns.SplitDomainName(name)
+	if len(labels) == 0 {
+		return ""
+	}
+	return unescapeDNSLabel(labels[0])
+}
+
+// unescapeDNSLabel reverses the presentation-format escaping applied by
+// the dns package ("\ " and "\DDD").
+func unescapeDNSLabel(label string) string {
+	if !strings.Contains(label, `\`) {
+		return label
+	}
+	var b strings.Builder
+	for i := 0; i < len(label); i++ {
+		ch := label[i]
+		if ch != '\\' || i+1 >= len(label) {
+			b.WriteByte(ch)
+			continue
+		}
+		if i+3 < len(label) && isDigit(label[i+1]) && isDigit(label[i+2]) && isDigit(label[i+3]) {
+			b.WriteByte((label[i+1]-'0')*100 + (label[i+2]-'0')*10 + (label[i+3] - '0'))
+			i += 3
+			continue
+		}
+		b.WriteByte(label[i+1])
+		i++
+	}
+	return b.String()
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+// Device maps the host to a device record. Each advertised service type is
+// kept as an "mdns:" tag and the device type is inferred from the services.
+func (h *MDNSHost) Device() *models.Device {
+	dev := &models.Device{
+		Hostname:        h.Hostname,
+		IPAddresses:     h.IPAddresses,
+		Status:          models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoverymDNS,
+		DeviceType:      models.DeviceTypeUnknown,
+	}
+	seen := make(map[string]bool)
+	for _, svc := range h.Services {
+		if dev.DeviceType == models.DeviceTypeUnknown {
+			dev.DeviceType = inferDeviceTypeFromService(svc.Type)
+		}
+		if !seen[svc.Type] {
+			seen[svc.Type] = true
+			dev.Tags = append(dev.Tags, "mdns:"+svc.Type)
+		}
+	}
+	return dev
+}
+
+// inferDeviceTypeFromService guesses the device type from the mDNS service name.
+func inferDeviceTypeFromService(service string) models.DeviceType {
+	switch {
+	case strings.Contains(service, "printer") || strings.Contains(service, "ipp"):
+		return models.DeviceTypePrinter
+	case strings.Contains(service, "airplay") || strings.Contains(service, "raop") ||
+		strings.Contains(service, "googlecast"):
+		return models.DeviceTypeIoT
+	case strings.Contains(service, "homekit") || strings.Contains(service, "hap") ||
+		strings.Contains(service, "mqtt"):
+		return models.DeviceTypeIoT
+	default:
+		return models.DeviceTypeUnknown
+	}
+}
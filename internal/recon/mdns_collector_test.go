@@ -0,0 +1,285 @@
+package recon
+
+import (
+	"context"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// mdnsResponse packs a canned mDNS response with the given answer and
+// additional records, as a responder would send it.
+func mdnsResponse(t *testing.T, answers, extra []string) []byte {
+	t.Helper()
+	msg := new(dns.Msg)
+	msg.Response = true
+	msg.Authoritative = true
+	for _, s := range answers {
+		msg.Answer = append(msg.Answer, mustRR(t, s))
+	}
+	for _, s := range extra {
+		msg.Extra = append(msg.Extra, mustRR(t, s))
+	}
+	pkt, err := msg.Pack()
+	if err != nil {
+		t.Fatalf("pack response: %v", err)
+	}
+	return pkt
+}
+
+func mustRR(t *testing.T, s string) dns.RR {
+	t.Helper()
+	rr, err := dns.NewRR(s)
+	if err != nil {
+		t.Fatalf("parse RR %q: %v", s, err)
+	}
+	return rr
+}
+
+func TestMDNSAggregator_Printer(t *testing.T) {
+	pkt := mdnsResponse(t,
+		[]string{`_ipp._tcp.local. 4500 IN PTR Office\ Printer._ipp._tcp.local.`},
+		[]string{
+			`Office\ Printer._ipp._tcp.local. 120 IN SRV 0 0 631 BRN001.local.`,
+			`Office\ Printer._ipp._tcp.local. 4500 IN TXT "rp=ipp/print" "ty=Brother HL-L2350DW"`,
+			`BRN001.local. 120 IN A 192.168.1.40`,
+			`BRN001.local. 120 IN AAAA fe80::1`,
+		},
+	)
+
+	agg := newMDNSAggregator()
+	if err := agg.AddPacket(pkt); err != nil {
+		t.Fatalf("AddPacket: %v", err)
+	}
+
+	hosts := agg.Hosts()
+	if len(hosts) != 1 {
+		t.Fatalf("hosts = %d, want 1", len(hosts))
+	}
+	h := hosts[0]
+	if h.Hostname != "brn001.local" {
+		t.Errorf("Hostname = %q, want brn001.local", h.Hostname)
+	}
+	if want := []string{"192.168.1.40", "fe80::1"}; !slices.Equal(h.IPAddresses, want) {
+		t.Errorf("IPAddresses = %v, want %v", h.IPAddresses, want)
+	}
+	want := []MDNSService{{Type: "_ipp._tcp", Instance: "Office Printer", Port: 631}}
+	if !slices.Equal(h.Services, want) {
+		t.Errorf("Services = %+v, want %+v", h.Services, want)
+	}
+}
+
+func TestMDNSAggregator_MultiplePackets(t *testing.T) {
+	agg := newMDNSAggregator()
+
+	// A Chromecast answers its own query; the NAS splits PTR and SRV/A
+	// across two packets and advertises two service types.
+	packets := [][]byte{
+		mdnsResponse(t,
+			[]string{`_googlecast._tcp.local. 120 IN PTR Chromecast-abc._googlecast._tcp.local.`},
+			[]string{
+				`Chromecast-abc._googlecast._tcp.local. 120 IN SRV 0 0 8009 abc.local.`,
+				`abc.local. 120 IN A 192.168.1.50`,
+			},
+		),
+		mdnsResponse(t,
+			[]string{
+				`_smb._tcp.local. 4500 IN PTR nas._smb._tcp.local.`,
+				`_http._tcp.local. 4500 IN PTR nas\ admin._http._tcp.local.`,
+			},
+			nil,
+		),
+		mdnsResponse(t,
+			[]string{
+				`nas._smb._tcp.local. 120 IN SRV 0 0 445 nas.local.`,
+				`nas\ admin._http._tcp.local. 120 IN SRV 0 0 5000 nas.local.`,
+				`nas.local. 120 IN A 192.168.1.10`,
+			},
+			nil,
+		),
+	}
+	for i, pkt := range packets {
+		if err := agg.AddPacket(pkt); err != nil {
+			t.Fatalf("AddPacket(%d): %v", i, err)
+		}
+	}
+
+	hosts := agg.Hosts()
+	if len(hosts) != 2 {
+		t.Fatalf("hosts = %+v, want 2", hosts)
+	}
+
+	cast, nas := hosts[0], hosts[1]
+	if cast.Hostname != "abc.local" || !slices.Equal(cast.IPAddresses, []string{"192.168.1.50"}) {
+		t.Errorf("chromecast = %+v", cast)
+	}
+	if want := []MDNSService{{Type: "_googlecast._tcp", Instance: "Chromecast-abc", Port: 8009}}; !slices.Equal(cast.Services, want) {
+		t.Errorf("chromecast services = %+v, want %+v", cast.Services, want)
+	}
+
+	if nas.Hostname != "nas.local" || !slices.Equal(nas.IPAddresses, []string{"192.168.1.10"}) {
+		t.Errorf("nas = %+v", nas)
+	}
+	wantNAS := []MDNSService{
+		{Type: "_http._tcp", Instance: "nas admin", Port: 5000},
+		{Type: "_smb._tcp", Instance: "nas", Port: 445},
+	}
+	if !slices.Equal(nas.Services, wantNAS) {
+		t.Errorf("nas services = %+v, want %+v", nas.Services, wantNAS)
+	}
+}
+
+func TestMDNSAggregator_IgnoresIncomplete(t *testing.T) {
+	agg := newMDNSAggregator()
+
+	// A query is not a response and must not contribute records.
+	query, err := buildMDNSQuery([]string{"_http._tcp"})
+	if err != nil {
+		t.Fatalf("buildMDNSQuery: %v", err)
+	}
+	if err := agg.AddPacket(query); err != nil {
+		t.Fatalf("AddPacket(query): %v", err)
+	}
+
+	// PTR without SRV, and SRV whose target never resolves.
+	pkt := mdnsResponse(t,
+		[]string{
+			`_ssh._tcp.local. 120 IN PTR lonely._ssh._tcp.local.`,
+			`ghost._http._tcp.local. 120 IN SRV 0 0 80 ghost.local.`,
+		},
+		nil,
+	)
+	if err := agg.AddPacket(pkt); err != nil {
+		t.Fatalf("AddPacket: %v", err)
+	}
+
+	if hosts := agg.Hosts(); len(hosts) != 0 {
+		t.Errorf("hosts = %+v, want none", hosts)
+	}
+}
+
+func TestMDNSAggregator_Malformed(t *testing.T) {
+	agg := newMDNSAggregator()
+	if err := agg.AddPacket([]byte{0x00, 0x01, 0x84}); err == nil {
+		t.Error("AddPacket(truncated) error = nil, want error")
+	}
+}
+
+func TestBuildMDNSQuery(t *testing.T) {
+	pkt, err := buildMDNSQuery([]string{"_ipp._tcp", "_googlecast._tcp"})
+	if err != nil {
+		t.Fatalf("buildMDNSQuery: %v", err)
+	}
+	var msg dns.Msg
+	if err := msg.Unpack(pkt); err != nil {
+		t.Fatalf("Unpack: %v", err)
+	}
+	if msg.Response {
+		t.Error("query has response bit set")
+	}
+	var names []string
+	for _, q := range msg.Question {
+		if q.Qtype != dns.TypePTR {
+			t.Errorf("question %s type = %d, want PTR", q.Name, q.Qtype)
+		}
+		names = append(names, q.Name)
+	}
+	if want := []string{"_ipp._tcp.local.", "_googlecast._tcp.local."}; !slices.Equal(names, want) {
+		t.Errorf("questions = %v, want %v", names, want)
+	}
+}
+
+func TestMDNSHost_Device(t *testing.T) {
+	h := MDNSHost{
+		Hostname:    "brn001.local",
+		IPAddresses: []string{"192.168.1.40"},
+		Services: []MDNSService{
+			{Type: "_http._tcp", Instance: "Brother", Port: 80},
+			{Type: "_ipp._tcp", Instance: "Office Printer", Port: 631},
+			{Type: "_ipp._tcp", Instance: "Office Printer (fax)", Port: 631},
+		},
+	}
+	dev := h.Device()
+	if dev.Hostname != "brn001.local" || !slices.Equal(dev.IPAddresses, h.IPAddresses) {
+		t.Errorf("device = %+v", dev)
+	}
+	if dev.DiscoveryMethod != models.DiscoverymDNS {
+		t.Errorf("DiscoveryMethod = %q, want %q", dev.DiscoveryMethod, models.DiscoverymDNS)
+	}
+	if dev.DeviceType != models.DeviceTypePrinter {
+		t.Errorf("DeviceType = %q, want %q", dev.DeviceType, models.DeviceTypePrinter)
+	}
+	if want := []string{"mdns:_http._tcp", "mdns:_ipp._tcp"}; !slices.Equal(dev.Tags, want) {
+		t.Errorf("Tags = %v, want %v", dev.Tags, want)
+	}
+}
+
+type fakeMDNSBrowser struct {
+	hosts []MDNSHost
+	iface string
+}
+
+func (b *fakeMDNSBrowser) Browse(_ context.Context, iface string) ([]MDNSHost, error) {
+	b.iface = iface
+	return b.hosts, nil
+}
+
+type staticScanInterface string
+
+func (s staticScanInterface) ScanInterface(context.Context) (string, error) {
+	return string(s), nil
+}
+
+func TestScanOrchestrator_BrowseMDNS(t *testing.T) {
+	orch, reconStore, collector := setupOrchestrator(t,
+		&mockPingScanner{}, &mockARPReader{}, &mockOUI{table: map[string]string{}})
+	ctx := context.Background()
+
+	browser := &fakeMDNSBrowser{hosts: []MDNSHost{
+		{
+			Hostname:    "abc.local",
+			IPAddresses: []string{"192.168.1.50"},
+			Services:    []MDNSService{{Type: "_googlecast._tcp", Instance: "Chromecast-abc", Port: 8009}},
+		},
+		{
+			// Outside the scanned subnet.
+			Hostname:    "remote.local",
+			IPAddresses: []string{"10.0.0.9"},
+			Services:    []MDNSService{{Type: "_ssh._tcp", Instance: "remote", Port: 22}},
+		},
+	}}
+	orch.SetMDNSBrowser(browser)
+	orch.SetScanInterfaceSource(staticScanInterface("eth1"))
+
+	if err := reconStore.CreateScan(ctx, &models.ScanResult{ID: "scan-mdns", Subnet: "192.168.1.0/24", Status: "running"}); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+	_, ipNet, _ := net.ParseCIDR("192.168.1.0/24")
+	orch.browseMDNS(ctx, "scan-mdns", ipNet, nil)
+
+	if browser.iface != "eth1" {
+		t.Errorf("browsed interface = %q, want eth1", browser.iface)
+	}
+
+	dev, err := reconStore.GetDeviceByIP(ctx, "192.168.1.50")
+	if err != nil || dev == nil {
+		t.Fatalf("GetDeviceByIP: %v, %v", dev, err)
+	}
+	if dev.Hostname != "abc.local" || dev.DeviceType != models.DeviceTypeIoT {
+		t.Errorf("device = %+v", dev)
+	}
+	if !slices.Contains(dev.Tags, "mdns:_googlecast._tcp") {
+		t.Errorf("Tags = %v, want mdns:_googlecast._tcp", dev.Tags)
+	}
+
+	if remote, _ := reconStore.GetDeviceByIP(ctx, "10.0.0.9"); remote != nil {
+		t.Errorf("out-of-subnet host was stored: %+v", remote)
+	}
+	if n := len(collector.byTopic(TopicDeviceDiscovered)); n != 1 {
+		t.Errorf("discovered events = %d, want 1", n)
+	}
+}
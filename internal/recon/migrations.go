@@ -372,5 +372,140 @@ func migrations() []plugin.Migration {
 				return err
 			},
 		},
+		{
+			Version:     14,
+			Description: "create recon_scan_schedules table for recurring subnet scans",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS recon_scan_schedules (
+					id               TEXT PRIMARY KEY,
+					subnet           TEXT NOT NULL,
+					interval_seconds INTEGER NOT NULL,
+					enabled          INTEGER NOT NULL DEFAULT 1,
+					last_run_at      DATETIME,
+					last_scan_id     TEXT NOT NULL DEFAULT '',
+					created_at       DATETIME NOT NULL,
+					updated_at       DATETIME NOT NULL
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     15,
+			Description: "snapshot device hostname, IPs, and MAC per scan in recon_scan_devices",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE recon_scan_devices ADD COLUMN hostname TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE recon_scan_devices ADD COLUMN ip_addresses TEXT NOT NULL DEFAULT '[]'`,
+					`ALTER TABLE recon_scan_devices ADD COLUMN mac_address TEXT NOT NULL DEFAULT ''`,
+					// Existing links only know the device's current values.
+					`UPDATE recon_scan_devices SET
+						hostname     = COALESCE((SELECT hostname FROM recon_devices d WHERE d.id = device_id), ''),
+						ip_addresses = COALESCE((SELECT ip_addresses FROM recon_devices d WHERE d.id = device_id), '[]'),
+						mac_address  = COALESCE((SELECT mac_address FROM recon_devices d WHERE d.id = device_id), '')`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     16,
+			Description: "create recon_scan_exclusions table for addresses scans must skip",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS recon_scan_exclusions (
+					id          TEXT PRIMARY KEY,
+					cidr        TEXT NOT NULL UNIQUE,
+					description TEXT NOT NULL DEFAULT '',
+					created_at  DATETIME NOT NULL
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     17,
+			Description: "create recon_device_ports table for TCP port scan results",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS recon_device_ports (
+					device_id  TEXT NOT NULL REFERENCES recon_devices(id) ON DELETE CASCADE,
+					port       INTEGER NOT NULL,
+					protocol   TEXT NOT NULL DEFAULT 'tcp',
+					state      TEXT NOT NULL,
+					service    TEXT NOT NULL DEFAULT '',
+					scanned_at DATETIME NOT NULL,
+					PRIMARY KEY (device_id, port, protocol)
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     18,
+			Description: "add archived_at to recon_devices for soft delete",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE recon_devices ADD COLUMN archived_at DATETIME`,
+					`CREATE INDEX IF NOT EXISTS idx_recon_devices_archived_at ON recon_devices(archived_at)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     19,
+			Description: "create recon_device_views table for saved device searches",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`CREATE TABLE IF NOT EXISTS recon_device_views (
+					id         TEXT PRIMARY KEY,
+					user_id    TEXT NOT NULL,
+					name       TEXT NOT NULL,
+					filter     TEXT NOT NULL DEFAULT '{}',
+					options    TEXT NOT NULL DEFAULT '{}',
+					created_at DATETIME NOT NULL,
+					UNIQUE (user_id, name)
+				)`)
+				return err
+			},
+		},
+		{
+			Version:     20,
+			Description: "generalize recon_device_history to field-level changes with a source",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE recon_device_history RENAME COLUMN old_status TO old_value`,
+					`ALTER TABLE recon_device_history RENAME COLUMN new_status TO new_value`,
+					`ALTER TABLE recon_device_history ADD COLUMN field TEXT NOT NULL DEFAULT 'status'`,
+					`ALTER TABLE recon_device_history ADD COLUMN source TEXT NOT NULL DEFAULT ''`,
+					`CREATE INDEX IF NOT EXISTS idx_recon_device_history_device_changed ON recon_device_history(device_id, changed_at)`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     21,
+			Description: "add rack and rack_unit to recon_devices for rack/location grouping",
+			Up: func(tx *sql.Tx) error {
+				stmts := []string{
+					`ALTER TABLE recon_devices ADD COLUMN rack TEXT NOT NULL DEFAULT ''`,
+					`ALTER TABLE recon_devices ADD COLUMN rack_unit INTEGER NOT NULL DEFAULT 0`,
+				}
+				for _, stmt := range stmts {
+					if _, err := tx.Exec(stmt); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
 	}
 }
@@ -12,8 +12,11 @@ import (
 var ouiRawData []byte
 
 // OUITable provides MAC address prefix to manufacturer lookup.
+// The embedded vendor list is loaded lazily and can be extended at runtime
+// with Merge, e.g. from the IEEE registry.
 type OUITable struct {
 	once  sync.Once
+	mu    sync.RWMutex
 	table map[string]string
 }
 
@@ -32,9 +35,32 @@ func (o *OUITable) Lookup(mac string) string {
 	if prefix == "" {
 		return ""
 	}
+	o.mu.RLock()
+	defer o.mu.RUnlock()
 	return o.table[prefix]
 }
 
+// Merge adds or replaces vendors for the given "AA:BB:CC" prefixes. Entries
+// from the embedded list that are not in entries are kept.
+func (o *OUITable) Merge(entries map[string]string) {
+	o.once.Do(o.load)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for prefix, vendor := range entries {
+		o.table[prefix] = vendor
+	}
+}
+
+// Len returns the number of known prefixes.
+func (o *OUITable) Len() int {
+	o.once.Do(o.load)
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return len(o.table)
+}
+
 // load parses the embedded OUI data into the lookup table.
 func (o *OUITable) load() {
 	o.table = make(map[string]string, 40000)
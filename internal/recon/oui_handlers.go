@@ -0,0 +1,53 @@
+package recon
+
+import (
+	"net/http"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+	"go.uber.org/zap"
+)
+
+// requireAdmin writes an error and returns false unless the caller is an
+// authenticated admin.
+func requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user := auth.UserFromContext(r.Context())
+	if user == nil {
+		writeError(w, http.StatusUnauthorized, "authentication required")
+		return false
+	}
+	if auth.Role(user.Role) != auth.RoleAdmin {
+		writeError(w, http.StatusForbidden, "admin role required")
+		return false
+	}
+	return true
+}
+
+// handleOUIUpdate refreshes the MAC vendor table from the IEEE registry.
+//
+//	@Summary		Update OUI table
+//	@Description	Downloads the IEEE OUI registry and merges it into the MAC vendor table. On failure the current table, including the built-in vendor list, stays in use. Requires the admin role.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{object}	OUIUpdateStatus
+//	@Failure		401	{object}	models.APIProblem
+//	@Failure		403	{object}	models.APIProblem
+//	@Failure		502	{object}	models.APIProblem
+//	@Router			/recon/oui/update [post]
+func (m *Module) handleOUIUpdate(w http.ResponseWriter, r *http.Request) {
+	if !requireAdmin(w, r) {
+		return
+	}
+	if m.ouiUpdater == nil {
+		writeError(w, http.StatusServiceUnavailable, "OUI updater not available")
+		return
+	}
+
+	status, err := m.ouiUpdater.Update(r.Context())
+	if err != nil {
+		m.logger.Warn("OUI registry update failed", zap.Error(err))
+		writeError(w, http.StatusBadGateway, "OUI registry update failed; the current vendor table is still in use")
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
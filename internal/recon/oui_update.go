@@ -0,0 +1,238 @@
+package recon
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultOUIRegistryURL is the IEEE MA-L (OUI) registry in text form.
+const DefaultOUIRegistryURL = "https://standards-oui.ieee.org/oui/oui.txt"
+
+// maxOUIRegistrySize bounds the registry download. The full IEEE file is
+// around 6 MB.
+const maxOUIRegistrySize = 32 << 20
+
+// OUIUpdateStatus describes the most recent successful registry update.
+type OUIUpdateStatus struct {
+	Source    string    `json:"source"`
+	Entries   int       `json:"entries"`
+	TableSize int       `json:"table_size"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OUIUpdater refreshes an OUITable from the IEEE OUI registry. A failed
+// fetch leaves the table unchanged, so lookups keep using the embedded list
+// and any previously merged data.
+type OUIUpdater struct {
+	table     *OUITable
+	client    *http.Client
+	url       string
+	cachePath string
+	logger    *zap.Logger
+
+	mu     sync.Mutex
+	status *OUIUpdateStatus
+}
+
+// NewOUIUpdater creates an updater for table using the given config.
+func NewOUIUpdater(table *OUITable, cfg OUIUpdateConfig, logger *zap.Logger) *OUIUpdater {
+	url := cfg.URL
+	if url == "" {
+		url = DefaultOUIRegistryURL
+	}
+	return &OUIUpdater{
+		table:     table,
+		client:    &http.Client{Timeout: 2 * time.Minute},
+		url:       url,
+		cachePath: cfg.CachePath,
+		logger:    logger,
+	}
+}
+
+// Status returns the last successful update, or nil if none has happened.
+func (u *OUIUpdater) Status() *OUIUpdateStatus {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.status == nil {
+		return nil
+	}
+	st := *u.status
+	return &st
+}
+
+// LoadCache merges the cached registry, if any, into the table.
+func (u *OUIUpdater) LoadCache() error {
+	if u.cachePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(u.cachePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read OUI cache: %w", err)
+	}
+	entries, err := ParseIEEEOUI(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parse OUI cache: %w", err)
+	}
+	modTime := time.Now().UTC()
+	if info, statErr := os.Stat(u.cachePath); statErr == nil {
+		modTime = info.ModTime().UTC()
+	}
+	u.apply(entries, u.cachePath, modTime)
+	return nil
+}
+
+// Update downloads and parses the registry, merges it into the table and
+// refreshes the cache file.
+func (u *OUIUpdater) Update(ctx context.Context) (*OUIUpdateStatus, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.url, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("build OUI registry request: %w", err)
+	}
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch OUI registry: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch OUI registry: unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxOUIRegistrySize))
+	if err != nil {
+		return nil, fmt.Errorf("read OUI registry: %w", err)
+	}
+	entries, err := ParseIEEEOUI(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	if u.cachePath != "" {
+		if err := writeFileAtomic(u.cachePath, data); err != nil {
+			// The table is still updated; only the next startup loses out.
+			u.logger.Warn("failed to write OUI cache", zap.String("path", u.cachePath), zap.Error(err))
+		}
+	}
+
+	return u.apply(entries, u.url, time.Now().UTC()), nil
+}
+
+// apply merges entries into the table and records the update.
+func (u *OUIUpdater) apply(entries map[string]string, source string, at time.Time) *OUIUpdateStatus {
+	u.table.Merge(entries)
+	st := &OUIUpdateStatus{
+		Source:    source,
+		Entries:   len(entries),
+		TableSize: u.table.Len(),
+		UpdatedAt: at,
+	}
+	u.mu.Lock()
+	u.status = st
+	u.mu.Unlock()
+	u.logger.Info("OUI table updated",
+		zap.String("source", source),
+		zap.Int("entries", st.Entries),
+		zap.Int("table_size", st.TableSize),
+	)
+	cp := *st
+	return &cp
+}
+
+// Run refreshes the table every interval until ctx is cancelled.
+func (u *OUIUpdater) Run(ctx context.Context, interval time.Duration) {
+	u.logger.Info("OUI updater started", zap.Duration("interval", interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			u.logger.Info("OUI updater stopped")
+			return
+		case <-ticker.C:
+			if _, err := u.Update(ctx); err != nil {
+				u.logger.Warn("OUI registry update failed, keeping current table", zap.Error(err))
+			}
+		}
+	}
+}
+
+// ParseIEEEOUI parses the IEEE oui.txt registry format, returning vendors
+// keyed by "AA:BB:CC" prefix. Only the "(hex)" lines are used:
+//
+//	28-6F-B9   (hex)		Nokia Shanghai Bell Co., Ltd.
+//
+// It returns an error if no entries are found, so an error page or
+// truncated download is never mistaken for an empty registry.
+func ParseIEEEOUI(r io.Reader) (map[string]string, error) {
+	entries := make(map[string]string, 40000)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		idx := strings.Index(line, "(hex)")
+		if idx < 0 {
+			continue
+		}
+		prefix := normalizeMAC(strings.TrimSpace(line[:idx]))
+		vendor := strings.TrimSpace(line[idx+len("(hex)"):])
+		if prefix == "" || vendor == "" || !isHexPrefix(prefix) {
+			continue
+		}
+		entries[prefix] = vendor
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scan OUI registry: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("OUI registry contained no entries")
+	}
+	return entries, nil
+}
+
+// isHexPrefix reports whether prefix is an "AA:BB:CC" hex prefix.
+func isHexPrefix(prefix string) bool {
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if c == ':' || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'F') {
+			continue
+		}
+		return false
+	}
+	return len(prefix) == 8
+}
+
+// writeFileAtomic writes data to path via a temporary file and rename so
+// readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".oui-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,154 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+	"go.uber.org/zap"
+)
+
+// ieeeRegistryChunk is an excerpt in the IEEE oui.txt format. FA-FF-01 is
+// not in the embedded vendor list.
+const ieeeRegistryChunk = `OUI/MA-L                                                    Organization
+company_id                                                  Organization
+                                                            Address
+
+FA-FF-01   (hex)		Example Networks Ltd.
+FAFF01     (base 16)		Example Networks Ltd.
+				1 Example Way
+				Springfield    12345
+				US
+
+00-50-56   (hex)		VMware, Inc.
+005056     (base 16)		VMware, Inc.
+				3401 Hillview Avenue
+				PALO ALTO  CA  94304
+				US
+`
+
+func TestParseIEEEOUI(t *testing.T) {
+	entries, err := ParseIEEEOUI(strings.NewReader(ieeeRegistryChunk))
+	if err != nil {
+		t.Fatalf("ParseIEEEOUI: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2: %v", len(entries), entries)
+	}
+	if got := entries["FA:FF:01"]; got != "Example Networks Ltd." {
+		t.Errorf("entries[FA:FF:01] = %q, want %q", got, "Example Networks Ltd.")
+	}
+}
+
+func TestParseIEEEOUI_NoEntries(t *testing.T) {
+	if _, err := ParseIEEEOUI(strings.NewReader("<html>maintenance</html>")); err == nil {
+		t.Error("ParseIEEEOUI() error = nil, want error for input without entries")
+	}
+}
+
+func TestOUIUpdater_Update(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(ieeeRegistryChunk))
+	}))
+	defer srv.Close()
+
+	table := NewOUITable()
+	if got := table.Lookup("FA:FF:01:00:00:01"); got != "" {
+		t.Fatalf("Lookup before update = %q, want empty", got)
+	}
+
+	cache := filepath.Join(t.TempDir(), "oui.txt")
+	u := NewOUIUpdater(table, OUIUpdateConfig{URL: srv.URL, CachePath: cache}, zap.NewNop())
+	status, err := u.Update(context.Background())
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if status.Entries != 2 {
+		t.Errorf("status.Entries = %d, want 2", status.Entries)
+	}
+	if got := table.Lookup("FA:FF:01:00:00:01"); got != "Example Networks Ltd." {
+		t.Errorf("Lookup after update = %q, want %q", got, "Example Networks Ltd.")
+	}
+	// Built-in entries not in the registry chunk are kept.
+	if got := table.Lookup("DC:A6:32:00:11:22"); got != "Raspberry Pi Trading Ltd" {
+		t.Errorf("Lookup built-in = %q, want %q", got, "Raspberry Pi Trading Ltd")
+	}
+
+	// A fresh table picks the update up from the cache.
+	fresh := NewOUITable()
+	if err := NewOUIUpdater(fresh, OUIUpdateConfig{CachePath: cache}, zap.NewNop()).LoadCache(); err != nil {
+		t.Fatalf("LoadCache: %v", err)
+	}
+	if got := fresh.Lookup("FA:FF:01:00:00:01"); got != "Example Networks Ltd." {
+		t.Errorf("Lookup from cache = %q, want %q", got, "Example Networks Ltd.")
+	}
+}
+
+func TestOUIUpdater_FetchFailureKeepsTable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	table := NewOUITable()
+	u := NewOUIUpdater(table, OUIUpdateConfig{URL: srv.URL}, zap.NewNop())
+	if _, err := u.Update(context.Background()); err == nil {
+		t.Fatal("Update() error = nil, want error for 503")
+	}
+	if u.Status() != nil {
+		t.Error("Status() != nil after failed update")
+	}
+	if got := table.Lookup("00:50:56:12:34:56"); got != "VMware, Inc." {
+		t.Errorf("Lookup = %q, want built-in %q", got, "VMware, Inc.")
+	}
+}
+
+func TestHandleOUIUpdate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(ieeeRegistryChunk))
+	}))
+	defer srv.Close()
+
+	m := newTestModule(t)
+	m.ouiUpdater = NewOUIUpdater(m.oui, OUIUpdateConfig{URL: srv.URL}, zap.NewNop())
+
+	tests := []struct {
+		name       string
+		role       auth.Role
+		wantStatus int
+	}{
+		{"viewer forbidden", auth.RoleViewer, http.StatusForbidden},
+		{"admin", auth.RoleAdmin, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/oui/update", http.NoBody)
+			req = req.WithContext(auth.ContextWithUser(req.Context(), &auth.Claims{UserID: "u1", Role: string(tt.role)}))
+			w := httptest.NewRecorder()
+
+			m.handleOUIUpdate(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var status OUIUpdateStatus
+			if err := json.NewDecoder(w.Body).Decode(&status); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if status.Entries != 2 {
+				t.Errorf("Entries = %d, want 2", status.Entries)
+			}
+			if got := m.oui.Lookup("FA:FF:01:AA:BB:CC"); got != "Example Networks Ltd." {
+				t.Errorf("Lookup = %q, want %q", got, "Example Networks Ltd.")
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package recon
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// PathMTURequest is the request body for POST /pmtu.
+type PathMTURequest struct {
+	Target    string `json:"target" example:"10.8.0.1"`
+	MaxMTU    int    `json:"max_mtu,omitempty" example:"1500"`
+	TimeoutMs int    `json:"timeout_ms,omitempty" example:"1000"`
+}
+
+// PathMTUResult is the outcome of a path MTU probe. LimitingHop is the
+// router that reported "fragmentation needed" (ICMPv6 "packet too big"),
+// when one did and the MTU is below the probed maximum.
+type PathMTUResult struct {
+	Target      string  `json:"target" example:"10.8.0.1"`
+	MTU         int     `json:"mtu" example:"1420"`
+	MaxMTU      int     `json:"max_mtu" example:"1500"`
+	LimitingHop string  `json:"limiting_hop,omitempty" example:"192.168.1.1"`
+	Probes      int     `json:"probes" example:"9"`
+	DurationMs  float64 `json:"duration_ms" example:"312.5"`
+}
+
+// errDFUnsupported is returned where probes cannot be sent with the Don't
+// Fragment bit set, so a path MTU cannot be measured.
+var errDFUnsupported = errors.New("cannot set the Don't Fragment bit on ICMP probes on this platform")
+
+// errPMTUNoReply is returned when the target does not answer even a
+// minimum-size probe.
+var errPMTUNoReply = errors.New("no reply from target at the minimum MTU")
+
+const (
+	pmtuMinV4      = 68   // smallest MTU every IPv4 link must carry
+	pmtuMinV6      = 1280 // smallest MTU every IPv6 link must carry
+	pmtuDefaultMax = 1500
+	pmtuMaxMTU     = 9216
+	pmtuAttempts   = 2 // tries per size before a silent drop counts as too big
+)
+
+// pmtuProbe is the outcome of probing one packet size.
+type pmtuProbe struct {
+	fits       bool   // the target answered, so the size traverses the path
+	from       string // router reporting fragmentation needed, if any
+	nextHopMTU int    // MTU that router reported; 0 if not given
+}
+
+// searchPathMTU finds the largest size in [lo, hi] that probe reports as
+// fitting, by binary search. lo must fit or errPMTUNoReply is returned; hi
+// is tried next since most paths carry the full MTU. When a router reports
+// its next-hop MTU, sizes above it are ruled out and it is tried directly.
+// It returns the MTU, the router that limited it (if one reported), and
+// the number of sizes probed.
+func searchPathMTU(ctx context.Context, lo, hi int, probe func(ctx context.Context, size int) (pmtuProbe, error)) (mtu int, limitingHop string, probes int, err error) {
+	try := func(size int) (pmtuProbe, error) {
+		probes++
+		return probe(ctx, size)
+	}
+
+	r, err := try(lo)
+	if err != nil {
+		return 0, "", probes, err
+	}
+	if !r.fits {
+		return 0, "", probes, errPMTUNoReply
+	}
+
+	good, bad := lo, hi+1 // largest size known to fit, smallest known not to
+	next := hi
+	for bad-good > 1 {
+		if err := ctx.Err(); err != nil {
+			return good, limitingHop, probes, err
+		}
+		size := next
+		if size <= good || size >= bad {
+			size = good + (bad-good)/2
+		}
+		next = 0
+
+		r, err := try(size)
+		if err != nil {
+			return good, limitingHop, probes, err
+		}
+		if r.fits {
+			good = size
+			continue
+		}
+		bad = size
+		if r.from != "" {
+			limitingHop = r.from
+			if r.nextHopMTU > good && r.nextHopMTU < bad {
+				bad = r.nextHopMTU + 1
+				next = r.nextHopMTU
+			}
+		}
+	}
+
+	if good == hi {
+		limitingHop = ""
+	}
+	return good, limitingHop, probes, nil
+}
+
+// PathMTU measures the path MTU to target: the largest IP packet, up to
+// maxMTU, that reaches it without fragmentation. It sends ICMP Echo
+// Requests with the Don't Fragment bit set, binary-searching the size, and
+// waits up to timeoutMs for each reply.
+func PathMTU(ctx context.Context, target string, maxMTU, timeoutMs int, logger *zap.Logger) (*PathMTUResult, error) {
+	targetIP, err := resolveTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	fam, targetIP := icmpFamilyFor(targetIP)
+
+	lo, headerLen := pmtuMinV4, ipv4.HeaderLen
+	if fam == icmpFamilyV6 {
+		lo, headerLen = pmtuMinV6, ipv6.HeaderLen
+	}
+	if maxMTU <= 0 {
+		maxMTU = pmtuDefaultMax
+	}
+	maxMTU = max(maxMTU, lo)
+
+	conn, network, err := listenPMTU(fam)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	p := &pmtuProber{
+		conn:      conn,
+		fam:       fam,
+		raw:       network == fam.rawNetwork,
+		headerLen: headerLen,
+		id:        (os.Getpid() + int(tracerouteSeq.Add(1))) & 0xffff,
+		timeout:   time.Duration(timeoutMs) * time.Millisecond,
+		buf:       make([]byte, maxMTU+headerLen),
+		logger:    logger,
+	}
+	if p.raw {
+		p.dst = &net.IPAddr{IP: targetIP}
+	} else {
+		p.dst = &net.UDPAddr{IP: targetIP}
+	}
+
+	start := time.Now()
+	mtu, hop, probes, err := searchPathMTU(ctx, lo, maxMTU, p.probe)
+	if err != nil {
+		return nil, err
+	}
+	return &PathMTUResult{
+		Target:      targetIP.String(),
+		MTU:         mtu,
+		MaxMTU:      maxMTU,
+		LimitingHop: hop,
+		Probes:      probes,
+		DurationMs:  float64(time.Since(start).Microseconds()) / 1000.0,
+	}, nil
+}
+
+// pmtuProber sends sized Echo Requests on a Don't Fragment socket.
+type pmtuProber struct {
+	conn      net.PacketConn
+	fam       *icmpFamily
+	raw       bool // raw sockets also receive errors from routers
+	dst       net.Addr
+	headerLen int
+	id, seq   int
+	timeout   time.Duration
+	buf       []byte
+	logger    *zap.Logger
+}
+
+// probe sends an Echo Request making an IP packet of size bytes. A size
+// that draws no reply after pmtuAttempts tries is reported as not fitting.
+func (p *pmtuProber) probe(ctx context.Context, size int) (pmtuProbe, error) {
+	for attempt := 0; attempt < pmtuAttempts; attempt++ {
+		p.seq = (p.seq + 1) & 0xffff
+		msg := &icmp.Message{
+			Type: p.fam.echoRequest,
+			Body: &icmp.Echo{ID: p.id, Seq: p.seq, Data: make([]byte, size-p.headerLen-8)},
+		}
+		b, err := msg.Marshal(nil)
+		if err != nil {
+			return pmtuProbe{}, fmt.Errorf("marshal probe: %w", err)
+		}
+		if _, err := p.conn.WriteTo(b, p.dst); err != nil {
+			// Larger than the outgoing interface's MTU.
+			if errors.Is(err, syscall.EMSGSIZE) {
+				return pmtuProbe{}, nil
+			}
+			return pmtuProbe{}, fmt.Errorf("send probe: %w", err)
+		}
+
+		r, ok, err := p.await(ctx)
+		if err != nil || ok {
+			return r, err
+		}
+		p.logger.Debug("path MTU probe timed out", zap.Int("size", size), zap.Int("attempt", attempt+1))
+	}
+	return pmtuProbe{}, nil
+}
+
+// await reads until the reply to the current probe arrives, a router
+// reports it too big, or the probe times out (ok false).
+func (p *pmtuProber) await(ctx context.Context) (r pmtuProbe, ok bool, err error) {
+	deadline := time.Now().Add(p.timeout)
+	if d, has := ctx.Deadline(); has && d.Before(deadline) {
+		deadline = d
+	}
+	if err := p.conn.SetReadDeadline(deadline); err != nil {
+		return pmtuProbe{}, false, fmt.Errorf("set read deadline: %w", err)
+	}
+
+	for {
+		n, peer, err := p.conn.ReadFrom(p.buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return pmtuProbe{}, false, ctx.Err()
+			}
+			return pmtuProbe{}, false, nil
+		}
+		reply, err := icmp.ParseMessage(p.fam.proto, p.buf[:n])
+		if err != nil {
+			continue
+		}
+
+		switch reply.Type {
+		case ipv4.ICMPTypeEchoReply, ipv6.ICMPTypeEchoReply:
+			// Datagram sockets rewrite the ID, but only see their own replies.
+			if echo, isEcho := reply.Body.(*icmp.Echo); isEcho && echo.Seq == p.seq && (!p.raw || echo.ID == p.id) {
+				return pmtuProbe{fits: true}, true, nil
+			}
+		case ipv4.ICMPTypeDestinationUnreachable:
+			// Code 4 is "fragmentation needed and DF set"; the next-hop
+			// MTU is in the second half of the header's unused word.
+			if reply.Code == 4 && n >= 8 && matchesProbe(reply, p.id, p.seq) {
+				return pmtuProbe{from: addrIP(peer), nextHopMTU: int(binary.BigEndian.Uint16(p.buf[6:8]))}, true, nil
+			}
+		case ipv6.ICMPTypePacketTooBig:
+			if ptb, isPTB := reply.Body.(*icmp.PacketTooBig); isPTB && matchesPayloadV6(ptb.Data, p.id, p.seq) {
+				return pmtuProbe{from: addrIP(peer), nextHopMTU: ptb.MTU}, true, nil
+			}
+		}
+	}
+}
+
+// handlePathMTU measures the path MTU to a target.
+//
+//	@Summary		Discover path MTU
+//	@Description	Finds the largest packet that reaches the target without fragmentation by sending ICMP echo requests with the Don't Fragment bit set, binary-searching sizes up to max_mtu (default 1500). Reports the router that limited the MTU when it sent a "fragmentation needed" error. Returns 501 on platforms where the Don't Fragment bit cannot be set.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		PathMTURequest	true	"Path MTU parameters"
+//	@Success		200		{object}	PathMTUResult
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		429		{object}	models.APIProblem	"Too many concurrent diagnostics"
+//	@Failure		500		{object}	models.APIProblem
+//	@Failure		501		{object}	models.APIProblem
+//	@Failure		502		{object}	models.APIProblem	"Target did not reply"
+//	@Router			/recon/pmtu [post]
+func (m *Module) handlePathMTU(w http.ResponseWriter, r *http.Request) {
+	var req PathMTURequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Target == "" {
+		writeError(w, http.StatusBadRequest, "target is required")
+		return
+	}
+	if !isValidTarget(req.Target) {
+		writeError(w, http.StatusBadRequest, "target must be a valid IP address or hostname")
+		return
+	}
+	maxMTU := req.MaxMTU
+	if maxMTU == 0 {
+		maxMTU = pmtuDefaultMax
+	}
+	if maxMTU < pmtuMinV6 || maxMTU > pmtuMaxMTU {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("max_mtu must be between %d and %d", pmtuMinV6, pmtuMaxMTU))
+		return
+	}
+	timeoutMs := req.TimeoutMs
+	if timeoutMs <= 0 || timeoutMs > 5000 {
+		timeoutMs = 1000
+	}
+
+	if !acquireDiagSlot() {
+		writeError(w, http.StatusTooManyRequests, "too many concurrent diagnostic operations, please wait")
+		return
+	}
+	defer releaseDiagSlot()
+
+	// Up to ~16 sizes, each tried up to pmtuAttempts times.
+	ctx, cancel := context.WithTimeout(r.Context(), 16*pmtuAttempts*time.Duration(timeoutMs)*time.Millisecond+5*time.Second)
+	defer cancel()
+
+	run := m.pathMTU
+	if run == nil {
+		run = PathMTU
+	}
+	result, err := run(ctx, req.Target, maxMTU, timeoutMs, m.logger.Named("pmtu"))
+	switch {
+	case errors.Is(err, errDFUnsupported):
+		writeError(w, http.StatusNotImplemented, err.Error())
+		return
+	case errors.Is(err, errPMTUNoReply):
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	case err != nil:
+		m.logger.Error("path MTU discovery failed",
+			zap.String("target", req.Target),
+			zap.Error(err),
+		)
+		writeError(w, http.StatusInternalServerError, "path MTU discovery failed: "+err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
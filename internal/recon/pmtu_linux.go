@@ -0,0 +1,68 @@
+//go:build linux
+
+package recon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// listenPMTU opens an ICMP socket for fam whose probes carry the Don't
+// Fragment bit and ignore the kernel's cached path MTU. It prefers a raw
+// socket, which also receives "fragmentation needed" errors from routers,
+// and falls back to an unprivileged datagram socket, which sees only echo
+// replies.
+func listenPMTU(fam *icmpFamily) (net.PacketConn, string, error) {
+	domain, proto := syscall.AF_INET, syscall.IPPROTO_ICMP
+	level, opt, val := syscall.IPPROTO_IP, syscall.IP_MTU_DISCOVER, syscall.IP_PMTUDISC_PROBE
+	var sa syscall.Sockaddr = &syscall.SockaddrInet4{}
+	if fam == icmpFamilyV6 {
+		domain, proto = syscall.AF_INET6, syscall.IPPROTO_ICMPV6
+		level, opt, val = syscall.IPPROTO_IPV6, syscall.IPV6_MTU_DISCOVER, syscall.IPV6_PMTUDISC_PROBE
+		sa = &syscall.SockaddrInet6{}
+	}
+	setDF := func(fd int) error {
+		// PMTUDISC_PROBE sets DF on every packet without clamping sends to
+		// the cached path MTU.
+		if err := syscall.SetsockoptInt(fd, level, opt, val); err != nil {
+			return fmt.Errorf("%w: %v", errDFUnsupported, err)
+		}
+		return nil
+	}
+
+	lc := net.ListenConfig{Control: func(_, _ string, c syscall.RawConn) error {
+		var dfErr error
+		if err := c.Control(func(fd uintptr) { dfErr = setDF(int(fd)) }); err != nil {
+			return err
+		}
+		return dfErr
+	}}
+	conn, rawErr := lc.ListenPacket(context.Background(), fam.rawNetwork, fam.rawAddr)
+	if rawErr == nil {
+		return conn, fam.rawNetwork, nil
+	}
+
+	// Unprivileged ICMP datagram socket (net.ipv4.ping_group_range).
+	fd, err := syscall.Socket(domain, syscall.SOCK_DGRAM, proto)
+	if err != nil {
+		return nil, "", fmt.Errorf("open ICMP socket: %w (raw socket: %v)", err, rawErr)
+	}
+	if err := setDF(fd); err != nil {
+		syscall.Close(fd)
+		return nil, "", err
+	}
+	if err := syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return nil, "", fmt.Errorf("bind ICMP socket: %w", err)
+	}
+	f := os.NewFile(uintptr(fd), "pmtu-icmp")
+	defer f.Close()
+	conn, err = net.FilePacketConn(f)
+	if err != nil {
+		return nil, "", fmt.Errorf("open ICMP socket: %w", err)
+	}
+	return conn, fam.udpNetwork, nil
+}
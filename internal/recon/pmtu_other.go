@@ -0,0 +1,15 @@
+//go:build !linux
+
+package recon
+
+import (
+	"fmt"
+	"net"
+	"runtime"
+)
+
+// listenPMTU reports that path MTU discovery is unavailable: setting the
+// Don't Fragment bit on ICMP sockets is only implemented for Linux.
+func listenPMTU(_ *icmpFamily) (net.PacketConn, string, error) {
+	return nil, "", fmt.Errorf("%w (%s)", errDFUnsupported, runtime.GOOS)
+}
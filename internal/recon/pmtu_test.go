@@ -0,0 +1,179 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakePath simulates a path that carries packets up to mtu. When hop is
+// set, oversize probes draw a "fragmentation needed" from it, reporting
+// mtu unless reportMTU is false.
+type fakePath struct {
+	mtu       int
+	hop       string
+	reportMTU bool
+	tried     []int
+}
+
+func (f *fakePath) probe(_ context.Context, size int) (pmtuProbe, error) {
+	f.tried = append(f.tried, size)
+	if size <= f.mtu {
+		return pmtuProbe{fits: true}, nil
+	}
+	r := pmtuProbe{from: f.hop}
+	if f.hop != "" && f.reportMTU {
+		r.nextHopMTU = f.mtu
+	}
+	return r, nil
+}
+
+func TestSearchPathMTU(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      fakePath
+		lo, hi    int
+		wantMTU   int
+		wantHop   string
+		wantTried []int
+	}{
+		{
+			name:      "full MTU",
+			path:      fakePath{mtu: 1500},
+			lo:        68,
+			hi:        1500,
+			wantMTU:   1500,
+			wantTried: []int{68, 1500},
+		},
+		{
+			name:    "silent drops",
+			path:    fakePath{mtu: 1420},
+			lo:      68,
+			hi:      1500,
+			wantMTU: 1420,
+		},
+		{
+			name:      "router reports next-hop MTU",
+			path:      fakePath{mtu: 1400, hop: "10.0.0.1", reportMTU: true},
+			lo:        68,
+			hi:        1500,
+			wantMTU:   1400,
+			wantHop:   "10.0.0.1",
+			wantTried: []int{68, 1500, 1400},
+		},
+		{
+			name:    "router reports without MTU",
+			path:    fakePath{mtu: 1280, hop: "10.0.0.1"},
+			lo:      68,
+			hi:      1500,
+			wantMTU: 1280,
+			wantHop: "10.0.0.1",
+		},
+		{
+			name:    "ipv6 minimum",
+			path:    fakePath{mtu: 1280},
+			lo:      1280,
+			hi:      9000,
+			wantMTU: 1280,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mtu, hop, probes, err := searchPathMTU(context.Background(), tt.lo, tt.hi, tt.path.probe)
+			if err != nil {
+				t.Fatalf("searchPathMTU() error = %v", err)
+			}
+			if mtu != tt.wantMTU {
+				t.Errorf("mtu = %d, want %d", mtu, tt.wantMTU)
+			}
+			if hop != tt.wantHop {
+				t.Errorf("limiting hop = %q, want %q", hop, tt.wantHop)
+			}
+			if probes != len(tt.path.tried) {
+				t.Errorf("probes = %d, but %d sizes were tried", probes, len(tt.path.tried))
+			}
+			if tt.wantTried != nil && !slices.Equal(tt.path.tried, tt.wantTried) {
+				t.Errorf("tried sizes = %v, want %v", tt.path.tried, tt.wantTried)
+			}
+			// A binary search over [lo, hi] needs about log2(hi-lo) probes.
+			if probes > 16 {
+				t.Errorf("probes = %d, want at most 16", probes)
+			}
+			for _, size := range tt.path.tried {
+				if size < tt.lo || size > tt.hi {
+					t.Errorf("tried size %d outside [%d, %d]", size, tt.lo, tt.hi)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchPathMTU_NoReply(t *testing.T) {
+	path := fakePath{mtu: 0}
+	_, _, probes, err := searchPathMTU(context.Background(), 68, 1500, path.probe)
+	if !errors.Is(err, errPMTUNoReply) {
+		t.Errorf("error = %v, want errPMTUNoReply", err)
+	}
+	if probes != 1 {
+		t.Errorf("probes = %d, want 1", probes)
+	}
+}
+
+func TestSearchPathMTU_ProbeError(t *testing.T) {
+	sendErr := errors.New("network is unreachable")
+	probe := func(_ context.Context, size int) (pmtuProbe, error) {
+		if size > 68 {
+			return pmtuProbe{}, sendErr
+		}
+		return pmtuProbe{fits: true}, nil
+	}
+	if _, _, _, err := searchPathMTU(context.Background(), 68, 1500, probe); !errors.Is(err, sendErr) {
+		t.Errorf("error = %v, want %v", err, sendErr)
+	}
+}
+
+func TestHandlePathMTU(t *testing.T) {
+	m := newTestModule(t)
+	mux := viewMux(m)
+	var gotMax int
+	m.pathMTU = func(_ context.Context, target string, maxMTU, _ int, _ *zap.Logger) (*PathMTUResult, error) {
+		gotMax = maxMTU
+		if target == "192.0.2.9" {
+			return nil, errDFUnsupported
+		}
+		return &PathMTUResult{Target: target, MTU: 1420, MaxMTU: maxMTU}, nil
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantMax int
+	}{
+		{"default max", `{"target":"192.0.2.1"}`, http.StatusOK, 1500},
+		{"jumbo max", `{"target":"192.0.2.1","max_mtu":9000}`, http.StatusOK, 9000},
+		{"missing target", `{}`, http.StatusBadRequest, 0},
+		{"max too small", `{"target":"192.0.2.1","max_mtu":576}`, http.StatusBadRequest, 0},
+		{"df unsupported", `{"target":"192.0.2.9"}`, http.StatusNotImplemented, 1500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMax = 0
+			req := httptest.NewRequest(http.MethodPost, "/pmtu", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+			if gotMax != tt.wantMax {
+				t.Errorf("max MTU passed = %d, want %d", gotMax, tt.wantMax)
+			}
+		})
+	}
+}
@@ -2,19 +2,42 @@ package recon
 
 import (
 	"context"
+	"errors"
 	"net"
 	"sort"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
 	"go.uber.org/zap"
 )
 
+// PortState classifies the outcome of a TCP connect probe.
+type PortState string
+
+const (
+	// PortOpen means the connection was accepted.
+	PortOpen PortState = "open"
+	// PortClosed means the host refused the connection (TCP RST).
+	PortClosed PortState = "closed"
+	// PortFiltered means no answer arrived before the timeout, or the
+	// probe was rejected in a way that suggests a firewall.
+	PortFiltered PortState = "filtered"
+)
+
+// PortStatus is the state of one probed port.
+type PortStatus struct {
+	Port  int
+	State PortState
+}
+
 // PortScanResult holds the results of a port scan for a single host.
+// Ports is empty for ports not probed because the scan was cancelled.
 type PortScanResult struct {
 	IP        string
 	OpenPorts []int
+	Ports     []PortStatus
 }
 
 // InfrastructurePorts are TCP ports commonly found on network infrastructure devices.
@@ -29,10 +52,48 @@ var InfrastructurePorts = []int{
 	8443, // HTTPS alt (Ubiquiti UniFi)
 }
 
-// PortScanner performs targeted TCP port scans on network devices.
+// WellKnownPorts maps the TCP ports probed by the device port scan to the
+// service usually found there. The set is deliberately small: it is enough
+// to guess a device's role without a full port sweep.
+var WellKnownPorts = map[int]string{
+	21:   "ftp",
+	22:   "ssh",
+	23:   "telnet",
+	25:   "smtp",
+	53:   "dns",
+	80:   "http",
+	139:  "netbios-ssn",
+	443:  "https",
+	445:  "smb",
+	631:  "ipp",
+	1883: "mqtt",
+	3306: "mysql",
+	3389: "rdp",
+	5432: "postgresql",
+	5900: "vnc",
+	8006: "proxmox",
+	8080: "http-alt",
+	8443: "https-alt",
+	9100: "jetdirect",
+}
+
+// DefaultDevicePorts returns the ports in WellKnownPorts in ascending order.
+func DefaultDevicePorts() []int {
+	ports := make([]int, 0, len(WellKnownPorts))
+	for p := range WellKnownPorts {
+		ports = append(ports, p)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+// PortScanner performs targeted TCP port scans on network devices. The
+// concurrency limit is shared by all scans made with the same scanner.
 type PortScanner struct {
 	timeout     time.Duration
 	concurrency int
+	sem         chan struct{}
+	dial        func(ctx context.Context, network, addr string) (net.Conn, error)
 	logger      *zap.Logger
 }
 
@@ -44,42 +105,54 @@ func NewPortScanner(timeout time.Duration, concurrency int, logger *zap.Logger)
 	if concurrency <= 0 {
 		concurrency = 10
 	}
+	d := &net.Dialer{}
 	return &PortScanner{
 		timeout:     timeout,
 		concurrency: concurrency,
+		sem:         make(chan struct{}, concurrency),
+		dial:        d.DialContext,
 		logger:      logger,
 	}
 }
 
-// ScanPorts checks which of the given ports are open on the target IP.
+// ScanPorts probes the given ports on the target IP and classifies each as
+// open, closed, or filtered. Cancelling ctx aborts in-flight dials and skips
+// ports not yet probed.
 func (s *PortScanner) ScanPorts(ctx context.Context, ip string, ports []int) *PortScanResult {
 	result := &PortScanResult{IP: ip}
 
 	var mu sync.Mutex
 	var wg sync.WaitGroup
-	sem := make(chan struct{}, s.concurrency)
 
+loop:
 	for _, port := range ports {
-		if ctx.Err() != nil {
-			break
+		select {
+		case <-ctx.Done():
+			break loop
+		case s.sem <- struct{}{}:
 		}
 		wg.Add(1)
-		sem <- struct{}{}
 		go func(p int) {
 			defer wg.Done()
-			defer func() { <-sem }()
+			defer func() { <-s.sem }()
 
-			if s.isPortOpen(ctx, ip, p) {
-				mu.Lock()
+			state, ok := s.probePort(ctx, ip, p)
+			if !ok {
+				return
+			}
+			mu.Lock()
+			result.Ports = append(result.Ports, PortStatus{Port: p, State: state})
+			if state == PortOpen {
 				result.OpenPorts = append(result.OpenPorts, p)
-				mu.Unlock()
 			}
+			mu.Unlock()
 		}(port)
 	}
 	wg.Wait()
 
 	// Sort for deterministic output.
 	sort.Ints(result.OpenPorts)
+	sort.Slice(result.Ports, func(i, j int) bool { return result.Ports[i].Port < result.Ports[j].Port })
 
 	s.logger.Debug("port scan complete",
 		zap.String("ip", ip),
@@ -89,14 +162,25 @@ func (s *PortScanner) ScanPorts(ctx context.Context, ip string, ports []int) *Po
 	return result
 }
 
-// isPortOpen attempts a TCP connection to the given port.
-func (s *PortScanner) isPortOpen(ctx context.Context, ip string, port int) bool {
+// probePort attempts a TCP connection to the given port. ok is false if the
+// probe was cut short by ctx, in which case the state is unknown.
+func (s *PortScanner) probePort(ctx context.Context, ip string, port int) (state PortState, ok bool) {
+	dialCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
 	addr := net.JoinHostPort(ip, strconv.Itoa(port))
-	d := net.Dialer{Timeout: s.timeout}
-	conn, err := d.DialContext(ctx, "tcp", addr)
-	if err != nil {
-		return false
+	conn, err := s.dial(dialCtx, "tcp", addr)
+	if err == nil {
+		conn.Close()
+		return PortOpen, true
+	}
+	if ctx.Err() != nil {
+		return "", false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return PortClosed, true
 	}
-	conn.Close()
-	return true
+	// Timeouts, host/network unreachable (often an ICMP reject from a
+	// firewall), and anything else we can't tell apart from filtering.
+	return PortFiltered, true
 }
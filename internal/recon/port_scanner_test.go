@@ -1,6 +1,11 @@
 package recon
 
 import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -227,3 +232,140 @@ func TestMatchesFingerprint(t *testing.T) {
 		})
 	}
 }
+
+// localPorts returns an open port with a listener and a port that refuses
+// connections on 127.0.0.1.
+func localPorts(t *testing.T) (open, closed int) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	refused, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closed = refused.Addr().(*net.TCPAddr).Port
+	refused.Close()
+	return ln.Addr().(*net.TCPAddr).Port, closed
+}
+
+// blackholeDial wraps the scanner's dialer so connections to port hang
+// until the dial context ends, like a firewall silently dropping SYNs.
+func blackholeDial(ps *PortScanner, port int) {
+	next := ps.dial
+	ps.dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if strings.HasSuffix(addr, ":"+strconv.Itoa(port)) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+		return next(ctx, network, addr)
+	}
+}
+
+func TestPortScanner_ClassifiesPorts(t *testing.T) {
+	open, closed := localPorts(t)
+	const filtered = 9
+	ps := NewPortScanner(100*time.Millisecond, 4, zap.NewNop())
+	blackholeDial(ps, filtered)
+
+	result := ps.ScanPorts(context.Background(), "127.0.0.1", []int{open, closed, filtered})
+
+	want := map[int]PortState{open: PortOpen, closed: PortClosed, filtered: PortFiltered}
+	if len(result.Ports) != len(want) {
+		t.Fatalf("ports = %+v, want %d results", result.Ports, len(want))
+	}
+	for _, p := range result.Ports {
+		if p.State != want[p.Port] {
+			t.Errorf("port %d state = %s, want %s", p.Port, p.State, want[p.Port])
+		}
+	}
+	if len(result.OpenPorts) != 1 || result.OpenPorts[0] != open {
+		t.Errorf("open ports = %v, want [%d]", result.OpenPorts, open)
+	}
+}
+
+func TestPortScanner_CancelStopsInFlightDials(t *testing.T) {
+	ps := NewPortScanner(30*time.Second, 4, zap.NewNop())
+	var started, finished atomic.Int32
+	ps.dial = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		started.Add(1)
+		defer finished.Add(1)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan *PortScanResult, 1)
+	go func() { done <- ps.ScanPorts(ctx, "192.0.2.1", DefaultDevicePorts()) }()
+
+	// Wait for the pool to fill, then cancel.
+	deadline := time.Now().Add(2 * time.Second)
+	for started.Load() < 4 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+
+	select {
+	case result := <-done:
+		if len(result.Ports) != 0 {
+			t.Errorf("ports = %+v, want none reported for cancelled probes", result.Ports)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ScanPorts did not return after cancellation")
+	}
+	if s, f := started.Load(), finished.Load(); s != 4 || f != s {
+		t.Errorf("dials started/finished = %d/%d, want 4/4", s, f)
+	}
+}
+
+func TestScanOrchestrator_ScanDevicePorts(t *testing.T) {
+	open, closed := localPorts(t)
+	orch, reconStore, _ := setupOrchestrator(t, &mockPingScanner{}, &mockARPReader{}, &mockOUI{})
+	ctx := context.Background()
+
+	dev := &models.Device{
+		IPAddresses:     []string{"127.0.0.1"},
+		Status:          models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	if _, err := reconStore.UpsertDevice(ctx, dev); err != nil {
+		t.Fatalf("UpsertDevice: %v", err)
+	}
+	alive := []HostResult{{IP: "127.0.0.1", Alive: true}}
+
+	// Disabled by default: nothing is stored.
+	orch.scanDevicePorts(ctx, alive)
+	if ports, _ := reconStore.ListDevicePorts(ctx, dev.ID, false); len(ports) != 0 {
+		t.Fatalf("ports without port scan enabled = %+v, want none", ports)
+	}
+
+	orch.SetDevicePortScan(NewPortScanner(time.Second, 4, zap.NewNop()), []int{open, closed})
+	orch.scanDevicePorts(ctx, alive)
+
+	ports, err := reconStore.ListDevicePorts(ctx, dev.ID, false)
+	if err != nil {
+		t.Fatalf("ListDevicePorts: %v", err)
+	}
+	if len(ports) != 2 {
+		t.Fatalf("ports = %+v, want 2", ports)
+	}
+	openOnly, err := reconStore.ListDevicePorts(ctx, dev.ID, true)
+	if err != nil {
+		t.Fatalf("ListDevicePorts open: %v", err)
+	}
+	if len(openOnly) != 1 || openOnly[0].Port != open || openOnly[0].State != PortOpen {
+		t.Errorf("open ports = %+v, want only %d", openOnly, open)
+	}
+}
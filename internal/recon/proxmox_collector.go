@@ -2,10 +2,9 @@ package recon
 
 import (
 	"context"
-	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
@@ -14,14 +13,26 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrProxmoxUnreachable is returned without contacting the API when a
+// Proxmox host or cluster node has failed repeatedly and its circuit
+// breaker is open.
+var ErrProxmoxUnreachable = errors.New("proxmox host unreachable")
+
+// proxmoxAPIHost is the circuit breaker key for calls that are not scoped
+// to a cluster node (the API endpoint itself).
+const proxmoxAPIHost = ""
+
 // ProxmoxCollector collects hardware information from a Proxmox VE REST API.
 // This provides agentless hardware profiling for Proxmox-managed infrastructure.
+//
+// GETs are retried on transport errors and 5xx responses. Failures are
+// tracked per cluster node: once a node has failed several collections in a
+// row its calls fail fast with ErrProxmoxUnreachable for a cooldown period
+// instead of waiting out timeouts and retries every cycle.
 type ProxmoxCollector struct {
-	baseURL     string
-	tokenID     string //nolint:gosec // G101: token identifier, not a credential
-	tokenSecret string //nolint:gosec // G101: field name, not a credential
-	httpClient  *http.Client
-	logger      *zap.Logger
+	api     *jsonCollector
+	breaker *circuitBreaker
+	logger  *zap.Logger
 }
 
 // ProxmoxNode represents a node returned by the Proxmox /nodes endpoint.
@@ -70,30 +81,41 @@ type proxmoxResponse struct {
 }
 
 // NewProxmoxCollector creates a new collector for querying the Proxmox VE REST API.
-// The tokenID should be in the format "USER@REALM!TOKENID" and tokenSecret is
-// the corresponding API token secret.
-func NewProxmoxCollector(baseURL, tokenID, tokenSecret string, logger *zap.Logger) *ProxmoxCollector {
+// The API token is looked up through creds by credentialID each time a
+// request is made, so the collector never holds the secret. The credential's
+// Username is the token ID ("USER@REALM!TOKENID") and its Secret the
+// corresponding token secret.
+func NewProxmoxCollector(baseURL, credentialID string, creds CollectorCredentialResolver, logger *zap.Logger) *ProxmoxCollector {
+	// Proxmox API token authentication.
+	authorize := func(req *http.Request) error {
+		cred, err := creds.ResolveCollectorCredential(req.Context(), credentialID)
+		if err != nil {
+			return fmt.Errorf("resolve credential %s: %w", credentialID, err)
+		}
+		if cred == nil || cred.Username == "" || cred.Secret == "" {
+			return fmt.Errorf("credential %s has no API token ID and secret", credentialID)
+		}
+		req.Header.Set("Authorization", "PVEAPIToken="+cred.Username+"="+cred.Secret)
+		return nil
+	}
 	return &ProxmoxCollector{
-		baseURL:     baseURL,
-		tokenID:     tokenID,
-		tokenSecret: tokenSecret,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{
-					MinVersion: tls.VersionTLS12,
-					//nolint:gosec // G402: Proxmox commonly uses self-signed certs in homelab environments.
-					InsecureSkipVerify: true,
-				},
-			},
-		},
-		logger: logger,
+		// Proxmox commonly uses self-signed certs in homelab environments.
+		api:     newJSONCollector("proxmox API", baseURL, authorize, true),
+		breaker: newCircuitBreaker(breakerFailureThreshold, breakerCooldown),
+		logger:  logger,
 	}
 }
 
+// SetRetryPolicy sets how many times a failed GET is retried and the
+// initial backoff between attempts, which doubles after each retry.
+func (c *ProxmoxCollector) SetRetryPolicy(maxRetries int, backoff time.Duration) {
+	c.api.maxRetries = maxRetries
+	c.api.retryBackoff = backoff
+}
+
 // CollectNodes returns all nodes in the Proxmox cluster.
 func (c *ProxmoxCollector) CollectNodes(ctx context.Context) ([]ProxmoxNode, error) {
-	body, err := c.apiGet(ctx, "/api2/json/nodes")
+	body, err := c.apiGet(ctx, proxmoxAPIHost, "/api2/json/nodes")
 	if err != nil {
 		return nil, fmt.Errorf("list nodes: %w", err)
 	}
@@ -110,7 +132,7 @@ func (c *ProxmoxCollector) CollectNodes(ctx context.Context) ([]ProxmoxNode, err
 // Returns the mapped DeviceHardware and DeviceStorage slices.
 func (c *ProxmoxCollector) CollectNodeHardware(ctx context.Context, node string) (*models.DeviceHardware, []models.DeviceStorage, error) {
 	// Get node status for CPU/RAM.
-	statusBody, err := c.apiGet(ctx, "/api2/json/nodes/"+node+"/status")
+	statusBody, err := c.apiGet(ctx, node, "/api2/json/nodes/"+node+"/status")
 	if err != nil {
 		return nil, nil, fmt.Errorf("get node status: %w", err)
 	}
@@ -145,7 +167,7 @@ func (c *ProxmoxCollector) CollectNodeHardware(ctx context.Context, node string)
 
 	// Get disk list.
 	var storage []models.DeviceStorage
-	diskBody, err := c.apiGet(ctx, "/api2/json/nodes/"+node+"/disks/list")
+	diskBody, err := c.apiGet(ctx, node, "/api2/json/nodes/"+node+"/disks/list")
 	if err != nil {
 		c.logger.Debug("failed to get proxmox disk list (may require root permissions)",
 			zap.String("node", node),
@@ -175,7 +197,7 @@ func (c *ProxmoxCollector) CollectNodeHardware(ctx context.Context, node string)
 
 // CollectVMs returns all QEMU VMs on a Proxmox node.
 func (c *ProxmoxCollector) CollectVMs(ctx context.Context, node string) ([]ProxmoxVM, error) {
-	body, err := c.apiGet(ctx, "/api2/json/nodes/"+node+"/qemu")
+	body, err := c.apiGet(ctx, node, "/api2/json/nodes/"+node+"/qemu")
 	if err != nil {
 		return nil, fmt.Errorf("list VMs: %w", err)
 	}
@@ -190,7 +212,7 @@ func (c *ProxmoxCollector) CollectVMs(ctx context.Context, node string) ([]Proxm
 
 // CollectContainers returns all LXC containers on a Proxmox node.
 func (c *ProxmoxCollector) CollectContainers(ctx context.Context, node string) ([]ProxmoxContainer, error) {
-	body, err := c.apiGet(ctx, "/api2/json/nodes/"+node+"/lxc")
+	body, err := c.apiGet(ctx, node, "/api2/json/nodes/"+node+"/lxc")
 	if err != nil {
 		return nil, fmt.Errorf("list containers: %w", err)
 	}
@@ -218,18 +240,18 @@ type ProxmoxResourceStatus struct {
 // CollectVMStatus returns live resource utilisation for a QEMU VM.
 func (c *ProxmoxCollector) CollectVMStatus(ctx context.Context, node string, vmid int) (*ProxmoxResourceStatus, error) {
 	path := fmt.Sprintf("/api2/json/nodes/%s/qemu/%d/status/current", node, vmid)
-	return c.collectResourceStatus(ctx, path)
+	return c.collectResourceStatus(ctx, node, path)
 }
 
 // CollectContainerStatus returns live resource utilisation for an LXC container.
 func (c *ProxmoxCollector) CollectContainerStatus(ctx context.Context, node string, vmid int) (*ProxmoxResourceStatus, error) {
 	path := fmt.Sprintf("/api2/json/nodes/%s/lxc/%d/status/current", node, vmid)
-	return c.collectResourceStatus(ctx, path)
+	return c.collectResourceStatus(ctx, node, path)
 }
 
 // collectResourceStatus fetches and maps a Proxmox status/current response.
-func (c *ProxmoxCollector) collectResourceStatus(ctx context.Context, path string) (*ProxmoxResourceStatus, error) {
-	body, err := c.apiGet(ctx, path)
+func (c *ProxmoxCollector) collectResourceStatus(ctx context.Context, node, path string) (*ProxmoxResourceStatus, error) {
+	body, err := c.apiGet(ctx, node, path)
 	if err != nil {
 		return nil, fmt.Errorf("get resource status: %w", err)
 	}
@@ -261,38 +283,29 @@ func (c *ProxmoxCollector) collectResourceStatus(ctx context.Context, path strin
 }
 
 // apiGet performs an authenticated GET request to the Proxmox API and
-// returns the unwrapped "data" field from the response envelope.
-func (c *ProxmoxCollector) apiGet(ctx context.Context, path string) (json.RawMessage, error) {
-	url := c.baseURL + path
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+// returns the unwrapped "data" field from the response envelope. node is the
+// cluster node the path is scoped to, or proxmoxAPIHost; it keys the
+// circuit breaker.
+func (c *ProxmoxCollector) apiGet(ctx context.Context, node, path string) (json.RawMessage, error) {
+	if !c.breaker.allow(node) {
+		if node == proxmoxAPIHost {
+			return nil, fmt.Errorf("%w: %s", ErrProxmoxUnreachable, c.api.baseURL)
+		}
+		return nil, fmt.Errorf("%w: node %s", ErrProxmoxUnreachable, node)
 	}
 
-	// Proxmox API token authentication.
-	req.Header.Set("Authorization", "PVEAPIToken="+c.tokenID+"="+c.tokenSecret)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("http request: %w", err)
+	var envelope proxmoxResponse
+	err := c.api.getJSON(ctx, path, &envelope)
+	// Only failures that say the host could not answer count against it;
+	// a 4xx (bad token, missing permission) means it is reachable.
+	if err == nil || !isTransientError(err) {
+		c.breaker.record(node, false)
+	} else if ctx.Err() == nil {
+		c.breaker.record(node, true)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("read response body: %w", err)
+		return nil, err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("proxmox API returned %d: %s", resp.StatusCode, string(body))
-	}
-
-	var envelope proxmoxResponse
-	if err := json.Unmarshal(body, &envelope); err != nil {
-		return nil, fmt.Errorf("parse response envelope: %w", err)
-	}
-
 	return envelope.Data, nil
 }
 
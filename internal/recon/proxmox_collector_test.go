@@ -3,9 +3,13 @@ package recon
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -85,7 +89,7 @@ func TestProxmoxCollector_CollectNodes(t *testing.T) {
 				}
 			})
 
-			c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+			c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 			nodes, err := c.CollectNodes(context.Background())
 
 			if tt.wantErr {
@@ -203,7 +207,7 @@ func TestProxmoxCollector_CollectNodeHardware(t *testing.T) {
 				}
 			})
 
-			c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+			c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 			hw, storage, err := c.CollectNodeHardware(context.Background(), "testnode")
 
 			if tt.wantErr {
@@ -300,7 +304,7 @@ func TestProxmoxCollector_CollectVMs(t *testing.T) {
 				}
 			})
 
-			c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+			c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 			vms, err := c.CollectVMs(context.Background(), "testnode")
 
 			if tt.wantErr {
@@ -353,7 +357,7 @@ func TestProxmoxCollector_CollectContainers(t *testing.T) {
 		}
 	})
 
-	c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 	containers, err := c.CollectContainers(context.Background(), "testnode")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -380,7 +384,7 @@ func TestProxmoxCollector_AuthHeader(t *testing.T) {
 		}
 	})
 
-	c := NewProxmoxCollector(srv.URL, "admin@pve!monitoring", "aaaabbbb-cccc-dddd-eeee-ffffgggghhh", zap.NewNop())
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "admin@pve!monitoring", Secret: "aaaabbbb-cccc-dddd-eeee-ffffgggghhh"}, zap.NewNop())
 	_, err := c.CollectNodes(context.Background())
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -392,6 +396,192 @@ func TestProxmoxCollector_AuthHeader(t *testing.T) {
 	}
 }
 
+// mockVaultCredential is one stored credential: its type as recorded on the
+// vault record and its decrypted data.
+type mockVaultCredential struct {
+	credType string
+	data     map[string]any
+}
+
+// mockVault implements CredentialDecrypter over a map of credential ID to
+// stored credential and records which IDs were requested.
+type mockVault struct {
+	creds     map[string]mockVaultCredential
+	requested []string
+}
+
+func (v *mockVault) DecryptTypedCredential(_ context.Context, id string) (string, map[string]any, error) {
+	v.requested = append(v.requested, id)
+	cred, ok := v.creds[id]
+	if !ok {
+		return "", nil, fmt.Errorf("credential not found: %s", id)
+	}
+	return cred.credType, cred.data, nil
+}
+
+func TestProxmoxCollector_VaultCredential(t *testing.T) {
+	var capturedAuth []string
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, r *http.Request) {
+		capturedAuth = append(capturedAuth, r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+
+	vault := &mockVault{creds: map[string]mockVaultCredential{
+		"cred-pve": {credType: "http_basic", data: map[string]any{"username": "subnetree@pve!inventory", "password": "vault-secret"}},
+	}}
+	m := &Module{}
+	c := NewProxmoxCollector(srv.URL, "cred-pve", m, zap.NewNop())
+
+	// Before the vault is wired the request fails without reaching the API.
+	if _, err := c.CollectNodes(context.Background()); !errors.Is(err, errNoCredentialResolver) {
+		t.Fatalf("CollectNodes without resolver: err = %v, want errNoCredentialResolver", err)
+	}
+	if len(capturedAuth) != 0 {
+		t.Fatalf("API called %d times without credentials", len(capturedAuth))
+	}
+
+	m.SetCollectorCredentialResolver(NewVaultCredentialAdapter(vault))
+	if _, err := c.CollectNodes(context.Background()); err != nil {
+		t.Fatalf("CollectNodes: %v", err)
+	}
+
+	// A rotated secret is picked up on the next request.
+	vault.creds["cred-pve"].data["password"] = "rotated-secret"
+	if _, err := c.CollectNodes(context.Background()); err != nil {
+		t.Fatalf("CollectNodes after rotation: %v", err)
+	}
+
+	want := []string{
+		"PVEAPIToken=subnetree@pve!inventory=vault-secret",
+		"PVEAPIToken=subnetree@pve!inventory=rotated-secret",
+	}
+	if len(capturedAuth) != len(want) {
+		t.Fatalf("Authorization headers = %q, want %q", capturedAuth, want)
+	}
+	for i := range want {
+		if capturedAuth[i] != want[i] {
+			t.Errorf("Authorization header %d = %q, want %q", i, capturedAuth[i], want[i])
+		}
+	}
+	if len(vault.requested) != 2 || vault.requested[0] != "cred-pve" {
+		t.Errorf("vault lookups = %v, want cred-pve once per request", vault.requested)
+	}
+}
+
+func TestProxmoxCollector_VaultCredentialMissing(t *testing.T) {
+	var calls atomic.Int32
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+
+	vault := &mockVault{creds: map[string]mockVaultCredential{
+		"cred-key": {credType: "api_key", data: map[string]any{"key": "no-token-id"}},
+	}}
+	for _, id := range []string{"cred-missing", "cred-key"} {
+		c := NewProxmoxCollector(srv.URL, id, NewVaultCredentialAdapter(vault), zap.NewNop())
+		if _, err := c.CollectNodes(context.Background()); err == nil {
+			t.Errorf("CollectNodes with %s: expected error, got nil", id)
+		}
+	}
+	if got := calls.Load(); got != 0 {
+		t.Errorf("API called %d times, want 0", got)
+	}
+}
+
+func TestProxmoxCollector_RetriesTransientFailures(t *testing.T) {
+	var attempts atomic.Int32
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		if attempts.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[{"vmid":100,"name":"web","status":"running"}]}`))
+	})
+
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "user@pam!test", Secret: "secret"}, zap.NewNop())
+	c.SetRetryPolicy(2, time.Millisecond)
+
+	vms, err := c.CollectVMs(context.Background(), "pve1")
+	if err != nil {
+		t.Fatalf("CollectVMs: %v", err)
+	}
+	if len(vms) != 1 || vms[0].Name != "web" {
+		t.Errorf("vms = %+v, want the one VM", vms)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+func TestProxmoxCollector_CircuitBreaker(t *testing.T) {
+	var attempts atomic.Int32
+	healthy := atomic.Bool{}
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "user@pam!test", Secret: "secret"}, zap.NewNop())
+	c.SetRetryPolicy(0, time.Millisecond)
+	now := time.Now()
+	c.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := range breakerFailureThreshold {
+		_, err := c.CollectVMs(ctx, "pve1")
+		if err == nil || errors.Is(err, ErrProxmoxUnreachable) {
+			t.Fatalf("call %d: error = %v, want the upstream 503", i+1, err)
+		}
+	}
+
+	// The circuit is open: calls fail fast without reaching the server.
+	_, err := c.CollectVMs(ctx, "pve1")
+	if !errors.Is(err, ErrProxmoxUnreachable) {
+		t.Fatalf("error = %v, want ErrProxmoxUnreachable", err)
+	}
+	if got := attempts.Load(); got != breakerFailureThreshold {
+		t.Errorf("attempts = %d, want %d", got, breakerFailureThreshold)
+	}
+
+	// Other nodes have their own circuit.
+	if _, err := c.CollectVMs(ctx, "pve2"); errors.Is(err, ErrProxmoxUnreachable) {
+		t.Errorf("pve2 error = %v, want it to be tried", err)
+	}
+
+	// After the cooldown one trial call goes through and closes the circuit.
+	healthy.Store(true)
+	now = now.Add(breakerCooldown)
+	if _, err := c.CollectVMs(ctx, "pve1"); err != nil {
+		t.Fatalf("trial call: %v", err)
+	}
+	if _, err := c.CollectVMs(ctx, "pve1"); err != nil {
+		t.Errorf("after recovery: %v", err)
+	}
+}
+
+func TestProxmoxCollector_ClientErrorsDoNotTripBreaker(t *testing.T) {
+	var attempts atomic.Int32
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "user@pam!test", Secret: "secret"}, zap.NewNop())
+	for range breakerFailureThreshold + 1 {
+		if _, err := c.CollectVMs(context.Background(), "pve1"); errors.Is(err, ErrProxmoxUnreachable) {
+			t.Fatalf("error = %v, want the 403 passed through", err)
+		}
+	}
+	if got := attempts.Load(); got != breakerFailureThreshold+1 {
+		t.Errorf("attempts = %d, want %d", got, breakerFailureThreshold+1)
+	}
+}
+
 func TestProxmoxCollector_CollectVMStatus(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -440,7 +630,7 @@ func TestProxmoxCollector_CollectVMStatus(t *testing.T) {
 				}
 			})
 
-			c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+			c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 			status, err := c.CollectVMStatus(context.Background(), "pve1", 100)
 
 			if tt.wantErr {
@@ -500,7 +690,7 @@ func TestProxmoxCollector_CollectContainerStatus(t *testing.T) {
 		}
 	})
 
-	c := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+	c := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 	status, err := c.CollectContainerStatus(context.Background(), "pve1", 200)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -10,10 +10,13 @@ import (
 )
 
 // ProxmoxSyncRequest is the request body for POST /recon/proxmox/sync.
+// The API token is given either as a vault credential_id or inline as
+// token_id and token_secret.
 type ProxmoxSyncRequest struct {
 	BaseURL      string `json:"base_url" example:"https://pve:8006"`
-	TokenID      string `json:"token_id" example:"user@pam!token"`       //nolint:gosec // G101: field name, not a credential
-	TokenSecret  string `json:"token_secret" example:"uuid-secret-here"` //nolint:gosec // G101: field name, not a credential
+	CredentialID string `json:"credential_id,omitempty" example:"cred-proxmox-001"`
+	TokenID      string `json:"token_id,omitempty" example:"user@pam!token"`       //nolint:gosec // G101: field name, not a credential
+	TokenSecret  string `json:"token_secret,omitempty" example:"uuid-secret-here"` //nolint:gosec // G101: field name, not a credential
 	HostDeviceID string `json:"host_device_id" example:"device-uuid"`
 }
 
@@ -36,12 +39,20 @@ func (m *Module) handleProxmoxSync(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid JSON body")
 		return
 	}
-	if req.BaseURL == "" || req.TokenID == "" || req.TokenSecret == "" || req.HostDeviceID == "" {
-		writeError(w, http.StatusBadRequest, "base_url, token_id, token_secret, and host_device_id are required")
+	if req.BaseURL == "" || req.HostDeviceID == "" {
+		writeError(w, http.StatusBadRequest, "base_url and host_device_id are required")
 		return
 	}
+	var creds CollectorCredentialResolver = m
+	if req.CredentialID == "" {
+		if req.TokenID == "" || req.TokenSecret == "" {
+			writeError(w, http.StatusBadRequest, "credential_id or token_id and token_secret are required")
+			return
+		}
+		creds = staticCredential{Username: req.TokenID, Secret: req.TokenSecret}
+	}
 
-	collector := NewProxmoxCollector(req.BaseURL, req.TokenID, req.TokenSecret, m.logger.Named("proxmox"))
+	collector := NewProxmoxCollector(req.BaseURL, req.CredentialID, creds, m.logger.Named("proxmox"))
 	result, err := m.proxmoxSyncer.Sync(r.Context(), collector, req.HostDeviceID)
 	if err != nil {
 		m.logger.Error("proxmox sync failed", zap.Error(err))
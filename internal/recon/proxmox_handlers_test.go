@@ -225,6 +225,26 @@ func TestHandleProxmoxSync_MissingFields(t *testing.T) {
 	}
 }
 
+func TestHandleProxmoxSync_VaultCredential(t *testing.T) {
+	m := newTestModuleWithProxmox(t)
+	m.SetCollectorCredentialResolver(NewVaultCredentialAdapter(&mockVault{creds: map[string]mockVaultCredential{}}))
+
+	// Only a credential reference is sent; the unknown ID fails at lookup
+	// rather than request validation.
+	body := `{"base_url":"https://pve:8006","credential_id":"cred-unknown","host_device_id":"dev-1"}`
+	req := httptest.NewRequest("POST", "/recon/proxmox/sync", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	m.handleProxmoxSync(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d; body: %s", w.Code, http.StatusInternalServerError, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "cred-unknown") {
+		t.Errorf("body = %s, want the credential lookup error", w.Body.String())
+	}
+}
+
 func TestHandleProxmoxSync_InvalidJSON(t *testing.T) {
 	m := newTestModuleWithProxmox(t)
 
@@ -2,6 +2,7 @@ package recon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -29,6 +30,10 @@ type ProxmoxSyncResult struct {
 	LXCsFound    int `json:"lxcs_found"`
 	Created      int `json:"created"`
 	Updated      int `json:"updated"`
+
+	// UnreachableNodes lists nodes skipped because their circuit breaker
+	// is open after repeated failures.
+	UnreachableNodes []string `json:"unreachable_nodes,omitempty"`
 }
 
 // Sync enumerates nodes, VMs and containers from the given collector and
@@ -48,6 +53,11 @@ func (s *ProxmoxSyncer) Sync(ctx context.Context, collector *ProxmoxCollector, h
 	for _, node := range nodes {
 		// Process QEMU VMs.
 		vms, vmErr := collector.CollectVMs(ctx, node.Node)
+		if errors.Is(vmErr, ErrProxmoxUnreachable) {
+			s.logger.Debug("skipping unreachable proxmox node", zap.String("node", node.Node))
+			result.UnreachableNodes = append(result.UnreachableNodes, node.Node)
+			continue
+		}
 		if vmErr != nil {
 			s.logger.Warn("failed to collect VMs for node", zap.String("node", node.Node), zap.Error(vmErr))
 			continue
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/HerbHall/subnetree/pkg/models"
 	"go.uber.org/zap"
@@ -78,7 +79,7 @@ func TestProxmoxSyncer_Sync(t *testing.T) {
 	}))
 	t.Cleanup(srv.Close)
 
-	collector := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+	collector := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 	syncer := NewProxmoxSyncer(s, zap.NewNop())
 
 	result, err := syncer.Sync(ctx, collector, "pve-host-1")
@@ -235,7 +236,7 @@ func TestProxmoxSyncer_Sync_UpdateExisting(t *testing.T) {
 	}))
 	t.Cleanup(srv.Close)
 
-	collector := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+	collector := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 	syncer := NewProxmoxSyncer(s, zap.NewNop())
 
 	result, err := syncer.Sync(ctx, collector, "pve-host-1")
@@ -260,6 +261,71 @@ func TestProxmoxSyncer_Sync_UpdateExisting(t *testing.T) {
 	}
 }
 
+func TestProxmoxSyncer_Sync_UnreachableNode(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	host := &models.Device{
+		ID:              "pve-host-1",
+		Hostname:        "proxmox-host",
+		DeviceType:      models.DeviceTypeServer,
+		Status:          models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	if _, err := s.UpsertDevice(ctx, host); err != nil {
+		t.Fatalf("upsert host: %v", err)
+	}
+
+	// pve2 is listed by the cluster but its own endpoints keep failing.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp any
+		switch r.URL.Path {
+		case "/api2/json/nodes":
+			resp = map[string]any{
+				"data": []map[string]any{
+					{"node": "pve1", "status": "online"},
+					{"node": "pve2", "status": "online"},
+				},
+			}
+		case "/api2/json/nodes/pve1/qemu", "/api2/json/nodes/pve1/lxc":
+			resp = map[string]any{"data": []map[string]any{}}
+		case "/api2/json/nodes/pve2/qemu":
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("encode response: %v", err)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	collector := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
+	collector.SetRetryPolicy(0, time.Millisecond)
+	syncer := NewProxmoxSyncer(s, zap.NewNop())
+
+	// Each sync counts one failure against pve2 until its circuit opens.
+	for i := range breakerFailureThreshold {
+		result, err := syncer.Sync(ctx, collector, "pve-host-1")
+		if err != nil {
+			t.Fatalf("Sync %d: %v", i+1, err)
+		}
+		if len(result.UnreachableNodes) != 0 {
+			t.Fatalf("Sync %d: UnreachableNodes = %v, want none before the breaker opens", i+1, result.UnreachableNodes)
+		}
+	}
+
+	result, err := syncer.Sync(ctx, collector, "pve-host-1")
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if len(result.UnreachableNodes) != 1 || result.UnreachableNodes[0] != "pve2" {
+		t.Errorf("UnreachableNodes = %v, want [pve2]", result.UnreachableNodes)
+	}
+}
+
 func TestProxmoxSyncer_MarkUnseen(t *testing.T) {
 	s := testStore(t)
 	ctx := context.Background()
@@ -333,7 +399,7 @@ func TestProxmoxSyncer_MarkUnseen(t *testing.T) {
 	}))
 	t.Cleanup(srv.Close)
 
-	collector := NewProxmoxCollector(srv.URL, "test@pve!token", "secret", zap.NewNop())
+	collector := NewProxmoxCollector(srv.URL, "", staticCredential{Username: "test@pve!token", Secret: "secret"}, zap.NewNop())
 	syncer := NewProxmoxSyncer(s, zap.NewNop())
 
 	if _, err := syncer.Sync(ctx, collector, "pve-host-1"); err != nil {
@@ -2,12 +2,15 @@ package recon
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"github.com/HerbHall/subnetree/pkg/roles"
 	"go.uber.org/zap"
@@ -15,35 +18,48 @@ import (
 
 // Compile-time interface guards.
 var (
-	_ plugin.Plugin          = (*Module)(nil)
-	_ plugin.HTTPProvider    = (*Module)(nil)
-	_ plugin.HealthChecker   = (*Module)(nil)
-	_ plugin.EventSubscriber = (*Module)(nil)
+	_ plugin.Plugin            = (*Module)(nil)
+	_ plugin.HTTPProvider      = (*Module)(nil)
+	_ plugin.HealthChecker     = (*Module)(nil)
+	_ plugin.ReadinessReporter = (*Module)(nil)
+	_ plugin.EventSubscriber   = (*Module)(nil)
 )
 
 // Module implements the Recon network discovery plugin.
 type Module struct {
-	logger        *zap.Logger
-	cfg           ReconConfig
-	store         *ReconStore
-	bus           plugin.EventBus
-	oui           *OUITable
-	orchestrator  *ScanOrchestrator
-	snmpCollector *SNMPCollector
-	wifiScanner   WifiScanner
-	mdns          *MDNSListener
-	upnp          *UPNPDiscoverer
-	scheduler     *ScanScheduler
-	consolidator  *ScanConsolidator
-	credAccessor   CredentialAccessor
-	credProvider   roles.CredentialProvider
-	profileSource  ProfileSource
+	logger           *zap.Logger
+	cfg              ReconConfig
+	store            *ReconStore
+	devices          services.DeviceRepository
+	bus              plugin.EventBus
+	oui              *OUITable
+	ouiUpdater       *OUIUpdater
+	orchestrator     *ScanOrchestrator
+	snmpCollector    *SNMPCollector
+	wifiScanner      WifiScanner
+	mdns             *MDNSListener
+	upnp             *UPNPDiscoverer
+	scheduler        *ScanScheduler
+	scheduleRunner   *ScanScheduleRunner
+	consolidator     *ScanConsolidator
+	credAccessor     CredentialAccessor
+	credProvider     roles.CredentialProvider
+	collectorCredsMu sync.RWMutex
+	collectorCreds   CollectorCredentialResolver
+	profileSource    ProfileSource
 	wifiAPEnumerator APClientEnumerator
 	proxmoxSyncer    *ProxmoxSyncer
-	activeScans    sync.Map // scanID -> context.CancelFunc
-	wg            sync.WaitGroup
-	scanCtx       context.Context
-	scanCancel    context.CancelFunc
+	collectors       *CollectorScheduler
+	activeScans      sync.Map // scanID -> context.CancelFunc
+	tracerouteOnce   sync.Once
+	traceroutes      *tracerouteLimiter
+	idempotencyOnce  sync.Once
+	idempotency      *idempotencyCache
+	traceroute       func(ctx context.Context, target string, maxHops, hopTimeoutMs, count, intervalMs int, logger *zap.Logger) (*TracerouteResult, error)
+	pathMTU          func(ctx context.Context, target string, maxMTU, timeoutMs int, logger *zap.Logger) (*PathMTUResult, error)
+	wg               sync.WaitGroup
+	scanCtx          context.Context
+	scanCancel       context.CancelFunc
 }
 
 // New creates a new Recon plugin instance.
@@ -92,12 +108,18 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 		if d := deps.Config.GetDuration("mdns_interval"); d > 0 {
 			m.cfg.MDNSInterval = d
 		}
+		if d := deps.Config.GetDuration("mdns_timeout"); d > 0 {
+			m.cfg.MDNSTimeout = d
+		}
 		if deps.Config.IsSet("upnp_enabled") {
 			m.cfg.UPNPEnabled = deps.Config.GetBool("upnp_enabled")
 		}
 		if d := deps.Config.GetDuration("upnp_interval"); d > 0 {
 			m.cfg.UPNPInterval = d
 		}
+		if v := deps.Config.GetInt("max_traceroutes"); v > 0 {
+			m.cfg.MaxTraceroutes = v
+		}
 		if deps.Config.IsSet("schedule.enabled") {
 			m.cfg.Schedule.Enabled = deps.Config.GetBool("schedule.enabled")
 		}
@@ -113,6 +135,26 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 		if v := deps.Config.GetString("schedule.subnet"); v != "" {
 			m.cfg.Schedule.Subnet = v
 		}
+		if deps.Config.IsSet("port_scan") {
+			if err := deps.Config.Sub("port_scan").Unmarshal(&m.cfg.PortScan); err != nil {
+				return fmt.Errorf("unmarshal recon port_scan config: %w", err)
+			}
+		}
+		if deps.Config.IsSet("collectors") {
+			if err := deps.Config.Sub("collectors").Unmarshal(&m.cfg.Collectors); err != nil {
+				return fmt.Errorf("unmarshal recon collectors config: %w", err)
+			}
+		}
+		if deps.Config.IsSet("hostname_lookup") {
+			if err := deps.Config.Sub("hostname_lookup").Unmarshal(&m.cfg.HostnameLookup); err != nil {
+				return fmt.Errorf("unmarshal recon hostname_lookup config: %w", err)
+			}
+		}
+		if deps.Config.IsSet("oui_update") {
+			if err := deps.Config.Sub("oui_update").Unmarshal(&m.cfg.OUIUpdate); err != nil {
+				return fmt.Errorf("unmarshal recon oui_update config: %w", err)
+			}
+		}
 	}
 
 	// Allow disabling discovery via environment for QC/testing containers.
@@ -136,7 +178,12 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 
 	// Initialize store and scanners.
 	m.store = NewReconStore(deps.Store.DB())
+	m.devices = services.NewTracedDeviceRepository(services.NewDeviceRepository(deps.Store))
 	m.oui = NewOUITable()
+	m.ouiUpdater = NewOUIUpdater(m.oui, m.cfg.OUIUpdate, m.logger.Named("oui"))
+	if err := m.ouiUpdater.LoadCache(); err != nil {
+		m.logger.Warn("failed to load cached OUI registry, using built-in table", zap.Error(err))
+	}
 
 	pinger := NewICMPScanner(m.cfg, m.logger.Named("icmp"))
 	var arp ARPTableReader
@@ -145,6 +192,13 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 	}
 
 	m.orchestrator = NewScanOrchestrator(m.store, m.bus, m.oui, pinger, arp, m.logger)
+	m.orchestrator.SetHostnameResolver(NewHostnameResolver(m.cfg.HostnameLookup), m.cfg.HostnameLookup.Timeout)
+	if m.cfg.PortScan.Enabled {
+		m.orchestrator.SetDevicePortScan(
+			NewPortScanner(m.cfg.PortScan.Timeout, m.cfg.PortScan.Concurrency, m.logger.Named("portscan")),
+			m.cfg.PortScan.Ports,
+		)
+	}
 
 	// Initialize WiFi scanner (auto-detects hardware availability).
 	m.wifiScanner = NewWifiScanner(m.logger.Named("wifi"))
@@ -154,9 +208,10 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 	m.wifiAPEnumerator = NewAPClientEnumerator(m.logger.Named("wifi-ap"))
 	m.orchestrator.SetAPClientEnumerator(m.wifiAPEnumerator)
 
-	// Initialize mDNS listener if enabled.
+	// Initialize mDNS listener and per-scan DNS-SD browse if enabled.
 	if m.cfg.MDNSEnabled {
 		m.mdns = NewMDNSListener(m.store, m.bus, m.logger.Named("mdns"), m.cfg.MDNSInterval)
+		m.orchestrator.SetMDNSBrowser(NewMDNSCollector(m.cfg.MDNSTimeout, m.logger.Named("mdns")))
 	}
 
 	// Initialize UPnP discoverer if enabled.
@@ -166,6 +221,46 @@ func (m *Module) Init(ctx context.Context, deps plugin.Dependencies) error {
 
 	m.proxmoxSyncer = NewProxmoxSyncer(m.store, m.logger.Named("proxmox-sync"))
 
+	// Register periodic inventory collectors.
+	m.collectors = NewCollectorScheduler(m.store, m.bus, m.cfg.Collectors.MaxConcurrent, m.logger.Named("collectors"))
+	for i := range m.cfg.Collectors.Proxmox {
+		pc := m.cfg.Collectors.Proxmox[i]
+		if !pc.Enabled {
+			continue
+		}
+		if pc.BaseURL == "" || pc.HostDeviceID == "" || (pc.CredentialID == "" && (pc.TokenID == "" || pc.TokenSecret == "")) {
+			m.logger.Warn("skipping proxmox collector with incomplete config", zap.String("name", pc.Name))
+			continue
+		}
+		name := pc.Name
+		if name == "" {
+			name = "proxmox:" + pc.BaseURL
+		}
+		var creds CollectorCredentialResolver = m
+		if pc.CredentialID == "" {
+			m.logger.Warn("proxmox collector token in config is deprecated; store it in the vault and set credential_id",
+				zap.String("name", name))
+			creds = staticCredential{Username: pc.TokenID, Secret: pc.TokenSecret}
+		}
+		collector := NewProxmoxCollector(pc.BaseURL, pc.CredentialID, creds, m.logger.Named("proxmox"))
+		if pc.MaxRetries > 0 || pc.RetryBackoff > 0 {
+			maxRetries, backoff := pc.MaxRetries, pc.RetryBackoff
+			if maxRetries <= 0 {
+				maxRetries = jsonCollectorMaxRetries
+			}
+			if backoff <= 0 {
+				backoff = jsonCollectorRetryBackoff
+			}
+			collector.SetRetryPolicy(maxRetries, backoff)
+		}
+		m.collectors.Register(&proxmoxPolledCollector{
+			name:         name,
+			collector:    collector,
+			syncer:       m.proxmoxSyncer,
+			hostDeviceID: pc.HostDeviceID,
+		}, pc.Interval)
+	}
+
 	m.logger.Info("recon module initialized")
 	return nil
 }
@@ -175,7 +270,7 @@ func (m *Module) Start(_ context.Context) error {
 
 	// Initialize SNMP collector and wire it into the scan orchestrator
 	// for FDB table walks during post-scan processing.
-	m.snmpCollector = NewSNMPCollector(m.logger.Named("snmp"))
+	m.snmpCollector = NewSNMPCollector("", "", m.logger.Named("snmp"))
 	m.orchestrator.SetSNMPWalker(m.snmpCollector)
 	m.orchestrator.SetCredentialLookup(m)
 
@@ -229,6 +324,39 @@ func (m *Module) Start(_ context.Context) error {
 		)
 	}
 
+	// Start the runner for API-managed scan schedules.
+	m.scheduleRunner = NewScanScheduleRunner(
+		m.store,
+		m.orchestrator,
+		&m.activeScans,
+		&m.wg,
+		m.newScanContext,
+		m.logger.Named("schedules"),
+	)
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.scheduleRunner.Run(m.scanCtx)
+	}()
+
+	// Start periodic inventory collectors if any are configured.
+	if m.collectors != nil && m.collectors.Len() > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.collectors.Run(m.scanCtx)
+		}()
+	}
+
+	// Start scheduled OUI registry refresh if enabled.
+	if m.cfg.OUIUpdate.Enabled && m.cfg.OUIUpdate.Interval > 0 {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.ouiUpdater.Run(m.scanCtx, m.cfg.OUIUpdate.Interval)
+		}()
+	}
+
 	// Start scan metrics consolidator background goroutine.
 	m.consolidator = NewScanConsolidator(m.store, m.logger.Named("consolidation"))
 	m.wg.Add(1)
@@ -256,6 +384,24 @@ func (m *Module) SetCredentialAccessor(ca CredentialAccessor) {
 	}
 }
 
+// SetCollectorCredentialResolver sets where authenticated inventory
+// collectors look up their API credentials.
+// Called from the composition root after all plugins are initialized.
+func (m *Module) SetCollectorCredentialResolver(r CollectorCredentialResolver) {
+	m.collectorCredsMu.Lock()
+	m.collectorCreds = r
+	m.collectorCredsMu.Unlock()
+}
+
+// SetScanInterfaceSource sets where the selected scan interface is read
+// from for interface-bound discovery such as mDNS.
+// Called from the composition root after all plugins are initialized.
+func (m *Module) SetScanInterfaceSource(src ScanInterfaceSource) {
+	if m.orchestrator != nil {
+		m.orchestrator.SetScanInterfaceSource(src)
+	}
+}
+
 // SetProfileSource sets the hardware profile source for bridging dispatch -> recon.
 // Called from the composition root after all plugins are initialized.
 func (m *Module) SetProfileSource(ps ProfileSource) {
@@ -310,10 +456,21 @@ func (m *Module) Subscriptions() []plugin.Subscription {
 // Routes implements plugin.HTTPProvider.
 func (m *Module) Routes() []plugin.Route {
 	return []plugin.Route{
-		{Method: "POST", Path: "/scan", Handler: m.handleScan},
+		{Method: "POST", Path: "/scan", Handler: m.idempotent("scan", m.handleScan)},
+		{Method: "POST", Path: "/oui/update", Handler: m.handleOUIUpdate},
 		{Method: "GET", Path: "/scans", Handler: m.handleListScans},
 		{Method: "GET", Path: "/scans/{id}", Handler: m.handleGetScan},
 		{Method: "GET", Path: "/scans/{id}/metrics", Handler: m.handleGetScanMetrics},
+		{Method: "POST", Path: "/scans/{id}/cancel", Handler: m.handleCancelScan},
+		{Method: "GET", Path: "/scans/{id}/diff", Handler: m.handleScanDiff},
+		{Method: "GET", Path: "/schedules", Handler: m.handleListScanSchedules},
+		{Method: "POST", Path: "/schedules", Handler: m.handleCreateScanSchedule},
+		{Method: "GET", Path: "/schedules/{id}", Handler: m.handleGetScanSchedule},
+		{Method: "PUT", Path: "/schedules/{id}", Handler: m.handleUpdateScanSchedule},
+		{Method: "DELETE", Path: "/schedules/{id}", Handler: m.handleDeleteScanSchedule},
+		{Method: "GET", Path: "/exclusions", Handler: m.handleListScanExclusions},
+		{Method: "POST", Path: "/exclusions", Handler: m.handleCreateScanExclusion},
+		{Method: "DELETE", Path: "/exclusions/{id}", Handler: m.handleDeleteScanExclusion},
 		{Method: "GET", Path: "/topology", Handler: m.handleTopology},
 		{Method: "GET", Path: "/hierarchy", Handler: m.handleGetHierarchy},
 		{Method: "GET", Path: "/topology/layouts", Handler: m.handleListTopologyLayouts},
@@ -322,16 +479,25 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "DELETE", Path: "/topology/layouts/{id}", Handler: m.handleDeleteTopologyLayout},
 		{Method: "GET", Path: "/devices", Handler: m.handleListDevices},
 		{Method: "POST", Path: "/devices", Handler: m.handleCreateDevice},
-		{Method: "GET", Path: "/devices/export", Handler: m.handleExportCSV},
+		{Method: "GET", Path: "/devices/export", Handler: m.handleExportDevices, Class: plugin.RouteStream},
 		{Method: "GET", Path: "/devices/ansible", Handler: m.handleExportAnsible},
-		{Method: "POST", Path: "/devices/import", Handler: m.handleImportCSV},
+		{Method: "POST", Path: "/devices/import", Handler: m.idempotent("devices-import", m.handleImportDevices), Class: plugin.RouteUpload},
+		{Method: "POST", Path: "/devices/merge", Handler: m.handleMergeDevices},
+		{Method: "GET", Path: "/devices/views", Handler: m.handleListDeviceViews},
+		{Method: "POST", Path: "/devices/views", Handler: m.handleCreateDeviceView},
+		{Method: "DELETE", Path: "/devices/views/{id}", Handler: m.handleDeleteDeviceView},
+		{Method: "GET", Path: "/devices/views/{id}/results", Handler: m.handleDeviceViewResults},
 		{Method: "GET", Path: "/devices/{id}", Handler: m.handleGetDevice},
 		{Method: "PUT", Path: "/devices/{id}", Handler: m.handleUpdateDevice},
 		{Method: "DELETE", Path: "/devices/{id}", Handler: m.handleDeleteDevice},
+		{Method: "POST", Path: "/devices/{id}/restore", Handler: m.handleRestoreDevice},
 		{Method: "GET", Path: "/devices/{id}/history", Handler: m.handleDeviceHistory},
 		{Method: "GET", Path: "/devices/{id}/scans", Handler: m.handleDeviceScans},
+		{Method: "GET", Path: "/devices/{id}/ports", Handler: m.handleDevicePorts},
 		{Method: "GET", Path: "/inventory/summary", Handler: m.handleInventorySummary},
 		{Method: "PATCH", Path: "/devices/bulk", Handler: m.handleBulkUpdateDevices},
+		{Method: "POST", Path: "/devices/bulk/tags/add", Handler: m.handleBulkAddTags},
+		{Method: "POST", Path: "/devices/bulk/tags/remove", Handler: m.handleBulkRemoveTags},
 		{Method: "GET", Path: "/metrics/health-score", Handler: m.handleHealthScore},
 		{Method: "GET", Path: "/metrics/aggregates", Handler: m.handleListMetricsAggregates},
 		{Method: "GET", Path: "/metrics/raw", Handler: m.handleListRawMetrics},
@@ -339,8 +505,9 @@ func (m *Module) Routes() []plugin.Route {
 		{Method: "POST", Path: "/snmp/discover", Handler: m.handleSNMPDiscover},
 		{Method: "GET", Path: "/snmp/system/{device_id}", Handler: m.handleSNMPSystemInfo},
 		{Method: "GET", Path: "/snmp/interfaces/{device_id}", Handler: m.handleSNMPInterfaces},
-		{Method: "POST", Path: "/traceroute", Handler: m.handleTraceroute},
-		{Method: "POST", Path: "/diag/ping", Handler: m.handleDiagPing},
+		{Method: "POST", Path: "/traceroute", Handler: m.handleTraceroute, Class: plugin.RouteLongRunning},
+		{Method: "POST", Path: "/pmtu", Handler: m.handlePathMTU, Class: plugin.RouteLongRunning},
+		{Method: "POST", Path: "/diag/ping", Handler: m.handleDiagPing, Class: plugin.RouteLongRunning},
 		{Method: "POST", Path: "/diag/dns", Handler: m.handleDiagDNS},
 		{Method: "POST", Path: "/diag/port-check", Handler: m.handleDiagPortCheck},
 		{Method: "GET", Path: "/devices/{id}/hardware", Handler: m.handleGetDeviceHardware},
@@ -379,6 +546,18 @@ func (m *Module) Health(_ context.Context) plugin.HealthStatus {
 	}
 }
 
+// Readiness reports whether recon can accept scans: the scan orchestrator
+// must be initialized and the module started and not yet stopped.
+func (m *Module) Readiness() error {
+	if m.orchestrator == nil {
+		return errors.New("scanner not initialized")
+	}
+	if m.scanCtx == nil || m.scanCtx.Err() != nil {
+		return errors.New("scanner not started")
+	}
+	return nil
+}
+
 // runDeviceLostChecker periodically checks for devices that haven't been seen
 // within the configured DeviceLostAfter threshold and marks them offline.
 func (m *Module) runDeviceLostChecker() {
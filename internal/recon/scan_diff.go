@@ -0,0 +1,226 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// ScanDeviceSnapshot is a device as recorded by a particular scan.
+type ScanDeviceSnapshot struct {
+	DeviceID    string   `json:"device_id"`
+	Hostname    string   `json:"hostname,omitempty"`
+	IPAddresses []string `json:"ip_addresses"`
+	MACAddress  string   `json:"mac_address,omitempty"`
+}
+
+// ScanDeviceChange describes a device seen by both scans whose key fields
+// differ. Fields lists the changed JSON field names.
+type ScanDeviceChange struct {
+	DeviceID string             `json:"device_id"`
+	Fields   []string           `json:"fields"`
+	Before   ScanDeviceSnapshot `json:"before"`
+	After    ScanDeviceSnapshot `json:"after"`
+}
+
+// ScanDiff is the difference between two scans. Added devices were seen
+// by ScanID but not AgainstScanID; Removed devices the reverse.
+type ScanDiff struct {
+	ScanID        string               `json:"scan_id"`
+	AgainstScanID string               `json:"against_scan_id"`
+	Added         []ScanDeviceSnapshot `json:"added"`
+	Removed       []ScanDeviceSnapshot `json:"removed"`
+	Changed       []ScanDeviceChange   `json:"changed"`
+	Unchanged     int                  `json:"unchanged"`
+}
+
+// ListScanDeviceSnapshots returns the devices linked to a scan with the
+// values recorded when each was linked.
+func (s *ReconStore) ListScanDeviceSnapshots(ctx context.Context, scanID string) ([]ScanDeviceSnapshot, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT device_id, hostname, ip_addresses, mac_address
+		FROM recon_scan_devices WHERE scan_id = ?
+		ORDER BY device_id`, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("list scan device snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snaps []ScanDeviceSnapshot
+	for rows.Next() {
+		var snap ScanDeviceSnapshot
+		var ipsJSON string
+		if err := rows.Scan(&snap.DeviceID, &snap.Hostname, &ipsJSON, &snap.MACAddress); err != nil {
+			return nil, fmt.Errorf("scan device snapshot row: %w", err)
+		}
+		if err := json.Unmarshal([]byte(ipsJSON), &snap.IPAddresses); err != nil || snap.IPAddresses == nil {
+			snap.IPAddresses = []string{}
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, rows.Err()
+}
+
+// GetPreviousCompletedScan returns the most recent completed scan of the
+// same subnet that started before scanID, or nil if there is none.
+func (s *ReconStore) GetPreviousCompletedScan(ctx context.Context, scanID string) (*models.ScanResult, error) {
+	var prevID string
+	err := s.db.QueryRowContext(ctx, `
+		SELECT p.id FROM recon_scans p
+		JOIN recon_scans c ON c.id = ?
+		WHERE p.subnet = c.subnet AND p.status = 'completed'
+			AND p.id != c.id AND p.started_at < c.started_at
+		ORDER BY p.started_at DESC, p.rowid DESC
+		LIMIT 1`, scanID,
+	).Scan(&prevID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get previous scan: %w", err)
+	}
+	return s.GetScan(ctx, prevID)
+}
+
+// DiffScans compares the devices seen by scanID against those seen by
+// againstID.
+func (s *ReconStore) DiffScans(ctx context.Context, scanID, againstID string) (*ScanDiff, error) {
+	after, err := s.ListScanDeviceSnapshots(ctx, scanID)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.ListScanDeviceSnapshots(ctx, againstID)
+	if err != nil {
+		return nil, err
+	}
+	diff := diffScanDevices(before, after)
+	diff.ScanID = scanID
+	diff.AgainstScanID = againstID
+	return diff, nil
+}
+
+// diffScanDevices buckets devices into added, removed, and changed by
+// device ID. IP addresses are compared as sets.
+func diffScanDevices(before, after []ScanDeviceSnapshot) *ScanDiff {
+	diff := &ScanDiff{
+		Added:   []ScanDeviceSnapshot{},
+		Removed: []ScanDeviceSnapshot{},
+		Changed: []ScanDeviceChange{},
+	}
+
+	prev := make(map[string]ScanDeviceSnapshot, len(before))
+	for _, d := range before {
+		prev[d.DeviceID] = d
+	}
+
+	for _, cur := range after {
+		old, ok := prev[cur.DeviceID]
+		if !ok {
+			diff.Added = append(diff.Added, cur)
+			continue
+		}
+		delete(prev, cur.DeviceID)
+
+		var fields []string
+		if !sameIPSet(old.IPAddresses, cur.IPAddresses) {
+			fields = append(fields, "ip_addresses")
+		}
+		if old.MACAddress != cur.MACAddress {
+			fields = append(fields, "mac_address")
+		}
+		if old.Hostname != cur.Hostname {
+			fields = append(fields, "hostname")
+		}
+		if len(fields) == 0 {
+			diff.Unchanged++
+			continue
+		}
+		diff.Changed = append(diff.Changed, ScanDeviceChange{
+			DeviceID: cur.DeviceID,
+			Fields:   fields,
+			Before:   old,
+			After:    cur,
+		})
+	}
+
+	for _, d := range before {
+		if _, ok := prev[d.DeviceID]; ok {
+			diff.Removed = append(diff.Removed, d)
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].DeviceID < diff.Added[j].DeviceID })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].DeviceID < diff.Removed[j].DeviceID })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].DeviceID < diff.Changed[j].DeviceID })
+	return diff
+}
+
+// sameIPSet reports whether a and b hold the same addresses in any order.
+func sameIPSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	as := slices.Clone(a)
+	bs := slices.Clone(b)
+	slices.Sort(as)
+	slices.Sort(bs)
+	return slices.Equal(as, bs)
+}
+
+// handleScanDiff returns what changed between two scans.
+//
+//	@Summary		Diff two scans
+//	@Description	Compares the devices seen by a scan against another scan and returns added, removed, and changed devices. Changes cover IP addresses, MAC address, and hostname. If against is omitted, the scan is compared with the previous completed scan of the same subnet.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string	true	"Scan ID"
+//	@Param			against	query		string	false	"Scan ID to compare against"
+//	@Success		200		{object}	ScanDiff
+//	@Failure		404		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/scans/{id}/diff [get]
+func (m *Module) handleScanDiff(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := m.store.GetScan(r.Context(), id); err != nil {
+		writeError(w, http.StatusNotFound, "scan not found")
+		return
+	}
+
+	againstID := r.URL.Query().Get("against")
+	if againstID == "" {
+		prev, err := m.store.GetPreviousCompletedScan(r.Context(), id)
+		if err != nil {
+			m.logger.Error("failed to find previous scan", zap.String("scan_id", id), zap.Error(err))
+			writeError(w, http.StatusInternalServerError, "failed to find previous scan")
+			return
+		}
+		if prev == nil {
+			writeError(w, http.StatusNotFound, "no earlier completed scan of this subnet to compare against")
+			return
+		}
+		againstID = prev.ID
+	} else if _, err := m.store.GetScan(r.Context(), againstID); err != nil {
+		writeError(w, http.StatusNotFound, "comparison scan not found")
+		return
+	}
+
+	diff, err := m.store.DiffScans(r.Context(), id, againstID)
+	if err != nil {
+		m.logger.Error("failed to diff scans",
+			zap.String("scan_id", id),
+			zap.String("against", againstID),
+			zap.Error(err),
+		)
+		writeError(w, http.StatusInternalServerError, "failed to diff scans")
+		return
+	}
+	writeJSON(w, http.StatusOK, diff)
+}
@@ -0,0 +1,230 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// seedDiffScan creates a scan with the given status and start time.
+func seedDiffScan(t *testing.T, s *ReconStore, subnet, startedAt, status string) *models.ScanResult {
+	t.Helper()
+	ctx := context.Background()
+	scan := &models.ScanResult{Subnet: subnet, StartedAt: startedAt}
+	if err := s.CreateScan(ctx, scan); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+	scan.Status = status
+	if err := s.UpdateScan(ctx, scan); err != nil {
+		t.Fatalf("UpdateScan: %v", err)
+	}
+	return scan
+}
+
+// seedDiffDevice creates a device and returns its ID.
+func seedDiffDevice(t *testing.T, s *ReconStore, hostname, ip, mac string) string {
+	t.Helper()
+	dev := &models.Device{
+		Hostname:        hostname,
+		IPAddresses:     []string{ip},
+		MACAddress:      mac,
+		Status:          models.DeviceStatusOnline,
+		DiscoveryMethod: models.DiscoveryICMP,
+	}
+	if _, err := s.UpsertDevice(context.Background(), dev); err != nil {
+		t.Fatalf("UpsertDevice: %v", err)
+	}
+	return dev.ID
+}
+
+func linkDiffDevices(t *testing.T, s *ReconStore, scanID string, deviceIDs ...string) {
+	t.Helper()
+	for _, id := range deviceIDs {
+		if err := s.LinkScanDevice(context.Background(), scanID, id); err != nil {
+			t.Fatalf("LinkScanDevice: %v", err)
+		}
+	}
+}
+
+// seedOverlappingScans creates two completed scans of 10.0.0.0/24:
+//   - "steady" is in both scans unchanged
+//   - "gone" is only in the first scan
+//   - "moved" changes IP between scans
+//   - "nic" changes MAC between scans
+//   - "new" is only in the second scan
+func seedOverlappingScans(t *testing.T, s *ReconStore) (first, second *models.ScanResult, ids map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+
+	ids = map[string]string{
+		"steady": seedDiffDevice(t, s, "steady", "10.0.0.1", "AA:00:00:00:00:01"),
+		"gone":   seedDiffDevice(t, s, "gone", "10.0.0.2", "AA:00:00:00:00:02"),
+		"moved":  seedDiffDevice(t, s, "moved", "10.0.0.3", "AA:00:00:00:00:03"),
+		"nic":    seedDiffDevice(t, s, "nic", "10.0.0.4", "AA:00:00:00:00:04"),
+	}
+
+	first = seedDiffScan(t, s, "10.0.0.0/24", "2026-03-01T10:00:00Z", "completed")
+	linkDiffDevices(t, s, first.ID, ids["steady"], ids["gone"], ids["moved"], ids["nic"])
+
+	// Change device records between scans.
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE recon_devices SET ip_addresses = '["10.0.0.33"]' WHERE id = ?`, ids["moved"]); err != nil {
+		t.Fatalf("update ip: %v", err)
+	}
+	if _, err := s.db.ExecContext(ctx,
+		`UPDATE recon_devices SET mac_address = 'BB:00:00:00:00:04' WHERE id = ?`, ids["nic"]); err != nil {
+		t.Fatalf("update mac: %v", err)
+	}
+	ids["new"] = seedDiffDevice(t, s, "new", "10.0.0.5", "AA:00:00:00:00:05")
+
+	second = seedDiffScan(t, s, "10.0.0.0/24", "2026-03-01T16:00:00Z", "completed")
+	linkDiffDevices(t, s, second.ID, ids["steady"], ids["moved"], ids["nic"], ids["new"])
+	return first, second, ids
+}
+
+func snapshotIDs(snaps []ScanDeviceSnapshot) []string {
+	out := make([]string, 0, len(snaps))
+	for _, s := range snaps {
+		out = append(out, s.DeviceID)
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestDiffScans_Buckets(t *testing.T) {
+	s := testStore(t)
+	first, second, ids := seedOverlappingScans(t, s)
+
+	diff, err := s.DiffScans(context.Background(), second.ID, first.ID)
+	if err != nil {
+		t.Fatalf("DiffScans: %v", err)
+	}
+
+	if got := snapshotIDs(diff.Added); !slices.Equal(got, []string{ids["new"]}) {
+		t.Errorf("added = %v, want [%s]", got, ids["new"])
+	}
+	if got := snapshotIDs(diff.Removed); !slices.Equal(got, []string{ids["gone"]}) {
+		t.Errorf("removed = %v, want [%s]", got, ids["gone"])
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("unchanged = %d, want 1", diff.Unchanged)
+	}
+
+	wantChanged := map[string]struct {
+		fields []string
+		before string
+		after  string
+	}{
+		ids["moved"]: {[]string{"ip_addresses"}, "10.0.0.3", "10.0.0.33"},
+		ids["nic"]:   {[]string{"mac_address"}, "AA:00:00:00:00:04", "BB:00:00:00:00:04"},
+	}
+	if len(diff.Changed) != len(wantChanged) {
+		t.Fatalf("changed = %+v, want %d entries", diff.Changed, len(wantChanged))
+	}
+	for _, c := range diff.Changed {
+		want, ok := wantChanged[c.DeviceID]
+		if !ok {
+			t.Errorf("unexpected changed device %s", c.DeviceID)
+			continue
+		}
+		if !slices.Equal(c.Fields, want.fields) {
+			t.Errorf("device %s fields = %v, want %v", c.DeviceID, c.Fields, want.fields)
+		}
+		var before, after string
+		if want.fields[0] == "ip_addresses" {
+			before, after = c.Before.IPAddresses[0], c.After.IPAddresses[0]
+		} else {
+			before, after = c.Before.MACAddress, c.After.MACAddress
+		}
+		if before != want.before || after != want.after {
+			t.Errorf("device %s = %s -> %s, want %s -> %s", c.DeviceID, before, after, want.before, want.after)
+		}
+	}
+}
+
+func TestDiffScanDevices_IPOrderIgnored(t *testing.T) {
+	before := []ScanDeviceSnapshot{{DeviceID: "d1", IPAddresses: []string{"10.0.0.1", "10.0.0.2"}}}
+	after := []ScanDeviceSnapshot{{DeviceID: "d1", IPAddresses: []string{"10.0.0.2", "10.0.0.1"}}}
+
+	diff := diffScanDevices(before, after)
+	if len(diff.Changed) != 0 || diff.Unchanged != 1 {
+		t.Errorf("diff = %+v, want device unchanged when only IP order differs", diff)
+	}
+}
+
+func TestHandleScanDiff(t *testing.T) {
+	m := newTestModule(t)
+	first, second, ids := seedOverlappingScans(t, m.store)
+
+	// A later failed scan and a scan of another subnet must not be picked as
+	// the default comparison.
+	seedDiffScan(t, m.store, "10.0.0.0/24", "2026-03-01T12:00:00Z", "failed")
+	seedDiffScan(t, m.store, "10.0.1.0/24", "2026-03-01T13:00:00Z", "completed")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /scans/{id}/diff", m.handleScanDiff)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, path, nil))
+		return rr
+	}
+
+	t.Run("defaults to previous completed scan", func(t *testing.T) {
+		rr := get("/scans/" + second.ID + "/diff")
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d; body: %s", rr.Code, http.StatusOK, rr.Body.String())
+		}
+		var diff ScanDiff
+		if err := json.NewDecoder(rr.Body).Decode(&diff); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if diff.AgainstScanID != first.ID {
+			t.Errorf("against = %s, want %s", diff.AgainstScanID, first.ID)
+		}
+		if len(diff.Added) != 1 || diff.Added[0].DeviceID != ids["new"] {
+			t.Errorf("added = %+v, want device %s", diff.Added, ids["new"])
+		}
+		if len(diff.Removed) != 1 || len(diff.Changed) != 2 {
+			t.Errorf("removed/changed = %d/%d, want 1/2", len(diff.Removed), len(diff.Changed))
+		}
+	})
+
+	t.Run("explicit against reverses buckets", func(t *testing.T) {
+		rr := get("/scans/" + first.ID + "/diff?against=" + second.ID)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		var diff ScanDiff
+		if err := json.NewDecoder(rr.Body).Decode(&diff); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(diff.Added) != 1 || diff.Added[0].DeviceID != ids["gone"] {
+			t.Errorf("added = %+v, want device %s", diff.Added, ids["gone"])
+		}
+		if len(diff.Removed) != 1 || diff.Removed[0].DeviceID != ids["new"] {
+			t.Errorf("removed = %+v, want device %s", diff.Removed, ids["new"])
+		}
+	})
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"no earlier scan", "/scans/" + first.ID + "/diff"},
+		{"unknown scan", "/scans/missing/diff"},
+		{"unknown against", "/scans/" + second.ID + "/diff?against=missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if rr := get(tt.path); rr.Code != http.StatusNotFound {
+				t.Errorf("status = %d, want %d", rr.Code, http.StatusNotFound)
+			}
+		})
+	}
+}
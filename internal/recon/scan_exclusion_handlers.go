@@ -0,0 +1,105 @@
+package recon
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// CreateScanExclusionRequest is the request body for POST /exclusions.
+type CreateScanExclusionRequest struct {
+	CIDR        string `json:"cidr" example:"192.168.1.10"`
+	Description string `json:"description,omitempty" example:"Printer that crashes on ping sweeps"`
+}
+
+// handleCreateScanExclusion adds an address or range to the exclusion list.
+//
+//	@Summary		Create scan exclusion
+//	@Description	Adds an IP address or CIDR that all scans skip. Excluded hosts are never probed and are not marked offline. A single IP is stored as a /32 (or /128 for IPv6).
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		CreateScanExclusionRequest	true	"Exclusion to create"
+//	@Success		201		{object}	ScanExclusion
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		409		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/exclusions [post]
+func (m *Module) handleCreateScanExclusion(w http.ResponseWriter, r *http.Request) {
+	var req CreateScanExclusionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.CIDR == "" {
+		writeError(w, http.StatusBadRequest, "cidr is required")
+		return
+	}
+	if _, err := normalizeExclusion(req.CIDR); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	excl := &ScanExclusion{CIDR: req.CIDR, Description: req.Description}
+	if err := m.store.CreateScanExclusion(r.Context(), excl); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			writeError(w, http.StatusConflict, err.Error())
+			return
+		}
+		m.logger.Error("failed to create scan exclusion", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to create scan exclusion")
+		return
+	}
+	writeJSON(w, http.StatusCreated, excl)
+}
+
+// handleListScanExclusions returns the exclusion list.
+//
+//	@Summary		List scan exclusions
+//	@Description	Returns the IP addresses and CIDRs that scans skip.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		ScanExclusion
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/exclusions [get]
+func (m *Module) handleListScanExclusions(w http.ResponseWriter, r *http.Request) {
+	exclusions, err := m.store.ListScanExclusions(r.Context())
+	if err != nil {
+		m.logger.Error("failed to list scan exclusions", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list scan exclusions")
+		return
+	}
+	if exclusions == nil {
+		exclusions = []ScanExclusion{}
+	}
+	writeJSON(w, http.StatusOK, exclusions)
+}
+
+// handleDeleteScanExclusion removes an entry from the exclusion list.
+//
+//	@Summary		Delete scan exclusion
+//	@Description	Removes an exclusion. Later scans probe the address range again.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Exclusion ID"
+//	@Success		204	"No content"
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/exclusions/{id} [delete]
+func (m *Module) handleDeleteScanExclusion(w http.ResponseWriter, r *http.Request) {
+	if err := m.store.DeleteScanExclusion(r.Context(), r.PathValue("id")); err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, "scan exclusion not found")
+			return
+		}
+		m.logger.Error("failed to delete scan exclusion", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to delete scan exclusion")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,106 @@
+package recon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanExclusion is an address range that scans never probe. CIDR is always
+// stored in canonical form; a single IP is stored as a /32 or /128.
+type ScanExclusion struct {
+	ID          string    `json:"id"`
+	CIDR        string    `json:"cidr" example:"192.168.1.10/32"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// normalizeExclusion validates an exclusion entry and returns it as a
+// canonical CIDR. Entries may be a CIDR or a single IP address.
+func normalizeExclusion(entry string) (string, error) {
+	entry = strings.TrimSpace(entry)
+	if _, ipNet, err := net.ParseCIDR(entry); err == nil {
+		return ipNet.String(), nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return "", fmt.Errorf("invalid exclusion %q: must be an IP address or CIDR", entry)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return v4.String() + "/32", nil
+	}
+	return ip.String() + "/128", nil
+}
+
+// CreateScanExclusion validates and inserts a scan exclusion.
+func (s *ReconStore) CreateScanExclusion(ctx context.Context, excl *ScanExclusion) error {
+	cidr, err := normalizeExclusion(excl.CIDR)
+	if err != nil {
+		return err
+	}
+	excl.CIDR = cidr
+	if excl.ID == "" {
+		excl.ID = uuid.New().String()
+	}
+	excl.CreatedAt = time.Now().UTC()
+
+	var exists int
+	if err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM recon_scan_exclusions WHERE cidr = ?`, excl.CIDR,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("check scan exclusion: %w", err)
+	}
+	if exists > 0 {
+		return fmt.Errorf("scan exclusion already exists: %s", excl.CIDR)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO recon_scan_exclusions (id, cidr, description, created_at)
+		VALUES (?, ?, ?, ?)`,
+		excl.ID, excl.CIDR, excl.Description, excl.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create scan exclusion: %w", err)
+	}
+	return nil
+}
+
+// ListScanExclusions returns all scan exclusions ordered by creation time.
+func (s *ReconStore) ListScanExclusions(ctx context.Context) ([]ScanExclusion, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, cidr, description, created_at
+		FROM recon_scan_exclusions ORDER BY created_at ASC, rowid ASC`)
+	if err != nil {
+		return nil, fmt.Errorf("list scan exclusions: %w", err)
+	}
+	defer rows.Close()
+
+	var exclusions []ScanExclusion
+	for rows.Next() {
+		var excl ScanExclusion
+		if err := rows.Scan(&excl.ID, &excl.CIDR, &excl.Description, &excl.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan scan exclusion row: %w", err)
+		}
+		exclusions = append(exclusions, excl)
+	}
+	return exclusions, rows.Err()
+}
+
+// DeleteScanExclusion removes a scan exclusion by ID.
+func (s *ReconStore) DeleteScanExclusion(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM recon_scan_exclusions WHERE id = ?`, id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete scan exclusion: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("scan exclusion not found: %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package recon
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// recordingPinger reports every host it probes as alive and records which
+// hosts it was asked to probe.
+type recordingPinger struct {
+	mu     sync.Mutex
+	probed []string
+}
+
+func (p *recordingPinger) Scan(ctx context.Context, subnet *net.IPNet, results chan<- HostResult) error {
+	return p.ScanExcluding(ctx, subnet, nil, results)
+}
+
+func (p *recordingPinger) ScanExcluding(ctx context.Context, subnet *net.IPNet, skip func(ip string) bool, results chan<- HostResult) error {
+	for _, ip := range expandSubnet(subnet) {
+		if skip != nil && skip(ip) {
+			continue
+		}
+		p.mu.Lock()
+		p.probed = append(p.probed, ip)
+		p.mu.Unlock()
+		select {
+		case results <- HostResult{IP: ip, Alive: true, RTT: time.Millisecond, Method: "icmp"}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func TestNormalizeExclusion(t *testing.T) {
+	tests := []struct {
+		entry   string
+		want    string
+		wantErr bool
+	}{
+		{"192.168.1.10", "192.168.1.10/32", false},
+		{" 192.168.1.10 ", "192.168.1.10/32", false},
+		{"192.168.1.77/28", "192.168.1.64/28", false},
+		{"fd00::1", "fd00::1/128", false},
+		{"fd00::/64", "fd00::/64", false},
+		{"192.168.1.300", "", true},
+		{"printer", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.entry, func(t *testing.T) {
+			got, err := normalizeExclusion(tt.entry)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("normalizeExclusion(%q) = %q, want error", tt.entry, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("normalizeExclusion(%q) = %q, %v; want %q", tt.entry, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanExclusionStore(t *testing.T) {
+	s := testStore(t)
+	ctx := context.Background()
+
+	excl := &ScanExclusion{CIDR: "10.0.0.5", Description: "flaky printer"}
+	if err := s.CreateScanExclusion(ctx, excl); err != nil {
+		t.Fatalf("CreateScanExclusion: %v", err)
+	}
+	if excl.ID == "" || excl.CIDR != "10.0.0.5/32" {
+		t.Fatalf("created = %+v, want ID and normalized CIDR", excl)
+	}
+
+	if err := s.CreateScanExclusion(ctx, &ScanExclusion{CIDR: "10.0.0.5/32"}); err == nil ||
+		!strings.Contains(err.Error(), "already exists") {
+		t.Errorf("duplicate create error = %v, want already exists", err)
+	}
+	if err := s.CreateScanExclusion(ctx, &ScanExclusion{CIDR: "not-an-ip"}); err == nil {
+		t.Error("invalid entry was accepted")
+	}
+
+	list, err := s.ListScanExclusions(ctx)
+	if err != nil {
+		t.Fatalf("ListScanExclusions: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != excl.ID || list[0].Description != "flaky printer" {
+		t.Fatalf("list = %+v, want the created exclusion", list)
+	}
+
+	if err := s.DeleteScanExclusion(ctx, excl.ID); err != nil {
+		t.Fatalf("DeleteScanExclusion: %v", err)
+	}
+	if err := s.DeleteScanExclusion(ctx, excl.ID); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("second delete error = %v, want not found", err)
+	}
+}
+
+func TestRunScan_ExcludedHostsNeverProbed(t *testing.T) {
+	orch, reconStore, _ := setupOrchestrator(t, &mockPingScanner{}, &mockARPReader{}, &mockOUI{})
+	pinger := &recordingPinger{}
+	orch.pinger = pinger
+	ctx := context.Background()
+
+	if err := reconStore.CreateScanExclusion(ctx, &ScanExclusion{CIDR: "10.3.0.5"}); err != nil {
+		t.Fatalf("CreateScanExclusion: %v", err)
+	}
+	if err := reconStore.CreateScan(ctx, &models.ScanResult{ID: "scan-excl", Subnet: "10.3.0.0/28", Status: "running"}); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+
+	// 10.3.0.5 is excluded by the stored list, 10.3.0.8-15 by the request.
+	orch.RunScan(ctx, "scan-excl", "10.3.0.0/28", "10.3.0.8/29")
+
+	want := []string{"10.3.0.1", "10.3.0.2", "10.3.0.3", "10.3.0.4", "10.3.0.6", "10.3.0.7"}
+	slices.Sort(pinger.probed)
+	if !slices.Equal(pinger.probed, want) {
+		t.Errorf("probed = %v, want %v", pinger.probed, want)
+	}
+
+	snaps, err := reconStore.ListScanDeviceSnapshots(ctx, "scan-excl")
+	if err != nil {
+		t.Fatalf("ListScanDeviceSnapshots: %v", err)
+	}
+	if len(snaps) != len(want) {
+		t.Errorf("scan devices = %d, want %d", len(snaps), len(want))
+	}
+	for _, snap := range snaps {
+		if slices.Contains(snap.IPAddresses, "10.3.0.5") {
+			t.Errorf("excluded host 10.3.0.5 recorded as device %s", snap.DeviceID)
+		}
+	}
+
+	scan, err := reconStore.GetScan(ctx, "scan-excl")
+	if err != nil {
+		t.Fatalf("GetScan: %v", err)
+	}
+	if scan.Total != len(want) || scan.Online != len(want) {
+		t.Errorf("scan total/online = %d/%d, want %d/%d", scan.Total, scan.Online, len(want), len(want))
+	}
+}
+
+func TestRunScan_ExclusionsFilterNonExcludingPinger(t *testing.T) {
+	pinger := &mockPingScanner{
+		results: []HostResult{
+			{IP: "10.4.0.1", Alive: true, Method: "icmp"},
+			{IP: "10.4.0.2", Alive: true, Method: "icmp"},
+		},
+	}
+	orch, reconStore, collector := setupOrchestrator(t, pinger, &mockARPReader{}, &mockOUI{})
+	ctx := context.Background()
+
+	if err := reconStore.CreateScan(ctx, &models.ScanResult{ID: "scan-filter", Subnet: "10.4.0.0/24", Status: "running"}); err != nil {
+		t.Fatalf("CreateScan: %v", err)
+	}
+	orch.RunScan(ctx, "scan-filter", "10.4.0.0/24", "10.4.0.2")
+
+	discovered := collector.byTopic(TopicDeviceDiscovered)
+	if len(discovered) != 1 {
+		t.Fatalf("discovered events = %d, want 1", len(discovered))
+	}
+	if ips := discovered[0].Payload.(*DeviceEvent).Device.IPAddresses; !slices.Equal(ips, []string{"10.4.0.1"}) {
+		t.Errorf("discovered device IPs = %v, want [10.4.0.1]", ips)
+	}
+}
+
+func TestHandleScanExclusions(t *testing.T) {
+	m := newTestModule(t)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /exclusions", m.handleListScanExclusions)
+	mux.HandleFunc("POST /exclusions", m.handleCreateScanExclusion)
+	mux.HandleFunc("DELETE /exclusions/{id}", m.handleDeleteScanExclusion)
+	mux.HandleFunc("POST /scan", m.handleScan)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{"create ip", http.MethodPost, "/exclusions", `{"cidr":"192.168.1.10"}`, http.StatusCreated},
+		{"create duplicate", http.MethodPost, "/exclusions", `{"cidr":"192.168.1.10/32"}`, http.StatusConflict},
+		{"create cidr", http.MethodPost, "/exclusions", `{"cidr":"192.168.1.128/25"}`, http.StatusCreated},
+		{"missing cidr", http.MethodPost, "/exclusions", `{}`, http.StatusBadRequest},
+		{"invalid cidr", http.MethodPost, "/exclusions", `{"cidr":"192.168.1.0/33"}`, http.StatusBadRequest},
+		{"delete unknown", http.MethodDelete, "/exclusions/missing", "", http.StatusNotFound},
+		{"scan invalid exclusion", http.MethodPost, "/scan", `{"subnet":"192.168.1.0/24","exclusions":["bogus"]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := doScheduleRequest(t, mux, tt.method, tt.path, tt.body)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d; body: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+		})
+	}
+
+	list, err := m.store.ListScanExclusions(context.Background())
+	if err != nil {
+		t.Fatalf("ListScanExclusions: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("exclusions = %d, want 2", len(list))
+	}
+	if rr := doScheduleRequest(t, mux, http.MethodDelete, "/exclusions/"+list[0].ID, ""); rr.Code != http.StatusNoContent {
+		t.Errorf("delete status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+}
@@ -0,0 +1,86 @@
+package recon
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// scanRateAlpha weights the newest sample in the smoothed probe rate.
+	scanRateAlpha = 0.3
+
+	// scanRateMinSample is the shortest interval sampled for the probe
+	// rate, so results that arrive in bursts don't swing it.
+	scanRateMinSample = 500 * time.Millisecond
+
+	// scanProgressInterval is how often progress is published while no
+	// new hosts are found.
+	scanProgressInterval = time.Second
+)
+
+// ProbeProgress is a point-in-time view of a scan's probing.
+type ProbeProgress struct {
+	Done  int
+	Total int
+	Rate  float64       // smoothed probes per second; 0 until measured
+	ETA   time.Duration // estimated time to finish probing; 0 if unknown or done
+}
+
+// scanRate tracks probes completed against the total for a running scan and
+// estimates the time remaining from an exponential moving average of the
+// probe rate. It is safe for concurrent use.
+type scanRate struct {
+	mu          sync.Mutex
+	total       int
+	done        int
+	start       time.Time
+	sampledAt   time.Time
+	sampledDone int
+	rate        float64
+}
+
+func newScanRate(total int, start time.Time) *scanRate {
+	return &scanRate{total: total, start: start, sampledAt: start}
+}
+
+// Update records that done probes have completed as of now.
+func (s *scanRate) Update(done int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.done = done
+	dt := now.Sub(s.sampledAt)
+	if dt <= 0 || (dt < scanRateMinSample && (s.rate > 0 || done < s.total)) {
+		return
+	}
+	sample := float64(done-s.sampledDone) / dt.Seconds()
+	if s.rate == 0 {
+		s.rate = sample
+	} else {
+		s.rate = scanRateAlpha*sample + (1-scanRateAlpha)*s.rate
+	}
+	s.sampledAt, s.sampledDone = now, done
+}
+
+// Progress returns the current probe counts, rate, and estimated time
+// remaining.
+func (s *scanRate) Progress() ProbeProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := ProbeProgress{Done: s.done, Total: s.total, Rate: s.rate}
+	if remaining := s.total - s.done; remaining > 0 && s.rate > 0 {
+		p.ETA = time.Duration(float64(remaining) / s.rate * float64(time.Second))
+	}
+	return p
+}
+
+// Progress returns the probe progress of a running scan, or false if the
+// scan is not running.
+func (o *ScanOrchestrator) Progress(scanID string) (ProbeProgress, bool) {
+	v, ok := o.progress.Load(scanID)
+	if !ok {
+		return ProbeProgress{}, false
+	}
+	return v.(*scanRate).Progress(), true
+}
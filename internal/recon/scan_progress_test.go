@@ -0,0 +1,69 @@
+package recon
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestScanRate_ETADecreasesToZero(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	const total = 1000
+	r := newScanRate(total, start)
+
+	// Results arrive once a second at an uneven 90-110 probes per tick.
+	now, done := start, 0
+	prevETA := time.Duration(math.MaxInt64)
+	for i := 0; done < total; i++ {
+		now = now.Add(time.Second)
+		done = min(done+90+(i%2)*20, total)
+		r.Update(done, now)
+
+		p := r.Progress()
+		if p.Done != done || p.Total != total {
+			t.Fatalf("tick %d: progress %d/%d, want %d/%d", i, p.Done, p.Total, done, total)
+		}
+		if p.ETA > prevETA {
+			t.Errorf("tick %d: ETA rose from %v to %v", i, prevETA, p.ETA)
+		}
+		prevETA = p.ETA
+		if p.Rate < 85 || p.Rate > 115 {
+			t.Errorf("tick %d: rate = %.1f/s, want about 100/s", i, p.Rate)
+		}
+	}
+
+	if p := r.Progress(); p.ETA != 0 {
+		t.Errorf("ETA at completion = %v, want 0", p.ETA)
+	}
+}
+
+func TestScanRate_SmoothsBursts(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := newScanRate(10000, start)
+
+	r.Update(100, start.Add(time.Second))
+	if got := r.Progress().Rate; got != 100 {
+		t.Fatalf("first rate = %.1f, want 100", got)
+	}
+
+	// A burst of results within the sampling interval is not sampled.
+	r.Update(400, start.Add(time.Second+10*time.Millisecond))
+	if got := r.Progress().Rate; got != 100 {
+		t.Errorf("rate after burst = %.1f, want unchanged 100", got)
+	}
+
+	// One fast interval moves the average only part of the way.
+	r.Update(700, start.Add(2*time.Second))
+	if got := r.Progress().Rate; got <= 100 || got >= 600 {
+		t.Errorf("rate after fast interval = %.1f, want between 100 and 600", got)
+	}
+}
+
+func TestScanRate_NoRateNoETA(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := newScanRate(254, start)
+	r.Update(3, start.Add(100*time.Millisecond))
+	if p := r.Progress(); p.Rate != 0 || p.ETA != 0 {
+		t.Errorf("progress before first sample = %+v, want no rate or ETA", p)
+	}
+}
@@ -0,0 +1,217 @@
+package recon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// CreateScanScheduleRequest is the request body for POST /schedules.
+type CreateScanScheduleRequest struct {
+	Subnet          string `json:"subnet" example:"192.168.1.0/24"`
+	IntervalSeconds int    `json:"interval_seconds" example:"21600"`
+	Enabled         *bool  `json:"enabled,omitempty"` // defaults to true
+}
+
+// UpdateScanScheduleRequest is the request body for PUT /schedules/{id}.
+// Omitted fields are left unchanged.
+type UpdateScanScheduleRequest struct {
+	Subnet          *string `json:"subnet,omitempty"`
+	IntervalSeconds *int    `json:"interval_seconds,omitempty"`
+	Enabled         *bool   `json:"enabled,omitempty"`
+}
+
+// validateScheduleInterval checks a schedule interval against the minimum.
+func validateScheduleInterval(seconds int) error {
+	if seconds < int(minScheduleInterval.Seconds()) {
+		return fmt.Errorf("interval_seconds must be at least %d", int(minScheduleInterval.Seconds()))
+	}
+	return nil
+}
+
+// handleCreateScanSchedule creates a recurring scan schedule.
+//
+//	@Summary		Create scan schedule
+//	@Description	Creates a schedule that rescans a subnet every interval_seconds. The subnet must be a CIDR no larger than /16 and the interval at least 300 seconds. Enabled schedules run for the first time within a minute of creation.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			request	body		CreateScanScheduleRequest	true	"Schedule to create"
+//	@Success		201		{object}	ScanSchedule
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/schedules [post]
+func (m *Module) handleCreateScanSchedule(w http.ResponseWriter, r *http.Request) {
+	var req CreateScanScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if req.Subnet == "" {
+		writeError(w, http.StatusBadRequest, "subnet is required")
+		return
+	}
+	if err := validateScanSubnet(req.Subnet); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err := validateScheduleInterval(req.IntervalSeconds); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	sched := &ScanSchedule{
+		Subnet:          req.Subnet,
+		IntervalSeconds: req.IntervalSeconds,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+	}
+	if err := m.store.CreateScanSchedule(r.Context(), sched); err != nil {
+		m.logger.Error("failed to create scan schedule", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to create scan schedule")
+		return
+	}
+	writeJSON(w, http.StatusCreated, sched)
+}
+
+// handleListScanSchedules returns all scan schedules.
+//
+//	@Summary		List scan schedules
+//	@Description	Returns all recurring scan schedules, including when each last ran.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Success		200	{array}		ScanSchedule
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/schedules [get]
+func (m *Module) handleListScanSchedules(w http.ResponseWriter, r *http.Request) {
+	schedules, err := m.store.ListScanSchedules(r.Context())
+	if err != nil {
+		m.logger.Error("failed to list scan schedules", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to list scan schedules")
+		return
+	}
+	if schedules == nil {
+		schedules = []ScanSchedule{}
+	}
+	writeJSON(w, http.StatusOK, schedules)
+}
+
+// handleGetScanSchedule returns a single scan schedule.
+//
+//	@Summary		Get scan schedule
+//	@Description	Returns a recurring scan schedule by ID.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path		string	true	"Schedule ID"
+//	@Success		200	{object}	ScanSchedule
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/schedules/{id} [get]
+func (m *Module) handleGetScanSchedule(w http.ResponseWriter, r *http.Request) {
+	sched, err := m.store.GetScanSchedule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		m.logger.Error("failed to get scan schedule", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to get scan schedule")
+		return
+	}
+	if sched == nil {
+		writeError(w, http.StatusNotFound, "scan schedule not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, sched)
+}
+
+// handleUpdateScanSchedule changes a schedule's subnet, interval, or enabled
+// flag. Disabling a schedule stops future runs; a scan already in progress
+// is left to finish.
+//
+//	@Summary		Update scan schedule
+//	@Description	Updates a recurring scan schedule. Omitted fields are unchanged. Set enabled to false to pause the schedule.
+//	@Tags			recon
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id		path		string						true	"Schedule ID"
+//	@Param			request	body		UpdateScanScheduleRequest	true	"Fields to update"
+//	@Success		200		{object}	ScanSchedule
+//	@Failure		400		{object}	models.APIProblem
+//	@Failure		404		{object}	models.APIProblem
+//	@Failure		500		{object}	models.APIProblem
+//	@Router			/recon/schedules/{id} [put]
+func (m *Module) handleUpdateScanSchedule(w http.ResponseWriter, r *http.Request) {
+	var req UpdateScanScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+
+	sched, err := m.store.GetScanSchedule(r.Context(), r.PathValue("id"))
+	if err != nil {
+		m.logger.Error("failed to get scan schedule", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "failed to update scan schedule")
+		return
+	}
+	if sched == nil {
+		writeError(w, http.StatusNotFound, "scan schedule not found")
+		return
+	}
+
+	if req.Subnet != nil {
+		if err := validateScanSubnet(*req.Subnet); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sched.Subnet = *req.Subnet
+	}
+	if req.IntervalSeconds != nil {
+		if err := validateScheduleInterval(*req.IntervalSeconds); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		sched.IntervalSeconds = *req.IntervalSeconds
+	}
+	if req.Enabled != nil {
+		sched.Enabled = *req.Enabled
+	}
+
+	if err := m.store.UpdateScanSchedule(r.Context(), sched); err != nil {
+		m.logger.Error("failed to update scan schedule", zap.Error(err))
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, "scan schedule not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to update scan schedule")
+		return
+	}
+	writeJSON(w, http.StatusOK, sched)
+}
+
+// handleDeleteScanSchedule deletes a scan schedule.
+//
+//	@Summary		Delete scan schedule
+//	@Description	Deletes a recurring scan schedule. A scan already in progress is left to finish.
+//	@Tags			recon
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			id	path	string	true	"Schedule ID"
+//	@Success		204	"No content"
+//	@Failure		404	{object}	models.APIProblem
+//	@Failure		500	{object}	models.APIProblem
+//	@Router			/recon/schedules/{id} [delete]
+func (m *Module) handleDeleteScanSchedule(w http.ResponseWriter, r *http.Request) {
+	if err := m.store.DeleteScanSchedule(r.Context(), r.PathValue("id")); err != nil {
+		m.logger.Error("failed to delete scan schedule", zap.Error(err))
+		if strings.Contains(err.Error(), "not found") {
+			writeError(w, http.StatusNotFound, "scan schedule not found")
+			return
+		}
+		writeError(w, http.StatusInternalServerError, "failed to delete scan schedule")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,115 @@
+package recon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scheduleMux(m *Module) *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schedules", m.handleListScanSchedules)
+	mux.HandleFunc("POST /schedules", m.handleCreateScanSchedule)
+	mux.HandleFunc("GET /schedules/{id}", m.handleGetScanSchedule)
+	mux.HandleFunc("PUT /schedules/{id}", m.handleUpdateScanSchedule)
+	mux.HandleFunc("DELETE /schedules/{id}", m.handleDeleteScanSchedule)
+	return mux
+}
+
+func doScheduleRequest(t *testing.T, mux *http.ServeMux, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandleCreateScanSchedule_Validation(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantDetail string
+	}{
+		{"valid", `{"subnet":"192.168.1.0/24","interval_seconds":21600}`, http.StatusCreated, ""},
+		{"missing subnet", `{"interval_seconds":21600}`, http.StatusBadRequest, "subnet is required"},
+		{"invalid cidr", `{"subnet":"192.168.1.0","interval_seconds":21600}`, http.StatusBadRequest, "invalid CIDR"},
+		{"subnet too large", `{"subnet":"10.0.0.0/8","interval_seconds":21600}`, http.StatusBadRequest, "subnet too large"},
+		{"interval too short", `{"subnet":"192.168.1.0/24","interval_seconds":60}`, http.StatusBadRequest, "interval_seconds must be at least 300"},
+		{"invalid json", `{`, http.StatusBadRequest, "invalid JSON body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := scheduleMux(newTestModule(t))
+			rr := doScheduleRequest(t, mux, http.MethodPost, "/schedules", tt.body)
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body: %s", rr.Code, tt.wantStatus, rr.Body.String())
+			}
+			if tt.wantDetail != "" && !strings.Contains(rr.Body.String(), tt.wantDetail) {
+				t.Errorf("body = %s, want detail containing %q", rr.Body.String(), tt.wantDetail)
+			}
+		})
+	}
+}
+
+func TestHandleScanSchedules_Lifecycle(t *testing.T) {
+	mux := scheduleMux(newTestModule(t))
+
+	rr := doScheduleRequest(t, mux, http.MethodPost, "/schedules", `{"subnet":"192.168.1.0/24","interval_seconds":3600}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, want %d; body: %s", rr.Code, http.StatusCreated, rr.Body.String())
+	}
+	var created ScanSchedule
+	if err := json.NewDecoder(rr.Body).Decode(&created); err != nil {
+		t.Fatalf("decode create response: %v", err)
+	}
+	if created.ID == "" || !created.Enabled || created.IntervalSeconds != 3600 {
+		t.Fatalf("created = %+v, want enabled schedule with ID and 3600s interval", created)
+	}
+
+	rr = doScheduleRequest(t, mux, http.MethodGet, "/schedules", "")
+	var list []ScanSchedule
+	if err := json.NewDecoder(rr.Body).Decode(&list); err != nil {
+		t.Fatalf("decode list response: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != created.ID {
+		t.Fatalf("list = %+v, want the created schedule", list)
+	}
+
+	// Disable the schedule.
+	rr = doScheduleRequest(t, mux, http.MethodPut, "/schedules/"+created.ID, `{"enabled":false}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("update status = %d, want %d; body: %s", rr.Code, http.StatusOK, rr.Body.String())
+	}
+	rr = doScheduleRequest(t, mux, http.MethodGet, "/schedules/"+created.ID, "")
+	var got ScanSchedule
+	if err := json.NewDecoder(rr.Body).Decode(&got); err != nil {
+		t.Fatalf("decode get response: %v", err)
+	}
+	if got.Enabled || got.Subnet != "192.168.1.0/24" || got.IntervalSeconds != 3600 {
+		t.Errorf("after update = %+v, want disabled with subnet and interval unchanged", got)
+	}
+
+	rr = doScheduleRequest(t, mux, http.MethodPut, "/schedules/"+created.ID, `{"subnet":"10.0.0.0/8"}`)
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("update with oversized subnet status = %d, want %d", rr.Code, http.StatusBadRequest)
+	}
+
+	rr = doScheduleRequest(t, mux, http.MethodDelete, "/schedules/"+created.ID, "")
+	if rr.Code != http.StatusNoContent {
+		t.Fatalf("delete status = %d, want %d", rr.Code, http.StatusNoContent)
+	}
+
+	for _, req := range []struct{ method, body string }{
+		{http.MethodGet, ""},
+		{http.MethodPut, `{"enabled":true}`},
+		{http.MethodDelete, ""},
+	} {
+		rr = doScheduleRequest(t, mux, req.method, "/schedules/"+created.ID, req.body)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("%s after delete status = %d, want %d", req.method, rr.Code, http.StatusNotFound)
+		}
+	}
+}
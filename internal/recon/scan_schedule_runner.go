@@ -0,0 +1,192 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// defaultScheduleCheckInterval is how often the runner looks for due
+// schedules. A schedule runs at most this long after it becomes due.
+const defaultScheduleCheckInterval = 30 * time.Second
+
+// minScheduleInterval is the shortest interval a scan schedule may use.
+const minScheduleInterval = 5 * time.Minute
+
+// validateScanSubnet checks that subnet is a valid CIDR no larger than /16.
+func validateScanSubnet(subnet string) error {
+	_, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return fmt.Errorf("invalid CIDR: %w", err)
+	}
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 16 {
+		return errors.New("subnet too large: maximum /16 allowed")
+	}
+	return nil
+}
+
+// ScanScheduleRunner starts scans for stored ScanSchedules when they are
+// due. Each schedule has at most one scan in flight; a scan that overruns
+// its interval delays the next run rather than overlapping with it.
+// Disabled and deleted schedules are dropped on the next check.
+type ScanScheduleRunner struct {
+	store        *ReconStore
+	orchestrator *ScanOrchestrator
+	activeScans  *sync.Map
+	wg           *sync.WaitGroup
+	newScanCtx   func() (context.Context, context.CancelFunc)
+	logger       *zap.Logger
+	nowFunc      func() time.Time
+	checkEvery   time.Duration
+
+	mu      sync.Mutex
+	running map[string]string // schedule ID -> scan ID
+}
+
+// NewScanScheduleRunner creates a runner. The newScanCtx function should
+// return a child context from the module's scan context for cancellation.
+func NewScanScheduleRunner(
+	store *ReconStore,
+	orchestrator *ScanOrchestrator,
+	activeScans *sync.Map,
+	wg *sync.WaitGroup,
+	newScanCtx func() (context.Context, context.CancelFunc),
+	logger *zap.Logger,
+) *ScanScheduleRunner {
+	return &ScanScheduleRunner{
+		store:        store,
+		orchestrator: orchestrator,
+		activeScans:  activeScans,
+		wg:           wg,
+		newScanCtx:   newScanCtx,
+		logger:       logger,
+		nowFunc:      time.Now,
+		checkEvery:   defaultScheduleCheckInterval,
+		running:      make(map[string]string),
+	}
+}
+
+// Run checks for due schedules immediately and then every check interval.
+// It blocks until ctx is cancelled.
+func (r *ScanScheduleRunner) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.checkEvery)
+	defer ticker.Stop()
+
+	r.logger.Info("scan schedule runner started", zap.Duration("check_interval", r.checkEvery))
+	for {
+		r.runDue(ctx)
+		select {
+		case <-ctx.Done():
+			r.logger.Info("scan schedule runner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue starts a scan for every enabled schedule whose interval has
+// elapsed since its last run and that has no scan in flight.
+func (r *ScanScheduleRunner) runDue(ctx context.Context) {
+	schedules, err := r.store.ListEnabledScanSchedules(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Warn("failed to load scan schedules", zap.Error(err))
+		}
+		return
+	}
+
+	now := r.nowFunc()
+	for i := range schedules {
+		sched := &schedules[i]
+		if sched.LastRunAt != nil && now.Sub(*sched.LastRunAt) < sched.Interval() {
+			continue
+		}
+		if scanID, busy := r.runningScan(sched.ID); busy {
+			r.logger.Debug("scheduled scan skipped: previous run still in progress",
+				zap.String("schedule_id", sched.ID),
+				zap.String("scan_id", scanID),
+			)
+			continue
+		}
+		r.start(ctx, sched, now)
+	}
+}
+
+// runningScan returns the in-flight scan for a schedule, if any.
+func (r *ScanScheduleRunner) runningScan(scheduleID string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	scanID, ok := r.running[scheduleID]
+	return scanID, ok
+}
+
+// start creates the scan record and runs the scan in the background,
+// mirroring handleScan.
+func (r *ScanScheduleRunner) start(ctx context.Context, sched *ScanSchedule, now time.Time) {
+	// Schedules are validated on create, but re-check in case the rules
+	// changed since the row was written.
+	if err := validateScanSubnet(sched.Subnet); err != nil {
+		r.logger.Error("scheduled scan: invalid subnet",
+			zap.String("schedule_id", sched.ID),
+			zap.String("subnet", sched.Subnet),
+			zap.Error(err),
+		)
+		return
+	}
+
+	scanID := uuid.New().String()
+	scan := &models.ScanResult{
+		ID:     scanID,
+		Subnet: sched.Subnet,
+		Status: "running",
+	}
+	if err := r.store.CreateScan(ctx, scan); err != nil {
+		r.logger.Error("scheduled scan: failed to create scan record",
+			zap.String("schedule_id", sched.ID),
+			zap.Error(err),
+		)
+		return
+	}
+	if err := r.store.MarkScanScheduleRun(ctx, sched.ID, scanID, now); err != nil {
+		r.logger.Warn("scheduled scan: failed to record run",
+			zap.String("schedule_id", sched.ID),
+			zap.Error(err),
+		)
+	}
+
+	r.mu.Lock()
+	r.running[sched.ID] = scanID
+	r.mu.Unlock()
+
+	scanCtx, cancel := r.newScanCtx()
+	r.activeScans.Store(scanID, cancel)
+	r.wg.Add(1)
+
+	r.logger.Info("scheduled scan started",
+		zap.String("schedule_id", sched.ID),
+		zap.String("scan_id", scanID),
+		zap.String("subnet", sched.Subnet),
+	)
+
+	scheduleID := sched.ID
+	subnet := sched.Subnet
+	go func() {
+		defer r.wg.Done()
+		defer func() {
+			r.mu.Lock()
+			delete(r.running, scheduleID)
+			r.mu.Unlock()
+		}()
+		defer r.activeScans.Delete(scanID)
+		defer cancel()
+		r.orchestrator.RunScan(scanCtx, scanID, subnet)
+	}()
+}
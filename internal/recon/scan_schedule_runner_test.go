@@ -0,0 +1,221 @@
+package recon
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/store"
+	"go.uber.org/zap"
+)
+
+func setupTestScheduleRunner(t *testing.T) (*ScanScheduleRunner, *ReconStore) {
+	t.Helper()
+
+	db, err := store.New(":memory:")
+	if err != nil {
+		t.Fatalf("open test db: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Migrate(context.Background(), "recon", migrations()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	s := NewReconStore(db.DB())
+	logger := zap.NewNop()
+	orchestrator := NewScanOrchestrator(s, &mockEventBus{}, NewOUITable(), &noopPinger{}, nil, logger)
+
+	var activeScans sync.Map
+	var wg sync.WaitGroup
+	t.Cleanup(wg.Wait)
+
+	parentCtx, parentCancel := context.WithCancel(context.Background())
+	t.Cleanup(parentCancel)
+	newScanCtx := func() (context.Context, context.CancelFunc) {
+		return context.WithCancel(parentCtx)
+	}
+
+	return NewScanScheduleRunner(s, orchestrator, &activeScans, &wg, newScanCtx, logger), s
+}
+
+func createTestSchedule(t *testing.T, s *ReconStore, subnet string, enabled bool) *ScanSchedule {
+	t.Helper()
+	sched := &ScanSchedule{Subnet: subnet, IntervalSeconds: 600, Enabled: enabled}
+	if err := s.CreateScanSchedule(context.Background(), sched); err != nil {
+		t.Fatalf("CreateScanSchedule: %v", err)
+	}
+	return sched
+}
+
+func countScans(t *testing.T, s *ReconStore) int {
+	t.Helper()
+	scans, err := s.ListScans(context.Background(), 100, 0)
+	if err != nil {
+		t.Fatalf("ListScans: %v", err)
+	}
+	return len(scans)
+}
+
+func TestScanScheduleRunner_TickerTriggersScan(t *testing.T) {
+	runner, s := setupTestScheduleRunner(t)
+	runner.checkEvery = 20 * time.Millisecond
+	sched := createTestSchedule(t, s, "10.0.0.0/24", true)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runner.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for countScans(t, s) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Several more ticks must not start a second scan before the interval.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+	runner.wg.Wait()
+
+	scans, err := s.ListScans(context.Background(), 100, 0)
+	if err != nil {
+		t.Fatalf("ListScans: %v", err)
+	}
+	if len(scans) != 1 {
+		t.Fatalf("scans = %d, want 1", len(scans))
+	}
+	if scans[0].Subnet != "10.0.0.0/24" {
+		t.Errorf("scan subnet = %q, want 10.0.0.0/24", scans[0].Subnet)
+	}
+
+	got, err := s.GetScanSchedule(context.Background(), sched.ID)
+	if err != nil {
+		t.Fatalf("GetScanSchedule: %v", err)
+	}
+	if got.LastRunAt == nil || got.LastScanID != scans[0].ID {
+		t.Errorf("schedule last run = %v/%q, want set to scan %q", got.LastRunAt, got.LastScanID, scans[0].ID)
+	}
+}
+
+func TestScanScheduleRunner_RerunsAfterInterval(t *testing.T) {
+	runner, s := setupTestScheduleRunner(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	runner.nowFunc = func() time.Time { return now }
+	createTestSchedule(t, s, "10.0.0.0/24", true)
+	ctx := context.Background()
+
+	runner.runDue(ctx)
+	runner.wg.Wait()
+	if n := countScans(t, s); n != 1 {
+		t.Fatalf("scans after first run = %d, want 1", n)
+	}
+
+	now = now.Add(5 * time.Minute)
+	runner.runDue(ctx)
+	runner.wg.Wait()
+	if n := countScans(t, s); n != 1 {
+		t.Fatalf("scans before interval elapsed = %d, want 1", n)
+	}
+
+	now = now.Add(5 * time.Minute)
+	runner.runDue(ctx)
+	runner.wg.Wait()
+	if n := countScans(t, s); n != 2 {
+		t.Fatalf("scans after interval elapsed = %d, want 2", n)
+	}
+}
+
+func TestScanScheduleRunner_DisablingStopsSchedule(t *testing.T) {
+	runner, s := setupTestScheduleRunner(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	runner.nowFunc = func() time.Time { return now }
+	sched := createTestSchedule(t, s, "10.0.0.0/24", true)
+	createTestSchedule(t, s, "10.0.1.0/24", false)
+	ctx := context.Background()
+
+	runner.runDue(ctx)
+	runner.wg.Wait()
+	if n := countScans(t, s); n != 1 {
+		t.Fatalf("scans with one enabled schedule = %d, want 1", n)
+	}
+
+	sched.Enabled = false
+	if err := s.UpdateScanSchedule(ctx, sched); err != nil {
+		t.Fatalf("UpdateScanSchedule: %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	runner.runDue(ctx)
+	runner.wg.Wait()
+	if n := countScans(t, s); n != 1 {
+		t.Errorf("scans after disabling = %d, want 1", n)
+	}
+}
+
+func TestScanScheduleRunner_OneScanPerSchedule(t *testing.T) {
+	runner, s := setupTestScheduleRunner(t)
+	sched := createTestSchedule(t, s, "10.0.0.0/24", true)
+	other := createTestSchedule(t, s, "10.0.1.0/24", true)
+
+	// Simulate a scan still running for the first schedule.
+	runner.running[sched.ID] = "in-flight"
+
+	runner.runDue(context.Background())
+	runner.wg.Wait()
+
+	scans, err := s.ListScans(context.Background(), 100, 0)
+	if err != nil {
+		t.Fatalf("ListScans: %v", err)
+	}
+	if len(scans) != 1 || scans[0].Subnet != other.Subnet {
+		t.Fatalf("scans = %+v, want one scan of %s", scans, other.Subnet)
+	}
+	if scanID, _ := runner.runningScan(sched.ID); scanID != "in-flight" {
+		t.Errorf("running scan = %q, want in-flight scan untouched", scanID)
+	}
+	if _, busy := runner.runningScan(other.ID); busy {
+		t.Error("finished scan still marked as running")
+	}
+}
+
+func TestScanScheduleRunner_SkipsInvalidSubnet(t *testing.T) {
+	runner, s := setupTestScheduleRunner(t)
+	createTestSchedule(t, s, "10.0.0.0/8", true)
+
+	runner.runDue(context.Background())
+	runner.wg.Wait()
+
+	if n := countScans(t, s); n != 0 {
+		t.Errorf("scans = %d, want 0 for an oversized subnet", n)
+	}
+}
+
+func TestValidateScanSubnet(t *testing.T) {
+	tests := []struct {
+		subnet  string
+		wantErr string
+	}{
+		{"192.168.1.0/24", ""},
+		{"10.0.0.0/16", ""},
+		{"10.0.0.0/15", "subnet too large: maximum /16 allowed"},
+		{"not-a-cidr", "invalid CIDR"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.subnet, func(t *testing.T) {
+			err := validateScanSubnet(tt.subnet)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validateScanSubnet(%q) error = %v", tt.subnet, err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validateScanSubnet(%q) error = %v, want %q", tt.subnet, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package recon
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ScanSchedule is a recurring scan of one subnet. Unlike the single
+// config-driven ScheduleConfig scan, schedules are managed through the API
+// and each runs on its own interval.
+type ScanSchedule struct {
+	ID              string     `json:"id"`
+	Subnet          string     `json:"subnet" example:"192.168.1.0/24"`
+	IntervalSeconds int        `json:"interval_seconds" example:"21600"`
+	Enabled         bool       `json:"enabled"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastScanID      string     `json:"last_scan_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Interval returns the schedule interval as a duration.
+func (s *ScanSchedule) Interval() time.Duration {
+	return time.Duration(s.IntervalSeconds) * time.Second
+}
+
+const scanScheduleColumns = `id, subnet, interval_seconds, enabled, last_run_at, last_scan_id, created_at, updated_at`
+
+// CreateScanSchedule inserts a new scan schedule.
+func (s *ReconStore) CreateScanSchedule(ctx context.Context, sched *ScanSchedule) error {
+	if sched.ID == "" {
+		sched.ID = uuid.New().String()
+	}
+	now := time.Now().UTC()
+	sched.CreatedAt = now
+	sched.UpdatedAt = now
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO recon_scan_schedules (id, subnet, interval_seconds, enabled, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		sched.ID, sched.Subnet, sched.IntervalSeconds, sched.Enabled, sched.CreatedAt, sched.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("create scan schedule: %w", err)
+	}
+	return nil
+}
+
+// GetScanSchedule returns a scan schedule by ID, or nil if it does not exist.
+func (s *ReconStore) GetScanSchedule(ctx context.Context, id string) (*ScanSchedule, error) {
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+scanScheduleColumns+` FROM recon_scan_schedules WHERE id = ?`, id)
+	sched, err := scanScanSchedule(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get scan schedule: %w", err)
+	}
+	return sched, nil
+}
+
+// ListScanSchedules returns all scan schedules ordered by creation time.
+func (s *ReconStore) ListScanSchedules(ctx context.Context) ([]ScanSchedule, error) {
+	return s.queryScanSchedules(ctx, `
+		SELECT `+scanScheduleColumns+` FROM recon_scan_schedules ORDER BY created_at ASC`)
+}
+
+// ListEnabledScanSchedules returns all enabled scan schedules.
+func (s *ReconStore) ListEnabledScanSchedules(ctx context.Context) ([]ScanSchedule, error) {
+	return s.queryScanSchedules(ctx, `
+		SELECT `+scanScheduleColumns+` FROM recon_scan_schedules WHERE enabled = 1 ORDER BY created_at ASC`)
+}
+
+// UpdateScanSchedule updates a schedule's subnet, interval, and enabled flag.
+func (s *ReconStore) UpdateScanSchedule(ctx context.Context, sched *ScanSchedule) error {
+	sched.UpdatedAt = time.Now().UTC()
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE recon_scan_schedules
+		SET subnet = ?, interval_seconds = ?, enabled = ?, updated_at = ?
+		WHERE id = ?`,
+		sched.Subnet, sched.IntervalSeconds, sched.Enabled, sched.UpdatedAt, sched.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update scan schedule: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("scan schedule not found: %s", sched.ID)
+	}
+	return nil
+}
+
+// MarkScanScheduleRun records that a schedule started the given scan.
+func (s *ReconStore) MarkScanScheduleRun(ctx context.Context, id, scanID string, at time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE recon_scan_schedules SET last_run_at = ?, last_scan_id = ? WHERE id = ?`,
+		at.UTC(), scanID, id,
+	)
+	if err != nil {
+		return fmt.Errorf("mark scan schedule run: %w", err)
+	}
+	return nil
+}
+
+// DeleteScanSchedule removes a scan schedule by ID.
+func (s *ReconStore) DeleteScanSchedule(ctx context.Context, id string) error {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM recon_scan_schedules WHERE id = ?`, id,
+	)
+	if err != nil {
+		return fmt.Errorf("delete scan schedule: %w", err)
+	}
+	rows, _ := result.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("scan schedule not found: %s", id)
+	}
+	return nil
+}
+
+func (s *ReconStore) queryScanSchedules(ctx context.Context, query string) ([]ScanSchedule, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list scan schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []ScanSchedule
+	for rows.Next() {
+		sched, err := scanScanSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan scan schedule: %w", err)
+		}
+		schedules = append(schedules, *sched)
+	}
+	return schedules, rows.Err()
+}
+
+// scanScanSchedule reads one schedule row in scanScheduleColumns order.
+func scanScanSchedule(row interface{ Scan(...any) error }) (*ScanSchedule, error) {
+	var sched ScanSchedule
+	var enabled int
+	var lastRunAt sql.NullTime
+	if err := row.Scan(&sched.ID, &sched.Subnet, &sched.IntervalSeconds, &enabled,
+		&lastRunAt, &sched.LastScanID, &sched.CreatedAt, &sched.UpdatedAt); err != nil {
+		return nil, err
+	}
+	sched.Enabled = enabled == 1
+	if lastRunAt.Valid {
+		t := lastRunAt.Time
+		sched.LastRunAt = &t
+	}
+	return &sched, nil
+}
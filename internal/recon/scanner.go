@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
-	"strings"
+	"sync"
 	"time"
 
 	"github.com/HerbHall/subnetree/pkg/models"
@@ -13,7 +13,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// dnsTimeout is the maximum time to wait for a reverse DNS lookup.
+// dnsTimeout is the default time allowed for looking up a host's name.
 const dnsTimeout = 500 * time.Millisecond
 
 // PingScanner probes hosts via ICMP and sends results to a channel.
@@ -21,6 +21,31 @@ type PingScanner interface {
 	Scan(ctx context.Context, subnet *net.IPNet, results chan<- HostResult) error
 }
 
+// ExcludingPingScanner is a PingScanner that can skip hosts before probing
+// them. The orchestrator uses it when a scan has exclusions; for other
+// scanners, results for excluded hosts are dropped instead.
+type ExcludingPingScanner interface {
+	PingScanner
+	ScanExcluding(ctx context.Context, subnet *net.IPNet, skip func(ip string) bool, results chan<- HostResult) error
+}
+
+// scanExclusions is a set of address ranges a scan must not probe.
+type scanExclusions []*net.IPNet
+
+// Contains reports whether ip falls inside any excluded range.
+func (e scanExclusions) Contains(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range e {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
 // ARPTableReader reads the system ARP table.
 type ARPTableReader interface {
 	ReadTable(ctx context.Context) map[string]string
@@ -41,6 +66,17 @@ type CredentialLookup interface {
 	FindSNMPCredentialForDevice(ctx context.Context, deviceID string) (credID string, err error)
 }
 
+// MDNSBrowser browses DNS-SD services on the local link.
+type MDNSBrowser interface {
+	Browse(ctx context.Context, iface string) ([]MDNSHost, error)
+}
+
+// ScanInterfaceSource reports the network interface selected for scanning
+// in settings. An empty name means the system default.
+type ScanInterfaceSource interface {
+	ScanInterface(ctx context.Context) (string, error)
+}
+
 // ScanOrchestrator coordinates ICMP scanning, ARP enrichment, OUI lookup,
 // device persistence, and event publishing.
 type ScanOrchestrator struct {
@@ -54,6 +90,13 @@ type ScanOrchestrator struct {
 	apEnumerator APClientEnumerator
 	credLookup   CredentialLookup
 	credAccess   CredentialAccessor
+	portScanner  *PortScanner
+	scanPorts    []int
+	mdnsBrowser  MDNSBrowser
+	scanIface    ScanInterfaceSource
+	hostnames    HostnameResolver
+	hostnameTTL  time.Duration
+	progress     sync.Map // scanID -> *scanRate while the scan is probing
 	logger       *zap.Logger
 }
 
@@ -67,13 +110,44 @@ func NewScanOrchestrator(
 	logger *zap.Logger,
 ) *ScanOrchestrator {
 	return &ScanOrchestrator{
-		store:  store,
-		bus:    bus,
-		oui:    oui,
-		pinger: pinger,
-		arp:    arp,
-		logger: logger,
+		store:       store,
+		bus:         bus,
+		oui:         oui,
+		pinger:      pinger,
+		arp:         arp,
+		hostnames:   NewReverseDNSResolver(),
+		hostnameTTL: dnsTimeout,
+		logger:      logger,
+	}
+}
+
+// SetHostnameResolver configures how names are found for discovered hosts.
+// Each host gets at most timeout for the whole lookup. A nil resolver
+// disables hostname lookup.
+func (o *ScanOrchestrator) SetHostnameResolver(r HostnameResolver, timeout time.Duration) {
+	o.hostnames = r
+	if timeout <= 0 {
+		timeout = dnsTimeout
 	}
+	o.hostnameTTL = timeout
+}
+
+// SetDevicePortScan enables a TCP port scan of every discovered device
+// after each scan, probing the given ports with scanner.
+func (o *ScanOrchestrator) SetDevicePortScan(scanner *PortScanner, ports []int) {
+	o.portScanner = scanner
+	o.scanPorts = ports
+}
+
+// SetMDNSBrowser enables mDNS/DNS-SD discovery as a post-scan stage.
+func (o *ScanOrchestrator) SetMDNSBrowser(b MDNSBrowser) {
+	o.mdnsBrowser = b
+}
+
+// SetScanInterfaceSource configures where the selected scan interface is
+// read from. Without one, interface-bound stages use the system default.
+func (o *ScanOrchestrator) SetScanInterfaceSource(src ScanInterfaceSource) {
+	o.scanIface = src
 }
 
 // SetSNMPWalker configures the SNMP FDB walker used during scan post-processing.
@@ -120,8 +194,34 @@ func (o *ScanOrchestrator) runStages(ctx context.Context, stages []scanStage) {
 	}
 }
 
-// RunScan executes a full network scan for the given subnet.
-func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
+// loadExclusions merges the persistent exclusion list with any per-scan
+// entries. Invalid entries are logged and ignored.
+func (o *ScanOrchestrator) loadExclusions(ctx context.Context, extra []string) scanExclusions {
+	entries := append([]string(nil), extra...)
+	stored, err := o.store.ListScanExclusions(ctx)
+	if err != nil {
+		o.logger.Warn("failed to load scan exclusions", zap.Error(err))
+	}
+	for i := range stored {
+		entries = append(entries, stored[i].CIDR)
+	}
+
+	var excl scanExclusions
+	for _, entry := range entries {
+		cidr, err := normalizeExclusion(entry)
+		if err != nil {
+			o.logger.Warn("ignoring scan exclusion", zap.String("entry", entry), zap.Error(err))
+			continue
+		}
+		_, ipNet, _ := net.ParseCIDR(cidr)
+		excl = append(excl, ipNet)
+	}
+	return excl
+}
+
+// RunScan executes a full network scan for the given subnet. Hosts matching
+// the stored exclusion list or any of the given exclusions are never probed.
+func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string, exclusions ...string) {
 	scanStart := time.Now()
 
 	_, ipNet, err := net.ParseCIDR(subnet)
@@ -150,11 +250,33 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 		arpTable = o.arp.ReadTable(ctx)
 	}
 
+	excluded := o.loadExclusions(ctx, exclusions)
+
+	// Count the probes the scanner will send, for progress and ETA. Only an
+	// excluding scanner skips excluded hosts.
+	hosts := expandSubnet(ipNet)
+	probesTotal := len(hosts)
+	if _, ok := o.pinger.(ExcludingPingScanner); ok && len(excluded) > 0 {
+		probesTotal = 0
+		for _, ip := range hosts {
+			if !excluded.Contains(ip) {
+				probesTotal++
+			}
+		}
+	}
+	rate := newScanRate(probesTotal, scanStart)
+	o.progress.Store(scanID, rate)
+	defer o.progress.Delete(scanID)
+
 	// Run ICMP scan.
 	results := make(chan HostResult, 256)
 	scanDone := make(chan error, 1)
 	go func() {
-		scanDone <- o.pinger.Scan(ctx, ipNet, results)
+		if ep, ok := o.pinger.(ExcludingPingScanner); ok && len(excluded) > 0 {
+			scanDone <- ep.ScanExcluding(ctx, ipNet, excluded.Contains, results)
+		} else {
+			scanDone <- o.pinger.Scan(ctx, ipNet, results)
+		}
 		close(results)
 	}()
 
@@ -165,8 +287,28 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 	var totalCount int
 	var devicesCreated int
 	var devicesUpdated int
+	var probesDone int
+	var lastProgress time.Time
+	publishProgress := func() {
+		p := rate.Progress()
+		o.publishEvent(ctx, TopicScanProgress, &ScanProgressEvent{
+			ScanID:      scanID,
+			HostsAlive:  len(alive),
+			SubnetSize:  subnetSize,
+			ProbesDone:  p.Done,
+			ProbesTotal: p.Total,
+			RatePPS:     p.Rate,
+			ETASeconds:  p.ETA.Seconds(),
+		})
+		lastProgress = time.Now()
+	}
 	for r := range results {
-		if !r.Alive {
+		probesDone++
+		rate.Update(probesDone, time.Now())
+		if !r.Alive || excluded.Contains(r.IP) {
+			if ctx.Err() == nil && time.Since(lastProgress) >= scanProgressInterval {
+				publishProgress()
+			}
 			continue
 		}
 		alive = append(alive, r)
@@ -229,11 +371,7 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 		}
 
 		// Emit incremental progress so the UI can show a running count.
-		o.publishEvent(ctx, TopicScanProgress, &ScanProgressEvent{
-			ScanID:     scanID,
-			HostsAlive: len(alive),
-			SubnetSize: subnetSize,
-		})
+		publishProgress()
 	}
 
 	// Ping + enrichment happen together in the streaming loop above.
@@ -244,8 +382,7 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 	cleanupCtx := context.Background()
 	if scanErr := <-scanDone; scanErr != nil {
 		if ctx.Err() != nil {
-			o.logger.Info("scan cancelled", zap.String("scan_id", scanID))
-			_ = o.store.UpdateScanError(cleanupCtx, scanID, "cancelled")
+			o.finishCancelled(scanID, subnet, totalCount, onlineCount, rate)
 			return
 		}
 		o.logger.Error("ICMP scan error", zap.Error(scanErr))
@@ -256,7 +393,9 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 	// Run post-scan processing stages.
 	o.runStages(ctx, []scanStage{
 		{"wifi-scan", func(ctx context.Context) { o.scanWifiNetworks(ctx) }},
+		{"mdns", func(ctx context.Context) { o.browseMDNS(ctx, scanID, ipNet, excluded) }},
 		{"port-scan", func(ctx context.Context) { o.portScanInfraDevices(ctx, alive, arpTable) }},
+		{"device-ports", func(ctx context.Context) { o.scanDevicePorts(ctx, alive) }},
 		{"classify", func(ctx context.Context) { o.classifyDevices(ctx, alive, arpTable) }},
 		{"unmanaged-switch", func(ctx context.Context) { o.detectUnmanagedSwitches(ctx, alive, arpTable) }},
 		{"fdb-walk", func(ctx context.Context) { o.walkSwitchFDBTables(ctx) }},
@@ -268,15 +407,23 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 	})
 
 	postDone := time.Now()
+	if ctx.Err() != nil {
+		o.finishCancelled(scanID, subnet, totalCount, onlineCount, rate)
+		return
+	}
 
 	// Update scan record.
+	probed := rate.Progress()
 	scan := &models.ScanResult{
-		ID:      scanID,
-		Subnet:  subnet,
-		Status:  "completed",
-		EndedAt: time.Now().UTC().Format(time.RFC3339),
-		Total:   totalCount,
-		Online:  onlineCount,
+		ID:          scanID,
+		Subnet:      subnet,
+		Status:      "completed",
+		EndedAt:     time.Now().UTC().Format(time.RFC3339),
+		Total:       totalCount,
+		Online:      onlineCount,
+		ProbesDone:  probed.Done,
+		ProbesTotal: probed.Total,
+		RatePPS:     probed.Rate,
 	}
 	if err := o.store.UpdateScan(ctx, scan); err != nil {
 		o.logger.Error("failed to update scan", zap.Error(err))
@@ -308,17 +455,160 @@ func (o *ScanOrchestrator) RunScan(ctx context.Context, scanID, subnet string) {
 	)
 }
 
-// resolveHostname performs a reverse DNS lookup for the given IP address.
-// Returns an empty string if the lookup fails or times out.
+// finishCancelled records a scan stopped by cancellation. Devices already
+// discovered stay linked to the scan and are counted in its totals.
+func (o *ScanOrchestrator) finishCancelled(scanID, subnet string, total, online int, rate *scanRate) {
+	// The scan context is done, so persist and publish on a fresh one.
+	ctx := context.Background()
+	probed := rate.Progress()
+	scan := &models.ScanResult{
+		ID:          scanID,
+		Subnet:      subnet,
+		Status:      "cancelled",
+		EndedAt:     time.Now().UTC().Format(time.RFC3339),
+		Total:       total,
+		Online:      online,
+		ProbesDone:  probed.Done,
+		ProbesTotal: probed.Total,
+		RatePPS:     probed.Rate,
+	}
+	if err := o.store.UpdateScan(ctx, scan); err != nil {
+		o.logger.Error("failed to update cancelled scan", zap.Error(err))
+	}
+	o.publishEvent(ctx, TopicScanCancelled, scan)
+	o.logger.Info("scan cancelled",
+		zap.String("scan_id", scanID),
+		zap.Int("total", total),
+		zap.Int("probes_done", probed.Done),
+	)
+}
+
+// scanDevicePorts probes the configured ports on each alive host and stores
+// the results on its device. It is a no-op unless SetDevicePortScan was
+// called. Hosts are scanned in parallel; the scanner bounds total dials.
+func (o *ScanOrchestrator) scanDevicePorts(ctx context.Context, alive []HostResult) {
+	if o.portScanner == nil || len(o.scanPorts) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var withOpen int
+	for _, host := range alive {
+		if ctx.Err() != nil {
+			break
+		}
+		device, err := o.store.GetDeviceByIP(ctx, host.IP)
+		if err != nil || device == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(deviceID, ip string) {
+			defer wg.Done()
+			result := o.portScanner.ScanPorts(ctx, ip, o.scanPorts)
+			if len(result.Ports) == 0 {
+				return
+			}
+			if err := o.store.ReplaceDevicePorts(ctx, deviceID, result.Ports, time.Now()); err != nil {
+				o.logger.Error("failed to store device ports",
+					zap.String("device_id", deviceID),
+					zap.Error(err))
+				return
+			}
+			if len(result.OpenPorts) > 0 {
+				mu.Lock()
+				withOpen++
+				mu.Unlock()
+			}
+		}(device.ID, host.IP)
+	}
+	wg.Wait()
+
+	o.logger.Info("device port scan complete",
+		zap.Int("hosts", len(alive)),
+		zap.Int("with_open_ports", withOpen))
+}
+
+// browseMDNS queries DNS-SD services on the selected scan interface and
+// upserts every responding host inside the scanned subnet. Services are
+// recorded as "mdns:" tags on the device.
+func (o *ScanOrchestrator) browseMDNS(ctx context.Context, scanID string, ipNet *net.IPNet, excluded scanExclusions) {
+	if o.mdnsBrowser == nil {
+		return
+	}
+
+	iface := ""
+	if o.scanIface != nil {
+		name, err := o.scanIface.ScanInterface(ctx)
+		if err != nil {
+			o.logger.Warn("failed to read scan interface, using default", zap.Error(err))
+		}
+		iface = name
+	}
+
+	hosts, err := o.mdnsBrowser.Browse(ctx, iface)
+	if err != nil {
+		o.logger.Warn("mDNS browse failed", zap.String("interface", iface), zap.Error(err))
+		return
+	}
+
+	var merged int
+	for i := range hosts {
+		h := &hosts[i]
+		inSubnet := false
+		for _, ip := range h.IPAddresses {
+			if parsed := net.ParseIP(ip); parsed != nil && ipNet.Contains(parsed) && !excluded.Contains(ip) {
+				inSubnet = true
+				break
+			}
+		}
+		if !inSubnet {
+			continue
+		}
+
+		device := h.Device()
+		created, err := o.store.UpsertDevice(ctx, device)
+		if err != nil {
+			o.logger.Error("failed to upsert mDNS device",
+				zap.String("hostname", h.Hostname), zap.Error(err))
+			continue
+		}
+		if err := o.store.LinkScanDevice(ctx, scanID, device.ID); err != nil {
+			o.logger.Error("failed to link scan device", zap.Error(err))
+		}
+
+		devEvent := &DeviceEvent{ScanID: scanID, Device: device}
+		if created {
+			o.publishEvent(ctx, TopicDeviceDiscovered, devEvent)
+		} else {
+			o.publishEvent(ctx, TopicDeviceUpdated, devEvent)
+		}
+		merged++
+	}
+
+	o.logger.Info("mDNS browse complete",
+		zap.String("interface", iface),
+		zap.Int("hosts", len(hosts)),
+		zap.Int("merged", merged))
+}
+
+// resolveHostname looks up a name for the given IP address with the
+// configured resolver. It is best effort: an empty string is returned if
+// lookup is disabled, fails, or times out.
 func (o *ScanOrchestrator) resolveHostname(ip string) string {
-	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	if o.hostnames == nil {
+		return ""
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), o.hostnameTTL)
 	defer cancel()
 
-	names, err := net.DefaultResolver.LookupAddr(ctx, ip)
-	if err != nil || len(names) == 0 {
+	name, err := o.hostnames.LookupHostname(ctx, ip)
+	if err != nil {
+		o.logger.Debug("hostname lookup failed", zap.String("ip", ip), zap.Error(err))
 		return ""
 	}
-	return strings.TrimSuffix(names[0], ".")
+	return name
 }
 
 // inferTopologyLinks creates topology edges between discovered devices and the
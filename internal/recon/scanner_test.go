@@ -275,7 +275,7 @@ func TestScanOrchestrator_Cancellation(t *testing.T) {
 		err:     context.Canceled,
 	}
 
-	orch, reconStore, _ := setupOrchestrator(t, pinger, &mockARPReader{}, &mockOUI{table: map[string]string{}})
+	orch, reconStore, collector := setupOrchestrator(t, pinger, &mockARPReader{}, &mockOUI{table: map[string]string{}})
 
 	ctx, cancel := context.WithCancel(context.Background())
 
@@ -285,10 +285,18 @@ func TestScanOrchestrator_Cancellation(t *testing.T) {
 	cancel() // Cancel immediately.
 	orch.RunScan(ctx, "scan-cancel", "10.0.0.0/24")
 
-	// Scan should be marked as failed/cancelled.
 	got, _ := reconStore.GetScan(context.Background(), "scan-cancel")
-	if got.Status != "failed" {
-		t.Errorf("scan status = %q, want failed (cancelled)", got.Status)
+	if got.Status != "cancelled" {
+		t.Errorf("scan status = %q, want cancelled", got.Status)
+	}
+
+	// Allow time for async events.
+	time.Sleep(50 * time.Millisecond)
+	if n := len(collector.byTopic(TopicScanCancelled)); n != 1 {
+		t.Errorf("cancelled events = %d, want 1", n)
+	}
+	if n := len(collector.byTopic(TopicScanCompleted)); n != 0 {
+		t.Errorf("completed events = %d, want 0 for a cancelled scan", n)
 	}
 }
 
@@ -69,14 +69,27 @@ type SNMPInterface struct {
 	OperStatus  int    // ifOperStatus (1=up, 2=down, 3=testing, etc.)
 }
 
-// SNMPCollector discovers device information using SNMP queries.
+// SNMPCollector discovers device information using SNMP queries. It is
+// bound to one SNMPv2c agent for Poll; the other methods take their target
+// and vault credential per call.
 type SNMPCollector struct {
-	logger *zap.Logger
+	target    string
+	community string
+	logger    *zap.Logger
 }
 
-// NewSNMPCollector creates a new SNMP collector.
-func NewSNMPCollector(logger *zap.Logger) *SNMPCollector {
-	return &SNMPCollector{logger: logger}
+// SNMPSession is the subset of *gosnmp.GoSNMP the collectors use once a
+// session is connected. It lets tests substitute a canned responder.
+type SNMPSession interface {
+	Get(oids []string) (*gosnmp.SnmpPacket, error)
+	BulkWalkAll(rootOid string) ([]gosnmp.SnmpPDU, error)
+}
+
+// NewSNMPCollector creates a new SNMP collector for the agent at target
+// (host or host:port) using community. A collector used only through the
+// per-call methods may leave target and community empty.
+func NewSNMPCollector(target, community string, logger *zap.Logger) *SNMPCollector {
+	return &SNMPCollector{target: target, community: community, logger: logger}
 }
 
 // newGoSNMP creates a configured GoSNMP instance for the given target and credential.
@@ -200,6 +213,26 @@ func (c *SNMPCollector) GetSystemInfo(ctx context.Context, target string, cred C
 	}
 	defer func() { _ = g.Conn.Close() }()
 
+	info, err := c.readSystemInfo(g)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("SNMP system info retrieved",
+		zap.String("target", target),
+		zap.String("name", info.Name),
+		zap.String("descr", info.Description),
+		zap.Int("services", info.Services),
+		zap.String("bridgeAddr", info.BridgeAddress),
+		zap.Int("bridgePorts", info.BridgeNumPorts),
+	)
+
+	return info, nil
+}
+
+// readSystemInfo queries the system group and BRIDGE-MIB scalars on an
+// open session.
+func (c *SNMPCollector) readSystemInfo(g SNMPSession) (*SNMPSystemInfo, error) {
 	oids := []string{
 		OIDSysDescr,
 		OIDSysObjectID,
@@ -238,21 +271,12 @@ func (c *SNMPCollector) GetSystemInfo(ctx context.Context, target string, cred C
 	// Query BRIDGE-MIB separately (not all devices support it).
 	c.queryBridgeInfo(g, info)
 
-	c.logger.Debug("SNMP system info retrieved",
-		zap.String("target", target),
-		zap.String("name", info.Name),
-		zap.String("descr", info.Description),
-		zap.Int("services", info.Services),
-		zap.String("bridgeAddr", info.BridgeAddress),
-		zap.Int("bridgePorts", info.BridgeNumPorts),
-	)
-
 	return info, nil
 }
 
 // queryBridgeInfo attempts to retrieve BRIDGE-MIB data from the device.
 // Many devices don't support BRIDGE-MIB, so errors are silently ignored.
-func (c *SNMPCollector) queryBridgeInfo(g *gosnmp.GoSNMP, info *SNMPSystemInfo) {
+func (c *SNMPCollector) queryBridgeInfo(g SNMPSession, info *SNMPSystemInfo) {
 	oids := []string{OIDBridgeBase, OIDBridgeNumPorts, OIDBridgeType}
 
 	result, err := g.Get(oids)
@@ -295,6 +319,21 @@ func (c *SNMPCollector) GetInterfaces(ctx context.Context, target string, cred C
 	}
 	defer func() { _ = g.Conn.Close() }()
 
+	interfaces, err := c.readInterfaces(g)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debug("SNMP interfaces retrieved",
+		zap.String("target", target),
+		zap.Int("count", len(interfaces)),
+	)
+
+	return interfaces, nil
+}
+
+// readInterfaces walks the IF-MIB ifTable on an open session.
+func (c *SNMPCollector) readInterfaces(g SNMPSession) ([]SNMPInterface, error) {
 	pdus, err := g.BulkWalkAll("1.3.6.1.2.1.2.2.1")
 	if err != nil {
 		return nil, fmt.Errorf("SNMP walk IF-MIB: %w", err)
@@ -349,56 +388,22 @@ func (c *SNMPCollector) GetInterfaces(ctx context.Context, target string, cred C
 		return interfaces[i].Index < interfaces[j].Index
 	})
 
-	c.logger.Debug("SNMP interfaces retrieved",
-		zap.String("target", target),
-		zap.Int("count", len(interfaces)),
-	)
-
 	return interfaces, nil
 }
 
 // Discover uses SNMP to discover devices at the given target IP.
-// It queries standard system MIB objects and returns device information.
+// It queries standard system MIB objects, the interface table, and LLDP
+// neighbors, and returns device information.
 func (c *SNMPCollector) Discover(ctx context.Context, target string, cred CredentialAccessor, credID string) ([]models.Device, error) {
-	sysInfo, err := c.GetSystemInfo(ctx, target, cred, credID)
+	data, err := c.Collect(ctx, target, cred, credID)
 	if err != nil {
-		return nil, fmt.Errorf("get system info: %w", err)
-	}
-
-	interfaces, err := c.GetInterfaces(ctx, target, cred, credID)
-	if err != nil {
-		c.logger.Warn("failed to get interfaces, continuing with system info only",
-			zap.String("target", target),
-			zap.Error(err),
-		)
-		interfaces = nil
-	}
-
-	// Determine hostname.
-	hostname := sysInfo.Name
-	if hostname == "" {
-		// Strip port from target if present.
-		h, _, splitErr := net.SplitHostPort(target)
-		if splitErr != nil {
-			hostname = target
-		} else {
-			hostname = h
-		}
-	}
-
-	// Find first non-loopback MAC address.
-	var macAddr string
-	for i := range interfaces {
-		// ifType 24 = softwareLoopback.
-		if interfaces[i].Type == 24 {
-			continue
-		}
-		if interfaces[i].PhysAddress != "" && interfaces[i].PhysAddress != "00:00:00:00:00:00" {
-			macAddr = interfaces[i].PhysAddress
-			break
-		}
+		return nil, err
 	}
+	return []models.Device{c.DeviceFromData(target, data)}, nil
+}
 
+// DeviceFromData builds a discovered device for target from collected SNMP data.
+func (c *SNMPCollector) DeviceFromData(target string, data *SNMPDeviceData) models.Device {
 	// Extract IP (strip port if present).
 	ip := target
 	if h, _, splitErr := net.SplitHostPort(target); splitErr == nil {
@@ -409,24 +414,27 @@ func (c *SNMPCollector) Discover(ctx context.Context, target string, cred Creden
 
 	device := models.Device{
 		ID:              uuid.New().String(),
-		Hostname:        hostname,
-		DeviceType:      inferDeviceType(sysInfo),
 		DiscoveryMethod: models.DiscoverySNMP,
 		IPAddresses:     []string{ip},
-		MACAddress:      macAddr,
 		Status:          models.DeviceStatusOnline,
 		FirstSeen:       now,
 		LastSeen:        now,
 	}
+	data.MergeInto(&device)
+	if device.Hostname == "" {
+		device.Hostname = ip
+	}
 
 	c.logger.Info("SNMP device discovered",
 		zap.String("target", target),
 		zap.String("hostname", device.Hostname),
 		zap.String("type", string(device.DeviceType)),
+		zap.String("manufacturer", device.Manufacturer),
 		zap.String("mac", device.MACAddress),
+		zap.Int("lldp_neighbors", len(data.Neighbors)),
 	)
 
-	return []models.Device{device}, nil
+	return device
 }
 
 // inferDeviceType determines device type from all available SNMP data.
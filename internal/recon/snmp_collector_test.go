@@ -9,7 +9,7 @@ import (
 )
 
 func TestNewGoSNMP_V2c(t *testing.T) {
-	c := NewSNMPCollector(nil)
+	c := NewSNMPCollector("", "", nil)
 	cred := &SNMPCredential{
 		Type:      "snmp_v2c",
 		Community: "public",
@@ -41,7 +41,7 @@ func TestNewGoSNMP_V2c(t *testing.T) {
 }
 
 func TestNewGoSNMP_V2c_WithPort(t *testing.T) {
-	c := NewSNMPCollector(nil)
+	c := NewSNMPCollector("", "", nil)
 	cred := &SNMPCredential{
 		Type:      "snmp_v2c",
 		Community: "public",
@@ -61,7 +61,7 @@ func TestNewGoSNMP_V2c_WithPort(t *testing.T) {
 }
 
 func TestNewGoSNMP_V3(t *testing.T) {
-	c := NewSNMPCollector(nil)
+	c := NewSNMPCollector("", "", nil)
 	cred := &SNMPCredential{
 		Type:              "snmp_v3",
 		Username:          "admin",
@@ -113,7 +113,7 @@ func TestNewGoSNMP_V3(t *testing.T) {
 }
 
 func TestNewGoSNMP_V3_SecurityLevels(t *testing.T) {
-	c := NewSNMPCollector(nil)
+	c := NewSNMPCollector("", "", nil)
 	tests := []struct {
 		level string
 		want  gosnmp.SnmpV3MsgFlags
@@ -143,7 +143,7 @@ func TestNewGoSNMP_V3_SecurityLevels(t *testing.T) {
 }
 
 func TestNewGoSNMP_InvalidType(t *testing.T) {
-	c := NewSNMPCollector(nil)
+	c := NewSNMPCollector("", "", nil)
 	cred := &SNMPCredential{
 		Type: "snmp_v1",
 	}
@@ -0,0 +1,171 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// snmpEnterpriseVendors maps IANA private enterprise numbers, as found in
+// sysObjectID, to manufacturer names.
+var snmpEnterpriseVendors = map[int]string{
+	9:     "Cisco",
+	11:    "Hewlett-Packard",
+	171:   "D-Link",
+	311:   "Microsoft",
+	674:   "Dell",
+	1916:  "Extreme Networks",
+	2011:  "Huawei",
+	2636:  "Juniper Networks",
+	3375:  "F5 Networks",
+	4526:  "Netgear",
+	6574:  "Synology",
+	6876:  "VMware",
+	8072:  "Net-SNMP",
+	11863: "TP-Link",
+	12356: "Fortinet",
+	14823: "Aruba Networks",
+	14988: "MikroTik",
+	24681: "QNAP",
+	25461: "Palo Alto Networks",
+	25506: "H3C",
+	30065: "Arista Networks",
+	41112: "Ubiquiti",
+}
+
+// vendorFromSysObjectID returns the manufacturer for a sysObjectID based on
+// its enterprise number, or "" if the OID is not under enterprises or the
+// enterprise is not known.
+func vendorFromSysObjectID(objectID string) string {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(objectID, "."), OIDEnterprises+".")
+	if !ok {
+		return ""
+	}
+	num, _, _ := strings.Cut(rest, ".")
+	enterprise, err := strconv.Atoi(num)
+	if err != nil {
+		return ""
+	}
+	return snmpEnterpriseVendors[enterprise]
+}
+
+// SNMPDeviceData is everything collected from one SNMP agent in a single
+// session: the system group, the interface table, and LLDP neighbors.
+type SNMPDeviceData struct {
+	System       *SNMPSystemInfo
+	Manufacturer string // from the sysObjectID enterprise number
+	Interfaces   []SNMPInterface
+	Neighbors    []LLDPNeighbor
+}
+
+// Collect opens one SNMP session to target and gathers system info,
+// interfaces, and LLDP neighbors. Only the system group is required;
+// interface and LLDP failures are logged and leave those fields empty.
+func (c *SNMPCollector) Collect(ctx context.Context, target string, cred CredentialAccessor, credID string) (*SNMPDeviceData, error) {
+	credential, err := cred.GetCredential(ctx, credID)
+	if err != nil {
+		return nil, fmt.Errorf("get credential: %w", err)
+	}
+	return c.collectTarget(ctx, target, credential)
+}
+
+// Poll collects device data from the collector's own target over SNMPv2c
+// with its community string.
+func (c *SNMPCollector) Poll(ctx context.Context) (*SNMPDeviceData, error) {
+	if c.target == "" {
+		return nil, errors.New("SNMP collector has no target")
+	}
+	return c.collectTarget(ctx, c.target, &SNMPCredential{Type: "snmp_v2c", Community: c.community})
+}
+
+// collectTarget opens one session to target with credential and collects.
+// Cancelling ctx aborts requests still in flight.
+func (c *SNMPCollector) collectTarget(ctx context.Context, target string, credential *SNMPCredential) (*SNMPDeviceData, error) {
+	g, err := c.newGoSNMP(target, credential)
+	if err != nil {
+		return nil, fmt.Errorf("configure SNMP: %w", err)
+	}
+	g.Context = ctx
+
+	if err := g.Connect(); err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", target, err)
+	}
+	defer func() { _ = g.Conn.Close() }()
+
+	return c.collect(g, target)
+}
+
+// collect gathers device data over an open session.
+func (c *SNMPCollector) collect(g SNMPSession, target string) (*SNMPDeviceData, error) {
+	info, err := c.readSystemInfo(g)
+	if err != nil {
+		return nil, fmt.Errorf("get system info: %w", err)
+	}
+	data := &SNMPDeviceData{
+		System:       info,
+		Manufacturer: vendorFromSysObjectID(info.ObjectID),
+	}
+
+	data.Interfaces, err = c.readInterfaces(g)
+	if err != nil {
+		c.logger.Warn("failed to get interfaces, continuing with system info only",
+			zap.String("target", target),
+			zap.Error(err),
+		)
+		data.Interfaces = nil
+	}
+
+	// DiscoverNeighbors treats unsupported LLDP-MIB as no neighbors.
+	data.Neighbors, err = NewLLDPCollector(c.logger).DiscoverNeighbors(g)
+	if err != nil {
+		c.logger.Warn("failed to get LLDP neighbors",
+			zap.String("target", target),
+			zap.Error(err),
+		)
+		data.Neighbors = nil
+	}
+
+	return data, nil
+}
+
+// MergeInto fills fields of device that are still empty from the SNMP data:
+// hostname from sysName, manufacturer from sysObjectID, MAC from the first
+// non-loopback interface, and device type when it is unknown. Interfaces
+// and LLDP neighbors have no Device field; callers persist them separately
+// (see LLDPCollector.BuildTopologyFromLLDP).
+func (d *SNMPDeviceData) MergeInto(device *models.Device) {
+	if d.System != nil {
+		if device.Hostname == "" {
+			device.Hostname = d.System.Name
+		}
+		if device.DeviceType == "" || device.DeviceType == models.DeviceTypeUnknown {
+			device.DeviceType = inferDeviceType(d.System)
+		}
+	}
+	if device.Manufacturer == "" {
+		device.Manufacturer = d.Manufacturer
+	}
+	if device.MACAddress == "" {
+		device.MACAddress = d.primaryMAC()
+	}
+}
+
+// primaryMAC returns the first non-loopback, non-zero interface MAC.
+func (d *SNMPDeviceData) primaryMAC() string {
+	for i := range d.Interfaces {
+		// ifType 24 = softwareLoopback.
+		if d.Interfaces[i].Type == 24 {
+			continue
+		}
+		if d.Interfaces[i].PhysAddress != "" && d.Interfaces[i].PhysAddress != "00:00:00:00:00:00" {
+			return d.Interfaces[i].PhysAddress
+		}
+	}
+	return ""
+}
@@ -0,0 +1,274 @@
+package recon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"go.uber.org/zap"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// mockSNMPSession answers Get and BulkWalkAll from a fixed set of PDUs,
+// standing in for a live SNMP agent.
+type mockSNMPSession struct {
+	pdus    []gosnmp.SnmpPDU
+	getErr  error
+	walkErr map[string]error
+}
+
+func (m *mockSNMPSession) Get(oids []string) (*gosnmp.SnmpPacket, error) {
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	pkt := &gosnmp.SnmpPacket{}
+	for _, oid := range oids {
+		pdu := gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.NoSuchObject}
+		for _, p := range m.pdus {
+			if p.Name == "."+oid {
+				pdu = p
+				break
+			}
+		}
+		pkt.Variables = append(pkt.Variables, pdu)
+	}
+	return pkt, nil
+}
+
+func (m *mockSNMPSession) BulkWalkAll(rootOid string) ([]gosnmp.SnmpPDU, error) {
+	if err := m.walkErr[rootOid]; err != nil {
+		return nil, err
+	}
+	prefix := "." + rootOid + "."
+	var out []gosnmp.SnmpPDU
+	for _, p := range m.pdus {
+		if strings.HasPrefix(p.Name, prefix) {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+func octet(oid, v string) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.OctetString, Value: []byte(v)}
+}
+
+func integer(oid string, v int) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.Integer, Value: v}
+}
+
+func objectID(oid, v string) gosnmp.SnmpPDU {
+	return gosnmp.SnmpPDU{Name: "." + oid, Type: gosnmp.ObjectIdentifier, Value: v}
+}
+
+// ciscoSwitchPDUs models a Catalyst switch with two interfaces and one LLDP neighbor.
+func ciscoSwitchPDUs() []gosnmp.SnmpPDU {
+	return []gosnmp.SnmpPDU{
+		octet(OIDSysDescr, "Cisco IOS Software, C2960X Software"),
+		objectID(OIDSysObjectID, ".1.3.6.1.4.1.9.1.1208"),
+		octet(OIDSysName, "core-sw1"),
+		integer(OIDSysServices, 2),
+		{Name: "." + OIDBridgeBase, Type: gosnmp.OctetString, Value: []byte{0x00, 0x1A, 0x2B, 0x3C, 0x4D, 0x5E}},
+		integer(OIDBridgeNumPorts, 48),
+		integer(OIDIfIndex+".1", 1),
+		octet(OIDIfDescr+".1", "Null0"),
+		integer(OIDIfType+".1", 24),
+		integer(OIDIfIndex+".10101", 10101),
+		octet(OIDIfDescr+".10101", "GigabitEthernet1/0/1"),
+		integer(OIDIfType+".10101", 6),
+		{Name: "." + OIDIfPhysAddress + ".10101", Type: gosnmp.OctetString, Value: []byte{0x00, 0x1A, 0x2B, 0x3C, 0x4D, 0x01}},
+		integer(OIDIfOperStatus+".10101", 1),
+		octet(OIDLLDPRemSysName+".0.10101.1", "edge-rtr"),
+		octet(OIDLLDPRemPortID+".0.10101.1", "ge-0/0/1"),
+		{Name: "." + OIDLLDPRemSysCapEnabled + ".0.10101.1", Type: gosnmp.OctetString, Value: []byte{0x00, 0x10}},
+	}
+}
+
+func TestSNMPCollector_Collect(t *testing.T) {
+	tests := []struct {
+		name          string
+		session       *mockSNMPSession
+		wantErr       bool
+		wantName      string
+		wantVendor    string
+		wantIfaces    int
+		wantNeighbors int
+	}{
+		{
+			name:          "cisco switch with lldp",
+			session:       &mockSNMPSession{pdus: ciscoSwitchPDUs()},
+			wantName:      "core-sw1",
+			wantVendor:    "Cisco",
+			wantIfaces:    2,
+			wantNeighbors: 1,
+		},
+		{
+			name: "net-snmp host without lldp",
+			session: &mockSNMPSession{pdus: []gosnmp.SnmpPDU{
+				octet(OIDSysDescr, "Linux nas01 6.1.0-18-amd64"),
+				objectID(OIDSysObjectID, ".1.3.6.1.4.1.8072.3.2.10"),
+				octet(OIDSysName, "nas01"),
+				integer(OIDIfIndex+".2", 2),
+				octet(OIDIfDescr+".2", "eth0"),
+			}},
+			wantName:   "nas01",
+			wantVendor: "Net-SNMP",
+			wantIfaces: 1,
+		},
+		{
+			name: "interface walk fails",
+			session: &mockSNMPSession{
+				pdus:    []gosnmp.SnmpPDU{octet(OIDSysName, "printer"), objectID(OIDSysObjectID, "1.3.6.1.4.1.99999.1")},
+				walkErr: map[string]error{"1.3.6.1.2.1.2.2.1": errors.New("timeout")},
+			},
+			wantName: "printer",
+		},
+		{
+			name:    "system get fails",
+			session: &mockSNMPSession{getErr: errors.New("request timeout")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewSNMPCollector("192.0.2.1", "public", zap.NewNop())
+			data, err := c.collect(tt.session, "192.0.2.1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("collect() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("collect() error = %v", err)
+			}
+			if data.System.Name != tt.wantName {
+				t.Errorf("System.Name = %q, want %q", data.System.Name, tt.wantName)
+			}
+			if data.Manufacturer != tt.wantVendor {
+				t.Errorf("Manufacturer = %q, want %q", data.Manufacturer, tt.wantVendor)
+			}
+			if len(data.Interfaces) != tt.wantIfaces {
+				t.Errorf("Interfaces = %d, want %d", len(data.Interfaces), tt.wantIfaces)
+			}
+			if len(data.Neighbors) != tt.wantNeighbors {
+				t.Errorf("Neighbors = %d, want %d", len(data.Neighbors), tt.wantNeighbors)
+			}
+		})
+	}
+}
+
+func TestSNMPCollector_Collect_LLDPNeighbor(t *testing.T) {
+	c := NewSNMPCollector("192.0.2.1", "public", zap.NewNop())
+	data, err := c.collect(&mockSNMPSession{pdus: ciscoSwitchPDUs()}, "192.0.2.1")
+	if err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+	if len(data.Neighbors) != 1 {
+		t.Fatalf("Neighbors = %d, want 1", len(data.Neighbors))
+	}
+	n := data.Neighbors[0]
+	if n.RemoteSysName != "edge-rtr" || n.RemotePortID != "ge-0/0/1" || n.LocalPort != "10101" {
+		t.Errorf("neighbor = %+v, want edge-rtr ge-0/0/1 on local port 10101", n)
+	}
+	if n.CapEnabled != LLDPCapRouter {
+		t.Errorf("CapEnabled = 0x%02X, want router", n.CapEnabled)
+	}
+}
+
+func TestSNMPDeviceData_MergeInto(t *testing.T) {
+	c := NewSNMPCollector("192.0.2.1", "public", zap.NewNop())
+	data, err := c.collect(&mockSNMPSession{pdus: ciscoSwitchPDUs()}, "192.0.2.1")
+	if err != nil {
+		t.Fatalf("collect() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		device models.Device
+		want   models.Device
+	}{
+		{
+			name:   "empty device takes snmp values",
+			device: models.Device{},
+			want: models.Device{
+				Hostname:     "core-sw1",
+				Manufacturer: "Cisco",
+				MACAddress:   "00:1A:2B:3C:4D:01",
+				DeviceType:   models.DeviceTypeSwitch,
+			},
+		},
+		{
+			name: "existing values are kept",
+			device: models.Device{
+				Hostname:     "sw1.lan",
+				Manufacturer: "Cisco Systems, Inc",
+				MACAddress:   "AA:BB:CC:DD:EE:FF",
+				DeviceType:   models.DeviceTypeRouter,
+			},
+			want: models.Device{
+				Hostname:     "sw1.lan",
+				Manufacturer: "Cisco Systems, Inc",
+				MACAddress:   "AA:BB:CC:DD:EE:FF",
+				DeviceType:   models.DeviceTypeRouter,
+			},
+		},
+		{
+			name:   "unknown type is replaced",
+			device: models.Device{Hostname: "sw1.lan", DeviceType: models.DeviceTypeUnknown},
+			want: models.Device{
+				Hostname:     "sw1.lan",
+				Manufacturer: "Cisco",
+				MACAddress:   "00:1A:2B:3C:4D:01",
+				DeviceType:   models.DeviceTypeSwitch,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.device
+			data.MergeInto(&got)
+			if got.Hostname != tt.want.Hostname || got.Manufacturer != tt.want.Manufacturer ||
+				got.MACAddress != tt.want.MACAddress || got.DeviceType != tt.want.DeviceType {
+				t.Errorf("MergeInto() = {%q %q %q %q}, want {%q %q %q %q}",
+					got.Hostname, got.Manufacturer, got.MACAddress, got.DeviceType,
+					tt.want.Hostname, tt.want.Manufacturer, tt.want.MACAddress, tt.want.DeviceType)
+			}
+		})
+	}
+}
+
+func TestVendorFromSysObjectID(t *testing.T) {
+	tests := []struct {
+		objectID string
+		want     string
+	}{
+		{".1.3.6.1.4.1.9.1.1208", "Cisco"},
+		{"1.3.6.1.4.1.2636.1.1.1.2.29", "Juniper Networks"},
+		{"1.3.6.1.4.1.41112.1.6", "Ubiquiti"},
+		{"1.3.6.1.4.1.14988.1", "MikroTik"},
+		{"1.3.6.1.4.1.99999.1", ""},
+		{"1.3.6.1.2.1.1", ""},
+		{"1.3.6.1.4.1.abc", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.objectID, func(t *testing.T) {
+			if got := vendorFromSysObjectID(tt.objectID); got != tt.want {
+				t.Errorf("vendorFromSysObjectID(%q) = %q, want %q", tt.objectID, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSNMPCollector_Poll_NoTarget(t *testing.T) {
+	c := NewSNMPCollector("", "public", zap.NewNop())
+	if _, err := c.Poll(context.Background()); err == nil {
+		t.Error("Poll() error = nil, want error for a collector without a target")
+	}
+}
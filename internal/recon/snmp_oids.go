@@ -46,3 +46,7 @@ const (
 
 // IF-MIB extensions (1.3.6.1.2.1.31.1.1.1).
 const OIDIfName = "1.3.6.1.2.1.31.1.1.1.1" // ifName (short name like "Gi0/1")
+
+// SNMPv2-SMI enterprises subtree. sysObjectID values for vendor agents
+// start with this prefix followed by the IANA private enterprise number.
+const OIDEnterprises = "1.3.6.1.4.1"
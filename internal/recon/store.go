@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/pkg/models"
 	"github.com/google/uuid"
 )
@@ -53,6 +54,8 @@ type ListDevicesOptions struct {
 	ScanID     string
 	Category   string
 	Owner      string
+
+	IncludeArchived bool // Include archived (soft-deleted) devices.
 }
 
 // UpdateDeviceParams holds partial update fields for a device.
@@ -63,6 +66,8 @@ type UpdateDeviceParams struct {
 	CustomFields *map[string]string `json:"custom_fields,omitempty"`
 	DeviceType   *string            `json:"device_type,omitempty"`
 	Location     *string            `json:"location,omitempty"`
+	Rack         *string            `json:"rack,omitempty"`
+	RackUnit     *int               `json:"rack_unit,omitempty"`
 	Category     *string            `json:"category,omitempty"`
 	PrimaryRole  *string            `json:"primary_role,omitempty"`
 	Owner        *string            `json:"owner,omitempty"`
@@ -78,12 +83,14 @@ type InventorySummary struct {
 	ByType       map[string]int `json:"by_type"`
 }
 
-// DeviceStatusChange records a status transition for a device.
-type DeviceStatusChange struct {
+// DeviceHistoryEntry records a change to one tracked field of a device.
+type DeviceHistoryEntry struct {
 	ID        string    `json:"id"`
 	DeviceID  string    `json:"device_id"`
-	OldStatus string    `json:"old_status"`
-	NewStatus string    `json:"new_status"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	Source    string    `json:"source"`
 	ChangedAt time.Time `json:"changed_at"`
 }
 
@@ -165,6 +172,14 @@ func (s *ReconStore) UpsertDevice(ctx context.Context, device *models.Device) (c
 		if device.Location != "" {
 			location = device.Location
 		}
+		rack := existing.Rack
+		if device.Rack != "" {
+			rack = device.Rack
+		}
+		rackUnit := existing.RackUnit
+		if device.RackUnit != 0 {
+			rackUnit = device.RackUnit
+		}
 		category := existing.Category
 		if device.Category != "" {
 			category = device.Category
@@ -226,13 +241,15 @@ func (s *ReconStore) UpsertDevice(ctx context.Context, device *models.Device) (c
 		_, err = s.db.ExecContext(ctx, `
 			UPDATE recon_devices SET
 				ip_addresses = ?, mac_address = ?, manufacturer = ?,
-				hostname = ?, os = ?, location = ?, category = ?, primary_role = ?, owner = ?, tags = ?,
+				hostname = ?, os = ?, location = ?, rack = ?, rack_unit = ?,
+				category = ?, primary_role = ?, owner = ?, tags = ?,
 				status = ?, discovery_method = ?, device_type = ?, last_seen = ?,
 				classification_confidence = ?, classification_source = ?, classification_signals = ?,
-				connection_type = ?
+				connection_type = ?, archived_at = NULL
 			WHERE id = ?`,
 			string(ipsJSON), mac, manufacturer,
-			hostname, osField, location, category, primaryRole, owner, string(tagsJSON),
+			hostname, osField, location, rack, rackUnit,
+			category, primaryRole, owner, string(tagsJSON),
 			newStatus, string(method), deviceType, now,
 			classConfidence, classSource, classSignals,
 			connType,
@@ -242,11 +259,16 @@ func (s *ReconStore) UpsertDevice(ctx context.Context, device *models.Device) (c
 			return false, fmt.Errorf("update device: %w", err)
 		}
 
-		// Record status change if status actually changed.
-		oldStatus := string(existing.Status)
-		if oldStatus != newStatus {
-			s.recordStatusChange(ctx, existing.ID, oldStatus, newStatus)
-		}
+		// Record the tracked fields this scan changed.
+		after := *existing
+		after.Hostname = hostname
+		after.IPAddresses = merged
+		after.MACAddress = mac
+		after.Manufacturer = manufacturer
+		after.DeviceType = models.DeviceType(deviceType)
+		after.OS = osField
+		after.Status = models.DeviceStatus(newStatus)
+		s.recordDeviceChanges(ctx, existing.ID, services.ChangeSourceScan, services.DiffDevices(existing, &after))
 
 		device.ID = existing.ID
 		return false, nil
@@ -275,14 +297,14 @@ func (s *ReconStore) UpsertDevice(ctx context.Context, device *models.Device) (c
 			id, hostname, ip_addresses, mac_address, manufacturer,
 			device_type, os, status, discovery_method, agent_id,
 			first_seen, last_seen, notes, tags, custom_fields,
-			location, category, primary_role, owner,
+			location, rack, rack_unit, category, primary_role, owner,
 			classification_confidence, classification_source, classification_signals,
 			parent_device_id, network_layer, connection_type
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		device.ID, device.Hostname, string(ipsJSON), device.MACAddress, device.Manufacturer,
 		string(device.DeviceType), device.OS, string(device.Status), string(device.DiscoveryMethod), device.AgentID,
 		now, now, device.Notes, string(tagsJSON), string(cfJSON),
-		device.Location, device.Category, device.PrimaryRole, device.Owner,
+		device.Location, device.Rack, device.RackUnit, device.Category, device.PrimaryRole, device.Owner,
 		device.ClassificationConfidence, device.ClassificationSource, device.ClassificationSignals,
 		device.ParentDeviceID, device.NetworkLayer, connType,
 	)
@@ -300,9 +322,9 @@ func (s *ReconStore) GetDevice(ctx context.Context, id string) (*models.Device,
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
+		parent_device_id, network_layer, connection_type, archived_at
 		FROM recon_devices WHERE id = ?`, id))
 }
 
@@ -312,9 +334,9 @@ func (s *ReconStore) GetDeviceByMAC(ctx context.Context, mac string) (*models.De
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
+		parent_device_id, network_layer, connection_type, archived_at
 		FROM recon_devices WHERE mac_address = ?`, mac))
 }
 
@@ -325,9 +347,9 @@ func (s *ReconStore) GetDeviceByIP(ctx context.Context, ip string) (*models.Devi
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
+		parent_device_id, network_layer, connection_type, archived_at
 		FROM recon_devices WHERE ip_addresses LIKE ?`, "%\""+ip+"\"%"))
 }
 
@@ -337,9 +359,9 @@ func (s *ReconStore) GetDeviceByHostname(ctx context.Context, hostname string) (
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
+		parent_device_id, network_layer, connection_type, archived_at
 		FROM recon_devices WHERE hostname = ?`, hostname))
 }
 
@@ -352,6 +374,9 @@ func (s *ReconStore) ListDevices(ctx context.Context, opts ListDevicesOptions) (
 	// Build WHERE clause.
 	where := "1=1"
 	args := []any{}
+	if !opts.IncludeArchived {
+		where += " AND archived_at IS NULL"
+	}
 	if opts.Status != "" {
 		where += " AND status = ?"
 		args = append(args, opts.Status)
@@ -392,9 +417,9 @@ func (s *ReconStore) ListDevices(ctx context.Context, opts ListDevicesOptions) (
 		"id, hostname, ip_addresses, mac_address, manufacturer, "+
 		"device_type, os, status, discovery_method, agent_id, "+
 		"first_seen, last_seen, notes, tags, custom_fields, "+
-		"location, category, primary_role, owner, "+
+		"location, rack, rack_unit, category, primary_role, owner, "+
 		"classification_confidence, classification_source, classification_signals, "+
-		"parent_device_id, network_layer, connection_type "+
+		"parent_device_id, network_layer, connection_type, archived_at "+
 		"FROM recon_devices WHERE "+where+" ORDER BY last_seen DESC LIMIT ? OFFSET ?",
 		queryArgs...)
 	if err != nil {
@@ -507,10 +532,26 @@ func (s *ReconStore) ListScans(ctx context.Context, limit, offset int) ([]models
 	return scans, rows.Err()
 }
 
-// LinkScanDevice associates a device with a scan.
+// CountScans returns the total number of scans.
+func (s *ReconStore) CountScans(ctx context.Context) (int, error) {
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM recon_scans`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count scans: %w", err)
+	}
+	return n, nil
+}
+
+// LinkScanDevice associates a device with a scan, snapshotting the device's
+// hostname, IPs, and MAC as seen by that scan so later scans can be diffed.
+// Linking the same device again refreshes the snapshot.
 func (s *ReconStore) LinkScanDevice(ctx context.Context, scanID, deviceID string) error {
 	_, err := s.db.ExecContext(ctx, `
-		INSERT OR IGNORE INTO recon_scan_devices (scan_id, device_id) VALUES (?, ?)`,
+		INSERT INTO recon_scan_devices (scan_id, device_id, hostname, ip_addresses, mac_address)
+		SELECT ?, id, hostname, ip_addresses, mac_address FROM recon_devices WHERE id = ?
+		ON CONFLICT(scan_id, device_id) DO UPDATE SET
+			hostname     = excluded.hostname,
+			ip_addresses = excluded.ip_addresses,
+			mac_address  = excluded.mac_address`,
 		scanID, deviceID,
 	)
 	return err
@@ -570,10 +611,10 @@ func (s *ReconStore) FindStaleDevices(ctx context.Context, threshold time.Time)
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
-		FROM recon_devices WHERE status = ? AND last_seen < ?`,
+		parent_device_id, network_layer, connection_type, archived_at
+		FROM recon_devices WHERE status = ? AND last_seen < ? AND archived_at IS NULL`,
 		string(models.DeviceStatusOnline), threshold,
 	)
 	if err != nil {
@@ -613,7 +654,9 @@ func (s *ReconStore) MarkDeviceOffline(ctx context.Context, deviceID string) err
 	}
 
 	if oldStatus != newStatus {
-		s.recordStatusChange(ctx, deviceID, oldStatus, newStatus)
+		s.recordDeviceChanges(ctx, deviceID, services.ChangeSourceScan, []services.DeviceChange{
+			{Field: "status", OldValue: oldStatus, NewValue: newStatus},
+		})
 	}
 	return nil
 }
@@ -638,7 +681,9 @@ func (s *ReconStore) UpdateDeviceStatus(ctx context.Context, deviceID string, st
 	}
 
 	if oldStatus != newStatus {
-		s.recordStatusChange(ctx, deviceID, oldStatus, newStatus)
+		s.recordDeviceChanges(ctx, deviceID, services.ChangeSourceSync, []services.DeviceChange{
+			{Field: "status", OldValue: oldStatus, NewValue: newStatus},
+		})
 	}
 	return nil
 }
@@ -648,17 +693,21 @@ func (s *ReconStore) scanDevice(row *sql.Row) (*models.Device, error) {
 	var d models.Device
 	var ipsJSON, tagsJSON, cfJSON string
 	var dt, status, method string
+	var archivedAt sql.NullTime
 	err := row.Scan(
 		&d.ID, &d.Hostname, &ipsJSON, &d.MACAddress, &d.Manufacturer,
 		&dt, &d.OS, &status, &method, &d.AgentID,
 		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON,
-		&d.Location, &d.Category, &d.PrimaryRole, &d.Owner,
+		&d.Location, &d.Rack, &d.RackUnit, &d.Category, &d.PrimaryRole, &d.Owner,
 		&d.ClassificationConfidence, &d.ClassificationSource, &d.ClassificationSignals,
-		&d.ParentDeviceID, &d.NetworkLayer, &d.ConnectionType,
+		&d.ParentDeviceID, &d.NetworkLayer, &d.ConnectionType, &archivedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if archivedAt.Valid {
+		d.ArchivedAt = &archivedAt.Time
+	}
 	d.DeviceType = models.DeviceType(dt)
 	d.Status = models.DeviceStatus(status)
 	d.DiscoveryMethod = models.DiscoveryMethod(method)
@@ -673,17 +722,21 @@ func (s *ReconStore) scanDeviceRow(rows *sql.Rows) (*models.Device, error) {
 	var d models.Device
 	var ipsJSON, tagsJSON, cfJSON string
 	var dt, status, method string
+	var archivedAt sql.NullTime
 	err := rows.Scan(
 		&d.ID, &d.Hostname, &ipsJSON, &d.MACAddress, &d.Manufacturer,
 		&dt, &d.OS, &status, &method, &d.AgentID,
 		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON,
-		&d.Location, &d.Category, &d.PrimaryRole, &d.Owner,
+		&d.Location, &d.Rack, &d.RackUnit, &d.Category, &d.PrimaryRole, &d.Owner,
 		&d.ClassificationConfidence, &d.ClassificationSource, &d.ClassificationSignals,
-		&d.ParentDeviceID, &d.NetworkLayer, &d.ConnectionType,
+		&d.ParentDeviceID, &d.NetworkLayer, &d.ConnectionType, &archivedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if archivedAt.Valid {
+		d.ArchivedAt = &archivedAt.Time
+	}
 	d.DeviceType = models.DeviceType(dt)
 	d.Status = models.DeviceStatus(status)
 	d.DiscoveryMethod = models.DiscoveryMethod(method)
@@ -693,14 +746,17 @@ func (s *ReconStore) scanDeviceRow(rows *sql.Rows) (*models.Device, error) {
 	return &d, nil
 }
 
-// recordStatusChange inserts a row into recon_device_history.
+// recordDeviceChanges inserts a row into recon_device_history per change.
 // Errors are silently ignored so callers are not disrupted by history failures.
-func (s *ReconStore) recordStatusChange(ctx context.Context, deviceID, oldStatus, newStatus string) {
-	_, _ = s.db.ExecContext(ctx, `
-		INSERT INTO recon_device_history (id, device_id, old_status, new_status, changed_at)
-		VALUES (?, ?, ?, ?, ?)`,
-		uuid.New().String(), deviceID, oldStatus, newStatus, time.Now().UTC(),
-	)
+func (s *ReconStore) recordDeviceChanges(ctx context.Context, deviceID, source string, changes []services.DeviceChange) {
+	now := time.Now().UTC()
+	for _, c := range changes {
+		_, _ = s.db.ExecContext(ctx, `
+			INSERT INTO recon_device_history (id, device_id, field, old_value, new_value, source, changed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			uuid.New().String(), deviceID, c.Field, c.OldValue, c.NewValue, source, now,
+		)
+	}
 }
 
 // UpdateDevice applies a partial update to an existing device.
@@ -754,6 +810,18 @@ func (s *ReconStore) UpdateDevice(ctx context.Context, id string, params UpdateD
 			return fmt.Errorf("update location: %w", err)
 		}
 	}
+	if params.Rack != nil {
+		_, err = s.db.ExecContext(ctx, `UPDATE recon_devices SET rack = ? WHERE id = ?`, *params.Rack, id)
+		if err != nil {
+			return fmt.Errorf("update rack: %w", err)
+		}
+	}
+	if params.RackUnit != nil {
+		_, err = s.db.ExecContext(ctx, `UPDATE recon_devices SET rack_unit = ? WHERE id = ?`, *params.RackUnit, id)
+		if err != nil {
+			return fmt.Errorf("update rack_unit: %w", err)
+		}
+	}
 	if params.Category != nil {
 		_, err = s.db.ExecContext(ctx, `UPDATE recon_devices SET category = ? WHERE id = ?`, *params.Category, id)
 		if err != nil {
@@ -772,6 +840,15 @@ func (s *ReconStore) UpdateDevice(ctx context.Context, id string, params UpdateD
 			return fmt.Errorf("update owner: %w", err)
 		}
 	}
+
+	after := *existing
+	if params.Hostname != nil {
+		after.Hostname = *params.Hostname
+	}
+	if params.DeviceType != nil {
+		after.DeviceType = models.DeviceType(*params.DeviceType)
+	}
+	s.recordDeviceChanges(ctx, id, services.ChangeSourceAPI, services.DiffDevices(existing, &after))
 	return nil
 }
 
@@ -845,14 +922,14 @@ func (s *ReconStore) InsertManualDevice(ctx context.Context, device *models.Devi
 			id, hostname, ip_addresses, mac_address, manufacturer,
 			device_type, os, status, discovery_method, agent_id,
 			first_seen, last_seen, notes, tags, custom_fields,
-			location, category, primary_role, owner,
+			location, rack, rack_unit, category, primary_role, owner,
 			classification_confidence, classification_source, classification_signals,
 			parent_device_id, network_layer, connection_type
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		device.ID, device.Hostname, string(ipsJSON), device.MACAddress, device.Manufacturer,
 		string(device.DeviceType), device.OS, string(device.Status), string(device.DiscoveryMethod), device.AgentID,
 		now, now, device.Notes, string(tagsJSON), string(cfJSON),
-		device.Location, device.Category, device.PrimaryRole, device.Owner,
+		device.Location, device.Rack, device.RackUnit, device.Category, device.PrimaryRole, device.Owner,
 		device.ClassificationConfidence, device.ClassificationSource, device.ClassificationSignals,
 		device.ParentDeviceID, device.NetworkLayer, manualConnType,
 	)
@@ -862,37 +939,46 @@ func (s *ReconStore) InsertManualDevice(ctx context.Context, device *models.Devi
 	return nil
 }
 
-// GetDeviceHistory returns paginated status change history for a device.
-func (s *ReconStore) GetDeviceHistory(ctx context.Context, deviceID string, limit, offset int) ([]DeviceStatusChange, int, error) {
+// GetDeviceHistory returns paginated field change history for a device,
+// newest first. If field is non-empty only changes to that field are
+// returned.
+func (s *ReconStore) GetDeviceHistory(ctx context.Context, deviceID, field string, limit, offset int) ([]DeviceHistoryEntry, int, error) {
 	if limit <= 0 {
 		limit = 50
 	}
 
+	where := `WHERE device_id = ?`
+	args := []any{deviceID}
+	if field != "" {
+		where += ` AND field = ?`
+		args = append(args, field)
+	}
+
 	var total int
 	err := s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM recon_device_history WHERE device_id = ?`, deviceID,
+		`SELECT COUNT(*) FROM recon_device_history `+where, args...,
 	).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("count device history: %w", err)
 	}
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT id, device_id, old_status, new_status, changed_at
+		SELECT id, device_id, field, old_value, new_value, source, changed_at
 		FROM recon_device_history
-		WHERE device_id = ?
-		ORDER BY changed_at DESC
+		`+where+`
+		ORDER BY changed_at DESC, rowid DESC
 		LIMIT ? OFFSET ?`,
-		deviceID, limit, offset,
+		append(args, limit, offset)...,
 	)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list device history: %w", err)
 	}
 	defer rows.Close()
 
-	var changes []DeviceStatusChange
+	var changes []DeviceHistoryEntry
 	for rows.Next() {
-		var c DeviceStatusChange
-		if err := rows.Scan(&c.ID, &c.DeviceID, &c.OldStatus, &c.NewStatus, &c.ChangedAt); err != nil {
+		var c DeviceHistoryEntry
+		if err := rows.Scan(&c.ID, &c.DeviceID, &c.Field, &c.OldValue, &c.NewValue, &c.Source, &c.ChangedAt); err != nil {
 			return nil, 0, fmt.Errorf("scan history row: %w", err)
 		}
 		changes = append(changes, c)
@@ -955,7 +1041,7 @@ func (s *ReconStore) GetInventorySummary(ctx context.Context, staleDays int) (*I
 			COUNT(*),
 			COALESCE(SUM(CASE WHEN status = 'online' THEN 1 ELSE 0 END), 0),
 			COALESCE(SUM(CASE WHEN status = 'offline' THEN 1 ELSE 0 END), 0)
-		FROM recon_devices`,
+		FROM recon_devices WHERE archived_at IS NULL`,
 	).Scan(&summary.TotalDevices, &summary.OnlineCount, &summary.OfflineCount)
 	if err != nil {
 		return nil, fmt.Errorf("inventory counts: %w", err)
@@ -964,7 +1050,7 @@ func (s *ReconStore) GetInventorySummary(ctx context.Context, staleDays int) (*I
 	// Stale count: online devices not seen in staleDays.
 	threshold := time.Now().UTC().Add(-time.Duration(staleDays) * 24 * time.Hour)
 	err = s.db.QueryRowContext(ctx,
-		`SELECT COUNT(*) FROM recon_devices WHERE status = 'online' AND last_seen < ?`,
+		`SELECT COUNT(*) FROM recon_devices WHERE status = 'online' AND last_seen < ? AND archived_at IS NULL`,
 		threshold,
 	).Scan(&summary.StaleCount)
 	if err != nil {
@@ -973,7 +1059,7 @@ func (s *ReconStore) GetInventorySummary(ctx context.Context, staleDays int) (*I
 
 	// Group by category.
 	catRows, err := s.db.QueryContext(ctx,
-		`SELECT category, COUNT(*) FROM recon_devices WHERE category != '' GROUP BY category`)
+		`SELECT category, COUNT(*) FROM recon_devices WHERE category != '' AND archived_at IS NULL GROUP BY category`)
 	if err != nil {
 		return nil, fmt.Errorf("by category: %w", err)
 	}
@@ -992,7 +1078,7 @@ func (s *ReconStore) GetInventorySummary(ctx context.Context, staleDays int) (*I
 
 	// Group by device_type.
 	typeRows, err := s.db.QueryContext(ctx,
-		`SELECT device_type, COUNT(*) FROM recon_devices GROUP BY device_type`)
+		`SELECT device_type, COUNT(*) FROM recon_devices WHERE archived_at IS NULL GROUP BY device_type`)
 	if err != nil {
 		return nil, fmt.Errorf("by type: %w", err)
 	}
@@ -1075,6 +1161,14 @@ func (s *ReconStore) BulkUpdateDevices(ctx context.Context, ids []string, params
 		setClauses = append(setClauses, "location = ?")
 		setArgs = append(setArgs, *params.Location)
 	}
+	if params.Rack != nil {
+		setClauses = append(setClauses, "rack = ?")
+		setArgs = append(setArgs, *params.Rack)
+	}
+	if params.RackUnit != nil {
+		setClauses = append(setClauses, "rack_unit = ?")
+		setArgs = append(setArgs, *params.RackUnit)
+	}
 	if params.Category != nil {
 		setClauses = append(setClauses, "category = ?")
 		setArgs = append(setArgs, *params.Category)
@@ -1469,8 +1563,9 @@ func (s *ReconStore) GetDeviceTree(ctx context.Context) ([]DeviceTreeNode, error
 	rows, err := s.db.QueryContext(ctx, `
 		SELECT d.id, d.hostname, d.device_type, d.status, d.ip_addresses,
 			d.parent_device_id, d.network_layer,
-			(SELECT COUNT(*) FROM recon_devices c WHERE c.parent_device_id = d.id) AS child_count
+			(SELECT COUNT(*) FROM recon_devices c WHERE c.parent_device_id = d.id AND c.archived_at IS NULL) AS child_count
 		FROM recon_devices d
+		WHERE d.archived_at IS NULL
 		ORDER BY d.network_layer ASC, d.hostname ASC`)
 	if err != nil {
 		return nil, fmt.Errorf("get device tree: %w", err)
@@ -1510,10 +1605,10 @@ func (s *ReconStore) ListAllDevices(ctx context.Context) ([]models.Device, error
 		id, hostname, ip_addresses, mac_address, manufacturer,
 		device_type, os, status, discovery_method, agent_id,
 		first_seen, last_seen, notes, tags, custom_fields,
-		location, category, primary_role, owner,
+		location, rack, rack_unit, category, primary_role, owner,
 		classification_confidence, classification_source, classification_signals,
-		parent_device_id, network_layer, connection_type
-		FROM recon_devices`)
+		parent_device_id, network_layer, connection_type, archived_at
+		FROM recon_devices WHERE archived_at IS NULL`)
 	if err != nil {
 		return nil, fmt.Errorf("list all devices: %w", err)
 	}
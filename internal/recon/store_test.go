@@ -633,7 +633,7 @@ func TestGetDeviceHistory_Empty(t *testing.T) {
 	s := testStore(t)
 	ctx := context.Background()
 
-	changes, total, err := s.GetDeviceHistory(ctx, "no-such-device", 50, 0)
+	changes, total, err := s.GetDeviceHistory(ctx, "no-such-device", "", 50, 0)
 	if err != nil {
 		t.Fatalf("GetDeviceHistory: %v", err)
 	}
@@ -663,7 +663,7 @@ func TestGetDeviceHistory_WithChanges(t *testing.T) {
 		t.Fatalf("MarkDeviceOffline: %v", err)
 	}
 
-	changes, total, err := s.GetDeviceHistory(ctx, d.ID, 50, 0)
+	changes, total, err := s.GetDeviceHistory(ctx, d.ID, "", 50, 0)
 	if err != nil {
 		t.Fatalf("GetDeviceHistory: %v", err)
 	}
@@ -673,11 +673,14 @@ func TestGetDeviceHistory_WithChanges(t *testing.T) {
 	if len(changes) != 1 {
 		t.Fatalf("changes = %d, want 1", len(changes))
 	}
-	if changes[0].OldStatus != "online" {
-		t.Errorf("OldStatus = %q, want online", changes[0].OldStatus)
+	if changes[0].Field != "status" {
+		t.Errorf("Field = %q, want status", changes[0].Field)
 	}
-	if changes[0].NewStatus != "offline" {
-		t.Errorf("NewStatus = %q, want offline", changes[0].NewStatus)
+	if changes[0].OldValue != "online" {
+		t.Errorf("OldValue = %q, want online", changes[0].OldValue)
+	}
+	if changes[0].NewValue != "offline" {
+		t.Errorf("NewValue = %q, want offline", changes[0].NewValue)
 	}
 }
 
@@ -737,18 +740,18 @@ func TestUpsertDevice_RecordsStatusChange(t *testing.T) {
 	}
 	_, _ = s.UpsertDevice(ctx, d2)
 
-	changes, total, err := s.GetDeviceHistory(ctx, d.ID, 50, 0)
+	changes, total, err := s.GetDeviceHistory(ctx, d.ID, "", 50, 0)
 	if err != nil {
 		t.Fatalf("GetDeviceHistory: %v", err)
 	}
 	if total != 1 {
 		t.Fatalf("total = %d, want 1", total)
 	}
-	if changes[0].OldStatus != "offline" {
-		t.Errorf("OldStatus = %q, want offline", changes[0].OldStatus)
+	if changes[0].OldValue != "offline" {
+		t.Errorf("OldValue = %q, want offline", changes[0].OldValue)
 	}
-	if changes[0].NewStatus != "online" {
-		t.Errorf("NewStatus = %q, want online", changes[0].NewStatus)
+	if changes[0].NewValue != "online" {
+		t.Errorf("NewValue = %q, want online", changes[0].NewValue)
 	}
 }
 
@@ -0,0 +1,183 @@
+package recon
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Topology export formats accepted by GET /topology?format=.
+const (
+	topologyFormatJSON      = "json"
+	topologyFormatDOT       = "dot"
+	topologyFormatCytoscape = "cytoscape"
+)
+
+// topologyEdgeStyle is how a link type is drawn in exported graphs.
+type topologyEdgeStyle struct {
+	Line  string // solid, dashed, or dotted
+	Color string
+	Width int
+}
+
+// topologyEdgeStyles maps link types to edge styles. Links seen directly
+// (LLDP, switch FDB) are drawn solid; ARP and inferred links are weaker
+// evidence and are drawn dashed and dotted.
+var topologyEdgeStyles = map[string]topologyEdgeStyle{
+	"lldp":     {Line: "solid", Color: "#2563eb", Width: 3},
+	"fdb":      {Line: "solid", Color: "#16a34a", Width: 2},
+	"arp":      {Line: "dashed", Color: "#6b7280", Width: 1},
+	"inferred": {Line: "dotted", Color: "#9ca3af", Width: 1},
+}
+
+// edgeStyle returns the style for a link type, defaulting to a thin solid line.
+func edgeStyle(linkType string) topologyEdgeStyle {
+	if s, ok := topologyEdgeStyles[linkType]; ok {
+		return s
+	}
+	return topologyEdgeStyle{Line: "solid", Color: "#374151", Width: 1}
+}
+
+// nodeLabel returns the display label for a node: hostname, then first IP,
+// then the device ID.
+func nodeLabel(n *TopologyNode) string {
+	if n.Label != "" {
+		return n.Label
+	}
+	if len(n.IPAddresses) > 0 {
+		return n.IPAddresses[0]
+	}
+	return n.ID
+}
+
+// dotQuote returns s as a quoted Graphviz DOT ID.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", "")
+	return `"` + r.Replace(s) + `"`
+}
+
+// writeTopologyDOT writes the graph as a Graphviz digraph. Edges keep the
+// parent -> child direction used by the JSON graph, and each group becomes
+// a labeled cluster.
+func writeTopologyDOT(w io.Writer, g *TopologyGraph) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph topology {")
+	fmt.Fprintln(bw, "  rankdir=TB;")
+	fmt.Fprintln(bw, `  node [shape=box, style=rounded, fontname="Helvetica"];`)
+	fmt.Fprintln(bw, `  edge [arrowhead=none, fontname="Helvetica", fontsize=9];`)
+
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		fmt.Fprintf(bw, "  %s [label=%s, device_type=%s, status=%s];\n",
+			dotQuote(n.ID), dotQuote(nodeLabel(n)),
+			dotQuote(string(n.DeviceType)), dotQuote(string(n.Status)))
+	}
+	for i := range g.Groups {
+		grp := &g.Groups[i]
+		fmt.Fprintf(bw, "  subgraph \"cluster_%d\" {\n", i+1)
+		fmt.Fprintf(bw, "    label=%s;\n", dotQuote(grp.Label))
+		for _, id := range grp.NodeIDs {
+			fmt.Fprintf(bw, "    %s;\n", dotQuote(id))
+		}
+		fmt.Fprintln(bw, "  }")
+	}
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		s := edgeStyle(e.LinkType)
+		fmt.Fprintf(bw, "  %s -> %s [label=%s, style=%s, color=%s, penwidth=%d];\n",
+			dotQuote(e.Source), dotQuote(e.Target), dotQuote(e.LinkType),
+			s.Line, dotQuote(s.Color), s.Width)
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// CytoscapeGraph is the Cytoscape.js elements document returned by
+// GET /topology?format=cytoscape. It can be passed straight to cy.add()
+// or used as the elements field of cy.json().
+type CytoscapeGraph struct {
+	Elements CytoscapeElements `json:"elements"`
+}
+
+// CytoscapeElements groups Cytoscape.js nodes and edges.
+type CytoscapeElements struct {
+	Nodes []CytoscapeElement `json:"nodes"`
+	Edges []CytoscapeElement `json:"edges"`
+}
+
+// CytoscapeElement is a single Cytoscape.js node or edge. Edges carry the
+// link type as a class and their style in data so stylesheets can use
+// selectors such as "edge.lldp" or mappers such as "data(line_style)".
+type CytoscapeElement struct {
+	Data    map[string]any `json:"data"`
+	Classes string         `json:"classes,omitempty"`
+}
+
+// topologyCytoscape converts the graph into Cytoscape.js elements. Groups
+// become compound nodes with the "group" class that parent their members.
+func topologyCytoscape(g *TopologyGraph) *CytoscapeGraph {
+	out := &CytoscapeGraph{Elements: CytoscapeElements{
+		Nodes: make([]CytoscapeElement, 0, len(g.Groups)+len(g.Nodes)),
+		Edges: make([]CytoscapeElement, 0, len(g.Edges)),
+	}}
+
+	for i := range g.Groups {
+		grp := &g.Groups[i]
+		out.Elements.Nodes = append(out.Elements.Nodes, CytoscapeElement{
+			Data:    map[string]any{"id": grp.ID, "label": grp.Label},
+			Classes: "group",
+		})
+	}
+
+	for i := range g.Nodes {
+		n := &g.Nodes[i]
+		data := map[string]any{
+			"id":           n.ID,
+			"label":        nodeLabel(n),
+			"device_type":  n.DeviceType,
+			"status":       n.Status,
+			"ip_addresses": n.IPAddresses,
+		}
+		if n.MACAddress != "" {
+			data["mac_address"] = n.MACAddress
+		}
+		if n.ParentDeviceID != "" {
+			data["parent_device_id"] = n.ParentDeviceID
+		}
+		if n.Group != "" {
+			data["parent"] = n.Group
+		}
+		out.Elements.Nodes = append(out.Elements.Nodes, CytoscapeElement{
+			Data:    data,
+			Classes: string(n.DeviceType),
+		})
+	}
+
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		s := edgeStyle(e.LinkType)
+		id := e.ID
+		if id == "" {
+			id = fmt.Sprintf("edge-%d", i+1)
+		}
+		data := map[string]any{
+			"id":         id,
+			"source":     e.Source,
+			"target":     e.Target,
+			"link_type":  e.LinkType,
+			"line_style": s.Line,
+			"line_color": s.Color,
+			"width":      s.Width,
+		}
+		if e.Speed > 0 {
+			data["speed"] = e.Speed
+		}
+		out.Elements.Edges = append(out.Elements.Edges, CytoscapeElement{
+			Data:    data,
+			Classes: e.LinkType,
+		})
+	}
+	return out
+}
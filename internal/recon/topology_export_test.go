@@ -0,0 +1,225 @@
+package recon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+const dotQuotedID = `"(?:[^"\\]|\\.)*"`
+
+var (
+	dotNodeStmt = regexp.MustCompile(`^(` + dotQuotedID + `) \[(.*)\];$`)
+	dotEdgeStmt = regexp.MustCompile(`^(` + dotQuotedID + `) -> (` + dotQuotedID + `) \[(.*)\];$`)
+	dotAttr     = regexp.MustCompile(`^\s*(\w+)=(` + dotQuotedID + `|[\w.]+)\s*(?:,|$)`)
+)
+
+// parsedDOT is the subset of a DOT document the exporter writes.
+type parsedDOT struct {
+	nodes map[string]map[string]string
+	edges []map[string]string
+}
+
+// parseDOT parses the statements writeTopologyDOT emits, failing the test
+// on anything that is not valid DOT syntax.
+func parseDOT(t *testing.T, doc string) parsedDOT {
+	t.Helper()
+	unquote := func(s string) string {
+		if strings.HasPrefix(s, `"`) {
+			s = s[1 : len(s)-1]
+			s = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\n`, "\n").Replace(s)
+		}
+		return s
+	}
+	parseAttrs := func(list string) map[string]string {
+		attrs := map[string]string{}
+		for list != "" {
+			m := dotAttr.FindStringSubmatch(list)
+			if m == nil {
+				t.Fatalf("invalid attribute list %q", list)
+			}
+			attrs[m[1]] = unquote(m[2])
+			list = list[len(m[0]):]
+		}
+		return attrs
+	}
+
+	out := parsedDOT{nodes: map[string]map[string]string{}}
+	sc := bufio.NewScanner(strings.NewReader(doc))
+	var lines []string
+	for sc.Scan() {
+		lines = append(lines, strings.TrimSpace(sc.Text()))
+	}
+	if len(lines) < 2 || lines[0] != "digraph topology {" || lines[len(lines)-1] != "}" {
+		t.Fatalf("DOT document is not a single digraph block:\n%s", doc)
+	}
+	for _, line := range lines[1 : len(lines)-1] {
+		switch {
+		case strings.HasPrefix(line, "rankdir="), strings.HasPrefix(line, "node ["), strings.HasPrefix(line, "edge ["):
+			continue
+		case dotEdgeStmt.MatchString(line):
+			m := dotEdgeStmt.FindStringSubmatch(line)
+			attrs := parseAttrs(m[3])
+			attrs["source"], attrs["target"] = unquote(m[1]), unquote(m[2])
+			out.edges = append(out.edges, attrs)
+		case dotNodeStmt.MatchString(line):
+			m := dotNodeStmt.FindStringSubmatch(line)
+			out.nodes[unquote(m[1])] = parseAttrs(m[2])
+		default:
+			t.Fatalf("unparseable DOT statement %q", line)
+		}
+	}
+	for _, e := range out.edges {
+		if out.nodes[e["source"]] == nil || out.nodes[e["target"]] == nil {
+			t.Errorf("edge %s -> %s references an undeclared node", e["source"], e["target"])
+		}
+	}
+	return out
+}
+
+func sampleTopologyGraph() *TopologyGraph {
+	return &TopologyGraph{
+		Nodes: []TopologyNode{
+			{ID: "gw", Label: "edge-router", DeviceType: models.DeviceTypeRouter, IPAddresses: []string{"10.0.0.1"}},
+			{ID: "sw", Label: `closet "A" switch`, DeviceType: models.DeviceTypeSwitch},
+			{ID: "nas", IPAddresses: []string{"10.0.0.20"}},
+			{ID: "cam"},
+		},
+		Edges: []TopologyEdge{
+			{ID: "l1", Source: "gw", Target: "sw", LinkType: "lldp", Speed: 1000},
+			{ID: "l2", Source: "sw", Target: "nas", LinkType: "fdb"},
+			{Source: "gw", Target: "cam", LinkType: "inferred"},
+		},
+	}
+}
+
+func TestWriteTopologyDOT(t *testing.T) {
+	var sb strings.Builder
+	if err := writeTopologyDOT(&sb, sampleTopologyGraph()); err != nil {
+		t.Fatalf("writeTopologyDOT: %v", err)
+	}
+	dot := parseDOT(t, sb.String())
+
+	if len(dot.nodes) != 4 || len(dot.edges) != 3 {
+		t.Fatalf("nodes/edges = %d/%d, want 4/3", len(dot.nodes), len(dot.edges))
+	}
+	wantLabels := map[string]string{
+		"gw":  "edge-router",
+		"sw":  `closet "A" switch`,
+		"nas": "10.0.0.20",
+		"cam": "cam",
+	}
+	for id, want := range wantLabels {
+		if got := dot.nodes[id]["label"]; got != want {
+			t.Errorf("node %s label = %q, want %q", id, got, want)
+		}
+	}
+	wantStyles := []string{"solid", "solid", "dotted"}
+	for i, e := range dot.edges {
+		if e["style"] != wantStyles[i] {
+			t.Errorf("edge %d (%s) style = %q, want %q", i, e["label"], e["style"], wantStyles[i])
+		}
+	}
+}
+
+func TestTopologyCytoscape(t *testing.T) {
+	cy := topologyCytoscape(sampleTopologyGraph())
+	if len(cy.Elements.Nodes) != 4 || len(cy.Elements.Edges) != 3 {
+		t.Fatalf("nodes/edges = %d/%d, want 4/3", len(cy.Elements.Nodes), len(cy.Elements.Edges))
+	}
+	if got := cy.Elements.Nodes[2].Data["label"]; got != "10.0.0.20" {
+		t.Errorf("label without hostname = %v, want IP", got)
+	}
+	lldp := cy.Elements.Edges[0]
+	if lldp.Classes != "lldp" || lldp.Data["line_style"] != "solid" || lldp.Data["speed"] != 1000 {
+		t.Errorf("lldp edge = %+v, want class lldp, solid line, speed 1000", lldp)
+	}
+	if id := cy.Elements.Edges[2].Data["id"]; id != "edge-3" {
+		t.Errorf("edge without ID got id %v, want generated edge-3", id)
+	}
+}
+
+func TestHandleTopology_Formats(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	router := &models.Device{
+		IPAddresses: []string{"10.0.0.1"}, MACAddress: "AA:00:00:00:00:01",
+		Hostname: "router", DeviceType: models.DeviceTypeRouter,
+		Status: models.DeviceStatusOnline, DiscoveryMethod: models.DiscoveryARP,
+	}
+	sw := &models.Device{
+		IPAddresses: []string{"10.0.0.2"}, MACAddress: "AA:00:00:00:00:02",
+		DeviceType: models.DeviceTypeSwitch,
+		Status:     models.DeviceStatusOnline, DiscoveryMethod: models.DiscoveryARP,
+	}
+	_, _ = m.store.UpsertDevice(ctx, router)
+	_, _ = m.store.UpsertDevice(ctx, sw)
+	_ = m.store.UpsertTopologyLink(ctx, &TopologyLink{
+		SourceDeviceID: router.ID, TargetDeviceID: sw.ID, LinkType: "arp",
+	})
+
+	get := func(format string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		m.handleTopology(w, httptest.NewRequest("GET", "/topology?format="+format, http.NoBody))
+		return w
+	}
+
+	t.Run("dot", func(t *testing.T) {
+		w := get("dot")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/vnd.graphviz") {
+			t.Errorf("content type = %q, want text/vnd.graphviz", ct)
+		}
+		dot := parseDOT(t, w.Body.String())
+		if len(dot.nodes) != 2 || len(dot.edges) != 1 {
+			t.Fatalf("nodes/edges = %d/%d, want 2/1", len(dot.nodes), len(dot.edges))
+		}
+		if got := dot.nodes[sw.ID]["label"]; got != "10.0.0.2" {
+			t.Errorf("switch label = %q, want IP fallback", got)
+		}
+		if got := dot.edges[0]["style"]; got != "dashed" {
+			t.Errorf("arp edge style = %q, want dashed", got)
+		}
+	})
+
+	t.Run("cytoscape", func(t *testing.T) {
+		w := get("cytoscape")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+		}
+		var cy CytoscapeGraph
+		if err := json.NewDecoder(w.Body).Decode(&cy); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(cy.Elements.Nodes) != 2 || len(cy.Elements.Edges) != 1 {
+			t.Errorf("nodes/edges = %d/%d, want 2/1", len(cy.Elements.Nodes), len(cy.Elements.Edges))
+		}
+	})
+
+	t.Run("json default", func(t *testing.T) {
+		w := get("")
+		var graph TopologyGraph
+		if err := json.NewDecoder(w.Body).Decode(&graph); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(graph.Nodes) != 2 || len(graph.Edges) != 1 {
+			t.Errorf("nodes/edges = %d/%d, want 2/1", len(graph.Nodes), len(graph.Edges))
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if w := get("svg"); w.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package recon
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Topology groupings accepted by GET /topology?group_by=.
+const topologyGroupByLocation = "location"
+
+// TopologyGroup clusters the topology nodes that share a location.
+type TopologyGroup struct {
+	ID      string   `json:"id" example:"location:DC1 Room 2"`
+	Label   string   `json:"label" example:"DC1 Room 2"`
+	NodeIDs []string `json:"node_ids"`
+}
+
+// groupTopologyByLocation clusters nodes by location, ordering each group's
+// members by rack and then rack unit so they read top-down as installed.
+// Nodes without a location stay ungrouped. Edges are not changed, so links
+// between locations are kept.
+func groupTopologyByLocation(g *TopologyGraph) {
+	members := make([]*TopologyNode, 0, len(g.Nodes))
+	for i := range g.Nodes {
+		if g.Nodes[i].Location != "" {
+			members = append(members, &g.Nodes[i])
+		}
+	}
+	slices.SortStableFunc(members, func(a, b *TopologyNode) int {
+		return cmp.Or(
+			cmp.Compare(a.Location, b.Location),
+			cmp.Compare(a.Rack, b.Rack),
+			cmp.Compare(a.RackUnit, b.RackUnit),
+			cmp.Compare(nodeLabel(a), nodeLabel(b)),
+		)
+	})
+
+	g.Groups = []TopologyGroup{}
+	for _, n := range members {
+		last := len(g.Groups) - 1
+		if last < 0 || g.Groups[last].Label != n.Location {
+			g.Groups = append(g.Groups, TopologyGroup{
+				ID:    "location:" + n.Location,
+				Label: n.Location,
+			})
+			last++
+		}
+		n.Group = g.Groups[last].ID
+		g.Groups[last].NodeIDs = append(g.Groups[last].NodeIDs, n.ID)
+	}
+}
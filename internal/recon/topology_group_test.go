@@ -0,0 +1,155 @@
+package recon
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+func TestGroupTopologyByLocation(t *testing.T) {
+	g := TopologyGraph{
+		Nodes: []TopologyNode{
+			{ID: "web-2", Label: "web-2", Location: "DC1", Rack: "A1", RackUnit: 20},
+			{ID: "laptop", Label: "laptop"},
+			{ID: "core", Label: "core", Location: "DC1", Rack: "A1", RackUnit: 40},
+			{ID: "web-1", Label: "web-1", Location: "DC1", Rack: "A1", RackUnit: 10},
+			{ID: "db", Label: "db", Location: "DC1", Rack: "A0", RackUnit: 30},
+			{ID: "ap", Label: "ap", Location: "Office"},
+		},
+		Edges: []TopologyEdge{
+			{Source: "core", Target: "web-1"},
+			{Source: "core", Target: "ap"},
+			{Source: "ap", Target: "laptop"},
+		},
+	}
+	groupTopologyByLocation(&g)
+
+	want := []TopologyGroup{
+		{ID: "location:DC1", Label: "DC1", NodeIDs: []string{"db", "web-1", "web-2", "core"}},
+		{ID: "location:Office", Label: "Office", NodeIDs: []string{"ap"}},
+	}
+	if len(g.Groups) != len(want) {
+		t.Fatalf("groups = %+v, want %+v", g.Groups, want)
+	}
+	for i := range want {
+		if g.Groups[i].ID != want[i].ID || g.Groups[i].Label != want[i].Label ||
+			!slices.Equal(g.Groups[i].NodeIDs, want[i].NodeIDs) {
+			t.Errorf("group %d = %+v, want %+v", i, g.Groups[i], want[i])
+		}
+	}
+
+	wantGroup := map[string]string{
+		"web-1": "location:DC1", "web-2": "location:DC1", "core": "location:DC1",
+		"db": "location:DC1", "ap": "location:Office", "laptop": "",
+	}
+	for _, n := range g.Nodes {
+		if n.Group != wantGroup[n.ID] {
+			t.Errorf("node %s group = %q, want %q", n.ID, n.Group, wantGroup[n.ID])
+		}
+	}
+	if len(g.Edges) != 3 {
+		t.Errorf("edges = %d, want 3", len(g.Edges))
+	}
+}
+
+func TestHandleTopology_GroupByLocation(t *testing.T) {
+	m := newTestModule(t)
+	ctx := context.Background()
+
+	devices := []*models.Device{
+		{
+			IPAddresses: []string{"10.0.0.1"}, MACAddress: "AA:00:00:00:00:01",
+			Hostname: "router", DeviceType: models.DeviceTypeRouter,
+			Location: "DC1", Rack: "A1", RackUnit: 42,
+		},
+		{
+			IPAddresses: []string{"10.0.0.2"}, MACAddress: "AA:00:00:00:00:02",
+			Hostname: "server", DeviceType: models.DeviceTypeServer,
+			Location: "DC1", Rack: "A1", RackUnit: 10,
+		},
+		{
+			IPAddresses: []string{"10.0.0.3"}, MACAddress: "AA:00:00:00:00:03",
+			Hostname: "printer", DeviceType: models.DeviceTypePrinter,
+			Location: "Office",
+		},
+		{
+			IPAddresses: []string{"10.0.0.4"}, MACAddress: "AA:00:00:00:00:04",
+			Hostname: "phone", DeviceType: models.DeviceTypePhone,
+		},
+	}
+	for _, d := range devices {
+		d.Status = models.DeviceStatusOnline
+		d.DiscoveryMethod = models.DiscoveryARP
+		if _, err := m.store.UpsertDevice(ctx, d); err != nil {
+			t.Fatalf("UpsertDevice: %v", err)
+		}
+	}
+	_ = m.store.UpsertTopologyLink(ctx, &TopologyLink{
+		SourceDeviceID: devices[0].ID, TargetDeviceID: devices[1].ID, LinkType: "lldp",
+	})
+
+	get := func(query string) (int, TopologyGraph) {
+		w := httptest.NewRecorder()
+		m.handleTopology(w, httptest.NewRequest("GET", "/topology"+query, http.NoBody))
+		var g TopologyGraph
+		_ = json.NewDecoder(w.Body).Decode(&g)
+		return w.Code, g
+	}
+
+	code, plain := get("")
+	if code != http.StatusOK {
+		t.Fatalf("ungrouped status = %d, want %d", code, http.StatusOK)
+	}
+	if plain.Groups != nil {
+		t.Errorf("ungrouped groups = %+v, want none", plain.Groups)
+	}
+
+	code, grouped := get("?group_by=location")
+	if code != http.StatusOK {
+		t.Fatalf("grouped status = %d, want %d", code, http.StatusOK)
+	}
+	if len(grouped.Groups) != 2 {
+		t.Fatalf("groups = %+v, want DC1 and Office", grouped.Groups)
+	}
+	dc1 := grouped.Groups[0]
+	if dc1.Label != "DC1" || !slices.Equal(dc1.NodeIDs, []string{devices[1].ID, devices[0].ID}) {
+		t.Errorf("DC1 group = %+v, want server then router", dc1)
+	}
+	if office := grouped.Groups[1]; office.Label != "Office" || !slices.Equal(office.NodeIDs, []string{devices[2].ID}) {
+		t.Errorf("Office group = %+v, want printer", office)
+	}
+	if len(grouped.Nodes) != len(plain.Nodes) {
+		t.Errorf("grouped nodes = %d, want %d", len(grouped.Nodes), len(plain.Nodes))
+	}
+
+	// Grouping must not drop or add edges.
+	edgeKey := func(e TopologyEdge) string { return e.Source + ">" + e.Target + ":" + e.LinkType }
+	var plainEdges, groupedEdges []string
+	for _, e := range plain.Edges {
+		plainEdges = append(plainEdges, edgeKey(e))
+	}
+	for _, e := range grouped.Edges {
+		groupedEdges = append(groupedEdges, edgeKey(e))
+	}
+	slices.Sort(plainEdges)
+	slices.Sort(groupedEdges)
+	if len(plainEdges) == 0 || !slices.Equal(plainEdges, groupedEdges) {
+		t.Errorf("grouped edges = %v, want %v", groupedEdges, plainEdges)
+	}
+
+	w := httptest.NewRecorder()
+	m.handleTopology(w, httptest.NewRequest("GET", "/topology?group_by=location&format=dot", http.NoBody))
+	if body := w.Body.String(); !strings.Contains(body, `subgraph "cluster_1"`) || !strings.Contains(body, `label="DC1";`) {
+		t.Errorf("DOT output missing location cluster:\n%s", body)
+	}
+
+	if code, _ := get("?group_by=rack"); code != http.StatusBadRequest {
+		t.Errorf("invalid group_by status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
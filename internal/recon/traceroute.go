@@ -13,15 +13,24 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/icmp"
 	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
 )
 
-// TracerouteHop represents a single hop in a traceroute.
+// TracerouteHop represents a single hop in a traceroute. Each hop is probed
+// Sent times; the RTT fields summarize the probes that got a reply, and
+// RTTMs is their average. Timeout is set when no probe got a reply.
 type TracerouteHop struct {
-	Hop      int    `json:"hop"`
-	IP       string `json:"ip,omitempty" example:"192.168.1.1"`
-	Hostname string `json:"hostname,omitempty" example:"router.local"`
+	Hop      int     `json:"hop"`
+	IP       string  `json:"ip,omitempty" example:"192.168.1.1"`
+	Hostname string  `json:"hostname,omitempty" example:"router.local"`
 	RTTMs    float64 `json:"rtt_ms" example:"1.23"`
-	Timeout  bool   `json:"timeout"`
+	MinRTTMs float64 `json:"min_rtt_ms" example:"0.98"`
+	AvgRTTMs float64 `json:"avg_rtt_ms" example:"1.23"`
+	MaxRTTMs float64 `json:"max_rtt_ms" example:"1.61"`
+	Sent     int     `json:"sent" example:"3"`
+	Received int     `json:"received" example:"3"`
+	LossPct  float64 `json:"loss_pct" example:"0"`
+	Timeout  bool    `json:"timeout"`
 }
 
 // TracerouteResult holds the complete traceroute output.
@@ -35,38 +44,98 @@ type TracerouteResult struct {
 
 // TracerouteRequest is the request body for POST /traceroute.
 type TracerouteRequest struct {
-	Target  string `json:"target" example:"192.168.1.1"`
-	MaxHops int    `json:"max_hops,omitempty" example:"30"`
-	TimeoutMs int  `json:"timeout_ms,omitempty" example:"1000"`
+	Target     string `json:"target" example:"192.168.1.1"`
+	MaxHops    int    `json:"max_hops,omitempty" example:"30"`
+	TimeoutMs  int    `json:"timeout_ms,omitempty" example:"1000"`
+	Count      int    `json:"count,omitempty" example:"3"`
+	IntervalMs int    `json:"interval_ms,omitempty" example:"100"`
 }
 
-// tracerouteRunning tracks whether a traceroute is currently in progress.
-// Used for rate limiting (max 1 concurrent traceroute).
-var tracerouteRunning atomic.Bool
+// icmpFamily holds the per-address-family details needed to send Echo
+// probes and parse replies.
+type icmpFamily struct {
+	proto       int       // IANA protocol number used to parse replies
+	echoRequest icmp.Type // Echo Request type for outgoing probes
+	udpNetwork  string    // unprivileged datagram socket network
+	rawNetwork  string    // privileged raw socket network
+	rawAddr     string    // wildcard listen address for the raw socket
+}
 
-// RunTraceroute performs an ICMP traceroute to the target IP.
-func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs int, logger *zap.Logger) (*TracerouteResult, error) {
-	// Resolve the target to an IP address.
-	targetIP := net.ParseIP(target)
-	if targetIP == nil {
-		addrs, err := net.DefaultResolver.LookupHost(ctx, target)
-		if err != nil {
-			return nil, fmt.Errorf("resolve target %q: %w", target, err)
-		}
-		if len(addrs) == 0 {
-			return nil, fmt.Errorf("no addresses for target %q", target)
-		}
-		targetIP = net.ParseIP(addrs[0])
-		if targetIP == nil {
-			return nil, fmt.Errorf("invalid resolved address %q", addrs[0])
-		}
+var (
+	icmpFamilyV4 = &icmpFamily{
+		proto:       1,
+		echoRequest: ipv4.ICMPTypeEcho,
+		udpNetwork:  "udp4",
+		rawNetwork:  "ip4:icmp",
+		rawAddr:     "0.0.0.0",
+	}
+	icmpFamilyV6 = &icmpFamily{
+		proto:       58,
+		echoRequest: ipv6.ICMPTypeEchoRequest,
+		udpNetwork:  "udp6",
+		rawNetwork:  "ip6:ipv6-icmp",
+		rawAddr:     "::",
+	}
+)
+
+// icmpFamilyFor selects the ICMP family for ip and returns the address in
+// its canonical form (4-byte for IPv4, including IPv4-mapped IPv6).
+func icmpFamilyFor(ip net.IP) (*icmpFamily, net.IP) {
+	if v4 := ip.To4(); v4 != nil {
+		return icmpFamilyV4, v4
+	}
+	return icmpFamilyV6, ip
+}
+
+// tracerouteLimiter bounds how many traceroutes run at once. Each run holds
+// a raw ICMP socket for its duration, so requests over the limit are
+// rejected rather than queued.
+type tracerouteLimiter struct {
+	slots chan struct{}
+}
+
+func newTracerouteLimiter(maxConcurrent int) *tracerouteLimiter {
+	if maxConcurrent <= 0 {
+		maxConcurrent = 1
+	}
+	return &tracerouteLimiter{slots: make(chan struct{}, maxConcurrent)}
+}
+
+// TryAcquire takes a slot without blocking. It reports false when all
+// slots are in use.
+func (l *tracerouteLimiter) TryAcquire() bool {
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
 	}
+}
+
+// Release returns a slot taken by TryAcquire.
+func (l *tracerouteLimiter) Release() {
+	<-l.slots
+}
+
+// Limit returns the maximum number of concurrent traceroutes.
+func (l *tracerouteLimiter) Limit() int {
+	return cap(l.slots)
+}
 
-	// Ensure IPv4.
-	targetIP = targetIP.To4()
-	if targetIP == nil {
-		return nil, fmt.Errorf("only IPv4 targets are supported")
+// tracerouteSeq distinguishes concurrent traceroutes so each uses its own
+// ICMP Echo identifier and cannot claim another run's replies.
+var tracerouteSeq atomic.Uint32
+
+// RunTraceroute performs an ICMP traceroute to the target IP. IPv4 targets
+// use ICMP Echo and IPv6 targets use ICMPv6 Echo; the family follows the
+// target (or the first resolved address for a hostname). Each hop is probed
+// count times, intervalMs apart.
+func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs, count, intervalMs int, logger *zap.Logger) (*TracerouteResult, error) {
+	targetIP, err := resolveTarget(ctx, target)
+	if err != nil {
+		return nil, err
 	}
+	fam, targetIP := icmpFamilyFor(targetIP)
 
 	if maxHops <= 0 {
 		maxHops = 30
@@ -75,6 +144,10 @@ func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs int
 		hopTimeoutMs = 1000
 	}
 	hopTimeout := time.Duration(hopTimeoutMs) * time.Millisecond
+	if count <= 0 {
+		count = 1
+	}
+	interval := time.Duration(max(intervalMs, 0)) * time.Millisecond
 
 	start := time.Now()
 	result := &TracerouteResult{
@@ -83,16 +156,17 @@ func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs int
 	}
 
 	// Open ICMP listener.
-	// On Windows, use "ip4:icmp" (privileged raw socket).
-	// On Linux/macOS, try unprivileged "udp4" first, fall back to "ip4:icmp".
-	conn, network, err := openICMPConn()
+	// On Windows, use a privileged raw socket.
+	// On Linux/macOS, try an unprivileged datagram socket first, fall back to raw.
+	conn, network, err := openICMPConn(fam)
 	if err != nil {
 		return nil, fmt.Errorf("open ICMP connection: %w", err)
 	}
 	defer conn.Close()
 
-	// Create a unique ICMP identifier from our PID (masked to 16 bits).
-	icmpID := os.Getpid() & 0xffff
+	// Create a unique ICMP identifier from our PID and a per-run sequence
+	// (masked to 16 bits).
+	icmpID := (os.Getpid() + int(tracerouteSeq.Add(1))) & 0xffff
 
 	for ttl := 1; ttl <= maxHops; ttl++ {
 		select {
@@ -103,8 +177,20 @@ func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs int
 		default:
 		}
 
-		hop, reached := probeHop(ctx, conn, network, targetIP, ttl, icmpID, ttl, hopTimeout, logger)
-		result.Hops = append(result.Hops, hop)
+		probes := make([]TracerouteHop, 0, count)
+		reached := false
+		for i := 0; i < count; i++ {
+			if i > 0 && !sleepCtx(ctx, interval) {
+				break
+			}
+			// Sequence numbers are unique per probe so late replies to an
+			// earlier probe are not counted against a later one.
+			seq := ((ttl-1)*count + i + 1) & 0xffff
+			probe, ok := probeHop(ctx, conn, fam, network, targetIP, ttl, icmpID, seq, hopTimeout, logger)
+			probes = append(probes, probe)
+			reached = reached || ok
+		}
+		result.Hops = append(result.Hops, summarizeProbes(ttl, probes))
 
 		if reached {
 			result.Reached = true
@@ -121,30 +207,117 @@ func RunTraceroute(ctx context.Context, target string, maxHops, hopTimeoutMs int
 	return result, nil
 }
 
-// openICMPConn opens an ICMP packet connection suitable for the current platform.
-func openICMPConn() (*icmp.PacketConn, string, error) {
+// sleepCtx waits for d, returning false early if ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// summarizeProbes combines the single-probe results for one TTL into a hop
+// with min/avg/max RTT and loss. The hop's IP is that of the first probe to
+// get a reply.
+func summarizeProbes(ttl int, probes []TracerouteHop) TracerouteHop {
+	hop := TracerouteHop{Hop: ttl, Sent: len(probes)}
+	var total float64
+	for _, p := range probes {
+		if p.Timeout {
+			continue
+		}
+		if hop.Received == 0 {
+			hop.IP = p.IP
+			hop.MinRTTMs, hop.MaxRTTMs = p.RTTMs, p.RTTMs
+		}
+		hop.Received++
+		total += p.RTTMs
+		hop.MinRTTMs = min(hop.MinRTTMs, p.RTTMs)
+		hop.MaxRTTMs = max(hop.MaxRTTMs, p.RTTMs)
+	}
+	if hop.Sent > 0 {
+		hop.LossPct = float64(hop.Sent-hop.Received) * 100 / float64(hop.Sent)
+	}
+	if hop.Received == 0 {
+		hop.Timeout = true
+		return hop
+	}
+	hop.AvgRTTMs = total / float64(hop.Received)
+	hop.RTTMs = hop.AvgRTTMs
+	return hop
+}
+
+// resolveTarget returns target as an IP address, resolving a hostname to its
+// first address.
+func resolveTarget(ctx context.Context, target string) (net.IP, error) {
+	if ip := net.ParseIP(target); ip != nil {
+		return ip, nil
+	}
+	addrs, err := net.DefaultResolver.LookupHost(ctx, target)
+	if err != nil {
+		return nil, fmt.Errorf("resolve target %q: %w", target, err)
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses for target %q", target)
+	}
+	ip := net.ParseIP(addrs[0])
+	if ip == nil {
+		return nil, fmt.Errorf("invalid resolved address %q", addrs[0])
+	}
+	return ip, nil
+}
+
+// addrIP returns the IP of a peer address read from an ICMP connection.
+func addrIP(addr net.Addr) string {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP.String()
+	case *net.IPAddr:
+		return a.IP.String()
+	default:
+		return addr.String()
+	}
+}
+
+// openICMPConn opens an ICMP packet connection for the given address family
+// suitable for the current platform.
+func openICMPConn(fam *icmpFamily) (*icmp.PacketConn, string, error) {
 	if runtime.GOOS == "windows" {
-		conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
-		return conn, "ip4:icmp", err
+		conn, err := icmp.ListenPacket(fam.rawNetwork, fam.rawAddr)
+		return conn, fam.rawNetwork, err
 	}
 
-	// Try unprivileged ICMP first (Linux with sysctl net.ipv4.ping_group_range).
-	conn, err := icmp.ListenPacket("udp4", "")
+	// Try unprivileged ICMP first (Linux with sysctl net.ipv4.ping_group_range,
+	// which also governs ICMPv6 datagram sockets).
+	conn, err := icmp.ListenPacket(fam.udpNetwork, "")
 	if err == nil {
-		return conn, "udp4", nil
+		return conn, fam.udpNetwork, nil
 	}
 
 	// Fall back to privileged raw socket.
-	conn, err = icmp.ListenPacket("ip4:icmp", "0.0.0.0")
-	return conn, "ip4:icmp", err
+	conn, err = icmp.ListenPacket(fam.rawNetwork, fam.rawAddr)
+	return conn, fam.rawNetwork, err
 }
 
-// probeHop sends a single ICMP Echo Request with the given TTL and waits for a response.
-func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target net.IP, ttl, id, seq int, timeout time.Duration, logger *zap.Logger) (hop TracerouteHop, reached bool) {
+// probeHop sends a single ICMP Echo Request with the given TTL and waits for
+// a response. The returned hop describes that one probe.
+func probeHop(ctx context.Context, conn *icmp.PacketConn, fam *icmpFamily, network string, target net.IP, ttl, id, seq int, timeout time.Duration, logger *zap.Logger) (hop TracerouteHop, reached bool) {
 	hop.Hop = ttl
 
-	// Set TTL on the connection.
-	if err := conn.IPv4PacketConn().SetTTL(ttl); err != nil {
+	// Set TTL (hop limit for IPv6) on the connection.
+	var err error
+	if fam == icmpFamilyV6 {
+		err = conn.IPv6PacketConn().SetHopLimit(ttl)
+	} else {
+		err = conn.IPv4PacketConn().SetTTL(ttl)
+	}
+	if err != nil {
 		logger.Debug("failed to set TTL", zap.Int("ttl", ttl), zap.Error(err))
 		hop.Timeout = true
 		return hop, false
@@ -152,7 +325,7 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 
 	// Build ICMP Echo Request.
 	msg := &icmp.Message{
-		Type: ipv4.ICMPTypeEcho,
+		Type: fam.echoRequest,
 		Code: 0,
 		Body: &icmp.Echo{
 			ID:   id,
@@ -169,7 +342,7 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 
 	// Determine destination address format based on network type.
 	var dst net.Addr
-	if network == "udp4" {
+	if network == fam.udpNetwork {
 		dst = &net.UDPAddr{IP: target, Port: 0}
 	} else {
 		dst = &net.IPAddr{IP: target}
@@ -206,26 +379,16 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 
 		rtt := time.Since(sendTime)
 
-		// Extract the IP from the peer address.
-		var peerIP string
-		switch p := peer.(type) {
-		case *net.UDPAddr:
-			peerIP = p.IP.String()
-		case *net.IPAddr:
-			peerIP = p.IP.String()
-		default:
-			peerIP = peer.String()
-		}
+		peerIP := addrIP(peer)
 
 		// Parse the ICMP message.
-		proto := 1 // ICMPv4 protocol number for parsing
-		reply, err := icmp.ParseMessage(proto, buf[:n])
+		reply, err := icmp.ParseMessage(fam.proto, buf[:n])
 		if err != nil {
 			continue
 		}
 
 		switch reply.Type {
-		case ipv4.ICMPTypeEchoReply:
+		case ipv4.ICMPTypeEchoReply, ipv6.ICMPTypeEchoReply:
 			// Check if this is our echo reply.
 			if echoReply, ok := reply.Body.(*icmp.Echo); ok {
 				if echoReply.ID == id && echoReply.Seq == seq {
@@ -234,7 +397,7 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 					return hop, true
 				}
 			}
-		case ipv4.ICMPTypeTimeExceeded:
+		case ipv4.ICMPTypeTimeExceeded, ipv6.ICMPTypeTimeExceeded:
 			// Time exceeded -- this is an intermediate router.
 			// Verify the inner payload matches our probe by checking the
 			// encapsulated ICMP Echo Request's ID and Seq.
@@ -243,7 +406,7 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 				hop.RTTMs = float64(rtt.Microseconds()) / 1000.0
 				return hop, false
 			}
-		case ipv4.ICMPTypeDestinationUnreachable:
+		case ipv4.ICMPTypeDestinationUnreachable, ipv6.ICMPTypeDestinationUnreachable:
 			// Destination unreachable can also indicate we hit the target
 			// (e.g., port unreachable on some systems).
 			if matchesProbe(reply, id, seq) {
@@ -264,8 +427,10 @@ func probeHop(ctx context.Context, conn *icmp.PacketConn, network string, target
 // matchesProbe checks whether an ICMP error message (Time Exceeded or
 // Destination Unreachable) contains our original Echo Request in the payload.
 // ICMP error messages include the IP header + first 8 bytes of the original
-// packet that triggered the error.
+// packet that triggered the error; ICMPv6 errors include as much of the
+// original packet as fits.
 func matchesProbe(reply *icmp.Message, expectedID, expectedSeq int) bool {
+	_, v6 := reply.Type.(ipv6.ICMPType)
 	body, ok := reply.Body.(*icmp.TimeExceeded)
 	if !ok {
 		// Try DstUnreach.
@@ -273,8 +438,14 @@ func matchesProbe(reply *icmp.Message, expectedID, expectedSeq int) bool {
 		if !ok2 {
 			return false
 		}
+		if v6 {
+			return matchesPayloadV6(bodyDU.Data, expectedID, expectedSeq)
+		}
 		return matchesPayload(bodyDU.Data, expectedID, expectedSeq)
 	}
+	if v6 {
+		return matchesPayloadV6(body.Data, expectedID, expectedSeq)
+	}
 	return matchesPayload(body.Data, expectedID, expectedSeq)
 }
 
@@ -306,6 +477,30 @@ func matchesPayload(data []byte, expectedID, expectedSeq int) bool {
 	return id == expectedID && seq == expectedSeq
 }
 
+// matchesPayloadV6 is the ICMPv6 counterpart of matchesPayload. The payload
+// starts with the fixed 40-byte IPv6 header; probes carry no extension
+// headers, so the next header must be ICMPv6 followed by an Echo Request.
+func matchesPayloadV6(data []byte, expectedID, expectedSeq int) bool {
+	const ipv6HeaderLen = 40
+	if len(data) < ipv6HeaderLen+8 {
+		return false
+	}
+	// Version in the upper 4 bits, next header at offset 6.
+	if data[0]>>4 != 6 || data[6] != 58 {
+		return false
+	}
+
+	icmpData := data[ipv6HeaderLen:]
+	// Check ICMPv6 type is Echo Request (128).
+	if icmpData[0] != 128 {
+		return false
+	}
+	id := int(binary.BigEndian.Uint16(icmpData[4:6]))
+	seq := int(binary.BigEndian.Uint16(icmpData[6:8]))
+
+	return id == expectedID && seq == expectedSeq
+}
+
 // resolveHostnames performs reverse DNS lookups on hop IPs (best effort).
 func resolveHostnames(hops []TracerouteHop, logger *zap.Logger) {
 	for i := range hops {
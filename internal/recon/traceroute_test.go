@@ -0,0 +1,185 @@
+package recon
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+func TestICMPFamilyFor(t *testing.T) {
+	tests := []struct {
+		name   string
+		ip     string
+		want   *icmpFamily
+		wantIP string
+		ipLen  int
+	}{
+		{"ipv4", "192.168.1.1", icmpFamilyV4, "192.168.1.1", net.IPv4len},
+		{"ipv4-mapped ipv6", "::ffff:10.0.0.1", icmpFamilyV4, "10.0.0.1", net.IPv4len},
+		{"ipv6 global", "2001:db8::1", icmpFamilyV6, "2001:db8::1", net.IPv6len},
+		{"ipv6 link-local", "fe80::1", icmpFamilyV6, "fe80::1", net.IPv6len},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fam, ip := icmpFamilyFor(net.ParseIP(tt.ip))
+			if fam != tt.want {
+				t.Errorf("family proto = %d, want %d", fam.proto, tt.want.proto)
+			}
+			if ip.String() != tt.wantIP || len(ip) != tt.ipLen {
+				t.Errorf("ip = %s (len %d), want %s (len %d)", ip, len(ip), tt.wantIP, tt.ipLen)
+			}
+		})
+	}
+
+	if icmpFamilyV6.udpNetwork != "udp6" || icmpFamilyV6.rawNetwork != "ip6:ipv6-icmp" {
+		t.Errorf("v6 networks = %s/%s, want udp6/ip6:ipv6-icmp", icmpFamilyV6.udpNetwork, icmpFamilyV6.rawNetwork)
+	}
+}
+
+// quotedV6Probe builds the original packet an IPv6 router quotes back in an
+// ICMPv6 error: a 40-byte IPv6 header followed by the Echo Request.
+func quotedV6Probe(t *testing.T, nextHeader byte, echoType icmp.Type, id, seq int) []byte {
+	t.Helper()
+	echo, err := (&icmp.Message{
+		Type: echoType,
+		Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("SubNetree-Traceroute")},
+	}).Marshal(nil)
+	if err != nil {
+		t.Fatalf("marshal echo: %v", err)
+	}
+
+	hdr := make([]byte, 40)
+	hdr[0] = 6 << 4
+	binary.BigEndian.PutUint16(hdr[4:6], uint16(len(echo))) //nolint:gosec // G115: test payload is small
+	hdr[6] = nextHeader
+	hdr[7] = 1 // hop limit expired
+	copy(hdr[8:24], net.ParseIP("2001:db8::10"))
+	copy(hdr[24:40], net.ParseIP("2001:db8::1"))
+	return append(hdr, echo...)
+}
+
+// parsedV6Error marshals an ICMPv6 error carrying data and parses it back,
+// as probeHop would on receipt.
+func parsedV6Error(t *testing.T, typ ipv6.ICMPType, body icmp.MessageBody) *icmp.Message {
+	t.Helper()
+	b, err := (&icmp.Message{Type: typ, Body: body}).Marshal(nil)
+	if err != nil {
+		t.Fatalf("marshal error message: %v", err)
+	}
+	msg, err := icmp.ParseMessage(icmpFamilyV6.proto, b)
+	if err != nil {
+		t.Fatalf("parse error message: %v", err)
+	}
+	return msg
+}
+
+func TestMatchesProbe_IPv6TimeExceeded(t *testing.T) {
+	const id, seq = 0x1234, 7
+
+	tests := []struct {
+		name string
+		data []byte
+		id   int
+		seq  int
+		want bool
+	}{
+		{"matching probe", quotedV6Probe(t, 58, ipv6.ICMPTypeEchoRequest, id, seq), id, seq, true},
+		{"different seq", quotedV6Probe(t, 58, ipv6.ICMPTypeEchoRequest, id, seq), id, seq + 1, false},
+		{"different id", quotedV6Probe(t, 58, ipv6.ICMPTypeEchoRequest, id, seq), id + 1, seq, false},
+		{"not icmpv6 next header", quotedV6Probe(t, 17, ipv6.ICMPTypeEchoRequest, id, seq), id, seq, false},
+		{"echo reply not request", quotedV6Probe(t, 58, ipv6.ICMPTypeEchoReply, id, seq), id, seq, false},
+		{"truncated", quotedV6Probe(t, 58, ipv6.ICMPTypeEchoRequest, id, seq)[:44], id, seq, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := parsedV6Error(t, ipv6.ICMPTypeTimeExceeded, &icmp.TimeExceeded{Data: tt.data})
+			if got := matchesProbe(msg, tt.id, tt.seq); got != tt.want {
+				t.Errorf("matchesProbe() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchesProbe_IPv6DestinationUnreachable(t *testing.T) {
+	msg := parsedV6Error(t, ipv6.ICMPTypeDestinationUnreachable,
+		&icmp.DstUnreach{Data: quotedV6Probe(t, 58, ipv6.ICMPTypeEchoRequest, 42, 3)})
+	if !matchesProbe(msg, 42, 3) {
+		t.Error("matchesProbe() = false for matching ICMPv6 Destination Unreachable")
+	}
+}
+
+func TestMatchesProbe_IPv4TimeExceeded(t *testing.T) {
+	echo, err := (&icmp.Message{
+		Type: ipv4.ICMPTypeEcho,
+		Body: &icmp.Echo{ID: 42, Seq: 3, Data: []byte("SubNetree-Traceroute")},
+	}).Marshal(nil)
+	if err != nil {
+		t.Fatalf("marshal echo: %v", err)
+	}
+	hdr := make([]byte, 20)
+	hdr[0] = 0x45 // version 4, IHL 5
+	hdr[9] = 1    // ICMP
+	data := append(hdr, echo...)
+
+	b, err := (&icmp.Message{Type: ipv4.ICMPTypeTimeExceeded, Body: &icmp.TimeExceeded{Data: data}}).Marshal(nil)
+	if err != nil {
+		t.Fatalf("marshal time exceeded: %v", err)
+	}
+	msg, err := icmp.ParseMessage(icmpFamilyV4.proto, b)
+	if err != nil {
+		t.Fatalf("parse time exceeded: %v", err)
+	}
+
+	if !matchesProbe(msg, 42, 3) {
+		t.Error("matchesProbe() = false for matching ICMPv4 Time Exceeded")
+	}
+	// An IPv4 quote must not be interpreted with the IPv6 layout.
+	if matchesPayloadV6(data, 42, 3) {
+		t.Error("matchesPayloadV6() = true for an IPv4 quote")
+	}
+}
+
+func TestSummarizeProbes(t *testing.T) {
+	reply := func(ip string, rtt float64) TracerouteHop {
+		return TracerouteHop{IP: ip, RTTMs: rtt}
+	}
+	lost := TracerouteHop{Timeout: true}
+
+	tests := []struct {
+		name   string
+		probes []TracerouteHop
+		want   TracerouteHop
+	}{
+		{
+			name:   "single probe",
+			probes: []TracerouteHop{reply("10.0.0.1", 1.5)},
+			want:   TracerouteHop{Hop: 4, IP: "10.0.0.1", RTTMs: 1.5, MinRTTMs: 1.5, AvgRTTMs: 1.5, MaxRTTMs: 1.5, Sent: 1, Received: 1},
+		},
+		{
+			name:   "all replied",
+			probes: []TracerouteHop{reply("10.0.0.1", 2), reply("10.0.0.1", 1), reply("10.0.0.1", 6)},
+			want:   TracerouteHop{Hop: 4, IP: "10.0.0.1", RTTMs: 3, MinRTTMs: 1, AvgRTTMs: 3, MaxRTTMs: 6, Sent: 3, Received: 3},
+		},
+		{
+			name:   "some lost",
+			probes: []TracerouteHop{lost, reply("10.0.0.2", 4), lost, reply("10.0.0.3", 2)},
+			want:   TracerouteHop{Hop: 4, IP: "10.0.0.2", RTTMs: 3, MinRTTMs: 2, AvgRTTMs: 3, MaxRTTMs: 4, Sent: 4, Received: 2, LossPct: 50},
+		},
+		{
+			name:   "all lost",
+			probes: []TracerouteHop{lost, lost, lost},
+			want:   TracerouteHop{Hop: 4, Sent: 3, LossPct: 100, Timeout: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := summarizeProbes(4, tt.probes); got != tt.want {
+				t.Errorf("summarizeProbes() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
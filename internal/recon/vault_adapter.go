@@ -5,13 +5,15 @@ import (
 	"fmt"
 )
 
-// CredentialDecrypter retrieves decrypted credential data from the vault.
+// CredentialDecrypter retrieves decrypted credential data from the vault,
+// along with the credential type recorded for it.
 type CredentialDecrypter interface {
-	DecryptCredential(ctx context.Context, id string) (map[string]any, error)
+	DecryptTypedCredential(ctx context.Context, id string) (credType string, data map[string]any, err error)
 }
 
-// VaultCredentialAdapter implements CredentialAccessor by using the vault
-// to retrieve and decrypt SNMP credentials.
+// VaultCredentialAdapter implements CredentialAccessor and
+// CollectorCredentialResolver by using the vault to retrieve and decrypt
+// SNMP and collector API credentials.
 type VaultCredentialAdapter struct {
 	decrypter CredentialDecrypter
 }
@@ -21,22 +23,20 @@ func NewVaultCredentialAdapter(dec CredentialDecrypter) *VaultCredentialAdapter
 	return &VaultCredentialAdapter{decrypter: dec}
 }
 
-// Compile-time interface guard.
-var _ CredentialAccessor = (*VaultCredentialAdapter)(nil)
+// Compile-time interface guards.
+var (
+	_ CredentialAccessor          = (*VaultCredentialAdapter)(nil)
+	_ CollectorCredentialResolver = (*VaultCredentialAdapter)(nil)
+)
 
 // GetCredential retrieves and parses an SNMP credential from the vault.
 func (a *VaultCredentialAdapter) GetCredential(ctx context.Context, id string) (*SNMPCredential, error) {
-	data, err := a.decrypter.DecryptCredential(ctx, id)
+	credType, data, err := a.decrypter.DecryptTypedCredential(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt credential %s: %w", id, err)
 	}
 
-	cred := &SNMPCredential{}
-
-	// Parse type field.
-	if t, ok := data["type"].(string); ok {
-		cred.Type = t
-	}
+	cred := &SNMPCredential{Type: credType}
 
 	switch cred.Type {
 	case "snmp_v2c":
@@ -74,3 +74,26 @@ func (a *VaultCredentialAdapter) GetCredential(ctx context.Context, id string) (
 
 	return cred, nil
 }
+
+// ResolveCollectorCredential retrieves a collector API credential from the
+// vault. An http_basic credential maps username and password to Username and
+// Secret; an api_key credential maps key to Secret and takes Username from
+// an optional username field.
+func (a *VaultCredentialAdapter) ResolveCollectorCredential(ctx context.Context, id string) (*CollectorCredential, error) {
+	credType, data, err := a.decrypter.DecryptTypedCredential(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt credential %s: %w", id, err)
+	}
+
+	username, _ := data["username"].(string)
+	cred := &CollectorCredential{Username: username}
+	switch credType {
+	case "http_basic":
+		cred.Secret, _ = data["password"].(string)
+	case "api_key":
+		cred.Secret, _ = data["key"].(string)
+	default:
+		return nil, fmt.Errorf("unsupported credential type for collector: %s", credType)
+	}
+	return cred, nil
+}
@@ -6,23 +6,25 @@ import (
 	"testing"
 )
 
-// mockDecrypter implements CredentialDecrypter for testing.
+// mockDecrypter implements CredentialDecrypter for testing. Like the vault,
+// it keeps the credential type apart from the decrypted data.
 type mockDecrypter struct {
-	data map[string]any
-	err  error
+	credType string
+	data     map[string]any
+	err      error
 }
 
-func (m *mockDecrypter) DecryptCredential(_ context.Context, _ string) (map[string]any, error) {
+func (m *mockDecrypter) DecryptTypedCredential(_ context.Context, _ string) (string, map[string]any, error) {
 	if m.err != nil {
-		return nil, m.err
+		return "", nil, m.err
 	}
-	return m.data, nil
+	return m.credType, m.data, nil
 }
 
 func TestVaultCredentialAdapter_SNMPv2c(t *testing.T) {
 	dec := &mockDecrypter{
+		credType: "snmp_v2c",
 		data: map[string]any{
-			"type":      "snmp_v2c",
 			"community": "public",
 		},
 	}
@@ -43,8 +45,8 @@ func TestVaultCredentialAdapter_SNMPv2c(t *testing.T) {
 
 func TestVaultCredentialAdapter_SNMPv3(t *testing.T) {
 	dec := &mockDecrypter{
+		credType: "snmp_v3",
 		data: map[string]any{
-			"type":                     "snmp_v3",
 			"username":                 "admin",
 			"auth_protocol":            "SHA-256",
 			"auth_passphrase":          "secret123",
@@ -93,8 +95,10 @@ func TestVaultCredentialAdapter_SNMPv3(t *testing.T) {
 
 func TestVaultCredentialAdapter_UnsupportedType(t *testing.T) {
 	dec := &mockDecrypter{
+		credType: "ssh_key",
 		data: map[string]any{
-			"type": "ssh_key",
+			"username":    "admin",
+			"private_key": "key",
 		},
 	}
 
@@ -117,7 +121,55 @@ func TestVaultCredentialAdapter_DecryptError(t *testing.T) {
 	}
 }
 
+func TestVaultCredentialAdapter_CollectorCredential(t *testing.T) {
+	tests := []struct {
+		name     string
+		credType string
+		data     map[string]any
+		want     CollectorCredential
+		wantErr  bool
+	}{
+		{
+			name:     "http_basic",
+			credType: "http_basic",
+			data:     map[string]any{"username": "root@pam!inv", "password": "s3cret"},
+			want:     CollectorCredential{Username: "root@pam!inv", Secret: "s3cret"},
+		},
+		{
+			name:     "api_key with username",
+			credType: "api_key",
+			data:     map[string]any{"key": "k3y", "username": "root@pam!inv"},
+			want:     CollectorCredential{Username: "root@pam!inv", Secret: "k3y"},
+		},
+		{
+			name:     "unsupported type",
+			credType: "snmp_v2c",
+			data:     map[string]any{"community": "public"},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := NewVaultCredentialAdapter(&mockDecrypter{credType: tt.credType, data: tt.data})
+			cred, err := adapter.ResolveCollectorCredential(context.Background(), "cred-5")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if *cred != tt.want {
+				t.Errorf("credential = %+v, want %+v", *cred, tt.want)
+			}
+		})
+	}
+}
+
 func TestVaultCredentialAdapter_InterfaceGuard(t *testing.T) {
 	// Verify compile-time interface guard works.
 	var _ CredentialAccessor = (*VaultCredentialAdapter)(nil)
+	var _ CollectorCredentialResolver = (*VaultCredentialAdapter)(nil)
 }
@@ -0,0 +1,504 @@
+package recon
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.uber.org/zap"
+)
+
+// VSphereCollector collects host, VM, and datastore inventory from a vCenter
+// or standalone ESXi endpoint. It speaks the vim25 SOAP API at /sdk directly
+// over net/http (the same approach as ProxmoxCollector) rather than pulling
+// in govmomi; only the handful of calls needed for inventory are used:
+// RetrieveServiceContent, Login, CreateContainerView, and
+// RetrievePropertiesEx. govmomi would add a large dependency tree (its
+// generated vim25 types alone are several megabytes) for those few calls,
+// and hand-written requests keep the collector testable against the same
+// canned-response httptest servers as the Proxmox collector.
+type VSphereCollector struct {
+	baseURL    string
+	username   string
+	password   string //nolint:gosec // G101: field name, not a credential
+	httpClient *http.Client
+	logger     *zap.Logger
+
+	mu      sync.Mutex
+	content *vsphereServiceContent // set after a successful login
+}
+
+// VSphereHost represents an ESXi HostSystem.
+type VSphereHost struct {
+	ID              string `json:"id"` // managed object ID, e.g. "host-12"
+	Name            string `json:"name"`
+	ConnectionState string `json:"connection_state"` // "connected", "disconnected", "notResponding"
+	PowerState      string `json:"power_state"`      // "poweredOn", "poweredOff", "standBy", "unknown"
+	Vendor          string `json:"vendor"`
+	Model           string `json:"model"`
+	CPUModel        string `json:"cpu_model"`
+	CPUCores        int    `json:"cpu_cores"`
+	CPUThreads      int    `json:"cpu_threads"`
+	CPUMhz          int    `json:"cpu_mhz"`
+	MemoryBytes     int64  `json:"memory_bytes"`
+}
+
+// VSphereVM represents a VirtualMachine.
+type VSphereVM struct {
+	ID         string `json:"id"` // managed object ID, e.g. "vm-42"
+	Name       string `json:"name"`
+	PowerState string `json:"power_state"` // "poweredOn", "poweredOff", "suspended"
+	NumCPU     int    `json:"num_cpu"`
+	MemoryMB   int    `json:"memory_mb"`
+	GuestOS    string `json:"guest_os"`
+	GuestIP    string `json:"guest_ip"`
+	HostID     string `json:"host_id"` // managed object ID of the running host
+}
+
+// VSphereDatastore represents a Datastore.
+type VSphereDatastore struct {
+	ID            string `json:"id"` // managed object ID, e.g. "datastore-7"
+	Name          string `json:"name"`
+	Type          string `json:"type"` // "VMFS", "NFS", "vsan", ...
+	CapacityBytes int64  `json:"capacity_bytes"`
+	FreeBytes     int64  `json:"free_bytes"`
+	Accessible    bool   `json:"accessible"`
+}
+
+// Property paths requested for each managed object type.
+var (
+	vsphereHostProps = []string{
+		"name",
+		"runtime.connectionState",
+		"runtime.powerState",
+		"summary.hardware.vendor",
+		"summary.hardware.model",
+		"summary.hardware.cpuModel",
+		"summary.hardware.numCpuCores",
+		"summary.hardware.numCpuThreads",
+		"summary.hardware.cpuMhz",
+		"summary.hardware.memorySize",
+	}
+	vsphereVMProps = []string{
+		"name",
+		"runtime.powerState",
+		"runtime.host",
+		"config.hardware.numCPU",
+		"config.hardware.memoryMB",
+		"config.guestFullName",
+		"guest.ipAddress",
+	}
+	vsphereDatastoreProps = []string{
+		"summary.name",
+		"summary.type",
+		"summary.capacity",
+		"summary.freeSpace",
+		"summary.accessible",
+	}
+)
+
+// vsphereMoRef is a vim25 ManagedObjectReference.
+type vsphereMoRef struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// vsphereServiceContent holds the service singletons used by the collector.
+type vsphereServiceContent struct {
+	RootFolder        vsphereMoRef `xml:"returnval>rootFolder"`
+	PropertyCollector vsphereMoRef `xml:"returnval>propertyCollector"`
+	ViewManager       vsphereMoRef `xml:"returnval>viewManager"`
+	SessionManager    vsphereMoRef `xml:"returnval>sessionManager"`
+}
+
+// vsphereObjectContent is one object from a RetrievePropertiesEx result.
+type vsphereObjectContent struct {
+	Obj     vsphereMoRef `xml:"obj"`
+	PropSet []struct {
+		Name string `xml:"name"`
+		Val  string `xml:"val"`
+	} `xml:"propSet"`
+}
+
+// vsphereRetrieveResult is the body of a (Continue)RetrievePropertiesEx response.
+type vsphereRetrieveResult struct {
+	Token   string                 `xml:"returnval>token"`
+	Objects []vsphereObjectContent `xml:"returnval>objects"`
+}
+
+// errVSphereNotAuthenticated is wrapped by call when the server rejects the
+// session with a NotAuthenticated fault, for example after it expired.
+var errVSphereNotAuthenticated = errors.New("vsphere session not authenticated")
+
+// vsphereEnvelope is the SOAP response envelope. The response element is
+// kept as raw XML and decoded by the caller.
+type vsphereEnvelope struct {
+	Body struct {
+		Fault *struct {
+			FaultString string `xml:"faultstring"`
+			Detail      struct {
+				Inner string `xml:",innerxml"`
+			} `xml:"detail"`
+		} `xml:"Fault"`
+		Inner []byte `xml:",innerxml"`
+	} `xml:"Body"`
+}
+
+// NewVSphereCollector creates a new collector for a vCenter or ESXi endpoint.
+// baseURL is the server root (e.g. "https://vcenter.lan"); requests go to
+// baseURL + "/sdk". The session is established lazily on first use.
+func NewVSphereCollector(baseURL, username, password string, logger *zap.Logger) *VSphereCollector {
+	jar, _ := cookiejar.New(nil) // only errors on a non-nil options value
+	return &VSphereCollector{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		username: username,
+		password: password,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+			Jar:     jar,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					MinVersion: tls.VersionTLS12,
+					//nolint:gosec // G402: vCenter and ESXi ship with self-signed certs by default.
+					InsecureSkipVerify: true,
+				},
+			},
+		},
+		logger: logger,
+	}
+}
+
+// CollectHosts returns all ESXi hosts visible to the session.
+func (c *VSphereCollector) CollectHosts(ctx context.Context) ([]VSphereHost, error) {
+	objects, err := c.retrieveAll(ctx, "HostSystem", vsphereHostProps)
+	if err != nil {
+		return nil, fmt.Errorf("list hosts: %w", err)
+	}
+
+	hosts := make([]VSphereHost, 0, len(objects))
+	for _, obj := range objects {
+		p := obj.props()
+		hosts = append(hosts, VSphereHost{
+			ID:              obj.Obj.Value,
+			Name:            p["name"],
+			ConnectionState: p["runtime.connectionState"],
+			PowerState:      p["runtime.powerState"],
+			Vendor:          p["summary.hardware.vendor"],
+			Model:           p["summary.hardware.model"],
+			CPUModel:        p["summary.hardware.cpuModel"],
+			CPUCores:        atoiOrZero(p["summary.hardware.numCpuCores"]),
+			CPUThreads:      atoiOrZero(p["summary.hardware.numCpuThreads"]),
+			CPUMhz:          atoiOrZero(p["summary.hardware.cpuMhz"]),
+			MemoryBytes:     parseInt64OrZero(p["summary.hardware.memorySize"]),
+		})
+	}
+	return hosts, nil
+}
+
+// CollectVMs returns all virtual machines visible to the session.
+func (c *VSphereCollector) CollectVMs(ctx context.Context) ([]VSphereVM, error) {
+	objects, err := c.retrieveAll(ctx, "VirtualMachine", vsphereVMProps)
+	if err != nil {
+		return nil, fmt.Errorf("list vms: %w", err)
+	}
+
+	vms := make([]VSphereVM, 0, len(objects))
+	for _, obj := range objects {
+		p := obj.props()
+		vms = append(vms, VSphereVM{
+			ID:         obj.Obj.Value,
+			Name:       p["name"],
+			PowerState: p["runtime.powerState"],
+			NumCPU:     atoiOrZero(p["config.hardware.numCPU"]),
+			MemoryMB:   atoiOrZero(p["config.hardware.memoryMB"]),
+			GuestOS:    p["config.guestFullName"],
+			GuestIP:    p["guest.ipAddress"],
+			HostID:     p["runtime.host"],
+		})
+	}
+	return vms, nil
+}
+
+// CollectDatastores returns all datastores visible to the session.
+func (c *VSphereCollector) CollectDatastores(ctx context.Context) ([]VSphereDatastore, error) {
+	objects, err := c.retrieveAll(ctx, "Datastore", vsphereDatastoreProps)
+	if err != nil {
+		return nil, fmt.Errorf("list datastores: %w", err)
+	}
+
+	stores := make([]VSphereDatastore, 0, len(objects))
+	for _, obj := range objects {
+		p := obj.props()
+		stores = append(stores, VSphereDatastore{
+			ID:            obj.Obj.Value,
+			Name:          p["summary.name"],
+			Type:          p["summary.type"],
+			CapacityBytes: parseInt64OrZero(p["summary.capacity"]),
+			FreeBytes:     parseInt64OrZero(p["summary.freeSpace"]),
+			Accessible:    p["summary.accessible"] == "true",
+		})
+	}
+	return stores, nil
+}
+
+// Hardware maps the host onto the hardware profile used for Proxmox nodes.
+func (h *VSphereHost) Hardware() *models.DeviceHardware {
+	now := time.Now().UTC()
+	return &models.DeviceHardware{
+		Hostname:           h.Name,
+		CPUModel:           h.CPUModel,
+		CPUCores:           h.CPUCores,
+		CPUThreads:         h.CPUThreads,
+		RAMTotalMB:         int(h.MemoryBytes / (1024 * 1024)),
+		PlatformType:       "baremetal",
+		Hypervisor:         "vmware",
+		SystemManufacturer: h.Vendor,
+		SystemModel:        h.Model,
+		CollectionSource:   "vsphere-api",
+		CollectedAt:        &now,
+	}
+}
+
+// Hardware maps the VM onto the hardware profile used for Proxmox guests.
+// VMHostID holds the vSphere host managed object ID, not a device ID.
+func (v *VSphereVM) Hardware() *models.DeviceHardware {
+	now := time.Now().UTC()
+	return &models.DeviceHardware{
+		Hostname:         v.Name,
+		OSName:           v.GuestOS,
+		CPUCores:         v.NumCPU,
+		RAMTotalMB:       v.MemoryMB,
+		PlatformType:     "vm",
+		Hypervisor:       "vmware",
+		VMHostID:         v.HostID,
+		CollectionSource: "vsphere-api",
+		CollectedAt:      &now,
+	}
+}
+
+// GuestStatus translates the VM power state into the "running"/"stopped"
+// vocabulary the Proxmox guest sync uses to derive device status.
+func (v *VSphereVM) GuestStatus() string {
+	if v.PowerState == "poweredOn" {
+		return "running"
+	}
+	return "stopped"
+}
+
+// Logout ends the vSphere session, if one is open.
+func (c *VSphereCollector) Logout(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.content == nil {
+		return nil
+	}
+	body := `<Logout>` + moRefXML("_this", c.content.SessionManager) + `</Logout>`
+	c.content = nil
+	if err := c.call(ctx, body, nil); err != nil {
+		return fmt.Errorf("logout: %w", err)
+	}
+	return nil
+}
+
+// session returns the service content, logging in on first use.
+func (c *VSphereCollector) session(ctx context.Context) (*vsphereServiceContent, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.content != nil {
+		return c.content, nil
+	}
+
+	var sc vsphereServiceContent
+	err := c.call(ctx,
+		`<RetrieveServiceContent><_this type="ServiceInstance">ServiceInstance</_this></RetrieveServiceContent>`,
+		&sc)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve service content: %w", err)
+	}
+
+	login := `<Login>` + moRefXML("_this", sc.SessionManager) +
+		`<userName>` + xmlEscape(c.username) + `</userName>` +
+		`<password>` + xmlEscape(c.password) + `</password></Login>`
+	if err := c.call(ctx, login, nil); err != nil {
+		return nil, fmt.Errorf("login: %w", err)
+	}
+
+	c.content = &sc
+	return c.content, nil
+}
+
+// dropSession forgets the cached session if it is still sc, so the next
+// call to session logs in again.
+func (c *VSphereCollector) dropSession(sc *vsphereServiceContent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.content == sc {
+		c.content = nil
+	}
+}
+
+// retrieveAll returns the requested properties for every object of objType
+// under the root folder, following continuation tokens. If the server no
+// longer accepts the cached session it logs in again and retries once.
+func (c *VSphereCollector) retrieveAll(ctx context.Context, objType string, props []string) ([]vsphereObjectContent, error) {
+	sc, err := c.session(ctx)
+	if err != nil {
+		return nil, err
+	}
+	objects, err := c.retrieveWith(ctx, sc, objType, props)
+	if !errors.Is(err, errVSphereNotAuthenticated) {
+		return objects, err
+	}
+
+	c.logger.Info("vsphere session no longer authenticated, logging in again", zap.String("url", c.baseURL))
+	c.dropSession(sc)
+	if sc, err = c.session(ctx); err != nil {
+		return nil, err
+	}
+	return c.retrieveWith(ctx, sc, objType, props)
+}
+
+// retrieveWith performs retrieveAll using the given session.
+func (c *VSphereCollector) retrieveWith(ctx context.Context, sc *vsphereServiceContent, objType string, props []string) ([]vsphereObjectContent, error) {
+	var view struct {
+		Returnval vsphereMoRef `xml:"returnval"`
+	}
+	err := c.call(ctx, `<CreateContainerView>`+moRefXML("_this", sc.ViewManager)+
+		moRefXML("container", sc.RootFolder)+
+		`<type>`+objType+`</type><recursive>true</recursive></CreateContainerView>`, &view)
+	if err != nil {
+		return nil, fmt.Errorf("create container view: %w", err)
+	}
+	defer func() {
+		if dErr := c.call(ctx, `<DestroyView>`+moRefXML("_this", view.Returnval)+`</DestroyView>`, nil); dErr != nil {
+			c.logger.Debug("failed to destroy vsphere container view", zap.Error(dErr))
+		}
+	}()
+
+	var spec strings.Builder
+	spec.WriteString(`<RetrievePropertiesEx>`)
+	spec.WriteString(moRefXML("_this", sc.PropertyCollector))
+	spec.WriteString(`<specSet><propSet><type>` + objType + `</type>`)
+	for _, p := range props {
+		spec.WriteString(`<pathSet>` + p + `</pathSet>`)
+	}
+	spec.WriteString(`</propSet><objectSet>`)
+	spec.WriteString(moRefXML("obj", view.Returnval))
+	spec.WriteString(`<skip>true</skip><selectSet xsi:type="TraversalSpec">` +
+		`<name>traverseView</name><type>ContainerView</type><path>view</path><skip>false</skip>` +
+		`</selectSet></objectSet></specSet><options/></RetrievePropertiesEx>`)
+
+	var res vsphereRetrieveResult
+	if err := c.call(ctx, spec.String(), &res); err != nil {
+		return nil, fmt.Errorf("retrieve properties: %w", err)
+	}
+	objects := res.Objects
+
+	for res.Token != "" {
+		token := res.Token
+		res = vsphereRetrieveResult{}
+		err := c.call(ctx, `<ContinueRetrievePropertiesEx>`+moRefXML("_this", sc.PropertyCollector)+
+			`<token>`+xmlEscape(token)+`</token></ContinueRetrievePropertiesEx>`, &res)
+		if err != nil {
+			return nil, fmt.Errorf("continue retrieve properties: %w", err)
+		}
+		objects = append(objects, res.Objects...)
+	}
+	return objects, nil
+}
+
+// call posts a SOAP request to /sdk and decodes the response element into
+// out. A nil out discards the response.
+func (c *VSphereCollector) call(ctx context.Context, body string, out any) error {
+	envelope := `<?xml version="1.0" encoding="UTF-8"?>` +
+		`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"` +
+		` xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns="urn:vim25">` +
+		`<soapenv:Body>` + body + `</soapenv:Body></soapenv:Envelope>`
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/sdk", strings.NewReader(envelope))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/xml; charset=utf-8")
+	req.Header.Set("SOAPAction", "urn:vim25/7.0")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	// Faults come back as HTTP 500 with a SOAP envelope, so try to decode
+	// before looking at the status code.
+	var env vsphereEnvelope
+	if xErr := xml.Unmarshal(respBody, &env); xErr != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("vsphere API returned status %d", resp.StatusCode)
+		}
+		return fmt.Errorf("parse response envelope: %w", xErr)
+	}
+	if env.Body.Fault != nil {
+		if strings.Contains(env.Body.Fault.Detail.Inner, "NotAuthenticated") {
+			return fmt.Errorf("vsphere fault: %s: %w", env.Body.Fault.FaultString, errVSphereNotAuthenticated)
+		}
+		return fmt.Errorf("vsphere fault: %s", env.Body.Fault.FaultString)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vsphere API returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := xml.Unmarshal(bytes.TrimSpace(env.Body.Inner), out); err != nil {
+		return fmt.Errorf("parse response: %w", err)
+	}
+	return nil
+}
+
+// props flattens an object's property set into a path-to-value map.
+func (o *vsphereObjectContent) props() map[string]string {
+	m := make(map[string]string, len(o.PropSet))
+	for _, p := range o.PropSet {
+		m[p.Name] = strings.TrimSpace(p.Val)
+	}
+	return m
+}
+
+// moRefXML renders a ManagedObjectReference element.
+func moRefXML(elem string, ref vsphereMoRef) string {
+	return `<` + elem + ` type="` + xmlEscape(ref.Type) + `">` + xmlEscape(ref.Value) + `</` + elem + `>`
+}
+
+// xmlEscape escapes s for use in XML text or attribute values.
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func atoiOrZero(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func parseInt64OrZero(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
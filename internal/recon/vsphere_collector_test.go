@@ -0,0 +1,302 @@
+package recon
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const vsphereServiceContentXML = `<RetrieveServiceContentResponse xmlns="urn:vim25"><returnval>
+<rootFolder type="Folder">group-d1</rootFolder>
+<propertyCollector type="PropertyCollector">propertyCollector</propertyCollector>
+<viewManager type="ViewManager">ViewManager</viewManager>
+<sessionManager type="SessionManager">SessionManager</sessionManager>
+</returnval></RetrieveServiceContentResponse>`
+
+// vsphereTestHandler serves a minimal vim25 SOAP endpoint. Requests are
+// dispatched on the body element name; RetrievePropertiesEx and
+// ContinueRetrievePropertiesEx responses come from props keyed by element.
+func vsphereTestHandler(t *testing.T, props map[string]string) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/sdk" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		body := string(b)
+
+		var inner string
+		switch {
+		case strings.Contains(body, "<RetrieveServiceContent>"):
+			inner = vsphereServiceContentXML
+		case strings.Contains(body, "<Login>"):
+			if !strings.Contains(body, "<userName>admin@vsphere.local</userName>") ||
+				!strings.Contains(body, "<password>s3cret&amp;</password>") {
+				w.WriteHeader(http.StatusInternalServerError)
+				inner = `<soapenv:Fault><faultcode>ServerFaultCode</faultcode>` +
+					`<faultstring>Cannot complete login due to an incorrect user name or password.</faultstring></soapenv:Fault>`
+				break
+			}
+			http.SetCookie(w, &http.Cookie{Name: "vmware_soap_session", Value: "abc"})
+			inner = `<LoginResponse xmlns="urn:vim25"><returnval><key>s1</key></returnval></LoginResponse>`
+		case strings.Contains(body, "<CreateContainerView>"):
+			if _, err := r.Cookie("vmware_soap_session"); err != nil {
+				t.Error("CreateContainerView sent without session cookie")
+			}
+			inner = `<CreateContainerViewResponse xmlns="urn:vim25"><returnval type="ContainerView">session[1]view</returnval></CreateContainerViewResponse>`
+		case strings.Contains(body, "<DestroyView>"):
+			inner = `<DestroyViewResponse xmlns="urn:vim25"></DestroyViewResponse>`
+		case strings.Contains(body, "<ContinueRetrievePropertiesEx>"):
+			inner = props["ContinueRetrievePropertiesEx"]
+		case strings.Contains(body, "<RetrievePropertiesEx>"):
+			inner = props["RetrievePropertiesEx"]
+		default:
+			t.Errorf("unexpected SOAP request: %s", body)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>`+
+			`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" `+
+			`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><soapenv:Body>`+
+			inner+`</soapenv:Body></soapenv:Envelope>`)
+	}
+}
+
+func TestVSphereCollector_CollectHosts(t *testing.T) {
+	props := map[string]string{
+		"RetrievePropertiesEx": `<RetrievePropertiesExResponse xmlns="urn:vim25"><returnval>
+<token>page2</token>
+<objects><obj type="HostSystem">host-10</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">esx01.lan</val></propSet>
+<propSet><name>runtime.connectionState</name><val xsi:type="HostSystemConnectionState">connected</val></propSet>
+<propSet><name>runtime.powerState</name><val xsi:type="HostSystemPowerState">poweredOn</val></propSet>
+<propSet><name>summary.hardware.vendor</name><val xsi:type="xsd:string">Dell Inc.</val></propSet>
+<propSet><name>summary.hardware.model</name><val xsi:type="xsd:string">PowerEdge R740</val></propSet>
+<propSet><name>summary.hardware.cpuModel</name><val xsi:type="xsd:string">Intel(R) Xeon(R) Gold 6130</val></propSet>
+<propSet><name>summary.hardware.numCpuCores</name><val xsi:type="xsd:short">32</val></propSet>
+<propSet><name>summary.hardware.numCpuThreads</name><val xsi:type="xsd:short">64</val></propSet>
+<propSet><name>summary.hardware.cpuMhz</name><val xsi:type="xsd:int">2100</val></propSet>
+<propSet><name>summary.hardware.memorySize</name><val xsi:type="xsd:long">274877906944</val></propSet>
+</objects></returnval></RetrievePropertiesExResponse>`,
+		"ContinueRetrievePropertiesEx": `<ContinueRetrievePropertiesExResponse xmlns="urn:vim25"><returnval>
+<objects><obj type="HostSystem">host-11</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">esx02.lan</val></propSet>
+<propSet><name>runtime.connectionState</name><val xsi:type="HostSystemConnectionState">notResponding</val></propSet>
+<propSet><name>runtime.powerState</name><val xsi:type="HostSystemPowerState">unknown</val></propSet>
+<propSet><name>summary.hardware.numCpuCores</name><val xsi:type="xsd:short">16</val></propSet>
+<propSet><name>summary.hardware.memorySize</name><val xsi:type="xsd:long">137438953472</val></propSet>
+</objects></returnval></ContinueRetrievePropertiesExResponse>`,
+	}
+	srv := newTestProxmoxServer(t, vsphereTestHandler(t, props))
+
+	c := NewVSphereCollector(srv.URL, "admin@vsphere.local", "s3cret&", zap.NewNop())
+	hosts, err := c.CollectHosts(context.Background())
+	if err != nil {
+		t.Fatalf("CollectHosts() error = %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("CollectHosts() returned %d hosts, want 2", len(hosts))
+	}
+
+	h := hosts[0]
+	if h.ID != "host-10" || h.Name != "esx01.lan" {
+		t.Errorf("host = %q/%q, want host-10/esx01.lan", h.ID, h.Name)
+	}
+	if h.CPUCores != 32 || h.CPUThreads != 64 || h.CPUMhz != 2100 {
+		t.Errorf("CPU = %d cores/%d threads/%d MHz, want 32/64/2100", h.CPUCores, h.CPUThreads, h.CPUMhz)
+	}
+	if h.MemoryBytes != 274877906944 {
+		t.Errorf("MemoryBytes = %d, want 274877906944", h.MemoryBytes)
+	}
+	if h.ConnectionState != "connected" || h.PowerState != "poweredOn" {
+		t.Errorf("state = %q/%q, want connected/poweredOn", h.ConnectionState, h.PowerState)
+	}
+
+	hw := h.Hardware()
+	if hw.RAMTotalMB != 262144 {
+		t.Errorf("Hardware().RAMTotalMB = %d, want 262144", hw.RAMTotalMB)
+	}
+	if hw.CPUCores != 32 || hw.Hypervisor != "vmware" || hw.SystemManufacturer != "Dell Inc." {
+		t.Errorf("Hardware() = %+v, want 32 cores, vmware, Dell Inc.", hw)
+	}
+
+	if hosts[1].Name != "esx02.lan" || hosts[1].CPUCores != 16 || hosts[1].ConnectionState != "notResponding" {
+		t.Errorf("second host = %+v, want esx02.lan with 16 cores, notResponding", hosts[1])
+	}
+}
+
+func TestVSphereCollector_CollectVMs(t *testing.T) {
+	props := map[string]string{
+		"RetrievePropertiesEx": `<RetrievePropertiesExResponse xmlns="urn:vim25"><returnval>
+<objects><obj type="VirtualMachine">vm-100</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">web-01</val></propSet>
+<propSet><name>runtime.powerState</name><val xsi:type="VirtualMachinePowerState">poweredOn</val></propSet>
+<propSet><name>runtime.host</name><val type="HostSystem" xsi:type="ManagedObjectReference">host-10</val></propSet>
+<propSet><name>config.hardware.numCPU</name><val xsi:type="xsd:int">4</val></propSet>
+<propSet><name>config.hardware.memoryMB</name><val xsi:type="xsd:int">8192</val></propSet>
+<propSet><name>config.guestFullName</name><val xsi:type="xsd:string">Ubuntu Linux (64-bit)</val></propSet>
+<propSet><name>guest.ipAddress</name><val xsi:type="xsd:string">10.0.0.21</val></propSet>
+</objects>
+<objects><obj type="VirtualMachine">vm-101</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">db-01</val></propSet>
+<propSet><name>runtime.powerState</name><val xsi:type="VirtualMachinePowerState">poweredOff</val></propSet>
+<propSet><name>config.hardware.numCPU</name><val xsi:type="xsd:int">2</val></propSet>
+<propSet><name>config.hardware.memoryMB</name><val xsi:type="xsd:int">4096</val></propSet>
+</objects>
+<objects><obj type="VirtualMachine">vm-102</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">build-01</val></propSet>
+<propSet><name>runtime.powerState</name><val xsi:type="VirtualMachinePowerState">suspended</val></propSet>
+</objects></returnval></RetrievePropertiesExResponse>`,
+	}
+	srv := newTestProxmoxServer(t, vsphereTestHandler(t, props))
+
+	c := NewVSphereCollector(srv.URL, "admin@vsphere.local", "s3cret&", zap.NewNop())
+	vms, err := c.CollectVMs(context.Background())
+	if err != nil {
+		t.Fatalf("CollectVMs() error = %v", err)
+	}
+
+	tests := []struct {
+		id         string
+		name       string
+		powerState string
+		status     string
+		numCPU     int
+		memoryMB   int
+		hostID     string
+	}{
+		{"vm-100", "web-01", "poweredOn", "running", 4, 8192, "host-10"},
+		{"vm-101", "db-01", "poweredOff", "stopped", 2, 4096, ""},
+		{"vm-102", "build-01", "suspended", "stopped", 0, 0, ""},
+	}
+	if len(vms) != len(tests) {
+		t.Fatalf("CollectVMs() returned %d VMs, want %d", len(vms), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vm := vms[i]
+			if vm.ID != tt.id || vm.Name != tt.name {
+				t.Errorf("vm = %q/%q, want %q/%q", vm.ID, vm.Name, tt.id, tt.name)
+			}
+			if vm.PowerState != tt.powerState {
+				t.Errorf("PowerState = %q, want %q", vm.PowerState, tt.powerState)
+			}
+			if got := vm.GuestStatus(); got != tt.status {
+				t.Errorf("GuestStatus() = %q, want %q", got, tt.status)
+			}
+			if vm.NumCPU != tt.numCPU || vm.MemoryMB != tt.memoryMB {
+				t.Errorf("resources = %d CPU/%d MB, want %d/%d", vm.NumCPU, vm.MemoryMB, tt.numCPU, tt.memoryMB)
+			}
+			if vm.HostID != tt.hostID {
+				t.Errorf("HostID = %q, want %q", vm.HostID, tt.hostID)
+			}
+		})
+	}
+
+	if vms[0].GuestIP != "10.0.0.21" || vms[0].GuestOS != "Ubuntu Linux (64-bit)" {
+		t.Errorf("guest info = %q/%q, want 10.0.0.21/Ubuntu Linux (64-bit)", vms[0].GuestIP, vms[0].GuestOS)
+	}
+}
+
+func TestVSphereCollector_CollectDatastores(t *testing.T) {
+	props := map[string]string{
+		"RetrievePropertiesEx": `<RetrievePropertiesExResponse xmlns="urn:vim25"><returnval>
+<objects><obj type="Datastore">datastore-7</obj>
+<propSet><name>summary.name</name><val xsi:type="xsd:string">vsanDatastore</val></propSet>
+<propSet><name>summary.type</name><val xsi:type="xsd:string">vsan</val></propSet>
+<propSet><name>summary.capacity</name><val xsi:type="xsd:long">4000787030016</val></propSet>
+<propSet><name>summary.freeSpace</name><val xsi:type="xsd:long">1000204886016</val></propSet>
+<propSet><name>summary.accessible</name><val xsi:type="xsd:boolean">true</val></propSet>
+</objects></returnval></RetrievePropertiesExResponse>`,
+	}
+	srv := newTestProxmoxServer(t, vsphereTestHandler(t, props))
+
+	c := NewVSphereCollector(srv.URL, "admin@vsphere.local", "s3cret&", zap.NewNop())
+	stores, err := c.CollectDatastores(context.Background())
+	if err != nil {
+		t.Fatalf("CollectDatastores() error = %v", err)
+	}
+	if len(stores) != 1 {
+		t.Fatalf("CollectDatastores() returned %d datastores, want 1", len(stores))
+	}
+	ds := stores[0]
+	if ds.Name != "vsanDatastore" || ds.Type != "vsan" || !ds.Accessible {
+		t.Errorf("datastore = %+v, want accessible vsanDatastore of type vsan", ds)
+	}
+	if ds.CapacityBytes != 4000787030016 || ds.FreeBytes != 1000204886016 {
+		t.Errorf("capacity/free = %d/%d, want 4000787030016/1000204886016", ds.CapacityBytes, ds.FreeBytes)
+	}
+}
+
+func TestVSphereCollector_LoginFault(t *testing.T) {
+	srv := newTestProxmoxServer(t, vsphereTestHandler(t, nil))
+
+	c := NewVSphereCollector(srv.URL, "admin@vsphere.local", "wrong", zap.NewNop())
+	_, err := c.CollectHosts(context.Background())
+	if err == nil {
+		t.Fatal("CollectHosts() expected error for bad credentials")
+	}
+	if !strings.Contains(err.Error(), "incorrect user name or password") {
+		t.Errorf("error = %v, want SOAP fault string", err)
+	}
+}
+
+func TestVSphereCollector_ReloginAfterSessionExpiry(t *testing.T) {
+	props := map[string]string{
+		"RetrievePropertiesEx": `<RetrievePropertiesExResponse xmlns="urn:vim25"><returnval>
+<objects><obj type="HostSystem">host-10</obj>
+<propSet><name>name</name><val xsi:type="xsd:string">esx01.lan</val></propSet>
+</objects></returnval></RetrievePropertiesExResponse>`,
+	}
+	inner := vsphereTestHandler(t, props)
+	var loggedIn atomic.Bool
+	var logins atomic.Int32
+	srv := newTestProxmoxServer(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body := string(b)
+		switch {
+		case strings.Contains(body, "<Login>"):
+			logins.Add(1)
+			loggedIn.Store(true)
+		case strings.Contains(body, "<CreateContainerView>") && !loggedIn.Load():
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?>`+
+				`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" `+
+				`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"><soapenv:Body>`+
+				`<soapenv:Fault><faultcode>ServerFaultCode</faultcode>`+
+				`<faultstring>The session is not authenticated.</faultstring>`+
+				`<detail><NotAuthenticatedFault xmlns="urn:vim25" xsi:type="NotAuthenticated">`+
+				`<object type="Folder">group-d1</object><privilegeId>System.View</privilegeId>`+
+				`</NotAuthenticatedFault></detail></soapenv:Fault></soapenv:Body></soapenv:Envelope>`)
+			return
+		}
+		r.Body = io.NopCloser(strings.NewReader(body))
+		inner(w, r)
+	})
+
+	c := NewVSphereCollector(srv.URL, "admin@vsphere.local", "s3cret&", zap.NewNop())
+	if _, err := c.CollectHosts(context.Background()); err != nil {
+		t.Fatalf("CollectHosts() error = %v", err)
+	}
+
+	// vCenter drops the session; the next collection must log in again.
+	loggedIn.Store(false)
+	hosts, err := c.CollectHosts(context.Background())
+	if err != nil {
+		t.Fatalf("CollectHosts() after session expiry error = %v", err)
+	}
+	if len(hosts) != 1 || hosts[0].Name != "esx01.lan" {
+		t.Errorf("hosts = %+v, want esx01.lan", hosts)
+	}
+	if got := logins.Load(); got != 2 {
+		t.Errorf("logins = %d, want 2", got)
+	}
+}
@@ -0,0 +1,97 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// gate tracks in-flight requests to one plugin's routes so a restart can
+// stop admitting new requests and wait for running ones to finish.
+type gate struct {
+	mu       sync.Mutex
+	closed   bool
+	inflight int
+	drained  chan struct{} // closed when inflight reaches zero while closed
+}
+
+// enter admits a request, reporting false if the gate is closed. Every
+// admitted request must call leave.
+func (g *gate) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.inflight++
+	return true
+}
+
+// leave marks an admitted request as finished.
+func (g *gate) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.inflight--
+	if g.inflight == 0 && g.drained != nil {
+		close(g.drained)
+		g.drained = nil
+	}
+}
+
+// close stops admitting requests and waits until in-flight requests finish
+// or ctx is done. The gate stays closed either way.
+func (g *gate) close(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	if g.inflight == 0 {
+		g.mu.Unlock()
+		return nil
+	}
+	if g.drained == nil {
+		g.drained = make(chan struct{})
+	}
+	drained := g.drained
+	g.mu.Unlock()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// open resumes admitting requests.
+func (g *gate) open() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.closed = false
+}
+
+// wrap admits requests to h through the gate, answering 503 while it is
+// closed.
+func (g *gate) wrap(name string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !g.enter() {
+			writeUnavailable(w, name)
+			return
+		}
+		defer g.leave()
+		h(w, r)
+	}
+}
+
+// writeUnavailable writes a problem+json 503 for a plugin that is
+// restarting or failed to restart.
+func writeUnavailable(w http.ResponseWriter, name string) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.Header().Set("Retry-After", "5")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"type":   "https://subnetree.com/problems/plugin-unavailable",
+		"title":  http.StatusText(http.StatusServiceUnavailable),
+		"status": http.StatusServiceUnavailable,
+		"detail": "plugin " + name + " is unavailable",
+	})
+}
@@ -4,13 +4,23 @@ package registry
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/HerbHall/subnetree/pkg/plugin"
 	"go.uber.org/zap"
 )
 
+// ErrNotRestartable is returned by Restart for plugins that were disabled
+// during Validate and so were never initialized.
+var ErrNotRestartable = errors.New("plugin was disabled at startup and cannot be restarted")
+
+// errRestarting is the disabled reason reported while a plugin restarts.
+var errRestarting = errors.New("plugin is restarting")
+
 // Registry manages the lifecycle of all registered plugins.
 type Registry struct {
 	mu       sync.RWMutex
@@ -18,7 +28,16 @@ type Registry struct {
 	infos    map[string]plugin.PluginInfo
 	order    []string // topological order after Validate
 	disabled map[string]bool
+	reasons  map[string]error    // why each disabled plugin was disabled
+	failed   map[string]bool     // disabled by an Init, ValidateConfig, or Start error
+	gates    map[string]*gate    // admits requests to each plugin's routes
+	unsubs   map[string][]func() // event unsubscribe funcs; guarded by unsubsMu
+	depsFn   func(name string) plugin.Dependencies
 	logger   *zap.Logger
+
+	// lifecycleMu serializes Restart with other restarts and StopAll.
+	lifecycleMu sync.Mutex
+	unsubsMu    sync.Mutex
 }
 
 // New creates a new plugin registry.
@@ -27,6 +46,10 @@ func New(logger *zap.Logger) *Registry {
 		plugins:  make(map[string]plugin.Plugin),
 		infos:    make(map[string]plugin.PluginInfo),
 		disabled: make(map[string]bool),
+		reasons:  make(map[string]error),
+		failed:   make(map[string]bool),
+		gates:    make(map[string]*gate),
+		unsubs:   make(map[string][]func()),
 		logger:   logger,
 	}
 }
@@ -48,6 +71,7 @@ func (r *Registry) Register(p plugin.Plugin) error {
 
 	r.plugins[name] = p
 	r.infos[name] = info
+	r.gates[name] = &gate{}
 	r.logger.Info("plugin registered",
 		zap.String("name", name),
 		zap.String("version", info.Version),
@@ -72,7 +96,7 @@ func (r *Registry) Validate() error {
 				zap.String("name", name),
 				zap.Error(err),
 			)
-			r.disabled[name] = true
+			r.disable(name, err)
 		}
 	}
 
@@ -90,7 +114,7 @@ func (r *Registry) Validate() error {
 					zap.String("name", name),
 					zap.String("missing_dep", dep),
 				)
-				r.disabled[name] = true
+				r.disable(name, fmt.Errorf("dependency %q is not registered", dep))
 				break
 			}
 			if r.disabled[dep] {
@@ -101,7 +125,7 @@ func (r *Registry) Validate() error {
 					zap.String("name", name),
 					zap.String("disabled_dep", dep),
 				)
-				r.disabled[name] = true
+				r.disable(name, fmt.Errorf("dependency %q is disabled", dep))
 				break
 			}
 		}
@@ -126,7 +150,7 @@ func (r *Registry) Validate() error {
 					zap.String("name", name),
 					zap.String("disabled_dep", dep),
 				)
-				r.disabled[name] = true
+				r.disable(name, fmt.Errorf("dependency %q is disabled", dep))
 				changed = true
 				break
 			}
@@ -150,6 +174,10 @@ func (r *Registry) Validate() error {
 
 // InitAll initializes all active plugins in dependency order.
 func (r *Registry) InitAll(ctx context.Context, depsFn func(name string) plugin.Dependencies) error {
+	r.mu.Lock()
+	r.depsFn = depsFn
+	r.mu.Unlock()
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -159,18 +187,7 @@ func (r *Registry) InitAll(ctx context.Context, depsFn func(name string) plugin.
 		// Validate config if plugin implements Validator.
 		r.logger.Info("initializing plugin", zap.String("name", name))
 		deps := depsFn(name)
-		var initErr error
-		func() {
-			defer func() {
-				if rec := recover(); rec != nil {
-					initErr = fmt.Errorf("plugin panicked during Init: %v", rec)
-					r.logger.Error("plugin panic recovered during Init",
-						zap.String("plugin", name), zap.Any("panic", rec))
-				}
-			}()
-			initErr = p.Init(ctx, deps)
-		}()
-		if initErr != nil {
+		if initErr := r.callInit(ctx, name, p, deps); initErr != nil {
 			info := r.infos[name]
 			if info.Required {
 				return fmt.Errorf("required plugin %q failed to initialize: %w", name, initErr)
@@ -179,7 +196,7 @@ func (r *Registry) InitAll(ctx context.Context, depsFn func(name string) plugin.
 				zap.String("name", name),
 				zap.Error(initErr),
 			)
-			r.disabled[name] = true
+			r.fail(name, initErr)
 			continue
 		}
 
@@ -194,20 +211,11 @@ func (r *Registry) InitAll(ctx context.Context, depsFn func(name string) plugin.
 					zap.String("name", name),
 					zap.Error(err),
 				)
-				r.disabled[name] = true
+				r.fail(name, fmt.Errorf("config validation: %w", err))
 			}
 		}
 
-		// Wire event subscriptions for EventSubscriber plugins.
-		if es, ok := p.(plugin.EventSubscriber); ok {
-			for _, sub := range es.Subscriptions() {
-				deps.Bus.Subscribe(sub.Topic, sub.Handler)
-				r.logger.Info("subscribed plugin to event",
-					zap.String("plugin", name),
-					zap.String("topic", sub.Topic),
-				)
-			}
-		}
+		r.subscribe(name, p, deps.Bus)
 	}
 	return nil
 }
@@ -223,18 +231,7 @@ func (r *Registry) StartAll(ctx context.Context) error {
 		}
 		p := r.plugins[name]
 		r.logger.Info("starting plugin", zap.String("name", name))
-		var startErr error
-		func() {
-			defer func() {
-				if rec := recover(); rec != nil {
-					startErr = fmt.Errorf("plugin panicked during Start: %v", rec)
-					r.logger.Error("plugin panic recovered during Start",
-						zap.String("plugin", name), zap.Any("panic", rec))
-				}
-			}()
-			startErr = p.Start(ctx)
-		}()
-		if startErr != nil {
+		if startErr := r.callStart(ctx, name, p); startErr != nil {
 			info := r.infos[name]
 			if info.Required {
 				return fmt.Errorf("required plugin %q failed to start: %w", name, startErr)
@@ -243,7 +240,7 @@ func (r *Registry) StartAll(ctx context.Context) error {
 				zap.String("name", name),
 				zap.Error(startErr),
 			)
-			r.disabled[name] = true
+			r.fail(name, startErr)
 		}
 	}
 	return nil
@@ -251,6 +248,8 @@ func (r *Registry) StartAll(ctx context.Context) error {
 
 // StopAll stops all active plugins in reverse dependency order.
 func (r *Registry) StopAll(ctx context.Context) {
+	r.lifecycleMu.Lock()
+	defer r.lifecycleMu.Unlock()
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -259,19 +258,233 @@ func (r *Registry) StopAll(ctx context.Context) {
 		if r.disabled[name] {
 			continue
 		}
-		p := r.plugins[name]
 		r.logger.Info("stopping plugin", zap.String("name", name))
-		func() {
-			defer func() {
-				if rec := recover(); rec != nil {
-					r.logger.Error("plugin panic recovered during Stop",
-						zap.String("plugin", name), zap.Any("panic", rec))
+		r.callStop(ctx, name, r.plugins[name])
+	}
+}
+
+// Restart stops a plugin and any active plugins that depend on it, then
+// initializes and starts them again in dependency order, without
+// restarting the server. Requests to the affected plugins' routes get 503
+// while they restart; Restart waits for in-flight requests to finish (or
+// ctx to end) before stopping anything. A plugin that fails to restart is
+// left stopped and marked failed, as are its dependents.
+//
+// ctx bounds the wait for in-flight requests and is passed to Stop. Init
+// and Start get a context that is never canceled, since Start's context
+// governs a plugin's background work after Restart returns.
+//
+// The plugin's routes are those mounted at startup, so a plugin that
+// failed before the server mounted its routes serves none until the
+// server restarts. Plugins must tolerate Init being called again after
+// Stop.
+func (r *Registry) Restart(ctx context.Context, name string) error {
+	r.lifecycleMu.Lock()
+	defer r.lifecycleMu.Unlock()
+
+	set, err := r.restartSet(name)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range set {
+		if err := r.gates[n].close(ctx); err != nil {
+			for _, m := range set {
+				if !r.IsDisabled(m) {
+					r.gates[m].open()
 				}
-			}()
-			if err := p.Stop(ctx); err != nil {
-				r.logger.Error("failed to stop plugin", zap.String("name", name), zap.Error(err))
 			}
-		}()
+			return fmt.Errorf("waiting for in-flight requests to plugin %q: %w", n, err)
+		}
+	}
+
+	r.logger.Info("restarting plugins", zap.String("name", name), zap.Strings("plugins", set))
+	for i := len(set) - 1; i >= 0; i-- {
+		n := set[i]
+		wasRunning := !r.IsDisabled(n)
+		r.mu.Lock()
+		r.disable(n, errRestarting)
+		delete(r.failed, n)
+		r.mu.Unlock()
+		if wasRunning {
+			r.logger.Info("stopping plugin", zap.String("name", n))
+			r.callStop(ctx, n, r.plugins[n])
+		}
+		r.unsubscribe(n)
+	}
+
+	runCtx := context.WithoutCancel(ctx)
+	var restartErr error
+	for _, n := range set {
+		if restartErr != nil {
+			r.mu.Lock()
+			r.fail(n, fmt.Errorf("dependency failed to restart: %w", restartErr))
+			r.mu.Unlock()
+			continue
+		}
+		if err := r.restartOne(runCtx, n); err != nil {
+			r.logger.Error("plugin failed to restart, disabling",
+				zap.String("name", n),
+				zap.Error(err),
+			)
+			restartErr = fmt.Errorf("plugin %q failed to restart: %w", n, err)
+			r.mu.Lock()
+			r.fail(n, err)
+			r.mu.Unlock()
+			continue
+		}
+		r.mu.Lock()
+		delete(r.disabled, n)
+		delete(r.reasons, n)
+		r.mu.Unlock()
+		r.gates[n].open()
+	}
+	if restartErr != nil {
+		return restartErr
+	}
+	r.logger.Info("plugins restarted", zap.Strings("plugins", set))
+	return nil
+}
+
+// restartSet returns name and the active plugins that depend on it,
+// directly or transitively, in dependency order.
+func (r *Registry) restartSet(name string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, ok := r.infos[name]
+	if !ok {
+		return nil, fmt.Errorf("plugin %q is not registered", name)
+	}
+	if r.depsFn == nil {
+		return nil, fmt.Errorf("plugins have not been initialized")
+	}
+	inOrder := false
+	for _, n := range r.order {
+		if n == name {
+			inOrder = true
+			break
+		}
+	}
+	if !inOrder {
+		return nil, fmt.Errorf("plugin %q: %w", name, ErrNotRestartable)
+	}
+	for _, dep := range info.Dependencies {
+		if r.disabled[dep] {
+			return nil, fmt.Errorf("plugin %q depends on %q which is not running", name, dep)
+		}
+	}
+
+	set := []string{name}
+	member := map[string]bool{name: true}
+	for _, n := range r.order {
+		if member[n] || r.disabled[n] {
+			continue
+		}
+		for _, dep := range r.infos[n].Dependencies {
+			if member[dep] {
+				set = append(set, n)
+				member[n] = true
+				break
+			}
+		}
+	}
+	return set, nil
+}
+
+// restartOne initializes, validates, and starts a stopped plugin. If any
+// step after Init fails the plugin is stopped again so it is not left half
+// started.
+func (r *Registry) restartOne(ctx context.Context, name string) error {
+	p := r.plugins[name]
+	r.logger.Info("initializing plugin", zap.String("name", name))
+	deps := r.depsFn(name)
+	if err := r.callInit(ctx, name, p, deps); err != nil {
+		return err
+	}
+	if v, ok := p.(plugin.Validator); ok {
+		if err := v.ValidateConfig(); err != nil {
+			r.callStop(ctx, name, p)
+			return fmt.Errorf("config validation: %w", err)
+		}
+	}
+	r.subscribe(name, p, deps.Bus)
+	r.logger.Info("starting plugin", zap.String("name", name))
+	if err := r.callStart(ctx, name, p); err != nil {
+		r.unsubscribe(name)
+		r.callStop(ctx, name, p)
+		return err
+	}
+	return nil
+}
+
+// callInit runs p.Init, converting a panic into an error.
+func (r *Registry) callInit(ctx context.Context, name string, p plugin.Plugin, deps plugin.Dependencies) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("plugin panicked during Init: %v", rec)
+			r.logger.Error("plugin panic recovered during Init",
+				zap.String("plugin", name), zap.Any("panic", rec))
+		}
+	}()
+	return p.Init(ctx, deps)
+}
+
+// callStart runs p.Start, converting a panic into an error.
+func (r *Registry) callStart(ctx context.Context, name string, p plugin.Plugin) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("plugin panicked during Start: %v", rec)
+			r.logger.Error("plugin panic recovered during Start",
+				zap.String("plugin", name), zap.Any("panic", rec))
+		}
+	}()
+	return p.Start(ctx)
+}
+
+// callStop runs p.Stop, logging any error or panic.
+func (r *Registry) callStop(ctx context.Context, name string, p plugin.Plugin) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Error("plugin panic recovered during Stop",
+				zap.String("plugin", name), zap.Any("panic", rec))
+		}
+	}()
+	if err := p.Stop(ctx); err != nil {
+		r.logger.Error("failed to stop plugin", zap.String("name", name), zap.Error(err))
+	}
+}
+
+// subscribe wires event subscriptions for EventSubscriber plugins,
+// remembering them so a restart can remove them.
+func (r *Registry) subscribe(name string, p plugin.Plugin, bus plugin.EventBus) {
+	es, ok := p.(plugin.EventSubscriber)
+	if !ok {
+		return
+	}
+	var unsubs []func()
+	for _, sub := range es.Subscriptions() {
+		unsubs = append(unsubs, bus.Subscribe(sub.Topic, sub.Handler))
+		r.logger.Info("subscribed plugin to event",
+			zap.String("plugin", name),
+			zap.String("topic", sub.Topic),
+		)
+	}
+	r.unsubsMu.Lock()
+	r.unsubs[name] = unsubs
+	r.unsubsMu.Unlock()
+}
+
+// unsubscribe removes a plugin's event subscriptions.
+func (r *Registry) unsubscribe(name string) {
+	r.unsubsMu.Lock()
+	unsubs := r.unsubs[name]
+	delete(r.unsubs, name)
+	r.unsubsMu.Unlock()
+	for _, unsub := range unsubs {
+		if unsub != nil {
+			unsub()
+		}
 	}
 }
 
@@ -313,13 +526,47 @@ func (r *Registry) AllRoutes() map[string][]plugin.Route {
 		p := r.plugins[name]
 		if hp, ok := p.(plugin.HTTPProvider); ok {
 			if pr := hp.Routes(); len(pr) > 0 {
-				routes[name] = pr
+				routes[name] = r.gateRoutes(name, pr)
 			}
 		}
 	}
 	return routes
 }
 
+// gateRoutes routes each handler through the plugin's gate so Restart can
+// hold off new requests and drain in-flight ones.
+func (r *Registry) gateRoutes(name string, routes []plugin.Route) []plugin.Route {
+	g := r.gates[name]
+	gated := make([]plugin.Route, len(routes))
+	for i, route := range routes {
+		if route.Handler != nil {
+			route.Handler = g.wrap(name, route.Handler)
+		}
+		gated[i] = route
+	}
+	return gated
+}
+
+// Ready checks every active plugin implementing ReadinessReporter, in
+// dependency order. It returns nil if all are ready, or an error naming each
+// plugin that is not and why.
+func (r *Registry) Ready() error {
+	var notReady []string
+	for _, p := range r.All() {
+		rr, ok := p.(plugin.ReadinessReporter)
+		if !ok {
+			continue
+		}
+		if err := rr.Readiness(); err != nil {
+			notReady = append(notReady, fmt.Sprintf("plugin %q not ready: %v", p.Info().Name, err))
+		}
+	}
+	if len(notReady) > 0 {
+		return errors.New(strings.Join(notReady, "; "))
+	}
+	return nil
+}
+
 // Resolve returns a plugin by name (implements plugin.PluginResolver).
 func (r *Registry) Resolve(name string) (plugin.Plugin, bool) {
 	return r.Get(name)
@@ -353,6 +600,67 @@ func (r *Registry) IsDisabled(name string) bool {
 	return r.disabled[name]
 }
 
+// Status returns the lifecycle status of a registered plugin.
+func (r *Registry) Status(name string) (plugin.PluginStatus, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if _, ok := r.plugins[name]; !ok {
+		return plugin.PluginStatus{}, false
+	}
+	return r.statusLocked(name), true
+}
+
+// Statuses returns the lifecycle status of every registered plugin: those in
+// the start order first, in dependency order, then the rest by name.
+func (r *Registry) Statuses() []plugin.PluginStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]plugin.PluginStatus, 0, len(r.plugins))
+	seen := make(map[string]bool, len(r.order))
+	for _, name := range r.order {
+		seen[name] = true
+		result = append(result, r.statusLocked(name))
+	}
+	rest := make([]string, 0, len(r.plugins)-len(r.order))
+	for name := range r.plugins {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	for _, name := range rest {
+		result = append(result, r.statusLocked(name))
+	}
+	return result
+}
+
+// statusLocked builds a plugin's status. Callers must hold r.mu.
+func (r *Registry) statusLocked(name string) plugin.PluginStatus {
+	status := plugin.PluginStatus{Info: r.infos[name], State: plugin.StateEnabled}
+	switch {
+	case r.failed[name]:
+		status.State = plugin.StateFailed
+		status.Err = r.reasons[name]
+	case r.disabled[name]:
+		status.State = plugin.StateDisabled
+		status.Err = r.reasons[name]
+	}
+	return status
+}
+
+// disable marks a plugin disabled before Init and records why.
+func (r *Registry) disable(name string, reason error) {
+	r.disabled[name] = true
+	r.reasons[name] = reason
+}
+
+// fail marks a plugin disabled by a lifecycle error and records the error.
+func (r *Registry) fail(name string, err error) {
+	r.disable(name, err)
+	r.failed[name] = true
+}
+
 // checkAPIVersion validates a plugin's API version against the server's range.
 func (r *Registry) checkAPIVersion(name string, apiVersion int) error {
 	if apiVersion < plugin.APIVersionMin {
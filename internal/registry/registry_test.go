@@ -3,6 +3,8 @@ package registry
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -93,6 +95,14 @@ type testHTTPPlugin struct {
 
 func (p *testHTTPPlugin) Routes() []plugin.Route { return p.routes }
 
+// testReadyPlugin implements both Plugin and ReadinessReporter.
+type testReadyPlugin struct {
+	testPlugin
+	readyErr error
+}
+
+func (p *testReadyPlugin) Readiness() error { return p.readyErr }
+
 // testEventSubPlugin implements both Plugin and EventSubscriber.
 type testEventSubPlugin struct {
 	testPlugin
@@ -291,6 +301,69 @@ func TestInitAllOptionalDisabledOnFailure(t *testing.T) {
 	}
 }
 
+func TestStatuses(t *testing.T) {
+	reg := New(testLogger())
+	good := newTestPlugin("good")
+	bad := newTestPlugin("bad")
+	bad.initErr = errors.New("init failed")
+	old := newTestPlugin("old")
+	old.info.APIVersion = plugin.APIVersionMin - 1
+	dependent := newTestPlugin("dependent", "old")
+	reg.Register(good)
+	reg.Register(bad)
+	reg.Register(old)
+	reg.Register(dependent)
+	if err := reg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if err := reg.InitAll(context.Background(), testDeps()); err != nil {
+		t.Fatalf("InitAll() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		wantState plugin.PluginState
+		wantErr   string
+	}{
+		{name: "good", wantState: plugin.StateEnabled},
+		{name: "bad", wantState: plugin.StateFailed, wantErr: "init failed"},
+		{name: "old", wantState: plugin.StateDisabled, wantErr: "Plugin API"},
+		{name: "dependent", wantState: plugin.StateDisabled, wantErr: `dependency "old" is disabled`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, ok := reg.Status(tt.name)
+			if !ok {
+				t.Fatalf("Status(%q) not found", tt.name)
+			}
+			if st.State != tt.wantState {
+				t.Errorf("State = %q, want %q", st.State, tt.wantState)
+			}
+			if tt.wantErr == "" {
+				if st.Err != nil {
+					t.Errorf("Err = %v, want nil", st.Err)
+				}
+			} else if st.Err == nil || !strings.Contains(st.Err.Error(), tt.wantErr) {
+				t.Errorf("Err = %v, want it to contain %q", st.Err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, ok := reg.Status("missing"); ok {
+		t.Error("Status(missing) found, want not found")
+	}
+
+	statuses := reg.Statuses()
+	if len(statuses) != 4 {
+		t.Fatalf("len(Statuses()) = %d, want 4", len(statuses))
+	}
+	// Plugins disabled during Validate are not in the start order and come
+	// last, sorted by name.
+	if statuses[2].Info.Name != "dependent" || statuses[3].Info.Name != "old" {
+		t.Errorf("Statuses() tail = %q, %q; want dependent, old", statuses[2].Info.Name, statuses[3].Info.Name)
+	}
+}
+
 func TestStartAllStopAll(t *testing.T) {
 	reg := New(testLogger())
 	reg.Register(newTestPlugin("a"))
@@ -890,3 +963,261 @@ func TestStopAll_ConcurrentSafety(t *testing.T) {
 		t.Errorf("stop count = %d, want 3", stopCount)
 	}
 }
+
+// lifecyclePlugin records lifecycle calls and can fail Start on demand.
+type lifecyclePlugin struct {
+	info     plugin.PluginInfo
+	calls    *[]string
+	startErr error
+	running  bool
+	routes   []plugin.Route
+}
+
+func newLifecyclePlugin(name string, calls *[]string, deps ...string) *lifecyclePlugin {
+	return &lifecyclePlugin{
+		info: plugin.PluginInfo{
+			Name:         name,
+			Version:      "1.0.0",
+			Dependencies: deps,
+			APIVersion:   plugin.APIVersionCurrent,
+		},
+		calls: calls,
+	}
+}
+
+func (p *lifecyclePlugin) Info() plugin.PluginInfo { return p.info }
+func (p *lifecyclePlugin) Init(_ context.Context, _ plugin.Dependencies) error {
+	*p.calls = append(*p.calls, "init:"+p.info.Name)
+	return nil
+}
+func (p *lifecyclePlugin) Start(_ context.Context) error {
+	*p.calls = append(*p.calls, "start:"+p.info.Name)
+	if p.startErr != nil {
+		return p.startErr
+	}
+	p.running = true
+	return nil
+}
+func (p *lifecyclePlugin) Stop(_ context.Context) error {
+	*p.calls = append(*p.calls, "stop:"+p.info.Name)
+	p.running = false
+	return nil
+}
+func (p *lifecyclePlugin) Routes() []plugin.Route { return p.routes }
+
+// startRegistry registers plugins and runs Validate, InitAll, and StartAll.
+func startRegistry(t *testing.T, plugins ...plugin.Plugin) *Registry {
+	t.Helper()
+	reg := New(testLogger())
+	for _, p := range plugins {
+		if err := reg.Register(p); err != nil {
+			t.Fatalf("Register: %v", err)
+		}
+	}
+	if err := reg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	ctx := context.Background()
+	if err := reg.InitAll(ctx, testDeps()); err != nil {
+		t.Fatalf("InitAll() error = %v", err)
+	}
+	if err := reg.StartAll(ctx); err != nil {
+		t.Fatalf("StartAll() error = %v", err)
+	}
+	return reg
+}
+
+func TestRestart_RestartsDependents(t *testing.T) {
+	var calls []string
+	base := newLifecyclePlugin("base", &calls)
+	mid := newLifecyclePlugin("mid", &calls, "base")
+	top := newLifecyclePlugin("top", &calls, "mid")
+	other := newLifecyclePlugin("other", &calls)
+	reg := startRegistry(t, base, mid, top, other)
+	calls = nil
+
+	if err := reg.Restart(context.Background(), "base"); err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+
+	want := []string{
+		"stop:top", "stop:mid", "stop:base",
+		"init:base", "start:base", "init:mid", "start:mid", "init:top", "start:top",
+	}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	for _, name := range []string{"base", "mid", "top", "other"} {
+		if st, _ := reg.Status(name); st.State != plugin.StateEnabled {
+			t.Errorf("%s state = %q, want enabled", name, st.State)
+		}
+	}
+}
+
+func TestRestart_FailureLeavesPluginFailed(t *testing.T) {
+	var calls []string
+	base := newLifecyclePlugin("base", &calls)
+	dependent := newLifecyclePlugin("dependent", &calls, "base")
+	reg := startRegistry(t, base, dependent)
+	calls = nil
+
+	base.startErr = errors.New("port in use")
+	err := reg.Restart(context.Background(), "base")
+	if err == nil || !strings.Contains(err.Error(), "port in use") {
+		t.Fatalf("Restart() error = %v, want port in use", err)
+	}
+
+	// Start failed after Init, so base is stopped again rather than left
+	// half started, and its dependent is never re-initialized.
+	want := []string{"stop:dependent", "stop:base", "init:base", "start:base", "stop:base"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+	if st, _ := reg.Status("base"); st.State != plugin.StateFailed || st.Err == nil {
+		t.Errorf("base status = %+v, want failed with error", st)
+	}
+	if st, _ := reg.Status("dependent"); st.State != plugin.StateFailed {
+		t.Errorf("dependent state = %q, want failed", st.State)
+	}
+	if _, ok := reg.Get("base"); ok {
+		t.Error("Get(base) found a failed plugin")
+	}
+
+	// A later successful restart brings the plugin back.
+	base.startErr = nil
+	if err := reg.Restart(context.Background(), "base"); err != nil {
+		t.Fatalf("second Restart() error = %v", err)
+	}
+	if st, _ := reg.Status("base"); st.State != plugin.StateEnabled {
+		t.Errorf("base state after retry = %q, want enabled", st.State)
+	}
+}
+
+func TestRestart_Errors(t *testing.T) {
+	var calls []string
+	old := newLifecyclePlugin("old", &calls)
+	old.info.APIVersion = plugin.APIVersionMin - 1
+	reg := startRegistry(t, old, newLifecyclePlugin("ok", &calls))
+
+	if err := reg.Restart(context.Background(), "missing"); err == nil {
+		t.Error("Restart(missing) error = nil, want error")
+	}
+	if err := reg.Restart(context.Background(), "old"); !errors.Is(err, ErrNotRestartable) {
+		t.Errorf("Restart(old) error = %v, want ErrNotRestartable", err)
+	}
+}
+
+func TestRestart_DrainsInFlightRequests(t *testing.T) {
+	var calls []string
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	p := newLifecyclePlugin("web", &calls)
+	p.routes = []plugin.Route{{Method: "GET", Path: "/slow", Handler: func(w http.ResponseWriter, _ *http.Request) {
+		close(entered)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	}}}
+	reg := startRegistry(t, p)
+	handler := reg.AllRoutes()["web"][0].Handler
+
+	inflight := httptest.NewRecorder()
+	served := make(chan struct{})
+	go func() {
+		handler(inflight, httptest.NewRequest("GET", "/api/v1/web/slow", http.NoBody))
+		close(served)
+	}()
+	<-entered
+
+	restarted := make(chan error, 1)
+	go func() { restarted <- reg.Restart(context.Background(), "web") }()
+
+	// New requests are turned away while the restart waits.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		w := httptest.NewRecorder()
+		reg.gates["web"].wrap("web", func(http.ResponseWriter, *http.Request) {})(w, httptest.NewRequest("GET", "/", http.NoBody))
+		if w.Code == http.StatusServiceUnavailable {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("gate never closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case err := <-restarted:
+		t.Fatalf("Restart returned %v before the in-flight request finished", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-served
+	if err := <-restarted; err != nil {
+		t.Fatalf("Restart() error = %v", err)
+	}
+	if inflight.Code != http.StatusOK {
+		t.Errorf("in-flight status = %d, want 200", inflight.Code)
+	}
+	if !p.running {
+		t.Error("plugin not running after restart")
+	}
+}
+
+func TestRestart_DrainTimeoutKeepsPluginRunning(t *testing.T) {
+	var calls []string
+	release := make(chan struct{})
+	entered := make(chan struct{})
+	p := newLifecyclePlugin("web", &calls)
+	p.routes = []plugin.Route{{Method: "GET", Path: "/slow", Handler: func(w http.ResponseWriter, _ *http.Request) {
+		close(entered)
+		<-release
+	}}}
+	reg := startRegistry(t, p)
+	handler := reg.AllRoutes()["web"][0].Handler
+	go handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", http.NoBody))
+	<-entered
+	defer close(release)
+	calls = nil
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := reg.Restart(ctx, "web"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Restart() error = %v, want deadline exceeded", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("calls = %v, want none after drain timeout", calls)
+	}
+	if !reg.gates["web"].enter() {
+		t.Error("gate still closed after drain timeout")
+	} else {
+		reg.gates["web"].leave()
+	}
+}
+
+func TestReady_ReportsNotReadyPlugins(t *testing.T) {
+	reg := New(zap.NewNop())
+	store := newTestPlugin("store")
+	recon := &testReadyPlugin{testPlugin: *newTestPlugin("recon", "store")}
+	pulse := &testReadyPlugin{testPlugin: *newTestPlugin("pulse", "store"), readyErr: errors.New("check scheduler is not running")}
+	for _, p := range []plugin.Plugin{store, recon, pulse} {
+		if err := reg.Register(p); err != nil {
+			t.Fatalf("Register(%s) error = %v", p.Info().Name, err)
+		}
+	}
+	if err := reg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	err := reg.Ready()
+	if err == nil {
+		t.Fatal("Ready() = nil, want error naming pulse")
+	}
+	if want := `plugin "pulse" not ready: check scheduler is not running`; err.Error() != want {
+		t.Errorf("Ready() = %q, want %q", err, want)
+	}
+
+	pulse.readyErr = nil
+	if err := reg.Ready(); err != nil {
+		t.Errorf("Ready() with all plugins ready = %v, want nil", err)
+	}
+}
@@ -0,0 +1,15 @@
+package profiler
+
+// Disk and NIC type labels reported in HardwareProfile. Every platform
+// collector classifies into these values so the server sees the same
+// labels regardless of the agent's OS.
+const (
+	diskTypeNVMe    = "NVMe"
+	diskTypeSSD     = "SSD"
+	diskTypeHDD     = "HDD"
+	diskTypeUnknown = "Unknown"
+
+	nicTypeEthernet = "ethernet"
+	nicTypeWiFi     = "wifi"
+	nicTypeVirtual  = "virtual"
+)
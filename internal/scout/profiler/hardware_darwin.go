@@ -0,0 +1,250 @@
+//go:build darwin
+
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	scoutpb "github.com/HerbHall/subnetree/api/proto/v1"
+	"go.uber.org/zap"
+)
+
+func collectHardware(ctx context.Context, logger *zap.Logger) (*scoutpb.HardwareProfile, error) {
+	hw := &scoutpb.HardwareProfile{}
+
+	// CPU and RAM from sysctl.
+	sysctl, err := runSysctl(ctx, "machdep.cpu.brand_string", "hw.physicalcpu", "hw.logicalcpu", "hw.memsize")
+	if err != nil {
+		logger.Debug("sysctl failed", zap.Error(err))
+	}
+	hw.CpuModel = sysctl["machdep.cpu.brand_string"]
+	if v, e := strconv.ParseInt(sysctl["hw.physicalcpu"], 10, 32); e == nil {
+		hw.CpuCores = int32(v)
+	}
+	if v, e := strconv.ParseInt(sysctl["hw.logicalcpu"], 10, 32); e == nil {
+		hw.CpuThreads = int32(v)
+	}
+	if v, e := strconv.ParseInt(sysctl["hw.memsize"], 10, 64); e == nil {
+		hw.RamBytes = v
+	}
+
+	// Disk detection is not implemented on macOS yet.
+
+	// System, GPU, and NIC info from a single system_profiler run.
+	report, err := runSystemProfiler(ctx, "SPHardwareDataType", "SPDisplaysDataType", "SPNetworkDataType")
+	if err != nil {
+		logger.Debug("system_profiler failed", zap.Error(err))
+		return hw, nil
+	}
+	if len(report.Hardware) > 0 {
+		h := report.Hardware[0]
+		hw.SystemManufacturer = "Apple"
+		hw.SystemModel = h.MachineModel
+		hw.SerialNumber = h.SerialNumber
+		hw.BiosVersion = h.BootROMVersion
+	}
+	hw.Gpus = darwinGPUs(report.Displays)
+	hw.Nics = darwinNICs(report.Network)
+
+	return hw, nil
+}
+
+// runSysctl reads the named sysctl values. Unknown names are omitted from the
+// result rather than failing the whole call.
+func runSysctl(ctx context.Context, names ...string) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "sysctl", names...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// sysctl exits non-zero if any name is unknown but still prints the rest.
+	err := cmd.Run()
+	values := parseSysctl(stdout.String())
+	if err != nil && len(values) == 0 {
+		return nil, fmt.Errorf("sysctl: %w (stderr: %s)", err, stderr.String())
+	}
+	return values, nil
+}
+
+// parseSysctl parses "name: value" lines from sysctl output.
+func parseSysctl(output string) map[string]string {
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		name, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+// systemProfilerReport holds the system_profiler -json data types the
+// profiler uses.
+type systemProfilerReport struct {
+	Hardware []spHardware `json:"SPHardwareDataType"`
+	Displays []spDisplay  `json:"SPDisplaysDataType"`
+	Network  []spNetwork  `json:"SPNetworkDataType"`
+}
+
+type spHardware struct {
+	MachineModel   string `json:"machine_model"`
+	SerialNumber   string `json:"serial_number"`
+	BootROMVersion string `json:"boot_rom_version"`
+}
+
+type spDisplay struct {
+	Name       string `json:"_name"`
+	Model      string `json:"sppci_model"`
+	VRAM       string `json:"spdisplays_vram"`        // Dedicated VRAM, e.g. "8 GB"
+	VRAMShared string `json:"spdisplays_vram_shared"` // Integrated GPUs
+}
+
+type spNetwork struct {
+	Name      string `json:"_name"`     // Service name, e.g. "Wi-Fi"
+	Interface string `json:"interface"` // BSD name, e.g. "en0"
+	Type      string `json:"type"`      // "AirPort", "Ethernet", "Bridge", "VPN (...)"
+	Ethernet  struct {
+		MACAddress   string `json:"MAC Address"`
+		MediaSubtype string `json:"MediaSubType"`
+	} `json:"Ethernet"`
+}
+
+// runSystemProfiler runs system_profiler -json for the given data types.
+func runSystemProfiler(ctx context.Context, dataTypes ...string) (*systemProfilerReport, error) {
+	args := append([]string{"-json"}, dataTypes...)
+	cmd := exec.CommandContext(ctx, "system_profiler", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("system_profiler: %w (stderr: %s)", err, stderr.String())
+	}
+
+	return parseSystemProfiler(stdout.Bytes())
+}
+
+// parseSystemProfiler decodes system_profiler -json output.
+func parseSystemProfiler(data []byte) (*systemProfilerReport, error) {
+	var report systemProfilerReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("decode system_profiler output: %w", err)
+	}
+	return &report, nil
+}
+
+// darwinGPUs converts SPDisplaysDataType entries to GPU info. Integrated GPUs
+// report shared memory, which is used when no dedicated VRAM is listed.
+func darwinGPUs(displays []spDisplay) []*scoutpb.GPUInfo {
+	var gpus []*scoutpb.GPUInfo
+	for _, d := range displays {
+		model := d.Model
+		if model == "" {
+			model = d.Name
+		}
+		if model == "" {
+			continue
+		}
+		vram := parseMemorySize(d.VRAM)
+		if vram == 0 {
+			vram = parseMemorySize(d.VRAMShared)
+		}
+		gpus = append(gpus, &scoutpb.GPUInfo{
+			Model:     model,
+			VramBytes: vram,
+		})
+	}
+	return gpus
+}
+
+// parseMemorySize parses sizes such as "8 GB" or "1536 MB" into bytes.
+func parseMemorySize(s string) int64 {
+	num, unit, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if !ok {
+		return 0
+	}
+	v, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToUpper(unit) {
+	case "GB":
+		return v << 30
+	case "MB":
+		return v << 20
+	case "KB":
+		return v << 10
+	default:
+		return 0
+	}
+}
+
+// darwinNICs converts SPNetworkDataType services to NIC info, named by BSD
+// interface like the Linux profiler. Services without an interface (such as
+// PPPoE placeholders) are skipped.
+func darwinNICs(services []spNetwork) []*scoutpb.NICInfo {
+	var nics []*scoutpb.NICInfo
+	for i := range services {
+		s := &services[i]
+		if s.Interface == "" {
+			continue
+		}
+		nics = append(nics, &scoutpb.NICInfo{
+			Name:       s.Interface,
+			MacAddress: s.Ethernet.MACAddress,
+			SpeedMbps:  parseMediaSpeed(s.Ethernet.MediaSubtype),
+			NicType:    classifyDarwinNICType(s.Interface, s.Type),
+		})
+	}
+	return nics
+}
+
+// parseMediaSpeed extracts the link speed in Mbps from a media subtype such
+// as "1000baseT" or "10GbaseT". Returns 0 for "autoselect" or unknown values.
+func parseMediaSpeed(subtype string) int64 {
+	s := strings.ToLower(subtype)
+	idx := strings.Index(s, "base")
+	if idx <= 0 {
+		return 0
+	}
+	num, mult := s[:idx], int64(1)
+	if strings.HasSuffix(num, "g") {
+		num, mult = strings.TrimSuffix(num, "g"), 1000
+	}
+	v, err := strconv.ParseInt(num, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v * mult
+}
+
+// classifyDarwinNICType determines NIC type from the BSD interface name and
+// the system_profiler hardware type.
+func classifyDarwinNICType(name, hwType string) string {
+	// Check name patterns first for tunnels, bridges, and VM interfaces.
+	n := strings.ToLower(name)
+	for _, prefix := range []string{"utun", "bridge", "awdl", "llw", "vmenet", "ipsec", "ppp", "gif", "stf"} {
+		if strings.HasPrefix(n, prefix) {
+			return nicTypeVirtual
+		}
+	}
+
+	t := strings.ToLower(hwType)
+	switch {
+	case t == "airport" || strings.Contains(t, "wi-fi"):
+		return nicTypeWiFi
+	case strings.HasPrefix(t, "vpn") || t == "bridge" || t == "ppp":
+		return nicTypeVirtual
+	default:
+		return nicTypeEthernet
+	}
+}
@@ -0,0 +1,209 @@
+//go:build darwin
+
+package profiler
+
+import (
+	"testing"
+)
+
+// Fixture: 16-inch MacBook Pro (2019) with integrated Intel and discrete AMD
+// GPUs, Wi-Fi, a USB Ethernet adapter, Thunderbolt Bridge, and a VPN tunnel.
+const systemProfilerFixture = `{
+  "SPHardwareDataType" : [
+    {
+      "_name" : "hardware_overview",
+      "boot_rom_version" : "2020.41.1.0.0",
+      "machine_model" : "MacBookPro16,1",
+      "machine_name" : "MacBook Pro",
+      "serial_number" : "C02ABC123DEF"
+    }
+  ],
+  "SPDisplaysDataType" : [
+    {
+      "_name" : "Intel UHD Graphics 630",
+      "sppci_bus" : "spdisplays_builtin",
+      "sppci_model" : "Intel UHD Graphics 630",
+      "spdisplays_vram_shared" : "1536 MB",
+      "spdisplays_vendor" : "Intel"
+    },
+    {
+      "_name" : "AMD Radeon Pro 5500M",
+      "sppci_bus" : "spdisplays_pcie_device",
+      "sppci_model" : "AMD Radeon Pro 5500M",
+      "spdisplays_vram" : "8 GB",
+      "spdisplays_vendor" : "sppci_vendor_amd"
+    }
+  ],
+  "SPNetworkDataType" : [
+    {
+      "_name" : "Wi-Fi",
+      "hardware" : "AirPort",
+      "interface" : "en0",
+      "type" : "AirPort",
+      "Ethernet" : {
+        "MAC Address" : "a4:83:e7:12:34:56",
+        "MediaOptions" : [],
+        "MediaSubType" : "autoselect"
+      }
+    },
+    {
+      "_name" : "USB 10/100/1000 LAN",
+      "hardware" : "Ethernet",
+      "interface" : "en7",
+      "type" : "Ethernet",
+      "Ethernet" : {
+        "MAC Address" : "00:e0:4c:68:01:02",
+        "MediaOptions" : ["Full Duplex"],
+        "MediaSubType" : "1000baseT"
+      }
+    },
+    {
+      "_name" : "Thunderbolt Bridge",
+      "hardware" : "Bridge",
+      "interface" : "bridge0",
+      "type" : "Bridge"
+    },
+    {
+      "_name" : "Corporate VPN",
+      "interface" : "utun3",
+      "type" : "VPN (com.wireguard.macos)"
+    },
+    {
+      "_name" : "PPPoE",
+      "type" : "PPP"
+    }
+  ]
+}`
+
+func TestParseSystemProfiler_GPUs(t *testing.T) {
+	report, err := parseSystemProfiler([]byte(systemProfilerFixture))
+	if err != nil {
+		t.Fatalf("parseSystemProfiler: %v", err)
+	}
+
+	gpus := darwinGPUs(report.Displays)
+	if len(gpus) != 2 {
+		t.Fatalf("got %d GPUs, want 2", len(gpus))
+	}
+
+	tests := []struct {
+		wantModel string
+		wantVRAM  int64
+	}{
+		{wantModel: "Intel UHD Graphics 630", wantVRAM: 1536 << 20},
+		{wantModel: "AMD Radeon Pro 5500M", wantVRAM: 8 << 30},
+	}
+	for i, tt := range tests {
+		if gpus[i].Model != tt.wantModel {
+			t.Errorf("gpu[%d].Model: got %q, want %q", i, gpus[i].Model, tt.wantModel)
+		}
+		if gpus[i].VramBytes != tt.wantVRAM {
+			t.Errorf("gpu[%d].VramBytes: got %d, want %d", i, gpus[i].VramBytes, tt.wantVRAM)
+		}
+	}
+
+	if len(report.Hardware) != 1 || report.Hardware[0].MachineModel != "MacBookPro16,1" {
+		t.Errorf("Hardware = %+v, want MacBookPro16,1", report.Hardware)
+	}
+}
+
+func TestParseSystemProfiler_NICs(t *testing.T) {
+	report, err := parseSystemProfiler([]byte(systemProfilerFixture))
+	if err != nil {
+		t.Fatalf("parseSystemProfiler: %v", err)
+	}
+
+	nics := darwinNICs(report.Network)
+	tests := []struct {
+		wantName  string
+		wantType  string
+		wantMAC   string
+		wantSpeed int64
+	}{
+		{wantName: "en0", wantType: "wifi", wantMAC: "a4:83:e7:12:34:56"},
+		{wantName: "en7", wantType: "ethernet", wantMAC: "00:e0:4c:68:01:02", wantSpeed: 1000},
+		{wantName: "bridge0", wantType: "virtual"},
+		{wantName: "utun3", wantType: "virtual"},
+	}
+	if len(nics) != len(tests) {
+		t.Fatalf("got %d NICs, want %d (PPPoE without interface skipped)", len(nics), len(tests))
+	}
+	for i, tt := range tests {
+		nic := nics[i]
+		if nic.Name != tt.wantName || nic.NicType != tt.wantType || nic.MacAddress != tt.wantMAC || nic.SpeedMbps != tt.wantSpeed {
+			t.Errorf("nic[%d] = {%q %q %q %d}, want {%q %q %q %d}", i,
+				nic.Name, nic.NicType, nic.MacAddress, nic.SpeedMbps,
+				tt.wantName, tt.wantType, tt.wantMAC, tt.wantSpeed)
+		}
+	}
+}
+
+func TestParseSystemProfiler_Invalid(t *testing.T) {
+	if _, err := parseSystemProfiler([]byte("not json")); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestClassifyDarwinNICType(t *testing.T) {
+	tests := []struct {
+		name     string
+		ifName   string
+		hwType   string
+		wantType string
+	}{
+		{name: "wifi airport", ifName: "en0", hwType: "AirPort", wantType: "wifi"},
+		{name: "wifi named", ifName: "en1", hwType: "Wi-Fi", wantType: "wifi"},
+		{name: "ethernet", ifName: "en7", hwType: "Ethernet", wantType: "ethernet"},
+		{name: "thunderbolt ethernet", ifName: "en2", hwType: "Thunderbolt Ethernet", wantType: "ethernet"},
+		{name: "virtual utun", ifName: "utun0", hwType: "", wantType: "virtual"},
+		{name: "virtual bridge", ifName: "bridge0", hwType: "Bridge", wantType: "virtual"},
+		{name: "virtual awdl", ifName: "awdl0", hwType: "", wantType: "virtual"},
+		{name: "virtual vpn type", ifName: "en9", hwType: "VPN (IKEv2)", wantType: "virtual"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyDarwinNICType(tt.ifName, tt.hwType)
+			if got != tt.wantType {
+				t.Errorf("classifyDarwinNICType(%q, %q): got %q, want %q", tt.ifName, tt.hwType, got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestParseSysctl(t *testing.T) {
+	output := "machdep.cpu.brand_string: Apple M1 Pro\nhw.physicalcpu: 10\nhw.logicalcpu: 10\nhw.memsize: 34359738368\n"
+	got := parseSysctl(output)
+
+	want := map[string]string{
+		"machdep.cpu.brand_string": "Apple M1 Pro",
+		"hw.physicalcpu":           "10",
+		"hw.logicalcpu":            "10",
+		"hw.memsize":               "34359738368",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: got %q, want %q", k, got[k], v)
+		}
+	}
+}
+
+func TestParseMediaSpeed(t *testing.T) {
+	tests := []struct {
+		subtype string
+		want    int64
+	}{
+		{subtype: "1000baseT", want: 1000},
+		{subtype: "100baseTX", want: 100},
+		{subtype: "10GbaseT", want: 10000},
+		{subtype: "2500Base-T", want: 2500},
+		{subtype: "autoselect", want: 0},
+		{subtype: "", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseMediaSpeed(tt.subtype); got != tt.want {
+			t.Errorf("parseMediaSpeed(%q): got %d, want %d", tt.subtype, got, tt.want)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-//go:build !windows
+//go:build !windows && !darwin
 
 package profiler
 
@@ -18,7 +18,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func collectHardware(_ context.Context, logger *zap.Logger) (*scoutpb.HardwareProfile, error) {
+func collectHardware(ctx context.Context, logger *zap.Logger) (*scoutpb.HardwareProfile, error) {
 	hw := &scoutpb.HardwareProfile{}
 
 	// CPU info from /proc/cpuinfo.
@@ -33,6 +33,9 @@ func collectHardware(_ context.Context, logger *zap.Logger) (*scoutpb.HardwarePr
 	// Disk info from /sys/block/.
 	hw.Disks = readBlockDevices(logger)
 
+	// SMART health from sysfs hwmon and smartctl.
+	readDiskHealth(ctx, logger, hw.Disks)
+
 	// NIC info from /sys/class/net/.
 	hw.Nics = readNetworkInterfaces(logger)
 
@@ -208,19 +211,19 @@ func readBlockDevices(logger *zap.Logger) []*scoutpb.DiskInfo {
 // classifyLinuxDiskType determines if a block device is NVMe, SSD, or HDD.
 func classifyLinuxDiskType(name, basePath string) string {
 	if strings.HasPrefix(name, "nvme") {
-		return "NVMe"
+		return diskTypeNVMe
 	}
 
 	// Check rotational flag: 0 = SSD, 1 = HDD.
 	rotData, err := os.ReadFile(filepath.Join(basePath, "queue", "rotational"))
 	if err == nil {
 		if strings.TrimSpace(string(rotData)) == "0" {
-			return "SSD"
+			return diskTypeSSD
 		}
-		return "HDD"
+		return diskTypeHDD
 	}
 
-	return "Unknown"
+	return diskTypeUnknown
 }
 
 // readNetworkInterfaces reads NIC info from /sys/class/net/.
@@ -278,20 +281,20 @@ func classifyLinuxNICType(name, kernelType string) string {
 	n := strings.ToLower(name)
 	switch {
 	case strings.HasPrefix(n, "wl") || strings.HasPrefix(n, "wlan"):
-		return "wifi"
+		return nicTypeWiFi
 	case strings.HasPrefix(n, "veth") || strings.HasPrefix(n, "docker") ||
 		strings.HasPrefix(n, "br-") || strings.HasPrefix(n, "virbr"):
-		return "virtual"
+		return nicTypeVirtual
 	}
 
 	// Kernel type codes.
 	switch kernelType {
 	case "1": // ARPHRD_ETHER
-		return "ethernet"
+		return nicTypeEthernet
 	case "801": // ARPHRD_IEEE80211 (wifi)
-		return "wifi"
+		return nicTypeWiFi
 	default:
-		return "ethernet"
+		return nicTypeEthernet
 	}
 }
 
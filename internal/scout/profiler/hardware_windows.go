@@ -48,11 +48,16 @@ func collectHardware(ctx context.Context, logger *zap.Logger) (*scoutpb.Hardware
 		hw.RamBytes = totalRAM
 	}
 
-	// Disk info.
+	// Disk info. Win32_DiskDrive reports SSDs as "Fixed hard disk media", so
+	// prefer the media and bus type from Get-PhysicalDisk when available.
 	diskRows, err := runWMIC(ctx, "diskdrive", "Model,Size,MediaType,SerialNumber")
 	if err != nil {
 		logger.Debug("wmic diskdrive failed", zap.Error(err))
 	} else {
+		physTypes, physErr := physicalDiskTypes(ctx)
+		if physErr != nil {
+			logger.Debug("Get-PhysicalDisk failed", zap.Error(physErr))
+		}
 		for _, row := range diskRows {
 			disk := &scoutpb.DiskInfo{
 				Model:  row["Model"],
@@ -62,6 +67,9 @@ func collectHardware(ctx context.Context, logger *zap.Logger) (*scoutpb.Hardware
 				disk.SizeBytes = v
 			}
 			disk.DiskType = classifyDiskType(row["MediaType"], row["Model"])
+			if t, ok := physTypes[row["Model"]]; ok && t != diskTypeUnknown {
+				disk.DiskType = t
+			}
 			disk.Name = row["Model"]
 			hw.Disks = append(hw.Disks, disk)
 		}
@@ -112,12 +120,19 @@ func collectHardware(ctx context.Context, logger *zap.Logger) (*scoutpb.Hardware
 	}
 
 	// System manufacturer and model.
-	csRows, err := runWMIC(ctx, "computersystem", "Manufacturer,Model")
+	csRows, err := runWMIC(ctx, "computersystem", "Manufacturer,Model,TotalPhysicalMemory")
 	if err != nil {
 		logger.Debug("wmic computersystem failed", zap.Error(err))
 	} else if len(csRows) > 0 {
 		hw.SystemManufacturer = csRows[0]["Manufacturer"]
 		hw.SystemModel = csRows[0]["Model"]
+		// Virtual machines often expose no memory chips; fall back to the
+		// OS-visible total.
+		if hw.RamBytes == 0 {
+			if v, e := strconv.ParseInt(csRows[0]["TotalPhysicalMemory"], 10, 64); e == nil {
+				hw.RamBytes = v
+			}
+		}
 	}
 
 	return hw, nil
@@ -256,30 +271,82 @@ func parseWMICCSV(data []byte) ([]map[string]string, error) {
 	return results, nil
 }
 
+// physicalDiskTypes maps disk friendly names to types classified from the
+// Storage module's Get-PhysicalDisk, which, unlike Win32_DiskDrive, knows
+// whether a disk is solid state. Friendly names match Win32_DiskDrive.Model.
+func physicalDiskTypes(ctx context.Context) (map[string]string, error) {
+	cmd := exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-Command",
+		"Get-PhysicalDisk | Select-Object FriendlyName,MediaType,BusType | ConvertTo-Csv -NoTypeInformation")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("powershell Get-PhysicalDisk: %w (stderr: %s)", err, stderr.String())
+	}
+
+	rows, err := parseWMICCSV(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return parsePhysicalDisks(rows), nil
+}
+
+// parsePhysicalDisks classifies Get-PhysicalDisk rows by friendly name.
+func parsePhysicalDisks(rows []map[string]string) map[string]string {
+	types := make(map[string]string, len(rows))
+	for _, row := range rows {
+		if name := row["FriendlyName"]; name != "" {
+			types[name] = classifyPhysicalDisk(row["MediaType"], row["BusType"])
+		}
+	}
+	return types
+}
+
+// classifyPhysicalDisk determines disk type from Get-PhysicalDisk's MediaType
+// and BusType, which ConvertTo-Csv renders as names ("SSD", "NVMe") or, on
+// some PowerShell versions, as the underlying enum numbers.
+func classifyPhysicalDisk(mediaType, busType string) string {
+	switch strings.ToLower(busType) {
+	case "nvme", "17":
+		return diskTypeNVMe
+	}
+	switch strings.ToLower(mediaType) {
+	case "ssd", "4":
+		return diskTypeSSD
+	case "hdd", "3":
+		return diskTypeHDD
+	}
+	return diskTypeUnknown
+}
+
+// classifyDiskType determines disk type from Win32_DiskDrive's MediaType and
+// model string.
 func classifyDiskType(mediaType, model string) string {
 	mt := strings.ToLower(mediaType)
 	mdl := strings.ToLower(model)
 
 	if strings.Contains(mdl, "nvme") {
-		return "NVMe"
+		return diskTypeNVMe
 	}
 	if strings.Contains(mdl, "ssd") || strings.Contains(mt, "ssd") || strings.Contains(mt, "solid") {
-		return "SSD"
+		return diskTypeSSD
 	}
 	if strings.Contains(mt, "fixed") || strings.Contains(mt, "hard") {
-		return "HDD"
+		return diskTypeHDD
 	}
-	return "Unknown"
+	return diskTypeUnknown
 }
 
+// classifyNICType determines NIC type from a Win32_NetworkAdapter name.
 func classifyNICType(name string) string {
 	n := strings.ToLower(name)
 	switch {
 	case strings.Contains(n, "wi-fi") || strings.Contains(n, "wifi") || strings.Contains(n, "wireless"):
-		return "wifi"
+		return nicTypeWiFi
 	case strings.Contains(n, "virtual") || strings.Contains(n, "hyper-v") || strings.Contains(n, "vmware"):
-		return "virtual"
+		return nicTypeVirtual
 	default:
-		return "ethernet"
+		return nicTypeEthernet
 	}
 }
@@ -0,0 +1,191 @@
+//go:build windows
+
+package profiler
+
+import (
+	"testing"
+)
+
+func TestParseWMICCSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantRows []map[string]string
+	}{
+		{
+			// Legacy wmic: leading blank line, CRLF endings, Node column.
+			name: "wmic cpu",
+			data: "\r\nNode,Name,NumberOfCores,NumberOfLogicalProcessors\r\n" +
+				"DESKTOP-01,Intel(R) Core(TM) i7-10700 CPU @ 2.90GHz,8,16\r\n",
+			wantRows: []map[string]string{{
+				"Node":                      "DESKTOP-01",
+				"Name":                      "Intel(R) Core(TM) i7-10700 CPU @ 2.90GHz",
+				"NumberOfCores":             "8",
+				"NumberOfLogicalProcessors": "16",
+			}},
+		},
+		{
+			// PowerShell ConvertTo-Csv: BOM, quoted values, embedded comma.
+			name: "cim memory",
+			data: "\xEF\xBB\xBF\"Capacity\",\"Manufacturer\"\r\n" +
+				"\"17179869184\",\"Samsung, Inc.\"\r\n" +
+				"\"17179869184\",\"Samsung, Inc.\"\r\n",
+			wantRows: []map[string]string{
+				{"Capacity": "17179869184", "Manufacturer": "Samsung, Inc."},
+				{"Capacity": "17179869184", "Manufacturer": "Samsung, Inc."},
+			},
+		},
+		{
+			name: "header only",
+			data: "\r\nNode,Name\r\n",
+		},
+		{
+			name: "empty",
+			data: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseWMICCSV([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("parseWMICCSV: %v", err)
+			}
+			if len(rows) != len(tt.wantRows) {
+				t.Fatalf("got %d rows, want %d: %v", len(rows), len(tt.wantRows), rows)
+			}
+			for i, want := range tt.wantRows {
+				for k, v := range want {
+					if rows[i][k] != v {
+						t.Errorf("row %d %s: got %q, want %q", i, k, rows[i][k], v)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestParsePhysicalDisks(t *testing.T) {
+	data := "\"FriendlyName\",\"MediaType\",\"BusType\"\r\n" +
+		"\"Samsung SSD 980 PRO 1TB\",\"SSD\",\"NVMe\"\r\n" +
+		"\"CT500MX500SSD1\",\"SSD\",\"SATA\"\r\n" +
+		"\"ST2000DM008-2FR102\",\"HDD\",\"SATA\"\r\n" +
+		"\"Msft Virtual Disk\",\"Unspecified\",\"SAS\"\r\n"
+
+	rows, err := parseWMICCSV([]byte(data))
+	if err != nil {
+		t.Fatalf("parseWMICCSV: %v", err)
+	}
+	got := parsePhysicalDisks(rows)
+
+	want := map[string]string{
+		"Samsung SSD 980 PRO 1TB": "NVMe",
+		"CT500MX500SSD1":          "SSD",
+		"ST2000DM008-2FR102":      "HDD",
+		"Msft Virtual Disk":       "Unknown",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d disks, want %d: %v", len(got), len(want), got)
+	}
+	for name, wantType := range want {
+		if got[name] != wantType {
+			t.Errorf("%s: got %q, want %q", name, got[name], wantType)
+		}
+	}
+}
+
+func TestClassifyPhysicalDisk(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		busType   string
+		wantType  string
+	}{
+		{name: "nvme bus", mediaType: "SSD", busType: "NVMe", wantType: "NVMe"},
+		{name: "nvme bus numeric", mediaType: "4", busType: "17", wantType: "NVMe"},
+		{name: "sata ssd", mediaType: "SSD", busType: "SATA", wantType: "SSD"},
+		{name: "sata ssd numeric", mediaType: "4", busType: "11", wantType: "SSD"},
+		{name: "sata hdd", mediaType: "HDD", busType: "SATA", wantType: "HDD"},
+		{name: "hdd numeric", mediaType: "3", busType: "11", wantType: "HDD"},
+		{name: "unspecified", mediaType: "Unspecified", busType: "SAS", wantType: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyPhysicalDisk(tt.mediaType, tt.busType)
+			if got != tt.wantType {
+				t.Errorf("classifyPhysicalDisk(%q, %q): got %q, want %q", tt.mediaType, tt.busType, got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestClassifyDiskType(t *testing.T) {
+	tests := []struct {
+		name      string
+		mediaType string
+		model     string
+		wantType  string
+	}{
+		{name: "nvme model", mediaType: "Fixed hard disk media", model: "WDC PC SN730 NVMe SSD", wantType: "NVMe"},
+		{name: "ssd model", mediaType: "Fixed hard disk media", model: "Samsung SSD 870 EVO 1TB", wantType: "SSD"},
+		{name: "fixed media", mediaType: "Fixed hard disk media", model: "ST2000DM008-2FR102", wantType: "HDD"},
+		{name: "removable", mediaType: "Removable Media", model: "SanDisk Cruzer USB Device", wantType: "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyDiskType(tt.mediaType, tt.model)
+			if got != tt.wantType {
+				t.Errorf("classifyDiskType(%q, %q): got %q, want %q", tt.mediaType, tt.model, got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestClassifyNICType(t *testing.T) {
+	tests := []struct {
+		name     string
+		nicName  string
+		wantType string
+	}{
+		{name: "wifi", nicName: "Intel(R) Wi-Fi 6 AX201 160MHz", wantType: "wifi"},
+		{name: "wireless", nicName: "Qualcomm Atheros Wireless Network Adapter", wantType: "wifi"},
+		{name: "hyper-v", nicName: "Hyper-V Virtual Ethernet Adapter", wantType: "virtual"},
+		{name: "vmware", nicName: "VMware Virtual Ethernet Adapter for VMnet8", wantType: "virtual"},
+		{name: "ethernet", nicName: "Intel(R) Ethernet Connection (7) I219-V", wantType: "ethernet"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyNICType(tt.nicName)
+			if got != tt.wantType {
+				t.Errorf("classifyNICType(%q): got %q, want %q", tt.nicName, got, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestWMICToCIMClass(t *testing.T) {
+	tests := []struct {
+		wmicClass  string
+		wantClass  string
+		wantFilter string
+		wantOK     bool
+	}{
+		{wmicClass: "cpu", wantClass: "Win32_Processor", wantOK: true},
+		{wmicClass: "path win32_videocontroller", wantClass: "Win32_VideoController", wantOK: true},
+		{wmicClass: `nic where "NetEnabled=true"`, wantClass: "Win32_NetworkAdapter", wantFilter: "NetEnabled=True", wantOK: true},
+		{wmicClass: "unknownalias"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.wmicClass, func(t *testing.T) {
+			class, filter, ok := wmicToCIMClass(tt.wmicClass)
+			if class != tt.wantClass || filter != tt.wantFilter || ok != tt.wantOK {
+				t.Errorf("wmicToCIMClass(%q) = %q, %q, %v; want %q, %q, %v",
+					tt.wmicClass, class, filter, ok, tt.wantClass, tt.wantFilter, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+//go:build !windows && !darwin
+
+package profiler
+
+import (
+	"testing"
+)
+
+const smartctlHeader = `smartctl 7.3 2022-02-28 r5338 [x86_64-linux-6.1.0-18-amd64] (local build)
+Copyright (C) 2002-22, Bruce Allen, Christian Franke, www.smartmontools.org
+
+`
+
+// Fixture: healthy SATA HDD.
+const smartctlHealthyATA = smartctlHeader + `=== START OF READ SMART DATA SECTION ===
+SMART Attributes Data Structure revision number: 16
+Vendor Specific SMART Attributes with Thresholds:
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  1 Raw_Read_Error_Rate     0x000f   118   099   006    Pre-fail  Always       -       185619408
+  5 Reallocated_Sector_Ct   0x0033   100   100   010    Pre-fail  Always       -       0
+  9 Power_On_Hours          0x0032   079   079   000    Old_age   Always       -       18562
+190 Airflow_Temperature_Cel 0x0022   066   055   040    Old_age   Always       -       34 (Min/Max 21/45)
+194 Temperature_Celsius     0x0022   034   045   000    Old_age   Always       -       34 (0 16 0 0 0)
+197 Current_Pending_Sector  0x0012   100   100   000    Old_age   Always       -       0
+198 Offline_Uncorrectable   0x0010   100   100   000    Old_age   Offline      -       0
+`
+
+// Fixture: failing SATA HDD with exhausted spare sectors.
+const smartctlFailingATA = smartctlHeader + `=== START OF READ SMART DATA SECTION ===
+SMART Attributes Data Structure revision number: 10
+Vendor Specific SMART Attributes with Thresholds:
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  1 Raw_Read_Error_Rate     0x002f   001   001   051    Pre-fail  Always   FAILING_NOW 48213
+  5 Reallocated_Sector_Ct   0x0033   001   001   010    Pre-fail  Always   FAILING_NOW 4088
+  9 Power_On_Hours          0x0032   012   012   000    Old_age   Always       -       64417h+33m+12.415s
+194 Temperature_Celsius     0x0022   108   095   000    Old_age   Always       -       42
+197 Current_Pending_Sector  0x0032   196   196   000    Old_age   Always       -       312
+`
+
+// Fixture: SATA SSD with a few reallocated sectors but no failed attributes.
+const smartctlWarningATA = smartctlHeader + `=== START OF READ SMART DATA SECTION ===
+ID# ATTRIBUTE_NAME          FLAG     VALUE WORST THRESH TYPE      UPDATED  WHEN_FAILED RAW_VALUE
+  5 Reallocated_Sector_Ct   0x0033   099   099   010    Pre-fail  Always       -       16
+  9 Power_On_Hours          0x0032   095   095   000    Old_age   Always       -       21873
+194 Temperature_Celsius     0x0022   067   052   000    Old_age   Always       -       33
+`
+
+// Fixture: healthy NVMe SSD.
+const smartctlHealthyNVMe = smartctlHeader + `=== START OF SMART DATA SECTION ===
+SMART/Health Information (NVMe Log 0x02)
+Critical Warning:                   0x00
+Temperature:                        38 Celsius
+Available Spare:                    100%
+Available Spare Threshold:          10%
+Percentage Used:                    2%
+Data Units Read:                    12,345,678 [6.32 TB]
+Power On Hours:                     4,321
+Unsafe Shutdowns:                   52
+Media and Data Integrity Errors:    0
+Temperature Sensor 1:               38 Celsius
+`
+
+// Fixture: NVMe SSD with its spare capacity below threshold.
+const smartctlCriticalNVMe = smartctlHeader + `=== START OF SMART DATA SECTION ===
+SMART/Health Information (NVMe Log 0x02)
+Critical Warning:                   0x01
+Temperature:                        51 Celsius
+Available Spare:                    4%
+Available Spare Threshold:          10%
+Percentage Used:                    87%
+Power On Hours:                     31,002
+Media and Data Integrity Errors:    17
+`
+
+// Fixture: smartctl run without root.
+const smartctlPermissionDenied = smartctlHeader + `Smartctl open device: /dev/sda failed: Permission denied
+`
+
+func TestParseSmartctl(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantOK      bool
+		wantHealth  string
+		wantTemp    int32
+		wantHours   int64
+		wantRealloc int64
+	}{
+		{name: "healthy ata", output: smartctlHealthyATA, wantOK: true, wantHealth: "healthy", wantTemp: 34, wantHours: 18562},
+		{name: "failing ata", output: smartctlFailingATA, wantOK: true, wantHealth: "failing", wantTemp: 42, wantHours: 64417, wantRealloc: 4088},
+		{name: "warning ata", output: smartctlWarningATA, wantOK: true, wantHealth: "warning", wantTemp: 33, wantHours: 21873, wantRealloc: 16},
+		{name: "healthy nvme", output: smartctlHealthyNVMe, wantOK: true, wantHealth: "healthy", wantTemp: 38, wantHours: 4321},
+		{name: "critical nvme", output: smartctlCriticalNVMe, wantOK: true, wantHealth: "failing", wantTemp: 51, wantHours: 31002},
+		{name: "permission denied", output: smartctlPermissionDenied},
+		{name: "empty", output: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseSmartctl(tt.output)
+			if ok != tt.wantOK {
+				t.Fatalf("ok: got %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got.health != tt.wantHealth {
+				t.Errorf("health: got %q, want %q", got.health, tt.wantHealth)
+			}
+			if got.temperature != tt.wantTemp {
+				t.Errorf("temperature: got %d, want %d", got.temperature, tt.wantTemp)
+			}
+			if got.powerOnHours != tt.wantHours {
+				t.Errorf("powerOnHours: got %d, want %d", got.powerOnHours, tt.wantHours)
+			}
+			if got.reallocatedSectors != tt.wantRealloc {
+				t.Errorf("reallocatedSectors: got %d, want %d", got.reallocatedSectors, tt.wantRealloc)
+			}
+		})
+	}
+}
+
+func TestLeadingInt(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{input: "18562", want: 18562},
+		{input: "4,321", want: 4321},
+		{input: "38 Celsius", want: 38},
+		{input: "64417h+33m+12.415s", want: 64417},
+		{input: "34 (Min/Max 21/45)", want: 34},
+		{input: "-", want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := leadingInt(tt.input); got != tt.want {
+			t.Errorf("leadingInt(%q): got %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+//go:build !windows && !darwin
+
+package profiler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	scoutpb "github.com/HerbHall/subnetree/api/proto/v1"
+	"go.uber.org/zap"
+)
+
+// SMART health labels reported in DiskInfo.SmartHealth.
+const (
+	smartHealthy = "healthy"
+	smartWarning = "warning"
+	smartFailing = "failing"
+	smartUnknown = "unknown"
+)
+
+// smartctlTimeout bounds each smartctl run.
+const smartctlTimeout = 10 * time.Second
+
+// smartData holds the health fields parsed from smartctl -A output.
+type smartData struct {
+	health             string
+	temperature        int32
+	powerOnHours       int64
+	reallocatedSectors int64
+}
+
+// readDiskHealth fills SMART health fields for each disk. Temperature comes
+// from sysfs hwmon when the kernel exposes it (NVMe, or SATA with the
+// drivetemp module); the other attributes need smartctl, which usually
+// requires root. Health stays "unknown" when smartctl is missing or cannot
+// read a disk.
+func readDiskHealth(ctx context.Context, logger *zap.Logger, disks []*scoutpb.DiskInfo) {
+	smartctl, err := exec.LookPath("smartctl")
+	if err != nil {
+		logger.Debug("smartctl not available for disk health", zap.Error(err))
+	}
+
+	for _, disk := range disks {
+		disk.SmartHealth = smartUnknown
+		disk.TemperatureCelsius = readHwmonTemperature("/sys/block/" + disk.Name)
+		if smartctl == "" {
+			continue
+		}
+
+		output := runSmartctl(ctx, smartctl, disk.Name)
+		data, ok := parseSmartctl(output)
+		if !ok {
+			logger.Debug("no SMART attributes for disk (unsupported or insufficient privileges)",
+				zap.String("disk", disk.Name))
+			continue
+		}
+		disk.SmartHealth = data.health
+		disk.PowerOnHours = data.powerOnHours
+		disk.ReallocatedSectors = data.reallocatedSectors
+		if disk.TemperatureCelsius == 0 {
+			disk.TemperatureCelsius = data.temperature
+		}
+	}
+}
+
+// readHwmonTemperature reads a block device's temperature in Celsius from its
+// hwmon sensor. Returns 0 if the device has no sensor.
+func readHwmonTemperature(basePath string) int32 {
+	for _, pattern := range []string{
+		filepath.Join(basePath, "device", "hwmon*", "temp1_input"),          // NVMe
+		filepath.Join(basePath, "device", "hwmon", "hwmon*", "temp1_input"), // drivetemp
+	} {
+		matches, _ := filepath.Glob(pattern)
+		for _, path := range matches {
+			v, err := strconv.ParseInt(readSysfsField(path), 10, 64)
+			if err == nil && v > 0 {
+				return int32(v / 1000) //nolint:gosec // G115: millidegrees fit in int32
+			}
+		}
+	}
+	return 0
+}
+
+// runSmartctl returns smartctl -A output for a disk. smartctl's exit status
+// is a bitmask that is non-zero for failing disks too, so the output is
+// returned regardless and the caller decides from its contents.
+func runSmartctl(ctx context.Context, smartctl, name string) string {
+	ctx, cancel := context.WithTimeout(ctx, smartctlTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, smartctl, "-A", "/dev/"+name)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	_ = cmd.Run()
+	return stdout.String()
+}
+
+// parseSmartctl parses smartctl -A output for ATA (attribute table) and NVMe
+// (health log) devices. Returns false if the output contains no attributes.
+//
+// A disk is failing when an ATA attribute is FAILING_NOW or the NVMe critical
+// warning is set, and in warning when it has reallocated, pending, or
+// uncorrectable sectors, an attribute failed in the past, NVMe media errors,
+// or has used up its rated endurance.
+func parseSmartctl(output string) (smartData, bool) {
+	data := smartData{health: smartHealthy}
+	found := false
+	warn := func() {
+		if data.health == smartHealthy {
+			data.health = smartWarning
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// NVMe health log: "Key:   value".
+		if key, value, ok := strings.Cut(line, ":"); ok {
+			value = strings.TrimSpace(value)
+			switch strings.TrimSpace(key) {
+			case "Critical Warning":
+				found = true
+				if v, err := strconv.ParseInt(strings.TrimPrefix(value, "0x"), 16, 64); err == nil && v != 0 {
+					data.health = smartFailing
+				}
+			case "Temperature":
+				found = true
+				data.temperature = int32(leadingInt(value)) //nolint:gosec // G115: temperature fits in int32
+			case "Power On Hours":
+				found = true
+				data.powerOnHours = leadingInt(value)
+			case "Media and Data Integrity Errors":
+				found = true
+				if leadingInt(value) > 0 {
+					warn()
+				}
+			case "Percentage Used":
+				found = true
+				if leadingInt(value) >= 100 {
+					warn()
+				}
+			}
+			continue
+		}
+
+		// ATA attribute table:
+		// ID# ATTRIBUTE_NAME FLAG VALUE WORST THRESH TYPE UPDATED WHEN_FAILED RAW_VALUE
+		fields := strings.Fields(line)
+		if len(fields) < 10 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue
+		}
+		found = true
+		raw := leadingInt(fields[9])
+
+		switch fields[8] {
+		case "FAILING_NOW":
+			data.health = smartFailing
+		case "In_the_past":
+			warn()
+		}
+
+		switch fields[1] {
+		case "Reallocated_Sector_Ct":
+			data.reallocatedSectors = raw
+			if raw > 0 {
+				warn()
+			}
+		case "Current_Pending_Sector", "Offline_Uncorrectable":
+			if raw > 0 {
+				warn()
+			}
+		case "Power_On_Hours":
+			data.powerOnHours = raw
+		case "Temperature_Celsius":
+			data.temperature = int32(raw) //nolint:gosec // G115: temperature fits in int32
+		case "Airflow_Temperature_Cel":
+			if data.temperature == 0 {
+				data.temperature = int32(raw) //nolint:gosec // G115: temperature fits in int32
+			}
+		}
+	}
+
+	if !found {
+		return smartData{}, false
+	}
+	return data, true
+}
+
+// leadingInt parses the integer at the start of a smartctl value, ignoring
+// thousands separators and trailing units ("4,321", "38 Celsius",
+// "18562h+12m+05.123s"). Returns 0 if there is none.
+func leadingInt(s string) int64 {
+	var n int64
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			n = n*10 + int64(r-'0')
+		case r == ',':
+		default:
+			return n
+		}
+	}
+	return n
+}
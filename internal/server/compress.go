@@ -0,0 +1,246 @@
+package server
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// compressMinSize is the smallest response body worth compressing; below it
+// the encoding overhead outweighs the savings.
+const compressMinSize = 1024
+
+var (
+	gzipWriterPool = sync.Pool{New: func() any { return gzip.NewWriter(io.Discard) }}
+	zlibWriterPool = sync.Pool{New: func() any { return zlib.NewWriter(io.Discard) }}
+)
+
+// CompressionMiddleware gzip- or deflate-encodes responses whose body is at
+// least minSize bytes, according to the request's Accept-Encoding. Bodies
+// are buffered until minSize is reached, so small responses are sent as-is.
+//
+// Only textual content types are compressed. Event streams, downloads
+// (Content-Disposition: attachment), responses that already carry a
+// Content-Encoding, range requests, and connection upgrades pass through
+// untouched.
+func CompressionMiddleware(minSize int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+			if encoding == "" || r.Method == http.MethodHead ||
+				r.Header.Get("Range") != "" || r.Header.Get("Upgrade") != "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, encoding: encoding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header,
+// preferring gzip when both are equally acceptable. It returns "" when
+// neither is accepted.
+func negotiateEncoding(accept string) string {
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(accept, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		weights[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, coding := range []string{"gzip", "deflate"} {
+		q, ok := weights[coding]
+		if !ok {
+			q, ok = weights["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = coding, q
+		}
+	}
+	return best
+}
+
+// compressibleType reports whether a Content-Type is text that compresses
+// well. Binary formats are usually compressed already.
+func compressibleType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case mediaType == "text/event-stream":
+		return false
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/javascript", "application/xml", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// compressWriter buffers the start of a response until it knows whether to
+// compress it: once minSize bytes are buffered the encoder is started, and
+// if the handler finishes or flushes first the body is sent uncompressed.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	status   int
+	eligible bool // Status and headers allow compression
+	decided  bool // Headers have been sent
+	buf      []byte
+	enc      io.WriteCloser // Nil unless compressing
+}
+
+func (cw *compressWriter) WriteHeader(code int) {
+	if cw.status == 0 {
+		cw.status = code
+	}
+}
+
+func (cw *compressWriter) Write(b []byte) (int, error) {
+	if !cw.decided {
+		cw.inspect()
+		if !cw.eligible {
+			if err := cw.commit(false); err != nil {
+				return 0, err
+			}
+		} else {
+			cw.buf = append(cw.buf, b...)
+			if len(cw.buf) < cw.minSize {
+				return len(b), nil
+			}
+			if err := cw.commit(true); err != nil {
+				return 0, err
+			}
+			return len(b), nil
+		}
+	}
+	if cw.enc != nil {
+		return cw.enc.Write(b)
+	}
+	return cw.ResponseWriter.Write(b)
+}
+
+// Flush sends the headers and anything buffered. A response flushed before
+// reaching minSize is streamed uncompressed from then on.
+func (cw *compressWriter) Flush() {
+	if !cw.decided {
+		cw.inspect()
+		if err := cw.commit(false); err != nil {
+			return
+		}
+	}
+	if f, ok := cw.enc.(interface{ Flush() error }); ok {
+		if err := f.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (cw *compressWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// inspect records the status and decides from the response headers whether
+// the body may be compressed.
+func (cw *compressWriter) inspect() {
+	if cw.status == 0 {
+		cw.status = http.StatusOK
+	}
+	h := cw.Header()
+	cw.eligible = cw.status >= http.StatusOK &&
+		cw.status != http.StatusNoContent &&
+		cw.status != http.StatusPartialContent &&
+		cw.status != http.StatusNotModified &&
+		h.Get("Content-Encoding") == "" &&
+		!strings.HasPrefix(h.Get("Content-Disposition"), "attachment") &&
+		compressibleType(h.Get("Content-Type"))
+}
+
+// commit sends the headers and any buffered body, starting the encoder if
+// compress is set.
+func (cw *compressWriter) commit(compress bool) error {
+	cw.decided = true
+	h := cw.Header()
+	if cw.eligible {
+		h.Add("Vary", "Accept-Encoding")
+	}
+	if compress {
+		h.Set("Content-Encoding", cw.encoding)
+		h.Del("Content-Length")
+		cw.enc = newEncoder(cw.encoding, cw.ResponseWriter)
+	}
+	cw.ResponseWriter.WriteHeader(cw.status)
+
+	buf := cw.buf
+	cw.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if cw.enc != nil {
+		_, err = cw.enc.Write(buf)
+	} else {
+		_, err = cw.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// close sends a response that never reached minSize and finishes the
+// encoder of one that did.
+func (cw *compressWriter) close() {
+	if !cw.decided {
+		if cw.status == 0 && len(cw.buf) == 0 {
+			return // Nothing written; net/http sends the default 200.
+		}
+		cw.inspect()
+		_ = cw.commit(false)
+	}
+	if cw.enc == nil {
+		return
+	}
+	_ = cw.enc.Close()
+	switch enc := cw.enc.(type) {
+	case *gzip.Writer:
+		gzipWriterPool.Put(enc)
+	case *zlib.Writer:
+		zlibWriterPool.Put(enc)
+	}
+}
+
+func newEncoder(encoding string, w io.Writer) io.WriteCloser {
+	if encoding == "gzip" {
+		gz := gzipWriterPool.Get().(*gzip.Writer)
+		gz.Reset(w)
+		return gz
+	}
+	zw := zlibWriterPool.Get().(*zlib.Writer)
+	zw.Reset(w)
+	return zw
+}
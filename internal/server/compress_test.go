@@ -0,0 +1,226 @@
+package server
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+// largeJSON is a JSON body comfortably above compressMinSize.
+var largeJSON = `{"items":[` + strings.Repeat(`{"hostname":"device","status":"online"},`, 100) + `{}]}`
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, body)
+	}
+}
+
+func serveCompressed(t *testing.T, h http.Handler, acceptEncoding string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/test", http.NoBody)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	CompressionMiddleware(compressMinSize)(h).ServeHTTP(w, req)
+	return w
+}
+
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var r io.Reader = w.Body
+	switch w.Header().Get("Content-Encoding") {
+	case "gzip":
+		gz, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("gzip reader: %v", err)
+		}
+		r = gz
+	case "deflate":
+		zr, err := zlib.NewReader(w.Body)
+		if err != nil {
+			t.Fatalf("zlib reader: %v", err)
+		}
+		r = zr
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return string(b)
+}
+
+func TestCompressionMiddleware_CompressesLargeJSON(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			w := serveCompressed(t, jsonHandler(largeJSON), encoding)
+
+			if got := w.Header().Get("Content-Encoding"); got != encoding {
+				t.Fatalf("Content-Encoding = %q, want %q", got, encoding)
+			}
+			if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Vary = %q, want Accept-Encoding", got)
+			}
+			if w.Body.Len() >= len(largeJSON) {
+				t.Errorf("compressed size %d not smaller than %d", w.Body.Len(), len(largeJSON))
+			}
+			if body := decodeBody(t, w); body != largeJSON {
+				t.Error("decompressed body does not match original")
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_SmallResponseUncompressed(t *testing.T) {
+	w := serveCompressed(t, jsonHandler(`{"status":"ok"}`), "gzip")
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("Vary = %q, want Accept-Encoding", got)
+	}
+	if w.Body.String() != `{"status":"ok"}` {
+		t.Errorf("body = %q", w.Body.String())
+	}
+}
+
+func TestCompressionMiddleware_PreservesStatus(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, largeJSON)
+	})
+	w := serveCompressed(t, h, "gzip")
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", w.Code)
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+}
+
+func TestCompressionMiddleware_Exempt(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		accept  string
+	}{
+		{name: "no accept-encoding", headers: map[string]string{"Content-Type": "application/json"}},
+		{name: "identity only", headers: map[string]string{"Content-Type": "application/json"}, accept: "identity"},
+		{name: "gzip refused", headers: map[string]string{"Content-Type": "application/json"}, accept: "gzip;q=0, deflate;q=0"},
+		{name: "event stream", headers: map[string]string{"Content-Type": "text/event-stream"}, accept: "gzip"},
+		{name: "download", headers: map[string]string{"Content-Type": "text/csv", "Content-Disposition": `attachment; filename="devices.csv"`}, accept: "gzip"},
+		{name: "already encoded", headers: map[string]string{"Content-Type": "application/json", "Content-Encoding": "br"}, accept: "gzip"},
+		{name: "binary", headers: map[string]string{"Content-Type": "image/png"}, accept: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				_, _ = io.WriteString(w, largeJSON)
+			})
+			w := serveCompressed(t, h, tt.accept)
+
+			if got, want := w.Header().Get("Content-Encoding"), tt.headers["Content-Encoding"]; got != want {
+				t.Errorf("Content-Encoding = %q, want %q", got, want)
+			}
+			if w.Body.String() != largeJSON {
+				t.Error("body was modified")
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_StreamFlushes(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, "event: chunk\ndata: {}\n\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+	})
+	w := serveCompressed(t, h, "gzip")
+
+	if !w.Flushed {
+		t.Error("expected flush to reach the underlying writer")
+	}
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Content-Encoding = %q, want none", got)
+	}
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, deflate, br", "gzip"},
+		{"deflate, gzip;q=0.5", "deflate"},
+		{"GZIP", "gzip"},
+		{"*", "gzip"},
+		{"*;q=0.5, gzip;q=0", "deflate"},
+		{"br, identity", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.accept); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.accept, got, tt.want)
+		}
+	}
+}
+
+func TestServer_CompressesPluginResponses(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	plugins := &mockPluginSource{
+		plugins: []plugin.Plugin{},
+		routes: map[string][]plugin.Route{
+			"recon": {
+				{Method: "GET", Path: "/devices", Handler: jsonHandler(largeJSON)},
+				{Method: "GET", Path: "/events", Handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.Header().Set("Content-Type", "text/event-stream")
+					_, _ = io.WriteString(w, largeJSON)
+				}},
+			},
+		},
+	}
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/api/v1/recon/devices")
+	if got := w.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("Content-Encoding = %q, want gzip", got)
+	}
+	if w.Header().Get("X-Request-ID") == "" {
+		t.Error("expected outer middleware headers on compressed response")
+	}
+	if body := decodeBody(t, w); body != largeJSON {
+		t.Error("decompressed body does not match original")
+	}
+
+	if w := get("/api/v1/recon/events"); w.Header().Get("Content-Encoding") != "" {
+		t.Errorf("SSE Content-Encoding = %q, want none", w.Header().Get("Content-Encoding"))
+	}
+}
@@ -8,9 +8,12 @@ import (
 
 // Config holds the server configuration.
 type Config struct {
-	Host    string `mapstructure:"host"`
-	Port    int    `mapstructure:"port"`
-	DataDir string `mapstructure:"data_dir"`
+	Host      string          `mapstructure:"host"`
+	Port      int             `mapstructure:"port"`
+	DataDir   string          `mapstructure:"data_dir"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+	Limits    RequestLimits   `mapstructure:"limits"`
 }
 
 // Addr returns the listen address as host:port.
@@ -26,10 +29,29 @@ func LoadConfig(configPath string) (*viper.Viper, error) {
 	v.SetDefault("server.host", "0.0.0.0")
 	v.SetDefault("server.port", 8080)
 	v.SetDefault("server.data_dir", "./data")
+	v.SetDefault("server.cors.allowed_origins", []string{})
+	v.SetDefault("server.cors.allowed_methods", defaultCORSMethods)
+	v.SetDefault("server.cors.allowed_headers", defaultCORSHeaders)
+	v.SetDefault("server.cors.allow_credentials", false)
+	v.SetDefault("server.cors.max_age", "10m")
+	v.SetDefault("server.rate_limit.anonymous.rps", 100)
+	v.SetDefault("server.rate_limit.anonymous.burst", 200)
+	v.SetDefault("server.rate_limit.authenticated.rps", 100)
+	v.SetDefault("server.rate_limit.authenticated.burst", 200)
+	v.SetDefault("server.limits.max_body_bytes", 1<<20)
+	v.SetDefault("server.limits.max_upload_bytes", 10<<20)
+	v.SetDefault("server.limits.handler_timeout", "14s")
+	v.SetDefault("server.event_recorder.size", 256)
+	v.SetDefault("tracing.exporter", "none")
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.insecure", false)
+	v.SetDefault("tracing.sample_ratio", 1.0)
 	v.SetDefault("logging.level", "info")
 	v.SetDefault("logging.format", "json")
 	v.SetDefault("database.driver", "sqlite")
-	v.SetDefault("database.dsn", "./data/subnetree.db")
+	v.SetDefault("database.dsn", "")
+	v.SetDefault("database.experimental_postgres", false)
+	v.SetDefault("database.backup_dir", "")
 
 	// Plugin defaults
 	v.SetDefault("plugins.recon.enabled", true)
@@ -44,9 +66,13 @@ func LoadConfig(configPath string) (*viper.Viper, error) {
 	v.SetDefault("plugins.pulse.ping_timeout", "5s")
 	v.SetDefault("plugins.pulse.ping_count", 3)
 	v.SetDefault("plugins.pulse.consecutive_failures", 3)
-	v.SetDefault("plugins.pulse.retention_period", "720h")
+	v.SetDefault("plugins.pulse.retention_period", "2160h")
 	v.SetDefault("plugins.pulse.max_workers", 10)
 	v.SetDefault("plugins.pulse.maintenance_interval", "1h")
+	v.SetDefault("plugins.pulse.prune_batch_size", 1000)
+	v.SetDefault("plugins.pulse.rollup_retention", "8760h")
+	v.SetDefault("plugins.pulse.escalate_after", "1h")
+	v.SetDefault("plugins.pulse.flap_window", "15m")
 	v.SetDefault("plugins.dispatch.enabled", true)
 	v.SetDefault("plugins.vault.enabled", true)
 	v.SetDefault("plugins.vault.audit_retention_period", "2160h")
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSConfig controls which cross-origin browser clients may call the API.
+// With no AllowedOrigins the server is same-origin only and sends no CORS
+// headers.
+type CORSConfig struct {
+	AllowedOrigins   []string      `mapstructure:"allowed_origins"` // "*" allows any origin
+	AllowedMethods   []string      `mapstructure:"allowed_methods"`
+	AllowedHeaders   []string      `mapstructure:"allowed_headers"` // "*" allows any header
+	AllowCredentials bool          `mapstructure:"allow_credentials"`
+	MaxAge           time.Duration `mapstructure:"max_age"` // How long browsers may cache a preflight
+}
+
+// CORS defaults applied by LoadConfig.
+var (
+	defaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	defaultCORSHeaders = []string{"Authorization", "Content-Type", "X-Request-ID"}
+)
+
+// CORSMiddleware answers preflight requests and adds CORS headers to
+// responses for origins in cfg.AllowedOrigins. Requests from other origins
+// are served without CORS headers, so browsers keep enforcing the
+// same-origin policy; their preflights are rejected with 403.
+func CORSMiddleware(cfg CORSConfig) Middleware {
+	if len(cfg.AllowedOrigins) == 0 {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	anyOrigin := slices.Contains(cfg.AllowedOrigins, "*")
+	anyHeader := slices.Contains(cfg.AllowedHeaders, "*")
+	methods := make(map[string]bool, len(cfg.AllowedMethods))
+	for _, m := range cfg.AllowedMethods {
+		methods[strings.ToUpper(m)] = true
+	}
+	headers := make(map[string]bool, len(cfg.AllowedHeaders))
+	for _, h := range cfg.AllowedHeaders {
+		headers[http.CanonicalHeaderKey(h)] = true
+	}
+	allowMethods := strings.Join(cfg.AllowedMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	originAllowed := func(origin string) bool {
+		return anyOrigin || slices.Contains(cfg.AllowedOrigins, origin)
+	}
+
+	// headersAllowed checks an Access-Control-Request-Headers list.
+	headersAllowed := func(requested string) bool {
+		if anyHeader {
+			return true
+		}
+		for _, h := range strings.Split(requested, ",") {
+			if h = strings.TrimSpace(h); h != "" && !headers[http.CanonicalHeaderKey(h)] {
+				return false
+			}
+		}
+		return true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			h := w.Header()
+			h.Add("Vary", "Origin")
+
+			reqMethod := r.Header.Get("Access-Control-Request-Method")
+			if r.Method == http.MethodOptions && reqMethod != "" {
+				h.Add("Vary", "Access-Control-Request-Method")
+				h.Add("Vary", "Access-Control-Request-Headers")
+				reqHeaders := r.Header.Get("Access-Control-Request-Headers")
+				if !originAllowed(origin) || !methods[reqMethod] || !headersAllowed(reqHeaders) {
+					Forbidden(w, "cross-origin request not allowed", r.URL.Path)
+					return
+				}
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Set("Access-Control-Allow-Methods", allowMethods)
+				if anyHeader && reqHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", reqHeaders)
+				} else if allowHeaders != "" {
+					h.Set("Access-Control-Allow-Headers", allowHeaders)
+				}
+				if cfg.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+				if cfg.MaxAge > 0 {
+					h.Set("Access-Control-Max-Age", maxAge)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			if originAllowed(origin) {
+				// Echo the origin rather than "*" so credentialed requests
+				// work and caches key on Vary: Origin.
+				h.Set("Access-Control-Allow-Origin", origin)
+				h.Set("Access-Control-Expose-Headers", "X-Request-ID, X-SubNetree-Version, X-Total-Count")
+				if cfg.AllowCredentials {
+					h.Set("Access-Control-Allow-Credentials", "true")
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
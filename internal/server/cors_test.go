@@ -0,0 +1,186 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+func testCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedOrigins:   []string{"http://localhost:5173"},
+		AllowedMethods:   defaultCORSMethods,
+		AllowedHeaders:   defaultCORSHeaders,
+		AllowCredentials: true,
+		MaxAge:           10 * time.Minute,
+	}
+}
+
+// serveCORS runs a request through CORSMiddleware and reports whether the
+// wrapped handler was reached.
+func serveCORS(cfg CORSConfig, req *http.Request) (w *httptest.ResponseRecorder, reached bool) {
+	h := CORSMiddleware(cfg)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w, reached
+}
+
+func preflight(origin, method, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/recon/scan", http.NoBody)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	w, reached := serveCORS(testCORSConfig(), preflight("http://localhost:5173", "POST", "authorization, content-type"))
+
+	if reached {
+		t.Error("preflight should be answered without calling the handler")
+	}
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", w.Code)
+	}
+	want := map[string]string{
+		"Access-Control-Allow-Origin":      "http://localhost:5173",
+		"Access-Control-Allow-Methods":     "GET, POST, PUT, PATCH, DELETE",
+		"Access-Control-Allow-Headers":     "Authorization, Content-Type, X-Request-ID",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Max-Age":           "600",
+	}
+	for k, v := range want {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestCORSMiddleware_PreflightRejected(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *http.Request
+	}{
+		{name: "origin", req: preflight("https://evil.example", "GET", "")},
+		{name: "method", req: preflight("http://localhost:5173", "TRACE", "")},
+		{name: "header", req: preflight("http://localhost:5173", "GET", "X-Custom")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, reached := serveCORS(testCORSConfig(), tt.req)
+			if reached {
+				t.Error("rejected preflight should not reach the handler")
+			}
+			if w.Code != http.StatusForbidden {
+				t.Errorf("status = %d, want 403", w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_ActualRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        CORSConfig
+		origin     string
+		wantOrigin string
+	}{
+		{name: "allowed origin echoed", cfg: testCORSConfig(), origin: "http://localhost:5173", wantOrigin: "http://localhost:5173"},
+		{name: "disallowed origin", cfg: testCORSConfig(), origin: "https://evil.example"},
+		{name: "same-origin default", cfg: CORSConfig{}, origin: "http://localhost:5173"},
+		{name: "wildcard echoes origin", cfg: CORSConfig{AllowedOrigins: []string{"*"}}, origin: "https://any.example", wantOrigin: "https://any.example"},
+		{name: "no origin header", cfg: testCORSConfig()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/health", http.NoBody)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			w, reached := serveCORS(tt.cfg, req)
+
+			// CORS is enforced by the browser; the request itself is served.
+			if !reached || w.Code != http.StatusOK {
+				t.Errorf("reached = %v, status = %d; want handler to run", reached, w.Code)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_DisabledPassesPreflightThrough(t *testing.T) {
+	_, reached := serveCORS(CORSConfig{}, preflight("http://localhost:5173", "GET", ""))
+	if !reached {
+		t.Error("with CORS disabled OPTIONS should reach the handler")
+	}
+}
+
+func TestLoadConfig_CORSDefaultsToSameOrigin(t *testing.T) {
+	v, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if origins := v.GetStringSlice("server.cors.allowed_origins"); len(origins) != 0 {
+		t.Errorf("allowed_origins = %v, want none", origins)
+	}
+	if methods := v.GetStringSlice("server.cors.allowed_methods"); len(methods) != len(defaultCORSMethods) {
+		t.Errorf("allowed_methods = %v, want %v", methods, defaultCORSMethods)
+	}
+}
+
+func TestServer_CORS(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	plugins := &mockPluginSource{
+		plugins: []plugin.Plugin{},
+		routes: map[string][]plugin.Route{
+			"recon": {
+				{Method: "POST", Path: "/scan", Handler: func(w http.ResponseWriter, _ *http.Request) {
+					w.WriteHeader(http.StatusAccepted)
+				}},
+			},
+		},
+	}
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, testCORSConfig(), RateLimitConfig{}, RequestLimits{})
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := serve(preflight("http://localhost:5173", "POST", "content-type")); w.Code != http.StatusNoContent {
+		t.Errorf("preflight status = %d, want 204", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/recon/scan", http.NoBody)
+	req.Header.Set("Origin", "http://localhost:5173")
+	w := serve(req)
+	if w.Code != http.StatusAccepted {
+		t.Errorf("status = %d, want 202", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:5173" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+
+	// Operational endpoints keep working for any caller.
+	for _, path := range []string{"/healthz", "/readyz"} {
+		req := httptest.NewRequest(http.MethodGet, path, http.NoBody)
+		req.Header.Set("Origin", "https://evil.example")
+		if w := serve(req); w.Code != http.StatusOK {
+			t.Errorf("GET %s status = %d, want 200", path, w.Code)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+// RequestLimits bounds plugin route requests by route class. Zero values
+// disable the corresponding limit.
+type RequestLimits struct {
+	MaxBodyBytes   int64         `mapstructure:"max_body_bytes"`   // RouteDefault, RouteStream and RouteLongRunning
+	MaxUploadBytes int64         `mapstructure:"max_upload_bytes"` // RouteUpload
+	HandlerTimeout time.Duration `mapstructure:"handler_timeout"`  // RouteDefault and RouteUpload
+}
+
+// handlerTimeoutBody is sent with the 503 when a handler overruns its
+// timeout.
+const handlerTimeoutBody = "request timed out"
+
+// wrap applies the body size limit and handler timeout for class to h.
+// Classes without a handler timeout also lift the server's write timeout,
+// which would otherwise cut their responses off regardless.
+func (l RequestLimits) wrap(class plugin.RouteClass, h http.Handler) http.Handler {
+	maxBody, timeout := l.MaxBodyBytes, l.HandlerTimeout
+	switch class {
+	case plugin.RouteUpload:
+		maxBody = l.MaxUploadBytes
+	case plugin.RouteStream, plugin.RouteLongRunning:
+		// http.TimeoutHandler buffers the whole response and cannot flush.
+		timeout = 0
+	case plugin.RouteProxy:
+		maxBody, timeout = 0, 0
+	}
+
+	if timeout > 0 {
+		h = http.TimeoutHandler(h, timeout, handlerTimeoutBody)
+	} else {
+		h = noWriteDeadline(h)
+	}
+	if maxBody > 0 {
+		h = limitBody(maxBody, h)
+	}
+	return h
+}
+
+// limitBody rejects requests whose declared Content-Length exceeds maxBytes
+// with 413, and caps bodies of unknown length so reads fail past maxBytes.
+func limitBody(maxBytes int64, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ContentLength > maxBytes {
+			PayloadTooLarge(w, fmt.Sprintf("request body exceeds %d bytes", maxBytes), r.URL.Path)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// noWriteDeadline clears the connection's write deadline before calling
+// next. Writers that do not support deadlines are left as they are.
+func noWriteDeadline(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.uber.org/zap"
+)
+
+var testLimits = RequestLimits{
+	MaxBodyBytes:   16,
+	MaxUploadBytes: 64,
+	HandlerTimeout: 50 * time.Millisecond,
+}
+
+// readAllHandler reads the whole body and reports read failures as 400.
+func readAllHandler(w http.ResponseWriter, r *http.Request) {
+	if _, err := io.ReadAll(r.Body); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			PayloadTooLarge(w, err.Error(), r.URL.Path)
+			return
+		}
+		BadRequest(w, err.Error(), r.URL.Path)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func postBody(h http.Handler, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/recon/devices/import", strings.NewReader(body))
+	if chunked {
+		req.ContentLength = -1
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestLimits_BodySize(t *testing.T) {
+	small := strings.Repeat("a", 10)
+	medium := strings.Repeat("a", 40)
+	large := strings.Repeat("a", 100)
+
+	tests := []struct {
+		name    string
+		class   plugin.RouteClass
+		body    string
+		chunked bool
+		want    int
+	}{
+		{name: "default within limit", class: plugin.RouteDefault, body: small, want: http.StatusOK},
+		{name: "default over limit", class: plugin.RouteDefault, body: medium, want: http.StatusRequestEntityTooLarge},
+		{name: "default over limit chunked", class: plugin.RouteDefault, body: medium, chunked: true, want: http.StatusRequestEntityTooLarge},
+		{name: "upload allows more", class: plugin.RouteUpload, body: medium, want: http.StatusOK},
+		{name: "upload over limit", class: plugin.RouteUpload, body: large, want: http.StatusRequestEntityTooLarge},
+		{name: "stream uses default limit", class: plugin.RouteStream, body: medium, want: http.StatusRequestEntityTooLarge},
+		{name: "proxy unlimited", class: plugin.RouteProxy, body: large, want: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postBody(testLimits.wrap(tt.class, http.HandlerFunc(readAllHandler)), tt.body, tt.chunked)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRequestLimits_RejectsBeforeHandler(t *testing.T) {
+	reached := false
+	h := testLimits.wrap(plugin.RouteDefault, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := postBody(h, strings.Repeat("a", 40), false)
+	if reached {
+		t.Error("handler ran for a body whose Content-Length exceeds the limit")
+	}
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
+}
+
+func TestRequestLimits_HandlerTimeout(t *testing.T) {
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+
+	start := time.Now()
+	w := postBody(testLimits.wrap(plugin.RouteDefault, slow), "", false)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	if w.Body.String() != handlerTimeoutBody {
+		t.Errorf("body = %q, want %q", w.Body.String(), handlerTimeoutBody)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("timed out after %v, want about %v", elapsed, testLimits.HandlerTimeout)
+	}
+}
+
+func TestRequestLimits_StreamNotTimedOut(t *testing.T) {
+	h := testLimits.wrap(plugin.RouteStream, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: 1\n\n")
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush: %v", err)
+		}
+		time.Sleep(2 * testLimits.HandlerTimeout)
+		_, _ = io.WriteString(w, "data: 2\n\n")
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/insight/query/stream", http.NoBody))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), "data: 2") {
+		t.Errorf("status = %d, body = %q; want the full stream", w.Code, w.Body.String())
+	}
+}
+
+func TestServer_RequestLimits(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	plugins := &mockPluginSource{
+		plugins: []plugin.Plugin{},
+		routes: map[string][]plugin.Route{
+			"recon": {
+				{Method: "POST", Path: "/scan", Handler: readAllHandler},
+				{Method: "POST", Path: "/devices/import", Handler: readAllHandler, Class: plugin.RouteUpload},
+			},
+		},
+	}
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, testLimits)
+
+	post := func(path, body string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	body := strings.Repeat("a", 40)
+	if code := post("/api/v1/recon/scan", body); code != http.StatusRequestEntityTooLarge {
+		t.Errorf("POST /scan status = %d, want 413", code)
+	}
+	if code := post("/api/v1/recon/devices/import", body); code != http.StatusOK {
+		t.Errorf("POST /devices/import status = %d, want 200", code)
+	}
+}
+
+func TestRequestLimits_UntimedClassesOutlastWriteTimeout(t *testing.T) {
+	for _, class := range []plugin.RouteClass{plugin.RouteStream, plugin.RouteLongRunning} {
+		srv := httptest.NewUnstartedServer(testLimits.wrap(class, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			time.Sleep(3 * testLimits.HandlerTimeout)
+			_, _ = io.WriteString(w, "done")
+		})))
+		srv.Config.WriteTimeout = testLimits.HandlerTimeout
+		srv.Start()
+
+		resp, err := http.Get(srv.URL)
+		if err != nil {
+			t.Errorf("class %d: GET: %v", class, err)
+			srv.Close()
+			continue
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		srv.Close()
+		if err != nil || string(body) != "done" {
+			t.Errorf("class %d: body = %q, err = %v; want the full response", class, body, err)
+		}
+	}
+}
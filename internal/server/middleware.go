@@ -8,13 +8,11 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/version"
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 )
 
 // Prometheus HTTP metrics.
@@ -154,81 +152,6 @@ func RecoveryMiddleware(logger *zap.Logger) Middleware {
 	}
 }
 
-// RateLimitMiddleware enforces per-IP rate limiting.
-// Requests to paths in skipPaths are not rate limited.
-func RateLimitMiddleware(rps float64, burst int, skipPaths []string) Middleware {
-	rl := &ipRateLimiter{
-		rateVal: rate.Limit(rps),
-		burst:   burst,
-	}
-	skip := make(map[string]bool, len(skipPaths))
-	for _, p := range skipPaths {
-		skip[p] = true
-	}
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if skip[r.URL.Path] {
-				next.ServeHTTP(w, r)
-				return
-			}
-
-			ip := clientIP(r)
-			if !rl.allow(ip) {
-				RateLimited(w, "rate limit exceeded", r.URL.Path)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
-// ipRateLimiter tracks per-IP token-bucket rate limiters.
-type ipRateLimiter struct {
-	mu       sync.Mutex
-	limiters map[string]*rateLimitEntry
-	rateVal  rate.Limit
-	burst    int
-}
-
-type rateLimitEntry struct {
-	limiter  *rate.Limiter
-	lastSeen time.Time
-}
-
-func (l *ipRateLimiter) allow(ip string) bool {
-	l.mu.Lock()
-	defer l.mu.Unlock()
-
-	if l.limiters == nil {
-		l.limiters = make(map[string]*rateLimitEntry)
-	}
-
-	e, ok := l.limiters[ip]
-	if !ok {
-		if len(l.limiters) >= 10000 {
-			l.cleanup()
-		}
-		e = &rateLimitEntry{limiter: rate.NewLimiter(l.rateVal, l.burst)}
-		l.limiters[ip] = e
-	}
-	e.lastSeen = time.Now()
-
-	return e.limiter.Allow()
-}
-
-// cleanup removes entries not seen in the last 10 minutes.
-// Must be called with l.mu held.
-func (l *ipRateLimiter) cleanup() {
-	cutoff := time.Now().Add(-10 * time.Minute)
-	for ip, e := range l.limiters {
-		if e.lastSeen.Before(cutoff) {
-			delete(l.limiters, ip)
-		}
-	}
-}
-
 // clientIP extracts the client IP from the request.
 func clientIP(r *http.Request) string {
 	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
@@ -265,6 +188,12 @@ func (w *statusWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers can flush through the logging middleware.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // generateID creates a random 32-character hex string for request IDs.
 func generateID() string {
 	b := make([]byte, 16)
@@ -154,7 +154,7 @@ func TestRateLimitMiddleware_AllowsTraffic(t *testing.T) {
 	})
 
 	// High rate to ensure requests pass.
-	handler := RateLimitMiddleware(1000, 1000, nil)(inner)
+	handler := RateLimitMiddleware(RateLimitConfig{Anonymous: RateLimit{RPS: 1000, Burst: 1000}}, nil)(inner)
 
 	req := httptest.NewRequest("GET", "/test", http.NoBody)
 	req.RemoteAddr = "192.168.1.1:12345"
@@ -172,7 +172,7 @@ func TestRateLimitMiddleware_BlocksExcessTraffic(t *testing.T) {
 	})
 
 	// 1 request per second, burst of 1. Second request should be blocked.
-	handler := RateLimitMiddleware(1, 1, nil)(inner)
+	handler := RateLimitMiddleware(RateLimitConfig{Anonymous: RateLimit{RPS: 1, Burst: 1}}, nil)(inner)
 
 	req := httptest.NewRequest("GET", "/test", http.NoBody)
 	req.RemoteAddr = "10.0.0.1:9999"
@@ -198,7 +198,7 @@ func TestRateLimitMiddleware_SkipsPaths(t *testing.T) {
 	})
 
 	// Very low rate, but /healthz should be skipped.
-	handler := RateLimitMiddleware(0.001, 1, []string{"/healthz"})(inner)
+	handler := RateLimitMiddleware(RateLimitConfig{Anonymous: RateLimit{RPS: 0.001, Burst: 1}}, []string{"/healthz"})(inner)
 
 	req := httptest.NewRequest("GET", "/healthz", http.NoBody)
 	req.RemoteAddr = "10.0.0.2:9999"
@@ -14,6 +14,7 @@ const (
 	ProblemTypeForbidden    = "https://subnetree.com/problems/forbidden"
 	ProblemTypeRateLimited  = "https://subnetree.com/problems/rate-limited"
 	ProblemTypeConflict     = "https://subnetree.com/problems/conflict"
+	ProblemTypeTooLarge     = "https://subnetree.com/problems/payload-too-large"
 )
 
 // Problem represents an RFC 7807 Problem Details response.
@@ -65,6 +66,39 @@ func InternalError(w http.ResponseWriter, detail, instance string) {
 	})
 }
 
+// Forbidden writes a 403 problem response.
+func Forbidden(w http.ResponseWriter, detail, instance string) {
+	WriteProblem(w, Problem{
+		Type:     ProblemTypeForbidden,
+		Title:    "Forbidden",
+		Status:   http.StatusForbidden,
+		Detail:   detail,
+		Instance: instance,
+	})
+}
+
+// Conflict writes a 409 problem response.
+func Conflict(w http.ResponseWriter, detail, instance string) {
+	WriteProblem(w, Problem{
+		Type:     ProblemTypeConflict,
+		Title:    "Conflict",
+		Status:   http.StatusConflict,
+		Detail:   detail,
+		Instance: instance,
+	})
+}
+
+// PayloadTooLarge writes a 413 problem response.
+func PayloadTooLarge(w http.ResponseWriter, detail, instance string) {
+	WriteProblem(w, Problem{
+		Type:     ProblemTypeTooLarge,
+		Title:    "Payload Too Large",
+		Status:   http.StatusRequestEntityTooLarge,
+		Detail:   detail,
+		Instance: instance,
+	})
+}
+
 // RateLimited writes a 429 problem response.
 func RateLimited(w http.ResponseWriter, detail, instance string) {
 	WriteProblem(w, Problem{
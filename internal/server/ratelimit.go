@@ -0,0 +1,192 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimit is a token-bucket limit: RPS requests per second on average,
+// with bursts of up to Burst requests.
+type RateLimit struct {
+	RPS   float64 `mapstructure:"rps"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// IdentityFunc names the authenticated caller of a request, such as
+// "user:<id>" or "key:<id>", or returns "" for unauthenticated requests.
+type IdentityFunc func(r *http.Request) string
+
+// RateLimitConfig configures per-caller rate limiting. Each authenticated
+// identity gets its own bucket, so one busy client cannot exhaust another's
+// allowance; unauthenticated requests are bucketed by client IP.
+type RateLimitConfig struct {
+	// Anonymous applies per client IP. A zero RPS disables rate limiting.
+	Anonymous RateLimit `mapstructure:"anonymous"`
+	// Authenticated applies per identity. A zero RPS uses Anonymous.
+	Authenticated RateLimit `mapstructure:"authenticated"`
+	// Overrides gives specific identities their own limit, keyed by the
+	// name Identity returns.
+	Overrides map[string]RateLimit `mapstructure:"overrides"`
+	// Identity resolves the caller; nil limits every request by client IP.
+	Identity IdentityFunc `mapstructure:"-"`
+}
+
+// limitFor returns the limit for an identity ("" when unauthenticated).
+func (c *RateLimitConfig) limitFor(identity string) RateLimit {
+	if identity == "" {
+		return c.Anonymous
+	}
+	// Viper lowercases map keys, so overrides are matched case-insensitively.
+	for k, l := range c.Overrides {
+		if strings.EqualFold(k, identity) {
+			return l
+		}
+	}
+	if c.Authenticated.RPS > 0 {
+		return c.Authenticated
+	}
+	return c.Anonymous
+}
+
+// RateLimitMiddleware enforces per-identity rate limiting, falling back to
+// per-IP limiting for unauthenticated requests. It must run after the auth
+// middleware for cfg.Identity to see the caller.
+// Requests to paths in skipPaths are not rate limited.
+func RateLimitMiddleware(cfg RateLimitConfig, skipPaths []string) Middleware {
+	rl := &rateLimiter{}
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip[r.URL.Path] {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var identity string
+			if cfg.Identity != nil {
+				identity = cfg.Identity(r)
+			}
+			limit := cfg.limitFor(identity)
+			if limit.RPS <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := identity
+			if key == "" {
+				key = "ip:" + clientIP(r)
+			}
+			if !rl.allow(key, limit) {
+				RateLimited(w, "rate limit exceeded", r.URL.Path)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// AuthFailureLimitMiddleware throttles clients that keep failing
+// authentication. It runs ahead of the auth middleware, which rejects bad
+// credentials itself and so never lets them reach RateLimitMiddleware. Each
+// 401 response costs the client IP a token from a cfg.Anonymous bucket;
+// once the bucket is empty every request from that IP gets 429 without
+// reaching auth, so guessed tokens and API keys cannot force unbounded
+// lookups. Successful requests cost nothing here, leaving authenticated
+// callers behind a shared IP to their own per-identity limits.
+func AuthFailureLimitMiddleware(cfg RateLimitConfig, skipPaths []string) Middleware {
+	rl := &rateLimiter{}
+	skip := make(map[string]bool, len(skipPaths))
+	for _, p := range skipPaths {
+		skip[p] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := cfg.Anonymous
+			if skip[r.URL.Path] || limit.RPS <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key := "ip:" + clientIP(r)
+			if rl.exhausted(key, limit) {
+				RateLimited(w, "too many failed authentication attempts", r.URL.Path)
+				return
+			}
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+			if sw.status == http.StatusUnauthorized {
+				rl.allow(key, limit)
+			}
+		})
+	}
+}
+
+// rateLimiter tracks a token-bucket limiter per caller.
+type rateLimiter struct {
+	mu       sync.Mutex
+	limiters map[string]*rateLimitEntry
+}
+
+type rateLimitEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// allow reports whether key may make a request. A new key's bucket is
+// created with limit; existing buckets keep the limit they started with.
+func (l *rateLimiter) allow(key string, limit RateLimit) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limiters == nil {
+		l.limiters = make(map[string]*rateLimitEntry)
+	}
+
+	e, ok := l.limiters[key]
+	if !ok {
+		if len(l.limiters) >= 10000 {
+			l.cleanup()
+		}
+		e = &rateLimitEntry{limiter: rate.NewLimiter(rate.Limit(limit.RPS), limit.Burst)}
+		l.limiters[key] = e
+	}
+	e.lastSeen = time.Now()
+
+	return e.limiter.Allow()
+}
+
+// exhausted reports whether key's bucket has no token left, without
+// spending one. Keys with no bucket yet are not exhausted.
+func (l *rateLimiter) exhausted(key string, limit RateLimit) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.limiters[key]
+	if !ok {
+		return false
+	}
+	e.lastSeen = time.Now()
+	return e.limiter.Tokens() < 1
+}
+
+// cleanup removes entries not seen in the last 10 minutes.
+// Must be called with l.mu held.
+func (l *rateLimiter) cleanup() {
+	cutoff := time.Now().Add(-10 * time.Minute)
+	for key, e := range l.limiters {
+		if e.lastSeen.Before(cutoff) {
+			delete(l.limiters, key)
+		}
+	}
+}
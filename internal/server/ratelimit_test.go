@@ -0,0 +1,163 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// identityHeader stands in for the auth middleware in these tests.
+func identityHeader(r *http.Request) string {
+	return r.Header.Get("X-Test-Identity")
+}
+
+func newRateLimitedHandler(cfg RateLimitConfig) http.Handler {
+	cfg.Identity = identityHeader
+	return RateLimitMiddleware(cfg, nil)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+// hit sends one request as identity (or anonymously when empty) from ip.
+func hit(h http.Handler, identity, ip string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/recon/devices", http.NoBody)
+	req.RemoteAddr = ip + ":40000"
+	if identity != "" {
+		req.Header.Set("X-Test-Identity", identity)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRateLimitMiddleware_IndependentIdentities(t *testing.T) {
+	h := newRateLimitedHandler(RateLimitConfig{
+		Anonymous:     RateLimit{RPS: 0.001, Burst: 1},
+		Authenticated: RateLimit{RPS: 0.001, Burst: 2},
+	})
+
+	// Both users share one IP, as behind a NAT or reverse proxy.
+	const ip = "10.0.0.5"
+	for i := range 2 {
+		if code := hit(h, "user:alice", ip); code != http.StatusOK {
+			t.Fatalf("alice request %d: status = %d, want 200", i+1, code)
+		}
+	}
+	if code := hit(h, "user:alice", ip); code != http.StatusTooManyRequests {
+		t.Fatalf("alice over limit: status = %d, want 429", code)
+	}
+
+	// Alice exhausting her bucket does not throttle Bob or an API key.
+	for _, identity := range []string{"user:bob", "key:k1"} {
+		if code := hit(h, identity, ip); code != http.StatusOK {
+			t.Errorf("%s: status = %d, want 200", identity, code)
+		}
+	}
+	// Nor anonymous callers from the same IP.
+	if code := hit(h, "", ip); code != http.StatusOK {
+		t.Errorf("anonymous: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitMiddleware_AnonymousByIP(t *testing.T) {
+	h := newRateLimitedHandler(RateLimitConfig{Anonymous: RateLimit{RPS: 0.001, Burst: 1}})
+
+	if code := hit(h, "", "10.0.0.1"); code != http.StatusOK {
+		t.Fatalf("first: status = %d, want 200", code)
+	}
+	if code := hit(h, "", "10.0.0.1"); code != http.StatusTooManyRequests {
+		t.Errorf("second from same IP: status = %d, want 429", code)
+	}
+	if code := hit(h, "", "10.0.0.2"); code != http.StatusOK {
+		t.Errorf("other IP: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitMiddleware_Overrides(t *testing.T) {
+	h := newRateLimitedHandler(RateLimitConfig{
+		Anonymous: RateLimit{RPS: 0.001, Burst: 1},
+		// Viper lowercases map keys; identities must still match.
+		Overrides: map[string]RateLimit{"key:trusted-abc": {RPS: 0.001, Burst: 5}},
+	})
+
+	for i := range 5 {
+		if code := hit(h, "key:Trusted-ABC", "10.0.0.9"); code != http.StatusOK {
+			t.Fatalf("trusted request %d: status = %d, want 200", i+1, code)
+		}
+	}
+	if code := hit(h, "key:Trusted-ABC", "10.0.0.9"); code != http.StatusTooManyRequests {
+		t.Errorf("trusted over limit: status = %d, want 429", code)
+	}
+
+	// Identities without an override fall back to the anonymous limit when
+	// no authenticated limit is set.
+	if code := hit(h, "key:other", "10.0.0.9"); code != http.StatusOK {
+		t.Fatalf("other first: status = %d, want 200", code)
+	}
+	if code := hit(h, "key:other", "10.0.0.9"); code != http.StatusTooManyRequests {
+		t.Errorf("other second: status = %d, want 429", code)
+	}
+}
+
+func TestRateLimitMiddleware_ZeroDisables(t *testing.T) {
+	h := newRateLimitedHandler(RateLimitConfig{})
+	for i := range 20 {
+		if code := hit(h, "", "10.0.0.1"); code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i+1, code)
+		}
+	}
+}
+
+func TestAuthFailureLimitMiddleware_InvalidKeysThrottled(t *testing.T) {
+	// Stands in for the auth middleware: rejects any key but "good".
+	var authCalls int
+	auth := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authCalls++
+			if r.Header.Get("X-API-Key") != "good" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	cfg := RateLimitConfig{Anonymous: RateLimit{RPS: 0.001, Burst: 3}}
+	h := Chain(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), AuthFailureLimitMiddleware(cfg, nil), auth)
+
+	send := func(key, ip string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/recon/devices", http.NoBody)
+		req.RemoteAddr = ip + ":40000"
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Valid requests cost nothing.
+	for i := range 5 {
+		if code := send("good", "10.0.0.7"); code != http.StatusOK {
+			t.Fatalf("valid request %d: status = %d, want 200", i+1, code)
+		}
+	}
+	for i := range 3 {
+		if code := send("guess", "10.0.0.7"); code != http.StatusUnauthorized {
+			t.Fatalf("invalid request %d: status = %d, want 401", i+1, code)
+		}
+	}
+	calls := authCalls
+	for i := range 5 {
+		if code := send("guess", "10.0.0.7"); code != http.StatusTooManyRequests {
+			t.Fatalf("invalid request %d over limit: status = %d, want 429", i+4, code)
+		}
+	}
+	if authCalls != calls {
+		t.Errorf("auth ran %d more times after the limit, want 0", authCalls-calls)
+	}
+
+	// Other clients are unaffected.
+	if code := send("guess", "10.0.0.8"); code != http.StatusUnauthorized {
+		t.Errorf("other IP: status = %d, want 401", code)
+	}
+}
@@ -20,6 +20,9 @@ import (
 type PluginSource interface {
 	AllRoutes() map[string][]plugin.Route
 	All() []plugin.Plugin
+	Get(name string) (plugin.Plugin, bool)
+	Status(name string) (plugin.PluginStatus, bool)
+	Statuses() []plugin.PluginStatus
 }
 
 // ReadinessChecker verifies that the server is ready to serve traffic.
@@ -33,6 +36,25 @@ type RouteRegistrar interface {
 	Middleware() func(http.Handler) http.Handler
 }
 
+// AdminGuard is implemented by RouteRegistrars that can restrict a handler
+// to administrators. Admin-only core routes are mounted only when the auth
+// registrar provides one.
+type AdminGuard interface {
+	RequireAdmin(next http.Handler) http.Handler
+}
+
+// PluginRestarter is implemented by PluginSources that can restart a single
+// plugin at runtime.
+type PluginRestarter interface {
+	Restart(ctx context.Context, name string) error
+}
+
+// PluginReadiness is implemented by PluginSources that can report whether
+// their plugins are ready; /readyz fails while any plugin is not.
+type PluginReadiness interface {
+	Ready() error
+}
+
 // Server is the main SubNetree HTTP server.
 type Server struct {
 	httpServer *http.Server
@@ -40,6 +62,7 @@ type Server struct {
 	logger     *zap.Logger
 	mux        *http.ServeMux
 	ready      ReadinessChecker
+	limits     RequestLimits
 }
 
 // SimpleRouteRegistrar can register routes without middleware.
@@ -52,8 +75,12 @@ type SimpleRouteRegistrar interface {
 // The dashboard parameter is optional; pass nil to disable dashboard serving.
 // When devMode is true, Swagger UI is served at /swagger/.
 // When demoMode is true, all write operations (POST/PUT/DELETE/PATCH) are blocked.
+// cors lists the cross-origin clients allowed to call the API; the zero value
+// is same-origin only. rateLimit sets per-caller request limits; the zero
+// value disables rate limiting. limits bounds plugin route request bodies
+// and handler run time by route class; the zero value sets no limits.
 // Additional route registrars can be passed to register extra API routes.
-func New(addr string, plugins PluginSource, logger *zap.Logger, ready ReadinessChecker, auth RouteRegistrar, dashboard http.Handler, devMode, demoMode bool, extraRoutes ...SimpleRouteRegistrar) *Server {
+func New(addr string, plugins PluginSource, logger *zap.Logger, ready ReadinessChecker, auth RouteRegistrar, dashboard http.Handler, devMode, demoMode bool, cors CORSConfig, rateLimit RateLimitConfig, limits RequestLimits, extraRoutes ...SimpleRouteRegistrar) *Server {
 	mux := http.NewServeMux()
 
 	s := &Server{
@@ -61,11 +88,15 @@ func New(addr string, plugins PluginSource, logger *zap.Logger, ready ReadinessC
 		logger:  logger,
 		mux:     mux,
 		ready:   ready,
+		limits:  limits,
 	}
 
 	s.registerRoutes()
 	if auth != nil {
 		auth.RegisterRoutes(mux)
+		if guard, ok := auth.(AdminGuard); ok {
+			s.registerAdminRoutes(guard)
+		}
 	}
 	for _, r := range extraRoutes {
 		r.RegisterRoutes(mux)
@@ -88,20 +119,31 @@ func New(addr string, plugins PluginSource, logger *zap.Logger, ready ReadinessC
 	middlewares := []Middleware{
 		RecoveryMiddleware(logger),
 		RequestIDMiddleware,
+		TracingMiddleware,
 		LoggingMiddleware(logger, []string{"/healthz", "/readyz", "/metrics"}),
+		// Outside auth and rate limiting so preflights are answered directly
+		// and error responses stay readable by allowed origins.
+		CORSMiddleware(cors),
+		// Inside logging so logged status reflects the handler, outside
+		// everything that writes a response body.
+		CompressionMiddleware(compressMinSize),
 		SecurityHeadersMiddleware,
 		VersionHeaderMiddleware,
-		RateLimitMiddleware(100, 200, []string{"/healthz", "/readyz", "/metrics"}),
 	}
 	if auth != nil {
-		middlewares = append(middlewares, auth.Middleware())
+		middlewares = append(middlewares,
+			AuthFailureLimitMiddleware(rateLimit, []string{"/healthz", "/readyz", "/metrics"}),
+			auth.Middleware())
 	}
+	// After auth so buckets can be keyed by the authenticated caller.
+	middlewares = append(middlewares,
+		RateLimitMiddleware(rateLimit, []string{"/healthz", "/readyz", "/metrics"}))
 	if demoMode {
 		middlewares = append(middlewares, DemoMiddleware)
 		logger.Warn("DEMO MODE ACTIVE: all write operations are blocked")
 	}
 
-	handler := Chain(mux, middlewares...)
+	handler := Chain(routeSpanHandler(mux), middlewares...)
 
 	s.httpServer = &http.Server{
 		Addr:         addr,
@@ -124,6 +166,14 @@ func (s *Server) registerRoutes() {
 	// Versioned API endpoints.
 	s.mux.HandleFunc("GET /api/v1/health", s.handleHealth)
 	s.mux.HandleFunc("GET /api/v1/plugins", s.handlePlugins)
+	s.mux.HandleFunc("GET /api/v1/plugins/{name}/health", s.handlePluginHealth)
+}
+
+// registerAdminRoutes sets up core routes restricted to administrators.
+func (s *Server) registerAdminRoutes(guard AdminGuard) {
+	if _, ok := s.plugins.(PluginRestarter); ok {
+		s.mux.Handle("POST /api/v1/plugins/{name}/restart", guard.RequireAdmin(http.HandlerFunc(s.handlePluginRestart)))
+	}
 }
 
 // mountPluginRoutes registers all plugin routes under /api/v1/{plugin}/.
@@ -132,7 +182,7 @@ func (s *Server) mountPluginRoutes() {
 	for pluginName, routes := range allRoutes {
 		for _, route := range routes {
 			pattern := fmt.Sprintf("%s /api/v1/%s%s", route.Method, pluginName, route.Path)
-			s.mux.HandleFunc(pattern, route.Handler)
+			s.mux.Handle(pattern, s.limits.wrap(route.Class, route.Handler))
 			s.logger.Debug("mounted route",
 				zap.String("plugin", pluginName),
 				zap.String("pattern", pattern),
@@ -162,22 +212,39 @@ func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]string{"status": "alive"})
 }
 
+// ReadyzResponse is the response for GET /readyz.
+type ReadyzResponse struct {
+	Status string `json:"status" example:"not ready"`
+	Error  string `json:"error,omitempty" example:"plugin \"pulse\" not ready: check scheduler is not running"`
+}
+
 // handleReadyz checks readiness -- returns 200 if the server can serve traffic.
+// The server is ready when its readiness checker passes and, if the plugin
+// source supports it, every plugin reports ready.
 func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	err := s.checkReady(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(ReadyzResponse{Status: "not ready", Error: err.Error()})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(ReadyzResponse{Status: "ready"})
+}
+
+// checkReady runs the server's readiness checker, then plugin readiness.
+func (s *Server) checkReady(ctx context.Context) error {
 	if s.ready != nil {
-		if err := s.ready(r.Context()); err != nil {
-			w.WriteHeader(http.StatusServiceUnavailable)
-			_ = json.NewEncoder(w).Encode(map[string]string{
-				"status": "not ready",
-				"error":  err.Error(),
-			})
-			return
+		if err := s.ready(ctx); err != nil {
+			return err
 		}
 	}
-
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ready"})
+	if pr, ok := s.plugins.(PluginReadiness); ok {
+		return pr.Ready()
+	}
+	return nil
 }
 
 // HealthResponse is the response for GET /health.
@@ -189,11 +256,21 @@ type HealthResponse struct {
 
 // PluginResponse describes a registered plugin.
 type PluginResponse struct {
-	Name        string `json:"name" example:"recon"`
-	Version     string `json:"version" example:"0.1.0"`
-	Description string `json:"description" example:"Network discovery and scanning"`
+	Name        string               `json:"name" example:"recon"`
+	Version     string               `json:"version" example:"0.1.0"`
+	Description string               `json:"description" example:"Network discovery and scanning"`
+	Status      plugin.PluginState   `json:"status" example:"enabled"`
+	Error       string               `json:"error,omitempty" example:"dependency \"vault\" is disabled"`
+	Health      *plugin.HealthStatus `json:"health,omitempty"`
 }
 
+// pluginHealthTimeout bounds each plugin's live health check.
+const pluginHealthTimeout = 2 * time.Second
+
+// pluginRestartDrainTimeout bounds how long a restart waits for in-flight
+// requests to the plugin; it stays under the server's WriteTimeout.
+const pluginRestartDrainTimeout = 10 * time.Second
+
 // handleHealth returns detailed health information (versioned API endpoint).
 //
 //	@Summary		Health check
@@ -214,22 +291,118 @@ func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
 // handlePlugins returns the list of registered plugins.
 //
 //	@Summary		List plugins
-//	@Description	Returns all registered plugins with their metadata.
+//	@Description	Returns all registered plugins with their metadata and status
+//	@Description	(enabled, disabled, or failed). Enabled plugins that report
+//	@Description	health include a live health result.
 //	@Tags			system
 //	@Produce		json
 //	@Success		200	{array}	PluginResponse
 //	@Router			/plugins [get]
-func (s *Server) handlePlugins(w http.ResponseWriter, _ *http.Request) {
-	plugins := s.plugins.All()
-	info := make([]PluginResponse, 0, len(plugins))
-	for _, p := range plugins {
-		pi := p.Info()
-		info = append(info, PluginResponse{
-			Name:        pi.Name,
-			Version:     pi.Version,
-			Description: pi.Description,
-		})
+func (s *Server) handlePlugins(w http.ResponseWriter, r *http.Request) {
+	statuses := s.plugins.Statuses()
+	info := make([]PluginResponse, 0, len(statuses))
+	for _, st := range statuses {
+		info = append(info, s.pluginResponse(r.Context(), st))
 	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(info)
 }
+
+// handlePluginHealth returns one plugin's status and live health.
+//
+//	@Summary		Plugin health
+//	@Description	Returns a plugin's status and, if it reports health, a live
+//	@Description	health result. Responds 503 when the plugin is disabled, failed,
+//	@Description	or unhealthy.
+//	@Tags			system
+//	@Produce		json
+//	@Param			name	path		string	true	"Plugin name"
+//	@Success		200		{object}	PluginResponse
+//	@Failure		404		{object}	Problem
+//	@Failure		503		{object}	PluginResponse
+//	@Router			/plugins/{name}/health [get]
+func (s *Server) handlePluginHealth(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	st, ok := s.plugins.Status(name)
+	if !ok {
+		NotFound(w, fmt.Sprintf("plugin %q is not registered", name), r.URL.Path)
+		return
+	}
+
+	resp := s.pluginResponse(r.Context(), st)
+	w.Header().Set("Content-Type", "application/json")
+	if resp.Status != plugin.StateEnabled || (resp.Health != nil && resp.Health.Status == "unhealthy") {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// handlePluginRestart stops and restarts one plugin, and the plugins that
+// depend on it, without restarting the server.
+//
+//	@Summary		Restart plugin
+//	@Description	Stops a plugin and its dependents, waits for in-flight requests
+//	@Description	to them, then initializes and starts them again. A plugin that
+//	@Description	fails to restart is left failed. Requires the admin role.
+//	@Tags			system
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			name	path		string	true	"Plugin name"
+//	@Success		200		{object}	PluginResponse
+//	@Failure		403		{object}	Problem
+//	@Failure		404		{object}	Problem
+//	@Failure		409		{object}	Problem
+//	@Failure		500		{object}	Problem
+//	@Router			/plugins/{name}/restart [post]
+func (s *Server) handlePluginRestart(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	st, ok := s.plugins.Status(name)
+	if !ok {
+		NotFound(w, fmt.Sprintf("plugin %q is not registered", name), r.URL.Path)
+		return
+	}
+	if st.State == plugin.StateDisabled {
+		Conflict(w, fmt.Sprintf("plugin %q is disabled and cannot be restarted; restart the server to enable it", name), r.URL.Path)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), pluginRestartDrainTimeout)
+	defer cancel()
+	if err := s.plugins.(PluginRestarter).Restart(ctx, name); err != nil {
+		s.logger.Error("plugin restart failed", zap.String("plugin", name), zap.Error(err))
+		InternalError(w, err.Error(), r.URL.Path)
+		return
+	}
+
+	st, _ = s.plugins.Status(name)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(s.pluginResponse(r.Context(), st))
+}
+
+// pluginResponse describes a plugin, running its health check if it is
+// enabled and implements plugin.HealthChecker.
+func (s *Server) pluginResponse(ctx context.Context, st plugin.PluginStatus) PluginResponse {
+	resp := PluginResponse{
+		Name:        st.Info.Name,
+		Version:     st.Info.Version,
+		Description: st.Info.Description,
+		Status:      st.State,
+	}
+	if st.Err != nil {
+		resp.Error = st.Err.Error()
+	}
+	if st.State != plugin.StateEnabled {
+		return resp
+	}
+	p, ok := s.plugins.Get(st.Info.Name)
+	if !ok {
+		return resp
+	}
+	if hc, ok := p.(plugin.HealthChecker); ok {
+		hctx, cancel := context.WithTimeout(ctx, pluginHealthTimeout)
+		health := hc.Health(hctx)
+		cancel()
+		resp.Health = &health
+	}
+	return resp
+}
@@ -21,8 +21,9 @@ import (
 
 // mockPluginSource satisfies the PluginSource interface for testing.
 type mockPluginSource struct {
-	plugins []plugin.Plugin
-	routes  map[string][]plugin.Route
+	plugins  []plugin.Plugin
+	routes   map[string][]plugin.Route
+	inactive []plugin.PluginStatus // disabled or failed plugins
 }
 
 func (m *mockPluginSource) AllRoutes() map[string][]plugin.Route {
@@ -36,6 +37,32 @@ func (m *mockPluginSource) All() []plugin.Plugin {
 	return m.plugins
 }
 
+func (m *mockPluginSource) Get(name string) (plugin.Plugin, bool) {
+	for _, p := range m.plugins {
+		if p.Info().Name == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func (m *mockPluginSource) Status(name string) (plugin.PluginStatus, bool) {
+	for _, st := range m.Statuses() {
+		if st.Info.Name == name {
+			return st, true
+		}
+	}
+	return plugin.PluginStatus{}, false
+}
+
+func (m *mockPluginSource) Statuses() []plugin.PluginStatus {
+	result := make([]plugin.PluginStatus, 0, len(m.plugins)+len(m.inactive))
+	for _, p := range m.plugins {
+		result = append(result, plugin.PluginStatus{Info: p.Info(), State: plugin.StateEnabled})
+	}
+	return append(result, m.inactive...)
+}
+
 // stubPlugin satisfies plugin.Plugin for testing.
 type stubPlugin struct {
 	info plugin.PluginInfo
@@ -57,7 +84,7 @@ func newTestServer(ready ReadinessChecker) *Server {
 			}},
 		},
 	}
-	return New("127.0.0.1:0", plugins, logger, ready, nil, nil, false, false)
+	return New("127.0.0.1:0", plugins, logger, ready, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
 }
 
 func TestHandleHealthz(t *testing.T) {
@@ -123,6 +150,37 @@ func TestHandleReadyz_Unhealthy(t *testing.T) {
 	}
 }
 
+func TestHandleReadyz_PluginNotReady(t *testing.T) {
+	plugins := &readyPluginSource{err: errors.New(`plugin "pulse" not ready: check scheduler is not running`)}
+	ready := ReadinessChecker(func(_ context.Context) error { return nil })
+	srv := New("127.0.0.1:0", plugins, zap.NewNop(), ready, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	req := httptest.NewRequest("GET", "/readyz", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+	var body ReadyzResponse
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Status != "not ready" {
+		t.Errorf("status = %q, want %q", body.Status, "not ready")
+	}
+	if !strings.Contains(body.Error, `"pulse"`) {
+		t.Errorf("error = %q, want it to name pulse", body.Error)
+	}
+
+	plugins.err = nil
+	w = httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Errorf("status with plugins ready = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
 func TestHandleReadyz_NilChecker(t *testing.T) {
 	srv := newTestServer(nil)
 
@@ -181,6 +239,253 @@ func TestHandlePlugins(t *testing.T) {
 	if plugins[0]["version"] != "1.0.0" {
 		t.Errorf("version = %q, want %q", plugins[0]["version"], "1.0.0")
 	}
+	if plugins[0]["status"] != "enabled" {
+		t.Errorf("status = %q, want %q", plugins[0]["status"], "enabled")
+	}
+}
+
+// healthPlugin is a stubPlugin that implements plugin.HealthChecker.
+type healthPlugin struct {
+	stubPlugin
+	health plugin.HealthStatus
+}
+
+func (h *healthPlugin) Health(_ context.Context) plugin.HealthStatus { return h.health }
+
+func newPluginHealthServer() *Server {
+	logger, _ := zap.NewDevelopment()
+	plugins := &mockPluginSource{
+		plugins: []plugin.Plugin{
+			&stubPlugin{info: plugin.PluginInfo{Name: "plain", Version: "1.0.0"}},
+			&healthPlugin{
+				stubPlugin: stubPlugin{info: plugin.PluginInfo{Name: "good", Version: "1.0.0"}},
+				health:     plugin.HealthStatus{Status: "healthy", Message: "all good"},
+			},
+			&healthPlugin{
+				stubPlugin: stubPlugin{info: plugin.PluginInfo{Name: "sick", Version: "1.0.0"}},
+				health:     plugin.HealthStatus{Status: "unhealthy", Message: "broker unreachable"},
+			},
+		},
+		inactive: []plugin.PluginStatus{
+			{
+				Info:  plugin.PluginInfo{Name: "broken", Version: "1.0.0"},
+				State: plugin.StateFailed,
+				Err:   errors.New("init failed"),
+			},
+			{
+				Info:  plugin.PluginInfo{Name: "off", Version: "1.0.0"},
+				State: plugin.StateDisabled,
+				Err:   errors.New(`dependency "broken" is disabled`),
+			},
+		},
+	}
+	return New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+}
+
+func TestHandlePlugins_Status(t *testing.T) {
+	srv := newPluginHealthServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/plugins", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var plugins []PluginResponse
+	if err := json.NewDecoder(w.Body).Decode(&plugins); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	byName := make(map[string]PluginResponse, len(plugins))
+	for _, p := range plugins {
+		byName[p.Name] = p
+	}
+	if len(byName) != 5 {
+		t.Fatalf("len(plugins) = %d, want 5", len(byName))
+	}
+
+	if p := byName["plain"]; p.Status != plugin.StateEnabled || p.Health != nil {
+		t.Errorf("plain = %+v, want enabled without health", p)
+	}
+	if p := byName["good"]; p.Health == nil || p.Health.Status != "healthy" {
+		t.Errorf("good health = %+v, want healthy", p.Health)
+	}
+	if p := byName["broken"]; p.Status != plugin.StateFailed || p.Error != "init failed" {
+		t.Errorf("broken = %+v, want failed with init error", p)
+	}
+	if p := byName["off"]; p.Status != plugin.StateDisabled || p.Health != nil {
+		t.Errorf("off = %+v, want disabled without health", p)
+	}
+}
+
+func TestHandlePluginHealth(t *testing.T) {
+	srv := newPluginHealthServer()
+
+	tests := []struct {
+		name       string
+		wantCode   int
+		wantStatus plugin.PluginState
+		wantHealth string
+	}{
+		{name: "plain", wantCode: http.StatusOK, wantStatus: plugin.StateEnabled},
+		{name: "good", wantCode: http.StatusOK, wantStatus: plugin.StateEnabled, wantHealth: "healthy"},
+		{name: "sick", wantCode: http.StatusServiceUnavailable, wantStatus: plugin.StateEnabled, wantHealth: "unhealthy"},
+		{name: "broken", wantCode: http.StatusServiceUnavailable, wantStatus: plugin.StateFailed},
+		{name: "off", wantCode: http.StatusServiceUnavailable, wantStatus: plugin.StateDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/plugins/"+tt.name+"/health", http.NoBody)
+			w := httptest.NewRecorder()
+			srv.mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantCode)
+			}
+			var resp PluginResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if resp.Status != tt.wantStatus {
+				t.Errorf("plugin status = %q, want %q", resp.Status, tt.wantStatus)
+			}
+			gotHealth := ""
+			if resp.Health != nil {
+				gotHealth = resp.Health.Status
+			}
+			if gotHealth != tt.wantHealth {
+				t.Errorf("health = %q, want %q", gotHealth, tt.wantHealth)
+			}
+		})
+	}
+}
+
+// restartablePluginSource is a mockPluginSource that implements
+// PluginRestarter.
+type restartablePluginSource struct {
+	mockPluginSource
+	restarted []string
+	err       error
+}
+
+func (m *restartablePluginSource) Restart(_ context.Context, name string) error {
+	m.restarted = append(m.restarted, name)
+	return m.err
+}
+
+// readyPluginSource is a mockPluginSource that implements PluginReadiness.
+type readyPluginSource struct {
+	mockPluginSource
+	err error
+}
+
+func (m *readyPluginSource) Ready() error { return m.err }
+
+// stubAuth is a RouteRegistrar and AdminGuard that admits requests carrying
+// an "X-Test-Role: admin" header.
+type stubAuth struct{}
+
+func (stubAuth) RegisterRoutes(_ *http.ServeMux) {}
+
+func (stubAuth) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler { return next }
+}
+
+func (stubAuth) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Test-Role") != "admin" {
+			Forbidden(w, "admin role required", r.URL.Path)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func TestHandlePluginRestart(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	plugins := &restartablePluginSource{mockPluginSource: mockPluginSource{
+		plugins: []plugin.Plugin{&stubPlugin{info: plugin.PluginInfo{Name: "recon", Version: "1.0.0"}}},
+		inactive: []plugin.PluginStatus{{
+			Info:  plugin.PluginInfo{Name: "old", Version: "1.0.0"},
+			State: plugin.StateDisabled,
+			Err:   errors.New("incompatible API version"),
+		}},
+	}}
+	srv := New("127.0.0.1:0", plugins, logger, nil, stubAuth{}, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	restart := func(name string, admin bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/v1/plugins/"+name+"/restart", http.NoBody)
+		if admin {
+			req.Header.Set("X-Test-Role", "admin")
+		}
+		w := httptest.NewRecorder()
+		srv.mux.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := restart("recon", false); w.Code != http.StatusForbidden {
+		t.Errorf("non-admin status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if len(plugins.restarted) != 0 {
+		t.Fatalf("restarted = %v, want none for non-admin", plugins.restarted)
+	}
+
+	w := restart("recon", true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("admin status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	var resp PluginResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Name != "recon" || resp.Status != plugin.StateEnabled {
+		t.Errorf("response = %+v, want recon enabled", resp)
+	}
+	if len(plugins.restarted) != 1 || plugins.restarted[0] != "recon" {
+		t.Errorf("restarted = %v, want [recon]", plugins.restarted)
+	}
+
+	if w := restart("missing", true); w.Code != http.StatusNotFound {
+		t.Errorf("unknown plugin status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if w := restart("old", true); w.Code != http.StatusConflict {
+		t.Errorf("disabled plugin status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	plugins.err = errors.New("start failed")
+	if w := restart("recon", true); w.Code != http.StatusInternalServerError {
+		t.Errorf("failed restart status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestHandlePluginRestart_NotMountedWithoutAdminGuard(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	plugins := &restartablePluginSource{mockPluginSource: mockPluginSource{
+		plugins: []plugin.Plugin{&stubPlugin{info: plugin.PluginInfo{Name: "recon"}}},
+	}}
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	req := httptest.NewRequest("POST", "/api/v1/plugins/recon/restart", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+	if w.Code == http.StatusOK || len(plugins.restarted) != 0 {
+		t.Errorf("status = %d, restarted = %v; want the route unmounted without auth", w.Code, plugins.restarted)
+	}
+}
+
+func TestHandlePluginHealth_NotFound(t *testing.T) {
+	srv := newPluginHealthServer()
+
+	req := httptest.NewRequest("GET", "/api/v1/plugins/nope/health", http.NoBody)
+	w := httptest.NewRecorder()
+	srv.mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", ct)
+	}
 }
 
 func TestHandleMetrics(t *testing.T) {
@@ -240,7 +545,7 @@ func TestPluginRoutes_Mounted(t *testing.T) {
 			},
 		},
 	}
-	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false)
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
 
 	req := httptest.NewRequest("POST", "/api/v1/recon/scan", http.NoBody)
 	w := httptest.NewRecorder()
@@ -273,7 +578,7 @@ func newTestServerWithListener(t *testing.T, slowHandler http.HandlerFunc) (*Ser
 	}
 
 	addr := listener.Addr().String()
-	srv := New(addr, plugins, logger, nil, nil, nil, false, false)
+	srv := New(addr, plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
 
 	return srv, listener, addr
 }
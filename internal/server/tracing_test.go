@@ -0,0 +1,107 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/tracing"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// installTestTracer routes spans to an in-memory exporter for the duration
+// of the test.
+func installTestTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exp), 1, "test")
+
+	prevTP, prevProp := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = tp.Shutdown(t.Context())
+		otel.SetTracerProvider(prevTP)
+		otel.SetTextMapPropagator(prevProp)
+	})
+	return exp
+}
+
+func TestServer_TracingSpan(t *testing.T) {
+	exp := installTestTracer(t)
+
+	var handlerSpan trace.SpanContext
+	logger, _ := zap.NewDevelopment()
+	plugins := &mockPluginSource{
+		plugins: []plugin.Plugin{},
+		routes: map[string][]plugin.Route{
+			"recon": {
+				{Method: "GET", Path: "/devices/{id}", Handler: func(w http.ResponseWriter, r *http.Request) {
+					handlerSpan = trace.SpanContextFromContext(r.Context())
+					w.WriteHeader(http.StatusOK)
+				}},
+			},
+		},
+	}
+	srv := New("127.0.0.1:0", plugins, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/recon/devices/abc", http.NoBody)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("span kind = %v, want server", span.SpanKind)
+	}
+	if want := "GET /api/v1/recon/devices/{id}"; span.Name != want {
+		t.Errorf("span name = %q, want %q", span.Name, want)
+	}
+	if got := span.SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s, want %s from traceparent", got, traceID)
+	}
+	if !span.Parent.IsRemote() {
+		t.Error("span parent is not the remote traceparent context")
+	}
+	if handlerSpan.SpanID() != span.SpanContext.SpanID() {
+		t.Error("handler context does not carry the server span")
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range span.Attributes {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if got := attrs[string(semconv.HTTPRouteKey)]; got != "/api/v1/recon/devices/{id}" {
+		t.Errorf("http.route = %q, want /api/v1/recon/devices/{id}", got)
+	}
+	if got := attrs[string(semconv.HTTPResponseStatusCodeKey)]; got != "200" {
+		t.Errorf("http.response.status_code = %q, want 200", got)
+	}
+}
+
+func TestServer_TracingDisabledByDefault(t *testing.T) {
+	logger, _ := zap.NewDevelopment()
+	srv := New("127.0.0.1:0", &mockPluginSource{}, logger, nil, nil, nil, false, false, CORSConfig{}, RateLimitConfig{}, RequestLimits{})
+
+	w := httptest.NewRecorder()
+	srv.httpServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200 with the no-op tracer", w.Code)
+	}
+}
@@ -5,18 +5,25 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
 	"github.com/google/uuid"
 )
 
 // DeviceFilter controls which devices are returned by List.
 type DeviceFilter struct {
-	Status     string // Filter by DeviceStatus value.
-	DeviceType string // Filter by DeviceType value.
-	Search     string // Search hostname, IP addresses, or MAC address.
-	ScanID     string // Filter to devices linked to a specific scan.
+	Status     string   // Filter by DeviceStatus value.
+	DeviceType string   // Filter by DeviceType value.
+	Search     string   // Search hostname, IP addresses, or MAC address, ranked by relevance.
+	ScanID     string   // Filter to devices linked to a specific scan.
+	Tags       []string // Filter to devices that have all of these tags.
+
+	IncludeArchived bool // Include archived (soft-deleted) devices.
 }
 
 // DeviceRepository provides CRUD access to network devices.
@@ -30,36 +37,60 @@ type DeviceRepository interface {
 	// Create inserts a new device. If device.ID is empty, a UUID is generated.
 	Create(ctx context.Context, device *models.Device) error
 
-	// Update modifies an existing device's mutable fields.
+	// Update modifies an existing device's mutable fields and records
+	// each changed tracked field in the device history.
 	Update(ctx context.Context, device *models.Device) error
 
-	// Delete removes a device by ID.
+	// Delete archives a device by ID. Archived devices keep their scan
+	// links and history but are excluded from List unless
+	// DeviceFilter.IncludeArchived is set. Archiving an archived device
+	// is a no-op.
 	Delete(ctx context.Context, id string) error
+
+	// HardDelete permanently removes a device, archived or not, along with
+	// its scan links and history.
+	HardDelete(ctx context.Context, id string) error
+
+	// Restore un-archives a device. Restoring a device that is not
+	// archived is a no-op.
+	Restore(ctx context.Context, id string) error
+
+	// AddTags adds tags to each of the given devices in a single
+	// transaction. Tags a device already has are not duplicated. Returns
+	// the number of devices whose tags changed; unknown IDs are ignored.
+	AddTags(ctx context.Context, ids, tags []string) (int, error)
+
+	// RemoveTags removes tags from each of the given devices in a single
+	// transaction, keeping all other tags. Returns the number of devices
+	// whose tags changed; unknown IDs are ignored.
+	RemoveTags(ctx context.Context, ids, tags []string) (int, error)
 }
 
 // Compile-time interface guard.
-var _ DeviceRepository = (*SQLiteDeviceRepository)(nil)
-
-// SQLiteDeviceRepository implements DeviceRepository using SQLite.
-// It queries the recon_devices table directly.
-type SQLiteDeviceRepository struct {
-	db *sql.DB
+var _ DeviceRepository = (*SQLDeviceRepository)(nil)
+
+// SQLDeviceRepository implements DeviceRepository on SQLite or PostgreSQL,
+// following the dialect of the store it was created with. It queries the
+// recon_devices table directly.
+type SQLDeviceRepository struct {
+	db      *sql.DB
+	dialect store.Dialect
 }
 
-// NewSQLiteDeviceRepository creates a DeviceRepository.
+// NewDeviceRepository creates a DeviceRepository.
 // The recon_devices table must already exist (created by the recon module's migrations).
-func NewSQLiteDeviceRepository(db *sql.DB) *SQLiteDeviceRepository {
-	return &SQLiteDeviceRepository{db: db}
+func NewDeviceRepository(s plugin.Store) *SQLDeviceRepository {
+	return &SQLDeviceRepository{db: s.DB(), dialect: store.DialectOf(s)}
 }
 
 // deviceColumns is the shared column list for device queries.
 const deviceColumns = `id, hostname, ip_addresses, mac_address, manufacturer,
 	device_type, os, status, discovery_method, agent_id,
-	first_seen, last_seen, notes, tags, custom_fields`
+	first_seen, last_seen, notes, tags, custom_fields, archived_at`
 
-func (r *SQLiteDeviceRepository) Get(ctx context.Context, id string) (*models.Device, error) {
+func (r *SQLDeviceRepository) Get(ctx context.Context, id string) (*models.Device, error) {
 	row := r.db.QueryRowContext(ctx,
-		`SELECT `+deviceColumns+` FROM recon_devices WHERE id = ?`, id)
+		r.dialect.Rebind(`SELECT `+deviceColumns+` FROM recon_devices WHERE id = ?`), id)
 	d, err := scanDevice(row)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -70,20 +101,34 @@ func (r *SQLiteDeviceRepository) Get(ctx context.Context, id string) (*models.De
 	return d, nil
 }
 
-func (r *SQLiteDeviceRepository) List(ctx context.Context, filter DeviceFilter, opts ListOptions) (*ListResult[models.Device], error) {
+// deviceSortColumns maps the SortBy values List accepts to columns.
+var deviceSortColumns = map[string]string{
+	"hostname":    "hostname",
+	"status":      "status",
+	"last_seen":   "last_seen",
+	"first_seen":  "first_seen",
+	"device_type": "device_type",
+}
+
+// SortByRelevance orders search results by how well they match
+// DeviceFilter.Search: exact hostname, hostname prefix, hostname substring,
+// then IP or MAC matches. It is the default order when Search is set.
+const SortByRelevance = "relevance"
+
+// IsDeviceSortField reports whether name is a SortBy value that List
+// accepts for devices.
+func IsDeviceSortField(name string) bool {
+	_, ok := deviceSortColumns[name]
+	return ok || name == SortByRelevance
+}
+
+func (r *SQLDeviceRepository) List(ctx context.Context, filter DeviceFilter, opts ListOptions) (*ListResult[models.Device], error) {
 	opts = normalizeListOptions(opts)
 
 	// Validate sortBy against allowed columns.
 	sortCol := "last_seen"
-	allowedSorts := map[string]string{
-		"hostname":  "hostname",
-		"status":    "status",
-		"last_seen": "last_seen",
-		"first_seen": "first_seen",
-		"device_type": "device_type",
-	}
 	if opts.SortBy != "" {
-		if col, ok := allowedSorts[opts.SortBy]; ok {
+		if col, ok := deviceSortColumns[opts.SortBy]; ok {
 			sortCol = col
 		}
 	}
@@ -92,6 +137,10 @@ func (r *SQLiteDeviceRepository) List(ctx context.Context, filter DeviceFilter,
 	where := "1=1"
 	var args []any
 
+	if !filter.IncludeArchived {
+		where += " AND archived_at IS NULL"
+	}
+
 	if filter.Status != "" {
 		where += " AND status = ?"
 		args = append(args, filter.Status)
@@ -101,7 +150,8 @@ func (r *SQLiteDeviceRepository) List(ctx context.Context, filter DeviceFilter,
 		args = append(args, filter.DeviceType)
 	}
 	if filter.Search != "" {
-		where += " AND (hostname LIKE ? OR ip_addresses LIKE ? OR mac_address LIKE ?)"
+		like := r.dialect.CaseInsensitiveLike()
+		where += " AND (hostname " + like + " ? OR ip_addresses " + like + " ? OR mac_address " + like + " ?)"
 		pattern := "%" + filter.Search + "%"
 		args = append(args, pattern, pattern, pattern)
 	}
@@ -109,34 +159,56 @@ func (r *SQLiteDeviceRepository) List(ctx context.Context, filter DeviceFilter,
 		where += " AND id IN (SELECT device_id FROM recon_scan_devices WHERE scan_id = ?)"
 		args = append(args, filter.ScanID)
 	}
+	for _, tag := range normalizeTags(filter.Tags) {
+		where += " AND " + r.dialect.JSONArrayContains("recon_devices.tags")
+		args = append(args, tag)
+	}
 
 	// Count total matching rows.
 	var total int
 	//nolint:gosec // where uses parameterized placeholders only
 	err := r.db.QueryRowContext(ctx,
-		"SELECT COUNT(*) FROM recon_devices WHERE "+where, args...,
+		r.dialect.Rebind("SELECT COUNT(*) FROM recon_devices WHERE "+where), args...,
 	).Scan(&total)
 	if err != nil {
 		return nil, fmt.Errorf("count devices: %w", err)
 	}
 
 	// Query with pagination and sorting.
-	queryArgs := make([]any, 0, len(args)+2)
+	queryArgs := make([]any, 0, len(args)+6)
 	queryArgs = append(queryArgs, args...)
-	queryArgs = append(queryArgs, opts.Limit, opts.Offset)
 
 	orderDir := "DESC"
 	if opts.SortOrder == "asc" {
 		orderDir = "ASC"
 	}
+	orderBy := sortCol + " " + orderDir
 
-	//nolint:gosec // where and sortCol are validated above, not user input
+	// Searches rank by relevance. An explicit SortBy overrides the ranking
+	// and relevance only breaks its ties; otherwise the sort column does.
+	if filter.Search != "" {
+		like := r.dialect.CaseInsensitiveLike()
+		rank := "CASE WHEN LOWER(hostname) = LOWER(?) THEN 0" +
+			" WHEN hostname " + like + " ? THEN 1" +
+			" WHEN hostname " + like + " ? THEN 2" +
+			" ELSE 3 END"
+		if opts.SortBy != "" && opts.SortBy != SortByRelevance {
+			orderBy += ", " + rank
+		} else {
+			orderBy = rank + ", " + orderBy
+		}
+		queryArgs = append(queryArgs, filter.Search, filter.Search+"%", "%"+filter.Search+"%")
+	}
+	queryArgs = append(queryArgs, opts.Limit, opts.Offset)
+
+	// id breaks ties so that paging through equal sort values is stable.
+	//nolint:gosec // where and orderBy are built from validated columns, not user input
 	query := fmt.Sprintf(
-		"SELECT %s FROM recon_devices WHERE %s ORDER BY %s %s LIMIT ? OFFSET ?",
-		deviceColumns, where, sortCol, orderDir,
+		"SELECT %s FROM recon_devices WHERE %s ORDER BY %s, id LIMIT ? OFFSET ?",
+		deviceColumns, where, orderBy,
 	)
 
-	rows, err := r.db.QueryContext(ctx, query, queryArgs...)
+	rows, err := r.db.QueryContext(ctx, r.dialect.Rebind(query), queryArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("list devices: %w", err)
 	}
@@ -160,7 +232,7 @@ func (r *SQLiteDeviceRepository) List(ctx context.Context, filter DeviceFilter,
 	return &ListResult[models.Device]{Items: devices, Total: total}, nil
 }
 
-func (r *SQLiteDeviceRepository) Create(ctx context.Context, device *models.Device) error {
+func (r *SQLDeviceRepository) Create(ctx context.Context, device *models.Device) error {
 	if device.ID == "" {
 		device.ID = uuid.New().String()
 	}
@@ -185,12 +257,12 @@ func (r *SQLiteDeviceRepository) Create(ctx context.Context, device *models.Devi
 		cfJSON = []byte("{}")
 	}
 
-	_, err := r.db.ExecContext(ctx, `
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(`
 		INSERT INTO recon_devices (
 			id, hostname, ip_addresses, mac_address, manufacturer,
 			device_type, os, status, discovery_method, agent_id,
 			first_seen, last_seen, notes, tags, custom_fields
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`),
 		device.ID, device.Hostname, string(ipsJSON), device.MACAddress, device.Manufacturer,
 		string(device.DeviceType), device.OS, string(device.Status), string(device.DiscoveryMethod), device.AgentID,
 		device.FirstSeen, device.LastSeen, device.Notes, string(tagsJSON), string(cfJSON),
@@ -201,7 +273,7 @@ func (r *SQLiteDeviceRepository) Create(ctx context.Context, device *models.Devi
 	return nil
 }
 
-func (r *SQLiteDeviceRepository) Update(ctx context.Context, device *models.Device) error {
+func (r *SQLDeviceRepository) Update(ctx context.Context, device *models.Device) error {
 	ipsJSON, _ := json.Marshal(device.IPAddresses)
 	if device.IPAddresses == nil {
 		ipsJSON = []byte("[]")
@@ -215,12 +287,27 @@ func (r *SQLiteDeviceRepository) Update(ctx context.Context, device *models.Devi
 		cfJSON = []byte("{}")
 	}
 
-	res, err := r.db.ExecContext(ctx, `
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback on commit is a no-op
+
+	before, err := scanDevice(tx.QueryRowContext(ctx,
+		r.dialect.Rebind(`SELECT `+deviceColumns+` FROM recon_devices WHERE id = ?`), device.ID))
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("get device %q: %w", device.ID, err)
+	}
+
+	_, err = tx.ExecContext(ctx, r.dialect.Rebind(`
 		UPDATE recon_devices SET
 			hostname = ?, ip_addresses = ?, mac_address = ?, manufacturer = ?,
 			device_type = ?, os = ?, status = ?, discovery_method = ?, agent_id = ?,
 			last_seen = ?, notes = ?, tags = ?, custom_fields = ?
-		WHERE id = ?`,
+		WHERE id = ?`),
 		device.Hostname, string(ipsJSON), device.MACAddress, device.Manufacturer,
 		string(device.DeviceType), device.OS, string(device.Status), string(device.DiscoveryMethod), device.AgentID,
 		device.LastSeen, device.Notes, string(tagsJSON), string(cfJSON),
@@ -229,6 +316,23 @@ func (r *SQLiteDeviceRepository) Update(ctx context.Context, device *models.Devi
 	if err != nil {
 		return fmt.Errorf("update device: %w", err)
 	}
+	if err := insertDeviceChanges(ctx, tx, r.dialect, device.ID, ChangeSourceAPI, DiffDevices(before, device)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func (r *SQLDeviceRepository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx,
+		r.dialect.Rebind(`UPDATE recon_devices SET archived_at = COALESCE(archived_at, ?) WHERE id = ?`),
+		time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("archive device: %w", err)
+	}
 	n, _ := res.RowsAffected()
 	if n == 0 {
 		return ErrNotFound
@@ -236,9 +340,9 @@ func (r *SQLiteDeviceRepository) Update(ctx context.Context, device *models.Devi
 	return nil
 }
 
-func (r *SQLiteDeviceRepository) Delete(ctx context.Context, id string) error {
+func (r *SQLDeviceRepository) HardDelete(ctx context.Context, id string) error {
 	res, err := r.db.ExecContext(ctx,
-		`DELETE FROM recon_devices WHERE id = ?`, id)
+		r.dialect.Rebind(`DELETE FROM recon_devices WHERE id = ?`), id)
 	if err != nil {
 		return fmt.Errorf("delete device: %w", err)
 	}
@@ -249,19 +353,122 @@ func (r *SQLiteDeviceRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+func (r *SQLDeviceRepository) Restore(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx,
+		r.dialect.Rebind(`UPDATE recon_devices SET archived_at = NULL WHERE id = ?`), id)
+	if err != nil {
+		return fmt.Errorf("restore device: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLDeviceRepository) AddTags(ctx context.Context, ids, tags []string) (int, error) {
+	tags = normalizeTags(tags)
+	return r.updateTags(ctx, ids, tags, func(existing []string) []string {
+		out := existing
+		for _, tag := range tags {
+			if !slices.Contains(out, tag) {
+				out = append(out, tag)
+			}
+		}
+		return out
+	})
+}
+
+func (r *SQLDeviceRepository) RemoveTags(ctx context.Context, ids, tags []string) (int, error) {
+	tags = normalizeTags(tags)
+	return r.updateTags(ctx, ids, tags, func(existing []string) []string {
+		return slices.DeleteFunc(existing, func(tag string) bool {
+			return slices.Contains(tags, tag)
+		})
+	})
+}
+
+// updateTags rewrites the tags of each device in ids with apply, inside a
+// single transaction. apply receives the device's normalized tags and may
+// modify them in place. Only rows whose tags change are written.
+func (r *SQLDeviceRepository) updateTags(ctx context.Context, ids, tags []string, apply func([]string) []string) (int, error) {
+	if len(ids) == 0 || len(tags) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck // rollback on commit is a no-op
+
+	updated := 0
+	for _, id := range normalizeTags(ids) {
+		var tagsJSON string
+		err := tx.QueryRowContext(ctx,
+			r.dialect.Rebind(`SELECT tags FROM recon_devices WHERE id = ?`), id).Scan(&tagsJSON)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("get tags for device %q: %w", id, err)
+		}
+
+		var stored []string
+		_ = json.Unmarshal([]byte(tagsJSON), &stored)
+		existing := normalizeTags(stored)
+		next := apply(slices.Clone(existing))
+		if slices.Equal(existing, next) {
+			continue
+		}
+
+		nextJSON, _ := json.Marshal(next)
+		if next == nil {
+			nextJSON = []byte("[]")
+		}
+		if _, err := tx.ExecContext(ctx,
+			r.dialect.Rebind(`UPDATE recon_devices SET tags = ? WHERE id = ?`), string(nextJSON), id); err != nil {
+			return 0, fmt.Errorf("update tags for device %q: %w", id, err)
+		}
+		updated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit tx: %w", err)
+	}
+	return updated, nil
+}
+
+// normalizeTags trims whitespace and drops empty and duplicate entries,
+// keeping the first occurrence of each.
+func normalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(out, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
+
 // scanDevice scans a single *sql.Row into a Device.
 func scanDevice(row *sql.Row) (*models.Device, error) {
 	var d models.Device
 	var ipsJSON, tagsJSON, cfJSON string
 	var dt, status, method string
+	var archivedAt sql.NullTime
 	err := row.Scan(
 		&d.ID, &d.Hostname, &ipsJSON, &d.MACAddress, &d.Manufacturer,
 		&dt, &d.OS, &status, &method, &d.AgentID,
-		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON,
+		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON, &archivedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if archivedAt.Valid {
+		d.ArchivedAt = &archivedAt.Time
+	}
 	d.DeviceType = models.DeviceType(dt)
 	d.Status = models.DeviceStatus(status)
 	d.DiscoveryMethod = models.DiscoveryMethod(method)
@@ -276,14 +483,18 @@ func scanDeviceRow(rows *sql.Rows) (*models.Device, error) {
 	var d models.Device
 	var ipsJSON, tagsJSON, cfJSON string
 	var dt, status, method string
+	var archivedAt sql.NullTime
 	err := rows.Scan(
 		&d.ID, &d.Hostname, &ipsJSON, &d.MACAddress, &d.Manufacturer,
 		&dt, &d.OS, &status, &method, &d.AgentID,
-		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON,
+		&d.FirstSeen, &d.LastSeen, &d.Notes, &tagsJSON, &cfJSON, &archivedAt,
 	)
 	if err != nil {
 		return nil, err
 	}
+	if archivedAt.Valid {
+		d.ArchivedAt = &archivedAt.Time
+	}
 	d.DeviceType = models.DeviceType(dt)
 	d.Status = models.DeviceStatus(status)
 	d.DiscoveryMethod = models.DiscoveryMethod(method)
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/store"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Sources recorded with device history entries.
+const (
+	ChangeSourceAPI  = "api"  // Edited through the API.
+	ChangeSourceScan = "scan" // Observed by a network scan.
+	ChangeSourceSync = "sync" // Reported by an external inventory sync.
+)
+
+// DeviceChange is a change to one tracked device field.
+type DeviceChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// DiffDevices returns the tracked fields whose values differ between before
+// and after, in a fixed field order. IP addresses are compared as a set.
+func DiffDevices(before, after *models.Device) []DeviceChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"hostname", before.Hostname, after.Hostname},
+		{"ip_addresses", joinSorted(before.IPAddresses), joinSorted(after.IPAddresses)},
+		{"mac_address", before.MACAddress, after.MACAddress},
+		{"manufacturer", before.Manufacturer, after.Manufacturer},
+		{"device_type", string(before.DeviceType), string(after.DeviceType)},
+		{"os", before.OS, after.OS},
+		{"status", string(before.Status), string(after.Status)},
+	}
+	var changes []DeviceChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, DeviceChange{Field: f.name, OldValue: f.old, NewValue: f.new})
+		}
+	}
+	return changes
+}
+
+// joinSorted joins a sorted copy of values with commas.
+func joinSorted(values []string) string {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// insertDeviceChanges records changes in recon_device_history within tx.
+func insertDeviceChanges(ctx context.Context, tx *sql.Tx, d store.Dialect, deviceID, source string, changes []DeviceChange) error {
+	now := time.Now().UTC()
+	for _, c := range changes {
+		if _, err := tx.ExecContext(ctx, d.Rebind(`
+			INSERT INTO recon_device_history (id, device_id, field, old_value, new_value, source, changed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			uuid.New().String(), deviceID, c.Field, c.OldValue, c.NewValue, source, now,
+		); err != nil {
+			return fmt.Errorf("record %s change: %w", c.Field, err)
+		}
+	}
+	return nil
+}
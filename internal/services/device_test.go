@@ -3,19 +3,22 @@ package services_test
 import (
 	"context"
 	"database/sql"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/internal/testutil"
 	"github.com/HerbHall/subnetree/pkg/models"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 )
 
-// reconMigrations creates the recon_devices and recon_scan_devices tables
-// needed by the device repository tests.
-var reconMigrations = []plugin.Migration{
-	{
+// reconMigrations creates the recon_devices, recon_scan_devices, and
+// recon_device_history tables needed by the device repository tests.
+func reconMigrations(d store.Dialect) []plugin.Migration {
+	ts := d.TimestampType()
+	return []plugin.Migration{{
 		Version:     1,
 		Description: "create recon tables for testing",
 		Up: func(tx *sql.Tx) error {
@@ -31,11 +34,12 @@ var reconMigrations = []plugin.Migration{
 					status           TEXT NOT NULL DEFAULT 'unknown',
 					discovery_method TEXT NOT NULL DEFAULT 'icmp',
 					agent_id         TEXT NOT NULL DEFAULT '',
-					first_seen       DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-					last_seen        DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					first_seen       ` + ts + ` NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					last_seen        ` + ts + ` NOT NULL DEFAULT CURRENT_TIMESTAMP,
 					notes            TEXT NOT NULL DEFAULT '',
 					tags             TEXT NOT NULL DEFAULT '[]',
-					custom_fields    TEXT NOT NULL DEFAULT '{}'
+					custom_fields    TEXT NOT NULL DEFAULT '{}',
+					archived_at      ` + ts + `
 				)`,
 				`CREATE INDEX idx_recon_devices_mac ON recon_devices(mac_address)`,
 				`CREATE INDEX idx_recon_devices_status ON recon_devices(status)`,
@@ -43,8 +47,8 @@ var reconMigrations = []plugin.Migration{
 				`CREATE TABLE recon_scans (
 					id         TEXT PRIMARY KEY,
 					subnet     TEXT NOT NULL,
-					started_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
-					ended_at   DATETIME,
+					started_at ` + ts + ` NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					ended_at   ` + ts + `,
 					status     TEXT NOT NULL DEFAULT 'pending',
 					total      INTEGER NOT NULL DEFAULT 0,
 					online     INTEGER NOT NULL DEFAULT 0,
@@ -56,6 +60,15 @@ var reconMigrations = []plugin.Migration{
 					device_id TEXT NOT NULL REFERENCES recon_devices(id) ON DELETE CASCADE,
 					PRIMARY KEY (scan_id, device_id)
 				)`,
+				`CREATE TABLE recon_device_history (
+					id         TEXT PRIMARY KEY,
+					device_id  TEXT NOT NULL REFERENCES recon_devices(id) ON DELETE CASCADE,
+					old_value  TEXT NOT NULL,
+					new_value  TEXT NOT NULL,
+					changed_at ` + ts + ` NOT NULL DEFAULT CURRENT_TIMESTAMP,
+					field      TEXT NOT NULL DEFAULT 'status',
+					source     TEXT NOT NULL DEFAULT ''
+				)`,
 			}
 			for _, stmt := range stmts {
 				if _, err := tx.Exec(stmt); err != nil {
@@ -64,19 +77,19 @@ var reconMigrations = []plugin.Migration{
 			}
 			return nil
 		},
-	},
+	}}
 }
 
 func newDeviceRepo(t *testing.T) (services.DeviceRepository, *sql.DB) {
 	t.Helper()
-	store := testutil.NewStore(t)
-	if err := store.Migrate(context.Background(), "recon", reconMigrations); err != nil {
+	s := testutil.NewStore(t)
+	if err := s.Migrate(context.Background(), "recon", reconMigrations(store.DialectSQLite)); err != nil {
 		t.Fatalf("recon migrations: %v", err)
 	}
-	return services.NewSQLiteDeviceRepository(store.DB()), store.DB()
+	return services.NewDeviceRepository(s), s.DB()
 }
 
-func TestSQLiteDeviceRepository_CreateAndGet(t *testing.T) {
+func TestDeviceRepository_CreateAndGet(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -109,7 +122,7 @@ func TestSQLiteDeviceRepository_CreateAndGet(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_CreateGeneratesID(t *testing.T) {
+func TestDeviceRepository_CreateGeneratesID(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -124,7 +137,7 @@ func TestSQLiteDeviceRepository_CreateGeneratesID(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_GetNotFound(t *testing.T) {
+func TestDeviceRepository_GetNotFound(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -134,7 +147,7 @@ func TestSQLiteDeviceRepository_GetNotFound(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_Update(t *testing.T) {
+func TestDeviceRepository_Update(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -163,7 +176,62 @@ func TestSQLiteDeviceRepository_Update(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_UpdateNotFound(t *testing.T) {
+func TestDeviceRepository_UpdateRecordsHistory(t *testing.T) {
+	repo, db := newDeviceRepo(t)
+	ctx := context.Background()
+
+	d := testutil.NewDevice(testutil.WithHostname("old-name"))
+	if err := repo.Create(ctx, &d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	oldStatus := string(d.Status)
+
+	d.Hostname = "new-name"
+	if err := repo.Update(ctx, &d); err != nil {
+		t.Fatalf("Update hostname: %v", err)
+	}
+	d.Status = models.DeviceStatusOffline
+	d.LastSeen = time.Now().UTC() // Untracked; must not add an entry.
+	if err := repo.Update(ctx, &d); err != nil {
+		t.Fatalf("Update status: %v", err)
+	}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT field, old_value, new_value, source FROM recon_device_history
+		WHERE device_id = ? ORDER BY changed_at, rowid`, d.ID)
+	if err != nil {
+		t.Fatalf("query history: %v", err)
+	}
+	defer rows.Close()
+	var got [][4]string
+	for rows.Next() {
+		var e [4]string
+		if err := rows.Scan(&e[0], &e[1], &e[2], &e[3]); err != nil {
+			t.Fatalf("scan history: %v", err)
+		}
+		got = append(got, e)
+	}
+
+	want := [][4]string{
+		{"hostname", "old-name", "new-name", services.ChangeSourceAPI},
+		{"status", oldStatus, "offline", services.ChangeSourceAPI},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("history = %v, want %v", got, want)
+	}
+}
+
+func TestDiffDevices_IgnoresIPOrder(t *testing.T) {
+	before := testutil.NewDevice(testutil.WithIP("10.0.0.1", "10.0.0.2"))
+	after := before
+	after.IPAddresses = []string{"10.0.0.2", "10.0.0.1"}
+
+	if changes := services.DiffDevices(&before, &after); len(changes) != 0 {
+		t.Errorf("DiffDevices() = %v, want no changes", changes)
+	}
+}
+
+func TestDeviceRepository_UpdateNotFound(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -176,7 +244,7 @@ func TestSQLiteDeviceRepository_UpdateNotFound(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_Delete(t *testing.T) {
+func TestDeviceRepository_Delete(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -188,13 +256,27 @@ func TestSQLiteDeviceRepository_Delete(t *testing.T) {
 		t.Fatalf("Delete: %v", err)
 	}
 
-	_, err := repo.Get(ctx, d.ID)
-	if err != services.ErrNotFound {
-		t.Errorf("Get after delete = %v, want ErrNotFound", err)
+	// Delete archives: the record is still readable by ID.
+	got, err := repo.Get(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("Get after delete: %v", err)
+	}
+	if got.ArchivedAt == nil {
+		t.Fatal("ArchivedAt not set after delete")
+	}
+	archivedAt := *got.ArchivedAt
+
+	// Deleting again keeps the original archive time.
+	if err := repo.Delete(ctx, d.ID); err != nil {
+		t.Fatalf("second Delete: %v", err)
+	}
+	got, _ = repo.Get(ctx, d.ID)
+	if got.ArchivedAt == nil || !got.ArchivedAt.Equal(archivedAt) {
+		t.Errorf("ArchivedAt after second delete = %v, want %v", got.ArchivedAt, archivedAt)
 	}
 }
 
-func TestSQLiteDeviceRepository_DeleteNotFound(t *testing.T) {
+func TestDeviceRepository_DeleteNotFound(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -204,7 +286,94 @@ func TestSQLiteDeviceRepository_DeleteNotFound(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListPagination(t *testing.T) {
+func TestDeviceRepository_ListArchived(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	kept := testutil.NewDevice(testutil.WithHostname("kept"))
+	archived := testutil.NewDevice(testutil.WithHostname("archived"))
+	for _, d := range []*models.Device{&kept, &archived} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+	if err := repo.Delete(ctx, archived.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	result, err := repo.List(ctx, services.DeviceFilter{}, services.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if result.Total != 1 || len(result.Items) != 1 || result.Items[0].ID != kept.ID {
+		t.Errorf("List = %+v, want only the unarchived device", result)
+	}
+
+	result, err = repo.List(ctx, services.DeviceFilter{IncludeArchived: true}, services.ListOptions{SortBy: "hostname", SortOrder: "asc"})
+	if err != nil {
+		t.Fatalf("List with archived: %v", err)
+	}
+	if result.Total != 2 || len(result.Items) != 2 {
+		t.Fatalf("List with archived total = %d, want 2", result.Total)
+	}
+	if result.Items[0].ArchivedAt == nil || result.Items[1].ArchivedAt != nil {
+		t.Errorf("ArchivedAt = %v, %v; want only the archived device set", result.Items[0].ArchivedAt, result.Items[1].ArchivedAt)
+	}
+}
+
+func TestDeviceRepository_Restore(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	d := testutil.NewDevice()
+	if err := repo.Create(ctx, &d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.Delete(ctx, d.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := repo.Restore(ctx, d.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	got, err := repo.Get(ctx, d.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.ArchivedAt != nil {
+		t.Errorf("ArchivedAt after restore = %v, want nil", got.ArchivedAt)
+	}
+	result, _ := repo.List(ctx, services.DeviceFilter{}, services.ListOptions{})
+	if result.Total != 1 {
+		t.Errorf("List total after restore = %d, want 1", result.Total)
+	}
+
+	if err := repo.Restore(ctx, "nonexistent-id"); err != services.ErrNotFound {
+		t.Errorf("Restore nonexistent = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeviceRepository_HardDelete(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	d := testutil.NewDevice()
+	if err := repo.Create(ctx, &d); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := repo.HardDelete(ctx, d.ID); err != nil {
+		t.Fatalf("HardDelete: %v", err)
+	}
+
+	if _, err := repo.Get(ctx, d.ID); err != services.ErrNotFound {
+		t.Errorf("Get after hard delete = %v, want ErrNotFound", err)
+	}
+	if err := repo.HardDelete(ctx, d.ID); err != services.ErrNotFound {
+		t.Errorf("HardDelete nonexistent = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDeviceRepository_ListPagination(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -240,7 +409,7 @@ func TestSQLiteDeviceRepository_ListPagination(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListFilterStatus(t *testing.T) {
+func TestDeviceRepository_ListFilterStatus(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -268,7 +437,7 @@ func TestSQLiteDeviceRepository_ListFilterStatus(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListFilterDeviceType(t *testing.T) {
+func TestDeviceRepository_ListFilterDeviceType(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -290,7 +459,7 @@ func TestSQLiteDeviceRepository_ListFilterDeviceType(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListSearch(t *testing.T) {
+func TestDeviceRepository_ListSearch(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -331,7 +500,50 @@ func TestSQLiteDeviceRepository_ListSearch(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListSortAsc(t *testing.T) {
+func TestDeviceRepository_ListSearchRanking(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	// Newest first by default, so without ranking the exact match would
+	// come last.
+	now := time.Now().UTC()
+	devices := []models.Device{
+		testutil.NewDevice(testutil.WithHostname("cafe"), testutil.WithIP("10.0.0.10")),
+		testutil.NewDevice(testutil.WithHostname("cafe-pos"), testutil.WithIP("10.0.0.11")),
+		testutil.NewDevice(testutil.WithHostname("old-cafe-tv"), testutil.WithIP("10.0.0.12")),
+		testutil.NewDevice(testutil.WithHostname("printer"), testutil.WithIP("10.0.0.13"), testutil.WithMAC("cafe.babe.0001")),
+	}
+	for i := range devices {
+		devices[i].LastSeen = now.Add(time.Duration(i) * time.Minute)
+		if err := repo.Create(ctx, &devices[i]); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	result, err := repo.List(ctx, services.DeviceFilter{Search: "CAFE"}, services.ListOptions{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var got []string
+	for _, d := range result.Items {
+		got = append(got, d.Hostname)
+	}
+	want := []string{"cafe", "cafe-pos", "old-cafe-tv", "printer"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+
+	// An explicit SortBy overrides the ranking.
+	result, err = repo.List(ctx, services.DeviceFilter{Search: "cafe"}, services.ListOptions{SortBy: "hostname", SortOrder: "desc"})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(result.Items) == 0 || result.Items[0].Hostname != "printer" {
+		t.Errorf("first with sort_by=hostname desc = %+v, want printer", result.Items)
+	}
+}
+
+func TestDeviceRepository_ListSortAsc(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -367,7 +579,7 @@ func TestSQLiteDeviceRepository_ListSortAsc(t *testing.T) {
 	}
 }
 
-func TestSQLiteDeviceRepository_ListEmpty(t *testing.T) {
+func TestDeviceRepository_ListEmpty(t *testing.T) {
 	repo, _ := newDeviceRepo(t)
 	ctx := context.Background()
 
@@ -385,3 +597,137 @@ func TestSQLiteDeviceRepository_ListEmpty(t *testing.T) {
 		t.Errorf("Items = %d, want 0", len(result.Items))
 	}
 }
+
+func TestDeviceRepository_ListFilterTags(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	tagSets := map[string][]string{
+		"web-prod":  {"prod", "web"},
+		"db-prod":   {"prod", "db", "linux"},
+		"web-lab":   {"lab", "web"},
+		"untagged":  nil,
+		"lookalike": {"production", "webserver"},
+	}
+	for host, tags := range tagSets {
+		d := testutil.NewDevice(testutil.WithHostname(host))
+		d.Tags = tags
+		if err := repo.Create(ctx, &d); err != nil {
+			t.Fatalf("Create %s: %v", host, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"single tag", []string{"prod"}, []string{"db-prod", "web-prod"}},
+		{"all tags required", []string{"prod", "web"}, []string{"web-prod"}},
+		{"no device has all", []string{"lab", "db"}, nil},
+		{"unknown tag", []string{"missing"}, nil},
+		{"no filter", nil, []string{"db-prod", "lookalike", "untagged", "web-lab", "web-prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.List(ctx, services.DeviceFilter{Tags: tt.tags},
+				services.ListOptions{SortBy: "hostname", SortOrder: "asc"})
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			var got []string
+			for i := range result.Items {
+				got = append(got, result.Items[i].Hostname)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hosts = %v, want %v", got, tt.want)
+			}
+			if result.Total != len(tt.want) {
+				t.Errorf("Total = %d, want %d", result.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestDeviceRepository_AddTags(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	d1 := testutil.NewDevice(testutil.WithHostname("tag-1"))
+	d1.Tags = []string{"lab", "linux"}
+	d2 := testutil.NewDevice(testutil.WithHostname("tag-2"))
+	for _, d := range []*models.Device{&d1, &d2} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// "linux" overlaps d1's existing tags; "prod" is given twice.
+	n, err := repo.AddTags(ctx, []string{d1.ID, d2.ID, "missing-id"}, []string{"linux", "prod", " prod "})
+	if err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("AddTags affected = %d, want 2", n)
+	}
+
+	got1, _ := repo.Get(ctx, d1.ID)
+	if want := []string{"lab", "linux", "prod"}; !reflect.DeepEqual(got1.Tags, want) {
+		t.Errorf("d1 tags = %v, want %v", got1.Tags, want)
+	}
+	got2, _ := repo.Get(ctx, d2.ID)
+	if want := []string{"linux", "prod"}; !reflect.DeepEqual(got2.Tags, want) {
+		t.Errorf("d2 tags = %v, want %v", got2.Tags, want)
+	}
+
+	// Adding tags every device already has changes nothing.
+	n, err = repo.AddTags(ctx, []string{d1.ID, d2.ID}, []string{"prod"})
+	if err != nil {
+		t.Fatalf("AddTags again: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("AddTags again affected = %d, want 0", n)
+	}
+}
+
+func TestDeviceRepository_RemoveTags(t *testing.T) {
+	repo, _ := newDeviceRepo(t)
+	ctx := context.Background()
+
+	d1 := testutil.NewDevice(testutil.WithHostname("tag-1"))
+	d1.Tags = []string{"lab", "linux", "prod"}
+	d2 := testutil.NewDevice(testutil.WithHostname("tag-2"))
+	d2.Tags = []string{"windows"}
+	for _, d := range []*models.Device{&d1, &d2} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	// d2 has neither tag, so only d1 is affected.
+	n, err := repo.RemoveTags(ctx, []string{d1.ID, d2.ID}, []string{"prod", "lab"})
+	if err != nil {
+		t.Fatalf("RemoveTags: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("RemoveTags affected = %d, want 1", n)
+	}
+
+	got1, _ := repo.Get(ctx, d1.ID)
+	if want := []string{"linux"}; !reflect.DeepEqual(got1.Tags, want) {
+		t.Errorf("d1 tags = %v, want %v", got1.Tags, want)
+	}
+	got2, _ := repo.Get(ctx, d2.ID)
+	if want := []string{"windows"}; !reflect.DeepEqual(got2.Tags, want) {
+		t.Errorf("d2 tags = %v, want %v", got2.Tags, want)
+	}
+
+	// Removing the last tag leaves an empty list.
+	if _, err := repo.RemoveTags(ctx, []string{d2.ID}, []string{"windows"}); err != nil {
+		t.Fatalf("RemoveTags last: %v", err)
+	}
+	got2, _ = repo.Get(ctx, d2.ID)
+	if len(got2.Tags) != 0 {
+		t.Errorf("d2 tags = %v, want none", got2.Tags)
+	}
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+
+	"github.com/HerbHall/subnetree/internal/tracing"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Compile-time interface guard.
+var _ DeviceRepository = (*TracedDeviceRepository)(nil)
+
+// TracedDeviceRepository wraps a DeviceRepository and records a span for
+// each call as a child of the span in the caller's context.
+type TracedDeviceRepository struct {
+	next DeviceRepository
+}
+
+// NewTracedDeviceRepository wraps next with tracing.
+func NewTracedDeviceRepository(next DeviceRepository) *TracedDeviceRepository {
+	return &TracedDeviceRepository{next: next}
+}
+
+func (t *TracedDeviceRepository) Get(ctx context.Context, id string) (*models.Device, error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Get", attribute.String("device.id", id))
+	device, err := t.next.Get(ctx, id)
+	tracing.End(span, err)
+	return device, err
+}
+
+func (t *TracedDeviceRepository) List(ctx context.Context, filter DeviceFilter, opts ListOptions) (*ListResult[models.Device], error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.List",
+		attribute.Int("list.limit", opts.Limit),
+		attribute.Int("list.offset", opts.Offset),
+	)
+	result, err := t.next.List(ctx, filter, opts)
+	if err == nil {
+		span.SetAttributes(attribute.Int("list.total", result.Total))
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+func (t *TracedDeviceRepository) Create(ctx context.Context, device *models.Device) error {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Create")
+	err := t.next.Create(ctx, device)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedDeviceRepository) Update(ctx context.Context, device *models.Device) error {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Update", attribute.String("device.id", device.ID))
+	err := t.next.Update(ctx, device)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedDeviceRepository) Delete(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Delete", attribute.String("device.id", id))
+	err := t.next.Delete(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedDeviceRepository) HardDelete(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.HardDelete", attribute.String("device.id", id))
+	err := t.next.HardDelete(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedDeviceRepository) Restore(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.Restore", attribute.String("device.id", id))
+	err := t.next.Restore(ctx, id)
+	tracing.End(span, err)
+	return err
+}
+
+func (t *TracedDeviceRepository) AddTags(ctx context.Context, ids, tags []string) (int, error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.AddTags", attribute.Int("device.count", len(ids)))
+	n, err := t.next.AddTags(ctx, ids, tags)
+	tracing.End(span, err)
+	return n, err
+}
+
+func (t *TracedDeviceRepository) RemoveTags(ctx context.Context, ids, tags []string) (int, error) {
+	ctx, span := tracing.Start(ctx, "DeviceRepository.RemoveTags", attribute.Int("device.count", len(ids)))
+	n, err := t.next.RemoveTags(ctx, ids, tags)
+	tracing.End(span, err)
+	return n, err
+}
@@ -0,0 +1,48 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedDeviceRepository_ChildSpans(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tp := tracing.NewProvider(sdktrace.NewSimpleSpanProcessor(exp), 1, "test")
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		otel.SetTracerProvider(prev)
+	})
+
+	inner, _ := newDeviceRepo(t)
+	repo := services.NewTracedDeviceRepository(inner)
+
+	ctx, parent := tracing.Start(context.Background(), "request")
+	if _, err := repo.Get(ctx, "nonexistent-id"); err != services.ErrNotFound {
+		t.Fatalf("Get nonexistent = %v, want ErrNotFound", err)
+	}
+	parent.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	child := spans[0]
+	if child.Name != "DeviceRepository.Get" {
+		t.Errorf("span name = %q, want DeviceRepository.Get", child.Name)
+	}
+	if child.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Error("repository span is not a child of the request span")
+	}
+	if child.Status.Code != codes.Error {
+		t.Errorf("status = %v, want error for ErrNotFound", child.Status.Code)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PasswordPolicy is the set of rules a new password must satisfy. It is
+// enforced wherever a password is set, so account setup and password
+// changes accept exactly the same passwords.
+type PasswordPolicy struct {
+	MinLength      int  `mapstructure:"min_length" json:"min_length"`
+	RequireUpper   bool `mapstructure:"require_upper" json:"require_upper"`
+	RequireLower   bool `mapstructure:"require_lower" json:"require_lower"`
+	RequireDigit   bool `mapstructure:"require_digit" json:"require_digit"`
+	RequireSymbol  bool `mapstructure:"require_symbol" json:"require_symbol"`
+	DisallowCommon bool `mapstructure:"disallow_common" json:"disallow_common"`
+}
+
+// DefaultPasswordPolicy requires at least 8 characters and rejects
+// well-known common passwords.
+func DefaultPasswordPolicy() PasswordPolicy {
+	return PasswordPolicy{MinLength: 8, DisallowCommon: true}
+}
+
+// Password policy rule names reported in PasswordViolation.Rule.
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleUpper     = "uppercase"
+	PasswordRuleLower     = "lowercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleCommon    = "common"
+)
+
+// PasswordViolation is one password policy rule a password failed.
+type PasswordViolation struct {
+	Rule    string `json:"rule" example:"min_length"`
+	Message string `json:"message" example:"password must be at least 8 characters"`
+}
+
+// PasswordPolicyError lists every rule a password failed.
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks password against every rule in the policy. It returns a
+// *PasswordPolicyError listing all failed rules, or nil if none failed.
+func (p PasswordPolicy) Validate(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	var violations []PasswordViolation
+	fail := func(rule, msg string) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: msg})
+	}
+	if utf8.RuneCountInString(password) < p.MinLength {
+		fail(PasswordRuleMinLength, fmt.Sprintf("password must be at least %d characters", p.MinLength))
+	}
+	if p.RequireUpper && !upper {
+		fail(PasswordRuleUpper, "password must contain an uppercase letter")
+	}
+	if p.RequireLower && !lower {
+		fail(PasswordRuleLower, "password must contain a lowercase letter")
+	}
+	if p.RequireDigit && !digit {
+		fail(PasswordRuleDigit, "password must contain a digit")
+	}
+	if p.RequireSymbol && !symbol {
+		fail(PasswordRuleSymbol, "password must contain a symbol")
+	}
+	if p.DisallowCommon && commonPasswords[strings.ToLower(password)] {
+		fail(PasswordRuleCommon, "password is too common")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// commonPasswords is a small denylist of the most frequently breached
+// passwords, compared case-insensitively.
+var commonPasswords = map[string]bool{
+	"password": true, "password1": true, "password123": true, "passw0rd": true,
+	"12345678": true, "123456789": true, "1234567890": true, "11111111": true,
+	"00000000": true, "87654321": true, "abc12345": true, "abcd1234": true,
+	"qwerty123": true, "qwertyuiop": true, "1q2w3e4r": true, "1qaz2wsx": true,
+	"iloveyou": true, "sunshine": true, "princess": true, "football": true,
+	"baseball": true, "superman": true, "starwars": true, "trustno1": true,
+	"letmein1": true, "welcome1": true, "welcome123": true, "admin123": true,
+	"administrator": true, "changeme": true, "default1": true, "subnetree": true,
+}
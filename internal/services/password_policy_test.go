@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/services"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	strict := services.PasswordPolicy{
+		MinLength:      10,
+		RequireUpper:   true,
+		RequireLower:   true,
+		RequireDigit:   true,
+		RequireSymbol:  true,
+		DisallowCommon: true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   services.PasswordPolicy
+		password string
+		want     []string
+	}{
+		{"compliant", strict, "Correct-Horse-42", nil},
+		{"too short", strict, "Ab1-xyz", []string{services.PasswordRuleMinLength}},
+		{"no uppercase", strict, "correct-horse-42", []string{services.PasswordRuleUpper}},
+		{"no lowercase", strict, "CORRECT-HORSE-42", []string{services.PasswordRuleLower}},
+		{"no digit", strict, "Correct-Horse-XL", []string{services.PasswordRuleDigit}},
+		{"no symbol", strict, "CorrectHorse42", []string{services.PasswordRuleSymbol}},
+		{"common", services.DefaultPasswordPolicy(), "Password123", []string{services.PasswordRuleCommon}},
+		{"default compliant", services.DefaultPasswordPolicy(), "securepassword", nil},
+		{"length counts runes", services.PasswordPolicy{MinLength: 8}, "ééééééé", []string{services.PasswordRuleMinLength}},
+		{"several failures", strict, "password", []string{
+			services.PasswordRuleMinLength, services.PasswordRuleUpper,
+			services.PasswordRuleDigit, services.PasswordRuleSymbol, services.PasswordRuleCommon,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.Validate(tt.password)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate(%q) = %v, want nil", tt.password, err)
+				}
+				return
+			}
+			var policyErr *services.PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("Validate(%q) = %v, want *PasswordPolicyError", tt.password, err)
+			}
+			var got []string
+			for _, v := range policyErr.Violations {
+				got = append(got, v.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate(%q) rules = %v, want %v", tt.password, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package services_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/internal/store"
+	"github.com/HerbHall/subnetree/internal/testutil"
+	"github.com/HerbHall/subnetree/pkg/models"
+)
+
+// newPostgresStore connects to the database named by NV_TEST_POSTGRES_DSN,
+// skipping the test when it is unset. Tables the tests create are dropped
+// before and after, so point it at a scratch database.
+func newPostgresStore(t *testing.T) *store.PostgresStore {
+	t.Helper()
+	dsn := os.Getenv("NV_TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("NV_TEST_POSTGRES_DSN not set; skipping PostgreSQL integration test")
+	}
+	s, err := store.NewPostgres(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres: %v", err)
+	}
+	drop := func() {
+		for _, table := range []string{"recon_scan_devices", "recon_scans", "recon_devices", "core_settings", "_migrations"} {
+			if _, err := s.DB().Exec("DROP TABLE IF EXISTS " + table); err != nil {
+				t.Fatalf("drop %s: %v", table, err)
+			}
+		}
+	}
+	drop()
+	t.Cleanup(func() {
+		drop()
+		s.Close()
+	})
+	return s
+}
+
+func TestPostgres_DeviceRepository(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+	if err := s.Migrate(ctx, "recon", reconMigrations(store.DialectPostgres)); err != nil {
+		t.Fatalf("recon migrations: %v", err)
+	}
+	repo := services.NewDeviceRepository(s)
+
+	d1 := testutil.NewDevice(testutil.WithHostname("Web-Server"), testutil.WithIP("10.0.0.1"))
+	d1.Tags = []string{"prod"}
+	d2 := testutil.NewDevice(testutil.WithHostname("printer"), testutil.WithStatus(models.DeviceStatusOffline))
+	for _, d := range []*models.Device{&d1, &d2} {
+		if err := repo.Create(ctx, d); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	got, err := repo.Get(ctx, d1.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Hostname != "Web-Server" || len(got.IPAddresses) != 1 {
+		t.Errorf("Get = %+v, want hostname Web-Server with one IP", got)
+	}
+
+	// Search is case-insensitive on both dialects.
+	res, err := repo.List(ctx, services.DeviceFilter{Search: "web"}, services.ListOptions{})
+	if err != nil {
+		t.Fatalf("List search: %v", err)
+	}
+	if res.Total != 1 || res.Items[0].ID != d1.ID {
+		t.Errorf("search web: total = %d, want 1 (%s)", res.Total, d1.ID)
+	}
+
+	res, err = repo.List(ctx, services.DeviceFilter{Tags: []string{"prod"}}, services.ListOptions{})
+	if err != nil {
+		t.Fatalf("List tags: %v", err)
+	}
+	if res.Total != 1 || res.Items[0].ID != d1.ID {
+		t.Errorf("tag prod: total = %d, want 1 (%s)", res.Total, d1.ID)
+	}
+
+	n, err := repo.AddTags(ctx, []string{d1.ID, d2.ID}, []string{"prod", "lab"})
+	if err != nil {
+		t.Fatalf("AddTags: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("AddTags updated %d devices, want 2", n)
+	}
+
+	if err := repo.Delete(ctx, d2.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	res, err = repo.List(ctx, services.DeviceFilter{Tags: []string{"lab"}}, services.ListOptions{SortBy: "hostname", SortOrder: "asc"})
+	if err != nil {
+		t.Fatalf("List after delete: %v", err)
+	}
+	if res.Total != 1 {
+		t.Errorf("after archive: total = %d, want 1", res.Total)
+	}
+	if err := repo.Restore(ctx, d2.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+
+	got.Notes = "rack 2"
+	if err := repo.Update(ctx, got); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := repo.HardDelete(ctx, d1.ID); err != nil {
+		t.Fatalf("HardDelete: %v", err)
+	}
+	if _, err := repo.Get(ctx, d1.ID); err != services.ErrNotFound {
+		t.Errorf("Get after HardDelete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestPostgres_SettingsRepository(t *testing.T) {
+	s := newPostgresStore(t)
+	ctx := context.Background()
+	repo, err := services.NewSettingsRepository(ctx, s)
+	if err != nil {
+		t.Fatalf("NewSettingsRepository: %v", err)
+	}
+
+	if err := repo.Set(ctx, "site_name", "SubNetree"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	// Setting an existing key takes the upsert path.
+	if err := repo.Set(ctx, "site_name", "Lab"); err != nil {
+		t.Fatalf("Set (update): %v", err)
+	}
+	got, err := repo.Get(ctx, "site_name")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Value != "Lab" || got.UpdatedAt.IsZero() {
+		t.Errorf("Get = %+v, want value Lab with updated_at set", got)
+	}
+
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(all) != 1 {
+		t.Errorf("GetAll returned %d settings, want 1", len(all))
+	}
+
+	if err := repo.Delete(ctx, "site_name"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := repo.Get(ctx, "site_name"); err != services.ErrNotFound {
+		t.Errorf("Get after Delete = %v, want ErrNotFound", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/internal/testutil"
 	"github.com/HerbHall/subnetree/pkg/models"
 	"github.com/google/uuid"
@@ -14,11 +15,11 @@ import (
 
 func newScanRepo(t *testing.T) (services.ScanRepository, *sql.DB) {
 	t.Helper()
-	store := testutil.NewStore(t)
-	if err := store.Migrate(context.Background(), "recon", reconMigrations); err != nil {
+	s := testutil.NewStore(t)
+	if err := s.Migrate(context.Background(), "recon", reconMigrations(store.DialectSQLite)); err != nil {
 		t.Fatalf("recon migrations: %v", err)
 	}
-	return services.NewSQLiteScanRepository(store.DB()), store.DB()
+	return services.NewSQLiteScanRepository(s.DB()), s.DB()
 }
 
 func TestSQLiteScanRepository_CreateAndGet(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/store"
 	"github.com/HerbHall/subnetree/pkg/plugin"
 )
 
@@ -32,26 +33,29 @@ type SettingsRepository interface {
 }
 
 // Compile-time interface guard.
-var _ SettingsRepository = (*SQLiteSettingsRepository)(nil)
+var _ SettingsRepository = (*SQLSettingsRepository)(nil)
 
-// SQLiteSettingsRepository implements SettingsRepository using SQLite.
-type SQLiteSettingsRepository struct {
-	db *sql.DB
+// SQLSettingsRepository implements SettingsRepository on SQLite or
+// PostgreSQL, following the dialect of the store it was created with.
+type SQLSettingsRepository struct {
+	db      *sql.DB
+	dialect store.Dialect
 }
 
-// NewSQLiteSettingsRepository creates a SettingsRepository and runs the
+// NewSettingsRepository creates a SettingsRepository and runs the
 // core_settings migration.
-func NewSQLiteSettingsRepository(ctx context.Context, store plugin.Store) (*SQLiteSettingsRepository, error) {
-	if err := store.Migrate(ctx, "core", settingsMigrations); err != nil {
+func NewSettingsRepository(ctx context.Context, s plugin.Store) (*SQLSettingsRepository, error) {
+	dialect := store.DialectOf(s)
+	if err := s.Migrate(ctx, "core", settingsMigrations(dialect)); err != nil {
 		return nil, fmt.Errorf("core settings migrations: %w", err)
 	}
-	return &SQLiteSettingsRepository{db: store.DB()}, nil
+	return &SQLSettingsRepository{db: s.DB(), dialect: dialect}, nil
 }
 
-func (r *SQLiteSettingsRepository) Get(ctx context.Context, key string) (*Setting, error) {
+func (r *SQLSettingsRepository) Get(ctx context.Context, key string) (*Setting, error) {
 	var s Setting
 	err := r.db.QueryRowContext(ctx,
-		`SELECT key, value, updated_at FROM core_settings WHERE key = ?`, key,
+		r.dialect.Rebind(`SELECT key, value, updated_at FROM core_settings WHERE key = ?`), key,
 	).Scan(&s.Key, &s.Value, &s.UpdatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -62,7 +66,7 @@ func (r *SQLiteSettingsRepository) Get(ctx context.Context, key string) (*Settin
 	return &s, nil
 }
 
-func (r *SQLiteSettingsRepository) GetAll(ctx context.Context) ([]Setting, error) {
+func (r *SQLSettingsRepository) GetAll(ctx context.Context) ([]Setting, error) {
 	rows, err := r.db.QueryContext(ctx,
 		`SELECT key, value, updated_at FROM core_settings ORDER BY key`)
 	if err != nil {
@@ -81,12 +85,13 @@ func (r *SQLiteSettingsRepository) GetAll(ctx context.Context) ([]Setting, error
 	return settings, rows.Err()
 }
 
-func (r *SQLiteSettingsRepository) Set(ctx context.Context, key, value string) error {
+func (r *SQLSettingsRepository) Set(ctx context.Context, key, value string) error {
 	now := time.Now().UTC()
-	_, err := r.db.ExecContext(ctx, `
+	// ON CONFLICT ... DO UPDATE is understood by both SQLite and PostgreSQL.
+	_, err := r.db.ExecContext(ctx, r.dialect.Rebind(`
 		INSERT INTO core_settings (key, value, updated_at)
 		VALUES (?, ?, ?)
-		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`,
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value, updated_at = excluded.updated_at`),
 		key, value, now,
 	)
 	if err != nil {
@@ -95,9 +100,9 @@ func (r *SQLiteSettingsRepository) Set(ctx context.Context, key, value string) e
 	return nil
 }
 
-func (r *SQLiteSettingsRepository) Delete(ctx context.Context, key string) error {
+func (r *SQLSettingsRepository) Delete(ctx context.Context, key string) error {
 	res, err := r.db.ExecContext(ctx,
-		`DELETE FROM core_settings WHERE key = ?`, key)
+		r.dialect.Rebind(`DELETE FROM core_settings WHERE key = ?`), key)
 	if err != nil {
 		return fmt.Errorf("delete setting %q: %w", key, err)
 	}
@@ -109,18 +114,20 @@ func (r *SQLiteSettingsRepository) Delete(ctx context.Context, key string) error
 }
 
 // settingsMigrations defines the database schema for core_settings.
-var settingsMigrations = []plugin.Migration{
-	{
-		Version:     1,
-		Description: "create core_settings table",
-		Up: func(tx *sql.Tx) error {
-			_, err := tx.Exec(`
-				CREATE TABLE core_settings (
-					key        TEXT PRIMARY KEY,
-					value      TEXT NOT NULL,
-					updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
-				)`)
-			return err
+func settingsMigrations(d store.Dialect) []plugin.Migration {
+	return []plugin.Migration{
+		{
+			Version:     1,
+			Description: "create core_settings table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec(`
+					CREATE TABLE core_settings (
+						key        TEXT PRIMARY KEY,
+						value      TEXT NOT NULL,
+						updated_at ` + d.TimestampType() + ` NOT NULL DEFAULT CURRENT_TIMESTAMP
+					)`)
+				return err
+			},
 		},
-	},
+	}
 }
@@ -11,14 +11,14 @@ import (
 func newSettingsRepo(t *testing.T) services.SettingsRepository {
 	t.Helper()
 	store := testutil.NewStore(t)
-	repo, err := services.NewSQLiteSettingsRepository(context.Background(), store)
+	repo, err := services.NewSettingsRepository(context.Background(), store)
 	if err != nil {
-		t.Fatalf("NewSQLiteSettingsRepository: %v", err)
+		t.Fatalf("NewSettingsRepository: %v", err)
 	}
 	return repo
 }
 
-func TestSQLiteSettingsRepository_SetAndGet(t *testing.T) {
+func TestSettingsRepository_SetAndGet(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -41,7 +41,7 @@ func TestSQLiteSettingsRepository_SetAndGet(t *testing.T) {
 	}
 }
 
-func TestSQLiteSettingsRepository_SetOverwrite(t *testing.T) {
+func TestSettingsRepository_SetOverwrite(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -61,7 +61,7 @@ func TestSQLiteSettingsRepository_SetOverwrite(t *testing.T) {
 	}
 }
 
-func TestSQLiteSettingsRepository_GetNotFound(t *testing.T) {
+func TestSettingsRepository_GetNotFound(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -71,7 +71,7 @@ func TestSQLiteSettingsRepository_GetNotFound(t *testing.T) {
 	}
 }
 
-func TestSQLiteSettingsRepository_GetAll(t *testing.T) {
+func TestSettingsRepository_GetAll(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -109,7 +109,7 @@ func TestSQLiteSettingsRepository_GetAll(t *testing.T) {
 	}
 }
 
-func TestSQLiteSettingsRepository_Delete(t *testing.T) {
+func TestSettingsRepository_Delete(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -126,7 +126,7 @@ func TestSQLiteSettingsRepository_Delete(t *testing.T) {
 	}
 }
 
-func TestSQLiteSettingsRepository_DeleteNotFound(t *testing.T) {
+func TestSettingsRepository_DeleteNotFound(t *testing.T) {
 	repo := newSettingsRepo(t)
 	ctx := context.Background()
 
@@ -21,6 +21,9 @@ type User struct {
 	CreatedAt    time.Time `json:"created_at"`
 	LastLogin    time.Time `json:"last_login,omitempty"`
 	Disabled     bool      `json:"disabled"`
+
+	FailedLoginAttempts int        `json:"-"`
+	LockedUntil         *time.Time `json:"locked_until,omitempty"`
 }
 
 // UserRepository provides access to user accounts.
@@ -48,6 +51,17 @@ type UserRepository interface {
 
 	// Count returns the total number of users.
 	Count(ctx context.Context) (int, error)
+
+	// RecordFailedLogin increments a user's failed login counter and
+	// returns the new count.
+	RecordFailedLogin(ctx context.Context, id string) (int, error)
+
+	// Lock locks a user's account until the given time.
+	Lock(ctx context.Context, id string, until time.Time) error
+
+	// Unlock clears a user's lockout and resets the failed login counter.
+	// It is also how a successful login resets the counter.
+	Unlock(ctx context.Context, id string) error
 }
 
 // Compile-time interface guard.
@@ -67,7 +81,7 @@ func NewSQLiteUserRepository(db *sql.DB) *SQLiteUserRepository {
 
 // userColumns is the shared SELECT column list for user queries.
 const userColumns = `id, username, email, password_hash, role, auth_provider,
-	created_at, last_login, disabled`
+	created_at, last_login, disabled, failed_login_attempts, locked_until`
 
 func (r *SQLiteUserRepository) Get(ctx context.Context, id string) (*User, error) {
 	row := r.db.QueryRowContext(ctx,
@@ -189,14 +203,56 @@ func (r *SQLiteUserRepository) Count(ctx context.Context) (int, error) {
 	return count, nil
 }
 
+func (r *SQLiteUserRepository) RecordFailedLogin(ctx context.Context, id string) (int, error) {
+	var attempts int
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE auth_users SET failed_login_attempts = failed_login_attempts + 1
+		WHERE id = ? RETURNING failed_login_attempts`, id,
+	).Scan(&attempts)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("record failed login: %w", err)
+	}
+	return attempts, nil
+}
+
+func (r *SQLiteUserRepository) Lock(ctx context.Context, id string, until time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE auth_users SET locked_until = ? WHERE id = ?`, until.UTC(), id)
+	if err != nil {
+		return fmt.Errorf("lock user: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *SQLiteUserRepository) Unlock(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE auth_users SET failed_login_attempts = 0, locked_until = NULL WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("unlock user: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	if n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
 // scanServiceUser scans a single *sql.Row into a User.
 func scanServiceUser(row *sql.Row) (*User, error) {
 	var u User
 	var passwordHash sql.NullString
-	var lastLogin sql.NullTime
+	var lastLogin, lockedUntil sql.NullTime
 
 	err := row.Scan(&u.ID, &u.Username, &u.Email, &passwordHash, &u.Role,
-		&u.AuthProvider, &u.CreatedAt, &lastLogin, &u.Disabled)
+		&u.AuthProvider, &u.CreatedAt, &lastLogin, &u.Disabled,
+		&u.FailedLoginAttempts, &lockedUntil)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +262,9 @@ func scanServiceUser(row *sql.Row) (*User, error) {
 	if lastLogin.Valid {
 		u.LastLogin = lastLogin.Time
 	}
+	if lockedUntil.Valid {
+		u.LockedUntil = &lockedUntil.Time
+	}
 	return &u, nil
 }
 
@@ -213,10 +272,11 @@ func scanServiceUser(row *sql.Row) (*User, error) {
 func scanServiceUserRow(rows *sql.Rows) (*User, error) {
 	var u User
 	var passwordHash sql.NullString
-	var lastLogin sql.NullTime
+	var lastLogin, lockedUntil sql.NullTime
 
 	err := rows.Scan(&u.ID, &u.Username, &u.Email, &passwordHash, &u.Role,
-		&u.AuthProvider, &u.CreatedAt, &lastLogin, &u.Disabled)
+		&u.AuthProvider, &u.CreatedAt, &lastLogin, &u.Disabled,
+		&u.FailedLoginAttempts, &lockedUntil)
 	if err != nil {
 		return nil, err
 	}
@@ -226,5 +286,8 @@ func scanServiceUserRow(rows *sql.Rows) (*User, error) {
 	if lastLogin.Valid {
 		u.LastLogin = lastLogin.Time
 	}
+	if lockedUntil.Valid {
+		u.LockedUntil = &lockedUntil.Time
+	}
 	return &u, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/HerbHall/subnetree/internal/services"
 	"github.com/HerbHall/subnetree/internal/testutil"
@@ -246,6 +247,53 @@ func TestSQLiteUserRepository_UpdateNotFound(t *testing.T) {
 	}
 }
 
+func TestSQLiteUserRepository_LockoutTransitions(t *testing.T) {
+	repo := newUserRepo(t)
+	ctx := context.Background()
+
+	u := makeUser("lockable", "lock@example.com", "viewer")
+	if err := repo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	for want := 1; want <= 3; want++ {
+		got, err := repo.RecordFailedLogin(ctx, u.ID)
+		if err != nil {
+			t.Fatalf("RecordFailedLogin: %v", err)
+		}
+		if got != want {
+			t.Errorf("RecordFailedLogin = %d, want %d", got, want)
+		}
+	}
+
+	until := time.Now().Add(15 * time.Minute)
+	if err := repo.Lock(ctx, u.ID, until); err != nil {
+		t.Fatalf("Lock: %v", err)
+	}
+	got, _ := repo.Get(ctx, u.ID)
+	if got.FailedLoginAttempts != 3 || got.LockedUntil == nil || !got.LockedUntil.Equal(until.UTC()) {
+		t.Errorf("after Lock: attempts = %d, locked_until = %v", got.FailedLoginAttempts, got.LockedUntil)
+	}
+
+	if err := repo.Unlock(ctx, u.ID); err != nil {
+		t.Fatalf("Unlock: %v", err)
+	}
+	got, _ = repo.Get(ctx, u.ID)
+	if got.FailedLoginAttempts != 0 || got.LockedUntil != nil {
+		t.Errorf("after Unlock: attempts = %d, locked_until = %v", got.FailedLoginAttempts, got.LockedUntil)
+	}
+
+	if _, err := repo.RecordFailedLogin(ctx, "missing"); err != services.ErrNotFound {
+		t.Errorf("RecordFailedLogin(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Lock(ctx, "missing", until); err != services.ErrNotFound {
+		t.Errorf("Lock(missing) = %v, want ErrNotFound", err)
+	}
+	if err := repo.Unlock(ctx, "missing"); err != services.ErrNotFound {
+		t.Errorf("Unlock(missing) = %v, want ErrNotFound", err)
+	}
+}
+
 func TestSQLiteUserRepository_UpdatePassword(t *testing.T) {
 	repo := newUserRepo(t)
 	ctx := context.Background()
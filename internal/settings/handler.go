@@ -7,10 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/pkg/httperr"
 	"go.uber.org/zap"
 )
 
@@ -123,9 +125,14 @@ func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/v1/settings/themes/active", h.handleGetActiveTheme)
 	mux.HandleFunc("PUT /api/v1/settings/themes/active", h.handleSetActiveTheme)
 	mux.HandleFunc("POST /api/v1/settings/themes", h.handleCreateTheme)
+	mux.HandleFunc("POST /api/v1/settings/themes/import", h.handleImportTheme)
 	mux.HandleFunc("GET /api/v1/settings/themes/{id}", h.handleGetTheme)
 	mux.HandleFunc("PUT /api/v1/settings/themes/{id}", h.handleUpdateTheme)
 	mux.HandleFunc("DELETE /api/v1/settings/themes/{id}", h.handleDeleteTheme)
+	mux.HandleFunc("GET /api/v1/settings/themes/{id}/export", h.handleExportTheme)
+	mux.HandleFunc("POST /api/v1/settings/themes/{id}/duplicate", h.handleDuplicateTheme)
+	mux.HandleFunc("GET /api/v1/settings/themes/{id}/versions", h.handleListThemeVersions)
+	mux.HandleFunc("POST /api/v1/settings/themes/{id}/rollback/{version}", h.handleRollbackTheme)
 }
 
 // handleListInterfaces returns all available network interfaces.
@@ -233,14 +240,7 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 
 // writeSettingsError writes an RFC 7807 problem response.
 func writeSettingsError(w http.ResponseWriter, status int, detail string) {
-	w.Header().Set("Content-Type", "application/problem+json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"type":   "https://subnetree.com/problems/settings-error",
-		"title":  http.StatusText(status),
-		"status": status,
-		"detail": detail,
-	})
+	httperr.WriteProblem(w, status, detail)
 }
 
 // ---------- Theme endpoints ----------
@@ -281,7 +281,7 @@ func (h *Handler) handleListThemes(w http.ResponseWriter, r *http.Request) {
 		if !strings.HasPrefix(key, themeKeyPrefix) {
 			continue
 		}
-		if key == themeActiveKey || key == themeSeededKey {
+		if key == themeActiveKey || key == themeSeededKey || strings.Contains(key, themeHistoryInfix) {
 			continue
 		}
 		var td ThemeDefinition
@@ -350,11 +350,12 @@ func (h *Handler) handleGetTheme(w http.ResponseWriter, r *http.Request) {
 // handleCreateTheme creates a new custom theme.
 //
 //	@Summary		Create theme
-//	@Description	Create a new custom theme with CSS token overrides.
+//	@Description	Create a new custom theme with CSS token overrides. Color tokens must be valid CSS colors, and text-primary/bg-root and btn-primary-text/btn-primary-bg must meet WCAG AA contrast (4.5:1).
 //	@Tags			settings
 //	@Accept			json
 //	@Produce		json
 //	@Param			request	body		ThemeDefinition			true	"Theme definition (id, created_at, updated_at, version, built_in are ignored)"
+//	@Param			contrast	query	string	false	"Contrast check mode: error (default) rejects low-contrast themes, warn saves them and returns warnings"	Enums(error, warn)
 //	@Success		201		{object}	ThemeDefinition			"Created theme"
 //	@Failure		400		{object}	SettingsProblemDetail	"Validation error"
 //	@Failure		500		{object}	SettingsProblemDetail	"Internal server error"
@@ -374,6 +375,14 @@ func (h *Handler) handleCreateTheme(w http.ResponseWriter, r *http.Request) {
 		writeSettingsError(w, http.StatusBadRequest, "base_mode must be \"dark\" or \"light\"")
 		return
 	}
+	if err := validateColorTokens(req.Tokens); err != nil {
+		writeSettingsError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	warnings, ok := enforceContrast(w, r, req.Tokens)
+	if !ok {
+		return
+	}
 
 	id, err := generateID()
 	if err != nil {
@@ -409,19 +418,21 @@ func (h *Handler) handleCreateTheme(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, td)
+	writeJSON(w, http.StatusCreated, themeResponse{ThemeDefinition: td, Warnings: warnings})
 }
 
 // handleUpdateTheme updates an existing custom theme.
 //
 //	@Summary		Update theme
-//	@Description	Update a custom theme. Built-in themes cannot be modified.
+//	@Description	Update a custom theme. Built-in themes cannot be modified. Color and contrast rules are the same as for create.
 //	@Tags			settings
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		string					true	"Theme ID"
 //	@Param			request	body		ThemeDefinition			true	"Fields to update"
+//	@Param			contrast	query	string	false	"Contrast check mode: error (default) rejects low-contrast themes, warn saves them and returns warnings"	Enums(error, warn)
 //	@Success		200		{object}	ThemeDefinition			"Updated theme"
+//	@Failure		400		{object}	SettingsProblemDetail	"Validation error"
 //	@Failure		403		{object}	SettingsProblemDetail	"Cannot modify built-in theme"
 //	@Failure		404		{object}	SettingsProblemDetail	"Theme not found"
 //	@Failure		500		{object}	SettingsProblemDetail	"Internal server error"
@@ -457,6 +468,10 @@ func (h *Handler) handleUpdateTheme(w http.ResponseWriter, r *http.Request) {
 		writeSettingsError(w, http.StatusBadRequest, "invalid request body")
 		return
 	}
+	if err := validateColorTokens(patch.Tokens); err != nil {
+		writeSettingsError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	// Merge only provided fields.
 	if patch.Name != "" {
@@ -506,6 +521,18 @@ func (h *Handler) handleUpdateTheme(w http.ResponseWriter, r *http.Request) {
 		existing.Tokens.Effects = patch.Tokens.Effects
 	}
 
+	warnings, ok := enforceContrast(w, r, existing.Tokens)
+	if !ok {
+		return
+	}
+
+	// Keep the previous version so the edit can be rolled back.
+	if err := h.saveThemeVersion(r.Context(), id, existing.Version, setting.Value); err != nil {
+		h.logger.Error("failed to save theme history", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
 	existing.Version++
 	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
 
@@ -522,7 +549,7 @@ func (h *Handler) handleUpdateTheme(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	writeJSON(w, http.StatusOK, existing)
+	writeJSON(w, http.StatusOK, themeResponse{ThemeDefinition: existing, Warnings: warnings})
 }
 
 // handleDeleteTheme deletes a custom theme.
@@ -567,6 +594,9 @@ func (h *Handler) handleDeleteTheme(w http.ResponseWriter, r *http.Request) {
 		writeSettingsError(w, http.StatusInternalServerError, "failed to delete theme")
 		return
 	}
+	if err := h.deleteThemeHistory(r.Context(), id); err != nil {
+		h.logger.Warn("failed to delete theme history", zap.String("id", id), zap.Error(err))
+	}
 
 	// If the deleted theme was the active theme, reset to default.
 	active, err := h.settings.Get(r.Context(), themeActiveKey)
@@ -650,6 +680,34 @@ func (h *Handler) handleSetActiveTheme(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, ActiveThemeResponse(req))
 }
 
+// themeResponse is a theme plus any contrast warnings raised while saving
+// it in warn mode.
+type themeResponse struct {
+	ThemeDefinition
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// enforceContrast runs the WCAG contrast check on tokens. With
+// ?contrast=warn failures are returned as warnings; otherwise (the
+// default, ?contrast=error) a 400 naming the failing pairs is written and
+// ok is false.
+func enforceContrast(w http.ResponseWriter, r *http.Request, tokens ThemeTokens) (warnings []string, ok bool) {
+	mode := r.URL.Query().Get("contrast")
+	if mode != "" && mode != "error" && mode != "warn" {
+		writeSettingsError(w, http.StatusBadRequest, "contrast must be \"error\" or \"warn\"")
+		return nil, false
+	}
+	failures := checkContrast(tokens)
+	if len(failures) == 0 {
+		return nil, true
+	}
+	if mode == "warn" {
+		return failures, true
+	}
+	writeSettingsError(w, http.StatusBadRequest, strings.Join(failures, "; "))
+	return nil, false
+}
+
 // ensureBuiltInThemes seeds built-in themes, adding any new ones that don't exist yet.
 func (h *Handler) ensureBuiltInThemes(ctx context.Context) error {
 	now := time.Now().UTC().Format(time.RFC3339)
@@ -918,9 +976,15 @@ func (h *Handler) ensureBuiltInThemes(ctx context.Context) error {
 	}
 
 	for i := range builtins {
-		// Skip themes that already exist.
-		if _, err := h.settings.Get(ctx, themeKeyPrefix+builtins[i].ID); err == nil {
-			continue
+		// Skip themes that already exist, unless their stored tokens predate
+		// a change to the built-in definition. Built-ins cannot be edited,
+		// so the stored tokens are refreshed in place.
+		if setting, err := h.settings.Get(ctx, themeKeyPrefix+builtins[i].ID); err == nil {
+			var stored ThemeDefinition
+			if err := json.Unmarshal([]byte(setting.Value), &stored); err != nil || reflect.DeepEqual(stored.Tokens, builtins[i].Tokens) {
+				continue
+			}
+			builtins[i].CreatedAt = stored.CreatedAt
 		}
 		data, err := json.Marshal(builtins[i])
 		if err != nil {
@@ -1037,7 +1101,7 @@ func classicDarkTokens() ThemeTokens {
 		Buttons: map[string]string{
 			"btn-primary-bg":    "#5b9cf6",
 			"btn-primary-hover": "#7ab3f8",
-			"btn-primary-text":  "#ffffff",
+			"btn-primary-text":  "#1a1b26",
 			"btn-danger-bg":     "#991b1b",
 			"btn-danger-hover":  "#b91c1c",
 			"btn-danger-text":   "#fecaca",
@@ -1169,7 +1233,7 @@ func sunsetTealTokens() ThemeTokens {
 		},
 		Buttons: map[string]string{
 			"btn-primary-bg": "#2A9D8F", "btn-primary-hover": "#35B4A5",
-			"btn-primary-text": "#1A2F36", "btn-danger-bg": "#C84B31",
+			"btn-primary-text": "#0F1E24", "btn-danger-bg": "#C84B31",
 			"btn-danger-hover": "#E76F51", "btn-danger-text": "#FDE8E0",
 		},
 		Inputs: map[string]string{
@@ -1264,7 +1328,7 @@ func nordicTokens() ThemeTokens {
 			"border-strong": "rgba(141, 153, 174, 0.25)", "border-focus": "#EF233C",
 		},
 		Buttons: map[string]string{
-			"btn-primary-bg": "#EF233C", "btn-primary-hover": "#F5405A",
+			"btn-primary-bg": "#D90429", "btn-primary-hover": "#EF233C",
 			"btn-primary-text": "#FFFFFF", "btn-danger-bg": "#D90429",
 			"btn-danger-hover": "#EF233C", "btn-danger-text": "#FDE8EA",
 		},
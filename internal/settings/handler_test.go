@@ -18,9 +18,9 @@ func setupHandlerEnv(t *testing.T) (*settings.Handler, *http.ServeMux) {
 	t.Helper()
 
 	store := testutil.NewStore(t)
-	repo, err := services.NewSQLiteSettingsRepository(context.Background(), store)
+	repo, err := services.NewSettingsRepository(context.Background(), store)
 	if err != nil {
-		t.Fatalf("NewSQLiteSettingsRepository: %v", err)
+		t.Fatalf("NewSettingsRepository: %v", err)
 	}
 
 	logger, _ := zap.NewDevelopment()
@@ -0,0 +1,190 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"go.uber.org/zap"
+)
+
+// Format marker and version written to every theme export.
+const (
+	themeBundleFormat  = "subnetree-theme"
+	themeBundleVersion = 1
+)
+
+// ThemeBundle is the portable form of a theme used for export and import.
+// It carries the theme's metadata and tokens but none of the fields the
+// server assigns (ID, timestamps, version, built-in flag).
+// @Description Portable theme export bundle.
+type ThemeBundle struct {
+	Format        string       `json:"format" example:"subnetree-theme"`
+	FormatVersion int          `json:"format_version" example:"1"`
+	Name          string       `json:"name" example:"Midnight"`
+	Description   string       `json:"description"`
+	BaseMode      string       `json:"base_mode" example:"dark"`
+	Layers        []ThemeLayer `json:"layers,omitempty"`
+	Tokens        ThemeTokens  `json:"tokens"`
+}
+
+// handleExportTheme returns a theme as a portable bundle.
+//
+//	@Summary		Export theme
+//	@Description	Download a theme as a portable JSON bundle that can be imported on another install.
+//	@Tags			settings
+//	@Produce		json
+//	@Param			id	path		string					true	"Theme ID"
+//	@Success		200	{object}	ThemeBundle				"Theme bundle"
+//	@Failure		404	{object}	SettingsProblemDetail	"Theme not found"
+//	@Failure		500	{object}	SettingsProblemDetail	"Internal server error"
+//	@Router			/settings/themes/{id}/export [get]
+func (h *Handler) handleExportTheme(w http.ResponseWriter, r *http.Request) {
+	if err := h.ensureBuiltInThemes(r.Context()); err != nil {
+		h.logger.Error("failed to ensure built-in themes", zap.Error(err))
+	}
+
+	id := r.PathValue("id")
+	setting, err := h.settings.Get(r.Context(), themeKeyPrefix+id)
+	if err != nil {
+		if err == services.ErrNotFound {
+			writeSettingsError(w, http.StatusNotFound, "theme not found")
+			return
+		}
+		h.logger.Error("failed to get theme for export", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to get theme")
+		return
+	}
+
+	var td ThemeDefinition
+	if err := json.Unmarshal([]byte(setting.Value), &td); err != nil {
+		h.logger.Error("failed to parse theme for export", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to parse theme")
+		return
+	}
+
+	bundle := ThemeBundle{
+		Format:        themeBundleFormat,
+		FormatVersion: themeBundleVersion,
+		Name:          td.Name,
+		Description:   td.Description,
+		BaseMode:      td.BaseMode,
+		Layers:        td.Layers,
+		Tokens:        td.Tokens,
+	}
+
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", themeFileName(td.Name)))
+	writeJSON(w, http.StatusOK, bundle)
+}
+
+// handleImportTheme creates a custom theme from an exported bundle.
+//
+//	@Summary		Import theme
+//	@Description	Create a custom theme from a bundle produced by the export endpoint. The theme gets a new ID and is never built-in. Color and contrast rules are the same as for create.
+//	@Tags			settings
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		ThemeBundle				true	"Theme bundle"
+//	@Param			contrast	query	string	false	"Contrast check mode: error (default) rejects low-contrast themes, warn saves them and returns warnings"	Enums(error, warn)
+//	@Success		201		{object}	ThemeDefinition			"Imported theme"
+//	@Failure		400		{object}	SettingsProblemDetail	"Invalid bundle"
+//	@Failure		500		{object}	SettingsProblemDetail	"Internal server error"
+//	@Router			/settings/themes/import [post]
+func (h *Handler) handleImportTheme(w http.ResponseWriter, r *http.Request) {
+	var bundle ThemeBundle
+	if err := json.NewDecoder(r.Body).Decode(&bundle); err != nil {
+		writeSettingsError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := validateThemeBundle(&bundle); err != nil {
+		writeSettingsError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	warnings, ok := enforceContrast(w, r, bundle.Tokens)
+	if !ok {
+		return
+	}
+
+	id, err := generateID()
+	if err != nil {
+		h.logger.Error("failed to generate theme ID", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to generate theme ID")
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	td := ThemeDefinition{
+		ID:          id,
+		Name:        bundle.Name,
+		Description: bundle.Description,
+		BaseMode:    bundle.BaseMode,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		BuiltIn:     false,
+		Layers:      bundle.Layers,
+		Tokens:      bundle.Tokens,
+	}
+
+	data, err := json.Marshal(td)
+	if err != nil {
+		h.logger.Error("failed to marshal imported theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
+	if err := h.settings.Set(r.Context(), themeKeyPrefix+td.ID, string(data)); err != nil {
+		h.logger.Error("failed to save imported theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, themeResponse{ThemeDefinition: td, Warnings: warnings})
+}
+
+// validateThemeBundle checks an imported bundle's format and fields before
+// it is stored. Contrast is enforced separately by enforceContrast, as for
+// created themes.
+func validateThemeBundle(b *ThemeBundle) error {
+	if b.Format != themeBundleFormat {
+		return fmt.Errorf("format must be %q", themeBundleFormat)
+	}
+	if b.FormatVersion != themeBundleVersion {
+		return fmt.Errorf("unsupported format_version %d", b.FormatVersion)
+	}
+	if strings.TrimSpace(b.Name) == "" {
+		return errors.New("name is required")
+	}
+	if b.BaseMode != "dark" && b.BaseMode != "light" {
+		return errors.New("base_mode must be \"dark\" or \"light\"")
+	}
+	for _, l := range b.Layers {
+		if !slices.Contains(allLayers, l) {
+			return fmt.Errorf("unknown layer %q", l)
+		}
+	}
+	return validateColorTokens(b.Tokens)
+}
+
+// themeFileName returns the download file name for an exported theme.
+func themeFileName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteByte('-')
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		slug = "theme"
+	}
+	return slug + ".theme.json"
+}
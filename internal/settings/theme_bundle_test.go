@@ -0,0 +1,208 @@
+package settings_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/settings"
+)
+
+func TestExportImportTheme_RoundTrip(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	created := doRequest(mux, "POST", "/api/v1/settings/themes", settings.ThemeDefinition{
+		Name:        "Midnight Ops",
+		Description: "Shared NOC theme",
+		BaseMode:    "dark",
+		Layers:      []settings.ThemeLayer{settings.LayerColors, settings.LayerEffects},
+		Tokens: settings.ThemeTokens{
+			Backgrounds: map[string]string{"bg-root": "#0a0a0a", "bg-hover": "rgba(255, 255, 255, 0.05)"},
+			Text:        map[string]string{"text-primary": "white"},
+			Status:      map[string]string{"status-warning": "hsl(40, 90%, 55%)"},
+			Effects:     map[string]string{"shadow-sm": "none"},
+		},
+	})
+	if created.Code != http.StatusCreated {
+		t.Fatalf("CreateTheme status = %d: %s", created.Code, created.Body.String())
+	}
+	var original settings.ThemeDefinition
+	_ = json.NewDecoder(created.Body).Decode(&original)
+
+	w := doRequest(mux, "GET", "/api/v1/settings/themes/"+original.ID+"/export", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("ExportTheme status = %d: %s", w.Code, w.Body.String())
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="midnight-ops.theme.json"` {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+
+	// Server-assigned fields must not leak into the bundle.
+	var raw map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("Decode bundle: %v", err)
+	}
+	for _, key := range []string{"id", "created_at", "updated_at", "version", "built_in"} {
+		if _, ok := raw[key]; ok {
+			t.Errorf("bundle contains server field %q", key)
+		}
+	}
+
+	var bundle settings.ThemeBundle
+	if err := json.Unmarshal(w.Body.Bytes(), &bundle); err != nil {
+		t.Fatalf("Decode bundle: %v", err)
+	}
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes/import", bundle)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("ImportTheme status = %d: %s", w.Code, w.Body.String())
+	}
+	var imported settings.ThemeDefinition
+	if err := json.NewDecoder(w.Body).Decode(&imported); err != nil {
+		t.Fatalf("Decode imported theme: %v", err)
+	}
+
+	if imported.ID == "" || imported.ID == original.ID {
+		t.Errorf("imported ID = %q, want a new ID", imported.ID)
+	}
+	if imported.BuiltIn || imported.Version != 1 {
+		t.Errorf("imported BuiltIn = %v, Version = %d", imported.BuiltIn, imported.Version)
+	}
+	if imported.Name != original.Name || imported.Description != original.Description || imported.BaseMode != original.BaseMode {
+		t.Errorf("imported metadata = %+v, want %+v", imported, original)
+	}
+	if !reflect.DeepEqual(imported.Tokens, original.Tokens) || !reflect.DeepEqual(imported.Layers, original.Layers) {
+		t.Errorf("imported tokens/layers differ from original")
+	}
+
+	// The imported theme is stored and retrievable.
+	w = doRequest(mux, "GET", "/api/v1/settings/themes/"+imported.ID, nil)
+	if w.Code != http.StatusOK {
+		t.Errorf("GetTheme(imported) status = %d", w.Code)
+	}
+}
+
+func TestExportTheme_BuiltInsImportCleanly(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "GET", "/api/v1/settings/themes", nil)
+	var themes []settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&themes)
+	if len(themes) == 0 {
+		t.Fatal("no built-in themes")
+	}
+
+	for _, th := range themes {
+		w := doRequest(mux, "GET", "/api/v1/settings/themes/"+th.ID+"/export", nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("export %s status = %d", th.ID, w.Code)
+		}
+		var bundle settings.ThemeBundle
+		_ = json.NewDecoder(w.Body).Decode(&bundle)
+
+		w = doRequest(mux, "POST", "/api/v1/settings/themes/import", bundle)
+		if w.Code != http.StatusCreated {
+			t.Errorf("import %s status = %d: %s", th.ID, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestExportTheme_NotFound(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "GET", "/api/v1/settings/themes/nonexistent/export", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestImportTheme_Validation(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	valid := func() settings.ThemeBundle {
+		return settings.ThemeBundle{
+			Format:        "subnetree-theme",
+			FormatVersion: 1,
+			Name:          "Imported",
+			BaseMode:      "light",
+			Tokens: settings.ThemeTokens{
+				Backgrounds: map[string]string{"bg-root": "#FFF"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*settings.ThemeBundle)
+		detail string
+	}{
+		{"wrong format", func(b *settings.ThemeBundle) { b.Format = "other" }, "format"},
+		{"future version", func(b *settings.ThemeBundle) { b.FormatVersion = 2 }, "format_version"},
+		{"missing name", func(b *settings.ThemeBundle) { b.Name = " " }, "name"},
+		{"bad base_mode", func(b *settings.ThemeBundle) { b.BaseMode = "sepia" }, "base_mode"},
+		{"unknown layer", func(b *settings.ThemeBundle) { b.Layers = []settings.ThemeLayer{"sound"} }, "layer"},
+		{"bad hex", func(b *settings.ThemeBundle) { b.Tokens.Backgrounds["bg-root"] = "#12345" }, "tokens.backgrounds.bg-root"},
+		{"not a color", func(b *settings.ThemeBundle) { b.Tokens.Text = map[string]string{"text-primary": "url(x)"} }, "tokens.text.text-primary"},
+		{"unknown keyword", func(b *settings.ThemeBundle) { b.Tokens.Charts = map[string]string{"chart-1": "blurple"} }, "tokens.charts.chart-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := valid()
+			tt.mutate(&b)
+			w := doRequest(mux, "POST", "/api/v1/settings/themes/import", b)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(w.Body.String(), tt.detail) {
+				t.Errorf("body = %s, want mention of %q", w.Body.String(), tt.detail)
+			}
+		})
+	}
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes/import", nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("empty body status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	// Nothing invalid was stored.
+	w = doRequest(mux, "GET", "/api/v1/settings/themes", nil)
+	var themes []settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&themes)
+	for _, th := range themes {
+		if !th.BuiltIn {
+			t.Errorf("custom theme %q stored after rejected imports", th.Name)
+		}
+	}
+}
+
+func TestImportTheme_Contrast(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	bundle := settings.ThemeBundle{
+		Format:        "subnetree-theme",
+		FormatVersion: 1,
+		Name:          "Low contrast",
+		BaseMode:      "dark",
+		Tokens: settings.ThemeTokens{
+			Buttons: map[string]string{"btn-primary-text": "#ffffff", "btn-primary-bg": "#5b9cf6"},
+		},
+	}
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes/import", bundle)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), "btn-primary-text (#ffffff) on btn-primary-bg (#5b9cf6)") {
+		t.Errorf("body = %s, want the failing pair", w.Body.String())
+	}
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes/import?contrast=warn", bundle)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("warn mode status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"warnings"`) {
+		t.Errorf("warn mode response has no warnings: %s", w.Body.String())
+	}
+}
@@ -0,0 +1,271 @@
+package settings
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// minContrastRatio is the WCAG 2.x AA minimum for normal text.
+const minContrastRatio = 4.5
+
+// contrastPairs are the foreground/background tokens checked for WCAG
+// contrast. A pair is only checked when the theme sets both tokens.
+var contrastPairs = []struct {
+	fg, bg string
+}{
+	{"text-primary", "bg-root"},
+	{"btn-primary-text", "btn-primary-bg"},
+}
+
+var (
+	hexColorRe  = regexp.MustCompile(`^#([0-9a-fA-F]{3,4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+	funcColorRe = regexp.MustCompile(`^(rgba?|hsla?)\(\s*[-+0-9.]+(deg|%)?(\s*[,\s]\s*[-+0-9.]+%?){2}(\s*[,/]\s*[0-9.]+%?)?\s*\)$`)
+)
+
+// cssNamedColors lists the CSS color keywords accepted in addition to
+// hex and rgb()/hsl() values.
+var cssNamedColors = strings.Fields(`
+	transparent currentcolor
+	aliceblue antiquewhite aqua aquamarine azure beige bisque black
+	blanchedalmond blue blueviolet brown burlywood cadetblue chartreuse
+	chocolate coral cornflowerblue cornsilk crimson cyan darkblue darkcyan
+	darkgoldenrod darkgray darkgreen darkgrey darkkhaki darkmagenta
+	darkolivegreen darkorange darkorchid darkred darksalmon darkseagreen
+	darkslateblue darkslategray darkslategrey darkturquoise darkviolet
+	deeppink deepskyblue dimgray dimgrey dodgerblue firebrick floralwhite
+	forestgreen fuchsia gainsboro ghostwhite gold goldenrod gray green
+	greenyellow grey honeydew hotpink indianred indigo ivory khaki lavender
+	lavenderblush lawngreen lemonchiffon lightblue lightcoral lightcyan
+	lightgoldenrodyellow lightgray lightgreen lightgrey lightpink
+	lightsalmon lightseagreen lightskyblue lightslategray lightslategrey
+	lightsteelblue lightyellow lime limegreen linen magenta maroon
+	mediumaquamarine mediumblue mediumorchid mediumpurple mediumseagreen
+	mediumslateblue mediumspringgreen mediumturquoise mediumvioletred
+	midnightblue mintcream mistyrose moccasin navajowhite navy oldlace
+	olive olivedrab orange orangered orchid palegoldenrod palegreen
+	paleturquoise palevioletred papayawhip peachpuff peru pink plum
+	powderblue purple rebeccapurple red rosybrown royalblue saddlebrown
+	salmon sandybrown seagreen seashell sienna silver skyblue slateblue
+	slategray slategrey snow springgreen steelblue tan teal thistle tomato
+	turquoise violet wheat white whitesmoke yellow yellowgreen
+`)
+
+// isCSSColor reports whether s is a hex color, an rgb()/rgba()/hsl()/hsla()
+// value, or a named CSS color.
+func isCSSColor(s string) bool {
+	s = strings.TrimSpace(s)
+	if hexColorRe.MatchString(s) {
+		return true
+	}
+	lower := strings.ToLower(s)
+	if funcColorRe.MatchString(lower) {
+		return true
+	}
+	return slices.Contains(cssNamedColors, lower)
+}
+
+// colorTokenGroups returns the token categories that hold colors, keyed
+// by their JSON name. Typography, spacing, and effects hold fonts,
+// lengths, shadows, and transitions and are not included.
+func colorTokenGroups(t ThemeTokens) []struct {
+	name   string
+	tokens map[string]string
+} {
+	return []struct {
+		name   string
+		tokens map[string]string
+	}{
+		{"backgrounds", t.Backgrounds},
+		{"text", t.Text},
+		{"borders", t.Borders},
+		{"buttons", t.Buttons},
+		{"inputs", t.Inputs},
+		{"sidebar", t.Sidebar},
+		{"status", t.Status},
+		{"charts", t.Charts},
+	}
+}
+
+// validateColorTokens checks that every token in a color category is a
+// valid CSS color.
+func validateColorTokens(t ThemeTokens) error {
+	for _, g := range colorTokenGroups(t) {
+		for key, value := range g.tokens {
+			if !isCSSColor(value) {
+				return fmt.Errorf("tokens.%s.%s: %q is not a valid CSS color", g.name, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// checkContrast returns a description of each contrastPairs entry whose
+// WCAG contrast ratio is below minContrastRatio. Pairs where either token
+// is unset or is a keyword such as "currentcolor" are skipped.
+func checkContrast(t ThemeTokens) []string {
+	lookup := func(key string) (string, bool) {
+		for _, g := range colorTokenGroups(t) {
+			if v, ok := g.tokens[key]; ok {
+				return v, true
+			}
+		}
+		return "", false
+	}
+
+	var failures []string
+	for _, p := range contrastPairs {
+		fgVal, ok1 := lookup(p.fg)
+		bgVal, ok2 := lookup(p.bg)
+		if !ok1 || !ok2 {
+			continue
+		}
+		fg, ok1 := parseColor(fgVal)
+		bg, ok2 := parseColor(bgVal)
+		if !ok1 || !ok2 {
+			continue
+		}
+		if ratio := contrastRatio(fg, bg); ratio < minContrastRatio {
+			failures = append(failures, fmt.Sprintf(
+				"%s (%s) on %s (%s) has contrast %.2f:1, below the WCAG AA minimum of %.1f:1",
+				p.fg, fgVal, p.bg, bgVal, ratio, minContrastRatio))
+		}
+	}
+	return failures
+}
+
+// rgba is a color with channels in [0, 1].
+type rgba struct {
+	r, g, b, a float64
+}
+
+// parseColor converts a hex, rgb()/rgba(), or hsl()/hsla() value to RGBA.
+// Named colors are not resolved.
+func parseColor(s string) (rgba, bool) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if hexColorRe.MatchString(s) {
+		return parseHexColor(s[1:])
+	}
+	if !funcColorRe.MatchString(s) {
+		return rgba{}, false
+	}
+
+	open := strings.IndexByte(s, '(')
+	name := s[:open]
+	args := strings.FieldsFunc(s[open+1:len(s)-1], func(r rune) bool {
+		return r == ',' || r == '/' || r == ' ' || r == '\t'
+	})
+	if len(args) < 3 {
+		return rgba{}, false
+	}
+
+	c := rgba{a: 1}
+	if len(args) == 4 {
+		c.a = clamp01(parseNumber(args[3], 1))
+	}
+	if strings.HasPrefix(name, "rgb") {
+		c.r = clamp01(parseNumber(args[0], 255))
+		c.g = clamp01(parseNumber(args[1], 255))
+		c.b = clamp01(parseNumber(args[2], 255))
+		return c, true
+	}
+
+	h := parseNumber(strings.TrimSuffix(args[0], "deg"), 1)
+	sat := clamp01(parseNumber(args[1], 100))
+	light := clamp01(parseNumber(args[2], 100))
+	c.r, c.g, c.b = hslToRGB(h, sat, light)
+	return c, true
+}
+
+func parseHexColor(hex string) (rgba, bool) {
+	if len(hex) <= 4 {
+		// Expand #rgb / #rgba shorthand.
+		var b strings.Builder
+		for _, ch := range hex {
+			b.WriteRune(ch)
+			b.WriteRune(ch)
+		}
+		hex = b.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgba{}, false
+	}
+	return rgba{
+		r: float64(v>>24&0xff) / 255,
+		g: float64(v>>16&0xff) / 255,
+		b: float64(v>>8&0xff) / 255,
+		a: float64(v&0xff) / 255,
+	}, true
+}
+
+// parseNumber parses a CSS number or percentage. Plain numbers are divided
+// by scale; percentages are always relative to 100.
+func parseNumber(s string, scale float64) float64 {
+	if p, ok := strings.CutSuffix(s, "%"); ok {
+		v, _ := strconv.ParseFloat(p, 64)
+		return v / 100
+	}
+	v, _ := strconv.ParseFloat(s, 64)
+	return v / scale
+}
+
+func hslToRGB(h, s, l float64) (r, g, b float64) {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return r + m, g + m, b + m
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// contrastRatio returns the WCAG contrast ratio of fg drawn over bg. A
+// translucent foreground is blended onto the background first; the
+// background is treated as opaque since what lies beneath it is unknown.
+func contrastRatio(fg, bg rgba) float64 {
+	blend := func(f, b float64) float64 { return f*fg.a + b*(1-fg.a) }
+	fg = rgba{r: blend(fg.r, bg.r), g: blend(fg.g, bg.g), b: blend(fg.b, bg.b), a: 1}
+
+	l1, l2 := relativeLuminance(fg), relativeLuminance(bg)
+	if l1 < l2 {
+		l1, l2 = l2, l1
+	}
+	return (l1 + 0.05) / (l2 + 0.05)
+}
+
+// relativeLuminance implements the WCAG 2.x relative luminance formula.
+func relativeLuminance(c rgba) float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.r) + 0.7152*linear(c.g) + 0.0722*linear(c.b)
+}
@@ -0,0 +1,184 @@
+package settings_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"github.com/HerbHall/subnetree/internal/settings"
+	"github.com/HerbHall/subnetree/internal/testutil"
+	"go.uber.org/zap"
+)
+
+func themeBody(text, bg, btnText, btnBg string) map[string]any {
+	tokens := map[string]any{}
+	if text != "" || bg != "" {
+		tokens["text"] = map[string]string{"text-primary": text}
+		tokens["backgrounds"] = map[string]string{"bg-root": bg}
+	}
+	if btnText != "" || btnBg != "" {
+		tokens["buttons"] = map[string]string{"btn-primary-text": btnText, "btn-primary-bg": btnBg}
+	}
+	return map[string]any{"name": "Contrast", "base_mode": "dark", "tokens": tokens}
+}
+
+func TestCreateTheme_Contrast(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	tests := []struct {
+		name   string
+		body   map[string]any
+		want   int
+		detail string
+	}{
+		{"black on white", themeBody("#000000", "#ffffff", "", ""), http.StatusCreated, ""},
+		{"light on navy", themeBody("#E8EDF2", "#0D2238", "#0D2238", "#62CB64"), http.StatusCreated, ""},
+		{"just above AA", themeBody("#767676", "#fff", "", ""), http.StatusCreated, ""},
+		{"rgb and hsl", themeBody("rgb(255 255 255)", "hsl(220, 40%, 10%)", "", ""), http.StatusCreated, ""},
+		{"text equals background", themeBody("#1a1b26", "#1a1b26", "", ""), http.StatusBadRequest, "text-primary (#1a1b26) on bg-root (#1a1b26)"},
+		{"just below AA", themeBody("#777777", "#ffffff", "", ""), http.StatusBadRequest, "text-primary"},
+		{"low contrast button", themeBody("", "", "#ffffff", "#5b9cf6"), http.StatusBadRequest, "btn-primary-text (#ffffff) on btn-primary-bg (#5b9cf6)"},
+		{"faded text", themeBody("rgba(255, 255, 255, 0.2)", "#000000", "", ""), http.StatusBadRequest, "text-primary"},
+		{"only one side set", map[string]any{
+			"name": "Partial", "base_mode": "dark",
+			"tokens": map[string]any{"text": map[string]string{"text-primary": "#000000"}},
+		}, http.StatusCreated, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(mux, "POST", "/api/v1/settings/themes", tt.body)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body: %s", w.Code, tt.want, w.Body.String())
+			}
+			if tt.detail != "" && !strings.Contains(w.Body.String(), tt.detail) {
+				t.Errorf("body = %s, want mention of %q", w.Body.String(), tt.detail)
+			}
+		})
+	}
+}
+
+func TestCreateTheme_ContrastWarnMode(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes?contrast=warn", themeBody("#333333", "#222222", "", ""))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	var resp struct {
+		ID       string   `json:"id"`
+		Warnings []string `json:"warnings"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Decode response: %v", err)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], "text-primary") {
+		t.Errorf("Warnings = %v, want one text-primary warning", resp.Warnings)
+	}
+
+	// Warnings are not persisted with the theme.
+	w = doRequest(mux, "GET", "/api/v1/settings/themes/"+resp.ID, nil)
+	if strings.Contains(w.Body.String(), "warnings") {
+		t.Errorf("stored theme contains warnings: %s", w.Body.String())
+	}
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes?contrast=ignore", themeBody("#000", "#fff", "", ""))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid contrast mode status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestCreateTheme_InvalidColor(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	for _, value := range []string{"#ggg", "red-ish", "rgb(1, 2)", ""} {
+		w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{
+			"name": "Bad", "base_mode": "dark",
+			"tokens": map[string]any{"borders": map[string]string{"border-focus": value}},
+		})
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: status = %d, want %d", value, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	// Non-color categories are not checked as colors.
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{
+		"name": "Fonts", "base_mode": "dark",
+		"tokens": map[string]any{"typography": map[string]string{"font-body": "'Inter', sans-serif"}},
+	})
+	if w.Code != http.StatusCreated {
+		t.Errorf("typography tokens status = %d, want %d; body: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+}
+
+func TestUpdateTheme_Contrast(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", themeBody("#ffffff", "#000000", "", ""))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateTheme status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	_ = json.NewDecoder(w.Body).Decode(&created)
+
+	// Changing only the background is checked against the stored text color.
+	patch := map[string]any{"tokens": map[string]any{"backgrounds": map[string]string{"bg-root": "#eeeeee"}}}
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+created.ID, patch)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d; body: %s", w.Code, http.StatusBadRequest, w.Body.String())
+	}
+
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+created.ID+"?contrast=warn", patch)
+	if w.Code != http.StatusOK {
+		t.Fatalf("warn mode status = %d, want %d; body: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"warnings"`) {
+		t.Errorf("warn mode response has no warnings: %s", w.Body.String())
+	}
+
+	patch = map[string]any{"tokens": map[string]any{"text": map[string]string{"text-primary": "not-a-color"}}}
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+created.ID, patch)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid color status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestBuiltInThemes_RefreshStaleTokens(t *testing.T) {
+	store := testutil.NewStore(t)
+	repo, err := services.NewSettingsRepository(context.Background(), store)
+	if err != nil {
+		t.Fatalf("NewSettingsRepository: %v", err)
+	}
+
+	// A Classic Dark seeded before its button colors were fixed.
+	stale := settings.ThemeDefinition{
+		ID: "builtin-classic-dark", Name: "Classic Dark", BaseMode: "dark", Version: 1,
+		CreatedAt: "2025-01-01T00:00:00Z", UpdatedAt: "2025-01-01T00:00:00Z", BuiltIn: true,
+		Tokens: settings.ThemeTokens{
+			Buttons: map[string]string{"btn-primary-text": "#ffffff", "btn-primary-bg": "#5b9cf6"},
+		},
+	}
+	data, _ := json.Marshal(stale)
+	if err := repo.Set(context.Background(), "theme:builtin-classic-dark", string(data)); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	settings.NewHandler(repo, zap.NewNop()).RegisterRoutes(mux)
+
+	w := doRequest(mux, "GET", "/api/v1/settings/themes/builtin-classic-dark", nil)
+	var got settings.ThemeDefinition
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if got.Tokens.Buttons["btn-primary-text"] == "#ffffff" {
+		t.Error("stale built-in tokens were not refreshed")
+	}
+	if got.CreatedAt != stale.CreatedAt {
+		t.Errorf("CreatedAt = %q, want %q", got.CreatedAt, stale.CreatedAt)
+	}
+}
@@ -0,0 +1,109 @@
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DuplicateThemeRequest is the optional body for duplicating a theme.
+// @Description Request body for duplicating a theme. All fields are optional.
+type DuplicateThemeRequest struct {
+	Name string `json:"name,omitempty" example:"Forest Dark (custom)"`
+}
+
+// cloneThemeTokens returns a deep copy of t so the copy can be edited
+// without affecting the source theme.
+func cloneThemeTokens(t ThemeTokens) ThemeTokens {
+	return ThemeTokens{
+		Backgrounds: maps.Clone(t.Backgrounds),
+		Text:        maps.Clone(t.Text),
+		Borders:     maps.Clone(t.Borders),
+		Buttons:     maps.Clone(t.Buttons),
+		Inputs:      maps.Clone(t.Inputs),
+		Sidebar:     maps.Clone(t.Sidebar),
+		Status:      maps.Clone(t.Status),
+		Charts:      maps.Clone(t.Charts),
+		Typography:  maps.Clone(t.Typography),
+		Spacing:     maps.Clone(t.Spacing),
+		Effects:     maps.Clone(t.Effects),
+	}
+}
+
+// handleDuplicateTheme creates an editable custom copy of any theme.
+//
+//	@Summary		Duplicate theme
+//	@Description	Create a custom copy of a theme, including built-in themes. The copy gets a new ID and is named "<name> (copy)" unless a name is given.
+//	@Tags			settings
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Theme ID"
+//	@Param			request	body		DuplicateThemeRequest	false	"Optional name for the copy"
+//	@Success		201		{object}	ThemeDefinition			"Created theme"
+//	@Failure		400		{object}	SettingsProblemDetail	"Invalid request body"
+//	@Failure		404		{object}	SettingsProblemDetail	"Theme not found"
+//	@Failure		500		{object}	SettingsProblemDetail	"Internal server error"
+//	@Router			/settings/themes/{id}/duplicate [post]
+func (h *Handler) handleDuplicateTheme(w http.ResponseWriter, r *http.Request) {
+	var req DuplicateThemeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeSettingsError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if err := h.ensureBuiltInThemes(r.Context()); err != nil {
+		h.logger.Error("failed to ensure built-in themes", zap.Error(err))
+	}
+
+	src, _, ok := h.getTheme(w, r, r.PathValue("id"))
+	if !ok {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = src.Name + " (copy)"
+	}
+
+	id, err := generateID()
+	if err != nil {
+		h.logger.Error("failed to generate theme ID", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to generate theme ID")
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	td := ThemeDefinition{
+		ID:          id,
+		Name:        name,
+		Description: src.Description,
+		BaseMode:    src.BaseMode,
+		Version:     1,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		BuiltIn:     false,
+		Layers:      slices.Clone(src.Layers),
+		Tokens:      cloneThemeTokens(src.Tokens),
+	}
+
+	data, err := json.Marshal(td)
+	if err != nil {
+		h.logger.Error("failed to marshal theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+	if err := h.settings.Set(r.Context(), themeKeyPrefix+td.ID, string(data)); err != nil {
+		h.logger.Error("failed to save theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, td)
+}
@@ -0,0 +1,131 @@
+package settings_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/settings"
+)
+
+func TestDuplicateTheme_BuiltIn(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "GET", "/api/v1/settings/themes/builtin-classic-dark", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GetTheme status = %d", w.Code)
+	}
+	var original settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&original)
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes/builtin-classic-dark/duplicate", nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("DuplicateTheme status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var dup settings.ThemeDefinition
+	if err := json.NewDecoder(w.Body).Decode(&dup); err != nil {
+		t.Fatalf("Decode duplicate: %v", err)
+	}
+
+	if dup.ID == "" || dup.ID == original.ID {
+		t.Errorf("duplicate ID = %q, want a new ID", dup.ID)
+	}
+	if dup.Name != "Classic Dark (copy)" {
+		t.Errorf("duplicate Name = %q, want %q", dup.Name, "Classic Dark (copy)")
+	}
+	if dup.BuiltIn || dup.Version != 1 {
+		t.Errorf("duplicate BuiltIn = %v, Version = %d", dup.BuiltIn, dup.Version)
+	}
+	if !reflect.DeepEqual(dup.Tokens, original.Tokens) || !reflect.DeepEqual(dup.Layers, original.Layers) {
+		t.Errorf("duplicate tokens/layers differ from original")
+	}
+
+	// The copy is editable even though the built-in's button colors are
+	// below the contrast minimum, since the edit does not touch them.
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+dup.ID, map[string]any{
+		"tokens": map[string]any{"charts": map[string]string{"chart-1": "#ff00ff"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateTheme(copy) status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(mux, "GET", "/api/v1/settings/themes/builtin-classic-dark", nil)
+	var after settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&after)
+	if !reflect.DeepEqual(after.Tokens, original.Tokens) {
+		t.Errorf("built-in tokens changed after editing the copy")
+	}
+}
+
+func TestDuplicateTheme_Custom(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{
+		"name":      "Original",
+		"base_mode": "light",
+		"tokens": map[string]any{
+			"backgrounds": map[string]string{"bg-root": "#ffffff"},
+			"text":        map[string]string{"text-primary": "#111111"},
+		},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateTheme status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var original settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&original)
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes/"+original.ID+"/duplicate",
+		settings.DuplicateThemeRequest{Name: "Renamed"})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("DuplicateTheme status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var dup settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&dup)
+	if dup.Name != "Renamed" {
+		t.Errorf("duplicate Name = %q, want %q", dup.Name, "Renamed")
+	}
+
+	// Editing either theme leaves the other untouched.
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+dup.ID, map[string]any{
+		"tokens": map[string]any{"backgrounds": map[string]string{"bg-root": "#eeeeee"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateTheme(copy) status = %d; body: %s", w.Code, w.Body.String())
+	}
+	w = doRequest(mux, "PUT", "/api/v1/settings/themes/"+original.ID, map[string]any{
+		"tokens": map[string]any{"text": map[string]string{"text-primary": "#000000"}},
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("UpdateTheme(original) status = %d; body: %s", w.Code, w.Body.String())
+	}
+
+	get := func(id string) settings.ThemeDefinition {
+		t.Helper()
+		w := doRequest(mux, "GET", "/api/v1/settings/themes/"+id, nil)
+		var td settings.ThemeDefinition
+		_ = json.NewDecoder(w.Body).Decode(&td)
+		return td
+	}
+	gotOrig, gotDup := get(original.ID), get(dup.ID)
+	if gotOrig.Tokens.Backgrounds["bg-root"] != "#ffffff" || gotOrig.Tokens.Text["text-primary"] != "#000000" {
+		t.Errorf("original tokens = %+v", gotOrig.Tokens)
+	}
+	if gotDup.Tokens.Backgrounds["bg-root"] != "#eeeeee" || gotDup.Tokens.Text["text-primary"] != "#111111" {
+		t.Errorf("duplicate tokens = %+v", gotDup.Tokens)
+	}
+}
+
+func TestDuplicateTheme_Errors(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes/nonexistent/duplicate", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown theme status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+
+	w = doRequest(mux, "POST", "/api/v1/settings/themes/builtin-forest-dark/duplicate", "not an object")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid body status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
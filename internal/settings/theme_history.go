@@ -0,0 +1,246 @@
+package settings
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/services"
+	"go.uber.org/zap"
+)
+
+const (
+	// themeHistoryInfix separates a theme ID from its version number in
+	// history keys: theme:{id}:history:{version}.
+	themeHistoryInfix = ":history:"
+	// maxThemeHistory is the number of prior versions kept per theme.
+	maxThemeHistory = 20
+)
+
+// ThemeVersion is one entry in a theme's version history.
+// @Description A current or prior version of a theme.
+type ThemeVersion struct {
+	Version   int          `json:"version" example:"2"`
+	Name      string       `json:"name"`
+	BaseMode  string       `json:"base_mode" example:"dark"`
+	UpdatedAt string       `json:"updated_at"`
+	Current   bool         `json:"current"`
+	Layers    []ThemeLayer `json:"layers,omitempty"`
+	Tokens    ThemeTokens  `json:"tokens"`
+}
+
+func themeHistoryPrefix(id string) string {
+	return themeKeyPrefix + id + themeHistoryInfix
+}
+
+func themeHistoryKey(id string, version int) string {
+	return themeHistoryPrefix(id) + strconv.Itoa(version)
+}
+
+// saveThemeVersion stores raw, the serialized theme at version, in the
+// theme's history and drops the entry that falls out of the window.
+func (h *Handler) saveThemeVersion(ctx context.Context, id string, version int, raw string) error {
+	if err := h.settings.Set(ctx, themeHistoryKey(id, version), raw); err != nil {
+		return fmt.Errorf("save theme history: %w", err)
+	}
+	if old := version - maxThemeHistory; old > 0 {
+		if err := h.settings.Delete(ctx, themeHistoryKey(id, old)); err != nil && err != services.ErrNotFound {
+			return fmt.Errorf("prune theme history: %w", err)
+		}
+	}
+	return nil
+}
+
+// themeHistory returns the stored prior versions of a theme, newest first.
+func (h *Handler) themeHistory(ctx context.Context, id string) ([]ThemeDefinition, error) {
+	all, err := h.settings.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list theme history: %w", err)
+	}
+	prefix := themeHistoryPrefix(id)
+	var versions []ThemeDefinition
+	for i := range all {
+		if !strings.HasPrefix(all[i].Key, prefix) {
+			continue
+		}
+		var td ThemeDefinition
+		if err := json.Unmarshal([]byte(all[i].Value), &td); err != nil {
+			h.logger.Warn("skipping unparsable theme version", zap.String("key", all[i].Key), zap.Error(err))
+			continue
+		}
+		versions = append(versions, td)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Version > versions[j].Version })
+	return versions, nil
+}
+
+// deleteThemeHistory removes all stored versions of a theme.
+func (h *Handler) deleteThemeHistory(ctx context.Context, id string) error {
+	all, err := h.settings.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("list theme history: %w", err)
+	}
+	prefix := themeHistoryPrefix(id)
+	for i := range all {
+		if strings.HasPrefix(all[i].Key, prefix) {
+			if err := h.settings.Delete(ctx, all[i].Key); err != nil && err != services.ErrNotFound {
+				return fmt.Errorf("delete theme history: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// getTheme loads a theme and its serialized form, writing a 404 or 500
+// response and returning ok=false on failure.
+func (h *Handler) getTheme(w http.ResponseWriter, r *http.Request, id string) (td ThemeDefinition, raw string, ok bool) {
+	setting, err := h.settings.Get(r.Context(), themeKeyPrefix+id)
+	if err != nil {
+		if err == services.ErrNotFound {
+			writeSettingsError(w, http.StatusNotFound, "theme not found")
+			return td, "", false
+		}
+		h.logger.Error("failed to get theme", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to get theme")
+		return td, "", false
+	}
+	if err := json.Unmarshal([]byte(setting.Value), &td); err != nil {
+		h.logger.Error("failed to parse theme", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to parse theme")
+		return td, "", false
+	}
+	return td, setting.Value, true
+}
+
+// handleListThemeVersions returns the current and prior versions of a theme.
+//
+//	@Summary		List theme versions
+//	@Description	Get the current version of a theme followed by its stored prior versions, newest first. Up to 20 prior versions are kept.
+//	@Tags			settings
+//	@Produce		json
+//	@Param			id	path		string					true	"Theme ID"
+//	@Success		200	{array}		ThemeVersion			"Theme versions"
+//	@Failure		404	{object}	SettingsProblemDetail	"Theme not found"
+//	@Failure		500	{object}	SettingsProblemDetail	"Internal server error"
+//	@Router			/settings/themes/{id}/versions [get]
+func (h *Handler) handleListThemeVersions(w http.ResponseWriter, r *http.Request) {
+	if err := h.ensureBuiltInThemes(r.Context()); err != nil {
+		h.logger.Error("failed to ensure built-in themes", zap.Error(err))
+	}
+
+	id := r.PathValue("id")
+	current, _, ok := h.getTheme(w, r, id)
+	if !ok {
+		return
+	}
+
+	history, err := h.themeHistory(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to list theme versions", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to list theme versions")
+		return
+	}
+
+	versions := make([]ThemeVersion, 0, len(history)+1)
+	versions = append(versions, toThemeVersion(&current, true))
+	for i := range history {
+		versions = append(versions, toThemeVersion(&history[i], false))
+	}
+	writeJSON(w, http.StatusOK, versions)
+}
+
+func toThemeVersion(td *ThemeDefinition, current bool) ThemeVersion {
+	return ThemeVersion{
+		Version:   td.Version,
+		Name:      td.Name,
+		BaseMode:  td.BaseMode,
+		UpdatedAt: td.UpdatedAt,
+		Current:   current,
+		Layers:    td.Layers,
+		Tokens:    td.Tokens,
+	}
+}
+
+// handleRollbackTheme restores a prior version of a theme as a new version.
+//
+//	@Summary		Roll back theme
+//	@Description	Restore the base mode, layers, and tokens of a prior version. The restored state is saved as a new version, so the rollback itself can be undone. Name and description are kept. Built-in themes cannot be rolled back.
+//	@Tags			settings
+//	@Produce		json
+//	@Param			id		path		string					true	"Theme ID"
+//	@Param			version	path		int						true	"Version to restore"
+//	@Success		200		{object}	ThemeDefinition			"Restored theme"
+//	@Failure		400		{object}	SettingsProblemDetail	"Invalid version"
+//	@Failure		403		{object}	SettingsProblemDetail	"Cannot modify built-in theme"
+//	@Failure		404		{object}	SettingsProblemDetail	"Theme or version not found"
+//	@Failure		500		{object}	SettingsProblemDetail	"Internal server error"
+//	@Router			/settings/themes/{id}/rollback/{version} [post]
+func (h *Handler) handleRollbackTheme(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	version, err := strconv.Atoi(r.PathValue("version"))
+	if err != nil || version < 1 {
+		writeSettingsError(w, http.StatusBadRequest, "version must be a positive integer")
+		return
+	}
+
+	existing, raw, ok := h.getTheme(w, r, id)
+	if !ok {
+		return
+	}
+	if existing.BuiltIn {
+		writeSettingsError(w, http.StatusForbidden, "cannot modify built-in theme")
+		return
+	}
+	if version == existing.Version {
+		writeSettingsError(w, http.StatusBadRequest, "version is already current")
+		return
+	}
+
+	setting, err := h.settings.Get(r.Context(), themeHistoryKey(id, version))
+	if err != nil {
+		if err == services.ErrNotFound {
+			writeSettingsError(w, http.StatusNotFound, "theme version not found")
+			return
+		}
+		h.logger.Error("failed to get theme version", zap.String("id", id), zap.Int("version", version), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to get theme version")
+		return
+	}
+	var target ThemeDefinition
+	if err := json.Unmarshal([]byte(setting.Value), &target); err != nil {
+		h.logger.Error("failed to parse theme version", zap.String("id", id), zap.Int("version", version), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to parse theme version")
+		return
+	}
+
+	if err := h.saveThemeVersion(r.Context(), id, existing.Version, raw); err != nil {
+		h.logger.Error("failed to save theme history", zap.String("id", id), zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
+	existing.BaseMode = target.BaseMode
+	existing.Layers = target.Layers
+	existing.Tokens = target.Tokens
+	existing.Version++
+	existing.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	data, err := json.Marshal(existing)
+	if err != nil {
+		h.logger.Error("failed to marshal restored theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+	if err := h.settings.Set(r.Context(), themeKeyPrefix+id, string(data)); err != nil {
+		h.logger.Error("failed to save restored theme", zap.Error(err))
+		writeSettingsError(w, http.StatusInternalServerError, "failed to store theme")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, existing)
+}
@@ -0,0 +1,168 @@
+package settings_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/HerbHall/subnetree/internal/settings"
+)
+
+func TestThemeVersions_EditTwiceAndRollBack(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	v1Tokens := map[string]string{"bg-root": "#101010", "bg-card": "#202020"}
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{
+		"name":      "Versioned",
+		"base_mode": "dark",
+		"tokens":    map[string]any{"backgrounds": v1Tokens},
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("CreateTheme status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var created settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&created)
+	base := "/api/v1/settings/themes/" + created.ID
+
+	for i, bg := range []string{"#303030", "#404040"} {
+		w = doRequest(mux, "PUT", base, map[string]any{
+			"tokens": map[string]any{"backgrounds": map[string]string{"bg-root": bg}},
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("edit %d status = %d; body: %s", i+1, w.Code, w.Body.String())
+		}
+	}
+
+	w = doRequest(mux, "GET", base+"/versions", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("ListVersions status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var versions []settings.ThemeVersion
+	if err := json.NewDecoder(w.Body).Decode(&versions); err != nil {
+		t.Fatalf("Decode versions: %v", err)
+	}
+	var got []int
+	for _, v := range versions {
+		got = append(got, v.Version)
+	}
+	if !reflect.DeepEqual(got, []int{3, 2, 1}) {
+		t.Fatalf("versions = %v, want [3 2 1]", got)
+	}
+	if !versions[0].Current || versions[1].Current || versions[2].Current {
+		t.Errorf("only the first entry should be current: %+v", versions)
+	}
+	if versions[0].Tokens.Backgrounds["bg-root"] != "#404040" {
+		t.Errorf("current bg-root = %q, want #404040", versions[0].Tokens.Backgrounds["bg-root"])
+	}
+
+	w = doRequest(mux, "POST", base+"/rollback/1", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Rollback status = %d; body: %s", w.Code, w.Body.String())
+	}
+	var restored settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&restored)
+	if restored.Version != 4 {
+		t.Errorf("restored Version = %d, want 4", restored.Version)
+	}
+	if !reflect.DeepEqual(restored.Tokens.Backgrounds, v1Tokens) {
+		t.Errorf("restored backgrounds = %v, want %v", restored.Tokens.Backgrounds, v1Tokens)
+	}
+
+	// The stored theme matches, and the pre-rollback state is in history.
+	w = doRequest(mux, "GET", base, nil)
+	var fetched settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&fetched)
+	if !reflect.DeepEqual(fetched.Tokens.Backgrounds, v1Tokens) {
+		t.Errorf("stored backgrounds = %v, want %v", fetched.Tokens.Backgrounds, v1Tokens)
+	}
+	w = doRequest(mux, "GET", base+"/versions", nil)
+	versions = nil
+	_ = json.NewDecoder(w.Body).Decode(&versions)
+	if len(versions) != 4 || versions[1].Version != 3 || versions[1].Tokens.Backgrounds["bg-root"] != "#404040" {
+		t.Errorf("versions after rollback = %+v", versions)
+	}
+
+	// History entries are not listed as themes.
+	w = doRequest(mux, "GET", "/api/v1/settings/themes", nil)
+	var themes []settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&themes)
+	count := 0
+	for _, th := range themes {
+		if th.ID == created.ID {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("theme listed %d times, want 1", count)
+	}
+}
+
+func TestThemeRollback_Errors(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{"name": "Solo", "base_mode": "light"})
+	var created settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&created)
+	base := "/api/v1/settings/themes/" + created.ID
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"built-in", "/api/v1/settings/themes/builtin-forest-dark/rollback/1", http.StatusForbidden},
+		{"unknown theme", "/api/v1/settings/themes/nope/rollback/1", http.StatusNotFound},
+		{"missing version", base + "/rollback/7", http.StatusNotFound},
+		{"current version", base + "/rollback/1", http.StatusBadRequest},
+		{"bad version", base + "/rollback/abc", http.StatusBadRequest},
+		{"zero version", base + "/rollback/0", http.StatusBadRequest},
+	}
+	doRequest(mux, "GET", "/api/v1/settings/themes", nil) // seed built-ins
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(mux, "POST", tt.path, nil)
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+
+	w = doRequest(mux, "GET", "/api/v1/settings/themes/nope/versions", nil)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("versions of unknown theme status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestThemeVersions_HistoryIsBounded(t *testing.T) {
+	_, mux := setupHandlerEnv(t)
+
+	w := doRequest(mux, "POST", "/api/v1/settings/themes", map[string]any{"name": "Busy", "base_mode": "dark"})
+	var created settings.ThemeDefinition
+	_ = json.NewDecoder(w.Body).Decode(&created)
+	base := "/api/v1/settings/themes/" + created.ID
+
+	for i := range 25 {
+		w = doRequest(mux, "PUT", base, map[string]any{"description": fmt.Sprintf("edit %d", i)})
+		if w.Code != http.StatusOK {
+			t.Fatalf("edit %d status = %d", i, w.Code)
+		}
+	}
+
+	w = doRequest(mux, "GET", base+"/versions", nil)
+	var versions []settings.ThemeVersion
+	_ = json.NewDecoder(w.Body).Decode(&versions)
+	// Current version 26 plus the 20 most recent prior versions.
+	if len(versions) != 21 || versions[0].Version != 26 || versions[20].Version != 6 {
+		t.Errorf("got %d versions, want 21 (26 down to 6): %+v", len(versions), versions)
+	}
+
+	// Deleting the theme removes its history.
+	if w := doRequest(mux, "DELETE", base, nil); w.Code != http.StatusNoContent {
+		t.Fatalf("DeleteTheme status = %d", w.Code)
+	}
+	if w := doRequest(mux, "POST", base+"/rollback/10", nil); w.Code != http.StatusNotFound {
+		t.Errorf("rollback after delete status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
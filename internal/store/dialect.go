@@ -0,0 +1,93 @@
+package store
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+// Dialect identifies the SQL database behind a store and isolates the SQL
+// that differs between them. Queries are written with SQLite's ?
+// placeholders and passed through Rebind.
+type Dialect string
+
+// Supported dialects. The values double as database.driver config values.
+const (
+	DialectSQLite   Dialect = "sqlite"
+	DialectPostgres Dialect = "postgres"
+)
+
+// DialectOf returns the dialect of s, defaulting to SQLite for stores that
+// do not report one.
+func DialectOf(s plugin.Store) Dialect {
+	if d, ok := s.(interface{ Dialect() Dialect }); ok {
+		return d.Dialect()
+	}
+	return DialectSQLite
+}
+
+// Rebind rewrites ? placeholders for the dialect: $1, $2, ... for
+// PostgreSQL, unchanged for SQLite. Question marks inside single-quoted
+// literals are left alone.
+func (d Dialect) Rebind(query string) string {
+	if d != DialectPostgres || !strings.Contains(query, "?") {
+		return query
+	}
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+	n, quoted := 0, false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			quoted = !quoted
+			b.WriteByte(c)
+		case c == '?' && !quoted:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// TimestampType is the column type for timestamps.
+func (d Dialect) TimestampType() string {
+	if d == DialectPostgres {
+		return "TIMESTAMPTZ"
+	}
+	return "DATETIME"
+}
+
+// CaseInsensitiveLike is the operator for case-insensitive LIKE matching.
+// SQLite's LIKE already ignores ASCII case.
+func (d Dialect) CaseInsensitiveLike() string {
+	if d == DialectPostgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// JSONArrayContains returns a predicate that is true when the JSON array
+// of strings stored as text in column contains the value bound to the
+// predicate's single ? placeholder.
+func (d Dialect) JSONArrayContains(column string) string {
+	if d == DialectPostgres {
+		return "EXISTS (SELECT 1 FROM jsonb_array_elements_text((" + column + ")::jsonb) AS elem(value) WHERE elem.value = ?)"
+	}
+	return "EXISTS (SELECT 1 FROM json_each(" + column + ") WHERE value = ?)"
+}
+
+// migrationLock returns a statement that serializes migrations across
+// processes sharing the database for the rest of the transaction, or ""
+// when the database already allows only one writer.
+func (d Dialect) migrationLock() string {
+	if d == DialectPostgres {
+		// Arbitrary application-wide key for pg_advisory_xact_lock.
+		return "SELECT pg_advisory_xact_lock(7245061302)"
+	}
+	return ""
+}
@@ -0,0 +1,69 @@
+package store
+
+import "testing"
+
+func TestRebind_postgres_numbers_placeholders(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{"SELECT * FROM t WHERE a = ?", "SELECT * FROM t WHERE a = $1"},
+		{"INSERT INTO t (a, b, c) VALUES (?, ?, ?)", "INSERT INTO t (a, b, c) VALUES ($1, $2, $3)"},
+		{"SELECT * FROM t WHERE a = '?' AND b = ?", "SELECT * FROM t WHERE a = '?' AND b = $1"},
+		{"SELECT * FROM t WHERE a = 'it''s?' AND b = ?", "SELECT * FROM t WHERE a = 'it''s?' AND b = $1"},
+	}
+	for _, tt := range tests {
+		if got := DialectPostgres.Rebind(tt.query); got != tt.want {
+			t.Errorf("Rebind(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestRebind_sqlite_unchanged(t *testing.T) {
+	q := "SELECT * FROM t WHERE a = ? AND b = ?"
+	if got := DialectSQLite.Rebind(q); got != q {
+		t.Errorf("Rebind(%q) = %q, want unchanged", q, got)
+	}
+}
+
+func TestDialectOf(t *testing.T) {
+	s := tempDB(t)
+	if got := DialectOf(s); got != DialectSQLite {
+		t.Errorf("DialectOf(SQLiteStore) = %q, want %q", got, DialectSQLite)
+	}
+}
+
+func TestOpen_selects_driver(t *testing.T) {
+	s, err := Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Open(sqlite): %v", err)
+	}
+	defer s.Close()
+	if s.Dialect() != DialectSQLite {
+		t.Errorf("Dialect() = %q, want %q", s.Dialect(), DialectSQLite)
+	}
+
+	if _, err := Open("mysql", "whatever"); err == nil {
+		t.Error("Open(mysql): expected error for unsupported driver, got nil")
+	}
+}
+
+func TestJSONArrayContains_sqlite(t *testing.T) {
+	s := tempDB(t)
+	query := "SELECT COUNT(*) FROM (SELECT '[\"a\",\"b\"]' AS tags) WHERE " + DialectSQLite.JSONArrayContains("tags")
+
+	var n int
+	if err := s.DB().QueryRow(query, "b").Scan(&n); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("contains b: count = %d, want 1", n)
+	}
+	if err := s.DB().QueryRow(query, "c").Scan(&n); err != nil {
+		t.Fatalf("query: %v", err)
+	}
+	if n != 0 {
+		t.Errorf("contains c: count = %d, want 0", n)
+	}
+}
@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+// postgresDSNEnv names the environment variable holding a connection string
+// for the PostgreSQL integration tests. They are skipped when it is unset.
+// The tests drop and recreate their tables, so point it at a scratch database.
+const postgresDSNEnv = "NV_TEST_POSTGRES_DSN"
+
+func tempPostgres(t *testing.T) *PostgresStore {
+	t.Helper()
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s not set; skipping PostgreSQL integration test", postgresDSNEnv)
+	}
+	s, err := NewPostgres(dsn)
+	if err != nil {
+		t.Fatalf("NewPostgres: %v", err)
+	}
+	drop := func() {
+		for _, table := range []string{"pgtest_items", "_migrations", "_schema_meta"} {
+			if _, err := s.DB().Exec("DROP TABLE IF EXISTS " + table); err != nil {
+				t.Fatalf("drop %s: %v", table, err)
+			}
+		}
+	}
+	drop()
+	t.Cleanup(func() {
+		drop()
+		s.Close()
+	})
+	return s
+}
+
+var pgtestMigrations = []plugin.Migration{
+	{
+		Version:     1,
+		Description: "create pgtest_items",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`CREATE TABLE pgtest_items (id TEXT PRIMARY KEY, name TEXT NOT NULL)`)
+			return err
+		},
+		Down: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`DROP TABLE pgtest_items`)
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "add created_at",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec(`ALTER TABLE pgtest_items ADD COLUMN created_at ` + DialectPostgres.TimestampType())
+			return err
+		},
+	},
+}
+
+func TestPostgres_migrate_and_rollback(t *testing.T) {
+	s := tempPostgres(t)
+	ctx := context.Background()
+
+	if err := s.Migrate(ctx, "pgtest", pgtestMigrations); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	// Re-running is a no-op.
+	if err := s.Migrate(ctx, "pgtest", pgtestMigrations); err != nil {
+		t.Fatalf("Migrate (again): %v", err)
+	}
+
+	var count int
+	if err := s.DB().QueryRowContext(ctx,
+		DialectPostgres.Rebind("SELECT COUNT(*) FROM _migrations WHERE plugin_name = ?"), "pgtest",
+	).Scan(&count); err != nil {
+		t.Fatalf("count migrations: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("applied migrations = %d, want 2", count)
+	}
+
+	// Version 2 has no Down, so rolling back past it must fail untouched.
+	err := s.Rollback(ctx, "pgtest", pgtestMigrations, 0)
+	if !errors.Is(err, ErrIrreversibleMigration) {
+		t.Fatalf("Rollback to 0: err = %v, want ErrIrreversibleMigration", err)
+	}
+	if _, err := s.DB().ExecContext(ctx, "SELECT created_at FROM pgtest_items"); err != nil {
+		t.Errorf("schema changed by failed rollback: %v", err)
+	}
+}
+
+func TestPostgres_check_version(t *testing.T) {
+	s := tempPostgres(t)
+	ctx := context.Background()
+
+	if err := s.CheckVersion(ctx, "1.2.0"); err != nil {
+		t.Fatalf("CheckVersion(1.2.0): %v", err)
+	}
+	if err := s.CheckVersion(ctx, "1.3.0"); err != nil {
+		t.Fatalf("CheckVersion(1.3.0): %v", err)
+	}
+	if err := s.CheckVersion(ctx, "1.2.0"); !errors.Is(err, ErrNewerSchema) {
+		t.Errorf("CheckVersion(1.2.0) after 1.3.0: err = %v, want ErrNewerSchema", err)
+	}
+}
+
+func TestPostgres_tx_rollback(t *testing.T) {
+	s := tempPostgres(t)
+	ctx := context.Background()
+
+	if err := s.Migrate(ctx, "pgtest", pgtestMigrations[:1]); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	sentinel := errors.New("abort")
+	err := s.Tx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			DialectPostgres.Rebind("INSERT INTO pgtest_items (id, name) VALUES (?, ?)"), "a", "alpha",
+		); err != nil {
+			return err
+		}
+		return sentinel
+	})
+	if !errors.Is(err, sentinel) {
+		t.Fatalf("Tx: err = %v, want sentinel", err)
+	}
+
+	var count int
+	if err := s.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM pgtest_items").Scan(&count); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("rows after rollback = %d, want 0", count)
+	}
+}
@@ -3,7 +3,9 @@ package store
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"sync"
 
 	"github.com/HerbHall/subnetree/pkg/plugin"
@@ -11,18 +13,66 @@ import (
 	_ "modernc.org/sqlite" // Pure-Go SQLite driver
 )
 
+// ErrIrreversibleMigration is returned by Rollback when a migration that
+// would have to be reverted has no Down function.
+var ErrIrreversibleMigration = errors.New("migration has no down migration")
+
 // ErrNewerSchema is returned when the database was created by a newer version
 // of SubNetree than the currently running binary.
 var ErrNewerSchema = fmt.Errorf("database was created by a newer version of SubNetree")
 
-// Compile-time interface guard.
-var _ plugin.Store = (*SQLiteStore)(nil)
+// Store is a plugin.Store with the lifecycle and schema management the
+// server needs. SQLiteStore and PostgresStore implement it.
+type Store interface {
+	plugin.Store
+
+	// Dialect reports the SQL dialect of the underlying database.
+	Dialect() Dialect
+
+	// CheckVersion guards against opening a database created by a newer
+	// binary and records currentVersion.
+	CheckVersion(ctx context.Context, currentVersion string) error
+
+	// Rollback reverts the named plugin's migrations above toVersion.
+	Rollback(ctx context.Context, pluginName string, migrations []plugin.Migration, toVersion int) error
+
+	// Close closes the underlying database connection.
+	Close() error
+}
+
+// Compile-time interface guards.
+var (
+	_ Store = (*SQLiteStore)(nil)
+	_ Store = (*PostgresStore)(nil)
+)
+
+// Open opens the database for the given driver ("sqlite" or "postgres").
+// For SQLite dsn is the database file path; for PostgreSQL it is a
+// connection string accepted by pgx.
+func Open(driver, dsn string) (Store, error) {
+	switch Dialect(driver) {
+	case DialectSQLite, "":
+		return New(dsn)
+	case DialectPostgres:
+		return NewPostgres(dsn)
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", driver)
+	}
+}
+
+// sqlStore holds the connection, transaction, and migration bookkeeping
+// shared by the SQLite and PostgreSQL stores. Its SQL is written for
+// SQLite and passed through the dialect.
+type sqlStore struct {
+	db      *sql.DB
+	dialect Dialect
+	mu      sync.Mutex // Serialize migrations
+	once    sync.Once  // Ensure _migrations table created once
+}
 
 // SQLiteStore implements plugin.Store backed by SQLite via modernc.org/sqlite.
 type SQLiteStore struct {
-	db   *sql.DB
-	mu   sync.Mutex // Serialize migrations
-	once sync.Once  // Ensure _migrations table created once
+	sqlStore
 }
 
 // New opens (or creates) a SQLite database at the given path and applies
@@ -58,17 +108,22 @@ func New(path string) (*SQLiteStore, error) {
 		}
 	}
 
-	return &SQLiteStore{db: db}, nil
+	return &SQLiteStore{sqlStore{db: db, dialect: DialectSQLite}}, nil
+}
+
+// Dialect returns DialectSQLite.
+func (s *SQLiteStore) Dialect() Dialect {
+	return DialectSQLite
 }
 
 // DB returns the underlying *sql.DB for direct queries.
-func (s *SQLiteStore) DB() *sql.DB {
+func (s *sqlStore) DB() *sql.DB {
 	return s.db
 }
 
 // Tx executes fn within a database transaction. The transaction is
 // committed if fn returns nil, rolled back otherwise.
-func (s *SQLiteStore) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+func (s *sqlStore) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin tx: %w", err)
@@ -87,7 +142,7 @@ func (s *SQLiteStore) Tx(ctx context.Context, fn func(tx *sql.Tx) error) error {
 // Migrate runs pending migrations for the named plugin. Already-applied
 // migrations (tracked in the shared _migrations table) are skipped.
 // Migrations must be provided in ascending Version order.
-func (s *SQLiteStore) Migrate(ctx context.Context, pluginName string, migrations []plugin.Migration) error {
+func (s *sqlStore) Migrate(ctx context.Context, pluginName string, migrations []plugin.Migration) error {
 	if err := s.ensureMigrationsTable(ctx); err != nil {
 		return err
 	}
@@ -96,7 +151,7 @@ func (s *SQLiteStore) Migrate(ctx context.Context, pluginName string, migrations
 	defer s.mu.Unlock()
 
 	for _, m := range migrations {
-		applied, err := s.isMigrationApplied(ctx, pluginName, m.Version)
+		applied, err := s.isMigrationApplied(ctx, s.db, pluginName, m.Version)
 		if err != nil {
 			return err
 		}
@@ -112,8 +167,72 @@ func (s *SQLiteStore) Migrate(ctx context.Context, pluginName string, migrations
 	return nil
 }
 
+// Backup writes a consistent snapshot of the database to destPath while it
+// stays open. VACUUM INTO copies the database inside one read transaction,
+// so writes that commit during the backup are simply not in the snapshot
+// and cannot leave it half-written. Other queries wait for the store's
+// single connection until the copy finishes. destPath must not exist.
+func (s *SQLiteStore) Backup(ctx context.Context, destPath string) error {
+	if _, err := os.Stat(destPath); err == nil {
+		return fmt.Errorf("backup destination %q already exists", destPath)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("stat backup destination: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, "VACUUM INTO ?", destPath); err != nil {
+		return fmt.Errorf("vacuum into %q: %w", destPath, err)
+	}
+	return nil
+}
+
+// Rollback reverts the named plugin's applied migrations above toVersion,
+// newest first, each in its own transaction together with removing its
+// _migrations record. migrations is the plugin's full migration list, as
+// passed to Migrate. Every migration to revert is checked for a Down
+// function before any is run, so an Up-only migration in the range fails
+// the rollback with ErrIrreversibleMigration and leaves the schema alone.
+func (s *sqlStore) Rollback(ctx context.Context, pluginName string, migrations []plugin.Migration, toVersion int) error {
+	if toVersion < 0 {
+		return fmt.Errorf("rollback %s: invalid target version %d", pluginName, toVersion)
+	}
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	applied, err := s.appliedVersionsAbove(ctx, pluginName, toVersion)
+	if err != nil {
+		return err
+	}
+
+	byVersion := make(map[int]plugin.Migration, len(migrations))
+	for _, m := range migrations {
+		byVersion[m.Version] = m
+	}
+	plan := make([]plugin.Migration, 0, len(applied))
+	for _, v := range applied {
+		m, ok := byVersion[v]
+		if !ok {
+			return fmt.Errorf("rollback %s: applied migration %d is not in the migration list", pluginName, v)
+		}
+		if m.Down == nil {
+			return fmt.Errorf("rollback %s to version %d: migration %d (%s): %w",
+				pluginName, toVersion, m.Version, m.Description, ErrIrreversibleMigration)
+		}
+		plan = append(plan, m)
+	}
+
+	for _, m := range plan {
+		if err := s.revertMigration(ctx, pluginName, m); err != nil {
+			return fmt.Errorf("rollback %s/%d (%s): %w", pluginName, m.Version, m.Description, err)
+		}
+	}
+	return nil
+}
+
 // Close closes the underlying database connection.
-func (s *SQLiteStore) Close() error {
+func (s *sqlStore) Close() error {
 	return s.db.Close()
 }
 
@@ -121,7 +240,7 @@ func (s *SQLiteStore) Close() error {
 // in the database. It prevents an older binary from opening a database created
 // by a newer version, which could corrupt data. The special version "dev"
 // always passes (both as stored and as current).
-func (s *SQLiteStore) CheckVersion(ctx context.Context, currentVersion string) error {
+func (s *sqlStore) CheckVersion(ctx context.Context, currentVersion string) error {
 	if err := s.ensureSchemaMetaTable(ctx); err != nil {
 		return fmt.Errorf("ensure schema meta table: %w", err)
 	}
@@ -134,7 +253,7 @@ func (s *SQLiteStore) CheckVersion(ctx context.Context, currentVersion string) e
 	if err == sql.ErrNoRows {
 		// First run: record the current version.
 		_, err = s.db.ExecContext(ctx,
-			"INSERT INTO _schema_meta (id, app_version, updated_at) VALUES (1, ?, CURRENT_TIMESTAMP)",
+			s.dialect.Rebind("INSERT INTO _schema_meta (id, app_version, updated_at) VALUES (1, ?, CURRENT_TIMESTAMP)"),
 			currentVersion,
 		)
 		if err != nil {
@@ -149,7 +268,7 @@ func (s *SQLiteStore) CheckVersion(ctx context.Context, currentVersion string) e
 	// "dev" always passes -- useful for local development.
 	if stored == "dev" || currentVersion == "dev" {
 		_, err = s.db.ExecContext(ctx,
-			"UPDATE _schema_meta SET app_version = ?, updated_at = CURRENT_TIMESTAMP WHERE id = 1",
+			s.dialect.Rebind("UPDATE _schema_meta SET app_version = ?, updated_at = CURRENT_TIMESTAMP WHERE id = 1"),
 			currentVersion,
 		)
 		if err != nil {
@@ -168,7 +287,7 @@ func (s *SQLiteStore) CheckVersion(ctx context.Context, currentVersion string) e
 	// Current >= stored: update the stored version.
 	if semver.Compare(cur, sto) > 0 {
 		_, err = s.db.ExecContext(ctx,
-			"UPDATE _schema_meta SET app_version = ?, updated_at = CURRENT_TIMESTAMP WHERE id = 1",
+			s.dialect.Rebind("UPDATE _schema_meta SET app_version = ?, updated_at = CURRENT_TIMESTAMP WHERE id = 1"),
 			currentVersion,
 		)
 		if err != nil {
@@ -180,12 +299,12 @@ func (s *SQLiteStore) CheckVersion(ctx context.Context, currentVersion string) e
 }
 
 // ensureSchemaMetaTable creates the _schema_meta table if it doesn't exist.
-func (s *SQLiteStore) ensureSchemaMetaTable(ctx context.Context) error {
+func (s *sqlStore) ensureSchemaMetaTable(ctx context.Context) error {
 	_, err := s.db.ExecContext(ctx, `
 		CREATE TABLE IF NOT EXISTS _schema_meta (
 			id           INTEGER  PRIMARY KEY CHECK (id = 1),
 			app_version  TEXT     NOT NULL,
-			updated_at   DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+			updated_at   `+s.dialect.TimestampType()+` NOT NULL DEFAULT CURRENT_TIMESTAMP
 		)
 	`)
 	return err
@@ -201,7 +320,7 @@ func normalizeVersion(v string) string {
 
 // ensureMigrationsTable creates the shared _migrations tracking table if it
 // doesn't already exist. Safe to call multiple times (uses sync.Once).
-func (s *SQLiteStore) ensureMigrationsTable(ctx context.Context) error {
+func (s *sqlStore) ensureMigrationsTable(ctx context.Context) error {
 	var err error
 	s.once.Do(func() {
 		_, err = s.db.ExecContext(ctx, `
@@ -209,7 +328,7 @@ func (s *SQLiteStore) ensureMigrationsTable(ctx context.Context) error {
 				plugin_name TEXT    NOT NULL,
 				version     INTEGER NOT NULL,
 				description TEXT    NOT NULL,
-				applied_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+				applied_at  `+s.dialect.TimestampType()+` NOT NULL DEFAULT CURRENT_TIMESTAMP,
 				PRIMARY KEY (plugin_name, version)
 			)
 		`)
@@ -217,10 +336,15 @@ func (s *SQLiteStore) ensureMigrationsTable(ctx context.Context) error {
 	return err
 }
 
-func (s *SQLiteStore) isMigrationApplied(ctx context.Context, pluginName string, version int) (bool, error) {
+// queryRower is satisfied by both *sql.DB and *sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (s *sqlStore) isMigrationApplied(ctx context.Context, q queryRower, pluginName string, version int) (bool, error) {
 	var count int
-	err := s.db.QueryRowContext(ctx,
-		"SELECT COUNT(*) FROM _migrations WHERE plugin_name = ? AND version = ?",
+	err := q.QueryRowContext(ctx,
+		s.dialect.Rebind("SELECT COUNT(*) FROM _migrations WHERE plugin_name = ? AND version = ?"),
 		pluginName, version,
 	).Scan(&count)
 	if err != nil {
@@ -229,14 +353,77 @@ func (s *SQLiteStore) isMigrationApplied(ctx context.Context, pluginName string,
 	return count > 0, nil
 }
 
-func (s *SQLiteStore) applyMigration(ctx context.Context, pluginName string, m plugin.Migration) error {
+// appliedVersionsAbove returns the plugin's applied migration versions
+// greater than version, newest first.
+func (s *sqlStore) appliedVersionsAbove(ctx context.Context, pluginName string, version int) ([]int, error) {
+	rows, err := s.db.QueryContext(ctx,
+		s.dialect.Rebind("SELECT version FROM _migrations WHERE plugin_name = ? AND version > ? ORDER BY version DESC"),
+		pluginName, version,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list migrations for %s: %w", pluginName, err)
+	}
+	defer rows.Close()
+
+	var versions []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			return nil, fmt.Errorf("scan migration version: %w", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions, rows.Err()
+}
+
+// lockMigrations takes the dialect's cross-process migration lock, if any,
+// for the rest of tx.
+func (s *sqlStore) lockMigrations(ctx context.Context, tx *sql.Tx) error {
+	lock := s.dialect.migrationLock()
+	if lock == "" {
+		return nil
+	}
+	if _, err := tx.ExecContext(ctx, lock); err != nil {
+		return fmt.Errorf("lock migrations: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlStore) revertMigration(ctx context.Context, pluginName string, m plugin.Migration) error {
 	return s.Tx(ctx, func(tx *sql.Tx) error {
-		if err := m.Up(tx); err != nil {
+		if err := s.lockMigrations(ctx, tx); err != nil {
+			return err
+		}
+		if err := m.Down(tx); err != nil {
 			return err
 		}
 
 		_, err := tx.ExecContext(ctx,
-			"INSERT INTO _migrations (plugin_name, version, description) VALUES (?, ?, ?)",
+			s.dialect.Rebind("DELETE FROM _migrations WHERE plugin_name = ? AND version = ?"),
+			pluginName, m.Version,
+		)
+		return err
+	})
+}
+
+func (s *sqlStore) applyMigration(ctx context.Context, pluginName string, m plugin.Migration) error {
+	return s.Tx(ctx, func(tx *sql.Tx) error {
+		if err := s.lockMigrations(ctx, tx); err != nil {
+			return err
+		}
+		// Another server sharing the database may have applied the
+		// migration while this one waited for the lock.
+		applied, err := s.isMigrationApplied(ctx, tx, pluginName, m.Version)
+		if err != nil || applied {
+			return err
+		}
+
+		if err := m.Up(tx); err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			s.dialect.Rebind("INSERT INTO _migrations (plugin_name, version, description) VALUES (?, ?, ?)"),
 			pluginName, m.Version, m.Description,
 		)
 		return err
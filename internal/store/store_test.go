@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -420,3 +421,297 @@ func TestCheckVersion_PatchUpgrade(t *testing.T) {
 		t.Fatalf("patch upgrade 0.4.0 -> 0.4.1: %v", err)
 	}
 }
+
+func TestBackup_copies_rows(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+
+	if _, err := s.DB().ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		if _, err := s.DB().ExecContext(ctx, "INSERT INTO test (name) VALUES (?)", name); err != nil {
+			t.Fatalf("insert %s: %v", name, err)
+		}
+	}
+
+	dest := filepath.Join(t.TempDir(), "backup.db")
+	if err := s.Backup(ctx, dest); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+
+	backup, err := New(dest)
+	if err != nil {
+		t.Fatalf("open backup: %v", err)
+	}
+	defer backup.Close()
+
+	var count int
+	if err := backup.DB().QueryRowContext(ctx, "SELECT COUNT(*) FROM test").Scan(&count); err != nil {
+		t.Fatalf("count rows: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("backup has %d rows, want 3", count)
+	}
+}
+
+func TestBackup_during_concurrent_writes(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+
+	if _, err := s.DB().ExecContext(ctx, "CREATE TABLE test (id INTEGER PRIMARY KEY, name TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				done <- nil
+				return
+			default:
+			}
+			if _, err := s.DB().ExecContext(ctx, "INSERT INTO test (name) VALUES (?)", "row"); err != nil {
+				done <- err
+				return
+			}
+		}
+	}()
+
+	dir := t.TempDir()
+	for i := range 3 {
+		dest := filepath.Join(dir, fmt.Sprintf("backup-%d.db", i))
+		if err := s.Backup(ctx, dest); err != nil {
+			t.Fatalf("Backup %d: %v", i, err)
+		}
+
+		backup, err := New(dest)
+		if err != nil {
+			t.Fatalf("open backup %d: %v", i, err)
+		}
+		var result string
+		err = backup.DB().QueryRowContext(ctx, "PRAGMA integrity_check").Scan(&result)
+		backup.Close()
+		if err != nil || result != "ok" {
+			t.Errorf("backup %d integrity_check = %q, %v; want ok", i, result, err)
+		}
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("concurrent insert: %v", err)
+	}
+}
+
+func TestBackup_refuses_existing_destination(t *testing.T) {
+	s := tempDB(t)
+	dest := filepath.Join(t.TempDir(), "exists.db")
+	if err := os.WriteFile(dest, []byte("keep me"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Backup(context.Background(), dest); err == nil {
+		t.Fatal("Backup over an existing file succeeded, want error")
+	}
+	if data, _ := os.ReadFile(dest); string(data) != "keep me" {
+		t.Error("Backup modified the existing destination file")
+	}
+}
+
+// versionedMigrations builds three reversible migrations for the rollback
+// tests: create a table, add a column, and add an index.
+func versionedMigrations() []plugin.Migration {
+	return []plugin.Migration{
+		{
+			Version:     1,
+			Description: "create devices table",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec("CREATE TABLE recon_devices (id INTEGER PRIMARY KEY, name TEXT)")
+				return err
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec("DROP TABLE recon_devices")
+				return err
+			},
+		},
+		{
+			Version:     2,
+			Description: "add ip column",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec("ALTER TABLE recon_devices ADD COLUMN ip TEXT")
+				return err
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec("ALTER TABLE recon_devices DROP COLUMN ip")
+				return err
+			},
+		},
+		{
+			Version:     3,
+			Description: "index ip",
+			Up: func(tx *sql.Tx) error {
+				_, err := tx.Exec("CREATE INDEX idx_recon_devices_ip ON recon_devices(ip)")
+				return err
+			},
+			Down: func(tx *sql.Tx) error {
+				_, err := tx.Exec("DROP INDEX idx_recon_devices_ip")
+				return err
+			},
+		},
+	}
+}
+
+func migrationVersions(t *testing.T, s *SQLiteStore, pluginName string) []int {
+	t.Helper()
+	rows, err := s.DB().Query("SELECT version FROM _migrations WHERE plugin_name = ? ORDER BY version", pluginName)
+	if err != nil {
+		t.Fatalf("query migrations: %v", err)
+	}
+	defer rows.Close()
+	var versions []int
+	for rows.Next() {
+		var v int
+		if err := rows.Scan(&v); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		versions = append(versions, v)
+	}
+	return versions
+}
+
+func schemaObjectExists(t *testing.T, s *SQLiteStore, kind, name string) bool {
+	t.Helper()
+	var count int
+	err := s.DB().QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?", kind, name).Scan(&count)
+	if err != nil {
+		t.Fatalf("query sqlite_master: %v", err)
+	}
+	return count > 0
+}
+
+func TestRollback_to_earlier_version(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+	migrations := versionedMigrations()
+
+	if err := s.Migrate(ctx, "recon", migrations); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := s.Rollback(ctx, "recon", migrations, 1); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+
+	if got := migrationVersions(t, s, "recon"); len(got) != 1 || got[0] != 1 {
+		t.Errorf("applied versions = %v, want [1]", got)
+	}
+	if schemaObjectExists(t, s, "index", "idx_recon_devices_ip") {
+		t.Error("v3 index still exists after rollback to v1")
+	}
+	if _, err := s.DB().ExecContext(ctx, "INSERT INTO recon_devices (id, name) VALUES (1, 'switch1')"); err != nil {
+		t.Errorf("v1 table unusable after rollback: %v", err)
+	}
+	if _, err := s.DB().ExecContext(ctx, "INSERT INTO recon_devices (id, name, ip) VALUES (2, 'x', '10.0.0.1')"); err == nil {
+		t.Error("v2 ip column still exists after rollback to v1")
+	}
+
+	// Migrating again re-applies v2 and v3.
+	if err := s.Migrate(ctx, "recon", migrations); err != nil {
+		t.Fatalf("re-Migrate: %v", err)
+	}
+	if got := migrationVersions(t, s, "recon"); len(got) != 3 {
+		t.Errorf("applied versions after re-migrate = %v, want [1 2 3]", got)
+	}
+}
+
+func TestRollback_to_zero(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+	migrations := versionedMigrations()
+
+	if err := s.Migrate(ctx, "recon", migrations); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := s.Rollback(ctx, "recon", migrations, 0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := migrationVersions(t, s, "recon"); len(got) != 0 {
+		t.Errorf("applied versions = %v, want none", got)
+	}
+	if schemaObjectExists(t, s, "table", "recon_devices") {
+		t.Error("table still exists after rollback to 0")
+	}
+}
+
+func TestRollback_up_only_migration_errors(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+	migrations := versionedMigrations()
+	migrations[1].Down = nil
+
+	if err := s.Migrate(ctx, "recon", migrations); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	err := s.Rollback(ctx, "recon", migrations, 1)
+	if !errors.Is(err, ErrIrreversibleMigration) {
+		t.Fatalf("Rollback error = %v, want ErrIrreversibleMigration", err)
+	}
+	// Nothing was reverted, not even v3 which has a Down.
+	if got := migrationVersions(t, s, "recon"); len(got) != 3 {
+		t.Errorf("applied versions = %v, want [1 2 3]", got)
+	}
+	if !schemaObjectExists(t, s, "index", "idx_recon_devices_ip") {
+		t.Error("v3 index dropped by a rollback that should have been refused")
+	}
+
+	// Rolling back only above the Up-only migration still works.
+	if err := s.Rollback(ctx, "recon", migrations, 2); err != nil {
+		t.Fatalf("Rollback to 2: %v", err)
+	}
+}
+
+func TestRollback_failed_down_keeps_version(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+	migrations := versionedMigrations()
+	migrations[1].Down = func(_ *sql.Tx) error { return errors.New("cannot drop column") }
+
+	if err := s.Migrate(ctx, "recon", migrations); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if err := s.Rollback(ctx, "recon", migrations, 1); err == nil {
+		t.Fatal("Rollback succeeded, want error from Down")
+	}
+	// v3 was reverted in its own transaction; v2's failed Down rolled back.
+	if got := migrationVersions(t, s, "recon"); len(got) != 2 || got[1] != 2 {
+		t.Errorf("applied versions = %v, want [1 2]", got)
+	}
+}
+
+func TestRollback_other_plugins_untouched(t *testing.T) {
+	s := tempDB(t)
+	ctx := context.Background()
+
+	if err := s.Migrate(ctx, "recon", versionedMigrations()); err != nil {
+		t.Fatalf("Migrate recon: %v", err)
+	}
+	pulse := []plugin.Migration{{
+		Version:     1,
+		Description: "create pulse table",
+		Up: func(tx *sql.Tx) error {
+			_, err := tx.Exec("CREATE TABLE pulse_checks (id INTEGER PRIMARY KEY)")
+			return err
+		},
+	}}
+	if err := s.Migrate(ctx, "pulse", pulse); err != nil {
+		t.Fatalf("Migrate pulse: %v", err)
+	}
+
+	if err := s.Rollback(ctx, "recon", versionedMigrations(), 0); err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if got := migrationVersions(t, s, "pulse"); len(got) != 1 {
+		t.Errorf("pulse versions = %v, want [1]", got)
+	}
+}
@@ -0,0 +1,108 @@
+// Package tracing configures OpenTelemetry tracing for SubNetree and
+// provides helpers for instrumenting code with spans.
+//
+// Tracing is off by default: the global tracer provider stays the OTel
+// no-op, so instrumented code pays almost nothing until an exporter is
+// configured.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies SubNetree's tracer.
+const instrumentationName = "github.com/HerbHall/subnetree"
+
+// Supported values for Config.Exporter.
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// Config holds the tracing configuration.
+type Config struct {
+	Exporter    string  `mapstructure:"exporter"`     // "none" (default) or "otlp"
+	Endpoint    string  `mapstructure:"endpoint"`     // OTLP/HTTP collector host:port; empty uses OTEL_EXPORTER_OTLP_ENDPOINT or localhost:4318
+	Insecure    bool    `mapstructure:"insecure"`     // Send to the collector over plain HTTP
+	SampleRatio float64 `mapstructure:"sample_ratio"` // Fraction of new traces recorded, 0-1
+}
+
+// Setup installs the global W3C trace-context propagator and, when an
+// exporter is configured, a tracer provider that exports to it. The
+// returned function flushes buffered spans and stops the exporter; it is
+// safe to call when tracing is disabled.
+func Setup(ctx context.Context, cfg Config, serviceVersion string) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+
+	opts := []otlptracehttp.Option{}
+	if cfg.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("create otlp exporter: %w", err)
+	}
+
+	tp := NewProvider(sdktrace.NewBatchSpanProcessor(exporter), cfg.SampleRatio, serviceVersion)
+	otel.SetTracerProvider(tp)
+	return tp.Shutdown, nil
+}
+
+// NewProvider creates a tracer provider that sends spans to processor.
+// New traces are sampled at ratio; spans with a remote parent follow the
+// parent's sampling decision.
+func NewProvider(processor sdktrace.SpanProcessor, ratio float64, serviceVersion string) *sdktrace.TracerProvider {
+	res := resource.NewSchemaless(
+		semconv.ServiceName("subnetree"),
+		semconv.ServiceVersion(serviceVersion),
+	)
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(res),
+	)
+}
+
+// Tracer returns SubNetree's tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End marks span as failed when err is non-nil, then ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -204,43 +204,52 @@ func (m *Module) CredentialsForDevice(ctx context.Context, deviceID string) ([]r
 // DecryptCredentialData decrypts and returns the credential data for the given ID.
 // Returns an error if the vault is sealed or the credential doesn't exist.
 func (m *Module) DecryptCredentialData(ctx context.Context, id string) (map[string]any, error) {
+	_, data, err := m.DecryptCredentialWithType(ctx, id)
+	return data, err
+}
+
+// DecryptCredentialWithType decrypts the credential data for the given ID and
+// returns it with the credential's type. The type is stored on the record,
+// not in the encrypted data, so consumers that interpret the data by type
+// need it returned alongside.
+func (m *Module) DecryptCredentialWithType(ctx context.Context, id string) (string, map[string]any, error) {
 	if m.store == nil {
-		return nil, fmt.Errorf("vault store not available")
+		return "", nil, fmt.Errorf("vault store not available")
 	}
 	if m.km.IsSealed() {
-		return nil, fmt.Errorf("vault is sealed")
+		return "", nil, fmt.Errorf("vault is sealed")
 	}
 
 	rec, err := m.store.GetCredential(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("get credential: %w", err)
+		return "", nil, fmt.Errorf("get credential: %w", err)
 	}
 	if rec == nil {
-		return nil, fmt.Errorf("credential not found: %s", id)
+		return "", nil, fmt.Errorf("credential not found: %s", id)
 	}
 
 	key, err := m.store.GetKey(ctx, id)
 	if err != nil || key == nil {
-		return nil, fmt.Errorf("encryption key not found for credential %s", id)
+		return "", nil, fmt.Errorf("encryption key not found for credential %s", id)
 	}
 
 	dek, err := m.km.UnwrapDEK(key.WrappedKey)
 	if err != nil {
-		return nil, fmt.Errorf("unwrap DEK: %w", err)
+		return "", nil, fmt.Errorf("unwrap DEK: %w", err)
 	}
 	defer ZeroBytes(dek)
 
 	plaintext, err := Decrypt(dek, rec.EncryptedData)
 	if err != nil {
-		return nil, fmt.Errorf("decrypt credential: %w", err)
+		return "", nil, fmt.Errorf("decrypt credential: %w", err)
 	}
 
 	var data map[string]any
 	if err := json.Unmarshal(plaintext, &data); err != nil {
-		return nil, fmt.Errorf("unmarshal credential data: %w", err)
+		return "", nil, fmt.Errorf("unmarshal credential data: %w", err)
 	}
 
-	return data, nil
+	return rec.Type, data, nil
 }
 
 // tryUnseal attempts to unseal the vault using env var or interactive prompt.
@@ -349,6 +349,30 @@ func TestCredentialProvider_CredentialsForDevice(t *testing.T) {
 	}
 }
 
+func TestDecryptCredentialWithType(t *testing.T) {
+	m := newTestModule(t)
+	insertTestCredential(t, m, "cred-pve", "Proxmox", CredTypeHTTPBasic, "",
+		map[string]any{"username": "subnetree@pve!inventory", "password": "s3cret"})
+
+	credType, data, err := m.DecryptCredentialWithType(context.Background(), "cred-pve")
+	if err != nil {
+		t.Fatalf("DecryptCredentialWithType() error = %v", err)
+	}
+	if credType != CredTypeHTTPBasic {
+		t.Errorf("type = %q, want %q", credType, CredTypeHTTPBasic)
+	}
+	if data["password"] != "s3cret" {
+		t.Errorf("password = %v, want s3cret", data["password"])
+	}
+	if _, ok := data["type"]; ok {
+		t.Error("type should come from the record, not the encrypted data")
+	}
+
+	if _, _, err := m.DecryptCredentialWithType(context.Background(), "nonexistent"); err == nil {
+		t.Error("expected error for nonexistent credential")
+	}
+}
+
 func TestCredentialProvider_NilStore(t *testing.T) {
 	m := New()
 	m.readPassphrase = func() (string, error) { return "", fmt.Errorf("no terminal") }
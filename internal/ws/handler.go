@@ -3,22 +3,28 @@ package ws
 import (
 	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/HerbHall/subnetree/internal/auth"
 	"github.com/HerbHall/subnetree/internal/recon"
 	"github.com/HerbHall/subnetree/pkg/models"
 	"github.com/HerbHall/subnetree/pkg/plugin"
-	"go.uber.org/zap"
 	"github.com/coder/websocket"
+	"go.uber.org/zap"
 )
 
-// Handler provides WebSocket endpoints for real-time scan updates.
+// Handler provides WebSocket endpoints for real-time scan updates and
+// device/alert status.
 type Handler struct {
-	hub    *Hub
-	tokens *auth.TokenService
-	bus    plugin.EventBus
-	logger *zap.Logger
+	hub       *Hub
+	statusHub *Hub
+	tokens    *auth.TokenService
+	bus       plugin.EventBus
+	logger    *zap.Logger
+
+	statusMu     sync.Mutex
+	deviceStatus map[string]string // device ID -> last broadcast status
 }
 
 // Compile-time check that Handler implements the server interface.
@@ -29,22 +35,38 @@ var _ interface {
 // NewHandler creates a WebSocket handler and subscribes to scan events.
 func NewHandler(tokens *auth.TokenService, bus plugin.EventBus, logger *zap.Logger) *Handler {
 	h := &Handler{
-		hub:    NewHub(logger),
-		tokens: tokens,
-		bus:    bus,
-		logger: logger,
+		hub:          NewHub(logger),
+		statusHub:    NewHub(logger),
+		tokens:       tokens,
+		bus:          bus,
+		logger:       logger,
+		deviceStatus: make(map[string]string),
 	}
 	h.subscribeToEvents()
+	h.subscribeToStatusEvents()
 	return h
 }
 
 // RegisterRoutes registers WebSocket routes on the server mux.
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /api/v1/ws/scan", h.handleScanStream)
+	mux.HandleFunc("GET /api/v1/ws/status", h.handleStatusStream)
 }
 
 // handleScanStream upgrades the connection to WebSocket and streams scan events.
 func (h *Handler) handleScanStream(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.hub)
+}
+
+// handleStatusStream upgrades the connection to WebSocket and streams device
+// online/offline transitions and pulse alerts.
+func (h *Handler) handleStatusStream(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, h.statusHub)
+}
+
+// serve authenticates the request, upgrades it to WebSocket, and relays
+// messages from hub until the client disconnects.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request, hub *Hub) {
 	// Validate JWT from query parameter (browser WS API doesn't support headers).
 	token := r.URL.Query().Get("token")
 	if token == "" {
@@ -75,7 +97,7 @@ func (h *Handler) handleScanStream(w http.ResponseWriter, r *http.Request) {
 		logger: h.logger,
 	}
 
-	h.hub.Register(client)
+	hub.Register(client)
 
 	// Run read and write pumps. When either exits, clean up.
 	ctx := r.Context()
@@ -89,7 +111,7 @@ func (h *Handler) handleScanStream(w http.ResponseWriter, r *http.Request) {
 	client.readPump(ctx)
 
 	// Client disconnected -- stop write pump and unregister.
-	h.hub.Unregister(client)
+	hub.Unregister(client)
 	conn.Close(websocket.StatusNormalClosure, "")
 	<-done
 }
@@ -127,8 +149,12 @@ func (h *Handler) subscribeToEvents() {
 			ScanID:    progress.ScanID,
 			Timestamp: event.Timestamp,
 			Data: ScanProgressData{
-				HostsAlive: progress.HostsAlive,
-				SubnetSize: progress.SubnetSize,
+				HostsAlive:  progress.HostsAlive,
+				SubnetSize:  progress.SubnetSize,
+				ProbesDone:  progress.ProbesDone,
+				ProbesTotal: progress.ProbesTotal,
+				RatePPS:     progress.RatePPS,
+				ETASeconds:  progress.ETASeconds,
 			},
 		})
 	})
@@ -180,6 +206,23 @@ func (h *Handler) subscribeToEvents() {
 		})
 	})
 
+	h.bus.Subscribe(recon.TopicScanCancelled, func(_ context.Context, event plugin.Event) {
+		scan, ok := event.Payload.(*models.ScanResult)
+		if !ok {
+			return
+		}
+		h.hub.Broadcast(Message{
+			Type:      MessageScanCancelled,
+			ScanID:    scan.ID,
+			Timestamp: event.Timestamp,
+			Data: ScanCompletedData{
+				Total:   scan.Total,
+				Online:  scan.Online,
+				EndedAt: scan.EndedAt,
+			},
+		})
+	})
+
 	h.logger.Info("subscribed to recon scan events for WebSocket broadcasting")
 }
 
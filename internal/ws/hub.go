@@ -5,9 +5,9 @@ import (
 	"sync"
 	"time"
 
-	"go.uber.org/zap"
 	"github.com/coder/websocket"
 	"github.com/coder/websocket/wsjson"
+	"go.uber.org/zap"
 )
 
 // Client represents a connected WebSocket client.
@@ -20,9 +20,9 @@ type Client struct {
 
 // Hub manages active WebSocket connections and broadcasts messages.
 type Hub struct {
-	mu         sync.RWMutex
-	clients    map[*Client]struct{}
-	logger     *zap.Logger
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+	logger  *zap.Logger
 }
 
 // NewHub creates a new WebSocket hub.
@@ -67,6 +67,31 @@ func (h *Hub) Broadcast(msg Message) {
 	}
 }
 
+// BroadcastOrEvict sends a message to all connected clients and disconnects
+// any client whose send buffer is full. Used for streams where a client
+// that silently misses messages would show stale state.
+func (h *Hub) BroadcastOrEvict(msg Message) {
+	var slow []*Client
+	h.mu.RLock()
+	for c := range h.clients {
+		select {
+		case c.send <- msg:
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, c := range slow {
+		h.logger.Warn("client send buffer full, disconnecting slow client",
+			zap.String("user_id", c.userID))
+		h.Unregister(c)
+		if c.conn != nil {
+			_ = c.conn.CloseNow()
+		}
+	}
+}
+
 // ClientCount returns the number of connected clients.
 func (h *Hub) ClientCount() int {
 	h.mu.RLock()
@@ -432,3 +432,30 @@ func TestUnregisterTwice(t *testing.T) {
 		t.Errorf("ClientCount() = %d, want 0", hub.ClientCount())
 	}
 }
+
+// TestBroadcastOrEvict verifies that a client with a full send buffer is
+// disconnected instead of silently missing messages.
+func TestBroadcastOrEvict(t *testing.T) {
+	hub := NewHub(testLogger())
+
+	slow := &Client{userID: "slow", send: make(chan Message, 1), logger: testLogger()}
+	fast := newTestClient("fast")
+	hub.Register(slow)
+	hub.Register(fast)
+
+	hub.BroadcastOrEvict(Message{Type: MessageDeviceStatus})
+	hub.BroadcastOrEvict(Message{Type: MessageDeviceStatus})
+
+	if hub.ClientCount() != 1 {
+		t.Fatalf("ClientCount() = %d, want 1 after evicting slow client", hub.ClientCount())
+	}
+	if len(fast.send) != 2 {
+		t.Errorf("fast client received %d messages, want 2", len(fast.send))
+	}
+
+	// The evicted client's channel is closed after the buffered message.
+	<-slow.send
+	if _, ok := <-slow.send; ok {
+		t.Error("slow client send channel still open")
+	}
+}
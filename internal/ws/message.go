@@ -3,6 +3,7 @@ package ws
 import (
 	"time"
 
+	"github.com/HerbHall/subnetree/internal/pulse"
 	"github.com/HerbHall/subnetree/pkg/models"
 )
 
@@ -14,13 +15,20 @@ const (
 	MessageScanProgress    MessageType = "scan.progress"
 	MessageScanDeviceFound MessageType = "scan.device_found"
 	MessageScanCompleted   MessageType = "scan.completed"
+	MessageScanCancelled   MessageType = "scan.cancelled"
 	MessageScanError       MessageType = "scan.error"
+
+	MessageDeviceStatus    MessageType = "device.status"
+	MessageAlertTriggered  MessageType = "alert.triggered"
+	MessageAlertResolved   MessageType = "alert.resolved"
+	MessageAlertEscalated  MessageType = "alert.escalated"
+	MessageAlertSuppressed MessageType = "alert.suppressed"
 )
 
 // Message is the envelope for all WebSocket messages.
 type Message struct {
 	Type      MessageType `json:"type"`
-	ScanID    string      `json:"scan_id"`
+	ScanID    string      `json:"scan_id,omitempty"`
 	Timestamp time.Time   `json:"timestamp"`
 	Data      any         `json:"data"`
 }
@@ -33,8 +41,12 @@ type ScanStartedData struct {
 
 // ScanProgressData is the payload for scan.progress messages.
 type ScanProgressData struct {
-	HostsAlive int `json:"hosts_alive"`
-	SubnetSize int `json:"subnet_size"`
+	HostsAlive  int     `json:"hosts_alive"`
+	SubnetSize  int     `json:"subnet_size"`
+	ProbesDone  int     `json:"probes_done"`
+	ProbesTotal int     `json:"probes_total"`
+	RatePPS     float64 `json:"rate_pps"`
+	ETASeconds  float64 `json:"eta_seconds"`
 }
 
 // ScanDeviceFoundData is the payload for scan.device_found messages.
@@ -42,7 +54,9 @@ type ScanDeviceFoundData struct {
 	Device *models.Device `json:"device"`
 }
 
-// ScanCompletedData is the payload for scan.completed messages.
+// ScanCompletedData is the payload for scan.completed and scan.cancelled
+// messages. For a cancelled scan the counts cover hosts found before it
+// stopped.
 type ScanCompletedData struct {
 	Total   int    `json:"total"`
 	Online  int    `json:"online"`
@@ -53,3 +67,18 @@ type ScanCompletedData struct {
 type ScanErrorData struct {
 	Error string `json:"error"`
 }
+
+// DeviceStatusData is the payload for device.status messages, sent when a
+// device's online/offline status changes.
+type DeviceStatusData struct {
+	DeviceID       string `json:"device_id"`
+	Hostname       string `json:"hostname,omitempty"`
+	IP             string `json:"ip,omitempty"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+}
+
+// AlertData is the payload for alert.* messages.
+type AlertData struct {
+	Alert *pulse.Alert `json:"alert"`
+}
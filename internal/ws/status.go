@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"context"
+
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"github.com/HerbHall/subnetree/internal/recon"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+)
+
+// subscribeToStatusEvents forwards device status transitions and pulse
+// alerts to clients of the status stream. Slow clients are disconnected
+// rather than allowed to block or fall behind.
+func (h *Handler) subscribeToStatusEvents() {
+	if h.bus == nil {
+		return
+	}
+
+	deviceHandler := func(_ context.Context, event plugin.Event) {
+		devEvent, ok := event.Payload.(*recon.DeviceEvent)
+		if !ok || devEvent.Device == nil {
+			return
+		}
+		d := devEvent.Device
+		ip := ""
+		if len(d.IPAddresses) > 0 {
+			ip = d.IPAddresses[0]
+		}
+		h.broadcastDeviceStatus(event, d.ID, d.Hostname, ip, string(d.Status))
+	}
+	h.bus.Subscribe(recon.TopicDeviceDiscovered, deviceHandler)
+	h.bus.Subscribe(recon.TopicDeviceUpdated, deviceHandler)
+
+	h.bus.Subscribe(recon.TopicDeviceLost, func(_ context.Context, event plugin.Event) {
+		lost, ok := event.Payload.(recon.DeviceLostEvent)
+		if !ok {
+			return
+		}
+		h.broadcastDeviceStatus(event, lost.DeviceID, "", lost.IP, string(models.DeviceStatusOffline))
+	})
+
+	alertTopics := map[string]MessageType{
+		pulse.TopicAlertTriggered:  MessageAlertTriggered,
+		pulse.TopicAlertResolved:   MessageAlertResolved,
+		pulse.TopicAlertEscalated:  MessageAlertEscalated,
+		pulse.TopicAlertSuppressed: MessageAlertSuppressed,
+	}
+	for topic, msgType := range alertTopics {
+		h.bus.Subscribe(topic, func(_ context.Context, event plugin.Event) {
+			alert, ok := event.Payload.(*pulse.Alert)
+			if !ok {
+				return
+			}
+			h.statusHub.BroadcastOrEvict(Message{
+				Type:      msgType,
+				Timestamp: event.Timestamp,
+				Data:      AlertData{Alert: alert},
+			})
+		})
+	}
+}
+
+// broadcastDeviceStatus sends a device.status message if status differs
+// from the last one sent for the device.
+func (h *Handler) broadcastDeviceStatus(event plugin.Event, deviceID, hostname, ip, status string) {
+	if deviceID == "" || status == "" {
+		return
+	}
+	h.statusMu.Lock()
+	previous := h.deviceStatus[deviceID]
+	if previous == status {
+		h.statusMu.Unlock()
+		return
+	}
+	h.deviceStatus[deviceID] = status
+	h.statusMu.Unlock()
+
+	h.statusHub.BroadcastOrEvict(Message{
+		Type:      MessageDeviceStatus,
+		Timestamp: event.Timestamp,
+		Data: DeviceStatusData{
+			DeviceID:       deviceID,
+			Hostname:       hostname,
+			IP:             ip,
+			Status:         status,
+			PreviousStatus: previous,
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HerbHall/subnetree/internal/auth"
+	"github.com/HerbHall/subnetree/internal/event"
+	"github.com/HerbHall/subnetree/internal/pulse"
+	"github.com/HerbHall/subnetree/internal/recon"
+	"github.com/HerbHall/subnetree/pkg/models"
+	"github.com/HerbHall/subnetree/pkg/plugin"
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+)
+
+// dialStatusStream starts a server for h and connects to the status stream.
+func dialStatusStream(t *testing.T, h *Handler, tokens *auth.TokenService) *websocket.Conn {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	h.RegisterRoutes(mux)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	token, err := tokens.IssueAccessToken(&auth.User{ID: "user-1", Username: "admin", Role: "admin"})
+	if err != nil {
+		t.Fatalf("IssueAccessToken: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/v1/ws/status?token=" + token
+	conn, _, err := websocket.Dial(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.CloseNow() })
+
+	// Wait for the server side to register the client.
+	deadline := time.Now().Add(2 * time.Second)
+	for h.statusHub.ClientCount() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("status client was not registered")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return conn
+}
+
+// readMessage reads one JSON message from conn.
+func readMessage(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var msg map[string]any
+	if err := wsjson.Read(ctx, conn, &msg); err != nil {
+		t.Fatalf("read message: %v", err)
+	}
+	return msg
+}
+
+func TestStatusStream_AlertReachesClient(t *testing.T) {
+	tokens := auth.NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
+	bus := event.NewBus(testLogger())
+	h := NewHandler(tokens, bus, testLogger())
+	conn := dialStatusStream(t, h, tokens)
+
+	_ = bus.Publish(context.Background(), plugin.Event{
+		Topic:     pulse.TopicAlertTriggered,
+		Source:    "pulse",
+		Timestamp: time.Now(),
+		Payload:   &pulse.Alert{ID: "alert-1", CheckID: "check-1", DeviceID: "dev-1", Severity: "warning"},
+	})
+
+	msg := readMessage(t, conn)
+	if msg["type"] != string(MessageAlertTriggered) {
+		t.Fatalf("type = %v, want %q", msg["type"], MessageAlertTriggered)
+	}
+	data, _ := msg["data"].(map[string]any)
+	alert, _ := data["alert"].(map[string]any)
+	if alert["id"] != "alert-1" {
+		t.Errorf("alert id = %v, want alert-1", alert["id"])
+	}
+}
+
+func TestStatusStream_DeviceTransitionsOnly(t *testing.T) {
+	tokens := auth.NewTokenService([]byte("test-secret-key-32bytes-long!!"), 15*time.Minute, 7*24*time.Hour)
+	bus := event.NewBus(testLogger())
+	h := NewHandler(tokens, bus, testLogger())
+	conn := dialStatusStream(t, h, tokens)
+
+	ctx := context.Background()
+	online := &models.Device{ID: "dev-1", Hostname: "nas", IPAddresses: []string{"10.0.0.5"}, Status: models.DeviceStatusOnline}
+	_ = bus.Publish(ctx, plugin.Event{Topic: recon.TopicDeviceDiscovered, Payload: &recon.DeviceEvent{Device: online}})
+	// Unchanged status is not re-sent.
+	_ = bus.Publish(ctx, plugin.Event{Topic: recon.TopicDeviceUpdated, Payload: &recon.DeviceEvent{Device: online}})
+	_ = bus.Publish(ctx, plugin.Event{Topic: recon.TopicDeviceLost, Payload: recon.DeviceLostEvent{DeviceID: "dev-1", IP: "10.0.0.5"}})
+
+	first := readMessage(t, conn)
+	second := readMessage(t, conn)
+	for i, want := range []string{"online", "offline"} {
+		msg := []map[string]any{first, second}[i]
+		if msg["type"] != string(MessageDeviceStatus) {
+			t.Fatalf("message %d type = %v, want %q", i, msg["type"], MessageDeviceStatus)
+		}
+		data, _ := msg["data"].(map[string]any)
+		if data["status"] != want {
+			t.Errorf("message %d status = %v, want %q", i, data["status"], want)
+		}
+	}
+}
@@ -21,22 +21,32 @@ type Anomaly struct {
 
 // Forecast represents a capacity forecast for a device metric.
 type Forecast struct {
-	DeviceID        string         `json:"device_id"`
-	MetricName      string         `json:"metric_name"`
-	CurrentValue    float64        `json:"current_value"`
-	PredictedValue  float64        `json:"predicted_value"`
-	TimeToThreshold *time.Duration `json:"time_to_threshold,omitempty" swaggertype:"integer"`
-	Threshold       float64        `json:"threshold"`
-	Confidence      float64        `json:"confidence"` // 0.0-1.0
-	Slope           float64        `json:"slope"`      // Rate of change per hour
-	GeneratedAt     time.Time      `json:"generated_at"`
+	DeviceID        string          `json:"device_id"`
+	MetricName      string          `json:"metric_name"`
+	CurrentValue    float64         `json:"current_value"`
+	PredictedValue  float64         `json:"predicted_value"`
+	TimeToThreshold *time.Duration  `json:"time_to_threshold,omitempty" swaggertype:"integer"`
+	Threshold       float64         `json:"threshold"`
+	Confidence      float64         `json:"confidence"` // 0.0-1.0
+	Slope           float64         `json:"slope"`      // Rate of change per hour
+	Algorithm       string          `json:"algorithm"`  // "linear", "holt_winters"
+	Points          []ForecastPoint `json:"points,omitempty"`
+	GeneratedAt     time.Time       `json:"generated_at"`
+}
+
+// ForecastPoint is a predicted value with its confidence bounds.
+type ForecastPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
+	Lower     float64   `json:"lower"`
+	Upper     float64   `json:"upper"`
 }
 
 // Baseline represents a learned baseline for a device metric.
 type Baseline struct {
 	DeviceID   string    `json:"device_id"`
 	MetricName string    `json:"metric_name"`
-	Algorithm  string    `json:"algorithm"` // "ewma", "holt_winters"
+	Algorithm  string    `json:"algorithm"` // "ewma", "holt_winters", "seasonal"
 	Mean       float64   `json:"mean"`
 	StdDev     float64   `json:"std_dev"`
 	Samples    int       `json:"samples"`
@@ -46,17 +56,21 @@ type Baseline struct {
 
 // AlertGroup represents a group of correlated alerts.
 type AlertGroup struct {
-	ID          string    `json:"id"`
-	RootCause   string    `json:"root_cause,omitempty"` // Device ID identified as root
-	DeviceIDs   []string  `json:"device_ids"`
-	AlertCount  int       `json:"alert_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	Description string    `json:"description"`
+	ID            string    `json:"id"`
+	RootCause     string    `json:"root_cause,omitempty"`      // Device ID identified as root
+	RootCauseHint string    `json:"root_cause_hint,omitempty"` // How the topology pass chose it: "alerting_ancestor", "highest_layer", "common_parent"
+	DeviceIDs     []string  `json:"device_ids"`
+	AlertCount    int       `json:"alert_count"`
+	CreatedAt     time.Time `json:"created_at"`
+	Description   string    `json:"description"`
 }
 
 // NLQueryRequest is the request body for POST /analytics/query.
+// SessionID is optional; queries sharing a session ID see the preceding
+// questions and answers, so follow-ups like "and on that device?" work.
 type NLQueryRequest struct {
-	Query string `json:"query"`
+	Query     string `json:"query"`
+	SessionID string `json:"session_id,omitempty"`
 }
 
 // NLQueryResponse is the response for POST /analytics/query.
@@ -0,0 +1,90 @@
+// Package httperr writes RFC 7807 Problem Details error responses with a
+// consistent shape across modules.
+// This package is Apache 2.0 licensed, part of the public plugin SDK.
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// typeBase prefixes every problem type URI.
+const typeBase = "https://subnetree.com/problems/"
+
+// Problem is an RFC 7807 Problem Details response. Code is an optional
+// machine-readable error code clients can branch on instead of parsing
+// Detail.
+type Problem struct {
+	Type     string `json:"type" example:"https://subnetree.com/problems/bad-request"`
+	Title    string `json:"title" example:"Bad Request"`
+	Status   int    `json:"status" example:"400"`
+	Detail   string `json:"detail,omitempty" example:"invalid subnet CIDR"`
+	Instance string `json:"instance,omitempty" example:"/api/v1/recon/scan"`
+	Code     string `json:"code,omitempty" example:"invalid_subnet"`
+}
+
+// Option sets an optional field on a Problem.
+type Option func(*Problem)
+
+// WithCode sets the machine-readable error code.
+func WithCode(code string) Option {
+	return func(p *Problem) { p.Code = code }
+}
+
+// WithInstance sets the URI of the request that failed.
+func WithInstance(instance string) Option {
+	return func(p *Problem) { p.Instance = instance }
+}
+
+// New builds the Problem for status. Type and Title are derived from
+// status so that every module reports the same error the same way.
+func New(status int, detail string, opts ...Option) Problem {
+	p := Problem{
+		Type:   TypeFor(status),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+	for _, opt := range opts {
+		opt(&p)
+	}
+	return p
+}
+
+// WriteProblem writes a problem response with the given status and detail.
+func WriteProblem(w http.ResponseWriter, status int, detail string, opts ...Option) {
+	Write(w, New(status, detail, opts...))
+}
+
+// Write writes p as a problem response.
+func Write(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// typeSlugs overrides the derived slug where the server already uses a
+// different one.
+var typeSlugs = map[int]string{
+	http.StatusInternalServerError:   "internal-error",
+	http.StatusTooManyRequests:       "rate-limited",
+	http.StatusRequestEntityTooLarge: "payload-too-large",
+}
+
+// TypeFor returns the problem type URI for status, such as
+// https://subnetree.com/problems/not-found for 404.
+func TypeFor(status int) string {
+	if slug, ok := typeSlugs[status]; ok {
+		return typeBase + slug
+	}
+	text := http.StatusText(status)
+	if text == "" {
+		return "about:blank"
+	}
+	slug := strings.ToLower(strings.NewReplacer(" ", "-", "'", "").Replace(text))
+	return typeBase + slug
+}
@@ -0,0 +1,107 @@
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteProblem(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		opts   []Option
+		want   Problem
+	}{
+		{
+			name:   "bad request",
+			status: http.StatusBadRequest,
+			want: Problem{
+				Type:   "https://subnetree.com/problems/bad-request",
+				Title:  "Bad Request",
+				Status: http.StatusBadRequest,
+				Detail: "something went wrong",
+			},
+		},
+		{
+			name:   "code and instance",
+			status: http.StatusNotFound,
+			opts:   []Option{WithCode("device_not_found"), WithInstance("/api/v1/recon/devices/abc")},
+			want: Problem{
+				Type:     "https://subnetree.com/problems/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "something went wrong",
+				Instance: "/api/v1/recon/devices/abc",
+				Code:     "device_not_found",
+			},
+		},
+		{
+			name:   "server error",
+			status: http.StatusInternalServerError,
+			want: Problem{
+				Type:   "https://subnetree.com/problems/internal-error",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+				Detail: "something went wrong",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			WriteProblem(w, tt.status, "something went wrong", tt.opts...)
+
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("Content-Type = %q, want application/problem+json", ct)
+			}
+			var got Problem
+			if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("problem = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteProblem_OmitsEmptyOptionalFields(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteProblem(w, http.StatusConflict, "")
+
+	var fields map[string]any
+	if err := json.NewDecoder(w.Body).Decode(&fields); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	for _, key := range []string{"detail", "instance", "code"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("field %q present, want omitted", key)
+		}
+	}
+	for _, key := range []string{"type", "title", "status"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("field %q missing", key)
+		}
+	}
+}
+
+func TestTypeFor(t *testing.T) {
+	tests := map[int]string{
+		http.StatusBadRequest:          "https://subnetree.com/problems/bad-request",
+		http.StatusServiceUnavailable:  "https://subnetree.com/problems/service-unavailable",
+		http.StatusTooManyRequests:     "https://subnetree.com/problems/rate-limited",
+		http.StatusUnprocessableEntity: "https://subnetree.com/problems/unprocessable-entity",
+		599:                            "about:blank",
+	}
+	for status, want := range tests {
+		if got := TypeFor(status); got != want {
+			t.Errorf("TypeFor(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
@@ -50,6 +50,8 @@ const (
 	DiscoveryWiFi    DiscoveryMethod = "wifi"
 	DiscoveryProxmox   DiscoveryMethod = "proxmox"
 	DiscoveryTailscale DiscoveryMethod = "tailscale"
+	DiscoveryDocker    DiscoveryMethod = "docker"
+	DiscoveryKubernetes DiscoveryMethod = "kubernetes"
 )
 
 // Device represents a network device tracked by SubNetree.
@@ -69,7 +71,9 @@ type Device struct {
 	Notes           string            `json:"notes,omitempty" example:"Production web server"`
 	Tags            []string          `json:"tags,omitempty"`
 	CustomFields    map[string]string `json:"custom_fields,omitempty"`
-	Location        string            `json:"location,omitempty" example:"Rack A3, U12"`
+	Location        string            `json:"location,omitempty" example:"DC1 Room 2"`
+	Rack            string            `json:"rack,omitempty" example:"A3"`
+	RackUnit        int               `json:"rack_unit,omitempty" example:"12"`
 	Category        string            `json:"category,omitempty" example:"production"`
 	PrimaryRole     string            `json:"primary_role,omitempty" example:"web-server"`
 	Owner           string            `json:"owner,omitempty" example:"platform-team"`
@@ -83,6 +87,10 @@ type Device struct {
 	ParentDeviceID string `json:"parent_device_id,omitempty"`
 	NetworkLayer   int    `json:"network_layer,omitempty" example:"4"` // 0=unknown, 1=gateway, 2=distribution, 3=access, 4=endpoint
 	ConnectionType string `json:"connection_type,omitempty" example:"wifi"` // "wired", "wifi", "unknown"
+
+	// ArchivedAt is set when the device has been deleted (archived) but its
+	// record and history are kept so it can be restored.
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
 }
 
 // ConnectionType indicates how a device connects to the network.
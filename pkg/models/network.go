@@ -30,6 +30,13 @@ type ScanResult struct {
 	Devices   []Device `json:"devices,omitempty"`
 	Total     int      `json:"total" example:"12"`
 	Online    int      `json:"online" example:"8"`
+
+	// Probe progress. Set while a scan is running and on the completed
+	// scan event; not stored.
+	ProbesDone  int     `json:"probes_done,omitempty" example:"128"`
+	ProbesTotal int     `json:"probes_total,omitempty" example:"254"`
+	RatePPS     float64 `json:"rate_pps,omitempty" example:"42.5"`
+	ETASeconds  float64 `json:"eta_seconds,omitempty" example:"3"`
 }
 
 // ScanMetrics holds detailed timing and performance data for a scan.
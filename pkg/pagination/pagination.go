@@ -0,0 +1,80 @@
+// Package pagination writes paginated list responses with a consistent
+// total-count contract across modules.
+// This package is Apache 2.0 licensed, part of the public plugin SDK.
+package pagination
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// TotalCountHeader carries the number of items matching a list request
+// before limit and offset are applied.
+const TotalCountHeader = "X-Total-Count"
+
+// EnvelopeParam is the query parameter that asks for a Page body instead
+// of a bare array. The bare array stays the default for compatibility.
+const EnvelopeParam = "envelope"
+
+// Page is the wrapped body of a paginated list response.
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Total  int `json:"total" example:"120"`
+	Limit  int `json:"limit" example:"50"`
+	Offset int `json:"offset" example:"0"`
+	// NextOffset is the offset of the following page; it is omitted on the
+	// last page.
+	NextOffset *int `json:"next_offset,omitempty" example:"50"`
+}
+
+// NewPage builds the Page for items taken from a result of total items
+// at the given limit and offset.
+func NewPage[T any](items []T, total, limit, offset int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	p := Page[T]{Items: items, Total: total, Limit: limit, Offset: offset}
+	if next := offset + len(items); len(items) > 0 && next < total {
+		p.NextOffset = &next
+	}
+	return p
+}
+
+// Wrapped reports whether r asked for the Page envelope with
+// ?envelope=true.
+func Wrapped(r *http.Request) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(EnvelopeParam))
+	return v
+}
+
+// Write writes a 200 list response. X-Total-Count is always set; the body
+// is a Page when the request asked for the envelope and the bare items
+// array otherwise.
+func Write[T any](w http.ResponseWriter, r *http.Request, items []T, total, limit, offset int) {
+	page := NewPage(items, total, limit, offset)
+
+	w.Header().Set(TotalCountHeader, strconv.Itoa(total))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if Wrapped(r) {
+		_ = json.NewEncoder(w).Encode(page)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(page.Items)
+}
+
+// Slice returns the window of items selected by limit and offset, for
+// lists that are paginated in memory. A limit of 0 or less means no limit.
+func Slice[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	if offset > 0 {
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
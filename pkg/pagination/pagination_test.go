@@ -0,0 +1,80 @@
+package pagination
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWrite_BareArray(t *testing.T) {
+	w := httptest.NewRecorder()
+	Write(w, httptest.NewRequest("GET", "/items?limit=2", http.NoBody), []string{"a", "b"}, 5, 2, 0)
+
+	if got := w.Header().Get(TotalCountHeader); got != "5" {
+		t.Errorf("%s = %q, want 5", TotalCountHeader, got)
+	}
+	var items []string
+	if err := json.NewDecoder(w.Body).Decode(&items); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(items, []string{"a", "b"}) {
+		t.Errorf("items = %v, want [a b]", items)
+	}
+}
+
+func TestWrite_Envelope(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []string
+		total    int
+		offset   int
+		wantNext *int
+	}{
+		{name: "first page", items: []string{"a", "b"}, total: 5, offset: 0, wantNext: intPtr(2)},
+		{name: "last page", items: []string{"e"}, total: 5, offset: 4},
+		{name: "empty", items: nil, total: 0, offset: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			Write(w, httptest.NewRequest("GET", "/items?envelope=true", http.NoBody), tt.items, tt.total, 2, tt.offset)
+
+			var page Page[string]
+			if err := json.NewDecoder(w.Body).Decode(&page); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if page.Total != tt.total || page.Limit != 2 || page.Offset != tt.offset {
+				t.Errorf("page = total %d, limit %d, offset %d; want %d, 2, %d", page.Total, page.Limit, page.Offset, tt.total, tt.offset)
+			}
+			if page.Items == nil {
+				t.Error("items = null, want an array")
+			}
+			if !reflect.DeepEqual(page.NextOffset, tt.wantNext) {
+				t.Errorf("next_offset = %v, want %v", page.NextOffset, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestSlice(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		limit, offset int
+		want          []int
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}},
+		{2, 0, []int{1, 2}},
+		{2, 4, []int{5}},
+		{10, 1, []int{2, 3, 4, 5}},
+		{2, 9, []int{}},
+	}
+	for _, tt := range tests {
+		if got := Slice(items, tt.limit, tt.offset); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Slice(limit %d, offset %d) = %v, want %v", tt.limit, tt.offset, got, tt.want)
+		}
+	}
+}
+
+func intPtr(n int) *int { return &n }
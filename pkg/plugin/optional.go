@@ -12,6 +12,13 @@ type HealthChecker interface {
 	Health(ctx context.Context) HealthStatus
 }
 
+// ReadinessReporter is implemented by plugins that can report whether they
+// are ready to serve traffic. Readiness returns nil when ready, or an error
+// saying what is not.
+type ReadinessReporter interface {
+	Readiness() error
+}
+
 // EventSubscriber is implemented by plugins that declare event subscriptions at init.
 type EventSubscriber interface {
 	Subscriptions() []Subscription
@@ -53,6 +53,11 @@ type Dependencies struct {
 	Store   Store          // Database access with per-plugin migrations
 	Bus     EventBus       // Event publish/subscribe for inter-plugin communication
 	Plugins PluginResolver // Resolve other plugins by name or role
+
+	// LogLevel is the server's runtime-adjustable log level, which Logger
+	// already honors. Plugins that build their own loggers should use it.
+	// It is the zero value when the host has no adjustable level.
+	LogLevel zap.AtomicLevel
 }
 
 // Route represents an HTTP route exposed by a plugin.
@@ -60,8 +65,29 @@ type Route struct {
 	Method  string
 	Path    string
 	Handler http.HandlerFunc
+	Class   RouteClass // Request limits to apply; zero is RouteDefault
 }
 
+// RouteClass selects the request body and handler time limits the server
+// applies to a route.
+type RouteClass int
+
+const (
+	// RouteDefault gets the standard body size limit and handler timeout.
+	RouteDefault RouteClass = iota
+	// RouteUpload accepts larger request bodies, e.g. bulk imports.
+	RouteUpload
+	// RouteStream has no handler timeout or write deadline, for responses
+	// written incrementally (server-sent events, streamed downloads).
+	RouteStream
+	// RouteProxy has neither limit; the proxied upstream governs both.
+	RouteProxy
+	// RouteLongRunning has the standard body size limit but no handler
+	// timeout or write deadline, for handlers that bound their own run time
+	// beyond the default (e.g. traceroute).
+	RouteLongRunning
+)
+
 // HealthStatus represents a plugin's health report.
 type HealthStatus struct {
 	Status  string            `json:"status"` // "healthy", "degraded", "unhealthy"
@@ -69,6 +95,28 @@ type HealthStatus struct {
 	Details map[string]string `json:"details,omitempty"`
 }
 
+// PluginState is a registered plugin's lifecycle state.
+type PluginState string
+
+const (
+	// StateEnabled means the plugin passed validation and is running.
+	StateEnabled PluginState = "enabled"
+	// StateDisabled means the plugin was turned off before Init, because of
+	// an incompatible API version or a missing or disabled dependency.
+	StateDisabled PluginState = "disabled"
+	// StateFailed means the plugin was turned off after Init, config
+	// validation, or Start returned an error.
+	StateFailed PluginState = "failed"
+)
+
+// PluginStatus reports a registered plugin's lifecycle state and, for
+// disabled or failed plugins, the reason.
+type PluginStatus struct {
+	Info  PluginInfo
+	State PluginState
+	Err   error
+}
+
 // Config abstracts configuration access. Wraps Viper today, replaceable later.
 type Config interface {
 	Unmarshal(target any) error
@@ -101,6 +149,7 @@ type Migration struct {
 	Version     int                    // Sequential version number (1, 2, 3, ...)
 	Description string                 // Human-readable description
 	Up          func(tx *sql.Tx) error // Forward migration function
+	Down        func(tx *sql.Tx) error // Optional: reverts Up; nil means the migration cannot be rolled back
 }
 
 // Publisher sends events to the bus. Use this thin interface in code
@@ -42,6 +42,23 @@ type MonitoringProvider interface {
 	Status(ctx context.Context, deviceID string) (*MonitorStatus, error)
 }
 
+// MonitoringHistoryProvider is optionally implemented by monitoring plugins
+// that keep alert and availability history. Resolve via
+// PluginResolver.ResolveByRole(RoleMonitoring) then type-assert.
+type MonitoringHistoryProvider interface {
+	// Alerts returns alerts, newest first, optionally filtered by device.
+	// Pass empty deviceID to list alerts for all devices.
+	Alerts(ctx context.Context, deviceID string, activeOnly bool, limit int) ([]MonitorAlert, error)
+
+	// Uptime returns availability for a device over a time range such as
+	// "24h" or "7d".
+	Uptime(ctx context.Context, deviceID, timeRange string) (*UptimeStats, error)
+
+	// DownDevices returns the status of every device currently failing
+	// its checks.
+	DownDevices(ctx context.Context) ([]MonitorStatus, error)
+}
+
 // CredentialProvider is implemented by plugins that store and retrieve
 // credentials for managed devices.
 type CredentialProvider interface {
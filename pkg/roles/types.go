@@ -10,6 +10,26 @@ type MonitorStatus struct {
 	CheckedAt time.Time `json:"checked_at"`
 }
 
+// MonitorAlert is a monitoring alert raised for a device.
+type MonitorAlert struct {
+	ID          string     `json:"id"`
+	DeviceID    string     `json:"device_id"`
+	DeviceName  string     `json:"device_name,omitempty"`
+	Severity    string     `json:"severity"`
+	Message     string     `json:"message"`
+	TriggeredAt time.Time  `json:"triggered_at"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty"`
+}
+
+// UptimeStats summarizes a device's availability over a time range.
+type UptimeStats struct {
+	DeviceID        string   `json:"device_id"`
+	Range           string   `json:"range"`
+	UptimePercent   *float64 `json:"uptime_percent"` // nil when there is no check data
+	DowntimeSeconds float64  `json:"downtime_seconds"`
+	Outages         int      `json:"outages"`
+}
+
 // Credential represents a stored credential (opaque to callers).
 type Credential struct {
 	ID       string `json:"id"`
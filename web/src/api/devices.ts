@@ -27,6 +27,8 @@ export async function updateDevice(
     custom_fields?: Record<string, string>
     device_type?: DeviceType
     location?: string
+    rack?: string
+    rack_unit?: number
     category?: string
     primary_role?: string
     owner?: string
@@ -96,10 +98,18 @@ export async function createDevice(data: CreateDeviceRequest): Promise<Device> {
 }
 
 /**
- * Delete a device by ID.
+ * Delete a device by ID. By default the device is archived and can be
+ * restored; pass hard to remove it and its history permanently.
  */
-export async function deleteDevice(id: string): Promise<void> {
-  return api.delete<void>(`/recon/devices/${id}`)
+export async function deleteDevice(id: string, hard = false): Promise<void> {
+  return api.delete<void>(`/recon/devices/${id}${hard ? '?hard=true' : ''}`)
+}
+
+/**
+ * Restore an archived device.
+ */
+export async function restoreDevice(id: string): Promise<Device> {
+  return api.post<Device>(`/recon/devices/${id}/restore`)
 }
 
 /**
@@ -116,7 +126,7 @@ export async function getDeviceStatusHistory(
   id: string,
   limit = 50
 ): Promise<DeviceStatusEvent[]> {
-  return api.get<DeviceStatusEvent[]>(`/recon/devices/${id}/history?limit=${limit}`)
+  return api.get<DeviceStatusEvent[]>(`/recon/devices/${id}/history?field=status&limit=${limit}`)
 }
 
 /**
@@ -187,7 +197,7 @@ export async function getInventorySummary(staleDays = 30): Promise<InventorySumm
  */
 export interface BulkUpdateRequest {
   device_ids: string[]
-  updates: Partial<Pick<Device, 'location' | 'category' | 'primary_role' | 'owner' | 'notes' | 'tags'>>
+  updates: Partial<Pick<Device, 'location' | 'rack' | 'rack_unit' | 'category' | 'primary_role' | 'owner' | 'notes' | 'tags'>>
 }
 
 /**
@@ -81,6 +81,7 @@ export async function listAlerts(params?: {
   severity?: string
   active?: boolean
   suppressed?: boolean
+  low_priority?: boolean | 'all'
   limit?: number
 }): Promise<Alert[]> {
   const query = new URLSearchParams()
@@ -88,6 +89,7 @@ export async function listAlerts(params?: {
   if (params?.severity) query.set('severity', params.severity)
   if (params?.active !== undefined) query.set('active', params.active.toString())
   if (params?.suppressed !== undefined) query.set('suppressed', params.suppressed.toString())
+  if (params?.low_priority !== undefined) query.set('low_priority', params.low_priority.toString())
   if (params?.limit) query.set('limit', params.limit.toString())
   const qs = query.toString()
   return api.get<Alert[]>(`/pulse/alerts${qs ? `?${qs}` : ''}`)
@@ -77,7 +77,7 @@ export type DeviceType =
   | 'unknown'
 
 /** How the device was discovered. */
-export type DiscoveryMethod = 'agent' | 'icmp' | 'arp' | 'snmp' | 'mdns' | 'upnp' | 'wifi' | 'proxmox' | 'tailscale'
+export type DiscoveryMethod = 'agent' | 'icmp' | 'arp' | 'snmp' | 'mdns' | 'upnp' | 'wifi' | 'proxmox' | 'tailscale' | 'docker' | 'kubernetes'
 
 /** How the device connects to the network. */
 export type ConnectionType = 'wired' | 'wifi' | 'unknown'
@@ -100,6 +100,8 @@ export interface Device {
   tags?: string[]
   custom_fields?: Record<string, string>
   location?: string
+  rack?: string
+  rack_unit?: number
   category?: string
   primary_role?: string
   owner?: string
@@ -107,6 +109,8 @@ export interface Device {
   classification_source?: string
   classification_signals?: string
   connection_type?: ConnectionType
+  /** Set when the device has been archived (soft-deleted). */
+  archived_at?: string
 }
 
 /** Topology node (simplified device for graph display). */
@@ -124,6 +128,11 @@ export interface TopologyNode {
   parent_device_id?: string
   /** Network layer: 0=unknown, 1=gateway, 2=distribution, 3=access, 4=endpoint. */
   network_layer?: number
+  location?: string
+  rack?: string
+  rack_unit?: number
+  /** ID of the group this node belongs to when the graph is grouped. */
+  group?: string
 }
 
 /** Topology edge (connection between devices). */
@@ -135,10 +144,19 @@ export interface TopologyEdge {
   speed?: number
 }
 
+/** Cluster of topology nodes that share a location. */
+export interface TopologyGroup {
+  id: string
+  label: string
+  node_ids: string[]
+}
+
 /** Network topology graph response. */
 export interface TopologyGraph {
   nodes: TopologyNode[]
   edges: TopologyEdge[]
+  /** Present only when requested with group_by. */
+  groups?: TopologyGroup[]
 }
 
 /** Scan status. */
@@ -165,6 +183,7 @@ export type ScanWSMessageType =
   | 'scan.progress'
   | 'scan.device_found'
   | 'scan.completed'
+  | 'scan.cancelled'
   | 'scan.error'
 
 /** WebSocket message envelope. */
@@ -183,12 +202,19 @@ export interface ScanStartedData {
 export interface ScanProgressData {
   hosts_alive: number
   subnet_size: number
+  probes_done: number
+  probes_total: number
+  /** Moving average of probes per second. */
+  rate_pps: number
+  /** Estimated seconds until probing finishes; 0 until a rate is known. */
+  eta_seconds: number
 }
 
 export interface ScanDeviceFoundData {
   device: Device
 }
 
+/** Payload of scan.completed and scan.cancelled messages. */
 export interface ScanCompletedData {
   total: number
   online: number
@@ -376,6 +402,10 @@ export interface Check {
   target: string
   interval_seconds: number
   enabled: boolean
+  low_priority: boolean
+  expected_status?: number
+  expected_body_substring?: string
+  failure_threshold?: number
   created_at: string
   updated_at: string
 }
@@ -406,6 +436,7 @@ export interface Alert {
   consecutive_failures: number
   suppressed: boolean
   suppressed_by?: string
+  low_priority: boolean
 }
 
 /** A dependency between a check and an upstream device for alert suppression. */
@@ -421,6 +452,9 @@ export interface CreateCheckRequest {
   check_type: CheckType
   target: string
   interval_seconds?: number
+  expected_status?: number
+  expected_body_substring?: string
+  failure_threshold?: number
 }
 
 /** Request body for updating a check. */
@@ -429,6 +463,9 @@ export interface UpdateCheckRequest {
   check_type?: CheckType
   interval_seconds?: number
   enabled?: boolean
+  expected_status?: number
+  expected_body_substring?: string
+  failure_threshold?: number
 }
 
 /** Composite monitoring status for a device. */
@@ -506,6 +543,12 @@ export interface TracerouteHop {
   ip?: string
   hostname?: string
   rtt_ms: number
+  min_rtt_ms: number
+  avg_rtt_ms: number
+  max_rtt_ms: number
+  sent: number
+  received: number
+  loss_pct: number
   timeout: boolean
 }
 
@@ -523,6 +566,8 @@ export interface TracerouteRequest {
   target: string
   max_hops?: number
   timeout_ms?: number
+  count?: number
+  interval_ms?: number
 }
 
 // ============================================================================
@@ -92,10 +92,15 @@ export function useScanProgress() {
           break
         }
 
-        case 'scan.completed': {
+        case 'scan.completed':
+        case 'scan.cancelled': {
           const data = message.data as ScanCompletedData
           completeScan(message.scan_id, data.total, data.online)
-          toast.success(`Scan complete: ${data.total} devices found, ${data.online} online`)
+          if (message.type === 'scan.cancelled') {
+            toast.info(`Scan cancelled: ${data.online} devices found before stopping`)
+          } else {
+            toast.success(`Scan complete: ${data.total} devices found, ${data.online} online`)
+          }
 
           // Cancel any pending debounced invalidation; we do a final one now.
           if (invalidateTimerRef.current) {